@@ -0,0 +1,213 @@
+/*
+# Module: cmd/graphfs/cmd_adopt.go
+Adopt command implementation.
+
+Plans LinkedDoc adoption for repositories with little or no metadata: infers
+a provisional graph from imports and reports which files to annotate first,
+in batches with the coverage each batch adds.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/adopt](../../pkg/adopt/report.go) - Adoption report
+- [../../pkg/scanner](../../pkg/scanner/scanner.go) - File scanning
+
+## Tags
+cli, command, adoption, planning
+
+## Exports
+adoptCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_adopt.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_adopt.go" ;
+	code:description "Adopt command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/adopt/report.go>, <../../pkg/scanner/scanner.go> ;
+	code:exports <#adoptCmd> ;
+	code:tags "cli", "command", "adoption", "planning" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/adopt"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adoptBatchSize int
+	adoptBatches   int
+	adoptFormat    string
+	adoptOutput    string
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt",
+	Short: "Plan LinkedDoc adoption",
+	Long: `Commands for adopting LinkedDoc in an existing codebase.
+
+Available subcommands:
+  report - Prioritized list of files to annotate first`,
+}
+
+var adoptReportCmd = &cobra.Command{
+	Use:   "report [path]",
+	Short: "Report which files to annotate first",
+	Long: `Report which files to annotate first in a repository with little or no
+LinkedDoc metadata.
+
+Imports are read from the source to infer a provisional dependency graph
+(Go, Python, JavaScript, TypeScript, Java, C and C++). Unannotated files are
+ranked by the share of files depending on them, with a bonus for entry
+points, and grouped into batches. Each batch shows the file coverage and
+import coverage (inferred imports between annotated files) reached once it
+and the batches before it are annotated.
+
+Examples:
+  graphfs adopt report
+  graphfs adopt report --batch-size 20 --batches 3
+  graphfs adopt report --format markdown -o ADOPTION.md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAdoptReport,
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+	adoptCmd.AddCommand(adoptReportCmd)
+
+	adoptReportCmd.Flags().IntVar(&adoptBatchSize, "batch-size", 10, "Files per batch")
+	adoptReportCmd.Flags().IntVar(&adoptBatches, "batches", 5, "Batches to plan (0 for all)")
+	adoptReportCmd.Flags().StringVarP(&adoptFormat, "format", "f", "table", "Output format (table, markdown, json)")
+	adoptReportCmd.Flags().StringVarP(&adoptOutput, "output", "o", "", "Write the report to a file")
+}
+
+func runAdoptReport(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Debug("Scanning %s...", absPath)
+	scanResult, err := scanner.NewScanner().Scan(absPath, scanner.ScanOptions{
+		IncludePatterns: config.Scan.Include,
+		ExcludePatterns: config.Scan.Exclude,
+		MaxFileSize:     config.Scan.MaxFileSize,
+		UseDefaults:     true,
+		IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+		Concurrent:      true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan: %w", err)
+	}
+
+	provisional, err := adopt.Infer(absPath, scanResult.Files)
+	if err != nil {
+		return fmt.Errorf("failed to infer imports: %w", err)
+	}
+	report := adopt.BuildReport(provisional, adopt.ReportOptions{
+		BatchSize:  adoptBatchSize,
+		MaxBatches: adoptBatches,
+	})
+
+	var output string
+	switch adoptFormat {
+	case "markdown", "md":
+		output = report.Markdown()
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		output = string(data) + "\n"
+	case "table":
+		if adoptOutput != "" {
+			return fmt.Errorf("--output needs --format markdown or json")
+		}
+		printAdoptReport(out, report)
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (use table, markdown or json)", adoptFormat)
+	}
+
+	if adoptOutput != "" {
+		if err := os.WriteFile(adoptOutput, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		out.Success("Adoption report written to %s", adoptOutput)
+		return nil
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+// printAdoptReport prints the report as tables, one per batch
+func printAdoptReport(out *cli.OutputFormatter, report *adopt.Report) {
+	out.Header("LinkedDoc Adoption Report")
+	out.Println("")
+	out.Println("Source files:     %d (%d annotated)", report.Files, report.Annotated)
+	out.Println("Inferred imports: %d (%d between annotated files)", report.Imports, report.CoveredImports)
+	out.Println("Entry points:     %d", report.EntryPoints)
+	if len(report.Unsupported) > 0 {
+		languages := make([]string, 0, len(report.Unsupported))
+		for language := range report.Unsupported {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		out.Warning("No import inference for: %s", strings.Join(languages, ", "))
+	}
+
+	if len(report.Batches) == 0 {
+		out.Println("")
+		out.Success("Every source file is annotated")
+		return
+	}
+
+	headers := []string{"File", "Score", "Why"}
+	for _, batch := range report.Batches {
+		out.Println("")
+		out.Info("Batch %d: file coverage %.0f%% (+%.0f%%), import coverage %.0f%% (+%.0f%%)",
+			batch.Number, batch.FileCoverage*100, batch.FileGain*100,
+			batch.ImportCoverage*100, batch.ImportGain*100)
+		rows := make([][]string, 0, len(batch.Files))
+		for _, c := range batch.Files {
+			rows = append(rows, []string{c.Path, fmt.Sprintf("%.2f", c.Score), c.Reason()})
+		}
+		out.Table(headers, rows)
+	}
+	if report.Remaining > 0 {
+		out.Println("")
+		out.Info("%d more file(s) after these batches (use --batches 0 for all)", report.Remaining)
+	}
+}
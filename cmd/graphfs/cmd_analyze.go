@@ -0,0 +1,207 @@
+/*
+# Module: cmd/graphfs/cmd_analyze.go
+Analyze command implementation.
+
+Groups graph analyses that look for design smells. The duplicates subcommand
+clusters modules sharing tags, concepts, exports and dependencies to flag
+likely duplicated functionality.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [cmd_effective](./cmd_effective.go) - Inherited metadata and concepts
+- [../../pkg/analysis](../../pkg/analysis/duplicates.go) - Duplicate detection
+
+## Tags
+cli, command, analysis, duplicates
+
+## Exports
+analyzeCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_analyze.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_analyze.go" ;
+	code:description "Analyze command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <./cmd_effective.go>, <../../pkg/analysis/duplicates.go> ;
+	code:exports <#analyzeCmd> ;
+	code:tags "cli", "command", "analysis", "duplicates" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	duplicatesMinScore float64
+	duplicatesFormat   string
+	duplicatesFail     bool
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze the codebase for design smells",
+	Long: `Analyze the knowledge graph for design smells.
+
+Available subcommands:
+  duplicates - Find modules that likely duplicate functionality`,
+}
+
+var analyzeDuplicatesCmd = &cobra.Command{
+	Use:   "duplicates [path]",
+	Short: "Find modules that likely duplicate functionality",
+	Long: `Find modules that likely duplicate functionality.
+
+Module pairs are scored (0-1) by the tags, concepts, exports and
+dependencies they share. Values shared by many modules, such as a "cli"
+tag, count less than rare ones. Concepts and inherited tags come from
+shadow metadata. Pairs scoring at least --min-score are grouped into
+clusters, most similar first.
+
+Examples:
+  graphfs analyze duplicates
+  graphfs analyze duplicates --min-score 0.7 --format json
+
+  # Fail CI when new duplicates appear
+  graphfs analyze duplicates --fail`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAnalyzeDuplicates,
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.AddCommand(analyzeDuplicatesCmd)
+
+	analyzeDuplicatesCmd.Flags().Float64Var(&duplicatesMinScore, "min-score", 0.5, "Similarity (0-1) from which modules are reported")
+	analyzeDuplicatesCmd.Flags().StringVarP(&duplicatesFormat, "format", "f", "text", "Output format (text, json)")
+	analyzeDuplicatesCmd.Flags().BoolVar(&duplicatesFail, "fail", false, "Exit with status 1 when duplicates are found")
+}
+
+func runAnalyzeDuplicates(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if duplicatesFormat != "text" && duplicatesFormat != "json" {
+		return fmt.Errorf("unknown format %q (use text or json)", duplicatesFormat)
+	}
+	if duplicatesMinScore <= 0 || duplicatesMinScore > 1 {
+		return fmt.Errorf("--min-score must be between 0 and 1")
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	// Inherited tags and concepts from shadow metadata
+	resolver, err := newEffectiveResolver(absPath)
+	if err != nil {
+		return err
+	}
+	if _, err := resolver.ApplyToGraph(g); err != nil {
+		return fmt.Errorf("failed to apply effective metadata: %w", err)
+	}
+	concepts := make(map[string][]string)
+	for path := range g.Modules {
+		meta, err := resolver.Resolve(path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", path, err)
+		}
+		if len(meta.Concepts) > 0 {
+			concepts[path] = meta.Concepts
+		}
+	}
+
+	report := analysis.FindDuplicates(g, analysis.DuplicateOptions{
+		MinScore: duplicatesMinScore,
+		Concepts: concepts,
+	})
+
+	if duplicatesFormat == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printDuplicates(out, report)
+	}
+
+	if duplicatesFail && len(report.Clusters) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// printDuplicates prints each cluster with its pairs and shared values
+func printDuplicates(out *cli.OutputFormatter, report *analysis.DuplicateReport) {
+	out.Header("Likely Duplicates")
+	out.Println("")
+	out.Info("Compared by %s", strings.Join(report.Facets, ", "))
+
+	if len(report.Clusters) == 0 {
+		out.Success("No similar modules found")
+		return
+	}
+
+	for i, cluster := range report.Clusters {
+		out.Println("")
+		out.Println("Cluster %d (score %.2f): %s", i+1, cluster.Score, strings.Join(cluster.Modules, ", "))
+		for _, pair := range cluster.Pairs {
+			out.Println("  %.2f  %s  ~  %s", pair.Score, pair.A, pair.B)
+			for _, facet := range report.Facets {
+				if shared := pair.Shared[facet]; len(shared) > 0 {
+					out.Println("        %s: %s", facet, strings.Join(shared, ", "))
+				}
+			}
+		}
+	}
+	out.Println("")
+	out.Warning("%d cluster(s) of similar modules", len(report.Clusters))
+}
@@ -0,0 +1,259 @@
+/*
+# Module: cmd/graphfs/cmd_audit.go
+Audit command implementation.
+
+Shows the audit log of shadow writes (.graphfs/audit.log) and applies the
+retention settings from the "audit" section of .graphfs/config.yaml.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Configuration handling
+- [../../pkg/shadow](../../pkg/shadow/audit.go) - Shadow audit log
+
+## Tags
+cli, command, audit, compliance
+
+## Exports
+auditCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_audit.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_audit.go" ;
+	code:description "Audit command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <../../pkg/shadow/audit.go> ;
+	code:exports <#auditCmd> ;
+	code:tags "cli", "command", "audit", "compliance" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditFormat     string
+	auditSince      string
+	auditActor      string
+	auditLimit      int
+	auditOlderThan  string
+	auditMaxRecords int
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of metadata changes",
+	Long: `Show the audit log of shadow metadata changes.
+
+Every write to a shadow entry (annotations, shadow builds and syncs, tag
+changes, prunes, path migrations, ...) is appended to .graphfs/audit.log with
+the time, the actor, the operation and the facts that were added (+) or
+removed (-). The actor is $GRAPHFS_ACTOR, else the git user email, else the
+OS user.
+
+Configure in .graphfs/config.yaml:
+
+  audit:
+    enabled: true       # default
+    retention: 365d     # 'graphfs audit prune' drops older records
+    max_records: 100000 # and keeps at most this many
+
+Examples:
+  graphfs audit show services/auth.go
+  graphfs audit show services/ --since 30d
+  graphfs audit show --actor alice@example.com --format json
+  graphfs audit prune`,
+}
+
+// auditShowCmd shows audit records
+var auditShowCmd = &cobra.Command{
+	Use:   "show [file]",
+	Short: "Show audit records for a file, a directory (ending in /) or everything",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runAuditShow,
+}
+
+// auditPruneCmd applies retention to the audit log
+var auditPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Drop audit records outside the retention settings",
+	Long: `Drop audit records older than the configured retention, or keep only
+the newest max_records. Flags override the configuration.
+
+Examples:
+  graphfs audit prune
+  graphfs audit prune --older-than 180d
+  graphfs audit prune --max-records 50000`,
+	Args: cobra.NoArgs,
+	RunE: runAuditPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditShowCmd)
+	auditCmd.AddCommand(auditPruneCmd)
+
+	auditShowCmd.Flags().StringVarP(&auditFormat, "format", "f", "text", "Output format (text, json)")
+	auditShowCmd.Flags().StringVar(&auditSince, "since", "", "Only records newer than a duration (e.g. 72h, 30d) or a date")
+	auditShowCmd.Flags().StringVar(&auditActor, "actor", "", "Only records by this actor")
+	auditShowCmd.Flags().IntVar(&auditLimit, "limit", 0, "Show only the N most recent records")
+
+	auditPruneCmd.Flags().StringVar(&auditOlderThan, "older-than", "", "Drop records older than a duration or date (default: audit.retention)")
+	auditPruneCmd.Flags().IntVar(&auditMaxRecords, "max-records", 0, "Keep at most N records (default: audit.max_records)")
+}
+
+func runAuditShow(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+	log := shadow.NewAuditLog(filepath.Join(absPath, shadow.DefaultAuditLog))
+
+	// Include records from before renames recorded by graphfs rename
+	paths := []string{""}
+	if len(args) > 0 {
+		paths = auditPaths(shadowFS, args[0])
+	}
+
+	var records []shadow.AuditRecord
+	for _, path := range paths {
+		matched, err := log.Records(path)
+		if err != nil {
+			return err
+		}
+		records = append(records, matched...)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	now := time.Now()
+	var since time.Time
+	if auditSince != "" {
+		if since, err = parseOlderThan(auditSince, now); err != nil {
+			return err
+		}
+	}
+
+	filtered := records[:0]
+	for _, record := range records {
+		if record.Time.Before(since) || (auditActor != "" && record.Actor != auditActor) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	if auditLimit > 0 && len(filtered) > auditLimit {
+		filtered = filtered[len(filtered)-auditLimit:]
+	}
+
+	if auditFormat == "json" {
+		data, err := json.MarshalIndent(filtered, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(filtered) == 0 {
+		out.Info("No audit records found")
+		return nil
+	}
+
+	for _, record := range filtered {
+		out.Println("%s  %-24s  %-14s  %s",
+			record.Time.Local().Format("2006-01-02 15:04:05"), record.Actor, record.Operation, record.Path)
+		for _, change := range record.Changes {
+			out.Println("    %s", change)
+		}
+	}
+	return nil
+}
+
+// auditPaths returns a path and the old paths renamed to it
+func auditPaths(shadowFS *shadow.ShadowFS, path string) []string {
+	path = filepath.ToSlash(path)
+	paths := []string{path}
+
+	aliases, err := shadowFS.Aliases()
+	if err != nil {
+		return paths
+	}
+	for oldPath, newPath := range aliases {
+		if newPath == strings.TrimSuffix(path, "/") {
+			paths = append(paths, oldPath)
+		}
+	}
+	return paths
+}
+
+func runAuditPrune(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	olderThan := auditOlderThan
+	if olderThan == "" {
+		olderThan = config.Audit.Retention
+	}
+	retention := shadow.AuditRetention{MaxRecords: auditMaxRecords}
+	if retention.MaxRecords == 0 {
+		retention.MaxRecords = config.Audit.MaxRecords
+	}
+	if olderThan != "" {
+		cutoff, err := parseOlderThan(olderThan, now)
+		if err != nil {
+			return err
+		}
+		retention.MaxAge = now.Sub(cutoff)
+	}
+	if retention.MaxAge == 0 && retention.MaxRecords == 0 {
+		return fmt.Errorf("no retention configured: set audit.retention or audit.max_records, or pass --older-than or --max-records")
+	}
+
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	removed, err := shadow.NewAuditLog(filepath.Join(absPath, shadow.DefaultAuditLog)).Prune(retention, now)
+	if err != nil {
+		return err
+	}
+	out.Success("Removed %d audit records", removed)
+	return nil
+}
@@ -0,0 +1,271 @@
+/*
+# Module: cmd/graphfs/cmd_bench.go
+Bench command implementation.
+
+Ingests Go benchmark output into .graphfs/benchmarks.json, attributing each
+benchmark to a module and keeping the previous run as the baseline, and shows
+each module's metrics with the change against that baseline. Query and
+validate add the metrics to modules as triples.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/analysis](../../pkg/analysis/benchmarks.go) - Benchmark metrics
+
+## Tags
+cli, command, performance, benchmarks
+
+## Exports
+benchCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_bench.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_bench.go" ;
+	code:description "Bench command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/analysis/benchmarks.go> ;
+	code:exports <#benchCmd> ;
+	code:tags "cli", "command", "performance", "benchmarks" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchFile     string
+	benchPath     string
+	benchFormat   string
+	benchReplace  bool
+	benchNoCommit bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Track benchmark metrics per module",
+	Long: `Commands for module performance metrics from Go benchmarks.
+
+Available subcommands:
+  ingest - Record go test -bench output
+  show   - Show each module's metrics and change against the baseline`,
+}
+
+var benchIngestCmd = &cobra.Command{
+	Use:   "ingest [file]",
+	Short: "Record Go benchmark output",
+	Long: `Record the output of go test -bench (with -benchmem for allocations)
+from a file, or standard input when no file or "-" is given.
+
+Each benchmark is attributed to the module it measures: the file under test
+(benchmarks in parser_test.go measure parser.go), else the module exporting
+the benchmarked name (BenchmarkParser_Parse measures the module exporting
+Parser), else the only module of the package. The run replaces the current
+one in .graphfs/benchmarks.json and the previous run becomes the baseline.
+
+Query and validate then add these triples to benchmarked modules:
+  code:benchNsPerOp, code:benchBytesPerOp, code:benchAllocsPerOp
+  code:benchNsChange, code:benchBytesChange, code:benchAllocsChange
+Changes are fractions against the baseline (0.1 is 10% more). Rules of type
+perf check them against the budgets in .graphfs/perf.yaml.
+
+Examples:
+  go test -run '^$' -bench . -benchmem ./... | graphfs bench ingest
+  graphfs bench ingest bench.txt --replace`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBenchIngest,
+}
+
+var benchShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show module benchmark metrics",
+	Long: `Show the summed benchmark metrics of each module in the latest run, with
+the change against the baseline run for benchmarks present in both.
+
+Examples:
+  graphfs bench show
+  graphfs bench show --format json`,
+	Args: cobra.NoArgs,
+	RunE: runBenchShow,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchIngestCmd)
+	benchCmd.AddCommand(benchShowCmd)
+
+	benchCmd.PersistentFlags().StringVar(&benchPath, "path", ".", "Project root")
+	benchCmd.PersistentFlags().StringVar(&benchFile, "file", analysis.DefaultBenchmarksFile, "Benchmark history, relative to the project root")
+
+	benchIngestCmd.Flags().BoolVar(&benchReplace, "replace", false, "Replace the current run without making it the baseline")
+	benchIngestCmd.Flags().BoolVar(&benchNoCommit, "no-commit", false, "Do not record the git commit of the run")
+
+	benchShowCmd.Flags().StringVarP(&benchFormat, "format", "f", "table", "Output format (table, json)")
+}
+
+func runBenchIngest(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, err := filepath.Abs(benchPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	var input io.Reader = os.Stdin
+	if len(args) > 0 && args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open benchmarks: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+	results, err := analysis.ParseBenchmarks(input)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no benchmark results found (run go test -bench)")
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			IgnoreFiles: []string{".gitignore", ".graphfsignore"},
+			Concurrent:  true,
+		},
+		BaseIRI: projectBaseIRI(absPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	run := analysis.AttributeBenchmarks(g, results)
+	if git := scanner.NewGitFilter(absPath); !benchNoCommit && git.IsGitRepository() {
+		if commit, err := git.HeadCommit(); err == nil {
+			run.Commit = commit
+		}
+	}
+
+	historyFile := benchHistoryFile(absPath)
+	history, err := analysis.LoadBenchmarkHistory(historyFile)
+	if err != nil {
+		return err
+	}
+	if benchReplace {
+		history.Current = run
+	} else {
+		history.Record(run)
+	}
+	if err := history.Save(historyFile); err != nil {
+		return err
+	}
+
+	out.Success("Recorded %d benchmark(s) for %d module(s)", len(results)-len(run.Unassigned), len(run.Modules))
+	for _, r := range run.Unassigned {
+		out.Warning("No module for %s.%s", r.Package, r.Name)
+	}
+	return nil
+}
+
+func runBenchShow(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, err := filepath.Abs(benchPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	history, err := analysis.LoadBenchmarkHistory(benchHistoryFile(absPath))
+	if err != nil {
+		return err
+	}
+	metrics := history.Metrics()
+
+	switch benchFormat {
+	case "json":
+		data, err := json.MarshalIndent(metrics, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "table":
+	default:
+		return fmt.Errorf("unknown format %q (use table or json)", benchFormat)
+	}
+
+	if len(metrics) == 0 {
+		out.Info("No benchmarks recorded (see graphfs bench ingest --help)")
+		return nil
+	}
+
+	headers := []string{"Module", "Benchmarks", "ns/op", "B/op", "allocs/op"}
+	rows := make([][]string, 0, len(metrics))
+	for _, m := range metrics {
+		rows = append(rows, []string{
+			m.Module,
+			strconv.Itoa(m.Benchmarks),
+			benchCell(m.NsPerOp, m.NsChange),
+			benchCell(m.BytesPerOp, m.BytesChange),
+			benchCell(m.AllocsPerOp, m.AllocsChange),
+		})
+	}
+	out.Table(headers, rows)
+	if history.Baseline == nil {
+		out.Info("No baseline run yet; changes appear after the next ingest")
+	}
+	return nil
+}
+
+// benchHistoryFile resolves --file against the project root
+func benchHistoryFile(root string) string {
+	if filepath.IsAbs(benchFile) {
+		return benchFile
+	}
+	return filepath.Join(root, benchFile)
+}
+
+// benchCell formats a metric with its change against the baseline
+func benchCell(value float64, change *float64) string {
+	cell := strconv.FormatFloat(value, 'f', -1, 64)
+	if change != nil && *change != 0 {
+		cell += fmt.Sprintf(" (%+.1f%%)", *change*100)
+	}
+	return cell
+}
+
+// applyBenchmarks adds the benchmark metrics recorded for the project, if
+// any, to the graph
+func applyBenchmarks(g *graph.Graph, rootPath string, out *cli.OutputFormatter) error {
+	history, err := analysis.LoadBenchmarkHistory(filepath.Join(rootPath, analysis.DefaultBenchmarksFile))
+	if err != nil {
+		return err
+	}
+
+	added := history.AddTriples(g)
+	if g.Store != nil {
+		g.Statistics.TotalTriples = g.Store.Count()
+	}
+	if out != nil && added > 0 {
+		out.Debug("Added %d benchmark triples", added)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+/*
+# Module: cmd/graphfs/cmd_budgets.go
+Budgets command implementation.
+
+Reports each package's consumption of the dependency budgets declared in
+.graphfs/budgets.yaml, with deltas against a base git ref for pull requests,
+and fails when a package exceeds its budget.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/analysis](../../pkg/analysis/budgets.go) - Dependency budgets
+- [../../pkg/diff](../../pkg/diff/differ.go) - Graphs at git refs
+
+## Tags
+cli, command, dependencies, budgets
+
+## Exports
+budgetsCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_budgets.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_budgets.go" ;
+	code:description "Budgets command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/analysis/budgets.go>, <../../pkg/diff/differ.go> ;
+	code:exports <#budgetsCmd> ;
+	code:tags "cli", "command", "dependencies", "budgets" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/diff"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	budgetsFile   string
+	budgetsBase   string
+	budgetsFormat string
+	budgetsAll    bool
+)
+
+// budgetsCmd represents the budgets command
+var budgetsCmd = &cobra.Command{
+	Use:   "budgets [path]",
+	Short: "Check packages against their dependency budgets",
+	Long: `Check packages against the dependency budgets in .graphfs/budgets.yaml.
+
+A package is a directory. Each budget applies to one package, or to a
+directory and every package below it when written as dir/...; the first
+matching budget applies. Dependencies are counted as packages.
+
+  budgets:
+    - package: pkg/api
+      max_direct_deps: 5       # packages imported directly
+      max_transitive: 20       # packages in the transitive closure
+      forbidden_layers: [data] # layers the package must not depend on
+    - package: internal/...
+      max_direct_deps: 8
+
+With --base, usage is compared against the same packages at a git ref, so
+pull requests show how much budget they consume. The command exits non-zero
+when any package is over budget. Budgets can also be enforced by validate
+with a rule of type budget.
+
+Examples:
+  graphfs budgets
+  graphfs budgets --base main --format markdown
+  graphfs budgets --all --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBudgets,
+}
+
+func init() {
+	rootCmd.AddCommand(budgetsCmd)
+
+	budgetsCmd.Flags().StringVar(&budgetsFile, "file", analysis.DefaultBudgetsFile, "Budgets file, relative to the project root")
+	budgetsCmd.Flags().StringVar(&budgetsBase, "base", "", "Git ref to compute usage deltas against (e.g. main)")
+	budgetsCmd.Flags().StringVarP(&budgetsFormat, "format", "f", "text", "Output format (text, markdown, json)")
+	budgetsCmd.Flags().BoolVar(&budgetsAll, "all", false, "Show packages within budget too")
+}
+
+func runBudgets(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	file := budgetsFile
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(absPath, file)
+	}
+	config, err := analysis.LoadBudgetConfig(file)
+	if err != nil {
+		return err
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			IgnoreFiles: []string{".gitignore", ".graphfsignore"},
+			Concurrent:  true,
+		},
+		BaseIRI: projectBaseIRI(absPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+	report := analysis.AnalyzeBudgets(g, config)
+
+	if budgetsBase != "" {
+		out.Debug("Building knowledge graph at %s...", budgetsBase)
+		baseGraph, err := diff.NewDiffer(absPath).GraphAtRef(budgetsBase)
+		if err != nil {
+			return fmt.Errorf("failed to build graph at %s: %w", budgetsBase, err)
+		}
+		analysis.CompareBudgets(analysis.AnalyzeBudgets(baseGraph, config), report)
+	}
+
+	over := report.OverBudget()
+	switch budgetsFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "markdown", "md":
+		fmt.Print(formatBudgetsMarkdown(report))
+	case "text":
+		printBudgets(out, report)
+	default:
+		return fmt.Errorf("unknown format %q (use text, markdown or json)", budgetsFormat)
+	}
+
+	// Exit with error code for CI integration
+	if len(over) > 0 {
+		if budgetsFormat == "text" {
+			out.Error("%d package(s) over dependency budget", len(over))
+		}
+		os.Exit(1)
+	}
+	return nil
+}
+
+// budgetRows returns the packages to show: all with --all or a baseline,
+// otherwise those over budget
+func budgetRows(report *analysis.BudgetReport) []*analysis.BudgetUsage {
+	if budgetsAll || budgetsBase != "" {
+		return report.Packages
+	}
+	return report.OverBudget()
+}
+
+func printBudgets(out *cli.OutputFormatter, report *analysis.BudgetReport) {
+	rows := budgetRows(report)
+	if len(report.Packages) == 0 {
+		out.Info("No packages match a budget")
+		return
+	}
+	if len(rows) == 0 {
+		out.Success("All %d packages within budget", len(report.Packages))
+		return
+	}
+
+	headers := []string{"Package", "Direct", "Transitive", "Forbidden", "Status"}
+	table := make([][]string, 0, len(rows))
+	for _, usage := range rows {
+		table = append(table, budgetRow(usage, false))
+	}
+	out.Table(headers, table)
+
+	for _, usage := range rows {
+		for _, edge := range usage.ForbiddenDeps {
+			out.Warning("%s: forbidden dependency %s", usage.Package, edge)
+		}
+	}
+}
+
+func formatBudgetsMarkdown(report *analysis.BudgetReport) string {
+	var sb strings.Builder
+	sb.WriteString("## Dependency budgets\n\n")
+
+	rows := budgetRows(report)
+	if len(rows) == 0 {
+		fmt.Fprintf(&sb, "All %d packages within budget.\n", len(report.Packages))
+		return sb.String()
+	}
+
+	sb.WriteString("| Package | Direct | Transitive | Forbidden | Status |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	for _, usage := range rows {
+		fmt.Fprintf(&sb, "| %s |\n", strings.Join(budgetRow(usage, true), " | "))
+	}
+	return sb.String()
+}
+
+// budgetRow formats a package's usage as used/limit with the delta
+func budgetRow(usage *analysis.BudgetUsage, markdown bool) []string {
+	budget := usage.Budget
+	var delta analysis.BudgetDelta
+	if usage.Delta != nil {
+		delta = *usage.Delta
+	}
+
+	status := "ok"
+	if usage.OverBudget() {
+		status = "over budget"
+		if markdown {
+			status = "**over budget**"
+		}
+	}
+	if usage.Delta != nil && usage.Delta.New {
+		status += " (new)"
+	}
+
+	pkg := usage.Package
+	if markdown {
+		pkg = "`" + pkg + "`"
+	}
+	return []string{
+		pkg,
+		budgetCell(len(usage.DirectDeps), budget.MaxDirectDeps, delta.DirectDeps, usage.Delta != nil),
+		budgetCell(usage.TransitiveDeps, budget.MaxTransitive, delta.TransitiveDeps, usage.Delta != nil),
+		budgetCell(len(usage.ForbiddenDeps), 0, delta.ForbiddenDeps, usage.Delta != nil),
+		status,
+	}
+}
+
+func budgetCell(used, limit, delta int, withDelta bool) string {
+	cell := fmt.Sprintf("%d", used)
+	if limit > 0 {
+		cell = fmt.Sprintf("%d/%d", used, limit)
+	}
+	if withDelta && delta != 0 {
+		cell += fmt.Sprintf(" (%+d)", delta)
+	}
+	return cell
+}
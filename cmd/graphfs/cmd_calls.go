@@ -0,0 +1,176 @@
+/*
+# Module: cmd/graphfs/cmd_calls.go
+Calls command implementation.
+
+Traces the callers and callees of a symbol, direct and transitive, through
+the call graph built from code:calls triples, as text, JSON or DOT.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/analysis](../../pkg/analysis/calls.go) - Call graph analysis
+- [../../pkg/viz](../../pkg/viz/calls.go) - Call trace diagrams
+
+## Tags
+cli, command, calls, call-graph
+
+## Exports
+callsCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_calls.go> a code:Module ;
+    code:name "cmd/graphfs/cmd_calls.go" ;
+    code:description "Calls command implementation" ;
+    code:language "go" ;
+    code:layer "cli" ;
+    code:linksTo <./root.go>, <../../pkg/analysis/calls.go>, <../../pkg/viz/calls.go> ;
+    code:exports <#callsCmd> ;
+    code:tags "cli", "command", "calls", "call-graph" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/viz"
+	"github.com/spf13/cobra"
+)
+
+var (
+	callsFormat    string
+	callsDirection string
+	callsDepth     int
+	callsExtract   bool
+)
+
+var callsCmd = &cobra.Command{
+	Use:   "calls <symbol> [path]",
+	Short: "Show the callers and callees of a symbol",
+	Long: `Show the callers and callees of a symbol, direct and transitive.
+
+Calls come from code:calls triples: those LinkedDoc headers declare on a
+module or on one of its symbols, and, with --extract, the calls Go source
+extraction finds in files without headers. Symbols are written path#Symbol
+(services/auth.go#AuthService.CheckPermission), or as a module path for
+calls made at module level. A symbol may be given by its name alone
+(CheckPermission) when no other symbol has that name.
+
+Transitive callers and callees are listed with their depth and the symbol
+they were reached through. --depth 1 lists direct calls only.
+
+Examples:
+  graphfs calls AuthService.CheckPermission
+  graphfs calls services/user.go#UserService.CreateUser --direction callees
+
+  # Callers up to two calls away, as a diagram
+  graphfs calls ValidateUser --direction callers --depth 2 --format dot | dot -Tsvg -o calls.svg`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runCalls,
+}
+
+func init() {
+	rootCmd.AddCommand(callsCmd)
+
+	callsCmd.Flags().StringVarP(&callsFormat, "format", "f", "text", "Output format (text, json, dot)")
+	callsCmd.Flags().StringVar(&callsDirection, "direction", string(analysis.CallersAndCallees), "Calls to follow (callers, callees, both)")
+	callsCmd.Flags().IntVar(&callsDepth, "depth", 0, "Follow calls up to N steps from the symbol (0 for all)")
+	callsCmd.Flags().BoolVar(&callsExtract, "extract", false, "Extract calls from Go files without LinkedDoc headers")
+}
+
+func runCalls(cmd *cobra.Command, args []string) error {
+	if callsFormat != "text" && callsFormat != "json" && callsFormat != "dot" {
+		return fmt.Errorf("unknown format %q (use text, json or dot)", callsFormat)
+	}
+
+	targetPath := "."
+	if len(args) > 1 {
+		targetPath = args[1]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		config = DefaultConfig()
+	}
+
+	fmt.Fprintln(os.Stderr, "Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI:       config.URIs.Base,
+		ExtractSource: callsExtract,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	cg := analysis.BuildCallGraph(g)
+	trace, err := cg.Trace(args[0], analysis.CallOptions{
+		Direction: analysis.CallDirection(callsDirection),
+		Depth:     callsDepth,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch callsFormat {
+	case "json":
+		data, err := json.MarshalIndent(trace, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "dot":
+		fmt.Print(viz.GenerateCallDOT(cg, trace, "Calls of "+trace.Symbol))
+	default:
+		printCallTrace(trace)
+	}
+	return nil
+}
+
+func printCallTrace(trace *analysis.CallTrace) {
+	fmt.Println(trace.Symbol)
+	direction := analysis.CallDirection(callsDirection)
+	if direction != analysis.CalleesOnly {
+		printCallSites("Callers", trace.Callers)
+	}
+	if direction != analysis.CallersOnly {
+		printCallSites("Callees", trace.Callees)
+	}
+}
+
+func printCallSites(heading string, sites []analysis.CallSite) {
+	fmt.Printf("\n%s (%d):\n", heading, len(sites))
+	if len(sites) == 0 {
+		fmt.Println("  none")
+	}
+	for _, site := range sites {
+		if site.Via != "" {
+			fmt.Printf("  %d  %s (via %s)\n", site.Depth, site.Symbol, site.Via)
+		} else {
+			fmt.Printf("  %d  %s\n", site.Depth, site.Symbol)
+		}
+	}
+}
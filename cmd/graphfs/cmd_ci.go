@@ -0,0 +1,349 @@
+/*
+# Module: cmd/graphfs/cmd_ci.go
+CI command implementation.
+
+Reports rule violations and change impact on pull requests. The changed
+files come from the pull request diff; violations in those files and the
+impact of the changed modules are posted as a comment or a check run.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [cmd_stats](./cmd_stats.go) - Rules discovery
+- [cmd_effective](./cmd_effective.go) - Effective metadata
+- [../../pkg/integrations/github](../../pkg/integrations/github/client.go) - GitHub API client
+- [../../pkg/integrations/github](../../pkg/integrations/github/report.go) - Pull request reports
+
+## Tags
+cli, command, ci, github
+
+## Exports
+ciCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_ci.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_ci.go" ;
+	code:description "CI command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <./cmd_stats.go>, <./cmd_effective.go>, <../../pkg/integrations/github/client.go>, <../../pkg/integrations/github/report.go> ;
+	code:exports <#ciCmd> ;
+	code:tags "cli", "command", "ci", "github" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/integrations/github"
+	"github.com/justin4957/graphfs/pkg/rules"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ciRepo          string
+	ciPR            int
+	ciToken         string
+	ciAPIURL        string
+	ciMode          string
+	ciCheckName     string
+	ciRulesFile     string
+	ciMaxDownstream int
+	ciChanged       []string
+	ciDryRun        bool
+)
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Continuous integration commands",
+	Long: `Commands for reporting graph analysis in continuous integration.
+
+Available subcommands:
+  github - Report violations and change impact on a GitHub pull request`,
+}
+
+var ciGithubCmd = &cobra.Command{
+	Use:   "github [path]",
+	Short: "Report violations and change impact on a GitHub pull request",
+	Long: `Report rule violations and change impact on a GitHub pull request.
+
+The files changed by the pull request are read from the GitHub API. Rules
+are validated against the knowledge graph and the violations in the changed
+files are reported, with the impact of the changed modules and a summary of
+the change. Violations elsewhere in the tree are counted but not listed.
+
+Modes:
+  comment  One pull request comment, updated in place on later runs (default)
+  check    A check run with an annotation for each violation
+
+In GitHub Actions the repository, pull request number, token and API URL
+are read from GITHUB_REPOSITORY, GITHUB_EVENT_PATH, GITHUB_TOKEN and
+GITHUB_API_URL. The command exits with status 1 when a changed file has an
+error-level violation.
+
+Examples:
+  # In a pull_request workflow
+  graphfs ci github
+
+  # Report as a check run
+  graphfs ci github --mode check
+
+  # Preview the report for a local change without calling GitHub
+  graphfs ci github --dry-run --changed pkg/api/handler.go`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCIGithub,
+}
+
+func init() {
+	rootCmd.AddCommand(ciCmd)
+	ciCmd.AddCommand(ciGithubCmd)
+
+	ciGithubCmd.Flags().StringVar(&ciRepo, "repo", os.Getenv("GITHUB_REPOSITORY"), "Repository as owner/name")
+	ciGithubCmd.Flags().IntVar(&ciPR, "pr", 0, "Pull request number (default from the GitHub Actions event)")
+	ciGithubCmd.Flags().StringVar(&ciToken, "token", "", "GitHub token (default $GITHUB_TOKEN)")
+	ciGithubCmd.Flags().StringVar(&ciAPIURL, "api-url", "", "GitHub API URL (default $GITHUB_API_URL or "+github.DefaultAPIURL+")")
+	ciGithubCmd.Flags().StringVar(&ciMode, "mode", "comment", "Report as a pull request comment or a check run (comment, check)")
+	ciGithubCmd.Flags().StringVar(&ciCheckName, "check-name", "graphfs", "Check run name in check mode")
+	ciGithubCmd.Flags().StringVarP(&ciRulesFile, "rules", "r", "", "Rules file (default .graphfs-rules.yml, else built-in rules)")
+	ciGithubCmd.Flags().IntVar(&ciMaxDownstream, "max-downstream", 15, "List at most N downstream modules (0 for all)")
+	ciGithubCmd.Flags().StringSliceVar(&ciChanged, "changed", nil, "Changed files relative to the repository root, instead of the pull request diff")
+	ciGithubCmd.Flags().BoolVar(&ciDryRun, "dry-run", false, "Print the report instead of posting it")
+}
+
+func runCIGithub(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if ciMode != "comment" && ciMode != "check" {
+		return fmt.Errorf("unknown mode %q (use comment or check)", ciMode)
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	client, err := newCIGithubClient()
+	if err != nil && !(ciDryRun && ciChanged != nil) {
+		return err
+	}
+	ctx := context.Background()
+
+	// Pull request paths are relative to the repository root, graph paths to
+	// the scanned directory
+	repoFiles := ciChanged
+	if repoFiles == nil {
+		files, err := client.PullRequestFiles(ctx, ciPR)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if file.Status != "removed" {
+				repoFiles = append(repoFiles, file.Path)
+			}
+		}
+	}
+	prefix := gitPathPrefix(absPath)
+	changed := make([]string, 0, len(repoFiles))
+	for _, file := range repoFiles {
+		file = filepath.ToSlash(file)
+		if strings.HasPrefix(file, prefix) {
+			changed = append(changed, strings.TrimPrefix(file, prefix))
+		}
+	}
+	out.Debug("%d changed file(s) under %s", len(changed), absPath)
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Info("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	resolver, err := newEffectiveResolver(absPath)
+	if err != nil {
+		return err
+	}
+	if _, err := resolver.ApplyToGraph(g); err != nil {
+		return fmt.Errorf("failed to apply effective metadata: %w", err)
+	}
+
+	ruleList, _, err := loadProjectRules(absPath, ciRulesFile)
+	if err != nil {
+		return err
+	}
+	if ruleList == nil {
+		ruleList = rules.GetBuiltInRules()
+	}
+	preprocessor, err := loadQueryPreprocessor(absPath)
+	if err != nil {
+		return err
+	}
+	engine := rules.NewEngine(g)
+	engine.SetPreprocessor(preprocessor)
+	result, err := engine.Validate(ruleList)
+	if err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	summary := analysis.SummarizeChange(g, changed)
+	for _, module := range summary.Modules {
+		if meta, err := resolver.Resolve(module.Path); err == nil {
+			summary.AddConcepts(meta.Concepts...)
+		}
+	}
+
+	var impact *analysis.ImpactResult
+	var modules []string
+	for _, path := range changed {
+		if g.Modules[path] != nil {
+			modules = append(modules, path)
+		}
+	}
+	if len(modules) > 0 {
+		impact, err = analysis.NewImpactAnalysis(g).AnalyzeMultipleModules(modules)
+		if err != nil {
+			return fmt.Errorf("impact analysis failed: %w", err)
+		}
+	}
+
+	report := github.NewReport(result, changed, summary, impact)
+	report.MaxDownstream = ciMaxDownstream
+
+	switch {
+	case ciDryRun:
+		fmt.Print(report.Markdown())
+	case ciMode == "check":
+		pr, err := client.PullRequest(ctx, ciPR)
+		if err != nil {
+			return err
+		}
+		url, err := client.CreateCheckRun(ctx, github.CheckRun{
+			Name:        ciCheckName,
+			HeadSHA:     pr.Head.SHA,
+			Conclusion:  report.Conclusion(),
+			Title:       report.Title(),
+			Summary:     report.Markdown(),
+			Annotations: report.Annotations(),
+		})
+		if err != nil {
+			return err
+		}
+		out.Success("Check run created: %s", url)
+	default:
+		url, err := client.UpsertComment(ctx, ciPR, github.CommentMarker, report.Markdown())
+		if err != nil {
+			return err
+		}
+		out.Success("Pull request comment posted: %s", url)
+	}
+
+	if report.Errors() > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// newCIGithubClient creates a GitHub client from the flags, falling back to
+// the GitHub Actions environment
+func newCIGithubClient() (*github.Client, error) {
+	if ciRepo == "" {
+		return nil, fmt.Errorf("no repository; pass --repo owner/name or set GITHUB_REPOSITORY")
+	}
+	if ciPR == 0 {
+		ciPR = actionsPullRequest()
+	}
+	if ciPR == 0 {
+		return nil, fmt.Errorf("no pull request; pass --pr or run from a pull_request workflow")
+	}
+
+	token := ciToken
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no token; pass --token or set GITHUB_TOKEN")
+	}
+
+	client := github.NewClient(ciRepo, token)
+	if ciAPIURL != "" {
+		client.BaseURL = ciAPIURL
+	} else if url := os.Getenv("GITHUB_API_URL"); url != "" {
+		client.BaseURL = url
+	}
+	return client, nil
+}
+
+// pullRequestRef matches the merge ref of a pull request workflow
+var pullRequestRef = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// actionsPullRequest returns the pull request number of a GitHub Actions
+// run, from the event payload or the ref, or 0
+func actionsPullRequest() int {
+	if eventPath := os.Getenv("GITHUB_EVENT_PATH"); eventPath != "" {
+		if data, err := os.ReadFile(eventPath); err == nil {
+			var event struct {
+				PullRequest struct {
+					Number int `json:"number"`
+				} `json:"pull_request"`
+			}
+			if json.Unmarshal(data, &event) == nil && event.PullRequest.Number > 0 {
+				return event.PullRequest.Number
+			}
+		}
+	}
+	if match := pullRequestRef.FindStringSubmatch(os.Getenv("GITHUB_REF")); match != nil {
+		number, _ := strconv.Atoi(match[1])
+		return number
+	}
+	return 0
+}
+
+// gitPathPrefix returns the path of a directory relative to the root of its
+// git repository, with a trailing slash, or "" at the root or outside git
+func gitPathPrefix(dir string) string {
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
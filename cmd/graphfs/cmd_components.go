@@ -0,0 +1,241 @@
+/*
+# Module: cmd/graphfs/cmd_components.go
+Components command implementation.
+
+Shows the components declared in .graphfs/components.yaml with their
+resolved members, public API and the dependencies between components, and
+loads components for the query, validate, viz and docs commands.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/analysis](../../pkg/analysis/components.go) - Components
+
+## Tags
+cli, command, components, architecture
+
+## Exports
+componentsCmd, loadProjectComponents, applyComponents
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_components.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_components.go" ;
+	code:description "Components command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/analysis/components.go> ;
+	code:exports <#componentsCmd>, <#loadProjectComponents>, <#applyComponents> ;
+	code:tags "cli", "command", "components", "architecture" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	componentsFile    string
+	componentsFormat  string
+	componentsMembers bool
+)
+
+// componentsCmd represents the components command
+var componentsCmd = &cobra.Command{
+	Use:   "components [path]",
+	Short: "Show components and the dependencies between them",
+	Long: `Show the components declared in .graphfs/components.yaml.
+
+A component groups modules above the module level. Each module belongs to
+the component with the most specific matching path; modules matching none
+are listed as unassigned. Dependencies between modules of different
+components become component dependencies.
+
+  components:
+    - name: api
+      description: HTTP API
+      paths: [pkg/api, cmd/server]   # files, directories, dir/... or globs
+      api: [pkg/api/client.go]       # public API modules (default: all)
+      depends_on: [core]             # allowed component dependencies (default: any)
+    - name: core
+      paths: [pkg/core/...]
+
+Dependencies on another component's non-API modules, and on components
+missing from depends_on, are reported as violations. Enforce them with a
+validate rule of type components. With a components file present, query and
+validate add code:memberOf, code:publicAPI, code:dependsOnComponent and
+code:bypassesAPI triples, viz --type component draws the component graph and
+docs lists components in the overview.
+
+Examples:
+  graphfs components
+  graphfs components --members
+  graphfs components --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runComponents,
+}
+
+func init() {
+	rootCmd.AddCommand(componentsCmd)
+
+	componentsCmd.Flags().StringVar(&componentsFile, "file", analysis.DefaultComponentsFile, "Components file, relative to the project root")
+	componentsCmd.Flags().StringVarP(&componentsFormat, "format", "f", "text", "Output format (text, json)")
+	componentsCmd.Flags().BoolVar(&componentsMembers, "members", false, "List the modules of each component")
+}
+
+func runComponents(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	file := componentsFile
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(absPath, file)
+	}
+	config, err := analysis.LoadComponentConfig(file)
+	if err != nil {
+		return err
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			IgnoreFiles: []string{".gitignore", ".graphfsignore"},
+			Concurrent:  true,
+		},
+		BaseIRI: projectBaseIRI(absPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+	result := analysis.AnalyzeComponents(g, config)
+
+	switch componentsFormat {
+	case "json":
+		report := struct {
+			*analysis.ComponentAnalysis
+			Violations []analysis.ComponentViolation `json:"violations"`
+		}{result, result.Violations()}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "text":
+		printComponents(out, result)
+	default:
+		return fmt.Errorf("unknown format %q (use text or json)", componentsFormat)
+	}
+	return nil
+}
+
+func printComponents(out *cli.OutputFormatter, result *analysis.ComponentAnalysis) {
+	rows := make([][]string, 0, len(result.Components))
+	for _, component := range result.Components {
+		api := "all"
+		if component.HasAPI() {
+			api = fmt.Sprintf("%d", len(component.API))
+		}
+		rows = append(rows, []string{
+			component.Name,
+			fmt.Sprintf("%d", len(component.Modules)),
+			api,
+			orDash(strings.Join(component.DependsOn, ", ")),
+			orDash(strings.Join(component.Dependents, ", ")),
+		})
+	}
+	out.Table([]string{"Component", "Modules", "API", "Depends on", "Used by"}, rows)
+
+	if componentsMembers {
+		for _, component := range result.Components {
+			out.Println("")
+			out.Println("%s:", component.Name)
+			for _, modulePath := range component.Modules {
+				marker := " "
+				if component.HasAPI() && component.IsPublic(modulePath) {
+					marker = "*"
+				}
+				out.Println("  %s %s", marker, modulePath)
+			}
+		}
+	}
+
+	if len(result.Unassigned) > 0 {
+		out.Println("")
+		out.Warning("%d module(s) belong to no component", len(result.Unassigned))
+		for _, modulePath := range result.Unassigned {
+			out.Debug("  %s", modulePath)
+		}
+	}
+
+	for _, v := range result.Violations() {
+		if v.Kind == analysis.ComponentViolationUndeclared {
+			out.Warning("%s -> %s: %s does not declare a dependency on %s", v.Source, v.Target, v.From, v.To)
+		} else {
+			out.Warning("%s -> %s: not part of the public API of %s", v.Source, v.Target, v.To)
+		}
+	}
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// loadProjectComponents resolves the components declared for a project
+// against a graph, returning nil without a components file
+func loadProjectComponents(g *graph.Graph, rootPath string) (*analysis.ComponentAnalysis, error) {
+	file := filepath.Join(rootPath, analysis.DefaultComponentsFile)
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	config, err := analysis.LoadComponentConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	return analysis.AnalyzeComponents(g, config), nil
+}
+
+// applyComponents adds component triples to a graph when the project
+// declares components
+func applyComponents(g *graph.Graph, rootPath string, out *cli.OutputFormatter) error {
+	components, err := loadProjectComponents(g, rootPath)
+	if err != nil || components == nil {
+		return err
+	}
+
+	added := components.AddTriples(g)
+	if g.Store != nil {
+		g.Statistics.TotalTriples = g.Store.Count()
+	}
+	if out != nil {
+		out.Debug("Added %d component triples", added)
+	}
+	return nil
+}
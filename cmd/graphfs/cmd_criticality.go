@@ -0,0 +1,234 @@
+/*
+# Module: cmd/graphfs/cmd_criticality.go
+Criticality command implementation.
+
+Scores modules by criticality using the model in the "criticality" section of
+.graphfs/config.yaml, and adds the scores to graphs used by query, validate
+and viz.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Configuration handling
+- [../../pkg/analysis](../../pkg/analysis/criticality.go) - Criticality scoring
+- [../../pkg/scanner](../../pkg/scanner/git_filter.go) - Git churn
+
+## Tags
+cli, command, criticality, risk
+
+## Exports
+criticalityCmd, applyCriticality
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_criticality.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_criticality.go" ;
+	code:description "Criticality command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <../../pkg/analysis/criticality.go>, <../../pkg/scanner/git_filter.go> ;
+	code:exports <#criticalityCmd>, <#applyCriticality> ;
+	code:tags "cli", "command", "criticality", "risk" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/filter"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	criticalityTop    int
+	criticalityFormat string
+	criticalityLevel  string
+	criticalityWhere  string
+)
+
+// criticalityCmd represents the criticality command
+var criticalityCmd = &cobra.Command{
+	Use:   "criticality [path]",
+	Short: "Score modules by criticality",
+	Long: `Score every module by criticality.
+
+The score (0-1) is a weighted sum of five signals, each normalized to 0-1:
+  fan_in        Transitive dependents, relative to the most depended-on module
+  entry_points  Share of entry points that reach the module
+  security      Risk level of the module's security zone
+  churn         Commits touching the module (git), relative to the busiest module
+  coverage      Missing tests: 1 without a test file or declared coverage
+
+Scores map to levels by threshold, and levels may require a minimum number of
+owners (from owner/owners annotations). Configure the model in
+.graphfs/config.yaml:
+
+  criticality:
+    weights: {fan_in: 0.3, entry_points: 0.2, security: 0.2, churn: 0.15, coverage: 0.15}
+    levels: {critical: 0.7, high: 0.5, medium: 0.3}
+    min_owners: {critical: 2}
+    churn_since: 6 months ago
+
+When the section is present, 'graphfs query' and 'graphfs validate' add
+code:criticality, code:criticalityLevel, code:ownerCount and
+code:ownerShortfall triples, so rules can require owners for critical
+modules. 'graphfs viz --size-by criticality' sizes nodes by score.
+
+Examples:
+  graphfs criticality
+  graphfs criticality --top 10
+  graphfs criticality --level critical --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCriticality,
+}
+
+func init() {
+	rootCmd.AddCommand(criticalityCmd)
+
+	criticalityCmd.Flags().IntVar(&criticalityTop, "top", 0, "Show only the N most critical modules")
+	criticalityCmd.Flags().StringVarP(&criticalityFormat, "format", "f", "table", "Output format (table, json)")
+	criticalityCmd.Flags().StringVar(&criticalityLevel, "level", "", "Show only modules at this level")
+	criticalityCmd.Flags().StringVar(&criticalityWhere, "where", "", "Show only modules matching a filter expression (scores use the whole graph)")
+}
+
+func runCriticality(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	where, err := filter.Parse(criticalityWhere)
+	if err != nil {
+		return err
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			IgnoreFiles: []string{".gitignore", ".graphfsignore"},
+			Concurrent:  true,
+		},
+		BaseIRI: projectBaseIRI(absPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	config, _ := projectCriticality(absPath)
+	result, err := applyCriticality(g, config, out)
+	if err != nil {
+		return err
+	}
+
+	var modules []*analysis.ModuleCriticality
+	for _, m := range result.Modules {
+		if criticalityLevel != "" && m.Level != criticalityLevel {
+			continue
+		}
+		if !where.Match(g.Modules[m.Path]) {
+			continue
+		}
+		modules = append(modules, m)
+	}
+	if criticalityTop > 0 && len(modules) > criticalityTop {
+		modules = modules[:criticalityTop]
+	}
+
+	if criticalityFormat == "json" {
+		data, err := json.MarshalIndent(modules, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	out.Header("Module Criticality")
+	out.Println("")
+	if len(modules) == 0 {
+		out.Info("No modules found")
+		return nil
+	}
+
+	headers := []string{"Module", "Score", "Level", "Fan-in", "Entry", "Security", "Churn", "Coverage", "Owners"}
+	rows := make([][]string, 0, len(modules))
+	shortfall := 0
+	for _, m := range modules {
+		owners := fmt.Sprintf("%d", m.Owners)
+		if m.RequiredOwners > 0 {
+			owners = fmt.Sprintf("%d/%d", m.Owners, m.RequiredOwners)
+		}
+		if m.OwnerShortfall() > 0 {
+			shortfall++
+		}
+		rows = append(rows, []string{
+			m.Path,
+			fmt.Sprintf("%.2f", m.Score),
+			m.Level,
+			fmt.Sprintf("%.2f", m.Signals.FanIn),
+			fmt.Sprintf("%.2f", m.Signals.EntryPoints),
+			fmt.Sprintf("%.2f", m.Signals.Security),
+			fmt.Sprintf("%.2f", m.Signals.Churn),
+			fmt.Sprintf("%.2f", m.Signals.Coverage),
+			owners,
+		})
+	}
+	out.Table(headers, rows)
+
+	if shortfall > 0 {
+		out.Println("")
+		out.Warning("%d module(s) have fewer owners than their level requires", shortfall)
+	}
+	return nil
+}
+
+// applyCriticality scores the modules of a built graph and adds criticality
+// triples to its store. Churn comes from git history when the project is a
+// repository.
+func applyCriticality(g *graph.Graph, config analysis.CriticalityConfig, out *cli.OutputFormatter) (*analysis.CriticalityAnalysis, error) {
+	opts := analysis.CriticalityOptions{Config: config}
+
+	git := scanner.NewGitFilter(g.Root)
+	if git.IsGitRepository() {
+		churn, err := git.CommitCounts(config.ChurnSince)
+		if err != nil {
+			// Scoring still works without churn
+			if out != nil {
+				out.Debug("Skipping churn: %v", err)
+			}
+		} else {
+			opts.Churn = churn
+		}
+	}
+
+	result, err := analysis.ScoreCriticality(g, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to score criticality: %w", err)
+	}
+
+	added := result.AddTriples(g)
+	if g.Store != nil {
+		g.Statistics.TotalTriples = g.Store.Count()
+	}
+	if out != nil {
+		out.Debug("Added %d criticality triples", added)
+	}
+	return result, nil
+}
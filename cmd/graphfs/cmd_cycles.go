@@ -0,0 +1,239 @@
+/*
+# Module: cmd/graphfs/cmd_cycles.go
+Cycles command implementation.
+
+Lists every dependency cycle, grouped by strongly connected component and
+ranked by component size, and writes a DOT or Mermaid diagram per component.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [../../pkg/analysis](../../pkg/analysis/cycles.go) - Cycle enumeration
+- [../../pkg/viz](../../pkg/viz/cycles.go) - Cycle diagrams
+
+## Tags
+cli, command, cycles, dependencies
+
+## Exports
+cyclesCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_cycles.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_cycles.go" ;
+	code:description "Cycles command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <../../pkg/analysis/cycles.go>, <../../pkg/viz/cycles.go> ;
+	code:exports <#cyclesCmd> ;
+	code:tags "cli", "command", "cycles", "dependencies" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/viz"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cyclesFormat    string
+	cyclesMaxCycles int
+	cyclesMaxLength int
+	cyclesInfer     bool
+	cyclesMinConf   float64
+	cyclesVizDir    string
+	cyclesVizFormat string
+	cyclesFail      bool
+)
+
+var cyclesCmd = &cobra.Command{
+	Use:   "cycles [path]",
+	Short: "List every dependency cycle",
+	Long: `List every dependency cycle in the codebase.
+
+Modules on cycles are grouped into strongly connected components: sets of
+modules that all reach each other. Components are ranked by size, and every
+elementary cycle within each is listed, shortest first. Breaking the
+shortest cycle of a component is usually the cheapest way to shrink it.
+
+Dense components have very many cycles, so at most --max-cycles are listed
+per component; --max-length skips longer cycles.
+
+With --infer-edges, imports between modules that their headers do not
+declare count as dependencies too; --min-confidence then drops the weaker
+ones (an import alone is 0.6, an import whose exports are referenced 0.9).
+
+With --viz each component is written to its own diagram, scc-1.dot (or
+.mmd) for the largest, with the edges of its shortest cycle highlighted.
+
+Examples:
+  graphfs cycles
+  graphfs cycles --format json --max-cycles 0
+
+  # Include undeclared imports whose exports are referenced
+  graphfs cycles --infer-edges --min-confidence 0.9
+
+  # One diagram per component
+  graphfs cycles --viz cycles/ --viz-format mermaid
+
+  # Fail a CI job when a cycle is introduced
+  graphfs cycles --fail`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCycles,
+}
+
+func init() {
+	rootCmd.AddCommand(cyclesCmd)
+
+	cyclesCmd.Flags().StringVarP(&cyclesFormat, "format", "f", "text", "Output format (text, json)")
+	cyclesCmd.Flags().IntVar(&cyclesMaxCycles, "max-cycles", 100, "List at most N cycles per component (0 for all)")
+	cyclesCmd.Flags().IntVar(&cyclesMaxLength, "max-length", 0, "Skip cycles through more than N modules (0 for any)")
+	cyclesCmd.Flags().BoolVar(&cyclesInfer, "infer-edges", false, "Count undeclared imports between modules as dependencies")
+	cyclesCmd.Flags().Float64Var(&cyclesMinConf, "min-confidence", 0,
+		"Ignore dependencies with a lower confidence (0-1; inferred edges are 0.6, or 0.9 when referenced)")
+	cyclesCmd.Flags().StringVar(&cyclesVizDir, "viz", "", "Write a diagram of each component to this directory")
+	cyclesCmd.Flags().StringVar(&cyclesVizFormat, "viz-format", "dot", "Diagram format (dot, mermaid)")
+	cyclesCmd.Flags().BoolVar(&cyclesFail, "fail", false, "Exit with status 1 when there are cycles")
+}
+
+func runCycles(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if cyclesFormat != "text" && cyclesFormat != "json" {
+		return fmt.Errorf("unknown format %q (use text or json)", cyclesFormat)
+	}
+	if cyclesVizFormat != "dot" && cyclesVizFormat != "mermaid" {
+		return fmt.Errorf("unknown diagram format %q (use dot or mermaid)", cyclesVizFormat)
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	fmt.Fprintln(os.Stderr, "Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI:    config.URIs.Base,
+		InferEdges: cyclesInfer,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	report := analysis.FindCycles(g, analysis.CycleOptions{
+		MaxCycles:     cyclesMaxCycles,
+		MaxLength:     cyclesMaxLength,
+		MinConfidence: cyclesMinConf,
+	})
+
+	if cyclesVizDir != "" {
+		if err := writeCycleDiagrams(g, report); err != nil {
+			return err
+		}
+		if len(report.Components) > 0 {
+			fmt.Fprintf(os.Stderr, "Wrote %d diagram(s) to %s\n", len(report.Components), cyclesVizDir)
+		}
+	}
+
+	if cyclesFormat == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printCyclesText(out, report)
+	}
+
+	if cyclesFail && len(report.Components) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func printCyclesText(out *cli.OutputFormatter, report *analysis.CycleReport) {
+	if len(report.Components) == 0 {
+		out.Success("No dependency cycles")
+		return
+	}
+
+	modules := 0
+	for _, component := range report.Components {
+		modules += len(component.Modules)
+	}
+	fmt.Printf("%d cycle(s) in %d component(s), %d module(s) involved\n", report.TotalCycles, len(report.Components), modules)
+
+	for i, component := range report.Components {
+		fmt.Printf("\nSCC %d: %d module(s), %d edge(s), %d cycle(s)", i+1, len(component.Modules), component.Edges, len(component.Cycles))
+		if component.Truncated {
+			fmt.Printf(" (truncated)")
+		}
+		fmt.Println()
+		for _, path := range component.Modules {
+			fmt.Printf("  • %s\n", path)
+		}
+		fmt.Println("  Cycles:")
+		for _, cycle := range component.Cycles {
+			fmt.Printf("    %s -> %s\n", strings.Join(cycle, " -> "), cycle[0])
+		}
+	}
+
+	if report.Truncated {
+		fmt.Printf("\nSome components have more cycles; raise --max-cycles to list them.\n")
+	}
+}
+
+// writeCycleDiagrams writes one diagram per component, numbered by rank
+func writeCycleDiagrams(g *graph.Graph, report *analysis.CycleReport) error {
+	if err := os.MkdirAll(cyclesVizDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	for i, component := range report.Components {
+		title := fmt.Sprintf("SCC %d: %d modules, %d cycles", i+1, len(component.Modules), len(component.Cycles))
+		diagram, ext := viz.GenerateCycleDOT(g, component, title), ".dot"
+		if cyclesVizFormat == "mermaid" {
+			diagram, ext = viz.GenerateCycleMermaid(g, component, title), ".mmd"
+		}
+		file := filepath.Join(cyclesVizDir, fmt.Sprintf("scc-%d%s", i+1, ext))
+		if err := os.WriteFile(file, []byte(diagram), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -12,11 +12,12 @@ import (
 )
 
 var (
-	deadCodeConfidence float64
-	deadCodeExclude    []string
-	deadCodeScript     string
-	deadCodeAggressive bool
-	deadCodeTarget     string
+	deadCodeConfidence       float64
+	deadCodeExclude          []string
+	deadCodeScript           string
+	deadCodeAggressive       bool
+	deadCodeTarget           string
+	deadCodeIncludeGenerated bool
 )
 
 var deadCodeCmd = &cobra.Command{
@@ -26,6 +27,7 @@ var deadCodeCmd = &cobra.Command{
 
 Analyzes the dependency graph to identify modules with no incoming references,
 unexported symbols that are never used, and dependencies that are declared but not used.
+Generated code ("Code generated ... DO NOT EDIT") is skipped unless --include-generated is set.
 
 Examples:
   # Basic dead code detection
@@ -58,6 +60,8 @@ func init() {
 		"Aggressive mode (more likely to flag code as dead)")
 	deadCodeCmd.Flags().StringVarP(&deadCodeTarget, "target", "t", ".",
 		"Target directory to analyze")
+	deadCodeCmd.Flags().BoolVar(&deadCodeIncludeGenerated, "include-generated", false,
+		"Include generated code (skipped by default)")
 }
 
 func runDeadCode(cmd *cobra.Command, args []string) error {
@@ -88,9 +92,10 @@ func runDeadCode(cmd *cobra.Command, args []string) error {
 
 	// Configure detection options
 	opts := analysis.DeadCodeOptions{
-		MinConfidence:   deadCodeConfidence,
-		ExcludePatterns: deadCodeExclude,
-		AggressiveMode:  deadCodeAggressive,
+		MinConfidence:    deadCodeConfidence,
+		ExcludePatterns:  deadCodeExclude,
+		AggressiveMode:   deadCodeAggressive,
+		IncludeGenerated: deadCodeIncludeGenerated,
 	}
 
 	// Perform dead code detection
@@ -141,7 +146,9 @@ func runDeadCode(cmd *cobra.Command, args []string) error {
 
 	// Coverage analysis
 	cyan.Println("\n📊 Usage Coverage:")
-	coverage := analysis.AnalyzeCoverage(g)
+	coverage := analysis.AnalyzeCoverageWithOptions(g, analysis.CoverageOptions{
+		IncludeGenerated: deadCodeIncludeGenerated,
+	})
 	fmt.Printf("  • Total modules: %d\n", coverage.TotalModules)
 	fmt.Printf("  • Referenced: %d (%.1f%%)\n", coverage.ReferencedModules, coverage.CoveragePercent)
 	fmt.Printf("  • Unreferenced: %d (%.1f%%)\n", coverage.UnreferencedModules,
@@ -0,0 +1,186 @@
+/*
+# Module: cmd/graphfs/cmd_describe.go
+Describe-change command implementation.
+
+Summarizes staged changes or a git diff range in terms of the knowledge
+graph (modules touched, layers, downstream dependents and concepts) for
+commit message bodies and pull request descriptions.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [cmd_effective](./cmd_effective.go) - Effective metadata
+- [../../pkg/analysis](../../pkg/analysis/change_summary.go) - Change summaries
+- [../../pkg/scanner](../../pkg/scanner/git_filter.go) - Git integration
+
+## Tags
+cli, command, git, change-summary
+
+## Exports
+describeChangeCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_describe.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_describe.go" ;
+	code:description "Describe-change command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./cmd_effective.go>, <../../pkg/analysis/change_summary.go>, <../../pkg/scanner/git_filter.go> ;
+	code:exports <#describeChangeCmd> ;
+	code:tags "cli", "command", "git", "change-summary" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	describeFormat        string
+	describeMaxDownstream int
+	describeOutput        string
+)
+
+// describeChangeCmd represents the describe-change command
+var describeChangeCmd = &cobra.Command{
+	Use:   "describe-change [range]",
+	Short: "Summarize a change for a commit message or PR description",
+	Long: `Summarize staged changes, or the changes in a git diff range, in terms of
+the knowledge graph: the modules touched, the layers they belong to, the
+modules downstream of them and their tags and concepts.
+
+Without a range the staged changes are described. A range is anything
+'git diff' accepts, such as main...HEAD or HEAD~3.
+
+Formats:
+  markdown  Pull request description section (default)
+  text      Plain lines for a commit message body
+  json      Structured summary
+
+Examples:
+  # Describe staged changes for a commit body
+  graphfs describe-change --format text
+
+  # Describe a branch for a pull request
+  graphfs describe-change main...HEAD > pr-summary.md
+
+  # Append to a commit message from a prepare-commit-msg hook
+  graphfs describe-change --format text >> "$1"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDescribeChange,
+}
+
+func init() {
+	rootCmd.AddCommand(describeChangeCmd)
+
+	describeChangeCmd.Flags().StringVarP(&describeFormat, "format", "f", "markdown", "Output format (markdown, text, json)")
+	describeChangeCmd.Flags().IntVar(&describeMaxDownstream, "max-downstream", 15, "List at most N downstream modules (0 for all)")
+	describeChangeCmd.Flags().StringVarP(&describeOutput, "output", "o", "", "Write the summary to a file")
+}
+
+func runDescribeChange(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	rng := ""
+	if len(args) > 0 {
+		rng = args[0]
+	}
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	gitFilter := scanner.NewGitFilter(absPath)
+	if !gitFilter.IsGitRepository() {
+		return fmt.Errorf("not a git repository: %s", absPath)
+	}
+	files, err := gitFilter.ChangedInRange(rng)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		if rng == "" {
+			return fmt.Errorf("no staged changes; stage files or pass a range such as main...HEAD")
+		}
+		return fmt.Errorf("no changes in %s", rng)
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			IgnoreFiles: []string{".gitignore", ".graphfsignore"},
+			Concurrent:  true,
+		},
+		BaseIRI: projectBaseIRI(absPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	resolver, err := newEffectiveResolver(absPath)
+	if err != nil {
+		return err
+	}
+	if _, err := resolver.ApplyToGraph(g); err != nil {
+		return fmt.Errorf("failed to apply effective metadata: %w", err)
+	}
+
+	changed := make([]string, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(absPath, file)
+		if err != nil {
+			continue
+		}
+		changed = append(changed, filepath.ToSlash(rel))
+	}
+
+	summary := analysis.SummarizeChange(g, changed)
+	for _, module := range summary.Modules {
+		if meta, err := resolver.Resolve(module.Path); err == nil {
+			summary.AddConcepts(meta.Concepts...)
+		}
+	}
+
+	var output string
+	switch describeFormat {
+	case "markdown", "md":
+		output = summary.Markdown(describeMaxDownstream)
+	case "text":
+		output = summary.Text(describeMaxDownstream)
+	case "json":
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		output = string(data) + "\n"
+	default:
+		return fmt.Errorf("unknown format %q (use markdown, text or json)", describeFormat)
+	}
+
+	if describeOutput != "" {
+		if err := os.WriteFile(describeOutput, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		out.Success("Change summary written to %s", describeOutput)
+		return nil
+	}
+
+	fmt.Print(output)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/justin4957/graphfs/pkg/docs"
+	"github.com/justin4957/graphfs/pkg/filter"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/scanner"
 	"github.com/spf13/cobra"
@@ -17,11 +18,13 @@ var (
 	docsTemplate     string
 	docsLayers       []string
 	docsTags         []string
+	docsWhere        string
 	docsDepth        int
 	docsIncludeGraph bool
 	docsTitle        string
 	docsAuthor       string
 	docsVersion      string
+	docsIDStrategy   string
 )
 
 var docsCmd = &cobra.Command{
@@ -40,6 +43,7 @@ The documentation includes:
   - Exported functions and types
   - Cross-links between modules
   - Project statistics and overview
+  - Components from .graphfs/components.yaml, when declared
   - Optional frontmatter for static site generators
 
 Examples:
@@ -59,7 +63,14 @@ Examples:
   graphfs docs --tag security --tag api
 
   # Include frontmatter for Jekyll/Hugo
-  graphfs docs --format single --author "GraphFS Team" --version "1.0.0"`,
+  graphfs docs --format single --author "GraphFS Team" --version "1.0.0"
+
+  # Keep the file names and anchors of earlier releases for published links
+  graphfs docs --format multi --id-strategy legacy
+
+File names and anchors come from module paths: pkg/graph/graph.go becomes
+pkg_graph_graph.md and #module-pkg-graph-graph-go. Set docs.id_strategy in
+.graphfs/config.yaml to make legacy naming the project default.`,
 	RunE: runDocs,
 }
 
@@ -71,11 +82,13 @@ func init() {
 	docsCmd.Flags().StringVar(&docsTemplate, "template", "", "Custom template file")
 	docsCmd.Flags().StringSliceVar(&docsLayers, "layer", []string{}, "Filter by layer (can be specified multiple times)")
 	docsCmd.Flags().StringSliceVar(&docsTags, "tag", []string{}, "Filter by tag (can be specified multiple times)")
+	docsCmd.Flags().StringVar(&docsWhere, "where", "", "Filter modules by expression (e.g. 'layer=api && tag!=deprecated')")
 	docsCmd.Flags().IntVar(&docsDepth, "depth", 0, "Maximum dependency depth (0 for unlimited)")
 	docsCmd.Flags().BoolVar(&docsIncludeGraph, "include-graph", false, "Include dependency graph visualizations")
 	docsCmd.Flags().StringVar(&docsTitle, "title", "", "Documentation title (defaults to project name)")
 	docsCmd.Flags().StringVar(&docsAuthor, "author", "", "Author name for frontmatter")
 	docsCmd.Flags().StringVar(&docsVersion, "version", "", "Version for frontmatter")
+	docsCmd.Flags().StringVar(&docsIDStrategy, "id-strategy", "", "File name and anchor strategy: path or legacy (default from config, else path)")
 }
 
 func runDocs(cmd *cobra.Command, args []string) error {
@@ -119,6 +132,15 @@ func runDocs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid format: %s (must be single, multi, or directory)", docsFormat)
 	}
 
+	idStrategyName := config.Docs.IDStrategy
+	if docsIDStrategy != "" {
+		idStrategyName = docsIDStrategy
+	}
+	idStrategy, err := docs.ParseIDStrategy(idStrategyName)
+	if err != nil {
+		return err
+	}
+
 	// Build the knowledge graph
 	fmt.Println("Building knowledge graph...")
 
@@ -171,17 +193,30 @@ func runDocs(cmd *cobra.Command, args []string) error {
 		frontMatter["project"] = projectName
 	}
 
+	components, err := loadProjectComponents(g, absPath)
+	if err != nil {
+		return err
+	}
+
+	where, err := filter.Parse(docsWhere)
+	if err != nil {
+		return err
+	}
+
 	docsOpts := docs.DocsOptions{
 		OutputDir:     docsOutputDir,
 		Format:        format,
 		Template:      docsTemplate,
 		IncludeLayers: docsLayers,
 		IncludeTags:   docsTags,
+		Where:         where,
 		Depth:         docsDepth,
 		IncludeGraph:  docsIncludeGraph,
 		Title:         title,
 		ProjectName:   projectName,
 		FrontMatter:   frontMatter,
+		Components:    components,
+		IDs:           idStrategy,
 	}
 
 	// Generate documentation
@@ -214,6 +249,9 @@ func runDocs(cmd *cobra.Command, args []string) error {
 	if len(docsTags) > 0 {
 		fmt.Printf("  Filtered by tags: %v\n", docsTags)
 	}
+	if where != nil {
+		fmt.Printf("  Filtered by: %s\n", where)
+	}
 
 	return nil
 }
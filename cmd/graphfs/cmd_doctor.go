@@ -3,9 +3,12 @@
 Doctor command for system diagnostics.
 
 Implements the 'graphfs doctor' command for running health checks and diagnostics.
+Checks the installation and the project, then rates overall health 0-100.
 
 ## Linked Modules
 - [../../pkg/doctor](../../pkg/doctor/doctor.go) - Health check system
+- [../../pkg/doctor](../../pkg/doctor/project.go) - Project health checks
+- [config](./config.go) - Scan configuration
 - [root](./root.go) - Root command
 
 ## Tags
@@ -23,7 +26,7 @@ doctorCmd
     code:description "Doctor command for system diagnostics" ;
     code:language "go" ;
     code:layer "cli" ;
-    code:linksTo <../../pkg/doctor/doctor.go>, <./root.go> ;
+    code:linksTo <../../pkg/doctor/doctor.go>, <../../pkg/doctor/project.go>, <./config.go>, <./root.go> ;
     code:exports <#doctorCmd> ;
     code:tags "cli", "diagnostics", "doctor" .
 <!-- End LinkedDoc RDF -->
@@ -38,15 +41,18 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/justin4957/graphfs/pkg/doctor"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
 	"github.com/spf13/cobra"
 )
 
 var doctorCmd = &cobra.Command{
-	Use:   "doctor",
+	Use:   "doctor [path]",
 	Short: "Run diagnostics and health checks",
 	Long: `Run comprehensive diagnostics to check GraphFS installation,
-configuration, and performance. Reports issues and provides
-recommendations for fixes.
+configuration, performance and project health. Reports issues,
+provides recommendations for fixes and rates overall health from
+0 to 100 (passed checks count fully, warnings half).
 
 The doctor command checks:
 - GraphFS and Go versions
@@ -57,13 +63,23 @@ The doctor command checks:
 - File permissions
 - Parser performance
 
+And for the project:
+- .graphfs directory and config.yaml syntax
+- Shadow index out of date with the shadow entries
+- Cache entries of deleted files
+- LinkedDoc headers that fail to parse
+- code:linksTo targets pointing to missing files
+- Git hooks running graphfs
+
 Exit Codes:
   0 - All checks passed (or only warnings)
   1 - One or more critical errors found
 
 Examples:
   graphfs doctor           # Run all diagnostics
-  graphfs doctor --verbose # Show detailed output`,
+  graphfs doctor --verbose # Show detailed output
+  graphfs doctor ./myproject`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runDoctor,
 }
 
@@ -87,14 +103,33 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Determine root path
-	rootPath, err := os.Getwd()
+	rootPath := "."
+	if len(args) > 0 {
+		rootPath = args[0]
+	}
+	rootPath, err := filepath.Abs(rootPath)
 	if err != nil {
-		rootPath = "."
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	config, err := loadConfig(filepath.Join(rootPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		config = DefaultConfig()
 	}
-	rootPath, _ = filepath.Abs(rootPath)
 
 	// Run all health checks
 	checks := doctor.RunAllChecks(rootPath, Version)
+	checks = append(checks, doctor.RunProjectChecks(rootPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})...)
 
 	issues := 0
 	warnings := 0
@@ -134,6 +169,16 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Summary
+	score := doctor.Score(checks)
+	switch {
+	case issues > 0:
+		red.Printf("Health score: %d/100\n", score)
+	case warnings > 0:
+		yellow.Printf("Health score: %d/100\n", score)
+	default:
+		green.Printf("Health score: %d/100\n", score)
+	}
+
 	if issues == 0 && warnings == 0 {
 		green.Println("✓ All checks passed! GraphFS is healthy.")
 		return nil
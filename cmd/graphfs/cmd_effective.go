@@ -0,0 +1,197 @@
+/*
+# Module: cmd/graphfs/cmd_effective.go
+Effective command implementation.
+
+Shows a module's effective metadata resolved from its shadow entry, directory
+entries and workspace defaults.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Configuration handling
+- [../../pkg/shadow](../../pkg/shadow/effective.go) - Effective metadata resolver
+
+## Tags
+cli, command, shadow, inheritance
+
+## Exports
+effectiveCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_effective.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_effective.go" ;
+	code:description "Effective command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <../../pkg/shadow/effective.go> ;
+	code:exports <#effectiveCmd> ;
+	code:tags "cli", "command", "shadow", "inheritance" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var effectiveOutput string
+
+// effectiveCmd shows the effective metadata of a module
+var effectiveCmd = &cobra.Command{
+	Use:   "effective <file>",
+	Short: "Show effective (inherited) metadata for a file",
+	Long: `Show a file's effective metadata.
+
+Metadata is resolved from three levels, most specific first:
+  1. The file's own shadow entry
+  2. Directory entries (.graphfs/shadow/<dir>/_directory.json), nearest first
+  3. Workspace defaults (the "defaults" section of .graphfs/config.yaml)
+
+Layer, language, name and description come from the most specific level that
+sets them. Tags and concepts accumulate across levels. Annotations resolve per
+key, most specific level first.
+
+Use --effective on 'graphfs query' and 'graphfs validate' to run against
+effective values instead of only what is declared in LinkedDoc headers.
+
+Examples:
+  graphfs effective services/auth.go
+  graphfs effective services/auth.go --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEffective,
+}
+
+func init() {
+	effectiveCmd.Flags().StringVarP(&effectiveOutput, "output", "o", "table", "Output format (table, json)")
+
+	rootCmd.AddCommand(effectiveCmd)
+}
+
+func runEffective(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	resolver, err := newEffectiveResolver(absPath)
+	if err != nil {
+		return err
+	}
+
+	meta, err := resolver.Resolve(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve effective metadata: %w", err)
+	}
+
+	if effectiveOutput == "json" {
+		data, err := json.MarshalIndent(meta, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	out.Header(fmt.Sprintf("Effective Metadata: %s", meta.SourcePath))
+	out.Println("")
+
+	headers := []string{"Field", "Value", "Origin"}
+	var rows [][]string
+
+	addRow := func(field, value, originKey string) {
+		if value == "" {
+			return
+		}
+		rows = append(rows, []string{field, value, meta.Origins[originKey]})
+	}
+
+	addRow("name", meta.Name, "name")
+	addRow("description", meta.Description, "description")
+	addRow("language", meta.Language, "language")
+	addRow("layer", meta.Layer, "layer")
+	for _, tag := range meta.Tags {
+		addRow("tag", tag, "tags:"+tag)
+	}
+	for _, concept := range meta.Concepts {
+		addRow("concept", concept, "concepts:"+concept)
+	}
+
+	keys := make([]string, 0, len(meta.Annotations))
+	for key := range meta.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		addRow("annotation:"+key, fmt.Sprintf("%v", meta.Annotations[key]), "annotation:"+key)
+	}
+
+	if len(rows) == 0 {
+		out.Info("No metadata found for %s", meta.SourcePath)
+		return nil
+	}
+
+	out.Table(headers, rows)
+	return nil
+}
+
+// newEffectiveResolver creates a resolver using the project's shadow file
+// system and the workspace defaults from .graphfs/config.yaml
+func newEffectiveResolver(absPath string) (*shadow.Resolver, error) {
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	return shadow.NewResolver(shadowFS, workspaceDefaultsEntry(config.Defaults)), nil
+}
+
+// workspaceDefaultsEntry converts configured defaults into a shadow entry
+func workspaceDefaultsEntry(defaults DefaultsConfig) *shadow.Entry {
+	if defaults.Layer == "" && len(defaults.Tags) == 0 && len(defaults.Annotations) == 0 {
+		return nil
+	}
+
+	entry := shadow.NewManualEntry(".")
+	entry.SetModule("", "", "", "", defaults.Layer, defaults.Tags)
+	for key, value := range defaults.Annotations {
+		entry.AddAnnotation(key, value, "")
+	}
+	return entry
+}
+
+// applyEffectiveMetadata applies inherited metadata to a built graph
+func applyEffectiveMetadata(g *graph.Graph, out *cli.OutputFormatter) error {
+	resolver, err := newEffectiveResolver(g.Root)
+	if err != nil {
+		return err
+	}
+
+	updated, err := resolver.ApplyToGraph(g)
+	if err != nil {
+		return fmt.Errorf("failed to apply effective metadata: %w", err)
+	}
+
+	if out != nil {
+		out.Debug("Applied inherited metadata to %d modules", updated)
+	}
+	return nil
+}
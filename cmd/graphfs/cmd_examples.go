@@ -2,11 +2,13 @@
 # Module: cmd/graphfs/cmd_examples.go
 Examples command implementation for query templates.
 
-Provides commands to list, show, run, save, and export query templates.
+Provides commands to list, show, run, save, and export query templates, and
+to fetch and update template packs from URLs or GitHub repositories.
 
 ## Linked Modules
 - [root](./root.go) - Root command
 - [../../pkg/query](../../pkg/query/templates.go) - Query templates
+- [../../pkg/query](../../pkg/query/packs.go) - Template packs
 - [../../pkg/cli](../../pkg/cli/output.go) - Output formatting
 
 ## Tags
@@ -24,7 +26,7 @@ examplesCmd
     code:description "Examples command implementation for query templates" ;
     code:language "go" ;
     code:layer "cli" ;
-    code:linksTo <./root.go>, <../../pkg/query/templates.go>, <../../pkg/cli/output.go> ;
+    code:linksTo <./root.go>, <../../pkg/query/templates.go>, <../../pkg/query/packs.go>, <../../pkg/cli/output.go> ;
     code:exports <#examplesCmd> ;
     code:tags "cli", "command", "examples", "templates" .
 <!-- End LinkedDoc RDF -->
@@ -34,10 +36,12 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/justin4957/graphfs/pkg/cli"
@@ -49,8 +53,17 @@ import (
 )
 
 var (
-	examplesCategory string
-	examplesOutput   string
+	examplesSaveQuery       string
+	examplesSaveDescription string
+	examplesSaveCategory    string
+	examplesSaveVariables   []string
+	examplesSaveFile        string
+	examplesSaveForce       bool
+
+	examplesCategory        string
+	examplesOutput          string
+	examplesSHA256          string
+	examplesAllowUnverified bool
 )
 
 // examplesCmd represents the examples command
@@ -79,7 +92,11 @@ Examples:
   graphfs examples save my-query --query="SELECT * WHERE {...}"
 
   # Export template to file
-  graphfs examples export find-dependencies > my-query.sparql`,
+  graphfs examples export find-dependencies > my-query.sparql
+
+  # Fetch a template pack and refresh installed packs
+  graphfs examples fetch acme/graphfs-queries
+  graphfs examples update`,
 }
 
 // examplesListCmd represents the list subcommand
@@ -124,13 +141,27 @@ Use 'graphfs examples show <name>' to see available variables.`,
 
 // examplesSaveCmd represents the save subcommand
 var examplesSaveCmd = &cobra.Command{
-	Use:   "save <name>",
+	Use:   "save [name]",
 	Short: "Save a custom query template",
 	Long: `Save a custom query template to the templates directory.
 
 Custom templates are stored in .graphfs/templates/ and can be used
-just like built-in templates.`,
-	Args: cobra.ExactArgs(1),
+just like built-in templates.
+
+Give the query with --query, or a JSON or YAML definition in the custom
+template format with --file (use - for stdin). Flags override fields of the
+definition. Variables are template fields such as {{.module}}; declare them
+with --variable name or --variable name=default, or leave them out to make
+every field in the query a required variable.
+
+The template is checked before it is written. Replacing an existing custom
+template, or shadowing a built-in or pack template, requires --force.`,
+	Example: `  graphfs examples save importers \
+    --query 'SELECT ?m WHERE { ?m <#imports> <#{{.module}}> }' \
+    --description "Modules that import a module" --category dependencies
+  graphfs examples save --file importers.yaml
+  cat importers.json | graphfs examples save --file -`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runExamplesSave,
 }
 
@@ -148,15 +179,58 @@ Use 'graphfs examples show <name>' to see available variables.`,
 	RunE: runExamplesExport,
 }
 
+// examplesFetchCmd represents the fetch subcommand
+var examplesFetchCmd = &cobra.Command{
+	Use:   "fetch <url|org/repo[@ref]>",
+	Short: "Download a template pack",
+	Long: `Download a template pack into .graphfs/templates/packs.
+
+A pack is a JSON file with a name and a list of templates in the same format
+as custom templates. A GitHub repository written as org/repo (or
+org/repo@ref) resolves to graphfs-templates.json at the repository root.
+
+The pack's SHA-256 checksum is verified against --sha256 when given, or
+against the checksum published at <url>.sha256. Packs without a checksum are
+rejected unless --allow-unverified is set. Custom templates in
+.graphfs/templates override pack templates with the same name.`,
+	Example: `  graphfs examples fetch acme/graphfs-queries
+  graphfs examples fetch https://queries.example.com/security.json
+  graphfs examples fetch https://example.com/pack.json --sha256 9f86d081...`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExamplesFetch,
+}
+
+// examplesUpdateCmd represents the update subcommand
+var examplesUpdateCmd = &cobra.Command{
+	Use:   "update [pack...]",
+	Short: "Refresh installed template packs",
+	Long: `Download installed template packs again from their recorded sources.
+
+Packs fetched with a published checksum are verified against the current
+published checksum. Packs pinned with --sha256 must still match the pinned
+checksum; fetch them again with a new --sha256 to move to a new version.`,
+	RunE: runExamplesUpdate,
+}
+
 func init() {
 	examplesCmd.AddCommand(examplesListCmd)
 	examplesCmd.AddCommand(examplesShowCmd)
 	examplesCmd.AddCommand(examplesRunCmd)
 	examplesCmd.AddCommand(examplesSaveCmd)
 	examplesCmd.AddCommand(examplesExportCmd)
+	examplesCmd.AddCommand(examplesFetchCmd)
+	examplesCmd.AddCommand(examplesUpdateCmd)
 
 	examplesListCmd.Flags().StringVar(&examplesCategory, "category", "", "Filter by category")
+	examplesSaveCmd.Flags().StringVar(&examplesSaveQuery, "query", "", "SPARQL query of the template")
+	examplesSaveCmd.Flags().StringVar(&examplesSaveDescription, "description", "", "What the template finds")
+	examplesSaveCmd.Flags().StringVar(&examplesSaveCategory, "category", "", "Template category (default: custom)")
+	examplesSaveCmd.Flags().StringArrayVar(&examplesSaveVariables, "variable", nil, "Declare a variable as name or name=default (repeatable)")
+	examplesSaveCmd.Flags().StringVarP(&examplesSaveFile, "file", "f", "", "Read a JSON or YAML template definition from a file (- for stdin)")
+	examplesSaveCmd.Flags().BoolVar(&examplesSaveForce, "force", false, "Replace an existing template")
 	examplesExportCmd.Flags().StringVarP(&examplesOutput, "output", "o", "", "Output file (default: stdout)")
+	examplesFetchCmd.Flags().StringVar(&examplesSHA256, "sha256", "", "Expected SHA-256 checksum of the pack")
+	examplesFetchCmd.Flags().BoolVar(&examplesAllowUnverified, "allow-unverified", false, "Install packs without a checksum")
 
 	// Register dynamic template variable flags
 	// This allows any --variable=value flags to be accepted
@@ -217,8 +291,10 @@ func runExamplesList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize template manager
-	templatesDir := filepath.Join(currentDir, ".graphfs", "templates")
-	tm := query.NewTemplateManager(templatesDir)
+	tm, err := newTemplateManager(currentDir)
+	if err != nil {
+		return err
+	}
 
 	// Get templates
 	templates := tm.ListTemplates(examplesCategory)
@@ -304,8 +380,10 @@ func runExamplesShow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize template manager
-	templatesDir := filepath.Join(currentDir, ".graphfs", "templates")
-	tm := query.NewTemplateManager(templatesDir)
+	tm, err := newTemplateManager(currentDir)
+	if err != nil {
+		return err
+	}
 
 	// Get template
 	tmpl, err := tm.GetTemplate(templateName)
@@ -397,8 +475,10 @@ func runExamplesRun(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize template manager
-	templatesDir := filepath.Join(currentDir, ".graphfs", "templates")
-	tm := query.NewTemplateManager(templatesDir)
+	tm, err := newTemplateManager(currentDir)
+	if err != nil {
+		return err
+	}
 
 	// Get template
 	tmpl, err := tm.GetTemplate(templateName)
@@ -456,7 +536,6 @@ func runExamplesRun(cmd *cobra.Command, args []string) error {
 
 func runExamplesSave(cmd *cobra.Command, args []string) error {
 	out := cli.NewOutputFormatter(quiet, verbose, noColor)
-	templateName := args[0]
 
 	// Get current directory
 	currentDir, err := os.Getwd()
@@ -464,30 +543,110 @@ func runExamplesSave(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	tmpl, err := templateFromSaveFlags(cmd, args)
+	if err != nil {
+		return err
+	}
+	if err := query.ValidateTemplate(tmpl); err != nil {
+		return err
+	}
+
 	// Initialize template manager
 	templatesDir := filepath.Join(currentDir, ".graphfs", "templates")
-	_ = query.NewTemplateManager(templatesDir)
-
-	// For now, we'll prompt the user to create the template JSON manually
-	// In a real implementation, we could read from stdin or flags
-	out.Info("To save a custom template, create a JSON file in:")
-	out.Info("  %s", templatesDir)
-	out.Info("")
-	out.Info("Example format:")
-	out.Info(`  {
-    "name": "%s",
-    "description": "Your template description",
-    "category": "custom",
-    "query": "SELECT * WHERE { ... }",
-    "variables": [
-      {"name": "var1", "description": "Variable description"}
-    ],
-    "example": "graphfs examples run %s --var1=value"
-  }`, templateName, templateName)
+	tm, err := newTemplateManager(currentDir)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockWorkspace(currentDir, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !examplesSaveForce {
+		if _, err := os.Stat(filepath.Join(templatesDir, tmpl.Name+".json")); err == nil {
+			return fmt.Errorf("custom template %s already exists (use --force to replace it)", tmpl.Name)
+		}
+		if existing, err := tm.GetTemplate(tmpl.Name); err == nil {
+			return fmt.Errorf("template %s would replace the %s template with the same name (use --force to replace it)", tmpl.Name, existing.Category)
+		}
+	}
 
+	if err := query.NewTemplateManager(templatesDir).SaveCustomTemplate(tmpl); err != nil {
+		return err
+	}
+
+	out.Success("Saved template %s to %s", tmpl.Name, filepath.ToSlash(filepath.Join(".graphfs", "templates", tmpl.Name+".json")))
+	out.Info("Run it with: %s", tmpl.Example)
 	return nil
 }
 
+// templateFromSaveFlags builds the template for 'examples save' from a
+// definition file or stdin, overridden by the name argument and flags
+func templateFromSaveFlags(cmd *cobra.Command, args []string) (*query.QueryTemplate, error) {
+	tmpl := &query.QueryTemplate{}
+	if examplesSaveFile != "" {
+		var data []byte
+		var err error
+		if examplesSaveFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(examplesSaveFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template definition: %w", err)
+		}
+		if tmpl, err = query.ParseTemplateDefinition(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(args) > 0 {
+		tmpl.Name = args[0]
+	}
+	if tmpl.Name == "" {
+		return nil, fmt.Errorf("template name required")
+	}
+	if cmd.Flags().Changed("query") {
+		tmpl.Query = examplesSaveQuery
+	}
+	if tmpl.Query == "" {
+		return nil, fmt.Errorf("--query or --file required")
+	}
+	if cmd.Flags().Changed("description") {
+		tmpl.Description = examplesSaveDescription
+	}
+	if cmd.Flags().Changed("category") {
+		tmpl.Category = examplesSaveCategory
+	}
+	if tmpl.Category == "" {
+		tmpl.Category = "custom"
+	}
+
+	if cmd.Flags().Changed("variable") {
+		tmpl.Variables = nil
+		for _, spec := range examplesSaveVariables {
+			name, value, _ := strings.Cut(spec, "=")
+			tmpl.Variables = append(tmpl.Variables, query.Variable{Name: name, Default: value})
+		}
+	} else if tmpl.Variables == nil {
+		for _, name := range query.TemplateVariableNames(tmpl.Query) {
+			tmpl.Variables = append(tmpl.Variables, query.Variable{Name: name})
+		}
+	}
+
+	if tmpl.Example == "" {
+		tmpl.Example = "graphfs examples run " + tmpl.Name
+		for _, v := range tmpl.Variables {
+			if v.Default == "" {
+				tmpl.Example += " --" + v.Name + "=<" + v.Name + ">"
+			}
+		}
+	}
+	return tmpl, nil
+}
+
 func runExamplesExport(cmd *cobra.Command, args []string) error {
 	templateName := args[0]
 
@@ -498,8 +657,10 @@ func runExamplesExport(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize template manager
-	templatesDir := filepath.Join(currentDir, ".graphfs", "templates")
-	tm := query.NewTemplateManager(templatesDir)
+	tm, err := newTemplateManager(currentDir)
+	if err != nil {
+		return err
+	}
 
 	// Get template
 	tmpl, err := tm.GetTemplate(templateName)
@@ -527,3 +688,141 @@ func runExamplesExport(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runExamplesFetch(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+	source := args[0]
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	templatesDir := filepath.Join(currentDir, ".graphfs", "templates")
+
+	url, err := query.ResolvePackSource(source)
+	if err != nil {
+		return err
+	}
+
+	out.Debug("Downloading %s", url)
+	pack, checksum, verify, err := query.NewPackFetcher(packFetchTimeout).Fetch(url, examplesSHA256, examplesAllowUnverified)
+	if err != nil {
+		return err
+	}
+	if verify == query.VerifyNone {
+		out.Warning("Installing %s without checksum verification", pack.Name)
+	}
+
+	record := query.InstalledPack{
+		Source:    source,
+		URL:       url,
+		SHA256:    checksum,
+		Verify:    verify,
+		FetchedAt: time.Now().UTC(),
+	}
+	if err := query.InstallPack(templatesDir, pack, record); err != nil {
+		return err
+	}
+
+	out.Success("Installed pack %s (%d templates)", packLabel(pack), len(pack.Templates))
+	out.Debug("sha256: %s", checksum)
+	return nil
+}
+
+func runExamplesUpdate(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	templatesDir := filepath.Join(currentDir, ".graphfs", "templates")
+
+	index, err := query.LoadPackIndex(templatesDir)
+	if err != nil {
+		return err
+	}
+
+	names := args
+	if len(names) == 0 {
+		names = index.Names()
+	}
+	if len(names) == 0 {
+		out.Info("No template packs installed")
+		return nil
+	}
+
+	fetcher := query.NewPackFetcher(packFetchTimeout)
+	failed := 0
+	for _, name := range names {
+		installed, ok := index.Packs[name]
+		if !ok {
+			out.Error("Pack not installed: %s", name)
+			failed++
+			continue
+		}
+
+		pinned := ""
+		if installed.Verify == query.VerifyPinned {
+			pinned = installed.SHA256
+		}
+		pack, checksum, verify, err := fetcher.Fetch(installed.URL, pinned, installed.Verify == query.VerifyNone)
+		if err != nil {
+			out.Error("%s: %v", name, err)
+			failed++
+			continue
+		}
+		if pack.Name != name {
+			out.Error("%s: source now provides pack %s; fetch it again to install under the new name", name, pack.Name)
+			failed++
+			continue
+		}
+		if checksum == installed.SHA256 {
+			out.Info("%s is up to date", name)
+			continue
+		}
+
+		record := *installed
+		record.SHA256 = checksum
+		record.Verify = verify
+		record.FetchedAt = time.Now().UTC()
+		if err := query.InstallPack(templatesDir, pack, record); err != nil {
+			return err
+		}
+		out.Success("Updated pack %s (%d templates)", packLabel(pack), len(pack.Templates))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to update %d pack(s)", failed)
+	}
+	return nil
+}
+
+// packFetchTimeout bounds each template pack download
+const packFetchTimeout = 30 * time.Second
+
+// packLabel formats a pack name with its version
+func packLabel(pack *query.TemplatePack) string {
+	if pack.Version == "" {
+		return pack.Name
+	}
+	return pack.Name + "@" + pack.Version
+}
+
+// newTemplateManager creates a template manager for the project with shared
+// prefixes and macros applied to rendered templates
+func newTemplateManager(currentDir string) (*query.TemplateManager, error) {
+	tm := query.NewTemplateManager(filepath.Join(currentDir, ".graphfs", "templates"))
+
+	preprocessor, err := loadQueryPreprocessor(currentDir)
+	if err != nil {
+		return nil, err
+	}
+	tm.SetPreprocessor(preprocessor)
+
+	if err := tm.AddSavedQueries(savedQueriesDir(currentDir)); err != nil {
+		return nil, err
+	}
+
+	return tm, nil
+}
@@ -0,0 +1,221 @@
+/*
+# Module: cmd/graphfs/cmd_export.go
+Export command implementation.
+
+Builds the knowledge graph and writes it in a format for external tools:
+JSON, RDF (Turtle, N-Triples, N-Quads), Cypher for Neo4j, or a SQLite
+database with modules, dependencies, exports, tags and annotations.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [cmd_scan](./cmd_scan.go) - JSON, RDF and Cypher writers
+- [../../pkg/export](../../pkg/export/sqlite.go) - SQLite export
+- [../../pkg/shadow](../../pkg/shadow/shadow.go) - Shadow annotations
+
+## Tags
+cli, command, export, sqlite
+
+## Exports
+exportCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_export.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_export.go" ;
+	code:description "Export command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <./cmd_scan.go>, <../../pkg/export/sqlite.go>, <../../pkg/shadow/shadow.go> ;
+	code:exports <#exportCmd> ;
+	code:tags "cli", "command", "export", "sqlite" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/export"
+	"github.com/justin4957/graphfs/pkg/filter"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	exportWhere  string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [path]",
+	Short: "Export the knowledge graph for external tools",
+	Long: `Export the knowledge graph for external tools.
+
+Formats:
+  • json     - Modules and statistics (.json)
+  • turtle   - Every triple as Turtle (.ttl)
+  • ntriples - Every triple as N-Triples (.nt)
+  • nquads   - Every triple with its named graph as N-Quads (.nq)
+  • cypher   - Cypher statements for Neo4j (.cypher, .cql)
+  • sqlite   - SQLite database (.db, .sqlite, .sqlite3)
+
+The format is detected from the output file's extension unless --format is
+given.
+
+The SQLite database has a normalized schema: modules, dependencies, exports,
+tags, properties (other RDF properties such as owner) and annotations (active
+shadow annotations), plus a dependency_paths view, so the graph can be
+queried with plain SQL.
+
+Examples:
+  graphfs export --format sqlite --output graph.db
+  graphfs export -o graph.ttl --where 'layer=service'
+
+  sqlite3 graph.db "SELECT layer, COUNT(*) FROM modules GROUP BY layer"
+  sqlite3 graph.db "SELECT target, COUNT(*) AS fan_in FROM dependency_paths GROUP BY target ORDER BY fan_in DESC LIMIT 10"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "", "Export format (json, turtle, ntriples, nquads, cypher, sqlite) - auto-detected from extension")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (required)")
+	exportCmd.Flags().StringVar(&exportWhere, "where", "", "Export only modules matching a filter expression")
+	_ = exportCmd.MarkFlagRequired("output")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	format, err := exportFormatFor(exportFormat, exportOutput)
+	if err != nil {
+		return err
+	}
+	where, err := filter.Parse(exportWhere)
+	if err != nil {
+		return err
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+	g = filter.Subgraph(g, where)
+
+	switch format {
+	case "json":
+		err = exportGraph(g, exportOutput)
+	case "cypher":
+		err = exportCypher(g, exportOutput)
+	case "sqlite":
+		err = export.WriteSQLite(exportOutput, g, export.SQLiteOptions{
+			Annotations: loadAnnotations(absPath, out),
+		})
+	default:
+		var skipped int
+		skipped, err = exportRDF(g, exportOutput, export.Format(format), rdfBaseIRI(absPath, config.URIs.Base))
+		if skipped > 0 {
+			out.Warning("Skipped %d triple(s) that are not valid RDF", skipped)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export graph: %w", err)
+	}
+
+	out.Success("Exported %d modules to %s", len(g.Modules), exportOutput)
+	return nil
+}
+
+// exportFormatFor returns the export format named by --format, or the one
+// the output file's extension implies
+func exportFormatFor(name, output string) (string, error) {
+	switch strings.ToLower(name) {
+	case "json", "cypher", "sqlite":
+		return strings.ToLower(name), nil
+	case "":
+		switch {
+		case strings.EqualFold(filepath.Ext(output), ".json"):
+			return "json", nil
+		case export.IsCypherFile(output):
+			return "cypher", nil
+		case export.IsSQLiteFile(output):
+			return "sqlite", nil
+		}
+		if format, ok := export.FormatForFile(output); ok {
+			return string(format), nil
+		}
+		return "", fmt.Errorf("cannot detect the export format of %s: use --format", output)
+	}
+	format, err := export.ParseFormat(name)
+	if err != nil {
+		return "", fmt.Errorf("unknown export format %q (must be json, turtle, ntriples, nquads, cypher or sqlite)", name)
+	}
+	return string(format), nil
+}
+
+// loadAnnotations returns the shadow annotations by module path, or none if
+// the project has no shadow file system
+func loadAnnotations(absPath string, out *cli.OutputFormatter) map[string][]shadow.Annotation {
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		out.Debug("No shadow annotations: %v", err)
+		return nil
+	}
+	entries, err := shadowFS.List()
+	if err != nil {
+		out.Debug("No shadow annotations: %v", err)
+		return nil
+	}
+
+	annotations := make(map[string][]shadow.Annotation)
+	for _, entry := range entries {
+		if len(entry.Annotations) > 0 {
+			path := filepath.ToSlash(entry.SourcePath)
+			annotations[path] = append(annotations[path], entry.Annotations...)
+		}
+	}
+	return annotations
+}
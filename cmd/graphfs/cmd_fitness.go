@@ -0,0 +1,197 @@
+/*
+# Module: cmd/graphfs/cmd_fitness.go
+Fitness command implementation.
+
+Scores how well actual dependency directions conform to the declared layer
+order, per layer and overall, and emits a markdown architecture fitness
+report. Saved reports build a history so conformance can be tracked over
+time.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Layer order and history configuration
+- [../../pkg/analysis](../../pkg/analysis/fitness.go) - Layer conformance
+
+## Tags
+cli, command, fitness, layers, architecture
+
+## Exports
+fitnessCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_fitness.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_fitness.go" ;
+	code:description "Fitness command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <../../pkg/analysis/fitness.go> ;
+	code:exports <#fitnessCmd> ;
+	code:tags "cli", "command", "fitness", "layers", "architecture" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fitnessLayers    []string
+	fitnessFormat    string
+	fitnessOutput    string
+	fitnessSave      bool
+	fitnessFailUnder float64
+)
+
+var fitnessCmd = &cobra.Command{
+	Use:   "fitness [path]",
+	Short: "Score layer conformance as an architecture fitness report",
+	Long: `Score how well dependencies conform to the declared layer order.
+
+Layers are declared from the top, either with --layers or in
+.graphfs/config.yaml:
+
+  fitness:
+    layers: [cli, service, data]
+
+A dependency conforms when it stays within its layer or points to a layer
+declared after it. Conformance is the share of conforming dependencies,
+per layer and overall; modules outside the declared layers are not scored.
+
+With --save the result is recorded in the fitness history
+(.graphfs/fitness-history.json by default, one entry per day) and the report
+gains a trend table.
+
+Examples:
+  graphfs fitness --layers cli,service,data
+  graphfs fitness --save -o docs/fitness.md
+  graphfs fitness --format json --fail-under 95`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFitness,
+}
+
+func init() {
+	rootCmd.AddCommand(fitnessCmd)
+
+	fitnessCmd.Flags().StringSliceVar(&fitnessLayers, "layers", nil, "Layer order, top first (overrides fitness.layers)")
+	fitnessCmd.Flags().StringVarP(&fitnessFormat, "format", "f", "markdown", "Output format (markdown, json)")
+	fitnessCmd.Flags().StringVarP(&fitnessOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	fitnessCmd.Flags().BoolVar(&fitnessSave, "save", false, "Record the result in the fitness history")
+	fitnessCmd.Flags().Float64Var(&fitnessFailUnder, "fail-under", 0, "Exit with status 1 when overall conformance is below this percentage")
+}
+
+func runFitness(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if fitnessFormat != "markdown" && fitnessFormat != "json" {
+		return fmt.Errorf("unknown format %q (use markdown or json)", fitnessFormat)
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	layers := fitnessLayers
+	if len(layers) == 0 {
+		layers = config.Fitness.Layers
+	}
+	if len(layers) == 0 {
+		return fmt.Errorf("no layer order declared: use --layers or set fitness.layers in .graphfs/config.yaml")
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	report, err := analysis.AssessFitness(g, layers)
+	if err != nil {
+		return err
+	}
+	report.Date = time.Now().Format("2006-01-02")
+
+	historyPath := config.Fitness.History
+	if historyPath == "" {
+		historyPath = filepath.Join(".graphfs", "fitness-history.json")
+	}
+	if !filepath.IsAbs(historyPath) {
+		historyPath = filepath.Join(absPath, historyPath)
+	}
+	history, err := analysis.LoadFitnessHistory(historyPath)
+	if err != nil {
+		return err
+	}
+	if fitnessSave {
+		history = history.Add(report.Entry())
+		if err := history.Save(historyPath); err != nil {
+			return err
+		}
+		out.Debug("Recorded conformance in %s", historyPath)
+	}
+
+	var content []byte
+	if fitnessFormat == "json" {
+		content, err = json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		content = append(content, '\n')
+	} else {
+		content = []byte(report.Markdown(history))
+	}
+
+	if fitnessOutput != "" {
+		if err := os.WriteFile(fitnessOutput, content, 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		out.Success("Architecture fitness report written to %s (%.1f%% conformance)", fitnessOutput, report.Conformance)
+	} else {
+		fmt.Print(string(content))
+	}
+
+	if fitnessFailUnder > 0 && report.Conformance < fitnessFailUnder {
+		fmt.Fprintf(os.Stderr, "Layer conformance %.1f%% is below %.1f%%\n", report.Conformance, fitnessFailUnder)
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+# Module: cmd/graphfs/cmd_history.go
+History command for architecture evolution.
+
+Implements 'graphfs history', which builds the graph at points in git
+history (every tag, or the last commit of each month) and reports module
+counts, coupling metrics and cycle counts over time.
+
+## Linked Modules
+- [../../pkg/history](../../pkg/history/timeline.go) - Graph statistics over history
+- [root](./root.go) - Root command
+
+## Tags
+cli, command, history, git, metrics
+
+## Exports
+historyCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_history.go> a code:Module ;
+    code:name "cmd/graphfs/cmd_history.go" ;
+    code:description "History command for architecture evolution" ;
+    code:language "go" ;
+    code:layer "cli" ;
+    code:linksTo <../../pkg/history/timeline.go>, <./root.go> ;
+    code:exports <#historyCmd> ;
+    code:tags "cli", "command", "history", "git", "metrics" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/history"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyFrom   string
+	historyTo     string
+	historyStep   string
+	historyFormat string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [path]",
+	Short: "Report how the architecture evolved over git history",
+	Long: `Build the graph at points in git history and report a time series of
+module and dependency counts, coupling metrics and dependency cycles.
+
+Points are every tag between --from and --to (--step tag), or the last
+commit of each month on the first-parent history (--step monthly), plus
+both ends of the range. Trees are read with git archive, so the working
+tree is left alone. The current project configuration is used for every
+point.
+
+Columns:
+  Modules, Edges       Module and dependency counts
+  Layers               Distinct layers
+  Avg fan-out          Dependencies per module
+  Max fan-in/fan-out   Largest fan-in and fan-out of any module
+  Instability          Average instability Ce/(Ca+Ce)
+  Depth                Longest distance from an entry point
+  Cycles               Groups of modules in a dependency cycle
+  Cyclic               Modules in a dependency cycle`,
+	Example: `  graphfs history --from v1.0 --to HEAD --step tag
+  graphfs history --from v1.0 --step monthly --format csv > evolution.csv
+  graphfs history --format md > docs/evolution.md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historyFrom, "from", "", "Oldest revision (default: the first commit)")
+	historyCmd.Flags().StringVar(&historyTo, "to", "HEAD", "Newest revision")
+	historyCmd.Flags().StringVar(&historyStep, "step", "tag", "Points to report (tag, monthly)")
+	historyCmd.Flags().StringVarP(&historyFormat, "format", "f", "table", "Output format (table, json, csv, md)")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	switch historyFormat {
+	case "table", "json", "csv", "md":
+	default:
+		return fmt.Errorf("unknown format %q (use table, json, csv or md)", historyFormat)
+	}
+	step, err := history.ParseStep(historyStep)
+	if err != nil {
+		return err
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	points, err := history.ListPoints(absPath, historyFrom, historyTo, step)
+	if err != nil {
+		return err
+	}
+	out.Debug("Building the graph at %d points", len(points))
+
+	build := func(dir string) (*graph.Graph, error) {
+		return graph.NewBuilder().Build(dir, graph.BuildOptions{
+			ScanOptions: scanner.ScanOptions{
+				IncludePatterns: config.Scan.Include,
+				ExcludePatterns: config.Scan.Exclude,
+				MaxFileSize:     config.Scan.MaxFileSize,
+				UseDefaults:     true,
+				IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+				Concurrent:      true,
+			},
+			BaseIRI:  config.URIs.Base,
+			UseCache: false, // Trees are temporary
+		})
+	}
+	progress := func(i int, point history.Point) {
+		out.Debug("[%d/%d] %s (%s)", i+1, len(points), point.Ref, shortCommit(point.Commit))
+	}
+
+	entries, err := history.Timeline(absPath, points, build, progress)
+	if err != nil {
+		return err
+	}
+
+	switch historyFormat {
+	case "json":
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write(historyHeaders)
+		for _, entry := range entries {
+			_ = w.Write(historyRow(entry))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	case "md":
+		fmt.Println("| " + strings.Join(historyHeaders, " | ") + " |")
+		fmt.Println(strings.Repeat("|---", len(historyHeaders)) + "|")
+		for _, entry := range entries {
+			fmt.Println("| " + strings.Join(historyRow(entry), " | ") + " |")
+		}
+	default:
+		rows := make([][]string, 0, len(entries))
+		for _, entry := range entries {
+			rows = append(rows, historyRow(entry))
+		}
+		out.Table(historyHeaders, rows)
+	}
+	return nil
+}
+
+var historyHeaders = []string{"Ref", "Date", "Commit", "Modules", "Edges", "Layers", "Avg fan-out", "Max fan-in", "Max fan-out", "Instability", "Depth", "Cycles", "Cyclic"}
+
+func historyRow(entry history.TimelineEntry) []string {
+	s := entry.Stats
+	return []string{
+		entry.Ref,
+		entry.Date.Format("2006-01-02"),
+		shortCommit(entry.Commit),
+		strconv.Itoa(s.Modules),
+		strconv.Itoa(s.Edges),
+		strconv.Itoa(s.Layers),
+		strconv.FormatFloat(s.AvgFanOut, 'f', -1, 64),
+		strconv.Itoa(s.MaxFanIn),
+		strconv.Itoa(s.MaxFanOut),
+		strconv.FormatFloat(s.AvgInstability, 'f', -1, 64),
+		strconv.Itoa(s.MaxDepth),
+		strconv.Itoa(s.CycleGroups),
+		strconv.Itoa(s.CyclicModules),
+	}
+}
+
+// shortCommit abbreviates a commit hash
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/justin4957/graphfs/pkg/analysis"
 	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
 	"github.com/justin4957/graphfs/pkg/viz"
 	"github.com/spf13/cobra"
 )
@@ -17,6 +20,11 @@ var (
 	impactFormat  string
 	impactCompare bool
 	impactViz     string
+	impactReverse bool
+	impactSince   string
+	impactOutput  string
+	impactFailOn  string
+	impactByOwner bool
 )
 
 var impactCmd = &cobra.Command{
@@ -44,7 +52,19 @@ Examples:
   graphfs impact --compare --modules utils/crypto.go,utils/logger.go,utils/validator.go
 
   # Output as JSON
-  graphfs impact services/auth.go --format json`,
+  graphfs impact services/auth.go --format json
+
+  # Reverse mode: everything a module depends on transitively, and which
+  # direct dependency pulls in each one
+  graphfs impact main.go --reverse
+
+  # Merged impact of everything changed on a branch, for CI
+  graphfs impact --since origin/main --format markdown -o impact.md
+  graphfs impact --since origin/main --format json --fail-on high
+
+  # Which teams does this change impact (CODEOWNERS or code:owner)
+  graphfs impact services/auth.go --by-owner
+  graphfs impact --since origin/main --by-owner --format markdown`,
 	RunE: runImpact,
 }
 
@@ -52,12 +72,24 @@ func init() {
 	rootCmd.AddCommand(impactCmd)
 
 	impactCmd.Flags().StringSliceVarP(&impactModules, "modules", "m", nil, "Comma-separated list of modules to analyze")
-	impactCmd.Flags().StringVarP(&impactFormat, "format", "f", "text", "Output format (text, json)")
+	impactCmd.Flags().StringVarP(&impactFormat, "format", "f", "text", "Output format (text, json, markdown with --since)")
 	impactCmd.Flags().BoolVarP(&impactCompare, "compare", "c", false, "Compare impacts of multiple modules")
 	impactCmd.Flags().StringVar(&impactViz, "viz", "", "Generate visualization (e.g., impact.svg)")
+	impactCmd.Flags().BoolVarP(&impactReverse, "reverse", "r", false, "Show the transitive dependency closure with the direct edge pulling in each dependency")
+	impactCmd.Flags().StringVar(&impactSince, "since", "", "Analyze the modules changed since a git ref (e.g. origin/main)")
+	impactCmd.Flags().StringVarP(&impactOutput, "output", "o", "", "Write the --since report to a file")
+	impactCmd.Flags().BoolVar(&impactByOwner, "by-owner", false, "Group changed and impacted modules by owner")
+	impactCmd.Flags().StringVar(&impactFailOn, "fail-on", "", "With --since, exit with status 1 at this risk level or above (low, medium, high, critical)")
 }
 
 func runImpact(cmd *cobra.Command, args []string) error {
+	if impactSince != "" {
+		if len(args) > 0 || len(impactModules) > 0 {
+			return fmt.Errorf("--since cannot be combined with module arguments")
+		}
+		return runSinceImpact()
+	}
+
 	// Determine which modules to analyze
 	var modulesToAnalyze []string
 
@@ -93,6 +125,17 @@ func runImpact(cmd *cobra.Command, args []string) error {
 	ia := analysis.NewImpactAnalysis(g)
 
 	// Perform analysis
+	if impactReverse {
+		if len(modulesToAnalyze) != 1 {
+			return fmt.Errorf("--reverse analyzes a single module")
+		}
+		return runReverseImpact(ia, modulesToAnalyze[0])
+	}
+
+	if impactByOwner {
+		return runOwnerImpact(ia, g, modulesToAnalyze)
+	}
+
 	if impactCompare && len(modulesToAnalyze) > 1 {
 		return runCompareImpacts(ia, g, modulesToAnalyze)
 	}
@@ -161,6 +204,180 @@ func runMultipleImpact(ia *analysis.ImpactAnalysis, g *graph.Graph, modules []st
 	return printImpactText(result)
 }
 
+// runSinceImpact analyzes the combined impact of the modules changed since a
+// git ref
+func runSinceImpact() error {
+	var failOn analysis.RiskLevel
+	if impactFailOn != "" {
+		failOn = analysis.RiskLevel(strings.ToUpper(impactFailOn))
+		if riskRank(failOn) == 0 {
+			return fmt.Errorf("invalid --fail-on level: %s (must be low, medium, high, or critical)", impactFailOn)
+		}
+	}
+	if impactFormat != "text" && impactFormat != "json" && impactFormat != "markdown" && impactFormat != "md" {
+		return fmt.Errorf("unknown format %q (use text, json or markdown)", impactFormat)
+	}
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	gitFilter := scanner.NewGitFilter(absPath)
+	if !gitFilter.IsGitRepository() {
+		return fmt.Errorf("not a git repository: %s", absPath)
+	}
+	files, err := gitFilter.ChangedInRange(impactSince + "...HEAD")
+	if err != nil {
+		return err
+	}
+	changed := make([]string, 0, len(files))
+	for _, file := range files {
+		if rel, err := filepath.Rel(absPath, file); err == nil {
+			changed = append(changed, filepath.ToSlash(rel))
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		Validate:       false,
+		ReportProgress: false,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Loaded %d modules, %d file(s) changed since %s\n\n", len(g.Modules), len(changed), impactSince)
+
+	impact, err := analysis.NewImpactAnalysis(g).AnalyzeChange(changed)
+	if err != nil {
+		return fmt.Errorf("impact analysis failed: %w", err)
+	}
+	impact.Range = impactSince + "...HEAD"
+	if impactByOwner {
+		modules := make([]string, 0, len(impact.Modules))
+		for _, module := range impact.Modules {
+			modules = append(modules, module.Path)
+		}
+		var dependents map[string]int
+		if impact.Combined != nil {
+			dependents = impact.Combined.TransitiveDependents
+		}
+		impact.Owners = analysis.ImpactByOwner(g, modules, dependents)
+	}
+
+	var output string
+	switch impactFormat {
+	case "json":
+		data, err := json.MarshalIndent(impact, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		output = string(data) + "\n"
+	case "markdown", "md":
+		output = impact.Markdown()
+	}
+
+	switch {
+	case impactOutput != "":
+		if output == "" {
+			output = impact.Markdown()
+		}
+		if err := os.WriteFile(impactOutput, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		color.Green("✓ Impact report written to %s\n", impactOutput)
+	case output != "":
+		fmt.Print(output)
+	default:
+		printChangeImpactText(impact)
+	}
+
+	if failOn != "" && riskRank(impact.RiskLevel) >= riskRank(failOn) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func printChangeImpactText(impact *analysis.ChangeImpact) {
+	cyan := color.New(color.FgCyan, color.Bold)
+
+	cyan.Printf("🔍 Change Impact: %s (%d modules)\n\n", impact.Range, len(impact.Modules))
+	for _, module := range impact.Modules {
+		fmt.Printf("  • %s: %s, %d impacted\n", module.Path, getRiskColor(module.RiskLevel).Sprint(module.RiskLevel), module.TotalImpactedModules)
+	}
+	if len(impact.OtherFiles) > 0 {
+		fmt.Printf("  • %d other file(s)\n", len(impact.OtherFiles))
+	}
+	fmt.Println()
+
+	if impact.Combined == nil {
+		fmt.Println("No modules changed.")
+		return
+	}
+	printImpactText(impact.Combined)
+	if impact.Owners != nil {
+		printOwnerImpactText(impact.Owners)
+	}
+}
+
+// runOwnerImpact reports the combined impact of modules grouped by owner
+func runOwnerImpact(ia *analysis.ImpactAnalysis, g *graph.Graph, modules []string) error {
+	result, err := ia.AnalyzeMultipleModules(modules)
+	if err != nil {
+		return fmt.Errorf("impact analysis failed: %w", err)
+	}
+	owners := analysis.ImpactByOwner(g, modules, result.TransitiveDependents)
+
+	if impactFormat == "json" {
+		data, err := json.MarshalIndent(owners, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printOwnerImpactText(owners)
+	return nil
+}
+
+func printOwnerImpactText(owners []analysis.OwnerImpact) {
+	cyan := color.New(color.FgCyan, color.Bold)
+
+	cyan.Printf("👥 Impacted Owners (%d)\n\n", len(owners))
+	if len(owners) == 0 {
+		fmt.Println("No modules impacted.")
+		return
+	}
+	for _, entry := range owners {
+		owner := entry.Owner
+		if owner == "" {
+			owner = "(unowned)"
+		}
+		fmt.Printf("  %s: %d changed, %d impacted\n", owner, len(entry.Changed), len(entry.Impacted))
+		for _, path := range entry.Changed {
+			fmt.Printf("    • %s (changed)\n", path)
+		}
+		for _, path := range entry.Impacted {
+			fmt.Printf("    • %s\n", path)
+		}
+	}
+	fmt.Println()
+}
+
+// riskRank orders risk levels from low to critical, 0 for unknown levels
+func riskRank(level analysis.RiskLevel) int {
+	switch level {
+	case analysis.RiskLevelLow:
+		return 1
+	case analysis.RiskLevelMedium:
+		return 2
+	case analysis.RiskLevelHigh:
+		return 3
+	case analysis.RiskLevelCritical:
+		return 4
+	}
+	return 0
+}
+
 func runCompareImpacts(ia *analysis.ImpactAnalysis, g *graph.Graph, modules []string) error {
 	results, err := ia.CompareImpacts(modules)
 	if err != nil {
@@ -226,7 +443,7 @@ func printImpactText(result *analysis.ImpactResult) error {
 
 	// Impact Summary
 	yellow.Println("Impact Summary:")
-	fmt.Printf("  • Direct Dependents: %d\n", len(result.DirectDependents))
+	fmt.Printf("  • Direct Dependents: %d (%d call sites)\n", len(result.DirectDependents), result.DirectCallSites)
 	fmt.Printf("  • Direct Dependencies: %d\n", len(result.DirectDependencies))
 	fmt.Printf("  • Total Impacted Modules: %d (%.1f%% of codebase)\n",
 		result.TotalImpactedModules,
@@ -292,6 +509,7 @@ func printImpactJSON(result *analysis.ImpactResult) error {
 	fmt.Printf("  \"total_impacted_modules\": %d,\n", result.TotalImpactedModules)
 	fmt.Printf("  \"impact_percentage\": %.2f,\n", result.ImpactPercentage)
 	fmt.Printf("  \"direct_dependents\": %d,\n", len(result.DirectDependents))
+	fmt.Printf("  \"direct_call_sites\": %d,\n", result.DirectCallSites)
 	fmt.Printf("  \"direct_dependencies\": %d,\n", len(result.DirectDependencies))
 	fmt.Printf("  \"max_impact_depth\": %d,\n", result.MaxImpactDepth)
 	fmt.Printf("  \"layers_impacted\": %d\n", len(result.ImpactByLayer))
@@ -299,6 +517,78 @@ func printImpactJSON(result *analysis.ImpactResult) error {
 	return nil
 }
 
+func runReverseImpact(ia *analysis.ImpactAnalysis, modulePath string) error {
+	result, err := ia.AnalyzeReverseImpact(modulePath)
+	if err != nil {
+		return fmt.Errorf("reverse impact analysis failed: %w", err)
+	}
+
+	if impactFormat == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return printReverseImpactText(result)
+}
+
+func printReverseImpactText(result *analysis.ReverseImpactResult) error {
+	cyan := color.New(color.FgCyan, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	gray := color.New(color.FgHiBlack)
+
+	// Header
+	cyan.Printf("🔍 Reverse Impact Analysis: %s\n\n", result.TargetModule)
+
+	// Summary
+	yellow.Println("Dependency Summary:")
+	fmt.Printf("  • Direct Dependencies: %d\n", len(result.DirectDependencies))
+	fmt.Printf("  • Total Dependencies: %d (%.1f%% of codebase)\n",
+		result.TotalDependencies,
+		result.DependencyPercentage)
+	fmt.Printf("  • Maximum Depth: %d\n", result.MaxDepth)
+	fmt.Println()
+
+	// What each direct edge pulls in
+	if len(result.DirectDependencies) > 0 {
+		yellow.Println("Direct Dependencies:")
+		for _, direct := range result.DirectDependencies {
+			fmt.Printf("  • %s: pulls in %d, %d only via this edge\n",
+				direct.Dependency, len(direct.PullsIn), len(direct.Exclusive))
+			for _, path := range direct.Exclusive {
+				gray.Printf("      - %s\n", path)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Transitive closure with reasons
+	if len(result.Dependencies) > 0 {
+		yellow.Println("Transitive Dependencies:")
+		for _, dep := range result.Dependencies {
+			if dep.Depth == 1 {
+				continue
+			}
+			fmt.Printf("  • %s (depth %d) via %s\n", dep.Module, dep.Depth, strings.Join(dep.ViaAll, ", "))
+			gray.Printf("      %s\n", strings.Join(dep.Path, " → "))
+		}
+		fmt.Println()
+	}
+
+	if len(result.UnresolvedDependencies) > 0 {
+		yellow.Println("Unresolved Dependencies:")
+		for _, path := range result.UnresolvedDependencies {
+			fmt.Printf("  • %s\n", path)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
 func getRiskColor(level analysis.RiskLevel) *color.Color {
 	switch level {
 	case analysis.RiskLevelCritical:
@@ -37,6 +37,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/justin4957/graphfs/pkg/query"
 	"github.com/spf13/cobra"
 )
 
@@ -45,7 +46,7 @@ var initCmd = &cobra.Command{
 	Use:   "init [path]",
 	Short: "Initialize GraphFS in a directory",
 	Long: `Initialize GraphFS in a directory by creating the .graphfs configuration
-directory, config file, and .graphfsignore file.
+directory, config file, shared prefixes file, and .graphfsignore file.
 
 Examples:
   graphfs init                  # Initialize in current directory
@@ -100,6 +101,15 @@ func runInit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("⚠ .graphfsignore file already exists: %s\n", ignorePath)
 	}
 
+	// Create shared prefixes file
+	prefixesPath := filepath.Join(graphfsDir, query.PrefixesFile)
+	if _, err := os.Stat(prefixesPath); os.IsNotExist(err) {
+		if err := createDefaultPrefixesFile(prefixesPath); err != nil {
+			return fmt.Errorf("failed to create prefixes file: %w", err)
+		}
+		fmt.Printf("✓ Created prefixes file: %s\n", prefixesPath)
+	}
+
 	// Create empty store directory
 	storeDir := filepath.Join(graphfsDir, "store")
 	if err := os.MkdirAll(storeDir, 0755); err != nil {
@@ -116,6 +126,16 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// createDefaultPrefixesFile creates a default .graphfs/prefixes.ttl file
+func createDefaultPrefixesFile(path string) error {
+	content := `# Shared prefixes, prepended to every query, template and rule pattern
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+`
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
 // createDefaultIgnoreFile creates a default .graphfsignore file
 func createDefaultIgnoreFile(path string) error {
 	content := `# GraphFS ignore patterns
@@ -0,0 +1,261 @@
+/*
+# Module: cmd/graphfs/cmd_links.go
+Links command implementation.
+
+Reports declared links whose targets don't resolve to a module, with the
+module paths they most likely meant, and with --fix rewrites the LinkedDoc
+headers to point at the fix.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [lock](./lock.go) - Workspace lock
+- [../../pkg/graph](../../pkg/graph/links.go) - Unresolved link detection
+- [../../pkg/shadow](../../pkg/shadow/linkfix.go) - Header link rewrites
+
+## Tags
+cli, command, links, validation, refactoring
+
+## Exports
+linksCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_links.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_links.go" ;
+	code:description "Links command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <./lock.go>, <../../pkg/graph/links.go>, <../../pkg/shadow/linkfix.go> ;
+	code:exports <#linksCmd> ;
+	code:tags "cli", "command", "links", "validation", "refactoring" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	linksFix    bool
+	linksDryRun bool
+	linksOutput string
+)
+
+var linksCmd = &cobra.Command{
+	Use:   "links [path]",
+	Short: "Report unresolved links with suggested fixes",
+	Long: `Report linksTo (and imports, uses, ...) targets that don't resolve.
+
+A declared link whose target is neither a module nor an existing file is
+kept by the graph builder but connects to nothing, so the dependency is
+missing from impact analysis, cycles and visualizations. Each unresolved
+link is listed with the module paths it most likely meant: the closest file
+names first, then the closest full paths. Files renamed with 'graphfs
+rename' are suggested from their recorded alias.
+
+With --fix, links whose best suggestion is unambiguous are rewritten in the
+LinkedDoc header, in code:linksTo and the Linked Modules section. Links
+with several equally close suggestions are left for you to choose.
+
+Examples:
+  graphfs links
+  graphfs links --fix --dry-run   # Show the header changes as diffs
+  graphfs links --fix
+  graphfs links --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLinks,
+}
+
+func init() {
+	rootCmd.AddCommand(linksCmd)
+
+	linksCmd.Flags().BoolVar(&linksFix, "fix", false, "Rewrite LinkedDoc headers to the unambiguous suggestion")
+	linksCmd.Flags().BoolVar(&linksDryRun, "dry-run", false, "With --fix, print diffs instead of writing files")
+	linksCmd.Flags().StringVarP(&linksOutput, "output", "o", "text", "Output format (text, json)")
+}
+
+func runLinks(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if linksOutput != "text" && linksOutput != "json" {
+		return fmt.Errorf("unknown output format %q (use text or json)", linksOutput)
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	links := g.UnresolvedLinks()
+	applyRenameAliases(absPath, g, links)
+
+	if linksFix {
+		return fixLinks(out, absPath, links)
+	}
+
+	if linksOutput == "json" {
+		if links == nil {
+			links = []graph.UnresolvedLink{}
+		}
+		data, err := json.MarshalIndent(links, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize links: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(links) == 0 {
+		out.Success("All links resolve")
+		return nil
+	}
+
+	out.Header(fmt.Sprintf("Unresolved Links (%d)", len(links)))
+	out.Println("")
+	fixable := 0
+	for _, link := range links {
+		out.Println("%s -> %s (%s)", link.Module, link.Target, link.Relation)
+		switch {
+		case link.Fix != "":
+			out.Println("  did you mean %s?", relativeLinkFrom(link.Module, link.Fix))
+			fixable++
+		case len(link.Suggestions) > 0:
+			candidates := make([]string, 0, len(link.Suggestions))
+			for _, suggestion := range link.Suggestions {
+				candidates = append(candidates, relativeLinkFrom(link.Module, suggestion))
+			}
+			out.Println("  candidates: %s", strings.Join(candidates, ", "))
+		default:
+			out.Println("  no similar module")
+		}
+	}
+	out.Println("")
+	if fixable > 0 {
+		out.Info("%d link(s) can be fixed with: graphfs links --fix", fixable)
+	}
+	return nil
+}
+
+// applyRenameAliases makes the recorded new path of a renamed target the
+// fix of its links, when that path is a module
+func applyRenameAliases(absPath string, g *graph.Graph, links []graph.UnresolvedLink) {
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return
+	}
+	for i := range links {
+		renamed := filepath.ToSlash(shadowFS.ResolveAlias(links[i].Target))
+		if renamed == links[i].Target || g.GetModule(renamed) == nil {
+			continue
+		}
+		links[i].Fix = renamed
+		suggestions := []string{renamed}
+		for _, suggestion := range links[i].Suggestions {
+			if suggestion != renamed {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+		links[i].Suggestions = suggestions
+	}
+}
+
+// fixLinks rewrites the headers of links with a fix, or prints the diffs
+// with --dry-run
+func fixLinks(out *cli.OutputFormatter, absPath string, links []graph.UnresolvedLink) error {
+	patches, err := shadow.PlanLinkFixes(absPath, links)
+	if err != nil {
+		return fmt.Errorf("failed to plan link fixes: %w", err)
+	}
+
+	skipped := 0
+	for _, link := range links {
+		if link.Fix == "" {
+			skipped++
+		}
+	}
+
+	if linksDryRun {
+		for _, patch := range patches {
+			fmt.Print(patch.UnifiedDiff())
+		}
+		out.Println("")
+		out.Info("%d file(s) would be updated, %d link(s) need a manual fix", len(patches), skipped)
+		return nil
+	}
+
+	if len(patches) > 0 {
+		unlock, err := lockWorkspace(absPath, out)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		for _, patch := range patches {
+			if err := patch.Apply(absPath); err != nil {
+				return err
+			}
+			out.Println("  updated %s", patch.Path)
+		}
+	}
+
+	out.Success("Updated %d file(s)", len(patches))
+	if skipped > 0 {
+		out.Info("%d link(s) need a manual fix; run 'graphfs links' to see candidates", skipped)
+	}
+	return nil
+}
+
+// relativeLinkFrom returns target as a LinkedDoc link from module's
+// directory, as it would be written in module's header
+func relativeLinkFrom(module, target string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(module)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
@@ -0,0 +1,224 @@
+/*
+# Module: cmd/graphfs/cmd_lint_docs.go
+Lint-docs command implementation.
+
+Checks LinkedDoc headers for style conformance: required sections, Linked
+Modules matching code:linksTo, description length and tag vocabulary, with
+suggested fixes and a GitHub Actions annotation output.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Tag vocabulary configuration
+- [../../pkg/doclint](../../pkg/doclint/doclint.go) - LinkedDoc header linter
+- [../../pkg/scanner](../../pkg/scanner/scanner.go) - File scanning
+
+## Tags
+cli, command, lint, docs
+
+## Exports
+lintDocsCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_lint_docs.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_lint_docs.go" ;
+	code:description "Lint-docs command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <../../pkg/doclint/doclint.go>, <../../pkg/scanner/scanner.go> ;
+	code:exports <#lintDocsCmd> ;
+	code:tags "cli", "command", "lint", "docs" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/doclint"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintDocsFormat string
+	lintDocsStrict bool
+)
+
+var lintDocsCmd = &cobra.Command{
+	Use:   "lint-docs [path]",
+	Short: "Check LinkedDoc headers for style conformance",
+	Long: `Check LinkedDoc headers for style conformance.
+
+Lints the headers themselves, not the graph they describe:
+
+  missing-section     '# Module:', '## Tags' or '## Exports' is missing
+  module-name         '# Module:' or code:name does not name the file
+  links-mismatch      '## Linked Modules' and code:linksTo differ
+  tags-mismatch       '## Tags' and code:tags differ
+  tag-format          A tag is not lowercase words joined by hyphens
+  tag-vocabulary      A tag is not in the configured vocabulary
+  tag-deprecated      A tag was deprecated with 'graphfs tags deprecate'
+  description         code:description is missing
+  description-length  code:description is too short or too long
+  rdf-syntax          The RDF block does not parse
+
+Every issue comes with a suggested fix. The tag vocabulary and description
+bounds are configured in .graphfs/config.yaml:
+
+  lint_docs:
+    tags: [api, cli, docs, server]
+    description_min: 10
+    description_max: 100
+
+Missing sections and descriptions are errors and fail the command; other
+issues are warnings, which fail it with --strict.
+
+Examples:
+  graphfs lint-docs
+  graphfs lint-docs pkg/server --strict
+  graphfs lint-docs --format json
+
+  # Annotate pull requests in GitHub Actions
+  graphfs lint-docs --format github`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLintDocs,
+}
+
+func init() {
+	rootCmd.AddCommand(lintDocsCmd)
+
+	lintDocsCmd.Flags().StringVarP(&lintDocsFormat, "format", "f", "text", "Output format (text, json, github)")
+	lintDocsCmd.Flags().BoolVar(&lintDocsStrict, "strict", false, "Fail on warnings as well as errors")
+}
+
+func runLintDocs(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	opts := lintDocsOptions(absPath, config, out)
+
+	out.Debug("Scanning %s...", absPath)
+	scanResult, err := scanner.NewScanner().Scan(absPath, scanner.ScanOptions{
+		IncludePatterns: config.Scan.Include,
+		ExcludePatterns: config.Scan.Exclude,
+		MaxFileSize:     config.Scan.MaxFileSize,
+		UseDefaults:     true,
+		IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+		Concurrent:      true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan: %w", err)
+	}
+
+	linter := doclint.NewLinter(opts)
+	report := &doclint.Report{}
+	for _, file := range scanResult.Files {
+		if !file.HasLinkedDoc {
+			continue
+		}
+		relPath, err := filepath.Rel(absPath, file.Path)
+		if err != nil {
+			relPath = file.Path
+		}
+		issues, err := linter.LintFile(absPath, relPath, file.Language)
+		if err != nil {
+			return err
+		}
+		report.Add(issues)
+	}
+
+	switch lintDocsFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "github":
+		fmt.Print(report.GitHubAnnotations())
+	case "text":
+		printLintDocsReport(out, report)
+	default:
+		return fmt.Errorf("unknown format %q (use text, json or github)", lintDocsFormat)
+	}
+
+	if report.HasErrors(lintDocsStrict) {
+		return fmt.Errorf("LinkedDoc headers have %d error(s) and %d warning(s)", report.Errors, report.Warnings)
+	}
+	return nil
+}
+
+// printLintDocsReport prints each issue with its suggested fix
+func printLintDocsReport(out *cli.OutputFormatter, report *doclint.Report) {
+	for _, issue := range report.Issues {
+		location := fmt.Sprintf("%s:%d", issue.Path, issue.Line)
+		if issue.Severity == doclint.SeverityError {
+			out.Error("%s: %s [%s]", location, issue.Message, issue.Rule)
+		} else {
+			out.Warning("%s: %s [%s]", location, issue.Message, issue.Rule)
+		}
+		if issue.Fix != "" {
+			out.Println("    fix: %s", issue.Fix)
+		}
+	}
+
+	if len(report.Issues) > 0 {
+		out.Println("")
+	}
+	if report.Errors == 0 && report.Warnings == 0 {
+		out.Success("%d LinkedDoc header(s) conform", report.Files)
+		return
+	}
+	out.Info("%d LinkedDoc header(s): %d error(s), %d warning(s)", report.Files, report.Errors, report.Warnings)
+}
+
+// lintDocsOptions returns the linter options from the config, with the
+// deprecated tags of the tag taxonomy when there is one
+func lintDocsOptions(absPath string, config *Config, out *cli.OutputFormatter) doclint.Options {
+	opts := doclint.Options{
+		Vocabulary:     config.LintDocs.Tags,
+		MinDescription: config.LintDocs.DescriptionMin,
+		MaxDescription: config.LintDocs.DescriptionMax,
+		Deprecated:     make(map[string]string),
+	}
+
+	// Deprecated tags are optional
+	if shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig()); err == nil {
+		if taxonomy, err := shadowFS.LoadTaxonomy(); err == nil {
+			for tag, deprecated := range taxonomy.Deprecated {
+				opts.Deprecated[tag] = deprecated.Replacement
+			}
+		} else {
+			out.Debug("No tag taxonomy: %v", err)
+		}
+	}
+	return opts
+}
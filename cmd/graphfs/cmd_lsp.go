@@ -0,0 +1,129 @@
+/*
+# Module: cmd/graphfs/cmd_lsp.go
+Language server command implementation.
+
+Runs the LinkedDoc language server over stdio for editors: hovers on linked
+modules, go-to-definition on linksTo targets, diagnostics for broken links
+and doclint issues, and completion of code: predicates.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [cmd_lint_docs](./cmd_lint_docs.go) - doclint options
+- [lifecycle](./lifecycle.go) - Graceful shutdown
+- [../../pkg/lsp](../../pkg/lsp/server.go) - LinkedDoc language server
+
+## Tags
+cli, command, lsp, editor
+
+## Exports
+lspCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_lsp.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_lsp.go" ;
+	code:description "Language server command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <./cmd_lint_docs.go>, <./lifecycle.go>, <../../pkg/lsp/server.go> ;
+	code:exports <#lspCmd> ;
+	code:tags "cli", "command", "lsp", "editor" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/lsp"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp [path]",
+	Short: "Run the LinkedDoc language server",
+	Long: `Run the LinkedDoc language server over stdio.
+
+Editors start the server with the project root and talk the Language Server
+Protocol on stdin and stdout. In LinkedDoc blocks it provides:
+
+  • Hover on linksTo, imports, extends, implements and uses targets with the
+    module's description, layer, language and tags
+  • Go-to-definition on link targets
+  • Diagnostics for links to missing files or to files that are not modules,
+    and the issues reported by 'graphfs lint-docs'
+  • Completion of code: predicates, and of classes after "a"
+
+The knowledge graph is built when the editor connects and rebuilt whenever
+a file is saved. Logs are written to stderr.
+
+Examples:
+  graphfs lsp
+  graphfs lsp /path/to/project --verbose`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLSP,
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	// Stdout carries the protocol
+	out := cli.NewOutputFormatter(quiet, verbose, true)
+	out.SetWriter(os.Stderr)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	server := lsp.NewServer(lsp.Options{
+		Root: absPath,
+		Load: func() (*graph.Graph, error) {
+			return graph.NewBuilder().Build(absPath, graph.BuildOptions{
+				ScanOptions: scanner.ScanOptions{
+					IncludePatterns: config.Scan.Include,
+					ExcludePatterns: config.Scan.Exclude,
+					MaxFileSize:     config.Scan.MaxFileSize,
+					UseDefaults:     true,
+					IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+					Concurrent:      true,
+				},
+				BaseIRI: config.URIs.Base,
+			})
+		},
+		Lint: lintDocsOptions(absPath, config, out),
+		Logf: out.Debug,
+	})
+
+	out.Debug("Serving LinkedDoc language server for %s", absPath)
+	lc := newLifecycle()
+	if err := server.Run(lc.Context(), os.Stdin, os.Stdout); err != nil {
+		return err
+	}
+	return shutdownLifecycle(lc, out)
+}
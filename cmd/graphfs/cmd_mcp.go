@@ -0,0 +1,140 @@
+/*
+# Module: cmd/graphfs/cmd_mcp.go
+MCP server command implementation.
+
+Builds the knowledge graph and serves it over stdio as Model Context
+Protocol tools, so LLM coding assistants can query modules, dependencies,
+change impact and concepts of the project.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [cmd_effective](./cmd_effective.go) - Effective shadow metadata
+- [lifecycle](./lifecycle.go) - Graceful shutdown
+- [../../pkg/mcp](../../pkg/mcp/server.go) - MCP server
+
+## Tags
+cli, command, mcp, ai
+
+## Exports
+mcpCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_mcp.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_mcp.go" ;
+	code:description "MCP server command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <./cmd_effective.go>, <./lifecycle.go>, <../../pkg/mcp/server.go> ;
+	code:exports <#mcpCmd> ;
+	code:tags "cli", "command", "mcp", "ai" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/mcp"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp [path]",
+	Short: "Serve the knowledge graph to AI assistants over MCP",
+	Long: `Serve the knowledge graph to AI assistants over the Model Context Protocol.
+
+MCP clients start the server and exchange JSON-RPC messages on stdin and
+stdout. The graph is built once at start; restart the server to pick up
+changes. Logs are written to stderr.
+
+Tools:
+  • query_modules     - Modules matching a filter expression, or a SPARQL query
+  • get_dependencies  - Dependencies and dependents of a module, optionally transitive
+  • impact_analysis   - Risk and transitive impact of changing modules
+  • search_by_concept - Modules ranked by shadow concepts, tags and descriptions
+
+Examples:
+  graphfs mcp
+  graphfs mcp /path/to/project --verbose`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	// Stdout carries the protocol
+	out := cli.NewOutputFormatter(quiet, verbose, true)
+	out.SetWriter(os.Stderr)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	// Shadow metadata adds concepts and fills in layers and tags
+	concepts := make(map[string][]string)
+	if resolver, err := newEffectiveResolver(absPath); err != nil {
+		out.Debug("No shadow metadata: %v", err)
+	} else {
+		if _, err := resolver.ApplyToGraph(g); err != nil {
+			out.Debug("Could not apply shadow metadata: %v", err)
+		}
+		for path := range g.Modules {
+			if meta, err := resolver.Resolve(path); err == nil && len(meta.Concepts) > 0 {
+				concepts[path] = meta.Concepts
+			}
+		}
+	}
+
+	server := mcp.NewServer(g, mcp.Options{Version: Version, Concepts: concepts, Logf: out.Debug})
+	out.Debug("Serving %d modules over MCP", len(g.Modules))
+
+	lc := newLifecycle()
+	if err := server.Run(lc.Context(), os.Stdin, os.Stdout); err != nil {
+		return err
+	}
+	return shutdownLifecycle(lc, out)
+}
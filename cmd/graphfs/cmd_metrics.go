@@ -0,0 +1,253 @@
+/*
+# Module: cmd/graphfs/cmd_metrics.go
+Metrics command implementation.
+
+Reports per-module structural metrics (fan-in, fan-out, instability,
+abstractness, depth from entry points and betweenness) as a table, JSON or
+CSV, and fails when a module exceeds a configured threshold.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [../../pkg/analysis](../../pkg/analysis/metrics.go) - Structural metrics
+
+## Tags
+cli, command, metrics, coupling
+
+## Exports
+metricsCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_metrics.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_metrics.go" ;
+	code:description "Metrics command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <../../pkg/analysis/metrics.go> ;
+	code:exports <#metricsCmd> ;
+	code:tags "cli", "command", "metrics", "coupling" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsFormat     string
+	metricsSort       string
+	metricsTop        int
+	metricsThresholds analysis.MetricThresholds
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics [path]",
+	Short: "Report structural metrics for each module",
+	Long: `Report structural metrics for each module.
+
+Metrics:
+  fan_in        Modules depending on the module (afferent coupling, Ca)
+  fan_out       Modules the module depends on (efferent coupling, Ce)
+  instability   Ce/(Ca+Ce): 0 for modules only depended on, 1 for modules
+                only depending on others
+  abstractness  Share of declared types that are interfaces (Go only)
+  distance      |abstractness + instability - 1|, the distance from the main
+                sequence (Go only)
+  depth         Dependency hops from the nearest entry point (-1 if unreachable)
+  betweenness   Share of shortest dependency paths passing through the module
+
+With any --max-* threshold the command exits with status 1 when a module
+exceeds it, listing the violations on stderr.
+
+Examples:
+  graphfs metrics
+  graphfs metrics --sort betweenness --top 10
+  graphfs metrics --format csv > metrics.csv
+
+  # Fail CI on hubs and deep dependency chains
+  graphfs metrics --max-fan-in 25 --max-depth 8 --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMetrics,
+}
+
+func init() {
+	rootCmd.AddCommand(metricsCmd)
+
+	metricsCmd.Flags().StringVarP(&metricsFormat, "format", "f", "table", "Output format (table, json, csv)")
+	metricsCmd.Flags().StringVar(&metricsSort, "sort", "path", "Sort by path, fan-in, fan-out, instability, distance, depth or betweenness")
+	metricsCmd.Flags().IntVar(&metricsTop, "top", 0, "Show only the first N modules")
+	metricsCmd.Flags().IntVar(&metricsThresholds.MaxFanIn, "max-fan-in", 0, "Fail when a module's fan-in exceeds N")
+	metricsCmd.Flags().IntVar(&metricsThresholds.MaxFanOut, "max-fan-out", 0, "Fail when a module's fan-out exceeds N")
+	metricsCmd.Flags().Float64Var(&metricsThresholds.MaxInstability, "max-instability", 0, "Fail when a module's instability exceeds this value")
+	metricsCmd.Flags().Float64Var(&metricsThresholds.MaxDistance, "max-distance", 0, "Fail when a module's distance from the main sequence exceeds this value")
+	metricsCmd.Flags().IntVar(&metricsThresholds.MaxDepth, "max-depth", 0, "Fail when a module is more than N hops from an entry point")
+	metricsCmd.Flags().Float64Var(&metricsThresholds.MaxBetweenness, "max-betweenness", 0, "Fail when a module's betweenness exceeds this value")
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if metricsFormat != "table" && metricsFormat != "json" && metricsFormat != "csv" {
+		return fmt.Errorf("unknown format %q (use table, json or csv)", metricsFormat)
+	}
+	less, err := metricsOrder(metricsSort)
+	if err != nil {
+		return err
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	report := analysis.ComputeMetrics(g)
+	violations := report.Check(metricsThresholds)
+
+	modules := append([]*analysis.StructuralMetrics(nil), report.Modules...)
+	sort.SliceStable(modules, func(i, j int) bool { return less(modules[i], modules[j]) })
+	if metricsTop > 0 && len(modules) > metricsTop {
+		modules = modules[:metricsTop]
+	}
+
+	switch metricsFormat {
+	case "json":
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"modules":    modules,
+			"violations": violations,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write(metricsHeaders)
+		for _, m := range modules {
+			_ = w.Write(metricsRow(m))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	default:
+		out.Header("Module Metrics")
+		out.Println("")
+		rows := make([][]string, 0, len(modules))
+		for _, m := range modules {
+			rows = append(rows, metricsRow(m))
+		}
+		out.Table(metricsHeaders, rows)
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			fmt.Fprintf(os.Stderr, "Threshold exceeded: %s\n", v)
+		}
+		os.Exit(1)
+	}
+	return nil
+}
+
+var metricsHeaders = []string{"Module", "Layer", "Fan-in", "Fan-out", "Instability", "Abstractness", "Distance", "Depth", "Betweenness"}
+
+func metricsRow(m *analysis.StructuralMetrics) []string {
+	optional := func(value *float64) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*value, 'f', 2, 64)
+	}
+	return []string{
+		m.Path,
+		m.Layer,
+		strconv.Itoa(m.FanIn),
+		strconv.Itoa(m.FanOut),
+		strconv.FormatFloat(m.Instability, 'f', 2, 64),
+		optional(m.Abstractness),
+		optional(m.Distance),
+		strconv.Itoa(m.Depth),
+		strconv.FormatFloat(m.Betweenness, 'f', 4, 64),
+	}
+}
+
+// metricsOrder returns the ordering for a --sort key: metrics descending,
+// then path
+func metricsOrder(key string) (func(a, b *analysis.StructuralMetrics) bool, error) {
+	var value func(m *analysis.StructuralMetrics) float64
+	switch key {
+	case "path":
+		return func(a, b *analysis.StructuralMetrics) bool { return a.Path < b.Path }, nil
+	case "fan-in":
+		value = func(m *analysis.StructuralMetrics) float64 { return float64(m.FanIn) }
+	case "fan-out":
+		value = func(m *analysis.StructuralMetrics) float64 { return float64(m.FanOut) }
+	case "instability":
+		value = func(m *analysis.StructuralMetrics) float64 { return m.Instability }
+	case "distance":
+		value = func(m *analysis.StructuralMetrics) float64 {
+			if m.Distance == nil {
+				return -1
+			}
+			return *m.Distance
+		}
+	case "depth":
+		value = func(m *analysis.StructuralMetrics) float64 { return float64(m.Depth) }
+	case "betweenness":
+		value = func(m *analysis.StructuralMetrics) float64 { return m.Betweenness }
+	default:
+		return nil, fmt.Errorf("unknown sort key %q (use path, fan-in, fan-out, instability, distance, depth or betweenness)", key)
+	}
+	return func(a, b *analysis.StructuralMetrics) bool {
+		if value(a) != value(b) {
+			return value(a) > value(b)
+		}
+		return a.Path < b.Path
+	}, nil
+}
@@ -0,0 +1,181 @@
+/*
+# Module: cmd/graphfs/cmd_new.go
+New command implementation.
+
+Scaffolds a source file with a LinkedDoc header, validates that declared
+links resolve to graph modules and pre-registers the shadow entry.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/scaffold](../../pkg/scaffold/scaffold.go) - Module templates
+- [../../pkg/shadow](../../pkg/shadow/builder.go) - Shadow entry builder
+
+## Tags
+cli, command, scaffold, templates
+
+## Exports
+newCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_new.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_new.go" ;
+	code:description "New command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/scaffold/scaffold.go>, <../../pkg/shadow/builder.go> ;
+	code:exports <#newCmd> ;
+	code:tags "cli", "command", "scaffold", "templates" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scaffold"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	newDescription string
+	newLayer       string
+	newTags        []string
+	newLinks       []string
+	newExports     []string
+	newStub        bool
+	newDryRun      bool
+)
+
+// newCmd represents the new command
+var newCmd = &cobra.Command{
+	Use:   "new <path>",
+	Short: "Create a source file with a LinkedDoc header",
+	Long: `Scaffold a new source file with a correct LinkedDoc header.
+
+The header is written in the comment syntax of the file's language, and a
+minimal language-specific stub (package clause, class, exports) follows it
+unless --stub=false is given.
+
+Links are given as paths relative to the project root and must resolve to
+existing modules in the knowledge graph. The new file's shadow entry is
+created immediately so it shows up in shadow queries before the next sync.
+
+Examples:
+  graphfs new services/auth.go --layer service --tags auth
+  graphfs new services/auth.go -d "Authentication service" --links models/user.go --exports AuthService
+  graphfs new scripts/report.py --tags reporting --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNew,
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+
+	newCmd.Flags().StringVarP(&newDescription, "description", "d", "", "Module description")
+	newCmd.Flags().StringVar(&newLayer, "layer", "", "Architectural layer")
+	newCmd.Flags().StringSliceVar(&newTags, "tags", nil, "Module tags")
+	newCmd.Flags().StringSliceVar(&newLinks, "links", nil, "Linked modules (paths relative to the project root)")
+	newCmd.Flags().StringSliceVar(&newExports, "exports", nil, "Exported symbols")
+	newCmd.Flags().BoolVar(&newStub, "stub", true, "Add a language-specific stub after the header")
+	newCmd.Flags().BoolVar(&newDryRun, "dry-run", false, "Print the file instead of creating it")
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	rootPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	relPath := filepath.Clean(args[0])
+	if filepath.IsAbs(relPath) {
+		if relPath, err = filepath.Rel(rootPath, relPath); err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+	}
+	if strings.HasPrefix(relPath, "..") {
+		return fmt.Errorf("%s is outside the project root", args[0])
+	}
+
+	fullPath := filepath.Join(rootPath, relPath)
+	if _, err := os.Stat(fullPath); err == nil {
+		return fmt.Errorf("%s already exists", relPath)
+	}
+
+	opts := scaffold.Options{
+		Path:        relPath,
+		Description: newDescription,
+		Layer:       newLayer,
+		Tags:        newTags,
+		Exports:     newExports,
+		Stub:        newStub,
+	}
+
+	if len(newLinks) > 0 {
+		out.Info("Building knowledge graph...")
+		g, err := graph.NewBuilder().Build(rootPath, graph.BuildOptions{Validate: false})
+		if err != nil {
+			return fmt.Errorf("failed to build graph: %w", err)
+		}
+
+		links, unresolved := scaffold.ResolveLinks(g, newLinks)
+		if len(unresolved) > 0 {
+			return fmt.Errorf("links do not resolve to graph modules: %s", strings.Join(unresolved, ", "))
+		}
+		opts.Links = links
+	}
+
+	content, err := scaffold.Render(opts)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	if newDryRun {
+		fmt.Print(content)
+		return nil
+	}
+
+	unlock, err := lockWorkspace(rootPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	out.Success("Created %s", relPath)
+
+	// Pre-register the shadow entry from the new header
+	shadowFS, err := openProjectShadowFS()
+	if err != nil {
+		return err
+	}
+	if err := shadowFS.LoadIndex(); err != nil {
+		return fmt.Errorf("failed to load shadow index: %w", err)
+	}
+	if err := shadow.NewBuilder(shadowFS).BuildFile(relPath, shadow.DefaultBuildOptions()); err != nil {
+		return fmt.Errorf("failed to register shadow entry: %w", err)
+	}
+	if err := shadowFS.SaveIndex(); err != nil {
+		return fmt.Errorf("failed to save shadow index: %w", err)
+	}
+	out.Success("Registered shadow entry for %s", relPath)
+
+	return nil
+}
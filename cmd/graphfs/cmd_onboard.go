@@ -0,0 +1,188 @@
+/*
+# Module: cmd/graphfs/cmd_onboard.go
+Onboard command implementation.
+
+Generates a time-boxed Markdown tour of an area of the codebase for an
+engineer new to it: entry points, key modules, owners, recent churn,
+concepts and a suggested reading order.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [cmd_effective](./cmd_effective.go) - Effective metadata
+- [../../pkg/analysis](../../pkg/analysis/onboarding.go) - Onboarding tours
+- [../../pkg/scanner](../../pkg/scanner/git_filter.go) - Git churn
+
+## Tags
+cli, command, onboarding, documentation
+
+## Exports
+onboardCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_onboard.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_onboard.go" ;
+	code:description "Onboard command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./cmd_effective.go>, <../../pkg/analysis/onboarding.go>, <../../pkg/scanner/git_filter.go> ;
+	code:exports <#onboardCmd> ;
+	code:tags "cli", "command", "onboarding", "documentation" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	onboardArea    string
+	onboardTimeBox time.Duration
+	onboardSince   string
+	onboardTop     int
+	onboardFormat  string
+	onboardOutput  string
+)
+
+// onboardCmd represents the onboard command
+var onboardCmd = &cobra.Command{
+	Use:   "onboard [path]",
+	Short: "Generate a guided tour of an area for a new engineer",
+	Long: `Generate a guided Markdown tour of the modules under a directory.
+
+The tour lists:
+  - Entry points: program entry points and modules used from outside the area
+  - Key modules: modules with the most dependencies and dependents in the area
+  - Owners: from owner/owners annotations
+  - Recent churn: modules with the most commits since --since (git)
+  - Concepts: tags and concepts of the area's modules
+  - Reading order: dependencies before the modules that use them
+
+Reading time is estimated from file length. Modules that do not fit in the
+--time box are listed after the reading order.
+
+Examples:
+  graphfs onboard --area pkg/payments
+  graphfs onboard --area pkg/payments --time 1h -o payments-tour.md
+  graphfs onboard --area services --time 0 --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOnboard,
+}
+
+func init() {
+	rootCmd.AddCommand(onboardCmd)
+
+	onboardCmd.Flags().StringVar(&onboardArea, "area", "", "Directory to tour, relative to the project root (required)")
+	onboardCmd.Flags().DurationVar(&onboardTimeBox, "time", 2*time.Hour, "Reading time box (0 for no limit)")
+	onboardCmd.Flags().StringVar(&onboardSince, "since", "3 months ago", "Count churn from commits since a date or duration (git --since syntax)")
+	onboardCmd.Flags().IntVar(&onboardTop, "top", 5, "Modules listed per entry point, key module and churn section")
+	onboardCmd.Flags().StringVarP(&onboardFormat, "format", "f", "markdown", "Output format (markdown, json)")
+	onboardCmd.Flags().StringVarP(&onboardOutput, "output", "o", "", "Write the tour to a file")
+	_ = onboardCmd.MarkFlagRequired("area")
+}
+
+func runOnboard(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			IgnoreFiles: []string{".gitignore", ".graphfsignore"},
+			Concurrent:  true,
+		},
+		BaseIRI: projectBaseIRI(absPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	resolver, err := newEffectiveResolver(absPath)
+	if err != nil {
+		return err
+	}
+	if _, err := resolver.ApplyToGraph(g); err != nil {
+		return fmt.Errorf("failed to apply effective metadata: %w", err)
+	}
+
+	opts := analysis.OnboardingOptions{
+		Area:    onboardArea,
+		TimeBox: onboardTimeBox,
+		Top:     onboardTop,
+	}
+	git := scanner.NewGitFilter(absPath)
+	if git.IsGitRepository() {
+		churn, err := git.CommitCounts(onboardSince)
+		if err != nil {
+			// The tour still works without churn
+			out.Debug("Skipping churn: %v", err)
+		} else {
+			opts.Churn = churn
+		}
+	}
+
+	tour, err := analysis.BuildOnboardingTour(g, opts)
+	if err != nil {
+		return err
+	}
+	for _, tm := range tour.ReadingOrder {
+		if meta, err := resolver.Resolve(tm.Path); err == nil {
+			tour.AddConcepts(meta.Concepts...)
+		}
+	}
+	for _, tm := range tour.Later {
+		if meta, err := resolver.Resolve(tm.Path); err == nil {
+			tour.AddConcepts(meta.Concepts...)
+		}
+	}
+
+	var output string
+	switch onboardFormat {
+	case "markdown", "md":
+		output = tour.Markdown()
+	case "json":
+		data, err := json.MarshalIndent(tour, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		output = string(data) + "\n"
+	default:
+		return fmt.Errorf("unknown format %q (use markdown or json)", onboardFormat)
+	}
+
+	if onboardOutput != "" {
+		if err := os.WriteFile(onboardOutput, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		out.Success("Onboarding tour written to %s", onboardOutput)
+		return nil
+	}
+
+	fmt.Print(output)
+	return nil
+}
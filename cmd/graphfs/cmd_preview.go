@@ -0,0 +1,215 @@
+/*
+# Module: cmd/graphfs/cmd_preview.go
+Preview command implementation.
+
+Serves generated docs and the dependency graph on localhost, rebuilding them
+and reloading open pages whenever the watcher updates the graph.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [cmd_docs](./cmd_docs.go) - Documentation generation
+- [cmd_watch](./cmd_watch.go) - File watching
+- [lifecycle](./lifecycle.go) - Graceful shutdown
+- [../../pkg/preview](../../pkg/preview/preview.go) - Live-reload preview server
+
+## Tags
+cli, command, preview, docs
+
+## Exports
+previewCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_preview.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_preview.go" ;
+	code:description "Preview command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./cmd_docs.go>, <./cmd_watch.go>, <./lifecycle.go>, <../../pkg/preview/preview.go> ;
+	code:exports <#previewCmd> ;
+	code:tags "cli", "command", "preview", "docs" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/docs"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/preview"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/viz"
+	"github.com/justin4957/graphfs/pkg/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	previewHost     string
+	previewPort     int
+	previewDebounce time.Duration
+	previewColorBy  string
+	previewTitle    string
+)
+
+// previewCmd represents the preview command
+var previewCmd = &cobra.Command{
+	Use:   "preview [path]",
+	Short: "Serve docs and the dependency graph with live reload",
+	Long: `Serve generated docs and the dependency graph on localhost.
+
+The preview generates multi-file docs (as 'graphfs docs --format multi')
+and a page with the Mermaid dependency graph, then watches the codebase.
+When a change updates the graph, both are regenerated and open pages reload,
+so LinkedDoc edits show up seconds after saving.
+
+Pages render Markdown and Mermaid in the browser with scripts loaded from
+cdn.jsdelivr.net.
+
+Examples:
+  graphfs preview                       # Serve on http://localhost:8090
+  graphfs preview --port 3000
+  graphfs preview services/ --color-by language`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+
+	previewCmd.Flags().StringVar(&previewHost, "host", "localhost", "Host to listen on")
+	previewCmd.Flags().IntVarP(&previewPort, "port", "p", 8090, "Port to listen on")
+	previewCmd.Flags().DurationVar(&previewDebounce, "debounce", 300*time.Millisecond, "Debounce duration for batching changes")
+	previewCmd.Flags().StringVar(&previewColorBy, "color-by", "layer", "Color graph nodes by: layer, language")
+	previewCmd.Flags().StringVar(&previewTitle, "title", "", "Documentation title (defaults to project name)")
+}
+
+func runPreview(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+	idStrategy, err := docs.ParseIDStrategy(config.Docs.IDStrategy)
+	if err != nil {
+		return err
+	}
+
+	out.Info("Building knowledge graph...")
+	builder := graph.NewBuilder()
+	g, err := builder.Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		ReportProgress: verbose,
+		UseCache:       true,
+		BaseIRI:        config.URIs.Base,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	previewServer := preview.NewServer(preview.Options{
+		Docs: docs.DocsOptions{
+			Title:       previewTitle,
+			ProjectName: filepath.Base(absPath),
+			IDs:         idStrategy,
+		},
+		Mermaid: viz.MermaidOptions{
+			Type:      viz.MermaidFlowchart,
+			Direction: "LR",
+			ColorBy:   previewColorBy,
+		},
+		Components: func(g *graph.Graph) (*analysis.ComponentAnalysis, error) {
+			return loadProjectComponents(g, absPath)
+		},
+	})
+	lc := newLifecycle()
+	defer lc.Shutdown(shutdownTimeout)
+	lc.Register("preview files", func(context.Context) error { return previewServer.Close() })
+
+	if err := previewServer.Update(g); err != nil {
+		return err
+	}
+	out.Success("Preview generated for %d modules", g.Statistics.TotalModules)
+
+	watcher, err := watch.NewWatcher(g, watch.WatchOptions{
+		Path:     absPath,
+		Debounce: previewDebounce,
+		Verbose:  verbose,
+	}, func(g *graph.Graph, changedFiles []string) {
+		if err := previewServer.Update(g); err != nil {
+			out.Error("Preview update failed: %v", err)
+			return
+		}
+		out.Info("Preview updated (%d file(s) changed)", len(changedFiles))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	watcher.Start()
+	lc.Register("watcher", watcher.Shutdown)
+
+	addr := net.JoinHostPort(previewHost, strconv.Itoa(previewPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	httpServer := &http.Server{Handler: previewServer.Handler()}
+	lc.Register("http server", httpServer.Shutdown)
+	// Close event streams first; Shutdown waits for them otherwise
+	lc.Register("event streams", func(context.Context) error { return previewServer.Close() })
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	out.Info("Serving preview at http://%s (Ctrl+C to stop)", addr)
+	select {
+	case <-lc.Done():
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("preview server failed: %w", err)
+		}
+	}
+	if err := shutdownLifecycle(lc, out); err != nil {
+		return err
+	}
+	out.Success("Preview stopped")
+	return nil
+}
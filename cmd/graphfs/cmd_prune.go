@@ -0,0 +1,193 @@
+/*
+# Module: cmd/graphfs/cmd_prune.go
+Prune command implementation.
+
+Removes stale external and inferred nodes from the shadow layer, printing a
+plan first and keeping an undo snapshot of every file it changes.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/shadow](../../pkg/shadow/prune.go) - Shadow pruning
+
+## Tags
+cli, command, prune, cleanup
+
+## Exports
+pruneCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_prune.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_prune.go" ;
+	code:description "Prune command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/shadow/prune.go> ;
+	code:exports <#pruneCmd> ;
+	code:tags "cli", "command", "prune", "cleanup" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneOlderThan string
+	pruneKinds     []string
+	pruneDryRun    bool
+	pruneUndo      bool
+	pruneSnapshot  string
+)
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune [path]",
+	Short: "Remove stale external and inferred nodes",
+	Long: `Remove stale nodes from the shadow layer.
+
+Kinds:
+  external   Entries whose source file disappeared, and dependencies or
+             linksTo triples pointing at project paths that no longer exist
+  inferred   Auto-generated entries never confirmed by a manual annotation,
+             and inferred triples such as code:inferredLayer
+
+Auto-generated entries for files that still exist are recreated by the next
+'graphfs shadow sync'.
+
+The plan is always printed first. Before anything is removed, the affected
+shadow files are copied into a snapshot under .graphfs/snapshots, which
+--undo restores.
+
+Examples:
+  graphfs prune --dry-run
+  graphfs prune --older-than 30d --kinds external,inferred
+  graphfs prune --undo
+  graphfs prune --undo --snapshot prune-20260101T120000.000Z`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "", "Only prune entries not updated for a duration (e.g. 72h, 30d) or since a date")
+	pruneCmd.Flags().StringSliceVar(&pruneKinds, "kinds", []string{string(shadow.PruneExternal)}, "Kinds to prune (external, inferred)")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Print the plan without removing anything")
+	pruneCmd.Flags().BoolVar(&pruneUndo, "undo", false, "Restore the latest prune snapshot")
+	pruneCmd.Flags().StringVar(&pruneSnapshot, "snapshot", "", "Snapshot to restore with --undo (default: latest)")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	if pruneUndo {
+		unlock, err := lockWorkspace(absPath, out)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		id, err := shadowFS.UndoPrune(pruneSnapshot)
+		if err != nil {
+			return fmt.Errorf("failed to undo prune: %w", err)
+		}
+		out.Success("Restored prune snapshot %s", id)
+		return nil
+	}
+
+	kinds, err := shadow.ParsePruneKinds(pruneKinds)
+	if err != nil {
+		return err
+	}
+
+	opts := shadow.PruneOptions{Kinds: kinds}
+	if pruneOlderThan != "" {
+		if opts.Before, err = parseOlderThan(pruneOlderThan, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// Hold the lock while planning so the plan matches what is applied
+	if !pruneDryRun {
+		unlock, err := lockWorkspace(absPath, out)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	plan, err := shadowFS.PlanPrune(opts)
+	if err != nil {
+		return fmt.Errorf("failed to plan prune: %w", err)
+	}
+
+	if len(plan.Actions) == 0 {
+		out.Success("Nothing to prune")
+		return nil
+	}
+
+	var rows [][]string
+	for _, action := range plan.Actions {
+		target := action.Target
+		if target == "" {
+			target = "(entry)"
+		}
+		rows = append(rows, []string{action.Path, string(action.Kind), target, action.Reason})
+	}
+	out.Table([]string{"Path", "Kind", "Removes", "Reason"}, rows)
+	out.Println("")
+
+	if pruneDryRun {
+		out.Info("%d item(s) would be pruned", len(plan.Actions))
+		return nil
+	}
+
+	id, err := shadowFS.ApplyPrune(plan)
+	if err != nil {
+		return fmt.Errorf("failed to prune: %w", err)
+	}
+
+	out.Success("Pruned %d item(s)", len(plan.Actions))
+	out.Info("Undo with: graphfs prune --undo --snapshot %s", id)
+	return nil
+}
+
+// parseOlderThan converts an age (30d, 72h) or a date into a cutoff time
+func parseOlderThan(value string, now time.Time) (time.Time, error) {
+	t, err := parseExpiry(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --older-than %q (use a duration like 72h or 30d, or a date like 2006-01-02)", value)
+	}
+	if t.After(now) {
+		// Durations are parsed as times in the future; mirror them back
+		return now.Add(-t.Sub(now)), nil
+	}
+	return t, nil
+}
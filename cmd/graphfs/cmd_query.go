@@ -34,14 +34,16 @@ queryCmd
 package main
 
 import (
-	"encoding/csv"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/filter"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/query"
 	"github.com/justin4957/graphfs/pkg/scanner"
@@ -49,14 +51,16 @@ import (
 )
 
 var (
-	queryFile     string
-	queryFormat   string
-	queryLimit    int
-	queryOutput   string
-	queryOffset   int
-	queryPageSize int
-	queryStream   bool
-	queryPage     int
+	queryFile      string
+	queryFormat    string
+	queryLimit     int
+	queryOutput    string
+	queryOffset    int
+	queryPageSize  int
+	queryStream    bool
+	queryPage      int
+	queryEffective bool
+	queryWhere     string
 )
 
 // queryCmd represents the query command
@@ -66,7 +70,9 @@ var queryCmd = &cobra.Command{
 	Long: `Execute SPARQL query against the knowledge graph.
 
 The query command allows you to query the knowledge graph using SPARQL.
-Results can be formatted as table, JSON, or CSV.
+Results can be formatted as a table, JSON, CSV, TSV, newline-delimited JSON
+(one object per result, for jq) or W3C SPARQL Results JSON (sparql-json,
+for other SPARQL tooling).
 
 Supports streaming and pagination for large result sets:
   --stream     Stream results incrementally (memory efficient)
@@ -75,6 +81,12 @@ Supports streaming and pagination for large result sets:
   --offset     Skip first N results
   --limit      Limit total results
 
+Shared prefixes in .graphfs/prefixes.ttl are prepended automatically and
+macros from .graphfs/macros.yaml are expanded (invoke as @name(args)).
+
+Named, parameterized queries can be kept in .graphfs/queries with
+'graphfs query save' and run with 'graphfs query run <name>'.
+
 Examples:
   # Inline query
   graphfs query 'SELECT * WHERE { ?s ?p ?o } LIMIT 10'
@@ -85,6 +97,13 @@ Examples:
   # Format as JSON
   graphfs query 'SELECT ?module WHERE { ?module a code:Module }' --format json
 
+  # Pipe results into jq or a spreadsheet
+  graphfs query --file queries/deps.sparql --format ndjson | jq -r .module
+  graphfs query --file queries/deps.sparql --format csv > deps.csv
+
+  # Run a saved query
+  graphfs query run importers module=pkg/graph/graph.go
+
   # Save to file
   graphfs query --file queries/deps.sparql --output results.json
 
@@ -95,20 +114,29 @@ Examples:
   graphfs query --page 1 --page-size 50 'SELECT * WHERE { ?s ?p ?o }'
 
   # Skip first 100 results
-  graphfs query --offset 100 --limit 50 'SELECT * WHERE { ?s ?p ?o }'`,
+  graphfs query --offset 100 --limit 50 'SELECT * WHERE { ?s ?p ?o }'
+
+  # Include layers/tags inherited from directory entries and workspace defaults
+  graphfs query --effective 'SELECT ?module ?layer WHERE { ?module code:layer ?layer }'
+
+  # Use a macro defined in .graphfs/macros.yaml
+  graphfs query 'SELECT ?module WHERE { @inLayer(?module, "services") }'`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runQuery,
 }
 
 func init() {
+	// Flags other than --file also apply to 'query run'
 	queryCmd.Flags().StringVarP(&queryFile, "file", "f", "", "Read query from file")
-	queryCmd.Flags().StringVar(&queryFormat, "format", "table", "Output format: table, json, csv")
-	queryCmd.Flags().IntVarP(&queryLimit, "limit", "l", 0, "Limit number of results (0 = no limit)")
-	queryCmd.Flags().StringVarP(&queryOutput, "output", "o", "", "Write results to file")
-	queryCmd.Flags().IntVar(&queryOffset, "offset", 0, "Skip first N results")
-	queryCmd.Flags().IntVar(&queryPageSize, "page-size", 100, "Number of results per page")
-	queryCmd.Flags().BoolVar(&queryStream, "stream", false, "Stream results incrementally")
-	queryCmd.Flags().IntVar(&queryPage, "page", 0, "Show specific page of results (1-indexed)")
+	queryCmd.PersistentFlags().StringVar(&queryFormat, "format", "table", "Output format: table, json, csv, tsv, ndjson, sparql-json")
+	queryCmd.PersistentFlags().IntVarP(&queryLimit, "limit", "l", 0, "Limit number of results (0 = no limit)")
+	queryCmd.PersistentFlags().StringVarP(&queryOutput, "output", "o", "", "Write results to file")
+	queryCmd.PersistentFlags().IntVar(&queryOffset, "offset", 0, "Skip first N results")
+	queryCmd.PersistentFlags().IntVar(&queryPageSize, "page-size", 100, "Number of results per page")
+	queryCmd.PersistentFlags().BoolVar(&queryStream, "stream", false, "Stream results incrementally")
+	queryCmd.PersistentFlags().IntVar(&queryPage, "page", 0, "Show specific page of results (1-indexed)")
+	queryCmd.PersistentFlags().StringVar(&queryWhere, "where", "", "Query only the triples of modules matching a filter expression")
+	queryCmd.PersistentFlags().BoolVar(&queryEffective, "effective", false, "Query effective (inherited) metadata from shadow entries and workspace defaults")
 }
 
 func runQuery(cmd *cobra.Command, args []string) error {
@@ -131,6 +159,16 @@ func runQuery(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("query string or --file required")
 	}
 
+	return runQueryString(queryString, out)
+}
+
+// runQueryString builds the graph of the current directory and runs a
+// query against it with the query flags
+func runQueryString(queryString string, out *cli.OutputFormatter) error {
+	if queryFormat != "table" && queryFormat != "json" && !slices.Contains(query.ResultFormats, queryFormat) {
+		return fmt.Errorf("unsupported format: %s (available: table, json, %s)", queryFormat, strings.Join(query.ResultFormats, ", "))
+	}
+
 	// Determine current directory
 	currentDir, err := os.Getwd()
 	if err != nil {
@@ -143,6 +181,16 @@ func runQuery(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("GraphFS not initialized. Run 'graphfs init' first")
 	}
 
+	// Expand shared prefixes and macros
+	preprocessor, err := loadQueryPreprocessor(currentDir)
+	if err != nil {
+		return err
+	}
+	queryString, err = preprocessor.Expand(queryString)
+	if err != nil {
+		return fmt.Errorf("failed to expand query macros: %w", err)
+	}
+
 	// Build graph (in a real implementation, we would load from store)
 	out.Debug("Building knowledge graph...")
 
@@ -154,11 +202,38 @@ func runQuery(cmd *cobra.Command, args []string) error {
 			Concurrent:  true,
 		},
 		ReportProgress: verbose,
+		BaseIRI:        projectBaseIRI(currentDir),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to build graph: %w", err)
 	}
 
+	if queryEffective {
+		if err := applyEffectiveMetadata(graphObj, out); err != nil {
+			return err
+		}
+	}
+
+	if config, ok := projectCriticality(currentDir); ok {
+		if _, err := applyCriticality(graphObj, config, out); err != nil {
+			return err
+		}
+	}
+
+	if err := applyComponents(graphObj, currentDir, out); err != nil {
+		return err
+	}
+
+	if err := applyBenchmarks(graphObj, currentDir, out); err != nil {
+		return err
+	}
+
+	where, err := filter.Parse(queryWhere)
+	if err != nil {
+		return err
+	}
+	graphObj = filter.Subgraph(graphObj, where)
+
 	out.Debug("Graph loaded: %d modules, %d triples",
 		graphObj.Statistics.TotalModules,
 		graphObj.Statistics.TotalTriples)
@@ -173,6 +248,15 @@ func runQuery(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// loadQueryPreprocessor loads .graphfs/prefixes.ttl and .graphfs/macros.yaml
+func loadQueryPreprocessor(rootDir string) (*query.Preprocessor, error) {
+	preprocessor, err := query.LoadPreprocessor(filepath.Join(rootDir, ".graphfs"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load query macros: %w", err)
+	}
+	return preprocessor, nil
+}
+
 // runNormalQuery executes a query normally (all results at once)
 func runNormalQuery(graphObj *graph.Graph, queryString string, out *cli.OutputFormatter) error {
 	// Parse the query to apply CLI flags
@@ -203,12 +287,10 @@ func runNormalQuery(graphObj *graph.Graph, queryString string, out *cli.OutputFo
 	switch queryFormat {
 	case "json":
 		output, err = formatJSON(result)
-	case "csv":
-		output, err = formatCSV(result)
 	case "table":
 		output, err = formatTable(result)
 	default:
-		return fmt.Errorf("unsupported format: %s", queryFormat)
+		output, err = formatResult(result, queryFormat)
 	}
 
 	if err != nil {
@@ -255,33 +337,49 @@ func runStreamingQuery(graphObj *graph.Graph, queryString string, out *cli.Outpu
 
 	stream := streamExecutor.ExecuteStreamWithProgress(parsedQuery, progressCallback)
 
-	fmt.Println("Streaming results...")
+	// Formats read by other tools get the results alone on stdout
+	var writer query.ResultWriter
+	info := os.Stdout
+	if queryFormat != "table" && queryFormat != "json" {
+		writer, err = query.NewResultWriter(os.Stdout, queryFormat)
+		if err != nil {
+			return err
+		}
+		info = os.Stderr
+	}
+
+	fmt.Fprintln(info, "Streaming results...")
 	if len(stream.Variables) > 0 {
-		fmt.Println("Variables:", stream.Variables)
+		fmt.Fprintln(info, "Variables:", stream.Variables)
+	}
+	fmt.Fprintln(info)
+
+	if writer != nil {
+		if err := writer.WriteHeader(stream.Variables); err != nil {
+			return fmt.Errorf("streaming failed: %w", err)
+		}
 	}
-	fmt.Println()
 
 	count := 0
 	err = stream.ForEach(func(binding map[string]string) error {
 		count++
-		switch queryFormat {
-		case "json":
+		switch {
+		case writer != nil:
+			return writer.WriteRow(binding)
+		case queryFormat == "json":
 			data, jsonErr := json.Marshal(binding)
 			if jsonErr != nil {
 				return jsonErr
 			}
 			fmt.Println(string(data))
-		case "csv":
-			var values []string
-			for _, v := range stream.Variables {
-				values = append(values, binding[v])
-			}
-			fmt.Println(strings.Join(values, ","))
 		default: // table format
 			fmt.Printf("%d. %v\n", count, binding)
 		}
 		return nil
 	})
+	if err == nil && writer != nil {
+		err = writer.Close()
+	}
 
 	if verbose {
 		fmt.Fprintln(os.Stderr) // Clear progress line
@@ -291,7 +389,7 @@ func runStreamingQuery(graphObj *graph.Graph, queryString string, out *cli.Outpu
 		return fmt.Errorf("streaming failed: %w", err)
 	}
 
-	fmt.Printf("\nTotal: %d results streamed\n", count)
+	fmt.Fprintf(info, "\nTotal: %d results streamed\n", count)
 	return nil
 }
 
@@ -339,12 +437,10 @@ func runPaginatedQuery(graphObj *graph.Graph, queryString string, out *cli.Outpu
 			return fmt.Errorf("failed to marshal JSON: %w", jsonErr)
 		}
 		output = string(data)
-	case "csv":
-		output, err = formatCSV(result)
 	case "table":
 		output, err = formatTable(result)
 	default:
-		return fmt.Errorf("unsupported format: %s", queryFormat)
+		output, err = formatResult(result, queryFormat)
 	}
 
 	if err != nil {
@@ -359,15 +455,20 @@ func runPaginatedQuery(graphObj *graph.Graph, queryString string, out *cli.Outpu
 		out.Success("Results written to %s", queryOutput)
 	} else {
 		fmt.Println(output)
-		// Print pagination info for table/csv format
+		// Print pagination info for table format, and to stderr for
+		// formats read by other tools
 		if queryFormat != "json" {
-			fmt.Printf("\nPage %d of %d (showing %d of %d total results)\n",
+			info := os.Stdout
+			if queryFormat != "table" {
+				info = os.Stderr
+			}
+			fmt.Fprintf(info, "\nPage %d of %d (showing %d of %d total results)\n",
 				paginatedResult.Page,
 				paginatedResult.TotalPages,
 				len(paginatedResult.Bindings),
 				paginatedResult.TotalCount)
 			if paginatedResult.HasMore {
-				fmt.Printf("Use --page %d to see more results\n", paginatedResult.Page+1)
+				fmt.Fprintf(info, "Use --page %d to see more results\n", paginatedResult.Page+1)
 			}
 		}
 	}
@@ -384,44 +485,11 @@ func formatJSON(result *query.QueryResult) (string, error) {
 	return string(data), nil
 }
 
-// formatCSV formats query results as CSV
-func formatCSV(result *query.QueryResult) (string, error) {
-	if len(result.Bindings) == 0 {
-		return "", nil
-	}
-
-	// Create in-memory CSV writer
-	var output [][]string
-
-	// Add header
-	output = append(output, result.Variables)
-
-	// Add rows
-	for _, binding := range result.Bindings {
-		row := make([]string, len(result.Variables))
-		for i, variable := range result.Variables {
-			if value, ok := binding[variable]; ok {
-				row[i] = value
-			}
-		}
-		output = append(output, row)
+// formatResult formats query results in one of query.ResultFormats
+func formatResult(result *query.QueryResult, format string) (string, error) {
+	var buf bytes.Buffer
+	if err := query.WriteResult(&buf, result, format); err != nil {
+		return "", err
 	}
-
-	// Convert to CSV string
-	var csvData string
-	writer := csv.NewWriter(&stringWriter{&csvData})
-	writer.WriteAll(output)
-	writer.Flush()
-
-	return csvData, writer.Error()
-}
-
-// stringWriter implements io.Writer for strings
-type stringWriter struct {
-	s *string
-}
-
-func (sw *stringWriter) Write(p []byte) (n int, err error) {
-	*sw.s += string(p)
-	return len(p), nil
+	return strings.TrimSuffix(buf.String(), "\n"), nil
 }
@@ -0,0 +1,339 @@
+/*
+# Module: cmd/graphfs/cmd_query_saved.go
+Saved query subcommands.
+
+Implements 'graphfs query save', 'list' and 'run' for named, parameterized
+queries kept in .graphfs/queries, so a team can share them with the project.
+
+## Linked Modules
+- [query](./cmd_query.go) - Query command
+- [examples](./cmd_examples.go) - Query templates
+- [lock](./lock.go) - Workspace lock
+- [../../pkg/query](../../pkg/query/saved.go) - Saved queries
+
+## Tags
+cli, command, query, sparql, saved-queries
+
+## Exports
+querySaveCmd, queryListCmd, queryRunCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_query_saved.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_query_saved.go" ;
+	code:description "Saved query subcommands" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./cmd_query.go>, <./cmd_examples.go>, <./lock.go>, <../../pkg/query/saved.go> ;
+	code:exports <#querySaveCmd>, <#queryListCmd>, <#queryRunCmd> ;
+	code:tags "cli", "command", "query", "sparql", "saved-queries" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/spf13/cobra"
+)
+
+var (
+	querySaveFile        string
+	querySaveDescription string
+	querySaveCategory    string
+	querySaveVars        []string
+	querySaveForce       bool
+)
+
+var querySaveCmd = &cobra.Command{
+	Use:   "save <name> [query]",
+	Short: "Save a named query to .graphfs/queries",
+	Long: `Save a query as .graphfs/queries/<name>.sparql.
+
+Parameters are written as template fields, such as <#{{.module}}>, and are
+bound when the query runs. Declare them with --var name or --var
+name=default; without --var every field in the query becomes a required
+parameter. The file starts with a YAML header in # comments and can be
+edited by hand and committed with the project.
+
+Saved queries are also available to 'graphfs examples run' and to
+\template in the REPL, and replace templates with the same name.`,
+	Example: `  graphfs query save importers 'SELECT ?user WHERE { ?user <#imports> <#{{.module}}> }' \
+    --description "Modules that import a module"
+  graphfs query save layer-deps --file queries/layer-deps.sparql --var layer=services`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runQuerySave,
+}
+
+var queryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved queries and templates",
+	Long: `List the queries saved in .graphfs/queries with their parameters,
+followed by the built-in, pack and custom templates 'query run' can also run.
+
+Use --format json for machine-readable output.`,
+	Args: cobra.NoArgs,
+	RunE: runQueryList,
+}
+
+var queryRunCmd = &cobra.Command{
+	Use:   "run <name> [param=value | value]...",
+	Short: "Run a saved query or template",
+	Long: `Run a saved query, or a template, with its parameters bound.
+
+Parameters are given as name=value; bare values bind the remaining
+parameters in order. Parameters with a default may be left out. The query
+flags (--format, --limit, --where, ...) apply as they do to 'graphfs query'.`,
+	Example: `  graphfs query run importers module=pkg/graph/graph.go
+  graphfs query run importers pkg/graph/graph.go --format csv`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQueryRun,
+}
+
+func init() {
+	queryCmd.AddCommand(querySaveCmd)
+	queryCmd.AddCommand(queryListCmd)
+	queryCmd.AddCommand(queryRunCmd)
+
+	querySaveCmd.Flags().StringVarP(&querySaveFile, "file", "f", "", "Read the query from a file")
+	querySaveCmd.Flags().StringVar(&querySaveDescription, "description", "", "What the query finds")
+	querySaveCmd.Flags().StringVar(&querySaveCategory, "category", "", "Category shown by 'examples list' (default: project)")
+	querySaveCmd.Flags().StringArrayVar(&querySaveVars, "var", nil, "Declare a parameter as name or name=default (repeatable)")
+	querySaveCmd.Flags().BoolVar(&querySaveForce, "force", false, "Replace an existing query")
+}
+
+// savedQueriesDir returns the saved queries directory of a project
+func savedQueriesDir(rootDir string) string {
+	return filepath.Join(rootDir, ".graphfs", query.QueriesDir)
+}
+
+func runQuerySave(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	var queryString string
+	switch {
+	case querySaveFile != "" && len(args) > 1:
+		return fmt.Errorf("give the query as an argument or with --file, not both")
+	case querySaveFile != "":
+		data, err := os.ReadFile(querySaveFile)
+		if err != nil {
+			return fmt.Errorf("failed to read query file: %w", err)
+		}
+		queryString = string(data)
+	case len(args) > 1:
+		queryString = args[1]
+	default:
+		return fmt.Errorf("query string or --file required")
+	}
+	if strings.TrimSpace(queryString) == "" {
+		return fmt.Errorf("query is empty")
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(currentDir, ".graphfs")); os.IsNotExist(err) {
+		return fmt.Errorf("GraphFS not initialized. Run 'graphfs init' first")
+	}
+
+	tmpl := &query.QueryTemplate{
+		Name:        args[0],
+		Description: querySaveDescription,
+		Category:    querySaveCategory,
+		Query:       strings.TrimSpace(queryString),
+	}
+
+	fields := query.TemplateVariableNames(tmpl.Query)
+	if len(querySaveVars) == 0 {
+		for _, field := range fields {
+			tmpl.Variables = append(tmpl.Variables, query.Variable{Name: field})
+		}
+	}
+	for _, spec := range querySaveVars {
+		name, value, _ := strings.Cut(spec, "=")
+		if !slices.Contains(fields, name) {
+			return fmt.Errorf("parameter %q is not used in the query (write it as {{.%s}})", name, name)
+		}
+		tmpl.Variables = append(tmpl.Variables, query.Variable{Name: name, Default: value})
+	}
+	for _, field := range fields {
+		if !slices.ContainsFunc(tmpl.Variables, func(v query.Variable) bool { return v.Name == field }) {
+			return fmt.Errorf("query uses {{.%s}} but it is not declared with --var", field)
+		}
+	}
+
+	// Catch template syntax errors now rather than when the query runs
+	if err := query.ValidateTemplate(tmpl); err != nil {
+		return err
+	}
+
+	unlock, err := lockWorkspace(currentDir, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path, err := query.SaveQuery(savedQueriesDir(currentDir), tmpl, querySaveForce)
+	if err != nil {
+		if !querySaveForce && strings.HasSuffix(err.Error(), "already exists") {
+			return fmt.Errorf("%w (use --force to replace it)", err)
+		}
+		return err
+	}
+
+	rel, _ := filepath.Rel(currentDir, path)
+	out.Success("Saved query %s to %s", tmpl.Name, filepath.ToSlash(rel))
+	out.Info("Run it with: %s", savedQueryUsage(tmpl))
+	return nil
+}
+
+// savedQueryUsage returns the command that runs a query
+func savedQueryUsage(tmpl *query.QueryTemplate) string {
+	usage := "graphfs query run " + tmpl.Name
+	for _, v := range tmpl.Variables {
+		if v.Default == "" {
+			usage += " " + v.Name + "=..."
+		}
+	}
+	return usage
+}
+
+// savedQueryListing is a query in 'query list --format json'
+type savedQueryListing struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Category    string           `json:"category"`
+	Saved       bool             `json:"saved"` // In .graphfs/queries rather than a template
+	Variables   []query.Variable `json:"variables"`
+}
+
+func runQueryList(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if queryFormat != "table" && queryFormat != "json" {
+		return fmt.Errorf("unsupported format for list: %s (use table or json)", queryFormat)
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	saved, err := query.ReadSavedQueries(savedQueriesDir(currentDir))
+	if err != nil {
+		return err
+	}
+	isSaved := make(map[string]bool, len(saved))
+	for _, tmpl := range saved {
+		isSaved[tmpl.Name] = true
+	}
+
+	var templates []*query.QueryTemplate
+	for _, tmpl := range query.NewTemplateManager(filepath.Join(currentDir, ".graphfs", "templates")).ListTemplates("") {
+		if !isSaved[tmpl.Name] {
+			templates = append(templates, tmpl)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	if queryFormat == "json" {
+		listing := make([]savedQueryListing, 0, len(saved)+len(templates))
+		for _, tmpl := range append(slices.Clone(saved), templates...) {
+			variables := tmpl.Variables
+			if variables == nil {
+				variables = []query.Variable{}
+			}
+			listing = append(listing, savedQueryListing{
+				Name:        tmpl.Name,
+				Description: tmpl.Description,
+				Category:    tmpl.Category,
+				Saved:       isSaved[tmpl.Name],
+				Variables:   variables,
+			})
+		}
+		data, err := json.MarshalIndent(listing, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize queries: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	out.Header(fmt.Sprintf("Saved Queries (%d)", len(saved)))
+	if len(saved) == 0 {
+		out.Println("  none; save one with 'graphfs query save <name> <query>'")
+	}
+	for _, tmpl := range saved {
+		out.Println("  %-28s %s", tmpl.Name, tmpl.Description)
+		if params := formatQueryParams(tmpl.Variables); params != "" {
+			out.Println("  %-28s params: %s", "", params)
+		}
+	}
+	out.Println("")
+
+	out.Header(fmt.Sprintf("Templates (%d)", len(templates)))
+	for _, tmpl := range templates {
+		out.Println("  %-28s %s", tmpl.Name, tmpl.Description)
+	}
+	out.Println("")
+	out.Info("Run one with: graphfs query run <name> [param=value ...]")
+	return nil
+}
+
+// formatQueryParams lists parameters with their defaults
+func formatQueryParams(variables []query.Variable) string {
+	params := make([]string, 0, len(variables))
+	for _, v := range variables {
+		if v.Default != "" {
+			params = append(params, v.Name+"="+v.Default)
+		} else {
+			params = append(params, v.Name)
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+func runQueryRun(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	tm, err := newTemplateManager(currentDir)
+	if err != nil {
+		return err
+	}
+	tmpl, err := tm.GetTemplate(args[0])
+	if err != nil {
+		return fmt.Errorf("no saved query or template named %s (see 'graphfs query list')", args[0])
+	}
+
+	variables, err := query.BindTemplateArgs(tmpl, args[1:])
+	if err != nil {
+		return err
+	}
+	queryString, err := tm.Render(tmpl, variables)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", tmpl.Name, err)
+	}
+	out.Debug("Rendered query:\n%s", queryString)
+
+	return runQueryString(queryString, out)
+}
@@ -0,0 +1,135 @@
+/*
+# Module: cmd/graphfs/cmd_rename.go
+Rename command implementation.
+
+Moves a file while keeping the graph consistent: rewrites references to it
+in dependents' LinkedDoc headers, moves the shadow entry and records an alias.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/shadow](../../pkg/shadow/rename.go) - Graph-aware rename support
+- [../../pkg/graph](../../pkg/graph/builder.go) - Graph builder
+
+## Tags
+cli, command, rename, refactoring
+
+## Exports
+renameCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_rename.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_rename.go" ;
+	code:description "Rename command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/shadow/rename.go>, <../../pkg/graph/builder.go> ;
+	code:exports <#renameCmd> ;
+	code:tags "cli", "command", "rename", "refactoring" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var renameWrite bool
+
+// renameCmd represents the rename command
+var renameCmd = &cobra.Command{
+	Use:   "rename <old-path> <new-path>",
+	Short: "Move a file and update LinkedDoc references to it",
+	Long: `Move a file while keeping the graph consistent.
+
+Rename finds every module that refers to the old path and rewrites each
+relative IRI resolving to it, keeping fragments: linksTo, symbol references
+such as code:calls <../utils/logger.go#Info>, and Linked Modules links. The
+moved file's own relative links, module name and URI are updated as well.
+
+By default the changes are printed as unified diffs. With --write the file
+is moved, dependents are updated in place, the shadow entry is moved, the
+index is updated and an alias from the old path to the new one is recorded
+in .graphfs/shadow/aliases.json.
+
+The file may already have been moved (e.g. with git mv); rename then only
+updates the references.
+
+Examples:
+  graphfs rename services/auth.go services/auth/service.go
+  graphfs rename services/auth.go services/auth/service.go --write`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+
+	renameCmd.Flags().BoolVar(&renameWrite, "write", false, "Move the file and apply changes instead of printing diffs")
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+	oldPath, newPath := filepath.Clean(args[0]), filepath.Clean(args[1])
+
+	rootPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	out.Info("Building knowledge graph...")
+	builder := graph.NewBuilder()
+	g, err := builder.Build(rootPath, graph.BuildOptions{Validate: false})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	plan, err := shadow.PlanRename(g, rootPath, oldPath, newPath)
+	if err != nil {
+		return fmt.Errorf("failed to plan rename: %w", err)
+	}
+
+	if !renameWrite {
+		fmt.Printf("rename %s => %s\n", filepath.ToSlash(oldPath), filepath.ToSlash(newPath))
+		fmt.Print(plan.Moved.UnifiedDiff())
+		for _, patch := range plan.Dependents {
+			fmt.Print(patch.UnifiedDiff())
+		}
+		out.Println("")
+		out.Info("%d dependent(s) would be updated. Run with --write to apply.", len(plan.Dependents))
+		return nil
+	}
+
+	shadowFS, err := shadow.NewShadowFS(rootPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	unlock, err := lockWorkspace(rootPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := plan.Apply(rootPath, shadowFS); err != nil {
+		return fmt.Errorf("failed to apply rename: %w", err)
+	}
+
+	out.Success("Renamed %s to %s", oldPath, newPath)
+	for _, patch := range plan.Dependents {
+		out.Println("  updated %s", patch.Path)
+	}
+	out.Info("Recorded alias %s -> %s", oldPath, newPath)
+
+	return nil
+}
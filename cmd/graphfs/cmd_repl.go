@@ -7,6 +7,7 @@ Implements the 'graphfs repl' command for interactive query sessions.
 ## Linked Modules
 - [../../pkg/repl](../../pkg/repl/repl.go) - REPL implementation
 - [main](./main.go) - CLI entry point
+- [lifecycle](./lifecycle.go) - Graceful shutdown
 
 ## Tags
 cli, repl, commands
@@ -20,7 +21,7 @@ cli, repl, commands
     code:description "CLI command for interactive REPL" ;
     code:language "go" ;
     code:layer "cli" ;
-    code:linksTo <../../pkg/repl/repl.go>, <./main.go> ;
+    code:linksTo <../../pkg/repl/repl.go>, <./main.go>, <./lifecycle.go> ;
     code:tags "cli", "repl", "commands" .
 <!-- End LinkedDoc RDF -->
 */
@@ -32,6 +33,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/justin4957/graphfs/pkg/cli"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/query"
 	"github.com/justin4957/graphfs/pkg/repl"
@@ -48,13 +50,14 @@ var replCmd = &cobra.Command{
 The REPL provides an interactive shell for executing SPARQL queries with:
 - Multi-line query editing
 - Command history (up/down arrows)
-- Tab completion for keywords and commands
-- Multiple output formats (table, JSON, CSV)
+- Tab completion for keywords, commands, prefixes, predicates and module URIs
+- Query templates with \template <name> [var=value ...]
+- Multiple output formats (table, JSON, CSV, TSV, NDJSON, SPARQL JSON)
 - Syntax highlighting and colored output
 
 REPL Commands:
   .help               Show help and available commands
-  .format [fmt]       Change output format (table, json, csv)
+  .format [fmt]       Change output format (table, json, csv, tsv, ndjson, sparql-json)
   .load <file>        Load and execute query from file
   .save <file>        Save last query to file
   .history            Show query history
@@ -62,6 +65,7 @@ REPL Commands:
   .schema             Show available predicates and types
   .examples           Show example queries
   .stats              Show graph statistics
+  .prefixes           List known prefixes
   .exit               Exit REPL (or Ctrl+D)
 
 Examples:
@@ -84,6 +88,9 @@ Examples:
 
   # Load query from file
   graphfs> .load my-query.sparql
+
+  # Run a query template
+  graphfs> \template find-usages module=pkg/graph/graph.go
 `,
 	RunE: runREPL,
 }
@@ -123,6 +130,7 @@ func runREPL(cmd *cobra.Command, args []string) error {
 	buildOpts := graph.BuildOptions{
 		ScanOptions: scanOpts,
 		Validate:    false, // Disable validation for REPL to avoid circular dependency false positives
+		BaseIRI:     config.URIs.Base,
 	}
 
 	g, err := builder.Build(rootPath, buildOpts)
@@ -132,14 +140,26 @@ func runREPL(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Built graph with %d modules, %d triples\n\n", len(g.Modules), g.Store.Count())
 
-	// Create query executor
+	// Create query executor with shared prefixes and macros
 	executor := query.NewExecutor(g.Store)
+	preprocessor, err := loadQueryPreprocessor(rootPath)
+	if err != nil {
+		return err
+	}
+	executor.SetPreprocessor(preprocessor)
+
+	templates, err := newTemplateManager(rootPath)
+	if err != nil {
+		return err
+	}
 
 	// Create REPL config
 	replConfig := &repl.Config{
 		HistoryFile: filepath.Join(os.TempDir(), ".graphfs_history"),
 		Prompt:      "graphfs> ",
 		NoColor:     noColor,
+		Prefixes:    preprocessor.Prefixes,
+		Templates:   templates,
 	}
 
 	// Create and start REPL
@@ -148,5 +168,11 @@ func runREPL(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create REPL: %w", err)
 	}
 
-	return r.Run()
+	// End the session on SIGINT/SIGTERM from outside the terminal so the
+	// history is saved; Ctrl+C at the prompt is still handled by readline
+	lc := newLifecycle()
+	if err := r.RunContext(lc.Context()); err != nil {
+		return err
+	}
+	return shutdownLifecycle(lc, cli.NewOutputFormatter(quiet, verbose, noColor))
 }
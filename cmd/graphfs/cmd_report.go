@@ -0,0 +1,209 @@
+/*
+# Module: cmd/graphfs/cmd_report.go
+Report command implementation.
+
+Writes an audit report bundle: a timestamped folder with docs, an HTML graph,
+the rules report, an SBOM, a metrics snapshot and a manifest. With
+--format html it writes a static multi-page HTML report instead.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [cmd_stats](./cmd_stats.go) - Rules discovery
+- [../../pkg/report](../../pkg/report/bundle.go) - Report bundles
+- [../../pkg/report/site](../../pkg/report/site.go) - Static HTML reports
+
+## Tags
+cli, command, report, audit
+
+## Exports
+reportCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_report.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_report.go" ;
+	code:description "Report command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./cmd_stats.go>, <../../pkg/report/bundle.go>, <../../pkg/report/site.go> ;
+	code:exports <#reportCmd> ;
+	code:tags "cli", "command", "report", "audit" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/report"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportBundle string
+	reportRules  string
+	reportFormat string
+	reportOutput string
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report [path]",
+	Short: "Write an audit report bundle or a static HTML report",
+	Long: `Write a single artifact to attach to compliance audits or architecture
+reviews.
+
+With --format bundle (the default) the bundle is a timestamped folder (graphfs-report-YYYYMMDD-HHMMSS) containing:
+  docs/           Generated module documentation
+  graph.html      Dependency graph (Mermaid)
+  rules.json      Rules report (from --rules, .graphfs-rules.yml, or built-in rules)
+  rules.xml       Rules report in JUnit format
+  sbom.cdx.json   CycloneDX SBOM of the scanned modules
+  metrics.json    Metrics snapshot (as shown by 'graphfs stats')
+  metrics.md      Metrics snapshot as Markdown
+  manifest.json   Every artifact with its size and SHA-256 checksum
+
+With --format html a standalone static site is written to --output, ready
+to publish from CI as a build artifact:
+  index.html      Overview with modules per layer, hotspots and cycles
+  modules.html    Every module, linking to a page per module with its
+                  documentation and a diagram of its neighbourhood
+  graph.html      Dependency graph (Mermaid)
+  rules.html      Rule violations
+  deadcode.html   Unreferenced modules and unused dependencies
+  metrics.html    Fan-in and fan-out charts and structural metrics
+
+Examples:
+  graphfs report --bundle out/
+  graphfs report --bundle audits/ --rules .graphfs-rules.yml
+  graphfs report --format html -o public/architecture`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVar(&reportBundle, "bundle", "", "Directory to write the report bundle into")
+	reportCmd.Flags().StringVarP(&reportRules, "rules", "r", "", "Path to rules file (YAML)")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "bundle", "Report format (bundle, html)")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "graphfs-report", "Directory to write the HTML report into")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	switch reportFormat {
+	case "bundle":
+		if reportBundle == "" {
+			return fmt.Errorf("--bundle is required for the bundle format")
+		}
+	case "html":
+	default:
+		return fmt.Errorf("unknown format %q (use bundle or html)", reportFormat)
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	out.Info("Building knowledge graph...")
+	scanOpts := scanner.ScanOptions{UseDefaults: true, Concurrent: true}
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{ScanOptions: scanOpts})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	files, err := scanCoverageFiles(absPath, scanOpts)
+	if err != nil {
+		return err
+	}
+
+	opts := report.BundleOptions{
+		OutputDir: reportBundle,
+		Tool:      fmt.Sprintf("%s %s", Name, Version),
+		Files:     files,
+	}
+
+	// Shadow statistics are optional
+	if shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig()); err == nil {
+		if err := shadowFS.LoadIndex(); err == nil {
+			stats := shadowFS.Index().Statistics()
+			opts.ShadowStats = &stats
+		} else {
+			out.Debug("No shadow index: %v", err)
+		}
+	}
+
+	opts.Rules, opts.RulesSource, err = loadProjectRules(absPath, reportRules)
+	if err != nil {
+		return err
+	}
+	opts.Preprocessor, err = loadQueryPreprocessor(absPath)
+	if err != nil {
+		return err
+	}
+
+	if reportFormat == "html" {
+		return writeHTMLReport(out, g, opts)
+	}
+
+	out.Info("Writing report bundle...")
+	dir, manifest, err := report.WriteBundle(g, opts)
+	if err != nil {
+		return fmt.Errorf("failed to write report bundle: %w", err)
+	}
+
+	out.Success("Report bundle written to %s", dir)
+	out.KeyValue("Modules", manifest.Modules)
+	out.KeyValue("Artifacts", len(manifest.Artifacts))
+	if manifest.RulesPassed {
+		out.KeyValue("Rules", "passed")
+	} else {
+		out.KeyValue("Rules", "failed (see rules.json)")
+	}
+	return nil
+}
+
+// writeHTMLReport writes the static HTML report with the inputs gathered
+// for a bundle
+func writeHTMLReport(out *cli.OutputFormatter, g *graph.Graph, opts report.BundleOptions) error {
+	out.Info("Writing HTML report...")
+	summary, err := report.WriteSite(g, report.SiteOptions{
+		OutputDir:    reportOutput,
+		Tool:         opts.Tool,
+		Files:        opts.Files,
+		ShadowStats:  opts.ShadowStats,
+		Rules:        opts.Rules,
+		RulesSource:  opts.RulesSource,
+		Preprocessor: opts.Preprocessor,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+
+	out.Success("HTML report written to %s", filepath.Join(reportOutput, "index.html"))
+	out.KeyValue("Modules", summary.Modules)
+	out.KeyValue("Pages", summary.Pages)
+	if summary.RulesPassed {
+		out.KeyValue("Rules", "passed")
+	} else {
+		out.KeyValue("Rules", fmt.Sprintf("failed, %d violations (see rules.html)", summary.Violations))
+	}
+	return nil
+}
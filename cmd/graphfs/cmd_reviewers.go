@@ -0,0 +1,196 @@
+/*
+# Module: cmd/graphfs/cmd_reviewers.go
+Reviewers command implementation.
+
+Suggests reviewers for staged changes or a git diff range from the owners
+of the changed modules and of their high-impact dependents, as JSON a
+GitHub Action can pass to the request-reviewers API.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [cmd_describe](./cmd_describe.go) - Change summaries
+- [cmd_criticality](./cmd_criticality.go) - Criticality scoring
+- [../../pkg/analysis](../../pkg/analysis/reviewers.go) - Reviewer suggestions
+
+## Tags
+cli, command, git, ownership, review
+
+## Exports
+reviewersCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_reviewers.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_reviewers.go" ;
+	code:description "Reviewers command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./cmd_describe.go>, <./cmd_criticality.go>, <../../pkg/analysis/reviewers.go> ;
+	code:exports <#reviewersCmd> ;
+	code:tags "cli", "command", "git", "ownership", "review" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewersFormat         string
+	reviewersDepth          int
+	reviewersMinCriticality float64
+	reviewersExclude        []string
+	reviewersMax            int
+)
+
+// reviewersCmd represents the reviewers command
+var reviewersCmd = &cobra.Command{
+	Use:   "reviewers [range]",
+	Short: "Suggest reviewers for a change from module owners",
+	Long: `Suggest reviewers for staged changes, or the changes in a git diff range.
+
+Owners come from owner/owners annotations. Owners of a changed module score
+1 per module. Dependents within --depth of the change whose criticality
+reaches --min-criticality are high-impact, and their owners score the
+module's criticality divided by its distance from the change.
+
+Owners written as GitHub handles (@alice) or teams (@org/team) fill the
+reviewers and team_reviewers fields of the JSON output, which match the
+body of GitHub's request-reviewers API. Other owners, such as email
+addresses, are listed as candidates only.
+
+Examples:
+  # Suggest reviewers for a branch
+  graphfs reviewers main...HEAD
+
+  # JSON for a GitHub Action, never requesting the author
+  graphfs reviewers origin/main...HEAD --format json --exclude "$PR_AUTHOR"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReviewers,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewersCmd)
+
+	reviewersCmd.Flags().StringVarP(&reviewersFormat, "format", "f", "table", "Output format (table, json)")
+	reviewersCmd.Flags().IntVar(&reviewersDepth, "depth", 1, "Consider dependents up to N steps downstream of the change")
+	reviewersCmd.Flags().Float64Var(&reviewersMinCriticality, "min-criticality", 0.5, "Minimum criticality of a high-impact dependent")
+	reviewersCmd.Flags().StringSliceVar(&reviewersExclude, "exclude", nil, "Owners never suggested, such as the author (repeatable)")
+	reviewersCmd.Flags().IntVar(&reviewersMax, "max", 5, "Request at most N reviewers and teams (0 for all)")
+}
+
+func runReviewers(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	rng := ""
+	if len(args) > 0 {
+		rng = args[0]
+	}
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	gitFilter := scanner.NewGitFilter(absPath)
+	if !gitFilter.IsGitRepository() {
+		return fmt.Errorf("not a git repository: %s", absPath)
+	}
+	files, err := gitFilter.ChangedInRange(rng)
+	if err != nil {
+		return err
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			IgnoreFiles: []string{".gitignore", ".graphfsignore"},
+			Concurrent:  true,
+		},
+		BaseIRI: projectBaseIRI(absPath),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	// Owners may be inherited from directory or workspace metadata
+	if err := applyEffectiveMetadata(g, out); err != nil {
+		return err
+	}
+
+	config, _ := projectCriticality(absPath)
+	criticality, err := applyCriticality(g, config, out)
+	if err != nil {
+		return err
+	}
+
+	changed := make([]string, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(absPath, file)
+		if err != nil {
+			continue
+		}
+		changed = append(changed, filepath.ToSlash(rel))
+	}
+
+	suggestion := analysis.SuggestReviewers(g, changed, analysis.ReviewerOptions{
+		MaxDepth:       reviewersDepth,
+		Criticality:    criticality,
+		MinCriticality: reviewersMinCriticality,
+		Exclude:        reviewersExclude,
+		MaxReviewers:   reviewersMax,
+	})
+
+	if reviewersFormat == "json" {
+		data, err := json.MarshalIndent(suggestion, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if reviewersFormat != "table" {
+		return fmt.Errorf("unknown format %q (use table or json)", reviewersFormat)
+	}
+
+	out.Header("Suggested Reviewers")
+	out.Println("")
+	if len(suggestion.Candidates) == 0 {
+		out.Info("No owners found for %d changed file(s)", len(changed))
+	} else {
+		headers := []string{"Owner", "Score", "Modules"}
+		rows := make([][]string, 0, len(suggestion.Candidates))
+		for _, c := range suggestion.Candidates {
+			modules := make([]string, len(c.Reasons))
+			for i, reason := range c.Reasons {
+				modules[i] = reason.Module
+				if reason.Depth > 0 {
+					modules[i] += fmt.Sprintf(" (dependent, depth %d)", reason.Depth)
+				}
+			}
+			rows = append(rows, []string{c.Owner, fmt.Sprintf("%.2f", c.Score), strings.Join(modules, ", ")})
+		}
+		out.Table(headers, rows)
+	}
+
+	if len(suggestion.Unowned) > 0 {
+		out.Println("")
+		out.Warning("%d changed module(s) have no owner: %s", len(suggestion.Unowned), strings.Join(suggestion.Unowned, ", "))
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ Scans a codebase and builds the knowledge graph using the graph builder.
 - [config](./config.go) - Configuration handling
 - [../../pkg/graph](../../pkg/graph/builder.go) - Graph builder
 - [../../pkg/scanner](../../pkg/scanner/scanner.go) - Scanner
+- [../../pkg/export](../../pkg/export/export.go) - RDF export
 
 ## Tags
 cli, command, scan
@@ -27,7 +28,7 @@ scanCmd
 	code:language "go" ;
 	code:layer "cli" ;
 	code:linksTo <./root.go>, <./config.go>, <../../pkg/graph/builder.go>,
-	             <../../pkg/scanner/scanner.go> ;
+	             <../../pkg/scanner/scanner.go>, <../../pkg/export/export.go> ;
 	code:exports <#scanCmd> ;
 	code:tags "cli", "command", "scan" .
 
@@ -38,11 +39,14 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/export"
+	"github.com/justin4957/graphfs/pkg/filter"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/scanner"
 	"github.com/spf13/cobra"
@@ -54,6 +58,7 @@ var (
 	scanValidate       bool
 	scanStats          bool
 	scanOutput         string
+	scanWhere          string
 	scanNoCache        bool
 	scanWorkers        int
 	scanStrict         bool
@@ -62,6 +67,14 @@ var (
 	scanSampleStrategy string
 	scanChangedSince   string
 	scanFocus          []string
+	scanInferLayers    bool
+	scanInferEdges     bool
+	scanExtract        bool
+	scanSaveSnapshot   string
+	scanRateLimit      float64
+	scanRetries        int
+	scanRetryBackoff   time.Duration
+	scanResume         bool
 )
 
 // scanCmd represents the scan command
@@ -84,15 +97,27 @@ Error Handling:
   Use --strict to abort on first error (useful for CI/CD).
   Use --max-errors N to limit error tolerance.
 
+Remote and Network-Mounted Roots:
+  --resume               Checkpoint scanned files and resume an interrupted scan
+  --retries N            Retry failed stats and reads with exponential backoff
+  --rate-limit N         Read at most N files per second
+
 Examples:
   graphfs scan                           # Scan current directory
   graphfs scan /path/to/project          # Scan specific directory
   graphfs scan --validate                # Scan with validation
   graphfs scan --stats                   # Show detailed statistics
   graphfs scan --output graph.json       # Export graph to JSON
+  graphfs scan --output graph.ttl        # Export triples as Turtle (.nt, .nq also)
+  graphfs scan --output graph.cypher     # Export Cypher statements for Neo4j
   graphfs scan --workers 4               # Use 4 parallel workers
   graphfs scan --strict                  # Abort on first error
   graphfs scan --max-errors 10           # Stop after 10 errors
+  graphfs scan --infer-layers            # Guess layers for unannotated modules
+  graphfs scan --extract                 # Build modules from unannotated source files
+
+  # Scan a flaky NFS mount gently, resuming where a failed scan stopped
+  graphfs scan /mnt/monorepo --resume --retries 5 --rate-limit 200
 
   # Sampling for quick exploration
   graphfs scan --sample 100              # Random sample of 100 files
@@ -103,6 +128,9 @@ Examples:
   graphfs scan --changed-since main      # Only files changed since main branch
   graphfs scan --changed-since HEAD~10   # Files changed in last 10 commits
 
+  # Save a snapshot for incremental CI builds (see 'graphfs validate --snapshot')
+  graphfs scan --save-snapshot .graphfs/ci-snapshot.json
+
   # Focus on specific subsystems
   graphfs scan --focus "api/**/*.go"                   # Only API Go files
   graphfs scan --focus "services/**" --focus "api/**"  # Multiple patterns`,
@@ -115,7 +143,8 @@ func init() {
 	scanCmd.Flags().StringSliceVar(&scanExclude, "exclude", nil, "Exclude files matching pattern")
 	scanCmd.Flags().BoolVar(&scanValidate, "validate", false, "Validate graph consistency")
 	scanCmd.Flags().BoolVar(&scanStats, "stats", false, "Show detailed statistics")
-	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", "", "Export graph to file")
+	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", "", "Export graph to file (.ttl, .nt and .nq write RDF; .cypher and .cql write Cypher; otherwise JSON)")
+	scanCmd.Flags().StringVar(&scanWhere, "where", "", "Export only modules matching a filter expression")
 	scanCmd.Flags().BoolVar(&scanNoCache, "no-cache", false, "Disable persistent caching")
 	scanCmd.Flags().IntVarP(&scanWorkers, "workers", "w", 0, "Number of parallel workers (0 = NumCPU)")
 	scanCmd.Flags().BoolVar(&scanStrict, "strict", false, "Abort on first error (for CI/CD)")
@@ -126,6 +155,18 @@ func init() {
 	scanCmd.Flags().StringVar(&scanSampleStrategy, "sample-strategy", "random", "Sampling strategy (random, stratified, recent)")
 	scanCmd.Flags().StringVar(&scanChangedSince, "changed-since", "", "Only analyze files changed since git ref")
 	scanCmd.Flags().StringSliceVar(&scanFocus, "focus", nil, "Focus on files matching pattern(s)")
+
+	// Layer inference
+	scanCmd.Flags().BoolVar(&scanInferLayers, "infer-layers", false, "Infer provisional layers for modules without code:layer")
+	scanCmd.Flags().BoolVar(&scanInferEdges, "infer-edges", false, "Add inferred edges for undeclared imports between modules")
+	scanCmd.Flags().BoolVar(&scanExtract, "extract", false, "Extract modules from Go, Python, TypeScript, JavaScript and Java files without LinkedDoc headers")
+	scanCmd.Flags().StringVar(&scanSaveSnapshot, "save-snapshot", "", "Save a build snapshot for incremental builds to file")
+
+	// Remote and network-mounted roots
+	scanCmd.Flags().Float64Var(&scanRateLimit, "rate-limit", 0, "Read at most N files per second (0 = unlimited)")
+	scanCmd.Flags().IntVar(&scanRetries, "retries", 0, "Retry failed file stats and reads N times")
+	scanCmd.Flags().DurationVar(&scanRetryBackoff, "retry-backoff", 200*time.Millisecond, "Delay before the first retry, doubled after each")
+	scanCmd.Flags().BoolVar(&scanResume, "resume", false, "Checkpoint scanned files and resume an interrupted scan")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
@@ -170,6 +211,12 @@ func runScan(cmd *cobra.Command, args []string) error {
 		Workers:         scanWorkers, // 0 = use NumCPU
 		StrictMode:      scanStrict,
 		MaxErrors:       scanMaxErrors,
+		RateLimit:       scanRateLimit,
+		Retries:         scanRetries,
+		RetryBackoff:    scanRetryBackoff,
+	}
+	if scanResume {
+		scanOpts.Checkpoint = filepath.Join(absPath, ".graphfs", "cache", "scan-checkpoint.json")
 	}
 
 	if len(scanInclude) > 0 {
@@ -194,6 +241,18 @@ func runScan(cmd *cobra.Command, args []string) error {
 		SampleStrategy: scanner.ParseSamplingStrategy(scanSampleStrategy),
 		ChangedSince:   scanChangedSince,
 		FocusPatterns:  scanFocus,
+
+		BaseIRI:        config.URIs.Base,
+		InferLayers:    scanInferLayers,
+		InferEdges:     scanInferEdges,
+		ExtractSource:  scanExtract,
+		RecordSnapshot: scanSaveSnapshot != "",
+		ToolVersion:    Version,
+	}
+
+	// A snapshot must cover the whole tree to be restored from
+	if scanSaveSnapshot != "" && (scanSample > 0 || scanChangedSince != "" || len(scanFocus) > 0) {
+		return fmt.Errorf("--save-snapshot cannot be combined with --sample, --changed-since or --focus")
 	}
 
 	graphObj, err := builder.Build(absPath, buildOpts)
@@ -201,12 +260,61 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to build graph: %w", err)
 	}
 
+	if scanSaveSnapshot != "" {
+		if err := saveBuildSnapshot(builder, absPath, scanSaveSnapshot, out); err != nil {
+			return err
+		}
+	}
+
 	// Print summary
 	out.Println("")
 	out.Success("Knowledge graph built successfully")
 	out.KeyValue("Modules", graphObj.Statistics.TotalModules)
 	out.KeyValue("Triples", graphObj.Statistics.TotalTriples)
 	out.KeyValue("Relationships", graphObj.Statistics.TotalRelationships)
+	if graphObj.Statistics.DuplicatePaths > 0 {
+		out.KeyValue("Duplicate paths", graphObj.Statistics.DuplicatePaths)
+		for _, module := range graphObj.Modules {
+			for _, alias := range module.Aliases {
+				out.Debug("  %s: alias of %s", alias, module.Path)
+			}
+		}
+	}
+
+	if scanInferLayers {
+		inferred := 0
+		for _, module := range graphObj.Modules {
+			if module.HasInferredLayer() {
+				inferred++
+				out.Debug("  %s: inferred layer %s", module.Path, module.Layer)
+			}
+		}
+		out.KeyValue("Inferred layers", inferred)
+	}
+
+	if scanExtract {
+		extracted := 0
+		for _, module := range graphObj.Modules {
+			if module.IsInferred() {
+				extracted++
+				out.Debug("  %s: extracted from source", module.Path)
+			}
+		}
+		out.KeyValue("Extracted modules", extracted)
+	}
+
+	if scanInferEdges || scanExtract {
+		inferred := 0
+		for _, module := range graphObj.Modules {
+			for _, edge := range module.Edges {
+				if edge.Inferred {
+					inferred++
+					out.Debug("  %s -> %s: inferred %s", module.Path, edge.Target, edge.Relation)
+				}
+			}
+		}
+		out.KeyValue("Inferred edges", inferred)
+	}
 
 	// Show validation results if requested
 	if scanValidate {
@@ -267,7 +375,24 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	// Export graph if requested
 	if scanOutput != "" {
-		if err := exportGraph(graphObj, scanOutput); err != nil {
+		where, err := filter.Parse(scanWhere)
+		if err != nil {
+			return err
+		}
+		exported := filter.Subgraph(graphObj, where)
+		if format, ok := export.FormatForFile(scanOutput); ok {
+			skipped, err := exportRDF(exported, scanOutput, format, rdfBaseIRI(absPath, config.URIs.Base))
+			if err != nil {
+				return fmt.Errorf("failed to export graph: %w", err)
+			}
+			if skipped > 0 {
+				out.Warning("Skipped %d triple(s) that are not valid RDF", skipped)
+			}
+		} else if export.IsCypherFile(scanOutput) {
+			if err := exportCypher(exported, scanOutput); err != nil {
+				return fmt.Errorf("failed to export graph: %w", err)
+			}
+		} else if err := exportGraph(exported, scanOutput); err != nil {
 			return fmt.Errorf("failed to export graph: %w", err)
 		}
 		out.Success("Graph exported to %s", scanOutput)
@@ -287,6 +412,7 @@ func exportGraph(g *graph.Graph, filename string) error {
 	// Create export structure
 	export := map[string]interface{}{
 		"root":       g.Root,
+		"provenance": g.Provenance,
 		"modules":    g.Modules,
 		"statistics": g.Statistics,
 	}
@@ -304,3 +430,66 @@ func exportGraph(g *graph.Graph, filename string) error {
 
 	return nil
 }
+
+// exportRDF writes the full triple store, including named graphs for
+// N-Quads, and returns the number of triples skipped as invalid RDF
+func exportRDF(g *graph.Graph, filename string, format export.Format, base string) (int, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	skipped, err := export.Write(f, export.Quads(g.Store), format, export.Options{
+		Base:   base,
+		Header: fmt.Sprintf("GraphFS export of %s", g.Root),
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return skipped, err
+}
+
+// exportCypher writes the modules and their relationships as Cypher
+// statements for Neo4j
+func exportCypher(g *graph.Graph, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	err = export.WriteCypher(f, g, export.CypherOptions{
+		Header: fmt.Sprintf("GraphFS export of %s", g.Root),
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// rdfBaseIRI returns the configured base IRI, or the file: IRI of the
+// project root, for resolving relative references in RDF exports
+func rdfBaseIRI(rootPath, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(rootPath) + "/"}).String()
+}
+
+// saveBuildSnapshot writes the snapshot recorded by the last build. Restoring
+// diffs against the snapshot's commit, so uncommitted changes are flagged.
+func saveBuildSnapshot(builder *graph.Builder, absPath, filename string, out *cli.OutputFormatter) error {
+	snapshot := builder.Snapshot()
+	if snapshot == nil {
+		return fmt.Errorf("no snapshot was recorded")
+	}
+
+	if snapshot.Commit == "" {
+		out.Warning("Not a git repository: restoring this snapshot needs explicit --changed files")
+	} else if changes, err := scanner.NewGitFilter(absPath).UncommittedChanges(); err == nil && len(changes) > 0 {
+		out.Warning("Snapshot includes %d uncommitted file(s); builds restored from it keep their uncommitted content", len(changes))
+	}
+
+	if err := snapshot.Save(filename); err != nil {
+		return err
+	}
+	out.Success("Saved snapshot of %d modules at %.12s to %s", len(snapshot.Modules), snapshot.Commit, filename)
+	return nil
+}
@@ -0,0 +1,225 @@
+/*
+# Module: cmd/graphfs/cmd_schedule.go
+Schedule command implementation.
+
+Runs the nightly analyses configured in .graphfs/schedule.yaml, stores the
+report and emits only what got worse since the previous night to webhooks
+and a Markdown digest.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [config](./config.go) - Scan configuration
+- [cmd_stats](./cmd_stats.go) - Rules discovery
+- [../../pkg/schedule](../../pkg/schedule/schedule.go) - Scheduled analysis runs
+
+## Tags
+cli, command, schedule, nightly
+
+## Exports
+scheduleCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_schedule.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_schedule.go" ;
+	code:description "Schedule command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./config.go>, <./cmd_stats.go>, <../../pkg/schedule/schedule.go> ;
+	code:exports <#scheduleCmd> ;
+	code:tags "cli", "command", "schedule", "nightly" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/schedule"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleDigest string
+	scheduleDryRun bool
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Scheduled analysis commands",
+	Long: `Commands for scheduled (nightly) analysis.
+
+Available subcommands:
+  run - Run the configured analyses and report what got worse`,
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run [path]",
+	Short: "Run the nightly analyses and report what got worse",
+	Long: `Run the analyses configured in .graphfs/schedule.yaml, intended for a
+nightly cron job or CI schedule.
+
+The report is stored in .graphfs/schedule/<date>.json and compared with the
+latest report from an earlier day. Only deltas are emitted: new dependency
+cycles, new dead code, coverage drops and new rule violations. They are
+posted as JSON to each webhook and written as a Markdown digest. The first
+run records a baseline.
+
+Configuration (.graphfs/schedule.yaml, all optional):
+  analyses: [cycles, deadcode, coverage, rules]
+  webhooks: [https://hooks.example.com/graphfs]
+  digest: reports/nightly.md    # relative to the project root
+  rules: .graphfs-rules.yml
+  keep: 30                      # stored reports
+  threshold: 1.0                # coverage drop in points to report
+
+Examples:
+  graphfs schedule run
+  graphfs schedule run --digest nightly.md
+
+  # Compare without storing the report or calling webhooks
+  graphfs schedule run --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScheduleRun,
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+
+	scheduleRunCmd.Flags().StringVar(&scheduleDigest, "digest", "", "Write the Markdown digest to a file (overrides the configured digest)")
+	scheduleRunCmd.Flags().BoolVar(&scheduleDryRun, "dry-run", false, "Print the digest without storing the report or calling webhooks")
+}
+
+func runScheduleRun(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	graphfsDir := filepath.Join(absPath, ".graphfs")
+	scheduleConfig, err := schedule.LoadConfig(graphfsDir)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadConfig(filepath.Join(graphfsDir, "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+	scanOpts := scanner.ScanOptions{
+		IncludePatterns: config.Scan.Include,
+		ExcludePatterns: config.Scan.Exclude,
+		MaxFileSize:     config.Scan.MaxFileSize,
+		UseDefaults:     true,
+		IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+		Concurrent:      true,
+	}
+
+	out.Info("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{ScanOptions: scanOpts, BaseIRI: config.URIs.Base})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	files, err := scanCoverageFiles(absPath, scanOpts)
+	if err != nil {
+		return err
+	}
+
+	rulesFile := scheduleConfig.Rules
+	if rulesFile != "" && !filepath.IsAbs(rulesFile) {
+		rulesFile = filepath.Join(absPath, rulesFile)
+	}
+	ruleList, _, err := loadProjectRules(absPath, rulesFile)
+	if err != nil {
+		return err
+	}
+	preprocessor, err := loadQueryPreprocessor(absPath)
+	if err != nil {
+		return err
+	}
+
+	out.Info("Running %d analyses...", len(scheduleConfig.Analyses))
+	report, err := schedule.Run(g, schedule.Options{
+		Analyses:     scheduleConfig.Analyses,
+		Files:        files,
+		Rules:        ruleList,
+		Preprocessor: preprocessor,
+	})
+	if err != nil {
+		return err
+	}
+
+	store := &schedule.Store{Dir: filepath.Join(graphfsDir, schedule.ReportsDir), Keep: scheduleConfig.Keep}
+	previous, err := store.Previous(report.Date)
+	if err != nil {
+		return err
+	}
+	if !scheduleDryRun {
+		if err := store.Save(report); err != nil {
+			return err
+		}
+	}
+	if previous == nil {
+		out.Success("Baseline recorded for %s; deltas are reported from the next run", report.Date)
+		return nil
+	}
+
+	delta := schedule.Diff(previous, report, scheduleConfig.Threshold)
+	if delta.IsEmpty() {
+		out.Success("Nothing got worse since %s", previous.Date)
+		return nil
+	}
+
+	digest := schedule.FormatMarkdown(delta)
+	digestPath := scheduleDigest
+	if digestPath == "" && scheduleConfig.Digest != "" {
+		digestPath = filepath.Join(absPath, scheduleConfig.Digest)
+	}
+	if digestPath == "" || scheduleDryRun {
+		fmt.Print(digest)
+	} else {
+		if err := os.MkdirAll(filepath.Dir(digestPath), 0755); err != nil {
+			return fmt.Errorf("failed to create digest directory: %w", err)
+		}
+		if err := os.WriteFile(digestPath, []byte(digest), 0644); err != nil {
+			return fmt.Errorf("failed to write digest: %w", err)
+		}
+		out.Info("Digest written to %s", digestPath)
+	}
+
+	if !scheduleDryRun && len(scheduleConfig.Webhooks) > 0 {
+		if err := schedule.Deliver(delta, scheduleConfig.Webhooks); err != nil {
+			return err
+		}
+		out.Info("Delivered to %d webhook(s)", len(scheduleConfig.Webhooks))
+	}
+
+	out.Warning("Changes since %s:", previous.Date)
+	out.KeyValue("New cycles", len(delta.NewCycles))
+	out.KeyValue("New dead code", len(delta.NewDeadCode))
+	out.KeyValue("Coverage drops", len(delta.CoverageDrops))
+	out.KeyValue("New violations", len(delta.NewViolations))
+	return nil
+}
@@ -0,0 +1,307 @@
+/*
+# Module: cmd/graphfs/cmd_search.go
+Search command implementation.
+
+Full-text search over shadow entries: paths, names, descriptions, exports,
+tags, concepts and annotation values, ranked by relevance. With --semantic,
+ranks modules by embedding similarity of their descriptions and header
+prose instead.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [cmd_shadow](./cmd_shadow.go) - Shadow index management
+- [config](./config.go) - Embedding provider configuration
+- [../../pkg/shadow](../../pkg/shadow/textindex.go) - Full-text index
+- [../../pkg/semantic](../../pkg/semantic/store.go) - Semantic search
+
+## Tags
+cli, command, search, shadow, semantic
+
+## Exports
+searchCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_search.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_search.go" ;
+	code:description "Search command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <./cmd_shadow.go>, <./config.go>, <../../pkg/shadow/textindex.go>, <../../pkg/semantic/store.go> ;
+	code:exports <#searchCmd> ;
+	code:tags "cli", "command", "search", "shadow", "semantic" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/semantic"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchLanguage string
+	searchLayer    string
+	searchTags     []string
+	searchConcepts []string
+	searchLimit    int
+	searchOutput   string
+	searchSemantic bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <text> [path]",
+	Short: "Full-text search over module metadata",
+	Long: `Search module metadata for text, best matches first.
+
+Searches the shadow index: paths, names, descriptions, exports, tags,
+concepts and annotation values. Modules must match every word of the text.
+Identifiers are split at camelCase boundaries, so "token" finds
+ValidateToken, and words also match as prefixes ("auth" finds
+"authentication"). Matches in paths, names, exports, tags and concepts
+rank above matches in descriptions and annotations.
+
+Run 'graphfs shadow build' first. Indexes built by older versions only
+search paths, names, tags and concepts until 'graphfs shadow rebuild-index'.
+
+Semantic search (--semantic) ranks modules by how close the embedding of
+their name, description and LinkedDoc header prose is to the text's,
+finding modules that describe an idea in other words. Embeddings are kept
+in .graphfs/embeddings.json and recomputed only for modules whose text
+changed. The provider is set in .graphfs/config.yaml:
+
+  semantic:
+    provider: openai                     # local (default) or openai
+    model: nomic-embed-text
+    base_url: http://localhost:11434/v1  # Any OpenAI-compatible API
+    api_key_env: OPENAI_API_KEY
+
+The local provider needs no model or network but only matches shared
+vocabulary.
+
+Examples:
+  graphfs search "token validation"
+  graphfs search retry --layer service --limit 5
+  graphfs search ParseConfig --output json
+  graphfs search --semantic "where do we hash passwords"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().StringVar(&searchLanguage, "language", "", "Filter by language")
+	searchCmd.Flags().StringVar(&searchLayer, "layer", "", "Filter by layer")
+	searchCmd.Flags().StringSliceVar(&searchTags, "tags", nil, "Filter by tags")
+	searchCmd.Flags().StringSliceVar(&searchConcepts, "concepts", nil, "Filter by concepts")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Maximum number of results (0 for all)")
+	searchCmd.Flags().StringVarP(&searchOutput, "output", "o", "table", "Output format (table, json, paths)")
+	searchCmd.Flags().BoolVar(&searchSemantic, "semantic", false, "Rank modules by embedding similarity")
+}
+
+// searchHit is a search result as printed
+type searchHit struct {
+	Path  string   `json:"path"`
+	Score float64  `json:"score"`
+	Layer string   `json:"layer,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	if searchOutput != "table" && searchOutput != "json" && searchOutput != "paths" {
+		return fmt.Errorf("unknown output format %q (use table, json or paths)", searchOutput)
+	}
+
+	targetPath := "."
+	if len(args) > 1 {
+		targetPath = args[1]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	search := shadowSearch
+	if searchSemantic {
+		search = semanticSearch
+	}
+	hits, err := search(out, absPath, args[0])
+	if err != nil {
+		return err
+	}
+
+	switch searchOutput {
+	case "json":
+		data, err := json.MarshalIndent(hits, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize results: %w", err)
+		}
+		fmt.Println(string(data))
+
+	case "paths":
+		for _, hit := range hits {
+			fmt.Println(hit.Path)
+		}
+
+	default: // table
+		if len(hits) == 0 {
+			out.Info("No modules match %q", args[0])
+			return nil
+		}
+
+		out.Header(fmt.Sprintf("Search Results for %q (%d)", args[0], len(hits)))
+		out.Println("")
+
+		rows := make([][]string, 0, len(hits))
+		for _, hit := range hits {
+			rows = append(rows, []string{fmt.Sprintf("%.2f", hit.Score), hit.Path, hit.Layer, strings.Join(hit.Tags, ", ")})
+		}
+		out.Table([]string{"Score", "Path", "Layer", "Tags"}, rows)
+	}
+
+	return nil
+}
+
+// shadowSearch runs a full-text search of the shadow index
+func shadowSearch(out *cli.OutputFormatter, absPath, text string) ([]searchHit, error) {
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+	if err := shadowFS.LoadIndex(); err != nil {
+		if err := shadowFS.RebuildIndex(); err != nil {
+			return nil, fmt.Errorf("failed to load or rebuild index: %w", err)
+		}
+	}
+
+	taxonomy, err := shadowFS.LoadConceptTaxonomy()
+	if err != nil {
+		return nil, err
+	}
+
+	results := shadowFS.Index().SearchRanked(shadow.SearchQuery{
+		Language:  searchLanguage,
+		Layer:     searchLayer,
+		Tags:      searchTags,
+		Concepts:  searchConcepts,
+		TextQuery: text,
+		Limit:     searchLimit,
+		Taxonomy:  taxonomy,
+	})
+
+	hits := make([]searchHit, 0, len(results))
+	for _, result := range results {
+		hit := searchHit{Path: result.Path, Score: result.Score}
+		if entry, ok := shadowFS.Index().Get(result.Path); ok {
+			hit.Layer = entry.Layer
+			hit.Tags = entry.Tags
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// semanticSearch ranks the modules of the graph by embedding similarity,
+// updating stored embeddings first
+func semanticSearch(out *cli.OutputFormatter, absPath, text string) ([]searchHit, error) {
+	if len(searchConcepts) > 0 {
+		return nil, fmt.Errorf("--concepts is not supported with --semantic")
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+	provider, err := semantic.NewProvider(config.Semantic)
+	if err != nil {
+		return nil, err
+	}
+
+	out.Debug("Building knowledge graph...")
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI: config.URIs.Base,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	ctx := context.Background()
+	storePath := filepath.Join(absPath, ".graphfs", semantic.StoreFile)
+	store, err := semantic.LoadStore(storePath)
+	if err != nil {
+		return nil, err
+	}
+	embedded, updateErr := store.Update(ctx, provider, semantic.Documents(absPath, g), config.Semantic.BatchSize)
+	if embedded > 0 {
+		// Keep the vectors computed so far even if a later batch failed
+		if err := store.Save(storePath); err != nil {
+			return nil, err
+		}
+		out.Debug("Embedded %d modules with %s", embedded, provider.ModelID())
+	}
+	if updateErr != nil {
+		return nil, fmt.Errorf("failed to embed modules: %w", updateErr)
+	}
+
+	results, err := store.Search(ctx, provider, text, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]searchHit, 0)
+	for _, result := range results {
+		module := g.GetModule(result.Path)
+		if module == nil || !matchesSearchFilters(module) {
+			continue
+		}
+		hits = append(hits, searchHit{Path: module.Path, Score: result.Score, Layer: module.Layer, Tags: module.Tags})
+		if searchLimit > 0 && len(hits) == searchLimit {
+			break
+		}
+	}
+	return hits, nil
+}
+
+// matchesSearchFilters reports whether a module passes the --language,
+// --layer and --tags filters
+func matchesSearchFilters(module *graph.Module) bool {
+	if searchLanguage != "" && module.Language != searchLanguage {
+		return false
+	}
+	if searchLayer != "" && module.Layer != searchLayer {
+		return false
+	}
+	for _, tag := range searchTags {
+		if !slices.Contains(module.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
@@ -8,6 +8,9 @@ Starts HTTP server for SPARQL and future GraphQL/REST endpoints.
 - [../../pkg/server](../../pkg/server/server.go) - HTTP server
 - [../../pkg/scanner](../../pkg/scanner/scanner.go) - Filesystem scanner
 - [../../pkg/graph](../../pkg/graph/graph.go) - Graph builder
+- [../../pkg/shadow](../../pkg/shadow/shadow.go) - Annotation storage
+- [../../pkg/watch](../../pkg/watch/watcher.go) - File watching
+- [lifecycle](./lifecycle.go) - Graceful shutdown
 
 ## Tags
 cli, server, command
@@ -21,7 +24,7 @@ cli, server, command
     code:description "CLI command to start GraphFS HTTP server" ;
     code:language "go" ;
     code:layer "cli" ;
-    code:linksTo <../../pkg/server/server.go>, <../../pkg/scanner/scanner.go>, <../../pkg/graph/graph.go> ;
+    code:linksTo <../../pkg/server/server.go>, <../../pkg/scanner/scanner.go>, <../../pkg/graph/graph.go>, <../../pkg/shadow/shadow.go>, <../../pkg/watch/watcher.go>, <./lifecycle.go> ;
     code:tags "cli", "server", "command" .
 <!-- End LinkedDoc RDF -->
 */
@@ -30,19 +33,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
 	"path/filepath"
-	"syscall"
 	"time"
 
+	"github.com/justin4957/graphfs/pkg/cli"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/query"
 	"github.com/justin4957/graphfs/pkg/scanner"
 	"github.com/justin4957/graphfs/pkg/server"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/justin4957/graphfs/pkg/watch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -55,6 +60,12 @@ var serveCmd = &cobra.Command{
 The server scans the codebase, builds the knowledge graph, and exposes
 query endpoints via HTTP. The graph is loaded at startup and kept in memory.
 
+With --watch, changed files are applied to a copy of the graph in the
+background; with --refresh, the graph is rebuilt at that interval. Either
+way the new graph is swapped in atomically once complete, so clients never
+query a partially built graph. /health and /version report the served graph
+version and its age.
+
 Examples:
   # Start server on default port 8080
   graphfs serve
@@ -65,15 +76,38 @@ Examples:
   # Start server on all interfaces
   graphfs serve --host 0.0.0.0 --port 8080
 
+  # Keep the graph up to date as files change
+  graphfs serve --watch
+
   # Query the server
   curl http://localhost:8080/sparql?query=SELECT+*+WHERE+{+?s+?p+?o+}+LIMIT+10
+
+  # Query the REST API from dashboards and editor plugins
+  curl http://localhost:8080/api/v1/modules
+  curl http://localhost:8080/api/v1/analysis/impact/services/auth.go
+  curl -X POST -d '{"query": "SELECT ?s WHERE { ?s ?p ?o } LIMIT 10"}' \
+    http://localhost:8080/api/v1/query
+
+  # Accept annotation writes from external tools
+  GRAPHFS_ANNOTATION_TOKEN=secret graphfs serve
+  curl -X POST -H "Authorization: Bearer secret" \
+    -d '{"key": "threat-model", "value": "reviewed", "author": "review-bot"}' \
+    http://localhost:8080/api/v1/modules/services/auth.go/annotations
+
+Annotation writes are disabled unless a token is given with
+--annotation-token or $GRAPHFS_ANNOTATION_TOKEN. They are stored as manual
+shadow annotations like graphfs shadow annotate, so rebuilds preserve them,
+and are recorded in the audit log with the actor api:<author>.
 `,
 	RunE: runServe,
 }
 
 var (
-	serveHost string
-	servePort int
+	serveHost            string
+	servePort            int
+	serveAnnotationToken string
+	serveWatch           bool
+	serveRefresh         time.Duration
 )
 
 func init() {
@@ -81,6 +115,9 @@ func init() {
 
 	serveCmd.Flags().StringVar(&serveHost, "host", "localhost", "Host to bind server to")
 	serveCmd.Flags().IntVar(&servePort, "port", 8080, "Port to listen on")
+	serveCmd.Flags().BoolVar(&serveWatch, "watch", false, "Update the graph in the background when files change")
+	serveCmd.Flags().DurationVar(&serveRefresh, "refresh", 0, "Rebuild the graph in the background at this interval (e.g. 10m)")
+	serveCmd.Flags().StringVar(&serveAnnotationToken, "annotation-token", "", "Bearer token that enables annotation writes (default: $GRAPHFS_ANNOTATION_TOKEN)")
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
@@ -114,6 +151,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	buildOpts := graph.BuildOptions{
 		ScanOptions: scanOpts,
 		Validate:    true,
+		BaseIRI:     config.URIs.Base,
 	}
 
 	g, err := builder.Build(rootPath, buildOpts)
@@ -123,8 +161,13 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Built graph with %d modules, %d triples\n", len(g.Modules), g.Store.Count())
 
-	// Create query executor
+	// Create query executor with shared prefixes and macros
 	executor := query.NewExecutor(g.Store)
+	preprocessor, err := loadQueryPreprocessor(rootPath)
+	if err != nil {
+		return err
+	}
+	executor.SetPreprocessor(preprocessor)
 
 	// Create server configuration
 	serverConfig := &server.Config{
@@ -141,29 +184,104 @@ func runServe(cmd *cobra.Command, args []string) error {
 		CacheTTL:         5 * time.Minute,
 	}
 
+	// Stop the server, watcher and stores on SIGINT/SIGTERM
+	lc := newLifecycle()
+	defer lc.Shutdown(shutdownTimeout)
+
+	// Enable annotation writes when a token is configured
+	serverConfig.AnnotationToken = serveAnnotationToken
+	if serverConfig.AnnotationToken == "" {
+		serverConfig.AnnotationToken = os.Getenv("GRAPHFS_ANNOTATION_TOKEN")
+	}
+	if serverConfig.AnnotationToken != "" {
+		shadowFS, err := shadow.NewShadowFS(rootPath, shadow.DefaultConfig())
+		if err != nil {
+			return fmt.Errorf("failed to create shadow file system: %w", err)
+		}
+		if err := shadowFS.Initialize(); err != nil {
+			return fmt.Errorf("failed to initialize shadow file system: %w", err)
+		}
+		serverConfig.Shadow = shadowFS
+		lc.Register("annotation store", func(context.Context) error { return shadowFS.Close() })
+	}
+
 	// Create and start server with GraphQL support
 	srv := server.NewServerWithGraph(serverConfig, executor, g)
 
-	// Handle graceful shutdown
-	go func() {
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-		<-sigChan
+	// Keep the served graph fresh in the background
+	newExecutor := func(g *graph.Graph) *query.Executor {
+		executor := query.NewExecutor(g.Store)
+		executor.SetPreprocessor(preprocessor)
+		return executor
+	}
+	rebuild := func() (*graph.Graph, *query.Executor, error) {
+		g, err := builder.Build(rootPath, buildOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return g, newExecutor(g), nil
+	}
+	if serveWatch {
+		watchOpts := watch.DefaultWatchOptions()
+		watchOpts.Path = rootPath
+		watcher, err := watch.NewWatcher(nil, watchOpts, func(_ *graph.Graph, changedFiles []string) {
+			err := srv.Refresh(func() (*graph.Graph, *query.Executor, error) {
+				// Update a copy so the served graph is never modified
+				next := srv.Graph().Clone()
+				if _, err := builder.Update(next, changedFiles, buildOpts); err != nil {
+					log.Printf("Incremental update failed, rebuilding: %v", err)
+					return rebuild()
+				}
+				return next, newExecutor(next), nil
+			})
+			if err != nil {
+				log.Printf("Error refreshing graph: %v", err)
+				return
+			}
+			log.Printf("Graph refreshed after %d changed file(s)", len(changedFiles))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create watcher: %w", err)
+		}
+		watcher.Start()
+		lc.Register("watcher", watcher.Shutdown)
+	}
+	if serveRefresh > 0 {
+		lc.Go("refresh", func(ctx context.Context) {
+			ticker := time.NewTicker(serveRefresh)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := srv.Refresh(rebuild); err != nil {
+						log.Printf("Error refreshing graph: %v", err)
+					}
+				}
+			}
+		})
+	}
+	lc.Register("http server", srv.Stop)
 
+	// Serve until a signal arrives or the server fails
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Start()
+	}()
+	var startErr error
+	select {
+	case <-lc.Done():
 		fmt.Println("\nShutting down server...")
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		if err := srv.Stop(ctx); err != nil {
-			log.Printf("Error during shutdown: %v", err)
+	case startErr = <-serveErr:
+		if errors.Is(startErr, http.ErrServerClosed) {
+			startErr = nil
 		}
-		os.Exit(0)
-	}()
-
-	// Start server (blocks until shutdown)
-	if err := srv.Start(); err != nil && err != http.ErrServerClosed {
-		return fmt.Errorf("server error: %w", err)
 	}
 
-	return nil
+	shutdownErr := shutdownLifecycle(lc, cli.NewOutputFormatter(quiet, verbose, noColor))
+	if startErr != nil {
+		return fmt.Errorf("server error: %w", startErr)
+	}
+	return shutdownErr
 }
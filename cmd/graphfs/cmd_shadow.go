@@ -36,11 +36,15 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/scanner"
 	"github.com/justin4957/graphfs/pkg/shadow"
 	"github.com/spf13/cobra"
@@ -62,9 +66,34 @@ var (
 	shadowOutput   string
 
 	// Shadow annotate flags
-	shadowKey    string
-	shadowValue  string
-	shadowAuthor string
+	shadowKey     string
+	shadowValue   string
+	shadowAuthor  string
+	shadowExpires string
+	shadowGlob    string
+	shadowRemove  bool
+
+	// Shadow expire flags
+	shadowPurge bool
+
+	// Shadow push-to-source flags
+	shadowPushFields    []string
+	shadowPushWrite     bool
+	shadowPushEffective bool
+
+	// Shadow rewrite-iris flags
+	shadowIRIBase   string
+	shadowIRIFrom   string
+	shadowIRIDryRun bool
+
+	// Shadow migrate-paths flags
+	shadowPathsDryRun bool
+
+	// Shadow migrate flags
+	shadowMigrateDryRun bool
+
+	// Shadow layout flags
+	shadowLayout string
 )
 
 // shadowCmd represents the shadow command
@@ -91,9 +120,15 @@ Subcommands:
   sync      Full sync (build + clean orphaned entries)
   query     Query shadow entries by various criteria
   show      Show shadow entry for a specific file
-  annotate  Add manual annotations to shadow entries
+  annotate  Add or remove manual annotations on shadow entries
+  annotations list  Audit annotation usage across shadow entries
+  validate  Check annotations against .graphfs/annotations.schema.yaml
   stats     Show shadow file system statistics
   clean     Remove orphaned shadow entries
+  expire    List or purge expired triples and annotations
+  push-to-source  Render shadow tags, layer and owner into LinkedDoc headers
+  rewrite-iris    Rewrite stored URIs against the configured base IRI
+  migrate   Upgrade shadow entries to the current format version
 
 Examples:
   graphfs shadow init                           # Initialize shadow file system
@@ -114,7 +149,10 @@ var shadowInitCmd = &cobra.Command{
 	Long: `Initialize the shadow file system structure.
 
 Creates the .graphfs/shadow/ directory and index file. This is automatically
-called by other shadow commands if needed.`,
+called by other shadow commands if needed.
+
+Use --layout packed to store entries in sharded JSONL files instead of one
+file per source file; see 'graphfs shadow migrate-layout'.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runShadowInit,
 }
@@ -167,6 +205,10 @@ Filters:
   --tags          Filter by tags (comma-separated, matches all)
   --concepts      Filter by concepts (comma-separated, matches all)
 
+Concepts declared in .graphfs/concepts.yaml also match their descendants
+and aliases: with "authentication" and "crypto" under "security",
+--concepts security matches entries about either.
+
 Output formats:
   --output json   Output as JSON
   --output table  Output as table (default)
@@ -193,20 +235,96 @@ Shows all metadata including:
 
 // shadowAnnotateCmd adds manual annotations
 var shadowAnnotateCmd = &cobra.Command{
-	Use:   "annotate <file>",
-	Short: "Add manual annotation to shadow entry",
+	Use:   "annotate [file]",
+	Short: "Add or remove manual annotations on shadow entries",
 	Long: `Add or update a manual annotation on a shadow entry.
 
 Annotations are key-value pairs that persist across rebuilds.
 Use this to add custom metadata like code review status, ownership, etc.
 
+Use --glob instead of a file to annotate every shadow entry matching a
+pattern: a directory, a directory followed by /**, or a glob such as
+'cmd/*/main.go'. Use --remove to delete the annotation with --key instead
+of setting it.
+
+Use --expires for temporary metadata such as rule waivers or incident notes.
+Expired annotations are ignored by the index, queries and rules, and can be
+removed with 'graphfs shadow expire --purge'.
+
+When .graphfs/annotations.schema.yaml exists, annotations must use a
+declared key and a value of its type; see 'graphfs shadow validate'.
+
 Example:
   graphfs shadow annotate pkg/api.go --key "reviewed" --value "true" --author "john"
-  graphfs shadow annotate pkg/api.go --key "owner" --value "team-backend"`,
-	Args: cobra.ExactArgs(1),
+  graphfs shadow annotate pkg/api.go --key "owner" --value "team-backend"
+  graphfs shadow annotate pkg/api.go --key "waiver" --value "no-cycles" --expires 14d
+  graphfs shadow annotate pkg/api.go --key "incident" --value "INC-42" --expires 2026-12-31
+  graphfs shadow annotate --glob 'pkg/api/**' --key owner --value team-x
+  graphfs shadow annotate --glob 'pkg/api/**' --key owner --remove`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runShadowAnnotate,
 }
 
+// shadowValidateCmd checks annotations against the annotation schema
+var shadowValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check annotations against the annotation schema",
+	Long: `Check every shadow entry against .graphfs/annotations.schema.yaml.
+
+The schema declares the allowed annotation keys with their value types
+(bool, enum, string, date) and the keys entries of each layer must carry:
+
+  keys:
+    owner:
+      type: string
+    reviewed:
+      type: bool
+    status:
+      type: enum
+      values: [active, deprecated, experimental]
+    review-date:
+      type: date
+  required:
+    "*": [owner]
+    api: [reviewed]
+
+Undeclared keys are violations unless the schema sets allow-unknown: true.
+Writes already reject annotations with an undeclared key or a wrong type;
+validate also reports annotations stored before the schema existed and
+entries missing required keys. Exits with an error when there are
+violations, so it can run in CI.
+
+Example:
+  graphfs shadow validate
+  graphfs shadow validate -o json`,
+	Args: cobra.NoArgs,
+	RunE: runShadowValidate,
+}
+
+// shadowAnnotationsCmd groups annotation audit commands
+var shadowAnnotationsCmd = &cobra.Command{
+	Use:   "annotations",
+	Short: "Audit annotations across shadow entries",
+}
+
+// shadowAnnotationsListCmd lists annotations across shadow entries
+var shadowAnnotationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List annotation usage across shadow entries",
+	Long: `List how annotations are used across the shadow file system.
+
+Without --key, shows each annotation key with the number of entries using
+it and its distinct values. With --key, lists every entry carrying that key
+with its value, author and expiry.
+
+Example:
+  graphfs shadow annotations list
+  graphfs shadow annotations list --key owner
+  graphfs shadow annotations list --key owner -o json`,
+	Args: cobra.NoArgs,
+	RunE: runShadowAnnotationsList,
+}
+
 // shadowStatsCmd shows shadow file system statistics
 var shadowStatsCmd = &cobra.Command{
 	Use:   "stats [path]",
@@ -233,6 +351,125 @@ This helps keep the shadow file system in sync with the actual codebase.`,
 	RunE: runShadowClean,
 }
 
+// shadowExpireCmd lists or purges expired triples and annotations
+var shadowExpireCmd = &cobra.Command{
+	Use:   "expire [path]",
+	Short: "List or purge expired shadow metadata",
+	Long: `List triples and annotations whose expiry has passed.
+
+Expired metadata is already ignored by the index, queries and rules.
+Use --purge to remove it from the shadow files.
+
+Example:
+  graphfs shadow expire
+  graphfs shadow expire --purge`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShadowExpire,
+}
+
+// shadowPushCmd renders shadow metadata back into LinkedDoc headers
+var shadowPushCmd = &cobra.Command{
+	Use:   "push-to-source [files...]",
+	Short: "Render shadow metadata into LinkedDoc headers",
+	Long: `Render curated shadow metadata (tags, layer, owner) back into each file's
+LinkedDoc block, so metadata can graduate from shadow-only into source.
+
+By default the changes are printed as unified diffs for review. Use --write
+to apply them in place. Without file arguments, every file with a shadow
+entry is processed.
+
+Tags are merged with the tags already in the header; layer and owner replace
+existing values.
+
+Flags:
+  --fields      Fields to push (default: tags,layer,owner)
+  --write       Apply the changes to the source files
+  --effective   Push effective metadata (including directory and workspace defaults)
+
+Example:
+  graphfs shadow push-to-source pkg/api/handler.go
+  graphfs shadow push-to-source --fields tags --write`,
+	RunE: runShadowPush,
+}
+
+// shadowRewriteIRIsCmd rewrites stored URIs against a base IRI
+var shadowRewriteIRIsCmd = &cobra.Command{
+	Use:   "rewrite-iris [path]",
+	Short: "Rewrite stored URIs against the base IRI",
+	Long: `Rewrite module URIs and triples in existing shadow entries so they use
+globally unique IRIs under the configured base instead of fragment-only
+identifiers (e.g. <#services/user.go>) and relative links.
+
+The base IRI is read from uris.base in .graphfs/config.yaml unless --base is
+given. Use --from to move entries written under a previous base IRI.
+
+Example:
+  graphfs shadow rewrite-iris --dry-run
+  graphfs shadow rewrite-iris --base https://graph.mycorp.com/repo/
+  graphfs shadow rewrite-iris --from https://old.mycorp.com/repo/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShadowRewriteIRIs,
+}
+
+// shadowMigratePathsCmd moves entries to canonical paths
+var shadowMigratePathsCmd = &cobra.Command{
+	Use:   "migrate-paths [path]",
+	Short: "Move shadow entries to canonical paths",
+	Long: `Rewrite shadow entries to canonical source paths (forward slashes, no
+leading ./) and move each shadow file to the location of its path key.
+
+Run this once after upgrading, after copying a shadow directory written on
+Windows, or after changing paths.fold_case in .graphfs/config.yaml. With case
+folding on, entries whose paths differ only in case are merged.
+
+Example:
+  graphfs shadow migrate-paths --dry-run
+  graphfs shadow migrate-paths`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShadowMigratePaths,
+}
+
+// shadowMigrateCmd upgrades entries to the current format version
+var shadowMigrateCmd = &cobra.Command{
+	Use:   "migrate [path]",
+	Short: "Upgrade shadow entries to the current format version",
+	Long: `Upgrade shadow entries written with an older shadow format version to the
+current one, applying each version's transforms to every entry in turn.
+
+Entries of an older major version, or without a version, cannot be read
+until they are migrated; commands that meet one report it and leave it
+untouched. Entries written by a newer graphfs are never rewritten: upgrade
+graphfs to read them.
+
+Example:
+  graphfs shadow migrate --dry-run
+  graphfs shadow migrate`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShadowMigrate,
+}
+
+// shadowMigrateLayoutCmd moves entries between storage layouts
+var shadowMigrateLayoutCmd = &cobra.Command{
+	Use:   "migrate-layout [path]",
+	Short: "Move shadow entries to another storage layout",
+	Long: `Move every shadow entry to another storage layout and rebuild the index.
+
+Layouts:
+  files   - One .shadow.json file per source file, mirroring the source tree
+  packed  - 256 sharded JSONL files under .graphfs/shadow/packed
+
+The packed layout keeps large repositories (tens of thousands of files) from
+creating huge shadow directory trees and makes listing entries much faster.
+The files layout is easier to read and to review in diffs. Every command
+detects the layout in use, so nothing else needs to change.
+
+Example:
+  graphfs shadow migrate-layout --to packed
+  graphfs shadow migrate-layout --to files`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShadowMigrateLayout,
+}
+
 // shadowRebuildIndexCmd rebuilds the index
 var shadowRebuildIndexCmd = &cobra.Command{
 	Use:   "rebuild-index [path]",
@@ -252,8 +489,17 @@ func init() {
 	shadowCmd.AddCommand(shadowQueryCmd)
 	shadowCmd.AddCommand(shadowShowCmd)
 	shadowCmd.AddCommand(shadowAnnotateCmd)
+	shadowCmd.AddCommand(shadowAnnotationsCmd)
+	shadowCmd.AddCommand(shadowValidateCmd)
+	shadowAnnotationsCmd.AddCommand(shadowAnnotationsListCmd)
 	shadowCmd.AddCommand(shadowStatsCmd)
 	shadowCmd.AddCommand(shadowCleanCmd)
+	shadowCmd.AddCommand(shadowExpireCmd)
+	shadowCmd.AddCommand(shadowPushCmd)
+	shadowCmd.AddCommand(shadowRewriteIRIsCmd)
+	shadowCmd.AddCommand(shadowMigratePathsCmd)
+	shadowCmd.AddCommand(shadowMigrateCmd)
+	shadowCmd.AddCommand(shadowMigrateLayoutCmd)
 	shadowCmd.AddCommand(shadowRebuildIndexCmd)
 
 	// Build flags
@@ -281,10 +527,43 @@ func init() {
 
 	// Annotate flags
 	shadowAnnotateCmd.Flags().StringVar(&shadowKey, "key", "", "Annotation key (required)")
-	shadowAnnotateCmd.Flags().StringVar(&shadowValue, "value", "", "Annotation value (required)")
+	shadowAnnotateCmd.Flags().StringVar(&shadowValue, "value", "", "Annotation value (required unless --remove)")
 	shadowAnnotateCmd.Flags().StringVar(&shadowAuthor, "author", "", "Annotation author")
+	shadowAnnotateCmd.Flags().StringVar(&shadowExpires, "expires", "", "Expire after a duration (e.g. 72h, 14d) or on a date (YYYY-MM-DD)")
+	shadowAnnotateCmd.Flags().StringVar(&shadowGlob, "glob", "", "Annotate every entry matching a path pattern (e.g. 'pkg/api/**')")
+	shadowAnnotateCmd.Flags().BoolVar(&shadowRemove, "remove", false, "Remove the annotation with --key")
 	_ = shadowAnnotateCmd.MarkFlagRequired("key")
-	_ = shadowAnnotateCmd.MarkFlagRequired("value")
+
+	// Validate flags
+	shadowValidateCmd.Flags().StringVarP(&shadowOutput, "output", "o", "table", "Output format (table, json)")
+
+	// Annotations list flags
+	shadowAnnotationsListCmd.Flags().StringVar(&shadowKey, "key", "", "Only list annotations with this key")
+	shadowAnnotationsListCmd.Flags().StringVarP(&shadowOutput, "output", "o", "table", "Output format (table, json)")
+
+	// Expire flags
+	shadowExpireCmd.Flags().BoolVar(&shadowPurge, "purge", false, "Remove expired metadata from shadow files")
+
+	// Push-to-source flags
+	shadowPushCmd.Flags().StringSliceVar(&shadowPushFields, "fields", []string{"tags", "layer", "owner"}, "Fields to push (tags, layer, owner)")
+	shadowPushCmd.Flags().BoolVar(&shadowPushWrite, "write", false, "Apply changes to source files instead of printing diffs")
+	shadowPushCmd.Flags().BoolVar(&shadowPushEffective, "effective", false, "Push effective metadata including inherited defaults")
+
+	// Rewrite-iris flags
+	shadowRewriteIRIsCmd.Flags().StringVar(&shadowIRIBase, "base", "", "Base IRI (default: uris.base from config)")
+	shadowRewriteIRIsCmd.Flags().StringVar(&shadowIRIFrom, "from", "", "Previous base IRI to move entries from")
+	shadowRewriteIRIsCmd.Flags().BoolVar(&shadowIRIDryRun, "dry-run", false, "List entries that would change without writing")
+
+	// Migrate-paths flags
+	shadowMigratePathsCmd.Flags().BoolVar(&shadowPathsDryRun, "dry-run", false, "List entries that would move without writing")
+
+	// Migrate flags
+	shadowMigrateCmd.Flags().BoolVar(&shadowMigrateDryRun, "dry-run", false, "List entries that would be upgraded without writing")
+
+	// Layout flags
+	shadowInitCmd.Flags().StringVar(&shadowLayout, "layout", "", "Storage layout: files or packed (default: files)")
+	shadowMigrateLayoutCmd.Flags().StringVar(&shadowLayout, "to", "", "Layout to move entries to: files or packed (required)")
+	shadowMigrateLayoutCmd.MarkFlagRequired("to")
 
 	// Register shadow command with root
 	rootCmd.AddCommand(shadowCmd)
@@ -303,18 +582,33 @@ func runShadowInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	config := shadow.DefaultConfig()
+	if shadowLayout != "" {
+		layout, err := shadow.ParseLayout(shadowLayout)
+		if err != nil {
+			return err
+		}
+		config.Layout = layout
+	}
+
 	// Create shadow file system
-	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	shadowFS, err := shadow.NewShadowFS(absPath, config)
 	if err != nil {
 		return fmt.Errorf("failed to create shadow file system: %w", err)
 	}
 
+	// An initialized workspace changes layout by moving its entries
+	detected := shadow.DetectLayout(shadowFS.ShadowPath())
+	if _, err := os.Stat(filepath.Join(shadowFS.ShadowPath(), "index.json")); err == nil && shadowLayout != "" && detected != config.Layout {
+		return fmt.Errorf("shadow entries use the %s layout; use 'graphfs shadow migrate-layout --to %s' to change it", detected, config.Layout)
+	}
+
 	// Initialize
 	if err := shadowFS.Initialize(); err != nil {
 		return fmt.Errorf("failed to initialize shadow file system: %w", err)
 	}
 
-	out.Success("Shadow file system initialized at %s", shadowFS.ShadowPath())
+	out.Success("Shadow file system initialized at %s (%s layout)", shadowFS.ShadowPath(), shadowFS.Layout())
 	return nil
 }
 
@@ -332,6 +626,12 @@ func runShadowBuild(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	out.Info("Building shadow file system...")
 
 	// Create and initialize shadow file system
@@ -361,6 +661,7 @@ func runShadowBuild(cmd *cobra.Command, args []string) error {
 		Workers:        shadowWorkers,
 		IncludeTriples: !shadowNoTriples,
 		SkipUnchanged:  !shadowForce,
+		BaseIRI:        projectBaseIRI(absPath),
 	}
 
 	// Run build
@@ -412,6 +713,12 @@ func runShadowSync(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	out.Info("Syncing shadow file system...")
 
 	// Create and initialize shadow file system
@@ -441,6 +748,7 @@ func runShadowSync(cmd *cobra.Command, args []string) error {
 		Workers:        shadowWorkers,
 		IncludeTriples: !shadowNoTriples,
 		SkipUnchanged:  !shadowForce,
+		BaseIRI:        projectBaseIRI(absPath),
 	}
 
 	if shadowSkipClean {
@@ -502,12 +810,18 @@ func runShadowQuery(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	taxonomy, err := shadowFS.LoadConceptTaxonomy()
+	if err != nil {
+		return err
+	}
+
 	// Build search query
 	query := shadow.SearchQuery{
 		Language: shadowLanguage,
 		Layer:    shadowLayer,
 		Tags:     shadowTags,
 		Concepts: shadowConcepts,
+		Taxonomy: taxonomy,
 	}
 
 	// Execute search
@@ -594,7 +908,15 @@ func runShadowShow(cmd *cobra.Command, args []string) error {
 
 	entry, err := shadowFS.Get(sourceFile)
 	if err != nil {
-		return fmt.Errorf("shadow entry not found for %s: %w", filePath, err)
+		// Follow renames recorded by graphfs rename
+		renamed := shadowFS.ResolveAlias(filePath)
+		if renamed == filePath {
+			return fmt.Errorf("shadow entry not found for %s: %w", filePath, err)
+		}
+		out.Info("%s was renamed to %s", filePath, renamed)
+		if entry, err = shadowFS.Get(renamed); err != nil {
+			return fmt.Errorf("shadow entry not found for %s: %w", renamed, err)
+		}
 	}
 
 	// Output
@@ -671,11 +993,17 @@ func runShadowShow(cmd *cobra.Command, args []string) error {
 	if len(entry.Annotations) > 0 {
 		out.Println("")
 		out.Header(fmt.Sprintf("Annotations (%d)", len(entry.Annotations)))
+		now := time.Now()
 		for _, a := range entry.Annotations {
 			out.Println("  - %s: %v", a.Key, a.Value)
 			if a.Author != "" {
 				out.Println("    (by %s)", a.Author)
 			}
+			if a.IsExpired(now) {
+				out.Println("    (expired %s)", a.ExpiresAt.Format("2006-01-02 15:04"))
+			} else if a.ExpiresAt != nil {
+				out.Println("    (expires %s)", a.ExpiresAt.Format("2006-01-02 15:04"))
+			}
 		}
 	}
 
@@ -691,7 +1019,16 @@ func runShadowShow(cmd *cobra.Command, args []string) error {
 func runShadowAnnotate(cmd *cobra.Command, args []string) error {
 	out := cli.NewOutputFormatter(quiet, verbose, noColor)
 
-	filePath := args[0]
+	switch {
+	case len(args) == 0 && shadowGlob == "":
+		return fmt.Errorf("a file or --glob is required")
+	case len(args) > 0 && shadowGlob != "":
+		return fmt.Errorf("give a file or --glob, not both")
+	case shadowRemove && (cmd.Flags().Changed("value") || shadowExpires != ""):
+		return fmt.Errorf("--remove cannot be combined with --value or --expires")
+	case !shadowRemove && !cmd.Flags().Changed("value"):
+		return fmt.Errorf("--value is required")
+	}
 
 	// Find project root
 	absPath, err := filepath.Abs(".")
@@ -699,6 +1036,12 @@ func runShadowAnnotate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Create shadow file system
 	config := shadow.DefaultConfig()
 	config.PreserveManual = true
@@ -711,33 +1054,204 @@ func runShadowAnnotate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize shadow file system: %w", err)
 	}
 
-	// Get or create shadow entry
+	if shadowRemove {
+		return removeShadowAnnotation(out, shadowFS, absPath, args)
+	}
+
+	annotation := shadow.Annotation{Key: shadowKey, Value: shadowValue, Author: shadowAuthor}
+	if shadowExpires != "" {
+		expiresAt, err := parseExpiry(shadowExpires, time.Now())
+		if err != nil {
+			return err
+		}
+		annotation.ExpiresAt = &expiresAt
+	}
+
+	if shadowGlob != "" {
+		paths, err := shadowFS.AnnotateMatching(shadowGlob, annotation, "")
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			out.Warning("No shadow entries match %s", shadowGlob)
+			return nil
+		}
+		for _, p := range paths {
+			out.Debug("  %s", p)
+		}
+		out.Success("Added annotation '%s' = '%s' to %d entries matching %s", shadowKey, shadowValue, len(paths), shadowGlob)
+		return nil
+	}
+
+	filePath := args[0]
 	sourceFile := filePath
 	if !filepath.IsAbs(filePath) {
 		sourceFile = filepath.Join(absPath, filePath)
 	}
 
-	entry, err := shadowFS.Get(sourceFile)
-	if err != nil {
-		// Create new manual entry
-		relPath, _ := filepath.Rel(absPath, sourceFile)
-		entry = shadow.NewManualEntry(relPath)
+	// Add the annotation, creating a manual entry if needed
+	if _, err := shadowFS.Annotate(sourceFile, annotation, ""); err != nil {
+		return fmt.Errorf("failed to save shadow entry: %w", err)
 	}
 
-	// Add annotation
-	entry.AddAnnotation(shadowKey, shadowValue, shadowAuthor)
+	out.Success("Added annotation '%s' = '%s' to %s", shadowKey, shadowValue, filePath)
+	return nil
+}
 
-	// Update source to mixed if it was auto
-	if entry.Source == shadow.SourceAuto {
-		entry.Source = shadow.SourceMixed
+// removeShadowAnnotation removes the --key annotation from a file or from
+// the entries matching --glob
+func removeShadowAnnotation(out *cli.OutputFormatter, shadowFS *shadow.ShadowFS, absPath string, args []string) error {
+	if shadowGlob != "" {
+		paths, err := shadowFS.RemoveAnnotationMatching(shadowGlob, shadowKey, "")
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			out.Debug("  %s", p)
+		}
+		out.Success("Removed annotation '%s' from %d entries matching %s", shadowKey, len(paths), shadowGlob)
+		return nil
 	}
 
-	// Save entry
-	if err := shadowFS.Set(sourceFile, entry); err != nil {
+	sourceFile := args[0]
+	if !filepath.IsAbs(sourceFile) {
+		sourceFile = filepath.Join(absPath, sourceFile)
+	}
+	removed, err := shadowFS.RemoveAnnotation(sourceFile, shadowKey, "")
+	if err != nil {
 		return fmt.Errorf("failed to save shadow entry: %w", err)
 	}
+	if !removed {
+		out.Warning("%s has no annotation '%s'", args[0], shadowKey)
+		return nil
+	}
 
-	out.Success("Added annotation '%s' = '%s' to %s", shadowKey, shadowValue, filePath)
+	out.Success("Removed annotation '%s' from %s", shadowKey, args[0])
+	return nil
+}
+
+func runShadowAnnotationsList(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	records, err := shadowFS.ListAnnotations(shadowKey)
+	if err != nil {
+		return err
+	}
+
+	if shadowKey != "" {
+		if shadowOutput == "json" {
+			data, _ := json.MarshalIndent(records, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(records) == 0 {
+			out.Info("No entries have annotation '%s'", shadowKey)
+			return nil
+		}
+		out.Header(fmt.Sprintf("Annotation '%s' (%d entries)", shadowKey, len(records)))
+		rows := make([][]string, 0, len(records))
+		for _, r := range records {
+			expires := ""
+			if r.ExpiresAt != nil {
+				expires = r.ExpiresAt.Format("2006-01-02")
+				if r.Expired {
+					expires += " (expired)"
+				}
+			}
+			rows = append(rows, []string{r.Path, fmt.Sprint(r.Value), r.Author, expires})
+		}
+		out.Table([]string{"Path", "Value", "Author", "Expires"}, rows)
+		return nil
+	}
+
+	usage := shadow.SummarizeAnnotations(records)
+	if shadowOutput == "json" {
+		data, _ := json.MarshalIndent(usage, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(usage) == 0 {
+		out.Info("No annotations found")
+		return nil
+	}
+	out.Header(fmt.Sprintf("Annotation Keys (%d)", len(usage)))
+	rows := make([][]string, 0, len(usage))
+	for _, u := range usage {
+		values := make([]string, 0, len(u.Values))
+		for value, count := range u.Values {
+			values = append(values, fmt.Sprintf("%s (%d)", value, count))
+		}
+		sort.Strings(values)
+		expired := ""
+		if u.Expired > 0 {
+			expired = strconv.Itoa(u.Expired)
+		}
+		rows = append(rows, []string{u.Key, strconv.Itoa(u.Count), expired, strings.Join(values, ", ")})
+	}
+	out.Table([]string{"Key", "Entries", "Expired", "Values"}, rows)
+	return nil
+}
+
+func runShadowValidate(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	schema, err := shadowFS.AnnotationSchema()
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		out.Info("No annotation schema; create %s to declare annotation keys", filepath.Join(".graphfs", shadow.AnnotationSchemaFile))
+		return nil
+	}
+
+	violations, err := shadowFS.ValidateAnnotations()
+	if err != nil {
+		return err
+	}
+
+	if shadowOutput == "json" {
+		if violations == nil {
+			violations = []shadow.SchemaViolation{}
+		}
+		data, _ := json.MarshalIndent(violations, "", "  ")
+		fmt.Println(string(data))
+	} else if len(violations) > 0 {
+		out.Header(fmt.Sprintf("Annotation Schema Violations (%d)", len(violations)))
+		rows := make([][]string, 0, len(violations))
+		for _, v := range violations {
+			rows = append(rows, []string{v.Path, v.Key, v.Message})
+		}
+		out.Table([]string{"Path", "Key", "Problem"}, rows)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%d annotation schema violations", len(violations))
+	}
+	if shadowOutput != "json" {
+		out.Success("All annotations match the schema")
+	}
 	return nil
 }
 
@@ -771,6 +1285,7 @@ func runShadowStats(cmd *cobra.Command, args []string) error {
 	out.Header("Shadow File System Statistics")
 	out.Println("")
 
+	out.KeyValue("Layout", shadowFS.Layout())
 	out.KeyValue("Total Entries", stats.TotalEntries)
 	out.KeyValue("Total Triples", stats.TotalTriples)
 	out.Println("")
@@ -829,6 +1344,12 @@ func runShadowClean(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	out.Info("Cleaning orphaned shadow entries...")
 
 	// Create and initialize shadow file system
@@ -881,6 +1402,12 @@ func runShadowRebuildIndex(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	out.Info("Rebuilding shadow index...")
 
 	// Create shadow file system
@@ -901,3 +1428,386 @@ func runShadowRebuildIndex(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runShadowExpire(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	now := time.Now()
+	items, err := shadowFS.FindExpired(now)
+	if err != nil {
+		return fmt.Errorf("failed to find expired metadata: %w", err)
+	}
+
+	if len(items) == 0 {
+		out.Success("No expired shadow metadata")
+		return nil
+	}
+
+	var rows [][]string
+	for _, item := range items {
+		rows = append(rows, []string{item.Path, item.Kind, item.Key, item.Value,
+			item.ExpiresAt.Format("2006-01-02 15:04")})
+	}
+	out.Table([]string{"Path", "Kind", "Key", "Value", "Expired"}, rows)
+	out.Println("")
+
+	if !shadowPurge {
+		out.Info("%d expired item(s). Run with --purge to remove them.", len(items))
+		return nil
+	}
+
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	removed, err := shadowFS.PurgeExpired(now)
+	if err != nil {
+		return fmt.Errorf("failed to purge expired metadata: %w", err)
+	}
+
+	out.Success("Purged %d expired item(s)", removed)
+	return nil
+}
+
+func runShadowPush(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	fields := make([]shadow.PushField, 0, len(shadowPushFields))
+	for _, f := range shadowPushFields {
+		fields = append(fields, shadow.PushField(strings.TrimSpace(f)))
+	}
+
+	shadowFS, err := openProjectShadowFS()
+	if err != nil {
+		return err
+	}
+	rootPath := shadowFS.RootPath()
+
+	if shadowPushWrite {
+		unlock, err := lockWorkspace(rootPath, out)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	var resolver *shadow.Resolver
+	if shadowPushEffective {
+		resolver, err = newEffectiveResolver(rootPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	paths := args
+	if len(paths) == 0 {
+		entries, err := shadowFS.List()
+		if err != nil {
+			return fmt.Errorf("failed to list shadow entries: %w", err)
+		}
+		for _, entry := range entries {
+			paths = append(paths, entry.SourcePath)
+		}
+	}
+
+	changed := 0
+	for _, path := range paths {
+		var values shadow.PushValues
+		if resolver != nil {
+			meta, err := resolver.Resolve(path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve metadata for %s: %w", path, err)
+			}
+			values = shadow.PushValuesFromEffective(meta)
+		} else {
+			entry, err := shadowFS.Get(path)
+			if err != nil {
+				return fmt.Errorf("failed to get shadow entry for %s: %w", path, err)
+			}
+			values = shadow.PushValuesFromEntry(entry)
+		}
+
+		patch, err := shadow.ProposePushToSource(rootPath, path, values, fields)
+		if err != nil {
+			out.Warning("Skipping %v", err)
+			continue
+		}
+		if !patch.Changed() {
+			continue
+		}
+		changed++
+
+		if !shadowPushWrite {
+			fmt.Print(patch.UnifiedDiff())
+			continue
+		}
+		if err := patch.Apply(rootPath); err != nil {
+			return err
+		}
+		out.Success("Updated %s", path)
+	}
+
+	switch {
+	case changed == 0:
+		out.Info("LinkedDoc headers already match shadow metadata")
+	case !shadowPushWrite:
+		out.Info("%d file(s) would change. Run with --write to apply.", changed)
+	}
+
+	return nil
+}
+
+func runShadowRewriteIRIs(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	base := shadowIRIBase
+	if base == "" {
+		base = projectBaseIRI(absPath)
+	}
+	if base == "" {
+		return fmt.Errorf("no base IRI: set uris.base in .graphfs/config.yaml or pass --base")
+	}
+
+	iris, err := graph.NewIRIMapper(base)
+	if err != nil {
+		return err
+	}
+
+	if !shadowIRIDryRun {
+		unlock, err := lockWorkspace(absPath, out)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	changed, err := shadowFS.RewriteIRIs(iris, shadowIRIFrom, shadowIRIDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite IRIs: %w", err)
+	}
+
+	if verbose || shadowIRIDryRun {
+		for _, path := range changed {
+			out.Println("  %s", path)
+		}
+	}
+
+	if shadowIRIDryRun {
+		out.Info("%d shadow entries would be rewritten against %s", len(changed), iris.Base())
+		return nil
+	}
+	out.Success("Rewrote %d shadow entries against %s", len(changed), iris.Base())
+	return nil
+}
+
+func runShadowMigratePaths(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	if !shadowPathsDryRun {
+		unlock, err := lockWorkspace(absPath, out)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	migrations, err := shadowFS.MigratePaths(shadowPathsDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to migrate paths: %w", err)
+	}
+
+	if verbose || shadowPathsDryRun {
+		for _, m := range migrations {
+			note := ""
+			if m.Merged {
+				note = " (merged)"
+			}
+			out.Println("  %s -> %s%s", m.From, m.To, note)
+		}
+	}
+
+	if shadowPathsDryRun {
+		out.Info("%d shadow entries would be migrated", len(migrations))
+		return nil
+	}
+	out.Success("Migrated %d shadow entries", len(migrations))
+	return nil
+}
+
+func runShadowMigrate(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	if !shadowMigrateDryRun {
+		unlock, err := lockWorkspace(absPath, out)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	result, err := shadowFS.Migrate(shadowMigrateDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to migrate shadow entries: %w", err)
+	}
+
+	if verbose || shadowMigrateDryRun {
+		for _, m := range result.Migrated {
+			from := m.From
+			if from == "" {
+				from = "unversioned"
+			}
+			out.Println("  %s: %s -> %s", m.Path, from, shadow.ShadowVersion)
+			for _, step := range m.Steps {
+				out.Debug("    %s -> %s: %s", step.From, step.To, step.Description)
+			}
+		}
+	}
+
+	for _, buildErr := range result.Errors {
+		out.Warning("  - %s: %s: %v", buildErr.Path, buildErr.Message, buildErr.Err)
+	}
+
+	if shadowMigrateDryRun {
+		out.Info("%d shadow entries would be migrated to version %s (%d already current)",
+			len(result.Migrated), shadow.ShadowVersion, result.Current)
+	} else {
+		out.Success("Migrated %d shadow entries to version %s (%d already current)",
+			len(result.Migrated), shadow.ShadowVersion, result.Current)
+	}
+
+	if len(result.Refused) > 0 {
+		out.Println("")
+		out.Error("Refused %d shadow entries:", len(result.Refused))
+		for _, versionErr := range result.Refused {
+			out.Println("  - %v", versionErr)
+		}
+		return fmt.Errorf("%d shadow entries use an incompatible format version", len(result.Refused))
+	}
+	return nil
+}
+
+// parseExpiry parses an expiry given as a duration (72h, 14d) or a date
+// (YYYY-MM-DD or RFC 3339) relative to now
+func parseExpiry(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.AddDate(0, 0, n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q (use a duration like 72h or 14d, or a date like 2006-01-02)", value)
+}
+
+func runShadowMigrateLayout(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	layout, err := shadow.ParseLayout(shadowLayout)
+	if err != nil {
+		return err
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	unlock, err := lockWorkspace(absPath, out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	from := shadowFS.Layout()
+	if from == layout {
+		out.Info("Shadow entries already use the %s layout", layout)
+		return nil
+	}
+
+	moved, err := shadowFS.ConvertLayout(layout)
+	if err != nil {
+		return fmt.Errorf("failed to migrate shadow layout: %w", err)
+	}
+
+	out.Success("Moved %d shadow entries from the %s layout to the %s layout", moved, from, layout)
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+# Module: cmd/graphfs/cmd_shadow_diff.go
+Shadow diff and snapshot subcommands.
+
+Implements 'graphfs shadow diff', which shows the modules whose
+dependencies, tags, layer or annotations changed since a git commit or a
+graph snapshot, and 'graphfs shadow snapshot', which saves one under
+.graphfs/snapshots like 'graphfs snapshot create'.
+
+## Linked Modules
+- [shadow](./cmd_shadow.go) - Shadow command
+- [snapshot](./cmd_snapshot.go) - Graph snapshot commands
+- [../../pkg/shadow](../../pkg/shadow/diff.go) - Shadow diffs
+
+## Tags
+cli, command, shadow, diff, review
+
+## Exports
+shadowDiffCmd, shadowSnapshotCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_shadow_diff.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_shadow_diff.go" ;
+	code:description "Shadow diff and snapshot subcommands" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./cmd_shadow.go>, <./cmd_snapshot.go>, <../../pkg/shadow/diff.go> ;
+	code:exports <#shadowDiffCmd>, <#shadowSnapshotCmd> ;
+	code:tags "cli", "command", "shadow", "diff", "review" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shadowDiffSince   string
+	shadowDiffAgainst string
+	shadowDiffFormat  string
+	shadowDiffOutput  string
+)
+
+var shadowDiffCmd = &cobra.Command{
+	Use:   "diff [path]",
+	Short: "Show how shadow metadata changed since a commit or snapshot",
+	Long: `Compare the shadow entries in the working tree with an earlier state and
+show the modules added and removed, and the modules whose layer,
+dependencies, tags or annotations changed.
+
+The earlier state is the shadow directory as committed at --since (default
+HEAD), or a snapshot given with --against: the name of a snapshot under
+.graphfs/snapshots (saved by 'graphfs shadow snapshot' or 'graphfs snapshot
+create'), a snapshot file, or a copy of a shadow directory in either layout.
+
+Formats:
+  text - Readable summary (default)
+  json - Machine-readable changes
+  md   - Markdown, for pull request descriptions and review comments`,
+	Example: `  graphfs shadow diff --since main
+  graphfs shadow diff --since origin/main --format md --output drift.md
+  graphfs shadow snapshot before && graphfs shadow build
+  graphfs shadow diff --against before`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShadowDiff,
+}
+
+var shadowSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <name> [path]",
+	Short: "Save a snapshot for a later shadow diff",
+	Long: `Save a snapshot of the graph and the annotations of the shadow entries
+under .graphfs/snapshots, as 'graphfs snapshot create' does, so later
+changes can be compared with 'graphfs shadow diff --against <name>'.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runShadowSnapshot,
+}
+
+func init() {
+	shadowCmd.AddCommand(shadowDiffCmd)
+	shadowCmd.AddCommand(shadowSnapshotCmd)
+
+	shadowDiffCmd.Flags().StringVar(&shadowDiffSince, "since", "", "Git commit, branch or tag to compare with (default: HEAD)")
+	shadowDiffCmd.Flags().StringVar(&shadowDiffAgainst, "against", "", "Snapshot name, snapshot file or shadow directory to compare with")
+	shadowDiffCmd.Flags().StringVar(&shadowDiffFormat, "format", "text", "Output format (text, json, md)")
+	shadowDiffCmd.Flags().StringVarP(&shadowDiffOutput, "output", "o", "", "Write the diff to a file")
+	shadowDiffCmd.MarkFlagsMutuallyExclusive("since", "against")
+}
+
+func runShadowDiff(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	var format shadow.DiffFormat
+	switch shadowDiffFormat {
+	case "text":
+		format = shadow.DiffText
+	case "json":
+		format = shadow.DiffJSON
+	case "md", "markdown":
+		format = shadow.DiffMarkdown
+	default:
+		return fmt.Errorf("unknown format: %s (supported: text, json, md)", shadowDiffFormat)
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	current, err := shadowFS.List()
+	if err != nil {
+		return fmt.Errorf("failed to read shadow entries: %w", err)
+	}
+
+	var earlier []*shadow.Entry
+	from := shadowDiffSince
+	if shadowDiffAgainst != "" {
+		from = shadowDiffAgainst
+		against := shadowDiffAgainst
+		if snapshots := projectSnapshotStore(absPath); snapshots.Exists(against) {
+			against = snapshots.Path(against)
+		}
+		earlier, err = shadow.LoadSnapshot(against)
+	} else {
+		if from == "" {
+			from = "HEAD"
+		}
+		out.Debug("Reading shadow entries at %s", from)
+		earlier, err = shadowFS.EntriesAt(from)
+	}
+	if err != nil {
+		return err
+	}
+
+	diff := shadow.DiffStates(earlier, current)
+	diff.From = from
+	diff.To = "working tree"
+
+	report, err := shadow.FormatDiff(diff, format)
+	if err != nil {
+		return err
+	}
+
+	if shadowDiffOutput != "" {
+		if err := os.WriteFile(shadowDiffOutput, []byte(report), 0644); err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+		out.Success("Shadow diff written to %s (%d modules changed)", shadowDiffOutput, len(diff.Changes))
+		return nil
+	}
+
+	fmt.Print(report)
+	return nil
+}
+
+func runShadowSnapshot(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 1 {
+		targetPath = args[1]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if err := graph.ValidateSnapshotName(args[0]); err != nil {
+		return err
+	}
+
+	snapshot, err := recordSnapshot(absPath, args[0], out)
+	if err != nil {
+		return err
+	}
+	if err := projectSnapshotStore(absPath).Save(snapshot); err != nil {
+		return err
+	}
+
+	out.Success("Saved snapshot %s of %d modules", args[0], len(snapshot.Modules))
+	return nil
+}
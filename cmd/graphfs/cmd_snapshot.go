@@ -0,0 +1,333 @@
+/*
+# Module: cmd/graphfs/cmd_snapshot.go
+Graph snapshot commands.
+
+Implements 'graphfs snapshot create/list/diff/delete', which save
+compressed snapshots of the graph under .graphfs/snapshots and compare any
+two of them, or one with the current graph. 'graphfs shadow diff --against'
+reads the same snapshots.
+
+## Linked Modules
+- [../../pkg/graph](../../pkg/graph/snapshot_store.go) - Named graph snapshots
+- [../../pkg/history](../../pkg/history/summary.go) - Graph summaries
+- [root](./root.go) - Root command
+
+## Tags
+cli, command, snapshot, history
+
+## Exports
+snapshotCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_snapshot.go> a code:Module ;
+    code:name "cmd/graphfs/cmd_snapshot.go" ;
+    code:description "Graph snapshot commands" ;
+    code:language "go" ;
+    code:layer "cli" ;
+    code:linksTo <../../pkg/graph/snapshot_store.go>, <../../pkg/history/summary.go>, <./root.go> ;
+    code:exports <#snapshotCmd> ;
+    code:tags "cli", "command", "snapshot", "history" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/history"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotPath   string
+	snapshotForce  bool
+	snapshotFormat string
+	snapshotOutput string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and compare graph snapshots",
+	Long: `Save compressed snapshots of the graph (its modules with their triples,
+and the annotations of their shadow entries) under .graphfs/snapshots, and
+compare their modules, dependencies, cycles and summary statistics to follow
+how the architecture changes over time. 'graphfs shadow diff --against <name>'
+compares shadow metadata with a snapshot.`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Save a snapshot of the current graph",
+	Long: `Build the graph and save a snapshot of it. The name defaults to the
+current time, e.g. 2026-01-31T120000.`,
+	Example: `  graphfs snapshot create v1.2.0
+  graphfs snapshot create`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSnapshotCreate,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots",
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotList,
+}
+
+var snapshotDiffCmd = &cobra.Command{
+	Use:   "diff <old> [new]",
+	Short: "Compare two snapshots, or a snapshot with the current graph",
+	Long: `Compare two snapshots, or a snapshot with the current graph when only one
+is given. Reports the modules and dependencies added and removed, the
+dependency cycles introduced and resolved, and the change of each summary
+metric.
+
+A cycle is reported as new when its modules were not all part of a single
+cycle before, so a cycle that grows is reported too, and as resolved when
+none of its modules is in a cycle any more.`,
+	Example: `  graphfs snapshot diff v1.2.0
+  graphfs snapshot diff v1.1.0 v1.2.0 --format md --output CHANGES.md`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runSnapshotDiff,
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>...",
+	Short: "Delete saved snapshots",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runSnapshotDelete,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotDiffCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+
+	snapshotCmd.PersistentFlags().StringVarP(&snapshotPath, "path", "p", ".", "Project directory")
+	snapshotCreateCmd.Flags().BoolVar(&snapshotForce, "force", false, "Replace an existing snapshot of the same name")
+	snapshotDiffCmd.Flags().StringVar(&snapshotFormat, "format", "text", "Output format (text, json, md)")
+	snapshotDiffCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "Write the comparison to a file")
+}
+
+// snapshotStore returns the absolute project path and its snapshot store
+func snapshotStore() (string, *graph.SnapshotStore, error) {
+	absPath, err := filepath.Abs(snapshotPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	return absPath, projectSnapshotStore(absPath), nil
+}
+
+// projectSnapshotStore returns the snapshot store of a project
+func projectSnapshotStore(absPath string) *graph.SnapshotStore {
+	return graph.NewSnapshotStore(filepath.Join(absPath, filepath.FromSlash(graph.SnapshotDir)))
+}
+
+// recordSnapshot builds the current graph of a project and records it in a
+// snapshot, with the annotations of its shadow entries
+func recordSnapshot(absPath, name string, out *cli.OutputFormatter) (*graph.Snapshot, error) {
+	builder, _, err := buildSnapshotGraph(absPath, true, out)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := builder.Snapshot()
+	snapshot.Name = name
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+	if err := shadowFS.RecordAnnotations(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read shadow entries: %w", err)
+	}
+	return snapshot, nil
+}
+
+// buildSnapshotGraph builds the current graph of a project, recording a
+// snapshot of it in the builder when record is set
+func buildSnapshotGraph(absPath string, record bool, out *cli.OutputFormatter) (*graph.Builder, *graph.Graph, error) {
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	config, err := loadConfig(filepath.Join(absPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		out.Debug("Could not load config, using defaults: %v", err)
+		config = DefaultConfig()
+	}
+
+	out.Debug("Building knowledge graph...")
+	builder := graph.NewBuilder()
+	g, err := builder.Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			IncludePatterns: config.Scan.Include,
+			ExcludePatterns: config.Scan.Exclude,
+			MaxFileSize:     config.Scan.MaxFileSize,
+			UseDefaults:     true,
+			IgnoreFiles:     []string{".gitignore", ".graphfsignore"},
+			Concurrent:      true,
+		},
+		BaseIRI:        config.URIs.Base,
+		RecordSnapshot: record,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build graph: %w", err)
+	}
+	return builder, g, nil
+}
+
+func runSnapshotCreate(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	absPath, store, err := snapshotStore()
+	if err != nil {
+		return err
+	}
+
+	name := time.Now().Format("2006-01-02T150405")
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if err := graph.ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	if store.Exists(name) && !snapshotForce {
+		return fmt.Errorf("snapshot %q already exists (use --force to replace it)", name)
+	}
+
+	snapshot, err := recordSnapshot(absPath, name, out)
+	if err != nil {
+		return err
+	}
+	if err := store.Save(snapshot); err != nil {
+		return err
+	}
+
+	summary := history.FromSnapshot(snapshot)
+	out.Success("Saved snapshot %s: %d modules, %d dependencies, %d cycle groups",
+		name, summary.Stats.Modules, summary.Stats.Edges, summary.Stats.CycleGroups)
+	return nil
+}
+
+func runSnapshotList(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	_, store, err := snapshotStore()
+	if err != nil {
+		return err
+	}
+	snapshots, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		out.Info("No snapshots saved (create one with 'graphfs snapshot create')")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		s := history.FromSnapshot(snapshot)
+		commit := s.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		rows = append(rows, []string{
+			s.Name,
+			s.CreatedAt.Local().Format("2006-01-02 15:04"),
+			commit,
+			strconv.Itoa(s.Stats.Modules),
+			strconv.Itoa(s.Stats.Edges),
+			strconv.Itoa(s.Stats.CycleGroups),
+		})
+	}
+	out.Table([]string{"Name", "Created", "Commit", "Modules", "Dependencies", "Cycles"}, rows)
+	return nil
+}
+
+func runSnapshotDiff(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	var format history.Format
+	switch snapshotFormat {
+	case "text":
+		format = history.FormatText
+	case "json":
+		format = history.FormatJSON
+	case "md", "markdown":
+		format = history.FormatMarkdown
+	default:
+		return fmt.Errorf("unknown format: %s (supported: text, json, md)", snapshotFormat)
+	}
+
+	absPath, store, err := snapshotStore()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := store.Load(args[0])
+	if err != nil {
+		return err
+	}
+	old := history.FromSnapshot(snapshot)
+
+	var current *history.Summary
+	if len(args) > 1 {
+		newer, err := store.Load(args[1])
+		if err != nil {
+			return err
+		}
+		current = history.FromSnapshot(newer)
+	} else {
+		_, g, err := buildSnapshotGraph(absPath, false, out)
+		if err != nil {
+			return err
+		}
+		current = history.FromGraph(g, "current")
+	}
+
+	report, err := history.FormatComparison(history.Compare(old, current), format)
+	if err != nil {
+		return err
+	}
+
+	if snapshotOutput != "" {
+		if err := os.WriteFile(snapshotOutput, []byte(report), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		out.Success("Comparison written to %s", snapshotOutput)
+		return nil
+	}
+
+	fmt.Print(report)
+	return nil
+}
+
+func runSnapshotDelete(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	_, store, err := snapshotStore()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if err := store.Delete(name); err != nil {
+			return err
+		}
+		out.Success("Deleted snapshot %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+/*
+# Module: cmd/graphfs/cmd_stats.go
+Stats command implementation.
+
+Aggregates graph statistics, shadow statistics, rule status, documentation
+coverage, dependency cycles and coupling hotspots into one dashboard view.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/dashboard](../../pkg/dashboard/dashboard.go) - Dashboard aggregation
+- [../../pkg/graph](../../pkg/graph/builder.go) - Graph builder
+
+## Tags
+cli, command, stats, dashboard
+
+## Exports
+statsCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_stats.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_stats.go" ;
+	code:description "Stats command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/dashboard/dashboard.go>, <../../pkg/graph/builder.go> ;
+	code:exports <#statsCmd> ;
+	code:tags "cli", "command", "stats", "dashboard" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/dashboard"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/rules"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+// defaultRulesFile is used by stats when present and no --rules flag is given
+const defaultRulesFile = ".graphfs-rules.yml"
+
+var (
+	statsFormat string
+	statsOutput string
+	statsRules  string
+	statsTop    int
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats [path]",
+	Short: "Show a project dashboard",
+	Long: `Show a project dashboard for architecture reviews.
+
+Aggregates into one view:
+  - Graph statistics (modules, triples, relationships, layers)
+  - Languages, cross-language dependencies and boundary modules
+  - Shadow file system statistics (if built)
+  - Rule status (from --rules, .graphfs-rules.yml, or built-in rules)
+  - LinkedDoc documentation coverage (generated files excluded)
+  - Dependency cycles
+  - Top coupling hotspots
+
+Examples:
+  graphfs stats
+  graphfs stats --format json
+  graphfs stats --format md --output ARCHITECTURE-REVIEW.md
+  graphfs stats --rules .graphfs-rules.yml --top 20`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsFormat, "format", "table", "Output format (table, json, md)")
+	statsCmd.Flags().StringVarP(&statsOutput, "output", "o", "", "Output file for report")
+	statsCmd.Flags().StringVarP(&statsRules, "rules", "r", "", "Path to rules file (YAML)")
+	statsCmd.Flags().IntVar(&statsTop, "top", dashboard.DefaultTopN, "Number of hotspots to show")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	switch statsFormat {
+	case "table", "json", "md", "markdown":
+	default:
+		return fmt.Errorf("unknown format: %s (supported: table, json, md)", statsFormat)
+	}
+
+	// Build knowledge graph
+	builder := graph.NewBuilder()
+	g, err := builder.Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{
+			UseDefaults: true,
+			Concurrent:  true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	files, err := scanCoverageFiles(absPath, scanner.ScanOptions{
+		UseDefaults: true,
+		Concurrent:  true,
+	})
+	if err != nil {
+		return err
+	}
+
+	opts := dashboard.Options{
+		Files: files,
+		TopN:  statsTop,
+	}
+
+	// Shadow statistics are optional
+	if shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig()); err == nil {
+		if err := shadowFS.LoadIndex(); err == nil {
+			stats := shadowFS.Index().Statistics()
+			opts.ShadowStats = &stats
+		} else {
+			out.Debug("No shadow index: %v", err)
+		}
+	}
+
+	opts.Rules, opts.RulesSource, err = loadProjectRules(absPath, statsRules)
+	if err != nil {
+		return err
+	}
+
+	opts.Preprocessor, err = loadQueryPreprocessor(absPath)
+	if err != nil {
+		return err
+	}
+
+	d, err := dashboard.Collect(g, opts)
+	if err != nil {
+		return fmt.Errorf("failed to collect dashboard: %w", err)
+	}
+
+	if statsFormat == "table" {
+		printDashboard(out, d)
+		return nil
+	}
+
+	format := dashboard.FormatJSON
+	if statsFormat == "md" || statsFormat == "markdown" {
+		format = dashboard.FormatMarkdown
+	}
+
+	report, err := dashboard.FormatDashboard(d, format)
+	if err != nil {
+		return fmt.Errorf("failed to format dashboard: %w", err)
+	}
+
+	if statsOutput != "" {
+		if err := os.WriteFile(statsOutput, []byte(report), 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		out.Success("Dashboard written to %s", statsOutput)
+		return nil
+	}
+
+	fmt.Println(report)
+	return nil
+}
+
+// scanCoverageFiles scans the project again for documentation coverage, since
+// the graph builder keeps only modules
+func scanCoverageFiles(absPath string, opts scanner.ScanOptions) ([]*scanner.FileInfo, error) {
+	scanResult, err := scanner.NewScanner().Scan(absPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan files: %w", err)
+	}
+	return scanResult.Files, nil
+}
+
+// printDashboard renders the dashboard as tables
+func printDashboard(out *cli.OutputFormatter, d *dashboard.Dashboard) {
+	out.Header("Graph")
+	out.KeyValue("Modules", d.Graph.Modules)
+	out.KeyValue("Triples", d.Graph.Triples)
+	out.KeyValue("Relationships", d.Graph.Relationships)
+	if len(d.Graph.ModulesByLayer) > 0 {
+		out.Println("")
+		var rows [][]string
+		for _, layer := range dashboard.SortedKeys(d.Graph.ModulesByLayer) {
+			name := layer
+			if name == "" {
+				name = "(none)"
+			}
+			rows = append(rows, []string{name, fmt.Sprintf("%d", d.Graph.ModulesByLayer[layer])})
+		}
+		out.Table([]string{"Layer", "Modules"}, rows)
+	}
+	out.Println("")
+
+	if d.Languages != nil && len(d.Languages.Languages) > 0 {
+		printLanguages(out, d.Languages)
+	}
+
+	out.Header("Shadow File System")
+	if d.Shadow == nil {
+		out.Info("Not built. Run 'graphfs shadow build' to enable.")
+	} else {
+		out.KeyValue("Entries", d.Shadow.Entries)
+		out.KeyValue("Triples", d.Shadow.Triples)
+		out.KeyValue("Auto / Manual / Mixed", fmt.Sprintf("%d / %d / %d",
+			d.Shadow.AutoEntries, d.Shadow.ManualEntries, d.Shadow.MixedEntries))
+	}
+	out.Println("")
+
+	out.Header("Rules")
+	out.KeyValue("Source", d.Rules.Source)
+	out.KeyValue("Passed", fmt.Sprintf("%d/%d", d.Rules.Passed, d.Rules.Total))
+	out.KeyValue("Violations", fmt.Sprintf("%d errors, %d warnings, %d info",
+		d.Rules.ErrorCount, d.Rules.WarningCount, d.Rules.InfoCount))
+	if len(d.Rules.FailedRules) > 0 {
+		out.BulletList(d.Rules.FailedRules)
+	}
+	out.Println("")
+
+	out.Header("Documentation Coverage")
+	out.KeyValue("LinkedDoc", fmt.Sprintf("%.1f%% (%d/%d files)",
+		d.Docs.CoveragePercent, d.Docs.DocumentedFiles, d.Docs.SourceFiles))
+	if d.Docs.GeneratedFiles > 0 {
+		out.KeyValue("Generated (excluded)", d.Docs.GeneratedFiles)
+	}
+	out.KeyValue("Without description", d.Docs.UndescribedModules)
+	out.Println("")
+
+	out.Header(fmt.Sprintf("Cycles (%d)", len(d.Cycles)))
+	if len(d.Cycles) == 0 {
+		out.Success("No dependency cycles found")
+	} else {
+		var items []string
+		for _, cycle := range d.Cycles {
+			items = append(items, strings.Join(cycle, " → "))
+		}
+		out.BulletList(items)
+	}
+	out.Println("")
+
+	out.Header("Top Hotspots")
+	if len(d.Hotspots) == 0 {
+		out.Info("No coupled modules found")
+		return
+	}
+	var rows [][]string
+	for _, h := range d.Hotspots {
+		rows = append(rows, []string{h.Path, h.Layer,
+			fmt.Sprintf("%d", h.Dependents), fmt.Sprintf("%d", h.Dependencies)})
+	}
+	out.Table([]string{"Module", "Layer", "Dependents", "Dependencies"}, rows)
+}
+
+// printLanguages renders the language breakdown
+func printLanguages(out *cli.OutputFormatter, l *graph.LanguageBreakdown) {
+	out.Header("Languages")
+	var rows [][]string
+	for _, s := range l.Languages {
+		rows = append(rows, []string{s.Language, fmt.Sprintf("%d", s.Modules), fmt.Sprintf("%.1f%%", s.Percent),
+			fmt.Sprintf("%d", s.CrossOut), fmt.Sprintf("%d", s.CrossIn)})
+	}
+	out.Table([]string{"Language", "Modules", "Share", "Cross Out", "Cross In"}, rows)
+
+	if l.IsPolyglot() {
+		out.Println("")
+		out.KeyValue("Cross-language dependencies", len(l.Edges))
+		out.KeyValue("Boundary modules", len(l.Boundaries))
+		if verbose {
+			var items []string
+			for _, boundary := range l.Boundaries {
+				items = append(items, fmt.Sprintf("%s (%s → %s)", boundary.Path, boundary.Language, strings.Join(boundary.Calls, ", ")))
+			}
+			out.BulletList(items)
+		}
+	}
+	out.Println("")
+}
+
+// loadProjectRules loads rules from an explicit file or the project default.
+// It returns no rules when neither exists, so callers fall back to built-in
+// rules.
+func loadProjectRules(absPath, rulesFile string) ([]*rules.Rule, string, error) {
+	rulesSource := rulesFile
+	if rulesFile == "" {
+		if _, err := os.Stat(filepath.Join(absPath, defaultRulesFile)); err != nil {
+			return nil, "", nil
+		}
+		rulesFile, rulesSource = filepath.Join(absPath, defaultRulesFile), defaultRulesFile
+	}
+
+	ruleSet, err := rules.ParseRules(rulesFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return ruleSet.Rules, rulesSource, nil
+}
@@ -0,0 +1,284 @@
+/*
+# Module: cmd/graphfs/cmd_tags.go
+Tags command implementation.
+
+Manages the project tag taxonomy across shadow entries: listing usage,
+renaming, merging and deprecating tags, and proposing LinkedDoc source edits.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/shadow](../../pkg/shadow/tags.go) - Tag taxonomy management
+
+## Tags
+cli, command, tags, taxonomy
+
+## Exports
+tagsCmd
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cmd_tags.go> a code:Module ;
+
+	code:name "cmd/graphfs/cmd_tags.go" ;
+	code:description "Tags command implementation" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/shadow/tags.go> ;
+	code:exports <#tagsCmd> ;
+	code:tags "cli", "command", "tags", "taxonomy" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagsOutput        string
+	tagsProposeSource bool
+	tagsReplacement   string
+	tagsReason        string
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Manage the tag taxonomy across shadow entries",
+	Long: `Manage the tag taxonomy across shadow entries.
+
+Tag sprawl (auth vs authentication vs authn) makes queries and docs harder
+to use. These commands clean tags up centrally in the shadow file system
+and can propose the matching edits to LinkedDoc headers in source files.
+
+Subcommands:
+  list       List tags with usage counts
+  rename     Rename a tag across all shadow entries
+  merge      Merge several tags into one
+  deprecate  Mark a tag as deprecated (optionally with a replacement)
+
+Examples:
+  graphfs tags list
+  graphfs tags rename authn authentication
+  graphfs tags merge auth authn --into authentication --propose-source
+  graphfs tags deprecate legacy --replacement deprecated-api`,
+}
+
+var tagsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tags with usage counts",
+	Args:  cobra.NoArgs,
+	RunE:  runTagsList,
+}
+
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag across all shadow entries",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runTagsRename,
+}
+
+var tagsMergeInto string
+
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge <tag>... --into <target>",
+	Short: "Merge several tags into one",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runTagsMerge,
+}
+
+var tagsDeprecateCmd = &cobra.Command{
+	Use:   "deprecate <tag>",
+	Short: "Mark a tag as deprecated",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTagsDeprecate,
+}
+
+func init() {
+	tagsCmd.AddCommand(tagsListCmd)
+	tagsCmd.AddCommand(tagsRenameCmd)
+	tagsCmd.AddCommand(tagsMergeCmd)
+	tagsCmd.AddCommand(tagsDeprecateCmd)
+
+	tagsListCmd.Flags().StringVarP(&tagsOutput, "output", "o", "table", "Output format (table, json)")
+
+	tagsRenameCmd.Flags().BoolVar(&tagsProposeSource, "propose-source", false, "Print proposed LinkedDoc edits for affected source files")
+
+	tagsMergeCmd.Flags().StringVar(&tagsMergeInto, "into", "", "Target tag (required)")
+	tagsMergeCmd.Flags().BoolVar(&tagsProposeSource, "propose-source", false, "Print proposed LinkedDoc edits for affected source files")
+	_ = tagsMergeCmd.MarkFlagRequired("into")
+
+	tagsDeprecateCmd.Flags().StringVar(&tagsReplacement, "replacement", "", "Tag to use instead")
+	tagsDeprecateCmd.Flags().StringVar(&tagsReason, "reason", "", "Reason for deprecation")
+
+	rootCmd.AddCommand(tagsCmd)
+}
+
+// openProjectShadowFS opens the shadow file system of the current directory
+func openProjectShadowFS() (*shadow.ShadowFS, error) {
+	absPath, err := filepath.Abs(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(absPath, shadow.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+
+	if err := shadowFS.Initialize(); err != nil {
+		return nil, fmt.Errorf("failed to initialize shadow file system: %w", err)
+	}
+
+	return shadowFS, nil
+}
+
+func runTagsList(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	shadowFS, err := openProjectShadowFS()
+	if err != nil {
+		return err
+	}
+
+	usage, err := shadowFS.TagUsage()
+	if err != nil {
+		return fmt.Errorf("failed to collect tag usage: %w", err)
+	}
+
+	if tagsOutput == "json" {
+		data, err := json.MarshalIndent(usage, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(usage) == 0 {
+		out.Info("No tags found. Run 'graphfs shadow build' first.")
+		return nil
+	}
+
+	out.Header(fmt.Sprintf("Tags (%d)", len(usage)))
+	out.Println("")
+
+	headers := []string{"Tag", "Count", "Status"}
+	var rows [][]string
+	for _, u := range usage {
+		status := ""
+		if u.Deprecated {
+			status = "deprecated"
+			if u.Replacement != "" {
+				status += " → " + u.Replacement
+			}
+		}
+		rows = append(rows, []string{u.Tag, fmt.Sprintf("%d", u.Count), status})
+	}
+	out.Table(headers, rows)
+
+	return nil
+}
+
+func runTagsRename(cmd *cobra.Command, args []string) error {
+	return changeTags([]string{args[0]}, args[1])
+}
+
+func runTagsMerge(cmd *cobra.Command, args []string) error {
+	return changeTags(args, tagsMergeInto)
+}
+
+// changeTags renames or merges tags and optionally proposes source edits
+func changeTags(sources []string, target string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	shadowFS, err := openProjectShadowFS()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockWorkspace(shadowFS.RootPath(), out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	result, err := shadowFS.MergeTags(sources, target)
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	out.Success("Replaced %s with '%s' in %d shadow entries",
+		strings.Join(quoteAll(sources), ", "), target, len(result.UpdatedEntries))
+	if verbose {
+		out.BulletList(result.UpdatedEntries)
+	}
+
+	if !tagsProposeSource || len(result.UpdatedEntries) == 0 {
+		return nil
+	}
+
+	edits, err := shadow.ProposeTagSourceEdits(shadowFS.RootPath(), result.UpdatedEntries, sources, target)
+	if err != nil {
+		return fmt.Errorf("failed to propose source edits: %w", err)
+	}
+
+	if len(edits) == 0 {
+		out.Info("No LinkedDoc source edits needed")
+		return nil
+	}
+
+	out.Header(fmt.Sprintf("Proposed LinkedDoc edits (%d)", len(edits)))
+	for _, edit := range edits {
+		fmt.Printf("%s:%d\n", edit.Path, edit.Line)
+		fmt.Printf("- %s\n", edit.Before)
+		fmt.Printf("+ %s\n", edit.After)
+	}
+
+	return nil
+}
+
+func runTagsDeprecate(cmd *cobra.Command, args []string) error {
+	out := cli.NewOutputFormatter(quiet, verbose, noColor)
+
+	shadowFS, err := openProjectShadowFS()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockWorkspace(shadowFS.RootPath(), out)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := shadowFS.DeprecateTag(args[0], tagsReplacement, tagsReason); err != nil {
+		return fmt.Errorf("failed to deprecate tag: %w", err)
+	}
+
+	if tagsReplacement != "" {
+		out.Success("Deprecated tag '%s' (use '%s' instead)", args[0], tagsReplacement)
+	} else {
+		out.Success("Deprecated tag '%s'", args[0])
+	}
+	return nil
+}
+
+// quoteAll wraps each string in single quotes
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + v + "'"
+	}
+	return quoted
+}
@@ -153,8 +153,12 @@ func TestScanCommandWithMinimalApp(t *testing.T) {
 		t.Skip("examples/minimal-app not found")
 	}
 
-	// Create temporary directory for output
+	// Scan a copy so the cache is not written into the example
 	tmpDir := t.TempDir()
+	appPath := filepath.Join(tmpDir, "minimal-app")
+	if err := os.CopyFS(appPath, os.DirFS(minimalAppPath)); err != nil {
+		t.Fatalf("failed to copy minimal-app: %v", err)
+	}
 	outputFile := filepath.Join(tmpDir, "graph.json")
 
 	// Set output flag
@@ -163,7 +167,7 @@ func TestScanCommandWithMinimalApp(t *testing.T) {
 	scanStats = true
 
 	// Run scan command
-	err := runScan(scanCmd, []string{minimalAppPath})
+	err := runScan(scanCmd, []string{appPath})
 	if err != nil {
 		t.Fatalf("scan command failed: %v", err)
 	}
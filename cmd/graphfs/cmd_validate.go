@@ -14,6 +14,14 @@ var (
 	validateRulesFile string
 	validateFormat    string
 	validateSeverity  string
+	validateEffective bool
+
+	validateSnapshot     string
+	validateSaveSnapshot string
+	validateChanged      []string
+
+	validateBaseline       string
+	validateUpdateBaseline bool
 )
 
 var validateCmd = &cobra.Command{
@@ -23,6 +31,16 @@ var validateCmd = &cobra.Command{
 
 Executes SPARQL-based rules to enforce architectural constraints and design principles.
 
+Layering policy needs no SPARQL: a rule with 'forbid' pairs
+({from-layer: data, to-layer: cli}, optionally transitive) or an
+'allow-only' matrix of the layers each layer may depend on is checked by
+traversing module dependencies.
+
+The rules file may also be a SHACL shapes file (.ttl or .shacl). Each shape
+with targets becomes a rule, and results are reported with the shape's
+severity (sh:Violation as error, sh:Warning as warning, sh:Info as info).
+Constraint components graphfs cannot evaluate are listed and ignored.
+
 Examples:
   # Validate with rules file
   graphfs validate --rules .graphfs-rules.yml
@@ -33,17 +51,41 @@ Examples:
   # Output as JUnit XML for CI/CD
   graphfs validate --rules .graphfs-rules.yml --format junit > results.xml
 
+  # Emit machine-applicable fixes for editor plugins and bots
+  # (see docs/QUICK_FIX_PROTOCOL.md; rules opt in with a 'fix' entry)
+  graphfs validate --rules .graphfs-rules.yml --format fixes
+
+  # Validate against SHACL shapes
+  graphfs validate --rules shapes/architecture.ttl
+
   # Only check error-level rules
-  graphfs validate --rules .graphfs-rules.yml --severity error`,
+  graphfs validate --rules .graphfs-rules.yml --severity error
+
+  # Validate against inherited layers/tags (see 'graphfs effective')
+  graphfs validate --rules .graphfs-rules.yml --effective
+
+  # Ratchet mode: record today's violations, then fail only on new ones
+  graphfs validate --rules .graphfs-rules.yml --baseline .graphfs/baseline.json --update-baseline
+  graphfs validate --rules .graphfs-rules.yml --baseline .graphfs/baseline.json
+
+  # Incremental CI build: restore a snapshot saved on the base branch with
+  # 'graphfs scan --save-snapshot' and re-parse only files changed since
+  graphfs validate --rules .graphfs-rules.yml --snapshot .graphfs/ci-snapshot.json`,
 	RunE: runValidate,
 }
 
 func init() {
 	rootCmd.AddCommand(validateCmd)
 
-	validateCmd.Flags().StringVarP(&validateRulesFile, "rules", "r", "", "Path to rules file (YAML)")
-	validateCmd.Flags().StringVarP(&validateFormat, "format", "f", "text", "Output format (text, json, junit)")
+	validateCmd.Flags().StringVarP(&validateRulesFile, "rules", "r", "", "Path to rules file (YAML, or SHACL shapes in Turtle)")
+	validateCmd.Flags().StringVarP(&validateFormat, "format", "f", "text", "Output format (text, json, junit, fixes)")
 	validateCmd.Flags().StringVarP(&validateSeverity, "severity", "s", "info", "Minimum severity level (info, warning, error)")
+	validateCmd.Flags().BoolVar(&validateEffective, "effective", false, "Validate against effective (inherited) metadata")
+	validateCmd.Flags().StringVar(&validateSnapshot, "snapshot", "", "Restore unchanged modules from a build snapshot and parse only changed files")
+	validateCmd.Flags().StringVar(&validateSaveSnapshot, "save-snapshot", "", "Save the merged build snapshot to file")
+	validateCmd.Flags().StringSliceVar(&validateChanged, "changed", nil, "Files changed since the snapshot (default: git diff against the snapshot commit)")
+	validateCmd.Flags().StringVar(&validateBaseline, "baseline", "", "Report only violations not recorded in this baseline file")
+	validateCmd.Flags().BoolVar(&validateUpdateBaseline, "update-baseline", false, "Record the current violations in the baseline file (default "+rules.DefaultBaselineFile+")")
 	validateCmd.MarkFlagRequired("rules")
 }
 
@@ -58,8 +100,18 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		ScanOptions: scanner.ScanOptions{
 			UseDefaults: true,
 		},
-		Validate:       false,
-		ReportProgress: false,
+		Validate:        false,
+		ReportProgress:  false,
+		RecordSnapshot:  validateSaveSnapshot != "",
+		SnapshotChanges: validateChanged,
+	}
+
+	if validateSnapshot != "" {
+		snapshot, err := graph.LoadSnapshot(validateSnapshot)
+		if err != nil {
+			return err
+		}
+		buildOpts.Snapshot = snapshot
 	}
 
 	g, err := builder.Build(targetPath, buildOpts)
@@ -67,6 +119,36 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to build graph: %w", err)
 	}
 
+	if validateSnapshot != "" {
+		fmt.Fprintf(os.Stderr, "Restored %d modules from snapshot, parsed %d changed\n",
+			g.Statistics.SnapshotRestored, len(g.Modules)-g.Statistics.SnapshotRestored)
+	}
+	if validateSaveSnapshot != "" {
+		if err := builder.Snapshot().Save(validateSaveSnapshot); err != nil {
+			return err
+		}
+	}
+
+	if validateEffective {
+		if err := applyEffectiveMetadata(g, nil); err != nil {
+			return err
+		}
+	}
+
+	if config, ok := projectCriticality(targetPath); ok {
+		if _, err := applyCriticality(g, config, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := applyComponents(g, targetPath, nil); err != nil {
+		return err
+	}
+
+	if err := applyBenchmarks(g, targetPath, nil); err != nil {
+		return err
+	}
+
 	fmt.Fprintf(os.Stderr, "Loaded %d modules\n\n", len(g.Modules))
 
 	// Parse severity level
@@ -85,11 +167,20 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	// Create engine and validate
 	engine := rules.NewEngine(g)
 
+	preprocessor, err := loadQueryPreprocessor(targetPath)
+	if err != nil {
+		return err
+	}
+	engine.SetPreprocessor(preprocessor)
+
 	// Parse rules file
 	ruleSet, err := rules.ParseRules(validateRulesFile)
 	if err != nil {
 		return fmt.Errorf("failed to parse rules: %w", err)
 	}
+	for _, warning := range ruleSet.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
 
 	// Validate with filters
 	result, err := engine.ValidateWithFilter(ruleSet.Rules, nil, minSeverity)
@@ -97,6 +188,10 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	if err := applyBaseline(result); err != nil {
+		return err
+	}
+
 	// Report results
 	var format rules.OutputFormat
 	switch validateFormat {
@@ -104,6 +199,8 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		format = rules.FormatJSON
 	case "junit":
 		format = rules.FormatJUnit
+	case "fixes":
+		format = rules.FormatFixes
 	default:
 		format = rules.FormatText
 	}
@@ -119,3 +216,32 @@ func runValidate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// applyBaseline records the violations in the baseline file with
+// --update-baseline, and removes those the baseline accepts from the result
+func applyBaseline(result *rules.ValidationResult) error {
+	baselineFile := validateBaseline
+	if baselineFile == "" {
+		if !validateUpdateBaseline {
+			return nil
+		}
+		baselineFile = rules.DefaultBaselineFile
+	}
+
+	if validateUpdateBaseline {
+		baseline := rules.NewBaseline(result)
+		if err := baseline.Save(baselineFile); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Recorded %d violation(s) in %s\n", len(result.Violations), baselineFile)
+		baseline.Apply(result)
+		return nil
+	}
+
+	baseline, err := rules.LoadBaseline(baselineFile)
+	if err != nil {
+		return fmt.Errorf("%w (create it with --update-baseline)", err)
+	}
+	baseline.Apply(result)
+	return nil
+}
@@ -3,13 +3,16 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/filter"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/scanner"
 	"github.com/justin4957/graphfs/pkg/viz"
+	"github.com/justin4957/graphfs/pkg/viz/c4"
 	"github.com/spf13/cobra"
 )
 
@@ -24,8 +27,27 @@ var (
 	vizRankdir    string
 	vizLayers     []string
 	vizTags       []string
+	vizWhere      string
+	vizRelations  []string
+	vizMinConf    float64
 	vizTarget     string
 	vizModule     string
+
+	vizSample          string
+	vizSampleThreshold int
+	vizMaxNodes        int
+	vizEntryPoints     []string
+	vizEgoDepth        int
+	vizSizeBy          string
+	vizRenderer        string
+	vizDiagram         string
+	vizGroupBy         string
+	vizC4Level         string
+	vizC4Container     string
+	vizC4System        string
+	vizC4Map           map[string]string
+	vizCollapse        string
+	vizCollapseDepth   int
 )
 
 var vizCmd = &cobra.Command{
@@ -39,20 +61,64 @@ Visualization Types:
   • impact     - Impact analysis (requires --module)
   • security   - Security zone boundaries
   • layer      - Layer-based grouping
+  • component  - Components from .graphfs/components.yaml and their edges
 
 Output Formats:
   • dot     - DOT source file (default)
-  • svg     - SVG vector graphics (requires graphviz)
-  • png     - PNG raster graphics (requires graphviz)
+  • svg     - SVG vector graphics
+  • png     - PNG raster graphics
   • pdf     - PDF document (requires graphviz)
   • mermaid - Mermaid diagram syntax (.mmd)
   • md      - Mermaid embedded in Markdown
+  • plantuml - PlantUML component or package diagram (.puml, dependency type only)
+  • d2      - D2 diagram (.d2, dependency type only)
+  • structurizr - C4 model as a Structurizr DSL workspace (.dsl)
+  • c4plantuml  - C4 view in C4-PlantUML (--c4-level)
+  • graphml - GraphML for yEd (.graphml, dependency type only)
+  • gexf    - GEXF for Gephi (.gexf, dependency type only)
+
+  PlantUML component diagrams group modules by --group-by (layer, package
+  or none); --diagram package draws one package per directory with the
+  dependencies between packages. Edges carry their relationship as a
+  stereotype such as <<linksTo>> or <<imports>>.
+
+  D2 diagrams nest modules in a container per directory, or group them by
+  layer (--group-by), and style them by language with D2 classes.
+
+  C4 output treats the codebase as one software system with a container
+  per layer and a component per module. A "container:<name>" tag or
+  --c4-map layer=name overrides a module's container; modules tagged
+  "external" become external systems. Structurizr workspaces hold the
+  context, container and component views; c4plantuml writes one view
+  (--c4-level context, container or component, with --c4-container to
+  draw the components of one container).
+
+  GraphML and GEXF nodes carry layer, language and tags attributes, and
+  edges their relation, call-site weight and whether they were inferred.
+
+  SVG and PNG are rendered with GraphViz when it is installed. Without it,
+  dependency graphs are drawn by a built-in layered layout (--renderer
+  builtin forces it); other types and PDF still need GraphViz and fall
+  back to DOT.
 
 Color Schemes:
   • language - Color by programming language
   • layer    - Color by architectural layer
   • default  - Default color scheme
 
+Sampling (large graphs):
+  Graphs above --sample-threshold modules (default 5000) can be reduced
+  so the output stays renderable. A warning banner lists what was omitted.
+  • top-n - Keep the --max-nodes most connected modules
+  • ego   - Keep modules within --ego-depth hops of --entry modules
+  • layer - Collapse modules into one node per layer
+
+Collapsing (large graphs):
+  --collapse dirs draws one node per directory, keeping --depth directory
+  levels (deeper directories merge into their ancestor). Dependencies
+  between directories are bundled into one edge labelled with how many
+  module dependencies it stands for. Filters apply to modules first.
+
 Layout Engines:
   • dot    - Hierarchical layout (default)
   • neato  - Spring model layout
@@ -64,23 +130,62 @@ Examples:
   # Generate dependency graph as SVG
   graphfs viz --type dependency --output deps.svg
 
+  # Render without GraphViz
+  graphfs viz --renderer builtin --color-by layer --output deps.png
+
   # Layer-based visualization with custom layout
   graphfs viz --type layer --layout neato --output layers.png
 
   # Filtered dependency graph
   graphfs viz --type dependency --layer service --output services.svg
+  graphfs viz --where 'layer=api && tag!=deprecated' --output api.svg
+  graphfs viz --relation imports,extends --min-confidence 0.9 --output declared.svg
 
   # Security zones visualization
   graphfs viz --type security --output security.pdf
 
+  # Security zones in Mermaid (zone subgraphs, violations and legend)
+  graphfs viz --type security --format md --output SECURITY.md
+
   # With title and labels
   graphfs viz --type dependency --title "My Project" --labels --output graph.svg
 
   # Generate Mermaid diagram
   graphfs viz --format mermaid --type dependency --output deps.mmd
 
+  # Sample a very large codebase
+  graphfs viz --sample top-n --max-nodes 300 --output deps.svg
+  graphfs viz --sample ego --entry cmd/server/main.go --output server.svg
+
+  # One node per directory, two levels deep
+  graphfs viz --collapse dirs --depth 2 --output dirs.svg
+  graphfs viz --collapse dirs --format mermaid --output dirs.mmd
+
+  # Size nodes by module criticality (see 'graphfs criticality')
+  graphfs viz --size-by criticality --output critical.svg
+
+  # Component graph, with boundary violations in red
+  graphfs viz --type component --output components.svg
+
   # Mermaid embedded in Markdown
-  graphfs viz --format md --type dependency --title "Architecture" --output README.md`,
+  graphfs viz --format md --type dependency --title "Architecture" --output README.md
+
+  # PlantUML for documentation pipelines
+  graphfs viz --color-by layer --output deps.puml
+  graphfs viz --diagram package --output packages.puml
+
+  # D2 for docs sites
+  graphfs viz --output deps.d2
+  graphfs viz --group-by layer --color-by layer --output layers.d2
+
+  # C4 model views from layers
+  graphfs viz --format structurizr --c4-system "Shop" --output workspace.dsl
+  graphfs viz --format c4plantuml --c4-level context --output context.puml
+  graphfs viz --format c4plantuml --c4-level component --c4-container service --output service.puml
+
+  # Explore large graphs in Gephi or yEd
+  graphfs viz --color-by layer --output deps.gexf
+  graphfs viz --output deps.graphml`,
 	RunE: runViz,
 }
 
@@ -88,15 +193,15 @@ func init() {
 	rootCmd.AddCommand(vizCmd)
 
 	vizCmd.Flags().StringVarP(&vizType, "type", "t", "dependency",
-		"Visualization type (dependency, impact, security, layer)")
+		"Visualization type (dependency, impact, security, layer, component)")
 	vizCmd.Flags().StringVarP(&vizOutput, "output", "o", "graph.dot",
 		"Output file path")
 	vizCmd.Flags().StringVarP(&vizLayout, "layout", "l", "dot",
 		"GraphViz layout engine (dot, neato, fdp, circo, twopi)")
 	vizCmd.Flags().StringVarP(&vizColorBy, "color-by", "c", "default",
-		"Color scheme (language, layer, default)")
+		"Color scheme (language, layer, security, default)")
 	vizCmd.Flags().StringVarP(&vizFormat, "format", "f", "",
-		"Output format (dot, svg, png, pdf, mermaid, md) - auto-detected from extension")
+		"Output format (dot, svg, png, pdf, mermaid, md, plantuml, d2, structurizr, c4plantuml, graphml, gexf) - auto-detected from extension")
 	vizCmd.Flags().StringVar(&vizTitle, "title", "",
 		"Graph title")
 	vizCmd.Flags().BoolVar(&vizShowLabels, "labels", false,
@@ -107,10 +212,46 @@ func init() {
 		"Filter by layer(s)")
 	vizCmd.Flags().StringSliceVar(&vizTags, "tag", []string{},
 		"Filter by tag(s)")
+	vizCmd.Flags().StringVar(&vizWhere, "where", "",
+		"Filter modules by expression (e.g. 'layer=api && tag!=deprecated')")
+	vizCmd.Flags().StringSliceVar(&vizRelations, "relation", []string{},
+		"Keep only dependencies with these relation(s) (linksTo, imports, extends, implements, uses)")
+	vizCmd.Flags().Float64Var(&vizMinConf, "min-confidence", 0,
+		"Keep only dependencies with at least this confidence (0-1; inferred edges are 0.6, or 0.9 when referenced)")
 	vizCmd.Flags().StringVarP(&vizTarget, "target", "d", ".",
 		"Target directory to analyze")
 	vizCmd.Flags().StringVarP(&vizModule, "module", "m", "",
 		"Module for impact visualization")
+	vizCmd.Flags().StringVar(&vizSample, "sample", "",
+		"Sampling strategy for large graphs (top-n, ego, layer)")
+	vizCmd.Flags().IntVar(&vizSampleThreshold, "sample-threshold", viz.DefaultSampleThreshold,
+		"Apply sampling only above this many modules")
+	vizCmd.Flags().IntVar(&vizMaxNodes, "max-nodes", viz.DefaultSampleMaxNodes,
+		"Maximum modules kept by sampling")
+	vizCmd.Flags().StringSliceVar(&vizEntryPoints, "entry", []string{},
+		"Entry point module(s) for ego sampling")
+	vizCmd.Flags().IntVar(&vizEgoDepth, "ego-depth", 2,
+		"Ego network radius for ego sampling")
+	vizCmd.Flags().StringVar(&vizCollapse, "collapse", "",
+		"Collapse modules into super-nodes (dirs) - dependency type only")
+	vizCmd.Flags().IntVar(&vizCollapseDepth, "depth", viz.DefaultCollapseDepth,
+		"Directory levels kept by --collapse dirs")
+	vizCmd.Flags().StringVar(&vizSizeBy, "size-by", "",
+		"Size nodes by a module score (criticality) - DOT output only")
+	vizCmd.Flags().StringVar(&vizRenderer, "renderer", "auto",
+		"SVG/PNG renderer (auto, graphviz, builtin)")
+	vizCmd.Flags().StringVar(&vizDiagram, "diagram", "component",
+		"PlantUML diagram (component, package)")
+	vizCmd.Flags().StringVar(&vizGroupBy, "group-by", "",
+		"Group PlantUML and D2 modules by (layer, package/directory, none) - default layer for PlantUML, directory for D2")
+	vizCmd.Flags().StringVar(&vizC4Level, "c4-level", "container",
+		"C4-PlantUML view (context, container, component)")
+	vizCmd.Flags().StringVar(&vizC4Container, "c4-container", "",
+		"Container of a C4 component view (default: all)")
+	vizCmd.Flags().StringVar(&vizC4System, "c4-system", "",
+		"C4 software system name (default: target directory name)")
+	vizCmd.Flags().StringToStringVar(&vizC4Map, "c4-map", map[string]string{},
+		"Map layers to C4 containers (e.g. api=Web API,services=Backend)")
 }
 
 func runViz(cmd *cobra.Command, args []string) error {
@@ -149,8 +290,10 @@ func runViz(cmd *cobra.Command, args []string) error {
 		vizTypeEnum = viz.VizSecurity
 	case "layer", "layers":
 		vizTypeEnum = viz.VizLayer
+	case "component", "components":
+		vizTypeEnum = viz.VizComponent
 	default:
-		return fmt.Errorf("invalid visualization type: %s (use: dependency, impact, security, layer)", vizType)
+		return fmt.Errorf("invalid visualization type: %s (use: dependency, impact, security, layer, component)", vizType)
 	}
 
 	// Create visualization options
@@ -164,11 +307,61 @@ func runViz(cmd *cobra.Command, args []string) error {
 		Title:      vizTitle,
 	}
 
+	// Size nodes by criticality if requested
+	switch vizSizeBy {
+	case "":
+	case "criticality":
+		gray.Println("Scoring module criticality...")
+		config, _ := projectCriticality(vizTarget)
+		result, err := applyCriticality(g, config, nil)
+		if err != nil {
+			return err
+		}
+		vizOpts.Criticality = result.Scores()
+	default:
+		return fmt.Errorf("invalid --size-by: %s (use: criticality)", vizSizeBy)
+	}
+
+	// Add sampling if specified
+	var sampling *viz.SamplingOptions
+	if vizSample != "" {
+		sampling = &viz.SamplingOptions{
+			Strategy:    viz.SamplingStrategy(vizSample),
+			Threshold:   vizSampleThreshold,
+			MaxNodes:    vizMaxNodes,
+			EntryPoints: vizEntryPoints,
+			EgoDepth:    vizEgoDepth,
+		}
+		vizOpts.Sampling = sampling
+	} else if len(g.Modules) > vizSampleThreshold {
+		gray.Printf("Warning: %d modules may not render; consider --sample top-n, ego or layer\n\n", len(g.Modules))
+	}
+
+	// Collapse modules into directories if requested
+	var collapse *viz.CollapseOptions
+	if vizCollapse != "" {
+		if vizTypeEnum != viz.VizDependency {
+			return fmt.Errorf("--collapse supports the dependency type only, not %s", vizType)
+		}
+		collapse = &viz.CollapseOptions{
+			Mode:  viz.CollapseMode(vizCollapse),
+			Depth: vizCollapseDepth,
+		}
+		vizOpts.Collapse = collapse
+	}
+
 	// Add filter if specified
-	if len(vizLayers) > 0 || len(vizTags) > 0 {
+	where, err := filter.Parse(vizWhere)
+	if err != nil {
+		return err
+	}
+	if len(vizLayers) > 0 || len(vizTags) > 0 || where != nil || len(vizRelations) > 0 || vizMinConf > 0 {
 		vizOpts.Filter = &viz.FilterOptions{
-			Layers: vizLayers,
-			Tags:   vizTags,
+			Layers:        vizLayers,
+			Tags:          vizTags,
+			Where:         where,
+			Relations:     vizRelations,
+			MinConfidence: vizMinConf,
 		}
 	}
 
@@ -190,8 +383,21 @@ func runViz(cmd *cobra.Command, args []string) error {
 			impactResult.TotalImpactedModules)
 	}
 
-	// For security visualization, run security analysis
-	if vizTypeEnum == viz.VizSecurity {
+	// For component visualization, resolve declared components
+	if vizTypeEnum == viz.VizComponent {
+		components, err := loadProjectComponents(g, vizTarget)
+		if err != nil {
+			return err
+		}
+		if components == nil {
+			return fmt.Errorf("component visualization requires %s", analysis.DefaultComponentsFile)
+		}
+		vizOpts.Components = components
+		gray.Printf("Components: %d components, %d edges\n\n", len(components.Components), len(components.Edges))
+	}
+
+	// For security visualization or coloring, run security analysis
+	if vizTypeEnum == viz.VizSecurity || vizColorBy == "security" {
 		gray.Println("Analyzing security boundaries...")
 		secOpts := analysis.SecurityOptions{
 			StrictMode: false,
@@ -214,21 +420,93 @@ func runViz(cmd *cobra.Command, args []string) error {
 	isMermaid := vizFormat == "mermaid" || vizFormat == "md" ||
 		strings.HasSuffix(vizOutput, ".mmd") || strings.HasSuffix(vizOutput, ".md")
 
-	if isMermaid {
+	// GraphML and GEXF need no GraphViz install
+	isExchangeFormat := vizFormat == "graphml" || vizFormat == "gexf" ||
+		(vizFormat == "" && (strings.HasSuffix(vizOutput, ".graphml") || strings.HasSuffix(vizOutput, ".gexf")))
+
+	isPlantUML := vizFormat == "plantuml" || vizFormat == "puml" ||
+		(vizFormat == "" && (strings.HasSuffix(vizOutput, ".puml") || strings.HasSuffix(vizOutput, ".plantuml")))
+
+	isD2 := vizFormat == "d2" || (vizFormat == "" && strings.HasSuffix(vizOutput, ".d2"))
+
+	isStructurizr := vizFormat == "structurizr" || (vizFormat == "" && strings.HasSuffix(vizOutput, ".dsl"))
+	isC4PlantUML := vizFormat == "c4plantuml" || vizFormat == "c4"
+
+	if isStructurizr || isC4PlantUML {
+		if vizTypeEnum != viz.VizDependency {
+			return fmt.Errorf("unsupported visualization type for C4 output: %s (only dependency)", vizType)
+		}
+		system := vizC4System
+		if system == "" {
+			if abs, err := filepath.Abs(vizTarget); err == nil {
+				system = filepath.Base(abs)
+			}
+		}
+		c4Opts := c4.Options{
+			System:     system,
+			Person:     "User",
+			Containers: vizC4Map,
+			Filter:     vizOpts.Filter,
+			Level:      c4.Level(vizC4Level),
+			Container:  vizC4Container,
+			Rankdir:    vizRankdir,
+			Title:      vizTitle,
+		}
+		var content string
+		if isStructurizr {
+			content, err = c4.GenerateStructurizr(g, c4Opts)
+		} else {
+			content, err = c4.GeneratePlantUML(g, c4Opts)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate C4 diagram: %w", err)
+		}
+		if err := os.WriteFile(vizOutput, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	} else if isPlantUML {
+		content, err := viz.GeneratePlantUML(g, viz.PlantUMLOptions{
+			VizOptions: vizOpts,
+			Diagram:    viz.PlantUMLDiagram(vizDiagram),
+			GroupBy:    viz.PlantUMLGroup(strings.Replace(vizGroupBy, "directory", "package", 1)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate PlantUML diagram: %w", err)
+		}
+		if err := os.WriteFile(vizOutput, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	} else if isD2 {
+		content, err := viz.GenerateD2(g, viz.D2Options{
+			VizOptions: vizOpts,
+			GroupBy:    viz.D2Group(strings.Replace(vizGroupBy, "package", "directory", 1)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate D2 diagram: %w", err)
+		}
+		if err := os.WriteFile(vizOutput, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	} else if isMermaid {
 		// Generate Mermaid diagram
 		mermaidOpts := viz.MermaidOptions{
 			Type:      viz.MermaidFlowchart,
 			Direction: vizRankdir,
 			ColorBy:   vizColorBy,
 			Title:     vizTitle,
+			Sampling:  sampling,
+			Collapse:  collapse,
+			Security:  vizOpts.Security,
+		}
+
+		// Security views group nodes into zone subgraphs
+		if vizTypeEnum == viz.VizSecurity {
+			mermaidOpts.ColorBy = "security"
 		}
 
 		// Add filter if specified
-		if len(vizLayers) > 0 || len(vizTags) > 0 {
-			mermaidOpts.Filter = &viz.FilterOptions{
-				Layers: vizLayers,
-				Tags:   vizTags,
-			}
+		if vizOpts.Filter != nil {
+			mermaidOpts.Filter = vizOpts.Filter
 		}
 
 		var mermaid string
@@ -237,6 +515,11 @@ func runViz(cmd *cobra.Command, args []string) error {
 		// Generate based on visualization type
 		if vizTypeEnum == viz.VizImpact && vizOpts.Impact != nil {
 			mermaid, err = viz.GenerateMermaidForImpact(g, vizOpts.Impact, mermaidOpts)
+		} else if vizTypeEnum == viz.VizComponent {
+			mermaid, err = viz.GenerateMermaidForComponents(vizOpts.Components, mermaidOpts)
+			if err == nil && (vizFormat == "md" || strings.HasSuffix(vizOutput, ".md")) {
+				mermaid = "```mermaid\n" + mermaid + "```\n"
+			}
 		} else {
 			// Embed in markdown if .md extension
 			if vizFormat == "md" || strings.HasSuffix(vizOutput, ".md") {
@@ -255,13 +538,27 @@ func runViz(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to write file: %w", err)
 		}
 	} else {
-		// Use GraphViz for other formats
-		// Validate layout if using GraphViz
-		if vizFormat != "" && vizFormat != "dot" {
+		renderer, err := viz.ParseRenderer(vizRenderer)
+		if err != nil {
+			return err
+		}
+
+		// Use GraphViz for other formats, or the built-in renderer for
+		// SVG and PNG dependency graphs when GraphViz is missing
+		format := vizFormat
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(vizOutput)), ".")
+		}
+		builtin := (format == "svg" || format == "png") && vizTypeEnum == viz.VizDependency
+		if format != "" && format != "dot" && !isExchangeFormat && renderer != viz.RendererBuiltin {
 			if err := viz.ValidateLayout(vizLayout); err != nil {
-				gray.Printf("Warning: %v\n", err)
-				gray.Println("Falling back to DOT format")
-				vizFormat = "dot"
+				if builtin && renderer == viz.RendererAuto {
+					gray.Println("GraphViz not found, using the built-in renderer")
+				} else if vizFormat != "" {
+					gray.Printf("Warning: %v\n", err)
+					gray.Println("Falling back to DOT format")
+					vizFormat = "dot"
+				}
 			}
 		}
 
@@ -270,6 +567,7 @@ func runViz(cmd *cobra.Command, args []string) error {
 			VizOptions: vizOpts,
 			Output:     vizOutput,
 			Format:     viz.OutputFormat(vizFormat),
+			Renderer:   renderer,
 		}
 
 		if err := viz.RenderToFile(g, renderOpts); err != nil {
@@ -287,6 +585,31 @@ func runViz(cmd *cobra.Command, args []string) error {
 		fmt.Println("  • Preview in VS Code: Install Mermaid extension")
 		fmt.Println("  • View online: https://mermaid.live/")
 		fmt.Println("  • Embed in docs: Copy into any Markdown file")
+	} else if isStructurizr {
+		cyan.Println("\n💡 Tips:")
+		fmt.Println("  • Preview: docker run -it --rm -p 8080:8080 -v $PWD:/usr/local/structurizr structurizr/lite")
+		fmt.Println("  • Export views: structurizr-cli export -workspace " + vizOutput + " -format plantuml/c4plantuml")
+		fmt.Println("  • Tag modules container:<name> or external to refine the model")
+	} else if isC4PlantUML {
+		cyan.Println("\n💡 Tips:")
+		fmt.Println("  • Render: plantuml -tsvg " + vizOutput)
+		fmt.Println("  • Other views: --c4-level context, container or component")
+		fmt.Println("  • Tag modules container:<name> or external to refine the model")
+	} else if isPlantUML {
+		cyan.Println("\n💡 Tips:")
+		fmt.Println("  • Render: plantuml -tsvg " + vizOutput)
+		fmt.Println("  • Include in docs: !include " + vizOutput)
+		fmt.Println("  • View online: https://www.plantuml.com/plantuml/")
+	} else if isD2 {
+		cyan.Println("\n💡 Tips:")
+		fmt.Println("  • Render: d2 " + vizOutput + " graph.svg")
+		fmt.Println("  • Try another layout: d2 --layout elk " + vizOutput + " graph.svg")
+		fmt.Println("  • View online: https://play.d2lang.com/")
+	} else if isExchangeFormat {
+		cyan.Println("\n💡 Tips:")
+		fmt.Println("  • Gephi: File → Open, then run a layout such as ForceAtlas 2")
+		fmt.Println("  • yEd: File → Open, then Layout → Hierarchical")
+		fmt.Println("  • Partition or color by the layer, language and relation attributes")
 	} else {
 		ext := vizOutput[len(vizOutput)-4:]
 		if ext == ".dot" {
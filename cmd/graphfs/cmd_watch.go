@@ -3,13 +3,18 @@
 Watch command for live file system monitoring.
 
 Implements the 'graphfs watch' command for monitoring file changes and
-automatically re-running queries or regenerating visualizations.
+automatically re-running queries or regenerating visualizations. Saved
+files update the in-memory graph and, when initialized, the shadow file
+system.
 
 ## Linked Modules
 - [../../pkg/watch](../../pkg/watch/watcher.go) - File system watcher
 - [../../pkg/graph](../../pkg/graph/graph.go) - Graph building
 - [../../pkg/query](../../pkg/query/engine.go) - Query engine
+- [../../pkg/watch](../../pkg/watch/subscriptions.go) - Team subscriptions and digests
+- [../../pkg/shadow](../../pkg/shadow/shadow.go) - Shadow file system
 - [root](./root.go) - Root command
+- [lifecycle](./lifecycle.go) - Graceful shutdown
 
 ## Tags
 cli, watch, monitoring
@@ -27,7 +32,7 @@ watchCmd
     code:language "go" ;
     code:layer "cli" ;
     code:linksTo <../../pkg/watch/watcher.go>, <../../pkg/graph/graph.go>,
-                 <../../pkg/query/engine.go>, <./root.go> ;
+                 <../../pkg/query/engine.go>, <../../pkg/watch/subscriptions.go>, <../../pkg/shadow/shadow.go>, <./root.go>, <./lifecycle.go> ;
     code:exports <#watchCmd> ;
     code:tags "cli", "watch", "monitoring" .
 <!-- End LinkedDoc RDF -->
@@ -38,15 +43,16 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/signal"
 	"path/filepath"
-	"syscall"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/justin4957/graphfs/pkg/cli"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/rules"
 	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
 	"github.com/justin4957/graphfs/pkg/viz"
 	"github.com/justin4957/graphfs/pkg/watch"
 	"github.com/spf13/cobra"
@@ -74,6 +80,37 @@ Examples:
   # Watch with custom debounce time
   graphfs watch --debounce 500ms "SELECT * WHERE { ... }"
 
+  # Emit per-team digests from .graphfs/subscriptions.yaml
+  graphfs watch --digests
+
+  # Poll git instead of using file system notifications
+  graphfs watch --backend git --poll-interval 5s --viz --output graph.svg
+
+Change Detection:
+  Saved files are picked up instantly through file system notifications
+  (fsnotify). Where those are unavailable, for example when the inotify
+  watch limit is reached or on network file systems, the watcher falls
+  back to polling git status for uncommitted files. --backend selects
+  auto (default), fsnotify or git.
+
+  Each batch of changes updates the graph incrementally. If the shadow
+  file system is initialized (.graphfs/shadow), the shadow entries of
+  changed files are rebuilt and those of removed files deleted; disable
+  with --shadow=false.
+
+Team Subscriptions (.graphfs/subscriptions.yaml):
+  subscriptions:
+    - team: auth
+      paths: ["services/auth/**"]
+      webhook: https://hooks.example.com/auth
+    - team: platform
+      tags: [shared]
+      report: .graphfs/digests/platform.jsonl
+
+  With --digests, each change rebuilds the graph, validates rules
+  (.graphfs-rules.yml or built-in) and sends each team a digest of new
+  dependents on their modules and new violations in their area.
+
 Exit Codes:
   0 - Watch completed successfully (Ctrl+C)
   1 - Error during setup or execution`,
@@ -87,6 +124,10 @@ var (
 	watchOutput   string
 	watchDebounce time.Duration
 	watchVerbose  bool
+	watchDigests  bool
+	watchBackend  string
+	watchPoll     time.Duration
+	watchShadow   bool
 )
 
 func init() {
@@ -96,6 +137,10 @@ func init() {
 	watchCmd.Flags().StringVarP(&watchOutput, "output", "o", "", "Output file for visualization")
 	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", 300*time.Millisecond, "Debounce duration for batching changes")
 	watchCmd.Flags().BoolVarP(&watchVerbose, "verbose", "v", false, "Enable verbose output")
+	watchCmd.Flags().BoolVar(&watchDigests, "digests", false, "Emit per-team digests from .graphfs/subscriptions.yaml")
+	watchCmd.Flags().StringVar(&watchBackend, "backend", string(watch.BackendAuto), "Change detection: auto, fsnotify or git")
+	watchCmd.Flags().DurationVar(&watchPoll, "poll-interval", watch.DefaultPollInterval, "Interval between git polls")
+	watchCmd.Flags().BoolVar(&watchShadow, "shadow", true, "Update shadow entries of changed files")
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
@@ -121,8 +166,8 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		queryString = args[0]
 	}
 
-	if queryString == "" && !watchViz {
-		return fmt.Errorf("either a query or --viz flag is required")
+	if queryString == "" && !watchViz && !watchDigests {
+		return fmt.Errorf("either a query, --viz or --digests flag is required")
 	}
 
 	// Resolve absolute path
@@ -175,11 +220,35 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// Load subscriptions and take the baseline snapshot if requested
+	var digester *digestRunner
+	if watchDigests {
+		digester, err = newDigestRunner(absPath, builder, opts)
+		if err != nil {
+			return err
+		}
+		green.Printf("✓ Loaded %d team subscription(s)\n", len(digester.subs))
+		fmt.Println()
+	}
+
 	// Setup watcher
 	watchOpts := watch.WatchOptions{
-		Path:     absPath,
-		Debounce: watchDebounce,
-		Verbose:  watchVerbose,
+		Path:         absPath,
+		Debounce:     watchDebounce,
+		Verbose:      watchVerbose,
+		Backend:      watch.Backend(watchBackend),
+		PollInterval: watchPoll,
+		BuildOptions: opts,
+	}
+	if watchShadow {
+		watchOpts.ShadowFS, err = openWatchShadowFS(absPath)
+		if err != nil {
+			return err
+		}
+		if watchOpts.ShadowFS != nil {
+			green.Println("✓ Updating shadow entries on changes")
+			fmt.Println()
+		}
 	}
 
 	watcher, err := watch.NewWatcher(g, watchOpts, func(graph *graph.Graph, changedFiles []string) {
@@ -206,30 +275,54 @@ func runWatch(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		// Emit team digests if requested
+		if digester != nil {
+			if err := digester.run(changedFiles, green, red); err != nil {
+				red.Printf("Digest error: %v\n", err)
+			}
+		}
+
 		fmt.Println()
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create watcher: %w", err)
 	}
 
+	lc := newLifecycle()
 	watcher.Start()
-	defer watcher.Stop()
+	lc.Register("watcher", watcher.Shutdown)
 
-	cyan.Printf("👀 Watching for changes in %s\n", watchPath)
+	cyan.Printf("👀 Watching for changes in %s (%s)\n", watchPath, watcher.Backend())
 	gray.Println("Press Ctrl+C to stop")
 	fmt.Println()
 
-	// Wait for interrupt
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
-
+	// Wait for SIGINT/SIGTERM, then let a batch in progress finish
+	<-lc.Done()
 	fmt.Println()
+	if err := shutdownLifecycle(lc, cli.NewOutputFormatter(quiet, watchVerbose, noColor)); err != nil {
+		return err
+	}
 	green.Println("✓ Watch stopped")
 
 	return nil
 }
 
+// openWatchShadowFS opens the shadow file system of the project with its
+// index loaded, or returns nil if it has not been initialized
+func openWatchShadowFS(root string) (*shadow.ShadowFS, error) {
+	shadowFS, err := shadow.NewShadowFS(root, shadow.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow file system: %w", err)
+	}
+	if _, err := os.Stat(shadowFS.ShadowPath()); os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err := shadowFS.LoadIndex(); err != nil {
+		return nil, fmt.Errorf("failed to load shadow index: %w", err)
+	}
+	return shadowFS, nil
+}
+
 // executeQuery runs a query and displays results
 func executeQuery(executor *query.Executor, queryString string, green, yellow, red *color.Color) error {
 	result, err := executor.ExecuteString(queryString)
@@ -291,3 +384,120 @@ func generateViz(g *graph.Graph, output string, green, yellow *color.Color) erro
 
 	return nil
 }
+
+// digestRunner rebuilds the graph after changes and delivers team digests
+type digestRunner struct {
+	root         string
+	builder      *graph.Builder
+	opts         graph.BuildOptions
+	subs         []watch.Subscription
+	ruleList     []*rules.Rule
+	preprocessor *query.Preprocessor
+	previous     watch.Snapshot
+}
+
+// newDigestRunner loads subscriptions and rules and snapshots the initial
+// graph. The baseline is built separately, as the watched graph is updated in
+// place.
+func newDigestRunner(root string, builder *graph.Builder, opts graph.BuildOptions) (*digestRunner, error) {
+	config, err := watch.LoadSubscriptions(filepath.Join(root, ".graphfs"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	if len(config.Subscriptions) == 0 {
+		return nil, fmt.Errorf("no subscriptions found in .graphfs/%s", watch.SubscriptionsFile)
+	}
+
+	ruleList := rules.GetBuiltInRules()
+	rulesPath := filepath.Join(root, defaultRulesFile)
+	if _, err := os.Stat(rulesPath); err == nil {
+		ruleSet, err := rules.ParseRules(rulesPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rules: %w", err)
+		}
+		ruleList = ruleSet.Rules
+	}
+
+	preprocessor, err := loadQueryPreprocessor(root)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &digestRunner{
+		root:         root,
+		builder:      builder,
+		opts:         opts,
+		subs:         config.Subscriptions,
+		ruleList:     ruleList,
+		preprocessor: preprocessor,
+	}
+
+	g, err := builder.Build(root, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graph: %w", err)
+	}
+	d.previous, err = d.snapshot(g)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// snapshot validates rules against the graph
+func (d *digestRunner) snapshot(g *graph.Graph) (watch.Snapshot, error) {
+	engine := rules.NewEngine(g)
+	engine.SetPreprocessor(d.preprocessor)
+
+	result, err := engine.Validate(d.ruleList)
+	if err != nil {
+		return watch.Snapshot{}, fmt.Errorf("failed to validate rules: %w", err)
+	}
+
+	return watch.Snapshot{Graph: g, Violations: result.Violations}, nil
+}
+
+// run rebuilds the graph, diffs it against the previous snapshot and
+// delivers a digest to each affected team
+func (d *digestRunner) run(changedFiles []string, green, red *color.Color) error {
+	g, err := d.builder.Build(d.root, d.opts)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild graph: %w", err)
+	}
+
+	current, err := d.snapshot(g)
+	if err != nil {
+		return err
+	}
+
+	relFiles := make([]string, 0, len(changedFiles))
+	for _, file := range changedFiles {
+		if rel, err := filepath.Rel(d.root, file); err == nil {
+			relFiles = append(relFiles, rel)
+		} else {
+			relFiles = append(relFiles, file)
+		}
+	}
+
+	digests := watch.BuildDigests(d.subs, d.previous, current, relFiles)
+	d.previous = current
+
+	for _, digest := range digests {
+		for _, sub := range d.subs {
+			if sub.Team != digest.Team {
+				continue
+			}
+			if sub.Report != "" && !filepath.IsAbs(sub.Report) {
+				sub.Report = filepath.Join(d.root, sub.Report)
+			}
+			if err := watch.DeliverDigest(sub, digest); err != nil {
+				red.Printf("✗ %v\n", err)
+				continue
+			}
+			green.Printf("✓ Digest for %s: %d new dependent(s), %d new violation(s)\n",
+				digest.Team, len(digest.NewDependents), len(digest.Violations))
+		}
+	}
+
+	return nil
+}
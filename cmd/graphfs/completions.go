@@ -167,6 +167,20 @@ func outputFormatCompletion(cmd *cobra.Command, args []string, toComplete string
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
+// exportFormatCompletion provides completion for export format flags
+func exportFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	formats := []string{"json", "turtle", "ntriples", "nquads", "cypher", "sqlite"}
+
+	var completions []string
+	for _, format := range formats {
+		if strings.HasPrefix(format, toComplete) {
+			completions = append(completions, format)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
 // queryFormatCompletion provides completion for query output format flags
 func queryFormatCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	formats := []string{"table", "json", "csv"}
@@ -238,7 +252,7 @@ func registerCompletions() error {
 	if err := queryCmd.MarkFlagFilename("file"); err != nil {
 		return fmt.Errorf("failed to mark query file flag: %w", err)
 	}
-	if err := queryCmd.MarkFlagFilename("output"); err != nil {
+	if err := queryCmd.MarkPersistentFlagFilename("output"); err != nil {
 		return fmt.Errorf("failed to mark query output flag: %w", err)
 	}
 
@@ -272,6 +286,24 @@ func registerCompletions() error {
 		return fmt.Errorf("failed to mark docs template flag: %w", err)
 	}
 
+	// Register completion for extract command
+	if err := extractCmd.MarkFlagFilename("out", "ttl"); err != nil {
+		return fmt.Errorf("failed to mark extract out flag: %w", err)
+	}
+
+	// Register completion for export command
+	if err := exportCmd.RegisterFlagCompletionFunc("format", exportFormatCompletion); err != nil {
+		return fmt.Errorf("failed to register export format completion: %w", err)
+	}
+	if err := exportCmd.MarkFlagFilename("output", "json", "ttl", "nt", "nq", "cypher", "cql", "db", "sqlite", "sqlite3"); err != nil {
+		return fmt.Errorf("failed to mark export output flag: %w", err)
+	}
+
+	// Register completion for schedule run command
+	if err := scheduleRunCmd.MarkFlagFilename("digest", "md"); err != nil {
+		return fmt.Errorf("failed to mark schedule run digest flag: %w", err)
+	}
+
 	// Register completion for examples command
 	if err := examplesListCmd.RegisterFlagCompletionFunc("category", categoryCompletion); err != nil {
 		return fmt.Errorf("failed to register examples list category completion: %w", err)
@@ -6,12 +6,17 @@ Manages loading and validation of configuration from files and environment.
 
 ## Linked Modules
 - [root](./root.go) - Root command
+- [../../pkg/pathkey](../../pkg/pathkey/pathkey.go) - Path case folding
+- [../../pkg/analysis](../../pkg/analysis/criticality.go) - Criticality model
+- [../../pkg/shadow](../../pkg/shadow/audit.go) - Audit log switch
+- [../../pkg/parser](../../pkg/parser/comments.go) - LinkedDoc comment styles
+- [../../pkg/semantic](../../pkg/semantic/provider.go) - Embedding providers
 
 ## Tags
 cli, config, viper
 
 ## Exports
-Config, initConfig, loadConfig, saveDefaultConfig
+Config, initConfig, loadConfig, projectBaseIRI, projectCriticality, saveDefaultConfig
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -23,8 +28,8 @@ Config, initConfig, loadConfig, saveDefaultConfig
 	code:description "Configuration handling for GraphFS CLI" ;
 	code:language "go" ;
 	code:layer "cli" ;
-	code:linksTo <./root.go> ;
-	code:exports <#Config>, <#initConfig>, <#loadConfig>, <#saveDefaultConfig> ;
+	code:linksTo <./root.go>, <../../pkg/pathkey/pathkey.go>, <../../pkg/analysis/criticality.go>, <../../pkg/shadow/audit.go>, <../../pkg/parser/comments.go>, <../../pkg/semantic/provider.go> ;
+	code:exports <#Config>, <#initConfig>, <#loadConfig>, <#projectBaseIRI>, <#projectCriticality>, <#saveDefaultConfig> ;
 	code:tags "cli", "config", "viper" .
 
 <!-- End LinkedDoc RDF -->
@@ -37,15 +42,37 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/semantic"
+	"github.com/justin4957/graphfs/pkg/shadow"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents GraphFS configuration
 type Config struct {
-	Version int         `yaml:"version"`
-	Scan    ScanConfig  `yaml:"scan"`
-	Query   QueryConfig `yaml:"query"`
+	Version  int            `yaml:"version"`
+	Scan     ScanConfig     `yaml:"scan"`
+	Query    QueryConfig    `yaml:"query"`
+	Defaults DefaultsConfig `yaml:"defaults,omitempty"`
+	URIs     URIConfig      `yaml:"uris,omitempty"`
+	Paths    PathsConfig    `yaml:"paths,omitempty"`
+	Audit    AuditConfig    `yaml:"audit,omitempty"`
+	Docs     DocsConfig     `yaml:"docs,omitempty"`
+	LintDocs LintDocsConfig `yaml:"lint_docs,omitempty"`
+	Fitness  FitnessConfig  `yaml:"fitness,omitempty"`
+
+	// Comments overrides LinkedDoc markers and comment syntax per language
+	Comments map[string]CommentConfig `yaml:"comments,omitempty"`
+
+	// Criticality configures module criticality scoring (see 'graphfs criticality')
+	Criticality *analysis.CriticalityConfig `yaml:"criticality,omitempty"`
+
+	// Semantic selects the embedding provider of 'graphfs search --semantic'
+	Semantic semantic.Config `yaml:"semantic,omitempty"`
 }
 
 // ScanConfig configures scanning behavior
@@ -61,6 +88,66 @@ type QueryConfig struct {
 	Timeout      time.Duration `yaml:"timeout"`
 }
 
+// DefaultsConfig holds workspace-wide metadata defaults inherited by all modules
+type DefaultsConfig struct {
+	Layer       string            `yaml:"layer,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// URIConfig configures the identifiers used in generated triples
+type URIConfig struct {
+	// Base is an absolute IRI (e.g. https://graph.mycorp.com/repo/) that
+	// fragment-only and relative module URIs are rewritten against
+	Base string `yaml:"base,omitempty"`
+}
+
+// PathsConfig configures how file paths are compared
+type PathsConfig struct {
+	// FoldCase treats paths differing only in case as the same file:
+	// auto (default: on for Windows and macOS), true or false
+	FoldCase string `yaml:"fold_case,omitempty"`
+}
+
+// DocsConfig configures generated documentation
+type DocsConfig struct {
+	// IDStrategy names module doc files and anchors: path (default) or
+	// legacy, which keeps the names of earlier releases for published links
+	IDStrategy string `yaml:"id_strategy,omitempty"`
+}
+
+// LintDocsConfig configures 'graphfs lint-docs'
+type LintDocsConfig struct {
+	// Tags is the tag vocabulary; empty allows any well-formed tag
+	Tags []string `yaml:"tags,omitempty"`
+
+	// DescriptionMin and DescriptionMax bound the length of
+	// code:description (defaults: 10 and 100)
+	DescriptionMin int `yaml:"description_min,omitempty"`
+	DescriptionMax int `yaml:"description_max,omitempty"`
+}
+
+// FitnessConfig configures 'graphfs fitness'
+type FitnessConfig struct {
+	// Layers is the declared layer order, top first; a layer may depend on
+	// itself and the layers after it
+	Layers []string `yaml:"layers,omitempty"`
+
+	// History is the file tracking conformance over time
+	// (default: .graphfs/fitness-history.json)
+	History string `yaml:"history,omitempty"`
+}
+
+// CommentConfig configures LinkedDoc blocks in one language, keyed by
+// language ("go", "python"); unset fields keep the language's defaults
+type CommentConfig struct {
+	parser.CommentStyle `yaml:",inline"`
+
+	// Extensions adds a language graphfs does not know, scanning files
+	// with these extensions (e.g. [".sql"])
+	Extensions []string `yaml:"extensions,omitempty"`
+}
+
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -95,6 +182,45 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil && verbose {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
+
+	// Path case folding applies to shadow, index and cache keys
+	fold, auto, err := pathkey.ParseFoldCase(viper.GetString("paths.fold_case"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	} else if !auto {
+		pathkey.SetDefaultFoldCase(fold)
+	}
+
+	// The shadow index follows --no-lock and --lock-timeout
+	shadow.SetLockDefaults(!noLock, lockTimeout)
+
+	// Shadow writes are audited unless disabled
+	if viper.IsSet("audit.enabled") {
+		shadow.SetAuditEnabled(viper.GetBool("audit.enabled"))
+	}
+
+	// Comment styles apply to the parser, scanner, scaffolding and pushes
+	if viper.IsSet("comments") {
+		config, err := loadConfig(viper.ConfigFileUsed())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Warning:", err)
+			return
+		}
+		registerCommentStyles(config.Comments)
+	}
+}
+
+// registerCommentStyles registers configured comment styles, and the
+// languages of any new extensions
+func registerCommentStyles(comments map[string]CommentConfig) {
+	for language, comment := range comments {
+		if len(comment.Extensions) > 0 {
+			if _, known := scanner.GetLanguage(language); !known {
+				scanner.RegisterLanguage(language, language, comment.Extensions)
+			}
+		}
+		parser.RegisterCommentStyle(language, comment.CommentStyle)
+	}
 }
 
 // loadConfig loads configuration from file or returns default
@@ -116,6 +242,40 @@ func loadConfig(configPath string) (*Config, error) {
 	return &config, nil
 }
 
+// projectBaseIRI returns the base IRI configured for the project at
+// rootPath, or "" if none is configured
+func projectBaseIRI(rootPath string) string {
+	config, err := loadConfig(filepath.Join(rootPath, ".graphfs", "config.yaml"))
+	if err != nil {
+		return ""
+	}
+	return config.URIs.Base
+}
+
+// AuditConfig configures the shadow write audit log
+type AuditConfig struct {
+	// Enabled records shadow writes in .graphfs/audit.log (default: true)
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// Retention drops records older than a duration (e.g. 365d) on
+	// 'graphfs audit prune'
+	Retention string `yaml:"retention,omitempty"`
+
+	// MaxRecords keeps at most this many of the newest records on
+	// 'graphfs audit prune'
+	MaxRecords int `yaml:"max_records,omitempty"`
+}
+
+// projectCriticality returns the project's criticality model, filled in from
+// the default model, and whether the project configures one
+func projectCriticality(rootPath string) (analysis.CriticalityConfig, bool) {
+	config, err := loadConfig(filepath.Join(rootPath, ".graphfs", "config.yaml"))
+	if err != nil || config.Criticality == nil {
+		return analysis.DefaultCriticalityConfig(), false
+	}
+	return config.Criticality.WithDefaults(), true
+}
+
 // saveDefaultConfig saves default configuration to file
 func saveDefaultConfig(configPath string) error {
 	config := DefaultConfig()
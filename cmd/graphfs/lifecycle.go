@@ -0,0 +1,78 @@
+/*
+# Module: cmd/graphfs/lifecycle.go
+Graceful shutdown for long-running commands.
+
+Creates the lifecycle manager that serve, watch, preview and repl run under,
+and shuts it down within the global --shutdown-timeout, printing the
+shutdown status report.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/lifecycle](../../pkg/lifecycle/lifecycle.go) - Lifecycle management
+
+## Tags
+cli, lifecycle, shutdown, signals
+
+## Exports
+newLifecycle, shutdownLifecycle
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#lifecycle.go> a code:Module ;
+
+	code:name "cmd/graphfs/lifecycle.go" ;
+	code:description "Graceful shutdown for long-running commands" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/lifecycle/lifecycle.go> ;
+	code:exports <#newLifecycle>, <#shutdownLifecycle> ;
+	code:tags "cli", "lifecycle", "shutdown", "signals" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/lifecycle"
+)
+
+var shutdownTimeout time.Duration
+
+// newLifecycle returns a lifecycle manager cancelled on SIGINT and SIGTERM
+func newLifecycle() *lifecycle.Manager {
+	return lifecycle.New(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// shutdownLifecycle stops everything the manager owns within
+// --shutdown-timeout and prints the status report. It returns an error if
+// something failed to stop or was still running at the deadline.
+func shutdownLifecycle(m *lifecycle.Manager, out *cli.OutputFormatter) error {
+	report := m.Shutdown(shutdownTimeout)
+
+	if report.Signal != "" {
+		out.Debug("Shutdown requested by %s", report.Signal)
+	}
+	for _, status := range append(append([]lifecycle.Status{}, report.Components...), report.Workers...) {
+		switch status.State {
+		case lifecycle.StateStopped:
+			out.Debug("  %s: stopped (%v)", status.Name, status.Duration.Round(time.Millisecond))
+		case lifecycle.StateFailed:
+			out.Warning("%s failed to stop: %s", status.Name, status.Error)
+		case lifecycle.StateTimeout:
+			out.Warning("%s still running after %v", status.Name, report.Timeout)
+		}
+	}
+	if !report.Clean() {
+		return report.Err()
+	}
+	out.Debug("Shutdown completed in %v", report.Duration.Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+# Module: cmd/graphfs/lock.go
+Workspace locking for commands that write to .graphfs.
+
+Serializes concurrent CLI invocations that modify the shadow file system
+so they cannot corrupt the index. Honors the global --no-lock and
+--lock-timeout flags.
+
+## Linked Modules
+- [root](./root.go) - Root command
+- [../../pkg/shadow](../../pkg/shadow/lock.go) - Workspace lock
+
+## Tags
+cli, locking, concurrency
+
+## Exports
+lockWorkspace
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#lock.go> a code:Module ;
+
+	code:name "cmd/graphfs/lock.go" ;
+	code:description "Workspace locking for commands that write to .graphfs" ;
+	code:language "go" ;
+	code:layer "cli" ;
+	code:linksTo <./root.go>, <../../pkg/shadow/lock.go> ;
+	code:exports <#lockWorkspace> ;
+	code:tags "cli", "locking", "concurrency" .
+
+<!-- End LinkedDoc RDF -->
+*/
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/cli"
+	"github.com/justin4957/graphfs/pkg/shadow"
+)
+
+var (
+	noLock      bool
+	lockTimeout time.Duration
+)
+
+// lockWorkspace takes the workspace lock for rootPath and returns a function
+// that releases it. With --no-lock it does nothing.
+func lockWorkspace(rootPath string, out *cli.OutputFormatter) (func(), error) {
+	if noLock {
+		return func() {}, nil
+	}
+
+	lock, err := shadow.AcquireLock(rootPath, 0)
+	if errors.Is(err, shadow.ErrLocked) && lockTimeout > 0 {
+		out.Info("Waiting for workspace lock (up to %v)...", lockTimeout)
+		lock, err = shadow.AcquireLock(rootPath, lockTimeout)
+	}
+	if err != nil {
+		if errors.Is(err, shadow.ErrLocked) {
+			return nil, fmt.Errorf("%w; retry later, raise --lock-timeout or pass --no-lock", err)
+		}
+		return nil, err
+	}
+
+	return func() {
+		if err := lock.Release(); err != nil {
+			out.Warning("%v", err)
+		}
+	}, nil
+}
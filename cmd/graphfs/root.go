@@ -34,6 +34,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -74,6 +75,9 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "minimal output (for scripting)")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&noLock, "no-lock", false, "don't lock the .graphfs workspace while writing")
+	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", 30*time.Second, "how long to wait for another graphfs process to release the workspace lock")
+	rootCmd.PersistentFlags().DurationVar(&shutdownTimeout, "shutdown-timeout", 10*time.Second, "how long serve, watch, preview and repl wait for workers and servers to stop on shutdown")
 
 	// Add subcommands
 	rootCmd.AddCommand(initCmd)
@@ -0,0 +1,75 @@
+/*
+# Module: internal/archive/tar.go
+Tar extraction for trees read from git.
+
+Writes the regular files of a tar archive, such as the output of git
+archive, below a directory, keeping their permission bits and refusing
+entries whose paths would escape it.
+
+## Tags
+archive, tar, git
+
+## Exports
+ExtractTar
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#tar.go> a code:Module ;
+    code:name "internal/archive/tar.go" ;
+    code:description "Tar extraction for trees read from git" ;
+    code:language "go" ;
+    code:layer "storage" ;
+    code:exports <#ExtractTar> ;
+    code:tags "archive", "tar", "git" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractTar writes the regular files of a tar archive below dir. Other
+// entries, such as symlinks, are skipped.
+func ExtractTar(r io.Reader, dir string) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if rel, err := filepath.Rel(dir, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive path escapes the target directory: %s", header.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode)&0777)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(file, reader)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tarOf(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTar(t *testing.T) {
+	dir := t.TempDir()
+	if err := ExtractTar(tarOf(t, map[string]string{"a/b.go": "package a", "..c.go": "package c"}), dir); err != nil {
+		t.Fatalf("ExtractTar failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "a", "b.go"))
+	if err != nil || string(data) != "package a" {
+		t.Errorf("a/b.go = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..c.go")); err != nil {
+		t.Errorf("Expected a file name starting with .. to be extracted: %v", err)
+	}
+}
+
+func TestExtractTar_Escape(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "out")
+	if err := ExtractTar(tarOf(t, map[string]string{"../escaped.go": "package x"}), dir); err == nil {
+		t.Error("Expected an error for a path escaping the directory")
+	}
+	if _, err := os.Stat(filepath.Join(root, "escaped.go")); !os.IsNotExist(err) {
+		t.Error("Expected nothing written outside the directory")
+	}
+}
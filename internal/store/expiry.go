@@ -0,0 +1,110 @@
+/*
+# Module: internal/store/expiry.go
+Ephemeral triple support for the triple store.
+
+Triples may carry an expiry timestamp (e.g. temporary waivers or incident
+notes). Expired triples stay in the indexes until purged but are hidden
+from Find and Get, so queries and rules ignore them automatically.
+
+## Linked Modules
+- [store](./store.go) - Triple store
+- [triple](./triple.go) - Triple data structure
+
+## Tags
+store, rdf, expiry, ttl
+
+## Exports
+AddWithExpiry, ExpiresAt, PurgeExpired, SetClock
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#expiry.go> a code:Module ;
+    code:name "internal/store/expiry.go" ;
+    code:description "Ephemeral triple support for the triple store" ;
+    code:language "go" ;
+    code:layer "storage" ;
+    code:linksTo <./store.go>, <./triple.go> ;
+    code:exports <#AddWithExpiry>, <#ExpiresAt>, <#PurgeExpired>, <#SetClock> ;
+    code:tags "store", "rdf", "expiry", "ttl" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package store
+
+import "time"
+
+// AddWithExpiry inserts a triple that is ignored after expiresAt.
+// A triple that already exists without an expiry stays permanent.
+func (ts *TripleStore) AddWithExpiry(subject, predicate, object string, expiresAt time.Time) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	triple := Triple{Subject: subject, Predicate: predicate, Object: object}
+	if ts.existsUnsafe(subject, predicate, object) {
+		if _, ephemeral := ts.expiry[triple]; !ephemeral {
+			return nil
+		}
+	}
+
+	ts.addUnsafe(subject, predicate, object)
+	ts.expiry[triple] = expiresAt
+	return nil
+}
+
+// ExpiresAt returns the expiry timestamp of a triple, if it has one
+func (ts *TripleStore) ExpiresAt(subject, predicate, object string) (time.Time, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	expiresAt, ok := ts.expiry[Triple{Subject: subject, Predicate: predicate, Object: object}]
+	return expiresAt, ok
+}
+
+// PurgeExpired removes expired triples from the store and returns how many were removed
+func (ts *TripleStore) PurgeExpired() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var expired []Triple
+	for triple := range ts.expiry {
+		if ts.isExpiredUnsafe(triple) {
+			expired = append(expired, triple)
+		}
+	}
+
+	for _, triple := range expired {
+		ts.deleteTripleUnsafe(triple.Subject, triple.Predicate, triple.Object)
+	}
+
+	return len(expired)
+}
+
+// SetClock overrides the clock used for expiry checks (useful in tests)
+func (ts *TripleStore) SetClock(now func() time.Time) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.now = now
+}
+
+// isExpiredUnsafe reports whether a triple has passed its expiry (no locking)
+func (ts *TripleStore) isExpiredUnsafe(triple Triple) bool {
+	expiresAt, ok := ts.expiry[triple]
+	return ok && !ts.now().Before(expiresAt)
+}
+
+// withoutExpiredUnsafe filters expired triples from results (no locking)
+func (ts *TripleStore) withoutExpiredUnsafe(triples []Triple) []Triple {
+	if len(ts.expiry) == 0 {
+		return triples
+	}
+
+	filtered := triples[:0]
+	for _, triple := range triples {
+		if !ts.isExpiredUnsafe(triple) {
+			filtered = append(filtered, triple)
+		}
+	}
+	return filtered
+}
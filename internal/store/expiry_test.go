@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTripleStore_AddWithExpiry(t *testing.T) {
+	ts := NewTripleStore()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts.SetClock(func() time.Time { return now })
+
+	ts.Add("<#a.go>", "code:layer", "api")
+	ts.AddWithExpiry("<#a.go>", "code:waiver", "no-cycles", now.Add(time.Hour))
+
+	if got := ts.Find("<#a.go>", "", ""); len(got) != 2 {
+		t.Fatalf("Expected 2 triples before expiry, got %d", len(got))
+	}
+	if _, ok := ts.ExpiresAt("<#a.go>", "code:waiver", "no-cycles"); !ok {
+		t.Error("Expected expiry to be recorded")
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	if got := ts.Find("<#a.go>", "", ""); len(got) != 1 {
+		t.Errorf("Expected expired triple to be hidden from Find, got %d triples", len(got))
+	}
+	if got := ts.Find("", "code:waiver", ""); len(got) != 0 {
+		t.Errorf("Expected expired triple to be hidden from predicate lookup, got %d", len(got))
+	}
+	if props := ts.Get("<#a.go>"); len(props["code:waiver"]) != 0 {
+		t.Error("Expected expired triple to be hidden from Get")
+	}
+
+	if ts.Count() != 2 {
+		t.Errorf("Expected expired triple to remain until purged, count = %d", ts.Count())
+	}
+	if purged := ts.PurgeExpired(); purged != 1 {
+		t.Errorf("PurgeExpired = %d, want 1", purged)
+	}
+	if ts.Count() != 1 {
+		t.Errorf("Count after purge = %d, want 1", ts.Count())
+	}
+}
+
+func TestTripleStore_AddMakesEphemeralPermanent(t *testing.T) {
+	ts := NewTripleStore()
+	now := time.Now()
+	ts.SetClock(func() time.Time { return now })
+
+	ts.AddWithExpiry("s", "p", "o", now.Add(-time.Minute))
+	if len(ts.Find("s", "p", "o")) != 0 {
+		t.Fatal("Expected already-expired triple to be hidden")
+	}
+
+	ts.Add("s", "p", "o")
+	if len(ts.Find("s", "p", "o")) != 1 {
+		t.Error("Expected Add to make the triple permanent")
+	}
+
+	// A permanent triple is not downgraded by AddWithExpiry
+	ts.AddWithExpiry("s", "p", "o", now.Add(-time.Minute))
+	if len(ts.Find("s", "p", "o")) != 1 {
+		t.Error("Expected permanent triple to stay visible")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+# Module: internal/store/graphs.go
+Named graph support for the triple store.
+
+Triples may be added to a named graph (e.g. build provenance) so they can be
+exported or replaced as a unit. The default graph is the union of all
+triples: Find and Get see named-graph triples like any other, so queries
+reach them without GRAPH clauses.
+
+## Linked Modules
+- [store](./store.go) - Triple store
+- [triple](./triple.go) - Triple data structure
+
+## Tags
+store, rdf, named-graphs
+
+## Exports
+AddToGraph, GraphOf, GraphTriples, GraphNames, ClearGraph
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#graphs.go> a code:Module ;
+    code:name "internal/store/graphs.go" ;
+    code:description "Named graph support for the triple store" ;
+    code:language "go" ;
+    code:layer "storage" ;
+    code:linksTo <./store.go>, <./triple.go> ;
+    code:exports <#AddToGraph>, <#GraphOf>, <#GraphTriples>, <#GraphNames>, <#ClearGraph> ;
+    code:tags "store", "rdf", "named-graphs" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package store
+
+import "sort"
+
+// AddToGraph inserts a triple into a named graph. A triple belongs to at
+// most one named graph; adding it again moves it.
+func (ts *TripleStore) AddToGraph(graphName, subject, predicate, object string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	triple := Triple{Subject: subject, Predicate: predicate, Object: object}
+	delete(ts.expiry, triple)
+	ts.addUnsafe(subject, predicate, object)
+	ts.graphs[triple] = graphName
+	return nil
+}
+
+// GraphOf returns the named graph of a triple, if it is in one
+func (ts *TripleStore) GraphOf(subject, predicate, object string) (string, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	graphName, ok := ts.graphs[Triple{Subject: subject, Predicate: predicate, Object: object}]
+	return graphName, ok
+}
+
+// GraphTriples returns the triples of a named graph, sorted
+func (ts *TripleStore) GraphTriples(graphName string) []Triple {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var triples []Triple
+	for triple, name := range ts.graphs {
+		if name == graphName {
+			triples = append(triples, triple)
+		}
+	}
+	sort.Slice(triples, func(i, j int) bool {
+		a, b := triples[i], triples[j]
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		if a.Predicate != b.Predicate {
+			return a.Predicate < b.Predicate
+		}
+		return a.Object < b.Object
+	})
+	return triples
+}
+
+// GraphNames returns the names of the named graphs holding triples, sorted
+func (ts *TripleStore) GraphNames() []string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range ts.graphs {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ClearGraph removes the triples of a named graph and returns how many were removed
+func (ts *TripleStore) ClearGraph(graphName string) int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var triples []Triple
+	for triple, name := range ts.graphs {
+		if name == graphName {
+			triples = append(triples, triple)
+		}
+	}
+	for _, triple := range triples {
+		ts.deleteTripleUnsafe(triple.Subject, triple.Predicate, triple.Object)
+	}
+	return len(triples)
+}
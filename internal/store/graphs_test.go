@@ -0,0 +1,41 @@
+package store
+
+import "testing"
+
+func TestTripleStore_NamedGraphs(t *testing.T) {
+	ts := NewTripleStore()
+	ts.Add("<#a.go>", "code:layer", "api")
+	ts.AddToGraph("urn:build", "<urn:b>", "code:commit", "abc123")
+	ts.AddToGraph("urn:build", "<urn:b>", "code:toolVersion", "0.1.0")
+
+	// The default graph is the union
+	if got := ts.Find("<urn:b>", "", ""); len(got) != 2 {
+		t.Errorf("Expected named-graph triples to be visible to Find, got %d", len(got))
+	}
+	if name, ok := ts.GraphOf("<urn:b>", "code:commit", "abc123"); !ok || name != "urn:build" {
+		t.Errorf("GraphOf = %q, %v; want urn:build", name, ok)
+	}
+	if _, ok := ts.GraphOf("<#a.go>", "code:layer", "api"); ok {
+		t.Error("Default-graph triple should not be in a named graph")
+	}
+
+	triples := ts.GraphTriples("urn:build")
+	if len(triples) != 2 || triples[0].Predicate != "code:commit" {
+		t.Errorf("GraphTriples = %v", triples)
+	}
+	if names := ts.GraphNames(); len(names) != 1 || names[0] != "urn:build" {
+		t.Errorf("GraphNames = %v", names)
+	}
+
+	ts.Delete("<urn:b>", "code:toolVersion", "")
+	if got := ts.GraphTriples("urn:build"); len(got) != 1 {
+		t.Errorf("Deleted triple should leave its graph, got %d triples", len(got))
+	}
+
+	if removed := ts.ClearGraph("urn:build"); removed != 1 {
+		t.Errorf("ClearGraph = %d, want 1", removed)
+	}
+	if ts.Count() != 1 || len(ts.GraphNames()) != 0 {
+		t.Errorf("Expected only the default-graph triple to remain, count = %d", ts.Count())
+	}
+}
@@ -0,0 +1,191 @@
+/*
+# Module: internal/store/scan.go
+Ordered prefix scans over the triple store.
+
+Keeps sorted subject and predicate indexes alongside the hash indexes so
+callers can iterate all triples for subjects under a path prefix (e.g.
+"<#pkg/api/") or for a predicate namespace (e.g. the code: schema) without
+scanning the whole store.
+
+## Linked Modules
+- [store](./store.go) - Triple store
+- [triple](./triple.go) - Triple data structure
+
+## Tags
+store, rdf, index, iterator
+
+## Exports
+SubjectsWithPrefix, PredicatesWithPrefix, ScanSubjectPrefix, ScanPredicatePrefix
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#scan.go> a code:Module ;
+    code:name "internal/store/scan.go" ;
+    code:description "Ordered prefix scans over the triple store" ;
+    code:language "go" ;
+    code:layer "storage" ;
+    code:linksTo <./store.go>, <./triple.go> ;
+    code:exports <#SubjectsWithPrefix>, <#PredicatesWithPrefix>, <#ScanSubjectPrefix>, <#ScanPredicatePrefix> ;
+    code:tags "store", "rdf", "index", "iterator" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package store
+
+import (
+	"iter"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SubjectsWithPrefix returns the subjects starting with prefix, in sorted order
+func (ts *TripleStore) SubjectsWithPrefix(prefix string) []string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	subjects, _ := ts.orderedKeysUnsafe()
+	return prefixRange(subjects, prefix)
+}
+
+// PredicatesWithPrefix returns the predicates starting with prefix, in sorted order
+func (ts *TripleStore) PredicatesWithPrefix(prefix string) []string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	_, predicates := ts.orderedKeysUnsafe()
+	return prefixRange(predicates, prefix)
+}
+
+// ScanSubjectPrefix iterates over the triples whose subject starts with
+// prefix, ordered by subject, predicate and object. Expired triples are
+// skipped. The store is not locked while the loop body runs, so it may
+// modify the store; such changes may not be visible to the iteration.
+func (ts *TripleStore) ScanSubjectPrefix(prefix string) iter.Seq[Triple] {
+	return func(yield func(Triple) bool) {
+		for _, subject := range ts.SubjectsWithPrefix(prefix) {
+			for _, triple := range ts.sortedTriples(subject, "") {
+				if !yield(triple) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ScanPredicatePrefix iterates over the triples whose predicate starts with
+// prefix (e.g. "https://schema.codedoc.org/"), ordered by predicate, subject
+// and object. Expired triples are skipped.
+func (ts *TripleStore) ScanPredicatePrefix(prefix string) iter.Seq[Triple] {
+	return func(yield func(Triple) bool) {
+		for _, predicate := range ts.PredicatesWithPrefix(prefix) {
+			for _, triple := range ts.sortedTriples("", predicate) {
+				if !yield(triple) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// sortedTriples returns the live triples for a subject or a predicate in a
+// stable order. It holds the read lock only while collecting.
+func (ts *TripleStore) sortedTriples(subject, predicate string) []Triple {
+	ts.mu.RLock()
+	var triples []Triple
+	if subject != "" {
+		for p, oMap := range ts.spo[subject] {
+			for o := range oMap {
+				triples = append(triples, Triple{Subject: subject, Predicate: p, Object: o})
+			}
+		}
+	} else {
+		for o, sMap := range ts.pos[predicate] {
+			for s := range sMap {
+				triples = append(triples, Triple{Subject: s, Predicate: predicate, Object: o})
+			}
+		}
+	}
+	triples = ts.withoutExpiredUnsafe(triples)
+	ts.mu.RUnlock()
+
+	sort.Slice(triples, func(i, j int) bool {
+		a, b := triples[i], triples[j]
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		if a.Predicate != b.Predicate {
+			return a.Predicate < b.Predicate
+		}
+		return a.Object < b.Object
+	})
+	return triples
+}
+
+// orderedKeysUnsafe returns the sorted subject and predicate indexes,
+// building them on the first scan. Callers hold at least the read lock and
+// must not keep the slices after releasing it; orderMu serializes
+// concurrent first builds.
+func (ts *TripleStore) orderedKeysUnsafe() (subjects, predicates []string) {
+	ts.orderMu.Lock()
+	defer ts.orderMu.Unlock()
+
+	if !ts.orderBuilt {
+		ts.subjectOrder = sortedKeys(ts.spo)
+		ts.predicateOrder = sortedKeys(ts.pos)
+		ts.orderBuilt = true
+	}
+
+	return ts.subjectOrder, ts.predicateOrder
+}
+
+// insertOrderedUnsafe inserts a new key into a sorted key index once it is
+// built (callers hold the write lock)
+func (ts *TripleStore) insertOrderedUnsafe(sorted []string, key string) []string {
+	if !ts.orderBuilt {
+		return sorted
+	}
+	i, found := slices.BinarySearch(sorted, key)
+	if found {
+		return sorted
+	}
+	return slices.Insert(sorted, i, key)
+}
+
+// removeOrderedUnsafe removes a deleted key from a sorted key index once it
+// is built (callers hold the write lock)
+func (ts *TripleStore) removeOrderedUnsafe(sorted []string, key string) []string {
+	if !ts.orderBuilt {
+		return sorted
+	}
+	i, found := slices.BinarySearch(sorted, key)
+	if !found {
+		return sorted
+	}
+	return slices.Delete(sorted, i, i+1)
+}
+
+// sortedKeys returns the keys of an index level in sorted order
+func sortedKeys(index map[string]map[string]map[string]bool) []string {
+	keys := make([]string, 0, len(index))
+	for k := range index {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// prefixRange returns a copy of the sorted keys starting with prefix
+func prefixRange(sorted []string, prefix string) []string {
+	start := sort.SearchStrings(sorted, prefix)
+	end := start
+	for end < len(sorted) && strings.HasPrefix(sorted[end], prefix) {
+		end++
+	}
+
+	result := make([]string, end-start)
+	copy(result, sorted[start:end])
+	return result
+}
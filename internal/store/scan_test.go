@@ -0,0 +1,113 @@
+package store
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func newScanTestStore() *TripleStore {
+	ts := NewTripleStore()
+	ts.Add("<#pkg/api/handler.go>", "https://schema.codedoc.org/layer", "api")
+	ts.Add("<#pkg/api/handler.go>", "https://schema.codedoc.org/tags", "http")
+	ts.Add("<#pkg/api/routes.go>", "https://schema.codedoc.org/layer", "api")
+	ts.Add("<#pkg/apiclient/client.go>", "https://schema.codedoc.org/layer", "client")
+	ts.Add("<#pkg/store/db.go>", "https://schema.codedoc.org/layer", "data")
+	ts.Add("<#pkg/store/db.go>", "http://www.w3.org/1999/02/22-rdf-syntax-ns#type", "code:Module")
+	return ts
+}
+
+func TestTripleStore_ScanSubjectPrefix(t *testing.T) {
+	ts := newScanTestStore()
+
+	var subjects []string
+	for triple := range ts.ScanSubjectPrefix("<#pkg/api/") {
+		subjects = append(subjects, triple.Subject)
+	}
+
+	expected := []string{"<#pkg/api/handler.go>", "<#pkg/api/handler.go>", "<#pkg/api/routes.go>"}
+	if !slices.Equal(subjects, expected) {
+		t.Errorf("Expected %v, got %v", expected, subjects)
+	}
+
+	// Early termination
+	count := 0
+	for range ts.ScanSubjectPrefix("<#pkg/") {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected iteration to stop after 2, got %d", count)
+	}
+}
+
+func TestTripleStore_ScanPredicatePrefix(t *testing.T) {
+	ts := newScanTestStore()
+
+	var triples []Triple
+	for triple := range ts.ScanPredicatePrefix("https://schema.codedoc.org/") {
+		triples = append(triples, triple)
+	}
+	if len(triples) != 5 {
+		t.Fatalf("Expected 5 code: triples, got %d", len(triples))
+	}
+	if triples[0].Predicate != "https://schema.codedoc.org/layer" || triples[0].Subject != "<#pkg/api/handler.go>" {
+		t.Errorf("Expected results ordered by predicate and subject, got %+v", triples[0])
+	}
+
+	predicates := ts.PredicatesWithPrefix("https://schema.codedoc.org/")
+	if !slices.Equal(predicates, []string{"https://schema.codedoc.org/layer", "https://schema.codedoc.org/tags"}) {
+		t.Errorf("Unexpected predicates: %v", predicates)
+	}
+}
+
+func TestTripleStore_ScanReflectsChanges(t *testing.T) {
+	ts := newScanTestStore()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts.SetClock(func() time.Time { return now })
+
+	if len(ts.SubjectsWithPrefix("<#pkg/api/")) != 2 {
+		t.Fatal("Expected 2 api subjects")
+	}
+
+	ts.Add("<#pkg/api/middleware.go>", "https://schema.codedoc.org/layer", "api")
+	ts.Delete("<#pkg/api/routes.go>", "https://schema.codedoc.org/layer", "api")
+	ts.AddWithExpiry("<#pkg/api/temp.go>", "https://schema.codedoc.org/layer", "api", now.Add(-time.Hour))
+
+	subjects := ts.SubjectsWithPrefix("<#pkg/api/")
+	expected := []string{"<#pkg/api/handler.go>", "<#pkg/api/middleware.go>", "<#pkg/api/temp.go>"}
+	if !slices.Equal(subjects, expected) {
+		t.Errorf("Expected %v, got %v", expected, subjects)
+	}
+
+	// Expired triples are skipped by scans
+	for triple := range ts.ScanSubjectPrefix("<#pkg/api/temp") {
+		t.Errorf("Expected no live triples for expired subject, got %+v", triple)
+	}
+
+	ts.Clear()
+	if len(ts.SubjectsWithPrefix("")) != 0 {
+		t.Error("Expected no subjects after Clear")
+	}
+}
+
+func TestTripleStore_OrderedKeysStaySorted(t *testing.T) {
+	ts := newScanTestStore()
+	ts.SubjectsWithPrefix("") // Build the sorted indexes
+
+	ts.Add("<#pkg/a.go>", "https://schema.codedoc.org/name", "a.go")
+	ts.Add("<#pkg/api/auth.go>", "https://schema.codedoc.org/layer", "api")
+	ts.Delete("<#pkg/store/db.go>", "http://www.w3.org/1999/02/22-rdf-syntax-ns#type", "code:Module")
+
+	subjects := ts.SubjectsWithPrefix("")
+	if !slices.IsSorted(subjects) || len(subjects) != 6 {
+		t.Errorf("Expected 6 sorted subjects, got %v", subjects)
+	}
+	predicates := ts.PredicatesWithPrefix("")
+	expected := []string{"https://schema.codedoc.org/layer", "https://schema.codedoc.org/name", "https://schema.codedoc.org/tags"}
+	if !slices.Equal(predicates, expected) {
+		t.Errorf("Expected %v, got %v", expected, predicates)
+	}
+}
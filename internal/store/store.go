@@ -12,7 +12,7 @@ for fast lookups and pattern matching.
 store, rdf, triplestore, in-memory
 
 ## Exports
-TripleStore, NewTripleStore
+TripleStore, NewTripleStore, TripleStore.Clone
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -24,7 +24,7 @@ TripleStore, NewTripleStore
     code:language "go" ;
     code:layer "storage" ;
     code:linksTo <./triple.go> ;
-    code:exports <#TripleStore>, <#NewTripleStore> ;
+    code:exports <#TripleStore>, <#NewTripleStore>, <#TripleStore.Clone> ;
     code:tags "store", "rdf", "triplestore", "in-memory" .
 
 <#TripleStore> a code:Type ;
@@ -45,6 +45,7 @@ package store
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 // IndexStats contains statistics for query optimization
@@ -73,6 +74,22 @@ type TripleStore struct {
 
 	// Statistics for query optimization
 	stats IndexStats
+
+	// Expiry timestamps for ephemeral triples (see expiry.go)
+	expiry map[Triple]time.Time
+
+	// Clock used for expiry checks
+	now func() time.Time
+
+	// Named graph of each triple that is in one (see graphs.go)
+	graphs map[Triple]string
+
+	// Sorted subject and predicate keys for prefix scans (see scan.go),
+	// built by the first scan and kept sorted by writes from then on
+	subjectOrder   []string
+	predicateOrder []string
+	orderBuilt     bool
+	orderMu        sync.Mutex
 }
 
 // NewTripleStore creates a new in-memory triple store
@@ -88,17 +105,36 @@ func NewTripleStore() *TripleStore {
 			ObjectCounts:    make(map[string]int),
 			TotalTriples:    0,
 		},
+		expiry: make(map[Triple]time.Time),
+		now:    time.Now,
+		graphs: make(map[Triple]string),
 	}
 }
 
-// Add inserts a triple into the store
+// Add inserts a triple into the store. Adding an existing ephemeral triple
+// makes it permanent.
 func (ts *TripleStore) Add(subject, predicate, object string) error {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 
+	delete(ts.expiry, Triple{Subject: subject, Predicate: predicate, Object: object})
+	ts.addUnsafe(subject, predicate, object)
+	return nil
+}
+
+// addUnsafe inserts a triple into the indexes (no locking)
+func (ts *TripleStore) addUnsafe(subject, predicate, object string) {
 	// Check if triple already exists
 	if ts.existsUnsafe(subject, predicate, object) {
-		return nil // Already exists, no error
+		return // Already exists, no error
+	}
+
+	// New subjects and predicates join the sorted key indexes
+	if ts.spo[subject] == nil {
+		ts.subjectOrder = ts.insertOrderedUnsafe(ts.subjectOrder, subject)
+	}
+	if ts.pos[predicate] == nil {
+		ts.predicateOrder = ts.insertOrderedUnsafe(ts.predicateOrder, predicate)
 	}
 
 	// Add to SPO index
@@ -135,7 +171,6 @@ func (ts *TripleStore) Add(subject, predicate, object string) error {
 	ts.stats.TotalTriples++
 
 	ts.count++
-	return nil
 }
 
 // AddTriple inserts a Triple struct into the store
@@ -153,11 +188,17 @@ func (ts *TripleStore) BulkAdd(triples []Triple) error {
 	return nil
 }
 
-// Find queries triples matching the pattern (use "" for wildcard)
+// Find queries triples matching the pattern (use "" for wildcard).
+// Expired triples are not returned.
 func (ts *TripleStore) Find(subject, predicate, object string) []Triple {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
 
+	return ts.withoutExpiredUnsafe(ts.findIndexedUnsafe(subject, predicate, object))
+}
+
+// findIndexedUnsafe queries the most specific index (no locking)
+func (ts *TripleStore) findIndexedUnsafe(subject, predicate, object string) []Triple {
 	var results []Triple
 
 	// All wildcards - return all triples
@@ -241,7 +282,8 @@ func (ts *TripleStore) Find(subject, predicate, object string) []Triple {
 	return results
 }
 
-// Get retrieves all properties for a subject as a map
+// Get retrieves all properties for a subject as a map.
+// Expired triples are not returned.
 func (ts *TripleStore) Get(subject string) map[string][]string {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
@@ -252,9 +294,14 @@ func (ts *TripleStore) Get(subject string) map[string][]string {
 		for p, oMap := range pMap {
 			var objects []string
 			for o := range oMap {
+				if ts.isExpiredUnsafe(Triple{Subject: subject, Predicate: p, Object: o}) {
+					continue
+				}
 				objects = append(objects, o)
 			}
-			result[p] = objects
+			if len(objects) > 0 {
+				result[p] = objects
+			}
 		}
 	}
 
@@ -285,7 +332,10 @@ func (ts *TripleStore) Clear() error {
 	ts.spo = make(map[string]map[string]map[string]bool)
 	ts.pos = make(map[string]map[string]map[string]bool)
 	ts.osp = make(map[string]map[string]map[string]bool)
+	ts.expiry = make(map[Triple]time.Time)
+	ts.graphs = make(map[Triple]string)
 	ts.count = 0
+	ts.subjectOrder, ts.predicateOrder, ts.orderBuilt = nil, nil, false
 
 	// Reset statistics
 	ts.stats = IndexStats{
@@ -298,6 +348,30 @@ func (ts *TripleStore) Clear() error {
 	return nil
 }
 
+// Clone returns an independent copy of the store, including expiry times
+// and named graphs
+func (ts *TripleStore) Clone() *TripleStore {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	clone := NewTripleStore()
+	for subject, predicates := range ts.spo {
+		for predicate, objects := range predicates {
+			for object := range objects {
+				clone.addUnsafe(subject, predicate, object)
+			}
+		}
+	}
+	for triple, expiresAt := range ts.expiry {
+		clone.expiry[triple] = expiresAt
+	}
+	for triple, name := range ts.graphs {
+		clone.graphs[triple] = name
+	}
+	clone.now = ts.now
+	return clone
+}
+
 // Count returns the total number of triples
 func (ts *TripleStore) Count() int {
 	ts.mu.RLock()
@@ -419,6 +493,9 @@ func (ts *TripleStore) findUnsafe(subject, predicate, object string) []Triple {
 
 // deleteTripleUnsafe deletes a specific triple (no locking)
 func (ts *TripleStore) deleteTripleUnsafe(subject, predicate, object string) {
+	delete(ts.expiry, Triple{Subject: subject, Predicate: predicate, Object: object})
+	delete(ts.graphs, Triple{Subject: subject, Predicate: predicate, Object: object})
+
 	// Remove from SPO index
 	if pMap, ok := ts.spo[subject]; ok {
 		if oMap, ok := pMap[predicate]; ok {
@@ -429,6 +506,7 @@ func (ts *TripleStore) deleteTripleUnsafe(subject, predicate, object string) {
 		}
 		if len(pMap) == 0 {
 			delete(ts.spo, subject)
+			ts.subjectOrder = ts.removeOrderedUnsafe(ts.subjectOrder, subject)
 		}
 	}
 
@@ -442,6 +520,7 @@ func (ts *TripleStore) deleteTripleUnsafe(subject, predicate, object string) {
 		}
 		if len(oMap) == 0 {
 			delete(ts.pos, predicate)
+			ts.predicateOrder = ts.removeOrderedUnsafe(ts.predicateOrder, predicate)
 		}
 	}
 
@@ -230,6 +230,29 @@ func TestTripleStore_Clear(t *testing.T) {
 	}
 }
 
+func TestTripleStore_Clone(t *testing.T) {
+	store := NewTripleStore()
+	store.Add("s1", "p1", "o1")
+	store.Add("s2", "p2", "o2")
+
+	clone := store.Clone()
+	clone.Add("s3", "p3", "o3")
+	store.Delete("s1", "", "")
+
+	if clone.Count() != 3 {
+		t.Errorf("Clone Count() = %d, want 3", clone.Count())
+	}
+	if len(clone.Find("s1", "p1", "o1")) != 1 {
+		t.Error("Deleting from the original should not change the clone")
+	}
+	if len(store.Find("s3", "", "")) != 0 {
+		t.Error("Adding to the clone should not change the original")
+	}
+	if len(clone.Find("", "p2", "")) != 1 || len(clone.Find("", "", "o2")) != 1 {
+		t.Error("Clone should be indexed by predicate and object")
+	}
+}
+
 func TestTripleStore_Subjects(t *testing.T) {
 	store := NewTripleStore()
 
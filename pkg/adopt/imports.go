@@ -0,0 +1,406 @@
+/*
+# Module: pkg/adopt/imports.go
+Provisional dependency graph inferred from source imports.
+
+Repositories without LinkedDoc metadata have no declared links, so adoption
+planning reads import statements instead. Imports are resolved to files in
+the repository for Go (through the nearest go.mod), Python, JavaScript,
+TypeScript, Java, C and C++; imports of external packages are ignored. Files
+in other languages take part without edges. Entry points are recognised from
+main functions in Go, Java, C and C++, and otherwise from __main__ guards,
+shebangs, conventional file names and cmd/ or bin/ directories.
+
+## Linked Modules
+- [report](./report.go) - Adoption report
+- [../scanner](../scanner/scanner.go) - File scanning
+
+## Tags
+adoption, imports, inference
+
+## Exports
+ProvisionalGraph, ProvisionalFile, Infer
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#imports.go> a code:Module ;
+    code:name "pkg/adopt/imports.go" ;
+    code:description "Provisional dependency graph inferred from source imports" ;
+    code:language "go" ;
+    code:layer "adopt" ;
+    code:linksTo <./report.go>, <../scanner/scanner.go> ;
+    code:exports <#ProvisionalGraph>, <#ProvisionalFile>, <#Infer> ;
+    code:tags "adoption", "imports", "inference" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package adopt
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// ProvisionalGraph is the import graph of a repository's source files
+type ProvisionalGraph struct {
+	Root  string
+	Files map[string]*ProvisionalFile // By slash-separated path relative to Root
+
+	// Unsupported counts files per language whose imports are not inferred
+	Unsupported map[string]int
+}
+
+// ProvisionalFile is a source file in the provisional graph
+type ProvisionalFile struct {
+	Path       string
+	Language   string
+	Annotated  bool     // Already has LinkedDoc metadata
+	EntryPoint bool     // Looks like a program entry point
+	Imports    []string // Repository files it imports, sorted
+	Dependents []string // Repository files importing it, sorted
+}
+
+// Language keys with import inference
+var inferredLanguages = map[string]bool{
+	"go": true, "python": true, "javascript": true, "typescript": true,
+	"java": true, "c": true, "cpp": true,
+}
+
+// Language keys whose entry points are recognised from a main function
+// alone; other languages also use file locations
+var preciseEntryPoints = map[string]bool{"go": true, "java": true, "c": true, "cpp": true}
+
+var (
+	goImportLine   = regexp.MustCompile(`^\s*(?:import\s+)?(?:[\w.]+\s+)?"([^"]+)"`)
+	pyImport       = regexp.MustCompile(`^\s*import\s+(.+)$`)
+	pyFromImport   = regexp.MustCompile(`^\s*from\s+(\.*)([\w.]*)\s+import\b`)
+	jsImport       = regexp.MustCompile(`(?:\bfrom\s*|\bimport\s*\(?\s*|\brequire\s*\(\s*)['"]([^'"]+)['"]`)
+	javaImport     = regexp.MustCompile(`^\s*import\s+(?:static\s+)?([\w.]+)\s*;`)
+	cInclude       = regexp.MustCompile(`^\s*#\s*include\s*"([^"]+)"`)
+	goModuleLine   = regexp.MustCompile(`^module\s+(\S+)`)
+	jsExtensions   = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"}
+	entryNames     = map[string]bool{"index": true, "main": true, "server": true, "app": true, "cli": true}
+	goEntryPackage = regexp.MustCompile(`^package\s+main\b`)
+)
+
+// Infer builds the provisional graph of scanned files under root. Files
+// with an unknown language, binary content or generated-code markers are
+// left out.
+func Infer(root string, files []*scanner.FileInfo) (*ProvisionalGraph, error) {
+	g := &ProvisionalGraph{
+		Root:        root,
+		Files:       make(map[string]*ProvisionalFile),
+		Unsupported: make(map[string]int),
+	}
+
+	for _, info := range files {
+		if info.Binary || info.Generated || info.Language == "unknown" {
+			continue
+		}
+		rel := info.Path
+		if filepath.IsAbs(rel) {
+			r, err := filepath.Rel(root, rel)
+			if err != nil {
+				continue
+			}
+			rel = r
+		}
+		rel = filepath.ToSlash(rel)
+		g.Files[rel] = &ProvisionalFile{Path: rel, Language: info.Language, Annotated: info.HasLinkedDoc}
+	}
+
+	r := newResolver(root, g.Files)
+	for _, file := range g.Files {
+		key := scanner.DetectLanguageKey(file.Path)
+		if !inferredLanguages[key] {
+			g.Unsupported[file.Language]++
+			file.EntryPoint = pathEntryPoint(file.Path)
+			continue
+		}
+
+		imports, entry, err := r.scan(file.Path, key)
+		if err != nil {
+			return nil, err
+		}
+		file.EntryPoint = entry || (!preciseEntryPoints[key] && pathEntryPoint(file.Path))
+		for _, dep := range imports {
+			if dep != file.Path && !slices.Contains(file.Imports, dep) {
+				file.Imports = append(file.Imports, dep)
+			}
+		}
+		sort.Strings(file.Imports)
+	}
+
+	for _, file := range g.Files {
+		for _, dep := range file.Imports {
+			g.Files[dep].Dependents = append(g.Files[dep].Dependents, file.Path)
+		}
+	}
+	for _, file := range g.Files {
+		sort.Strings(file.Dependents)
+	}
+
+	return g, nil
+}
+
+// TransitiveDependents returns the files that import path directly or
+// indirectly
+func (g *ProvisionalGraph) TransitiveDependents(p string) map[string]bool {
+	seen := make(map[string]bool)
+	queue := []string{p}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range g.Files[current].Dependents {
+			if !seen[dependent] && dependent != p {
+				seen[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return seen
+}
+
+// resolver maps import statements to repository files
+type resolver struct {
+	root     string
+	files    map[string]*ProvisionalFile
+	goModule string              // Import path of the root directory
+	goDirs   map[string][]string // Directory -> non-test Go files
+	suffixes map[string][]string // Java class path (a/b/C.java) -> files
+}
+
+func newResolver(root string, files map[string]*ProvisionalFile) *resolver {
+	r := &resolver{
+		root:     root,
+		files:    files,
+		goDirs:   make(map[string][]string),
+		suffixes: make(map[string][]string),
+	}
+	for p := range files {
+		switch scanner.DetectLanguageKey(p) {
+		case "go":
+			if !strings.HasSuffix(p, "_test.go") {
+				r.goDirs[path.Dir(p)] = append(r.goDirs[path.Dir(p)], p)
+			}
+		case "java":
+			// Index every suffix so packages resolve under any source root
+			parts := strings.Split(p, "/")
+			for i := range parts {
+				suffix := strings.Join(parts[i:], "/")
+				r.suffixes[suffix] = append(r.suffixes[suffix], p)
+			}
+		}
+	}
+	r.goModule = goImportPath(root)
+	return r
+}
+
+// goImportPath returns the import path of root from the nearest go.mod in
+// root or a parent directory, or "" outside a Go module
+func goImportPath(root string) string {
+	for dir := root; ; dir = filepath.Dir(dir) {
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				m := goModuleLine.FindStringSubmatch(strings.TrimSpace(line))
+				if m == nil {
+					continue
+				}
+				rel, err := filepath.Rel(dir, root)
+				if err != nil || rel == "." {
+					return m[1]
+				}
+				return m[1] + "/" + filepath.ToSlash(rel)
+			}
+			return ""
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// scan reads a file's imports and whether it is an entry point
+func (r *resolver) scan(p, lang string) ([]string, bool, error) {
+	f, err := os.Open(filepath.Join(r.root, filepath.FromSlash(p)))
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	var imports []string
+	entry := false
+	inGoImports := false
+	goMainPackage := false
+	lineNum := 0
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		lineNum++
+		trimmed := strings.TrimSpace(line)
+
+		switch lang {
+		case "go":
+			if goEntryPackage.MatchString(trimmed) {
+				goMainPackage = true
+			}
+			if goMainPackage && strings.HasPrefix(trimmed, "func main()") {
+				entry = true
+			}
+			switch {
+			case strings.HasPrefix(trimmed, "import ("):
+				inGoImports = true
+			case inGoImports && trimmed == ")":
+				inGoImports = false
+			case inGoImports || strings.HasPrefix(trimmed, "import "):
+				if m := goImportLine.FindStringSubmatch(trimmed); m != nil {
+					imports = append(imports, r.goPackage(m[1])...)
+				}
+			}
+
+		case "python":
+			if strings.Contains(trimmed, "__name__") && strings.Contains(trimmed, "__main__") {
+				entry = true
+			}
+			if m := pyFromImport.FindStringSubmatch(line); m != nil {
+				imports = append(imports, r.pythonModule(p, m[1], m[2])...)
+			} else if m := pyImport.FindStringSubmatch(line); m != nil {
+				for _, name := range strings.Split(m[1], ",") {
+					// "import a.b as c"
+					if fields := strings.Fields(name); len(fields) > 0 {
+						imports = append(imports, r.pythonModule(p, "", fields[0])...)
+					}
+				}
+			}
+
+		case "javascript", "typescript":
+			if lineNum == 1 && strings.HasPrefix(line, "#!") {
+				entry = true
+			}
+			for _, m := range jsImport.FindAllStringSubmatch(line, -1) {
+				if dep := r.jsModule(p, m[1]); dep != "" {
+					imports = append(imports, dep)
+				}
+			}
+
+		case "java":
+			if strings.Contains(trimmed, "static void main(") {
+				entry = true
+			}
+			if m := javaImport.FindStringSubmatch(line); m != nil {
+				class := strings.ReplaceAll(m[1], ".", "/") + ".java"
+				if deps := r.suffixes[class]; len(deps) == 1 {
+					imports = append(imports, deps[0])
+				}
+			}
+
+		case "c", "cpp":
+			if strings.HasPrefix(trimmed, "int main(") {
+				entry = true
+			}
+			if m := cInclude.FindStringSubmatch(line); m != nil {
+				if dep := r.firstExisting(path.Join(path.Dir(p), m[1]), m[1], path.Join("include", m[1])); dep != "" {
+					imports = append(imports, dep)
+				}
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, false, err
+	}
+	if lang == "python" && path.Base(p) == "__main__.py" {
+		entry = true
+	}
+	return imports, entry, nil
+}
+
+// goPackage returns the files of an imported package inside the module
+func (r *resolver) goPackage(importPath string) []string {
+	if r.goModule == "" {
+		return nil
+	}
+	if importPath == r.goModule {
+		return r.goDirs["."]
+	}
+	if rel, ok := strings.CutPrefix(importPath, r.goModule+"/"); ok {
+		return r.goDirs[rel]
+	}
+	return nil
+}
+
+// pythonModule resolves "import a.b" (dots empty) or "from ..a import"
+// relative to the importing file
+func (r *resolver) pythonModule(from, dots, name string) []string {
+	base := "."
+	if dots != "" {
+		base = path.Dir(from)
+		for i := 1; i < len(dots); i++ {
+			base = path.Dir(base)
+		}
+	}
+	modulePath := path.Join(base, strings.ReplaceAll(name, ".", "/"))
+	if dep := r.firstExisting(modulePath+".py", path.Join(modulePath, "__init__.py")); dep != "" {
+		return []string{dep}
+	}
+	return nil
+}
+
+// jsModule resolves a relative JavaScript or TypeScript specifier
+func (r *resolver) jsModule(from, specifier string) string {
+	if !strings.HasPrefix(specifier, "./") && !strings.HasPrefix(specifier, "../") {
+		return ""
+	}
+	target := path.Join(path.Dir(from), specifier)
+	candidates := []string{target}
+	for _, ext := range jsExtensions {
+		candidates = append(candidates, target+ext)
+	}
+	// Compiled-extension imports ("./x.js") of TypeScript sources
+	if ext := path.Ext(target); ext == ".js" || ext == ".mjs" || ext == ".cjs" {
+		stem := strings.TrimSuffix(target, ext)
+		candidates = append(candidates, stem+".ts", stem+".tsx")
+	}
+	for _, ext := range jsExtensions {
+		candidates = append(candidates, path.Join(target, "index"+ext))
+	}
+	return r.firstExisting(candidates...)
+}
+
+// firstExisting returns the first candidate that is a file in the graph
+func (r *resolver) firstExisting(candidates ...string) string {
+	for _, candidate := range candidates {
+		candidate = path.Clean(candidate)
+		if _, ok := r.files[candidate]; ok {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// pathEntryPoint recognises entry points by location: files under cmd/ or
+// bin/, and main, index, server, app or cli files at the root or in src/
+func pathEntryPoint(p string) bool {
+	dir := path.Dir(p)
+	for _, segment := range strings.Split(dir, "/") {
+		if segment == "cmd" || segment == "bin" {
+			return true
+		}
+	}
+	if dir != "." && dir != "src" {
+		return false
+	}
+	name := path.Base(p)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return entryNames[name]
+}
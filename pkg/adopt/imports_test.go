@@ -0,0 +1,99 @@
+package adopt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// writeRepo writes files under a temporary root and returns the root and
+// scanner entries for them
+func writeRepo(t *testing.T, files map[string]string) (string, []*scanner.FileInfo) {
+	t.Helper()
+	root := t.TempDir()
+	var infos []*scanner.FileInfo
+	for p, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		infos = append(infos, &scanner.FileInfo{
+			Path:         full,
+			Language:     scanner.DetectLanguage(p),
+			HasLinkedDoc: strings.Contains(content, "LinkedDoc RDF"),
+		})
+	}
+	return root, infos
+}
+
+func TestInfer_Go(t *testing.T) {
+	root, files := writeRepo(t, map[string]string{
+		"go.mod":                  "module example.com/app\n\ngo 1.23\n",
+		"cmd/app/main.go":         "package main\n\nimport (\n\t\"fmt\"\n\tstore \"example.com/app/internal/store\"\n)\n\nfunc main() {}\n",
+		"internal/store/db.go":    "package store\n\nimport \"example.com/app/pkg/util\"\n",
+		"internal/store/kv.go":    "package store\n",
+		"internal/store/_test.go": "package store\n",
+		"pkg/util/util.go":        "package util\n",
+		"pkg/util/util_test.go":   "package util\n\nimport \"example.com/app/internal/store\"\n",
+	})
+
+	g, err := Infer(root, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := g.Files["cmd/app/main.go"]
+	if !main.EntryPoint || strings.Join(main.Imports, ",") != "internal/store/db.go,internal/store/kv.go" {
+		t.Errorf("main.go = %+v, want an entry point importing the store package", main)
+	}
+	if got := strings.Join(g.Files["pkg/util/util.go"].Dependents, ","); got != "internal/store/db.go" {
+		t.Errorf("util.go dependents = %s, want db.go", got)
+	}
+	// main.go through db.go; util_test.go imports the store package
+	if got := len(g.TransitiveDependents("pkg/util/util.go")); got != 3 {
+		t.Errorf("util.go transitive dependents = %d, want 3", got)
+	}
+}
+
+func TestInfer_PythonAndTypeScript(t *testing.T) {
+	root, files := writeRepo(t, map[string]string{
+		"app/__init__.py":       "",
+		"app/models.py":         "import os\n",
+		"app/views.py":          "from . import models\nfrom .models import User\nimport app.models as m, sys\n",
+		"manage.py":             "import app.views\n\nif __name__ == \"__main__\":\n    pass\n",
+		"web/src/index.ts":      "import { api } from './api'\nimport React from 'react'\n",
+		"web/src/api/index.ts":  "export * from \"../util.js\"\nconst x = require('./client')\n",
+		"web/src/api/client.ts": "",
+		"web/src/util.ts":       "",
+		"lib/tool.rs":           "use crate::x;\n",
+	})
+
+	g, err := Infer(root, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"app/views.py":         "app/__init__.py,app/models.py",
+		"manage.py":            "app/views.py",
+		"web/src/index.ts":     "web/src/api/index.ts",
+		"web/src/api/index.ts": "web/src/api/client.ts,web/src/util.ts",
+	}
+	for p, want := range tests {
+		if got := strings.Join(g.Files[p].Imports, ","); got != want {
+			t.Errorf("%s imports = %q, want %q", p, got, want)
+		}
+	}
+	if !g.Files["manage.py"].EntryPoint || g.Files["app/views.py"].EntryPoint {
+		t.Error("Expected manage.py, and not views.py, to be an entry point")
+	}
+	if g.Unsupported["Rust"] != 1 {
+		t.Errorf("Unsupported = %v, want Rust: 1", g.Unsupported)
+	}
+}
@@ -0,0 +1,244 @@
+/*
+# Module: pkg/adopt/report.go
+Adoption report: which files to annotate first.
+
+Ranks files without LinkedDoc metadata in a provisional graph by how much
+of the repository they explain: the share of files that depend on them,
+plus a bonus for entry points. Ranked files are grouped into batches, each
+with the file coverage and import coverage the repository would reach once
+the batch and the ones before it are annotated. Import coverage counts
+inferred imports between annotated files, which the knowledge graph can
+then represent as declared links.
+
+## Linked Modules
+- [imports](./imports.go) - Provisional dependency graph
+
+## Tags
+adoption, planning, coverage
+
+## Exports
+Report, Batch, Candidate, ReportOptions, BuildReport
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#report.go> a code:Module ;
+    code:name "pkg/adopt/report.go" ;
+    code:description "Adoption report: which files to annotate first" ;
+    code:language "go" ;
+    code:layer "adopt" ;
+    code:linksTo <./imports.go> ;
+    code:exports <#Report>, <#Batch>, <#Candidate>, <#ReportOptions>, <#BuildReport> ;
+    code:tags "adoption", "planning", "coverage" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package adopt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// entryPointBonus is added to the score of entry points
+const entryPointBonus = 0.5
+
+// ReportOptions configures the adoption report
+type ReportOptions struct {
+	BatchSize  int // Files per batch (default 10)
+	MaxBatches int // Batches to plan (0 for all)
+}
+
+// Report is a prioritized plan for annotating a repository
+type Report struct {
+	Files          int            `json:"files"`           // Source files in the provisional graph
+	Annotated      int            `json:"annotated"`       // Files that already have LinkedDoc metadata
+	Imports        int            `json:"imports"`         // Inferred imports between files
+	CoveredImports int            `json:"covered_imports"` // Imports between annotated files
+	EntryPoints    int            `json:"entry_points"`
+	Unsupported    map[string]int `json:"unsupported,omitempty"` // Files per language without import inference
+	Batches        []Batch        `json:"batches"`
+	Remaining      int            `json:"remaining"` // Unannotated files beyond the planned batches
+}
+
+// Batch is a group of files to annotate together
+type Batch struct {
+	Number         int         `json:"number"`
+	Files          []Candidate `json:"files"`
+	FileCoverage   float64     `json:"file_coverage"`   // 0-1, after this batch
+	ImportCoverage float64     `json:"import_coverage"` // 0-1, after this batch
+	FileGain       float64     `json:"file_gain"`       // Increase over the previous batch
+	ImportGain     float64     `json:"import_gain"`
+}
+
+// Candidate is a file to annotate
+type Candidate struct {
+	Path       string  `json:"path"`
+	Language   string  `json:"language"`
+	Score      float64 `json:"score"`
+	EntryPoint bool    `json:"entry_point,omitempty"`
+	Dependents int     `json:"dependents"` // Files importing it directly or indirectly
+	Imports    int     `json:"imports"`    // Repository files it imports
+}
+
+// Reason describes why the candidate ranks where it does
+func (c Candidate) Reason() string {
+	var reasons []string
+	if c.EntryPoint {
+		reasons = append(reasons, "entry point")
+	}
+	if c.Dependents > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d dependent(s)", c.Dependents))
+	}
+	if len(reasons) == 0 {
+		return "leaf"
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// BuildReport ranks the unannotated files of g and plans batches. A file
+// scores the share of other files depending on it, plus 0.5 if it is an
+// entry point; ties go to files with more imports, then by path.
+func BuildReport(g *ProvisionalGraph, opts ReportOptions) *Report {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 10
+	}
+
+	report := &Report{Files: len(g.Files), Unsupported: g.Unsupported}
+	annotated := make(map[string]bool)
+	var candidates []Candidate
+	for p, file := range g.Files {
+		report.Imports += len(file.Imports)
+		if file.EntryPoint {
+			report.EntryPoints++
+		}
+		if file.Annotated {
+			annotated[p] = true
+			continue
+		}
+
+		dependents := len(g.TransitiveDependents(p))
+		score := 0.0
+		if len(g.Files) > 1 {
+			score = float64(dependents) / float64(len(g.Files)-1)
+		}
+		if file.EntryPoint {
+			score += entryPointBonus
+		}
+		candidates = append(candidates, Candidate{
+			Path:       p,
+			Language:   file.Language,
+			Score:      round(score),
+			EntryPoint: file.EntryPoint,
+			Dependents: dependents,
+			Imports:    len(file.Imports),
+		})
+	}
+	report.Annotated = len(annotated)
+	report.CoveredImports = coveredImports(g, annotated)
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Imports != b.Imports {
+			return a.Imports > b.Imports
+		}
+		return a.Path < b.Path
+	})
+
+	fileCoverage := ratio(report.Annotated, report.Files)
+	importCoverage := ratio(report.CoveredImports, report.Imports)
+	for start := 0; start < len(candidates); start += opts.BatchSize {
+		if opts.MaxBatches > 0 && len(report.Batches) == opts.MaxBatches {
+			report.Remaining = len(candidates) - start
+			break
+		}
+		end := min(start+opts.BatchSize, len(candidates))
+		batch := Batch{Number: len(report.Batches) + 1, Files: candidates[start:end]}
+		for _, c := range batch.Files {
+			annotated[c.Path] = true
+		}
+
+		batch.FileCoverage = ratio(len(annotated), report.Files)
+		batch.ImportCoverage = ratio(coveredImports(g, annotated), report.Imports)
+		batch.FileGain = round(batch.FileCoverage - fileCoverage)
+		batch.ImportGain = round(batch.ImportCoverage - importCoverage)
+		fileCoverage, importCoverage = batch.FileCoverage, batch.ImportCoverage
+		report.Batches = append(report.Batches, batch)
+	}
+
+	return report
+}
+
+// Markdown renders the report
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString("# LinkedDoc Adoption Report\n\n")
+	fmt.Fprintf(&sb, "- **Source files:** %d (%d annotated, %s)\n", r.Files, r.Annotated, percent(ratio(r.Annotated, r.Files)))
+	fmt.Fprintf(&sb, "- **Inferred imports:** %d (%d between annotated files, %s)\n", r.Imports, r.CoveredImports, percent(ratio(r.CoveredImports, r.Imports)))
+	fmt.Fprintf(&sb, "- **Entry points:** %d\n", r.EntryPoints)
+	if len(r.Unsupported) > 0 {
+		languages := make([]string, 0, len(r.Unsupported))
+		for language, count := range r.Unsupported {
+			languages = append(languages, fmt.Sprintf("%s (%d)", language, count))
+		}
+		sort.Strings(languages)
+		fmt.Fprintf(&sb, "- **Without import inference:** %s\n", strings.Join(languages, ", "))
+	}
+
+	if len(r.Batches) == 0 {
+		sb.WriteString("\nEvery source file is annotated.\n")
+		return sb.String()
+	}
+
+	for _, batch := range r.Batches {
+		fmt.Fprintf(&sb, "\n## Batch %d\n\n", batch.Number)
+		fmt.Fprintf(&sb, "File coverage %s (+%s), import coverage %s (+%s)\n\n",
+			percent(batch.FileCoverage), percent(batch.FileGain),
+			percent(batch.ImportCoverage), percent(batch.ImportGain))
+		sb.WriteString("| File | Score | Why |\n|------|-------|-----|\n")
+		for _, c := range batch.Files {
+			fmt.Fprintf(&sb, "| `%s` | %.2f | %s |\n", c.Path, c.Score, c.Reason())
+		}
+	}
+	if r.Remaining > 0 {
+		fmt.Fprintf(&sb, "\n%d more file(s) after these batches.\n", r.Remaining)
+	}
+
+	return sb.String()
+}
+
+// coveredImports counts imports whose both ends are annotated
+func coveredImports(g *ProvisionalGraph, annotated map[string]bool) int {
+	covered := 0
+	for p := range annotated {
+		for _, dep := range g.Files[p].Imports {
+			if annotated[dep] {
+				covered++
+			}
+		}
+	}
+	return covered
+}
+
+// ratio returns n/total rounded to two places, or 0 when total is 0
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round(float64(n) / float64(total))
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func percent(v float64) string {
+	return fmt.Sprintf("%.0f%%", v*100)
+}
@@ -0,0 +1,70 @@
+package adopt
+
+import (
+	"strings"
+	"testing"
+)
+
+// createTestProvisionalGraph builds:
+//
+//	main.go -> service.go -> db.go
+//	handler.go -> service.go
+//	util.go (annotated) -> db.go
+func createTestProvisionalGraph() *ProvisionalGraph {
+	g := &ProvisionalGraph{Files: map[string]*ProvisionalFile{
+		"main.go":    {Path: "main.go", Language: "Go", EntryPoint: true, Imports: []string{"service.go"}},
+		"handler.go": {Path: "handler.go", Language: "Go", Imports: []string{"service.go"}},
+		"service.go": {Path: "service.go", Language: "Go", Imports: []string{"db.go"}, Dependents: []string{"handler.go", "main.go"}},
+		"db.go":      {Path: "db.go", Language: "Go", Dependents: []string{"service.go", "util.go"}},
+		"util.go":    {Path: "util.go", Language: "Go", Annotated: true, Imports: []string{"db.go"}},
+	}}
+	return g
+}
+
+func TestBuildReport(t *testing.T) {
+	report := BuildReport(createTestProvisionalGraph(), ReportOptions{BatchSize: 2})
+
+	if report.Files != 5 || report.Annotated != 1 || report.Imports != 4 || report.EntryPoints != 1 {
+		t.Fatalf("Report totals = %+v", report)
+	}
+	if len(report.Batches) != 2 {
+		t.Fatalf("Batches = %d, want 2", len(report.Batches))
+	}
+
+	first := report.Batches[0]
+	var paths []string
+	for _, c := range first.Files {
+		paths = append(paths, c.Path)
+	}
+	// db.go has four dependents (1.0); main.go is an entry point (0.5)
+	if strings.Join(paths, ",") != "db.go,main.go" {
+		t.Errorf("First batch = %v, want db.go, main.go", paths)
+	}
+	if first.FileCoverage != 0.6 || first.FileGain != 0.4 {
+		t.Errorf("First batch file coverage = %v (+%v), want 0.6 (+0.4)", first.FileCoverage, first.FileGain)
+	}
+	// util.go -> db.go
+	if first.ImportCoverage != 0.25 {
+		t.Errorf("First batch import coverage = %v, want 0.25", first.ImportCoverage)
+	}
+
+	last := report.Batches[1]
+	if last.FileCoverage != 1 || last.ImportCoverage != 1 {
+		t.Errorf("Last batch coverage = %v / %v, want full coverage", last.FileCoverage, last.ImportCoverage)
+	}
+
+	markdown := report.Markdown()
+	for _, want := range []string{"## Batch 1", "| `db.go` | 1.00 | 4 dependent(s) |", "| `main.go` | 0.50 | entry point |"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestBuildReport_MaxBatches(t *testing.T) {
+	report := BuildReport(createTestProvisionalGraph(), ReportOptions{BatchSize: 1, MaxBatches: 2})
+
+	if len(report.Batches) != 2 || report.Remaining != 2 {
+		t.Errorf("Batches = %d, remaining = %d; want 2 and 2", len(report.Batches), report.Remaining)
+	}
+}
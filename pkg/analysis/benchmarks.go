@@ -0,0 +1,519 @@
+/*
+# Module: pkg/analysis/benchmarks.go
+Module performance metrics and budgets from Go benchmarks.
+
+Go benchmark output (go test -bench -benchmem) is parsed and each benchmark
+attributed to a module: the file under test (parser_test.go benchmarks
+parser.go), else the module exporting the benchmarked name, else the only
+module of the package. Ingested runs are kept in .graphfs/benchmarks.json
+with the previous run as the baseline, so modules get latency and allocation
+triples with their change against the last run. Performance budgets in
+.graphfs/perf.yaml limit those metrics and regressions for modules selected
+by path or tag.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [budgets](./budgets.go) - Dependency budgets
+
+## Tags
+analysis, performance, benchmarks, budgets
+
+## Exports
+BenchmarkResult, BenchmarkRun, BenchmarkHistory, ModuleMetrics, PerfBudgetConfig, PerfBudget, PerfViolation, ParseBenchmarks, AttributeBenchmarks, LoadBenchmarkHistory, LoadPerfBudgetConfig, CheckPerfBudgets
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#benchmarks.go> a code:Module ;
+    code:name "pkg/analysis/benchmarks.go" ;
+    code:description "Module performance metrics and budgets from Go benchmarks" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go>, <./budgets.go> ;
+    code:exports <#BenchmarkResult>, <#BenchmarkRun>, <#BenchmarkHistory>, <#ModuleMetrics>, <#PerfBudgetConfig>, <#PerfBudget>, <#PerfViolation>, <#ParseBenchmarks>, <#AttributeBenchmarks>, <#LoadBenchmarkHistory>, <#LoadPerfBudgetConfig>, <#CheckPerfBudgets> ;
+    code:tags "analysis", "performance", "benchmarks", "budgets" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"gopkg.in/yaml.v3"
+)
+
+// Benchmark history and performance budget files, relative to the project root
+const (
+	DefaultBenchmarksFile  = ".graphfs/benchmarks.json"
+	DefaultPerfBudgetsFile = ".graphfs/perf.yaml"
+)
+
+// Predicates for module benchmark metrics. Changes are fractions against the
+// baseline run (0.1 is 10% more).
+const (
+	BenchNsPerOpPredicate      = "https://schema.codedoc.org/benchNsPerOp"
+	BenchBytesPerOpPredicate   = "https://schema.codedoc.org/benchBytesPerOp"
+	BenchAllocsPerOpPredicate  = "https://schema.codedoc.org/benchAllocsPerOp"
+	BenchNsChangePredicate     = "https://schema.codedoc.org/benchNsChange"
+	BenchBytesChangePredicate  = "https://schema.codedoc.org/benchBytesChange"
+	BenchAllocsChangePredicate = "https://schema.codedoc.org/benchAllocsChange"
+)
+
+// BenchmarkResult is one benchmark line of go test -bench output
+type BenchmarkResult struct {
+	Package     string  `json:"package"` // Import path from the "pkg:" line
+	Name        string  `json:"name"`    // Without the -GOMAXPROCS suffix
+	Iterations  int64   `json:"iterations"`
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  float64 `json:"bytesPerOp,omitempty"`
+	AllocsPerOp float64 `json:"allocsPerOp,omitempty"`
+}
+
+// key identifies a benchmark across runs
+func (r BenchmarkResult) key() string {
+	return r.Package + "." + r.Name
+}
+
+// BenchmarkRun is one ingested benchmark run, grouped by module path
+type BenchmarkRun struct {
+	Commit     string                       `json:"commit,omitempty"`
+	CreatedAt  time.Time                    `json:"created_at"`
+	Modules    map[string][]BenchmarkResult `json:"modules"`
+	Unassigned []BenchmarkResult            `json:"unassigned,omitempty"` // Benchmarks matching no module
+}
+
+// BenchmarkHistory is the latest run and the run before it
+type BenchmarkHistory struct {
+	Current  *BenchmarkRun `json:"current"`
+	Baseline *BenchmarkRun `json:"baseline,omitempty"`
+}
+
+// ModuleMetrics are a module's summed benchmark metrics. Changes compare
+// the benchmarks present in both runs, so adding a benchmark is not a
+// regression; they are nil without a baseline.
+type ModuleMetrics struct {
+	Module       string   `json:"module"`
+	Benchmarks   int      `json:"benchmarks"`
+	NsPerOp      float64  `json:"nsPerOp"`
+	BytesPerOp   float64  `json:"bytesPerOp"`
+	AllocsPerOp  float64  `json:"allocsPerOp"`
+	NsChange     *float64 `json:"nsChange,omitempty"`
+	BytesChange  *float64 `json:"bytesChange,omitempty"`
+	AllocsChange *float64 `json:"allocsChange,omitempty"`
+}
+
+// benchmarkLine matches "BenchmarkName-8  1000  1234 ns/op ..."
+var benchmarkLine = regexp.MustCompile(`^(Benchmark\S*?)(?:-\d+)?\s+(\d+)\s+(.*)$`)
+
+// ParseBenchmarks reads go test -bench output. Lines other than "pkg:" and
+// benchmark results are ignored, so full go test output can be piped in.
+func ParseBenchmarks(r io.Reader) ([]BenchmarkResult, error) {
+	var results []BenchmarkResult
+	pkg := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "pkg:"); ok {
+			pkg = strings.TrimSpace(rest)
+			continue
+		}
+		match := benchmarkLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		iterations, _ := strconv.ParseInt(match[2], 10, 64)
+		result := BenchmarkResult{Package: pkg, Name: match[1], Iterations: iterations}
+		fields := strings.Fields(match[3])
+		for i := 0; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				result.NsPerOp = value
+			case "B/op":
+				result.BytesPerOp = value
+			case "allocs/op":
+				result.AllocsPerOp = value
+			}
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read benchmarks: %w", err)
+	}
+	return results, nil
+}
+
+// benchmarkFunc matches benchmark function declarations in test files
+var benchmarkFunc = regexp.MustCompile(`(?m)^func (Benchmark\w*)\s*\(`)
+
+// AttributeBenchmarks groups results by the module they measure. Package
+// import paths are resolved against the module path in the root's go.mod.
+func AttributeBenchmarks(g *graph.Graph, results []BenchmarkResult) *BenchmarkRun {
+	run := &BenchmarkRun{
+		CreatedAt: time.Now().UTC(),
+		Modules:   make(map[string][]BenchmarkResult),
+	}
+
+	modulePath := goModulePath(g.Root)
+	packages := make(map[string][]string)
+	for p := range g.Modules {
+		packages[path.Dir(p)] = append(packages[path.Dir(p)], p)
+	}
+	for _, modules := range packages {
+		sort.Strings(modules)
+	}
+
+	definitions := make(map[string]map[string]string) // dir -> benchmark -> test file
+	for _, result := range results {
+		dir, ok := packageDir(modulePath, result.Package)
+		if !ok {
+			run.Unassigned = append(run.Unassigned, result)
+			continue
+		}
+		if _, ok := definitions[dir]; !ok {
+			definitions[dir] = benchmarkDefinitions(filepath.Join(g.Root, filepath.FromSlash(dir)))
+		}
+
+		module := benchmarkModule(g, packages[dir], dir, result.Name, definitions[dir])
+		if module == "" {
+			run.Unassigned = append(run.Unassigned, result)
+			continue
+		}
+		run.Modules[module] = append(run.Modules[module], result)
+	}
+	return run
+}
+
+// benchmarkModule picks the module a benchmark measures
+func benchmarkModule(g *graph.Graph, modules []string, dir, name string, definitions map[string]string) string {
+	top, _, _ := strings.Cut(name, "/")
+
+	// parser_test.go benchmarks parser.go
+	if testFile, ok := definitions[top]; ok {
+		candidate := path.Join(dir, strings.TrimSuffix(testFile, "_test.go")+".go")
+		if g.Modules[candidate] != nil {
+			return candidate
+		}
+	}
+
+	// BenchmarkParser_Parse benchmarks the module exporting Parser
+	subject, _, _ := strings.Cut(strings.TrimPrefix(top, "Benchmark"), "_")
+	if subject != "" {
+		for _, p := range modules {
+			for _, export := range g.Modules[p].Exports {
+				if strings.TrimLeft(strings.Trim(export, "<>"), "#") == subject {
+					return p
+				}
+			}
+		}
+	}
+
+	if len(modules) == 1 {
+		return modules[0]
+	}
+	return ""
+}
+
+// benchmarkDefinitions maps benchmark functions to the test files in a
+// directory that declare them
+func benchmarkDefinitions(dir string) map[string]string {
+	definitions := make(map[string]string)
+	files, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, match := range benchmarkFunc.FindAllStringSubmatch(string(content), -1) {
+			definitions[match[1]] = filepath.Base(file)
+		}
+	}
+	return definitions
+}
+
+// packageDir returns the directory of an import path within the Go module
+func packageDir(modulePath, importPath string) (string, bool) {
+	if modulePath == "" || importPath == "" {
+		return "", false
+	}
+	if importPath == modulePath {
+		return ".", true
+	}
+	dir, ok := strings.CutPrefix(importPath, modulePath+"/")
+	return dir, ok
+}
+
+// goModulePath reads the module path from go.mod in root
+func goModulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// LoadBenchmarkHistory reads a benchmarks file; a missing file is an empty
+// history
+func LoadBenchmarkHistory(filePath string) (*BenchmarkHistory, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return &BenchmarkHistory{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmarks: %w", err)
+	}
+
+	var history BenchmarkHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse benchmarks: %w", err)
+	}
+	return &history, nil
+}
+
+// Record makes run the current run, keeping the previous one as the baseline
+func (h *BenchmarkHistory) Record(run *BenchmarkRun) {
+	if h.Current != nil {
+		h.Baseline = h.Current
+	}
+	h.Current = run
+}
+
+// Save writes the history to a file
+func (h *BenchmarkHistory) Save(filePath string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal benchmarks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write benchmarks: %w", err)
+	}
+	return nil
+}
+
+// Metrics returns the metrics of each module in the current run, sorted by
+// module path
+func (h *BenchmarkHistory) Metrics() []*ModuleMetrics {
+	if h.Current == nil {
+		return nil
+	}
+
+	baseline := make(map[string]BenchmarkResult)
+	if h.Baseline != nil {
+		for _, results := range h.Baseline.Modules {
+			for _, r := range results {
+				baseline[r.key()] = r
+			}
+		}
+	}
+
+	metrics := make([]*ModuleMetrics, 0, len(h.Current.Modules))
+	for module, results := range h.Current.Modules {
+		m := &ModuleMetrics{Module: module, Benchmarks: len(results)}
+		var current, previous BenchmarkResult
+		compared := false
+		for _, r := range results {
+			m.NsPerOp += r.NsPerOp
+			m.BytesPerOp += r.BytesPerOp
+			m.AllocsPerOp += r.AllocsPerOp
+			if base, ok := baseline[r.key()]; ok {
+				compared = true
+				current.NsPerOp += r.NsPerOp
+				current.BytesPerOp += r.BytesPerOp
+				current.AllocsPerOp += r.AllocsPerOp
+				previous.NsPerOp += base.NsPerOp
+				previous.BytesPerOp += base.BytesPerOp
+				previous.AllocsPerOp += base.AllocsPerOp
+			}
+		}
+		if compared {
+			m.NsChange = relativeChange(previous.NsPerOp, current.NsPerOp)
+			m.BytesChange = relativeChange(previous.BytesPerOp, current.BytesPerOp)
+			m.AllocsChange = relativeChange(previous.AllocsPerOp, current.AllocsPerOp)
+		}
+		metrics = append(metrics, m)
+	}
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Module < metrics[j].Module })
+	return metrics
+}
+
+// relativeChange returns (after-before)/before; growth from zero counts as
+// a 100% increase
+func relativeChange(before, after float64) *float64 {
+	change := 0.0
+	switch {
+	case before > 0:
+		change = (after - before) / before
+	case after > 0:
+		change = 1
+	}
+	return &change
+}
+
+// AddTriples adds benchmark metric triples to the modules of the current
+// run found in the graph, returning the number of triples added
+func (h *BenchmarkHistory) AddTriples(g *graph.Graph) int {
+	if g.Store == nil {
+		return 0
+	}
+
+	before := g.Store.Count()
+	for _, m := range h.Metrics() {
+		module := g.Modules[m.Module]
+		if module == nil {
+			continue
+		}
+		_ = g.Store.Add(module.URI, BenchNsPerOpPredicate, formatMetric(m.NsPerOp))
+		_ = g.Store.Add(module.URI, BenchBytesPerOpPredicate, formatMetric(m.BytesPerOp))
+		_ = g.Store.Add(module.URI, BenchAllocsPerOpPredicate, formatMetric(m.AllocsPerOp))
+		if m.NsChange != nil {
+			_ = g.Store.Add(module.URI, BenchNsChangePredicate, strconv.FormatFloat(*m.NsChange, 'f', 3, 64))
+			_ = g.Store.Add(module.URI, BenchBytesChangePredicate, strconv.FormatFloat(*m.BytesChange, 'f', 3, 64))
+			_ = g.Store.Add(module.URI, BenchAllocsChangePredicate, strconv.FormatFloat(*m.AllocsChange, 'f', 3, 64))
+		}
+	}
+	return g.Store.Count() - before
+}
+
+// formatMetric formats a per-op metric without trailing zeros
+func formatMetric(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// PerfBudgetConfig is the contents of a performance budgets file
+type PerfBudgetConfig struct {
+	Budgets []PerfBudget `yaml:"budgets" json:"budgets"`
+}
+
+// PerfBudget limits the benchmark metrics of modules with a tag or matching
+// a path pattern (a module path, a directory, or a directory followed by
+// "/..."). Regression limits are fractions against the baseline run; zero
+// limits are unset.
+type PerfBudget struct {
+	Name   string `yaml:"name,omitempty" json:"name,omitempty"`
+	Tag    string `yaml:"tag,omitempty" json:"tag,omitempty"`
+	Module string `yaml:"module,omitempty" json:"module,omitempty"`
+
+	MaxNsPerOp     float64 `yaml:"max_ns_per_op,omitempty" json:"maxNsPerOp,omitempty"`
+	MaxBytesPerOp  float64 `yaml:"max_bytes_per_op,omitempty" json:"maxBytesPerOp,omitempty"`
+	MaxAllocsPerOp float64 `yaml:"max_allocs_per_op,omitempty" json:"maxAllocsPerOp,omitempty"`
+
+	MaxNsRegression     float64 `yaml:"max_ns_regression,omitempty" json:"maxNsRegression,omitempty"`
+	MaxBytesRegression  float64 `yaml:"max_bytes_regression,omitempty" json:"maxBytesRegression,omitempty"`
+	MaxAllocsRegression float64 `yaml:"max_allocs_regression,omitempty" json:"maxAllocsRegression,omitempty"`
+}
+
+// PerfViolation is a module exceeding a performance budget
+type PerfViolation struct {
+	Module   string      `json:"module"`
+	Budget   *PerfBudget `json:"budget"`
+	Exceeded []string    `json:"exceeded"` // Descriptions of exceeded limits
+}
+
+// LoadPerfBudgetConfig reads and validates a performance budgets file
+func LoadPerfBudgetConfig(filePath string) (*PerfBudgetConfig, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read performance budgets: %w", err)
+	}
+
+	var config PerfBudgetConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse performance budgets: %w", err)
+	}
+
+	for i, budget := range config.Budgets {
+		if budget.Tag == "" && budget.Module == "" {
+			return nil, fmt.Errorf("performance budget %d: missing tag or module", i)
+		}
+	}
+
+	return &config, nil
+}
+
+// matches reports whether a budget applies to a module
+func (b *PerfBudget) matches(module *graph.Module) bool {
+	if b.Tag != "" && !slices.Contains(module.Tags, b.Tag) {
+		return false
+	}
+	if b.Module == "" {
+		return true
+	}
+	pattern := strings.Trim(path.Clean(b.Module), "/")
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return strings.HasPrefix(module.Path, prefix+"/")
+	}
+	return module.Path == pattern || path.Dir(module.Path) == pattern
+}
+
+// exceeded describes the limits of b that m exceeds
+func (b *PerfBudget) exceeded(m *ModuleMetrics) []string {
+	var exceeded []string
+	limit := func(name string, value, max float64) {
+		if max > 0 && value > max {
+			exceeded = append(exceeded, fmt.Sprintf("%s %s > %s", name, formatMetric(value), formatMetric(max)))
+		}
+	}
+	regression := func(name string, change *float64, max float64) {
+		if max > 0 && change != nil && *change > max {
+			exceeded = append(exceeded, fmt.Sprintf("%s +%.1f%% > +%.1f%%", name, *change*100, max*100))
+		}
+	}
+	limit("ns/op", m.NsPerOp, b.MaxNsPerOp)
+	limit("B/op", m.BytesPerOp, b.MaxBytesPerOp)
+	limit("allocs/op", m.AllocsPerOp, b.MaxAllocsPerOp)
+	regression("ns/op", m.NsChange, b.MaxNsRegression)
+	regression("B/op", m.BytesChange, b.MaxBytesRegression)
+	regression("allocs/op", m.AllocsChange, b.MaxAllocsRegression)
+	return exceeded
+}
+
+// CheckPerfBudgets reports every budget exceeded by a module of the current
+// run. Modules without benchmarks are not checked.
+func CheckPerfBudgets(g *graph.Graph, history *BenchmarkHistory, config *PerfBudgetConfig) []*PerfViolation {
+	var violations []*PerfViolation
+	for _, m := range history.Metrics() {
+		module := g.Modules[m.Module]
+		if module == nil {
+			continue
+		}
+		for i := range config.Budgets {
+			budget := &config.Budgets[i]
+			if !budget.matches(module) {
+				continue
+			}
+			if exceeded := budget.exceeded(m); len(exceeded) > 0 {
+				violations = append(violations, &PerfViolation{Module: m.Module, Budget: budget, Exceeded: exceeded})
+			}
+		}
+	}
+	return violations
+}
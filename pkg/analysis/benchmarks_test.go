@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+const testBenchOutput = `goos: linux
+goarch: amd64
+pkg: example.com/app/store
+cpu: Test CPU
+BenchmarkGet-8          	 1000000	      1000 ns/op	     100 B/op	      10 allocs/op
+BenchmarkCache_Put/small-8	  500000	      2000 ns/op	      50 B/op	       2 allocs/op
+BenchmarkOther-8        	 1000000	       300 ns/op
+PASS
+ok  	example.com/app/store	3.2s
+pkg: example.com/vendored/x
+BenchmarkX-8 	100	5 ns/op
+`
+
+// createTestBenchGraph builds a Go module with store/db.go (tested by
+// db_test.go) and store/cache.go (exporting Cache)
+func createTestBenchGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	g := graph.NewGraph(t.TempDir(), store.NewTripleStore())
+	files := map[string]string{
+		"go.mod":              "module example.com/app\n",
+		"store/db_test.go":    "package store\n\nfunc BenchmarkGet(b *testing.B) {}\nfunc BenchmarkOther(b *testing.B) {}\n",
+		"store/cache_test.go": "package store\n\nfunc BenchmarkCache_Put(b *testing.B) {}\n",
+	}
+	for p, content := range files {
+		full := filepath.Join(g.Root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.AddModule(&graph.Module{Path: "store/db.go", URI: "<#store/db.go>", Tags: []string{"hot-path"}})
+	g.AddModule(&graph.Module{Path: "store/cache.go", URI: "<#store/cache.go>", Exports: []string{"#Cache"}})
+	return g
+}
+
+func TestParseBenchmarks(t *testing.T) {
+	results, err := ParseBenchmarks(strings.NewReader(testBenchOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Got %d results, want 4: %+v", len(results), results)
+	}
+
+	put := results[1]
+	if put.Package != "example.com/app/store" || put.Name != "BenchmarkCache_Put/small" ||
+		put.NsPerOp != 2000 || put.BytesPerOp != 50 || put.AllocsPerOp != 2 {
+		t.Errorf("Sub-benchmark = %+v", put)
+	}
+	if other := results[2]; other.NsPerOp != 300 || other.AllocsPerOp != 0 {
+		t.Errorf("Benchmark without -benchmem = %+v", other)
+	}
+}
+
+func TestAttributeBenchmarks(t *testing.T) {
+	g := createTestBenchGraph(t)
+	results, _ := ParseBenchmarks(strings.NewReader(testBenchOutput))
+
+	run := AttributeBenchmarks(g, results)
+	if got := len(run.Modules["store/db.go"]); got != 2 {
+		t.Errorf("db.go benchmarks = %d, want 2 (from db_test.go)", got)
+	}
+	if got := run.Modules["store/cache.go"]; len(got) != 1 || got[0].Name != "BenchmarkCache_Put/small" {
+		t.Errorf("cache.go benchmarks = %+v, want the Cache benchmark", got)
+	}
+	if len(run.Unassigned) != 1 || run.Unassigned[0].Package != "example.com/vendored/x" {
+		t.Errorf("Unassigned = %+v, want the benchmark outside the module", run.Unassigned)
+	}
+}
+
+func TestBenchmarkHistory_MetricsAndBudgets(t *testing.T) {
+	g := createTestBenchGraph(t)
+	results, _ := ParseBenchmarks(strings.NewReader(testBenchOutput))
+
+	history := &BenchmarkHistory{}
+	history.Record(AttributeBenchmarks(g, results))
+
+	// The next run allocates 20% more in BenchmarkGet and adds a benchmark
+	next := strings.Replace(testBenchOutput, "10 allocs/op", "14 allocs/op", 1) +
+		"pkg: example.com/app/store\nBenchmarkNew-8 100 50 ns/op 0 B/op 9 allocs/op\n"
+	results, _ = ParseBenchmarks(strings.NewReader(next))
+	history.Record(AttributeBenchmarks(g, results))
+
+	metrics := history.Metrics()
+	if len(metrics) != 2 || metrics[1].Module != "store/db.go" {
+		t.Fatalf("Metrics = %+v", metrics)
+	}
+	db := metrics[1]
+	if db.AllocsPerOp != 14 || db.AllocsChange == nil || *db.AllocsChange != 0.4 {
+		t.Errorf("db.go allocs = %v (change %v), want 14 and +40%% (BenchmarkNew is new)", db.AllocsPerOp, db.AllocsChange)
+	}
+
+	if added := history.AddTriples(g); added != 12 {
+		t.Errorf("AddTriples added %d, want 12", added)
+	}
+
+	config := &PerfBudgetConfig{Budgets: []PerfBudget{
+		{Tag: "hot-path", MaxAllocsRegression: 0.1},
+		{Module: "store/...", MaxNsPerOp: 10000},
+	}}
+	violations := CheckPerfBudgets(g, history, config)
+	if len(violations) != 1 || violations[0].Module != "store/db.go" || !strings.Contains(violations[0].Exceeded[0], "+40.0%") {
+		t.Errorf("Violations = %+v, want the db.go allocation regression", violations)
+	}
+
+	path := filepath.Join(t.TempDir(), "benchmarks.json")
+	if err := history.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBenchmarkHistory(path)
+	if err != nil || loaded.Baseline == nil || len(loaded.Current.Modules) != 2 {
+		t.Errorf("Loaded history = %+v, %v", loaded, err)
+	}
+}
+
+func TestLoadPerfBudgetConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf.yaml")
+	if err := os.WriteFile(path, []byte("budgets:\n  - max_ns_per_op: 10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPerfBudgetConfig(path); err == nil {
+		t.Error("Expected error for a budget without a tag or module")
+	}
+}
@@ -0,0 +1,275 @@
+/*
+# Module: pkg/analysis/budgets.go
+Dependency budgets per package.
+
+Packages (directories) declare budgets in .graphfs/budgets.yaml: a maximum
+number of direct dependency packages, a maximum transitive closure and
+layers they must not depend on. The analyzer reports each package's
+consumption, the limits it exceeds and deltas against a baseline.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, dependencies, budgets
+
+## Exports
+BudgetConfig, PackageBudget, BudgetReport, BudgetUsage, BudgetDelta, LoadBudgetConfig, AnalyzeBudgets, CompareBudgets
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#budgets.go> a code:Module ;
+    code:name "pkg/analysis/budgets.go" ;
+    code:description "Dependency budgets per package" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go> ;
+    code:exports <#BudgetConfig>, <#PackageBudget>, <#BudgetReport>, <#BudgetUsage>, <#BudgetDelta>, <#LoadBudgetConfig>, <#AnalyzeBudgets>, <#CompareBudgets> ;
+    code:tags "analysis", "dependencies", "budgets" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBudgetsFile is the budgets file relative to the project root
+const DefaultBudgetsFile = ".graphfs/budgets.yaml"
+
+// BudgetConfig is the contents of a budgets file
+type BudgetConfig struct {
+	Budgets []PackageBudget `yaml:"budgets" json:"budgets"`
+}
+
+// PackageBudget limits the dependencies of matching packages. Package is a
+// directory relative to the root ("." for the root), or a directory followed
+// by "/..." to match it and every package below it. Zero limits are unset.
+type PackageBudget struct {
+	Package         string   `yaml:"package" json:"package"`
+	MaxDirectDeps   int      `yaml:"max_direct_deps,omitempty" json:"maxDirectDeps,omitempty"`
+	MaxTransitive   int      `yaml:"max_transitive,omitempty" json:"maxTransitive,omitempty"`
+	ForbiddenLayers []string `yaml:"forbidden_layers,omitempty" json:"forbiddenLayers,omitempty"`
+}
+
+// BudgetReport contains the budget usage of every package with a budget
+type BudgetReport struct {
+	Packages []*BudgetUsage `json:"packages"`
+}
+
+// BudgetUsage is a package's consumption of its budget
+type BudgetUsage struct {
+	Package        string         `json:"package"`
+	Budget         *PackageBudget `json:"budget"`
+	Modules        int            `json:"modules"`
+	DirectDeps     []string       `json:"directDeps"`              // Packages depended on directly
+	TransitiveDeps int            `json:"transitiveDeps"`          // Packages in the transitive closure
+	ForbiddenDeps  []string       `json:"forbiddenDeps,omitempty"` // "from -> to (layer)" edges into forbidden layers
+	Exceeded       []string       `json:"exceeded,omitempty"`      // Descriptions of exceeded limits
+	Delta          *BudgetDelta   `json:"delta,omitempty"`         // Change against a baseline
+}
+
+// BudgetDelta is the change in a package's usage against a baseline
+type BudgetDelta struct {
+	DirectDeps     int  `json:"directDeps"`
+	TransitiveDeps int  `json:"transitiveDeps"`
+	ForbiddenDeps  int  `json:"forbiddenDeps"`
+	New            bool `json:"new,omitempty"` // The package has no baseline usage
+}
+
+// LoadBudgetConfig reads and validates a budgets file
+func LoadBudgetConfig(filePath string) (*BudgetConfig, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budgets: %w", err)
+	}
+
+	var config BudgetConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse budgets: %w", err)
+	}
+
+	for i, budget := range config.Budgets {
+		if budget.Package == "" {
+			return nil, fmt.Errorf("budget %d: missing package", i)
+		}
+		if budget.MaxDirectDeps < 0 || budget.MaxTransitive < 0 {
+			return nil, fmt.Errorf("budget %s: limits must not be negative", budget.Package)
+		}
+	}
+
+	return &config, nil
+}
+
+// matches reports whether a budget applies to a package directory
+func (b *PackageBudget) matches(pkg string) bool {
+	pattern := strings.Trim(path.Clean(b.Package), "/")
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+	}
+	if pattern == "..." {
+		return true
+	}
+	return pkg == pattern
+}
+
+// AnalyzeBudgets measures each package against the first budget matching it.
+// Dependencies are counted as packages: a package depending on three files in
+// one other directory uses one direct dependency.
+func AnalyzeBudgets(g *graph.Graph, config *BudgetConfig) *BudgetReport {
+	report := &BudgetReport{Packages: make([]*BudgetUsage, 0)}
+
+	packages := make(map[string][]string)
+	for modulePath := range g.Modules {
+		pkg := path.Dir(modulePath)
+		packages[pkg] = append(packages[pkg], modulePath)
+	}
+
+	names := make([]string, 0, len(packages))
+	for pkg := range packages {
+		names = append(names, pkg)
+	}
+	sort.Strings(names)
+
+	for _, pkg := range names {
+		var budget *PackageBudget
+		for i := range config.Budgets {
+			if config.Budgets[i].matches(pkg) {
+				budget = &config.Budgets[i]
+				break
+			}
+		}
+		if budget == nil {
+			continue
+		}
+		report.Packages = append(report.Packages, measureBudget(g, pkg, packages[pkg], budget))
+	}
+
+	return report
+}
+
+// measureBudget computes a package's usage and the limits it exceeds
+func measureBudget(g *graph.Graph, pkg string, modules []string, budget *PackageBudget) *BudgetUsage {
+	usage := &BudgetUsage{
+		Package:    pkg,
+		Budget:     budget,
+		Modules:    len(modules),
+		DirectDeps: make([]string, 0),
+	}
+	sort.Strings(modules)
+
+	direct := make(map[string]bool)
+	for _, modulePath := range modules {
+		for _, edge := range g.Modules[modulePath].DependencyEdges() {
+			dep := edge.Target
+			depModule, ok := g.Modules[dep]
+			if !ok {
+				continue
+			}
+			if depPkg := path.Dir(dep); depPkg != pkg {
+				direct[depPkg] = true
+			}
+			if depModule.Layer != "" && slices.Contains(budget.ForbiddenLayers, depModule.Layer) {
+				usage.ForbiddenDeps = append(usage.ForbiddenDeps,
+					fmt.Sprintf("%s -> %s (%s)", modulePath, dep, depModule.Layer))
+			}
+		}
+	}
+	usage.DirectDeps = sortedKeys(direct)
+
+	// Transitive closure over modules, counted as packages
+	visited := make(map[string]bool)
+	queue := append([]string(nil), modules...)
+	for _, modulePath := range modules {
+		visited[modulePath] = true
+	}
+	transitive := make(map[string]bool)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Modules[current].DependencyEdges() {
+			dep := edge.Target
+			if visited[dep] {
+				continue
+			}
+			if _, ok := g.Modules[dep]; !ok {
+				continue
+			}
+			visited[dep] = true
+			if depPkg := path.Dir(dep); depPkg != pkg {
+				transitive[depPkg] = true
+			}
+			queue = append(queue, dep)
+		}
+	}
+	usage.TransitiveDeps = len(transitive)
+
+	if budget.MaxDirectDeps > 0 && len(usage.DirectDeps) > budget.MaxDirectDeps {
+		usage.Exceeded = append(usage.Exceeded,
+			fmt.Sprintf("direct dependencies %d/%d", len(usage.DirectDeps), budget.MaxDirectDeps))
+	}
+	if budget.MaxTransitive > 0 && usage.TransitiveDeps > budget.MaxTransitive {
+		usage.Exceeded = append(usage.Exceeded,
+			fmt.Sprintf("transitive dependencies %d/%d", usage.TransitiveDeps, budget.MaxTransitive))
+	}
+	if len(usage.ForbiddenDeps) > 0 {
+		usage.Exceeded = append(usage.Exceeded,
+			fmt.Sprintf("%d dependencies on forbidden layers", len(usage.ForbiddenDeps)))
+	}
+
+	return usage
+}
+
+// OverBudget reports whether the package exceeds any limit
+func (u *BudgetUsage) OverBudget() bool {
+	return len(u.Exceeded) > 0
+}
+
+// OverBudget returns the packages exceeding a limit
+func (r *BudgetReport) OverBudget() []*BudgetUsage {
+	var over []*BudgetUsage
+	for _, usage := range r.Packages {
+		if usage.OverBudget() {
+			over = append(over, usage)
+		}
+	}
+	return over
+}
+
+// CompareBudgets sets the delta of each package in current against its
+// usage in base, for trend reporting on pull requests
+func CompareBudgets(base, current *BudgetReport) {
+	baseUsage := make(map[string]*BudgetUsage, len(base.Packages))
+	for _, usage := range base.Packages {
+		baseUsage[usage.Package] = usage
+	}
+
+	for _, usage := range current.Packages {
+		previous, ok := baseUsage[usage.Package]
+		if !ok {
+			usage.Delta = &BudgetDelta{
+				DirectDeps:     len(usage.DirectDeps),
+				TransitiveDeps: usage.TransitiveDeps,
+				ForbiddenDeps:  len(usage.ForbiddenDeps),
+				New:            true,
+			}
+			continue
+		}
+		usage.Delta = &BudgetDelta{
+			DirectDeps:     len(usage.DirectDeps) - len(previous.DirectDeps),
+			TransitiveDeps: usage.TransitiveDeps - previous.TransitiveDeps,
+			ForbiddenDeps:  len(usage.ForbiddenDeps) - len(previous.ForbiddenDeps),
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzeBudgets(t *testing.T) {
+	g := createTestGraphForImpact()
+
+	config := &BudgetConfig{Budgets: []PackageBudget{
+		{Package: "handlers", MaxDirectDeps: 1, MaxTransitive: 5, ForbiddenLayers: []string{"core"}},
+		{Package: "services/...", MaxTransitive: 1},
+	}}
+	report := AnalyzeBudgets(g, config)
+
+	if len(report.Packages) != 2 {
+		t.Fatalf("Expected 2 packages with budgets, got %d", len(report.Packages))
+	}
+
+	handlers := report.Packages[0]
+	if handlers.Package != "handlers" || len(handlers.DirectDeps) != 1 || handlers.TransitiveDeps != 3 {
+		t.Errorf("handlers usage = %+v, want 1 direct and 3 transitive packages", handlers)
+	}
+	if handlers.OverBudget() {
+		t.Errorf("handlers should be within budget, exceeded %v", handlers.Exceeded)
+	}
+
+	services := report.Packages[1]
+	if services.TransitiveDeps != 2 || !services.OverBudget() {
+		t.Errorf("services usage = %+v, want 2 transitive packages over a budget of 1", services)
+	}
+	if over := report.OverBudget(); len(over) != 1 || over[0].Package != "services" {
+		t.Errorf("OverBudget = %v, want services", over)
+	}
+}
+
+func TestAnalyzeBudgets_ForbiddenLayers(t *testing.T) {
+	g := createTestGraphForImpact()
+
+	report := AnalyzeBudgets(g, &BudgetConfig{Budgets: []PackageBudget{
+		{Package: "utils", ForbiddenLayers: []string{"core"}},
+	}})
+	utils := report.Packages[0]
+	if len(utils.ForbiddenDeps) != 2 || !utils.OverBudget() {
+		t.Errorf("utils usage = %+v, want 2 forbidden dependencies", utils)
+	}
+}
+
+func TestCompareBudgets(t *testing.T) {
+	g := createTestGraphForImpact()
+	config := &BudgetConfig{Budgets: []PackageBudget{{Package: "..."}}}
+	base := AnalyzeBudgets(g, config)
+
+	g.Modules["handlers/api.go"].Dependencies = append(g.Modules["handlers/api.go"].Dependencies, "core/core.go")
+	delete(g.Modules, "isolated/module.go")
+	g.Modules["cmd/main.go"] = g.Modules["handlers/api.go"]
+	current := AnalyzeBudgets(g, config)
+	CompareBudgets(base, current)
+
+	for _, usage := range current.Packages {
+		switch usage.Package {
+		case "handlers":
+			if usage.Delta.DirectDeps != 1 || usage.Delta.TransitiveDeps != 0 {
+				t.Errorf("handlers delta = %+v, want +1 direct", usage.Delta)
+			}
+		case "cmd":
+			if !usage.Delta.New {
+				t.Errorf("cmd delta = %+v, want new", usage.Delta)
+			}
+		}
+	}
+}
+
+func TestLoadBudgetConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budgets.yaml")
+	content := "budgets:\n  - package: pkg/api\n    max_direct_deps: 5\n    forbidden_layers: [data]\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadBudgetConfig(path)
+	if err != nil {
+		t.Fatalf("LoadBudgetConfig failed: %v", err)
+	}
+	if len(config.Budgets) != 1 || config.Budgets[0].MaxDirectDeps != 5 || config.Budgets[0].ForbiddenLayers[0] != "data" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	if err := os.WriteFile(path, []byte("budgets:\n  - max_direct_deps: 5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBudgetConfig(path); err == nil {
+		t.Error("Expected error for budget without package")
+	}
+}
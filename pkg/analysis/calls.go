@@ -0,0 +1,335 @@
+/*
+# Module: pkg/analysis/calls.go
+Symbol call graph analysis.
+
+Builds a graph of calls between symbols from code:calls triples: those a
+LinkedDoc header declares on a module or on one of its symbols (such as
+<#UserService.CreateUser>), and the path.Func calls Go source extraction
+records for a module. Symbols are identified as path#Symbol, or by the
+module path for calls made at module level. Traces list the callers and
+callees of a symbol, direct and transitive, up to a depth.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [../pathkey](../pathkey/pathkey.go) - Canonical module paths
+
+## Tags
+analysis, calls, call-graph, symbols
+
+## Exports
+CallGraph, BuildCallGraph, CallDirection, CallOptions, CallTrace, CallSite
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#calls.go> a code:Module ;
+    code:name "pkg/analysis/calls.go" ;
+    code:description "Symbol call graph analysis" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go>, <../pathkey/pathkey.go> ;
+    code:exports <#CallGraph>, <#BuildCallGraph>, <#CallDirection>, <#CallOptions>, <#CallTrace>, <#CallSite> ;
+    code:tags "analysis", "calls", "call-graph", "symbols" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// CallGraph is the graph of calls between symbols
+type CallGraph struct {
+	Callees map[string][]string // Symbols each symbol calls, sorted
+	Callers map[string][]string // Symbols calling each symbol, sorted
+	modules map[string]string   // Module of each symbol in the graph
+}
+
+// CallDirection selects which side of a symbol a trace follows
+type CallDirection string
+
+const (
+	CallersAndCallees CallDirection = "both"
+	CallersOnly       CallDirection = "callers"
+	CalleesOnly       CallDirection = "callees"
+)
+
+// CallOptions configures a call trace
+type CallOptions struct {
+	Direction CallDirection // Callers, callees or both (default: both)
+	Depth     int           // Calls followed from the symbol (0 = all)
+}
+
+// CallTrace lists the callers and callees of a symbol
+type CallTrace struct {
+	Symbol  string     `json:"symbol"`
+	Module  string     `json:"module,omitempty"`
+	Callers []CallSite `json:"callers"` // By depth, then symbol
+	Callees []CallSite `json:"callees"` // By depth, then symbol
+}
+
+// CallSite is a symbol reached from the traced symbol
+type CallSite struct {
+	Symbol string `json:"symbol"`
+	Module string `json:"module,omitempty"` // Empty outside the graph, e.g. fmt.Errorf
+	Depth  int    `json:"depth"`            // 1 for direct calls
+	Via    string `json:"via,omitempty"`    // Symbol one call closer to the traced symbol
+}
+
+// BuildCallGraph collects the calls of every module in the graph
+func BuildCallGraph(g *graph.Graph) *CallGraph {
+	cg := &CallGraph{
+		Callees: make(map[string][]string),
+		Callers: make(map[string][]string),
+		modules: make(map[string]string),
+	}
+	r := newCallResolver(g)
+
+	paths := make([]string, 0, len(g.Modules))
+	for p := range g.Modules {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	seen := make(map[[2]string]bool)
+	for _, p := range paths {
+		for _, triple := range g.FileTriples(p) {
+			if !strings.HasSuffix(triple.Predicate, "calls") {
+				continue
+			}
+			caller, callerModule := r.resolve(triple.Subject, p)
+			callee, calleeModule := r.resolve(triple.Object, p)
+			if caller == "" || callee == "" || caller == callee || seen[[2]string{caller, callee}] {
+				continue
+			}
+			seen[[2]string{caller, callee}] = true
+			cg.Callees[caller] = append(cg.Callees[caller], callee)
+			cg.Callers[callee] = append(cg.Callers[callee], caller)
+			if callerModule != "" {
+				cg.modules[caller] = callerModule
+			}
+			if calleeModule != "" {
+				cg.modules[callee] = calleeModule
+			}
+		}
+	}
+	for _, symbols := range cg.Callees {
+		sort.Strings(symbols)
+	}
+	for _, symbols := range cg.Callers {
+		sort.Strings(symbols)
+	}
+	return cg
+}
+
+// Symbols returns every symbol that calls or is called, sorted
+func (cg *CallGraph) Symbols() []string {
+	symbols := make([]string, 0, len(cg.Callees)+len(cg.Callers))
+	for symbol := range cg.Callees {
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range cg.Callers {
+		if _, ok := cg.Callees[symbol]; !ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Module returns the module path of a symbol, or "" outside the graph
+func (cg *CallGraph) Module(symbol string) string {
+	return cg.modules[symbol]
+}
+
+// Lookup finds the symbol a name refers to: a full path#Symbol, a module
+// path, or a symbol name such as CheckPermission or
+// AuthService.CheckPermission when it is unambiguous
+func (cg *CallGraph) Lookup(name string) (string, error) {
+	symbols := cg.Symbols()
+	var matches []string
+	for _, symbol := range symbols {
+		if symbol == name {
+			return symbol, nil
+		}
+		_, short, ok := strings.Cut(symbol, "#")
+		if !ok {
+			short = symbol
+		}
+		if short == name || strings.HasSuffix(short, "."+name) {
+			matches = append(matches, symbol)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no calls to or from %s", name)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%s is ambiguous: %s (use path#Symbol)", name, strings.Join(matches, ", "))
+}
+
+// Trace lists the callers and callees of a symbol up to opts.Depth calls
+// away. A symbol reached several ways is listed once, at its least depth.
+func (cg *CallGraph) Trace(name string, opts CallOptions) (*CallTrace, error) {
+	symbol, err := cg.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &CallTrace{
+		Symbol:  symbol,
+		Module:  cg.modules[symbol],
+		Callers: make([]CallSite, 0),
+		Callees: make([]CallSite, 0),
+	}
+	switch opts.Direction {
+	case "", CallersAndCallees:
+		trace.Callers = cg.walk(symbol, cg.Callers, opts.Depth)
+		trace.Callees = cg.walk(symbol, cg.Callees, opts.Depth)
+	case CallersOnly:
+		trace.Callers = cg.walk(symbol, cg.Callers, opts.Depth)
+	case CalleesOnly:
+		trace.Callees = cg.walk(symbol, cg.Callees, opts.Depth)
+	default:
+		return nil, fmt.Errorf("unknown direction %q (use callers, callees or both)", opts.Direction)
+	}
+	return trace, nil
+}
+
+// walk visits the symbols reachable from symbol breadth first
+func (cg *CallGraph) walk(symbol string, next map[string][]string, maxDepth int) []CallSite {
+	sites := make([]CallSite, 0)
+	visited := map[string]bool{symbol: true}
+	frontier := []string{symbol}
+	for depth := 1; len(frontier) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		var reached []string
+		for _, from := range frontier {
+			for _, to := range next[from] {
+				if visited[to] {
+					continue
+				}
+				visited[to] = true
+				reached = append(reached, to)
+				site := CallSite{Symbol: to, Module: cg.modules[to], Depth: depth}
+				if depth > 1 {
+					site.Via = from
+				}
+				sites = append(sites, site)
+			}
+		}
+		frontier = reached
+	}
+	sort.SliceStable(sites, func(i, j int) bool {
+		if sites[i].Depth != sites[j].Depth {
+			return sites[i].Depth < sites[j].Depth
+		}
+		return sites[i].Symbol < sites[j].Symbol
+	})
+	return sites
+}
+
+// callResolver maps the terms of code:calls triples to symbols
+type callResolver struct {
+	g        *graph.Graph
+	modules  map[string]string   // Module paths by module and component URI, unbracketed
+	packages map[string][]string // Module paths by directory, sorted
+	base     string              // Base IRI of module URIs ("" when written as fragments)
+}
+
+func newCallResolver(g *graph.Graph) *callResolver {
+	r := &callResolver{
+		g:        g,
+		modules:  make(map[string]string),
+		packages: make(map[string][]string),
+	}
+	for p, module := range g.Modules {
+		uri := strings.Trim(module.URI, "<>")
+		r.modules[uri] = p
+		if strings.Contains(uri, "://") && strings.HasSuffix(uri, "/"+p) {
+			r.base = strings.TrimSuffix(uri, p)
+		}
+		for _, component := range module.Components {
+			r.modules[strings.Trim(component.URI, "<>")] = p
+		}
+		dir := path.Dir(p)
+		r.packages[dir] = append(r.packages[dir], p)
+	}
+	for _, paths := range r.packages {
+		sort.Strings(paths)
+	}
+	return r
+}
+
+// resolve returns the symbol a term written in filePath refers to and its
+// module ("" outside the graph). Module URIs stand for the module itself;
+// terms with a fragment name a symbol of the module before it, or of
+// filePath when there is none.
+func (r *callResolver) resolve(term, filePath string) (string, string) {
+	inner := strings.Trim(term, "<>")
+	if p, ok := r.modules[inner]; ok {
+		return p, p
+	}
+	ref, symbol, ok := strings.Cut(inner, "#")
+	if !ok {
+		return r.resolveGoCall(inner)
+	}
+	if symbol == "" {
+		return "", ""
+	}
+
+	modulePath := ref
+	switch {
+	case ref == "":
+		modulePath = filePath
+	case r.modules[ref] != "":
+		modulePath = r.modules[ref]
+	case strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../"):
+		modulePath = pathkey.Canonical(path.Join(path.Dir(filePath), ref))
+	case r.base != "" && strings.HasPrefix(ref, r.base):
+		modulePath = strings.TrimPrefix(ref, r.base)
+	}
+	if module := r.g.GetModule(modulePath); module != nil {
+		return module.Path + "#" + symbol, module.Path
+	}
+	return modulePath + "#" + symbol, ""
+}
+
+// resolveGoCall resolves a call extracted from Go source, such as
+// github.com/acme/app/pkg/auth.Login, to the module of the package's
+// directory that exports the function. Calls into other packages, such as
+// fmt.Errorf, are kept as written.
+func (r *callResolver) resolveGoCall(call string) (string, string) {
+	slash := strings.LastIndex(call, "/")
+	dot := strings.LastIndex(call, ".")
+	if dot <= slash {
+		return call, ""
+	}
+	importPath, name := call[:dot], call[dot+1:]
+
+	best := ""
+	for dir := range r.packages {
+		if dir == "." || (importPath != dir && !strings.HasSuffix(importPath, "/"+dir)) {
+			continue
+		}
+		if len(dir) > len(best) {
+			best = dir
+		}
+	}
+	for _, p := range r.packages[best] {
+		for _, export := range r.g.Modules[p].Exports {
+			if strings.TrimLeft(strings.Trim(export, "<>"), "#") == name {
+				return p + "#" + name, p
+			}
+		}
+	}
+	return call, ""
+}
@@ -0,0 +1,174 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// callFiles declare calls on symbols (user.go), at module level (main.go)
+// and, in extracted Go source, through an import (cmd/tool/main.go)
+var callFiles = map[string]string{
+	"go.mod": "module example.com/app\n\ngo 1.21\n",
+	"main.go": `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#main.go> a code:Module ;
+    code:name "main.go" ;
+    code:calls <./services/user.go#NewUserService> .
+<!-- End LinkedDoc RDF -->
+*/
+package main
+`,
+	"services/user.go": `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#services/user.go> a code:Module ;
+    code:name "services/user.go" ;
+    code:exports <#NewUserService>, <#UserService> .
+<#UserService.CreateUser> a code:Method ;
+    code:description "Creates a new user" ;
+    code:calls <./auth.go#CheckPermission>, <#UserService.validate> .
+<#UserService.validate> a code:Method ;
+    code:calls <./auth.go#CheckPermission> .
+<!-- End LinkedDoc RDF -->
+*/
+package services
+`,
+	"services/auth.go": `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#services/auth.go> a code:Module ;
+    code:name "services/auth.go" ;
+    code:exports <#CheckPermission> .
+<#CheckPermission> a code:Function ;
+    code:calls <../utils/log.go#Info> .
+<!-- End LinkedDoc RDF -->
+*/
+package services
+`,
+	"cmd/tool/main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/app/services"
+)
+
+func main() {
+	services.CheckPermission()
+	fmt.Println()
+}
+`,
+}
+
+func buildCallGraph(t *testing.T, baseIRI string) *CallGraph {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range callFiles {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g, err := graph.NewBuilder().Build(root, graph.BuildOptions{
+		ScanOptions:   scanner.ScanOptions{UseDefaults: true},
+		BaseIRI:       baseIRI,
+		ExtractSource: true,
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return BuildCallGraph(g)
+}
+
+func TestBuildCallGraph(t *testing.T) {
+	for _, base := range []string{"", "https://graph.example.com/app/"} {
+		cg := buildCallGraph(t, base)
+
+		want := map[string][]string{
+			"main.go": {"services/user.go#NewUserService"},
+			"services/user.go#UserService.CreateUser": {"services/auth.go#CheckPermission", "services/user.go#UserService.validate"},
+			"services/user.go#UserService.validate":   {"services/auth.go#CheckPermission"},
+			"services/auth.go#CheckPermission":        {"utils/log.go#Info"},
+			"cmd/tool/main.go":                        {"fmt.Println", "services/auth.go#CheckPermission"},
+		}
+		for caller, callees := range want {
+			if got := cg.Callees[caller]; !reflect.DeepEqual(got, callees) {
+				t.Errorf("base %q: callees of %s = %v, want %v", base, caller, got, callees)
+			}
+		}
+		if got := cg.Module("services/auth.go#CheckPermission"); got != "services/auth.go" {
+			t.Errorf("base %q: module = %q", base, got)
+		}
+		if got := cg.Module("utils/log.go#Info"); got != "" {
+			t.Errorf("base %q: symbol outside the graph has module %q", base, got)
+		}
+	}
+}
+
+func TestCallGraphTrace(t *testing.T) {
+	cg := buildCallGraph(t, "")
+
+	trace, err := cg.Trace("CheckPermission", CallOptions{})
+	if err != nil {
+		t.Fatalf("Trace failed: %v", err)
+	}
+	if trace.Symbol != "services/auth.go#CheckPermission" || trace.Module != "services/auth.go" {
+		t.Errorf("trace = %+v", trace)
+	}
+	wantCallers := []CallSite{
+		{Symbol: "cmd/tool/main.go", Module: "cmd/tool/main.go", Depth: 1},
+		{Symbol: "services/user.go#UserService.CreateUser", Module: "services/user.go", Depth: 1},
+		{Symbol: "services/user.go#UserService.validate", Module: "services/user.go", Depth: 1},
+	}
+	if !reflect.DeepEqual(trace.Callers, wantCallers) {
+		t.Errorf("callers = %+v, want %+v", trace.Callers, wantCallers)
+	}
+	if len(trace.Callees) != 1 || trace.Callees[0].Symbol != "utils/log.go#Info" {
+		t.Errorf("callees = %+v", trace.Callees)
+	}
+
+	trace, err = cg.Trace("services/user.go#UserService.CreateUser", CallOptions{Direction: CalleesOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCallees := []CallSite{
+		{Symbol: "services/auth.go#CheckPermission", Module: "services/auth.go", Depth: 1},
+		{Symbol: "services/user.go#UserService.validate", Module: "services/user.go", Depth: 1},
+		{Symbol: "utils/log.go#Info", Depth: 2, Via: "services/auth.go#CheckPermission"},
+	}
+	if !reflect.DeepEqual(trace.Callees, wantCallees) || len(trace.Callers) != 0 {
+		t.Errorf("callees = %+v, want %+v", trace.Callees, wantCallees)
+	}
+
+	trace, err = cg.Trace("UserService.CreateUser", CallOptions{Direction: CalleesOnly, Depth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.Callees) != 2 {
+		t.Errorf("depth 1 callees = %+v, want direct calls only", trace.Callees)
+	}
+}
+
+func TestCallGraphLookupErrors(t *testing.T) {
+	cg := buildCallGraph(t, "")
+	if _, err := cg.Trace("Missing", CallOptions{}); err == nil {
+		t.Error("expected error for a symbol without calls")
+	}
+	if _, err := cg.Trace("main.go", CallOptions{Direction: "sideways"}); err == nil {
+		t.Error("expected error for an unknown direction")
+	}
+
+	cg.Callers["other.go#CheckPermission"] = []string{"main.go"}
+	if _, err := cg.Lookup("CheckPermission"); err == nil {
+		t.Error("expected error for an ambiguous name")
+	}
+}
@@ -0,0 +1,200 @@
+/*
+# Module: pkg/analysis/change_impact.go
+Merged impact of a set of changed files.
+
+Maps changed files to modules and combines their impact into one report,
+with the impact of each changed module alongside, for CI jobs that assess
+a diff range rather than a single module.
+
+## Linked Modules
+- [impact](./impact.go) - Impact analysis engine
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, impact-analysis, git, ci
+
+## Exports
+ChangeImpact, ModuleImpact
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#change_impact.go> a code:Module ;
+    code:name "pkg/analysis/change_impact.go" ;
+    code:description "Merged impact of a set of changed files" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <./impact.go>, <../graph/graph.go> ;
+    code:exports <#ChangeImpact>, <#ModuleImpact> ;
+    code:tags "analysis", "impact-analysis", "git", "ci" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeImpact is the combined impact of the modules in a change
+type ChangeImpact struct {
+	Range                string         `json:"range,omitempty"`
+	Modules              []ModuleImpact `json:"modules"`              // Changed modules, highest impact first
+	OtherFiles           []string       `json:"otherFiles,omitempty"` // Changed files that are not modules
+	RiskLevel            RiskLevel      `json:"riskLevel"`
+	RiskFactors          []string       `json:"riskFactors,omitempty"`
+	Recommendations      []string       `json:"recommendations,omitempty"`
+	BreakingChanges      bool           `json:"breakingChanges"`
+	DirectDependents     []string       `json:"directDependents"`
+	TotalImpactedModules int            `json:"totalImpactedModules"`
+	ImpactPercentage     float64        `json:"impactPercentage"`
+	MaxImpactDepth       int            `json:"maxImpactDepth"`
+	ImpactByLayer        map[string]int `json:"impactByLayer,omitempty"`
+
+	// Owners groups the impact by owner when requested (see ImpactByOwner)
+	Owners []OwnerImpact `json:"owners,omitempty"`
+
+	Combined *ImpactResult `json:"-"` // Merged result, nil when no module changed
+}
+
+// ModuleImpact is the impact of one changed module on its own
+type ModuleImpact struct {
+	Path                 string    `json:"path"`
+	Layer                string    `json:"layer,omitempty"`
+	RiskLevel            RiskLevel `json:"riskLevel"`
+	DirectDependents     int       `json:"directDependents"`
+	TotalImpactedModules int       `json:"totalImpactedModules"`
+	ImpactPercentage     float64   `json:"impactPercentage"`
+}
+
+// AnalyzeChange analyzes the combined impact of changed files (paths
+// relative to the graph root). Files that are not modules are listed but
+// otherwise ignored; a change without modules has low risk.
+func (ia *ImpactAnalysis) AnalyzeChange(changed []string) (*ChangeImpact, error) {
+	impact := &ChangeImpact{
+		Modules:          make([]ModuleImpact, 0),
+		RiskLevel:        RiskLevelLow,
+		DirectDependents: make([]string, 0),
+	}
+
+	seen := make(map[string]bool)
+	modules := make([]string, 0, len(changed))
+	for _, path := range changed {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, ok := ia.graph.Modules[path]; !ok {
+			impact.OtherFiles = append(impact.OtherFiles, path)
+			continue
+		}
+		modules = append(modules, path)
+	}
+	sort.Strings(impact.OtherFiles)
+	if len(modules) == 0 {
+		return impact, nil
+	}
+
+	for _, path := range modules {
+		result, err := ia.AnalyzeImpact(path)
+		if err != nil {
+			return nil, err
+		}
+		impact.Modules = append(impact.Modules, ModuleImpact{
+			Path:                 path,
+			Layer:                ia.graph.Modules[path].Layer,
+			RiskLevel:            result.RiskLevel,
+			DirectDependents:     len(result.DirectDependents),
+			TotalImpactedModules: result.TotalImpactedModules,
+			ImpactPercentage:     result.ImpactPercentage,
+		})
+	}
+	sort.Slice(impact.Modules, func(i, j int) bool {
+		a, b := impact.Modules[i], impact.Modules[j]
+		if a.TotalImpactedModules != b.TotalImpactedModules {
+			return a.TotalImpactedModules > b.TotalImpactedModules
+		}
+		return a.Path < b.Path
+	})
+
+	combined, err := ia.AnalyzeMultipleModules(modules)
+	if err != nil {
+		return nil, err
+	}
+	impact.Combined = combined
+	impact.RiskLevel = combined.RiskLevel
+	impact.RiskFactors = combined.RiskFactors
+	impact.Recommendations = combined.Recommendations
+	impact.BreakingChanges = combined.BreakingChanges
+	impact.DirectDependents = combined.DirectDependents
+	impact.TotalImpactedModules = combined.TotalImpactedModules
+	impact.ImpactPercentage = combined.ImpactPercentage
+	impact.MaxImpactDepth = combined.MaxImpactDepth
+	impact.ImpactByLayer = combined.ImpactByLayer
+
+	return impact, nil
+}
+
+// Markdown renders the change impact as a CI report or pull request comment
+func (c *ChangeImpact) Markdown() string {
+	var sb strings.Builder
+
+	title := "## Change impact"
+	if c.Range != "" {
+		title += fmt.Sprintf(" (`%s`)", c.Range)
+	}
+	sb.WriteString(title + "\n\n")
+	fmt.Fprintf(&sb, "**Risk:** %s", c.RiskLevel)
+	if c.BreakingChanges {
+		sb.WriteString(" (breaking changes)")
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "**Impacted modules:** %d (%.1f%% of the graph), up to %d level(s) deep\n",
+		c.TotalImpactedModules, c.ImpactPercentage, c.MaxImpactDepth)
+	if len(c.ImpactByLayer) > 0 {
+		layers := make([]string, 0, len(c.ImpactByLayer))
+		for layer := range c.ImpactByLayer {
+			layers = append(layers, layer)
+		}
+		sort.Strings(layers)
+		for i, layer := range layers {
+			layers[i] = fmt.Sprintf("%s (%d)", layer, c.ImpactByLayer[layer])
+		}
+		fmt.Fprintf(&sb, "**By layer:** %s\n", strings.Join(layers, ", "))
+	}
+
+	fmt.Fprintf(&sb, "\n### Changed modules (%d)\n\n", len(c.Modules))
+	if len(c.Modules) == 0 {
+		sb.WriteString("None.\n")
+	} else {
+		sb.WriteString("| Module | Layer | Risk | Direct dependents | Impacted |\n|---|---|---|---|---|\n")
+		for _, module := range c.Modules {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s | %d | %d (%.1f%%) |\n", module.Path, module.Layer, module.RiskLevel,
+				module.DirectDependents, module.TotalImpactedModules, module.ImpactPercentage)
+		}
+	}
+	if len(c.OtherFiles) > 0 {
+		fmt.Fprintf(&sb, "\nOther files: %s\n", strings.Join(c.OtherFiles, ", "))
+	}
+
+	if len(c.RiskFactors) > 0 {
+		sb.WriteString("\n### Risk factors\n\n")
+		for _, factor := range c.RiskFactors {
+			fmt.Fprintf(&sb, "- %s\n", factor)
+		}
+	}
+	if len(c.Recommendations) > 0 {
+		sb.WriteString("\n### Recommendations\n\n")
+		for _, rec := range c.Recommendations {
+			fmt.Fprintf(&sb, "- %s\n", rec)
+		}
+	}
+	if c.Owners != nil {
+		sb.WriteString("\n### Impacted owners\n\n" + OwnerImpactMarkdown(c.Owners))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeChange(t *testing.T) {
+	ia := NewImpactAnalysis(createTestGraphForImpact())
+
+	impact, err := ia.AnalyzeChange([]string{"isolated/module.go", "utils/utilsA.go", "README.md", "utils/utilsA.go"})
+	if err != nil {
+		t.Fatalf("AnalyzeChange() error = %v", err)
+	}
+
+	if len(impact.Modules) != 2 || impact.Modules[0].Path != "utils/utilsA.go" || impact.Modules[1].Path != "isolated/module.go" {
+		t.Fatalf("Modules = %+v, want utilsA then isolated", impact.Modules)
+	}
+	if impact.Modules[0].TotalImpactedModules != 2 || impact.Modules[0].DirectDependents != 1 {
+		t.Errorf("utilsA impact = %+v, want 2 impacted and 1 direct dependent", impact.Modules[0])
+	}
+	if len(impact.OtherFiles) != 1 || impact.OtherFiles[0] != "README.md" {
+		t.Errorf("OtherFiles = %v, want README.md", impact.OtherFiles)
+	}
+	if impact.TotalImpactedModules != 2 || impact.Combined == nil || impact.MaxImpactDepth != 2 {
+		t.Errorf("Combined impact = %d modules, depth %d", impact.TotalImpactedModules, impact.MaxImpactDepth)
+	}
+
+	markdown := impact.Markdown()
+	for _, want := range []string{"## Change impact", "| `utils/utilsA.go` | utils |", "Other files: README.md"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestAnalyzeChange_NoModules(t *testing.T) {
+	ia := NewImpactAnalysis(createTestGraphForImpact())
+
+	impact, err := ia.AnalyzeChange([]string{"docs/guide.md"})
+	if err != nil {
+		t.Fatalf("AnalyzeChange() error = %v", err)
+	}
+	if impact.RiskLevel != RiskLevelLow || impact.TotalImpactedModules != 0 || impact.Combined != nil {
+		t.Errorf("Impact = %+v, want low risk and nothing impacted", impact)
+	}
+	if !strings.Contains(impact.Markdown(), "### Changed modules (0)\n\nNone.") {
+		t.Errorf("Markdown() should list no modules:\n%s", impact.Markdown())
+	}
+}
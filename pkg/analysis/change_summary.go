@@ -0,0 +1,305 @@
+/*
+# Module: pkg/analysis/change_summary.go
+Graph-aware summaries of a set of changed files.
+
+Maps changed files to modules and summarizes the layers they touch, the
+modules downstream of them and their tags and concepts, for use in commit
+messages and pull request descriptions.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, git, change-summary
+
+## Exports
+ChangeSummary, ChangedModule, DownstreamModule, SummarizeChange
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#change_summary.go> a code:Module ;
+    code:name "pkg/analysis/change_summary.go" ;
+    code:description "Graph-aware summaries of a set of changed files" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go> ;
+    code:exports <#ChangeSummary>, <#ChangedModule>, <#DownstreamModule>, <#SummarizeChange> ;
+    code:tags "analysis", "git", "change-summary" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// ChangeSummary describes a change in terms of the knowledge graph
+type ChangeSummary struct {
+	Modules           []ChangedModule    `json:"modules"`
+	OtherFiles        []string           `json:"otherFiles,omitempty"` // Changed files that are not modules
+	Layers            []string           `json:"layers"`               // Layers of the changed modules
+	Downstream        []DownstreamModule `json:"downstream,omitempty"` // Modules depending on the change
+	DownstreamByLayer map[string]int     `json:"downstreamByLayer,omitempty"`
+	Concepts          []string           `json:"concepts,omitempty"` // Tags and concepts of the changed modules
+}
+
+// ChangedModule is a module touched by the change
+type ChangedModule struct {
+	Path        string   `json:"path"`
+	Layer       string   `json:"layer,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Dependents  int      `json:"dependents"` // Direct dependents outside the change
+}
+
+// DownstreamModule is an unchanged module that transitively depends on the change
+type DownstreamModule struct {
+	Path  string `json:"path"`
+	Layer string `json:"layer,omitempty"`
+	Depth int    `json:"depth"` // 1 for direct dependents
+}
+
+// SummarizeChange summarizes changed files (paths relative to the graph
+// root) against a graph. Concepts beyond module tags can be added by the
+// caller with AddConcepts.
+func SummarizeChange(g *graph.Graph, changed []string) *ChangeSummary {
+	summary := &ChangeSummary{DownstreamByLayer: make(map[string]int)}
+
+	changedSet := make(map[string]bool)
+	layers := make(map[string]bool)
+	for _, path := range changed {
+		module, ok := g.Modules[path]
+		if !ok {
+			summary.OtherFiles = append(summary.OtherFiles, path)
+			continue
+		}
+		if changedSet[path] {
+			continue
+		}
+		changedSet[path] = true
+		if module.Layer != "" {
+			layers[module.Layer] = true
+		}
+		summary.AddConcepts(module.Tags...)
+	}
+
+	reverseDeps := make(map[string][]string)
+	for path, module := range g.Modules {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
+			reverseDeps[dep] = append(reverseDeps[dep], path)
+		}
+	}
+
+	for path := range changedSet {
+		module := g.Modules[path]
+		dependents := 0
+		for _, dependent := range reverseDeps[path] {
+			if !changedSet[dependent] {
+				dependents++
+			}
+		}
+		summary.Modules = append(summary.Modules, ChangedModule{
+			Path:        path,
+			Layer:       module.Layer,
+			Description: module.Description,
+			Tags:        module.Tags,
+			Dependents:  dependents,
+		})
+	}
+	sort.Slice(summary.Modules, func(i, j int) bool {
+		return summary.Modules[i].Path < summary.Modules[j].Path
+	})
+	sort.Strings(summary.OtherFiles)
+	summary.Layers = sortedKeys(layers)
+
+	for path, depth := range changeDependents(reverseDeps, changedSet) {
+		layer := g.Modules[path].Layer
+		summary.Downstream = append(summary.Downstream, DownstreamModule{Path: path, Layer: layer, Depth: depth})
+		if layer != "" {
+			summary.DownstreamByLayer[layer]++
+		}
+	}
+	sort.Slice(summary.Downstream, func(i, j int) bool {
+		a, b := summary.Downstream[i], summary.Downstream[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		return a.Path < b.Path
+	})
+
+	return summary
+}
+
+// AddConcepts adds concepts to the summary, skipping duplicates
+func (s *ChangeSummary) AddConcepts(concepts ...string) {
+	for _, concept := range concepts {
+		if concept == "" || slices.Contains(s.Concepts, concept) {
+			continue
+		}
+		s.Concepts = append(s.Concepts, concept)
+	}
+	sort.Strings(s.Concepts)
+}
+
+// Markdown renders the summary as a pull request description section
+func (s *ChangeSummary) Markdown(maxDownstream int) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Change summary\n\n")
+	fmt.Fprintf(&sb, "**Layers:** %s\n", orNone(s.Layers))
+	if len(s.Concepts) > 0 {
+		fmt.Fprintf(&sb, "**Concepts:** %s\n", strings.Join(s.Concepts, ", "))
+	}
+
+	fmt.Fprintf(&sb, "\n### Modules touched (%d)\n\n", len(s.Modules))
+	for _, module := range s.Modules {
+		fmt.Fprintf(&sb, "- `%s`", module.Path)
+		if module.Layer != "" {
+			fmt.Fprintf(&sb, " (%s)", module.Layer)
+		}
+		if module.Description != "" {
+			fmt.Fprintf(&sb, " - %s", module.Description)
+		}
+		sb.WriteString("\n")
+	}
+	if len(s.OtherFiles) > 0 {
+		fmt.Fprintf(&sb, "\nOther files: %s\n", strings.Join(s.OtherFiles, ", "))
+	}
+
+	fmt.Fprintf(&sb, "\n### Downstream dependents (%d)\n\n", len(s.Downstream))
+	if len(s.Downstream) == 0 {
+		sb.WriteString("None.\n")
+		return sb.String()
+	}
+	if layers := s.downstreamLayers(); layers != "" {
+		fmt.Fprintf(&sb, "By layer: %s\n\n", layers)
+	}
+	shown, more := limitDownstream(s.Downstream, maxDownstream)
+	for _, module := range shown {
+		fmt.Fprintf(&sb, "- `%s`", module.Path)
+		if module.Depth > 1 {
+			fmt.Fprintf(&sb, " (transitive, depth %d)", module.Depth)
+		}
+		sb.WriteString("\n")
+	}
+	if more > 0 {
+		fmt.Fprintf(&sb, "- ... and %d more\n", more)
+	}
+
+	return sb.String()
+}
+
+// Text renders the summary as plain text for a commit message body
+func (s *ChangeSummary) Text(maxDownstream int) string {
+	var sb strings.Builder
+
+	paths := make([]string, len(s.Modules))
+	for i, module := range s.Modules {
+		paths[i] = module.Path
+	}
+	fmt.Fprintf(&sb, "Modules: %s\n", orNone(paths))
+	fmt.Fprintf(&sb, "Layers: %s\n", orNone(s.Layers))
+	if len(s.Concepts) > 0 {
+		fmt.Fprintf(&sb, "Concepts: %s\n", strings.Join(s.Concepts, ", "))
+	}
+
+	if len(s.Downstream) == 0 {
+		sb.WriteString("Downstream: none\n")
+		return sb.String()
+	}
+	shown, more := limitDownstream(s.Downstream, maxDownstream)
+	names := make([]string, len(shown))
+	for i, module := range shown {
+		names[i] = module.Path
+	}
+	if more > 0 {
+		names = append(names, fmt.Sprintf("and %d more", more))
+	}
+	fmt.Fprintf(&sb, "Downstream (%d): %s\n", len(s.Downstream), strings.Join(names, ", "))
+	if layers := s.downstreamLayers(); layers != "" {
+		fmt.Fprintf(&sb, "Downstream layers: %s\n", layers)
+	}
+
+	return sb.String()
+}
+
+// downstreamLayers formats downstream counts per layer, largest first
+func (s *ChangeSummary) downstreamLayers() string {
+	layers := make([]string, 0, len(s.DownstreamByLayer))
+	for layer := range s.DownstreamByLayer {
+		layers = append(layers, layer)
+	}
+	sort.Slice(layers, func(i, j int) bool {
+		ci, cj := s.DownstreamByLayer[layers[i]], s.DownstreamByLayer[layers[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return layers[i] < layers[j]
+	})
+
+	parts := make([]string, len(layers))
+	for i, layer := range layers {
+		parts[i] = fmt.Sprintf("%s (%d)", layer, s.DownstreamByLayer[layer])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// changeDependents returns the unchanged modules that transitively depend on
+// any changed module, with their shortest distance from the change
+func changeDependents(reverseDeps map[string][]string, changed map[string]bool) map[string]int {
+	depths := make(map[string]int)
+	queue := make([]string, 0, len(changed))
+	for path := range changed {
+		depths[path] = 0
+		queue = append(queue, path)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range reverseDeps[current] {
+			if _, seen := depths[dependent]; seen {
+				continue
+			}
+			depths[dependent] = depths[current] + 1
+			queue = append(queue, dependent)
+		}
+	}
+
+	for path := range changed {
+		delete(depths, path)
+	}
+	return depths
+}
+
+func limitDownstream(modules []DownstreamModule, max int) ([]DownstreamModule, int) {
+	if max <= 0 || len(modules) <= max {
+		return modules, 0
+	}
+	return modules[:max], len(modules) - max
+}
+
+func orNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarizeChange(t *testing.T) {
+	g := createTestGraphForImpact()
+	g.Modules["utils/utilsA.go"].Tags = []string{"crypto"}
+
+	summary := SummarizeChange(g, []string{"utils/utilsA.go", "services/serviceA.go", "README.md"})
+
+	if len(summary.Modules) != 2 || summary.Modules[0].Path != "services/serviceA.go" {
+		t.Fatalf("Modules = %+v, want serviceA and utilsA", summary.Modules)
+	}
+	// utilsA's only dependent is serviceA, which is part of the change
+	if summary.Modules[1].Dependents != 0 {
+		t.Errorf("utilsA dependents outside the change = %d, want 0", summary.Modules[1].Dependents)
+	}
+	if strings.Join(summary.Layers, ",") != "services,utils" {
+		t.Errorf("Layers = %v, want services, utils", summary.Layers)
+	}
+	if len(summary.OtherFiles) != 1 || summary.OtherFiles[0] != "README.md" {
+		t.Errorf("OtherFiles = %v, want README.md", summary.OtherFiles)
+	}
+	if len(summary.Downstream) != 1 || summary.Downstream[0].Path != "handlers/api.go" || summary.Downstream[0].Depth != 1 {
+		t.Errorf("Downstream = %+v, want handlers/api.go at depth 1", summary.Downstream)
+	}
+	if summary.DownstreamByLayer["handlers"] != 1 {
+		t.Errorf("DownstreamByLayer = %v, want handlers: 1", summary.DownstreamByLayer)
+	}
+
+	summary.AddConcepts("auth", "crypto")
+	if strings.Join(summary.Concepts, ",") != "auth,crypto" {
+		t.Errorf("Concepts = %v, want auth, crypto", summary.Concepts)
+	}
+
+	text := summary.Text(0)
+	if !strings.Contains(text, "Downstream (1): handlers/api.go") {
+		t.Errorf("Text missing downstream line:\n%s", text)
+	}
+	markdown := summary.Markdown(0)
+	if !strings.Contains(markdown, "### Modules touched (2)") {
+		t.Errorf("Markdown missing modules section:\n%s", markdown)
+	}
+}
+
+func TestSummarizeChange_Transitive(t *testing.T) {
+	g := createTestGraphForImpact()
+
+	summary := SummarizeChange(g, []string{"core/core.go"})
+	if len(summary.Downstream) != 5 {
+		t.Fatalf("Downstream = %d modules, want 5", len(summary.Downstream))
+	}
+	if last := summary.Downstream[len(summary.Downstream)-1]; last.Path != "handlers/api.go" || last.Depth != 3 {
+		t.Errorf("Deepest downstream = %+v, want handlers/api.go at depth 3", last)
+	}
+
+	if text := summary.Text(2); !strings.Contains(text, "and 3 more") {
+		t.Errorf("Expected truncated downstream list, got:\n%s", text)
+	}
+}
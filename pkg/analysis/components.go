@@ -0,0 +1,392 @@
+/*
+# Module: pkg/analysis/components.go
+Components: named groups of modules above the module level.
+
+Components are declared in .graphfs/components.yaml with the paths they
+include and the modules forming their public API. Every module is assigned
+to the component with the most specific matching path, and dependencies
+between modules of different components become component edges. Edges into
+a component's non-API modules, and edges a component does not declare in
+depends_on, are reported as violations.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, components, architecture
+
+## Exports
+DefaultComponentsFile, ComponentConfig, ComponentSpec, ComponentAnalysis, Component, ComponentEdge, ComponentViolation, LoadComponentConfig, AnalyzeComponents
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#components.go> a code:Module ;
+    code:name "pkg/analysis/components.go" ;
+    code:description "Components: named groups of modules above the module level" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go> ;
+    code:exports <#DefaultComponentsFile>, <#ComponentConfig>, <#ComponentSpec>, <#ComponentAnalysis>, <#Component>,
+                 <#ComponentEdge>, <#ComponentViolation>, <#LoadComponentConfig>, <#AnalyzeComponents> ;
+    code:tags "analysis", "components", "architecture" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultComponentsFile is the components file relative to the project root
+const DefaultComponentsFile = ".graphfs/components.yaml"
+
+// Component triples. code:Component already names components declared by
+// LinkedDoc blocks within a file, so components spanning modules use their
+// own type.
+const (
+	ComponentTypeURI            = "https://schema.codedoc.org/ArchitecturalComponent"
+	MemberOfPredicate           = "https://schema.codedoc.org/memberOf"           // module -> component
+	PublicAPIPredicate          = "https://schema.codedoc.org/publicAPI"          // component -> module
+	DependsOnComponentPredicate = "https://schema.codedoc.org/dependsOnComponent" // component -> component
+	BypassesAPIPredicate        = "https://schema.codedoc.org/bypassesAPI"        // module -> non-API module of another component
+
+	componentNamePredicate = "https://schema.codedoc.org/name"
+	componentTypePredicate = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+)
+
+// Violation kinds
+const (
+	ComponentViolationNonAPI     = "non-api"    // Dependency on a module outside the target's public API
+	ComponentViolationUndeclared = "undeclared" // Component dependency missing from depends_on
+)
+
+// ComponentConfig is the contents of a components file
+type ComponentConfig struct {
+	Components []ComponentSpec `yaml:"components" json:"components"`
+}
+
+// ComponentSpec declares a component. Paths and API entries are relative to
+// the project root: a file, a directory (including everything below it), a
+// directory followed by "/..." or a glob. DependsOn, when set, lists the
+// only components this one may depend on.
+type ComponentSpec struct {
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Paths       []string `yaml:"paths" json:"paths"`
+	API         []string `yaml:"api,omitempty" json:"api,omitempty"`
+	DependsOn   []string `yaml:"depends_on,omitempty" json:"dependsOn,omitempty"`
+}
+
+// ComponentAnalysis contains resolved components and the edges between them
+type ComponentAnalysis struct {
+	Components []*Component     `json:"components"`
+	Edges      []*ComponentEdge `json:"edges"`
+	Unassigned []string         `json:"unassigned,omitempty"` // Modules matching no component
+
+	byName     map[string]*Component
+	membership map[string]string
+}
+
+// Component is a declared component with its resolved members
+type Component struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Modules     []string `json:"modules"`
+	API         []string `json:"api,omitempty"`        // Member modules forming the public API
+	Layers      []string `json:"layers,omitempty"`     // Layers of the member modules
+	DependsOn   []string `json:"dependsOn,omitempty"`  // Components depended on
+	Dependents  []string `json:"dependents,omitempty"` // Components depending on this one
+
+	spec *ComponentSpec
+}
+
+// ComponentEdge is a dependency between components, derived from the
+// dependencies between their modules
+type ComponentEdge struct {
+	From         string       `json:"from"`
+	To           string       `json:"to"`
+	Dependencies []ModuleEdge `json:"dependencies"`
+	NonAPI       int          `json:"nonApi"`   // Dependencies on modules outside To's public API
+	Declared     bool         `json:"declared"` // Allowed by From's depends_on (or unrestricted)
+}
+
+// ModuleEdge is a module dependency underlying a component edge
+type ModuleEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Public bool   `json:"public"` // To is part of its component's public API
+}
+
+// ComponentViolation is a dependency breaking a component's boundaries
+type ComponentViolation struct {
+	Kind   string `json:"kind"`
+	From   string `json:"from"`   // Component
+	To     string `json:"to"`     // Component
+	Source string `json:"source"` // Module
+	Target string `json:"target"` // Module
+}
+
+// LoadComponentConfig reads and validates a components file
+func LoadComponentConfig(filePath string) (*ComponentConfig, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read components: %w", err)
+	}
+
+	var config ComponentConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse components: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for i, spec := range config.Components {
+		if spec.Name == "" {
+			return nil, fmt.Errorf("component %d: missing name", i)
+		}
+		if names[spec.Name] {
+			return nil, fmt.Errorf("duplicate component: %s", spec.Name)
+		}
+		names[spec.Name] = true
+		if len(spec.Paths) == 0 {
+			return nil, fmt.Errorf("component %s: missing paths", spec.Name)
+		}
+	}
+	for _, spec := range config.Components {
+		for _, dep := range spec.DependsOn {
+			if !names[dep] {
+				return nil, fmt.Errorf("component %s: depends_on unknown component %s", spec.Name, dep)
+			}
+		}
+	}
+
+	return &config, nil
+}
+
+// AnalyzeComponents assigns every module to a component and derives the
+// edges between components. A module belongs to the component with the most
+// specific matching path; ties go to the component declared first.
+func AnalyzeComponents(g *graph.Graph, config *ComponentConfig) *ComponentAnalysis {
+	result := &ComponentAnalysis{
+		Components: make([]*Component, 0, len(config.Components)),
+		Edges:      make([]*ComponentEdge, 0),
+		byName:     make(map[string]*Component),
+		membership: make(map[string]string),
+	}
+
+	for i := range config.Components {
+		spec := &config.Components[i]
+		component := &Component{
+			Name:        spec.Name,
+			Description: spec.Description,
+			Modules:     make([]string, 0),
+			spec:        spec,
+		}
+		result.Components = append(result.Components, component)
+		result.byName[spec.Name] = component
+	}
+
+	paths := make([]string, 0, len(g.Modules))
+	for modulePath := range g.Modules {
+		paths = append(paths, modulePath)
+	}
+	sort.Strings(paths)
+
+	for _, modulePath := range paths {
+		best, bestScore := (*Component)(nil), -1
+		for _, component := range result.Components {
+			if score := matchComponentPaths(component.spec.Paths, modulePath); score > bestScore {
+				best, bestScore = component, score
+			}
+		}
+		if best == nil {
+			result.Unassigned = append(result.Unassigned, modulePath)
+			continue
+		}
+
+		result.membership[modulePath] = best.Name
+		best.Modules = append(best.Modules, modulePath)
+		if best.HasAPI() && matchComponentPaths(best.spec.API, modulePath) >= 0 {
+			best.API = append(best.API, modulePath)
+		}
+		if layer := g.Modules[modulePath].Layer; layer != "" && !slices.Contains(best.Layers, layer) {
+			best.Layers = append(best.Layers, layer)
+		}
+	}
+	for _, component := range result.Components {
+		sort.Strings(component.Layers)
+	}
+
+	edges := make(map[[2]string]*ComponentEdge)
+	for _, modulePath := range paths {
+		from, ok := result.membership[modulePath]
+		if !ok {
+			continue
+		}
+		for _, depEdge := range g.Modules[modulePath].DependencyEdges() {
+			dep := depEdge.Target
+			to, ok := result.membership[dep]
+			if !ok || to == from {
+				continue
+			}
+
+			key := [2]string{from, to}
+			edge := edges[key]
+			if edge == nil {
+				allowed := result.byName[from].spec.DependsOn
+				edge = &ComponentEdge{
+					From:     from,
+					To:       to,
+					Declared: allowed == nil || slices.Contains(allowed, to),
+				}
+				edges[key] = edge
+				result.Edges = append(result.Edges, edge)
+			}
+
+			public := result.byName[to].IsPublic(dep)
+			edge.Dependencies = append(edge.Dependencies, ModuleEdge{From: modulePath, To: dep, Public: public})
+			if !public {
+				edge.NonAPI++
+			}
+		}
+	}
+
+	sort.Slice(result.Edges, func(i, j int) bool {
+		if result.Edges[i].From != result.Edges[j].From {
+			return result.Edges[i].From < result.Edges[j].From
+		}
+		return result.Edges[i].To < result.Edges[j].To
+	})
+	for _, edge := range result.Edges {
+		result.byName[edge.From].DependsOn = append(result.byName[edge.From].DependsOn, edge.To)
+		result.byName[edge.To].Dependents = append(result.byName[edge.To].Dependents, edge.From)
+	}
+	for _, component := range result.Components {
+		sort.Strings(component.Dependents)
+	}
+
+	return result
+}
+
+// matchComponentPaths returns the specificity of the most specific pattern
+// matching a module path (the length of its literal part), or -1
+func matchComponentPaths(patterns []string, modulePath string) int {
+	best := -1
+	for _, pattern := range patterns {
+		pattern = strings.Trim(path.Clean(pattern), "/")
+
+		var literal string
+		var matched bool
+		switch {
+		case pattern == "." || pattern == "...":
+			matched = true
+		case strings.HasSuffix(pattern, "/..."):
+			literal = strings.TrimSuffix(pattern, "/...")
+			matched = modulePath == literal || strings.HasPrefix(modulePath, literal+"/")
+		case strings.ContainsAny(pattern, "*?["):
+			literal = pattern[:strings.IndexAny(pattern, "*?[")]
+			matched, _ = path.Match(pattern, modulePath)
+		default:
+			literal = pattern
+			matched = modulePath == pattern || strings.HasPrefix(modulePath, pattern+"/")
+		}
+
+		if matched && len(literal) > best {
+			best = len(literal)
+		}
+	}
+	return best
+}
+
+// HasAPI reports whether the component declares public API modules
+func (c *Component) HasAPI() bool {
+	return c.spec != nil && len(c.spec.API) > 0
+}
+
+// IsPublic reports whether a member module is part of the component's
+// public API. Components declaring no API are fully public.
+func (c *Component) IsPublic(modulePath string) bool {
+	return !c.HasAPI() || slices.Contains(c.API, modulePath)
+}
+
+// ComponentOf returns the component a module belongs to, or ""
+func (a *ComponentAnalysis) ComponentOf(modulePath string) string {
+	return a.membership[modulePath]
+}
+
+// Component returns a component by name, or nil
+func (a *ComponentAnalysis) Component(name string) *Component {
+	return a.byName[name]
+}
+
+// Violations returns the module dependencies breaking component boundaries:
+// dependencies on another component's non-API modules, and dependencies on
+// components missing from depends_on
+func (a *ComponentAnalysis) Violations() []ComponentViolation {
+	var violations []ComponentViolation
+	for _, edge := range a.Edges {
+		for _, dep := range edge.Dependencies {
+			violation := ComponentViolation{From: edge.From, To: edge.To, Source: dep.From, Target: dep.To}
+			if !edge.Declared {
+				violation.Kind = ComponentViolationUndeclared
+				violations = append(violations, violation)
+			}
+			if !dep.Public {
+				violation.Kind = ComponentViolationNonAPI
+				violations = append(violations, violation)
+			}
+		}
+	}
+	return violations
+}
+
+// AddTriples adds component nodes, memberships, public API modules,
+// component dependencies and API bypasses to the graph's store, so SPARQL
+// queries and rules can use them. Returns the number of triples added.
+func (a *ComponentAnalysis) AddTriples(g *graph.Graph) int {
+	if g.Store == nil {
+		return 0
+	}
+
+	before := g.Store.Count()
+	for _, component := range a.Components {
+		subject := "<" + componentIRI(component.Name) + ">"
+		_ = g.Store.Add(subject, componentTypePredicate, ComponentTypeURI)
+		_ = g.Store.Add(subject, componentNamePredicate, component.Name)
+		for _, modulePath := range component.Modules {
+			module := g.Modules[modulePath]
+			_ = g.Store.Add(module.URI, MemberOfPredicate, componentIRI(component.Name))
+		}
+		for _, modulePath := range component.API {
+			// URI objects are stored without the brackets subjects carry
+			api, _ := graph.Unbracket(g.Modules[modulePath].URI)
+			_ = g.Store.Add(subject, PublicAPIPredicate, api)
+		}
+	}
+	for _, edge := range a.Edges {
+		_ = g.Store.Add("<"+componentIRI(edge.From)+">", DependsOnComponentPredicate, componentIRI(edge.To))
+		for _, dep := range edge.Dependencies {
+			if !dep.Public {
+				target, _ := graph.Unbracket(g.Modules[dep.To].URI)
+				_ = g.Store.Add(g.Modules[dep.From].URI, BypassesAPIPredicate, target)
+			}
+		}
+	}
+	return g.Store.Count() - before
+}
+
+// componentIRI returns the IRI of a component node, without brackets
+func componentIRI(name string) string {
+	return "#component/" + url.PathEscape(name)
+}
@@ -0,0 +1,131 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func testComponentConfig() *ComponentConfig {
+	return &ComponentConfig{Components: []ComponentSpec{
+		{Name: "web", Paths: []string{"handlers"}, DependsOn: []string{"domain"}},
+		{Name: "domain", Paths: []string{"services/...", "utils"}, API: []string{"services/*.go"}, DependsOn: []string{"platform"}},
+		{Name: "platform", Paths: []string{"core"}, API: []string{"core/api.go"}},
+		{Name: "legacy", Paths: []string{"utils/utilsB.go"}},
+	}}
+}
+
+func TestAnalyzeComponents(t *testing.T) {
+	g := createTestGraphForImpact()
+	result := AnalyzeComponents(g, testComponentConfig())
+
+	// The most specific path wins
+	if got := result.ComponentOf("utils/utilsB.go"); got != "legacy" {
+		t.Errorf("utilsB component = %q, want legacy", got)
+	}
+	if got := result.ComponentOf("utils/utilsA.go"); got != "domain" {
+		t.Errorf("utilsA component = %q, want domain", got)
+	}
+	if len(result.Unassigned) != 1 || result.Unassigned[0] != "isolated/module.go" {
+		t.Errorf("Unassigned = %v, want isolated/module.go", result.Unassigned)
+	}
+
+	domain := result.Component("domain")
+	if strings.Join(domain.API, ",") != "services/serviceA.go,services/serviceB.go" {
+		t.Errorf("domain API = %v", domain.API)
+	}
+	if strings.Join(domain.Layers, ",") != "services,utils" {
+		t.Errorf("domain layers = %v", domain.Layers)
+	}
+	if strings.Join(domain.DependsOn, ",") != "legacy,platform" || strings.Join(domain.Dependents, ",") != "web" {
+		t.Errorf("domain depends on %v, dependents %v", domain.DependsOn, domain.Dependents)
+	}
+
+	edges := make(map[string]*ComponentEdge)
+	for _, edge := range result.Edges {
+		edges[edge.From+"->"+edge.To] = edge
+	}
+	if len(edges) != 4 {
+		t.Fatalf("Edges = %v, want 4", edges)
+	}
+	if edge := edges["web->domain"]; len(edge.Dependencies) != 2 || edge.NonAPI != 0 || !edge.Declared {
+		t.Errorf("web->domain = %+v", edge)
+	}
+	if edge := edges["domain->legacy"]; edge.Declared {
+		t.Error("domain->legacy should not be declared")
+	}
+	if edge := edges["domain->platform"]; edge.NonAPI != 1 {
+		t.Errorf("domain->platform non-API dependencies = %d, want 1", edge.NonAPI)
+	}
+
+	kinds := make(map[string]int)
+	for _, v := range result.Violations() {
+		kinds[v.Kind]++
+	}
+	if kinds[ComponentViolationUndeclared] != 1 || kinds[ComponentViolationNonAPI] != 2 {
+		t.Errorf("Violations by kind = %v, want 1 undeclared and 2 non-api", kinds)
+	}
+}
+
+func TestComponentsAddTriples(t *testing.T) {
+	g := graph.NewGraph(t.TempDir(), store.NewTripleStore())
+	g.AddModule(&graph.Module{Path: "app/main.go", URI: "<#app/main.go>", Dependencies: []string{"lib/internal.go"}})
+	g.AddModule(&graph.Module{Path: "lib/internal.go", URI: "<#lib/internal.go>"})
+	g.AddModule(&graph.Module{Path: "lib/api.go", URI: "<#lib/api.go>"})
+
+	result := AnalyzeComponents(g, &ComponentConfig{Components: []ComponentSpec{
+		{Name: "app", Paths: []string{"app"}},
+		{Name: "lib", Paths: []string{"lib"}, API: []string{"lib/api.go"}},
+	}})
+	if added := result.AddTriples(g); added == 0 {
+		t.Fatal("expected triples to be added")
+	}
+
+	if got := g.Store.Find("<#app/main.go>", MemberOfPredicate, ""); len(got) != 1 || got[0].Object != "#component/app" {
+		t.Errorf("main.go membership triples = %v", got)
+	}
+	if got := g.Store.Find("<#component/lib>", PublicAPIPredicate, ""); len(got) != 1 || got[0].Object != "#lib/api.go" {
+		t.Errorf("lib public API triples = %v", got)
+	}
+	if got := g.Store.Find("<#component/app>", DependsOnComponentPredicate, ""); len(got) != 1 {
+		t.Errorf("app component dependency triples = %v", got)
+	}
+	if got := g.Store.Find("<#app/main.go>", BypassesAPIPredicate, ""); len(got) != 1 || got[0].Object != "#lib/internal.go" {
+		t.Errorf("main.go API bypass triples = %v", got)
+	}
+}
+
+func TestLoadComponentConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		file := filepath.Join(dir, "components.yaml")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	config, err := LoadComponentConfig(write("components:\n  - name: api\n    paths: [pkg/api]\n    api: [pkg/api/api.go]\n"))
+	if err != nil {
+		t.Fatalf("LoadComponentConfig failed: %v", err)
+	}
+	if len(config.Components) != 1 || config.Components[0].API[0] != "pkg/api/api.go" {
+		t.Errorf("Components = %+v", config.Components)
+	}
+
+	invalid := []string{
+		"components:\n  - paths: [a]\n",
+		"components:\n  - name: a\n",
+		"components:\n  - name: a\n    paths: [a]\n  - name: a\n    paths: [b]\n",
+		"components:\n  - name: a\n    paths: [a]\n    depends_on: [b]\n",
+	}
+	for _, content := range invalid {
+		if _, err := LoadComponentConfig(write(content)); err == nil {
+			t.Errorf("expected error for %q", content)
+		}
+	}
+}
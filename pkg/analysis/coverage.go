@@ -13,7 +13,7 @@ coverage and identify underutilized components.
 analysis, coverage, usage
 
 ## Exports
-CoverageAnalysis, ModuleCoverage, AnalyzeCoverage
+CoverageAnalysis, ModuleCoverage, CoverageOptions, AnalyzeCoverage, AnalyzeCoverageWithOptions
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -25,7 +25,7 @@ CoverageAnalysis, ModuleCoverage, AnalyzeCoverage
     code:language "go" ;
     code:layer "analysis" ;
     code:linksTo <../graph/graph.go>, <./deadcode.go> ;
-    code:exports <#CoverageAnalysis>, <#ModuleCoverage>, <#AnalyzeCoverage> ;
+    code:exports <#CoverageAnalysis>, <#ModuleCoverage>, <#CoverageOptions>, <#AnalyzeCoverage>, <#AnalyzeCoverageWithOptions> ;
     code:tags "analysis", "coverage", "usage" .
 <!-- End LinkedDoc RDF -->
 */
@@ -61,10 +61,21 @@ type ModuleCoverage struct {
 	TransitiveRefs int // Number of transitive references
 }
 
-// AnalyzeCoverage performs usage coverage analysis
+// CoverageOptions configures coverage analysis
+type CoverageOptions struct {
+	IncludeGenerated bool // Include generated code (excluded by default)
+}
+
+// AnalyzeCoverage performs usage coverage analysis with default options
 func AnalyzeCoverage(g *graph.Graph) *CoverageAnalysis {
+	return AnalyzeCoverageWithOptions(g, CoverageOptions{})
+}
+
+// AnalyzeCoverageWithOptions performs usage coverage analysis
+func AnalyzeCoverageWithOptions(g *graph.Graph, opts CoverageOptions) *CoverageAnalysis {
 	analyzer := &coverageAnalyzer{
 		graph:             g,
+		options:           opts,
 		incomingRefs:      make(map[string][]string),
 		outgoingRefs:      make(map[string][]string),
 		transitiveRefs:    make(map[string]int),
@@ -76,6 +87,7 @@ func AnalyzeCoverage(g *graph.Graph) *CoverageAnalysis {
 
 type coverageAnalyzer struct {
 	graph             *graph.Graph
+	options           CoverageOptions
 	incomingRefs      map[string][]string
 	outgoingRefs      map[string][]string
 	transitiveRefs    map[string]int
@@ -92,6 +104,10 @@ func (a *coverageAnalyzer) analyze() *CoverageAnalysis {
 	// Create module coverage entries
 	coverages := make([]*ModuleCoverage, 0, len(a.graph.Modules))
 	for _, module := range a.graph.Modules {
+		// Generated modules still count as references but are not reported
+		if module.IsGenerated() && !a.options.IncludeGenerated {
+			continue
+		}
 		coverage := a.analyzeModule(module)
 		coverages = append(coverages, coverage)
 		a.moduleCoverageMap[module.Path] = coverage
@@ -141,7 +157,9 @@ func (a *coverageAnalyzer) buildReferenceMaps() {
 	for _, module := range a.graph.Modules {
 		a.outgoingRefs[module.Path] = module.Dependencies
 
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+
+			dep := edge.Target
 			a.incomingRefs[dep] = append(a.incomingRefs[dep], module.Path)
 		}
 	}
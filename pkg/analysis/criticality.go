@@ -0,0 +1,398 @@
+/*
+# Module: pkg/analysis/criticality.go
+Module criticality scoring.
+
+Combines five signals into one criticality score per module, each
+normalized to 0-1 and weighted by a configurable model:
+
+  - fan_in: transitive dependents, relative to the most depended-on module
+  - entry_points: share of entry points that reach the module
+  - security: risk level of the module's security zone
+  - churn: commits touching the module, relative to the busiest module
+  - coverage: missing tests (1 when no test file or declared coverage)
+
+Scores map to levels (critical, high, medium, low) by threshold, and each
+level may require a minimum number of owners. Scores, levels and owner
+shortfalls are added to the graph as triples so rules can use them.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [zones](./zones.go) - Security zone classification
+- [deadcode](./deadcode.go) - Entry point detection
+- [graph_algorithms](./graph_algorithms.go) - Transitive dependents
+
+## Tags
+analysis, criticality, risk, ownership
+
+## Exports
+CriticalityConfig, CriticalityWeights, DefaultCriticalityConfig, CriticalitySignals, ModuleCriticality, CriticalityAnalysis, CriticalityOptions, ScoreCriticality, Round
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#criticality.go> a code:Module ;
+    code:name "pkg/analysis/criticality.go" ;
+    code:description "Module criticality scoring" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go>, <./zones.go>, <./deadcode.go>, <./graph_algorithms.go> ;
+    code:exports <#CriticalityConfig>, <#CriticalityWeights>, <#DefaultCriticalityConfig>, <#CriticalitySignals>,
+                 <#ModuleCriticality>, <#CriticalityAnalysis>, <#CriticalityOptions>, <#ScoreCriticality>, <#Round> ;
+    code:tags "analysis", "criticality", "risk", "ownership" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Predicates for criticality triples
+const (
+	CriticalityPredicate      = "https://schema.codedoc.org/criticality"
+	CriticalityLevelPredicate = "https://schema.codedoc.org/criticalityLevel"
+	OwnerCountPredicate       = "https://schema.codedoc.org/ownerCount"
+	OwnerShortfallPredicate   = "https://schema.codedoc.org/ownerShortfall"
+)
+
+// CriticalityWeights weighs the criticality signals. Weights are relative;
+// they need not sum to 1.
+type CriticalityWeights struct {
+	FanIn       float64 `yaml:"fan_in" json:"fan_in"`
+	EntryPoints float64 `yaml:"entry_points" json:"entry_points"`
+	Security    float64 `yaml:"security" json:"security"`
+	Churn       float64 `yaml:"churn" json:"churn"`
+	Coverage    float64 `yaml:"coverage" json:"coverage"`
+}
+
+// CriticalityConfig defines the scoring model
+type CriticalityConfig struct {
+	Weights CriticalityWeights `yaml:"weights" json:"weights"`
+
+	// Levels maps level names to the minimum score for the level
+	Levels map[string]float64 `yaml:"levels" json:"levels"`
+
+	// MinOwners maps level names to the number of owners modules at that
+	// level require
+	MinOwners map[string]int `yaml:"min_owners,omitempty" json:"min_owners,omitempty"`
+
+	// ChurnSince limits churn to commits after a date or duration
+	// (git --since syntax, default: 6 months ago)
+	ChurnSince string `yaml:"churn_since,omitempty" json:"churn_since,omitempty"`
+}
+
+// DefaultCriticalityConfig returns the default scoring model
+func DefaultCriticalityConfig() CriticalityConfig {
+	return CriticalityConfig{
+		Weights: CriticalityWeights{
+			FanIn:       0.3,
+			EntryPoints: 0.2,
+			Security:    0.2,
+			Churn:       0.15,
+			Coverage:    0.15,
+		},
+		Levels: map[string]float64{
+			"critical": 0.7,
+			"high":     0.5,
+			"medium":   0.3,
+		},
+		MinOwners: map[string]int{
+			"critical": 2,
+		},
+		ChurnSince: "6 months ago",
+	}
+}
+
+// WithDefaults fills unset parts of the model from the default model, so a
+// config may override only the weights or only the levels
+func (c CriticalityConfig) WithDefaults() CriticalityConfig {
+	defaults := DefaultCriticalityConfig()
+	if c.Weights == (CriticalityWeights{}) {
+		c.Weights = defaults.Weights
+	}
+	if c.Levels == nil {
+		c.Levels = defaults.Levels
+	}
+	if c.MinOwners == nil {
+		c.MinOwners = defaults.MinOwners
+	}
+	if c.ChurnSince == "" {
+		c.ChurnSince = defaults.ChurnSince
+	}
+	return c
+}
+
+// lowLevel is the level of modules below every threshold
+const lowLevel = "low"
+
+// Validate checks that weights are usable
+func (c CriticalityConfig) Validate() error {
+	w := c.Weights
+	for name, weight := range map[string]float64{
+		"fan_in": w.FanIn, "entry_points": w.EntryPoints, "security": w.Security,
+		"churn": w.Churn, "coverage": w.Coverage,
+	} {
+		if weight < 0 {
+			return fmt.Errorf("criticality weight %s must not be negative", name)
+		}
+	}
+	if w.FanIn+w.EntryPoints+w.Security+w.Churn+w.Coverage == 0 {
+		return fmt.Errorf("criticality weights must not all be zero")
+	}
+	for level, threshold := range c.Levels {
+		if threshold < 0 || threshold > 1 {
+			return fmt.Errorf("criticality level %s threshold must be between 0 and 1", level)
+		}
+	}
+	return nil
+}
+
+// CriticalitySignals holds a module's normalized (0-1) signals
+type CriticalitySignals struct {
+	FanIn       float64 `json:"fan_in"`
+	EntryPoints float64 `json:"entry_points"`
+	Security    float64 `json:"security"`
+	Churn       float64 `json:"churn"`
+	Coverage    float64 `json:"coverage"`
+}
+
+// ModuleCriticality is the criticality of one module
+type ModuleCriticality struct {
+	Path           string             `json:"path"`
+	Score          float64            `json:"score"`
+	Level          string             `json:"level"`
+	Signals        CriticalitySignals `json:"signals"`
+	Owners         int                `json:"owners"`
+	RequiredOwners int                `json:"required_owners,omitempty"`
+}
+
+// OwnerShortfall returns how many owners the module is missing
+func (m *ModuleCriticality) OwnerShortfall() int {
+	if m.Owners >= m.RequiredOwners {
+		return 0
+	}
+	return m.RequiredOwners - m.Owners
+}
+
+// CriticalityAnalysis holds scores for every module, most critical first
+type CriticalityAnalysis struct {
+	Modules []*ModuleCriticality `json:"modules"`
+	byPath  map[string]*ModuleCriticality
+}
+
+// CriticalityOptions configures scoring
+type CriticalityOptions struct {
+	Config CriticalityConfig
+
+	// Churn maps module paths to commit counts (optional)
+	Churn map[string]int
+}
+
+// ScoreCriticality scores every module in the graph
+func ScoreCriticality(g *graph.Graph, opts CriticalityOptions) (*CriticalityAnalysis, error) {
+	if err := opts.Config.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Fan-in and entry point reachability
+	detector := NewDetector(g, DeadCodeOptions{})
+	dependents := make(map[string]int, len(g.Modules))
+	reachedBy := make(map[string]int, len(g.Modules))
+	entryPoints := 0
+	for p, module := range g.Modules {
+		dependents[p] = len(TransitiveDependents(g, p))
+		if !detector.isEntryPoint(module) {
+			continue
+		}
+		entryPoints++
+		reachedBy[p]++
+		for dep := range TransitiveDependencies(g, p) {
+			reachedBy[dep]++
+		}
+	}
+	maxDependents := maxValue(dependents)
+	maxChurn := maxValue(opts.Churn)
+
+	zones := NewZoneClassifier(g)
+	analysis := &CriticalityAnalysis{byPath: make(map[string]*ModuleCriticality)}
+	for p, module := range g.Modules {
+		signals := CriticalitySignals{
+			FanIn:    ratio(dependents[p], maxDependents),
+			Security: float64(GetZoneRiskLevel(zones.ClassifyModule(module).Zone)) / 5,
+			Churn:    ratio(opts.Churn[p], maxChurn),
+			Coverage: 1 - testCoverage(g.Root, module),
+		}
+		if entryPoints > 0 {
+			signals.EntryPoints = ratio(reachedBy[p], entryPoints)
+		}
+
+		score := Round(opts.Config.score(signals))
+		level := opts.Config.level(score)
+		mc := &ModuleCriticality{
+			Path:           p,
+			Score:          score,
+			Level:          level,
+			Signals:        signals,
+			Owners:         ownerCount(module),
+			RequiredOwners: opts.Config.MinOwners[level],
+		}
+		analysis.Modules = append(analysis.Modules, mc)
+		analysis.byPath[p] = mc
+	}
+
+	sort.Slice(analysis.Modules, func(i, j int) bool {
+		a, b := analysis.Modules[i], analysis.Modules[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Path < b.Path
+	})
+
+	return analysis, nil
+}
+
+// Get returns the criticality of a module
+func (a *CriticalityAnalysis) Get(path string) (*ModuleCriticality, bool) {
+	mc, ok := a.byPath[path]
+	return mc, ok
+}
+
+// Scores returns each module's score by path
+func (a *CriticalityAnalysis) Scores() map[string]float64 {
+	scores := make(map[string]float64, len(a.Modules))
+	for _, mc := range a.Modules {
+		scores[mc.Path] = mc.Score
+	}
+	return scores
+}
+
+// AddTriples records scores, levels, owner counts and owner shortfalls in
+// the graph's triple store and returns the number of triples added
+func (a *CriticalityAnalysis) AddTriples(g *graph.Graph) int {
+	if g.Store == nil {
+		return 0
+	}
+
+	before := g.Store.Count()
+	for _, mc := range a.Modules {
+		module := g.Modules[mc.Path]
+		_ = g.Store.Add(module.URI, CriticalityPredicate, strconv.FormatFloat(mc.Score, 'f', 2, 64))
+		_ = g.Store.Add(module.URI, CriticalityLevelPredicate, mc.Level)
+		_ = g.Store.Add(module.URI, OwnerCountPredicate, strconv.Itoa(mc.Owners))
+		if shortfall := mc.OwnerShortfall(); shortfall > 0 {
+			_ = g.Store.Add(module.URI, OwnerShortfallPredicate, strconv.Itoa(shortfall))
+		}
+	}
+	return g.Store.Count() - before
+}
+
+// score combines signals with the configured weights
+func (c CriticalityConfig) score(s CriticalitySignals) float64 {
+	w := c.Weights
+	total := w.FanIn + w.EntryPoints + w.Security + w.Churn + w.Coverage
+	return (w.FanIn*s.FanIn + w.EntryPoints*s.EntryPoints + w.Security*s.Security +
+		w.Churn*s.Churn + w.Coverage*s.Coverage) / total
+}
+
+// level returns the level with the highest threshold the score reaches
+func (c CriticalityConfig) level(score float64) string {
+	level, best := lowLevel, -1.0
+	for name, threshold := range c.Levels {
+		if score >= threshold && (threshold > best || (threshold == best && name < level)) {
+			level, best = name, threshold
+		}
+	}
+	return level
+}
+
+// ownerCount counts distinct owners from code:owner (comma-separated
+// values allowed)
+func ownerCount(module *graph.Module) int {
+	return len(moduleOwners(module))
+}
+
+// moduleOwners returns the distinct owners of a module from code:owner,
+// code:owners and code:ownedBy (which includes CODEOWNERS), sorted
+func moduleOwners(module *graph.Module) []string {
+	owners := make(map[string]bool)
+	for predicate, values := range module.Properties {
+		if name := graph.LocalName(predicate); name != "owner" && name != "owners" && name != "ownedBy" {
+			continue
+		}
+		for _, value := range values {
+			for _, owner := range strings.Split(value, ",") {
+				if owner = strings.TrimSpace(owner); owner != "" {
+					owners[owner] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(owners)
+}
+
+// testCoverage returns declared code:coverage (a percentage or fraction),
+// otherwise 1 if a conventional test file sits next to the module and 0 if not
+func testCoverage(root string, module *graph.Module) float64 {
+	for predicate, values := range module.Properties {
+		if graph.LocalName(predicate) != "coverage" || len(values) == 0 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSuffix(values[0], "%"), 64); err == nil {
+			if v > 1 {
+				v /= 100
+			}
+			return math.Max(0, math.Min(1, v))
+		}
+	}
+
+	dir, base := path.Split(module.Path)
+	ext := path.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	candidates := []string{
+		name + "_test" + ext,
+		"test_" + base,
+		name + ".test" + ext,
+		name + ".spec" + ext,
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(dir), candidate)); err == nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ratio returns n/max, or 0 when max is 0
+func ratio(n, max int) float64 {
+	if max == 0 {
+		return 0
+	}
+	return float64(n) / float64(max)
+}
+
+// maxValue returns the largest value in a map
+func maxValue(m map[string]int) int {
+	max := 0
+	for _, v := range m {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// Round rounds a score or ratio to two decimals
+func Round(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,155 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// createCriticalityGraph builds:
+// main.go -> service.go -> db.go
+// util.go (isolated, tested)
+func createCriticalityGraph(t *testing.T) *graph.Graph {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "util_test.go"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := graph.NewGraph(root, store.NewTripleStore())
+	modules := []*graph.Module{
+		{Path: "main.go", URI: "<#main.go>", Dependencies: []string{"service.go"}},
+		{Path: "service.go", URI: "<#service.go>", Dependencies: []string{"db.go"},
+			Properties: map[string][]string{"https://schema.codedoc.org/owner": {"alice"}}},
+		{Path: "db.go", URI: "<#db.go>",
+			Properties: map[string][]string{"https://schema.codedoc.org/owners": {"alice, bob"}}},
+		{Path: "util.go", URI: "<#util.go>"},
+	}
+	for _, m := range modules {
+		g.AddModule(m)
+	}
+	return g
+}
+
+func TestScoreCriticality(t *testing.T) {
+	g := createCriticalityGraph(t)
+
+	result, err := ScoreCriticality(g, CriticalityOptions{
+		Config: DefaultCriticalityConfig(),
+		Churn:  map[string]int{"service.go": 10, "db.go": 5},
+	})
+	if err != nil {
+		t.Fatalf("ScoreCriticality failed: %v", err)
+	}
+
+	db, _ := result.Get("db.go")
+	if db.Signals.FanIn != 1 {
+		t.Errorf("db.go fan-in = %v, want 1", db.Signals.FanIn)
+	}
+	if db.Signals.EntryPoints != 1 {
+		t.Errorf("db.go entry points = %v, want 1", db.Signals.EntryPoints)
+	}
+	if db.Signals.Churn != 0.5 {
+		t.Errorf("db.go churn = %v, want 0.5", db.Signals.Churn)
+	}
+	if db.Owners != 2 {
+		t.Errorf("db.go owners = %d, want 2", db.Owners)
+	}
+
+	util, _ := result.Get("util.go")
+	if util.Signals.Coverage != 0 {
+		t.Errorf("util.go coverage signal = %v, want 0 (has test file)", util.Signals.Coverage)
+	}
+	if util.Level != "low" {
+		t.Errorf("util.go level = %s, want low", util.Level)
+	}
+
+	if result.Modules[len(result.Modules)-1].Path != "util.go" {
+		t.Errorf("expected util.go to be least critical, got %s", result.Modules[len(result.Modules)-1].Path)
+	}
+}
+
+func TestScoreCriticality_Weights(t *testing.T) {
+	g := createCriticalityGraph(t)
+
+	config := DefaultCriticalityConfig()
+	config.Weights = CriticalityWeights{Churn: 1}
+	result, err := ScoreCriticality(g, CriticalityOptions{
+		Config: config,
+		Churn:  map[string]int{"util.go": 4, "main.go": 1},
+	})
+	if err != nil {
+		t.Fatalf("ScoreCriticality failed: %v", err)
+	}
+
+	if result.Modules[0].Path != "util.go" || result.Modules[0].Score != 1 {
+		t.Errorf("expected util.go first with score 1, got %s (%v)", result.Modules[0].Path, result.Modules[0].Score)
+	}
+	if result.Modules[0].Level != "critical" {
+		t.Errorf("util.go level = %s, want critical", result.Modules[0].Level)
+	}
+	if main, _ := result.Get("main.go"); main.Score != 0.25 {
+		t.Errorf("main.go score = %v, want 0.25", main.Score)
+	}
+}
+
+func TestCriticalityAddTriples(t *testing.T) {
+	g := createCriticalityGraph(t)
+
+	config := DefaultCriticalityConfig()
+	config.Weights = CriticalityWeights{FanIn: 1}
+	config.MinOwners = map[string]int{"critical": 2}
+	result, err := ScoreCriticality(g, CriticalityOptions{Config: config})
+	if err != nil {
+		t.Fatalf("ScoreCriticality failed: %v", err)
+	}
+
+	if added := result.AddTriples(g); added == 0 {
+		t.Fatal("expected triples to be added")
+	}
+
+	// db.go is critical with two owners; service.go is below critical
+	if got := g.Store.Find("<#db.go>", CriticalityLevelPredicate, ""); len(got) != 1 || got[0].Object != "critical" {
+		t.Errorf("db.go level triples = %v", got)
+	}
+	if got := g.Store.Find("<#db.go>", OwnerShortfallPredicate, ""); len(got) != 0 {
+		t.Errorf("db.go should have no owner shortfall, got %v", got)
+	}
+	if got := g.Store.Find("<#db.go>", CriticalityPredicate, ""); len(got) != 1 || got[0].Object != "1.00" {
+		t.Errorf("db.go score triples = %v", got)
+	}
+
+	config.Levels = map[string]float64{"critical": 0.5}
+	result, _ = ScoreCriticality(g, CriticalityOptions{Config: config})
+	result.AddTriples(g)
+	if got := g.Store.Find("<#service.go>", OwnerShortfallPredicate, ""); len(got) != 1 || got[0].Object != "1" {
+		t.Errorf("service.go owner shortfall triples = %v", got)
+	}
+}
+
+func TestCriticalityConfigValidate(t *testing.T) {
+	config := DefaultCriticalityConfig()
+	if err := config.Validate(); err != nil {
+		t.Errorf("default config invalid: %v", err)
+	}
+
+	config.Weights = CriticalityWeights{}
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for all-zero weights")
+	}
+
+	config.Weights = CriticalityWeights{FanIn: 1, Churn: -1}
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for negative weight")
+	}
+
+	config = CriticalityConfig{Weights: CriticalityWeights{Churn: 1}}.WithDefaults()
+	if config.Weights.Churn != 1 || config.Weights.FanIn != 0 {
+		t.Errorf("WithDefaults overrode weights: %+v", config.Weights)
+	}
+	if config.Levels["critical"] != 0.7 || config.MinOwners["critical"] != 2 {
+		t.Errorf("WithDefaults did not fill levels and owners: %+v", config)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+# Module: pkg/analysis/cycles.go
+Dependency cycle enumeration.
+
+Groups cyclic modules into strongly connected components (Tarjan) and lists
+every elementary cycle within each component (Johnson), ranking components
+by size. Enumeration can be bounded, as dense components have exponentially
+many cycles.
+
+## Linked Modules
+- [graph_algorithms](./graph_algorithms.go) - Strongly connected components
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, cycles, graph-algorithms, dependencies
+
+## Exports
+CycleOptions, CycleReport, CycleComponent, FindCycles
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cycles.go> a code:Module ;
+    code:name "pkg/analysis/cycles.go" ;
+    code:description "Dependency cycle enumeration" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <./graph_algorithms.go>, <../graph/graph.go> ;
+    code:exports <#CycleOptions>, <#CycleReport>, <#CycleComponent>, <#FindCycles> ;
+    code:tags "analysis", "cycles", "graph-algorithms", "dependencies" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"sort"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// CycleOptions bounds cycle enumeration
+type CycleOptions struct {
+	MaxCycles     int     // Cycles listed per component (0 = all)
+	MaxLength     int     // Longest cycle listed, in modules (0 = any)
+	MinConfidence float64 // Ignore edges with a lower confidence, e.g. inferred imports (0 = all)
+}
+
+// CycleReport lists the dependency cycles of a graph
+type CycleReport struct {
+	Components  []CycleComponent `json:"components"` // Largest first
+	TotalCycles int              `json:"totalCycles"`
+	Truncated   bool             `json:"truncated"` // Some component hit MaxCycles
+}
+
+// CycleComponent is a strongly connected component with a cycle. Every
+// dependency between its modules is part of some cycle.
+type CycleComponent struct {
+	Modules   []string   `json:"modules"` // Sorted
+	Edges     int        `json:"edges"`   // Dependencies between the modules
+	Cycles    [][]string `json:"cycles"`  // Each starting at its smallest path, without repeating it
+	Truncated bool       `json:"truncated"`
+}
+
+// FindCycles returns every component of the graph with a dependency cycle
+// and the elementary cycles within it, shortest first
+func FindCycles(g *graph.Graph, opts CycleOptions) *CycleReport {
+	report := &CycleReport{Components: make([]CycleComponent, 0)}
+
+	for _, scc := range stronglyConnectedComponents(g, opts.MinConfidence) {
+		members := make(map[string]bool, len(scc))
+		for _, path := range scc {
+			if _, ok := g.Modules[path]; ok {
+				members[path] = true
+			}
+		}
+
+		// Dependencies within the component, deduplicated and sorted
+		adjacency := make(map[string][]string, len(members))
+		edges := 0
+		for path := range members {
+			seen := make(map[string]bool)
+			for _, edge := range g.Modules[path].DependencyEdges() {
+				dep := edge.Target
+				if edge.Confidence >= opts.MinConfidence && members[dep] && !seen[dep] {
+					seen[dep] = true
+					adjacency[path] = append(adjacency[path], dep)
+				}
+			}
+			sort.Strings(adjacency[path])
+			edges += len(adjacency[path])
+		}
+		if edges == 0 {
+			continue // A single module without a self-dependency
+		}
+
+		modules := make([]string, 0, len(members))
+		for path := range members {
+			modules = append(modules, path)
+		}
+		sort.Strings(modules)
+
+		cycles, truncated := elementaryCycles(modules, adjacency, opts)
+		report.Components = append(report.Components, CycleComponent{
+			Modules:   modules,
+			Edges:     edges,
+			Cycles:    cycles,
+			Truncated: truncated,
+		})
+		report.TotalCycles += len(cycles)
+		report.Truncated = report.Truncated || truncated
+	}
+
+	sort.SliceStable(report.Components, func(i, j int) bool {
+		a, b := report.Components[i], report.Components[j]
+		if len(a.Modules) != len(b.Modules) {
+			return len(a.Modules) > len(b.Modules)
+		}
+		if len(a.Cycles) != len(b.Cycles) {
+			return len(a.Cycles) > len(b.Cycles)
+		}
+		return a.Modules[0] < b.Modules[0]
+	})
+
+	return report
+}
+
+// elementaryCycles lists the cycles of a strongly connected component with
+// Johnson's algorithm: for each start module in order, circuits through
+// later modules only, blocking modules that cannot currently reach the start
+func elementaryCycles(modules []string, adjacency map[string][]string, opts CycleOptions) ([][]string, bool) {
+	order := make(map[string]int, len(modules))
+	for i, path := range modules {
+		order[path] = i
+	}
+
+	cycles := make([][]string, 0)
+	truncated := false
+
+	for startIndex, start := range modules {
+		blocked := make(map[string]bool)
+		blockedBy := make(map[string]map[string]bool)
+		stack := []string{}
+
+		var unblock func(path string)
+		unblock = func(path string) {
+			blocked[path] = false
+			for waiting := range blockedBy[path] {
+				delete(blockedBy[path], waiting)
+				if blocked[waiting] {
+					unblock(waiting)
+				}
+			}
+		}
+
+		var circuit func(path string) bool
+		circuit = func(path string) bool {
+			found := false
+			stack = append(stack, path)
+			blocked[path] = true
+
+			for _, next := range adjacency[path] {
+				if truncated || order[next] < startIndex {
+					continue
+				}
+				if next == start {
+					if opts.MaxLength <= 0 || len(stack) <= opts.MaxLength {
+						if opts.MaxCycles > 0 && len(cycles) >= opts.MaxCycles {
+							truncated = true
+							continue
+						}
+						cycles = append(cycles, append([]string(nil), stack...))
+					}
+					found = true
+				} else if !blocked[next] && (opts.MaxLength <= 0 || len(stack) < opts.MaxLength) {
+					if circuit(next) {
+						found = true
+					}
+				}
+			}
+
+			// Past MaxLength a module is left unblocked, as a shorter path
+			// to it may still close a cycle
+			if found || opts.MaxLength > 0 {
+				unblock(path)
+			} else {
+				for _, next := range adjacency[path] {
+					if order[next] < startIndex {
+						continue
+					}
+					if blockedBy[next] == nil {
+						blockedBy[next] = make(map[string]bool)
+					}
+					blockedBy[next][path] = true
+				}
+			}
+
+			stack = stack[:len(stack)-1]
+			return found
+		}
+
+		circuit(start)
+		if truncated {
+			break
+		}
+	}
+
+	sort.SliceStable(cycles, func(i, j int) bool {
+		return len(cycles[i]) < len(cycles[j])
+	})
+	return cycles, truncated
+}
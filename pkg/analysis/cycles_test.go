@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func createCycleGraph(deps map[string][]string) *graph.Graph {
+	g := &graph.Graph{Modules: make(map[string]*graph.Module)}
+	for path, dependencies := range deps {
+		g.Modules[path] = &graph.Module{Path: path, Dependencies: dependencies}
+	}
+	return g
+}
+
+func TestFindCycles(t *testing.T) {
+	g := createCycleGraph(map[string][]string{
+		"a.go": {"b.go", "b.go"},
+		"b.go": {"a.go", "c.go"},
+		"c.go": {"a.go", "c.go", "d.go"},
+		"d.go": {"missing.go"},
+		"e.go": {"f.go"},
+		"f.go": {"e.go"},
+	})
+
+	report := FindCycles(g, CycleOptions{})
+
+	if len(report.Components) != 2 || report.TotalCycles != 4 || report.Truncated {
+		t.Fatalf("Report = %+v, want 2 components and 4 cycles", report)
+	}
+	abc := report.Components[0]
+	if fmt.Sprint(abc.Modules) != "[a.go b.go c.go]" || abc.Edges != 5 {
+		t.Errorf("Largest component = %v with %d edges, want a, b, c with 5", abc.Modules, abc.Edges)
+	}
+	if fmt.Sprint(abc.Cycles) != "[[c.go] [a.go b.go] [a.go b.go c.go]]" {
+		t.Errorf("Cycles = %v", abc.Cycles)
+	}
+	if fmt.Sprint(report.Components[1].Cycles) != "[[e.go f.go]]" {
+		t.Errorf("Second component cycles = %v", report.Components[1].Cycles)
+	}
+}
+
+func TestFindCycles_MinConfidence(t *testing.T) {
+	g := createCycleGraph(map[string][]string{
+		"a.go": {"b.go"},
+		"b.go": {"c.go"},
+		"c.go": {},
+	})
+	g.Modules["b.go"].AddEdge(graph.Edge{Target: "a.go", Relation: graph.RelationImports, Inferred: true})
+	g.Modules["c.go"].AddEdge(graph.Edge{Target: "b.go", Relation: graph.RelationImports, Inferred: true, Confidence: graph.ConfidenceReferenced})
+
+	if report := FindCycles(g, CycleOptions{}); report.TotalCycles != 2 {
+		t.Errorf("TotalCycles = %d, want 2 with every edge", report.TotalCycles)
+	}
+
+	report := FindCycles(g, CycleOptions{MinConfidence: graph.ConfidenceReferenced})
+	if report.TotalCycles != 1 || fmt.Sprint(report.Components[0].Modules) != "[b.go c.go]" {
+		t.Errorf("Report = %+v, want only the b.go, c.go cycle", report)
+	}
+	if report := FindCycles(g, CycleOptions{MinConfidence: graph.ConfidenceDeclared}); len(report.Components) != 0 {
+		t.Errorf("Components = %+v, want none from declared edges", report.Components)
+	}
+}
+
+func TestFindCycles_CompleteGraph(t *testing.T) {
+	deps := make(map[string][]string)
+	paths := []string{"a", "b", "c", "d"}
+	for _, from := range paths {
+		for _, to := range paths {
+			if from != to {
+				deps[from] = append(deps[from], to)
+			}
+		}
+	}
+	g := createCycleGraph(deps)
+
+	// 6 two-module, 8 three-module and 6 four-module cycles
+	if report := FindCycles(g, CycleOptions{}); report.TotalCycles != 20 {
+		t.Errorf("TotalCycles = %d, want 20", report.TotalCycles)
+	}
+	if report := FindCycles(g, CycleOptions{MaxLength: 3}); report.TotalCycles != 14 {
+		t.Errorf("TotalCycles with MaxLength 3 = %d, want 14", report.TotalCycles)
+	}
+	report := FindCycles(g, CycleOptions{MaxCycles: 5})
+	if report.TotalCycles != 5 || !report.Truncated || !report.Components[0].Truncated {
+		t.Errorf("MaxCycles 5 = %d cycles, truncated %v", report.TotalCycles, report.Truncated)
+	}
+}
+
+func TestFindCycles_Acyclic(t *testing.T) {
+	g := createTestGraphForImpact()
+
+	if report := FindCycles(g, CycleOptions{}); len(report.Components) != 0 || report.TotalCycles != 0 {
+		t.Errorf("Acyclic graph report = %+v", report)
+	}
+}
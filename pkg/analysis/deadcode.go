@@ -80,11 +80,12 @@ type DeadSymbol struct {
 
 // DeadCodeOptions configures dead code detection
 type DeadCodeOptions struct {
-	MinConfidence   float64  // Minimum confidence threshold (0.0-1.0)
-	ExcludePatterns []string // Glob patterns to exclude
-	AggressiveMode  bool     // More aggressive detection
-	ConsiderFileAge bool     // Factor in file modification time
-	MaxFileAgeDays  int      // Files older than this are more likely dead (default: 180)
+	MinConfidence    float64  // Minimum confidence threshold (0.0-1.0)
+	ExcludePatterns  []string // Glob patterns to exclude
+	AggressiveMode   bool     // More aggressive detection
+	ConsiderFileAge  bool     // Factor in file modification time
+	MaxFileAgeDays   int      // Files older than this are more likely dead (default: 180)
+	IncludeGenerated bool     // Also report generated code (skipped by default)
 }
 
 // Detector performs dead code detection
@@ -160,7 +161,8 @@ func (d *Detector) findUnreferencedModules() []*DeadModule {
 	// Build reverse dependency map (who depends on whom)
 	dependents := make(map[string][]string)
 	for _, module := range d.graph.Modules {
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
 			dependents[dep] = append(dependents[dep], module.Path)
 		}
 	}
@@ -177,6 +179,11 @@ func (d *Detector) findUnreferencedModules() []*DeadModule {
 			continue
 		}
 
+		// Skip generated code - it is regenerated, not hand-maintained
+		if module.IsGenerated() && !d.options.IncludeGenerated {
+			continue
+		}
+
 		// ALWAYS skip test files - they're run by go test, not imported
 		// Test files should never be flagged as dead code
 		if d.isTestFile(module.Path) {
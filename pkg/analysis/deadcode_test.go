@@ -162,6 +162,48 @@ func TestDetector_FindUnreferencedModules(t *testing.T) {
 	}
 }
 
+func TestDetector_SkipsGeneratedModules(t *testing.T) {
+	g := createTestGraphForDeadCode()
+
+	generated := &graph.Module{
+		Path:       "api/service.pb.go",
+		URI:        "<#service.pb.go>",
+		Name:       "service.pb.go",
+		Layer:      "api",
+		Exports:    []string{"ServiceClient"},
+		Properties: map[string][]string{graph.GeneratedPredicate: {"true"}},
+	}
+	g.AddModule(generated)
+
+	isReported := func(modules []*DeadModule) bool {
+		for _, dm := range modules {
+			if dm.Module.Path == generated.Path {
+				return true
+			}
+		}
+		return false
+	}
+
+	defaultModules := NewDetector(g, DeadCodeOptions{MinConfidence: 0.1}).findUnreferencedModules()
+	if isReported(defaultModules) {
+		t.Error("Generated module should be skipped by default")
+	}
+
+	includeModules := NewDetector(g, DeadCodeOptions{MinConfidence: 0.1, IncludeGenerated: true}).findUnreferencedModules()
+	if !isReported(includeModules) {
+		t.Error("Generated module should be reported with IncludeGenerated")
+	}
+
+	coverage := AnalyzeCoverage(g)
+	if coverage.TotalModules != len(g.Modules)-1 {
+		t.Errorf("Expected coverage to exclude generated module, got %d of %d modules", coverage.TotalModules, len(g.Modules))
+	}
+	coverage = AnalyzeCoverageWithOptions(g, CoverageOptions{IncludeGenerated: true})
+	if coverage.TotalModules != len(g.Modules) {
+		t.Errorf("Expected coverage to include generated module, got %d of %d modules", coverage.TotalModules, len(g.Modules))
+	}
+}
+
 func TestDetector_IsEntryPoint(t *testing.T) {
 	g := createTestGraphForDeadCode()
 	opts := DeadCodeOptions{}
@@ -0,0 +1,148 @@
+/*
+# Module: pkg/analysis/debt.go
+Outstanding debt from TODO, FIXME and DEPRECATED markers.
+
+Summarizes the comment markers recorded on modules per module and per owner
+(from owner/owners annotations), with the modules carrying the most markers
+first.
+
+## Linked Modules
+- [../graph](../graph/markers.go) - Comment markers
+- [criticality](./criticality.go) - Owner annotations
+
+## Tags
+analysis, technical-debt, ownership
+
+## Exports
+DebtSummary, ModuleDebt, OwnerDebt, DebtCounts, SummarizeDebt
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#debt.go> a code:Module ;
+    code:name "pkg/analysis/debt.go" ;
+    code:description "Outstanding debt from TODO, FIXME and DEPRECATED markers" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/markers.go>, <./criticality.go> ;
+    code:exports <#DebtSummary>, <#ModuleDebt>, <#OwnerDebt>, <#DebtCounts>, <#SummarizeDebt> ;
+    code:tags "analysis", "technical-debt", "ownership" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"sort"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Unowned is the owner of debt in modules without owner annotations
+const Unowned = "(unowned)"
+
+// DebtCounts counts markers by kind
+type DebtCounts struct {
+	TODO       int `json:"todo"`
+	FIXME      int `json:"fixme"`
+	Deprecated int `json:"deprecated"`
+}
+
+// Total returns the number of markers
+func (c DebtCounts) Total() int {
+	return c.TODO + c.FIXME + c.Deprecated
+}
+
+func (c *DebtCounts) add(kind string) {
+	switch kind {
+	case graph.MarkerTODO:
+		c.TODO++
+	case graph.MarkerFIXME:
+		c.FIXME++
+	case graph.MarkerDeprecated:
+		c.Deprecated++
+	}
+}
+
+// ModuleDebt is the outstanding debt of one module
+type ModuleDebt struct {
+	Path    string         `json:"path"`
+	Owners  []string       `json:"owners,omitempty"`
+	Counts  DebtCounts     `json:"counts"`
+	Markers []graph.Marker `json:"markers"`
+}
+
+// OwnerDebt is the outstanding debt in the modules of one owner
+type OwnerDebt struct {
+	Owner   string     `json:"owner"`
+	Modules int        `json:"modules"`
+	Counts  DebtCounts `json:"counts"`
+}
+
+// DebtSummary is the outstanding debt of a graph
+type DebtSummary struct {
+	Counts  DebtCounts   `json:"counts"`
+	Modules []ModuleDebt `json:"modules"` // Most markers first
+	Owners  []OwnerDebt  `json:"owners"`  // Most markers first
+}
+
+// SummarizeDebt summarizes the markers of every module in the graph
+func SummarizeDebt(g *graph.Graph) *DebtSummary {
+	summary := &DebtSummary{}
+	owners := make(map[string]*OwnerDebt)
+
+	for _, module := range g.Modules {
+		if len(module.Markers) == 0 {
+			continue
+		}
+
+		debt := ModuleDebt{
+			Path:    module.Path,
+			Owners:  moduleOwners(module),
+			Markers: module.Markers,
+		}
+		for _, marker := range module.Markers {
+			debt.Counts.add(marker.Kind)
+			summary.Counts.add(marker.Kind)
+		}
+		summary.Modules = append(summary.Modules, debt)
+
+		moduleOwnerNames := debt.Owners
+		if len(moduleOwnerNames) == 0 {
+			moduleOwnerNames = []string{Unowned}
+		}
+		for _, owner := range moduleOwnerNames {
+			od := owners[owner]
+			if od == nil {
+				od = &OwnerDebt{Owner: owner}
+				owners[owner] = od
+			}
+			od.Modules++
+			od.Counts.TODO += debt.Counts.TODO
+			od.Counts.FIXME += debt.Counts.FIXME
+			od.Counts.Deprecated += debt.Counts.Deprecated
+		}
+	}
+
+	sort.Slice(summary.Modules, func(i, j int) bool {
+		a, b := summary.Modules[i], summary.Modules[j]
+		if a.Counts.Total() != b.Counts.Total() {
+			return a.Counts.Total() > b.Counts.Total()
+		}
+		return a.Path < b.Path
+	})
+
+	for _, od := range owners {
+		summary.Owners = append(summary.Owners, *od)
+	}
+	sort.Slice(summary.Owners, func(i, j int) bool {
+		a, b := summary.Owners[i], summary.Owners[j]
+		if a.Counts.Total() != b.Counts.Total() {
+			return a.Counts.Total() > b.Counts.Total()
+		}
+		return a.Owner < b.Owner
+	})
+
+	return summary
+}
@@ -0,0 +1,45 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestSummarizeDebt(t *testing.T) {
+	g := graph.NewGraph(t.TempDir(), store.NewTripleStore())
+	owner := map[string][]string{"https://schema.codedoc.org/owners": {"alice, bob"}}
+	modules := []*graph.Module{
+		{Path: "a.go", URI: "<#a.go>", Properties: owner, Markers: []graph.Marker{
+			{Kind: graph.MarkerTODO, Line: 3},
+			{Kind: graph.MarkerFIXME, Line: 9},
+		}},
+		{Path: "b.go", URI: "<#b.go>", Markers: []graph.Marker{
+			{Kind: graph.MarkerDeprecated, Line: 1},
+		}},
+		{Path: "c.go", URI: "<#c.go>"},
+	}
+	for _, m := range modules {
+		g.AddModule(m)
+	}
+
+	debt := SummarizeDebt(g)
+	if debt.Counts != (DebtCounts{TODO: 1, FIXME: 1, Deprecated: 1}) {
+		t.Errorf("Counts = %+v", debt.Counts)
+	}
+	if len(debt.Modules) != 2 || debt.Modules[0].Path != "a.go" || debt.Modules[0].Counts.Total() != 2 {
+		t.Errorf("Modules = %+v, want a.go first with 2 markers", debt.Modules)
+	}
+
+	owners := make(map[string]OwnerDebt)
+	for _, od := range debt.Owners {
+		owners[od.Owner] = od
+	}
+	if len(owners) != 3 || owners["alice"].Counts.Total() != 2 || owners["bob"].Counts.FIXME != 1 {
+		t.Errorf("Owners = %+v", debt.Owners)
+	}
+	if owners[Unowned].Modules != 1 || owners[Unowned].Counts.Deprecated != 1 {
+		t.Errorf("Unowned debt = %+v", owners[Unowned])
+	}
+}
@@ -0,0 +1,257 @@
+/*
+# Module: pkg/analysis/duplicates.go
+Module similarity and duplicate-concept detection.
+
+Scores module pairs by the tags, concepts, exports and dependencies they
+share, weighting each shared value by how rare it is in the project, and
+clusters pairs above a threshold to flag likely duplicated functionality
+(e.g. two "crypto utils" modules).
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, similarity, duplicates
+
+## Exports
+DuplicateOptions, DuplicateReport, DuplicateCluster, SimilarPair, FindDuplicates
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#duplicates.go> a code:Module ;
+    code:name "pkg/analysis/duplicates.go" ;
+    code:description "Module similarity and duplicate-concept detection" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go> ;
+    code:exports <#DuplicateOptions>, <#DuplicateReport>, <#DuplicateCluster>, <#SimilarPair>, <#FindDuplicates> ;
+    code:tags "analysis", "similarity", "duplicates" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Similarity facets and their weights
+const (
+	facetTags         = "tags"
+	facetConcepts     = "concepts"
+	facetExports      = "exports"
+	facetDependencies = "dependencies"
+)
+
+var facetWeights = map[string]float64{
+	facetTags:         0.3,
+	facetConcepts:     0.3,
+	facetExports:      0.2,
+	facetDependencies: 0.2,
+}
+
+var facetOrder = []string{facetTags, facetConcepts, facetExports, facetDependencies}
+
+// DuplicateOptions configures duplicate detection
+type DuplicateOptions struct {
+	// MinScore is the similarity (0-1) from which a pair is reported
+	// (default: 0.5)
+	MinScore float64
+
+	// Concepts are the concepts of each module, keyed by path, e.g. from
+	// shadow metadata
+	Concepts map[string][]string
+}
+
+// DuplicateReport lists clusters of similar modules, most similar first
+type DuplicateReport struct {
+	Clusters []DuplicateCluster `json:"clusters"`
+	Facets   []string           `json:"facets"` // Facets with values in the project
+}
+
+// DuplicateCluster is a group of modules connected by similar pairs
+type DuplicateCluster struct {
+	Modules []string      `json:"modules"`
+	Score   float64       `json:"score"` // Highest pair score
+	Pairs   []SimilarPair `json:"pairs"`
+}
+
+// SimilarPair is a pair of modules scoring above the threshold
+type SimilarPair struct {
+	A      string              `json:"a"`
+	B      string              `json:"b"`
+	Score  float64             `json:"score"`
+	Shared map[string][]string `json:"shared"` // Shared values by facet
+}
+
+// FindDuplicates scores every pair of modules sharing at least one value and
+// clusters the pairs scoring at least opts.MinScore.
+//
+// Each facet is an IDF-weighted Jaccard similarity, so values most modules
+// share (a "cli" tag) count less than rare ones ("crypto"). Facets no module
+// in the project has are left out, so projects without concepts are not
+// penalized, while a pair sharing only tags cannot score above the tag weight.
+func FindDuplicates(g *graph.Graph, opts DuplicateOptions) *DuplicateReport {
+	if opts.MinScore <= 0 {
+		opts.MinScore = 0.5
+	}
+
+	paths := make([]string, 0, len(g.Modules))
+	for path := range g.Modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	features := make(map[string]map[string]map[string]bool, len(paths))
+	for _, path := range paths {
+		module := g.Modules[path]
+		exports := make([]string, 0, len(module.Exports))
+		for _, export := range module.Exports {
+			exports = append(exports, strings.ToLower(export))
+		}
+		features[path] = map[string]map[string]bool{
+			facetTags:         valueSet(module.Tags),
+			facetConcepts:     valueSet(opts.Concepts[path]),
+			facetExports:      valueSet(exports),
+			facetDependencies: valueSet(module.Dependencies),
+		}
+	}
+
+	// Document frequencies and an inverted index of candidate pairs
+	df := make(map[string]map[string]int)
+	index := make(map[string][]string)
+	for _, facet := range facetOrder {
+		df[facet] = make(map[string]int)
+	}
+	for _, path := range paths {
+		for _, facet := range facetOrder {
+			for value := range features[path][facet] {
+				df[facet][value]++
+				index[facet+"\x00"+value] = append(index[facet+"\x00"+value], path)
+			}
+		}
+	}
+
+	report := &DuplicateReport{Clusters: make([]DuplicateCluster, 0), Facets: make([]string, 0)}
+	totalWeight := 0.0
+	for _, facet := range facetOrder {
+		if len(df[facet]) > 0 {
+			report.Facets = append(report.Facets, facet)
+			totalWeight += facetWeights[facet]
+		}
+	}
+	if totalWeight == 0 {
+		return report
+	}
+
+	idf := func(facet, value string) float64 {
+		return math.Log(1 + float64(len(paths))/float64(df[facet][value]))
+	}
+
+	candidates := make(map[[2]string]bool)
+	for _, members := range index {
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				candidates[[2]string{members[i], members[j]}] = true
+			}
+		}
+	}
+
+	var pairs []SimilarPair
+	for candidate := range candidates {
+		a, b := features[candidate[0]], features[candidate[1]]
+		score := 0.0
+		shared := make(map[string][]string)
+		for _, facet := range report.Facets {
+			intersection, union := 0.0, 0.0
+			for value := range a[facet] {
+				union += idf(facet, value)
+				if b[facet][value] {
+					intersection += idf(facet, value)
+					shared[facet] = append(shared[facet], value)
+				}
+			}
+			for value := range b[facet] {
+				if !a[facet][value] {
+					union += idf(facet, value)
+				}
+			}
+			if union > 0 {
+				score += facetWeights[facet] * intersection / union
+			}
+			sort.Strings(shared[facet])
+		}
+		score = Round(score / totalWeight)
+		if score >= opts.MinScore {
+			pairs = append(pairs, SimilarPair{A: candidate[0], B: candidate[1], Score: score, Shared: shared})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+
+	// Union-find over the reported pairs
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(path string) string {
+		if parent[path] == "" || parent[path] == path {
+			return path
+		}
+		parent[path] = find(parent[path])
+		return parent[path]
+	}
+	for _, pair := range pairs {
+		if ra, rb := find(pair.A), find(pair.B); ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	clusters := make(map[string]*DuplicateCluster)
+	var roots []string
+	for _, pair := range pairs {
+		root := find(pair.A)
+		cluster, ok := clusters[root]
+		if !ok {
+			cluster = &DuplicateCluster{Score: pair.Score}
+			clusters[root] = cluster
+			roots = append(roots, root)
+		}
+		cluster.Pairs = append(cluster.Pairs, pair)
+		for _, path := range []string{pair.A, pair.B} {
+			if !slices.Contains(cluster.Modules, path) {
+				cluster.Modules = append(cluster.Modules, path)
+			}
+		}
+	}
+	for _, root := range roots {
+		cluster := clusters[root]
+		sort.Strings(cluster.Modules)
+		report.Clusters = append(report.Clusters, *cluster)
+	}
+
+	return report
+}
+
+// valueSet returns the non-empty values as a set
+func valueSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		if value != "" {
+			set[value] = true
+		}
+	}
+	return set
+}
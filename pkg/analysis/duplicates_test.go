@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	g := &graph.Graph{Modules: map[string]*graph.Module{
+		"auth/crypto.go":    {Path: "auth/crypto.go", Tags: []string{"utils", "crypto"}, Exports: []string{"Hash", "Encrypt"}, Dependencies: []string{"vendor/aes.go"}},
+		"billing/crypto.go": {Path: "billing/crypto.go", Tags: []string{"utils", "crypto"}, Exports: []string{"hash", "Decrypt"}, Dependencies: []string{"vendor/aes.go"}},
+		"shared/cipher.go":  {Path: "shared/cipher.go", Tags: []string{"utils"}, Exports: []string{"Encrypt"}},
+		"api/users.go":      {Path: "api/users.go", Tags: []string{"utils", "http"}, Exports: []string{"List"}},
+		"api/orders.go":     {Path: "api/orders.go", Tags: []string{"utils", "http"}, Exports: []string{"Create"}},
+		"vendor/aes.go":     {Path: "vendor/aes.go"},
+	}}
+	concepts := map[string][]string{
+		"auth/crypto.go":    {"encryption"},
+		"billing/crypto.go": {"encryption"},
+		"shared/cipher.go":  {"encryption"},
+	}
+
+	report := FindDuplicates(g, DuplicateOptions{MinScore: 0.5, Concepts: concepts})
+
+	if len(report.Facets) != 4 {
+		t.Errorf("Facets = %v, want all four", report.Facets)
+	}
+	if len(report.Clusters) != 1 {
+		t.Fatalf("Clusters = %+v, want the crypto modules only", report.Clusters)
+	}
+	cluster := report.Clusters[0]
+	if len(cluster.Modules) != 3 || cluster.Modules[0] != "auth/crypto.go" || cluster.Modules[2] != "shared/cipher.go" {
+		t.Errorf("Cluster modules = %v", cluster.Modules)
+	}
+	top := cluster.Pairs[0]
+	if top.A != "auth/crypto.go" || top.B != "billing/crypto.go" || top.Score != cluster.Score {
+		t.Errorf("Top pair = %+v", top)
+	}
+	if shared := top.Shared["exports"]; len(shared) != 1 || shared[0] != "hash" {
+		t.Errorf("Shared exports = %v, want case-insensitive hash", shared)
+	}
+
+	// Without concepts the concept facet is left out and the http handlers,
+	// sharing only tags, stay below the threshold
+	report = FindDuplicates(g, DuplicateOptions{})
+	if len(report.Facets) != 3 {
+		t.Errorf("Facets = %v, want no concepts", report.Facets)
+	}
+	for _, cluster := range report.Clusters {
+		for _, path := range cluster.Modules {
+			if path == "api/users.go" {
+				t.Errorf("Tag-only pair reported: %+v", cluster)
+			}
+		}
+	}
+}
@@ -0,0 +1,277 @@
+/*
+# Module: pkg/analysis/fitness.go
+Layer conformance scoring for architecture fitness reports.
+
+Compares declared layers, ordered from the top (e.g. cli, service, data),
+against actual dependency directions. A dependency conforms when it stays in
+its layer or points down; conformance is the share of conforming
+dependencies per layer and overall. Reports are kept in a history so
+conformance can be tracked over time.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, layers, architecture, fitness
+
+## Exports
+FitnessReport, LayerConformance, LayerViolation, AssessFitness, FitnessEntry, FitnessHistory, LoadFitnessHistory
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#fitness.go> a code:Module ;
+    code:name "pkg/analysis/fitness.go" ;
+    code:description "Layer conformance scoring for architecture fitness reports" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go> ;
+    code:exports <#FitnessReport>, <#LayerConformance>, <#LayerViolation>, <#AssessFitness>, <#FitnessEntry>, <#FitnessHistory>, <#LoadFitnessHistory> ;
+    code:tags "analysis", "layers", "architecture", "fitness" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// maxFitnessViolations is how many violations the Markdown report lists
+const maxFitnessViolations = 50
+
+// FitnessReport is the layer conformance of a graph
+type FitnessReport struct {
+	Date         string             `json:"date,omitempty"`
+	Layers       []string           `json:"layers"` // Declared order, top first
+	PerLayer     []LayerConformance `json:"perLayer"`
+	Dependencies int                `json:"dependencies"` // Between modules of declared layers
+	Conforming   int                `json:"conforming"`
+	Conformance  float64            `json:"conformance"` // Percent, 100 without dependencies
+	Unlayered    int                `json:"unlayered"`   // Modules outside the declared layers
+	Violations   []LayerViolation   `json:"violations"`
+}
+
+// LayerConformance is the conformance of the dependencies leaving a layer
+type LayerConformance struct {
+	Layer        string  `json:"layer"`
+	Modules      int     `json:"modules"`
+	Dependencies int     `json:"dependencies"`
+	Conforming   int     `json:"conforming"`
+	Conformance  float64 `json:"conformance"`
+}
+
+// LayerViolation is a dependency pointing up the declared layers
+type LayerViolation struct {
+	From      string `json:"from"`
+	FromLayer string `json:"fromLayer"`
+	To        string `json:"to"`
+	ToLayer   string `json:"toLayer"`
+}
+
+// AssessFitness scores the dependencies between modules of the declared
+// layers, given from the top. Dependencies involving other modules are not
+// counted.
+func AssessFitness(g *graph.Graph, layers []string) (*FitnessReport, error) {
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("no layers declared")
+	}
+	rank := make(map[string]int, len(layers))
+	for i, layer := range layers {
+		if _, dup := rank[layer]; dup || layer == "" {
+			return nil, fmt.Errorf("invalid layer order: %q is empty or listed twice", layer)
+		}
+		rank[layer] = i
+	}
+
+	perLayer := make(map[string]*LayerConformance, len(layers))
+	report := &FitnessReport{Layers: layers, Violations: make([]LayerViolation, 0)}
+	for _, layer := range layers {
+		perLayer[layer] = &LayerConformance{Layer: layer}
+	}
+
+	paths := make([]string, 0, len(g.Modules))
+	for path := range g.Modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		module := g.Modules[path]
+		fromRank, ok := rank[module.Layer]
+		if !ok {
+			report.Unlayered++
+			continue
+		}
+		layer := perLayer[module.Layer]
+		layer.Modules++
+
+		seen := make(map[string]bool)
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
+			target, exists := g.Modules[dep]
+			if !exists || seen[dep] || dep == path {
+				continue
+			}
+			seen[dep] = true
+			toRank, ok := rank[target.Layer]
+			if !ok {
+				continue
+			}
+			layer.Dependencies++
+			if toRank >= fromRank {
+				layer.Conforming++
+				continue
+			}
+			report.Violations = append(report.Violations, LayerViolation{
+				From: path, FromLayer: module.Layer, To: dep, ToLayer: target.Layer,
+			})
+		}
+	}
+
+	for _, name := range layers {
+		layer := perLayer[name]
+		layer.Conformance = conformance(layer.Conforming, layer.Dependencies)
+		report.PerLayer = append(report.PerLayer, *layer)
+		report.Dependencies += layer.Dependencies
+		report.Conforming += layer.Conforming
+	}
+	report.Conformance = conformance(report.Conforming, report.Dependencies)
+
+	return report, nil
+}
+
+// conformance returns a percentage with one decimal, 100 for no dependencies
+func conformance(conforming, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return math.Round(float64(conforming)/float64(total)*1000) / 10
+}
+
+// Markdown renders the report, with a trend table when history is given
+func (r *FitnessReport) Markdown(history FitnessHistory) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Architecture fitness\n\n")
+	if r.Date != "" {
+		fmt.Fprintf(&sb, "**Date:** %s\n", r.Date)
+	}
+	fmt.Fprintf(&sb, "**Layer order:** %s\n", strings.Join(r.Layers, " → "))
+	fmt.Fprintf(&sb, "**Conformance:** %.1f%% (%d of %d dependencies point down or stay within a layer)\n",
+		r.Conformance, r.Conforming, r.Dependencies)
+	if r.Unlayered > 0 {
+		fmt.Fprintf(&sb, "\n%d module(s) outside the declared layers are not scored.\n", r.Unlayered)
+	}
+
+	sb.WriteString("\n## By layer\n\n")
+	sb.WriteString("| Layer | Modules | Dependencies | Conforming | Conformance |\n|---|---|---|---|---|\n")
+	for _, layer := range r.PerLayer {
+		fmt.Fprintf(&sb, "| %s | %d | %d | %d | %.1f%% |\n",
+			layer.Layer, layer.Modules, layer.Dependencies, layer.Conforming, layer.Conformance)
+	}
+
+	fmt.Fprintf(&sb, "\n## Violations (%d)\n\n", len(r.Violations))
+	if len(r.Violations) == 0 {
+		sb.WriteString("None.\n")
+	}
+	for i, v := range r.Violations {
+		if i == maxFitnessViolations {
+			fmt.Fprintf(&sb, "- ... and %d more\n", len(r.Violations)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "- `%s` (%s) → `%s` (%s)\n", v.From, v.FromLayer, v.To, v.ToLayer)
+	}
+
+	if len(history) > 0 {
+		sb.WriteString("\n## Trend\n\n")
+		sb.WriteString("| Date | Overall | " + strings.Join(r.Layers, " | ") + " |\n")
+		sb.WriteString("|---|---|" + strings.Repeat("---|", len(r.Layers)) + "\n")
+		for _, entry := range history {
+			fmt.Fprintf(&sb, "| %s | %.1f%% |", entry.Date, entry.Conformance)
+			for _, layer := range r.Layers {
+				if value, ok := entry.Layers[layer]; ok {
+					fmt.Fprintf(&sb, " %.1f%% |", value)
+				} else {
+					sb.WriteString(" - |")
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// FitnessEntry is one report in the history
+type FitnessEntry struct {
+	Date        string             `json:"date"`
+	Conformance float64            `json:"conformance"`
+	Layers      map[string]float64 `json:"layers"`
+}
+
+// Entry returns the history entry of the report
+func (r *FitnessReport) Entry() FitnessEntry {
+	entry := FitnessEntry{Date: r.Date, Conformance: r.Conformance, Layers: make(map[string]float64, len(r.PerLayer))}
+	for _, layer := range r.PerLayer {
+		entry.Layers[layer.Layer] = layer.Conformance
+	}
+	return entry
+}
+
+// FitnessHistory is a list of reports, oldest first
+type FitnessHistory []FitnessEntry
+
+// LoadFitnessHistory reads a history file; a missing file is an empty history
+func LoadFitnessHistory(filePath string) (FitnessHistory, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fitness history: %w", err)
+	}
+	var history FitnessHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse fitness history: %w", err)
+	}
+	return history, nil
+}
+
+// Add records an entry, replacing an entry of the same date, keeping the
+// history sorted by date
+func (h FitnessHistory) Add(entry FitnessEntry) FitnessHistory {
+	for i := range h {
+		if h[i].Date == entry.Date {
+			h[i] = entry
+			return h
+		}
+	}
+	h = append(h, entry)
+	sort.SliceStable(h, func(i, j int) bool { return h[i].Date < h[j].Date })
+	return h
+}
+
+// Save writes the history to a file
+func (h FitnessHistory) Save(filePath string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fitness history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write fitness history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssessFitness(t *testing.T) {
+	g := createTestGraphForImpact()
+	// A utility reaching up into the service layer, and an unlayered module
+	g.Modules["utils/utilsB.go"].Dependencies = append(g.Modules["utils/utilsB.go"].Dependencies, "services/serviceA.go")
+	g.Modules["core/core.go"].Layer = ""
+
+	report, err := AssessFitness(g, []string{"handlers", "services", "utils"})
+	if err != nil {
+		t.Fatalf("AssessFitness() error = %v", err)
+	}
+
+	// handlers: 2 down; services: 2 down; utils: 1 up (utilsB -> serviceA),
+	// dependencies on the unlayered core are not counted
+	if report.Dependencies != 5 || report.Conforming != 4 || report.Conformance != 80 || report.Unlayered != 1 {
+		t.Errorf("Report = %d of %d (%.1f%%), %d unlayered", report.Conforming, report.Dependencies, report.Conformance, report.Unlayered)
+	}
+	utils := report.PerLayer[2]
+	if utils.Layer != "utils" || utils.Modules != 3 || utils.Dependencies != 1 || utils.Conformance != 0 {
+		t.Errorf("utils = %+v", utils)
+	}
+	if len(report.Violations) != 1 || report.Violations[0].From != "utils/utilsB.go" || report.Violations[0].ToLayer != "services" {
+		t.Errorf("Violations = %+v", report.Violations)
+	}
+
+	report.Date = "2026-01-02"
+	history := FitnessHistory{{Date: "2026-01-01", Conformance: 100, Layers: map[string]float64{"handlers": 100}}}.Add(report.Entry())
+	markdown := report.Markdown(history)
+	for _, want := range []string{"**Conformance:** 80.0% (4 of 5", "| utils | 3 | 1 | 0 | 0.0% |",
+		"- `utils/utilsB.go` (utils) → `services/serviceA.go` (services)", "| 2026-01-01 | 100.0% | 100.0% | - | - |",
+		"| 2026-01-02 | 80.0% | 100.0% | 100.0% | 0.0% |"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, markdown)
+		}
+	}
+
+	if _, err := AssessFitness(g, []string{"utils", "utils"}); err == nil {
+		t.Error("Expected an error for a duplicate layer")
+	}
+}
+
+func TestFitnessHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fitness", "history.json")
+
+	history, err := LoadFitnessHistory(file)
+	if err != nil || len(history) != 0 {
+		t.Fatalf("LoadFitnessHistory() of a missing file = %v, %v", history, err)
+	}
+
+	history = history.Add(FitnessEntry{Date: "2026-02-01", Conformance: 90})
+	history = history.Add(FitnessEntry{Date: "2026-01-01", Conformance: 80})
+	history = history.Add(FitnessEntry{Date: "2026-02-01", Conformance: 95})
+	if err := history.Save(file); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadFitnessHistory(file)
+	if err != nil {
+		t.Fatalf("LoadFitnessHistory() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Date != "2026-01-01" || loaded[1].Conformance != 95 {
+		t.Errorf("History = %+v", loaded)
+	}
+}
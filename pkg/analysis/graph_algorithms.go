@@ -56,7 +56,8 @@ func TopologicalSort(g *graph.Graph) ([]*graph.Module, error) {
 
 	// Build graph structure
 	for path, module := range g.Modules {
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
 			adjList[dep] = append(adjList[dep], path)
 			inDegree[path]++
 		}
@@ -135,7 +136,8 @@ func ShortestPath(g *graph.Graph, fromPath, toPath string) []string {
 		module := g.Modules[current]
 
 		// Check all dependencies
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
 			if visited[dep] {
 				continue
 			}
@@ -159,6 +161,12 @@ func ShortestPath(g *graph.Graph, fromPath, toPath string) []string {
 // An SCC is a maximal set of modules where each module is reachable from every other module.
 // Returns a slice of SCCs, where each SCC is a slice of module paths.
 func StronglyConnectedComponents(g *graph.Graph) [][]string {
+	return stronglyConnectedComponents(g, 0)
+}
+
+// stronglyConnectedComponents finds the SCCs formed by the dependency edges
+// with at least minConfidence
+func stronglyConnectedComponents(g *graph.Graph, minConfidence float64) [][]string {
 	// Tarjan's algorithm for finding SCCs
 	index := 0
 	stack := []string{}
@@ -177,7 +185,11 @@ func StronglyConnectedComponents(g *graph.Graph) [][]string {
 
 		// Consider successors (dependencies)
 		if module, exists := g.Modules[v]; exists {
-			for _, w := range module.Dependencies {
+			for _, edge := range module.DependencyEdges() {
+				if edge.Confidence < minConfidence {
+					continue
+				}
+				w := edge.Target
 				if _, visited := indices[w]; !visited {
 					strongConnect(w)
 					if lowLinks[w] < lowLinks[v] {
@@ -253,7 +265,8 @@ func TransitiveDependencies(g *graph.Graph, modulePath string) map[string]int {
 
 		// Add dependencies to queue
 		if module, exists := g.Modules[current.path]; exists {
-			for _, dep := range module.Dependencies {
+			for _, edge := range module.DependencyEdges() {
+				dep := edge.Target
 				queue = append(queue, struct {
 					path  string
 					depth int
@@ -279,7 +292,8 @@ func TransitiveDependents(g *graph.Graph, modulePath string) map[string]int {
 	// Build reverse dependency graph
 	reverseDeps := make(map[string][]string)
 	for path, module := range g.Modules {
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
 			reverseDeps[dep] = append(reverseDeps[dep], path)
 		}
 	}
@@ -329,9 +343,13 @@ func CyclicDependencies(g *graph.Graph) [][]string {
 		if len(scc) > 1 {
 			cycles = append(cycles, scc)
 		} else if len(scc) == 1 {
-			// Check for self-loop
-			module := g.Modules[scc[0]]
-			for _, dep := range module.Dependencies {
+			// Check for self-loop (dangling links have no module)
+			module, ok := g.Modules[scc[0]]
+			if !ok {
+				continue
+			}
+			for _, edge := range module.DependencyEdges() {
+				dep := edge.Target
 				if dep == scc[0] {
 					cycles = append(cycles, scc)
 					break
@@ -367,7 +385,8 @@ func DependencyDepth(g *graph.Graph, modulePath string) int {
 		}
 
 		maxDepth := 0
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
 			depth := calculateDepth(dep)
 			if depth+1 > maxDepth {
 				maxDepth = depth + 1
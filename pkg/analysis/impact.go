@@ -57,6 +57,7 @@ type ImpactResult struct {
 	// Direct impact
 	DirectDependents   []string // Modules that directly depend on target
 	DirectDependencies []string // Modules that target directly depends on
+	DirectCallSites    int      // Weight of direct dependents' edges (call sites of target's exports)
 
 	// Transitive impact
 	TransitiveDependents map[string]int // All modules affected (with depth)
@@ -110,6 +111,7 @@ func (ia *ImpactAnalysis) AnalyzeImpact(modulePath string) (*ImpactResult, error
 	// Get direct dependents and dependencies
 	result.DirectDependents = ia.getDirectDependents(modulePath)
 	result.DirectDependencies = module.Dependencies
+	result.DirectCallSites = ia.callSites(result.DirectDependents, modulePath)
 
 	// Get transitive dependents (modules impacted by changes)
 	result.TransitiveDependents = TransitiveDependents(ia.graph, modulePath)
@@ -171,12 +173,15 @@ func (ia *ImpactAnalysis) AnalyzeMultipleModules(modulePaths []string) (*ImpactR
 		module := ia.graph.Modules[modulePath]
 
 		// Collect direct dependents
-		for _, dep := range ia.getDirectDependents(modulePath) {
+		dependents := ia.getDirectDependents(modulePath)
+		for _, dep := range dependents {
 			directDependentsSet[dep] = true
 		}
+		result.DirectCallSites += ia.callSites(dependents, modulePath)
 
 		// Collect direct dependencies
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
 			directDependenciesSet[dep] = true
 		}
 
@@ -223,7 +228,8 @@ func (ia *ImpactAnalysis) getDirectDependents(modulePath string) []string {
 	dependents := make([]string, 0)
 
 	for path, module := range ia.graph.Modules {
-		for _, dep := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
 			if dep == modulePath {
 				dependents = append(dependents, path)
 				break
@@ -235,6 +241,17 @@ func (ia *ImpactAnalysis) getDirectDependents(modulePath string) []string {
 	return dependents
 }
 
+// callSites sums the weights of the dependents' edges to the target
+func (ia *ImpactAnalysis) callSites(dependents []string, modulePath string) int {
+	sites := 0
+	for _, dependent := range dependents {
+		if module := ia.graph.Modules[dependent]; module != nil {
+			sites += module.EdgeTo(modulePath).Weight
+		}
+	}
+	return sites
+}
+
 // calculateImpactByLayer calculates the number of impacted modules per layer
 func (ia *ImpactAnalysis) calculateImpactByLayer(result *ImpactResult) {
 	for modulePath := range result.TransitiveDependents {
@@ -335,6 +352,15 @@ func (ia *ImpactAnalysis) assessRisk(result *ImpactResult) {
 		result.RiskFactors = append(result.RiskFactors, fmt.Sprintf("Deep dependency chain (depth %d)", result.MaxImpactDepth))
 	}
 
+	// Factor 6: Call sites in direct dependents
+	if result.DirectCallSites > 50 {
+		riskScore += 2
+		result.RiskFactors = append(result.RiskFactors, fmt.Sprintf("Heavily used by direct dependents (%d call sites)", result.DirectCallSites))
+	} else if result.DirectCallSites > 20 {
+		riskScore += 1
+		result.RiskFactors = append(result.RiskFactors, fmt.Sprintf("Widely used by direct dependents (%d call sites)", result.DirectCallSites))
+	}
+
 	// Determine risk level
 	if riskScore >= 8 {
 		result.RiskLevel = RiskLevelCritical
@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/justin4957/graphfs/pkg/graph"
@@ -151,6 +152,33 @@ func TestAnalyzeImpact_LeafModule(t *testing.T) {
 	}
 }
 
+func TestAnalyzeImpact_CallSites(t *testing.T) {
+	g := createTestGraphForImpact()
+	ia := NewImpactAnalysis(g)
+
+	baseline, err := ia.AnalyzeImpact("core/core.go")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if baseline.DirectCallSites != 2 {
+		t.Errorf("Expected 2 call sites for unweighted edges, got %d", baseline.DirectCallSites)
+	}
+
+	g.Modules["utils/utilsA.go"].AddEdge(graph.Edge{Target: "core/core.go", Weight: 40})
+	g.Modules["utils/utilsB.go"].AddEdge(graph.Edge{Target: "core/core.go", Weight: 20})
+
+	result, err := ia.AnalyzeImpact("core/core.go")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.DirectCallSites != 60 {
+		t.Errorf("Expected 60 call sites, got %d", result.DirectCallSites)
+	}
+	if !strings.Contains(strings.Join(result.RiskFactors, "\n"), "60 call sites") {
+		t.Errorf("Expected a call-site risk factor, got %v", result.RiskFactors)
+	}
+}
+
 func TestAnalyzeImpact_IsolatedModule(t *testing.T) {
 	g := createTestGraphForImpact()
 	ia := NewImpactAnalysis(g)
@@ -438,3 +466,66 @@ func TestBreakingChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeReverseImpact(t *testing.T) {
+	g := createTestGraphForImpact()
+	ia := NewImpactAnalysis(g)
+
+	result, err := ia.AnalyzeReverseImpact("handlers/api.go")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.TotalDependencies != 5 {
+		t.Errorf("Expected 5 transitive dependencies, got %d", result.TotalDependencies)
+	}
+	if result.MaxDepth != 3 {
+		t.Errorf("Expected max depth 3, got %d", result.MaxDepth)
+	}
+
+	reasons := make(map[string]DependencyReason)
+	for _, dep := range result.Dependencies {
+		reasons[dep.Module] = dep
+	}
+
+	utilsA := reasons["utils/utilsA.go"]
+	if utilsA.Via != "services/serviceA.go" || len(utilsA.ViaAll) != 1 {
+		t.Errorf("Expected utilsA via serviceA only, got %+v", utilsA)
+	}
+
+	core := reasons["core/core.go"]
+	if core.Depth != 3 || len(core.ViaAll) != 2 {
+		t.Errorf("Expected core at depth 3 via both services, got %+v", core)
+	}
+	if len(core.Path) != 4 || core.Path[0] != "handlers/api.go" || core.Path[3] != "core/core.go" {
+		t.Errorf("Unexpected path to core: %v", core.Path)
+	}
+
+	for _, direct := range result.DirectDependencies {
+		if direct.Dependency != "services/serviceA.go" {
+			continue
+		}
+		if len(direct.PullsIn) != 2 {
+			t.Errorf("Expected serviceA to pull in 2 modules, got %v", direct.PullsIn)
+		}
+		if len(direct.Exclusive) != 1 || direct.Exclusive[0] != "utils/utilsA.go" {
+			t.Errorf("Expected utilsA exclusive to serviceA, got %v", direct.Exclusive)
+		}
+	}
+}
+
+func TestAnalyzeReverseImpact_LeafAndMissing(t *testing.T) {
+	ia := NewImpactAnalysis(createTestGraphForImpact())
+
+	result, err := ia.AnalyzeReverseImpact("core/core.go")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TotalDependencies != 0 || len(result.DirectDependencies) != 0 {
+		t.Errorf("Expected no dependencies for core, got %+v", result)
+	}
+
+	if _, err := ia.AnalyzeReverseImpact("missing.go"); err == nil {
+		t.Error("Expected error for non-existent module")
+	}
+}
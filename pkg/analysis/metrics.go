@@ -0,0 +1,279 @@
+/*
+# Module: pkg/analysis/metrics.go
+Structural module metrics.
+
+Computes per-module coupling metrics: fan-in (afferent coupling), fan-out
+(efferent coupling), instability Ce/(Ca+Ce), abstractness and distance from
+the main sequence for Go modules, depth from the nearest entry point and
+betweenness centrality. Thresholds turn the metrics into CI checks.
+
+## Linked Modules
+- [deadcode](./deadcode.go) - Entry point detection
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, metrics, coupling, architecture
+
+## Exports
+StructuralMetrics, MetricsReport, ComputeMetrics, MetricThresholds, MetricViolation
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#metrics.go> a code:Module ;
+    code:name "pkg/analysis/metrics.go" ;
+    code:description "Structural module metrics" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <./deadcode.go>, <../graph/graph.go> ;
+    code:exports <#StructuralMetrics>, <#MetricsReport>, <#ComputeMetrics>, <#MetricThresholds>, <#MetricViolation> ;
+    code:tags "analysis", "metrics", "coupling", "architecture" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// StructuralMetrics holds the structural metrics of one module
+type StructuralMetrics struct {
+	Path        string  `json:"path"`
+	Layer       string  `json:"layer,omitempty"`
+	FanIn       int     `json:"fan_in"`      // Modules depending on this one (Ca)
+	FanOut      int     `json:"fan_out"`     // Modules this one depends on (Ce)
+	Instability float64 `json:"instability"` // Ce/(Ca+Ce), 0 when isolated
+	Depth       int     `json:"depth"`       // Dependency hops from the nearest entry point, -1 if unreachable
+	Betweenness float64 `json:"betweenness"` // Share of shortest dependency paths through the module (0-1)
+
+	// Go modules only: share of declared types that are interfaces, and
+	// |A+I-1|, the distance from the main sequence
+	Abstractness *float64 `json:"abstractness,omitempty"`
+	Distance     *float64 `json:"distance,omitempty"`
+}
+
+// MetricsReport holds the metrics of every module, sorted by path
+type MetricsReport struct {
+	Modules []*StructuralMetrics `json:"modules"`
+}
+
+// ComputeMetrics computes the metrics of every module in the graph.
+// Dependencies on paths that are not modules are ignored.
+func ComputeMetrics(g *graph.Graph) *MetricsReport {
+	paths := make([]string, 0, len(g.Modules))
+	for path := range g.Modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// Deduplicated dependencies between modules
+	adjacency := make(map[string][]string, len(paths))
+	fanIn := make(map[string]int, len(paths))
+	for _, path := range paths {
+		seen := make(map[string]bool)
+		for _, edge := range g.Modules[path].DependencyEdges() {
+			dep := edge.Target
+			if _, ok := g.Modules[dep]; !ok || seen[dep] || dep == path {
+				continue
+			}
+			seen[dep] = true
+			adjacency[path] = append(adjacency[path], dep)
+			fanIn[dep]++
+		}
+		sort.Strings(adjacency[path])
+	}
+
+	depths := entryPointDepths(g, paths, adjacency)
+	betweenness := betweennessCentrality(paths, adjacency)
+
+	report := &MetricsReport{Modules: make([]*StructuralMetrics, 0, len(paths))}
+	for _, path := range paths {
+		module := g.Modules[path]
+		m := &StructuralMetrics{
+			Path:        path,
+			Layer:       module.Layer,
+			FanIn:       fanIn[path],
+			FanOut:      len(adjacency[path]),
+			Depth:       -1,
+			Betweenness: math.Round(betweenness[path]*10000) / 10000,
+		}
+		if total := m.FanIn + m.FanOut; total > 0 {
+			m.Instability = Round(float64(m.FanOut) / float64(total))
+		}
+		if depth, ok := depths[path]; ok {
+			m.Depth = depth
+		}
+		if a, ok := goAbstractness(g.Root, module); ok {
+			distance := Round(math.Abs(a + m.Instability - 1))
+			m.Abstractness, m.Distance = &a, &distance
+		}
+		report.Modules = append(report.Modules, m)
+	}
+
+	return report
+}
+
+// entryPointDepths returns the shortest distance from any entry point to
+// each reachable module
+func entryPointDepths(g *graph.Graph, paths []string, adjacency map[string][]string) map[string]int {
+	detector := NewDetector(g, DeadCodeOptions{})
+	depths := make(map[string]int)
+	var queue []string
+	for _, path := range paths {
+		if detector.isEntryPoint(g.Modules[path]) {
+			depths[path] = 0
+			queue = append(queue, path)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range adjacency[current] {
+			if _, seen := depths[dep]; !seen {
+				depths[dep] = depths[current] + 1
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return depths
+}
+
+// betweennessCentrality computes Brandes' betweenness on the directed
+// dependency graph, normalized by the (n-1)(n-2) ordered pairs of other
+// modules
+func betweennessCentrality(paths []string, adjacency map[string][]string) map[string]float64 {
+	centrality := make(map[string]float64, len(paths))
+	n := len(paths)
+	if n < 3 {
+		return centrality
+	}
+
+	for _, source := range paths {
+		var order []string
+		predecessors := make(map[string][]string)
+		sigma := map[string]float64{source: 1}
+		distance := map[string]int{source: 0}
+
+		queue := []string{source}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			order = append(order, v)
+			for _, w := range adjacency[v] {
+				if _, seen := distance[w]; !seen {
+					distance[w] = distance[v] + 1
+					queue = append(queue, w)
+				}
+				if distance[w] == distance[v]+1 {
+					sigma[w] += sigma[v]
+					predecessors[w] = append(predecessors[w], v)
+				}
+			}
+		}
+
+		delta := make(map[string]float64)
+		for i := len(order) - 1; i >= 0; i-- {
+			w := order[i]
+			for _, v := range predecessors[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != source {
+				centrality[w] += delta[w]
+			}
+		}
+	}
+
+	scale := 1 / float64((n-1)*(n-2))
+	for path := range centrality {
+		centrality[path] *= scale
+	}
+	return centrality
+}
+
+// goAbstractness returns the share of a Go module's declared types that are
+// interfaces. Modules that are not Go, cannot be parsed or declare no types
+// have no abstractness.
+func goAbstractness(root string, module *graph.Module) (float64, bool) {
+	if !strings.HasSuffix(module.Path, ".go") {
+		return 0, false
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(root, module.Path), nil, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, false
+	}
+
+	types, interfaces := 0, 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			types++
+			if _, ok := spec.(*ast.TypeSpec).Type.(*ast.InterfaceType); ok {
+				interfaces++
+			}
+		}
+	}
+	if types == 0 {
+		return 0, false
+	}
+	return Round(float64(interfaces) / float64(types)), true
+}
+
+// MetricThresholds are upper bounds on module metrics; zero disables a bound
+type MetricThresholds struct {
+	MaxFanIn       int
+	MaxFanOut      int
+	MaxInstability float64
+	MaxDepth       int
+	MaxBetweenness float64
+	MaxDistance    float64
+}
+
+// MetricViolation is a module metric above its threshold
+type MetricViolation struct {
+	Path   string  `json:"path"`
+	Metric string  `json:"metric"`
+	Value  float64 `json:"value"`
+	Limit  float64 `json:"limit"`
+}
+
+// String describes the violation
+func (v MetricViolation) String() string {
+	return fmt.Sprintf("%s: %s %g exceeds %g", v.Path, v.Metric, v.Value, v.Limit)
+}
+
+// Check returns every metric above its threshold, by module path
+func (r *MetricsReport) Check(t MetricThresholds) []MetricViolation {
+	var violations []MetricViolation
+	check := func(m *StructuralMetrics, metric string, value, limit float64) {
+		if limit > 0 && value > limit {
+			violations = append(violations, MetricViolation{Path: m.Path, Metric: metric, Value: value, Limit: limit})
+		}
+	}
+
+	for _, m := range r.Modules {
+		check(m, "fan_in", float64(m.FanIn), float64(t.MaxFanIn))
+		check(m, "fan_out", float64(m.FanOut), float64(t.MaxFanOut))
+		check(m, "instability", m.Instability, t.MaxInstability)
+		check(m, "depth", float64(m.Depth), float64(t.MaxDepth))
+		check(m, "betweenness", m.Betweenness, t.MaxBetweenness)
+		if m.Distance != nil {
+			check(m, "distance", *m.Distance, t.MaxDistance)
+		}
+	}
+	return violations
+}
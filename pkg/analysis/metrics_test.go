@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	root := t.TempDir()
+	source := "package a\n\ntype Store interface{ Get() }\n\ntype memory struct{}\n"
+	if err := os.WriteFile(filepath.Join(root, "a.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := createCycleGraph(map[string][]string{
+		"main.go": {"a.go", "c.go"},
+		"a.go":    {"b.go", "b.go"},
+		"c.go":    {"b.go", "missing.go"},
+		"b.go":    {},
+		"d.go":    {},
+	})
+	g.Root = root
+
+	report := ComputeMetrics(g)
+	byPath := make(map[string]*StructuralMetrics)
+	for _, m := range report.Modules {
+		byPath[m.Path] = m
+	}
+
+	tests := []struct {
+		path        string
+		fanIn       int
+		fanOut      int
+		instability float64
+		depth       int
+		betweenness float64
+	}{
+		{"main.go", 0, 2, 1, 0, 0},
+		{"a.go", 1, 1, 0.5, 1, 0.0417},
+		{"c.go", 1, 1, 0.5, 1, 0.0417},
+		{"b.go", 2, 0, 0, 2, 0},
+		{"d.go", 0, 0, 0, -1, 0},
+	}
+	for _, tt := range tests {
+		m := byPath[tt.path]
+		if m.FanIn != tt.fanIn || m.FanOut != tt.fanOut || m.Instability != tt.instability || m.Depth != tt.depth || m.Betweenness != tt.betweenness {
+			t.Errorf("%s = %+v, want fan-in %d, fan-out %d, instability %v, depth %d, betweenness %v",
+				tt.path, m, tt.fanIn, tt.fanOut, tt.instability, tt.depth, tt.betweenness)
+		}
+	}
+
+	a := byPath["a.go"]
+	if a.Abstractness == nil || *a.Abstractness != 0.5 || *a.Distance != 0 {
+		t.Errorf("a.go abstractness = %v, distance = %v, want 0.5 and 0", a.Abstractness, a.Distance)
+	}
+	if byPath["b.go"].Abstractness != nil {
+		t.Errorf("b.go has no source and should have no abstractness")
+	}
+
+	violations := report.Check(MetricThresholds{MaxFanIn: 1, MaxInstability: 0.9})
+	if len(violations) != 2 || violations[0].String() != "b.go: fan_in 2 exceeds 1" || violations[1].String() != "main.go: instability 1 exceeds 0.9" {
+		t.Errorf("Check() = %v", violations)
+	}
+}
@@ -0,0 +1,467 @@
+/*
+# Module: pkg/analysis/onboarding.go
+Guided onboarding tours of an area of the codebase.
+
+Builds a time-boxed tour of the modules under a directory: entry points into
+the area, key modules by degree centrality within the area, owners, recent
+churn, concepts, and a reading order that visits dependencies before the
+modules that use them. Reading time is estimated from file length, and
+modules that do not fit the time box are listed for later.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [deadcode](./deadcode.go) - Entry point detection
+- [criticality](./criticality.go) - Owner annotations
+
+## Tags
+analysis, onboarding, documentation
+
+## Exports
+OnboardingOptions, OnboardingTour, TourModule, OwnerSummary, BuildOnboardingTour
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#onboarding.go> a code:Module ;
+    code:name "pkg/analysis/onboarding.go" ;
+    code:description "Guided onboarding tours of an area of the codebase" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <../graph/graph.go>, <./deadcode.go>, <./criticality.go> ;
+    code:exports <#OnboardingOptions>, <#OnboardingTour>, <#TourModule>, <#OwnerSummary>, <#BuildOnboardingTour> ;
+    code:tags "analysis", "onboarding", "documentation" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// linesPerMinute is the assumed reading speed for estimating reading time
+const linesPerMinute = 30
+
+// OnboardingOptions configures an onboarding tour
+type OnboardingOptions struct {
+	// Area is a directory relative to the graph root ("" or "." for all modules)
+	Area string
+
+	// TimeBox limits the reading order to modules that fit in the given
+	// reading time (0 for no limit)
+	TimeBox time.Duration
+
+	// Churn maps module paths to recent commit counts (optional)
+	Churn map[string]int
+
+	// Top limits the entry point, key module and churn sections (default 5)
+	Top int
+}
+
+// TourModule is a module in an onboarding tour
+type TourModule struct {
+	Path        string   `json:"path"`
+	Layer       string   `json:"layer,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Reason      string   `json:"reason,omitempty"`    // Why the module is an entry point
+	Centrality  float64  `json:"centrality"`          // Degree centrality within the area (0-1)
+	Commits     int      `json:"commits,omitempty"`   // Recent commits touching the module
+	Owners      []string `json:"owners,omitempty"`    // From owner/owners annotations
+	Minutes     int      `json:"minutes"`             // Estimated reading time
+	DependsOn   []string `json:"dependsOn,omitempty"` // Dependencies within the area
+}
+
+// OwnerSummary counts the modules of an area an owner is responsible for
+type OwnerSummary struct {
+	Owner   string `json:"owner"`
+	Modules int    `json:"modules"`
+}
+
+// OnboardingTour is a guided tour of an area
+type OnboardingTour struct {
+	Area           string         `json:"area"`
+	Modules        int            `json:"modules"`
+	Minutes        int            `json:"minutes"`                  // Reading time of the whole area
+	TimeBoxMinutes int            `json:"timeBoxMinutes,omitempty"` // 0 when unlimited
+	Concepts       []string       `json:"concepts,omitempty"`
+	EntryPoints    []TourModule   `json:"entryPoints"`
+	KeyModules     []TourModule   `json:"keyModules"`
+	Owners         []OwnerSummary `json:"owners,omitempty"`
+	Unowned        int            `json:"unowned"`
+	Churn          []TourModule   `json:"churn,omitempty"`
+	ReadingOrder   []TourModule   `json:"readingOrder"`
+	Later          []TourModule   `json:"later,omitempty"`    // Modules beyond the time box
+	External       []string       `json:"external,omitempty"` // Modules outside the area it depends on
+}
+
+// BuildOnboardingTour builds a tour of the modules under opts.Area
+func BuildOnboardingTour(g *graph.Graph, opts OnboardingOptions) (*OnboardingTour, error) {
+	if opts.Top <= 0 {
+		opts.Top = 5
+	}
+	area := normalizeArea(opts.Area)
+
+	members := make(map[string]bool)
+	for p := range g.Modules {
+		if inArea(p, area) {
+			members[p] = true
+		}
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no modules found under %s", orRoot(area))
+	}
+
+	tour := &OnboardingTour{
+		Area:           orRoot(area),
+		Modules:        len(members),
+		TimeBoxMinutes: int(opts.TimeBox.Minutes()),
+	}
+
+	reverseDeps := make(map[string][]string)
+	for p, module := range g.Modules {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
+			reverseDeps[dep] = append(reverseDeps[dep], p)
+		}
+	}
+
+	detector := NewDetector(g, DeadCodeOptions{})
+	modules := make(map[string]*TourModule, len(members))
+	outsideDependents := make(map[string]int)
+	external := make(map[string]bool)
+	owners := make(map[string]int)
+	for p := range members {
+		module := g.Modules[p]
+		tm := &TourModule{
+			Path:        p,
+			Layer:       module.Layer,
+			Description: module.Description,
+			Commits:     opts.Churn[p],
+			Owners:      moduleOwners(module),
+			Minutes:     readingMinutes(g.Root, p),
+		}
+
+		degree := 0
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
+			if dep == p {
+				continue
+			}
+			if !members[dep] {
+				external[dep] = true
+				continue
+			}
+			if !slices.Contains(tm.DependsOn, dep) {
+				tm.DependsOn = append(tm.DependsOn, dep)
+				degree++
+			}
+		}
+		sort.Strings(tm.DependsOn)
+		for _, dependent := range uniqueStrings(reverseDeps[p]) {
+			if dependent == p {
+				continue
+			}
+			if members[dependent] {
+				degree++
+			} else {
+				outsideDependents[p]++
+			}
+		}
+		if len(members) > 1 {
+			tm.Centrality = Round(float64(degree) / float64(2*(len(members)-1)))
+		}
+
+		if detector.isEntryPoint(module) {
+			tm.Reason = "program entry point"
+		} else if n := outsideDependents[p]; n > 0 {
+			tm.Reason = fmt.Sprintf("used by %d module(s) outside the area", n)
+		}
+
+		if len(tm.Owners) == 0 {
+			tour.Unowned++
+		}
+		for _, owner := range tm.Owners {
+			owners[owner]++
+		}
+
+		tour.AddConcepts(module.Tags...)
+		tour.Minutes += tm.Minutes
+		modules[p] = tm
+	}
+
+	byCentrality := make([]*TourModule, 0, len(modules))
+	for _, tm := range modules {
+		byCentrality = append(byCentrality, tm)
+	}
+	sort.Slice(byCentrality, func(i, j int) bool {
+		return moreCentral(byCentrality[i], byCentrality[j])
+	})
+
+	// Entry points: program entry points, then modules used from outside the
+	// area. An area nothing uses is entered from the top of its own graph.
+	var entries []*TourModule
+	for _, tm := range byCentrality {
+		if tm.Reason != "" {
+			entries = append(entries, tm)
+		}
+	}
+	if len(entries) == 0 {
+		for _, tm := range byCentrality {
+			if !hasAreaDependents(tm.Path, members, reverseDeps) {
+				tm.Reason = "not used by other modules in the area"
+				entries = append(entries, tm)
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.Reason == "program entry point") != (b.Reason == "program entry point") {
+			return a.Reason == "program entry point"
+		}
+		return outsideDependents[a.Path] > outsideDependents[b.Path]
+	})
+	for _, tm := range limitTour(entries, opts.Top) {
+		tour.EntryPoints = append(tour.EntryPoints, *tm)
+	}
+
+	for _, tm := range limitTour(byCentrality, opts.Top) {
+		tour.KeyModules = append(tour.KeyModules, *tm)
+	}
+
+	for owner, count := range owners {
+		tour.Owners = append(tour.Owners, OwnerSummary{Owner: owner, Modules: count})
+	}
+	sort.Slice(tour.Owners, func(i, j int) bool {
+		a, b := tour.Owners[i], tour.Owners[j]
+		if a.Modules != b.Modules {
+			return a.Modules > b.Modules
+		}
+		return a.Owner < b.Owner
+	})
+
+	var churned []*TourModule
+	for _, tm := range byCentrality {
+		if tm.Commits > 0 {
+			churned = append(churned, tm)
+		}
+	}
+	sort.SliceStable(churned, func(i, j int) bool {
+		return churned[i].Commits > churned[j].Commits
+	})
+	for _, tm := range limitTour(churned, opts.Top) {
+		tour.Churn = append(tour.Churn, *tm)
+	}
+
+	elapsed := 0
+	for _, tm := range readingOrder(modules) {
+		if tour.TimeBoxMinutes > 0 && (len(tour.Later) > 0 || elapsed+tm.Minutes > tour.TimeBoxMinutes) && len(tour.ReadingOrder) > 0 {
+			tour.Later = append(tour.Later, *tm)
+			continue
+		}
+		elapsed += tm.Minutes
+		tour.ReadingOrder = append(tour.ReadingOrder, *tm)
+	}
+
+	tour.External = sortedKeys(external)
+	return tour, nil
+}
+
+// AddConcepts adds concepts to the tour, skipping duplicates
+func (t *OnboardingTour) AddConcepts(concepts ...string) {
+	for _, concept := range concepts {
+		if concept == "" || slices.Contains(t.Concepts, concept) {
+			continue
+		}
+		t.Concepts = append(t.Concepts, concept)
+	}
+	sort.Strings(t.Concepts)
+}
+
+// Markdown renders the tour as a guided Markdown document
+func (t *OnboardingTour) Markdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Onboarding: %s\n\n", t.Area)
+	fmt.Fprintf(&sb, "%d modules, about %s of reading", t.Modules, formatMinutes(t.Minutes))
+	if t.TimeBoxMinutes > 0 {
+		fmt.Fprintf(&sb, " (time box: %s)", formatMinutes(t.TimeBoxMinutes))
+	}
+	sb.WriteString(".\n")
+	if len(t.Concepts) > 0 {
+		fmt.Fprintf(&sb, "\n**Concepts:** %s\n", strings.Join(t.Concepts, ", "))
+	}
+
+	sb.WriteString("\n## Entry points\n\n")
+	for _, tm := range t.EntryPoints {
+		fmt.Fprintf(&sb, "- `%s` - %s", tm.Path, tm.Reason)
+		if tm.Description != "" {
+			fmt.Fprintf(&sb, ". %s", tm.Description)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n## Key modules\n\n")
+	sb.WriteString("| Module | Centrality | Layer | Description |\n")
+	sb.WriteString("|--------|------------|-------|-------------|\n")
+	for _, tm := range t.KeyModules {
+		fmt.Fprintf(&sb, "| `%s` | %.2f | %s | %s |\n", tm.Path, tm.Centrality, tm.Layer, tm.Description)
+	}
+
+	sb.WriteString("\n## Owners\n\n")
+	if len(t.Owners) == 0 {
+		sb.WriteString("No owners are annotated.\n")
+	}
+	for _, owner := range t.Owners {
+		fmt.Fprintf(&sb, "- %s (%d modules)\n", owner.Owner, owner.Modules)
+	}
+	if len(t.Owners) > 0 && t.Unowned > 0 {
+		fmt.Fprintf(&sb, "\n%d module(s) have no owner.\n", t.Unowned)
+	}
+
+	sb.WriteString("\n## Recent churn\n\n")
+	if len(t.Churn) == 0 {
+		sb.WriteString("No recent commits.\n")
+	}
+	for _, tm := range t.Churn {
+		fmt.Fprintf(&sb, "- `%s` - %d commit(s)\n", tm.Path, tm.Commits)
+	}
+
+	sb.WriteString("\n## Suggested reading order\n\n")
+	sb.WriteString("Dependencies come before the modules that use them.\n\n")
+	for i, tm := range t.ReadingOrder {
+		fmt.Fprintf(&sb, "%d. `%s` (~%s)", i+1, tm.Path, formatMinutes(tm.Minutes))
+		if tm.Description != "" {
+			fmt.Fprintf(&sb, " - %s", tm.Description)
+		}
+		sb.WriteString("\n")
+	}
+	if len(t.Later) > 0 {
+		fmt.Fprintf(&sb, "\n### After the time box (%d)\n\n", len(t.Later))
+		for _, tm := range t.Later {
+			fmt.Fprintf(&sb, "- `%s` (~%s)\n", tm.Path, formatMinutes(tm.Minutes))
+		}
+	}
+
+	if len(t.External) > 0 {
+		fmt.Fprintf(&sb, "\n## Dependencies outside the area (%d)\n\n", len(t.External))
+		for _, p := range t.External {
+			fmt.Fprintf(&sb, "- `%s`\n", p)
+		}
+	}
+
+	return sb.String()
+}
+
+// readingOrder orders modules so that dependencies within the area come
+// first, preferring central modules among those ready to read. Cycles are
+// broken at the module with the fewest unread dependencies.
+func readingOrder(modules map[string]*TourModule) []*TourModule {
+	remaining := make(map[string]int, len(modules))
+	dependents := make(map[string][]string)
+	for p, tm := range modules {
+		remaining[p] = len(tm.DependsOn)
+		for _, dep := range tm.DependsOn {
+			dependents[dep] = append(dependents[dep], p)
+		}
+	}
+
+	order := make([]*TourModule, 0, len(modules))
+	for len(remaining) > 0 {
+		var next *TourModule
+		for p, count := range remaining {
+			tm := modules[p]
+			if next == nil || count < remaining[next.Path] ||
+				(count == remaining[next.Path] && moreCentral(tm, next)) {
+				next = tm
+			}
+		}
+
+		order = append(order, next)
+		delete(remaining, next.Path)
+		for _, dependent := range dependents[next.Path] {
+			if _, ok := remaining[dependent]; ok {
+				remaining[dependent]--
+			}
+		}
+	}
+	return order
+}
+
+// moreCentral orders tour modules by centrality, then path
+func moreCentral(a, b *TourModule) bool {
+	if a.Centrality != b.Centrality {
+		return a.Centrality > b.Centrality
+	}
+	return a.Path < b.Path
+}
+
+// hasAreaDependents reports whether another module in the area uses p
+func hasAreaDependents(p string, members map[string]bool, reverseDeps map[string][]string) bool {
+	for _, dependent := range reverseDeps[p] {
+		if dependent != p && members[dependent] {
+			return true
+		}
+	}
+	return false
+}
+
+// readingMinutes estimates the time to read a module from its line count
+func readingMinutes(root, modulePath string) int {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(modulePath)))
+	if err != nil {
+		return 1
+	}
+	lines := bytes.Count(data, []byte("\n")) + 1
+	return max(1, (lines+linesPerMinute-1)/linesPerMinute)
+}
+
+// normalizeArea cleans an area directory; the root area is ""
+func normalizeArea(area string) string {
+	area = path.Clean(filepath.ToSlash(area))
+	area = strings.TrimSuffix(strings.TrimPrefix(area, "./"), "/")
+	if area == "." || area == "/" {
+		return ""
+	}
+	return area
+}
+
+// inArea reports whether a module path lies under an area
+func inArea(modulePath, area string) bool {
+	return area == "" || modulePath == area || strings.HasPrefix(modulePath, area+"/")
+}
+
+func orRoot(area string) string {
+	if area == "" {
+		return "."
+	}
+	return area
+}
+
+func limitTour(modules []*TourModule, max int) []*TourModule {
+	if len(modules) <= max {
+		return modules
+	}
+	return modules[:max]
+}
+
+// formatMinutes formats a reading time such as "45 min" or "1h 30m"
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
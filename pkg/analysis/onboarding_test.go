@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// createOnboardingGraph builds an area pkg/payments used by api/handler.go:
+// api/handler.go -> pkg/payments/service.go -> pkg/payments/ledger.go -> pkg/db/db.go
+// pkg/payments/refunds.go -> pkg/payments/ledger.go
+func createOnboardingGraph(t *testing.T) *graph.Graph {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "pkg", "payments"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 90 lines: 3 minutes of reading
+	if err := os.WriteFile(filepath.Join(root, "pkg", "payments", "ledger.go"), []byte(strings.Repeat("x\n", 89)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	owner := map[string][]string{"https://schema.codedoc.org/owner": {"payments-team"}}
+	g := graph.NewGraph(root, store.NewTripleStore())
+	modules := []*graph.Module{
+		{Path: "api/handler.go", URI: "<#handler.go>", Dependencies: []string{"pkg/payments/service.go"}},
+		{Path: "pkg/payments/service.go", URI: "<#service.go>", Description: "Payment service",
+			Dependencies: []string{"pkg/payments/ledger.go"}, Tags: []string{"payments"}, Properties: owner},
+		{Path: "pkg/payments/ledger.go", URI: "<#ledger.go>", Dependencies: []string{"pkg/db/db.go"},
+			Tags: []string{"accounting"}, Properties: owner},
+		{Path: "pkg/payments/refunds.go", URI: "<#refunds.go>", Dependencies: []string{"pkg/payments/ledger.go"}},
+		{Path: "pkg/db/db.go", URI: "<#db.go>"},
+	}
+	for _, m := range modules {
+		g.AddModule(m)
+	}
+	return g
+}
+
+func TestBuildOnboardingTour(t *testing.T) {
+	g := createOnboardingGraph(t)
+
+	tour, err := BuildOnboardingTour(g, OnboardingOptions{
+		Area:  "./pkg/payments/",
+		Churn: map[string]int{"pkg/payments/refunds.go": 4, "pkg/payments/service.go": 2},
+	})
+	if err != nil {
+		t.Fatalf("BuildOnboardingTour failed: %v", err)
+	}
+
+	if tour.Area != "pkg/payments" || tour.Modules != 3 || tour.Minutes != 5 {
+		t.Errorf("Tour = %s with %d modules and %d minutes", tour.Area, tour.Modules, tour.Minutes)
+	}
+	if len(tour.EntryPoints) != 1 || tour.EntryPoints[0].Path != "pkg/payments/service.go" {
+		t.Errorf("Entry points = %+v, want service.go", tour.EntryPoints)
+	}
+	if tour.KeyModules[0].Path != "pkg/payments/ledger.go" || tour.KeyModules[0].Centrality != 0.5 {
+		t.Errorf("Most central module = %+v, want ledger.go at 0.5", tour.KeyModules[0])
+	}
+	if len(tour.Owners) != 1 || tour.Owners[0].Modules != 2 || tour.Unowned != 1 {
+		t.Errorf("Owners = %+v with %d unowned", tour.Owners, tour.Unowned)
+	}
+	if len(tour.Churn) != 2 || tour.Churn[0].Path != "pkg/payments/refunds.go" {
+		t.Errorf("Churn = %+v", tour.Churn)
+	}
+	if strings.Join(tour.Concepts, ",") != "accounting,payments" {
+		t.Errorf("Concepts = %v", tour.Concepts)
+	}
+	if len(tour.External) != 1 || tour.External[0] != "pkg/db/db.go" {
+		t.Errorf("External = %v", tour.External)
+	}
+
+	// Dependencies come first
+	var order []string
+	for _, tm := range tour.ReadingOrder {
+		order = append(order, tm.Path)
+	}
+	want := "pkg/payments/ledger.go,pkg/payments/refunds.go,pkg/payments/service.go"
+	if strings.Join(order, ",") != want {
+		t.Errorf("Reading order = %v, want %s", order, want)
+	}
+
+	markdown := tour.Markdown()
+	for _, section := range []string{"# Onboarding: pkg/payments", "## Entry points", "## Key modules", "## Suggested reading order", "1. `pkg/payments/ledger.go` (~3 min)"} {
+		if !strings.Contains(markdown, section) {
+			t.Errorf("Markdown missing %q:\n%s", section, markdown)
+		}
+	}
+}
+
+func TestBuildOnboardingTour_TimeBox(t *testing.T) {
+	g := createOnboardingGraph(t)
+
+	tour, err := BuildOnboardingTour(g, OnboardingOptions{Area: "pkg/payments", TimeBox: 4 * time.Minute})
+	if err != nil {
+		t.Fatalf("BuildOnboardingTour failed: %v", err)
+	}
+	if len(tour.ReadingOrder) != 2 || len(tour.Later) != 1 {
+		t.Errorf("Expected 2 modules in the time box and 1 later, got %d and %d", len(tour.ReadingOrder), len(tour.Later))
+	}
+
+	if _, err := BuildOnboardingTour(g, OnboardingOptions{Area: "pkg/missing"}); err == nil {
+		t.Error("Expected error for an area without modules")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+# Module: pkg/analysis/owner_impact.go
+Change impact grouped by owner.
+
+Groups the changed and transitively impacted modules of an impact analysis
+by their owners (code:owner, code:owners and CODEOWNERS via code:ownedBy),
+answering "which teams does this change impact".
+
+## Linked Modules
+- [impact](./impact.go) - Impact analysis
+- [criticality](./criticality.go) - Module owners
+- [../graph](../graph/owners.go) - Module ownership
+
+## Tags
+analysis, impact, ownership
+
+## Exports
+OwnerImpact, ImpactByOwner, OwnerImpactMarkdown
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#owner_impact.go> a code:Module ;
+    code:name "pkg/analysis/owner_impact.go" ;
+    code:description "Change impact grouped by owner" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <./impact.go>, <./criticality.go>, <../graph/owners.go> ;
+    code:exports <#OwnerImpact>, <#ImpactByOwner>, <#OwnerImpactMarkdown> ;
+    code:tags "analysis", "impact", "ownership" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// OwnerImpact lists the modules of one owner touched by a change. Owner is
+// empty for modules nobody owns.
+type OwnerImpact struct {
+	Owner    string   `json:"owner"`
+	Changed  []string `json:"changed"`  // Changed modules the owner owns
+	Impacted []string `json:"impacted"` // Transitive dependents the owner owns
+}
+
+// ImpactByOwner groups changed modules and their transitive dependents by
+// owner. A module owned by several owners is listed under each; changed
+// modules are not repeated as impacted. Owners with changed modules come
+// first, then by impacted modules, then by name; unowned modules come last.
+func ImpactByOwner(g *graph.Graph, changed []string, dependents map[string]int) []OwnerImpact {
+	byOwner := make(map[string]*OwnerImpact)
+	add := func(path string, isChanged bool) {
+		module := g.GetModule(path)
+		if module == nil {
+			return
+		}
+		owners := moduleOwners(module)
+		if len(owners) == 0 {
+			owners = []string{""}
+		}
+		for _, owner := range owners {
+			entry, ok := byOwner[owner]
+			if !ok {
+				entry = &OwnerImpact{Owner: owner, Changed: make([]string, 0), Impacted: make([]string, 0)}
+				byOwner[owner] = entry
+			}
+			if isChanged {
+				entry.Changed = append(entry.Changed, module.Path)
+			} else {
+				entry.Impacted = append(entry.Impacted, module.Path)
+			}
+		}
+	}
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, path := range changed {
+		if module := g.GetModule(path); module != nil && !isChanged[module.Path] {
+			isChanged[module.Path] = true
+			add(path, true)
+		}
+	}
+	for path := range dependents {
+		if module := g.GetModule(path); module != nil && !isChanged[module.Path] {
+			add(path, false)
+		}
+	}
+
+	result := make([]OwnerImpact, 0, len(byOwner))
+	for _, entry := range byOwner {
+		sort.Strings(entry.Changed)
+		sort.Strings(entry.Impacted)
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.Owner == "") != (b.Owner == "") {
+			return b.Owner == ""
+		}
+		if len(a.Changed) != len(b.Changed) {
+			return len(a.Changed) > len(b.Changed)
+		}
+		if len(a.Impacted) != len(b.Impacted) {
+			return len(a.Impacted) > len(b.Impacted)
+		}
+		return a.Owner < b.Owner
+	})
+	return result
+}
+
+// OwnerImpactMarkdown renders owner impact as a Markdown table
+func OwnerImpactMarkdown(owners []OwnerImpact) string {
+	var sb strings.Builder
+	if len(owners) == 0 {
+		sb.WriteString("No modules impacted.\n")
+		return sb.String()
+	}
+	sb.WriteString("| Owner | Changed | Impacted |\n|---|---|---|\n")
+	for _, entry := range owners {
+		owner := entry.Owner
+		if owner == "" {
+			owner = "_unowned_"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s |\n", owner, markdownPaths(entry.Changed), markdownPaths(entry.Impacted))
+	}
+	return sb.String()
+}
+
+// markdownPaths lists paths as inline code, or a dash when there are none
+func markdownPaths(paths []string) string {
+	if len(paths) == 0 {
+		return "-"
+	}
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = "`" + path + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,40 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestImpactByOwner(t *testing.T) {
+	g := createTestGraphForImpact()
+	owner := func(path string, owners ...string) {
+		module := g.Modules[path]
+		module.Properties = map[string][]string{graph.OwnedByPredicate: owners}
+	}
+	owner("utils/utilsA.go", "@acme/platform")
+	owner("services/serviceA.go", "@acme/services")
+	owner("services/serviceB.go", "@acme/services", "@alice")
+
+	result, err := NewImpactAnalysis(g).AnalyzeMultipleModules([]string{"utils/utilsA.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	owners := ImpactByOwner(g, []string{"utils/utilsA.go"}, result.TransitiveDependents)
+
+	want := []OwnerImpact{
+		{Owner: "@acme/platform", Changed: []string{"utils/utilsA.go"}, Impacted: []string{}},
+		{Owner: "@acme/services", Changed: []string{}, Impacted: []string{"services/serviceA.go"}},
+		{Owner: "", Changed: []string{}, Impacted: []string{"handlers/api.go"}},
+	}
+	if !reflect.DeepEqual(owners, want) {
+		t.Errorf("ImpactByOwner() = %+v, want %+v", owners, want)
+	}
+
+	markdown := OwnerImpactMarkdown(owners)
+	if !strings.Contains(markdown, "| @acme/services | - | `services/serviceA.go` |") || !strings.Contains(markdown, "| _unowned_ |") {
+		t.Errorf("OwnerImpactMarkdown() =\n%s", markdown)
+	}
+}
@@ -0,0 +1,206 @@
+/*
+# Module: pkg/analysis/reverse_impact.go
+Reverse impact analysis for GraphFS.
+
+Reports the full transitive dependency closure of a module, annotated with
+the direct dependency edge that pulls in each transitive dependency. Helps
+with dependency pruning by showing which modules would drop out of the
+closure if a direct dependency were removed.
+
+## Linked Modules
+- [impact](./impact.go) - Impact analysis engine
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+analysis, impact-analysis, dependencies, refactoring
+
+## Exports
+ReverseImpactResult, DependencyReason, DirectDependencyImpact, AnalyzeReverseImpact
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#reverse_impact.go> a code:Module ;
+    code:name "pkg/analysis/reverse_impact.go" ;
+    code:description "Reverse impact analysis for GraphFS" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <./impact.go>, <../graph/graph.go> ;
+    code:exports <#ReverseImpactResult>, <#DependencyReason>, <#DirectDependencyImpact>, <#AnalyzeReverseImpact> ;
+    code:tags "analysis", "impact-analysis", "dependencies", "refactoring" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DependencyReason explains why a module is in the transitive dependency closure
+type DependencyReason struct {
+	Module string   `json:"module"`  // Dependency in the closure
+	Depth  int      `json:"depth"`   // Shortest distance from the target
+	Via    string   `json:"via"`     // Direct dependency on the shortest path
+	ViaAll []string `json:"via_all"` // All direct dependencies that pull it in
+	Path   []string `json:"path"`    // Shortest path from the target
+}
+
+// DirectDependencyImpact summarizes what a single direct dependency pulls in
+type DirectDependencyImpact struct {
+	Dependency string   `json:"dependency"` // Direct dependency of the target
+	PullsIn    []string `json:"pulls_in"`   // Transitive dependencies reachable through it
+	Exclusive  []string `json:"exclusive"`  // Dependencies only reachable through it
+}
+
+// ReverseImpactResult contains the transitive dependency closure of a module
+type ReverseImpactResult struct {
+	TargetModule           string                   `json:"target_module"`
+	Dependencies           []DependencyReason       `json:"dependencies"`
+	DirectDependencies     []DirectDependencyImpact `json:"direct_dependencies"`
+	TotalDependencies      int                      `json:"total_dependencies"`
+	MaxDepth               int                      `json:"max_depth"`
+	DependencyPercentage   float64                  `json:"dependency_percentage"`
+	UnresolvedDependencies []string                 `json:"unresolved_dependencies,omitempty"`
+}
+
+// AnalyzeReverseImpact reports everything a module depends on transitively,
+// and which direct edge pulls in each dependency. Removing a direct
+// dependency drops its Exclusive dependencies from the closure.
+func (ia *ImpactAnalysis) AnalyzeReverseImpact(modulePath string) (*ReverseImpactResult, error) {
+	module, exists := ia.graph.Modules[modulePath]
+	if !exists {
+		return nil, fmt.Errorf("module not found: %s", modulePath)
+	}
+
+	result := &ReverseImpactResult{
+		TargetModule:       modulePath,
+		Dependencies:       make([]DependencyReason, 0),
+		DirectDependencies: make([]DirectDependencyImpact, 0),
+	}
+
+	// Shortest paths from the target
+	parents := ia.dependencyParents(modulePath)
+
+	// Which direct dependencies reach each module
+	viaAll := make(map[string][]string)
+	direct := uniqueStrings(module.Dependencies)
+	for _, dep := range direct {
+		impact := DirectDependencyImpact{Dependency: dep, PullsIn: make([]string, 0)}
+		for reached := range ia.dependencyParents(dep) {
+			if reached == modulePath {
+				continue
+			}
+			viaAll[reached] = append(viaAll[reached], dep)
+			if reached != dep {
+				impact.PullsIn = append(impact.PullsIn, reached)
+			}
+		}
+		sort.Strings(impact.PullsIn)
+		result.DirectDependencies = append(result.DirectDependencies, impact)
+	}
+
+	for path := range parents {
+		if path == modulePath {
+			continue
+		}
+
+		reason := DependencyReason{
+			Module: path,
+			Path:   pathFromParents(parents, path),
+			ViaAll: viaAll[path],
+		}
+		reason.Depth = len(reason.Path) - 1
+		reason.Via = reason.Path[1]
+		sort.Strings(reason.ViaAll)
+		result.Dependencies = append(result.Dependencies, reason)
+
+		if reason.Depth > result.MaxDepth {
+			result.MaxDepth = reason.Depth
+		}
+		if _, resolved := ia.graph.Modules[path]; !resolved {
+			result.UnresolvedDependencies = append(result.UnresolvedDependencies, path)
+		}
+	}
+
+	sort.Slice(result.Dependencies, func(i, j int) bool {
+		if result.Dependencies[i].Depth != result.Dependencies[j].Depth {
+			return result.Dependencies[i].Depth < result.Dependencies[j].Depth
+		}
+		return result.Dependencies[i].Module < result.Dependencies[j].Module
+	})
+	sort.Strings(result.UnresolvedDependencies)
+
+	// A dependency reached through a single direct edge is exclusive to it
+	for i := range result.DirectDependencies {
+		impact := &result.DirectDependencies[i]
+		impact.Exclusive = make([]string, 0)
+		for _, path := range impact.PullsIn {
+			if len(viaAll[path]) == 1 {
+				impact.Exclusive = append(impact.Exclusive, path)
+			}
+		}
+	}
+
+	result.TotalDependencies = len(result.Dependencies)
+	if totalModules := len(ia.graph.Modules); totalModules > 0 {
+		result.DependencyPercentage = float64(result.TotalDependencies) / float64(totalModules) * 100
+	}
+
+	return result, nil
+}
+
+// dependencyParents runs a BFS over dependencies and returns the BFS parent
+// of every reachable module (the start module maps to "")
+func (ia *ImpactAnalysis) dependencyParents(start string) map[string]string {
+	parents := map[string]string{start: ""}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		module, exists := ia.graph.Modules[current]
+		if !exists {
+			continue
+		}
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
+			if _, visited := parents[dep]; !visited {
+				parents[dep] = current
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	return parents
+}
+
+// pathFromParents reconstructs the BFS path ending at path
+func pathFromParents(parents map[string]string, path string) []string {
+	var reversed []string
+	for current := path; current != ""; current = parents[current] {
+		reversed = append(reversed, current)
+	}
+
+	result := make([]string, len(reversed))
+	for i, p := range reversed {
+		result[len(reversed)-1-i] = p
+	}
+	return result
+}
+
+// uniqueStrings returns values without duplicates, preserving order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,222 @@
+/*
+# Module: pkg/analysis/reviewers.go
+Reviewer suggestions for a set of changed files.
+
+Combines the owners of changed modules with the owners of high-impact
+modules depending on them, scoring each owner by how closely their modules
+are tied to the change. Owners written as GitHub handles or teams are
+mapped to the reviewers and team_reviewers fields of GitHub's review
+request API so a workflow can assign them directly.
+
+## Linked Modules
+- [change_summary](./change_summary.go) - Changed files and their dependents
+- [criticality](./criticality.go) - Owner annotations and criticality scores
+
+## Tags
+analysis, git, ownership, review
+
+## Exports
+ReviewerSuggestion, ReviewerCandidate, ReviewerReason, ReviewerOptions, SuggestReviewers
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#reviewers.go> a code:Module ;
+    code:name "pkg/analysis/reviewers.go" ;
+    code:description "Reviewer suggestions for a set of changed files" ;
+    code:language "go" ;
+    code:layer "analysis" ;
+    code:linksTo <./change_summary.go>, <./criticality.go> ;
+    code:exports <#ReviewerSuggestion>, <#ReviewerCandidate>, <#ReviewerReason>, <#ReviewerOptions>, <#SuggestReviewers> ;
+    code:tags "analysis", "git", "ownership", "review" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// defaultDependentWeight scores dependents when no criticality is available
+const defaultDependentWeight = 0.5
+
+// ReviewerOptions configures reviewer suggestions
+type ReviewerOptions struct {
+	// MaxDepth is how far downstream of the change dependents are
+	// considered (default 1: direct dependents only)
+	MaxDepth int
+
+	// Criticality scores dependents. Without it every dependent within
+	// MaxDepth counts, with a weight of 0.5.
+	Criticality *CriticalityAnalysis
+
+	// MinCriticality is the score a dependent needs to be high-impact
+	// (default 0.5)
+	MinCriticality float64
+
+	// Exclude lists owners never suggested, such as the change's author.
+	// A leading "@" and case are ignored.
+	Exclude []string
+
+	// MaxReviewers limits the reviewers and teams requested (0 for all)
+	MaxReviewers int
+}
+
+// ReviewerSuggestion is the result of SuggestReviewers. Reviewers and
+// TeamReviewers match the body of GitHub's request-reviewers API.
+type ReviewerSuggestion struct {
+	Reviewers     []string            `json:"reviewers"`      // GitHub user logins
+	TeamReviewers []string            `json:"team_reviewers"` // GitHub team slugs
+	Candidates    []ReviewerCandidate `json:"candidates"`     // Every owner, best first
+	Unowned       []string            `json:"unowned,omitempty"`
+}
+
+// ReviewerCandidate is an owner with a stake in the change
+type ReviewerCandidate struct {
+	Owner   string           `json:"owner"`
+	Score   float64          `json:"score"`
+	Reasons []ReviewerReason `json:"reasons"`
+}
+
+// ReviewerReason is a module that ties an owner to the change
+type ReviewerReason struct {
+	Module      string  `json:"module"`
+	Depth       int     `json:"depth"` // 0 for changed modules
+	Criticality float64 `json:"criticality,omitempty"`
+}
+
+// SuggestReviewers suggests reviewers for changed files (paths relative to
+// the graph root). Each changed module adds 1 to the score of its owners;
+// each high-impact dependent adds its criticality divided by its distance
+// from the change.
+func SuggestReviewers(g *graph.Graph, changed []string, opts ReviewerOptions) *ReviewerSuggestion {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 1
+	}
+	if opts.MinCriticality <= 0 {
+		opts.MinCriticality = 0.5
+	}
+	excluded := make(map[string]bool, len(opts.Exclude))
+	for _, owner := range opts.Exclude {
+		excluded[normalizeOwner(owner)] = true
+	}
+
+	candidates := make(map[string]*ReviewerCandidate)
+	credit := func(module *graph.Module, reason ReviewerReason, weight float64) bool {
+		owners := moduleOwners(module)
+		for _, owner := range owners {
+			if excluded[normalizeOwner(owner)] {
+				continue
+			}
+			candidate, ok := candidates[owner]
+			if !ok {
+				candidate = &ReviewerCandidate{Owner: owner}
+				candidates[owner] = candidate
+			}
+			candidate.Score += weight
+			candidate.Reasons = append(candidate.Reasons, reason)
+		}
+		return len(owners) > 0
+	}
+
+	summary := &ReviewerSuggestion{Reviewers: []string{}, TeamReviewers: []string{}}
+	changedSet := make(map[string]bool)
+	for _, path := range changed {
+		if _, ok := g.Modules[path]; ok {
+			changedSet[path] = true
+		}
+	}
+	for _, path := range sortedKeys(changedSet) {
+		if !credit(g.Modules[path], ReviewerReason{Module: path}, 1) {
+			summary.Unowned = append(summary.Unowned, path)
+		}
+	}
+
+	reverseDeps := make(map[string][]string)
+	for path, module := range g.Modules {
+		for _, edge := range module.DependencyEdges() {
+			dep := edge.Target
+			reverseDeps[dep] = append(reverseDeps[dep], path)
+		}
+	}
+	dependents := changeDependents(reverseDeps, changedSet)
+	paths := make([]string, 0, len(dependents))
+	for path, depth := range dependents {
+		if depth <= opts.MaxDepth {
+			paths = append(paths, path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if dependents[paths[i]] != dependents[paths[j]] {
+			return dependents[paths[i]] < dependents[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	for _, path := range paths {
+		weight := defaultDependentWeight
+		reason := ReviewerReason{Module: path, Depth: dependents[path]}
+		if opts.Criticality != nil {
+			mc, ok := opts.Criticality.Get(path)
+			if !ok || mc.Score < opts.MinCriticality {
+				continue
+			}
+			weight = mc.Score
+			reason.Criticality = mc.Score
+		}
+		credit(g.Modules[path], reason, Round(weight/float64(reason.Depth)))
+	}
+
+	for _, candidate := range candidates {
+		candidate.Score = Round(candidate.Score)
+		summary.Candidates = append(summary.Candidates, *candidate)
+	}
+	sort.Slice(summary.Candidates, func(i, j int) bool {
+		a, b := summary.Candidates[i], summary.Candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Owner < b.Owner
+	})
+
+	for _, candidate := range summary.Candidates {
+		if opts.MaxReviewers > 0 && len(summary.Reviewers)+len(summary.TeamReviewers) >= opts.MaxReviewers {
+			break
+		}
+		login, team := githubReviewer(candidate.Owner)
+		switch {
+		case login != "":
+			summary.Reviewers = append(summary.Reviewers, login)
+		case team != "":
+			summary.TeamReviewers = append(summary.TeamReviewers, team)
+		}
+	}
+
+	return summary
+}
+
+// githubReviewer maps an owner to a GitHub login ("@alice" or "alice") or
+// team slug ("@org/team"). Owners that are neither, such as email
+// addresses, map to nothing.
+func githubReviewer(owner string) (login, team string) {
+	name := strings.TrimPrefix(strings.TrimSpace(owner), "@")
+	if name == "" || strings.ContainsAny(name, "@ ") {
+		return "", ""
+	}
+	if org, slug, ok := strings.Cut(name, "/"); ok {
+		if org == "" || slug == "" || strings.Contains(slug, "/") {
+			return "", ""
+		}
+		return "", slug
+	}
+	return name, ""
+}
+
+// normalizeOwner compares owners regardless of a leading "@" and case
+func normalizeOwner(owner string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(owner), "@"))
+}
@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func createTestGraphForReviewers() *graph.Graph {
+	g := createTestGraphForImpact()
+	g.Modules["utils/utilsA.go"].Properties = map[string][]string{"https://schema.codedoc.org/owner": {"@alice"}}
+	g.Modules["services/serviceA.go"].Properties = map[string][]string{"https://schema.codedoc.org/owners": {"@acme/platform, bob@example.com"}}
+	g.Modules["handlers/api.go"].Properties = map[string][]string{"https://schema.codedoc.org/owner": {"carol"}}
+	return g
+}
+
+func TestSuggestReviewers(t *testing.T) {
+	g := createTestGraphForReviewers()
+
+	s := SuggestReviewers(g, []string{"utils/utilsA.go", "core/core.go", "README.md"}, ReviewerOptions{})
+
+	if len(s.Candidates) != 3 || s.Candidates[0].Owner != "@alice" || s.Candidates[0].Score != 1 {
+		t.Fatalf("Candidates = %+v, want @alice first with score 1", s.Candidates)
+	}
+	// serviceA depends on utilsA directly; api is two steps away
+	for _, c := range s.Candidates[1:] {
+		if c.Score != 0.5 || c.Reasons[0].Module != "services/serviceA.go" || c.Reasons[0].Depth != 1 {
+			t.Errorf("Candidate %+v, want a serviceA owner with score 0.5", c)
+		}
+	}
+	if strings.Join(s.Reviewers, ",") != "alice" {
+		t.Errorf("Reviewers = %v, want alice", s.Reviewers)
+	}
+	if strings.Join(s.TeamReviewers, ",") != "platform" {
+		t.Errorf("TeamReviewers = %v, want platform", s.TeamReviewers)
+	}
+	if strings.Join(s.Unowned, ",") != "core/core.go" {
+		t.Errorf("Unowned = %v, want core/core.go", s.Unowned)
+	}
+}
+
+func TestSuggestReviewers_Options(t *testing.T) {
+	g := createTestGraphForReviewers()
+	criticality := &CriticalityAnalysis{byPath: map[string]*ModuleCriticality{
+		"services/serviceA.go": {Path: "services/serviceA.go", Score: 0.3},
+		"handlers/api.go":      {Path: "handlers/api.go", Score: 0.8},
+	}}
+
+	s := SuggestReviewers(g, []string{"utils/utilsA.go"}, ReviewerOptions{
+		MaxDepth:     2,
+		Criticality:  criticality,
+		Exclude:      []string{"Alice"},
+		MaxReviewers: 1,
+	})
+
+	// serviceA is below the criticality threshold and alice is excluded
+	if len(s.Candidates) != 1 || s.Candidates[0].Owner != "carol" || s.Candidates[0].Score != 0.4 {
+		t.Fatalf("Candidates = %+v, want carol with score 0.4", s.Candidates)
+	}
+	if reason := s.Candidates[0].Reasons[0]; reason.Depth != 2 || reason.Criticality != 0.8 {
+		t.Errorf("Reason = %+v, want api at depth 2 with criticality 0.8", reason)
+	}
+	if strings.Join(s.Reviewers, ",") != "carol" || len(s.TeamReviewers) != 0 {
+		t.Errorf("Reviewers = %v, teams = %v; want carol only", s.Reviewers, s.TeamReviewers)
+	}
+}
+
+func TestGithubReviewer(t *testing.T) {
+	tests := []struct{ owner, login, team string }{
+		{"@alice", "alice", ""},
+		{"bob", "bob", ""},
+		{"@acme/platform", "", "platform"},
+		{"bob@example.com", "", ""},
+		{"Platform Team", "", ""},
+	}
+	for _, tt := range tests {
+		if login, team := githubReviewer(tt.owner); login != tt.login || team != tt.team {
+			t.Errorf("githubReviewer(%q) = %q, %q; want %q, %q", tt.owner, login, team, tt.login, tt.team)
+		}
+	}
+}
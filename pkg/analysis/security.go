@@ -147,7 +147,9 @@ func (sa *SecurityAnalyzer) detectBoundaries(zones map[SecurityZone][]*ModuleZon
 	for _, module := range sa.graph.Modules {
 		sourceZone := moduleZones[module.Path]
 
-		for _, depPath := range module.Dependencies {
+		for _, edge := range module.DependencyEdges() {
+
+			depPath := edge.Target
 			depModule := sa.graph.GetModule(depPath)
 			if depModule == nil {
 				continue
@@ -8,6 +8,7 @@ re-parsing unchanged files on subsequent scans.
 ## Linked Modules
 - [cache](./cache.go) - In-memory caching
 - [graph/module](../graph/module.go) - Module data structure
+- [pathkey](../pathkey/pathkey.go) - Canonical path keys
 
 ## Tags
 cache, persistence, performance
@@ -24,7 +25,7 @@ Manager, NewManager, CacheStats
     code:description "Persistent cache manager for knowledge graph modules" ;
     code:language "go" ;
     code:layer "cache" ;
-    code:linksTo <./cache.go>, <../graph/module.go> ;
+    code:linksTo <./cache.go>, <../graph/module.go>, <../pathkey/pathkey.go> ;
     code:exports <#Manager>, <#NewManager>, <#CacheStats> ;
     code:tags "cache", "persistence", "performance" .
 <!-- End LinkedDoc RDF -->
@@ -40,9 +41,11 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync/atomic"
 	"time"
 
+	"github.com/justin4957/graphfs/pkg/pathkey"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -51,7 +54,11 @@ const (
 	metadataBucket   = "metadata"
 	modulesBucket    = "modules"
 	fileHashesBucket = "file_hashes"
+	predicateBucket  = "predicate_index"
 	defaultCacheDir  = ".graphfs/cache"
+
+	// pathKeysMetadata records how file paths were turned into keys
+	pathKeysMetadata = "path_keys"
 )
 
 // CacheStats represents cache statistics
@@ -85,6 +92,7 @@ type Manager struct {
 	db       *bolt.DB
 	root     string
 	cacheDir string
+	keys     pathkey.Normalizer
 	hits     atomic.Int64 // Thread-safe cache hits counter
 	misses   atomic.Int64 // Thread-safe cache misses counter
 }
@@ -116,6 +124,9 @@ func NewManager(root string) (*Manager, error) {
 		if _, err := tx.CreateBucketIfNotExists([]byte(fileHashesBucket)); err != nil {
 			return err
 		}
+		if _, err := tx.CreateBucketIfNotExists([]byte(predicateBucket)); err != nil {
+			return err
+		}
 		return nil
 	})
 	if err != nil {
@@ -127,6 +138,13 @@ func NewManager(root string) (*Manager, error) {
 		db:       db,
 		root:     root,
 		cacheDir: cacheDir,
+		keys:     pathkey.Default(),
+	}
+
+	// Drop entries keyed under a different path scheme
+	if err := manager.migratePathKeys(); err != nil {
+		db.Close()
+		return nil, err
 	}
 
 	// Store cache version
@@ -161,7 +179,7 @@ func (m *Manager) Get(filePath string) (*CachedData, bool) {
 	var cached CachedModule
 	err = m.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(modulesBucket))
-		data := bucket.Get([]byte(filePath))
+		data := bucket.Get([]byte(m.key(filePath)))
 		if data == nil {
 			return fmt.Errorf("not found")
 		}
@@ -223,31 +241,94 @@ func (m *Manager) Set(filePath string, module interface{}, triples []Triple) err
 	}
 
 	// Store in database
+	key := m.key(filePath)
 	return m.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(modulesBucket))
-		if err := bucket.Put([]byte(filePath), data); err != nil {
+
+		// Replace the predicate index entries of any previous version
+		if err := unindexPredicates(tx, key, bucket.Get([]byte(key))); err != nil {
+			return err
+		}
+		if err := indexPredicates(tx, key, triples); err != nil {
+			return err
+		}
+
+		if err := bucket.Put([]byte(key), data); err != nil {
 			return err
 		}
 
 		// Store file hash separately for quick lookups
 		hashBucket := tx.Bucket([]byte(fileHashesBucket))
-		return hashBucket.Put([]byte(filePath), []byte(fileHash))
+		return hashBucket.Put([]byte(key), []byte(fileHash))
 	})
 }
 
 // Invalidate removes a module from the cache
 func (m *Manager) Invalidate(filePath string) error {
+	key := m.key(filePath)
 	return m.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(modulesBucket))
-		if err := bucket.Delete([]byte(filePath)); err != nil {
+		if err := unindexPredicates(tx, key, bucket.Get([]byte(key))); err != nil {
+			return err
+		}
+		if err := bucket.Delete([]byte(key)); err != nil {
 			return err
 		}
 
 		hashBucket := tx.Bucket([]byte(fileHashesBucket))
-		return hashBucket.Delete([]byte(filePath))
+		return hashBucket.Delete([]byte(key))
 	})
 }
 
+// key returns the database key for a file path
+func (m *Manager) key(filePath string) string {
+	return m.keys.Key(filePath)
+}
+
+// pathKeyScheme names the current key scheme for the metadata bucket
+func (m *Manager) pathKeyScheme() string {
+	if m.keys.FoldCase {
+		return "canonical-folded"
+	}
+	return "canonical"
+}
+
+// migratePathKeys clears cached modules stored under another key scheme.
+// Caches written before path keys used native paths, which match the
+// canonical scheme everywhere except Windows and case-folding platforms.
+func (m *Manager) migratePathKeys() error {
+	scheme := m.pathKeyScheme()
+
+	var recorded string
+	_ = m.db.View(func(tx *bolt.Tx) error {
+		recorded = string(tx.Bucket([]byte(metadataBucket)).Get([]byte(pathKeysMetadata)))
+		return nil
+	})
+	if recorded == scheme {
+		return nil
+	}
+
+	legacyCompatible := recorded == "" && runtime.GOOS != "windows" && !m.keys.FoldCase
+	if !legacyCompatible {
+		err := m.db.Update(func(tx *bolt.Tx) error {
+			for _, name := range []string{modulesBucket, fileHashesBucket, predicateBucket} {
+				if err := tx.DeleteBucket([]byte(name)); err != nil && err != bolt.ErrBucketNotFound {
+					return err
+				}
+				if _, err := tx.CreateBucket([]byte(name)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate cache keys: %w", err)
+		}
+	}
+
+	return m.setMetadata(pathKeysMetadata, scheme)
+}
+
 // Clear removes all cached modules
 func (m *Manager) Clear() error {
 	return m.db.Update(func(tx *bolt.Tx) error {
@@ -264,6 +345,12 @@ func (m *Manager) Clear() error {
 				return err
 			}
 		}
+		if err := tx.DeleteBucket([]byte(predicateBucket)); err != nil {
+			// Ignore if bucket doesn't exist
+			if err.Error() != "bucket not found" {
+				return err
+			}
+		}
 
 		if _, err := tx.CreateBucket([]byte(modulesBucket)); err != nil {
 			return err
@@ -271,6 +358,9 @@ func (m *Manager) Clear() error {
 		if _, err := tx.CreateBucket([]byte(fileHashesBucket)); err != nil {
 			return err
 		}
+		if _, err := tx.CreateBucket([]byte(predicateBucket)); err != nil {
+			return err
+		}
 
 		return nil
 	})
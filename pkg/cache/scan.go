@@ -0,0 +1,174 @@
+/*
+# Module: pkg/cache/scan.go
+Ordered prefix scans over the persistent module cache.
+
+BoltDB keeps keys sorted, so cached modules under a path prefix can be read
+with a cursor seek instead of a full bucket scan. A predicate index
+(predicate, subject, object, file path) supports scanning all cached
+triples for a predicate namespace such as the code: schema.
+
+## Linked Modules
+- [manager](./manager.go) - Persistent cache manager
+
+## Tags
+cache, persistence, index, iterator
+
+## Exports
+ScanPathPrefix, ScanPredicatePrefix
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#scan.go> a code:Module ;
+    code:name "pkg/cache/scan.go" ;
+    code:description "Ordered prefix scans over the persistent module cache" ;
+    code:language "go" ;
+    code:layer "cache" ;
+    code:linksTo <./manager.go> ;
+    code:exports <#ScanPathPrefix>, <#ScanPredicatePrefix> ;
+    code:tags "cache", "persistence", "index", "iterator" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"iter"
+	"path/filepath"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// keySeparator separates the parts of a predicate index key
+const keySeparator = "\x00"
+
+// ScanPathPrefix iterates over cached modules whose file path starts with
+// prefix, in path order. Relative prefixes are resolved against the cache
+// root. Paths are returned as path keys (forward slashes, case-folded where
+// configured) and entries as stored, without checking file hashes.
+func (m *Manager) ScanPathPrefix(prefix string) iter.Seq2[string, *CachedData] {
+	// Key normalization drops the trailing separator that marks a directory
+	directory := prefix == "" || prefix == "." || strings.HasSuffix(prefix, "/") || strings.HasSuffix(prefix, `\`)
+	if !filepath.IsAbs(prefix) {
+		prefix = filepath.Join(m.root, prefix)
+	}
+	prefix = m.key(prefix)
+	if directory && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return func(yield func(string, *CachedData) bool) {
+		type entry struct {
+			path string
+			data *CachedData
+		}
+
+		// Collect within the read transaction so the loop body may write
+		var entries []entry
+		_ = m.db.View(func(tx *bolt.Tx) error {
+			cursor := tx.Bucket([]byte(modulesBucket)).Cursor()
+			p := []byte(prefix)
+			for k, v := cursor.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = cursor.Next() {
+				var cached CachedModule
+				if err := json.Unmarshal(v, &cached); err != nil {
+					continue
+				}
+				moduleJSON, err := json.Marshal(cached.Module)
+				if err != nil {
+					continue
+				}
+				entries = append(entries, entry{
+					path: string(k),
+					data: &CachedData{ModuleJSON: moduleJSON, Triples: cached.Triples},
+				})
+			}
+			return nil
+		})
+
+		for _, e := range entries {
+			if !yield(e.path, e.data) {
+				return
+			}
+		}
+	}
+}
+
+// ScanPredicatePrefix iterates over cached triples whose predicate starts
+// with prefix, ordered by predicate, subject and object, along with the file
+// path they were parsed from
+func (m *Manager) ScanPredicatePrefix(prefix string) iter.Seq2[Triple, string] {
+	return func(yield func(Triple, string) bool) {
+		type entry struct {
+			triple Triple
+			path   string
+		}
+
+		var entries []entry
+		_ = m.db.View(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(predicateBucket))
+			if bucket == nil {
+				return nil
+			}
+			cursor := bucket.Cursor()
+			p := []byte(prefix)
+			for k, _ := cursor.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = cursor.Next() {
+				parts := strings.Split(string(k), keySeparator)
+				if len(parts) != 4 {
+					continue
+				}
+				entries = append(entries, entry{
+					triple: Triple{Predicate: parts[0], Subject: parts[1], Object: parts[2]},
+					path:   parts[3],
+				})
+			}
+			return nil
+		})
+
+		for _, e := range entries {
+			if !yield(e.triple, e.path) {
+				return
+			}
+		}
+	}
+}
+
+// predicateKey builds the predicate index key for a triple parsed from filePath
+func predicateKey(t Triple, filePath string) []byte {
+	return []byte(strings.Join([]string{t.Predicate, t.Subject, t.Object, filePath}, keySeparator))
+}
+
+// indexPredicates adds a module's triples to the predicate index
+func indexPredicates(tx *bolt.Tx, filePath string, triples []Triple) error {
+	bucket := tx.Bucket([]byte(predicateBucket))
+	for _, t := range triples {
+		if err := bucket.Put(predicateKey(t, filePath), []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unindexPredicates removes a previously cached module's triples from the
+// predicate index. data is the stored CachedModule JSON (nil if absent).
+func unindexPredicates(tx *bolt.Tx, filePath string, data []byte) error {
+	if data == nil {
+		return nil
+	}
+
+	var cached CachedModule
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil // Unreadable entries are overwritten anyway
+	}
+
+	bucket := tx.Bucket([]byte(predicateBucket))
+	for _, t := range cached.Triples {
+		if err := bucket.Delete(predicateKey(t, filePath)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newScanTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	root := t.TempDir()
+	manager, err := NewManager(root)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+
+	files := map[string][]Triple{
+		"pkg/api/handler.go": {
+			{Subject: "<#handler.go>", Predicate: "https://schema.codedoc.org/layer", Object: "api"},
+			{Subject: "<#handler.go>", Predicate: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type", Object: "code:Module"},
+		},
+		"pkg/api/routes.go": {
+			{Subject: "<#routes.go>", Predicate: "https://schema.codedoc.org/layer", Object: "api"},
+		},
+		"pkg/apiclient/client.go": {
+			{Subject: "<#client.go>", Predicate: "https://schema.codedoc.org/layer", Object: "client"},
+		},
+	}
+	for path, triples := range files {
+		fullPath := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte("package x\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if err := manager.Set(fullPath, map[string]string{"path": path}, triples); err != nil {
+			t.Fatalf("Failed to cache module: %v", err)
+		}
+	}
+
+	return manager
+}
+
+func TestManager_ScanPathPrefix(t *testing.T) {
+	manager := newScanTestManager(t)
+
+	var paths []string
+	for path, data := range manager.ScanPathPrefix("pkg/api/") {
+		rel, _ := filepath.Rel(manager.root, path)
+		paths = append(paths, filepath.ToSlash(rel))
+		if len(data.Triples) == 0 {
+			t.Errorf("Expected cached triples for %s", rel)
+		}
+	}
+
+	if len(paths) != 2 || paths[0] != "pkg/api/handler.go" || paths[1] != "pkg/api/routes.go" {
+		t.Errorf("Expected the two pkg/api modules in order, got %v", paths)
+	}
+
+	count := 0
+	for range manager.ScanPathPrefix("") {
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 modules under the root, got %d", count)
+	}
+}
+
+func TestManager_ScanPredicatePrefix(t *testing.T) {
+	manager := newScanTestManager(t)
+
+	var objects []string
+	for triple, path := range manager.ScanPredicatePrefix("https://schema.codedoc.org/layer") {
+		objects = append(objects, triple.Object)
+		if path == "" {
+			t.Error("Expected source file path for indexed triple")
+		}
+	}
+	if len(objects) != 3 {
+		t.Fatalf("Expected 3 layer triples, got %v", objects)
+	}
+
+	// Invalidating a module removes its triples from the index
+	if err := manager.Invalidate(filepath.Join(manager.root, "pkg/apiclient/client.go")); err != nil {
+		t.Fatalf("Invalidate failed: %v", err)
+	}
+	count := 0
+	for range manager.ScanPredicatePrefix("https://schema.codedoc.org/") {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 code: triples after invalidation, got %d", count)
+	}
+
+	if err := manager.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	for triple := range manager.ScanPredicatePrefix("") {
+		t.Errorf("Expected empty index after Clear, got %+v", triple)
+	}
+}
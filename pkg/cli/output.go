@@ -39,6 +39,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
 	"github.com/schollz/progressbar/v3"
 )
 
@@ -70,6 +71,12 @@ func NewOutputFormatter(quiet, verbose, noColor bool) *OutputFormatter {
 	}
 }
 
+// SetWriter redirects output other than errors, e.g. to stderr when stdout
+// carries a protocol
+func (o *OutputFormatter) SetWriter(w io.Writer) {
+	o.writer = w
+}
+
 // Success prints a success message in green
 func (o *OutputFormatter) Success(format string, args ...interface{}) {
 	if o.quiet {
@@ -160,13 +167,10 @@ func (o *OutputFormatter) Table(headers []string, rows [][]string) {
 	}
 
 	// Create simple table writer
-	table := tablewriter.NewWriter(o.writer)
+	table := tablewriter.NewTable(o.writer, tablewriter.WithHeaderAutoFormat(tw.Off))
 
 	// Set headers - convert []string to []any
-	headerInterface := make([]interface{}, len(headers))
-	for i, v := range headers {
-		headerInterface[i] = v
-	}
+	headerInterface := headerCells(headers)
 	table.Header(headerInterface...)
 
 	// Add rows
@@ -189,13 +193,10 @@ func (o *OutputFormatter) TableWithBorders(headers []string, rows [][]string) {
 	}
 
 	// Create table writer
-	table := tablewriter.NewWriter(o.writer)
+	table := tablewriter.NewTable(o.writer, tablewriter.WithHeaderAutoFormat(tw.Off))
 
 	// Set headers - convert []string to []any
-	headerInterface := make([]interface{}, len(headers))
-	for i, v := range headers {
-		headerInterface[i] = v
-	}
+	headerInterface := headerCells(headers)
 	table.Header(headerInterface...)
 
 	// Add rows
@@ -211,6 +212,16 @@ func (o *OutputFormatter) TableWithBorders(headers []string, rows [][]string) {
 	table.Render()
 }
 
+// headerCells upper-cases table headers. The table writer's own header
+// formatting is off, since it spaces out hyphens ("FAN - IN").
+func headerCells(headers []string) []interface{} {
+	cells := make([]interface{}, len(headers))
+	for i, v := range headers {
+		cells[i] = strings.ToUpper(v)
+	}
+	return cells
+}
+
 // ProgressBar creates a new progress bar
 func (o *OutputFormatter) ProgressBar(total int, description string) *progressbar.ProgressBar {
 	if o.quiet {
@@ -0,0 +1,282 @@
+/*
+# Module: pkg/dashboard/dashboard.go
+Project dashboard aggregation.
+
+Collects graph statistics, shadow statistics, rule status, documentation
+coverage, dependency cycles and coupling hotspots into a single snapshot
+suitable for a periodic architecture review.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [../analysis](../analysis/graph_algorithms.go) - Cycle detection
+- [../rules](../rules/engine.go) - Rule validation
+- [../shadow](../shadow/index.go) - Shadow index statistics
+- [../scanner](../scanner/scanner.go) - Scanned file information
+
+## Tags
+dashboard, statistics, reporting
+
+## Exports
+Dashboard, GraphSummary, ShadowSummary, RulesSummary, DocsCoverage, Hotspot, Options, Collect,
+FileCoverage
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#dashboard.go> a code:Module ;
+    code:name "pkg/dashboard/dashboard.go" ;
+    code:description "Project dashboard aggregation" ;
+    code:language "go" ;
+    code:layer "dashboard" ;
+    code:linksTo <../graph/graph.go>, <../analysis/graph_algorithms.go>, <../rules/engine.go>,
+                 <../shadow/index.go>, <../scanner/scanner.go> ;
+    code:exports <#Dashboard>, <#GraphSummary>, <#ShadowSummary>, <#RulesSummary>,
+                 <#DocsCoverage>, <#Hotspot>, <#Options>, <#Collect>, <#FileCoverage> ;
+    code:tags "dashboard", "statistics", "reporting" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/rules"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+)
+
+// DefaultTopN is the default number of hotspots reported
+const DefaultTopN = 10
+
+// Dashboard is a point-in-time overview of a project
+type Dashboard struct {
+	Root        string                   `json:"root"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Graph       GraphSummary             `json:"graph"`
+	Languages   *graph.LanguageBreakdown `json:"languages"`
+	Shadow      *ShadowSummary           `json:"shadow,omitempty"`
+	Rules       RulesSummary             `json:"rules"`
+	Docs        DocsCoverage             `json:"docs"`
+	Cycles      [][]string               `json:"cycles"`
+	Hotspots    []Hotspot                `json:"hotspots"`
+}
+
+// GraphSummary summarizes the knowledge graph
+type GraphSummary struct {
+	Modules           int            `json:"modules"`
+	Triples           int            `json:"triples"`
+	Relationships     int            `json:"relationships"`
+	ModulesByLanguage map[string]int `json:"modules_by_language"`
+	ModulesByLayer    map[string]int `json:"modules_by_layer"`
+}
+
+// ShadowSummary summarizes the shadow file system index
+type ShadowSummary struct {
+	Entries       int `json:"entries"`
+	Triples       int `json:"triples"`
+	ManualEntries int `json:"manual_entries"`
+	AutoEntries   int `json:"auto_entries"`
+	MixedEntries  int `json:"mixed_entries"`
+}
+
+// RulesSummary summarizes architectural rule validation
+type RulesSummary struct {
+	Source       string   `json:"source"`
+	Total        int      `json:"total"`
+	Passed       int      `json:"passed"`
+	Failed       int      `json:"failed"`
+	ErrorCount   int      `json:"error_count"`
+	WarningCount int      `json:"warning_count"`
+	InfoCount    int      `json:"info_count"`
+	FailedRules  []string `json:"failed_rules,omitempty"`
+}
+
+// DocsCoverage summarizes LinkedDoc documentation coverage.
+// Generated files are excluded from the percentage.
+type DocsCoverage struct {
+	SourceFiles        int     `json:"source_files"`
+	DocumentedFiles    int     `json:"documented_files"`
+	GeneratedFiles     int     `json:"generated_files"`
+	CoveragePercent    float64 `json:"coverage_percent"`
+	DescribedModules   int     `json:"described_modules"`
+	UndescribedModules int     `json:"undescribed_modules"`
+}
+
+// Hotspot is a highly coupled module
+type Hotspot struct {
+	Path         string `json:"path"`
+	Layer        string `json:"layer,omitempty"`
+	Dependents   int    `json:"dependents"`
+	Dependencies int    `json:"dependencies"`
+	Score        int    `json:"score"`
+}
+
+// Options configures dashboard collection
+type Options struct {
+	Files        []*scanner.FileInfo // Scanned files for docs coverage (optional)
+	ShadowStats  *shadow.IndexStats  // Shadow index statistics (optional)
+	Rules        []*rules.Rule       // Rules to validate (built-in rules if empty)
+	RulesSource  string              // Description of where rules came from
+	Preprocessor *query.Preprocessor // Shared prefixes and macros for rules (optional)
+	TopN         int                 // Number of hotspots (default: 10)
+}
+
+// Collect builds a dashboard for the graph
+func Collect(g *graph.Graph, opts Options) (*Dashboard, error) {
+	if opts.TopN <= 0 {
+		opts.TopN = DefaultTopN
+	}
+
+	d := &Dashboard{
+		Root:        g.Root,
+		GeneratedAt: time.Now(),
+		Graph: GraphSummary{
+			Modules:           len(g.Modules),
+			Triples:           g.Statistics.TotalTriples,
+			Relationships:     g.Statistics.TotalRelationships,
+			ModulesByLanguage: g.Statistics.ModulesByLanguage,
+			ModulesByLayer:    g.Statistics.ModulesByLayer,
+		},
+		Languages: g.AnalyzeLanguages(),
+		Cycles:    analysis.CyclicDependencies(g),
+		Hotspots:  findHotspots(g, opts.TopN),
+	}
+
+	if opts.ShadowStats != nil {
+		d.Shadow = &ShadowSummary{
+			Entries:       opts.ShadowStats.TotalEntries,
+			Triples:       opts.ShadowStats.TotalTriples,
+			ManualEntries: opts.ShadowStats.ManualEntries,
+			AutoEntries:   opts.ShadowStats.AutoEntries,
+			MixedEntries:  opts.ShadowStats.MixedEntries,
+		}
+	}
+
+	rulesSummary, err := validateRules(g, opts)
+	if err != nil {
+		return nil, err
+	}
+	d.Rules = rulesSummary
+
+	d.Docs = docsCoverage(g, opts.Files)
+
+	return d, nil
+}
+
+// validateRules runs the configured (or built-in) rules against the graph
+func validateRules(g *graph.Graph, opts Options) (RulesSummary, error) {
+	ruleList := opts.Rules
+	source := opts.RulesSource
+	if len(ruleList) == 0 {
+		ruleList = rules.GetBuiltInRules()
+		source = "built-in"
+	}
+
+	engine := rules.NewEngine(g)
+	engine.SetPreprocessor(opts.Preprocessor)
+
+	result, err := engine.Validate(ruleList)
+	if err != nil {
+		return RulesSummary{}, fmt.Errorf("failed to validate rules: %w", err)
+	}
+
+	summary := RulesSummary{
+		Source:       source,
+		Total:        result.TotalRules,
+		Passed:       len(result.PassedRules),
+		Failed:       len(result.FailedRules),
+		ErrorCount:   result.ErrorCount,
+		WarningCount: result.WarningCount,
+		InfoCount:    result.InfoCount,
+	}
+	for _, rule := range result.FailedRules {
+		summary.FailedRules = append(summary.FailedRules, rule.ID)
+	}
+
+	return summary, nil
+}
+
+// docsCoverage computes LinkedDoc coverage from scanned files and module descriptions
+func docsCoverage(g *graph.Graph, files []*scanner.FileInfo) DocsCoverage {
+	coverage := FileCoverage(files)
+
+	for _, module := range g.Modules {
+		if module.IsGenerated() {
+			continue
+		}
+		if module.Description != "" {
+			coverage.DescribedModules++
+		} else {
+			coverage.UndescribedModules++
+		}
+	}
+
+	return coverage
+}
+
+// FileCoverage computes LinkedDoc coverage of scanned files. Module
+// description counts are left empty.
+func FileCoverage(files []*scanner.FileInfo) DocsCoverage {
+	var coverage DocsCoverage
+
+	for _, file := range files {
+		if file.Generated {
+			coverage.GeneratedFiles++
+			continue
+		}
+		coverage.SourceFiles++
+		if file.HasLinkedDoc {
+			coverage.DocumentedFiles++
+		}
+	}
+
+	if coverage.SourceFiles > 0 {
+		coverage.CoveragePercent = float64(coverage.DocumentedFiles) / float64(coverage.SourceFiles) * 100.0
+	}
+
+	return coverage
+}
+
+// findHotspots returns the most coupled modules, ranked by dependents plus
+// dependencies. Generated modules are skipped.
+func findHotspots(g *graph.Graph, topN int) []Hotspot {
+	hotspots := make([]Hotspot, 0, len(g.Modules))
+	for _, module := range g.Modules {
+		if module.IsGenerated() {
+			continue
+		}
+		score := len(module.Dependents) + len(module.Dependencies)
+		if score == 0 {
+			continue
+		}
+		hotspots = append(hotspots, Hotspot{
+			Path:         module.Path,
+			Layer:        module.Layer,
+			Dependents:   len(module.Dependents),
+			Dependencies: len(module.Dependencies),
+			Score:        score,
+		})
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+		if hotspots[i].Dependents != hotspots[j].Dependents {
+			return hotspots[i].Dependents > hotspots[j].Dependents
+		}
+		return hotspots[i].Path < hotspots[j].Path
+	})
+
+	if len(hotspots) > topN {
+		hotspots = hotspots[:topN]
+	}
+	return hotspots
+}
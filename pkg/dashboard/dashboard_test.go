@@ -0,0 +1,157 @@
+/*
+# Module: pkg/dashboard/dashboard_test.go
+Tests for project dashboard aggregation.
+
+## Tags
+dashboard, test
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#dashboard_test.go> a code:Module ;
+    code:name "pkg/dashboard/dashboard_test.go" ;
+    code:description "Tests for project dashboard aggregation" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./dashboard.go>, <./report.go> ;
+    code:tags "dashboard", "test" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package dashboard
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+)
+
+func newTestGraph() *graph.Graph {
+	g := graph.NewGraph("/project", store.NewTripleStore())
+
+	modules := []struct {
+		path, layer, description string
+		deps                     []string
+	}{
+		{"main.go", "cmd", "Entry point", []string{"a.go", "b.go"}},
+		{"a.go", "services", "Service A", []string{"b.go"}},
+		{"b.go", "services", "", []string{"a.go"}},
+		{"c.go", "utils", "Isolated", nil},
+	}
+	for _, m := range modules {
+		module := graph.NewModule(m.path, "<#"+m.path+">")
+		module.Layer = m.layer
+		module.Description = m.description
+		for _, dep := range m.deps {
+			module.AddDependency(dep)
+		}
+		g.AddModule(module)
+	}
+
+	for _, module := range g.Modules {
+		for _, dep := range module.Dependencies {
+			if target, ok := g.Modules[dep]; ok {
+				target.AddDependent(module.Path)
+			}
+		}
+	}
+
+	return g
+}
+
+func TestCollect(t *testing.T) {
+	g := newTestGraph()
+
+	files := []*scanner.FileInfo{
+		{Path: "main.go", HasLinkedDoc: true},
+		{Path: "a.go", HasLinkedDoc: true},
+		{Path: "b.go", HasLinkedDoc: true},
+		{Path: "c.go", HasLinkedDoc: true},
+		{Path: "d.go"},
+		{Path: "gen.pb.go", Generated: true},
+	}
+
+	d, err := Collect(g, Options{
+		Files:       files,
+		ShadowStats: &shadow.IndexStats{TotalEntries: 4},
+		TopN:        2,
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if d.Graph.Modules != 4 {
+		t.Errorf("Expected 4 modules, got %d", d.Graph.Modules)
+	}
+	if d.Shadow == nil || d.Shadow.Entries != 4 {
+		t.Errorf("Expected shadow summary with 4 entries, got %+v", d.Shadow)
+	}
+	if d.Rules.Source != "built-in" || d.Rules.Total == 0 {
+		t.Errorf("Expected built-in rules to run, got %+v", d.Rules)
+	}
+
+	if d.Docs.SourceFiles != 5 || d.Docs.DocumentedFiles != 4 || d.Docs.GeneratedFiles != 1 {
+		t.Errorf("Unexpected docs coverage: %+v", d.Docs)
+	}
+	if d.Docs.CoveragePercent != 80.0 {
+		t.Errorf("Expected 80%% coverage, got %.1f", d.Docs.CoveragePercent)
+	}
+	if d.Docs.UndescribedModules != 1 {
+		t.Errorf("Expected 1 undescribed module, got %d", d.Docs.UndescribedModules)
+	}
+
+	if len(d.Cycles) != 1 {
+		t.Errorf("Expected 1 cycle, got %v", d.Cycles)
+	}
+
+	if len(d.Hotspots) != 2 {
+		t.Fatalf("Expected 2 hotspots, got %d", len(d.Hotspots))
+	}
+	for _, h := range d.Hotspots {
+		if h.Path == "c.go" {
+			t.Error("Isolated module should not be a hotspot")
+		}
+	}
+}
+
+func TestFormatDashboard(t *testing.T) {
+	d, err := Collect(newTestGraph(), Options{})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	md, err := FormatDashboard(d, FormatMarkdown)
+	if err != nil {
+		t.Fatalf("FormatDashboard(md) failed: %v", err)
+	}
+	for _, section := range []string{"# Project Dashboard", "## Rules", "## Cycles (1)", "## Top Hotspots"} {
+		if !strings.Contains(md, section) {
+			t.Errorf("Expected Markdown to contain %q", section)
+		}
+	}
+	if strings.Contains(md, "## Shadow File System") {
+		t.Error("Expected shadow section to be omitted without shadow stats")
+	}
+
+	data, err := FormatDashboard(d, FormatJSON)
+	if err != nil {
+		t.Fatalf("FormatDashboard(json) failed: %v", err)
+	}
+	var decoded Dashboard
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if decoded.Graph.Modules != 4 {
+		t.Errorf("Expected 4 modules in JSON, got %d", decoded.Graph.Modules)
+	}
+
+	if _, err := FormatDashboard(d, "xml"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,206 @@
+/*
+# Module: pkg/dashboard/report.go
+Report formatting for project dashboards.
+
+Renders a dashboard as JSON or Markdown. Table output is rendered by the
+CLI using the shared output formatter.
+
+## Linked Modules
+- [dashboard](./dashboard.go) - Dashboard aggregation
+
+## Tags
+dashboard, reporting, formatting
+
+## Exports
+ReportFormat, FormatDashboard, SortedKeys
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#report.go> a code:Module ;
+    code:name "pkg/dashboard/report.go" ;
+    code:description "Report formatting for project dashboards" ;
+    code:language "go" ;
+    code:layer "dashboard" ;
+    code:linksTo <./dashboard.go> ;
+    code:exports <#ReportFormat>, <#FormatDashboard>, <#SortedKeys> ;
+    code:tags "dashboard", "reporting", "formatting" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// ReportFormat specifies the output format for dashboard reports
+type ReportFormat string
+
+const (
+	FormatJSON     ReportFormat = "json"
+	FormatMarkdown ReportFormat = "md"
+)
+
+// FormatDashboard formats a dashboard according to the specified format
+func FormatDashboard(d *Dashboard, format ReportFormat) (string, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case FormatMarkdown:
+		return formatMarkdown(d), nil
+	default:
+		return "", fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+// formatMarkdown generates a Markdown report
+func formatMarkdown(d *Dashboard) string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "# Project Dashboard")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "_Generated %s_\n\n", d.GeneratedAt.Format("2006-01-02 15:04"))
+
+	// Graph
+	fmt.Fprintln(&b, "## Graph")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "- **Modules:** %d\n", d.Graph.Modules)
+	fmt.Fprintf(&b, "- **Triples:** %d\n", d.Graph.Triples)
+	fmt.Fprintf(&b, "- **Relationships:** %d\n", d.Graph.Relationships)
+	fmt.Fprintln(&b)
+	writeCountTable(&b, "Layer", d.Graph.ModulesByLayer)
+
+	// Languages
+	if d.Languages != nil && len(d.Languages.Languages) > 0 {
+		writeLanguages(&b, d.Languages)
+	}
+
+	// Shadow
+	if d.Shadow != nil {
+		fmt.Fprintln(&b, "## Shadow File System")
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "- **Entries:** %d (%d auto, %d manual, %d mixed)\n",
+			d.Shadow.Entries, d.Shadow.AutoEntries, d.Shadow.ManualEntries, d.Shadow.MixedEntries)
+		fmt.Fprintf(&b, "- **Triples:** %d\n", d.Shadow.Triples)
+		fmt.Fprintln(&b)
+	}
+
+	// Rules
+	fmt.Fprintln(&b, "## Rules")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "- **Source:** %s\n", d.Rules.Source)
+	fmt.Fprintf(&b, "- **Passed:** %d/%d\n", d.Rules.Passed, d.Rules.Total)
+	fmt.Fprintf(&b, "- **Violations:** %d errors, %d warnings, %d info\n",
+		d.Rules.ErrorCount, d.Rules.WarningCount, d.Rules.InfoCount)
+	for _, id := range d.Rules.FailedRules {
+		fmt.Fprintf(&b, "  - ❌ `%s`\n", id)
+	}
+	fmt.Fprintln(&b)
+
+	// Docs
+	fmt.Fprintln(&b, "## Documentation Coverage")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "- **LinkedDoc coverage:** %.1f%% (%d/%d source files)\n",
+		d.Docs.CoveragePercent, d.Docs.DocumentedFiles, d.Docs.SourceFiles)
+	if d.Docs.GeneratedFiles > 0 {
+		fmt.Fprintf(&b, "- **Generated files excluded:** %d\n", d.Docs.GeneratedFiles)
+	}
+	fmt.Fprintf(&b, "- **Modules without description:** %d\n", d.Docs.UndescribedModules)
+	fmt.Fprintln(&b)
+
+	// Cycles
+	fmt.Fprintf(&b, "## Cycles (%d)\n\n", len(d.Cycles))
+	if len(d.Cycles) == 0 {
+		fmt.Fprintln(&b, "No dependency cycles found.")
+	}
+	for _, cycle := range d.Cycles {
+		fmt.Fprintf(&b, "- `%s`\n", strings.Join(cycle, "` → `"))
+	}
+	fmt.Fprintln(&b)
+
+	// Hotspots
+	fmt.Fprintln(&b, "## Top Hotspots")
+	fmt.Fprintln(&b)
+	if len(d.Hotspots) == 0 {
+		fmt.Fprintln(&b, "No coupled modules found.")
+	} else {
+		fmt.Fprintln(&b, "| Module | Layer | Dependents | Dependencies |")
+		fmt.Fprintln(&b, "|--------|-------|------------|--------------|")
+		for _, h := range d.Hotspots {
+			fmt.Fprintf(&b, "| `%s` | %s | %d | %d |\n", h.Path, h.Layer, h.Dependents, h.Dependencies)
+		}
+	}
+
+	return b.String()
+}
+
+// writeLanguages writes the language breakdown with cross-language edges
+// and boundary modules
+func writeLanguages(b *strings.Builder, l *graph.LanguageBreakdown) {
+	fmt.Fprintln(b, "## Languages")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "| Language | Modules | Share | Cross-language out | Cross-language in |")
+	fmt.Fprintln(b, "|---|---|---|---|---|")
+	for _, s := range l.Languages {
+		fmt.Fprintf(b, "| %s | %d | %.1f%% | %d | %d |\n", s.Language, s.Modules, s.Percent, s.CrossOut, s.CrossIn)
+	}
+	fmt.Fprintln(b)
+
+	if !l.IsPolyglot() {
+		return
+	}
+	fmt.Fprintf(b, "**Cross-language dependencies:** %d\n\n", len(l.Edges))
+	if len(l.Boundaries) > 0 {
+		fmt.Fprintln(b, "| Boundary module | Language | Calls |")
+		fmt.Fprintln(b, "|---|---|---|")
+		for _, boundary := range l.Boundaries {
+			fmt.Fprintf(b, "| `%s` | %s | %s |\n", boundary.Path, boundary.Language, strings.Join(boundary.Calls, ", "))
+		}
+		fmt.Fprintln(b)
+	}
+}
+
+// writeCountTable writes a two-column Markdown table sorted by descending count
+func writeCountTable(b *strings.Builder, label string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	keys := SortedKeys(counts)
+	fmt.Fprintf(b, "| %s | Modules |\n", label)
+	fmt.Fprintln(b, "|---|---|")
+	for _, key := range keys {
+		name := key
+		if name == "" {
+			name = "(none)"
+		}
+		fmt.Fprintf(b, "| %s | %d |\n", name, counts[key])
+	}
+	fmt.Fprintln(b)
+}
+
+// SortedKeys returns map keys sorted by descending count and then by name
+func SortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
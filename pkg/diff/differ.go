@@ -36,6 +36,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/scanner"
@@ -112,8 +114,22 @@ func (d *Differ) buildCurrentGraph() (*graph.Graph, error) {
 	})
 }
 
+// GraphAtRef builds the graph for a Git reference in a temporary worktree
+func (d *Differ) GraphAtRef(ref string) (*graph.Graph, error) {
+	return d.buildGraphAtRef(ref)
+}
+
 // buildGraphAtRef builds the graph for a specific Git reference
 func (d *Differ) buildGraphAtRef(ref string) (*graph.Graph, error) {
+	// Build the same subdirectory of the worktree when the repo path is
+	// below the repository root, so module paths match the current graph
+	prefixCmd := exec.Command("git", "rev-parse", "--show-prefix")
+	prefixCmd.Dir = d.gitRepo
+	prefix, err := prefixCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse failed: %w", err)
+	}
+
 	// Create temporary directory
 	tmpDir, err := os.MkdirTemp("", "graphfs-diff-*")
 	if err != nil {
@@ -137,7 +153,7 @@ func (d *Differ) buildGraphAtRef(ref string) (*graph.Graph, error) {
 
 	// Build graph from temp directory
 	builder := graph.NewBuilder()
-	return builder.Build(tmpDir, graph.BuildOptions{
+	return builder.Build(filepath.Join(tmpDir, strings.TrimSpace(string(prefix))), graph.BuildOptions{
 		ScanOptions: scanner.ScanOptions{
 			MaxFileSize:    1024 * 1024,
 			FollowSymlinks: false,
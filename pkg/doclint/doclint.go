@@ -0,0 +1,541 @@
+/*
+# Module: pkg/doclint/doclint.go
+LinkedDoc header style linter.
+
+Checks LinkedDoc headers themselves rather than the graph they describe:
+the Module, Tags and Exports sections are present, the Linked Modules
+section agrees with code:linksTo, tags agree between the prose and RDF and
+come from the project vocabulary, and the description has a sensible
+length. Each issue carries a suggested fix.
+
+## Linked Modules
+- [report](./report.go) - Lint report and output formats
+- [../parser](../parser/parser.go) - LinkedDoc parser
+- [../graph](../graph/links.go) - Edit distance
+
+## Tags
+docs, lint, linkeddoc, style
+
+## Exports
+Severity, Issue, Options, Linter, NewLinter
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#doclint.go> a code:Module ;
+    code:name "pkg/doclint/doclint.go" ;
+    code:description "LinkedDoc header style linter" ;
+    code:language "go" ;
+    code:layer "docs" ;
+    code:linksTo <./report.go>, <../parser/parser.go>, <../graph/links.go> ;
+    code:exports <#Severity>, <#Issue>, <#Options>, <#Linter>, <#NewLinter> ;
+    code:tags "docs", "lint", "linkeddoc", "style" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package doclint
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+)
+
+// Severity is the severity of a lint issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rules reported by the linter
+const (
+	RuleRDF             = "rdf-syntax"
+	RuleMissingSection  = "missing-section"
+	RuleModuleName      = "module-name"
+	RuleLinksMismatch   = "links-mismatch"
+	RuleTagsMismatch    = "tags-mismatch"
+	RuleTagFormat       = "tag-format"
+	RuleTagVocabulary   = "tag-vocabulary"
+	RuleTagDeprecated   = "tag-deprecated"
+	RuleDescription     = "description"
+	RuleDescriptionSize = "description-length"
+)
+
+// Default description length bounds
+const (
+	DefaultMinDescription = 10
+	DefaultMaxDescription = 100
+)
+
+// Issue is one style problem in a LinkedDoc header
+type Issue struct {
+	Path     string   `json:"path"`
+	Line     int      `json:"line"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Fix      string   `json:"fix,omitempty"` // Suggested fix
+}
+
+// Options configures the linter
+type Options struct {
+	MinDescription int // Minimum description length (0 = default)
+	MaxDescription int // Maximum description length (0 = default)
+
+	// Vocabulary lists the allowed tags; empty allows any well-formed tag
+	Vocabulary []string
+
+	// Deprecated maps deprecated tags to their replacement, or ""
+	Deprecated map[string]string
+}
+
+// Linter checks LinkedDoc headers against the style conventions
+type Linter struct {
+	opts       Options
+	parser     *parser.Parser
+	vocabulary map[string]bool
+}
+
+// NewLinter creates a linter
+func NewLinter(opts Options) *Linter {
+	if opts.MinDescription <= 0 {
+		opts.MinDescription = DefaultMinDescription
+	}
+	if opts.MaxDescription <= 0 {
+		opts.MaxDescription = DefaultMaxDescription
+	}
+	vocabulary := make(map[string]bool, len(opts.Vocabulary))
+	for _, tag := range opts.Vocabulary {
+		vocabulary[tag] = true
+	}
+	return &Linter{opts: opts, parser: parser.NewParser(), vocabulary: vocabulary}
+}
+
+// LintFile lints the LinkedDoc header of the file at relPath under rootPath.
+// Files without a LinkedDoc block have no issues.
+func (l *Linter) LintFile(rootPath, relPath, language string) ([]Issue, error) {
+	content, err := os.ReadFile(filepath.Join(rootPath, relPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	return l.LintContent(filepath.ToSlash(relPath), string(content), language), nil
+}
+
+// LintContent lints the LinkedDoc header in content, reporting issues
+// against relPath
+func (l *Linter) LintContent(relPath, content, language string) []Issue {
+	h := parseHeader(content, parser.CommentStyleFor(language))
+	if h == nil {
+		return nil
+	}
+
+	c := &checker{linter: l, path: relPath, header: h}
+	triples, err := l.parser.ParseStringFor(content, language)
+	if err != nil {
+		c.add(h.markerLine, RuleRDF, SeverityError, fmt.Sprintf("LinkedDoc RDF does not parse: %v", err), "")
+		return c.issues
+	}
+	c.rdf = moduleProperties(triples)
+
+	c.checkSections()
+	c.checkModuleName()
+	c.checkLinks()
+	c.checkTags()
+	c.checkDescription()
+
+	sort.SliceStable(c.issues, func(i, j int) bool { return c.issues[i].Line < c.issues[j].Line })
+	return c.issues
+}
+
+// section is a "## Name" section of a LinkedDoc header
+type section struct {
+	line  int      // Line of the heading
+	lines []string // Non-empty lines of the body
+	first int      // Line of the first body line, or the heading
+}
+
+// header is the prose part of a LinkedDoc header, before the RDF block
+type header struct {
+	module     string // Value of "# Module:"
+	moduleLine int    // 0 if there is no Module line
+	markerLine int    // Line of the RDF start marker
+	sections   map[string]*section
+	lines      []string // Lines of the file
+}
+
+// parseHeader reads the prose sections before the first RDF block, or
+// returns nil if content has no LinkedDoc block
+func parseHeader(content string, style parser.CommentStyle) *header {
+	lines := strings.Split(content, "\n")
+	h := &header{sections: make(map[string]*section), lines: lines}
+
+	var current *section
+	for i := style.SkipLines; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.Contains(line, style.StartMarker) {
+			h.markerLine = i + 1
+			return h
+		}
+		if style.LinePrefix != "" {
+			line = strings.TrimSpace(strings.TrimPrefix(line, style.LinePrefix))
+		}
+
+		switch {
+		case strings.HasPrefix(line, "# Module:"):
+			h.module = strings.TrimSpace(strings.TrimPrefix(line, "# Module:"))
+			h.moduleLine = i + 1
+			current = nil
+		case strings.HasPrefix(line, "## "):
+			current = &section{line: i + 1, first: i + 1}
+			h.sections[strings.TrimSpace(strings.TrimPrefix(line, "## "))] = current
+		case current != nil && line != "":
+			if len(current.lines) == 0 {
+				current.first = i + 1
+			}
+			current.lines = append(current.lines, line)
+		}
+	}
+	return nil
+}
+
+// rdfModule holds the code: properties of the module resource
+type rdfModule struct {
+	found       bool
+	name        string
+	description string
+	linksTo     []string
+	tags        []string
+}
+
+// moduleProperties extracts the module resource's properties from triples
+func moduleProperties(triples []parser.Triple) rdfModule {
+	var subject string
+	for _, t := range triples {
+		if strings.HasSuffix(t.Predicate, "rdf-syntax-ns#type") && strings.HasSuffix(t.Object.String(), "Module") {
+			subject = t.Subject
+			break
+		}
+	}
+
+	m := rdfModule{found: subject != ""}
+	for _, t := range triples {
+		if t.Subject != subject {
+			continue
+		}
+		value := t.Object.String()
+		switch {
+		case strings.HasSuffix(t.Predicate, "/name"):
+			m.name = value
+		case strings.HasSuffix(t.Predicate, "/description"):
+			m.description = value
+		case strings.HasSuffix(t.Predicate, "/linksTo"):
+			m.linksTo = append(m.linksTo, value)
+		case strings.HasSuffix(t.Predicate, "/tags"):
+			m.tags = append(m.tags, value)
+		}
+	}
+	return m
+}
+
+// checker accumulates the issues of one file
+type checker struct {
+	linter *Linter
+	path   string
+	header *header
+	rdf    rdfModule
+	issues []Issue
+}
+
+// rdfLine returns the line of the first code:<property> statement in the
+// RDF block, or the line of the start marker
+func (c *checker) rdfLine(property string) int {
+	for i := c.header.markerLine; i < len(c.header.lines); i++ {
+		if fields := strings.Fields(c.header.lines[i]); len(fields) > 0 && fields[0] == "code:"+property {
+			return i + 1
+		}
+	}
+	return c.header.markerLine
+}
+
+func (c *checker) add(line int, rule string, severity Severity, message, fix string) {
+	if line == 0 {
+		line = 1
+	}
+	c.issues = append(c.issues, Issue{
+		Path:     c.path,
+		Line:     line,
+		Rule:     rule,
+		Severity: severity,
+		Message:  message,
+		Fix:      fix,
+	})
+}
+
+// checkSections reports missing required sections
+func (c *checker) checkSections() {
+	if c.header.moduleLine == 0 {
+		c.add(1, RuleMissingSection, SeverityError, "LinkedDoc header has no '# Module:' line",
+			fmt.Sprintf("start the header with '# Module: %s'", c.path))
+	}
+	if !c.rdf.found {
+		c.add(c.header.markerLine, RuleMissingSection, SeverityError, "LinkedDoc RDF has no resource of type code:Module",
+			fmt.Sprintf("declare '<#%s> a code:Module'", path.Base(c.path)))
+	}
+
+	for _, name := range []string{"Tags", "Exports"} {
+		if c.header.sections[name] == nil {
+			fix := fmt.Sprintf("add a '## %s' section before the RDF block", name)
+			if name == "Tags" && len(c.rdf.tags) > 0 {
+				fix = fmt.Sprintf("add '## Tags' with: %s", strings.Join(c.rdf.tags, ", "))
+			}
+			c.add(c.header.markerLine, RuleMissingSection, SeverityError,
+				fmt.Sprintf("LinkedDoc header has no '## %s' section", name), fix)
+		}
+	}
+}
+
+// checkModuleName reports Module lines and code:name values that do not
+// name the file
+func (c *checker) checkModuleName() {
+	if c.header.moduleLine != 0 && !namesFile(c.header.module, c.path) {
+		c.add(c.header.moduleLine, RuleModuleName, SeverityWarning,
+			fmt.Sprintf("'# Module: %s' does not name this file", c.header.module),
+			fmt.Sprintf("change it to '# Module: %s'", c.path))
+	}
+	if c.rdf.name != "" && !namesFile(c.rdf.name, c.path) {
+		c.add(c.rdfLine("name"), RuleModuleName, SeverityWarning,
+			fmt.Sprintf("code:name %q does not name this file", c.rdf.name),
+			fmt.Sprintf("change it to code:name %q", c.path))
+	}
+}
+
+// namesFile reports whether name identifies the file at relPath. Either
+// may be relative to a different root, so one suffix of the other matches.
+func namesFile(name, relPath string) bool {
+	name = strings.TrimPrefix(name, "./")
+	return name == relPath || strings.HasSuffix(relPath, "/"+name) || strings.HasSuffix(name, "/"+relPath)
+}
+
+// linkPattern matches a Linked Modules entry: "- [name](target) - text"
+var linkPattern = regexp.MustCompile(`^[-*]\s*\[([^\]]*)\]\(([^)]+)\)`)
+
+// checkLinks compares the Linked Modules section with code:linksTo
+func (c *checker) checkLinks() {
+	links := c.header.sections["Linked Modules"]
+	rdfLinks := make(map[string]bool)
+	for _, target := range c.rdf.linksTo {
+		rdfLinks[path.Clean(target)] = true
+	}
+
+	if links == nil {
+		if len(c.rdf.linksTo) > 0 {
+			c.add(c.rdfLine("linksTo"), RuleMissingSection, SeverityWarning,
+				"code:linksTo has targets but the header has no '## Linked Modules' section",
+				"add '## Linked Modules' with: "+strings.Join(linkEntries(c.rdf.linksTo), "; "))
+		}
+		return
+	}
+
+	listed := make(map[string]bool)
+	for i, line := range links.lines {
+		match := linkPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		target := path.Clean(match[2])
+		listed[target] = true
+		if !rdfLinks[target] {
+			c.add(links.first+i, RuleLinksMismatch, SeverityWarning,
+				fmt.Sprintf("Linked Modules lists %s but code:linksTo does not", match[2]),
+				fmt.Sprintf("add <%s> to code:linksTo, or remove the entry", match[2]))
+		}
+	}
+
+	var missing []string
+	for _, target := range c.rdf.linksTo {
+		if !listed[path.Clean(target)] {
+			missing = append(missing, target)
+		}
+	}
+	if len(missing) > 0 {
+		c.add(links.line, RuleLinksMismatch, SeverityWarning,
+			fmt.Sprintf("code:linksTo has %s but Linked Modules does not list it", strings.Join(missing, ", ")),
+			"add "+strings.Join(linkEntries(missing), "; "))
+	}
+}
+
+// linkEntries formats Linked Modules entries for link targets
+func linkEntries(targets []string) []string {
+	entries := make([]string, len(targets))
+	for i, target := range targets {
+		name := strings.TrimSuffix(path.Base(target), path.Ext(target))
+		entries[i] = fmt.Sprintf("'- [%s](%s)'", name, target)
+	}
+	return entries
+}
+
+// tagPattern is the tag style: lowercase words joined by hyphens
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// checkTags compares the Tags section with code:tags and checks every tag
+// against the style and vocabulary
+func (c *checker) checkTags() {
+	tags := c.header.sections["Tags"]
+	var listed []string
+	line := c.header.markerLine
+	if tags != nil {
+		listed = splitList(tags.lines)
+		line = tags.first
+	}
+
+	if tags != nil {
+		if onlyProse, onlyRDF := difference(listed, c.rdf.tags), difference(c.rdf.tags, listed); len(onlyProse) > 0 || len(onlyRDF) > 0 {
+			var parts []string
+			if len(onlyProse) > 0 {
+				parts = append(parts, "only in ## Tags: "+strings.Join(onlyProse, ", "))
+			}
+			if len(onlyRDF) > 0 {
+				parts = append(parts, "only in code:tags: "+strings.Join(onlyRDF, ", "))
+			}
+			c.add(line, RuleTagsMismatch, SeverityWarning,
+				"## Tags and code:tags differ ("+strings.Join(parts, "; ")+")",
+				"list the same tags in both")
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range append(listed, c.rdf.tags...) {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		c.checkTag(line, tag)
+	}
+}
+
+// checkTag checks one tag's style, deprecation and vocabulary membership
+func (c *checker) checkTag(line int, tag string) {
+	l := c.linter
+	if replacement, deprecated := l.opts.Deprecated[tag]; deprecated {
+		fix := "remove it"
+		if replacement != "" {
+			fix = fmt.Sprintf("replace it with %q", replacement)
+		}
+		c.add(line, RuleTagDeprecated, SeverityWarning, fmt.Sprintf("tag %q is deprecated", tag), fix)
+		return
+	}
+
+	if !tagPattern.MatchString(tag) {
+		c.add(line, RuleTagFormat, SeverityWarning,
+			fmt.Sprintf("tag %q is not lowercase words joined by hyphens", tag),
+			fmt.Sprintf("use %q", normalizeTag(tag)))
+		return
+	}
+
+	if len(l.vocabulary) > 0 && !l.vocabulary[tag] {
+		fix := "add it to the tag vocabulary or use a listed tag"
+		if suggestion := l.closestTag(tag); suggestion != "" {
+			fix = fmt.Sprintf("replace it with %q", suggestion)
+		}
+		c.add(line, RuleTagVocabulary, SeverityWarning, fmt.Sprintf("tag %q is not in the tag vocabulary", tag), fix)
+	}
+}
+
+// normalizeTag converts a tag to the tag style
+func normalizeTag(tag string) string {
+	var b strings.Builder
+	for i, r := range tag {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r - 'A' + 'a')
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// closestTag returns the vocabulary tag nearest to tag, or "" if none is
+// close: the vocabulary tag it is a prefix or extension of, or one within
+// two edits
+func (l *Linter) closestTag(tag string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range l.opts.Vocabulary {
+		distance := graph.EditDistance(tag, candidate)
+		if strings.HasPrefix(candidate, tag) || strings.HasPrefix(tag, candidate) {
+			distance = 1
+		}
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// checkDescription checks that code:description is present and of a
+// reasonable length
+func (c *checker) checkDescription() {
+	if !c.rdf.found {
+		return
+	}
+	opts := c.linter.opts
+	description := strings.TrimSpace(c.rdf.description)
+	switch {
+	case description == "":
+		c.add(c.header.markerLine, RuleDescription, SeverityError, "LinkedDoc RDF has no code:description",
+			"add a one-line code:description summarizing the module")
+	case len(description) < opts.MinDescription:
+		c.add(c.rdfLine("description"), RuleDescriptionSize, SeverityWarning,
+			fmt.Sprintf("code:description is %d characters, shorter than %d", len(description), opts.MinDescription),
+			"say what the module does, not just its name")
+	case len(description) > opts.MaxDescription:
+		c.add(c.rdfLine("description"), RuleDescriptionSize, SeverityWarning,
+			fmt.Sprintf("code:description is %d characters, longer than %d", len(description), opts.MaxDescription),
+			"keep the first sentence as the description and move detail into the header prose")
+	}
+}
+
+// splitList splits comma-separated section lines into items
+func splitList(lines []string) []string {
+	var items []string
+	for _, line := range lines {
+		for _, item := range strings.Split(line, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// difference returns the items of a not in b
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		inB[item] = true
+	}
+	var diff []string
+	for _, item := range a {
+		if !inB[item] {
+			diff = append(diff, item)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,156 @@
+package doclint
+
+import (
+	"strings"
+	"testing"
+)
+
+const conformingHeader = `/*
+# Module: pkg/auth/service.go
+Authentication service.
+
+## Linked Modules
+- [store](./store.go) - Session store
+
+## Tags
+auth, security
+
+## Exports
+Service
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+
+<#service.go> a code:Module ;
+    code:name "pkg/auth/service.go" ;
+    code:description "Authentication service" ;
+    code:linksTo <./store.go> ;
+    code:exports <#Service> ;
+    code:tags "auth", "security" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package auth
+`
+
+// rules returns the rules of issues, in order
+func rules(issues []Issue) []string {
+	var names []string
+	for _, issue := range issues {
+		names = append(names, issue.Rule)
+	}
+	return names
+}
+
+func TestLintContent_Conforming(t *testing.T) {
+	linter := NewLinter(Options{Vocabulary: []string{"auth", "security"}})
+	if issues := linter.LintContent("pkg/auth/service.go", conformingHeader, "go"); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", issues)
+	}
+	if issues := linter.LintContent("main.go", "package main\n", "go"); issues != nil {
+		t.Errorf("Files without LinkedDoc should have no issues, got %+v", issues)
+	}
+}
+
+func TestLintContent_Issues(t *testing.T) {
+	content := `/*
+# Module: pkg/auth/old.go
+Authentication service.
+
+## Linked Modules
+- [store](./store.go) - Session store
+- [cache](./cache.go) - Token cache
+
+## Tags
+authn, Security
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+
+<#service.go> a code:Module ;
+    code:name "pkg/auth/service.go" ;
+    code:description "Auth" ;
+    code:linksTo <./store.go>, <./crypto.go> ;
+    code:tags "authn", "Security", "legacy" .
+<!-- End LinkedDoc RDF -->
+*/
+`
+	linter := NewLinter(Options{
+		Vocabulary: []string{"auth", "authentication", "security"},
+		Deprecated: map[string]string{"legacy": "deprecated-api"},
+	})
+	issues := linter.LintContent("pkg/auth/service.go", content, "go")
+
+	byRule := make(map[string][]Issue)
+	for _, issue := range issues {
+		byRule[issue.Rule] = append(byRule[issue.Rule], issue)
+	}
+
+	if got := byRule[RuleModuleName]; len(got) != 1 || got[0].Line != 2 || !strings.Contains(got[0].Fix, "# Module: pkg/auth/service.go") {
+		t.Errorf("module-name issues = %+v", got)
+	}
+	if got := byRule[RuleMissingSection]; len(got) != 1 || !strings.Contains(got[0].Message, "## Exports") || got[0].Severity != SeverityError {
+		t.Errorf("missing-section issues = %+v", got)
+	}
+
+	links := byRule[RuleLinksMismatch]
+	if len(links) != 2 {
+		t.Fatalf("links-mismatch issues = %+v", links)
+	}
+	if links[0].Line != 5 || !strings.Contains(links[0].Fix, "'- [crypto](./crypto.go)'") {
+		t.Errorf("Missing link issue = %+v", links[0])
+	}
+	if links[1].Line != 7 || !strings.Contains(links[1].Fix, "<./cache.go>") {
+		t.Errorf("Extra link issue = %+v", links[1])
+	}
+
+	if got := byRule[RuleTagsMismatch]; len(got) != 1 || !strings.Contains(got[0].Message, "only in code:tags: legacy") {
+		t.Errorf("tags-mismatch issues = %+v", got)
+	}
+	if got := byRule[RuleTagVocabulary]; len(got) != 1 || got[0].Fix != `replace it with "auth"` {
+		t.Errorf("tag-vocabulary issues = %+v", got)
+	}
+	if got := byRule[RuleTagFormat]; len(got) != 1 || got[0].Fix != `use "security"` {
+		t.Errorf("tag-format issues = %+v", got)
+	}
+	if got := byRule[RuleTagDeprecated]; len(got) != 1 || got[0].Fix != `replace it with "deprecated-api"` {
+		t.Errorf("tag-deprecated issues = %+v", got)
+	}
+	if got := byRule[RuleDescriptionSize]; len(got) != 1 || got[0].Line != 17 {
+		t.Errorf("description-length issues = %+v", got)
+	}
+
+	for i := 1; i < len(issues); i++ {
+		if issues[i].Line < issues[i-1].Line {
+			t.Errorf("Issues not sorted by line: %v", rules(issues))
+			break
+		}
+	}
+}
+
+func TestLintContent_InvalidRDF(t *testing.T) {
+	content := "/*\n# Module: a.go\n<!-- LinkedDoc RDF -->\n<#a.go> a code:Module ;\n*/\n"
+	issues := NewLinter(Options{}).LintContent("a.go", content, "go")
+	if len(issues) != 1 || issues[0].Rule != RuleRDF {
+		t.Errorf("Expected one rdf-syntax issue, got %v", rules(issues))
+	}
+}
+
+func TestReport_GitHubAnnotations(t *testing.T) {
+	var report Report
+	report.Add([]Issue{
+		{Path: "a,b.go", Line: 3, Rule: RuleTagFormat, Severity: SeverityWarning, Message: "tag \"A\" is 100% wrong", Fix: "use \"a\""},
+		{Path: "c.go", Line: 1, Rule: RuleMissingSection, Severity: SeverityError, Message: "no tags"},
+	})
+	report.Add(nil)
+
+	if report.Files != 2 || report.Errors != 1 || report.Warnings != 1 || !report.HasErrors(false) {
+		t.Errorf("Report totals = %+v", report)
+	}
+
+	want := "::warning file=a%2Cb.go,line=3,title=LinkedDoc tag-format::tag \"A\" is 100%25 wrong%0ASuggested fix: use \"a\"\n" +
+		"::error file=c.go,line=1,title=LinkedDoc missing-section::no tags\n"
+	if got := report.GitHubAnnotations(); got != want {
+		t.Errorf("GitHubAnnotations() =\n%s\nwant\n%s", got, want)
+	}
+}
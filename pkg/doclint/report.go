@@ -0,0 +1,94 @@
+/*
+# Module: pkg/doclint/report.go
+Lint report and output formats.
+
+Collects the issues found across files and writes them as text, JSON or
+GitHub Actions workflow commands, which annotate the offending lines in
+pull requests.
+
+## Linked Modules
+- [doclint](./doclint.go) - LinkedDoc header style linter
+
+## Tags
+docs, lint, report, github
+
+## Exports
+Report, Report.Add, Report.HasErrors, Report.GitHubAnnotations
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#report.go> a code:Module ;
+    code:name "pkg/doclint/report.go" ;
+    code:description "Lint report and output formats" ;
+    code:language "go" ;
+    code:layer "docs" ;
+    code:linksTo <./doclint.go> ;
+    code:exports <#Report>, <#Report.Add>, <#Report.HasErrors>, <#Report.GitHubAnnotations> ;
+    code:tags "docs", "lint", "report", "github" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package doclint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report collects the lint issues of a set of files
+type Report struct {
+	Files    int     `json:"files"` // Files with a LinkedDoc header
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+	Issues   []Issue `json:"issues"`
+}
+
+// Add records the issues of one linted file
+func (r *Report) Add(issues []Issue) {
+	r.Files++
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	r.Issues = append(r.Issues, issues...)
+}
+
+// HasErrors reports whether any issue is an error, or with strict set any
+// issue at all
+func (r *Report) HasErrors(strict bool) bool {
+	return r.Errors > 0 || (strict && r.Warnings > 0)
+}
+
+// GitHubAnnotations formats the issues as GitHub Actions workflow commands,
+// one ::error or ::warning line per issue
+func (r *Report) GitHubAnnotations() string {
+	var b strings.Builder
+	for _, issue := range r.Issues {
+		message := issue.Message
+		if issue.Fix != "" {
+			message += "\nSuggested fix: " + issue.Fix
+		}
+		fmt.Fprintf(&b, "::%s file=%s,line=%d,title=%s::%s\n",
+			issue.Severity,
+			escapeProperty(issue.Path),
+			issue.Line,
+			escapeProperty("LinkedDoc "+issue.Rule),
+			escapeData(message))
+	}
+	return b.String()
+}
+
+// escapeData escapes a workflow command message
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,172 @@
+/*
+# Module: pkg/docs/ids.go
+Filename and anchor strategies for generated documentation.
+
+An IDStrategy turns a module path into the name of its documentation file
+and the anchor of its section. The default path strategy works for any
+language: it drops only the final extension from filenames and slugs every
+run of punctuation in anchors. The legacy strategy reproduces the names of
+earlier releases, which stripped ".go" wherever it appeared, so published
+links keep working. Names two modules would share are disambiguated by the
+generator.
+
+## Linked Modules
+- [markdown](./markdown.go) - Markdown documentation generator
+
+## Tags
+docs, links, anchors
+
+## Exports
+IDStrategy, PathIDs, LegacyIDs, ParseIDStrategy, IDStrategyNames
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#ids.go> a code:Module ;
+    code:name "pkg/docs/ids.go" ;
+    code:description "Filename and anchor strategies for generated documentation" ;
+    code:language "go" ;
+    code:layer "docs" ;
+    code:linksTo <./markdown.go> ;
+    code:exports <#IDStrategy>, <#PathIDs>, <#LegacyIDs>, <#ParseIDStrategy>, <#IDStrategyNames> ;
+    code:tags "docs", "links", "anchors" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package docs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// IDStrategy names the documentation file and section anchor of a module
+type IDStrategy interface {
+	// FileName returns the file name for a module, without ".md"
+	FileName(modulePath string) string
+	// Anchor returns the anchor of a module's section, without "#"
+	Anchor(modulePath string) string
+}
+
+// PathIDs is the language-agnostic strategy:
+// pkg/graph/graph.go -> pkg_graph_graph.md and #module-pkg-graph-graph-go
+type PathIDs struct{}
+
+// FileName joins the path's directories with "_" and drops its extension
+func (PathIDs) FileName(modulePath string) string {
+	modulePath = strings.TrimSuffix(modulePath, path.Ext(modulePath))
+	return strings.ReplaceAll(modulePath, "/", "_")
+}
+
+// Anchor lowercases the path and replaces each run of other characters than
+// letters and digits with "-"
+func (PathIDs) Anchor(modulePath string) string {
+	return "module-" + slug(modulePath)
+}
+
+// LegacyIDs reproduces the file names and anchors of earlier releases
+type LegacyIDs struct{}
+
+// FileName joins the path's directories with "_" and removes every ".go"
+func (LegacyIDs) FileName(modulePath string) string {
+	fileName := strings.ReplaceAll(modulePath, "/", "_")
+	return strings.ReplaceAll(fileName, ".go", "")
+}
+
+// Anchor lowercases the path and replaces "/", "." and "_" with "-"
+func (LegacyIDs) Anchor(modulePath string) string {
+	anchor := strings.ToLower(modulePath)
+	anchor = strings.ReplaceAll(anchor, "/", "-")
+	anchor = strings.ReplaceAll(anchor, ".", "-")
+	anchor = strings.ReplaceAll(anchor, "_", "-")
+	return "module-" + anchor
+}
+
+// IDStrategyNames lists the strategies ParseIDStrategy accepts
+var IDStrategyNames = []string{"path", "legacy"}
+
+// ParseIDStrategy returns the strategy with the given name; "" is "path"
+func ParseIDStrategy(name string) (IDStrategy, error) {
+	switch strings.ToLower(name) {
+	case "", "path":
+		return PathIDs{}, nil
+	case "legacy":
+		return LegacyIDs{}, nil
+	}
+	return nil, fmt.Errorf("unknown id strategy %q (must be %s)", name, strings.Join(IDStrategyNames, " or "))
+}
+
+// slug lowercases s and replaces each run of other characters than letters
+// and digits with "-"
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// moduleIDs assigns file names and anchors to the documented modules,
+// disambiguating names the strategy gives to several modules
+type moduleIDs struct {
+	strategy  IDStrategy
+	fileNames map[string]string
+	anchors   map[string]string
+}
+
+func newModuleIDs(strategy IDStrategy, modulePaths []string) *moduleIDs {
+	return &moduleIDs{
+		strategy:  strategy,
+		fileNames: assignUnique(modulePaths, strategy.FileName),
+		anchors:   assignUnique(modulePaths, strategy.Anchor),
+	}
+}
+
+// fileName returns a module's file name, including ".md"
+func (ids *moduleIDs) fileName(modulePath string) string {
+	if name, ok := ids.fileNames[modulePath]; ok {
+		return name + ".md"
+	}
+	return ids.strategy.FileName(modulePath) + ".md"
+}
+
+// anchor returns a module's anchor
+func (ids *moduleIDs) anchor(modulePath string) string {
+	if anchor, ok := ids.anchors[modulePath]; ok {
+		return anchor
+	}
+	return ids.strategy.Anchor(modulePath)
+}
+
+// assignUnique names each path, in order. A name already taken gets the
+// path's extension appended, then a number.
+func assignUnique(modulePaths []string, name func(string) string) map[string]string {
+	names := make(map[string]string, len(modulePaths))
+	taken := make(map[string]bool, len(modulePaths))
+	for _, modulePath := range modulePaths {
+		candidate := name(modulePath)
+		if taken[candidate] {
+			if ext := strings.TrimPrefix(path.Ext(modulePath), "."); ext != "" {
+				candidate += "-" + ext
+			}
+			for i, base := 2, candidate; taken[candidate]; i++ {
+				candidate = fmt.Sprintf("%s-%d", base, i)
+			}
+		}
+		taken[candidate] = true
+		names[modulePath] = candidate
+	}
+	return names
+}
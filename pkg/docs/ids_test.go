@@ -0,0 +1,98 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestIDStrategies(t *testing.T) {
+	tests := []struct {
+		path                     string
+		pathFile, pathAnchor     string
+		legacyFile, legacyAnchor string
+	}{
+		{"pkg/graph/graph.go", "pkg_graph_graph", "module-pkg-graph-graph-go", "pkg_graph_graph", "module-pkg-graph-graph-go"},
+		{"app/v1.0/handlers.py", "app_v1.0_handlers", "module-app-v1-0-handlers-py", "app_v1.0_handlers.py", "module-app-v1-0-handlers-py"},
+		{"src/cargo.gopher/Index.ts", "src_cargo.gopher_Index", "module-src-cargo-gopher-index-ts", "src_cargopher_Index.ts", "module-src-cargo-gopher-index-ts"},
+		{"web/@scope/my_lib.d.ts", "web_@scope_my_lib.d", "module-web-scope-my-lib-d-ts", "web_@scope_my_lib.d.ts", "module-web-@scope-my-lib-d-ts"},
+	}
+
+	for _, tt := range tests {
+		if got := (PathIDs{}).FileName(tt.path); got != tt.pathFile {
+			t.Errorf("PathIDs.FileName(%q) = %q, want %q", tt.path, got, tt.pathFile)
+		}
+		if got := (PathIDs{}).Anchor(tt.path); got != tt.pathAnchor {
+			t.Errorf("PathIDs.Anchor(%q) = %q, want %q", tt.path, got, tt.pathAnchor)
+		}
+		if got := (LegacyIDs{}).FileName(tt.path); got != tt.legacyFile {
+			t.Errorf("LegacyIDs.FileName(%q) = %q, want %q", tt.path, got, tt.legacyFile)
+		}
+		if got := (LegacyIDs{}).Anchor(tt.path); got != tt.legacyAnchor {
+			t.Errorf("LegacyIDs.Anchor(%q) = %q, want %q", tt.path, got, tt.legacyAnchor)
+		}
+	}
+}
+
+func TestParseIDStrategy(t *testing.T) {
+	if s, err := ParseIDStrategy(""); err != nil || s != (PathIDs{}) {
+		t.Errorf("ParseIDStrategy(\"\") = %v, %v; want PathIDs", s, err)
+	}
+	if s, err := ParseIDStrategy("Legacy"); err != nil || s != (LegacyIDs{}) {
+		t.Errorf("ParseIDStrategy(\"Legacy\") = %v, %v; want LegacyIDs", s, err)
+	}
+	if _, err := ParseIDStrategy("uuid"); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
+
+func TestGenerateDocs_DisambiguatesFileNames(t *testing.T) {
+	g := graph.NewGraph("polyglot", store.NewTripleStore())
+	for _, path := range []string{"lib/util.go", "lib/util.py", "main.go"} {
+		g.AddModule(&graph.Module{Path: path, Name: filepath.Base(path)})
+	}
+	g.Modules["main.go"].Dependencies = []string{"lib/util.go", "lib/util.py"}
+
+	outputDir := t.TempDir()
+	if err := GenerateDocs(g, DocsOptions{OutputDir: outputDir, Format: DocsMultiFile}); err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+
+	for _, name := range []string{"lib_util.md", "lib_util-py.md", "main.md"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("Expected %s: %v", name, err)
+		}
+	}
+	mainDoc, err := os.ReadFile(filepath.Join(outputDir, "main.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mainDoc), "(lib_util-py.md)") {
+		t.Errorf("Expected link to the disambiguated file:\n%s", mainDoc)
+	}
+}
+
+func TestGenerateDocs_SectionAnchors(t *testing.T) {
+	g := createTestGraph()
+	outputDir := t.TempDir()
+	if err := GenerateDocs(g, DocsOptions{OutputDir: outputDir, Format: DocsSingleFile, IDs: LegacyIDs{}}); err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"- [api/handlers.go](#module-api-handlers-go)",
+		"<a id=\"module-api-handlers-go\"></a>",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected %q in generated docs", want)
+		}
+	}
+}
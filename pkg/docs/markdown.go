@@ -8,6 +8,8 @@ exports, usage examples, and relationships.
 ## Linked Modules
 - [../graph](../graph/graph.go) - Graph data structure
 - [../analysis](../analysis/impact.go) - Impact analysis
+- [../analysis](../analysis/components.go) - Components
+- [../filter](../filter/filter.go) - Filter expressions
 
 ## Tags
 documentation, markdown, generator
@@ -24,7 +26,7 @@ GenerateDocs, GenerateModuleDocs, DocsOptions, DocsFormat
     code:description "Markdown documentation generator" ;
     code:language "go" ;
     code:layer "documentation" ;
-    code:linksTo <../graph/graph.go>, <../analysis/impact.go> ;
+    code:linksTo <../graph/graph.go>, <../analysis/impact.go>, <../analysis/components.go>, <../filter/filter.go> ;
     code:exports <#GenerateDocs>, <#GenerateModuleDocs>, <#DocsOptions> ;
     code:tags "documentation", "markdown", "generator" .
 <!-- End LinkedDoc RDF -->
@@ -40,6 +42,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/filter"
 	"github.com/justin4957/graphfs/pkg/graph"
 )
 
@@ -54,16 +58,23 @@ const (
 
 // DocsOptions configures documentation generation
 type DocsOptions struct {
-	OutputDir     string            // Output directory
-	Format        DocsFormat        // Output format
-	Template      string            // Custom template path
-	IncludeLayers []string          // Include only these layers
-	IncludeTags   []string          // Include only modules with these tags
-	Depth         int               // Max dependency depth (0 = unlimited)
-	IncludeGraph  bool              // Include dependency graphs
-	FrontMatter   map[string]string // Frontmatter for static site generators
-	Title         string            // Documentation title
-	ProjectName   string            // Project name
+	OutputDir     string             // Output directory
+	Format        DocsFormat         // Output format
+	Template      string             // Custom template path
+	IncludeLayers []string           // Include only these layers
+	IncludeTags   []string           // Include only modules with these tags
+	Where         *filter.Expression // Include only modules matching this filter
+	Depth         int                // Max dependency depth (0 = unlimited)
+	IncludeGraph  bool               // Include dependency graphs
+	FrontMatter   map[string]string  // Frontmatter for static site generators
+	Title         string             // Documentation title
+	ProjectName   string             // Project name
+
+	// Declared components, documented in the overview (optional)
+	Components *analysis.ComponentAnalysis
+
+	// IDs names module files and anchors (nil = PathIDs)
+	IDs IDStrategy
 }
 
 // ModuleDoc represents documentation for a single module
@@ -79,6 +90,7 @@ type DocsGenerator struct {
 	graph   *graph.Graph
 	options DocsOptions
 	modules []*ModuleDoc
+	ids     *moduleIDs
 }
 
 // NewDocsGenerator creates a new documentation generator
@@ -171,11 +183,23 @@ func (dg *DocsGenerator) prepareModuleDocs() error {
 		return dg.modules[i].Module.Path < dg.modules[j].Module.Path
 	})
 
+	// Name files and anchors once, so names the strategy repeats are
+	// disambiguated the same way in every link
+	paths := make([]string, len(dg.modules))
+	for i, moduleDoc := range dg.modules {
+		paths[i] = moduleDoc.Module.Path
+	}
+	dg.ids = newModuleIDs(dg.idStrategy(), paths)
+
 	return nil
 }
 
 // shouldIncludeModule checks if a module should be included
 func (dg *DocsGenerator) shouldIncludeModule(module *graph.Module) bool {
+	if !dg.options.Where.Match(module) {
+		return false
+	}
+
 	// Filter by layer
 	if len(dg.options.IncludeLayers) > 0 {
 		found := false
@@ -396,11 +420,19 @@ func (dg *DocsGenerator) generateDirectoryFile(dir string, modules []*ModuleDoc)
 func (dg *DocsGenerator) writeModuleSection(w *strings.Builder, moduleDoc *ModuleDoc, level int) {
 	module := moduleDoc.Module
 
-	// Module title
+	// Module title, with an explicit anchor where sections share a file
+	if level > 1 {
+		w.WriteString(fmt.Sprintf("<a id=\"%s\"></a>\n\n", dg.getModuleAnchor(module)))
+	}
 	dg.writeHeader(w, fmt.Sprintf("Module: %s", module.Path), level)
 	w.WriteString("\n")
 
 	// Metadata
+	if dg.options.Components != nil {
+		if component := dg.options.Components.ComponentOf(module.Path); component != "" {
+			w.WriteString(fmt.Sprintf("**Component:** %s  \n", component))
+		}
+	}
 	if module.Layer != "" {
 		w.WriteString(fmt.Sprintf("**Layer:** %s  \n", module.Layer))
 	}
@@ -458,6 +490,20 @@ func (dg *DocsGenerator) writeModuleSection(w *strings.Builder, moduleDoc *Modul
 		}
 		w.WriteString("\n")
 	}
+
+	// Outstanding debt
+	if len(module.Markers) > 0 {
+		dg.writeHeader(w, "Outstanding Debt", level+1)
+		w.WriteString("\n")
+		for _, marker := range module.Markers {
+			w.WriteString(fmt.Sprintf("- **%s** (line %d)", marker.Kind, marker.Line))
+			if marker.Text != "" {
+				w.WriteString(fmt.Sprintf(": %s", marker.Text))
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString("\n")
+	}
 }
 
 // writeFrontMatter writes frontmatter for static site generators
@@ -507,6 +553,104 @@ func (dg *DocsGenerator) writeOverview(w *strings.Builder) {
 		w.WriteString(fmt.Sprintf("  - %s: %d modules\n", layer, count))
 	}
 	w.WriteString("\n")
+
+	dg.writeLanguages(w)
+	dg.writeComponents(w)
+	dg.writeDebt(w)
+}
+
+// writeLanguages writes the project's language breakdown, with boundary
+// modules for polyglot projects
+func (dg *DocsGenerator) writeLanguages(w *strings.Builder) {
+	breakdown := dg.graph.AnalyzeLanguages()
+	if len(breakdown.Languages) == 0 {
+		return
+	}
+
+	dg.writeHeader(w, "Languages", 3)
+	w.WriteString("\n")
+	for _, s := range breakdown.Languages {
+		w.WriteString(fmt.Sprintf("- **%s:** %d modules (%.1f%%)\n", s.Language, s.Modules, s.Percent))
+	}
+	w.WriteString("\n")
+
+	if !breakdown.IsPolyglot() || len(breakdown.Boundaries) == 0 {
+		return
+	}
+	w.WriteString(fmt.Sprintf("%d cross-language dependencies. Boundary modules:\n\n", len(breakdown.Edges)))
+	for _, boundary := range breakdown.Boundaries {
+		w.WriteString(fmt.Sprintf("- `%s` (%s) calls %s\n", boundary.Path, boundary.Language, strings.Join(boundary.Calls, ", ")))
+	}
+	w.WriteString("\n")
+}
+
+// writeComponents writes the declared components with their public API and
+// the components they depend on
+func (dg *DocsGenerator) writeComponents(w *strings.Builder) {
+	components := dg.options.Components
+	if components == nil || len(components.Components) == 0 {
+		return
+	}
+
+	dg.writeHeader(w, "Components", 3)
+	w.WriteString("\n")
+	for _, component := range components.Components {
+		w.WriteString(fmt.Sprintf("- **%s** (%d modules)", component.Name, len(component.Modules)))
+		if component.Description != "" {
+			w.WriteString(fmt.Sprintf(" - %s", component.Description))
+		}
+		w.WriteString("\n")
+		if len(component.API) > 0 {
+			w.WriteString(fmt.Sprintf("  - Public API: `%s`\n", strings.Join(component.API, "`, `")))
+		}
+		if len(component.DependsOn) > 0 {
+			w.WriteString(fmt.Sprintf("  - Depends on: %s\n", strings.Join(component.DependsOn, ", ")))
+		}
+		if len(component.Dependents) > 0 {
+			w.WriteString(fmt.Sprintf("  - Used by: %s\n", strings.Join(component.Dependents, ", ")))
+		}
+	}
+	w.WriteString("\n")
+
+	if violations := components.Violations(); len(violations) > 0 {
+		w.WriteString(fmt.Sprintf("%d dependencies cross component boundaries:\n\n", len(violations)))
+		for _, v := range violations {
+			w.WriteString(fmt.Sprintf("- `%s` -> `%s` (%s -> %s, %s)\n", v.Source, v.Target, v.From, v.To, v.Kind))
+		}
+		w.WriteString("\n")
+	}
+}
+
+// writeDebt writes outstanding TODO, FIXME and DEPRECATED markers per owner
+// and the modules with the most markers
+func (dg *DocsGenerator) writeDebt(w *strings.Builder) {
+	debt := analysis.SummarizeDebt(dg.graph)
+	if debt.Counts.Total() == 0 {
+		return
+	}
+
+	dg.writeHeader(w, "Outstanding Debt", 3)
+	w.WriteString("\n")
+	w.WriteString(fmt.Sprintf("%d TODO, %d FIXME and %d DEPRECATED markers in %d modules.\n\n",
+		debt.Counts.TODO, debt.Counts.FIXME, debt.Counts.Deprecated, len(debt.Modules)))
+
+	w.WriteString("| Owner | Modules | TODO | FIXME | DEPRECATED |\n")
+	w.WriteString("|-------|---------|------|-------|------------|\n")
+	for _, owner := range debt.Owners {
+		w.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %d |\n",
+			owner.Owner, owner.Modules, owner.Counts.TODO, owner.Counts.FIXME, owner.Counts.Deprecated))
+	}
+	w.WriteString("\n")
+
+	modules := debt.Modules
+	if len(modules) > 10 {
+		modules = modules[:10]
+	}
+	w.WriteString("Modules with the most markers:\n\n")
+	for _, module := range modules {
+		w.WriteString(fmt.Sprintf("- `%s`: %d\n", module.Path, module.Counts.Total()))
+	}
+	w.WriteString("\n")
 }
 
 // writeTableOfContents writes a table of contents
@@ -527,12 +671,26 @@ func (dg *DocsGenerator) writeFooter(w *strings.Builder) {
 		time.Now().Format("2006-01-02 15:04:05")))
 }
 
+// idStrategy returns the configured ID strategy
+func (dg *DocsGenerator) idStrategy() IDStrategy {
+	if dg.options.IDs == nil {
+		return PathIDs{}
+	}
+	return dg.options.IDs
+}
+
+// moduleIDs returns the assigned module IDs, or the strategy's names when
+// modules have not been prepared
+func (dg *DocsGenerator) moduleIDs() *moduleIDs {
+	if dg.ids == nil {
+		return newModuleIDs(dg.idStrategy(), nil)
+	}
+	return dg.ids
+}
+
 // getModuleFileName returns the filename for a module
 func (dg *DocsGenerator) getModuleFileName(module *graph.Module) string {
-	// Convert path to filename: pkg/graph/graph.go -> pkg_graph_graph.md
-	fileName := strings.ReplaceAll(module.Path, "/", "_")
-	fileName = strings.ReplaceAll(fileName, ".go", "")
-	return fileName + ".md"
+	return dg.moduleIDs().fileName(module.Path)
 }
 
 // getModuleLinkPath returns the link path for a module
@@ -557,12 +715,7 @@ func (dg *DocsGenerator) getModuleLinkPath(module *graph.Module) string {
 
 // getModuleAnchor returns the anchor for a module
 func (dg *DocsGenerator) getModuleAnchor(module *graph.Module) string {
-	// Convert to markdown anchor format
-	anchor := strings.ToLower(module.Path)
-	anchor = strings.ReplaceAll(anchor, "/", "-")
-	anchor = strings.ReplaceAll(anchor, ".", "-")
-	anchor = strings.ReplaceAll(anchor, "_", "-")
-	return "module-" + anchor
+	return dg.moduleIDs().anchor(module.Path)
 }
 
 // GenerateModuleDocs generates documentation for a single module
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/analysis"
 	"github.com/justin4957/graphfs/pkg/graph"
 )
 
@@ -143,6 +144,72 @@ func TestGenerateDocs_SingleFile(t *testing.T) {
 	}
 }
 
+func TestGenerateDocs_Components(t *testing.T) {
+	g := createTestGraph()
+	tmpDir := t.TempDir()
+
+	components := analysis.AnalyzeComponents(g, &analysis.ComponentConfig{Components: []analysis.ComponentSpec{
+		{Name: "web", Paths: []string{"api"}},
+		{Name: "accounts", Description: "User accounts", Paths: []string{"services", "data", "utils"}, API: []string{"services/auth.go"}},
+	}})
+
+	err := GenerateDocs(g, DocsOptions{OutputDir: tmpDir, Format: DocsSingleFile, Components: components})
+	if err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read README.md: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		"### Components",
+		"- **accounts** (4 modules) - User accounts",
+		"  - Public API: `services/auth.go`",
+		"  - Used by: web",
+		"- `api/handlers.go` -> `services/users.go` (web -> accounts, non-api)",
+		"**Component:** accounts",
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("Missing %q", want)
+		}
+	}
+}
+
+func TestGenerateDocs_Debt(t *testing.T) {
+	g := createTestGraph()
+	tmpDir := t.TempDir()
+
+	g.Modules["api/handlers.go"].Markers = []graph.Marker{
+		{Kind: graph.MarkerTODO, Line: 12, Text: "paginate results"},
+		{Kind: graph.MarkerFIXME, Line: 30},
+	}
+
+	if err := GenerateDocs(g, DocsOptions{OutputDir: tmpDir, Format: DocsSingleFile}); err != nil {
+		t.Fatalf("GenerateDocs failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read README.md: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		"### Outstanding Debt",
+		"1 TODO, 1 FIXME and 0 DEPRECATED markers in 1 modules.",
+		"| (unowned) | 1 | 1 | 1 | 0 |",
+		"- **TODO** (line 12): paginate results",
+		"- **FIXME** (line 30)\n",
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("Missing %q", want)
+		}
+	}
+}
+
 func TestGenerateDocs_MultiFile(t *testing.T) {
 	g := createTestGraph()
 	tmpDir := t.TempDir()
@@ -0,0 +1,38 @@
+//go:build !windows
+
+/*
+# Module: pkg/doctor/diskspace_unix.go
+Free disk space on Unix systems.
+
+## Linked Modules
+- [doctor](./doctor.go) - Health checks
+
+## Tags
+diagnostics, disk, unix
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#diskspace_unix.go> a code:Module ;
+    code:name "pkg/doctor/diskspace_unix.go" ;
+    code:description "Free disk space on Unix systems" ;
+    code:language "go" ;
+    code:layer "diagnostics" ;
+    code:linksTo <./doctor.go> ;
+    code:tags "diagnostics", "disk", "unix" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package doctor
+
+import "syscall"
+
+// availableBytes returns the disk space available to this user at path
+func availableBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
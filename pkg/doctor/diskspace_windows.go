@@ -0,0 +1,42 @@
+//go:build windows
+
+/*
+# Module: pkg/doctor/diskspace_windows.go
+Free disk space on Windows.
+
+## Linked Modules
+- [doctor](./doctor.go) - Health checks
+
+## Tags
+diagnostics, disk, windows
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#diskspace_windows.go> a code:Module ;
+    code:name "pkg/doctor/diskspace_windows.go" ;
+    code:description "Free disk space on Windows" ;
+    code:language "go" ;
+    code:layer "diagnostics" ;
+    code:linksTo <./doctor.go> ;
+    code:tags "diagnostics", "disk", "windows" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package doctor
+
+import "golang.org/x/sys/windows"
+
+// availableBytes returns the disk space available to this user at path
+func availableBytes(path string) (uint64, error) {
+	dir, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var available uint64
+	if err := windows.GetDiskFreeSpaceEx(dir, &available, nil, nil); err != nil {
+		return 0, err
+	}
+	return available, nil
+}
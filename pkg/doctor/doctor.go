@@ -9,6 +9,8 @@ validation, and performance benchmarking.
 - [../cache](../cache/cache.go) - Cache management
 - [../parser](../parser/parser.go) - Parser
 - [../graph](../graph/graph.go) - Graph builder
+- [diskspace_unix](./diskspace_unix.go) - Free disk space on Unix
+- [diskspace_windows](./diskspace_windows.go) - Free disk space on Windows
 
 ## Tags
 diagnostics, health-check, troubleshooting
@@ -25,7 +27,8 @@ CheckStatus, HealthCheck, RunAllChecks
     code:description "Health check system for GraphFS diagnostics" ;
     code:language "go" ;
     code:layer "diagnostics" ;
-    code:linksTo <../cache/cache.go>, <../parser/parser.go>, <../graph/graph.go> ;
+    code:linksTo <../cache/cache.go>, <../parser/parser.go>, <../graph/graph.go>,
+                 <./diskspace_unix.go>, <./diskspace_windows.go> ;
     code:exports <#CheckStatus>, <#HealthCheck>, <#RunAllChecks> ;
     code:tags "diagnostics", "health-check", "troubleshooting" .
 <!-- End LinkedDoc RDF -->
@@ -40,7 +43,6 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/justin4957/graphfs/pkg/cache"
@@ -174,7 +176,7 @@ func CheckCacheIntegrity(rootPath string) HealthCheck {
 	}
 
 	// Try to open cache
-	cacheManager, err := cache.NewManager(cacheDir)
+	cacheManager, err := cache.NewManager(rootPath)
 	if err != nil {
 		return HealthCheck{
 			Name:    "Cache integrity",
@@ -223,8 +225,7 @@ func CheckConfigFile(rootPath string) HealthCheck {
 
 // CheckDiskSpace checks available disk space
 func CheckDiskSpace(rootPath string) HealthCheck {
-	var stat syscall.Statfs_t
-	err := syscall.Statfs(rootPath, &stat)
+	available, err := availableBytes(rootPath)
 	if err != nil {
 		return HealthCheck{
 			Name:    "Disk space",
@@ -234,7 +235,7 @@ func CheckDiskSpace(rootPath string) HealthCheck {
 	}
 
 	// Calculate available space in GB
-	availableGB := float64(stat.Bavail*uint64(stat.Bsize)) / (1024 * 1024 * 1024)
+	availableGB := float64(available) / (1024 * 1024 * 1024)
 
 	if availableGB < 1.0 {
 		return HealthCheck{
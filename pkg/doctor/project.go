@@ -0,0 +1,385 @@
+/*
+# Module: pkg/doctor/project.go
+Project health checks for GraphFS diagnostics.
+
+Checks the state of a project rather than the installation: the .graphfs
+directory and its configuration, whether the shadow index matches the
+shadow entries, cache entries for deleted files, LinkedDoc headers that
+don't parse, code:linksTo targets that point to missing files, and git
+hooks running graphfs. Score summarizes a set of checks as 0-100.
+
+## Linked Modules
+- [doctor](./doctor.go) - Health check system
+- [../shadow](../shadow/shadow.go) - Shadow file system
+- [../cache](../cache/scan.go) - Cached modules
+- [../scanner](../scanner/scanner.go) - File scanning
+- [../graph](../graph/builder.go) - Graph builder
+
+## Tags
+diagnostics, health-check, project
+
+## Exports
+CheckGraphFSDirectory, CheckShadowIndex, CheckOrphanedCache, CheckLinkedDocHeaders, CheckLinksTo, CheckGitHooks, RunProjectChecks, Score
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#project.go> a code:Module ;
+    code:name "pkg/doctor/project.go" ;
+    code:description "Project health checks for GraphFS diagnostics" ;
+    code:language "go" ;
+    code:layer "diagnostics" ;
+    code:linksTo <./doctor.go>, <../shadow/shadow.go>, <../cache/scan.go>, <../scanner/scanner.go>, <../graph/builder.go> ;
+    code:exports <#CheckGraphFSDirectory>, <#CheckShadowIndex>, <#CheckOrphanedCache>, <#CheckLinkedDocHeaders>, <#CheckLinksTo>, <#CheckGitHooks>, <#RunProjectChecks>, <#Score> ;
+    code:tags "diagnostics", "health-check", "project" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package doctor
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/cache"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"gopkg.in/yaml.v3"
+)
+
+// maxListed is how many offending paths a check message names
+const maxListed = 3
+
+// CheckGraphFSDirectory checks that .graphfs is a directory and that its
+// configuration file, if any, is valid YAML
+func CheckGraphFSDirectory(rootPath string) HealthCheck {
+	dir := filepath.Join(rootPath, ".graphfs")
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return HealthCheck{
+			Name:    ".graphfs directory",
+			Status:  StatusWarning,
+			Message: "No .graphfs directory (not initialized)",
+			Fix:     "Initialize the project: graphfs init",
+		}
+	}
+	if err != nil || !info.IsDir() {
+		return HealthCheck{
+			Name:    ".graphfs directory",
+			Status:  StatusError,
+			Message: ".graphfs exists but is not a directory",
+			Fix:     "Move the .graphfs file away, then run: graphfs init",
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.yaml"))
+	if err == nil {
+		var config map[string]interface{}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return HealthCheck{
+				Name:    ".graphfs directory",
+				Status:  StatusError,
+				Message: fmt.Sprintf("config.yaml is not valid YAML: %v", err),
+				Fix:     "Fix the syntax of .graphfs/config.yaml",
+			}
+		}
+	}
+
+	return HealthCheck{
+		Name:    ".graphfs directory",
+		Status:  StatusOK,
+		Message: "Initialized",
+	}
+}
+
+// CheckShadowIndex checks that the shadow index lists every shadow entry as
+// last written, and nothing else
+func CheckShadowIndex(rootPath string) HealthCheck {
+	config := shadow.DefaultConfig()
+	config.Audit = false
+	shadowFS, err := shadow.NewShadowFS(rootPath, config)
+	if err != nil {
+		return HealthCheck{
+			Name:    "Shadow index",
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Could not open shadow file system: %v", err),
+		}
+	}
+	if _, err := os.Stat(shadowFS.ShadowPath()); os.IsNotExist(err) {
+		return HealthCheck{
+			Name:    "Shadow index",
+			Status:  StatusOK,
+			Message: "No shadow entries",
+		}
+	}
+
+	if err := shadowFS.LoadIndex(); err != nil {
+		return HealthCheck{
+			Name:    "Shadow index",
+			Status:  StatusError,
+			Message: fmt.Sprintf("Index missing or unreadable: %v", err),
+			Fix:     "Rebuild the index: graphfs shadow rebuild-index",
+		}
+	}
+	entries, err := shadowFS.List()
+	if err != nil {
+		return HealthCheck{
+			Name:    "Shadow index",
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Could not list shadow entries: %v", err),
+		}
+	}
+
+	index := shadowFS.Index()
+	stale, indexed := 0, 0
+	for _, entry := range entries {
+		indexEntry, ok := index.Get(entry.SourcePath)
+		if ok {
+			indexed++
+		}
+		if !ok || !indexEntry.UpdatedAt.Equal(entry.UpdatedAt) {
+			stale++
+		}
+	}
+	// Index entries whose shadow file was removed
+	stale += max(index.Count()-indexed, 0)
+	if stale > 0 {
+		return HealthCheck{
+			Name:    "Shadow index",
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d of %d entries out of date in the index", stale, len(entries)),
+			Fix:     "Rebuild the index: graphfs shadow rebuild-index",
+		}
+	}
+
+	return HealthCheck{
+		Name:    "Shadow index",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d entries indexed", len(entries)),
+	}
+}
+
+// CheckOrphanedCache checks for cached modules whose file no longer exists
+func CheckOrphanedCache(rootPath string) HealthCheck {
+	if _, err := os.Stat(filepath.Join(rootPath, ".graphfs", "cache")); os.IsNotExist(err) {
+		return HealthCheck{
+			Name:    "Orphaned cache entries",
+			Status:  StatusOK,
+			Message: "No cache",
+		}
+	}
+
+	cacheManager, err := cache.NewManager(rootPath)
+	if err != nil {
+		return HealthCheck{
+			Name:    "Orphaned cache entries",
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Could not open cache: %v", err),
+		}
+	}
+	defer cacheManager.Close()
+
+	var orphaned []string
+	for path := range cacheManager.ScanPathPrefix("") {
+		if _, err := os.Stat(filepath.FromSlash(path)); os.IsNotExist(err) {
+			if rel, err := filepath.Rel(rootPath, filepath.FromSlash(path)); err == nil {
+				path = filepath.ToSlash(rel)
+			}
+			orphaned = append(orphaned, path)
+		}
+	}
+	if len(orphaned) > 0 {
+		return HealthCheck{
+			Name:    "Orphaned cache entries",
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d cached modules of deleted files: %s", len(orphaned), listPaths(orphaned)),
+			Fix:     "Clear the cache: graphfs cache clear",
+		}
+	}
+
+	return HealthCheck{
+		Name:    "Orphaned cache entries",
+		Status:  StatusOK,
+		Message: "Every cached module has a file",
+	}
+}
+
+// CheckLinkedDocHeaders checks that the LinkedDoc headers of scanned files
+// parse. Every source file is parsed, since the scanner doesn't flag
+// headers it can't extract, such as one missing its end marker.
+func CheckLinkedDocHeaders(rootPath string, files []*scanner.FileInfo) HealthCheck {
+	p := parser.NewParser()
+	headers := 0
+	var malformed []string
+	for _, file := range files {
+		if file.Language == "unknown" || file.Binary {
+			continue
+		}
+		triples, err := p.ParseFor(file.Path, scanner.DetectLanguageKey(file.Path))
+		if err == nil {
+			if len(triples) > 0 {
+				headers++
+			}
+			continue
+		}
+		headers++
+		path := file.Path
+		if rel, err := filepath.Rel(rootPath, file.Path); err == nil {
+			path = filepath.ToSlash(rel)
+		}
+		malformed = append(malformed, path)
+	}
+
+	if len(malformed) > 0 {
+		return HealthCheck{
+			Name:    "LinkedDoc headers",
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d of %d headers do not parse: %s", len(malformed), headers, listPaths(malformed)),
+			Fix:     "Show the parse errors: graphfs lint-docs",
+		}
+	}
+
+	return HealthCheck{
+		Name:    "LinkedDoc headers",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d headers parse", headers),
+	}
+}
+
+// CheckLinksTo checks that every code:linksTo target is a module of the
+// graph or an existing file or directory
+func CheckLinksTo(g *graph.Graph) HealthCheck {
+	links := 0
+	var unresolved []string
+	for _, module := range g.Modules {
+		for _, dep := range module.Dependencies {
+			if strings.Contains(dep, "://") {
+				continue // External IRI
+			}
+			links++
+			if g.GetModule(dep) != nil {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(g.Root, filepath.FromSlash(dep))); err == nil {
+				continue // File without a LinkedDoc header, or a package directory
+			}
+			unresolved = append(unresolved, module.Path+" -> "+dep)
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return HealthCheck{
+			Name:    "linksTo targets",
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("%d of %d links point to missing files: %s", len(unresolved), links, listPaths(unresolved)),
+			Fix:     "Fix or remove the targets in code:linksTo; graphfs lsp marks broken links in the editor",
+		}
+	}
+
+	return HealthCheck{
+		Name:    "linksTo targets",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d links resolve", links),
+	}
+}
+
+// CheckGitHooks checks that a pre-commit or pre-push hook runs graphfs
+func CheckGitHooks(rootPath string) HealthCheck {
+	out, err := exec.Command("git", "-C", rootPath, "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return HealthCheck{
+			Name:    "Git hooks",
+			Status:  StatusOK,
+			Message: "Not a git repository",
+		}
+	}
+	hooksDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(rootPath, hooksDir)
+	}
+
+	for _, hook := range []string{"pre-commit", "pre-push"} {
+		data, err := os.ReadFile(filepath.Join(hooksDir, hook))
+		if err == nil && strings.Contains(string(data), "graphfs") {
+			return HealthCheck{
+				Name:    "Git hooks",
+				Status:  StatusOK,
+				Message: fmt.Sprintf("%s hook runs graphfs", hook),
+			}
+		}
+	}
+
+	return HealthCheck{
+		Name:    "Git hooks",
+		Status:  StatusWarning,
+		Message: "No pre-commit or pre-push hook runs graphfs",
+		Fix:     fmt.Sprintf("Lint headers before each commit: printf '#!/bin/sh\\ngraphfs lint-docs\\n' > %s && chmod +x %[1]s", filepath.Join(hooksDir, "pre-commit")),
+	}
+}
+
+// RunProjectChecks runs the project health checks, scanning and building
+// the graph with opts
+func RunProjectChecks(rootPath string, opts graph.BuildOptions) []HealthCheck {
+	checks := []HealthCheck{
+		CheckGraphFSDirectory(rootPath),
+		CheckShadowIndex(rootPath),
+		CheckOrphanedCache(rootPath),
+	}
+
+	if result, err := scanner.NewScanner().Scan(rootPath, opts.ScanOptions); err != nil {
+		checks = append(checks, HealthCheck{
+			Name:    "LinkedDoc headers",
+			Status:  StatusError,
+			Message: fmt.Sprintf("Scan failed: %v", err),
+		})
+	} else {
+		checks = append(checks, CheckLinkedDocHeaders(rootPath, result.Files))
+	}
+
+	if g, err := graph.NewBuilder().Build(rootPath, opts); err != nil {
+		checks = append(checks, HealthCheck{
+			Name:    "linksTo targets",
+			Status:  StatusError,
+			Message: fmt.Sprintf("Could not build the graph: %v", err),
+		})
+	} else {
+		checks = append(checks, CheckLinksTo(g))
+	}
+
+	return append(checks, CheckGitHooks(rootPath))
+}
+
+// Score rates checks from 0 to 100: passed checks count fully, warnings
+// half and errors not at all
+func Score(checks []HealthCheck) int {
+	if len(checks) == 0 {
+		return 100
+	}
+	points := 0.0
+	for _, check := range checks {
+		switch check.Status {
+		case StatusOK:
+			points++
+		case StatusWarning:
+			points += 0.5
+		}
+	}
+	return int(math.Round(100 * points / float64(len(checks))))
+}
+
+// listPaths names the first few paths, sorted
+func listPaths(paths []string) string {
+	sort.Strings(paths)
+	if len(paths) <= maxListed {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:maxListed], ", "), len(paths)-maxListed)
+}
@@ -0,0 +1,192 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/cache"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckGraphFSDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if check := CheckGraphFSDirectory(tmpDir); check.Status != StatusWarning || !strings.Contains(check.Fix, "graphfs init") {
+		t.Errorf("Without .graphfs: %+v", check)
+	}
+
+	writeFile(t, filepath.Join(tmpDir, ".graphfs", "config.yaml"), "scan:\n  include: [\"**/*.go\"]\n")
+	if check := CheckGraphFSDirectory(tmpDir); check.Status != StatusOK {
+		t.Errorf("With a valid config: %+v", check)
+	}
+
+	writeFile(t, filepath.Join(tmpDir, ".graphfs", "config.yaml"), "scan: [unclosed\n")
+	if check := CheckGraphFSDirectory(tmpDir); check.Status != StatusError {
+		t.Errorf("With invalid YAML: %+v", check)
+	}
+}
+
+func TestCheckShadowIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if check := CheckShadowIndex(tmpDir); check.Status != StatusOK {
+		t.Errorf("Without shadow entries: %+v", check)
+	}
+
+	shadowFS, err := shadow.NewShadowFS(tmpDir, shadow.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shadowFS.Set(filepath.Join(tmpDir, "main.go"), shadow.NewEntry("main.go", shadow.SourceManual)); err != nil {
+		t.Fatal(err)
+	}
+	if err := shadowFS.SaveIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if check := CheckShadowIndex(tmpDir); check.Status != StatusOK {
+		t.Errorf("With a current index: %+v", check)
+	}
+
+	// An entry written after the index was saved
+	if err := shadowFS.Set(filepath.Join(tmpDir, "util.go"), shadow.NewEntry("util.go", shadow.SourceManual)); err != nil {
+		t.Fatal(err)
+	}
+	check := CheckShadowIndex(tmpDir)
+	if check.Status != StatusWarning || !strings.Contains(check.Message, "1 of 2") || !strings.Contains(check.Fix, "rebuild-index") {
+		t.Errorf("With a stale index: %+v", check)
+	}
+}
+
+func TestCheckOrphanedCache(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if check := CheckOrphanedCache(tmpDir); check.Status != StatusOK {
+		t.Errorf("Without a cache: %+v", check)
+	}
+
+	cacheManager, err := cache.NewManager(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"kept.go", "deleted.go"} {
+		path := filepath.Join(tmpDir, name)
+		writeFile(t, path, "package main\n")
+		if err := cacheManager.Set(path, graph.NewModule(name, "<#"+name+">"), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cacheManager.Close()
+	if err := os.Remove(filepath.Join(tmpDir, "deleted.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	check := CheckOrphanedCache(tmpDir)
+	if check.Status != StatusWarning || !strings.Contains(check.Message, "deleted.go") || strings.Contains(check.Message, "kept.go") {
+		t.Errorf("With an orphaned entry: %+v", check)
+	}
+}
+
+func TestCheckLinkedDocHeaders(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "good.go"), `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#good.go> a code:Module ;
+    code:name "good.go" .
+<!-- End LinkedDoc RDF -->
+*/
+package main
+`)
+	writeFile(t, filepath.Join(tmpDir, "bad.go"), `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#bad.go> a code:Module ;
+    code:name "bad.go" .
+*/
+package main
+`)
+
+	result, err := scanner.NewScanner().Scan(tmpDir, scanner.ScanOptions{UseDefaults: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := CheckLinkedDocHeaders(tmpDir, result.Files)
+	if check.Status != StatusError || !strings.Contains(check.Message, "1 of 2") || !strings.Contains(check.Message, "bad.go") {
+		t.Errorf("CheckLinkedDocHeaders() = %+v", check)
+	}
+}
+
+func TestCheckLinksTo(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "README.md"), "# Readme\n")
+
+	g := graph.NewGraph(tmpDir, store.NewTripleStore())
+	main := graph.NewModule("main.go", "<#main.go>")
+	main.Dependencies = []string{"util.go", "README.md", "https://example.com/spec"}
+	g.AddModule(main)
+	g.AddModule(graph.NewModule("util.go", "<#util.go>"))
+
+	if check := CheckLinksTo(g); check.Status != StatusOK {
+		t.Errorf("With resolved links: %+v", check)
+	}
+
+	main.Dependencies = append(main.Dependencies, "missing.go")
+	check := CheckLinksTo(g)
+	if check.Status != StatusWarning || !strings.Contains(check.Message, "main.go -> missing.go") {
+		t.Errorf("With a missing target: %+v", check)
+	}
+}
+
+func TestCheckGitHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmpDir := t.TempDir()
+
+	if check := CheckGitHooks(tmpDir); check.Status != StatusOK {
+		t.Errorf("Outside a repository: %+v", check)
+	}
+
+	if err := exec.Command("git", "init", "-q", tmpDir).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if check := CheckGitHooks(tmpDir); check.Status != StatusWarning || !strings.Contains(check.Fix, "pre-commit") {
+		t.Errorf("Without hooks: %+v", check)
+	}
+
+	writeFile(t, filepath.Join(tmpDir, ".git", "hooks", "pre-commit"), "#!/bin/sh\ngraphfs lint-docs\n")
+	if check := CheckGitHooks(tmpDir); check.Status != StatusOK {
+		t.Errorf("With a pre-commit hook: %+v", check)
+	}
+}
+
+func TestScore(t *testing.T) {
+	checks := []HealthCheck{
+		{Status: StatusOK},
+		{Status: StatusOK},
+		{Status: StatusWarning},
+		{Status: StatusError},
+	}
+	if got := Score(checks); got != 63 {
+		t.Errorf("Score() = %d, want 63", got)
+	}
+	if got := Score(nil); got != 100 {
+		t.Errorf("Score(nil) = %d, want 100", got)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+# Module: pkg/export/cypher.go
+Cypher export for Neo4j.
+
+Writes the module graph as Cypher statements that load into Neo4j with
+cypher-shell or the Neo4j Browser. Modules become :Module nodes keyed by
+path, with their name, description, language, layer, tags and exports as
+properties. Dependencies become relationships named after their relation
+(LINKS_TO, IMPORTS, EXTENDS, IMPLEMENTS, USES) with weight, inferred,
+confidence and declaring predicate properties, and code:calls references to
+other modules become CALLS relationships holding the called symbol.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+export, cypher, neo4j
+
+## Exports
+CypherOptions, IsCypherFile, WriteCypher
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cypher.go> a code:Module ;
+    code:name "pkg/export/cypher.go" ;
+    code:description "Cypher export for Neo4j" ;
+    code:language "go" ;
+    code:layer "export" ;
+    code:linksTo <../graph/graph.go> ;
+    code:exports <#CypherOptions>, <#IsCypherFile>, <#WriteCypher> ;
+    code:tags "export", "cypher", "neo4j" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// CypherOptions configures Cypher export
+type CypherOptions struct {
+	// Header is written as a leading comment
+	Header string
+}
+
+// IsCypherFile reports whether a file name has a Cypher extension (.cypher
+// or .cql)
+func IsCypherFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".cypher", ".cql":
+		return true
+	}
+	return false
+}
+
+// WriteCypher writes the modules of a graph as Cypher CREATE statements and
+// their dependencies and calls as relationships. Modules are written in path
+// order, so the output is reproducible. The statements create new nodes, so
+// load them into a database without a previous load of the same graph.
+func WriteCypher(w io.Writer, g *graph.Graph, opts CypherOptions) error {
+	paths := make([]string, 0, len(g.Modules))
+	for p := range g.Modules {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	bw := bufio.NewWriter(w)
+	for _, line := range strings.Split(opts.Header, "\n") {
+		if line != "" {
+			fmt.Fprintf(bw, "// %s\n", line)
+		}
+	}
+	bw.WriteString("CREATE CONSTRAINT graphfs_module_path IF NOT EXISTS FOR (m:Module) REQUIRE m.path IS UNIQUE;\n")
+
+	bw.WriteString("\n// Modules\n")
+	for _, p := range paths {
+		module := g.Modules[p]
+		props := []string{
+			"path: " + quoteLiteral(module.Path),
+			"uri: " + quoteLiteral(module.URI),
+		}
+		for _, prop := range [][2]string{
+			{"name", module.Name},
+			{"description", module.Description},
+			{"language", module.Language},
+			{"layer", module.Layer},
+		} {
+			if prop[1] != "" {
+				props = append(props, prop[0]+": "+quoteLiteral(prop[1]))
+			}
+		}
+		if len(module.Tags) > 0 {
+			props = append(props, "tags: "+cypherList(module.Tags))
+		}
+		if len(module.Exports) > 0 {
+			props = append(props, "exports: "+cypherList(module.Exports))
+		}
+		fmt.Fprintf(bw, "CREATE (:Module {%s});\n", strings.Join(props, ", "))
+	}
+
+	bw.WriteString("\n// Dependencies\n")
+	for _, p := range paths {
+		for _, edge := range g.Modules[p].DependencyEdges() {
+			if g.Modules[edge.Target] == nil {
+				continue
+			}
+			props := fmt.Sprintf("weight: %d, inferred: %t, confidence: %s", edge.Weight, edge.Inferred,
+				strconv.FormatFloat(edge.Confidence, 'f', -1, 64))
+			if edge.Predicate != "" {
+				props += ", predicate: " + quoteLiteral(edge.Predicate)
+			}
+			fmt.Fprintf(bw, "%s CREATE (a)-[:%s {%s}]->(b);\n",
+				matchPair(p, edge.Target), relationshipType(edge.Relation), props)
+		}
+	}
+
+	bw.WriteString("\n// Calls\n")
+	for _, p := range paths {
+		for _, call := range g.Modules[p].Calls {
+			target, symbol := resolveCall(p, call)
+			if target == p || g.Modules[target] == nil {
+				continue
+			}
+			fmt.Fprintf(bw, "%s CREATE (a)-[:CALLS {symbol: %s}]->(b);\n", matchPair(p, target), quoteLiteral(symbol))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// matchPair matches two modules by path as a and b
+func matchPair(from, to string) string {
+	return fmt.Sprintf("MATCH (a:Module {path: %s}), (b:Module {path: %s})", quoteLiteral(from), quoteLiteral(to))
+}
+
+// relationshipType converts a relation (e.g. linksTo) to a relationship
+// type (LINKS_TO)
+func relationshipType(relation string) string {
+	if relation == "" {
+		relation = graph.RelationLinksTo
+	}
+	var b strings.Builder
+	for i, ch := range relation {
+		switch {
+		case ch >= 'A' && ch <= 'Z':
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(ch)
+		case ch >= 'a' && ch <= 'z':
+			b.WriteRune(ch - 'a' + 'A')
+		case ch >= '0' && ch <= '9':
+			b.WriteRune(ch)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// resolveCall splits a code:calls reference such as
+// ../utils/crypto.go#HashPassword into the called module's path and symbol.
+// References without a file part call the module itself.
+func resolveCall(modulePath, call string) (string, string) {
+	call, _ = graph.Unbracket(call)
+	file, symbol, _ := strings.Cut(call, "#")
+	if file == "" {
+		return modulePath, symbol
+	}
+	if strings.HasPrefix(file, "./") || strings.HasPrefix(file, "../") {
+		file = path.Join(path.Dir(modulePath), file)
+	}
+	return pathkey.Canonical(file), symbol
+}
+
+// cypherList writes a list of string literals
+func cypherList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteLiteral(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
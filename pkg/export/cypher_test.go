@@ -0,0 +1,73 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestWriteCypher(t *testing.T) {
+	g := graph.NewGraph("/repo", store.NewTripleStore())
+	main := &graph.Module{
+		Path:         "main.go",
+		URI:          "<#main.go>",
+		Name:         "main.go",
+		Description:  "Entry \"point\"\nof the app",
+		Language:     "go",
+		Tags:         []string{"entrypoint"},
+		Dependencies: []string{"services/auth.go", "services/users.go", "vendor/missing.go"},
+		Calls:        []string{"./services/auth.go#Login", "#init", "fmt.Println"},
+	}
+	main.AddEdge(graph.Edge{Target: "services/auth.go", Weight: 3})
+	main.AddEdge(graph.Edge{Target: "services/users.go", Relation: graph.RelationImports, Inferred: true})
+	g.AddModule(main)
+	g.AddModule(&graph.Module{Path: "services/auth.go", URI: "<#auth.go>", Layer: "service", Exports: []string{"Login"}})
+	g.AddModule(&graph.Module{Path: "services/users.go", URI: "<#users.go>"})
+
+	var b strings.Builder
+	if err := WriteCypher(&b, g, CypherOptions{Header: "GraphFS export"}); err != nil {
+		t.Fatalf("WriteCypher() error = %v", err)
+	}
+	output := b.String()
+
+	for _, want := range []string{
+		"// GraphFS export\nCREATE CONSTRAINT graphfs_module_path IF NOT EXISTS FOR (m:Module) REQUIRE m.path IS UNIQUE;\n",
+		`CREATE (:Module {path: "main.go", uri: "<#main.go>", name: "main.go", description: "Entry \"point\"\nof the app", language: "go", tags: ["entrypoint"]});`,
+		`CREATE (:Module {path: "services/auth.go", uri: "<#auth.go>", layer: "service", exports: ["Login"]});`,
+		`MATCH (a:Module {path: "main.go"}), (b:Module {path: "services/auth.go"}) CREATE (a)-[:LINKS_TO {weight: 3, inferred: false, confidence: 1, predicate: "code:linksTo"}]->(b);`,
+		`MATCH (a:Module {path: "main.go"}), (b:Module {path: "services/users.go"}) CREATE (a)-[:IMPORTS {weight: 1, inferred: true, confidence: 0.6}]->(b);`,
+		`MATCH (a:Module {path: "main.go"}), (b:Module {path: "services/auth.go"}) CREATE (a)-[:CALLS {symbol: "Login"}]->(b);`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %s\n%s", want, output)
+		}
+	}
+
+	// Edges to modules outside the graph and calls that don't resolve to
+	// another module are left out
+	if strings.Contains(output, "vendor/missing.go") || strings.Contains(output, "Println") || strings.Contains(output, `"init"`) {
+		t.Errorf("Unexpected relationship in:\n%s", output)
+	}
+	if got := strings.Count(output, ")-[:"); got != 3 {
+		t.Errorf("Expected 3 relationships, got %d", got)
+	}
+}
+
+func TestRelationshipType(t *testing.T) {
+	for relation, want := range map[string]string{
+		"linksTo":    "LINKS_TO",
+		"implements": "IMPLEMENTS",
+		"":           "LINKS_TO",
+		"dependsOn2": "DEPENDS_ON2",
+		"x-ref":      "X_REF",
+	} {
+		if got := relationshipType(relation); got != want {
+			t.Errorf("relationshipType(%q) = %s, want %s", relation, got, want)
+		}
+	}
+	if !IsCypherFile("out/graph.CQL") || IsCypherFile("graph.json") {
+		t.Error("IsCypherFile() misclassified a file")
+	}
+}
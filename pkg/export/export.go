@@ -0,0 +1,341 @@
+/*
+# Module: pkg/export/export.go
+RDF export of the triple store.
+
+Serializes triples as Turtle, N-Triples or N-Quads so the graph can be
+loaded into external SPARQL endpoints. The triple store keeps subjects in
+angle brackets and URI objects without them, and has no datatypes, so terms
+are classified here: bracketed terms, absolute IRIs and fragment or relative
+references are IRIs, _: labels are blank nodes and everything else is a
+plain string literal. Relative references are resolved against a base IRI.
+Triples that cannot be written as valid RDF, such as those with a literal
+subject or predicate, are skipped and counted.
+
+## Linked Modules
+- [turtle](./turtle.go) - Turtle serialization
+- [ntriples](./ntriples.go) - N-Triples and N-Quads serialization
+- [cypher](./cypher.go) - Cypher export for Neo4j
+- [sqlite](./sqlite.go) - SQLite export of the module graph
+- [../../internal/store](../../internal/store/store.go) - Triple store
+- [../graph](../graph/iri.go) - IRI helpers
+
+## Tags
+export, rdf, turtle, ntriples, nquads
+
+## Exports
+Format, FormatTurtle, FormatNTriples, FormatNQuads, ParseFormat, FormatForFile, Prefix, DefaultPrefixes, Options, Quad, Quads, Write
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#export.go> a code:Module ;
+    code:name "pkg/export/export.go" ;
+    code:description "RDF export of the triple store" ;
+    code:language "go" ;
+    code:layer "export" ;
+    code:linksTo <./turtle.go>, <./ntriples.go>, <./cypher.go>, <./sqlite.go>, <../../internal/store/store.go>, <../graph/iri.go> ;
+    code:exports <#Format>, <#FormatTurtle>, <#FormatNTriples>, <#FormatNQuads>, <#ParseFormat>, <#FormatForFile>,
+                 <#Prefix>, <#DefaultPrefixes>, <#Options>, <#Quad>, <#Quads>, <#Write> ;
+    code:tags "export", "rdf", "turtle", "ntriples", "nquads" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Format is an RDF serialization
+type Format string
+
+const (
+	FormatTurtle   Format = "turtle"
+	FormatNTriples Format = "ntriples"
+	FormatNQuads   Format = "nquads"
+)
+
+// ParseFormat parses a format name or its usual file extension
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "turtle", "ttl":
+		return FormatTurtle, nil
+	case "ntriples", "n-triples", "nt":
+		return FormatNTriples, nil
+	case "nquads", "n-quads", "nq":
+		return FormatNQuads, nil
+	}
+	return "", fmt.Errorf("unknown RDF format %q (must be turtle, ntriples or nquads)", name)
+}
+
+// FormatForFile returns the RDF format for a file extension (.ttl, .nt or
+// .nq)
+func FormatForFile(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ttl":
+		return FormatTurtle, true
+	case ".nt":
+		return FormatNTriples, true
+	case ".nq":
+		return FormatNQuads, true
+	}
+	return "", false
+}
+
+// Prefix is a namespace written as a prefixed name in Turtle
+type Prefix struct {
+	Name      string
+	Namespace string
+}
+
+// DefaultPrefixes are the namespaces used by GraphFS metadata
+var DefaultPrefixes = []Prefix{
+	{"code", "https://schema.codedoc.org/"},
+	{"rdf", "http://www.w3.org/1999/02/22-rdf-syntax-ns#"},
+	{"rdfs", "http://www.w3.org/2000/01/rdf-schema#"},
+	{"xsd", "http://www.w3.org/2001/XMLSchema#"},
+}
+
+// Options configures serialization
+type Options struct {
+	// Base resolves fragment and relative references (e.g. <#main.go>).
+	// Required for N-Triples and N-Quads when the store holds relative
+	// references; Turtle keeps them relative without a base.
+	Base string
+
+	// Prefixes are the Turtle namespaces (default: DefaultPrefixes). Only
+	// those in use are declared.
+	Prefixes []Prefix
+
+	// Header is written as a leading comment (Turtle only)
+	Header string
+}
+
+// Quad is a triple and the named graph holding it ("" for the default
+// graph)
+type Quad struct {
+	store.Triple
+	Graph string
+}
+
+// Quads returns every triple of a store with its named graph, sorted by
+// subject, predicate and object
+func Quads(ts *store.TripleStore) []Quad {
+	quads := make([]Quad, 0, ts.Count())
+	for t := range ts.ScanSubjectPrefix("") {
+		graphName, _ := ts.GraphOf(t.Subject, t.Predicate, t.Object)
+		quads = append(quads, Quad{Triple: t, Graph: graphName})
+	}
+	return quads
+}
+
+// Write serializes quads in a format and returns the number of triples
+// skipped as invalid RDF. Turtle and N-Triples have no named graphs, so
+// their triples are written to the default graph.
+func Write(w io.Writer, quads []Quad, format Format, opts Options) (int, error) {
+	switch format {
+	case FormatNQuads:
+		return WriteNQuads(w, quads, opts)
+	case FormatTurtle, FormatNTriples:
+		triples := make([]store.Triple, len(quads))
+		for i, q := range quads {
+			triples[i] = q.Triple
+		}
+		if format == FormatTurtle {
+			return WriteTurtle(w, triples, opts)
+		}
+		return WriteNTriples(w, triples, opts)
+	}
+	return 0, fmt.Errorf("unknown RDF format %q", format)
+}
+
+// termKind is the RDF kind of a stored term
+type termKind int
+
+const (
+	kindIRI termKind = iota
+	kindBlank
+	kindLiteral
+)
+
+// term is a classified RDF term. IRIs are resolved against the base when
+// there is one.
+type term struct {
+	kind  termKind
+	value string
+}
+
+var (
+	// schemePattern matches the scheme of an absolute IRI
+	schemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+	// blankLabelPattern matches blank node labels that need no rewriting
+	blankLabelPattern = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9_.-]*[A-Za-z0-9_-])?$`)
+)
+
+// termResolver classifies stored terms and resolves relative IRIs
+type termResolver struct {
+	base *url.URL
+}
+
+// newTermResolver creates a resolver for an optional absolute base IRI
+func newTermResolver(base string) (*termResolver, error) {
+	r := &termResolver{}
+	if base == "" {
+		return r, nil
+	}
+	u, err := url.Parse(base)
+	if err != nil || !u.IsAbs() {
+		return nil, fmt.Errorf("invalid base IRI %q: must be absolute", base)
+	}
+	r.base = u
+	return r, nil
+}
+
+// subject classifies a subject, which must be an IRI or a blank node
+func (r *termResolver) subject(value string) (term, error) {
+	if t, ok := blankNode(value); ok {
+		return t, nil
+	}
+	inner, bracketed := graph.Unbracket(value)
+	if !bracketed && !isIRI(inner) {
+		return term{}, fmt.Errorf("subject %q is not an IRI", value)
+	}
+	return r.iri(inner)
+}
+
+// predicate classifies a predicate, which must be an IRI
+func (r *termResolver) predicate(value string) (term, error) {
+	inner, _ := graph.Unbracket(value)
+	if inner == "" || strings.ContainsAny(inner, " \t\n\r\"<>{}|^`\\") {
+		return term{}, fmt.Errorf("predicate %q is not an IRI", value)
+	}
+	return r.iri(inner)
+}
+
+// object classifies an object as an IRI, a blank node or a literal
+func (r *termResolver) object(value string) term {
+	if t, ok := blankNode(value); ok {
+		return t
+	}
+	inner, bracketed := graph.Unbracket(value)
+	if bracketed || isIRI(inner) {
+		if t, err := r.iri(inner); err == nil {
+			return t
+		}
+	}
+	return term{kind: kindLiteral, value: value}
+}
+
+// graphName classifies a named graph, which must be an IRI
+func (r *termResolver) graphName(value string) (term, error) {
+	inner, _ := graph.Unbracket(value)
+	return r.iri(inner)
+}
+
+// iri resolves a reference against the base. Without a base, relative
+// references are returned as they are.
+func (r *termResolver) iri(value string) (term, error) {
+	if schemePattern.MatchString(value) || r.base == nil {
+		return term{kind: kindIRI, value: value}, nil
+	}
+	ref, err := url.Parse(value)
+	if err != nil {
+		return term{}, fmt.Errorf("invalid IRI %q: %w", value, err)
+	}
+	return term{kind: kindIRI, value: r.base.ResolveReference(ref).String()}, nil
+}
+
+// absolute reports whether an IRI term is absolute
+func (t term) absolute() bool {
+	return t.kind != kindIRI || schemePattern.MatchString(t.value)
+}
+
+// blankNode classifies a _: label, rewriting characters labels may not hold
+func blankNode(value string) (term, bool) {
+	label, ok := strings.CutPrefix(value, "_:")
+	if !ok || label == "" {
+		return term{}, false
+	}
+	if !blankLabelPattern.MatchString(label) {
+		var b strings.Builder
+		for _, ch := range label {
+			if ch < 0x80 && (ch == '_' || ch == '-' || ch >= '0' && ch <= '9' || ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z') {
+				b.WriteRune(ch)
+				continue
+			}
+			fmt.Fprintf(&b, "_%X_", ch)
+		}
+		label = b.String()
+	}
+	return term{kind: kindBlank, value: label}, true
+}
+
+// isIRI reports whether an unbracketed stored term is an IRI rather than a
+// literal: an absolute http(s), urn or file IRI, or a fragment or relative
+// reference
+func isIRI(value string) bool {
+	if strings.ContainsAny(value, " \t\n\"") {
+		return false
+	}
+	for _, prefix := range []string{"http://", "https://", "urn:", "file:", "#", "./", "../"} {
+		if strings.HasPrefix(value, prefix) && len(value) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// iriRef writes an IRI in angle brackets, percent-encoding characters IRIs
+// may not hold
+func iriRef(value string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, ch := range strings.ToValidUTF8(value, "�") {
+		if ch <= ' ' || strings.ContainsRune(`<>"{}|^`+"`\\", ch) {
+			fmt.Fprintf(&b, "%%%02X", ch)
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// quoteLiteral writes a string literal with the escapes shared by Turtle,
+// N-Triples and Cypher. Other control characters are written as \u escapes.
+func quoteLiteral(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, ch := range strings.ToValidUTF8(value, "�") {
+		switch ch {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if ch < 0x20 || ch == 0x7F {
+				fmt.Fprintf(&b, `\u%04X`, ch)
+				continue
+			}
+			b.WriteRune(ch)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
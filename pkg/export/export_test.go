@@ -0,0 +1,145 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/shacl"
+)
+
+const code = "https://schema.codedoc.org/"
+
+func testStore() *store.TripleStore {
+	ts := store.NewTripleStore()
+	ts.Add("<#main.go>", rdfType, code+"Module")
+	ts.Add("<#main.go>", code+"description", "Entry point \"main\"\nwith\ttabs and a \x01 control")
+	ts.Add("<#main.go>", code+"linksTo", "./utils.go")
+	ts.Add("<#main.go>", code+"exports", "#Run")
+	ts.Add("<#main.go>", code+"name", "main.go")
+	ts.Add("_:b12", code+"note", "blank")
+	// Junk from a malformed header: the predicate is not an IRI
+	ts.Add(code+"description", "\"Creates", "a user\"")
+	ts.AddToGraph("urn:graphfs:provenance", "<#graphfs-build>", code+"builtBy", "graphfs")
+	return ts
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"ttl": FormatTurtle, "N-Triples": FormatNTriples, ".nq": FormatNQuads, "turtle": FormatTurtle} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("rdfxml"); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+	if format, ok := FormatForFile("out/graph.NT"); !ok || format != FormatNTriples {
+		t.Errorf("FormatForFile() = %q, %v", format, ok)
+	}
+	if _, ok := FormatForFile("graph.json"); ok {
+		t.Error("Expected no RDF format for .json")
+	}
+}
+
+func TestWriteNTriples(t *testing.T) {
+	var b strings.Builder
+	skipped, err := Write(&b, Quads(testStore()), FormatNTriples, Options{Base: "https://example.com/repo/"})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if skipped != 1 {
+		t.Errorf("Skipped = %d, want 1", skipped)
+	}
+
+	output := b.String()
+	for _, want := range []string{
+		`<https://example.com/repo/#main.go> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://schema.codedoc.org/Module> .`,
+		`<https://example.com/repo/#main.go> <https://schema.codedoc.org/linksTo> <https://example.com/repo/utils.go> .`,
+		`<https://example.com/repo/#main.go> <https://schema.codedoc.org/exports> <https://example.com/repo/#Run> .`,
+		`"Entry point \"main\"\nwith\ttabs and a \u0001 control"`,
+		`_:b12 <https://schema.codedoc.org/note> "blank" .`,
+		`<https://example.com/repo/#graphfs-build> <https://schema.codedoc.org/builtBy> "graphfs" .`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %s\n%s", want, output)
+		}
+	}
+	if lines := strings.Count(output, "\n"); lines != 7 {
+		t.Errorf("Expected 7 statements, got %d\n%s", lines, output)
+	}
+
+	// Relative references cannot be written without a base
+	if _, err := Write(&b, Quads(testStore()), FormatNTriples, Options{}); err == nil {
+		t.Error("Expected error for relative IRIs without a base")
+	}
+	if _, err := Write(&b, nil, FormatNTriples, Options{Base: "repo/"}); err == nil {
+		t.Error("Expected error for a relative base IRI")
+	}
+}
+
+func TestWriteNQuads(t *testing.T) {
+	var b strings.Builder
+	if _, err := Write(&b, Quads(testStore()), FormatNQuads, Options{Base: "https://example.com/repo/"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := `<https://example.com/repo/#graphfs-build> <https://schema.codedoc.org/builtBy> "graphfs" <urn:graphfs:provenance> .`
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("Output missing %s\n%s", want, b.String())
+	}
+	if !strings.Contains(b.String(), `_:b12 <https://schema.codedoc.org/note> "blank" .`) {
+		t.Errorf("Expected default graph triples without a graph name\n%s", b.String())
+	}
+}
+
+func TestWriteTurtle(t *testing.T) {
+	var b strings.Builder
+	skipped, err := Write(&b, Quads(testStore()), FormatTurtle, Options{Header: "GraphFS export"})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if skipped != 1 {
+		t.Errorf("Skipped = %d, want 1", skipped)
+	}
+	output := b.String()
+	if !strings.HasPrefix(output, "# GraphFS export\n@prefix code: <https://schema.codedoc.org/> .\n") {
+		t.Errorf("Unexpected header:\n%s", output)
+	}
+	if strings.Contains(output, "@prefix rdf:") || strings.Contains(output, "@prefix xsd:") {
+		t.Errorf("Only prefixes in use should be declared:\n%s", output)
+	}
+	if !strings.Contains(output, "<#main.go> a code:Module ;") {
+		t.Errorf("Expected rdf:type as \"a\" first:\n%s", output)
+	}
+
+	doc, err := shacl.ParseTurtle(output)
+	if err != nil {
+		t.Fatalf("Output is not valid Turtle: %v\n%s", err, output)
+	}
+	if len(doc.Triples) != 7 {
+		t.Errorf("Parsed %d triples, want 7\n%s", len(doc.Triples), output)
+	}
+	for _, triple := range doc.Triples {
+		if triple.Predicate.Value == code+"description" && triple.Object.Value != "Entry point \"main\"\nwith\ttabs and a \x01 control" {
+			t.Errorf("Description round-tripped as %q", triple.Object.Value)
+		}
+	}
+}
+
+func TestTurtleTerm(t *testing.T) {
+	resolver, _ := newTermResolver("")
+	tw := &turtleWriter{prefixes: DefaultPrefixes, used: make(map[string]bool)}
+	for object, want := range map[string]string{
+		"https://schema.codedoc.org/Module": "code:Module",
+		"#payments/charge.go":               "<#payments/charge.go>",
+		"<#a.go/edge/b c.go>":               "<#a.go/edge/b%20c.go>",
+		"./ledger.go":                       "<./ledger.go>",
+		"pkg/db.go":                         `"pkg/db.go"`,
+		"say \"hi\"\n":                      `"say \"hi\"\n"`,
+		"#":                                 `"#"`,
+		"_:b 1":                             "_:b_20_1",
+	} {
+		if got := tw.term(resolver.object(object)); got != want {
+			t.Errorf("term(%q) = %s, want %s", object, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+# Module: pkg/export/ntriples.go
+N-Triples and N-Quads serialization.
+
+Writes one statement per line with absolute IRIs, so every relative
+reference in the store must resolve against the base IRI. N-Quads keeps
+named graphs such as the build provenance graph.
+
+## Linked Modules
+- [export](./export.go) - Term classification and escaping
+
+## Tags
+export, rdf, ntriples, nquads
+
+## Exports
+WriteNTriples, WriteNQuads
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#ntriples.go> a code:Module ;
+    code:name "pkg/export/ntriples.go" ;
+    code:description "N-Triples and N-Quads serialization" ;
+    code:language "go" ;
+    code:layer "export" ;
+    code:linksTo <./export.go> ;
+    code:exports <#WriteNTriples>, <#WriteNQuads> ;
+    code:tags "export", "rdf", "ntriples", "nquads" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+// WriteNTriples writes triples as N-Triples and returns the number skipped
+// as invalid RDF
+func WriteNTriples(w io.Writer, triples []store.Triple, opts Options) (int, error) {
+	quads := make([]Quad, len(triples))
+	for i, t := range triples {
+		quads[i] = Quad{Triple: t}
+	}
+	return writeLines(w, quads, opts, false)
+}
+
+// WriteNQuads writes quads as N-Quads and returns the number skipped as
+// invalid RDF
+func WriteNQuads(w io.Writer, quads []Quad, opts Options) (int, error) {
+	return writeLines(w, quads, opts, true)
+}
+
+// writeLines writes one statement per line, with the graph name when
+// withGraph is set
+func writeLines(w io.Writer, quads []Quad, opts Options, withGraph bool) (int, error) {
+	resolver, err := newTermResolver(opts.Base)
+	if err != nil {
+		return 0, err
+	}
+
+	bw := bufio.NewWriter(w)
+	seen := make(map[Quad]bool, len(quads))
+	skipped := 0
+	for _, q := range quads {
+		if !withGraph {
+			q.Graph = ""
+		}
+		if seen[q] {
+			continue
+		}
+		seen[q] = true
+
+		terms, err := resolver.statement(q.Triple)
+		if err != nil {
+			skipped++
+			continue
+		}
+		if withGraph && q.Graph != "" {
+			graphName, err := resolver.graphName(q.Graph)
+			if err != nil {
+				skipped++
+				continue
+			}
+			terms = append(terms, graphName)
+		}
+		for _, t := range terms {
+			if !t.absolute() {
+				return skipped, fmt.Errorf("relative IRI %q needs a base IRI", t.value)
+			}
+		}
+
+		for _, t := range terms {
+			bw.WriteString(lineTerm(t))
+			bw.WriteByte(' ')
+		}
+		bw.WriteString(".\n")
+	}
+	return skipped, bw.Flush()
+}
+
+// statement classifies the terms of a triple
+func (r *termResolver) statement(t store.Triple) ([]term, error) {
+	subject, err := r.subject(t.Subject)
+	if err != nil {
+		return nil, err
+	}
+	predicate, err := r.predicate(t.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	return []term{subject, predicate, r.object(t.Object)}, nil
+}
+
+// lineTerm writes a term in N-Triples syntax
+func lineTerm(t term) string {
+	switch t.kind {
+	case kindBlank:
+		return "_:" + t.value
+	case kindLiteral:
+		return quoteLiteral(t.value)
+	}
+	return iriRef(t.value)
+}
@@ -0,0 +1,277 @@
+/*
+# Module: pkg/export/sqlite.go
+SQLite export of the module graph.
+
+Writes modules and their dependencies, exports, tags, RDF properties and
+shadow annotations to a normalized SQLite database, so analysts can query
+the graph with plain SQL. Dependencies keep the target path even when the
+target is not a module in the graph, and the dependency_paths view joins
+both ends by path for quick queries.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [../shadow](../shadow/entry.go) - Shadow annotations
+
+## Tags
+export, sqlite, sql
+
+## Exports
+SQLiteSchema, SQLiteOptions, IsSQLiteFile, WriteSQLite
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#sqlite.go> a code:Module ;
+    code:name "pkg/export/sqlite.go" ;
+    code:description "SQLite export of the module graph" ;
+    code:language "go" ;
+    code:layer "export" ;
+    code:linksTo <../graph/graph.go>, <../shadow/entry.go> ;
+    code:exports <#SQLiteSchema>, <#SQLiteOptions>, <#IsSQLiteFile>, <#WriteSQLite> ;
+    code:tags "export", "sqlite", "sql" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package export
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/shadow"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+// SQLiteSchema is the schema of SQLite exports
+const SQLiteSchema = `
+CREATE TABLE modules (
+    id          INTEGER PRIMARY KEY,
+    path        TEXT NOT NULL UNIQUE,
+    uri         TEXT NOT NULL,
+    name        TEXT,
+    description TEXT,
+    language    TEXT,
+    layer       TEXT
+);
+
+CREATE TABLE dependencies (
+    module_id   INTEGER NOT NULL REFERENCES modules(id),
+    target_path TEXT NOT NULL,
+    target_id   INTEGER REFERENCES modules(id), -- NULL when the target is not a module
+    relation    TEXT NOT NULL,
+    predicate   TEXT,             -- NULL when inferred
+    weight      INTEGER NOT NULL,
+    inferred    INTEGER NOT NULL,
+    confidence  REAL NOT NULL,
+    PRIMARY KEY (module_id, target_path)
+);
+
+CREATE TABLE exports (
+    module_id INTEGER NOT NULL REFERENCES modules(id),
+    symbol    TEXT NOT NULL,
+    PRIMARY KEY (module_id, symbol)
+);
+
+CREATE TABLE tags (
+    module_id INTEGER NOT NULL REFERENCES modules(id),
+    tag       TEXT NOT NULL,
+    PRIMARY KEY (module_id, tag)
+);
+
+CREATE TABLE properties (
+    module_id INTEGER NOT NULL REFERENCES modules(id),
+    predicate TEXT NOT NULL,
+    value     TEXT NOT NULL
+);
+
+CREATE TABLE annotations (
+    module_id  INTEGER NOT NULL REFERENCES modules(id),
+    key        TEXT NOT NULL,
+    value      TEXT NOT NULL, -- Strings as they are, other values as JSON
+    author     TEXT,
+    updated_at TEXT,
+    expires_at TEXT,
+    PRIMARY KEY (module_id, key)
+);
+
+CREATE INDEX dependencies_target ON dependencies(target_id);
+CREATE INDEX tags_tag ON tags(tag);
+CREATE INDEX properties_predicate ON properties(predicate);
+
+CREATE VIEW dependency_paths AS
+SELECT m.path AS source, d.target_path AS target, d.relation, d.predicate, d.weight, d.inferred, d.confidence
+FROM dependencies d JOIN modules m ON m.id = d.module_id;
+`
+
+// SQLiteOptions configures SQLite export
+type SQLiteOptions struct {
+	// Annotations are the shadow annotations by module path (optional).
+	// Expired annotations are left out.
+	Annotations map[string][]shadow.Annotation
+}
+
+// IsSQLiteFile reports whether a file name has a SQLite extension (.db,
+// .sqlite or .sqlite3)
+func IsSQLiteFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return true
+	}
+	return false
+}
+
+// WriteSQLite writes the graph to a new SQLite database at filename,
+// replacing any existing file. The database is written next to it and
+// renamed into place, so a failed export leaves the old file intact.
+func WriteSQLite(filename string, g *graph.Graph, opts SQLiteOptions) error {
+	tmp := filename + ".tmp"
+	_ = os.Remove(tmp)
+	if err := writeSQLite(tmp, g, opts); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// writeSQLite creates the database and inserts the graph in one transaction
+func writeSQLite(filename string, g *graph.Graph, opts SQLiteOptions) error {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(SQLiteSchema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertGraph(tx, g, opts, time.Now()); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return db.Close()
+}
+
+// insertGraph inserts every module, in path order, and its rows
+func insertGraph(tx *sql.Tx, g *graph.Graph, opts SQLiteOptions, now time.Time) error {
+	paths := make([]string, 0, len(g.Modules))
+	for p := range g.Modules {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	ids := make(map[string]int64, len(paths))
+	for i, p := range paths {
+		module := g.Modules[p]
+		ids[p] = int64(i + 1)
+		if _, err := tx.Exec(`INSERT INTO modules (id, path, uri, name, description, language, layer) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			ids[p], module.Path, module.URI, nullString(module.Name), nullString(module.Description),
+			nullString(module.Language), nullString(module.Layer)); err != nil {
+			return fmt.Errorf("failed to insert module %s: %w", p, err)
+		}
+	}
+
+	for _, p := range paths {
+		module := g.Modules[p]
+		id := ids[p]
+
+		for _, edge := range module.DependencyEdges() {
+			var target interface{}
+			if targetID, ok := ids[edge.Target]; ok {
+				target = targetID
+			}
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO dependencies (module_id, target_path, target_id, relation, predicate, weight, inferred, confidence) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				id, edge.Target, target, edge.Relation, nullString(edge.Predicate), edge.Weight, edge.Inferred, edge.Confidence); err != nil {
+				return fmt.Errorf("failed to insert dependency of %s: %w", p, err)
+			}
+		}
+		for _, symbol := range module.Exports {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO exports (module_id, symbol) VALUES (?, ?)`, id, symbol); err != nil {
+				return fmt.Errorf("failed to insert export of %s: %w", p, err)
+			}
+		}
+		for _, tag := range module.Tags {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO tags (module_id, tag) VALUES (?, ?)`, id, tag); err != nil {
+				return fmt.Errorf("failed to insert tag of %s: %w", p, err)
+			}
+		}
+
+		predicates := make([]string, 0, len(module.Properties))
+		for predicate := range module.Properties {
+			predicates = append(predicates, predicate)
+		}
+		sort.Strings(predicates)
+		for _, predicate := range predicates {
+			for _, value := range module.Properties[predicate] {
+				if _, err := tx.Exec(`INSERT INTO properties (module_id, predicate, value) VALUES (?, ?, ?)`, id, predicate, value); err != nil {
+					return fmt.Errorf("failed to insert property of %s: %w", p, err)
+				}
+			}
+		}
+
+		for _, annotation := range opts.Annotations[p] {
+			if annotation.IsExpired(now) {
+				continue
+			}
+			value, err := annotationValue(annotation.Value)
+			if err != nil {
+				return fmt.Errorf("failed to encode annotation %s of %s: %w", annotation.Key, p, err)
+			}
+			if _, err := tx.Exec(`INSERT OR REPLACE INTO annotations (module_id, key, value, author, updated_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)`,
+				id, annotation.Key, value, nullString(annotation.Author), timestamp(annotation.UpdatedAt), expiry(annotation.ExpiresAt)); err != nil {
+				return fmt.Errorf("failed to insert annotation of %s: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
+
+// annotationValue stores strings as they are and other values as JSON
+func annotationValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// timestamp stores a time as RFC 3339, or NULL when unset
+func timestamp(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// expiry stores an optional expiry time
+func expiry(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
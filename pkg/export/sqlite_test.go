@@ -0,0 +1,111 @@
+package export
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/shadow"
+)
+
+func TestWriteSQLite(t *testing.T) {
+	g := graph.NewGraph("/repo", store.NewTripleStore())
+	main := &graph.Module{
+		Path:         "main.go",
+		URI:          "<#main.go>",
+		Name:         "main.go",
+		Language:     "go",
+		Layer:        "cmd",
+		Tags:         []string{"entrypoint", "cli"},
+		Exports:      []string{"Run"},
+		Dependencies: []string{"services/auth.go", "vendor/missing.go"},
+		Properties:   map[string][]string{"https://schema.codedoc.org/owner": {"@alice"}},
+	}
+	main.AddEdge(graph.Edge{Target: "services/auth.go", Relation: graph.RelationImports, Weight: 4, Inferred: true})
+	g.AddModule(main)
+	g.AddModule(&graph.Module{Path: "services/auth.go", URI: "<#auth.go>", Layer: "service"})
+
+	expired := time.Now().Add(-time.Hour)
+	filename := filepath.Join(t.TempDir(), "graph.db")
+	if err := os.WriteFile(filename, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := WriteSQLite(filename, g, SQLiteOptions{Annotations: map[string][]shadow.Annotation{
+		"main.go": {
+			{Key: "ticket", Value: "OPS-12", Author: "bot"},
+			{Key: "review", Value: map[string]interface{}{"score": 3}},
+			{Key: "old", Value: "gone", ExpiresAt: &expired},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("WriteSQLite() error = %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	count := func(query string, args ...interface{}) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+	if n := count(`SELECT COUNT(*) FROM modules`); n != 2 {
+		t.Errorf("modules = %d, want 2", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM tags t JOIN modules m ON m.id = t.module_id WHERE m.path = 'main.go'`); n != 2 {
+		t.Errorf("tags = %d, want 2", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM exports WHERE symbol = 'Run'`); n != 1 {
+		t.Errorf("exports = %d, want 1", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM properties WHERE predicate LIKE '%owner' AND value = '@alice'`); n != 1 {
+		t.Errorf("owner properties = %d, want 1", n)
+	}
+
+	var relation string
+	var weight int
+	var inferred bool
+	var predicate sql.NullString
+	var confidence float64
+	if err := db.QueryRow(`SELECT relation, predicate, weight, inferred, confidence FROM dependency_paths WHERE source = 'main.go' AND target = 'services/auth.go'`).
+		Scan(&relation, &predicate, &weight, &inferred, &confidence); err != nil {
+		t.Fatal(err)
+	}
+	if relation != graph.RelationImports || predicate.Valid || weight != 4 || !inferred || confidence != graph.ConfidenceImported {
+		t.Errorf("Dependency = %s, %v, %d, %v, %v", relation, predicate, weight, inferred, confidence)
+	}
+	if n := count(`SELECT COUNT(*) FROM dependencies WHERE target_path = 'vendor/missing.go' AND target_id IS NULL`); n != 1 {
+		t.Errorf("Expected the dependency outside the graph with a NULL target_id")
+	}
+
+	values := make(map[string]string)
+	rows, err := db.Query(`SELECT key, value FROM annotations`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			t.Fatal(err)
+		}
+		values[key] = value
+	}
+	if len(values) != 2 || values["ticket"] != "OPS-12" || values["review"] != `{"score":3}` {
+		t.Errorf("Annotations = %v", values)
+	}
+
+	if _, err := os.Stat(filename + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Temporary database was left behind")
+	}
+}
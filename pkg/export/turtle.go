@@ -0,0 +1,160 @@
+/*
+# Module: pkg/export/turtle.go
+Turtle serialization.
+
+Writes triples as a Turtle document grouped by subject, with rdf:type
+written as "a" and IRIs in known namespaces as prefixed names. Only the
+prefixes in use are declared.
+
+## Linked Modules
+- [export](./export.go) - Term classification and escaping
+
+## Tags
+export, rdf, turtle
+
+## Exports
+WriteTurtle
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#turtle.go> a code:Module ;
+    code:name "pkg/export/turtle.go" ;
+    code:description "Turtle serialization" ;
+    code:language "go" ;
+    code:layer "export" ;
+    code:linksTo <./export.go> ;
+    code:exports <#WriteTurtle> ;
+    code:tags "export", "rdf", "turtle" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+// rdfType is written as "a"
+const rdfType = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+
+// localNamePattern matches local names that need no escaping
+var localNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// WriteTurtle writes triples as a Turtle document and returns the number
+// skipped as invalid RDF. Subjects keep their first-seen order and
+// duplicate triples are dropped.
+func WriteTurtle(w io.Writer, triples []store.Triple, opts Options) (int, error) {
+	resolver, err := newTermResolver(opts.Base)
+	if err != nil {
+		return 0, err
+	}
+	prefixes := opts.Prefixes
+	if prefixes == nil {
+		prefixes = DefaultPrefixes
+	}
+	tw := &turtleWriter{prefixes: prefixes, used: make(map[string]bool)}
+
+	// Group by subject, then predicate
+	var subjects []string
+	bySubject := make(map[string]map[string][]string)
+	skipped := 0
+	for _, t := range triples {
+		terms, err := resolver.statement(t)
+		if err != nil {
+			skipped++
+			continue
+		}
+		subject, predicate, object := tw.term(terms[0]), tw.predicate(terms[1]), tw.term(terms[2])
+
+		predicates := bySubject[subject]
+		if predicates == nil {
+			predicates = make(map[string][]string)
+			bySubject[subject] = predicates
+			subjects = append(subjects, subject)
+		}
+		if !slices.Contains(predicates[predicate], object) {
+			predicates[predicate] = append(predicates[predicate], object)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, line := range strings.Split(opts.Header, "\n") {
+		if line != "" {
+			fmt.Fprintf(bw, "# %s\n", line)
+		}
+	}
+	for _, p := range prefixes {
+		if tw.used[p.Name] {
+			fmt.Fprintf(bw, "@prefix %s: %s .\n", p.Name, iriRef(p.Namespace))
+		}
+	}
+
+	for _, subject := range subjects {
+		predicates := bySubject[subject]
+		names := make([]string, 0, len(predicates))
+		for predicate := range predicates {
+			names = append(names, predicate)
+		}
+		// rdf:type first, as "a"
+		sort.Slice(names, func(i, j int) bool {
+			if (names[i] == "a") != (names[j] == "a") {
+				return names[i] == "a"
+			}
+			return names[i] < names[j]
+		})
+
+		fmt.Fprintf(bw, "\n%s ", subject)
+		for i, predicate := range names {
+			if i > 0 {
+				bw.WriteString(" ;\n    ")
+			}
+			objects := predicates[predicate]
+			sort.Strings(objects)
+			fmt.Fprintf(bw, "%s %s", predicate, strings.Join(objects, ", "))
+		}
+		bw.WriteString(" .\n")
+	}
+
+	return skipped, bw.Flush()
+}
+
+// turtleWriter writes terms and records the prefixes they use
+type turtleWriter struct {
+	prefixes []Prefix
+	used     map[string]bool
+}
+
+// predicate writes a predicate as "a", a prefixed name or an IRI
+func (tw *turtleWriter) predicate(t term) string {
+	if t.value == rdfType {
+		return "a"
+	}
+	return tw.term(t)
+}
+
+// term writes a term, using a prefixed name for IRIs in a known namespace
+func (tw *turtleWriter) term(t term) string {
+	switch t.kind {
+	case kindBlank:
+		return "_:" + t.value
+	case kindLiteral:
+		return quoteLiteral(t.value)
+	}
+	for _, p := range tw.prefixes {
+		if local, ok := strings.CutPrefix(t.value, p.Namespace); ok && localNamePattern.MatchString(local) {
+			tw.used[p.Name] = true
+			return p.Name + ":" + local
+		}
+	}
+	return iriRef(t.value)
+}
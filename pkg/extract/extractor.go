@@ -0,0 +1,189 @@
+/*
+# Module: pkg/extract/extractor.go
+Per-language source extractors.
+
+An Extractor infers module metadata from one language's source files.
+Extractors are registered by scanner language key, and the graph builder
+looks up the one for each scanned file without LinkedDoc metadata. The
+result becomes module triples marked code:inferred, whose imports the
+builder resolves into inferred dependency edges.
+
+## Linked Modules
+- [golang](./golang.go) - Go source metadata extraction
+- [python](./python.go) - Python source metadata extraction
+- [typescript](./typescript.go) - TypeScript and JavaScript source metadata extraction
+- [java](./java.go) - Java source metadata extraction
+- [../scanner](../scanner/language.go) - Language detection
+- [../parser](../parser/triple.go) - RDF triples
+
+## Tags
+extract, inference, languages
+
+## Exports
+Extractor, File, Register, For, ForFile, Supports, Triples, InferredPredicate, ImportsPredicate
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#extractor.go> a code:Module ;
+    code:name "pkg/extract/extractor.go" ;
+    code:description "Per-language source extractors" ;
+    code:language "go" ;
+    code:layer "extract" ;
+    code:linksTo <./golang.go>, <./python.go>, <./typescript.go>, <./java.go>, <../scanner/language.go>, <../parser/triple.go> ;
+    code:exports <#Extractor>, <#File>, <#Register>, <#For>, <#ForFile>, <#Supports>, <#Triples>, <#InferredPredicate>, <#ImportsPredicate> ;
+    code:tags "extract", "inference", "languages" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package extract
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	rdf "github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+const codePrefix = "https://schema.codedoc.org/"
+
+// Predicates of extracted metadata
+const (
+	// InferredPredicate marks a module whose metadata was extracted from
+	// its source rather than declared in a LinkedDoc header
+	InferredPredicate = codePrefix + "inferred"
+
+	// ImportsPredicate records an imported package or module path
+	ImportsPredicate = codePrefix + "importsPackage"
+)
+
+// Extractor infers module metadata from source files of one language
+type Extractor interface {
+	// Language returns the scanner language key the extractor handles
+	Language() string
+
+	// Supports reports whether metadata is extracted from the file, e.g.
+	// leaving out tests
+	Supports(filePath string) bool
+
+	// Extract parses source and extracts its metadata
+	Extract(filename string, src []byte) (*File, error)
+}
+
+// File is the metadata extracted from a source file
+type File struct {
+	Language    string   // Scanner language key
+	Package     string   // Package name, where the language declares one
+	Description string   // First sentence of the file's documentation, if any
+	Imports     []string // Imported package or module paths as written, sorted
+	Exports     []string // Exported identifiers; Go methods as Type.Method
+	Calls       []string // Calls into imported packages as path.Func, sorted (Go only)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Extractor)
+)
+
+func init() {
+	Register(goExtractor{})
+	Register(pythonExtractor{})
+	Register(typeScriptExtractor{language: "typescript"})
+	Register(typeScriptExtractor{language: "javascript"})
+	Register(javaExtractor{})
+}
+
+// Register registers an extractor for its language, replacing any
+// previous one
+func Register(e Extractor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[e.Language()] = e
+}
+
+// For returns the extractor for a scanner language key, or nil
+func For(language string) Extractor {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[language]
+}
+
+// ForFile returns the extractor for a scanned file, or nil when the file is
+// binary or no extractor supports it
+func ForFile(file *scanner.FileInfo) Extractor {
+	if file.Binary {
+		return nil
+	}
+	if e := For(scanner.DetectLanguageKey(file.Path)); e != nil && e.Supports(file.Path) {
+		return e
+	}
+	return nil
+}
+
+// Supports reports whether metadata can be extracted from the file at
+// filePath by a registered extractor
+func Supports(filePath string) bool {
+	e := For(scanner.DetectLanguageKey(filePath))
+	return e != nil && e.Supports(filePath)
+}
+
+// Triples returns the metadata as module triples for the file at relPath,
+// in the form the LinkedDoc parser produces, marked code:inferred
+func (f *File) Triples(relPath string) []rdf.Triple {
+	subject := "<#" + relPath + ">"
+	triples := []rdf.Triple{
+		{Subject: subject, Predicate: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type", Object: rdf.NewURI(codePrefix + "Module")},
+		{Subject: subject, Predicate: codePrefix + "name", Object: rdf.NewLiteral(relPath)},
+		{Subject: subject, Predicate: codePrefix + "language", Object: rdf.NewLiteral(f.Language)},
+		{Subject: subject, Predicate: InferredPredicate, Object: rdf.NewLiteral("true")},
+	}
+	if f.Description != "" {
+		triples = append(triples, rdf.Triple{Subject: subject, Predicate: codePrefix + "description", Object: rdf.NewLiteral(f.Description)})
+	}
+	for _, export := range f.Exports {
+		triples = append(triples, rdf.Triple{Subject: subject, Predicate: codePrefix + "exports", Object: rdf.NewURI("#" + export)})
+	}
+	for _, importPath := range f.Imports {
+		triples = append(triples, rdf.Triple{Subject: subject, Predicate: ImportsPredicate, Object: rdf.NewLiteral(importPath)})
+	}
+	for _, call := range f.Calls {
+		triples = append(triples, rdf.Triple{Subject: subject, Predicate: codePrefix + "calls", Object: rdf.NewLiteral(call)})
+	}
+	return triples
+}
+
+// Triples extracts the metadata of the file at filePath as module triples
+// for relPath, with the extractor registered for its language
+func Triples(filePath, relPath string) ([]rdf.Triple, error) {
+	e := For(scanner.DetectLanguageKey(filePath))
+	if e == nil {
+		return nil, fmt.Errorf("no extractor for %s", filePath)
+	}
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	f, err := e.Extract(filePath, src)
+	if err != nil {
+		return nil, err
+	}
+	return f.Triples(relPath), nil
+}
+
+// uniqueSorted returns values sorted without duplicates
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
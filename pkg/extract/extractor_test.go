@@ -0,0 +1,71 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestFile_Triples(t *testing.T) {
+	f := &File{Language: "go", Package: "util", Exports: []string{"Log"}, Imports: []string{"fmt"}, Calls: []string{"fmt.Println"}}
+
+	got := make(map[string][]string)
+	for _, triple := range f.Triples("util/log.go") {
+		if triple.Subject != "<#util/log.go>" {
+			t.Errorf("Unexpected subject %q", triple.Subject)
+		}
+		got[triple.Predicate] = append(got[triple.Predicate], triple.Object.String())
+	}
+
+	want := map[string][]string{
+		"http://www.w3.org/1999/02/22-rdf-syntax-ns#type": {"https://schema.codedoc.org/Module"},
+		"https://schema.codedoc.org/name":                 {"util/log.go"},
+		"https://schema.codedoc.org/language":             {"go"},
+		"https://schema.codedoc.org/exports":              {"#Log"},
+		"https://schema.codedoc.org/calls":                {"fmt.Println"},
+		ImportsPredicate:                                  {"fmt"},
+		InferredPredicate:                                 {"true"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Triples() = %v, want %v", got, want)
+	}
+
+	for _, triple := range f.Triples("util/log.go") {
+		if triple.Predicate == "https://schema.codedoc.org/exports" {
+			if _, ok := triple.Object.(parser.URIObject); !ok {
+				t.Errorf("Exports should be URIs, got %T", triple.Object)
+			}
+		}
+	}
+}
+
+func TestSupports(t *testing.T) {
+	for path, want := range map[string]bool{
+		"main.go":       true,
+		"main_test.go":  false,
+		"main.py":       true,
+		"test_main.py":  false,
+		"app.spec.ts":   false,
+		"Main.java":     true,
+		"MainTest.java": false,
+		"main.rs":       false,
+	} {
+		if got := Supports(path); got != want {
+			t.Errorf("Supports(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestForFile(t *testing.T) {
+	if e := ForFile(&scanner.FileInfo{Path: "/src/app/models.py"}); e == nil || e.Language() != "python" {
+		t.Errorf("ForFile(models.py) = %v, want the python extractor", e)
+	}
+	if e := ForFile(&scanner.FileInfo{Path: "/src/app/index.js"}); e == nil || e.Language() != "javascript" {
+		t.Errorf("ForFile(index.js) = %v, want the javascript extractor", e)
+	}
+	if e := ForFile(&scanner.FileInfo{Path: "/src/logo.py", Binary: true}); e != nil {
+		t.Errorf("ForFile() of a binary file = %v, want nil", e)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+# Module: pkg/extract/golang.go
+Go source metadata extraction.
+
+Parses Go files with go/ast to infer the metadata a LinkedDoc header would
+declare: the module name, package imports, exported identifiers and calls
+into imported packages.
+
+## Linked Modules
+- [extractor](./extractor.go) - Per-language source extractors
+
+## Tags
+extract, go, ast, inference
+
+## Exports
+ExtractGo
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#golang.go> a code:Module ;
+    code:name "pkg/extract/golang.go" ;
+    code:description "Go source metadata extraction" ;
+    code:language "go" ;
+    code:layer "extract" ;
+    code:linksTo <./extractor.go> ;
+    code:exports <#ExtractGo> ;
+    code:tags "extract", "go", "ast", "inference" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package extract
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goExtractor extracts Go files other than tests
+type goExtractor struct{}
+
+func (goExtractor) Language() string { return "go" }
+
+func (goExtractor) Supports(filePath string) bool {
+	return strings.HasSuffix(filePath, ".go") && !strings.HasSuffix(filePath, "_test.go")
+}
+
+func (goExtractor) Extract(filename string, src []byte) (*File, error) {
+	return ExtractGo(filename, src)
+}
+
+// ExtractGo parses Go source and extracts its metadata
+func ExtractGo(filename string, src []byte) (*File, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	f := &File{Language: "go", Package: file.Name.Name}
+	if file.Doc != nil {
+		f.Description = strings.TrimSuffix(doc.Synopsis(file.Doc.Text()), ".")
+	}
+
+	// Imported packages by the name they are referenced with
+	packages := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		f.Imports = append(f.Imports, importPath)
+
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "_" && name != "." {
+			packages[name] = importPath
+		}
+	}
+	sort.Strings(f.Imports)
+
+	for _, decl := range file.Decls {
+		f.Exports = append(f.Exports, declExports(decl)...)
+	}
+
+	calls := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok {
+			if importPath, ok := packages[ident.Name]; ok {
+				calls[importPath+"."+selector.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	for call := range calls {
+		f.Calls = append(f.Calls, call)
+	}
+	sort.Strings(f.Calls)
+
+	return f, nil
+}
+
+// declExports returns the exported identifiers a declaration introduces
+func declExports(decl ast.Decl) []string {
+	var exports []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil
+		}
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return []string{d.Name.Name}
+		}
+		if receiver := receiverType(d.Recv.List[0].Type); ast.IsExported(receiver) {
+			return []string{receiver + "." + d.Name.Name}
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					exports = append(exports, s.Name.Name)
+				}
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					if name.IsExported() {
+						exports = append(exports, name.Name)
+					}
+				}
+			}
+		}
+	}
+	return exports
+}
+
+// receiverType returns the type name of a method receiver
+func receiverType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverType(t.X)
+	case *ast.IndexExpr: // Generic receiver T[P]
+		return receiverType(t.X)
+	case *ast.IndexListExpr: // Generic receiver T[P, Q]
+		return receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+const source = `// Package store persists records. It is safe for concurrent use.
+package store
+
+import (
+	"fmt"
+	str "strings"
+	_ "embed"
+
+	"example.com/app/util"
+)
+
+const MaxSize, minSize = 10, 1
+
+var ErrFull = fmt.Errorf("store is full")
+
+type Store struct{}
+
+type cache[K comparable] struct{}
+
+func NewStore() *Store {
+	util.Log(str.ToUpper("new"))
+	util.Log("again")
+	return &Store{}
+}
+
+func (s *Store) Put(key string) { fmt.Println(key) }
+
+func (c *cache[K]) Get() {}
+
+func helper() {}
+`
+
+func TestExtractGo(t *testing.T) {
+	f, err := ExtractGo("store.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ExtractGo() error = %v", err)
+	}
+
+	if f.Package != "store" {
+		t.Errorf("Package = %q, want store", f.Package)
+	}
+	if f.Description != "Package store persists records" {
+		t.Errorf("Description = %q", f.Description)
+	}
+	if want := []string{"embed", "example.com/app/util", "fmt", "strings"}; !reflect.DeepEqual(f.Imports, want) {
+		t.Errorf("Imports = %v, want %v", f.Imports, want)
+	}
+	if want := []string{"MaxSize", "ErrFull", "Store", "NewStore", "Store.Put"}; !reflect.DeepEqual(f.Exports, want) {
+		t.Errorf("Exports = %v, want %v", f.Exports, want)
+	}
+	if want := []string{"example.com/app/util.Log", "fmt.Errorf", "fmt.Println", "strings.ToUpper"}; !reflect.DeepEqual(f.Calls, want) {
+		t.Errorf("Calls = %v, want %v", f.Calls, want)
+	}
+
+	if _, err := ExtractGo("bad.go", []byte("package")); err == nil {
+		t.Error("ExtractGo() should fail on invalid source")
+	}
+}
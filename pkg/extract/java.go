@@ -0,0 +1,127 @@
+/*
+# Module: pkg/extract/java.go
+Java source metadata extraction.
+
+Reads Java files for the package declaration, imports, the Javadoc of the
+first public type, the public top-level types and their public methods as
+Type.method.
+
+## Linked Modules
+- [extractor](./extractor.go) - Per-language source extractors
+
+## Tags
+extract, java, inference
+
+## Exports
+ExtractJava
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#java.go> a code:Module ;
+    code:name "pkg/extract/java.go" ;
+    code:description "Java source metadata extraction" ;
+    code:language "go" ;
+    code:layer "extract" ;
+    code:linksTo <./extractor.go> ;
+    code:exports <#ExtractJava> ;
+    code:tags "extract", "java", "inference" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package extract
+
+import (
+	"go/doc"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	javaPackage    = regexp.MustCompile(`^package\s+([\w.]+)\s*;`)
+	javaImportDecl = regexp.MustCompile(`^import\s+(?:static\s+)?([\w.]+(?:\.\*)?)\s*;`)
+	javaType       = regexp.MustCompile(`^public\s+(?:(?:abstract|final|sealed|non-sealed|strictfp)\s+)*(?:class|interface|enum|record|@interface)\s+(\w+)`)
+	javaMethod     = regexp.MustCompile(`^\s+public\s+(?:(?:static|final|abstract|synchronized|default|native)\s+)*(?:<[^>]*>\s+)?[\w.<>\[\]?, ]+?\s+(\w+)\s*\(`)
+)
+
+// javaExtractor extracts Java files other than tests
+type javaExtractor struct{}
+
+func (javaExtractor) Language() string { return "java" }
+
+func (javaExtractor) Supports(filePath string) bool {
+	name := strings.TrimSuffix(path.Base(filePath), ".java")
+	return !strings.HasSuffix(name, "Test") && !strings.HasSuffix(name, "Tests") &&
+		!strings.Contains(filePath, "src/test/")
+}
+
+func (javaExtractor) Extract(filename string, src []byte) (*File, error) {
+	return ExtractJava(filename, src)
+}
+
+// ExtractJava reads Java source and extracts its metadata. Methods are
+// attributed to the public top-level type declared before them.
+func ExtractJava(filename string, src []byte) (*File, error) {
+	f := &File{Language: "java"}
+
+	var javadoc []string
+	inComment, inJavadoc := false, false
+	typeName := ""
+	for _, line := range strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inComment {
+			if inJavadoc && f.Description == "" && typeName == "" {
+				javadoc = append(javadoc, strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(trimmed, "*/"), "*")))
+			}
+			inComment = !strings.Contains(trimmed, "*/")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "/*") {
+			inJavadoc = strings.HasPrefix(trimmed, "/**")
+			if inJavadoc {
+				javadoc = []string{strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmed, "/**"), "*/"))}
+			}
+			inComment = !strings.Contains(trimmed[2:], "*/")
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "@") {
+			continue
+		}
+
+		switch {
+		case javaPackage.MatchString(line):
+			f.Package = javaPackage.FindStringSubmatch(line)[1]
+		case javaImportDecl.MatchString(line):
+			f.Imports = append(f.Imports, javaImportDecl.FindStringSubmatch(line)[1])
+		case javaType.MatchString(line):
+			if typeName == "" && f.Description == "" {
+				f.Description = strings.TrimSuffix(doc.Synopsis(javadocSummary(javadoc)), ".")
+			}
+			typeName = javaType.FindStringSubmatch(line)[1]
+			f.Exports = append(f.Exports, typeName)
+		case typeName != "" && javaMethod.MatchString(line):
+			f.Exports = append(f.Exports, typeName+"."+javaMethod.FindStringSubmatch(line)[1])
+		case line[0] != ' ' && line[0] != '\t':
+			// A non-public top-level declaration ends the previous type
+			typeName = ""
+		}
+		javadoc = nil
+	}
+
+	f.Imports = uniqueSorted(f.Imports)
+	return f, nil
+}
+
+// javadocSummary returns a Javadoc comment's text before its block tags
+func javadocSummary(lines []string) string {
+	var summary []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "@") {
+			break
+		}
+		summary = append(summary, line)
+	}
+	return strings.Join(summary, "\n")
+}
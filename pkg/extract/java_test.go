@@ -0,0 +1,55 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+const javaSource = `/*
+ * Copyright Example Corp.
+ */
+package com.example.users;
+
+import java.util.List;
+import static com.example.util.Strings.*;
+import com.example.db.Repository;
+
+/**
+ * Stores and looks up users.
+ *
+ * @author someone
+ */
+@Service
+public final class UserService {
+    private final Repository repository;
+
+    public UserService(Repository repository) {}
+
+    public List<User> findAll() { return null; }
+
+    public static <T> T first(List<T> items) { return null; }
+
+    void internal() {}
+}
+
+class Helper {
+    public void help() {}
+}
+`
+
+func TestExtractJava(t *testing.T) {
+	f, err := ExtractJava("UserService.java", []byte(javaSource))
+	if err != nil {
+		t.Fatalf("ExtractJava() error = %v", err)
+	}
+
+	if f.Package != "com.example.users" || f.Description != "Stores and looks up users" {
+		t.Errorf("Package = %q, Description = %q", f.Package, f.Description)
+	}
+	if want := []string{"com.example.db.Repository", "com.example.util.Strings.*", "java.util.List"}; !reflect.DeepEqual(f.Imports, want) {
+		t.Errorf("Imports = %v, want %v", f.Imports, want)
+	}
+	if want := []string{"UserService", "UserService.findAll", "UserService.first"}; !reflect.DeepEqual(f.Exports, want) {
+		t.Errorf("Exports = %v, want %v", f.Exports, want)
+	}
+}
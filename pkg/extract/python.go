@@ -0,0 +1,173 @@
+/*
+# Module: pkg/extract/python.go
+Python source metadata extraction.
+
+Reads Python files line by line for the module docstring, import
+statements and public top-level names: those listed in __all__, or else the
+functions and classes not starting with an underscore.
+
+## Linked Modules
+- [extractor](./extractor.go) - Per-language source extractors
+
+## Tags
+extract, python, inference
+
+## Exports
+ExtractPython
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#python.go> a code:Module ;
+    code:name "pkg/extract/python.go" ;
+    code:description "Python source metadata extraction" ;
+    code:language "go" ;
+    code:layer "extract" ;
+    code:linksTo <./extractor.go> ;
+    code:exports <#ExtractPython> ;
+    code:tags "extract", "python", "inference" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package extract
+
+import (
+	"go/doc"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	pyImportStatement = regexp.MustCompile(`^import\s+(.+)$`)
+	pyFromStatement   = regexp.MustCompile(`^from\s+(\.*[\w.]*)\s+import\b`)
+	pyDefinition      = regexp.MustCompile(`^(?:async\s+)?(?:def|class)\s+([A-Za-z]\w*)`)
+	pyAllStart        = regexp.MustCompile(`^__all__\s*(?::[^=]*)?=\s*[\[(]`)
+	pyQuotedName      = regexp.MustCompile(`['"](\w+)['"]`)
+)
+
+// pythonExtractor extracts Python files other than tests
+type pythonExtractor struct{}
+
+func (pythonExtractor) Language() string { return "python" }
+
+func (pythonExtractor) Supports(filePath string) bool {
+	name := path.Base(filePath)
+	return !strings.HasPrefix(name, "test_") && !strings.HasSuffix(name, "_test.py") && name != "conftest.py"
+}
+
+func (pythonExtractor) Extract(filename string, src []byte) (*File, error) {
+	return ExtractPython(filename, src)
+}
+
+// ExtractPython reads Python source and extracts its metadata. Statements
+// are read from unindented lines outside multi-line strings, so imports
+// inside functions are left out.
+func ExtractPython(filename string, src []byte) (*File, error) {
+	f := &File{Language: "python"}
+	lines := strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+
+	docstring, next := pythonDocstring(lines)
+	f.Description = strings.TrimSuffix(doc.Synopsis(docstring), ".")
+
+	var definitions, all []string
+	inAll := false
+	quote := ""
+	for _, line := range lines[next:] {
+		if quote != "" {
+			if strings.Count(line, quote)%2 == 1 {
+				quote = ""
+			}
+			continue
+		}
+		if inAll {
+			all = append(all, quotedNames(line)...)
+			inAll = !strings.ContainsAny(line, "])")
+			continue
+		}
+		// A statement opening a multi-line string skips to its end
+		for _, q := range []string{`"""`, `'''`} {
+			if strings.Count(line, q)%2 == 1 {
+				quote = q
+			}
+		}
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+			continue
+		}
+
+		switch {
+		case pyImportStatement.MatchString(line):
+			for _, part := range strings.Split(pyImportStatement.FindStringSubmatch(line)[1], ",") {
+				if fields := strings.Fields(stripComment(part)); len(fields) > 0 {
+					f.Imports = append(f.Imports, fields[0])
+				}
+			}
+		case pyFromStatement.MatchString(line):
+			f.Imports = append(f.Imports, pyFromStatement.FindStringSubmatch(line)[1])
+		case pyAllStart.MatchString(line):
+			all = append(all, quotedNames(line)...)
+			inAll = !strings.ContainsAny(line[strings.IndexAny(line, "[(")+1:], "])")
+		case pyDefinition.MatchString(line):
+			definitions = append(definitions, pyDefinition.FindStringSubmatch(line)[1])
+		}
+	}
+
+	f.Imports = uniqueSorted(f.Imports)
+	f.Exports = definitions
+	if len(all) > 0 {
+		f.Exports = all
+	}
+	return f, nil
+}
+
+// pythonDocstring returns the module docstring and the index of the line
+// after it
+func pythonDocstring(lines []string) (string, int) {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		trimmed = strings.TrimLeft(trimmed, "rRuU")
+		for _, q := range []string{`"""`, `'''`, `"`, `'`} {
+			if !strings.HasPrefix(trimmed, q) {
+				continue
+			}
+			body := trimmed[len(q):]
+			if end := strings.Index(body, q); end >= 0 {
+				return body[:end], i + 1
+			}
+			if len(q) == 1 {
+				return "", i
+			}
+			text := []string{body}
+			for j := i + 1; j < len(lines); j++ {
+				if end := strings.Index(lines[j], q); end >= 0 {
+					return strings.Join(append(text, lines[j][:end]), "\n"), j + 1
+				}
+				text = append(text, lines[j])
+			}
+			return "", i
+		}
+		return "", i
+	}
+	return "", len(lines)
+}
+
+// quotedNames returns the quoted identifiers on a line
+func quotedNames(line string) []string {
+	var names []string
+	for _, match := range pyQuotedName.FindAllStringSubmatch(stripComment(line), -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// stripComment removes a trailing # comment
+func stripComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
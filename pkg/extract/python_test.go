@@ -0,0 +1,67 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+const pythonSource = `#!/usr/bin/env python
+"""User storage.
+
+Persists users in the database.
+"""
+
+import os, sys as system
+import app.db.session  # noqa
+from . import helpers
+from ..models import (
+    User,
+    Group,
+)
+
+__all__ = [
+    "UserStore",
+    "load_users",
+]
+
+HELP = """
+import not_an_import
+def not_a_function():
+"""
+
+
+class UserStore:
+    def save(self):
+        import json
+
+
+def load_users():
+    pass
+
+
+def _private():
+    pass
+`
+
+func TestExtractPython(t *testing.T) {
+	f, err := ExtractPython("store.py", []byte(pythonSource))
+	if err != nil {
+		t.Fatalf("ExtractPython() error = %v", err)
+	}
+
+	if f.Language != "python" || f.Description != "User storage" {
+		t.Errorf("Language = %q, Description = %q", f.Language, f.Description)
+	}
+	if want := []string{".", "..models", "app.db.session", "os", "sys"}; !reflect.DeepEqual(f.Imports, want) {
+		t.Errorf("Imports = %v, want %v", f.Imports, want)
+	}
+	if want := []string{"UserStore", "load_users"}; !reflect.DeepEqual(f.Exports, want) {
+		t.Errorf("Exports = %v, want %v", f.Exports, want)
+	}
+
+	// Without __all__, public top-level definitions are exported
+	f, _ = ExtractPython("util.py", []byte("'''Helpers.'''\nclass Cache: pass\nasync def fetch(): pass\ndef _hidden(): pass\n"))
+	if want := []string{"Cache", "fetch"}; !reflect.DeepEqual(f.Exports, want) || f.Description != "Helpers" {
+		t.Errorf("Exports = %v, want %v; Description = %q", f.Exports, want, f.Description)
+	}
+}
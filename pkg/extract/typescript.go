@@ -0,0 +1,185 @@
+/*
+# Module: pkg/extract/typescript.go
+TypeScript and JavaScript source metadata extraction.
+
+Reads TypeScript and JavaScript files for the leading JSDoc comment, the
+modules they import, re-export or require, and the names they export with
+export declarations, export lists and CommonJS exports.
+
+## Linked Modules
+- [extractor](./extractor.go) - Per-language source extractors
+
+## Tags
+extract, typescript, javascript, inference
+
+## Exports
+ExtractTypeScript
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#typescript.go> a code:Module ;
+    code:name "pkg/extract/typescript.go" ;
+    code:description "TypeScript and JavaScript source metadata extraction" ;
+    code:language "go" ;
+    code:layer "extract" ;
+    code:linksTo <./extractor.go> ;
+    code:exports <#ExtractTypeScript> ;
+    code:tags "extract", "typescript", "javascript", "inference" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package extract
+
+import (
+	"go/doc"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	tsModuleSpecifier = regexp.MustCompile(`(?:\bfrom\s*|^\s*import\s*|\bimport\s*\(\s*|\brequire\s*\(\s*)['"]([^'"]+)['"]`)
+	tsDeclaration     = regexp.MustCompile(`^export\s+(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?(?:function\s*\*?|class|const|let|var|interface|type|enum|namespace)\s+([A-Za-z_$][\w$]*)`)
+	tsDefault         = regexp.MustCompile(`^export\s+default\b`)
+	tsExportList      = regexp.MustCompile(`^export\s+(?:type\s+)?\{([^}]*)\}`)
+	cjsExport         = regexp.MustCompile(`^(?:module\.)?exports\.([A-Za-z_$][\w$]*)\s*=`)
+	cjsExportObject   = regexp.MustCompile(`^module\.exports\s*=\s*\{([^}]*)\}`)
+)
+
+// typeScriptExtractor extracts TypeScript or JavaScript files other than
+// tests
+type typeScriptExtractor struct {
+	language string
+}
+
+func (e typeScriptExtractor) Language() string { return e.language }
+
+func (typeScriptExtractor) Supports(filePath string) bool {
+	name := path.Base(filePath)
+	return !strings.Contains(name, ".test.") && !strings.Contains(name, ".spec.") &&
+		!strings.Contains(filePath, "__tests__/")
+}
+
+func (e typeScriptExtractor) Extract(filename string, src []byte) (*File, error) {
+	f, err := ExtractTypeScript(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	f.Language = e.language
+	return f, nil
+}
+
+// ExtractTypeScript reads TypeScript or JavaScript source and extracts its
+// metadata. Exports are read from top-level statements; comments are
+// skipped line by line.
+func ExtractTypeScript(filename string, src []byte) (*File, error) {
+	f := &File{Language: "typescript"}
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+	f.Description = strings.TrimSuffix(doc.Synopsis(leadingJSDoc(text)), ".")
+
+	inComment := false
+	for _, line := range strings.Split(text, "\n") {
+		if inComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				continue
+			}
+			line = line[end+2:]
+			inComment = false
+		}
+		for {
+			start := strings.Index(line, "/*")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(line[start+2:], "*/")
+			if end < 0 {
+				line = line[:start]
+				inComment = true
+				break
+			}
+			line = line[:start] + line[start+2+end+2:]
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "//") {
+			continue
+		}
+
+		for _, match := range tsModuleSpecifier.FindAllStringSubmatch(line, -1) {
+			f.Imports = append(f.Imports, match[1])
+		}
+
+		switch {
+		case tsDeclaration.MatchString(line):
+			f.Exports = append(f.Exports, tsDeclaration.FindStringSubmatch(line)[1])
+		case tsDefault.MatchString(line):
+			f.Exports = append(f.Exports, "default")
+		case tsExportList.MatchString(line):
+			f.Exports = append(f.Exports, exportListNames(tsExportList.FindStringSubmatch(line)[1])...)
+		case cjsExport.MatchString(line):
+			f.Exports = append(f.Exports, cjsExport.FindStringSubmatch(line)[1])
+		case cjsExportObject.MatchString(line):
+			f.Exports = append(f.Exports, exportListNames(cjsExportObject.FindStringSubmatch(line)[1])...)
+		}
+	}
+
+	f.Imports = uniqueSorted(f.Imports)
+	return f, nil
+}
+
+// leadingJSDoc returns the text of a /** comment opening the file, after
+// any shebang and line comments
+func leadingJSDoc(text string) string {
+	for {
+		text = strings.TrimLeft(text, " \t\n")
+		if !strings.HasPrefix(text, "#!") && !strings.HasPrefix(text, "//") {
+			break
+		}
+		i := strings.Index(text, "\n")
+		if i < 0 {
+			return ""
+		}
+		text = text[i+1:]
+	}
+	if !strings.HasPrefix(text, "/**") {
+		return ""
+	}
+	end := strings.Index(text, "*/")
+	if end < 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text[3:end], "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "*")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			// Tags end the summary, except a file overview's own text
+			tag, rest, _ := strings.Cut(line, " ")
+			if tag != "@file" && tag != "@fileoverview" {
+				break
+			}
+			line = strings.TrimSpace(rest)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// exportListNames returns the exported names of an export list such as
+// "a, b as c, type d", or of an object literal such as "a, b: c"
+func exportListNames(list string) []string {
+	var names []string
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(item), "type "))
+		if _, alias, ok := strings.Cut(item, " as "); ok {
+			item = alias
+		}
+		key, _, _ := strings.Cut(item, ":")
+		if name := strings.TrimSpace(key); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
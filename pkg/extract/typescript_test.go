@@ -0,0 +1,57 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+const typeScriptSource = `/**
+ * HTTP client for the users API.
+ * @module users
+ */
+import axios from 'axios';
+import { User, type Role } from "./models";
+import './polyfills';
+// import { old } from './legacy';
+/* import { gone } from './removed'; */
+export * from './types';
+
+export interface Options { retries: number }
+export const DEFAULT_TIMEOUT = 30;
+export async function fetchUsers(): Promise<User[]> {
+  const { parse } = await import('./parse');
+  return [];
+}
+export default class UsersClient {}
+export { fetchUsers as listUsers, type Role };
+`
+
+func TestExtractTypeScript(t *testing.T) {
+	f, err := ExtractTypeScript("users.ts", []byte(typeScriptSource))
+	if err != nil {
+		t.Fatalf("ExtractTypeScript() error = %v", err)
+	}
+
+	if f.Description != "HTTP client for the users API" {
+		t.Errorf("Description = %q", f.Description)
+	}
+	if want := []string{"./models", "./parse", "./polyfills", "./types", "axios"}; !reflect.DeepEqual(f.Imports, want) {
+		t.Errorf("Imports = %v, want %v", f.Imports, want)
+	}
+	if want := []string{"Options", "DEFAULT_TIMEOUT", "fetchUsers", "UsersClient", "listUsers", "Role"}; !reflect.DeepEqual(f.Exports, want) {
+		t.Errorf("Exports = %v, want %v", f.Exports, want)
+	}
+
+	// CommonJS modules are extracted as JavaScript
+	e := For("javascript")
+	f, err = e.Extract("util.js", []byte("const fs = require('fs');\nexports.read = read;\nmodule.exports = { write, close: closeFile };\n"))
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if f.Language != "javascript" || !reflect.DeepEqual(f.Imports, []string{"fs"}) {
+		t.Errorf("Language = %q, Imports = %v", f.Language, f.Imports)
+	}
+	if want := []string{"read", "write", "close"}; !reflect.DeepEqual(f.Exports, want) {
+		t.Errorf("Exports = %v, want %v", f.Exports, want)
+	}
+}
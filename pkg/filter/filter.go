@@ -0,0 +1,248 @@
+/*
+# Module: pkg/filter/filter.go
+Module filter expressions.
+
+Parses expressions such as `layer=api && tag!=deprecated` and evaluates them
+against module metadata, so every exporter and analysis selects modules the
+same way. Comparisons are field=value and field!=value; values may be quoted
+and may contain glob wildcards (`path=pkg/api/*`). Comparisons combine with
+&&, || and !, and group with parentheses. Matching is case-insensitive
+except for paths.
+
+Fields are path, name, description, language, layer, tag, export and
+dependency. Any other field is matched against the module's additional RDF
+properties by predicate local name (`owner=@alice`). For fields with several
+values (tags, exports, properties), = holds when any value matches and !=
+when none does.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+filter, expressions, export, query
+
+## Exports
+Expression, Parse, MustParse, Subgraph
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#filter.go> a code:Module ;
+    code:name "pkg/filter/filter.go" ;
+    code:description "Module filter expressions" ;
+    code:language "go" ;
+    code:layer "filter" ;
+    code:linksTo <../graph/graph.go> ;
+    code:exports <#Expression>, <#Parse>, <#MustParse>, <#Subgraph> ;
+    code:tags "filter", "expressions", "export", "query" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package filter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Expression is a parsed filter expression. The nil expression matches
+// every module.
+type Expression struct {
+	source string
+	root   node
+}
+
+// node is a boolean expression over a module
+type node interface {
+	match(m *graph.Module) bool
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ operand node }
+
+// comparison is field=value or field!=value
+type comparison struct {
+	field  string
+	value  string
+	negate bool
+}
+
+func (n andNode) match(m *graph.Module) bool { return n.left.match(m) && n.right.match(m) }
+func (n orNode) match(m *graph.Module) bool  { return n.left.match(m) || n.right.match(m) }
+func (n notNode) match(m *graph.Module) bool { return !n.operand.match(m) }
+
+func (c comparison) match(m *graph.Module) bool {
+	matched := false
+	for _, value := range fieldValues(m, c.field) {
+		if c.matchValue(value) {
+			matched = true
+			break
+		}
+	}
+	return matched != c.negate
+}
+
+// matchValue compares one module value with the expression value
+func (c comparison) matchValue(value string) bool {
+	pattern := c.value
+	if c.field != "path" {
+		value, pattern = strings.ToLower(value), strings.ToLower(pattern)
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, err := path.Match(pattern, value)
+		return err == nil && ok
+	}
+	return value == pattern
+}
+
+// fieldValues returns the values of a module field
+func fieldValues(m *graph.Module, field string) []string {
+	switch field {
+	case "path":
+		return append([]string{m.Path}, m.Aliases...)
+	case "name":
+		return []string{m.Name}
+	case "description":
+		return []string{m.Description}
+	case "language":
+		return []string{m.Language}
+	case "layer":
+		return []string{m.Layer}
+	case "tag", "tags":
+		return m.Tags
+	case "export", "exports":
+		exports := make([]string, len(m.Exports))
+		for i, export := range m.Exports {
+			exports[i] = strings.TrimLeft(strings.Trim(export, "<>"), "#")
+		}
+		return exports
+	case "dependency", "dependencies":
+		return m.Dependencies
+	}
+
+	var values []string
+	for predicate, objects := range m.Properties {
+		if graph.LocalName(predicate) == field {
+			values = append(values, objects...)
+		}
+	}
+	return values
+}
+
+// Parse parses a filter expression. An empty expression returns nil, which
+// matches every module.
+func Parse(expr string) (*Expression, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	p := &exprParser{input: expr}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid filter %q: unexpected %q", expr, p.tokens[p.pos].text)
+	}
+	return &Expression{source: expr, root: root}, nil
+}
+
+// MustParse parses a filter expression and panics if it is invalid
+func MustParse(expr string) *Expression {
+	e, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Match reports whether a module satisfies the expression
+func (e *Expression) Match(m *graph.Module) bool {
+	if e == nil {
+		return true
+	}
+	return e.root.match(m)
+}
+
+// String returns the expression as written
+func (e *Expression) String() string {
+	if e == nil {
+		return ""
+	}
+	return e.source
+}
+
+// Subgraph returns a graph with the modules of g matching e. Dependencies and
+// dependents are limited to matching modules, and triples about other
+// modules are left out. Modules are copied, so g is not modified; a nil
+// expression returns g itself.
+func Subgraph(g *graph.Graph, e *Expression) *graph.Graph {
+	if e == nil {
+		return g
+	}
+
+	kept := make(map[string]bool)
+	dropped := make(map[string]bool) // URIs of modules left out
+	for p, module := range g.Modules {
+		if e.Match(module) {
+			kept[p] = true
+			continue
+		}
+		dropped[module.URI] = true
+		for _, component := range module.Components {
+			dropped[component.URI] = true
+		}
+	}
+
+	var sub *graph.Graph
+	if g.Store != nil {
+		sub = graph.NewGraph(g.Root, storeWithout(g, dropped))
+	} else {
+		sub = graph.NewGraph(g.Root, nil)
+	}
+	sub.Provenance = g.Provenance
+
+	for p := range kept {
+		module := *g.Modules[p]
+		module.Dependencies = keep(module.Dependencies, kept)
+		module.Dependents = keep(module.Dependents, kept)
+		module.Edges = keepEdges(module.Edges, kept)
+		sub.AddModule(&module)
+		sub.Statistics.TotalRelationships += len(module.Dependencies)
+	}
+	if sub.Store != nil {
+		sub.Statistics.TotalTriples = sub.Store.Count()
+	}
+	sub.Statistics.BuildDuration = g.Statistics.BuildDuration
+	return sub
+}
+
+// keep returns the paths in paths that are kept
+func keep(paths []string, kept map[string]bool) []string {
+	var result []string
+	for _, p := range paths {
+		if kept[p] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// keepEdges returns the edges to kept modules
+func keepEdges(edges []graph.Edge, kept map[string]bool) []graph.Edge {
+	var result []graph.Edge
+	for _, edge := range edges {
+		if kept[edge.Target] {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func createTestGraph() *graph.Graph {
+	g := graph.NewGraph("/repo", store.NewTripleStore())
+	modules := []*graph.Module{
+		{Path: "api/users.go", URI: "<#api/users.go>", Layer: "api", Language: "go", Tags: []string{"http"},
+			Dependencies: []string{"services/users.go"}, Exports: []string{"#UsersHandler"}},
+		{Path: "api/legacy.go", URI: "<#api/legacy.go>", Layer: "API", Language: "go", Tags: []string{"http", "deprecated"}},
+		{Path: "services/users.go", URI: "<#services/users.go>", Layer: "service", Language: "go",
+			Dependents: []string{"api/users.go"}, Properties: map[string][]string{"https://schema.codedoc.org/owner": {"@alice"}}},
+		{Path: "web/app.ts", URI: "<#web/app.ts>", Layer: "ui", Language: "typescript"},
+	}
+	for _, m := range modules {
+		g.AddModule(m)
+		_ = g.Store.Add(m.URI, "https://schema.codedoc.org/layer", m.Layer)
+	}
+	return g
+}
+
+func matching(t *testing.T, g *graph.Graph, expr string) string {
+	t.Helper()
+	e, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	var paths []string
+	for p, m := range g.Modules {
+		if e.Match(m) {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+func TestExpression_Match(t *testing.T) {
+	g := createTestGraph()
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"layer=api && tag!=deprecated", "api/users.go"},
+		{"layer=api", "api/legacy.go,api/users.go"},
+		{"language=go && !(layer=api)", "services/users.go"},
+		{"layer=ui || owner=@alice", "services/users.go,web/app.ts"},
+		{`path="api/*"`, "api/legacy.go,api/users.go"},
+		{"path=API/*", ""},
+		{"export=UsersHandler", "api/users.go"},
+		{"dependency=services/users.go", "api/users.go"},
+		{"tag!=http", "services/users.go,web/app.ts"},
+		{"layer=api || layer=ui && language=go", "api/legacy.go,api/users.go"},
+		{"", "api/legacy.go,api/users.go,services/users.go,web/app.ts"},
+	}
+	for _, tt := range tests {
+		if got := matching(t, g, tt.expr); got != tt.want {
+			t.Errorf("%q matched %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{"layer", "layer=", "layer=api &&", "(layer=api", "layer=api)", `tag="x`, "=api"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	g := createTestGraph()
+
+	sub := Subgraph(g, MustParse("layer=service || path=api/users.go"))
+	if len(sub.Modules) != 2 || sub.Statistics.TotalModules != 2 {
+		t.Fatalf("Subgraph has %d modules, want 2", len(sub.Modules))
+	}
+	if deps := sub.Modules["api/users.go"].Dependencies; len(deps) != 1 {
+		t.Errorf("api/users.go dependencies = %v, want services/users.go", deps)
+	}
+	if sub.Store.Count() != 2 || len(sub.Store.Find("<#web/app.ts>", "", "")) != 0 {
+		t.Errorf("Subgraph store has %d triples, want 2 without web/app.ts", sub.Store.Count())
+	}
+
+	// The original graph is unchanged
+	if len(g.Modules) != 4 || g.Store.Count() != 4 {
+		t.Error("Subgraph modified the original graph")
+	}
+	if Subgraph(g, nil) != g {
+		t.Error("Subgraph with a nil expression should return the graph")
+	}
+}
@@ -0,0 +1,240 @@
+/*
+# Module: pkg/filter/parser.go
+Filter expression parser.
+
+Tokenizes and parses filter expressions by recursive descent: || binds
+looser than &&, which binds looser than !. Values are bare words or quoted
+strings.
+
+## Linked Modules
+- [filter](./filter.go) - Filter expressions
+
+## Tags
+filter, parser
+
+## Exports
+(none)
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#parser.go> a code:Module ;
+    code:name "pkg/filter/parser.go" ;
+    code:description "Filter expression parser" ;
+    code:language "go" ;
+    code:layer "filter" ;
+    code:linksTo <./filter.go> ;
+    code:tags "filter", "parser" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenEq
+	tokenNeq
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// exprParser parses one expression
+type exprParser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+// operators in matching order (longest first)
+var operators = []token{
+	{tokenNeq, "!="},
+	{tokenAnd, "&&"},
+	{tokenOr, "||"},
+	{tokenEq, "=="},
+	{tokenEq, "="},
+	{tokenNot, "!"},
+	{tokenLParen, "("},
+	{tokenRParen, ")"},
+}
+
+// tokenize splits the input into tokens
+func (p *exprParser) tokenize() error {
+	s := p.input
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		if unicode.IsSpace(c) {
+			i++
+			continue
+		}
+
+		if c == '"' || c == '\'' {
+			end := strings.IndexRune(s[i+1:], c)
+			if end < 0 {
+				return fmt.Errorf("invalid filter %q: unterminated string", p.input)
+			}
+			p.tokens = append(p.tokens, token{tokenString, s[i+1 : i+1+end]})
+			i += end + 2
+			continue
+		}
+
+		matched := false
+		for _, op := range operators {
+			if strings.HasPrefix(s[i:], op.text) {
+				p.tokens = append(p.tokens, op)
+				i += len(op.text)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		start := i
+		for i < len(s) && !unicode.IsSpace(rune(s[i])) && !strings.ContainsRune(`=!&|()"'`, rune(s[i])) {
+			i++
+		}
+		if i == start {
+			return fmt.Errorf("invalid filter %q: unexpected %q", p.input, s[i:i+1])
+		}
+		p.tokens = append(p.tokens, token{tokenWord, s[start:i]})
+	}
+	return nil
+}
+
+// peek returns the current token kind, or -1 at the end
+func (p *exprParser) peek() tokenKind {
+	if p.pos >= len(p.tokens) {
+		return -1
+	}
+	return p.tokens[p.pos].kind
+}
+
+func (p *exprParser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == tokenOr {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == tokenAnd {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	switch p.peek() {
+	case tokenNot:
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	case tokenLParen:
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != tokenRParen {
+			return nil, fmt.Errorf("invalid filter %q: missing )", p.input)
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses field=value or field!=value
+func (p *exprParser) parseComparison() (node, error) {
+	if p.peek() != tokenWord {
+		return nil, p.expected("a field name")
+	}
+	field := strings.ToLower(p.tokens[p.pos].text)
+	p.pos++
+
+	negate := false
+	switch p.peek() {
+	case tokenEq:
+	case tokenNeq:
+		negate = true
+	default:
+		return nil, p.expected("= or != after " + field)
+	}
+	p.pos++
+
+	if kind := p.peek(); kind != tokenWord && kind != tokenString {
+		return nil, p.expected("a value for " + field)
+	}
+	value := p.tokens[p.pos].text
+	p.pos++
+
+	return comparison{field: field, value: value, negate: negate}, nil
+}
+
+// expected reports a missing token
+func (p *exprParser) expected(what string) error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("invalid filter %q: expected %s at end", p.input, what)
+	}
+	return fmt.Errorf("invalid filter %q: expected %s, got %q", p.input, what, p.tokens[p.pos].text)
+}
+
+// storeWithout copies the triples of g's store, keeping named graphs,
+// except those with a subject in dropped
+func storeWithout(g *graph.Graph, dropped map[string]bool) *store.TripleStore {
+	ts := store.NewTripleStore()
+	for t := range g.Store.ScanSubjectPrefix("") {
+		if dropped[t.Subject] {
+			continue
+		}
+		if name, ok := g.Store.GraphOf(t.Subject, t.Predicate, t.Object); ok {
+			_ = ts.AddToGraph(name, t.Subject, t.Predicate, t.Object)
+			continue
+		}
+		_ = ts.Add(t.Subject, t.Predicate, t.Object)
+	}
+	return ts
+}
@@ -8,9 +8,13 @@ Orchestrates scanner, parser, and triple store to build the knowledge graph.
 - [graph](./graph.go) - Graph data structure
 - [module](./module.go) - Module data structure
 - [validator](./validator.go) - Graph validation
+- [snapshot](./snapshot.go) - Build snapshots for incremental builds
+- [edges](./edges.go) - Dependency edge metadata
 - [../../pkg/scanner](../../pkg/scanner/scanner.go) - File scanner
 - [../../pkg/parser](../../pkg/parser/parser.go) - LinkedDoc parser
+- [../../pkg/extract](../../pkg/extract/extractor.go) - Per-language source extractors
 - [../../internal/store](../../internal/store/store.go) - Triple store
+- [../../pkg/pathkey](../../pkg/pathkey/pathkey.go) - Canonical path keys
 
 ## Tags
 graph, builder, orchestration
@@ -27,9 +31,9 @@ Builder, BuildOptions, NewBuilder
     code:description "Graph builder implementation" ;
     code:language "go" ;
     code:layer "graph" ;
-    code:linksTo <./graph.go>, <./module.go>, <./validator.go>,
-                 <../../pkg/scanner/scanner.go>, <../../pkg/parser/parser.go>,
-                 <../../internal/store/store.go> ;
+    code:linksTo <./graph.go>, <./module.go>, <./validator.go>, <./snapshot.go>, <./edges.go>,
+                 <../../pkg/scanner/scanner.go>, <../../pkg/parser/parser.go>, <../../pkg/extract/extractor.go>,
+                 <../../internal/store/store.go>, <../../pkg/pathkey/pathkey.go> ;
     code:exports <#Builder>, <#BuildOptions>, <#NewBuilder> ;
     code:tags "graph", "builder", "orchestration" .
 <!-- End LinkedDoc RDF -->
@@ -50,7 +54,9 @@ import (
 
 	"github.com/justin4957/graphfs/internal/store"
 	"github.com/justin4957/graphfs/pkg/cache"
+	"github.com/justin4957/graphfs/pkg/extract"
 	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/pathkey"
 	"github.com/justin4957/graphfs/pkg/scanner"
 )
 
@@ -60,6 +66,7 @@ type Builder struct {
 	parser       *parser.Parser
 	validator    *Validator
 	cacheManager *cache.Manager
+	snapshot     *Snapshot // Snapshot being recorded (nil = not recording)
 }
 
 // BuildOptions configures graph building
@@ -75,6 +82,50 @@ type BuildOptions struct {
 	SampleSeed     int64                    // Random seed for reproducible sampling
 	ChangedSince   string                   // Git ref to filter changed files
 	FocusPatterns  []string                 // File patterns to focus on
+
+	// BaseIRI rewrites fragment-only and relative URIs in the triple store
+	// into absolute IRIs under this base (empty = keep them as written)
+	BaseIRI string
+
+	// InferLayers assigns provisional layers to modules without code:layer,
+	// marked with code:inferredLayer
+	InferLayers bool
+
+	// InferEdges adds dependency edges, marked inferred, for imports between
+	// modules found in their source but not declared with code:linksTo
+	InferEdges bool
+
+	// ExtractSource builds modules for source files without LinkedDoc
+	// headers with the extractor registered for their language, marked
+	// code:inferred, with inferred imports edges
+	ExtractSource bool
+
+	// Snapshot restores modules from a prior build instead of scanning the
+	// tree; only files in SnapshotChanges are parsed. When SnapshotChanges is
+	// nil, the files git reports changed since Snapshot.Commit are used.
+	Snapshot        *Snapshot
+	SnapshotChanges []string
+
+	// RecordSnapshot records the build in a snapshot, returned by
+	// Builder.Snapshot
+	RecordSnapshot bool
+
+	// ToolVersion is the graphfs version recorded in the build provenance
+	// (empty = the module version the binary was built with)
+	ToolVersion string
+}
+
+// parses reports whether a scanned file becomes a module: it has LinkedDoc
+// metadata, or its metadata can be extracted from source
+func (opts BuildOptions) parses(file *scanner.FileInfo) bool {
+	return file.HasLinkedDoc || (opts.ExtractSource && extract.ForFile(file) != nil)
+}
+
+// infersEdges reports whether imports edges are inferred for a module: for
+// every module with InferEdges, otherwise for extracted modules, which have
+// no declared edges
+func (opts BuildOptions) infersEdges(module *Module) bool {
+	return opts.InferEdges || module.IsInferred()
 }
 
 // NewBuilder creates a new graph builder
@@ -96,6 +147,13 @@ func (b *Builder) Build(rootPath string, opts BuildOptions) (*Graph, error) {
 		return nil, fmt.Errorf("failed to resolve root path: %w", err)
 	}
 
+	var iris *IRIMapper
+	if opts.BaseIRI != "" {
+		if iris, err = NewIRIMapper(opts.BaseIRI); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize cache if enabled
 	if opts.UseCache && b.cacheManager == nil {
 		cacheManager, err := cache.NewManager(absRoot)
@@ -114,25 +172,23 @@ func (b *Builder) Build(rootPath string, opts BuildOptions) (*Graph, error) {
 	tripleStore := store.NewTripleStore()
 	graph := NewGraph(absRoot, tripleStore)
 
-	// Scan for files
-	if opts.ReportProgress {
-		fmt.Println("Scanning codebase...")
-	}
-
-	scanResult, err := b.scanner.Scan(absRoot, opts.ScanOptions)
-	if err != nil {
-		return nil, fmt.Errorf("scan failed: %w", err)
+	b.snapshot = nil
+	if opts.RecordSnapshot {
+		// Snapshots of non-git trees can still be restored with explicit changes
+		commit, _ := scanner.NewGitFilter(absRoot).HeadCommit()
+		b.snapshot = NewSnapshot(commit)
 	}
 
-	// Report scan errors if any (for partial results)
-	if scanResult.Errors.HasErrors() && opts.ReportProgress {
-		fmt.Fprint(os.Stderr, scanResult.Errors.Report())
-		fmt.Fprintf(os.Stderr, "\n✓ Partial scan results: %d files scanned, %d files failed\n",
-			scanResult.FilesScanned, scanResult.FilesFailed)
+	// Find files to parse: changed files when restoring a snapshot, otherwise
+	// every file with LinkedDoc metadata
+	var linkedDocFiles []scanner.FileInfo
+	if opts.Snapshot != nil {
+		linkedDocFiles, err = b.restoreSnapshot(graph, absRoot, opts, iris)
+	} else {
+		linkedDocFiles, err = b.scanFiles(absRoot, opts)
 	}
-
-	if opts.ReportProgress {
-		fmt.Printf("Found %d files with LinkedDoc metadata\n", len(scanResult.Files))
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse each file and build graph
@@ -150,21 +206,6 @@ func (b *Builder) Build(rootPath string, opts BuildOptions) (*Graph, error) {
 	var cacheHits atomic.Int64
 	var cacheMisses atomic.Int64
 
-	// Filter files with LinkedDoc
-	var linkedDocFiles []scanner.FileInfo
-	for _, file := range scanResult.Files {
-		if file.HasLinkedDoc {
-			linkedDocFiles = append(linkedDocFiles, *file)
-		}
-	}
-
-	// Apply smart filtering and sampling
-	linkedDocFiles = b.applyFilters(linkedDocFiles, absRoot, opts)
-
-	if opts.ReportProgress && len(linkedDocFiles) != len(scanResult.Files) {
-		fmt.Printf("After filtering: %d files to process\n", len(linkedDocFiles))
-	}
-
 	// Process files in parallel
 	var wg sync.WaitGroup
 	fileChan := make(chan scanner.FileInfo, len(linkedDocFiles))
@@ -184,19 +225,9 @@ func (b *Builder) Build(rootPath string, opts BuildOptions) (*Graph, error) {
 						// Unmarshal the cached module
 						var cachedModule Module
 						if err := json.Unmarshal(cachedData.ModuleJSON, &cachedModule); err == nil {
-							// Add module to graph (thread-safe)
-							graph.AddModule(&cachedModule)
-
-							// Restore triples to graph store (thread-safe)
-							for _, triple := range cachedData.Triples {
-								if err := graph.Store.Add(triple.Subject, triple.Predicate, triple.Object); err != nil {
-									// Log error but continue - this shouldn't break the build
-									if opts.ReportProgress {
-										fmt.Printf("Warning: failed to restore triple for %s: %v\n", file.Path, err)
-									}
-								}
-							}
-
+							// Links may have changed while the file did not
+							cachedModule.Aliases = relativePaths(absRoot, file.Aliases)
+							b.restoreModule(graph, &cachedModule, cachedData.Triples, iris, opts.ReportProgress)
 							cacheHits.Add(1)
 							continue
 						}
@@ -205,7 +236,7 @@ func (b *Builder) Build(rootPath string, opts BuildOptions) (*Graph, error) {
 					cacheMisses.Add(1)
 				}
 
-				if err := b.processFile(file, graph, absRoot, opts.UseCache, workerParser); err != nil {
+				if err := b.processFile(file, graph, absRoot, opts.UseCache, workerParser, iris); err != nil {
 					if opts.ReportProgress {
 						fmt.Printf("Warning: failed to process %s: %v\n", file.Path, err)
 					}
@@ -235,6 +266,23 @@ func (b *Builder) Build(rootPath string, opts BuildOptions) (*Graph, error) {
 		fmt.Printf("Cache: %d hits, %d misses (%.1f%% hit rate)\n", hits, misses, hitRate)
 	}
 
+	if err := addAliasTriples(graph); err != nil {
+		return nil, err
+	}
+
+	// Complete dependency edges: inferred imports, then call-site weights
+	if opts.InferEdges || opts.ExtractSource {
+		inferred, err := graph.inferEdges(opts.infersEdges)
+		if err != nil {
+			return nil, fmt.Errorf("failed to infer edges: %w", err)
+		}
+		if opts.ReportProgress && inferred > 0 {
+			fmt.Printf("Inferred %d dependency edges from imports\n", inferred)
+		}
+	}
+	graph.WeighEdges()
+	graph.AddEdgeTriples()
+
 	// Update statistics
 	graph.Statistics.TotalTriples = tripleStore.Count()
 	graph.Statistics.BuildDuration = time.Since(startTime)
@@ -245,6 +293,34 @@ func (b *Builder) Build(rootPath string, opts BuildOptions) (*Graph, error) {
 	// Build dependency graph (reverse dependencies)
 	b.buildDependencyGraph(graph)
 
+	// Record cross-language dependencies as queryable triples
+	if graph.AddLanguageTriples(graph.AnalyzeLanguages()) > 0 {
+		graph.Statistics.TotalTriples = tripleStore.Count()
+	}
+
+	// Record owners from CODEOWNERS and code:owner as code:ownedBy
+	codeowners, err := LoadCodeowners(absRoot)
+	if err != nil && opts.ReportProgress {
+		fmt.Printf("Warning: ignoring CODEOWNERS: %v\n", err)
+	}
+	if graph.AssignOwners(codeowners) > 0 {
+		graph.Statistics.TotalTriples = tripleStore.Count()
+	}
+
+	if opts.InferLayers {
+		inferences := graph.InferLayers()
+		graph.Statistics.TotalTriples = tripleStore.Count()
+		if opts.ReportProgress && len(inferences) > 0 {
+			fmt.Printf("Inferred layers for %d modules\n", len(inferences))
+		}
+	}
+
+	// Record what produced the graph in the provenance named graph
+	graph.SetProvenance(NewProvenance(absRoot, opts))
+	if b.snapshot != nil {
+		b.snapshot.Provenance = graph.Provenance
+	}
+
 	// Validate if requested
 	if opts.Validate {
 		if opts.ReportProgress {
@@ -283,42 +359,220 @@ func (b *Builder) Rebuild(rootPath string, opts BuildOptions) (*Graph, error) {
 	return b.Build(rootPath, opts)
 }
 
-// processFile parses a file and adds it to the graph
-func (b *Builder) processFile(file scanner.FileInfo, graph *Graph, rootPath string, useCache bool, p *parser.Parser) error {
-	// Parse LinkedDoc metadata
-	triples, err := p.Parse(file.Path)
+// Snapshot returns the snapshot recorded by the last build with
+// RecordSnapshot set, or nil
+func (b *Builder) Snapshot() *Snapshot {
+	return b.snapshot
+}
+
+// scanFiles scans the tree for files with LinkedDoc metadata and applies
+// filtering and sampling
+func (b *Builder) scanFiles(absRoot string, opts BuildOptions) ([]scanner.FileInfo, error) {
+	if opts.ReportProgress {
+		fmt.Println("Scanning codebase...")
+	}
+
+	scanResult, err := b.scanner.Scan(absRoot, opts.ScanOptions)
 	if err != nil {
-		return fmt.Errorf("parse failed: %w", err)
+		return nil, fmt.Errorf("scan failed: %w", err)
 	}
 
+	// Report scan errors if any (for partial results)
+	if scanResult.Errors.HasErrors() && opts.ReportProgress {
+		fmt.Fprint(os.Stderr, scanResult.Errors.Report())
+		fmt.Fprintf(os.Stderr, "\n✓ Partial scan results: %d files scanned, %d files failed\n",
+			scanResult.FilesScanned, scanResult.FilesFailed)
+	}
+
+	if opts.ReportProgress {
+		fmt.Printf("Found %d files with LinkedDoc metadata\n", len(scanResult.Files))
+		if scanResult.Duplicates > 0 {
+			fmt.Printf("Merged %d duplicate paths (symlinks or hard links) into module aliases\n", scanResult.Duplicates)
+		}
+		if scanResult.Resumed > 0 {
+			fmt.Printf("Resumed %d files from the scan checkpoint\n", scanResult.Resumed)
+		}
+	}
+
+	// Filter files with LinkedDoc
+	var linkedDocFiles []scanner.FileInfo
+	for _, file := range scanResult.Files {
+		if opts.parses(file) {
+			linkedDocFiles = append(linkedDocFiles, *file)
+		}
+	}
+
+	// Apply smart filtering and sampling
+	linkedDocFiles = b.applyFilters(linkedDocFiles, absRoot, opts)
+
+	if opts.ReportProgress && len(linkedDocFiles) != len(scanResult.Files) {
+		fmt.Printf("After filtering: %d files to process\n", len(linkedDocFiles))
+	}
+
+	return linkedDocFiles, nil
+}
+
+// restoreSnapshot adds modules from opts.Snapshot whose files are unchanged
+// to the graph and returns the changed files with LinkedDoc metadata, which
+// still need parsing. Deleted files are dropped.
+func (b *Builder) restoreSnapshot(graph *Graph, absRoot string, opts BuildOptions, iris *IRIMapper) ([]scanner.FileInfo, error) {
+	changes := opts.SnapshotChanges
+	if changes == nil {
+		if opts.Snapshot.Commit == "" {
+			return nil, fmt.Errorf("snapshot has no commit; changed files must be given explicitly")
+		}
+		git := scanner.NewGitFilter(absRoot)
+		if !git.IsGitRepository() {
+			return nil, fmt.Errorf("snapshot builds without explicit changes need a git repository")
+		}
+		var err error
+		if changes, err = git.DiffFromCommit(opts.Snapshot.Commit); err != nil {
+			return nil, fmt.Errorf("failed to diff against snapshot commit: %w", err)
+		}
+	}
+
+	keys := pathkey.Default()
+	changed := make(map[string]bool, len(changes))
+	paths := make([]string, 0, len(changes))
+	for _, p := range changes {
+		if filepath.IsAbs(p) {
+			if rel, err := filepath.Rel(absRoot, p); err == nil {
+				p = rel
+			}
+		}
+		changed[keys.Key(p)] = true
+		paths = append(paths, filepath.Join(absRoot, filepath.FromSlash(pathkey.Canonical(p))))
+	}
+
+	for p, entry := range opts.Snapshot.Modules {
+		if changed[keys.Key(p)] || entry.Module == nil {
+			continue
+		}
+		b.restoreModule(graph, entry.Module, entry.Triples, iris, opts.ReportProgress)
+		graph.Statistics.SnapshotRestored++
+	}
+
+	scanResult, err := b.scanner.ScanPaths(absRoot, paths, opts.ScanOptions)
+	if err != nil {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+
+	var linkedDocFiles []scanner.FileInfo
+	for _, file := range scanResult.Files {
+		if opts.parses(file) {
+			linkedDocFiles = append(linkedDocFiles, *file)
+		}
+	}
+
+	if opts.ReportProgress {
+		fmt.Printf("Restored %d modules from snapshot, %d changed files to parse\n",
+			graph.Statistics.SnapshotRestored, len(linkedDocFiles))
+	}
+
+	return linkedDocFiles, nil
+}
+
+// restoreModule adds a module and its triples, stored with URIs as written,
+// to the graph, applying the base IRI (thread-safe)
+func (b *Builder) restoreModule(graph *Graph, written *Module, triples []cache.Triple, iris *IRIMapper, report bool) {
+	if b.snapshot != nil {
+		b.snapshot.add(written.Path, written, triples)
+	}
+
+	module := copyModule(written)
+	if iris != nil {
+		module.URI = iris.Resolve(module.URI, module.Path)
+		for _, component := range module.Components {
+			component.URI = iris.Resolve(component.URI, module.Path)
+		}
+	}
+	graph.AddModule(module)
+
+	added := make([]store.Triple, 0, len(triples))
+	for _, triple := range triples {
+		subject, object := triple.Subject, triple.Object
+		if iris != nil {
+			subject = iris.Resolve(subject, module.Path)
+			object = iris.Resolve(object, module.Path)
+		}
+		if err := graph.Store.Add(subject, triple.Predicate, object); err != nil {
+			// Log error but continue - this shouldn't break the build
+			if report {
+				fmt.Printf("Warning: failed to restore triple for %s: %v\n", module.Path, err)
+			}
+			continue
+		}
+		added = append(added, store.Triple{Subject: subject, Predicate: triple.Predicate, Object: object})
+	}
+	graph.recordFileTriples(module.Path, added)
+}
+
+// processFile parses a file and adds it to the graph
+func (b *Builder) processFile(file scanner.FileInfo, graph *Graph, rootPath string, useCache bool, p *parser.Parser, iris *IRIMapper) error {
 	// Get relative path
 	relPath, err := filepath.Rel(rootPath, file.Path)
 	if err != nil {
 		relPath = file.Path
 	}
+	relPath = pathkey.Canonical(relPath)
 
-	// Create module if it doesn't exist
-	var moduleURI string
-	var module *Module
+	// Parse LinkedDoc metadata, or extract it from unannotated source
+	var triples []parser.Triple
+	if file.HasLinkedDoc {
+		triples, err = p.ParseFor(file.Path, scanner.DetectLanguageKey(file.Path))
+	} else {
+		triples, err = extract.Triples(file.Path, relPath)
+	}
+	if err != nil {
+		return fmt.Errorf("parse failed: %w", err)
+	}
 
 	// Collect triples for caching
 	var cacheTriples []cache.Triple
 
+	// Record the triples added for the file so Update can remove them
+	var added []store.Triple
+	addTriple := func(subject, predicate, object string) error {
+		if err := graph.Store.Add(subject, predicate, object); err != nil {
+			return err
+		}
+		added = append(added, store.Triple{Subject: subject, Predicate: predicate, Object: object})
+		return nil
+	}
+	defer func() { graph.recordFileTriples(relPath, added) }()
+
+	// storeURI maps a URI as written to its form in the triple store
+	storeURI := func(term string) string {
+		if iris == nil {
+			return term
+		}
+		return iris.Resolve(term, relPath)
+	}
+
+	// Module and component nodes in declaration order. A file may hold
+	// several LinkedDoc blocks; the first code:Module is the file's module
+	// and every other module or component node becomes one of its components.
+	var moduleURI string
+	var componentURIs []string
+	nodes := make(map[string]*Module)
+
 	// Process triples
 	for _, triple := range triples {
 		// Add to triple store
-		var objectStr string
+		var objectStr, storeObject string
 		switch obj := triple.Object.(type) {
 		case parser.LiteralObject:
 			objectStr = obj.Value
+			storeObject = objectStr
 		case parser.URIObject:
 			objectStr = obj.URI
+			storeObject = storeURI(objectStr)
 		case parser.BlankNodeObject:
 			// Skip blank nodes for now
 			continue
 		}
 
-		if err := graph.Store.Add(triple.Subject, triple.Predicate, objectStr); err != nil {
+		if err := addTriple(storeURI(triple.Subject), triple.Predicate, storeObject); err != nil {
 			return fmt.Errorf("failed to add triple: %w", err)
 		}
 
@@ -329,31 +583,133 @@ func (b *Builder) processFile(file scanner.FileInfo, graph *Graph, rootPath stri
 			Object:    objectStr,
 		})
 
-		// Extract module information
-		if strings.Contains(triple.Predicate, "rdf-syntax-ns#type") &&
-			strings.Contains(objectStr, "Module") {
-			moduleURI = triple.Subject
-			if module == nil {
-				module = NewModule(relPath, moduleURI)
+		// Extract module and component nodes
+		if strings.Contains(triple.Predicate, "rdf-syntax-ns#type") && nodes[triple.Subject] == nil {
+			isModule := strings.Contains(objectStr, "Module")
+			if isModule || strings.HasSuffix(objectStr, "Component") {
+				nodes[triple.Subject] = NewModule(relPath, storeURI(triple.Subject))
+				if isModule && moduleURI == "" {
+					moduleURI = triple.Subject
+				} else {
+					componentURIs = append(componentURIs, triple.Subject)
+				}
 			}
 		}
 
-		if module != nil && triple.Subject == moduleURI {
-			b.extractModuleProperty(module, triple.Predicate, objectStr, relPath)
+		if node := nodes[triple.Subject]; node != nil {
+			b.extractModuleProperty(node, triple.Predicate, objectStr, relPath)
+		}
+	}
+
+	// Components without a file-level module get one for the file
+	if moduleURI == "" && len(componentURIs) > 0 {
+		moduleURI = "<#" + filepath.ToSlash(relPath) + ">"
+		nodes[moduleURI] = NewModule(relPath, storeURI(moduleURI))
+		nodes[moduleURI].Name = filepath.ToSlash(relPath)
+		if err := addTriple(storeURI(moduleURI), typePredicate, moduleType); err != nil {
+			return fmt.Errorf("failed to add triple: %w", err)
+		}
+		cacheTriples = append(cacheTriples, cache.Triple{Subject: moduleURI, Predicate: typePredicate, Object: moduleType})
+	}
+
+	module := nodes[moduleURI]
+	for _, componentURI := range componentURIs {
+		component := nodes[componentURI]
+		module.AddComponent(component)
+
+		// Objects are stored without brackets, like other URI objects
+		object, _ := Unbracket(component.URI)
+		if err := addTriple(module.URI, ContainsPredicate, object); err != nil {
+			return fmt.Errorf("failed to add triple: %w", err)
 		}
+		object, _ = Unbracket(componentURI)
+		cacheTriples = append(cacheTriples, cache.Triple{Subject: moduleURI, Predicate: ContainsPredicate, Object: object})
 	}
 
 	// Add module to graph if we found one
 	if module != nil {
+		// Mark generated code so analyses can skip it
+		if file.Generated && !module.IsGenerated() {
+			if err := addTriple(module.URI, GeneratedPredicate, "true"); err != nil {
+				return fmt.Errorf("failed to add triple: %w", err)
+			}
+			cacheTriples = append(cacheTriples, cache.Triple{
+				Subject:   moduleURI,
+				Predicate: GeneratedPredicate,
+				Object:    "true",
+			})
+			module.AddProperty(GeneratedPredicate, "true")
+		}
+
+		// Record TODO, FIXME and DEPRECATED comments
+		if source, err := os.ReadFile(file.Path); err == nil {
+			module.Markers = ScanMarkers(string(source))
+		}
+		for _, marker := range module.Markers {
+			if err := addTriple(module.URI, marker.Predicate(), marker.Value()); err != nil {
+				return fmt.Errorf("failed to add triple: %w", err)
+			}
+			cacheTriples = append(cacheTriples, cache.Triple{
+				Subject:   moduleURI,
+				Predicate: marker.Predicate(),
+				Object:    marker.Value(),
+			})
+		}
+
+		module.Aliases = relativePaths(rootPath, file.Aliases)
 		graph.AddModule(module)
 
-		// Cache the module and its triples if caching is enabled
-		if useCache && b.cacheManager != nil {
-			// Ignore cache write errors - caching is not critical
-			_ = b.cacheManager.Set(file.Path, module, cacheTriples)
+		// Cache and record the module and its triples if enabled
+		if (useCache && b.cacheManager != nil) || b.snapshot != nil {
+			// Cache URIs as written so a changed base IRI never goes stale
+			cached := *module
+			cached.URI = moduleURI
+			cached.Components = make([]*Module, len(module.Components))
+			for i, component := range module.Components {
+				c := *component
+				c.URI = componentURIs[i]
+				cached.Components[i] = &c
+			}
+
+			if useCache && b.cacheManager != nil {
+				// Ignore cache write errors - caching is not critical
+				_ = b.cacheManager.Set(file.Path, &cached, cacheTriples)
+			}
+			if b.snapshot != nil {
+				b.snapshot.add(relPath, copyModule(&cached), cacheTriples)
+			}
+		}
+	}
+
+	return nil
+}
+
+// relativePaths converts absolute file paths to canonical paths relative
+// to the root
+func relativePaths(rootPath string, paths []string) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+	rel := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if r, err := filepath.Rel(rootPath, p); err == nil {
+			p = r
 		}
+		rel = append(rel, pathkey.Canonical(p))
 	}
+	return rel
+}
 
+// addAliasTriples records the path aliases of every module as
+// code:pathAlias triples
+func addAliasTriples(graph *Graph) error {
+	for _, module := range graph.Modules {
+		for _, alias := range module.Aliases {
+			if err := graph.Store.Add(module.URI, PathAliasPredicate, alias); err != nil {
+				return fmt.Errorf("failed to add triple: %w", err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -371,7 +727,9 @@ func (b *Builder) extractModuleProperty(module *Module, predicate, value, module
 	case strings.HasSuffix(predicate, "linksTo"):
 		// Resolve relative path to absolute path relative to project root
 		resolvedPath := b.resolveDependencyPath(value, modulePath)
-		module.AddDependency(resolvedPath)
+		module.AddEdge(Edge{Target: resolvedPath, Relation: RelationLinksTo, Predicate: predicate})
+	case edgeRelation(predicate) != "":
+		module.AddEdge(Edge{Target: b.resolveDependencyPath(value, modulePath), Relation: edgeRelation(predicate), Predicate: predicate})
 	case strings.HasSuffix(predicate, "exports"):
 		module.AddExport(value)
 	case strings.HasSuffix(predicate, "calls"):
@@ -401,10 +759,9 @@ func (b *Builder) resolveDependencyPath(depPath, modulePath string) string {
 	// Resolve the relative path
 	resolvedPath := filepath.Join(moduleDir, depPath)
 
-	// Clean the path to normalize it (removes . and .. components)
-	cleanPath := filepath.Clean(resolvedPath)
-
-	return cleanPath
+	// Clean the path to normalize it (removes . and .. components) and use
+	// forward slashes so it matches module paths on every platform
+	return pathkey.Canonical(resolvedPath)
 }
 
 // buildDependencyGraph builds reverse dependency relationships
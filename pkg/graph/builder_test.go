@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestBuild_MultipleLinkedDocBlocks(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"user.go": `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#user.go> a code:Module ;
+    code:name "user.go" .
+<!-- End LinkedDoc RDF -->
+*/
+package app
+`,
+		"handlers.go": `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#handlers.go> a code:Module ;
+    code:name "handlers.go" ;
+    code:layer "api" .
+<!-- End LinkedDoc RDF -->
+*/
+package app
+
+// <!-- LinkedDoc RDF -->
+// <#UserHandler> a code:Component ;
+//     code:name "UserHandler" ;
+//     code:linksTo <./user.go> .
+// <!-- End LinkedDoc RDF -->
+func UserHandler() {}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, err := NewBuilder().Build(root, BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	handlers := g.Modules["handlers.go"]
+	if handlers == nil {
+		t.Fatal("handlers.go module not found")
+	}
+	if handlers.Layer != "api" {
+		t.Errorf("Layer = %q, want file module properties", handlers.Layer)
+	}
+	if len(handlers.Components) != 1 || handlers.Components[0].Name != "UserHandler" {
+		t.Fatalf("Components = %v, want UserHandler", handlers.Components)
+	}
+
+	// Component dependencies roll up to the file module
+	if len(handlers.Dependencies) != 1 || handlers.Dependencies[0] != "user.go" {
+		t.Errorf("Dependencies = %v, want [user.go]", handlers.Dependencies)
+	}
+
+	contains := g.Store.Find(handlers.URI, ContainsPredicate, "")
+	if len(contains) != 1 || contains[0].Object != "#UserHandler" {
+		t.Errorf("contains triples = %v, want #UserHandler", contains)
+	}
+}
+
+func TestBuild_DuplicatePaths(t *testing.T) {
+	root := t.TempDir()
+	content := `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#shared.go> a code:Module ;
+    code:name "shared.go" .
+<!-- End LinkedDoc RDF -->
+*/
+package app
+`
+	if err := os.MkdirAll(filepath.Join(root, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "lib", "shared.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The same file through a symlink and a hard link
+	if err := os.Symlink(filepath.Join("lib", "shared.go"), filepath.Join(root, "link.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Link(filepath.Join(root, "lib", "shared.go"), filepath.Join(root, "lib", "copy.go")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+
+	g, err := NewBuilder().Build(root, BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true, FollowSymlinks: true},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if len(g.Modules) != 1 || g.Statistics.TotalModules != 1 {
+		t.Fatalf("Expected 1 module, got %d", len(g.Modules))
+	}
+	module := g.Modules["lib/copy.go"]
+	if module == nil {
+		t.Fatalf("Expected the shortest non-symlink path as the module path, got %v", g.Modules)
+	}
+	if len(module.Aliases) != 2 || module.Aliases[0] != "lib/shared.go" || module.Aliases[1] != "link.go" {
+		t.Errorf("Aliases = %v, want [lib/shared.go link.go]", module.Aliases)
+	}
+	if g.Statistics.DuplicatePaths != 2 {
+		t.Errorf("DuplicatePaths = %d, want 2", g.Statistics.DuplicatePaths)
+	}
+	if g.GetModule("link.go") != module {
+		t.Error("GetModule() should resolve aliases")
+	}
+	if triples := g.Store.Find(module.URI, PathAliasPredicate, ""); len(triples) != 2 {
+		t.Errorf("Expected 2 pathAlias triples, got %d", len(triples))
+	}
+}
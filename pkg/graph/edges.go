@@ -0,0 +1,388 @@
+/*
+# Module: pkg/graph/edges.go
+Dependency edge metadata.
+
+Each dependency of a module carries an edge: its relation type (linksTo, or
+imports, extends, implements and uses when declared with those predicates),
+the predicate that declared it, a weight counting the call sites of the
+target's exports in the dependent's source, whether it was declared in a
+LinkedDoc header or inferred from source imports, and a confidence (1 for
+declared edges, lower for inferred ones). Edges are added to the triple
+store as code:DependencyEdge nodes so they can be queried by relation,
+weight, origin or confidence.
+
+## Linked Modules
+- [graph](./graph.go) - Graph data structure
+- [module](./module.go) - Module data structure
+- [builder](./builder.go) - Graph builder
+- [../adopt](../adopt/imports.go) - Import inference
+
+## Tags
+graph, dependencies, edges, weights
+
+## Exports
+Edge, DependencyEdgePredicate, EdgeTargetPredicate, EdgeRelationPredicate, EdgeWeightPredicate, EdgeInferredPredicate, EdgeSourcePredicate, EdgeConfidencePredicate, ConfidenceDeclared, ConfidenceReferenced, ConfidenceImported, Module.AddEdge, Module.EdgeTo, Module.DependencyEdges, Graph.WeighEdges, Graph.InferEdges, Graph.AddEdgeTriples
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#edges.go> a code:Module ;
+    code:name "pkg/graph/edges.go" ;
+    code:description "Dependency edge metadata" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./graph.go>, <./module.go>, <./builder.go>, <../adopt/imports.go> ;
+    code:exports <#Edge>, <#DependencyEdgePredicate>, <#EdgeTargetPredicate>, <#EdgeRelationPredicate>,
+                 <#EdgeWeightPredicate>, <#EdgeInferredPredicate>, <#EdgeSourcePredicate>, <#EdgeConfidencePredicate>,
+                 <#ConfidenceDeclared>, <#ConfidenceReferenced>, <#ConfidenceImported>, <#Module.AddEdge>, <#Module.EdgeTo>,
+                 <#Module.DependencyEdges>, <#Graph.WeighEdges>, <#Graph.InferEdges>, <#Graph.AddEdgeTriples> ;
+    code:tags "graph", "dependencies", "edges", "weights" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/adopt"
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// Predicates of dependency edge nodes
+const (
+	DependencyEdgePredicate = "https://schema.codedoc.org/dependencyEdge"
+	EdgeTargetPredicate     = "https://schema.codedoc.org/edgeTarget"
+	EdgeRelationPredicate   = "https://schema.codedoc.org/relation"
+	EdgeWeightPredicate     = "https://schema.codedoc.org/weight"
+	EdgeInferredPredicate   = "https://schema.codedoc.org/inferred"
+	EdgeSourcePredicate     = "https://schema.codedoc.org/sourcePredicate"
+	EdgeConfidencePredicate = "https://schema.codedoc.org/confidence"
+
+	dependencyEdgeType = "https://schema.codedoc.org/DependencyEdge"
+
+	// edgePredicatePrefix prefixes relations to form the predicate
+	// declaring them, as written in LinkedDoc headers
+	edgePredicatePrefix = "code:"
+)
+
+// Edge confidences
+const (
+	ConfidenceDeclared   = 1.0 // Declared in a LinkedDoc header
+	ConfidenceReferenced = 0.9 // Inferred from an import whose exports the source references
+	ConfidenceImported   = 0.6 // Inferred from an import alone
+)
+
+// Edge relation types
+const (
+	RelationLinksTo    = "linksTo"
+	RelationImports    = "imports"
+	RelationExtends    = "extends"
+	RelationImplements = "implements"
+	RelationUses       = "uses"
+)
+
+// edgeRelations are the predicates, besides linksTo, that declare a typed
+// dependency
+var edgeRelations = []string{RelationImports, RelationExtends, RelationImplements, RelationUses}
+
+// Edge is a dependency of a module with its metadata
+type Edge struct {
+	Target     string  // Dependency, as in Module.Dependencies
+	Relation   string  // Relation type (linksTo, imports, ...)
+	Predicate  string  // Predicate that declared the edge, e.g. code:uses ("" when inferred)
+	Weight     int     // Call sites of the target's exports, at least 1
+	Inferred   bool    // Inferred from source imports rather than declared
+	Confidence float64 // 0-1; ConfidenceDeclared unless inferred
+}
+
+// AddEdge adds a dependency with its metadata. A declared edge replaces an
+// inferred one to the same target; otherwise the first relation is kept and
+// the larger weight and confidence win.
+func (m *Module) AddEdge(edge Edge) {
+	edge = edge.normalize()
+	m.AddDependency(edge.Target)
+
+	for i := range m.Edges {
+		existing := &m.Edges[i]
+		if existing.Target != edge.Target {
+			continue
+		}
+		if existing.Inferred && !edge.Inferred {
+			existing.Relation, existing.Predicate, existing.Inferred = edge.Relation, edge.Predicate, false
+		}
+		if edge.Weight > existing.Weight {
+			existing.Weight = edge.Weight
+		}
+		if edge.Confidence > existing.Confidence {
+			existing.Confidence = edge.Confidence
+		}
+		return
+	}
+	m.Edges = append(m.Edges, edge)
+}
+
+// EdgeTo returns the edge to a dependency. Dependencies added without
+// metadata are declared linksTo edges of weight 1.
+func (m *Module) EdgeTo(target string) Edge {
+	for _, edge := range m.Edges {
+		if edge.Target == target {
+			return edge.normalize()
+		}
+	}
+	return Edge{Target: target}.normalize()
+}
+
+// normalize fills in unset metadata: the linksTo relation, the predicate of
+// declared edges, a weight of 1 and the default confidence
+func (e Edge) normalize() Edge {
+	if e.Relation == "" {
+		e.Relation = RelationLinksTo
+	}
+	if e.Weight < 1 {
+		e.Weight = 1
+	}
+	if e.Inferred {
+		if e.Confidence <= 0 {
+			e.Confidence = ConfidenceImported
+		}
+		return e
+	}
+	if e.Predicate == "" {
+		e.Predicate = edgePredicatePrefix + e.Relation
+	}
+	if e.Confidence <= 0 {
+		e.Confidence = ConfidenceDeclared
+	}
+	return e
+}
+
+// DependencyEdges returns the edges of every dependency, in dependency order
+func (m *Module) DependencyEdges() []Edge {
+	edges := make([]Edge, len(m.Dependencies))
+	for i, dep := range m.Dependencies {
+		edges[i] = m.EdgeTo(dep)
+	}
+	return edges
+}
+
+// edgeRelation returns the relation a predicate declares, or ""
+func edgeRelation(predicate string) string {
+	name := predicate[strings.LastIndexAny(predicate, "/#:")+1:]
+	for _, relation := range edgeRelations {
+		if name == relation {
+			return relation
+		}
+	}
+	return ""
+}
+
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// WeighEdges sets the weight of every dependency edge to the number of
+// references to the target's exports in the dependent's source, outside its
+// LinkedDoc header, with a minimum of 1
+func (g *Graph) WeighEdges() {
+	for _, module := range g.Modules {
+		g.weighEdges(module)
+	}
+}
+
+// weighEdges sets the weights of one module's dependency edges
+func (g *Graph) weighEdges(module *Module) {
+	if len(module.Dependencies) == 0 {
+		return
+	}
+
+	identifiers := g.sourceIdentifiers(module)
+	for _, dep := range module.Dependencies {
+		edge := module.EdgeTo(dep)
+		edge.Weight = 1
+		if target := g.GetModule(dep); target != nil {
+			if sites := callSites(identifiers, target.Exports); sites > 1 {
+				edge.Weight = sites
+			}
+		}
+		module.setEdge(edge)
+	}
+}
+
+// sourceIdentifiers counts the identifiers in a module's source after its
+// LinkedDoc header; unreadable sources have none
+func (g *Graph) sourceIdentifiers(module *Module) map[string]int {
+	counts := make(map[string]int)
+	source, err := os.ReadFile(filepath.Join(g.Root, filepath.FromSlash(module.Path)))
+	if err != nil {
+		return counts
+	}
+	style := parser.CommentStyleFor(scanner.DetectLanguageKey(module.Path))
+	for _, id := range identifierPattern.FindAllString(stripLinkedDoc(string(source), style.EndMarker), -1) {
+		counts[id]++
+	}
+	return counts
+}
+
+// setEdge replaces or adds the edge to a dependency
+func (m *Module) setEdge(edge Edge) {
+	for i := range m.Edges {
+		if m.Edges[i].Target == edge.Target {
+			m.Edges[i] = edge
+			return
+		}
+	}
+	m.Edges = append(m.Edges, edge)
+}
+
+// stripLinkedDoc returns source without the text up to the end marker of
+// its LinkedDoc block, so header references are not counted as call sites
+func stripLinkedDoc(source, endMarker string) string {
+	if i := strings.Index(source, endMarker); i >= 0 {
+		return source[i+len(endMarker):]
+	}
+	return source
+}
+
+// callSites sums the references to exported names; a method export such as
+// Graph.InferLayers counts references to InferLayers
+func callSites(identifiers map[string]int, exports []string) int {
+	sites := 0
+	seen := make(map[string]bool)
+	for _, export := range exports {
+		name := strings.TrimLeft(strings.Trim(export, "<>"), "#")
+		if i := strings.LastIndexAny(name, ".#/"); i >= 0 {
+			name = name[i+1:]
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		sites += identifiers[name]
+	}
+	return sites
+}
+
+// InferEdges adds inferred imports edges for imports between modules that
+// are not declared, returning how many were added. An import of a module
+// with exports counts only if the source references one of them. Dependents are not
+// updated; Build runs this before computing them.
+func (g *Graph) InferEdges() (int, error) {
+	return g.inferEdges(func(*Module) bool { return true })
+}
+
+// inferEdges adds inferred imports edges from the modules selected by
+// include; imports resolve against every module
+func (g *Graph) inferEdges(include func(*Module) bool) (int, error) {
+	files := make([]*scanner.FileInfo, 0, len(g.Modules))
+	for p := range g.Modules {
+		files = append(files, &scanner.FileInfo{
+			Path:         filepath.Join(g.Root, filepath.FromSlash(p)),
+			Language:     scanner.DetectLanguage(p),
+			HasLinkedDoc: true,
+		})
+	}
+	inferred, err := adopt.Infer(g.Root, files)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for p, file := range inferred.Files {
+		module := g.Modules[p]
+		if module == nil || !include(module) {
+			continue
+		}
+		var identifiers map[string]int
+		for _, dep := range file.Imports {
+			target := g.Modules[dep]
+			if target == nil || module.hasDependency(dep) {
+				continue
+			}
+			// Package imports resolve to every file of the package; keep
+			// the files whose exports the source references
+			confidence := ConfidenceImported
+			if len(target.Exports) > 0 {
+				if identifiers == nil {
+					identifiers = g.sourceIdentifiers(module)
+				}
+				if callSites(identifiers, target.Exports) == 0 {
+					continue
+				}
+				confidence = ConfidenceReferenced
+			}
+			module.AddEdge(Edge{Target: dep, Relation: RelationImports, Weight: 1, Inferred: true, Confidence: confidence})
+			added++
+		}
+	}
+	return added, nil
+}
+
+// hasDependency reports whether dep is already a dependency
+func (m *Module) hasDependency(dep string) bool {
+	for _, existing := range m.Dependencies {
+		if existing == dep {
+			return true
+		}
+	}
+	return false
+}
+
+// AddEdgeTriples records every dependency edge as a code:DependencyEdge node
+// linked from its module, returning the number of triples added
+func (g *Graph) AddEdgeTriples() int {
+	if g.Store == nil {
+		return 0
+	}
+
+	before := g.Store.Count()
+	for _, module := range g.Modules {
+		g.addEdgeTriples(module)
+	}
+	return g.Store.Count() - before
+}
+
+// addEdgeTriples records one module's dependency edges
+func (g *Graph) addEdgeTriples(module *Module) {
+	for _, edge := range module.DependencyEdges() {
+		node := edgeNode(module.URI, edge.Target)
+		target := edge.Target
+		if targetModule := g.GetModule(edge.Target); targetModule != nil {
+			target = targetModule.URI
+		}
+
+		// The edge node and a target module are stored in the same form as
+		// subjects, so queries can join ?module code:dependencyEdge ?edge
+		// with the edge's own triples and ?edge code:edgeTarget ?target
+		// with the target's. Only fails for empty terms, which these never
+		// are.
+		_ = g.Store.Add(module.URI, DependencyEdgePredicate, node)
+		_ = g.Store.Add(node, typePredicate, dependencyEdgeType)
+		_ = g.Store.Add(node, EdgeTargetPredicate, target)
+		_ = g.Store.Add(node, EdgeRelationPredicate, edge.Relation)
+		_ = g.Store.Add(node, EdgeWeightPredicate, strconv.Itoa(edge.Weight))
+		_ = g.Store.Add(node, EdgeInferredPredicate, strconv.FormatBool(edge.Inferred))
+		_ = g.Store.Add(node, EdgeConfidencePredicate, strconv.FormatFloat(edge.Confidence, 'f', -1, 64))
+		if edge.Predicate != "" {
+			_ = g.Store.Add(node, EdgeSourcePredicate, edge.Predicate)
+		}
+	}
+}
+
+// removeEdgeTriples removes the edge nodes of a module
+func (g *Graph) removeEdgeTriples(module *Module) {
+	for _, t := range g.Store.Find(module.URI, DependencyEdgePredicate, "") {
+		_ = g.Store.Delete(t.Object, "", "")
+	}
+	_ = g.Store.Delete(module.URI, DependencyEdgePredicate, "")
+}
+
+// edgeNode returns the URI of the edge node from a module to a dependency
+func edgeNode(moduleURI, target string) string {
+	uri, _ := Unbracket(moduleURI)
+	return "<" + uri + "/edge/" + strings.Trim(target, "<>") + ">"
+}
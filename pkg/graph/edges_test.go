@@ -0,0 +1,305 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func writeEdgeProject(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+		"main.go": `/*
+# Module: main.go
+Entry point.
+
+<!-- LinkedDoc RDF -->
+<#main.go> a code:Module ;
+    code:name "main.go" ;
+    code:linksTo <./store/store.go> ;
+    code:uses <./config/config.go> .
+<!-- End LinkedDoc RDF -->
+*/
+
+package main
+
+import (
+	"example.com/app/config"
+	"example.com/app/store"
+	"example.com/app/util"
+)
+
+func main() {
+	s := store.NewStore(config.Load())
+	s.Put("a")
+	s.Put("b")
+	_ = store.NewStore(nil)
+	util.Log("done")
+}
+`,
+		"store/store.go": `/*
+# Module: store/store.go
+Storage.
+
+<!-- LinkedDoc RDF -->
+<#store.go> a code:Module ;
+    code:name "store/store.go" ;
+    code:exports <#NewStore>, <#Store.Put> .
+<!-- End LinkedDoc RDF -->
+*/
+
+package store
+`,
+		"config/config.go": `/*
+# Module: config/config.go
+Configuration.
+
+<!-- LinkedDoc RDF -->
+<#config.go> a code:Module ;
+    code:name "config/config.go" ;
+    code:exports <#Load> .
+<!-- End LinkedDoc RDF -->
+*/
+
+package config
+`,
+		"util/log.go": `/*
+# Module: util/log.go
+Logging.
+
+<!-- LinkedDoc RDF -->
+<#log.go> a code:Module ;
+    code:name "util/log.go" ;
+    code:exports <#Log> .
+<!-- End LinkedDoc RDF -->
+*/
+
+package util
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func TestBuilder_DependencyEdges(t *testing.T) {
+	root := writeEdgeProject(t)
+
+	g, err := NewBuilder().Build(root, BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+		InferEdges:  true,
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	main := g.Modules["main.go"]
+	if main == nil {
+		t.Fatal("main.go not in graph")
+	}
+
+	tests := []struct {
+		target     string
+		relation   string
+		predicate  string
+		weight     int
+		inferred   bool
+		confidence float64
+	}{
+		{"store/store.go", RelationLinksTo, "code:linksTo", 4, false, ConfidenceDeclared}, // NewStore x2, Put x2
+		{"config/config.go", RelationUses, "code:uses", 1, false, ConfidenceDeclared},
+		{"util/log.go", RelationImports, "", 1, true, ConfidenceReferenced},
+	}
+	for _, tt := range tests {
+		edge := main.EdgeTo(tt.target)
+		if edge.Relation != tt.relation || edge.Predicate != tt.predicate || edge.Weight != tt.weight ||
+			edge.Inferred != tt.inferred || edge.Confidence != tt.confidence {
+			t.Errorf("EdgeTo(%s) = %+v, want relation=%s predicate=%q weight=%d inferred=%v confidence=%v",
+				tt.target, edge, tt.relation, tt.predicate, tt.weight, tt.inferred, tt.confidence)
+		}
+	}
+	if len(main.Dependencies) != 3 {
+		t.Errorf("Expected 3 dependencies, got %v", main.Dependencies)
+	}
+
+	edges := g.Store.Find("<#main.go>", DependencyEdgePredicate, "")
+	if len(edges) != 3 {
+		t.Fatalf("Expected 3 dependency edge triples, got %v", edges)
+	}
+	node := edgeNode("<#main.go>", "store/store.go")
+	if weights := g.Store.Find(node, EdgeWeightPredicate, ""); len(weights) != 1 || weights[0].Object != "4" {
+		t.Errorf("Expected weight 4 for %s, got %v", node, weights)
+	}
+	if targets := g.Store.Find(node, EdgeTargetPredicate, ""); len(targets) != 1 || targets[0].Object != "<#store.go>" {
+		t.Errorf("Expected target <#store.go> for %s, got %v", node, targets)
+	}
+	if sources := g.Store.Find(node, EdgeSourcePredicate, ""); len(sources) != 1 || sources[0].Object != "code:linksTo" {
+		t.Errorf("Expected source predicate code:linksTo for %s, got %v", node, sources)
+	}
+	inferred := edgeNode("<#main.go>", "util/log.go")
+	if confidences := g.Store.Find(inferred, EdgeConfidencePredicate, ""); len(confidences) != 1 || confidences[0].Object != "0.9" {
+		t.Errorf("Expected confidence 0.9 for %s, got %v", inferred, confidences)
+	}
+	if len(g.Store.Find(inferred, EdgeSourcePredicate, "")) != 0 {
+		t.Errorf("Inferred edge %s should have no source predicate", inferred)
+	}
+}
+
+func TestStripLinkedDoc(t *testing.T) {
+	source := "# LinkedDoc:\n# code:exports <#Sync> .\n# End LinkedDoc\ndef run(): Sync()\n"
+	if got := stripLinkedDoc(source, "End LinkedDoc"); got != "\ndef run(): Sync()\n" {
+		t.Errorf("stripLinkedDoc() = %q", got)
+	}
+	if got := stripLinkedDoc(source, parser.DefaultEndMarker); got != source {
+		t.Errorf("stripLinkedDoc() without the end marker = %q, want source unchanged", got)
+	}
+}
+
+func TestModule_AddEdge(t *testing.T) {
+	m := NewModule("a.go", "<#a.go>")
+	m.AddEdge(Edge{Target: "b.go", Relation: RelationImports, Weight: 2, Inferred: true})
+	m.AddEdge(Edge{Target: "b.go", Weight: 1})
+	m.AddDependency("c.go")
+
+	if edge := m.EdgeTo("b.go"); edge.Relation != RelationLinksTo || edge.Inferred || edge.Weight != 2 ||
+		edge.Confidence != ConfidenceDeclared || edge.Predicate != "code:linksTo" {
+		t.Errorf("Declared edge should replace inferred one keeping the weight, got %+v", edge)
+	}
+	if edge := m.EdgeTo("c.go"); edge.Relation != RelationLinksTo || edge.Weight != 1 || edge.Confidence != ConfidenceDeclared {
+		t.Errorf("Dependency without metadata should default to linksTo/1, got %+v", edge)
+	}
+
+	m.AddEdge(Edge{Target: "d.go", Relation: RelationImports, Inferred: true})
+	m.AddEdge(Edge{Target: "d.go", Relation: RelationImports, Inferred: true, Confidence: ConfidenceReferenced})
+	if edge := m.EdgeTo("d.go"); edge.Predicate != "" || edge.Confidence != ConfidenceReferenced {
+		t.Errorf("Inferred edge should have no predicate and the higher confidence, got %+v", edge)
+	}
+	if len(m.DependencyEdges()) != 3 {
+		t.Errorf("Expected 2 edges, got %+v", m.DependencyEdges())
+	}
+}
+
+func TestBuilder_ExtractSource(t *testing.T) {
+	root := writeEdgeProject(t)
+	files := map[string]string{
+		// Unannotated files, one importing another and an annotated module
+		"cmd/tool/main.go":      "package main\n\nimport (\n\t\"example.com/app/report\"\n\t\"example.com/app/store\"\n)\n\nfunc main() { report.Print(store.NewStore()) }\n",
+		"report/report.go":      "// Package report prints reports.\npackage report\n\nfunc Print(v any) {}\n",
+		"report/report_test.go": "package report\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	opts := BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}}
+	g, err := NewBuilder().Build(root, opts)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if g.Modules["report/report.go"] != nil {
+		t.Error("Unannotated files should not be modules without ExtractSource")
+	}
+
+	opts.ExtractSource = true
+	g, err = NewBuilder().Build(root, opts)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	report := g.Modules["report/report.go"]
+	if report == nil || !report.IsInferred() {
+		t.Fatalf("Expected an inferred report/report.go module, got %+v", report)
+	}
+	if report.Description != "Package report prints reports" || len(report.Exports) != 1 || report.Exports[0] != "#Print" {
+		t.Errorf("Unexpected extracted metadata: %+v", report)
+	}
+	if g.Modules["report/report_test.go"] != nil {
+		t.Error("Test files should not be extracted")
+	}
+	if g.Modules["main.go"].IsInferred() {
+		t.Error("Annotated modules should not be marked inferred")
+	}
+
+	tool := g.Modules["cmd/tool/main.go"]
+	if tool == nil {
+		t.Fatal("cmd/tool/main.go not in graph")
+	}
+	for _, target := range []string{"report/report.go", "store/store.go"} {
+		if edge := tool.EdgeTo(target); !tool.hasDependency(target) || edge.Relation != RelationImports || !edge.Inferred {
+			t.Errorf("EdgeTo(%s) = %+v, want an inferred imports edge", target, edge)
+		}
+	}
+	if len(tool.Calls) != 2 {
+		t.Errorf("Expected 2 calls, got %v", tool.Calls)
+	}
+
+	// Annotated modules keep their declared edges only without InferEdges
+	if g.Modules["main.go"].hasDependency("util/log.go") {
+		t.Error("Imports of annotated modules should only be inferred with InferEdges")
+	}
+	if len(g.Store.Find("<#report/report.go>", EdgeInferredPredicate, "true")) != 1 {
+		t.Error("Expected a code:inferred triple for the extracted module")
+	}
+}
+
+func TestBuilder_ExtractSource_Languages(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app/main.py":                        "from app.models import User\n\nprint(User())\n",
+		"app/models.py":                      "\"\"\"Data models.\"\"\"\n\nclass User:\n    pass\n",
+		"web/src/index.ts":                   "import { fetchUsers } from './api';\n\nfetchUsers();\n",
+		"web/src/api.ts":                     "export function fetchUsers() {}\n",
+		"java/src/com/example/App.java":      "package com.example;\n\nimport com.example.db.Store;\n\npublic class App {\n    Store store = new Store();\n}\n",
+		"java/src/com/example/db/Store.java": "package com.example.db;\n\npublic class Store {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	g, err := NewBuilder().Build(root, BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}, ExtractSource: true})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(g.Modules) != len(files) {
+		t.Fatalf("Expected %d extracted modules, got %d", len(files), len(g.Modules))
+	}
+	if models := g.Modules["app/models.py"]; models.Language != "python" || models.Description != "Data models" {
+		t.Errorf("Unexpected extracted metadata: %+v", models)
+	}
+
+	for source, target := range map[string]string{
+		"app/main.py":                   "app/models.py",
+		"web/src/index.ts":              "web/src/api.ts",
+		"java/src/com/example/App.java": "java/src/com/example/db/Store.java",
+	} {
+		module := g.Modules[source]
+		if edge := module.EdgeTo(target); !module.hasDependency(target) || !edge.Inferred {
+			t.Errorf("%s: EdgeTo(%s) = %+v, want an inferred imports edge", source, target, edge)
+		}
+	}
+}
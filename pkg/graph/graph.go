@@ -13,7 +13,7 @@ Defines the Graph and GraphStats types for managing the codebase knowledge graph
 graph, knowledge-graph, data-structure
 
 ## Exports
-Graph, GraphStats
+Graph, GraphStats, Graph.Clone, Graph.FileTriples
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -25,7 +25,7 @@ Graph, GraphStats
     code:language "go" ;
     code:layer "graph" ;
     code:linksTo <./builder.go>, <./module.go>, <../../internal/store/store.go> ;
-    code:exports <#Graph>, <#GraphStats> ;
+    code:exports <#Graph>, <#GraphStats>, <#Graph.Clone>, <#Graph.FileTriples> ;
     code:tags "graph", "knowledge-graph", "data-structure" .
 <!-- End LinkedDoc RDF -->
 */
@@ -41,11 +41,14 @@ import (
 
 // Graph represents a codebase knowledge graph
 type Graph struct {
-	Store      *store.TripleStore // Triple store containing all RDF triples
-	Root       string             // Root directory path
-	Modules    map[string]*Module // Modules indexed by path
-	Statistics GraphStats         // Graph statistics
-	mu         sync.Mutex         // Mutex for thread-safe operations
+	Store      *store.TripleStore        // Triple store containing all RDF triples
+	Root       string                    // Root directory path
+	Modules    map[string]*Module        // Modules indexed by path
+	Statistics GraphStats                // Graph statistics
+	Provenance *Provenance               // What produced the graph (nil = not recorded)
+	aliases    map[string]string         // Module paths indexed by alias path
+	files      map[string][]store.Triple // Triples each source file added, by path
+	mu         sync.Mutex                // Mutex for thread-safe operations
 }
 
 // GraphStats provides statistics about the knowledge graph
@@ -56,6 +59,8 @@ type GraphStats struct {
 	ModulesByLanguage  map[string]int // Modules grouped by language
 	ModulesByLayer     map[string]int // Modules grouped by layer
 	BuildDuration      time.Duration  // Time taken to build graph
+	SnapshotRestored   int            // Modules restored from a prior snapshot
+	DuplicatePaths     int            // Paths merged into another module as aliases
 }
 
 // NewGraph creates a new empty graph
@@ -64,6 +69,7 @@ func NewGraph(root string, tripleStore *store.TripleStore) *Graph {
 		Store:   tripleStore,
 		Root:    root,
 		Modules: make(map[string]*Module),
+		aliases: make(map[string]string),
 		Statistics: GraphStats{
 			ModulesByLanguage: make(map[string]int),
 			ModulesByLayer:    make(map[string]int),
@@ -71,9 +77,66 @@ func NewGraph(root string, tripleStore *store.TripleStore) *Graph {
 	}
 }
 
-// GetModule returns a module by its path
+// Clone returns a deep copy of the graph with its own triple store, so the
+// copy can be read while the original is updated
+func (g *Graph) Clone() *Graph {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	clone := &Graph{
+		Root:       g.Root,
+		Modules:    make(map[string]*Module, len(g.Modules)),
+		Statistics: g.Statistics,
+		aliases:    make(map[string]string, len(g.aliases)),
+		files:      make(map[string][]store.Triple, len(g.files)),
+	}
+	if g.Store != nil {
+		clone.Store = g.Store.Clone()
+	}
+	if g.Provenance != nil {
+		provenance := *g.Provenance
+		clone.Provenance = &provenance
+	}
+	for path, module := range g.Modules {
+		c := copyModule(module)
+		c.Dependents = append([]string{}, module.Dependents...)
+		clone.Modules[path] = c
+	}
+	for alias, path := range g.aliases {
+		clone.aliases[alias] = path
+	}
+	for path, triples := range g.files {
+		clone.files[path] = append([]store.Triple{}, triples...)
+	}
+	clone.Statistics.ModulesByLanguage = copyCounts(g.Statistics.ModulesByLanguage)
+	clone.Statistics.ModulesByLayer = copyCounts(g.Statistics.ModulesByLayer)
+	return clone
+}
+
+// copyCounts copies a statistics map
+func copyCounts(counts map[string]int) map[string]int {
+	c := make(map[string]int, len(counts))
+	for key, n := range counts {
+		c[key] = n
+	}
+	return c
+}
+
+// FileTriples returns the triples a source file added to the store, with
+// their subjects and objects as stored. Symbols a header declares, such as
+// <#UserService.CreateUser>, keep their file only here.
+func (g *Graph) FileTriples(path string) []store.Triple {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]store.Triple{}, g.files[path]...)
+}
+
+// GetModule returns a module by its path or one of its aliases
 func (g *Graph) GetModule(path string) *Module {
-	return g.Modules[path]
+	if module, ok := g.Modules[path]; ok {
+		return module
+	}
+	return g.Modules[g.aliases[path]]
 }
 
 // AddModule adds a module to the graph (thread-safe)
@@ -83,6 +146,13 @@ func (g *Graph) AddModule(module *Module) {
 
 	g.Modules[module.Path] = module
 	g.Statistics.TotalModules++
+	if len(module.Aliases) > 0 && g.aliases == nil {
+		g.aliases = make(map[string]string)
+	}
+	for _, alias := range module.Aliases {
+		g.aliases[alias] = module.Path
+		g.Statistics.DuplicatePaths++
+	}
 
 	// Update statistics
 	if module.Language != "" {
@@ -105,6 +175,10 @@ func (g *Graph) RemoveModule(path string) {
 
 	// Update statistics
 	g.Statistics.TotalModules--
+	for _, alias := range module.Aliases {
+		delete(g.aliases, alias)
+		g.Statistics.DuplicatePaths--
+	}
 	if module.Language != "" {
 		g.Statistics.ModulesByLanguage[module.Language]--
 		if g.Statistics.ModulesByLanguage[module.Language] <= 0 {
@@ -66,6 +66,35 @@ func TestGraph_GetModule(t *testing.T) {
 	}
 }
 
+func TestGraph_Clone(t *testing.T) {
+	graph := NewGraph("/test", store.NewTripleStore())
+	module := NewModule("main.go", "<#main.go>")
+	module.Language = "go"
+	module.AddDependency("util.go")
+	module.Dependents = []string{"cmd.go"}
+	graph.AddModule(module)
+	graph.Store.Add("<#main.go>", "name", "main.go")
+
+	clone := graph.Clone()
+	graph.RemoveModule("main.go")
+	graph.Store.Delete("<#main.go>", "", "")
+	module.AddDependency("log.go")
+
+	cloned := clone.GetModule("main.go")
+	if cloned == nil {
+		t.Fatal("Removing from the original should not change the clone")
+	}
+	if len(cloned.Dependencies) != 1 || len(cloned.Dependents) != 1 {
+		t.Errorf("Clone dependencies = %v, dependents = %v", cloned.Dependencies, cloned.Dependents)
+	}
+	if clone.Store.Count() != 1 {
+		t.Errorf("Clone store Count() = %d, want 1", clone.Store.Count())
+	}
+	if clone.Statistics.ModulesByLanguage["go"] != 1 {
+		t.Errorf("Clone ModulesByLanguage[go] = %d, want 1", clone.Statistics.ModulesByLanguage["go"])
+	}
+}
+
 func TestGraph_GetModulesByLanguage(t *testing.T) {
 	graph := NewGraph("/test", nil)
 
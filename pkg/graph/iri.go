@@ -0,0 +1,159 @@
+/*
+# Module: pkg/graph/iri.go
+Base IRI mapping for generated triples.
+
+LinkedDoc headers identify modules and symbols with fragment-only URIs such
+as <#services/user.go> and relative links such as <../models/user.go>. When
+a base IRI is configured (e.g. https://graph.mycorp.com/repo/), these are
+rewritten into globally unique, dereferenceable IRIs:
+
+  <#services/user.go>          -> <https://graph.mycorp.com/repo/services/user.go>
+  #UserService (in user.go)    -> https://graph.mycorp.com/repo/services/user.go#UserService
+  ../models/user.go (in user.go) -> https://graph.mycorp.com/repo/models/user.go
+
+## Linked Modules
+- [builder](./builder.go) - Graph builder
+
+## Tags
+graph, rdf, iri, uri
+
+## Exports
+IRIMapper, NewIRIMapper, Unbracket, LocalName
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#iri.go> a code:Module ;
+    code:name "pkg/graph/iri.go" ;
+    code:description "Base IRI mapping for generated triples" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./builder.go> ;
+    code:exports <#IRIMapper>, <#NewIRIMapper>, <#Unbracket>, <#LocalName> ;
+    code:tags "graph", "rdf", "iri", "uri" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IRIMapper rewrites fragment-only and relative URIs against a base IRI
+type IRIMapper struct {
+	base string
+}
+
+// NewIRIMapper creates a mapper for an absolute base IRI. A trailing slash
+// is added if missing, so module paths are appended as path segments.
+func NewIRIMapper(base string) (*IRIMapper, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base IRI %q: %w", base, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base IRI %q: must be absolute (e.g. https://graph.example.com/repo/)", base)
+	}
+	if u.Fragment != "" || u.RawQuery != "" {
+		return nil, fmt.Errorf("invalid base IRI %q: must not have a query or fragment", base)
+	}
+
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return &IRIMapper{base: base}, nil
+}
+
+// Base returns the base IRI, always ending in a slash
+func (m *IRIMapper) Base() string {
+	return m.base
+}
+
+// ModuleIRI returns the IRI for a module path relative to the project root
+func (m *IRIMapper) ModuleIRI(modulePath string) string {
+	return m.base + strings.TrimPrefix(filepath.ToSlash(modulePath), "./")
+}
+
+// Resolve rewrites a URI term found in modulePath's LinkedDoc header. Terms
+// in angle brackets keep their brackets. Absolute IRIs, blank nodes and
+// literals are returned unchanged, as are links that leave the project.
+func (m *IRIMapper) Resolve(term, modulePath string) string {
+	inner, bracketed := Unbracket(term)
+	modulePath = filepath.ToSlash(modulePath)
+
+	var resolved string
+	switch {
+	case strings.HasPrefix(inner, "#"):
+		fragment := inner[1:]
+		switch {
+		case fragment == modulePath || fragment == path.Base(modulePath):
+			resolved = m.ModuleIRI(modulePath)
+		case strings.Contains(fragment, "/"):
+			// Fragment holding a project path, e.g. <#services/user.go>
+			resolved = m.ModuleIRI(fragment)
+		default:
+			// Symbol defined by the module, e.g. #UserService
+			resolved = m.ModuleIRI(modulePath) + "#" + fragment
+		}
+	case strings.HasPrefix(inner, "./") || strings.HasPrefix(inner, "../"):
+		joined := path.Clean(path.Join(path.Dir(modulePath), inner))
+		if joined == ".." || strings.HasPrefix(joined, "../") {
+			return term
+		}
+		resolved = m.ModuleIRI(joined)
+	default:
+		return term
+	}
+
+	if bracketed {
+		return "<" + resolved + ">"
+	}
+	return resolved
+}
+
+// Rebase rewrites a term minted under oldBase to the mapper's base, for
+// moving existing data to a new base IRI. Other terms are returned unchanged.
+func (m *IRIMapper) Rebase(term, oldBase string) string {
+	if oldBase == "" {
+		return term
+	}
+	if !strings.HasSuffix(oldBase, "/") {
+		oldBase += "/"
+	}
+
+	inner, bracketed := Unbracket(term)
+	if !strings.HasPrefix(inner, oldBase) {
+		return term
+	}
+
+	resolved := m.base + strings.TrimPrefix(inner, oldBase)
+	if bracketed {
+		return "<" + resolved + ">"
+	}
+	return resolved
+}
+
+// Unbracket strips surrounding angle brackets from a URI term, reporting
+// whether it had them
+func Unbracket(term string) (string, bool) {
+	if strings.HasPrefix(term, "<") && strings.HasSuffix(term, ">") {
+		return term[1 : len(term)-1], true
+	}
+	return term, false
+}
+
+// LocalName returns the part of an IRI or prefixed name after its last '/',
+// '#' or ':'
+func LocalName(iri string) string {
+	iri, _ = Unbracket(iri)
+	if i := strings.LastIndexAny(iri, "/#:"); i >= 0 {
+		return iri[i+1:]
+	}
+	return iri
+}
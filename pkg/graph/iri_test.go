@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestNewIRIMapper(t *testing.T) {
+	m, err := NewIRIMapper("https://graph.example.com/repo")
+	if err != nil {
+		t.Fatalf("NewIRIMapper() error = %v", err)
+	}
+	if m.Base() != "https://graph.example.com/repo/" {
+		t.Errorf("Base() = %q, want trailing slash", m.Base())
+	}
+
+	for _, base := range []string{"", "graph.example.com/repo", "/repo/", "https://graph.example.com/repo#x"} {
+		if _, err := NewIRIMapper(base); err == nil {
+			t.Errorf("NewIRIMapper(%q) expected error", base)
+		}
+	}
+}
+
+func TestIRIMapper_Resolve(t *testing.T) {
+	m, _ := NewIRIMapper("https://graph.example.com/repo/")
+
+	tests := []struct {
+		term string
+		want string
+	}{
+		{"<#services/user.go>", "<https://graph.example.com/repo/services/user.go>"},
+		{"<#user.go>", "<https://graph.example.com/repo/services/user.go>"},
+		{"#UserService", "https://graph.example.com/repo/services/user.go#UserService"},
+		{"./auth.go", "https://graph.example.com/repo/services/auth.go"},
+		{"../models/user.go", "https://graph.example.com/repo/models/user.go"},
+		{"../../outside.go", "../../outside.go"},
+		{"https://schema.codedoc.org/Module", "https://schema.codedoc.org/Module"},
+		{"_:b12", "_:b12"},
+	}
+
+	for _, tt := range tests {
+		if got := m.Resolve(tt.term, "services/user.go"); got != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestIRIMapper_Rebase(t *testing.T) {
+	m, _ := NewIRIMapper("https://new.example.com/repo/")
+
+	if got := m.Rebase("<https://old.example.com/repo/a.go>", "https://old.example.com/repo"); got != "<https://new.example.com/repo/a.go>" {
+		t.Errorf("Rebase() = %q", got)
+	}
+	if got := m.Rebase("https://other.example.com/a.go", "https://old.example.com/repo/"); got != "https://other.example.com/a.go" {
+		t.Errorf("Rebase() should leave other IRIs unchanged, got %q", got)
+	}
+}
+
+func TestLocalName(t *testing.T) {
+	tests := map[string]string{
+		"https://schema.codedoc.org/owner": "owner",
+		"<https://example.com/ns#team>":    "team",
+		"code:coverage":                    "coverage",
+		"layer":                            "layer",
+	}
+	for iri, want := range tests {
+		if got := LocalName(iri); got != want {
+			t.Errorf("LocalName(%q) = %q, want %q", iri, got, want)
+		}
+	}
+}
+
+func TestBuild_BaseIRI(t *testing.T) {
+	absPath, err := filepath.Abs("../../examples/minimal-app")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+
+	g, err := NewBuilder().Build(absPath, BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+		BaseIRI:     "https://graph.example.com/minimal-app/",
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	user := g.Modules["services/user.go"]
+	if user == nil {
+		t.Fatal("services/user.go module not found")
+	}
+	if user.URI != "<https://graph.example.com/minimal-app/services/user.go>" {
+		t.Errorf("URI = %q, want base IRI", user.URI)
+	}
+
+	// Dependencies stay project-relative paths for analysis
+	if len(user.Dependencies) == 0 || user.Dependencies[0] != "services/auth.go" {
+		t.Errorf("Dependencies = %v, want resolved paths", user.Dependencies)
+	}
+
+	links := g.Store.Find(user.URI, "https://schema.codedoc.org/linksTo", "")
+	if len(links) == 0 {
+		t.Fatal("Expected linksTo triples for module IRI")
+	}
+	for _, triple := range links {
+		if !strings.HasPrefix(triple.Object, "https://graph.example.com/") {
+			t.Errorf("linksTo object %q not rewritten", triple.Object)
+		}
+	}
+
+	if _, err := NewBuilder().Build(absPath, BuildOptions{BaseIRI: "not-an-iri"}); err == nil {
+		t.Error("Expected error for invalid base IRI")
+	}
+}
@@ -0,0 +1,204 @@
+/*
+# Module: pkg/graph/languages.go
+Language statistics and polyglot breakdown.
+
+Counts modules per language, finds dependency edges that cross from one
+language to another, and marks boundary modules: modules that depend on a
+module written in a different language. Cross-language edges and boundaries
+are added to the triple store so they can be queried like any other metadata.
+
+## Linked Modules
+- [graph](./graph.go) - Graph data structure
+- [module](./module.go) - Module data structure
+
+## Tags
+graph, languages, polyglot, statistics
+
+## Exports
+CrossLanguageDependencyPredicate, LanguageBoundaryPredicate, LanguageBreakdown, LanguageStats, CrossLanguageEdge, BoundaryModule, Graph.AnalyzeLanguages, Graph.AddLanguageTriples
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#languages.go> a code:Module ;
+    code:name "pkg/graph/languages.go" ;
+    code:description "Language statistics and polyglot breakdown" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./graph.go>, <./module.go> ;
+    code:exports <#CrossLanguageDependencyPredicate>, <#LanguageBoundaryPredicate>, <#LanguageBreakdown>,
+                 <#LanguageStats>, <#CrossLanguageEdge>, <#BoundaryModule>,
+                 <#Graph.AnalyzeLanguages>, <#Graph.AddLanguageTriples> ;
+    code:tags "graph", "languages", "polyglot", "statistics" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import "sort"
+
+const (
+	// CrossLanguageDependencyPredicate links a module to a dependency written
+	// in another language
+	CrossLanguageDependencyPredicate = "https://schema.codedoc.org/crossLanguageDependency"
+
+	// LanguageBoundaryPredicate holds each other language a boundary module
+	// depends on
+	LanguageBoundaryPredicate = "https://schema.codedoc.org/languageBoundary"
+)
+
+// unknownLanguage groups modules without code:language
+const unknownLanguage = "unknown"
+
+// LanguageBreakdown summarizes the languages of a project
+type LanguageBreakdown struct {
+	Languages  []LanguageStats     `json:"languages"`
+	Edges      []CrossLanguageEdge `json:"cross_language_edges"`
+	Boundaries []BoundaryModule    `json:"boundary_modules"`
+}
+
+// LanguageStats holds module and dependency counts for one language
+type LanguageStats struct {
+	Language     string  `json:"language"`
+	Modules      int     `json:"modules"`
+	Percent      float64 `json:"percent"`
+	Dependencies int     `json:"dependencies"` // Dependencies of its modules
+	CrossOut     int     `json:"cross_out"`    // Dependencies on modules in other languages
+	CrossIn      int     `json:"cross_in"`     // Dependencies on its modules from other languages
+	Boundaries   int     `json:"boundaries"`   // Its modules depending on other languages
+}
+
+// CrossLanguageEdge is a dependency between modules in different languages
+type CrossLanguageEdge struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	FromLanguage string `json:"from_language"`
+	ToLanguage   string `json:"to_language"`
+}
+
+// BoundaryModule is a module depending on modules in other languages
+type BoundaryModule struct {
+	Path      string   `json:"path"`
+	Language  string   `json:"language"`
+	Calls     []string `json:"calls"` // Other languages, sorted
+	EdgeCount int      `json:"edge_count"`
+}
+
+// IsPolyglot reports whether the project has modules in more than one known
+// language
+func (b *LanguageBreakdown) IsPolyglot() bool {
+	known := 0
+	for _, stats := range b.Languages {
+		if stats.Language != unknownLanguage {
+			known++
+		}
+	}
+	return known > 1
+}
+
+// AnalyzeLanguages computes the language breakdown of the graph. Modules
+// without a language are counted as "unknown" and never form cross-language
+// edges.
+func (g *Graph) AnalyzeLanguages() *LanguageBreakdown {
+	byLanguage := make(map[string]*LanguageStats)
+	stats := func(language string) *LanguageStats {
+		if byLanguage[language] == nil {
+			byLanguage[language] = &LanguageStats{Language: language}
+		}
+		return byLanguage[language]
+	}
+
+	paths := make([]string, 0, len(g.Modules))
+	for p := range g.Modules {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	breakdown := &LanguageBreakdown{}
+	for _, p := range paths {
+		module := g.Modules[p]
+		from := moduleLanguage(module)
+		fromStats := stats(from)
+		fromStats.Modules++
+
+		called := make(map[string]bool)
+		edges := 0
+		for _, dep := range module.Dependencies {
+			fromStats.Dependencies++
+			target := g.Modules[dep]
+			if target == nil || from == unknownLanguage {
+				continue
+			}
+			to := moduleLanguage(target)
+			if to == unknownLanguage || to == from {
+				continue
+			}
+
+			breakdown.Edges = append(breakdown.Edges, CrossLanguageEdge{
+				From: p, To: dep, FromLanguage: from, ToLanguage: to,
+			})
+			fromStats.CrossOut++
+			stats(to).CrossIn++
+			called[to] = true
+			edges++
+		}
+
+		if len(called) > 0 {
+			boundary := BoundaryModule{Path: p, Language: from, EdgeCount: edges}
+			for language := range called {
+				boundary.Calls = append(boundary.Calls, language)
+			}
+			sort.Strings(boundary.Calls)
+			breakdown.Boundaries = append(breakdown.Boundaries, boundary)
+			fromStats.Boundaries++
+		}
+	}
+
+	for _, s := range byLanguage {
+		if len(g.Modules) > 0 {
+			s.Percent = float64(s.Modules) / float64(len(g.Modules)) * 100
+		}
+		breakdown.Languages = append(breakdown.Languages, *s)
+	}
+	sort.Slice(breakdown.Languages, func(i, j int) bool {
+		a, b := breakdown.Languages[i], breakdown.Languages[j]
+		if a.Modules != b.Modules {
+			return a.Modules > b.Modules
+		}
+		return a.Language < b.Language
+	})
+
+	return breakdown
+}
+
+// AddLanguageTriples records cross-language edges and boundary languages in
+// the triple store and returns the number of triples added
+func (g *Graph) AddLanguageTriples(b *LanguageBreakdown) int {
+	if g.Store == nil {
+		return 0
+	}
+
+	before := g.Store.Count()
+	for _, edge := range b.Edges {
+		from, to := g.Modules[edge.From], g.Modules[edge.To]
+		object, _ := Unbracket(to.URI)
+		// Only fails for empty terms, which module URIs never are
+		_ = g.Store.Add(from.URI, CrossLanguageDependencyPredicate, object)
+	}
+	for _, boundary := range b.Boundaries {
+		module := g.Modules[boundary.Path]
+		for _, language := range boundary.Calls {
+			_ = g.Store.Add(module.URI, LanguageBoundaryPredicate, language)
+		}
+	}
+	return g.Store.Count() - before
+}
+
+// moduleLanguage returns the module's language, or "unknown"
+func moduleLanguage(m *Module) string {
+	if m.Language == "" {
+		return unknownLanguage
+	}
+	return m.Language
+}
@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+func TestGraph_AnalyzeLanguages(t *testing.T) {
+	g := NewGraph("/test/root", store.NewTripleStore())
+
+	add := func(path, language string, deps ...string) {
+		module := NewModule(path, "<#"+path+">")
+		module.Language = language
+		for _, dep := range deps {
+			module.AddDependency(dep)
+		}
+		g.AddModule(module)
+	}
+
+	add("api/server.go", "go", "api/handler.go", "ml/model.py", "web/app.ts")
+	add("api/handler.go", "go", "ml/model.py")
+	add("ml/model.py", "python", "ml/features.py")
+	add("ml/features.py", "python")
+	add("web/app.ts", "typescript", "api/server.go")
+	add("README.md", "")
+
+	breakdown := g.AnalyzeLanguages()
+
+	if !breakdown.IsPolyglot() {
+		t.Error("Expected a polyglot project")
+	}
+	if len(breakdown.Languages) != 4 {
+		t.Fatalf("Expected 4 languages (including unknown), got %+v", breakdown.Languages)
+	}
+	if goStats := breakdown.Languages[0]; goStats.Language != "go" || goStats.Modules != 2 ||
+		goStats.CrossOut != 3 || goStats.CrossIn != 1 || goStats.Boundaries != 2 {
+		t.Errorf("Unexpected go stats: %+v", goStats)
+	}
+	if len(breakdown.Edges) != 4 {
+		t.Errorf("Expected 4 cross-language edges, got %+v", breakdown.Edges)
+	}
+
+	if len(breakdown.Boundaries) != 3 {
+		t.Fatalf("Expected 3 boundary modules, got %+v", breakdown.Boundaries)
+	}
+	server := breakdown.Boundaries[1]
+	if server.Path != "api/server.go" || server.EdgeCount != 2 ||
+		len(server.Calls) != 2 || server.Calls[0] != "python" || server.Calls[1] != "typescript" {
+		t.Errorf("Unexpected boundary for api/server.go: %+v", server)
+	}
+
+	added := g.AddLanguageTriples(breakdown)
+	if added != 4+4 {
+		t.Errorf("Expected 8 triples, got %d", added)
+	}
+	triples := g.Store.Find("<#api/server.go>", LanguageBoundaryPredicate, "")
+	if len(triples) != 2 {
+		t.Errorf("Expected 2 languageBoundary triples for api/server.go, got %v", triples)
+	}
+}
+
+func TestGraph_AnalyzeLanguages_SingleLanguage(t *testing.T) {
+	g := NewGraph("/test/root", store.NewTripleStore())
+	a := NewModule("a.go", "<#a.go>")
+	a.Language = "go"
+	a.AddDependency("b.go")
+	b := NewModule("b.go", "<#b.go>")
+	b.Language = "go"
+	g.AddModule(a)
+	g.AddModule(b)
+
+	breakdown := g.AnalyzeLanguages()
+	if breakdown.IsPolyglot() || len(breakdown.Edges) != 0 || len(breakdown.Boundaries) != 0 {
+		t.Errorf("Expected no cross-language edges, got %+v", breakdown)
+	}
+	if breakdown.Languages[0].Percent != 100 {
+		t.Errorf("Expected 100%% go, got %+v", breakdown.Languages[0])
+	}
+	if added := g.AddLanguageTriples(breakdown); added != 0 {
+		t.Errorf("Expected no triples, got %d", added)
+	}
+}
@@ -0,0 +1,351 @@
+/*
+# Module: pkg/graph/layers.go
+Heuristic layer inference for unannotated modules.
+
+Assigns a provisional layer to modules without code:layer, using three
+signals in order of confidence:
+
+ 1. Directory consensus: declared layers of other modules in the same directory
+ 2. Directory names: a path segment matching a layer used in the project, or a
+    conventional name such as "handlers" or "models"
+ 3. Import direction: the layers neighbours import from or are imported by
+
+Inferred layers are recorded with code:inferredLayer instead of code:layer, so
+rules can distinguish declared from guessed values.
+
+## Linked Modules
+- [graph](./graph.go) - Graph data structure
+- [module](./module.go) - Module data structure
+
+## Tags
+graph, layers, inference, architecture
+
+## Exports
+InferredLayerPredicate, LayerInference, Graph.InferLayers, Module.HasInferredLayer
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#layers.go> a code:Module ;
+    code:name "pkg/graph/layers.go" ;
+    code:description "Heuristic layer inference for unannotated modules" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./graph.go>, <./module.go> ;
+    code:exports <#InferredLayerPredicate>, <#LayerInference>, <#Graph.InferLayers>, <#Module.HasInferredLayer> ;
+    code:tags "graph", "layers", "inference", "architecture" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// InferredLayerPredicate holds a provisional layer guessed for a module
+// without a declared code:layer
+const InferredLayerPredicate = "https://schema.codedoc.org/inferredLayer"
+
+// Inference reasons, from most to least confident
+const (
+	LayerReasonDirectory     = "directory"      // Siblings in the same directory declare it
+	LayerReasonDirectoryName = "directory-name" // A path segment names the layer
+	LayerReasonNeighbors     = "neighbors"      // Import direction of neighbouring modules
+)
+
+// LayerInference records a provisional layer assigned to a module
+type LayerInference struct {
+	Path   string
+	Layer  string
+	Reason string
+}
+
+// conventionalLayers maps common directory names to layer names, used when
+// no layer declared in the project matches a directory
+var conventionalLayers = map[string]string{
+	"cmd":          "cli",
+	"cli":          "cli",
+	"api":          "api",
+	"handler":      "api",
+	"handlers":     "api",
+	"controller":   "api",
+	"controllers":  "api",
+	"routes":       "api",
+	"server":       "api",
+	"service":      "service",
+	"services":     "service",
+	"usecase":      "service",
+	"usecases":     "service",
+	"domain":       "domain",
+	"model":        "model",
+	"models":       "model",
+	"entity":       "model",
+	"entities":     "model",
+	"repository":   "data",
+	"repositories": "data",
+	"store":        "data",
+	"storage":      "data",
+	"db":           "data",
+	"dao":          "data",
+	"util":         "utility",
+	"utils":        "utility",
+	"helpers":      "utility",
+	"common":       "utility",
+	"ui":           "ui",
+	"views":        "ui",
+	"components":   "ui",
+	"pages":        "ui",
+	"config":       "config",
+}
+
+// InferLayers assigns a provisional layer to every module without one and
+// returns the assignments ordered by path. Each module's Layer is set, the
+// InferredLayerPredicate property and triple are added, and layer statistics
+// are updated. Modules no signal applies to are left without a layer.
+func (g *Graph) InferLayers() []LayerInference {
+	declared := make(map[string]string)
+	var pending []string
+	for p, module := range g.Modules {
+		if module.Layer != "" {
+			declared[p] = module.Layer
+		} else {
+			pending = append(pending, p)
+		}
+	}
+	sort.Strings(pending)
+	if len(pending) == 0 || len(declared) == 0 {
+		return nil
+	}
+
+	var inferences []LayerInference
+	layers := make(map[string]string, len(declared))
+	for p, layer := range declared {
+		layers[p] = layer
+	}
+
+	// Directory signals only look at declared layers
+	var unresolved []string
+	for _, p := range pending {
+		if layer := g.directoryConsensus(p, declared); layer != "" {
+			inferences = append(inferences, LayerInference{Path: p, Layer: layer, Reason: LayerReasonDirectory})
+		} else if layer := directoryNameLayer(p, declared); layer != "" {
+			inferences = append(inferences, LayerInference{Path: p, Layer: layer, Reason: LayerReasonDirectoryName})
+		} else {
+			unresolved = append(unresolved, p)
+			continue
+		}
+		layers[p] = inferences[len(inferences)-1].Layer
+	}
+
+	// Neighbour signals propagate through inferred layers until stable
+	imports := g.layerImports(declared)
+	for len(unresolved) > 0 {
+		var assigned []LayerInference
+		var remaining []string
+		for _, p := range unresolved {
+			if layer := g.neighborLayer(p, layers, imports); layer != "" {
+				assigned = append(assigned, LayerInference{Path: p, Layer: layer, Reason: LayerReasonNeighbors})
+			} else {
+				remaining = append(remaining, p)
+			}
+		}
+		if len(assigned) == 0 {
+			break
+		}
+		for _, inference := range assigned {
+			layers[inference.Path] = inference.Layer
+		}
+		inferences = append(inferences, assigned...)
+		unresolved = remaining
+	}
+
+	sort.Slice(inferences, func(i, j int) bool {
+		return inferences[i].Path < inferences[j].Path
+	})
+	for _, inference := range inferences {
+		g.applyInferredLayer(inference)
+	}
+	return inferences
+}
+
+// HasInferredLayer reports whether the module's layer was guessed by
+// InferLayers rather than declared
+func (m *Module) HasInferredLayer() bool {
+	return len(m.Properties[InferredLayerPredicate]) > 0
+}
+
+// applyInferredLayer records an inference on its module
+func (g *Graph) applyInferredLayer(inference LayerInference) {
+	module := g.Modules[inference.Path]
+	module.Layer = inference.Layer
+	if module.Properties == nil {
+		module.Properties = make(map[string][]string)
+	}
+	module.AddProperty(InferredLayerPredicate, inference.Layer)
+	g.Statistics.ModulesByLayer[inference.Layer]++
+
+	if g.Store != nil {
+		// Only fails for empty terms, which module URIs and layers never are
+		_ = g.Store.Add(module.URI, InferredLayerPredicate, inference.Layer)
+	}
+}
+
+// directoryConsensus returns the layer declared by a strict majority of the
+// other modules in p's directory
+func (g *Graph) directoryConsensus(p string, declared map[string]string) string {
+	dir := path.Dir(p)
+	votes := make(map[string]int)
+	total := 0
+	for other, layer := range declared {
+		if path.Dir(other) == dir {
+			votes[layer]++
+			total++
+		}
+	}
+
+	layer, count := topVote(votes)
+	if count*2 > total {
+		return layer
+	}
+	return ""
+}
+
+// directoryNameLayer matches p's directories, innermost first, against the
+// project's declared layers and then conventional directory names
+func directoryNameLayer(p string, declared map[string]string) string {
+	vocabulary := make(map[string]bool)
+	for _, layer := range declared {
+		vocabulary[layer] = true
+	}
+	known := make([]string, 0, len(vocabulary))
+	for layer := range vocabulary {
+		known = append(known, layer)
+	}
+	sort.Strings(known)
+
+	segments := strings.Split(path.Dir(p), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := strings.ToLower(segments[i])
+		if segment == "." || segment == "" {
+			continue
+		}
+		for _, layer := range known {
+			if layerNameMatches(segment, layer) {
+				return layer
+			}
+		}
+		if layer, ok := conventionalLayers[segment]; ok {
+			// Prefer the project's spelling of a conventional layer
+			for _, known := range known {
+				if layerNameMatches(layer, known) {
+					return known
+				}
+			}
+			return layer
+		}
+	}
+	return ""
+}
+
+// layerNameMatches compares a directory name with a layer name, ignoring
+// case and plurals and allowing abbreviations such as "utils" for "utility"
+func layerNameMatches(dir, layer string) bool {
+	dir = strings.TrimSuffix(strings.ToLower(dir), "s")
+	layer = strings.TrimSuffix(strings.ToLower(layer), "s")
+	if dir == layer {
+		return true
+	}
+	if len(dir) < 3 || len(layer) < 3 {
+		return false
+	}
+	return strings.HasPrefix(layer, dir) || strings.HasPrefix(dir, layer)
+}
+
+// layerImports counts declared dependencies between layers, as
+// imports[from][to]
+func (g *Graph) layerImports(declared map[string]string) map[string]map[string]int {
+	imports := make(map[string]map[string]int)
+	for p, from := range declared {
+		for _, dep := range g.Modules[p].Dependencies {
+			to, ok := declared[dep]
+			if !ok {
+				continue
+			}
+			if imports[from] == nil {
+				imports[from] = make(map[string]int)
+			}
+			imports[from][to]++
+		}
+	}
+	return imports
+}
+
+// neighborLayer scores layers from p's neighbours. A module imported by
+// layer A likely belongs to a layer A imports; a module importing layer C
+// likely belongs to a layer that imports C. Without declared imports between
+// layers, neighbours' own layers are used.
+func (g *Graph) neighborLayer(p string, layers map[string]string, imports map[string]map[string]int) string {
+	module := g.Modules[p]
+	scores := make(map[string]float64)
+
+	for _, dependent := range module.Dependents {
+		from, ok := layers[dependent]
+		if !ok {
+			continue
+		}
+		addWeighted(scores, imports[from], from)
+	}
+
+	for _, dep := range module.Dependencies {
+		to, ok := layers[dep]
+		if !ok {
+			continue
+		}
+		importers := make(map[string]int)
+		for from, targets := range imports {
+			if targets[to] > 0 {
+				importers[from] = targets[to]
+			}
+		}
+		addWeighted(scores, importers, to)
+	}
+
+	best, bestScore := "", 0.0
+	for layer, score := range scores {
+		if score > bestScore || (score == bestScore && layer < best) {
+			best, bestScore = layer, score
+		}
+	}
+	return best
+}
+
+// addWeighted adds one vote spread over counts, or for fallback if counts
+// is empty
+func addWeighted(scores map[string]float64, counts map[string]int, fallback string) {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		scores[fallback]++
+		return
+	}
+	for layer, count := range counts {
+		scores[layer] += float64(count) / float64(total)
+	}
+}
+
+// topVote returns the layer with the most votes, breaking ties by name
+func topVote(votes map[string]int) (string, int) {
+	best, bestCount := "", 0
+	for layer, count := range votes {
+		if count > bestCount || (count == bestCount && layer < best) {
+			best, bestCount = layer, count
+		}
+	}
+	return best, bestCount
+}
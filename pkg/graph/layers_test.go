@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+func TestGraph_InferLayers(t *testing.T) {
+	g := NewGraph("/test/root", store.NewTripleStore())
+
+	add := func(path, layer string, deps ...string) {
+		module := NewModule(path, "<#"+path+">")
+		module.Layer = layer
+		for _, dep := range deps {
+			module.AddDependency(dep)
+		}
+		g.AddModule(module)
+	}
+
+	add("services/user.go", "service", "models/user.go")
+	add("services/auth.go", "", "models/user.go")            // sibling declares service
+	add("models/user.go", "model")                           // declared
+	add("models/account.go", "")                             // sibling declares model
+	add("utils/strings.go", "")                              // conventional directory name
+	add("api/router.go", "transport", "internal/session.go") // declared
+	add("internal/session.go", "", "services/user.go")       // imported by transport, imports service
+	add("main.go", "")                                       // no signal
+
+	NewBuilder().buildDependencyGraph(g)
+	inferences := g.InferLayers()
+
+	want := map[string]struct{ layer, reason string }{
+		"services/auth.go":    {"service", LayerReasonDirectory},
+		"models/account.go":   {"model", LayerReasonDirectory},
+		"utils/strings.go":    {"utility", LayerReasonDirectoryName},
+		"internal/session.go": {"service", LayerReasonNeighbors},
+	}
+	if len(inferences) != len(want) {
+		t.Fatalf("Expected %d inferences, got %v", len(want), inferences)
+	}
+	for _, inference := range inferences {
+		expected, ok := want[inference.Path]
+		if !ok {
+			t.Errorf("Unexpected inference for %s", inference.Path)
+			continue
+		}
+		if inference.Layer != expected.layer || inference.Reason != expected.reason {
+			t.Errorf("%s: got %s (%s), want %s (%s)", inference.Path,
+				inference.Layer, inference.Reason, expected.layer, expected.reason)
+		}
+		module := g.Modules[inference.Path]
+		if module.Layer != expected.layer || !module.HasInferredLayer() {
+			t.Errorf("%s: inference not applied to module", inference.Path)
+		}
+		if len(g.Store.Find(module.URI, InferredLayerPredicate, expected.layer)) != 1 {
+			t.Errorf("%s: missing inferredLayer triple", inference.Path)
+		}
+		if len(g.Store.Find(module.URI, "https://schema.codedoc.org/layer", "")) != 0 {
+			t.Errorf("%s: inferred layer must not be stored as code:layer", inference.Path)
+		}
+	}
+
+	if g.Modules["main.go"].Layer != "" {
+		t.Errorf("main.go: expected no layer, got %q", g.Modules["main.go"].Layer)
+	}
+	if g.Modules["services/user.go"].HasInferredLayer() {
+		t.Error("Declared layer marked as inferred")
+	}
+}
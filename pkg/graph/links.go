@@ -0,0 +1,179 @@
+/*
+# Module: pkg/graph/links.go
+Unresolved link detection with suggestions.
+
+The builder keeps a declared dependency whose target is not a module, but
+no dependent edge is recorded for it, so the link silently drops out of
+impact analysis and visualizations. UnresolvedLinks reports those links
+with the module paths they most likely meant: the closest file names
+first, then the closest full paths. A suggestion with a closer file name
+than the rest is offered as the fix.
+
+## Linked Modules
+- [graph](./graph.go) - Graph data structure
+- [edges](./edges.go) - Dependency edge metadata
+- [builder](./builder.go) - Graph builder
+
+## Tags
+graph, dependencies, links, validation
+
+## Exports
+UnresolvedLink, Graph.UnresolvedLinks, EditDistance
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#links.go> a code:Module ;
+    code:name "pkg/graph/links.go" ;
+    code:description "Unresolved link detection with suggestions" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./graph.go>, <./edges.go>, <./builder.go> ;
+    code:exports <#UnresolvedLink>, <#Graph.UnresolvedLinks>, <#EditDistance> ;
+    code:tags "graph", "dependencies", "links", "validation" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is how many suggestions an unresolved link lists
+const maxSuggestions = 3
+
+// UnresolvedLink is a declared dependency whose target is neither a module
+// nor an existing file or directory
+type UnresolvedLink struct {
+	Module      string   `json:"module"`   // Declaring module
+	Target      string   `json:"target"`   // Target as resolved against the module
+	Relation    string   `json:"relation"` // linksTo, imports, ...
+	Suggestions []string `json:"suggestions,omitempty"`
+	Fix         string   `json:"fix,omitempty"` // Suggestion with the strictly closest file name, if any
+}
+
+// UnresolvedLinks returns the declared dependencies of every module that
+// don't resolve, sorted by module and target. External IRIs are skipped.
+func (g *Graph) UnresolvedLinks() []UnresolvedLink {
+	paths := make([]string, 0, len(g.Modules))
+	resolvable := make(map[string]bool, 2*len(g.Modules))
+	for modulePath, module := range g.Modules {
+		paths = append(paths, modulePath)
+		resolvable[module.URI] = true
+		resolvable[module.Name] = true
+	}
+	sort.Strings(paths)
+
+	var links []UnresolvedLink
+	for _, modulePath := range paths {
+		module := g.Modules[modulePath]
+		for _, dep := range module.Dependencies {
+			edge := module.EdgeTo(dep)
+			if edge.Inferred || strings.Contains(dep, "://") || g.resolves(dep, resolvable) {
+				continue
+			}
+
+			link := UnresolvedLink{Module: modulePath, Target: dep, Relation: edge.Relation}
+			link.Suggestions, link.Fix = suggestPaths(dep, paths)
+			links = append(links, link)
+		}
+	}
+
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].Module != links[j].Module {
+			return links[i].Module < links[j].Module
+		}
+		return links[i].Target < links[j].Target
+	})
+	return links
+}
+
+// resolves reports whether a dependency names a module, the way the builder
+// matches dependents, or an existing file or directory
+func (g *Graph) resolves(dep string, resolvable map[string]bool) bool {
+	if g.GetModule(dep) != nil || resolvable[dep] {
+		return true
+	}
+	for modulePath := range g.Modules {
+		if strings.HasSuffix(modulePath, dep) {
+			return true
+		}
+	}
+	_, err := os.Stat(filepath.Join(g.Root, filepath.FromSlash(dep)))
+	return err == nil
+}
+
+// suggestPaths returns the module paths closest to target and the fix: the
+// only candidate, or the one whose file name is strictly closest. Candidates
+// must have a file name within a quarter of its length in edits (at least
+// two); they rank by file name distance, then full path distance.
+func suggestPaths(target string, paths []string) ([]string, string) {
+	type candidate struct {
+		path     string
+		baseDist int
+		pathDist int
+	}
+
+	base := path.Base(target)
+	maxBaseDist := max(2, len(base)/4)
+
+	var candidates []candidate
+	for _, modulePath := range paths {
+		baseDist := EditDistance(base, path.Base(modulePath))
+		if baseDist > maxBaseDist {
+			continue
+		}
+		candidates = append(candidates, candidate{modulePath, baseDist, EditDistance(target, modulePath)})
+	}
+	if len(candidates) == 0 {
+		return nil, ""
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].baseDist != candidates[j].baseDist {
+			return candidates[i].baseDist < candidates[j].baseDist
+		}
+		if candidates[i].pathDist != candidates[j].pathDist {
+			return candidates[i].pathDist < candidates[j].pathDist
+		}
+		return candidates[i].path < candidates[j].path
+	})
+
+	suggestions := make([]string, 0, maxSuggestions)
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		suggestions = append(suggestions, c.path)
+	}
+
+	fix := ""
+	if len(candidates) == 1 || candidates[0].baseDist < candidates[1].baseDist {
+		fix = candidates[0].path
+	}
+	return suggestions, fix
+}
+
+// editDistance is the Levenshtein distance between a and b
+func EditDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
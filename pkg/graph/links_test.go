@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+func TestGraph_UnresolvedLinks(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("# App\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGraph(root, store.NewTripleStore())
+	for _, path := range []string{"services/auth.go", "services/user.go", "api/user.go", "utils/log.go"} {
+		g.AddModule(NewModule(path, "<#"+filepath.Base(path)+">"))
+	}
+	handler := NewModule("api/handler.go", "<#handler.go>")
+	handler.AddEdge(Edge{Target: "services/auht.go"})                    // Typo
+	handler.AddEdge(Edge{Target: "pkg/user.go", Relation: RelationUses}) // Ambiguous
+	handler.AddEdge(Edge{Target: "services/billing.go"})                 // Nothing close
+	handler.AddEdge(Edge{Target: "utils/log.go"})                        // Resolves
+	handler.AddEdge(Edge{Target: "README.md"})                           // Existing file
+	handler.AddEdge(Edge{Target: "https://example.com/spec"})            // External
+	handler.AddEdge(Edge{Target: "generated/client.go", Inferred: true}) // Not declared
+	g.AddModule(handler)
+
+	want := []UnresolvedLink{
+		{Module: "api/handler.go", Target: "pkg/user.go", Relation: RelationUses, Suggestions: []string{"api/user.go", "services/user.go"}},
+		{Module: "api/handler.go", Target: "services/auht.go", Relation: RelationLinksTo, Suggestions: []string{"services/auth.go"}, Fix: "services/auth.go"},
+		{Module: "api/handler.go", Target: "services/billing.go", Relation: RelationLinksTo},
+	}
+	if got := g.UnresolvedLinks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnresolvedLinks() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSuggestPaths(t *testing.T) {
+	paths := []string{"web/api.ts", "web/app.ts", "web/lib/app.ts"}
+
+	// A strictly closer file name is the fix
+	suggestions, fix := suggestPaths("web/app.js", paths[:2])
+	if !reflect.DeepEqual(suggestions, []string{"web/app.ts", "web/api.ts"}) || fix != "web/app.ts" {
+		t.Errorf("suggestPaths() = %v, %q", suggestions, fix)
+	}
+
+	// Equally close file names are only suggestions, nearest path first
+	suggestions, fix = suggestPaths("web/app.js", paths)
+	if !reflect.DeepEqual(suggestions, []string{"web/app.ts", "web/lib/app.ts", "web/api.ts"}) || fix != "" {
+		t.Errorf("suggestPaths() = %v, %q", suggestions, fix)
+	}
+}
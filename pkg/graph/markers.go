@@ -0,0 +1,108 @@
+/*
+# Module: pkg/graph/markers.go
+TODO, FIXME and DEPRECATED comment markers.
+
+Finds comments that start with TODO, FIXME or DEPRECATED (including Go's
+"Deprecated:" convention) in a module's source and records each on the
+module with its line number and text:
+
+  <#main.go> code:todo "42: handle retries" ;
+      code:fixme "57: leaks a connection" .
+
+## Linked Modules
+- [module](./module.go) - Module data structure
+- [builder](./builder.go) - Graph builder
+
+## Tags
+graph, markers, technical-debt
+
+## Exports
+Marker, ScanMarkers, MarkerTODO, MarkerFIXME, MarkerDeprecated, TODOPredicate, FIXMEPredicate, DeprecatedPredicate
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#markers.go> a code:Module ;
+    code:name "pkg/graph/markers.go" ;
+    code:description "TODO, FIXME and DEPRECATED comment markers" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./module.go>, <./builder.go> ;
+    code:exports <#Marker>, <#ScanMarkers>, <#MarkerTODO>, <#MarkerFIXME>, <#MarkerDeprecated>,
+                 <#TODOPredicate>, <#FIXMEPredicate>, <#DeprecatedPredicate> ;
+    code:tags "graph", "markers", "technical-debt" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Marker kinds
+const (
+	MarkerTODO       = "TODO"
+	MarkerFIXME      = "FIXME"
+	MarkerDeprecated = "DEPRECATED"
+)
+
+// Marker predicates, with "<line>: <text>" values
+const (
+	TODOPredicate       = "https://schema.codedoc.org/todo"
+	FIXMEPredicate      = "https://schema.codedoc.org/fixme"
+	DeprecatedPredicate = "https://schema.codedoc.org/deprecated"
+)
+
+// Marker is a TODO, FIXME or DEPRECATED comment
+type Marker struct {
+	Kind string `json:"kind"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// markerPattern matches a marker as the first word of a comment, so prose
+// that merely mentions TODO is not a marker
+var markerPattern = regexp.MustCompile(`(?://+|#+|/\*+|^\s*\*+|--|<!--|;+)\s*(TODO|FIXME|DEPRECATED|Deprecated)\b(.*)`)
+
+// ScanMarkers returns the markers in source, in line order
+func ScanMarkers(source string) []Marker {
+	var markers []Marker
+	for i, line := range strings.Split(source, "\n") {
+		match := markerPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		text := strings.TrimSpace(match[2])
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(text, "-->"), "*/"))
+		text = strings.TrimSpace(strings.TrimLeft(text, ":-"))
+		markers = append(markers, Marker{
+			Kind: strings.ToUpper(match[1]),
+			Line: i + 1,
+			Text: text,
+		})
+	}
+	return markers
+}
+
+// Predicate returns the predicate recording the marker
+func (m Marker) Predicate() string {
+	switch m.Kind {
+	case MarkerFIXME:
+		return FIXMEPredicate
+	case MarkerDeprecated:
+		return DeprecatedPredicate
+	}
+	return TODOPredicate
+}
+
+// Value returns the marker's triple value, "<line>: <text>"
+func (m Marker) Value() string {
+	if m.Text == "" {
+		return strconv.Itoa(m.Line)
+	}
+	return strconv.Itoa(m.Line) + ": " + m.Text
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestScanMarkers(t *testing.T) {
+	source := `package app
+
+// TODO: handle retries
+func Send() {} // FIXME - leaks a connection
+
+// Deprecated: use SendAll instead.
+func Old() {}
+
+/* TODO */
+# DEPRECATED remove in v2
+<!-- TODO: document flags -->
+// This mentions TODO in prose and is not a marker
+var s = "TODO: not a comment"
+`
+	want := []Marker{
+		{Kind: MarkerTODO, Line: 3, Text: "handle retries"},
+		{Kind: MarkerFIXME, Line: 4, Text: "leaks a connection"},
+		{Kind: MarkerDeprecated, Line: 6, Text: "use SendAll instead."},
+		{Kind: MarkerTODO, Line: 9, Text: ""},
+		{Kind: MarkerDeprecated, Line: 10, Text: "remove in v2"},
+		{Kind: MarkerTODO, Line: 11, Text: "document flags"},
+	}
+
+	got := ScanMarkers(source)
+	if len(got) != len(want) {
+		t.Fatalf("ScanMarkers() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Marker %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBuild_Markers(t *testing.T) {
+	root := t.TempDir()
+	content := `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#app.go> a code:Module ;
+    code:name "app.go" .
+<!-- End LinkedDoc RDF -->
+*/
+package app
+
+// TODO: handle retries
+// FIXME: leaks a connection
+`
+	if err := os.WriteFile(filepath.Join(root, "app.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewBuilder().Build(root, BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	module := g.Modules["app.go"]
+	if module == nil || len(module.Markers) != 2 {
+		t.Fatalf("Expected 2 markers on app.go, got %+v", module)
+	}
+	if triples := g.Store.Find(module.URI, TODOPredicate, "10: handle retries"); len(triples) != 1 {
+		t.Error("Expected a code:todo triple with the line number")
+	}
+	if triples := g.Store.Find(module.URI, FIXMEPredicate, ""); len(triples) != 1 {
+		t.Error("Expected a code:fixme triple")
+	}
+}
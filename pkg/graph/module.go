@@ -11,7 +11,7 @@ Defines the Module type with metadata and relationship information.
 graph, module, data-structure
 
 ## Exports
-Module
+Module, GeneratedPredicate, ContainsPredicate
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -23,13 +23,29 @@ Module
     code:language "go" ;
     code:layer "graph" ;
     code:linksTo <./graph.go> ;
-    code:exports <#Module> ;
+    code:exports <#Module>, <#GeneratedPredicate>, <#ContainsPredicate> ;
     code:tags "graph", "module", "data-structure" .
 <!-- End LinkedDoc RDF -->
 */
 
 package graph
 
+// GeneratedPredicate marks modules whose source is generated code
+const GeneratedPredicate = "https://schema.codedoc.org/generated"
+
+// ContainsPredicate links a file's module to the components declared by
+// additional LinkedDoc blocks in the same file
+const ContainsPredicate = "https://schema.codedoc.org/contains"
+
+// PathAliasPredicate records another path of a module's file, such as a
+// symlink or hard link to it
+const PathAliasPredicate = "https://schema.codedoc.org/pathAlias"
+
+const (
+	typePredicate = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	moduleType    = "https://schema.codedoc.org/Module"
+)
+
 // Module represents a code module in the knowledge graph
 type Module struct {
 	// Identity
@@ -38,19 +54,29 @@ type Module struct {
 	Name        string // Display name
 	Description string // Module description
 
+	// Other paths of the same file (symlinks, hard links)
+	Aliases []string
+
 	// Metadata
 	Language string   // Programming language
 	Layer    string   // Architectural layer (e.g., "services", "utils")
 	Tags     []string // Tags for categorization
 
 	// Relationships
-	Dependencies []string // Modules this module depends on (linksTo)
+	Dependencies []string // Targets of DependencyEdges, in order
 	Dependents   []string // Modules that depend on this module (reverse linksTo)
 	Exports      []string // Exported symbols/functions
 	Calls        []string // Functions this module calls
+	Edges        []Edge   // Metadata of dependencies (see EdgeTo)
 
 	// Additional properties
 	Properties map[string][]string // Additional RDF properties
+
+	// Components declared by additional LinkedDoc blocks in the same file
+	Components []*Module
+
+	// TODO, FIXME and DEPRECATED comments in the file
+	Markers []Marker
 }
 
 // NewModule creates a new module
@@ -122,11 +148,46 @@ func (m *Module) AddTag(tag string) {
 	m.Tags = append(m.Tags, tag)
 }
 
+// AddComponent adds a component declared in the module's file. The
+// component's dependencies and exports also become the module's, so
+// file-level analyses see every edge in the file.
+func (m *Module) AddComponent(component *Module) {
+	m.Components = append(m.Components, component)
+	for _, edge := range component.DependencyEdges() {
+		m.AddEdge(edge)
+	}
+	for _, export := range component.Exports {
+		m.AddExport(export)
+	}
+}
+
 // AddProperty adds a property value
 func (m *Module) AddProperty(predicate, value string) {
 	m.Properties[predicate] = append(m.Properties[predicate], value)
 }
 
+// IsGenerated returns true if the module is marked as generated code
+func (m *Module) IsGenerated() bool {
+	for _, value := range m.Properties[GeneratedPredicate] {
+		if value == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInferred returns true if the module's metadata was extracted from its
+// source rather than declared in a LinkedDoc header. Such modules carry the
+// code:inferred predicate that also marks inferred edges.
+func (m *Module) IsInferred() bool {
+	for _, value := range m.Properties[EdgeInferredPredicate] {
+		if value == "true" {
+			return true
+		}
+	}
+	return false
+}
+
 // HasCircularDependency checks if adding a dependency would create a cycle
 func (m *Module) HasCircularDependency(target string, graph *Graph) bool {
 	return m.hasCircularDependencyRecursive(target, graph, make(map[string]bool))
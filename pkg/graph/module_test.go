@@ -87,6 +87,19 @@ func TestModule_AddProperty(t *testing.T) {
 	}
 }
 
+func TestModule_IsGenerated(t *testing.T) {
+	module := NewModule("gen.go", "<#gen.go>")
+	module.AddProperty("https://example.com/notGenerated", "true")
+	if module.IsGenerated() {
+		t.Error("Expected only the generated predicate to mark a module generated")
+	}
+
+	module.AddProperty(GeneratedPredicate, "true")
+	if !module.IsGenerated() {
+		t.Error("Expected module to be generated")
+	}
+}
+
 func TestModule_HasCircularDependency(t *testing.T) {
 	// Create a simple graph with circular dependency
 	graph := NewGraph("/test", nil)
@@ -0,0 +1,232 @@
+/*
+# Module: pkg/graph/owners.go
+Module ownership from CODEOWNERS and code:owner.
+
+Parses CODEOWNERS files (GitHub and GitLab syntax: gitignore-style patterns,
+the last matching rule wins) and records the owners of every module as
+code:ownedBy triples. Owners declared with code:owner or code:owners take
+precedence over CODEOWNERS, so headers can refine repository-wide rules.
+
+## Linked Modules
+- [graph](./graph.go) - Graph data structure
+- [module](./module.go) - Module data structure
+
+## Tags
+graph, ownership, codeowners
+
+## Exports
+OwnedByPredicate, CodeownersFiles, Codeowners, ParseCodeowners, LoadCodeowners, Graph.AssignOwners, Module.Owners
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#owners.go> a code:Module ;
+    code:name "pkg/graph/owners.go" ;
+    code:description "Module ownership from CODEOWNERS and code:owner" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./graph.go>, <./module.go> ;
+    code:exports <#OwnedByPredicate>, <#CodeownersFiles>, <#Codeowners>, <#ParseCodeowners>, <#LoadCodeowners>, <#Graph.AssignOwners>, <#Module.Owners> ;
+    code:tags "graph", "ownership", "codeowners" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OwnedByPredicate links a module to each of its owners
+const OwnedByPredicate = "https://schema.codedoc.org/ownedBy"
+
+// CodeownersFiles are the locations searched for a CODEOWNERS file, in
+// GitHub's order of precedence
+var CodeownersFiles = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Codeowners is a parsed CODEOWNERS file
+type Codeowners struct {
+	Path  string // File the rules were read from, relative to the root
+	rules []codeownersRule
+}
+
+type codeownersRule struct {
+	match  *regexp.Regexp
+	owners []string
+}
+
+// ParseCodeowners parses CODEOWNERS rules. Comments, blank lines and
+// GitLab section headers ("[Section]") are skipped; a pattern without owners
+// clears ownership of the files it matches.
+func ParseCodeowners(r io.Reader) (*Codeowners, error) {
+	co := &Codeowners{}
+	lines := bufio.NewScanner(r)
+	for number := 1; lines.Scan(); number++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		match, err := codeownersPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %w", number, fields[0], err)
+		}
+		co.rules = append(co.rules, codeownersRule{match: match, owners: fields[1:]})
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	return co, nil
+}
+
+// LoadCodeowners reads the first CODEOWNERS file found under root. It
+// returns nil without error when the project has none.
+func LoadCodeowners(root string) (*Codeowners, error) {
+	for _, name := range CodeownersFiles {
+		file, err := os.Open(filepath.Join(root, filepath.FromSlash(name)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		co, err := ParseCodeowners(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		co.Path = name
+		return co, nil
+	}
+	return nil, nil
+}
+
+// Owners returns the owners of a path relative to the root, from the last
+// matching rule
+func (co *Codeowners) Owners(p string) []string {
+	if co == nil {
+		return nil
+	}
+	p = strings.TrimPrefix(filepath.ToSlash(p), "./")
+	for i := len(co.rules) - 1; i >= 0; i-- {
+		if co.rules[i].match.MatchString(p) {
+			if len(co.rules[i].owners) == 0 {
+				return nil
+			}
+			return co.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// codeownersPattern compiles a gitignore-style pattern. Patterns with a
+// leading or inner slash are anchored to the root, others match at any
+// depth; a pattern matching a directory matches everything below it.
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "/**") && i+3 == len(trimmed):
+			sb.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			sb.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(trimmed[i])))
+		}
+	}
+	if dirOnly {
+		sb.WriteString("/.*$")
+	} else {
+		sb.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(sb.String())
+}
+
+// AssignOwners records the owners of every module as OwnedByPredicate
+// properties and triples, replacing earlier assignments. Declared code:owner
+// and code:owners values win over CODEOWNERS, which may be nil. Returns the
+// number of owned modules.
+func (g *Graph) AssignOwners(co *Codeowners) int {
+	if g.Store != nil {
+		_ = g.Store.Delete("", OwnedByPredicate, "")
+	}
+
+	owned := 0
+	for p, module := range g.Modules {
+		delete(module.Properties, OwnedByPredicate)
+		owners := module.declaredOwners()
+		if len(owners) == 0 {
+			owners = co.Owners(p)
+		}
+		if len(owners) == 0 {
+			continue
+		}
+		owned++
+		if module.Properties == nil {
+			module.Properties = make(map[string][]string)
+		}
+		for _, owner := range owners {
+			module.AddProperty(OwnedByPredicate, owner)
+			if g.Store != nil {
+				// Only fails for empty terms, which module URIs and owners never are
+				_ = g.Store.Add(module.URI, OwnedByPredicate, owner)
+			}
+		}
+	}
+	return owned
+}
+
+// Owners returns the module's owners as assigned by AssignOwners
+func (m *Module) Owners() []string {
+	return m.Properties[OwnedByPredicate]
+}
+
+// declaredOwners returns the distinct owners from code:owner and
+// code:owners, which may be comma-separated, sorted
+func (m *Module) declaredOwners() []string {
+	seen := make(map[string]bool)
+	var owners []string
+	for predicate, values := range m.Properties {
+		if name := predicate[strings.LastIndexAny(predicate, "/#:")+1:]; name != "owner" && name != "owners" {
+			continue
+		}
+		for _, value := range values {
+			for _, owner := range strings.Split(value, ",") {
+				if owner = strings.TrimSpace(owner); owner != "" && !seen[owner] {
+					seen[owner] = true
+					owners = append(owners, owner)
+				}
+			}
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+func TestCodeowners_Owners(t *testing.T) {
+	co, err := ParseCodeowners(strings.NewReader(`# Default owners
+*                   @acme/core
+*.md                @acme/docs   # any depth
+/services/          @acme/services
+services/billing/** @acme/billing @alice
+docs                @acme/docs
+
+[Frontend]
+web/*.ts            @acme/web
+web/generated.ts
+`))
+	if err != nil {
+		t.Fatalf("ParseCodeowners() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"@acme/core"}},
+		{"pkg/api/README.md", []string{"@acme/docs"}},
+		{"services/user.go", []string{"@acme/services"}},
+		{"services/billing/invoice/pdf.go", []string{"@acme/billing", "@alice"}},
+		{"internal/services/user.go", []string{"@acme/core"}}, // /services/ is anchored
+		{"pkg/docs/intro.txt", []string{"@acme/docs"}},        // docs matches at any depth
+		{"web/app.ts", []string{"@acme/web"}},
+		{"web/lib/app.ts", []string{"@acme/core"}}, // * stays within a directory
+		{"web/generated.ts", nil},                  // no owners clears ownership
+	}
+	for _, tt := range tests {
+		if got := co.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCodeowners(t *testing.T) {
+	root := t.TempDir()
+	if co, err := LoadCodeowners(root); co != nil || err != nil {
+		t.Fatalf("LoadCodeowners() without a file = %v, %v", co, err)
+	}
+
+	for _, name := range []string{"CODEOWNERS", ".github/CODEOWNERS"} {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("* @"+strings.Trim(name, "./")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	co, err := LoadCodeowners(root)
+	if err != nil {
+		t.Fatalf("LoadCodeowners() error = %v", err)
+	}
+	if co.Path != ".github/CODEOWNERS" || co.Owners("a.go")[0] != "@github/CODEOWNERS" {
+		t.Errorf("LoadCodeowners() read %s, owners %v; want .github/CODEOWNERS first", co.Path, co.Owners("a.go"))
+	}
+}
+
+func TestGraph_AssignOwners(t *testing.T) {
+	g := NewGraph("/test/root", store.NewTripleStore())
+	g.AddModule(NewModule("services/user.go", "<#user.go>"))
+	declared := NewModule("services/auth.go", "<#auth.go>")
+	declared.AddProperty("https://schema.codedoc.org/owner", "@bob, @carol")
+	g.AddModule(declared)
+	g.AddModule(NewModule("main.go", "<#main.go>"))
+
+	co, err := ParseCodeowners(strings.NewReader("services/ @acme/services\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if owned := g.AssignOwners(co); owned != 2 {
+		t.Errorf("AssignOwners() = %d, want 2", owned)
+	}
+	if owners := g.Modules["services/user.go"].Owners(); !reflect.DeepEqual(owners, []string{"@acme/services"}) {
+		t.Errorf("user.go owners = %v", owners)
+	}
+	if owners := g.Modules["services/auth.go"].Owners(); !reflect.DeepEqual(owners, []string{"@bob", "@carol"}) {
+		t.Errorf("auth.go owners = %v, want declared owners over CODEOWNERS", owners)
+	}
+	if owners := g.Modules["main.go"].Owners(); len(owners) != 0 {
+		t.Errorf("main.go owners = %v", owners)
+	}
+
+	// Reassigning replaces earlier triples
+	g.AssignOwners(nil)
+	if triples := g.Store.Find("", OwnedByPredicate, ""); len(triples) != 2 {
+		t.Errorf("Expected only the 2 declared owner triples, got %v", triples)
+	}
+	if owners := g.Modules["services/user.go"].Owners(); len(owners) != 0 {
+		t.Errorf("user.go owners after reassigning = %v", owners)
+	}
+}
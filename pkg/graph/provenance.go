@@ -0,0 +1,196 @@
+/*
+# Module: pkg/graph/provenance.go
+Build provenance metadata.
+
+Records what produced a graph — the graphfs version, the git commit of the
+tree, the build time and the options that shape the graph's contents — as
+triples in a dedicated named graph, so they can be queried like any other
+triple and exported and verified with the graph:
+
+  <#graphfs-build> code:builtBy "graphfs" ;
+      code:toolVersion "0.1.0" ;
+      code:commit "4c62f21..." ;
+      code:builtAt "2026-10-15T09:30:00Z" ;
+      code:buildOption "infer_layers=true" .
+
+## Linked Modules
+- [graph](./graph.go) - Graph data structure
+- [builder](./builder.go) - Graph builder
+- [snapshot](./snapshot.go) - Build snapshots
+
+## Tags
+graph, provenance, metadata
+
+## Exports
+Provenance, NewProvenance, ProvenanceGraph, ProvenanceSubject, BuiltByPredicate, ToolVersionPredicate, CommitPredicate, UncommittedChangesPredicate, BuiltAtPredicate, BuildOptionPredicate
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#provenance.go> a code:Module ;
+    code:name "pkg/graph/provenance.go" ;
+    code:description "Build provenance metadata" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./graph.go>, <./builder.go>, <./snapshot.go> ;
+    code:exports <#Provenance>, <#NewProvenance>, <#ProvenanceGraph>, <#ProvenanceSubject>,
+                 <#BuiltByPredicate>, <#ToolVersionPredicate>, <#CommitPredicate>,
+                 <#UncommittedChangesPredicate>, <#BuiltAtPredicate>, <#BuildOptionPredicate> ;
+    code:tags "graph", "provenance", "metadata" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+const graphfsModule = "github.com/justin4957/graphfs"
+
+// Named graph and subject of build provenance triples
+const (
+	ProvenanceGraph   = "urn:graphfs:provenance"
+	ProvenanceSubject = "<#graphfs-build>"
+)
+
+// Provenance predicates
+const (
+	BuiltByPredicate            = "https://schema.codedoc.org/builtBy"
+	ToolVersionPredicate        = "https://schema.codedoc.org/toolVersion"
+	CommitPredicate             = "https://schema.codedoc.org/commit"
+	UncommittedChangesPredicate = "https://schema.codedoc.org/uncommittedChanges"
+	BuiltAtPredicate            = "https://schema.codedoc.org/builtAt"
+	BuildOptionPredicate        = "https://schema.codedoc.org/buildOption" // "<name>=<value>"
+)
+
+// Provenance records what produced a graph
+type Provenance struct {
+	Tool               string            `json:"tool"`
+	Version            string            `json:"version,omitempty"`
+	Commit             string            `json:"commit,omitempty"` // Empty outside git repositories
+	UncommittedChanges bool              `json:"uncommitted_changes,omitempty"`
+	BuiltAt            time.Time         `json:"built_at"`
+	Options            map[string]string `json:"options,omitempty"` // Options that shape the graph, by name
+}
+
+// NewProvenance records a build of root with opts
+func NewProvenance(root string, opts BuildOptions) *Provenance {
+	p := &Provenance{
+		Tool:    "graphfs",
+		Version: toolVersion(opts.ToolVersion),
+		BuiltAt: time.Now().UTC().Truncate(time.Second),
+		Options: buildOptionValues(opts),
+	}
+
+	git := scanner.NewGitFilter(root)
+	if git.IsGitRepository() {
+		p.Commit, _ = git.HeadCommit()
+		if changes, err := git.UncommittedChanges(); err == nil {
+			p.UncommittedChanges = len(changes) > 0
+		}
+	}
+	return p
+}
+
+// toolVersion returns version, or the graphfs module version the running
+// binary was built with
+func toolVersion(version string) string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == graphfsModule {
+			return info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == graphfsModule {
+				return dep.Version
+			}
+		}
+	}
+	return ""
+}
+
+// buildOptionValues returns the options that shape a graph's contents,
+// leaving out those at their zero value
+func buildOptionValues(opts BuildOptions) map[string]string {
+	values := make(map[string]string)
+	set := func(name, value string) {
+		if value != "" && value != "0" && value != "false" {
+			values[name] = value
+		}
+	}
+
+	scan := opts.ScanOptions
+	set("include", strings.Join(scan.IncludePatterns, ","))
+	set("exclude", strings.Join(scan.ExcludePatterns, ","))
+	set("max_file_size", strconv.FormatInt(scan.MaxFileSize, 10))
+	set("follow_symlinks", strconv.FormatBool(scan.FollowSymlinks))
+	set("use_default_ignores", strconv.FormatBool(scan.UseDefaults))
+	set("validate", strconv.FormatBool(opts.Validate))
+	set("use_cache", strconv.FormatBool(opts.UseCache))
+	if opts.SampleSize > 0 {
+		set("sample_size", strconv.Itoa(opts.SampleSize))
+		set("sample_strategy", opts.SampleStrategy.String())
+		set("sample_seed", strconv.FormatInt(opts.SampleSeed, 10))
+	}
+	set("changed_since", opts.ChangedSince)
+	set("focus", strings.Join(opts.FocusPatterns, ","))
+	set("base_iri", opts.BaseIRI)
+	set("infer_layers", strconv.FormatBool(opts.InferLayers))
+	set("infer_edges", strconv.FormatBool(opts.InferEdges))
+	set("extract_source", strconv.FormatBool(opts.ExtractSource))
+	if opts.Snapshot != nil {
+		set("snapshot_commit", opts.Snapshot.Commit)
+	}
+	return values
+}
+
+// addTriples records the provenance in the provenance named graph
+func (p *Provenance) addTriples(g *Graph) {
+	add := func(predicate, object string) {
+		if object != "" {
+			// Only fails for empty terms, which are skipped above
+			_ = g.Store.AddToGraph(ProvenanceGraph, ProvenanceSubject, predicate, object)
+		}
+	}
+
+	add(BuiltByPredicate, p.Tool)
+	add(ToolVersionPredicate, p.Version)
+	add(CommitPredicate, p.Commit)
+	if p.UncommittedChanges {
+		add(UncommittedChangesPredicate, "true")
+	}
+	add(BuiltAtPredicate, p.BuiltAt.Format(time.RFC3339))
+
+	names := make([]string, 0, len(p.Options))
+	for name := range p.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(BuildOptionPredicate, name+"="+p.Options[name])
+	}
+}
+
+// SetProvenance records p as the graph's provenance, replacing any earlier
+// provenance triples
+func (g *Graph) SetProvenance(p *Provenance) {
+	g.Provenance = p
+	if g.Store == nil {
+		return
+	}
+	g.Store.ClearGraph(ProvenanceGraph)
+	if p != nil {
+		p.addTriples(g)
+	}
+	g.Statistics.TotalTriples = g.Store.Count()
+}
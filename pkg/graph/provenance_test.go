@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestBuild_RecordsProvenance(t *testing.T) {
+	root := t.TempDir()
+	content := `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#main.go> a code:Module ;
+    code:name "main.go" .
+<!-- End LinkedDoc RDF -->
+*/
+package main
+`
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := NewBuilder()
+	g, err := builder.Build(root, BuildOptions{
+		ScanOptions:    scanner.ScanOptions{UseDefaults: true},
+		InferLayers:    true,
+		RecordSnapshot: true,
+		ToolVersion:    "1.2.3",
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	p := g.Provenance
+	if p == nil {
+		t.Fatal("Expected provenance to be recorded")
+	}
+	if p.Tool != "graphfs" || p.Version != "1.2.3" || p.BuiltAt.IsZero() {
+		t.Errorf("Unexpected provenance %+v", p)
+	}
+	if p.Options["infer_layers"] != "true" || p.Options["use_default_ignores"] != "true" {
+		t.Errorf("Options = %v, want infer_layers and use_default_ignores", p.Options)
+	}
+	if _, ok := p.Options["validate"]; ok {
+		t.Error("Options at their zero value should be left out")
+	}
+
+	// Queryable through the default graph, and held in the provenance graph
+	if got := g.Store.Find(ProvenanceSubject, ToolVersionPredicate, ""); len(got) != 1 || got[0].Object != "1.2.3" {
+		t.Errorf("Expected tool version triple, got %v", got)
+	}
+	if got := g.Store.Find(ProvenanceSubject, BuildOptionPredicate, "infer_layers=true"); len(got) != 1 {
+		t.Error("Expected infer_layers build option triple")
+	}
+	triples := g.Store.GraphTriples(ProvenanceGraph)
+	if len(triples) != len(g.Store.Find(ProvenanceSubject, "", "")) {
+		t.Errorf("Expected every provenance triple in %s, got %d", ProvenanceGraph, len(triples))
+	}
+	if g.Statistics.TotalTriples != g.Store.Count() {
+		t.Errorf("TotalTriples = %d, want %d", g.Statistics.TotalTriples, g.Store.Count())
+	}
+
+	if snapshot := builder.Snapshot(); snapshot == nil || snapshot.Provenance != p {
+		t.Error("Expected the snapshot to carry the build provenance")
+	}
+
+	// Replacing the provenance drops the earlier triples
+	g.SetProvenance(&Provenance{Tool: "graphfs", BuiltAt: p.BuiltAt})
+	if got := g.Store.Find(ProvenanceSubject, ToolVersionPredicate, ""); len(got) != 0 {
+		t.Errorf("Expected earlier tool version to be removed, got %v", got)
+	}
+	if got := g.Store.GraphTriples(ProvenanceGraph); len(got) != 2 {
+		t.Errorf("Expected builtBy and builtAt triples, got %v", got)
+	}
+}
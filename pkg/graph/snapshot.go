@@ -0,0 +1,197 @@
+/*
+# Module: pkg/graph/snapshot.go
+Graph snapshots.
+
+A snapshot records every module of a build with its triples, keeping URIs as
+written, together with the git commit it was built from. Builds given a prior
+snapshot restore unchanged modules from it and re-parse only the files that
+differ from that commit, so CI runs on a pull request cost time in proportion
+to the diff rather than the repository. Named snapshots kept in a
+SnapshotStore are also what history comparisons and shadow diffs read.
+
+## Linked Modules
+- [builder](./builder.go) - Graph builder
+- [module](./module.go) - Module data structure
+- [snapshot_store](./snapshot_store.go) - Named snapshots
+- [../cache](../cache/manager.go) - Cached triple format
+
+## Tags
+graph, snapshot, incremental, ci
+
+## Exports
+SnapshotVersion, Snapshot, SnapshotEntry, NewSnapshot, LoadSnapshot, Snapshot.Graph
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#snapshot.go> a code:Module ;
+    code:name "pkg/graph/snapshot.go" ;
+    code:description "Graph snapshots" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./builder.go>, <./module.go>, <./snapshot_store.go>, <../cache/manager.go> ;
+    code:exports <#SnapshotVersion>, <#Snapshot>, <#SnapshotEntry>, <#NewSnapshot>, <#LoadSnapshot>, <#Snapshot.Graph> ;
+    code:tags "graph", "snapshot", "incremental", "ci" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/cache"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// SnapshotVersion is the snapshot format version
+const SnapshotVersion = 1
+
+// Snapshot is a saved build, keyed by canonical module path
+type Snapshot struct {
+	Version   int                       `json:"version"`
+	Name      string                    `json:"name,omitempty"`   // Name in a SnapshotStore
+	Commit    string                    `json:"commit,omitempty"` // Git commit the build was made from
+	CreatedAt time.Time                 `json:"created_at"`
+	Modules   map[string]*SnapshotEntry `json:"modules"`
+
+	// Shadow annotations of each module, by key, as text
+	Annotations map[string]map[string]string `json:"annotations,omitempty"`
+
+	// Provenance of the build the snapshot was recorded from
+	Provenance *Provenance `json:"provenance,omitempty"`
+
+	mu sync.Mutex
+}
+
+// SnapshotEntry is one file's module and triples, with URIs as written
+type SnapshotEntry struct {
+	Module  *Module        `json:"module"`
+	Triples []cache.Triple `json:"triples"`
+}
+
+// NewSnapshot creates an empty snapshot for a commit
+func NewSnapshot(commit string) *Snapshot {
+	return &Snapshot{
+		Version:   SnapshotVersion,
+		Commit:    commit,
+		CreatedAt: time.Now().UTC(),
+		Modules:   make(map[string]*SnapshotEntry),
+	}
+}
+
+// LoadSnapshot reads a snapshot file, gzip-compressed or not
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		data, err = io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (expected %d)", snapshot.Version, SnapshotVersion)
+	}
+	if snapshot.Modules == nil {
+		snapshot.Modules = make(map[string]*SnapshotEntry)
+	}
+
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to a file, gzip-compressed when the file name
+// ends in .gz
+func (s *Snapshot) Save(path string) error {
+	s.mu.Lock()
+	data, err := json.Marshal(s)
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if strings.HasSuffix(path, ".gz") {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(data); err != nil {
+			return fmt.Errorf("failed to compress snapshot: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to compress snapshot: %w", err)
+		}
+		data = compressed.Bytes()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Graph returns a graph of the snapshot's modules, without their triples,
+// for analyses of the recorded state
+func (s *Snapshot) Graph() *Graph {
+	g := NewGraph("", store.NewTripleStore())
+	g.Provenance = s.Provenance
+	for _, entry := range s.Modules {
+		if entry.Module != nil {
+			g.AddModule(copyModule(entry.Module))
+		}
+	}
+	return g
+}
+
+// add records a module with URIs as written (safe for concurrent use)
+func (s *Snapshot) add(path string, module *Module, triples []cache.Triple) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Modules[pathkey.Canonical(path)] = &SnapshotEntry{Module: module, Triples: triples}
+}
+
+// copyModule returns a copy of a module that the graph can modify without
+// changing the original
+func copyModule(m *Module) *Module {
+	c := *m
+	c.Aliases = append([]string{}, m.Aliases...)
+	c.Markers = append([]Marker{}, m.Markers...)
+	c.Dependencies = append([]string{}, m.Dependencies...)
+	c.Edges = append([]Edge{}, m.Edges...)
+	c.Dependents = []string{}
+	c.Exports = append([]string{}, m.Exports...)
+	c.Calls = append([]string{}, m.Calls...)
+	c.Tags = append([]string{}, m.Tags...)
+	c.Properties = make(map[string][]string, len(m.Properties))
+	for predicate, values := range m.Properties {
+		c.Properties[predicate] = append([]string{}, values...)
+	}
+	c.Components = make([]*Module, len(m.Components))
+	for i, component := range m.Components {
+		c.Components[i] = copyModule(component)
+	}
+	return &c
+}
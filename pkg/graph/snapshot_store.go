@@ -0,0 +1,146 @@
+/*
+# Module: pkg/graph/snapshot_store.go
+Named graph snapshots.
+
+Keeps gzip-compressed snapshots by name in one directory, usually
+.graphfs/snapshots, so architecture can be compared across releases and
+shadow metadata diffed against an earlier state without rebuilding old
+trees.
+
+## Linked Modules
+- [snapshot](./snapshot.go) - Graph snapshots
+
+## Tags
+graph, snapshot, history
+
+## Exports
+SnapshotDir, SnapshotStore, NewSnapshotStore, ValidateSnapshotName
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#snapshot_store.go> a code:Module ;
+    code:name "pkg/graph/snapshot_store.go" ;
+    code:description "Named graph snapshots" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./snapshot.go> ;
+    code:exports <#SnapshotDir>, <#SnapshotStore>, <#NewSnapshotStore>, <#ValidateSnapshotName> ;
+    code:tags "graph", "snapshot", "history" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SnapshotDir holds named snapshots, relative to the project root
+const SnapshotDir = ".graphfs/snapshots"
+
+// snapshotExt is the file extension of stored snapshots
+const snapshotExt = ".json.gz"
+
+// ValidateSnapshotName checks that a snapshot name can be used as a file name
+func ValidateSnapshotName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return nil
+}
+
+// SnapshotStore keeps named, compressed snapshots in a directory
+type SnapshotStore struct {
+	dir string
+}
+
+// NewSnapshotStore returns a store for a directory, usually SnapshotDir
+func NewSnapshotStore(dir string) *SnapshotStore {
+	return &SnapshotStore{dir: dir}
+}
+
+// Path returns the file of a named snapshot
+func (s *SnapshotStore) Path(name string) string {
+	return filepath.Join(s.dir, name+snapshotExt)
+}
+
+// Exists reports whether a snapshot is stored
+func (s *SnapshotStore) Exists(name string) bool {
+	if ValidateSnapshotName(name) != nil {
+		return false
+	}
+	_, err := os.Stat(s.Path(name))
+	return err == nil
+}
+
+// Save writes a snapshot under its name, replacing any snapshot of the same
+// name
+func (s *SnapshotStore) Save(snapshot *Snapshot) error {
+	if err := ValidateSnapshotName(snapshot.Name); err != nil {
+		return err
+	}
+	return snapshot.Save(s.Path(snapshot.Name))
+}
+
+// Load reads a named snapshot
+func (s *SnapshotStore) Load(name string) (*Snapshot, error) {
+	if err := ValidateSnapshotName(name); err != nil {
+		return nil, err
+	}
+	if !s.Exists(name) {
+		return nil, fmt.Errorf("snapshot %q not found", name)
+	}
+	snapshot, err := LoadSnapshot(s.Path(name))
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %q: %w", name, err)
+	}
+	snapshot.Name = name
+	return snapshot, nil
+}
+
+// List returns the stored snapshots, oldest first
+func (s *SnapshotStore) List() ([]*Snapshot, error) {
+	files, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var snapshots []*Snapshot
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), snapshotExt) {
+			continue
+		}
+		snapshot, err := s.Load(strings.TrimSuffix(file.Name(), snapshotExt))
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// Delete removes a named snapshot
+func (s *SnapshotStore) Delete(name string) error {
+	if err := ValidateSnapshotName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(s.Path(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot %q not found", name)
+		}
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func writeSnapshotModule(t *testing.T, root, name, layer, linksTo string) {
+	t.Helper()
+	content := `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#` + name + `> a code:Module ;
+    code:name "` + name + `" ;
+`
+	if linksTo != "" {
+		content += `    code:linksTo <./` + linksTo + `> ;
+`
+	}
+	content += `    code:layer "` + layer + `" .
+<!-- End LinkedDoc RDF -->
+*/
+package app
+`
+	if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild_Snapshot(t *testing.T) {
+	root := t.TempDir()
+	writeSnapshotModule(t, root, "a.go", "api", "b.go")
+	writeSnapshotModule(t, root, "b.go", "core", "")
+	writeSnapshotModule(t, root, "c.go", "core", "")
+
+	opts := BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}}
+
+	builder := NewBuilder()
+	recordOpts := opts
+	recordOpts.RecordSnapshot = true
+	if _, err := builder.Build(root, recordOpts); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	snapshot := builder.Snapshot()
+	if snapshot == nil || len(snapshot.Modules) != 3 {
+		t.Fatalf("expected snapshot with 3 modules, got %+v", snapshot)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := snapshot.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+
+	// Change b.go, delete c.go, add d.go
+	writeSnapshotModule(t, root, "b.go", "data", "")
+	if err := os.Remove(filepath.Join(root, "c.go")); err != nil {
+		t.Fatal(err)
+	}
+	writeSnapshotModule(t, root, "d.go", "api", "a.go")
+
+	incrementalOpts := opts
+	incrementalOpts.Snapshot = loaded
+	incrementalOpts.SnapshotChanges = []string{"b.go", "c.go", filepath.Join(root, "d.go")}
+	incrementalOpts.RecordSnapshot = true
+	incremental, err := builder.Build(root, incrementalOpts)
+	if err != nil {
+		t.Fatalf("incremental Build() error = %v", err)
+	}
+
+	full, err := NewBuilder().Build(root, opts)
+	if err != nil {
+		t.Fatalf("full Build() error = %v", err)
+	}
+
+	if incremental.Statistics.SnapshotRestored != 1 {
+		t.Errorf("SnapshotRestored = %d, want 1", incremental.Statistics.SnapshotRestored)
+	}
+	if len(incremental.Modules) != len(full.Modules) {
+		t.Fatalf("incremental build has %d modules, full build %d", len(incremental.Modules), len(full.Modules))
+	}
+	if incremental.Statistics.TotalTriples != full.Statistics.TotalTriples {
+		t.Errorf("incremental build has %d triples, full build %d",
+			incremental.Statistics.TotalTriples, full.Statistics.TotalTriples)
+	}
+	if incremental.Modules["c.go"] != nil {
+		t.Error("deleted c.go should not be restored")
+	}
+	if layer := incremental.Modules["b.go"].Layer; layer != "data" {
+		t.Errorf("b.go layer = %q, want re-parsed value data", layer)
+	}
+	if dependents := incremental.Modules["a.go"].Dependents; len(dependents) != 1 {
+		t.Errorf("a.go dependents = %v, want d.go", dependents)
+	}
+
+	// The recorded snapshot covers the merged build
+	if got := len(builder.Snapshot().Modules); got != 3 {
+		t.Errorf("merged snapshot has %d modules, want 3", got)
+	}
+}
+
+func TestLoadSnapshot_Version(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "modules": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSnapshot(path); err == nil {
+		t.Error("expected error for unsupported snapshot version")
+	}
+}
+
+func TestSnapshotStore(t *testing.T) {
+	snapshotStore := NewSnapshotStore(t.TempDir())
+
+	if snapshots, err := snapshotStore.List(); err != nil || len(snapshots) != 0 {
+		t.Fatalf("List on an empty store = %v, %v", snapshots, err)
+	}
+
+	older := NewSnapshot("abc123")
+	older.Name = "v1"
+	older.CreatedAt = time.Now().Add(-time.Hour)
+	newer := NewSnapshot("def456")
+	newer.Name = "v2"
+	module := NewModule("a.go", "<#a.go>")
+	module.AddDependency("b.go")
+	newer.add("a.go", module, nil)
+	newer.Annotations = map[string]map[string]string{"a.go": {"owner": "core"}}
+	for _, s := range []*Snapshot{newer, older} {
+		if err := snapshotStore.Save(s); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	loaded, err := snapshotStore.Load("v2")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Commit != "def456" || loaded.Annotations["a.go"]["owner"] != "core" {
+		t.Errorf("Unexpected snapshot after round trip: %+v", loaded)
+	}
+	g := loaded.Graph()
+	if len(g.Modules) != 1 || g.Modules["a.go"].Dependencies[0] != "b.go" {
+		t.Errorf("Unexpected graph of snapshot: %+v", g.Modules)
+	}
+
+	snapshots, err := snapshotStore.List()
+	if err != nil || len(snapshots) != 2 || snapshots[0].Name != "v1" {
+		t.Errorf("Expected snapshots oldest first, got %v, %v", snapshots, err)
+	}
+
+	if err := snapshotStore.Delete("v1"); err != nil || snapshotStore.Exists("v1") {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, err := snapshotStore.Load("v1"); err == nil {
+		t.Error("Expected an error loading a deleted snapshot")
+	}
+	if err := snapshotStore.Save(&Snapshot{Name: "../escape"}); err == nil {
+		t.Error("Expected an invalid name to be rejected")
+	}
+}
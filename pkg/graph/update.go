@@ -0,0 +1,290 @@
+/*
+# Module: pkg/graph/update.go
+Incremental graph updates.
+
+Builder.Update applies a set of changed files to a built graph in place:
+only those files are re-parsed, their previous modules and triples are
+replaced, and reverse dependencies, edge weights and cross-language triples
+are recomputed for what the change touches. Deleted files, and files that
+no longer carry LinkedDoc metadata, are removed from the graph.
+
+## Linked Modules
+- [builder](./builder.go) - Graph builder
+- [graph](./graph.go) - Graph data structure
+- [edges](./edges.go) - Dependency edge metadata
+- [../../internal/store](../../internal/store/store.go) - Triple store
+
+## Tags
+graph, incremental, update, watch
+
+## Exports
+UpdateResult, Builder.Update
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#update.go> a code:Module ;
+    code:name "pkg/graph/update.go" ;
+    code:description "Incremental graph updates" ;
+    code:language "go" ;
+    code:layer "graph" ;
+    code:linksTo <./builder.go>, <./graph.go>, <./edges.go>, <../../internal/store/store.go> ;
+    code:exports <#UpdateResult>, <#Builder.Update> ;
+    code:tags "graph", "incremental", "update", "watch" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// UpdateResult summarizes an incremental update
+type UpdateResult struct {
+	Added    []string      // Paths of new modules
+	Updated  []string      // Paths of re-parsed modules
+	Removed  []string      // Paths of modules whose file was deleted or lost its LinkedDoc
+	Duration time.Duration // Time taken to update
+}
+
+// Changed reports whether the update added, updated or removed a module
+func (r *UpdateResult) Changed() bool {
+	return len(r.Added)+len(r.Updated)+len(r.Removed) > 0
+}
+
+// Update applies changed files, absolute or relative to g.Root, to a graph
+// built by Build, re-parsing only those files. Modules of deleted files or
+// files without LinkedDoc metadata (or extractable source, with
+// ExtractSource) are removed. opts should match the
+// options the graph was built with; the cache and snapshot options are not
+// used. The graph must not be read concurrently while it is updated.
+func (b *Builder) Update(g *Graph, changedFiles []string, opts BuildOptions) (*UpdateResult, error) {
+	startTime := time.Now()
+	result := &UpdateResult{}
+
+	var iris *IRIMapper
+	if opts.BaseIRI != "" {
+		var err error
+		if iris, err = NewIRIMapper(opts.BaseIRI); err != nil {
+			return nil, err
+		}
+	}
+
+	// Changed paths relative to the root; a changed alias stands for its
+	// module's file
+	changed := make(map[string]bool)
+	var paths []string
+	for _, p := range changedFiles {
+		if filepath.IsAbs(p) {
+			rel, err := filepath.Rel(g.Root, p)
+			if err != nil {
+				continue
+			}
+			p = rel
+		}
+		p = pathkey.Canonical(p)
+		if p == ".." || strings.HasPrefix(p, "../") {
+			continue
+		}
+		if module := g.GetModule(p); module != nil {
+			p = module.Path
+		}
+		if !changed[p] {
+			changed[p] = true
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	if len(paths) == 0 {
+		result.Duration = time.Since(startTime)
+		return result, nil
+	}
+
+	// Scan the changed files that still exist
+	var existing []string
+	for _, p := range paths {
+		abs := filepath.Join(g.Root, filepath.FromSlash(p))
+		if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+			existing = append(existing, abs)
+		}
+	}
+	parse := make(map[string]scanner.FileInfo)
+	if len(existing) > 0 {
+		scanResult, err := b.scanner.ScanPaths(g.Root, existing, opts.ScanOptions)
+		if err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		for _, file := range scanResult.Files {
+			if !opts.parses(file) {
+				continue
+			}
+			if rel, err := filepath.Rel(g.Root, file.Path); err == nil {
+				parse[pathkey.Canonical(rel)] = *file
+			}
+		}
+	}
+
+	// Remove the previous state of every changed file, then re-parse
+	for _, p := range paths {
+		_, reparse := parse[p]
+		switch {
+		case g.Modules[p] != nil && reparse:
+			result.Updated = append(result.Updated, p)
+		case g.Modules[p] != nil:
+			result.Removed = append(result.Removed, p)
+		case reparse:
+			result.Added = append(result.Added, p)
+		}
+		g.removeFile(p)
+	}
+	for _, p := range result.Removed {
+		g.dropInferredEdges(p)
+	}
+	for _, p := range paths {
+		file, ok := parse[p]
+		if !ok {
+			continue
+		}
+		if err := b.processFile(file, g, g.Root, false, b.parser, iris); err != nil {
+			return nil, fmt.Errorf("failed to process %s: %w", p, err)
+		}
+		if module := g.Modules[p]; module != nil {
+			for _, alias := range module.Aliases {
+				if err := g.Store.Add(module.URI, PathAliasPredicate, alias); err != nil {
+					return nil, fmt.Errorf("failed to add triple: %w", err)
+				}
+			}
+		}
+	}
+
+	if opts.InferEdges || opts.ExtractSource {
+		if _, err := g.inferEdges(opts.infersEdges); err != nil {
+			return nil, fmt.Errorf("failed to infer edges: %w", err)
+		}
+	}
+
+	// Reverse dependencies are cheap to recompute for the whole graph
+	for _, module := range g.Modules {
+		module.Dependents = []string{}
+	}
+	b.buildDependencyGraph(g)
+
+	// Edges of changed modules and of modules depending on changed files;
+	// inferred imports may have added edges anywhere
+	affected := make(map[string]*Module)
+	if opts.InferEdges || opts.ExtractSource {
+		affected = g.Modules
+	} else {
+		for p, module := range g.Modules {
+			if changed[p] {
+				affected[p] = module
+				continue
+			}
+			for _, dep := range module.Dependencies {
+				if target := g.GetModule(dep); changed[dep] || (target != nil && changed[target.Path]) {
+					affected[p] = module
+					break
+				}
+			}
+		}
+	}
+	for _, module := range affected {
+		g.removeEdgeTriples(module)
+		g.weighEdges(module)
+		g.addEdgeTriples(module)
+	}
+
+	// Cross-language triples depend on both ends of each edge
+	_ = g.Store.Delete("", CrossLanguageDependencyPredicate, "")
+	_ = g.Store.Delete("", LanguageBoundaryPredicate, "")
+	g.AddLanguageTriples(g.AnalyzeLanguages())
+
+	// Ownership of new modules; an unreadable CODEOWNERS keeps declared owners
+	codeowners, _ := LoadCodeowners(g.Root)
+	g.AssignOwners(codeowners)
+
+	if opts.InferLayers {
+		g.InferLayers()
+	}
+
+	g.Statistics.TotalTriples = g.Store.Count()
+	g.Statistics.TotalRelationships = b.countRelationships(g)
+	result.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// recordFileTriples records the triples a source file added (thread-safe)
+func (g *Graph) recordFileTriples(path string, triples []store.Triple) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.files == nil {
+		g.files = make(map[string][]store.Triple)
+	}
+	g.files[path] = append(g.files[path], triples...)
+}
+
+// removeFile removes a file's module and the triples it added, keeping
+// triples another file also added
+func (g *Graph) removeFile(path string) {
+	if module := g.Modules[path]; module != nil {
+		g.removeEdgeTriples(module)
+		_ = g.Store.Delete(module.URI, PathAliasPredicate, "")
+		_ = g.Store.Delete(module.URI, InferredLayerPredicate, "")
+		g.RemoveModule(path)
+	}
+
+	triples := g.files[path]
+	delete(g.files, path)
+	if len(triples) == 0 {
+		return
+	}
+
+	shared := make(map[store.Triple]bool, len(triples))
+	for _, t := range triples {
+		shared[t] = false
+	}
+	for _, other := range g.files {
+		for _, t := range other {
+			if _, ok := shared[t]; ok {
+				shared[t] = true
+			}
+		}
+	}
+	for t, keep := range shared {
+		if !keep {
+			_ = g.Store.Delete(t.Subject, t.Predicate, t.Object)
+		}
+	}
+}
+
+// dropInferredEdges removes inferred edges to a removed module; declared
+// dependencies are kept, as the header still names them
+func (g *Graph) dropInferredEdges(target string) {
+	for _, module := range g.Modules {
+		for i, edge := range module.Edges {
+			if edge.Target != target || !edge.Inferred {
+				continue
+			}
+			module.Edges = append(module.Edges[:i], module.Edges[i+1:]...)
+			for j, dep := range module.Dependencies {
+				if dep == target {
+					module.Dependencies = append(module.Dependencies[:j], module.Dependencies[j+1:]...)
+					break
+				}
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// graphState returns the triples and dependency lists of a graph, leaving out
+// provenance, for comparing graphs
+func graphState(g *Graph) (triples []string, deps map[string][]string) {
+	for _, t := range g.Store.Find("", "", "") {
+		if t.Subject == ProvenanceSubject {
+			continue
+		}
+		triples = append(triples, t.Subject+" "+t.Predicate+" "+t.Object)
+	}
+	sort.Strings(triples)
+
+	deps = make(map[string][]string)
+	for p, module := range g.Modules {
+		dependents := append([]string{}, module.Dependents...)
+		sort.Strings(dependents)
+		deps[p] = append(append([]string{}, module.Dependencies...), dependents...)
+	}
+	return triples, deps
+}
+
+func TestBuilder_Update(t *testing.T) {
+	root := writeEdgeProject(t)
+	opts := BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}, InferEdges: true}
+
+	builder := NewBuilder()
+	g, err := builder.Build(root, opts)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Change an export, add a module and delete one
+	write("store/store.go", `/*
+# Module: store/store.go
+Storage.
+
+<!-- LinkedDoc RDF -->
+<#store.go> a code:Module ;
+    code:name "store/store.go" ;
+    code:layer "data" ;
+    code:exports <#NewStore> .
+<!-- End LinkedDoc RDF -->
+*/
+
+package store
+`)
+	write("store/cache.go", `/*
+# Module: store/cache.go
+Cache.
+
+<!-- LinkedDoc RDF -->
+<#cache.go> a code:Module ;
+    code:name "store/cache.go" ;
+    code:linksTo <./store.go> .
+<!-- End LinkedDoc RDF -->
+*/
+
+package store
+
+var _ = NewStore
+`)
+	if err := os.Remove(filepath.Join(root, "util", "log.go")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+
+	result, err := builder.Update(g, []string{
+		filepath.Join(root, "store", "store.go"),
+		"store/cache.go",
+		"util/log.go",
+	}, opts)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.Added, []string{"store/cache.go"}) ||
+		!reflect.DeepEqual(result.Updated, []string{"store/store.go"}) ||
+		!reflect.DeepEqual(result.Removed, []string{"util/log.go"}) {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	fresh, err := NewBuilder().Build(root, opts)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	gotTriples, gotDeps := graphState(g)
+	wantTriples, wantDeps := graphState(fresh)
+	if !reflect.DeepEqual(gotTriples, wantTriples) {
+		t.Errorf("Updated triples differ from a fresh build:\ngot  %v\nwant %v", gotTriples, wantTriples)
+	}
+	if !reflect.DeepEqual(gotDeps, wantDeps) {
+		t.Errorf("Updated dependencies differ from a fresh build:\ngot  %v\nwant %v", gotDeps, wantDeps)
+	}
+	if g.Statistics.TotalModules != fresh.Statistics.TotalModules || g.Statistics.TotalTriples != fresh.Statistics.TotalTriples {
+		t.Errorf("Statistics = %+v, want %+v", g.Statistics, fresh.Statistics)
+	}
+	if weight := g.Modules["main.go"].EdgeTo("store/store.go").Weight; weight != 2 {
+		t.Errorf("Expected main.go -> store/store.go weight 2 after Put was removed, got %d", weight)
+	}
+}
+
+func TestBuilder_Update_SharedTriples(t *testing.T) {
+	root := t.TempDir()
+	header := func(name string) string {
+		return `/*
+<!-- LinkedDoc RDF -->
+<#` + name + `> a code:Module ;
+    code:name "` + name + `" .
+<#Shared> a code:Type .
+<!-- End LinkedDoc RDF -->
+*/
+`
+	}
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(header(name)), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	builder := NewBuilder()
+	g, err := builder.Build(root, BuildOptions{})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "a.go")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if _, err := builder.Update(g, []string{"a.go"}, BuildOptions{}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if g.Modules["a.go"] != nil || g.Modules["b.go"] == nil {
+		t.Errorf("Expected only b.go to remain, got %v", g.Modules)
+	}
+	if len(g.Store.Find("<#Shared>", "", "")) == 0 {
+		t.Error("Triples also declared by b.go should be kept")
+	}
+	if len(g.Store.Find("<#a.go>", "", "")) != 0 {
+		t.Error("Triples of a.go should be removed")
+	}
+}
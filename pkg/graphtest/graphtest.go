@@ -0,0 +1,215 @@
+/*
+# Module: pkg/graphtest/graphtest.go
+Graph assertions for Go tests.
+
+Lets teams write architecture tests in their normal Go test suite: build a
+graph from the repository once, then assert there are no dependency cycles,
+that layers only depend downwards, or that one part of the codebase never
+depends on another.
+
+Selectors used by the assertions match a module when they equal its layer,
+its path, or a directory prefix of its path (e.g. "api", "pkg/api",
+"pkg/api/handler.go").
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [../graph](../graph/builder.go) - Graph builder
+
+## Tags
+testing, architecture, assertions, graph
+
+## Exports
+Load, AssertNoCycle, AssertLayerOrder, AssertNoDependency
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#graphtest.go> a code:Module ;
+    code:name "pkg/graphtest/graphtest.go" ;
+    code:description "Graph assertions for Go tests" ;
+    code:language "go" ;
+    code:layer "testing" ;
+    code:linksTo <../graph/graph.go>, <../graph/builder.go> ;
+    code:exports <#Load>, <#AssertNoCycle>, <#AssertLayerOrder>, <#AssertNoDependency> ;
+    code:tags "testing", "architecture", "assertions", "graph" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package graphtest
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// graphCache holds graphs built by Load, keyed by absolute root path
+var graphCache sync.Map
+
+// Load builds the knowledge graph for rootPath, failing the test on error.
+// Graphs are built once per root and shared by later calls in the same
+// test binary, so they must not be modified.
+func Load(t testing.TB, rootPath string) *graph.Graph {
+	t.Helper()
+
+	absRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		t.Fatalf("graphtest: failed to resolve root path: %v", err)
+	}
+
+	if cached, ok := graphCache.Load(absRoot); ok {
+		return cached.(*graph.Graph)
+	}
+
+	g, err := graph.NewBuilder().Build(absRoot, graph.BuildOptions{Validate: false})
+	if err != nil {
+		t.Fatalf("graphtest: failed to build graph for %s: %v", absRoot, err)
+	}
+
+	actual, _ := graphCache.LoadOrStore(absRoot, g)
+	return actual.(*graph.Graph)
+}
+
+// AssertNoCycle reports every dependency cycle in the graph. It returns
+// true if the graph is acyclic.
+func AssertNoCycle(t testing.TB, g *graph.Graph) bool {
+	t.Helper()
+
+	cycles := findCycles(g)
+	for _, cycle := range cycles {
+		t.Errorf("graphtest: dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return len(cycles) == 0
+}
+
+// AssertLayerOrder checks that modules only depend on their own layer or on
+// layers listed after it. Layers are given from the top (e.g. "cli", "api",
+// "service", "data"). Modules in unlisted layers are ignored. It returns
+// true if no module depends upwards.
+func AssertLayerOrder(t testing.TB, g *graph.Graph, layers ...string) bool {
+	t.Helper()
+
+	rank := make(map[string]int, len(layers))
+	for i, layer := range layers {
+		rank[layer] = i
+	}
+
+	ok := true
+	for _, module := range sortedModules(g) {
+		fromRank, listed := rank[module.Layer]
+		if !listed {
+			continue
+		}
+		for _, dep := range module.Dependencies {
+			target, exists := g.Modules[dep]
+			if !exists {
+				continue
+			}
+			if toRank, listed := rank[target.Layer]; listed && toRank < fromRank {
+				t.Errorf("graphtest: %s (%s) depends on %s (%s), which is a higher layer",
+					module.Path, module.Layer, target.Path, target.Layer)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// AssertNoDependency checks that no module matching from depends directly on
+// a module matching to, e.g. AssertNoDependency(t, g, "api", "data"). It
+// returns true if there is no such dependency.
+func AssertNoDependency(t testing.TB, g *graph.Graph, from, to string) bool {
+	t.Helper()
+
+	ok := true
+	for _, module := range sortedModules(g) {
+		if !matches(module, from) {
+			continue
+		}
+		for _, dep := range module.Dependencies {
+			if target, exists := g.Modules[dep]; exists && matches(target, to) {
+				t.Errorf("graphtest: %s depends on %s (%s must not depend on %s)",
+					module.Path, target.Path, from, to)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// matches reports whether a selector matches a module's layer, path or
+// directory
+func matches(module *graph.Module, selector string) bool {
+	if module.Layer == selector || module.Path == selector {
+		return true
+	}
+	return strings.HasPrefix(module.Path, strings.TrimSuffix(selector, "/")+"/")
+}
+
+// sortedModules returns the graph's modules ordered by path, so failures are
+// reported in a stable order
+func sortedModules(g *graph.Graph) []*graph.Module {
+	modules := make([]*graph.Module, 0, len(g.Modules))
+	for _, module := range g.Modules {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+	return modules
+}
+
+// findCycles runs a DFS over dependencies and returns one path per back
+// edge, each starting and ending at the same module
+func findCycles(g *graph.Graph) [][]string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(path string)
+	visit = func(path string) {
+		state[path] = inProgress
+		stack = append(stack, path)
+
+		deps := append([]string(nil), g.Modules[path].Dependencies...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, exists := g.Modules[dep]; !exists {
+				continue
+			}
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case inProgress:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						cycle := append([]string(nil), stack[i:]...)
+						cycles = append(cycles, append(cycle, dep))
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[path] = done
+	}
+
+	for _, module := range sortedModules(g) {
+		if state[module.Path] == unvisited {
+			visit(module.Path)
+		}
+	}
+	return cycles
+}
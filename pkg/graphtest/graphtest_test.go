@@ -0,0 +1,116 @@
+package graphtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// recorder captures assertion failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func buildTestGraph(deps map[string][]string, layers map[string]string) *graph.Graph {
+	g := graph.NewGraph("/project", store.NewTripleStore())
+	for path, layer := range layers {
+		module := graph.NewModule(path, "<#"+path+">")
+		module.Layer = layer
+		module.Dependencies = deps[path]
+		g.AddModule(module)
+	}
+	return g
+}
+
+func TestAssertNoCycle(t *testing.T) {
+	layers := map[string]string{"a.go": "api", "b.go": "service", "c.go": "data"}
+
+	acyclic := buildTestGraph(map[string][]string{"a.go": {"b.go"}, "b.go": {"c.go"}}, layers)
+	rec := &recorder{TB: t}
+	if !AssertNoCycle(rec, acyclic) || len(rec.errors) != 0 {
+		t.Errorf("Expected no cycle, got %v", rec.errors)
+	}
+
+	cyclic := buildTestGraph(map[string][]string{"a.go": {"b.go"}, "b.go": {"c.go"}, "c.go": {"a.go"}}, layers)
+	rec = &recorder{TB: t}
+	if AssertNoCycle(rec, cyclic) {
+		t.Error("Expected cycle to be reported")
+	}
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "a.go -> b.go -> c.go -> a.go") {
+		t.Errorf("Expected cycle path in failure, got %v", rec.errors)
+	}
+}
+
+func TestAssertLayerOrder(t *testing.T) {
+	g := buildTestGraph(
+		map[string][]string{
+			"api/handler.go":  {"service/user.go"},
+			"service/user.go": {"data/repo.go", "api/handler.go"},
+			"data/repo.go":    {"util/log.go"},
+		},
+		map[string]string{
+			"api/handler.go":  "api",
+			"service/user.go": "service",
+			"data/repo.go":    "data",
+			"util/log.go":     "util",
+		},
+	)
+
+	rec := &recorder{TB: t}
+	if AssertLayerOrder(rec, g, "api", "service", "data") {
+		t.Error("Expected upward dependency to be reported")
+	}
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "service/user.go (service) depends on api/handler.go (api)") {
+		t.Errorf("Expected one layer violation, got %v", rec.errors)
+	}
+}
+
+func TestAssertNoDependency(t *testing.T) {
+	g := buildTestGraph(
+		map[string][]string{
+			"api/handler.go":  {"data/repo.go", "service/user.go"},
+			"service/user.go": {"data/repo.go"},
+		},
+		map[string]string{
+			"api/handler.go":  "api",
+			"service/user.go": "service",
+			"data/repo.go":    "data",
+		},
+	)
+
+	rec := &recorder{TB: t}
+	if AssertNoDependency(rec, g, "api", "data") {
+		t.Error("Expected api -> data dependency to be reported")
+	}
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "api/handler.go depends on data/repo.go") {
+		t.Errorf("Expected one violation, got %v", rec.errors)
+	}
+
+	// Directory selectors
+	rec = &recorder{TB: t}
+	if !AssertNoDependency(rec, g, "data/", "api") {
+		t.Errorf("Expected no data -> api dependency, got %v", rec.errors)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	g := Load(t, "../../examples/minimal-app")
+	if len(g.Modules) == 0 {
+		t.Fatal("Expected modules in example graph")
+	}
+	if Load(t, "../../examples/minimal-app") != g {
+		t.Error("Expected Load to reuse the graph for the same root")
+	}
+
+	AssertNoDependency(t, g, "utils", "services")
+}
@@ -0,0 +1,326 @@
+/*
+# Module: pkg/history/compare.go
+Comparison of graph snapshots.
+
+Reports the modules and dependencies added and removed between two
+snapshots, the dependency cycles introduced and resolved, and the change
+of each summary statistic, as text, JSON or Markdown.
+
+## Linked Modules
+- [summary](./summary.go) - Graph summaries
+- [../analysis](../analysis/criticality.go) - Score rounding
+
+## Tags
+history, diff, metrics
+
+## Exports
+Comparison, MetricDelta, Compare, Format, FormatText, FormatJSON, FormatMarkdown, FormatComparison
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#compare.go> a code:Module ;
+    code:name "pkg/history/compare.go" ;
+    code:description "Comparison of graph snapshots" ;
+    code:language "go" ;
+    code:layer "history" ;
+    code:linksTo <./summary.go>, <../analysis/criticality.go> ;
+    code:exports <#Comparison>, <#MetricDelta>, <#Compare>, <#Format>, <#FormatText>, <#FormatJSON>, <#FormatMarkdown>, <#FormatComparison> ;
+    code:tags "history", "diff", "metrics" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+)
+
+// Comparison is the difference between two snapshots
+type Comparison struct {
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	AddedModules   []string      `json:"added_modules"`
+	RemovedModules []string      `json:"removed_modules"`
+	AddedEdges     []EdgeRecord  `json:"added_edges"`
+	RemovedEdges   []EdgeRecord  `json:"removed_edges"`
+	NewCycles      [][]string    `json:"new_cycles"`      // Cyclic components not contained in an old one
+	ResolvedCycles [][]string    `json:"resolved_cycles"` // Old cyclic components none of whose modules is still cyclic
+	Metrics        []MetricDelta `json:"metrics"`
+}
+
+// MetricDelta is the change of one summary statistic
+type MetricDelta struct {
+	Name  string  `json:"name"`
+	Old   float64 `json:"old"`
+	New   float64 `json:"new"`
+	Delta float64 `json:"delta"`
+}
+
+// Compare compares an older snapshot with a newer one
+func Compare(old, new *Summary) *Comparison {
+	c := &Comparison{From: old.Name, To: new.Name}
+
+	oldModules := make([]string, len(old.Modules))
+	for i, m := range old.Modules {
+		oldModules[i] = m.Path
+	}
+	newModules := make([]string, len(new.Modules))
+	for i, m := range new.Modules {
+		newModules[i] = m.Path
+	}
+	c.AddedModules = difference(newModules, oldModules)
+	c.RemovedModules = difference(oldModules, newModules)
+
+	c.AddedEdges = edgeDifference(new.Edges, old.Edges)
+	c.RemovedEdges = edgeDifference(old.Edges, new.Edges)
+
+	c.NewCycles = uncontainedCycles(new.Cycles, old.Cycles)
+	c.ResolvedCycles = resolvedCycles(old.Cycles, new.Cycles)
+
+	oldValues, newValues := old.Stats.values(), new.Stats.values()
+	for i, metric := range oldValues {
+		c.Metrics = append(c.Metrics, MetricDelta{
+			Name:  metric.name,
+			Old:   metric.value,
+			New:   newValues[i].value,
+			Delta: analysis.Round(newValues[i].value - metric.value),
+		})
+	}
+	return c
+}
+
+// namedValue is a statistic with its name
+type namedValue struct {
+	name  string
+	value float64
+}
+
+// values lists the statistics in report order
+func (s Stats) values() []namedValue {
+	return []namedValue{
+		{"modules", float64(s.Modules)},
+		{"edges", float64(s.Edges)},
+		{"layers", float64(s.Layers)},
+		{"avg_fan_out", s.AvgFanOut},
+		{"max_fan_in", float64(s.MaxFanIn)},
+		{"max_fan_out", float64(s.MaxFanOut)},
+		{"avg_instability", s.AvgInstability},
+		{"max_depth", float64(s.MaxDepth)},
+		{"cycle_groups", float64(s.CycleGroups)},
+		{"cyclic_modules", float64(s.CyclicModules)},
+	}
+}
+
+// difference returns the sorted items of a that are not in b
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		inB[item] = true
+	}
+	result := make([]string, 0)
+	for _, item := range a {
+		if !inB[item] {
+			result = append(result, item)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// edgeDifference returns the edges of a that are not in b, ignoring relations
+func edgeDifference(a, b []EdgeRecord) []EdgeRecord {
+	inB := make(map[[2]string]bool, len(b))
+	for _, edge := range b {
+		inB[[2]string{edge.From, edge.To}] = true
+	}
+	result := make([]EdgeRecord, 0)
+	for _, edge := range a {
+		if !inB[[2]string{edge.From, edge.To}] {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+// uncontainedCycles returns the cyclic components of a whose modules are not
+// all in a single component of b. A component that grew counts as new.
+func uncontainedCycles(a, b [][]string) [][]string {
+	componentOf := make(map[string]int)
+	for i, component := range b {
+		for _, path := range component {
+			componentOf[path] = i
+		}
+	}
+
+	result := make([][]string, 0)
+	for _, component := range a {
+		first, ok := componentOf[component[0]]
+		contained := ok
+		for _, path := range component[1:] {
+			if i, ok := componentOf[path]; !ok || i != first {
+				contained = false
+				break
+			}
+		}
+		if !contained {
+			result = append(result, component)
+		}
+	}
+	return result
+}
+
+// resolvedCycles returns the cyclic components of a none of whose modules
+// is in a component of b. A component that shrank is not resolved.
+func resolvedCycles(a, b [][]string) [][]string {
+	cyclic := make(map[string]bool)
+	for _, component := range b {
+		for _, path := range component {
+			cyclic[path] = true
+		}
+	}
+
+	result := make([][]string, 0)
+	for _, component := range a {
+		resolved := true
+		for _, path := range component {
+			if cyclic[path] {
+				resolved = false
+				break
+			}
+		}
+		if resolved {
+			result = append(result, component)
+		}
+	}
+	return result
+}
+
+// Format is the output format of a comparison
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "md"
+)
+
+// FormatComparison renders a comparison as text, JSON or Markdown
+func FormatComparison(c *Comparison, format Format) (string, error) {
+	switch format {
+	case FormatText:
+		return formatText(c), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize comparison: %w", err)
+		}
+		return string(data) + "\n", nil
+	case FormatMarkdown:
+		return formatMarkdown(c), nil
+	}
+	return "", fmt.Errorf("unknown format: %s", format)
+}
+
+// formatText renders a comparison as plain text
+func formatText(c *Comparison) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Graph changes from %s to %s\n\n", c.From, c.To)
+
+	b.WriteString("Metrics:\n")
+	for _, m := range c.Metrics {
+		line := fmt.Sprintf("  %-16s %10s -> %-10s %s", m.Name, formatValue(m.Old), formatValue(m.New), formatDelta(m.Delta))
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", title, len(items))
+		for _, item := range items {
+			fmt.Fprintf(&b, "  %s\n", item)
+		}
+	}
+	writeList("Added modules", prefixed("+ ", c.AddedModules))
+	writeList("Removed modules", prefixed("- ", c.RemovedModules))
+	writeList("Added dependencies", prefixed("+ ", edgeLabels(c.AddedEdges)))
+	writeList("Removed dependencies", prefixed("- ", edgeLabels(c.RemovedEdges)))
+	writeList("New cycles", prefixed("! ", cycleLabels(c.NewCycles)))
+	writeList("Resolved cycles", prefixed("✓ ", cycleLabels(c.ResolvedCycles)))
+	return b.String()
+}
+
+// formatMarkdown renders a comparison as Markdown
+func formatMarkdown(c *Comparison) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Graph Changes\n\nFrom `%s` to `%s`.\n\n", c.From, c.To)
+
+	b.WriteString("## Metrics\n\n| Metric | Old | New | Change |\n|--------|-----|-----|--------|\n")
+	for _, m := range c.Metrics {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", m.Name, formatValue(m.Old), formatValue(m.New), formatDelta(m.Delta))
+	}
+
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", title, len(items))
+		for _, item := range items {
+			fmt.Fprintf(&b, "- `%s`\n", item)
+		}
+	}
+	writeList("Added Modules", c.AddedModules)
+	writeList("Removed Modules", c.RemovedModules)
+	writeList("Added Dependencies", edgeLabels(c.AddedEdges))
+	writeList("Removed Dependencies", edgeLabels(c.RemovedEdges))
+	writeList("New Cycles", cycleLabels(c.NewCycles))
+	writeList("Resolved Cycles", cycleLabels(c.ResolvedCycles))
+	return b.String()
+}
+
+// formatValue prints a statistic without trailing zeros
+func formatValue(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
+
+// formatDelta prints a change with its sign, or nothing when there is none
+func formatDelta(delta float64) string {
+	if delta == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+g", delta)
+}
+
+// prefixed prefixes each item
+func prefixed(prefix string, items []string) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = prefix + item
+	}
+	return result
+}
+
+// edgeLabels names each edge as "from -> to"
+func edgeLabels(edges []EdgeRecord) []string {
+	labels := make([]string, len(edges))
+	for i, edge := range edges {
+		labels[i] = edge.From + " -> " + edge.To
+	}
+	return labels
+}
+
+// cycleLabels names each cyclic component by its modules
+func cycleLabels(cycles [][]string) []string {
+	labels := make([]string, len(cycles))
+	for i, cycle := range cycles {
+		labels[i] = strings.Join(cycle, ", ")
+	}
+	return labels
+}
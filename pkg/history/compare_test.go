@@ -0,0 +1,95 @@
+package history
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	old := FromGraph(createHistoryGraph(map[string][]string{
+		"a.go": {"b.go"},
+		"b.go": {"a.go"},
+		"c.go": {"a.go"},
+		"d.go": nil,
+	}), "v1")
+	new := FromGraph(createHistoryGraph(map[string][]string{
+		"a.go": nil,
+		"c.go": {"e.go"},
+		"e.go": {"c.go"},
+		"d.go": nil,
+	}), "v2")
+
+	c := Compare(old, new)
+	if strings.Join(c.AddedModules, ",") != "e.go" || strings.Join(c.RemovedModules, ",") != "b.go" {
+		t.Errorf("Modules +%v -%v", c.AddedModules, c.RemovedModules)
+	}
+	if strings.Join(edgeLabels(c.AddedEdges), ",") != "c.go -> e.go,e.go -> c.go" {
+		t.Errorf("Added edges: %v", edgeLabels(c.AddedEdges))
+	}
+	if strings.Join(edgeLabels(c.RemovedEdges), ",") != "a.go -> b.go,b.go -> a.go,c.go -> a.go" {
+		t.Errorf("Removed edges: %v", edgeLabels(c.RemovedEdges))
+	}
+	if len(c.NewCycles) != 1 || strings.Join(c.NewCycles[0], ",") != "c.go,e.go" {
+		t.Errorf("New cycles: %v", c.NewCycles)
+	}
+	if len(c.ResolvedCycles) != 1 || strings.Join(c.ResolvedCycles[0], ",") != "a.go,b.go" {
+		t.Errorf("Resolved cycles: %v", c.ResolvedCycles)
+	}
+
+	for _, m := range c.Metrics {
+		if m.Name == "modules" && (m.Old != 4 || m.New != 4 || m.Delta != 0) {
+			t.Errorf("Unexpected modules delta: %+v", m)
+		}
+	}
+}
+
+func TestCompareContainedCycle(t *testing.T) {
+	old := FromGraph(createHistoryGraph(map[string][]string{
+		"a.go": {"b.go"}, "b.go": {"c.go"}, "c.go": {"a.go"},
+	}), "v1")
+	new := FromGraph(createHistoryGraph(map[string][]string{
+		"a.go": {"b.go"}, "b.go": {"a.go"}, "c.go": nil,
+	}), "v2")
+
+	c := Compare(old, new)
+	if len(c.NewCycles) != 0 {
+		t.Errorf("A shrunken cycle should not be new: %v", c.NewCycles)
+	}
+	if len(c.ResolvedCycles) != 0 {
+		t.Errorf("A shrunken cycle is still a cycle: %v", c.ResolvedCycles)
+	}
+
+	c = Compare(new, old)
+	if len(c.NewCycles) != 1 {
+		t.Errorf("A grown cycle should be new: %v", c.NewCycles)
+	}
+}
+
+func TestFormatComparison(t *testing.T) {
+	old := FromGraph(createHistoryGraph(map[string][]string{"a.go": nil}), "v1")
+	new := FromGraph(createHistoryGraph(map[string][]string{"a.go": nil, "b.go": {"a.go"}}), "v2")
+	c := Compare(old, new)
+
+	text, err := FormatComparison(c, FormatText)
+	if err != nil {
+		t.Fatalf("FormatComparison(text) failed: %v", err)
+	}
+	for _, want := range []string{"from v1 to v2", "+1", "+ b.go", "+ b.go -> a.go"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text output missing %q:\n%s", want, text)
+		}
+	}
+
+	md, err := FormatComparison(c, FormatMarkdown)
+	if err != nil || !strings.Contains(md, "| modules | 1 | 2 | +1 |") || !strings.Contains(md, "## Added Modules (1)") {
+		t.Errorf("Unexpected Markdown output: %s, %v", md, err)
+	}
+
+	data, err := FormatComparison(c, FormatJSON)
+	if err != nil || !strings.Contains(data, `"added_edges"`) {
+		t.Errorf("Unexpected JSON output: %s, %v", data, err)
+	}
+	if _, err := FormatComparison(c, "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,181 @@
+/*
+# Module: pkg/history/summary.go
+Graph summaries for historical comparison.
+
+A summary lists the modules, dependency edges, dependency cycles and
+summary statistics of a graph at a point in time, either the current graph
+or a snapshot saved under .graphfs/snapshots, so architecture can be
+compared across releases without rebuilding old trees.
+
+## Linked Modules
+- [compare](./compare.go) - Summary comparison
+- [../graph](../graph/snapshot.go) - Graph snapshots
+- [../analysis](../analysis/metrics.go) - Structural metrics
+
+## Tags
+history, snapshot, metrics
+
+## Exports
+Summary, ModuleRecord, EdgeRecord, Stats, FromGraph, FromSnapshot, ComputeStats
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#summary.go> a code:Module ;
+    code:name "pkg/history/summary.go" ;
+    code:description "Graph summaries for historical comparison" ;
+    code:language "go" ;
+    code:layer "history" ;
+    code:linksTo <./compare.go>, <../graph/snapshot.go>, <../analysis/metrics.go> ;
+    code:exports <#Summary>, <#ModuleRecord>, <#EdgeRecord>, <#Stats>, <#FromGraph>, <#FromSnapshot>, <#ComputeStats> ;
+    code:tags "history", "snapshot", "metrics" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Summary is the structure of a graph at a point in time
+type Summary struct {
+	Name      string         `json:"name"`
+	Commit    string         `json:"commit,omitempty"` // Git commit the graph was built from
+	CreatedAt time.Time      `json:"created_at"`
+	Modules   []ModuleRecord `json:"modules"` // Sorted by path
+	Edges     []EdgeRecord   `json:"edges"`   // Sorted by source, then target
+	Cycles    [][]string     `json:"cycles"`  // Modules of each cyclic component, sorted
+	Stats     Stats          `json:"stats"`
+}
+
+// ModuleRecord is one module of a summary
+type ModuleRecord struct {
+	Path     string   `json:"path"`
+	Layer    string   `json:"layer,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// EdgeRecord is a dependency between two modules
+type EdgeRecord struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation,omitempty"`
+}
+
+// Stats summarizes the size, coupling and cycles of a graph
+type Stats struct {
+	Modules        int     `json:"modules"`
+	Edges          int     `json:"edges"`
+	Layers         int     `json:"layers"`
+	AvgFanOut      float64 `json:"avg_fan_out"`
+	MaxFanIn       int     `json:"max_fan_in"`
+	MaxFanOut      int     `json:"max_fan_out"`
+	AvgInstability float64 `json:"avg_instability"`
+	MaxDepth       int     `json:"max_depth"`
+	CycleGroups    int     `json:"cycle_groups"`   // Components with a dependency cycle
+	CyclicModules  int     `json:"cyclic_modules"` // Modules in some cycle
+}
+
+// FromGraph summarizes a graph. Dependencies on paths that are not modules
+// are left out.
+func FromGraph(g *graph.Graph, name string) *Summary {
+	summary := &Summary{
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		Modules:   make([]ModuleRecord, 0, len(g.Modules)),
+		Edges:     make([]EdgeRecord, 0),
+		Cycles:    make([][]string, 0),
+	}
+	if g.Provenance != nil {
+		summary.Commit = g.Provenance.Commit
+	}
+
+	for path, module := range g.Modules {
+		summary.Modules = append(summary.Modules, ModuleRecord{
+			Path:     path,
+			Layer:    module.Layer,
+			Language: module.Language,
+			Tags:     append([]string(nil), module.Tags...),
+		})
+		seen := make(map[string]bool)
+		for _, edge := range module.DependencyEdges() {
+			if _, ok := g.Modules[edge.Target]; !ok || seen[edge.Target] {
+				continue
+			}
+			seen[edge.Target] = true
+			summary.Edges = append(summary.Edges, EdgeRecord{From: path, To: edge.Target, Relation: edge.Relation})
+		}
+	}
+	sort.Slice(summary.Modules, func(i, j int) bool {
+		return summary.Modules[i].Path < summary.Modules[j].Path
+	})
+	sort.Slice(summary.Edges, func(i, j int) bool {
+		a, b := summary.Edges[i], summary.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+
+	// Components only; listing elementary cycles is exponential
+	for _, component := range analysis.FindCycles(g, analysis.CycleOptions{MaxCycles: 1}).Components {
+		summary.Cycles = append(summary.Cycles, component.Modules)
+	}
+
+	summary.Stats = ComputeStats(g)
+	return summary
+}
+
+// FromSnapshot summarizes the graph a snapshot recorded
+func FromSnapshot(snapshot *graph.Snapshot) *Summary {
+	summary := FromGraph(snapshot.Graph(), snapshot.Name)
+	summary.Commit = snapshot.Commit
+	summary.CreatedAt = snapshot.CreatedAt
+	return summary
+}
+
+// ComputeStats summarizes a graph
+func ComputeStats(g *graph.Graph) Stats {
+	stats := Stats{Modules: len(g.Modules)}
+
+	layers := make(map[string]bool)
+	for _, module := range g.Modules {
+		if module.Layer != "" {
+			layers[module.Layer] = true
+		}
+	}
+	stats.Layers = len(layers)
+
+	metrics := analysis.ComputeMetrics(g)
+	instability := 0.0
+	for _, m := range metrics.Modules {
+		stats.Edges += m.FanOut
+		instability += m.Instability
+		if m.FanIn > stats.MaxFanIn {
+			stats.MaxFanIn = m.FanIn
+		}
+		if m.FanOut > stats.MaxFanOut {
+			stats.MaxFanOut = m.FanOut
+		}
+		if m.Depth > stats.MaxDepth {
+			stats.MaxDepth = m.Depth
+		}
+	}
+	if stats.Modules > 0 {
+		stats.AvgFanOut = analysis.Round(float64(stats.Edges) / float64(stats.Modules))
+		stats.AvgInstability = analysis.Round(instability / float64(stats.Modules))
+	}
+
+	for _, component := range analysis.FindCycles(g, analysis.CycleOptions{MaxCycles: 1}).Components {
+		stats.CycleGroups++
+		stats.CyclicModules += len(component.Modules)
+	}
+	return stats
+}
@@ -0,0 +1,60 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func createHistoryGraph(deps map[string][]string) *graph.Graph {
+	g := &graph.Graph{Modules: make(map[string]*graph.Module)}
+	for path, dependencies := range deps {
+		g.Modules[path] = &graph.Module{Path: path, Layer: "core", Dependencies: dependencies}
+	}
+	return g
+}
+
+func TestFromGraph(t *testing.T) {
+	g := createHistoryGraph(map[string][]string{
+		"a.go": {"b.go", "fmt"},
+		"b.go": {"c.go"},
+		"c.go": {"b.go"},
+	})
+
+	s := FromGraph(g, "base")
+	if len(s.Modules) != 3 || s.Modules[0].Path != "a.go" {
+		t.Errorf("Unexpected modules: %+v", s.Modules)
+	}
+	if len(s.Edges) != 3 {
+		t.Errorf("Expected dependencies on non-modules to be left out, got %+v", s.Edges)
+	}
+	if len(s.Cycles) != 1 || len(s.Cycles[0]) != 2 {
+		t.Errorf("Unexpected cycles: %v", s.Cycles)
+	}
+
+	want := Stats{Modules: 3, Edges: 3, Layers: 1, AvgFanOut: 1, MaxFanIn: 2, MaxFanOut: 1, CycleGroups: 1, CyclicModules: 2}
+	s.Stats.AvgInstability, s.Stats.MaxDepth = 0, 0
+	if s.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", s.Stats, want)
+	}
+}
+
+func TestFromSnapshot(t *testing.T) {
+	snapshot := graph.NewSnapshot("abc123")
+	snapshot.Name = "v1"
+	snapshot.CreatedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	snapshot.Modules["a.go"] = &graph.SnapshotEntry{Module: &graph.Module{Path: "a.go", Dependencies: []string{"b.go"}}}
+	snapshot.Modules["b.go"] = &graph.SnapshotEntry{Module: &graph.Module{Path: "b.go", Layer: "core"}}
+
+	s := FromSnapshot(snapshot)
+	if s.Name != "v1" || s.Commit != "abc123" || !s.CreatedAt.Equal(snapshot.CreatedAt) {
+		t.Errorf("Unexpected summary header: %+v", s)
+	}
+	if len(s.Modules) != 2 || len(s.Edges) != 1 || s.Edges[0] != (EdgeRecord{From: "a.go", To: "b.go", Relation: "linksTo"}) {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+	if s.Stats.Modules != 2 || s.Stats.Layers != 1 {
+		t.Errorf("Unexpected stats: %+v", s.Stats)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+# Module: pkg/history/timeline.go
+Graph statistics over git history.
+
+Picks points in a range of git history (every tag, or the last commit of
+each month), extracts the tree at each point with git archive, without
+touching the working tree, and records the statistics of the graph built
+from it, for architecture-evolution reports.
+
+## Linked Modules
+- [summary](./summary.go) - Graph statistics
+- [../graph](../graph/graph.go) - Graph data structure
+- [../../internal/archive](../../internal/archive/tar.go) - Tar extraction
+
+## Tags
+history, git, metrics, timeline
+
+## Exports
+Step, StepTag, StepMonthly, ParseStep, Point, TimelineEntry, ListPoints, ExtractTree, Timeline
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#timeline.go> a code:Module ;
+    code:name "pkg/history/timeline.go" ;
+    code:description "Graph statistics over git history" ;
+    code:language "go" ;
+    code:layer "history" ;
+    code:linksTo <./summary.go>, <../graph/graph.go>, <../../internal/archive/tar.go> ;
+    code:exports <#Step>, <#StepTag>, <#StepMonthly>, <#ParseStep>, <#Point>, <#TimelineEntry>, <#ListPoints>, <#ExtractTree>, <#Timeline> ;
+    code:tags "history", "git", "metrics", "timeline" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/internal/archive"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Step is how points are picked from history
+type Step string
+
+const (
+	StepTag     Step = "tag"     // Every tag in the range
+	StepMonthly Step = "monthly" // The last commit of each month
+)
+
+// ParseStep parses a step name
+func ParseStep(name string) (Step, error) {
+	switch Step(name) {
+	case StepTag, StepMonthly:
+		return Step(name), nil
+	}
+	return "", fmt.Errorf("unknown step %q (use tag or monthly)", name)
+}
+
+// Point is a commit in history
+type Point struct {
+	Ref    string    `json:"ref"` // Tag, month (2006-01) or the ref given
+	Commit string    `json:"commit"`
+	Date   time.Time `json:"date"` // Committer date
+}
+
+// TimelineEntry is the graph statistics at a point
+type TimelineEntry struct {
+	Point
+	Stats Stats `json:"stats"`
+}
+
+// ListPoints returns the points between two refs, oldest first. The range
+// includes both ends; an empty from starts at the first commit.
+func ListPoints(repo, from, to string, step Step) ([]Point, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+	end, err := resolvePoint(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	if from != "" {
+		start, err := resolvePoint(repo, from)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, start)
+	}
+
+	switch step {
+	case StepTag:
+		args := []string{"for-each-ref", "--merged=" + end.Commit, "--format=%(refname:short)", "refs/tags"}
+		if from != "" {
+			args = append(args, "--contains="+points[0].Commit)
+		}
+		output, err := git(repo, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range strings.Fields(output) {
+			point, err := resolvePoint(repo, tag)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, point)
+		}
+	case StepMonthly:
+		rangeArg := end.Commit
+		if from != "" {
+			rangeArg = points[0].Commit + ".." + end.Commit
+		}
+		output, err := git(repo, "log", "--first-parent", "--format=%H %cI", rangeArg)
+		if err != nil {
+			return nil, err
+		}
+		// Newest first, so the first commit seen in a month is its last
+		seen := make(map[string]bool)
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			point, err := parsePoint(line)
+			if err != nil {
+				return nil, err
+			}
+			month := point.Date.Format("2006-01")
+			if !seen[month] {
+				seen[month] = true
+				point.Ref = month
+				points = append(points, point)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown step %q", step)
+	}
+	points = append(points, end)
+
+	// Oldest first, one point per commit, keeping the first name
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Date.Before(points[j].Date)
+	})
+	unique := make([]Point, 0, len(points))
+	seen := make(map[string]bool)
+	for _, point := range points {
+		if !seen[point.Commit] {
+			seen[point.Commit] = true
+			unique = append(unique, point)
+		}
+	}
+	return unique, nil
+}
+
+// resolvePoint returns the commit a ref names
+func resolvePoint(repo, ref string) (Point, error) {
+	output, err := git(repo, "show", "-s", "--format=%H %cI", ref+"^{commit}")
+	if err != nil {
+		return Point{}, fmt.Errorf("unknown revision %s: %w", ref, err)
+	}
+	point, err := parsePoint(strings.TrimSpace(output))
+	point.Ref = ref
+	return point, err
+}
+
+// parsePoint parses a "<hash> <ISO date>" line
+func parsePoint(line string) (Point, error) {
+	hash, date, ok := strings.Cut(line, " ")
+	if !ok {
+		return Point{}, fmt.Errorf("unexpected git output: %q", line)
+	}
+	when, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return Point{}, fmt.Errorf("unexpected git date %q: %w", date, err)
+	}
+	return Point{Commit: hash, Date: when}, nil
+}
+
+// git runs a git command in a repository and returns its output
+func git(repo string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repo
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return string(output), nil
+}
+
+// ExtractTree writes the files of repo (a repository or a directory in one)
+// as of a commit to a temporary directory. It returns the directory and a
+// function removing it.
+func ExtractTree(repo, commit string) (string, func(), error) {
+	output, err := git(repo, "rev-parse", "--show-toplevel", "--show-prefix")
+	if err != nil {
+		return "", nil, err
+	}
+	lines := strings.SplitN(output, "\n", 3)
+	if len(lines) < 2 {
+		return "", nil, fmt.Errorf("unexpected git rev-parse output: %q", output)
+	}
+	topLevel, prefix := lines[0], strings.TrimSuffix(strings.TrimSpace(lines[1]), "/")
+
+	// Archive the directory's tree itself, so paths are relative to it
+	treeish := commit
+	if prefix != "" {
+		treeish += ":" + prefix
+	}
+	tarball, err := git(topLevel, "archive", "--format=tar", treeish)
+	if err != nil {
+		if _, verifyErr := git(topLevel, "rev-parse", "--verify", "--quiet", commit+"^{commit}"); verifyErr != nil {
+			return "", nil, err
+		}
+		tarball = "" // The directory did not exist yet
+	}
+
+	tmpDir, err := os.MkdirTemp("", "graphfs-history-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	if err := archive.ExtractTar(strings.NewReader(tarball), tmpDir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to extract %s: %w", commit, err)
+	}
+	return tmpDir, cleanup, nil
+}
+
+// Timeline builds the graph at each point with build and records its
+// statistics. progress, if not nil, is called before each build.
+func Timeline(repo string, points []Point, build func(dir string) (*graph.Graph, error), progress func(i int, point Point)) ([]TimelineEntry, error) {
+	entries := make([]TimelineEntry, 0, len(points))
+	for i, point := range points {
+		if progress != nil {
+			progress(i, point)
+		}
+
+		dir, cleanup, err := ExtractTree(repo, point.Commit)
+		if err != nil {
+			return nil, err
+		}
+		// Metrics read Go sources, so the files stay until they are computed
+		g, err := build(dir)
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to build graph at %s: %w", point.Ref, err)
+		}
+		entries = append(entries, TimelineEntry{Point: point, Stats: ComputeStats(g)})
+		cleanup()
+	}
+	return entries, nil
+}
@@ -0,0 +1,152 @@
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// newHistoryRepo creates a repository with a commit per date, each adding a
+// file under src/, tagging the commits named in tags
+func newHistoryRepo(t *testing.T, dates []string, tags map[int]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	run := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	run("", "init", "-q")
+
+	for i, date := range dates {
+		path := filepath.Join(repo, "src", "file"+string(rune('a'+i))+".go")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package src\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(date, "add", ".")
+		run(date, "commit", "-q", "-m", "commit")
+		if tag, ok := tags[i]; ok {
+			run(date, "tag", tag)
+		}
+	}
+	return repo
+}
+
+func pointRefs(points []Point) string {
+	refs := make([]string, len(points))
+	for i, point := range points {
+		refs[i] = point.Ref
+	}
+	return strings.Join(refs, ",")
+}
+
+func TestListPoints(t *testing.T) {
+	repo := newHistoryRepo(t, []string{
+		"2025-01-05T10:00:00Z",
+		"2025-01-20T10:00:00Z",
+		"2025-02-03T10:00:00Z",
+		"2025-03-10T10:00:00Z",
+		"2025-03-12T10:00:00Z",
+	}, map[int]string{0: "v0.1", 1: "v1.0", 3: "v1.1"})
+
+	points, err := ListPoints(repo, "v1.0", "HEAD", StepTag)
+	if err != nil {
+		t.Fatalf("ListPoints(tag) failed: %v", err)
+	}
+	if got := pointRefs(points); got != "v1.0,v1.1,HEAD" {
+		t.Errorf("Tag points = %s", got)
+	}
+
+	points, err = ListPoints(repo, "", "HEAD", StepMonthly)
+	if err != nil {
+		t.Fatalf("ListPoints(monthly) failed: %v", err)
+	}
+	if got := pointRefs(points); got != "2025-01,2025-02,2025-03" {
+		t.Errorf("Monthly points = %s", got)
+	}
+	if points[0].Date.Day() != 20 {
+		t.Errorf("Expected the last commit of January, got %v", points[0].Date)
+	}
+
+	if _, err := ListPoints(repo, "no-such-tag", "HEAD", StepTag); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	repo := newHistoryRepo(t, []string{
+		"2025-01-05T10:00:00Z",
+		"2025-02-03T10:00:00Z",
+		"2025-03-10T10:00:00Z",
+	}, map[int]string{0: "v1", 2: "v2"})
+
+	points, err := ListPoints(repo, "", "", StepTag)
+	if err != nil {
+		t.Fatalf("ListPoints failed: %v", err)
+	}
+
+	// Count the files extracted at each point; the working tree is untouched
+	build := func(dir string) (*graph.Graph, error) {
+		files, err := os.ReadDir(filepath.Join(dir, "src"))
+		if err != nil {
+			return nil, err
+		}
+		g := &graph.Graph{Root: dir, Modules: make(map[string]*graph.Module)}
+		for _, file := range files {
+			path := "src/" + file.Name()
+			g.Modules[path] = &graph.Module{Path: path}
+		}
+		return g, nil
+	}
+	entries, err := Timeline(repo, points, build, nil)
+	if err != nil {
+		t.Fatalf("Timeline failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Stats.Modules != 1 || entries[1].Stats.Modules != 3 {
+		t.Errorf("Unexpected timeline: %+v", entries)
+	}
+}
+
+func TestExtractTreeSubdirectory(t *testing.T) {
+	repo := newHistoryRepo(t, []string{"2025-01-05T10:00:00Z"}, nil)
+
+	dir, cleanup, err := ExtractTree(filepath.Join(repo, "src"), "HEAD")
+	if err != nil {
+		t.Fatalf("ExtractTree failed: %v", err)
+	}
+	defer cleanup()
+	if _, err := os.Stat(filepath.Join(dir, "filea.go")); err != nil {
+		t.Errorf("Expected the subdirectory's files at the returned path: %v", err)
+	}
+
+	// A directory added later extracts as an empty tree at older commits
+	if err := os.MkdirAll(filepath.Join(repo, "later"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dir, cleanup, err = ExtractTree(filepath.Join(repo, "later"), "HEAD")
+	if err != nil {
+		t.Fatalf("ExtractTree of a new directory failed: %v", err)
+	}
+	defer cleanup()
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Expected an empty tree, got %d files", len(files))
+	}
+	if _, _, err := ExtractTree(repo, "no-such-rev"); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+}
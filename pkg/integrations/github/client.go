@@ -0,0 +1,279 @@
+/*
+# Module: pkg/integrations/github/client.go
+GitHub REST API client for pull request integration.
+
+Lists the files changed by a pull request, maintains a single graphfs
+comment on it (updated in place on later runs) and creates check runs with
+annotations. Only the endpoints graphfs needs are implemented.
+
+## Linked Modules
+- [report](./report.go) - Pull request reports
+
+## Tags
+github, integration, ci
+
+## Exports
+DefaultAPIURL, Client, NewClient, PullRequest, ChangedFile, CheckRun, Annotation
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#client.go> a code:Module ;
+    code:name "pkg/integrations/github/client.go" ;
+    code:description "GitHub REST API client for pull request integration" ;
+    code:language "go" ;
+    code:layer "integrations" ;
+    code:linksTo <./report.go> ;
+    code:exports <#DefaultAPIURL>, <#Client>, <#NewClient>, <#PullRequest>, <#ChangedFile>, <#CheckRun>, <#Annotation> ;
+    code:tags "github", "integration", "ci" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the REST API of github.com
+const DefaultAPIURL = "https://api.github.com"
+
+const (
+	requestTimeout = 30 * time.Second
+	perPage        = 100
+	maxPages       = 30 // The files endpoint lists at most 3000 files
+
+	// maxAnnotations is how many annotations one check run request accepts
+	maxAnnotations = 50
+)
+
+// Client calls the GitHub REST API for one repository
+type Client struct {
+	BaseURL string       // API root, e.g. DefaultAPIURL or a GitHub Enterprise Server URL
+	Repo    string       // Repository as owner/name
+	Token   string       // Token with pull request and checks permissions
+	HTTP    *http.Client // HTTP client (default: 30s timeout)
+}
+
+// NewClient creates a client for a repository on github.com
+func NewClient(repo, token string) *Client {
+	return &Client{
+		BaseURL: DefaultAPIURL,
+		Repo:    repo,
+		Token:   token,
+		HTTP:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// PullRequest is the part of a pull request graphfs uses
+type PullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Head    struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// ChangedFile is a file changed by a pull request
+type ChangedFile struct {
+	Path         string `json:"filename"`
+	Status       string `json:"status"` // added, removed, modified, renamed, ...
+	PreviousPath string `json:"previous_filename,omitempty"`
+}
+
+// CheckRun is a completed check run with its output
+type CheckRun struct {
+	Name        string
+	HeadSHA     string
+	Conclusion  string // success, failure or neutral
+	Title       string
+	Summary     string // Markdown
+	Annotations []Annotation
+}
+
+// Annotation marks a line range of a file in a check run
+type Annotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Level     string `json:"annotation_level"` // notice, warning or failure
+	Title     string `json:"title,omitempty"`
+	Message   string `json:"message"`
+}
+
+// PullRequest returns a pull request
+func (c *Client) PullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", c.Repo, number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// PullRequestFiles returns the files changed by a pull request
+func (c *Client) PullRequestFiles(ctx context.Context, number int) ([]ChangedFile, error) {
+	var files []ChangedFile
+	for page := 1; page <= maxPages; page++ {
+		var batch []ChangedFile
+		path := fmt.Sprintf("/repos/%s/pulls/%d/files?per_page=%d&page=%d", c.Repo, number, perPage, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+		files = append(files, batch...)
+		if len(batch) < perPage {
+			break
+		}
+	}
+	return files, nil
+}
+
+// UpsertComment updates the pull request comment containing marker, or
+// creates one, and returns its URL. The body must contain the marker for
+// later runs to find it.
+func (c *Client) UpsertComment(ctx context.Context, number int, marker, body string) (string, error) {
+	type comment struct {
+		ID      int64  `json:"id"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+	}
+
+	var existing *comment
+	for page := 1; page <= maxPages && existing == nil; page++ {
+		var batch []comment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d", c.Repo, number, perPage, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return "", err
+		}
+		for i := range batch {
+			if strings.Contains(batch[i].Body, marker) {
+				existing = &batch[i]
+				break
+			}
+		}
+		if len(batch) < perPage {
+			break
+		}
+	}
+
+	var saved comment
+	request := map[string]string{"body": body}
+	if existing != nil {
+		path := fmt.Sprintf("/repos/%s/issues/comments/%d", c.Repo, existing.ID)
+		if err := c.do(ctx, http.MethodPatch, path, request, &saved); err != nil {
+			return "", err
+		}
+		return saved.HTMLURL, nil
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", c.Repo, number)
+	if err := c.do(ctx, http.MethodPost, path, request, &saved); err != nil {
+		return "", err
+	}
+	return saved.HTMLURL, nil
+}
+
+// CreateCheckRun creates a completed check run and returns its URL.
+// Annotations beyond the per-request limit are added by updating the run.
+func (c *Client) CreateCheckRun(ctx context.Context, run CheckRun) (string, error) {
+	output := func(annotations []Annotation) map[string]interface{} {
+		return map[string]interface{}{
+			"title":       run.Title,
+			"summary":     run.Summary,
+			"annotations": annotations,
+		}
+	}
+	batch := func(i int) []Annotation {
+		end := i + maxAnnotations
+		if end > len(run.Annotations) {
+			end = len(run.Annotations)
+		}
+		return run.Annotations[i:end]
+	}
+
+	var created struct {
+		ID      int64  `json:"id"`
+		HTMLURL string `json:"html_url"`
+	}
+	request := map[string]interface{}{
+		"name":         run.Name,
+		"head_sha":     run.HeadSHA,
+		"status":       "completed",
+		"conclusion":   run.Conclusion,
+		"completed_at": time.Now().UTC().Format(time.RFC3339),
+		"output":       output(batch(0)),
+	}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/check-runs", c.Repo), request, &created); err != nil {
+		return "", err
+	}
+
+	for i := maxAnnotations; i < len(run.Annotations); i += maxAnnotations {
+		path := fmt.Sprintf("/repos/%s/check-runs/%d", c.Repo, created.ID)
+		if err := c.do(ctx, http.MethodPatch, path, map[string]interface{}{"output": output(batch(i))}, nil); err != nil {
+			return "", err
+		}
+	}
+	return created.HTMLURL, nil
+}
+
+// do sends a request and decodes the JSON response into out, if not nil
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub API %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(data, &apiError) != nil || apiError.Message == "" {
+			apiError.Message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("GitHub API %s %s returned %s: %s", method, path, resp.Status, apiError.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GitHub API response: %w", err)
+	}
+	return nil
+}
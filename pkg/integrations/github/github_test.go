@@ -0,0 +1,177 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/rules"
+)
+
+// fakeAPI records requests and answers them from handlers keyed by
+// "METHOD path"
+type fakeAPI struct {
+	mu       sync.Mutex
+	requests []string
+	bodies   []map[string]interface{}
+	handlers map[string]func(w http.ResponseWriter, r *http.Request)
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *Client) {
+	api := &fakeAPI{handlers: make(map[string]func(http.ResponseWriter, *http.Request))}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		var body map[string]interface{}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+
+		key := r.Method + " " + r.URL.RequestURI()
+		api.mu.Lock()
+		api.requests = append(api.requests, key)
+		api.bodies = append(api.bodies, body)
+		handler, ok := api.handlers[key]
+		api.mu.Unlock()
+		if !ok {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("acme/app", "secret")
+	client.BaseURL = server.URL
+	return api, client
+}
+
+func (a *fakeAPI) handle(key string, status int, response string) {
+	a.handlers[key] = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, response)
+	}
+}
+
+func TestClient_PullRequestFiles(t *testing.T) {
+	api, client := newFakeAPI(t)
+
+	var page []string
+	for i := 0; i < perPage; i++ {
+		page = append(page, fmt.Sprintf(`{"filename":"f%d.go","status":"modified"}`, i))
+	}
+	api.handle("GET /repos/acme/app/pulls/7/files?per_page=100&page=1", 200, "["+strings.Join(page, ",")+"]")
+	api.handle("GET /repos/acme/app/pulls/7/files?per_page=100&page=2", 200, `[{"filename":"new.go","status":"renamed","previous_filename":"old.go"}]`)
+
+	files, err := client.PullRequestFiles(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("PullRequestFiles() error = %v", err)
+	}
+	if len(files) != perPage+1 || files[perPage].Path != "new.go" || files[perPage].PreviousPath != "old.go" {
+		t.Errorf("Unexpected files: %d, last %+v", len(files), files[len(files)-1])
+	}
+
+	client.Token = "wrong"
+	if _, err := client.PullRequest(context.Background(), 7); err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("Expected an API error, got %v", err)
+	}
+}
+
+func TestClient_UpsertComment(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("GET /repos/acme/app/issues/7/comments?per_page=100&page=1", 200, `[{"id":1,"body":"LGTM"}]`)
+	api.handle("POST /repos/acme/app/issues/7/comments", 201, `{"id":2,"html_url":"https://github.com/acme/app/pull/7#issuecomment-2"}`)
+
+	url, err := client.UpsertComment(context.Background(), 7, CommentMarker, CommentMarker+"\nfirst")
+	if err != nil || !strings.HasSuffix(url, "issuecomment-2") {
+		t.Fatalf("UpsertComment() = %q, %v", url, err)
+	}
+
+	// The second run updates the comment holding the marker
+	api.handle("GET /repos/acme/app/issues/7/comments?per_page=100&page=1", 200,
+		`[{"id":1,"body":"LGTM"},{"id":2,"body":"`+CommentMarker+`\nfirst"}]`)
+	api.handle("PATCH /repos/acme/app/issues/comments/2", 200, `{"id":2,"html_url":"https://github.com/acme/app/pull/7#issuecomment-2"}`)
+	if _, err := client.UpsertComment(context.Background(), 7, CommentMarker, CommentMarker+"\nsecond"); err != nil {
+		t.Fatalf("UpsertComment() error = %v", err)
+	}
+	last := api.requests[len(api.requests)-1]
+	if last != "PATCH /repos/acme/app/issues/comments/2" || api.bodies[len(api.bodies)-1]["body"] != CommentMarker+"\nsecond" {
+		t.Errorf("Expected the comment to be updated, got %s %v", last, api.bodies[len(api.bodies)-1])
+	}
+}
+
+func TestClient_CreateCheckRun(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("POST /repos/acme/app/check-runs", 201, `{"id":9,"html_url":"https://github.com/acme/app/runs/9"}`)
+	api.handle("PATCH /repos/acme/app/check-runs/9", 200, `{"id":9}`)
+
+	run := CheckRun{Name: "graphfs", HeadSHA: "abc", Conclusion: "failure", Title: "t", Summary: "s"}
+	for i := 0; i < 120; i++ {
+		run.Annotations = append(run.Annotations, Annotation{Path: "a.go", StartLine: 1, EndLine: 1, Level: "failure", Message: "m"})
+	}
+	url, err := client.CreateCheckRun(context.Background(), run)
+	if err != nil || url != "https://github.com/acme/app/runs/9" {
+		t.Fatalf("CreateCheckRun() = %q, %v", url, err)
+	}
+
+	// 50 annotations are created with the run, the other 70 in two updates
+	if strings.Join(api.requests, ", ") != "POST /repos/acme/app/check-runs, PATCH /repos/acme/app/check-runs/9, PATCH /repos/acme/app/check-runs/9" {
+		t.Fatalf("Unexpected requests: %v", api.requests)
+	}
+	var counts []int
+	for _, body := range api.bodies {
+		counts = append(counts, len(body["output"].(map[string]interface{})["annotations"].([]interface{})))
+	}
+	if fmt.Sprint(counts) != "[50 50 20]" {
+		t.Errorf("Annotation batches = %v", counts)
+	}
+	if api.bodies[0]["conclusion"] != "failure" || api.bodies[0]["head_sha"] != "abc" {
+		t.Errorf("Unexpected check run: %v", api.bodies[0])
+	}
+}
+
+func TestReport(t *testing.T) {
+	layering := &rules.Rule{ID: "layering", Name: "Layer matrix", Severity: rules.SeverityError}
+	tags := &rules.Rule{ID: "tags", Name: "Tags", Severity: rules.SeverityWarning}
+	result := &rules.ValidationResult{Violations: []rules.Violation{
+		{Rule: tags, FilePath: "api/handler.go", Message: "Missing | tags"},
+		{Rule: layering, FilePath: "api/handler.go", LineNumber: 12, Message: "api -> store", Suggestion: "Go through the service"},
+		{Rule: layering, FilePath: "legacy/old.go", Message: "legacy -> cli"},
+	}}
+	change := &analysis.ChangeSummary{Modules: []analysis.ChangedModule{{Path: "api/handler.go", Layer: "api"}}, Layers: []string{"api"}}
+	impact := &analysis.ImpactResult{RiskLevel: analysis.RiskLevelHigh, TotalImpactedModules: 4, RiskFactors: []string{"Many dependents"}}
+
+	report := NewReport(result, []string{"api/handler.go"}, change, impact)
+	if len(report.Violations) != 2 || report.OtherViolations != 1 || report.Violations[0].Rule != layering {
+		t.Fatalf("Unexpected report violations: %+v", report.Violations)
+	}
+	if report.Conclusion() != "failure" || report.Title() != "2 violation(s) in changed files, high impact on 4 module(s)" {
+		t.Errorf("Conclusion() = %s, Title() = %s", report.Conclusion(), report.Title())
+	}
+
+	markdown := report.Markdown()
+	for _, want := range []string{CommentMarker, "| error | `api/handler.go:12` | layering | api -> store |", `Missing \| tags`,
+		"1 violation(s) outside the changed files", "## Impact: HIGH", "- Many dependents", "## Change summary"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, markdown)
+		}
+	}
+
+	annotations := report.Annotations()
+	if len(annotations) != 2 || annotations[0].StartLine != 12 || annotations[0].Level != "failure" ||
+		!strings.Contains(annotations[0].Message, "Go through the service") || annotations[1].StartLine != 1 || annotations[1].Level != "warning" {
+		t.Errorf("Unexpected annotations: %+v", annotations)
+	}
+
+	if clean := NewReport(&rules.ValidationResult{}, nil, nil, nil); clean.Conclusion() != "success" || clean.Title() != "No violations in changed files" {
+		t.Errorf("Unexpected clean report: %s, %s", clean.Conclusion(), clean.Title())
+	}
+}
@@ -0,0 +1,216 @@
+/*
+# Module: pkg/integrations/github/report.go
+Pull request reports of rule violations and change impact.
+
+Combines the rule violations in a pull request's changed files with the
+change summary and impact analysis of the changed modules, rendered as a
+Markdown comment or as a check run with one annotation per violation.
+
+## Linked Modules
+- [client](./client.go) - GitHub REST API client
+- [../../rules](../../rules/rule.go) - Rule violations
+- [../../analysis](../../analysis/change_summary.go) - Change summaries
+- [../../analysis](../../analysis/impact.go) - Impact analysis
+
+## Tags
+github, integration, ci, report
+
+## Exports
+CommentMarker, Report, NewReport
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#report.go> a code:Module ;
+    code:name "pkg/integrations/github/report.go" ;
+    code:description "Pull request reports of rule violations and change impact" ;
+    code:language "go" ;
+    code:layer "integrations" ;
+    code:linksTo <./client.go>, <../../rules/rule.go>, <../../analysis/change_summary.go>, <../../analysis/impact.go> ;
+    code:exports <#CommentMarker>, <#Report>, <#NewReport> ;
+    code:tags "github", "integration", "ci", "report" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/rules"
+)
+
+// CommentMarker identifies the pull request comment graphfs maintains
+const CommentMarker = "<!-- graphfs-ci -->"
+
+// maxBodyLength keeps comments and check summaries under GitHub's 65536
+// character limit
+const maxBodyLength = 60000
+
+// Report is what graphfs reports on a pull request
+type Report struct {
+	Violations      []rules.Violation       // Violations in the changed files
+	OtherViolations int                     // Violations elsewhere, not reported
+	Change          *analysis.ChangeSummary // Modules touched and downstream
+	Impact          *analysis.ImpactResult  // Nil when no module changed
+	MaxDownstream   int                     // Downstream modules listed (0 for all)
+}
+
+// NewReport keeps the violations of a validation result that are in the
+// changed files (paths relative to the graph root)
+func NewReport(result *rules.ValidationResult, changed []string, change *analysis.ChangeSummary, impact *analysis.ImpactResult) *Report {
+	inChange := make(map[string]bool, len(changed))
+	for _, path := range changed {
+		inChange[path] = true
+	}
+
+	report := &Report{Change: change, Impact: impact}
+	if result == nil {
+		return report
+	}
+	for _, v := range result.Violations {
+		if inChange[v.FilePath] {
+			report.Violations = append(report.Violations, v)
+		} else {
+			report.OtherViolations++
+		}
+	}
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		a, b := report.Violations[i], report.Violations[j]
+		if severityRank(a.Rule.Severity) != severityRank(b.Rule.Severity) {
+			return severityRank(a.Rule.Severity) > severityRank(b.Rule.Severity)
+		}
+		return a.FilePath < b.FilePath
+	})
+	return report
+}
+
+// severityRank orders severities from info to error
+func severityRank(severity rules.Severity) int {
+	switch severity {
+	case rules.SeverityError:
+		return 3
+	case rules.SeverityWarning:
+		return 2
+	}
+	return 1
+}
+
+// Errors returns the number of error-level violations
+func (r *Report) Errors() int {
+	count := 0
+	for _, v := range r.Violations {
+		if v.Rule.Severity == rules.SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// Conclusion returns the check run conclusion: failure with error-level
+// violations, else success
+func (r *Report) Conclusion() string {
+	if r.Errors() > 0 {
+		return "failure"
+	}
+	return "success"
+}
+
+// Title returns a one-line summary
+func (r *Report) Title() string {
+	title := fmt.Sprintf("%d violation(s) in changed files", len(r.Violations))
+	if len(r.Violations) == 0 {
+		title = "No violations in changed files"
+	}
+	if r.Impact != nil {
+		title += fmt.Sprintf(", %s impact on %d module(s)", strings.ToLower(string(r.Impact.RiskLevel)), r.Impact.TotalImpactedModules)
+	}
+	return title
+}
+
+// Markdown renders the report as a pull request comment, starting with
+// CommentMarker
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(CommentMarker + "\n")
+	sb.WriteString("# GraphFS report\n\n")
+	sb.WriteString(r.Title() + ".\n\n")
+
+	fmt.Fprintf(&sb, "## Rule violations (%d)\n\n", len(r.Violations))
+	if len(r.Violations) == 0 {
+		sb.WriteString("None in the changed files.\n")
+	} else {
+		sb.WriteString("| Severity | File | Rule | Message |\n|---|---|---|---|\n")
+		for _, v := range r.Violations {
+			location := v.FilePath
+			if v.LineNumber > 0 {
+				location = fmt.Sprintf("%s:%d", v.FilePath, v.LineNumber)
+			}
+			fmt.Fprintf(&sb, "| %s | `%s` | %s | %s |\n", v.Rule.Severity, location, tableCell(v.Rule.ID), tableCell(v.Message))
+		}
+	}
+	if r.OtherViolations > 0 {
+		fmt.Fprintf(&sb, "\n%d violation(s) outside the changed files are not shown.\n", r.OtherViolations)
+	}
+
+	if r.Impact != nil {
+		fmt.Fprintf(&sb, "\n## Impact: %s\n\n", r.Impact.RiskLevel)
+		fmt.Fprintf(&sb, "%d module(s) transitively affected (%.1f%% of the graph), up to %d level(s) deep.\n",
+			r.Impact.TotalImpactedModules, r.Impact.ImpactPercentage, r.Impact.MaxImpactDepth)
+		for _, factor := range r.Impact.RiskFactors {
+			fmt.Fprintf(&sb, "- %s\n", factor)
+		}
+	}
+
+	if r.Change != nil {
+		sb.WriteString("\n")
+		sb.WriteString(r.Change.Markdown(r.MaxDownstream))
+	}
+
+	body := sb.String()
+	if len(body) > maxBodyLength {
+		body = strings.ToValidUTF8(body[:maxBodyLength], "") + "\n\n_Report truncated._\n"
+	}
+	return body
+}
+
+// Annotations returns a check run annotation for each violation
+func (r *Report) Annotations() []Annotation {
+	annotations := make([]Annotation, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		line := v.LineNumber
+		if line < 1 {
+			line = 1
+		}
+		level := "notice"
+		switch v.Rule.Severity {
+		case rules.SeverityError:
+			level = "failure"
+		case rules.SeverityWarning:
+			level = "warning"
+		}
+		message := v.Message
+		if v.Suggestion != "" {
+			message += "\n\n" + v.Suggestion
+		}
+		annotations = append(annotations, Annotation{
+			Path:      v.FilePath,
+			StartLine: line,
+			EndLine:   line,
+			Level:     level,
+			Title:     v.Rule.Name,
+			Message:   message,
+		})
+	}
+	return annotations
+}
+
+// tableCell escapes text for a Markdown table cell
+func tableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,268 @@
+/*
+# Module: pkg/lifecycle/lifecycle.go
+Lifecycle management for long-running modes.
+
+A Manager owns the root context of a long-running command (serve, watch,
+preview, repl). It is cancelled on SIGINT/SIGTERM, runs background workers
+that honour it, and on shutdown stops registered components (watchers, HTTP
+servers, stores) in reverse order within one deadline. Shutdown returns a
+status report of every worker and component, so a sidecar supervisor can
+tell a clean stop from one that timed out.
+
+## Linked Modules
+- [../server](../server/server.go) - HTTP server
+- [../watch](../watch/watcher.go) - File system watcher
+
+## Tags
+lifecycle, shutdown, signals, context
+
+## Exports
+Manager, New, Status, Report, StateStopped, StateFailed, StateTimeout
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#lifecycle.go> a code:Module ;
+    code:name "pkg/lifecycle/lifecycle.go" ;
+    code:description "Lifecycle management for long-running modes" ;
+    code:language "go" ;
+    code:layer "lifecycle" ;
+    code:linksTo <../server/server.go>, <../watch/watcher.go> ;
+    code:exports <#Manager>, <#New>, <#Status>, <#Report>, <#StateStopped>, <#StateFailed>, <#StateTimeout> ;
+    code:tags "lifecycle", "shutdown", "signals", "context" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Shutdown states of a worker or component
+const (
+	StateStopped = "stopped" // Stopped within the deadline
+	StateFailed  = "failed"  // Stop returned an error
+	StateTimeout = "timeout" // Still running at the deadline
+)
+
+// Manager owns the root context of a long-running command and shuts down
+// its workers and components
+type Manager struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	stopSignal func()
+
+	mu         sync.Mutex
+	components []component
+	workers    []*worker
+	signal     os.Signal // Signal that triggered shutdown, if any
+
+	shutdownOnce sync.Once
+	shutdown     *Report // Report of the first Shutdown
+}
+
+// component is a resource stopped on shutdown
+type component struct {
+	name string
+	stop func(context.Context) error
+}
+
+// worker is a goroutine started with Go
+type worker struct {
+	name string
+	done chan struct{}
+}
+
+// New creates a manager whose context is derived from parent and cancelled
+// when one of the signals arrives
+func New(parent context.Context, signals ...os.Signal) *Manager {
+	ctx, cancel := context.WithCancel(parent)
+	m := &Manager{ctx: ctx, cancel: cancel, stopSignal: func() {}}
+
+	if len(signals) > 0 {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, signals...)
+		stop := make(chan struct{})
+		var once sync.Once
+		m.stopSignal = func() {
+			once.Do(func() {
+				signal.Stop(sigChan)
+				close(stop)
+			})
+		}
+		go func() {
+			select {
+			case sig := <-sigChan:
+				m.mu.Lock()
+				m.signal = sig
+				m.mu.Unlock()
+				cancel()
+			case <-stop:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	return m
+}
+
+// Context returns the root context, cancelled on a signal or shutdown
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Done returns a channel closed when shutdown was requested
+func (m *Manager) Done() <-chan struct{} {
+	return m.ctx.Done()
+}
+
+// Signal returns the signal that requested shutdown, or nil
+func (m *Manager) Signal() os.Signal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.signal
+}
+
+// Register adds a component stopped on shutdown. Components are stopped in
+// reverse order of registration, so register dependencies first.
+func (m *Manager) Register(name string, stop func(context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, component{name: name, stop: stop})
+}
+
+// Go runs fn in a worker goroutine. fn must return once its context is
+// cancelled; shutdown waits for it until the deadline.
+func (m *Manager) Go(name string, fn func(context.Context)) {
+	w := &worker{name: name, done: make(chan struct{})}
+	m.mu.Lock()
+	m.workers = append(m.workers, w)
+	m.mu.Unlock()
+
+	go func() {
+		defer close(w.done)
+		fn(m.ctx)
+	}()
+}
+
+// Shutdown cancels the root context, stops the components in reverse order
+// and waits for the workers, all within timeout. Components reached after
+// the deadline are still stopped, with an expired context. Later calls
+// return the first report.
+func (m *Manager) Shutdown(timeout time.Duration) *Report {
+	m.shutdownOnce.Do(func() {
+		m.shutdown = m.stop(timeout)
+	})
+	return m.shutdown
+}
+
+// stop shuts everything down and returns the report
+func (m *Manager) stop(timeout time.Duration) *Report {
+	m.mu.Lock()
+	components := append([]component{}, m.components...)
+	workers := append([]*worker{}, m.workers...)
+	m.mu.Unlock()
+
+	m.stopSignal()
+	m.cancel()
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	report := &Report{Timeout: timeout}
+	if sig := m.Signal(); sig != nil {
+		report.Signal = sig.String()
+	}
+	for i := len(components) - 1; i >= 0; i-- {
+		report.Components = append(report.Components, stopComponent(ctx, components[i]))
+	}
+	for _, w := range workers {
+		report.Workers = append(report.Workers, waitWorker(ctx, w))
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+// stopComponent stops a component, giving up at the deadline
+func stopComponent(ctx context.Context, c component) Status {
+	start := time.Now()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- c.stop(ctx)
+	}()
+
+	status := Status{Name: c.name, State: StateStopped}
+	select {
+	case err := <-errChan:
+		if err != nil {
+			status.State = StateFailed
+			status.Error = err.Error()
+		}
+	case <-ctx.Done():
+		status.State = StateTimeout
+	}
+	status.Duration = time.Since(start)
+	return status
+}
+
+// waitWorker waits for a worker to return, giving up at the deadline
+func waitWorker(ctx context.Context, w *worker) Status {
+	start := time.Now()
+	status := Status{Name: w.name, State: StateStopped}
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		status.State = StateTimeout
+	}
+	status.Duration = time.Since(start)
+	return status
+}
+
+// Status is the shutdown outcome of a worker or component
+type Status struct {
+	Name     string        `json:"name"`
+	State    string        `json:"state"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Report is the shutdown status report
+type Report struct {
+	Signal     string        `json:"signal,omitempty"` // Signal that requested shutdown
+	Timeout    time.Duration `json:"timeout"`
+	Duration   time.Duration `json:"duration"`
+	Components []Status      `json:"components"` // In stop order
+	Workers    []Status      `json:"workers"`
+}
+
+// Clean returns true if everything stopped within the deadline
+func (r *Report) Clean() bool {
+	return r.Err() == nil
+}
+
+// Err returns an error naming what failed or timed out, or nil if the
+// shutdown was clean
+func (r *Report) Err() error {
+	var problems []string
+	for _, status := range append(append([]Status{}, r.Components...), r.Workers...) {
+		switch status.State {
+		case StateFailed:
+			problems = append(problems, fmt.Sprintf("%s: %s", status.Name, status.Error))
+		case StateTimeout:
+			problems = append(problems, fmt.Sprintf("%s: still running after %v", status.Name, r.Timeout))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unclean shutdown: %s", strings.Join(problems, "; "))
+}
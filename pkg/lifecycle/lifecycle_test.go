@@ -0,0 +1,95 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	m := New(context.Background())
+
+	var mu sync.Mutex
+	var order []string
+	stop := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return err
+		}
+	}
+	m.Register("store", stop("store", nil))
+	m.Register("http server", stop("http server", nil))
+
+	workerStopped := false
+	m.Go("refresh", func(ctx context.Context) {
+		<-ctx.Done()
+		workerStopped = true
+	})
+
+	report := m.Shutdown(time.Second)
+	if want := []string{"http server", "store"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Stop order = %v, want %v", order, want)
+	}
+	if !workerStopped || len(report.Workers) != 1 || report.Workers[0].State != StateStopped {
+		t.Errorf("Expected the worker to stop, got %+v", report.Workers)
+	}
+	if !report.Clean() || report.Err() != nil {
+		t.Errorf("Expected a clean shutdown, got %v", report.Err())
+	}
+	if m.Context().Err() == nil {
+		t.Error("Expected the context to be cancelled")
+	}
+
+	// Shutdown runs once
+	if again := m.Shutdown(time.Second); again != report || len(order) != 2 {
+		t.Error("Expected a second Shutdown to return the first report")
+	}
+}
+
+func TestManager_Shutdown_Unclean(t *testing.T) {
+	m := New(context.Background())
+	m.Register("hung", func(context.Context) error {
+		select {}
+	})
+	m.Register("store", func(context.Context) error { return errors.New("disk full") })
+	release := make(chan struct{})
+	defer close(release)
+	m.Go("stuck", func(context.Context) { <-release })
+
+	report := m.Shutdown(50 * time.Millisecond)
+	states := map[string]string{}
+	for _, status := range append(report.Components, report.Workers...) {
+		states[status.Name] = status.State
+	}
+	want := map[string]string{"hung": StateTimeout, "store": StateFailed, "stuck": StateTimeout}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("States = %v, want %v", states, want)
+	}
+
+	err := report.Err()
+	if err == nil || !strings.Contains(err.Error(), "store: disk full") || !strings.Contains(err.Error(), "stuck: still running") {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestManager_Signal(t *testing.T) {
+	m := New(context.Background(), syscall.SIGUSR1)
+	defer m.Shutdown(time.Second)
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case <-m.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the signal to cancel the context")
+	}
+	if report := m.Shutdown(time.Second); report.Signal != syscall.SIGUSR1.String() {
+		t.Errorf("Signal = %q, want %q", report.Signal, syscall.SIGUSR1.String())
+	}
+}
@@ -0,0 +1,423 @@
+/*
+# Module: pkg/lsp/document.go
+Open documents and their LinkedDoc statements.
+
+Finds the LinkedDoc blocks of an open document, strips line comment leaders
+while keeping columns, and tokenizes the Turtle inside into statements with
+source ranges, so hover, definition and diagnostics can map positions to
+subjects, predicates and objects. Link targets are resolved like the graph
+builder resolves them.
+
+## Linked Modules
+- [../parser](../parser/comments.go) - LinkedDoc comment styles
+- [../scanner](../scanner/language.go) - Language detection
+
+## Tags
+lsp, linkeddoc, turtle, tokenizer
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#document.go> a code:Module ;
+    code:name "pkg/lsp/document.go" ;
+    code:description "Open documents and their LinkedDoc statements" ;
+    code:language "go" ;
+    code:layer "lsp" ;
+    code:linksTo <../parser/comments.go>, <../scanner/language.go> ;
+    code:tags "lsp", "linkeddoc", "turtle", "tokenizer" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package lsp
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// codeNamespace is the namespace of LinkedDoc predicates
+const codeNamespace = "https://schema.codedoc.org/"
+
+// Token kinds
+const (
+	tokenIRI     = iota
+	tokenName    // Prefixed name or the "a" keyword
+	tokenLiteral // Quoted string, with any language tag or datatype
+	tokenPunct   // One of ; , . [ ] ( )
+)
+
+// token is a Turtle token with its range in the document
+type token struct {
+	kind int
+	text string
+	rng  Range
+}
+
+// statement is a subject, predicate and object triple in a LinkedDoc block.
+// Blank node subjects and objects are their "[" token.
+type statement struct {
+	subject   token
+	predicate token
+	object    token
+}
+
+// block is the lines of a LinkedDoc block, between its markers
+type block struct {
+	start, end int // First and last line, zero-based
+	leader     string
+}
+
+// document is an open text document
+type document struct {
+	uri      string
+	path     string // Slash-separated path relative to the server root
+	language string
+	lines    []string
+
+	blocks     []block
+	statements []statement
+	prefix     string // Prefix bound to the LinkedDoc namespace
+}
+
+// newDocument parses the LinkedDoc blocks of a document
+func newDocument(uri, relPath, text string) *document {
+	d := &document{
+		uri:      uri,
+		path:     relPath,
+		language: scanner.DetectLanguageKey(relPath),
+		lines:    strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"),
+		prefix:   "code",
+	}
+	d.findBlocks()
+	for _, b := range d.blocks {
+		d.statements = append(d.statements, d.parseStatements(b)...)
+	}
+	return d
+}
+
+// findBlocks finds the blocks between start and end markers, remembering
+// the comment leader written before each start marker
+func (d *document) findBlocks() {
+	style := parser.CommentStyleFor(d.language)
+	start := -1
+	var leader string
+	for i, line := range d.lines {
+		if i < style.SkipLines {
+			continue
+		}
+		if start == -1 {
+			if idx := strings.Index(line, style.StartMarker); idx != -1 {
+				start, leader = i+1, strings.TrimSpace(line[:idx])
+			}
+			continue
+		}
+		if strings.Contains(line, style.EndMarker) {
+			d.blocks = append(d.blocks, block{start: start, end: i - 1, leader: leader})
+			start = -1
+		}
+	}
+}
+
+// inBlock returns the block containing a line
+func (d *document) inBlock(line int) (block, bool) {
+	for _, b := range d.blocks {
+		if line >= b.start && line <= b.end {
+			return b, true
+		}
+	}
+	return block{}, false
+}
+
+// body returns the byte offset where a block line's content starts, after
+// indentation and comment leader
+func (b block) body(line string) int {
+	offset := len(line) - len(strings.TrimLeft(line, " \t"))
+	if b.leader != "" && strings.HasPrefix(line[offset:], b.leader) {
+		offset += len(b.leader)
+	}
+	return offset
+}
+
+// tokenize splits the lines of a block into tokens, skipping prefix
+// declarations and comments
+func (d *document) tokenize(b block) []token {
+	var tokens []token
+	for i := b.start; i <= b.end && i < len(d.lines); i++ {
+		line := d.lines[i]
+		pos := b.body(line)
+		rest := strings.TrimSpace(line[pos:])
+		if strings.HasPrefix(rest, "@prefix") || strings.HasPrefix(rest, "@base") {
+			d.readPrefix(rest)
+			continue
+		}
+
+		for pos < len(line) {
+			c := line[pos]
+			start := pos
+			kind := tokenName
+			switch {
+			case c == ' ' || c == '\t':
+				pos++
+				continue
+			case c == '#':
+				pos = len(line)
+				continue
+			case c == '<':
+				kind = tokenIRI
+				if end := strings.IndexByte(line[pos:], '>'); end != -1 {
+					pos += end + 1
+				} else {
+					pos = len(line)
+				}
+			case c == '"':
+				kind = tokenLiteral
+				pos = literalEnd(line, pos)
+			case strings.IndexByte(";,.[]()", c) != -1:
+				kind = tokenPunct
+				pos++
+			default:
+				for pos < len(line) && !strings.ContainsRune(" \t;,[]()<\"", rune(line[pos])) {
+					pos++
+				}
+				// A trailing "." ends the statement
+				for pos > start+1 && line[pos-1] == '.' {
+					pos--
+				}
+			}
+			tokens = append(tokens, token{kind: kind, text: line[start:pos], rng: Range{
+				Start: Position{Line: i, Character: utf16Len(line[:start])},
+				End:   Position{Line: i, Character: utf16Len(line[:pos])},
+			}})
+		}
+	}
+	return tokens
+}
+
+// literalEnd returns the offset after a quoted literal starting at pos,
+// including any language tag or datatype
+func literalEnd(line string, pos int) int {
+	pos++
+	for pos < len(line) && line[pos] != '"' {
+		if line[pos] == '\\' {
+			pos++
+		}
+		pos++
+	}
+	if pos < len(line) {
+		pos++
+	}
+	for pos < len(line) && !strings.ContainsRune(" \t;,.[]()", rune(line[pos])) {
+		pos++
+	}
+	return min(pos, len(line))
+}
+
+// readPrefix remembers the prefix bound to the LinkedDoc namespace
+func (d *document) readPrefix(declaration string) {
+	fields := strings.Fields(declaration)
+	if len(fields) >= 3 && fields[0] == "@prefix" && strings.Trim(fields[2], "<>") == codeNamespace {
+		d.prefix = strings.TrimSuffix(fields[1], ":")
+	}
+}
+
+// parseStatements turns the tokens of a block into statements. Malformed
+// Turtle is skipped up to the next "."; the parser reports the error.
+func (d *document) parseStatements(b block) []statement {
+	const (
+		expectSubject = iota
+		expectPredicate
+		expectObject
+		afterObject
+	)
+
+	type frame struct{ subject, predicate token }
+	var (
+		statements []statement
+		stack      []frame
+		current    frame
+		state      = expectSubject
+	)
+	for _, t := range d.tokenize(b) {
+		switch {
+		case t.kind == tokenPunct && t.text == ".":
+			stack, state = nil, expectSubject
+		case state == expectSubject && (t.kind == tokenIRI || t.kind == tokenName):
+			current, state = frame{subject: t}, expectPredicate
+		case state == expectSubject && t.text == "[":
+			current, state = frame{subject: t}, expectPredicate
+		case state == expectPredicate && (t.kind == tokenIRI || t.kind == tokenName):
+			current.predicate, state = t, expectObject
+		case state == expectObject && t.text == "[":
+			statements = append(statements, statement{subject: current.subject, predicate: current.predicate, object: t})
+			stack = append(stack, current)
+			current, state = frame{subject: t}, expectPredicate
+		case state == expectObject && t.kind != tokenPunct:
+			statements = append(statements, statement{subject: current.subject, predicate: current.predicate, object: t})
+			state = afterObject
+		case t.text == "]" && len(stack) > 0 && (state == afterObject || state == expectPredicate):
+			current, stack, state = stack[len(stack)-1], stack[:len(stack)-1], afterObject
+		case state == afterObject && t.text == ",":
+			state = expectObject
+		case (state == afterObject || state == expectPredicate) && t.text == ";":
+			state = expectPredicate
+		default:
+			// Skip to the end of the statement
+			state = -1
+		}
+	}
+	return statements
+}
+
+// at returns the statement and the token of it at a position
+func (d *document) at(p Position) (statement, token, bool) {
+	for _, s := range d.statements {
+		for _, t := range []token{s.object, s.predicate, s.subject} {
+			if t.rng.contains(p) {
+				return s, t, true
+			}
+		}
+	}
+	return statement{}, token{}, false
+}
+
+// isModuleSubject reports whether a subject is typed code:Module
+func (d *document) isModuleSubject(t token) bool {
+	for _, s := range d.statements {
+		if s.subject.text == t.text && s.predicate.text == "a" && d.localName(s.object) == "Module" {
+			return true
+		}
+	}
+	return false
+}
+
+// lineLength returns the length of a line in UTF-16 code units
+func (d *document) lineLength(line int) int {
+	if line >= len(d.lines) {
+		return 0
+	}
+	return utf16Len(d.lines[line])
+}
+
+// localName returns the LinkedDoc name of a prefixed name or IRI, or "" if
+// it is not in the LinkedDoc namespace
+func (d *document) localName(t token) string {
+	switch t.kind {
+	case tokenName:
+		if name, ok := strings.CutPrefix(t.text, d.prefix+":"); ok {
+			return name
+		}
+	case tokenIRI:
+		if name, ok := strings.CutPrefix(strings.Trim(t.text, "<>"), codeNamespace); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// resolve returns the candidate paths, relative to the root, of a link
+// target. Like the graph builder, targets starting with "./" or containing
+// ".." are relative to the document; others are kept as they are and also
+// tried next to the document. Fragment-only and absolute IRIs have none.
+func (d *document) resolve(target string) []string {
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	if i := strings.IndexByte(target, '#'); i != -1 {
+		target = target[:i]
+	}
+	if target == "" || strings.Contains(target, ":") || path.IsAbs(target) {
+		return nil
+	}
+
+	dir := path.Dir(d.path)
+	if strings.HasPrefix(target, "./") || strings.Contains(target, "..") {
+		return []string{path.Join(dir, target)}
+	}
+	candidates := []string{path.Clean(target)}
+	if dir != "." {
+		candidates = append(candidates, path.Join(dir, target))
+	}
+	return candidates
+}
+
+// pathFromURI returns the file path of a file:// URI
+func pathFromURI(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	p := u.Path
+	// Windows drive letters arrive as /C:/...
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p), true
+}
+
+// uriFromPath returns the file:// URI of an absolute file path
+func uriFromPath(p string) string {
+	p = filepath.ToSlash(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the unit of LSP
+// character offsets
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// byteOffset returns the byte offset in line of a UTF-16 character offset
+func byteOffset(line string, character int) int {
+	n := 0
+	for i, r := range line {
+		if n >= character {
+			return i
+		}
+		n += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+// wordBefore returns the prefixed name being typed before a position and
+// its start offset in UTF-16 code units
+func (d *document) wordBefore(p Position) (string, int) {
+	if p.Line < 0 || p.Line >= len(d.lines) {
+		return "", p.Character
+	}
+	line := d.lines[p.Line][:byteOffset(d.lines[p.Line], p.Character)]
+	start := len(line)
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(line[:start])
+		if r != ':' && r != '_' && r != '-' && !isAlnum(r) {
+			break
+		}
+		start -= size
+	}
+	return line[start:], utf16Len(line[:start])
+}
+
+// afterTypeKeyword reports whether the word before a position follows the
+// "a" keyword, where classes rather than predicates are completed
+func (d *document) afterTypeKeyword(p Position, wordStart int) bool {
+	line := d.lines[p.Line][:byteOffset(d.lines[p.Line], wordStart)]
+	fields := strings.Fields(line)
+	return len(fields) > 0 && fields[len(fields)-1] == "a"
+}
+
+// isAlnum reports whether r is an ASCII letter or digit
+func isAlnum(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,80 @@
+package lsp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewDocument_Statements(t *testing.T) {
+	text := `# Module: tools/sync.py
+# <!-- LinkedDoc RDF -->
+# @prefix c: <https://schema.codedoc.org/> .
+# <#sync.py> a c:Module ;
+#     c:name "tools/sync.py" ;   # trailing comment
+#     c:linksTo <./db.py>, <../lib/ü.py> ;
+#     c:hasMethod [ c:name "run" ] ;
+#     c:tags "sync" .
+# <!-- End LinkedDoc RDF -->
+import os
+`
+	d := newDocument("file:///repo/tools/sync.py", "tools/sync.py", text)
+	if d.prefix != "c" {
+		t.Errorf("prefix = %q, want c", d.prefix)
+	}
+
+	var got []string
+	for _, s := range d.statements {
+		got = append(got, s.subject.text+" "+s.predicate.text+" "+s.object.text)
+	}
+	want := []string{
+		"<#sync.py> a c:Module",
+		`<#sync.py> c:name "tools/sync.py"`,
+		"<#sync.py> c:linksTo <./db.py>",
+		"<#sync.py> c:linksTo <../lib/ü.py>",
+		"<#sync.py> c:hasMethod [",
+		`[ c:name "run"`,
+		`<#sync.py> c:tags "sync"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("statements =\n%q\nwant\n%q", got, want)
+	}
+
+	link := d.statements[3].object
+	if link.rng != (Range{Start: Position{Line: 5, Character: 27}, End: Position{Line: 5, Character: 40}}) {
+		t.Errorf("Range of %s = %+v", link.text, link.rng)
+	}
+	if _, tok, ok := d.at(Position{Line: 5, Character: 10}); !ok || tok.text != "c:linksTo" {
+		t.Errorf("at() = %q, %v", tok.text, ok)
+	}
+	if name := d.localName(d.statements[2].predicate); name != "linksTo" {
+		t.Errorf("localName() = %q", name)
+	}
+}
+
+func TestDocument_Resolve(t *testing.T) {
+	d := newDocument("file:///repo/pkg/auth/service.go", "pkg/auth/service.go", "")
+	for target, want := range map[string][]string{
+		"<./store.go>":          {"pkg/auth/store.go"},
+		"<../graph/graph.go>":   {"pkg/graph/graph.go"},
+		"<store.go#Store>":      {"store.go", "pkg/auth/store.go"},
+		"<#Service>":            nil,
+		"<https://example.com>": nil,
+	} {
+		if got := d.resolve(target); !reflect.DeepEqual(got, want) {
+			t.Errorf("resolve(%s) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestURIs(t *testing.T) {
+	uri := uriFromPath("/repo/my dir/a.go")
+	if uri != "file:///repo/my%20dir/a.go" {
+		t.Errorf("uriFromPath() = %s", uri)
+	}
+	if p, ok := pathFromURI(uri); !ok || p != "/repo/my dir/a.go" {
+		t.Errorf("pathFromURI() = %s, %v", p, ok)
+	}
+	if _, ok := pathFromURI("untitled:Untitled-1"); ok {
+		t.Error("Expected non-file URIs to have no path")
+	}
+}
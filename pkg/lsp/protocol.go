@@ -0,0 +1,224 @@
+/*
+# Module: pkg/lsp/protocol.go
+Language Server Protocol messages and JSON-RPC framing.
+
+Defines the subset of LSP types the LinkedDoc language server uses and reads
+and writes JSON-RPC 2.0 messages with Content-Length headers, as sent over
+stdio by editors such as VS Code and Neovim.
+
+## Linked Modules
+- [server](./server.go) - LinkedDoc language server
+
+## Tags
+lsp, jsonrpc, protocol
+
+## Exports
+Position, Range, Location, Diagnostic, CompletionItem
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#protocol.go> a code:Module ;
+    code:name "pkg/lsp/protocol.go" ;
+    code:description "Language Server Protocol messages and JSON-RPC framing" ;
+    code:language "go" ;
+    code:layer "lsp" ;
+    code:linksTo <./server.go> ;
+    code:exports <#Position>, <#Range>, <#Location>, <#Diagnostic>, <#CompletionItem> ;
+    code:tags "lsp", "jsonrpc", "protocol" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+)
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// message is a JSON-RPC request, notification or response
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+// responseError is a JSON-RPC error
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// readMessage reads one message framed by a Content-Length header
+func readMessage(r *bufio.Reader) (*message, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return &message{Error: &responseError{Code: codeParseError, Message: err.Error()}}, nil
+	}
+	return &msg, nil
+}
+
+// writeMessage writes one message with its Content-Length header
+func writeMessage(w io.Writer, msg *message) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// Position is a zero-based line and UTF-16 character offset
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a half-open range between two positions
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// contains reports whether a position is within the range, including its end
+func (r Range) contains(p Position) bool {
+	after := p.Line > r.Start.Line || (p.Line == r.Start.Line && p.Character >= r.Start.Character)
+	before := p.Line < r.End.Line || (p.Line == r.End.Line && p.Character <= r.End.Character)
+	return after && before
+}
+
+// Location is a range in a document
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// Diagnostic severities
+const (
+	severityError       = 1
+	severityWarning     = 2
+	severityInformation = 3
+)
+
+// Diagnostic is a problem reported in a document
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// Completion item kinds
+const (
+	completionKindClass    = 7
+	completionKindProperty = 10
+)
+
+// CompletionItem is a completion proposal
+type CompletionItem struct {
+	Label         string         `json:"label"`
+	Kind          int            `json:"kind"`
+	Detail        string         `json:"detail,omitempty"`
+	Documentation *markupContent `json:"documentation,omitempty"`
+	TextEdit      *textEdit      `json:"textEdit,omitempty"`
+}
+
+// markupContent is Markdown shown in hovers and completion documentation
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// markdown wraps Markdown text
+func markdown(value string) *markupContent {
+	return &markupContent{Kind: "markdown", Value: value}
+}
+
+// textEdit replaces a range with new text
+type textEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// hover is the result of textDocument/hover
+type hover struct {
+	Contents *markupContent `json:"contents"`
+	Range    *Range         `json:"range,omitempty"`
+}
+
+// textDocumentItem is an opened document
+type textDocumentItem struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
+// textDocumentIdentifier names a document
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// didOpenParams are the params of textDocument/didOpen
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+// didChangeParams are the params of textDocument/didChange with full sync
+type didChangeParams struct {
+	TextDocument   textDocumentItem `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+// documentParams are the params of didClose and didSave
+type documentParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+// positionParams are the params of hover, definition and completion
+type positionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// publishDiagnosticsParams are the params of textDocument/publishDiagnostics
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
@@ -0,0 +1,536 @@
+/*
+# Module: pkg/lsp/server.go
+LinkedDoc language server.
+
+Serves the Language Server Protocol for LinkedDoc headers: hovers show the
+description, layer and tags of linked modules, go-to-definition opens the
+targets of linksTo and other link predicates, diagnostics report broken
+links and doclint issues, and completion offers code: predicates and
+classes inside LinkedDoc blocks. The knowledge graph is built on start and
+rebuilt in the background whenever a document is saved.
+
+## Linked Modules
+- [protocol](./protocol.go) - JSON-RPC framing and LSP types
+- [document](./document.go) - LinkedDoc statements of open documents
+- [vocabulary](./vocabulary.go) - Predicates and classes
+- [../graph](../graph/graph.go) - Graph data structure
+- [../doclint](../doclint/doclint.go) - LinkedDoc style checks
+
+## Tags
+lsp, editor, server, linkeddoc
+
+## Exports
+Options, Server, NewServer
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#server.go> a code:Module ;
+    code:name "pkg/lsp/server.go" ;
+    code:description "LinkedDoc language server" ;
+    code:language "go" ;
+    code:layer "lsp" ;
+    code:linksTo <./protocol.go>, <./document.go>, <./vocabulary.go>, <../graph/graph.go>, <../doclint/doclint.go> ;
+    code:exports <#Options>, <#Server>, <#NewServer> ;
+    code:tags "lsp", "editor", "server", "linkeddoc" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/justin4957/graphfs/pkg/doclint"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// Diagnostic codes reported by the server, besides doclint rules
+const (
+	CodeBrokenLink    = "broken-link"
+	CodeUnknownModule = "unknown-module"
+)
+
+// Options configures the language server
+type Options struct {
+	Root string // Project root that documents and links are resolved against
+
+	// Load builds the knowledge graph of Root. It is called once the client
+	// is initialized and again after each save.
+	Load func() (*graph.Graph, error)
+
+	Lint doclint.Options                          // Options of the doclint diagnostics
+	Logf func(format string, args ...interface{}) // Optional log of server events
+}
+
+// Server is a LinkedDoc language server for one project
+type Server struct {
+	opts   Options
+	linter *doclint.Linter
+
+	mu     sync.Mutex
+	graph  *graph.Graph
+	docs   map[string]*document
+	loads  int // Graph loads started
+	loaded int // Newest graph load applied
+
+	writeMu sync.Mutex
+	w       io.Writer
+	wg      sync.WaitGroup
+}
+
+// NewServer creates a language server
+func NewServer(opts Options) *Server {
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...interface{}) {}
+	}
+	return &Server{
+		opts:   opts,
+		linter: doclint.NewLinter(opts.Lint),
+		docs:   make(map[string]*document),
+	}
+}
+
+// Run serves messages read from r, writing responses and notifications to
+// w, until the client sends exit, r ends or ctx is cancelled
+func (s *Server) Run(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+	defer s.wg.Wait()
+
+	messages := make(chan *message)
+	errs := make(chan error, 1)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			msg, err := readMessage(br)
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read message: %w", err)
+		case msg := <-messages:
+			if msg.Method == "exit" {
+				return nil
+			}
+			s.handle(msg)
+		}
+	}
+}
+
+// handle answers a request or acts on a notification. Responses from the
+// client are ignored since the server sends no requests.
+func (s *Server) handle(msg *message) {
+	switch {
+	case msg.Error != nil && msg.Method == "":
+		if msg.ID == nil {
+			s.reply(nil, nil, msg.Error)
+		}
+	case msg.ID == nil:
+		s.notification(msg)
+	case msg.Method != "":
+		result, err := s.request(msg)
+		s.reply(msg.ID, result, err)
+	}
+}
+
+// request returns the result of a request
+func (s *Server) request(msg *message) (interface{}, *responseError) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   map[string]interface{}{"openClose": true, "change": 1, "save": true},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+				"completionProvider": map[string]interface{}{"triggerCharacters": []string{":"}},
+			},
+			"serverInfo": map[string]string{"name": "graphfs"},
+		}, nil
+	case "shutdown":
+		return nil, nil
+	}
+
+	var params positionParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	d := s.document(params.TextDocument.URI)
+
+	switch msg.Method {
+	case "textDocument/hover":
+		if d == nil {
+			return nil, nil
+		}
+		return s.hover(d, params.Position), nil
+	case "textDocument/definition":
+		if d == nil {
+			return nil, nil
+		}
+		return s.definition(d, params.Position), nil
+	case "textDocument/completion":
+		if d == nil {
+			return []CompletionItem{}, nil
+		}
+		return completions(d, params.Position), nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", msg.Method)}
+}
+
+// notification acts on a notification
+func (s *Server) notification(msg *message) {
+	switch msg.Method {
+	case "initialized":
+		s.reload(s.startLoad())
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err == nil {
+			s.open(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			s.open(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+	case "textDocument/didClose":
+		var params documentParams
+		if err := json.Unmarshal(msg.Params, &params); err == nil {
+			s.mu.Lock()
+			delete(s.docs, params.TextDocument.URI)
+			s.mu.Unlock()
+			s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+		}
+	case "textDocument/didSave":
+		load := s.startLoad()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.reload(load)
+		}()
+	}
+}
+
+// open parses a document's text and publishes its diagnostics
+func (s *Server) open(uri, text string) {
+	relPath := uri
+	if p, ok := pathFromURI(uri); ok {
+		if rel, err := filepath.Rel(s.opts.Root, p); err == nil {
+			relPath = filepath.ToSlash(rel)
+		}
+	}
+	d := newDocument(uri, relPath, text)
+
+	s.mu.Lock()
+	s.docs[uri] = d
+	g := s.graph
+	s.mu.Unlock()
+	s.publish(d, g)
+}
+
+// document returns an open document
+func (s *Server) document(uri string) *document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.docs[uri]
+}
+
+// startLoad numbers a new graph load
+func (s *Server) startLoad() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loads++
+	return s.loads
+}
+
+// reload builds the graph and republishes the diagnostics of every open
+// document, unless a newer load finished first. A failed load keeps the
+// previous graph.
+func (s *Server) reload(load int) {
+	if s.opts.Load == nil {
+		return
+	}
+	g, err := s.opts.Load()
+	if err != nil {
+		s.opts.Logf("Failed to build graph: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	if load < s.loaded {
+		s.mu.Unlock()
+		return
+	}
+	s.graph, s.loaded = g, load
+	docs := make([]*document, 0, len(s.docs))
+	for _, d := range s.docs {
+		docs = append(docs, d)
+	}
+	s.mu.Unlock()
+
+	s.opts.Logf("Loaded %d modules", len(g.Modules))
+	for _, d := range docs {
+		s.publish(d, g)
+	}
+}
+
+// currentGraph returns the latest graph, or nil before the first load
+func (s *Server) currentGraph() *graph.Graph {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.graph
+}
+
+// publish sends the diagnostics of a document
+func (s *Server) publish(d *document, g *graph.Graph) {
+	s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: d.uri, Diagnostics: s.diagnostics(d, g)})
+}
+
+// diagnostics reports links to missing files, links to files that are not
+// modules of the graph, and doclint issues
+func (s *Server) diagnostics(d *document, g *graph.Graph) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	for _, st := range d.statements {
+		if !linkPredicates[d.localName(st.predicate)] || st.object.kind != tokenIRI {
+			continue
+		}
+		candidates := d.resolve(st.object.text)
+		if len(candidates) == 0 {
+			continue
+		}
+		target, info, ok := s.stat(candidates)
+		switch {
+		case !ok:
+			diagnostics = append(diagnostics, Diagnostic{
+				Range: st.object.rng, Severity: severityError, Code: CodeBrokenLink, Source: "graphfs",
+				Message: fmt.Sprintf("Broken link: %s does not exist", candidates[0]),
+			})
+		case g != nil && !info.IsDir() && g.GetModule(target) == nil:
+			diagnostics = append(diagnostics, Diagnostic{
+				Range: st.object.rng, Severity: severityWarning, Code: CodeUnknownModule, Source: "graphfs",
+				Message: fmt.Sprintf("%s is not a module: it has no LinkedDoc header or is not scanned", target),
+			})
+		}
+	}
+
+	for _, issue := range s.linter.LintContent(d.path, strings.Join(d.lines, "\n"), d.language) {
+		line := max(issue.Line-1, 0)
+		diagnostic := Diagnostic{
+			Range:    Range{Start: Position{Line: line}, End: Position{Line: line, Character: d.lineLength(line)}},
+			Severity: severityWarning,
+			Code:     issue.Rule,
+			Source:   "graphfs",
+			Message:  issue.Message,
+		}
+		if issue.Severity == doclint.SeverityError {
+			diagnostic.Severity = severityError
+		}
+		if issue.Fix != "" {
+			diagnostic.Message += "\nFix: " + issue.Fix
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// stat returns the first candidate path that exists under the root
+func (s *Server) stat(candidates []string) (string, os.FileInfo, bool) {
+	for _, candidate := range candidates {
+		if info, err := os.Stat(filepath.Join(s.opts.Root, filepath.FromSlash(candidate))); err == nil {
+			return candidate, info, true
+		}
+	}
+	return "", nil, false
+}
+
+// hover describes the linked module, predicate, class or document module
+// at a position
+func (s *Server) hover(d *document, p Position) *hover {
+	st, t, ok := d.at(p)
+	if !ok {
+		return nil
+	}
+	rng := t.rng
+	predicate := d.localName(st.predicate)
+
+	var contents string
+	switch {
+	case t == st.predicate && st.predicate.text == "a":
+		contents = "**a** — shorthand for `rdf:type`"
+	case t == st.predicate:
+		if term, ok := lookupTerm(predicates, predicate); ok {
+			contents = termMarkdown(d.prefix, term)
+		}
+	case t == st.object && st.predicate.text == "a":
+		if term, ok := lookupTerm(classes, d.localName(t)); ok {
+			contents = termMarkdown(d.prefix, term)
+		}
+	case t == st.object && linkPredicates[predicate] && t.kind == tokenIRI:
+		contents = s.linkMarkdown(d, t)
+	case t == st.subject && d.isModuleSubject(t):
+		if g := s.currentGraph(); g != nil {
+			if module := g.GetModule(d.path); module != nil {
+				contents = moduleMarkdown(module)
+			}
+		}
+	}
+	if contents == "" {
+		return nil
+	}
+	return &hover{Contents: markdown(contents), Range: &rng}
+}
+
+// linkMarkdown describes the target of a link
+func (s *Server) linkMarkdown(d *document, t token) string {
+	candidates := d.resolve(t.text)
+	if len(candidates) == 0 {
+		return ""
+	}
+	target, _, ok := s.stat(candidates)
+	if !ok {
+		return fmt.Sprintf("`%s` does not exist", candidates[0])
+	}
+	if g := s.currentGraph(); g != nil {
+		if module := g.GetModule(target); module != nil {
+			return moduleMarkdown(module)
+		}
+	}
+	return fmt.Sprintf("`%s` is not a module of the knowledge graph", target)
+}
+
+// moduleMarkdown describes a module
+func moduleMarkdown(module *graph.Module) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n", module.Path)
+	if module.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", module.Description)
+	}
+	var facts []string
+	if module.Layer != "" {
+		facts = append(facts, fmt.Sprintf("- Layer: `%s`", module.Layer))
+	}
+	if module.Language != "" {
+		facts = append(facts, fmt.Sprintf("- Language: `%s`", module.Language))
+	}
+	if len(module.Tags) > 0 {
+		facts = append(facts, fmt.Sprintf("- Tags: `%s`", strings.Join(module.Tags, "`, `")))
+	}
+	if len(facts) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(facts, "\n"))
+	}
+	return b.String()
+}
+
+// termMarkdown describes a predicate or class
+func termMarkdown(prefix string, t term) string {
+	return fmt.Sprintf("**%s:%s** (%s)\n\n%s", prefix, t.name, t.detail, t.doc)
+}
+
+// definition returns the file a link points to
+func (s *Server) definition(d *document, p Position) []Location {
+	st, t, ok := d.at(p)
+	if !ok || t != st.object || t.kind != tokenIRI || !linkPredicates[d.localName(st.predicate)] {
+		return []Location{}
+	}
+	target, info, ok := s.stat(d.resolve(t.text))
+	if !ok || info.IsDir() {
+		return []Location{}
+	}
+	return []Location{{URI: uriFromPath(filepath.Join(s.opts.Root, filepath.FromSlash(target)))}}
+}
+
+// completions offers predicates, or classes after "a", for the prefixed
+// name typed before a position in a LinkedDoc block
+func completions(d *document, p Position) []CompletionItem {
+	items := []CompletionItem{}
+	if _, ok := d.inBlock(p.Line); !ok {
+		return items
+	}
+	word, start := d.wordBefore(p)
+	prefix := d.prefix + ":"
+	if !strings.HasPrefix(word, prefix) && !strings.HasPrefix(prefix, word) {
+		return items
+	}
+
+	terms, kind := predicates, completionKindProperty
+	if d.afterTypeKeyword(p, start) {
+		terms, kind = classes, completionKindClass
+	}
+	for _, t := range terms {
+		label := prefix + t.name
+		if !strings.HasPrefix(label, word) {
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:         label,
+			Kind:          kind,
+			Detail:        t.detail,
+			Documentation: markdown(t.doc),
+			TextEdit: &textEdit{
+				Range:   Range{Start: Position{Line: p.Line, Character: start}, End: p},
+				NewText: label,
+			},
+		})
+	}
+	return items
+}
+
+// reply sends the response to a request
+func (s *Server) reply(id *json.RawMessage, result interface{}, rerr *responseError) {
+	if id == nil {
+		null := json.RawMessage("null")
+		id = &null
+	}
+	msg := &message{ID: id, Error: rerr}
+	if rerr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			msg.Error = &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+		msg.Result = data
+	}
+	s.write(msg)
+}
+
+// notify sends a notification
+func (s *Server) notify(method string, params interface{}) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		s.opts.Logf("Failed to encode %s: %v", method, err)
+		return
+	}
+	s.write(&message{Method: method, Params: data})
+}
+
+// write sends a message, one at a time
+func (s *Server) write(msg *message) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := writeMessage(s.w, msg); err != nil {
+		s.opts.Logf("Failed to write message: %v", err)
+	}
+}
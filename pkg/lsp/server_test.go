@@ -0,0 +1,330 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+const serviceSource = `/*
+# Module: pkg/auth/service.go
+Authentication service.
+
+## Linked Modules
+- [store](./store.go) - Session store
+- [missing](./missing.go) - Removed module
+- [notes](./notes.txt) - Design notes
+
+## Tags
+auth
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+
+<#service.go> a code:Module ;
+    code:name "pkg/auth/service.go" ;
+    code:description "Authentication service" ;
+    code:linksTo <./store.go>, <./missing.go>, <./notes.txt> ;
+    code:tags "auth" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package auth
+`
+
+const storeSource = `/*
+# Module: pkg/auth/store.go
+Session store.
+
+## Tags
+auth, storage
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+
+<#store.go> a code:Module ;
+    code:name "pkg/auth/store.go" ;
+    code:description "Session store backed by Redis" ;
+    code:layer "data" ;
+    code:tags "auth", "storage" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package auth
+`
+
+// testClient drives a server over pipes
+type testClient struct {
+	t        *testing.T
+	in       *io.PipeWriter
+	messages chan *message // Read from the server
+	nextID   int
+
+	diagnostics map[string][]Diagnostic // Latest published, by URI
+}
+
+// startServer serves a project with the service and store modules
+func startServer(t *testing.T) (*testClient, string) {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"pkg/auth/service.go": serviceSource,
+		"pkg/auth/store.go":   storeSource,
+		"pkg/auth/notes.txt":  "notes\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := NewServer(Options{
+		Root: root,
+		Load: func() (*graph.Graph, error) {
+			return graph.NewBuilder().Build(root, graph.BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}})
+		},
+	})
+	clientIn, serverIn := io.Pipe()
+	serverOut, clientOut := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Run(context.Background(), clientIn, clientOut)
+		clientOut.Close()
+	}()
+
+	// Read concurrently so the server never blocks writing while the client
+	// writes
+	c := &testClient{t: t, in: serverIn, messages: make(chan *message, 64), diagnostics: make(map[string][]Diagnostic)}
+	go func() {
+		defer close(c.messages)
+		r := bufio.NewReader(serverOut)
+		for {
+			msg, err := readMessage(r)
+			if err != nil {
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+	t.Cleanup(func() {
+		c.notify("exit", nil)
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+	return c, root
+}
+
+// notify sends a notification
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	data, _ := json.Marshal(params)
+	if err := writeMessage(c.in, &message{Method: method, Params: data}); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// request sends a request and reads messages until its response, recording
+// published diagnostics on the way
+func (c *testClient) request(method string, params interface{}, result interface{}) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	data, _ := json.Marshal(params)
+	if err := writeMessage(c.in, &message{ID: &id, Method: method, Params: data}); err != nil {
+		c.t.Fatal(err)
+	}
+
+	for msg := range c.messages {
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var published publishDiagnosticsParams
+			if err := json.Unmarshal(msg.Params, &published); err != nil {
+				c.t.Fatal(err)
+			}
+			c.diagnostics[published.URI] = published.Diagnostics
+			continue
+		}
+		if msg.ID != nil && string(*msg.ID) == string(id) {
+			if msg.Error == nil && result != nil {
+				if err := json.Unmarshal(msg.Result, result); err != nil {
+					c.t.Fatal(err)
+				}
+			}
+			return msg.Error
+		}
+	}
+	c.t.Fatalf("No response to %s", method)
+	return nil
+}
+
+// open initializes the server and opens the service module
+func (c *testClient) open(root string) string {
+	c.t.Helper()
+	var initialized struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	if err := c.request("initialize", map[string]interface{}{"rootUri": uriFromPath(root)}, &initialized); err != nil {
+		c.t.Fatalf("initialize: %v", err.Message)
+	}
+	if initialized.Capabilities["hoverProvider"] != true || initialized.Capabilities["definitionProvider"] != true {
+		c.t.Errorf("Capabilities = %v", initialized.Capabilities)
+	}
+	c.notify("initialized", struct{}{})
+
+	uri := uriFromPath(filepath.Join(root, "pkg", "auth", "service.go"))
+	c.notify("textDocument/didOpen", didOpenParams{TextDocument: textDocumentItem{URI: uri, Version: 1, Text: serviceSource}})
+	return uri
+}
+
+// position returns the position of the first occurrence of s in the
+// service source
+func position(s string, offset int) Position {
+	for i, line := range strings.Split(serviceSource, "\n") {
+		if col := strings.Index(line, s); col != -1 {
+			return Position{Line: i, Character: col + offset}
+		}
+	}
+	return Position{}
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	c, root := startServer(t)
+	uri := c.open(root)
+	// Responses follow the diagnostics of the opened document
+	if err := c.request("shutdown", nil, nil); err != nil {
+		t.Fatal(err.Message)
+	}
+
+	codes := make(map[string]Diagnostic)
+	for _, d := range c.diagnostics[uri] {
+		codes[d.Code] = d
+	}
+	broken, ok := codes[CodeBrokenLink]
+	if !ok || broken.Severity != severityError || !strings.Contains(broken.Message, "pkg/auth/missing.go") {
+		t.Errorf("Expected a broken link error, got %+v", c.diagnostics[uri])
+	}
+	if broken.Range.Start != position("<./missing.go>", 0) {
+		t.Errorf("Broken link range = %+v", broken.Range)
+	}
+	unknown, ok := codes[CodeUnknownModule]
+	if !ok || unknown.Severity != severityWarning || !strings.Contains(unknown.Message, "notes.txt") {
+		t.Errorf("Expected an unknown module warning, got %+v", c.diagnostics[uri])
+	}
+	// doclint issues are reported on their line
+	if lint, ok := codes["missing-section"]; !ok || !strings.Contains(lint.Message, "Exports") || lint.Range.Start.Line != position("<!-- LinkedDoc RDF -->", 0).Line {
+		t.Errorf("Expected the missing Exports section, got %+v", c.diagnostics[uri])
+	}
+	if len(c.diagnostics[uri]) != 3 {
+		t.Errorf("Expected 3 diagnostics, got %+v", c.diagnostics[uri])
+	}
+}
+
+func TestServer_HoverAndDefinition(t *testing.T) {
+	c, root := startServer(t)
+	uri := c.open(root)
+
+	var h hover
+	if err := c.request("textDocument/hover", positionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: position("<./store.go>", 3)}, &h); err != nil {
+		t.Fatal(err.Message)
+	}
+	if h.Contents == nil {
+		t.Fatal("Expected a hover on the linksTo target")
+	}
+	for _, want := range []string{"**pkg/auth/store.go**", "Session store backed by Redis", "Layer: `data`", "`auth`, `storage`"} {
+		if !strings.Contains(h.Contents.Value, want) {
+			t.Errorf("Hover missing %q:\n%s", want, h.Contents.Value)
+		}
+	}
+
+	h = hover{}
+	if err := c.request("textDocument/hover", positionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: position("code:linksTo", 6)}, &h); err != nil {
+		t.Fatal(err.Message)
+	}
+	if h.Contents == nil || !strings.Contains(h.Contents.Value, "**code:linksTo**") {
+		t.Errorf("Predicate hover = %+v", h.Contents)
+	}
+
+	var locations []Location
+	if err := c.request("textDocument/definition", positionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: position("<./store.go>", 1)}, &locations); err != nil {
+		t.Fatal(err.Message)
+	}
+	if len(locations) != 1 || locations[0].URI != uriFromPath(filepath.Join(root, "pkg", "auth", "store.go")) {
+		t.Errorf("Definition = %+v", locations)
+	}
+
+	locations = nil
+	if err := c.request("textDocument/definition", positionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: position("<./missing.go>", 1)}, &locations); err != nil {
+		t.Fatal(err.Message)
+	}
+	if len(locations) != 0 {
+		t.Errorf("Expected no definition of a missing file, got %+v", locations)
+	}
+}
+
+func TestServer_Completion(t *testing.T) {
+	c, root := startServer(t)
+	uri := c.open(root)
+
+	edited := strings.Replace(serviceSource, `code:tags "auth" .`, "code:tags \"auth\" ;\n    code:la\n<#Helper> a code:F", 1)
+	c.notify("textDocument/didChange", didChangeParams{
+		TextDocument: textDocumentItem{URI: uri, Version: 2},
+		ContentChanges: []struct {
+			Text string `json:"text"`
+		}{{Text: edited}},
+	})
+	lines := strings.Split(edited, "\n")
+	line := 0
+	for i, l := range lines {
+		if strings.TrimSpace(l) == "code:la" {
+			line = i
+		}
+	}
+
+	var items []CompletionItem
+	if err := c.request("textDocument/completion", positionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: Position{Line: line, Character: 11}}, &items); err != nil {
+		t.Fatal(err.Message)
+	}
+	var labels []string
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+	if strings.Join(labels, " ") != "code:language code:layer" {
+		t.Errorf("Completions = %v", labels)
+	}
+	if items[0].TextEdit == nil || items[0].TextEdit.Range.Start != (Position{Line: line, Character: 4}) {
+		t.Errorf("TextEdit = %+v", items[0].TextEdit)
+	}
+
+	items = nil
+	if err := c.request("textDocument/completion", positionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: Position{Line: line + 1, Character: 18}}, &items); err != nil {
+		t.Fatal(err.Message)
+	}
+	if len(items) != 2 || items[0].Label != "code:Function" || items[0].Kind != completionKindClass {
+		t.Errorf("Class completions = %+v", items)
+	}
+
+	// Outside LinkedDoc blocks there is nothing to complete
+	items = nil
+	if err := c.request("textDocument/completion", positionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: Position{Line: 0, Character: 2}}, &items); err != nil {
+		t.Fatal(err.Message)
+	}
+	if len(items) != 0 {
+		t.Errorf("Expected no completions outside blocks, got %+v", items)
+	}
+
+	if err := c.request("workspace/symbol", map[string]string{"query": "x"}, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("Expected method not found, got %+v", err)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+# Module: pkg/lsp/vocabulary.go
+LinkedDoc predicates and classes offered by the language server.
+
+Lists the code: predicates and classes written in LinkedDoc headers with
+short documentation, for completion and predicate hovers.
+
+## Linked Modules
+- [server](./server.go) - LinkedDoc language server
+
+## Tags
+lsp, vocabulary, completion
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#vocabulary.go> a code:Module ;
+    code:name "pkg/lsp/vocabulary.go" ;
+    code:description "LinkedDoc predicates and classes offered by the language server" ;
+    code:language "go" ;
+    code:layer "lsp" ;
+    code:linksTo <./server.go> ;
+    code:tags "lsp", "vocabulary", "completion" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package lsp
+
+import "github.com/justin4957/graphfs/pkg/graph"
+
+// term is a predicate or class of the LinkedDoc vocabulary
+type term struct {
+	name   string
+	detail string // Expected value
+	doc    string
+}
+
+// predicates are the code: predicates of LinkedDoc headers
+var predicates = []term{
+	{"name", "literal", "Module path relative to the project root, or the name of a symbol."},
+	{"description", "literal", "One-line summary shown in docs, search and hovers."},
+	{"language", "literal", "Source language, such as \"go\" or \"python\"."},
+	{"layer", "literal", "Architectural layer checked by layer rules."},
+	{"linksTo", "<path>", "Dependency on another module, relative to this file when it starts with `./` or `../`."},
+	{"imports", "<path>", "Dependency that imports the target module."},
+	{"extends", "<path>", "Dependency that extends a type of the target module."},
+	{"implements", "<path>", "Dependency that implements an interface of the target module."},
+	{"uses", "<path>", "Dependency that uses the target module at run time."},
+	{"exports", "<#Symbol>", "Symbol exported by the module."},
+	{"calls", "<path#Symbol>", "Exported symbol of another module called by this one."},
+	{"tags", "literal", "Free-form tags used by filters, search and the tag taxonomy."},
+	{"owner", "literal", "Owning team or person, as in CODEOWNERS."},
+	{"ownedBy", "literal", "Owner recorded by graphfs from code:owner or CODEOWNERS."},
+	{"kind", "literal", "Kind of a symbol, such as \"struct\" or \"interface\"."},
+	{"isLeaf", "boolean", "Whether the module has no dependencies by design."},
+	{"hasMethod", "[ ... ]", "Method of a type, as a blank node with its name and description."},
+	{"hasField", "[ ... ]", "Field of a type, as a blank node with its name and description."},
+	{"returns", "literal", "Return type of a function or method."},
+	{"type", "literal", "Type of a field or parameter."},
+}
+
+// classes are the code: classes used after "a"
+var classes = []term{
+	{"Module", "class", "A source file with a LinkedDoc header."},
+	{"Function", "class", "An exported function."},
+	{"Type", "class", "An exported type."},
+	{"Method", "class", "A method of a type."},
+	{"Field", "class", "A field of a type."},
+	{"Component", "class", "A group of modules with a public API."},
+}
+
+// linkPredicates are the predicates whose objects are module paths
+var linkPredicates = map[string]bool{
+	graph.RelationLinksTo:    true,
+	graph.RelationImports:    true,
+	graph.RelationExtends:    true,
+	graph.RelationImplements: true,
+	graph.RelationUses:       true,
+}
+
+// lookupTerm returns the term with a name
+func lookupTerm(terms []term, name string) (term, bool) {
+	for _, t := range terms {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return term{}, false
+}
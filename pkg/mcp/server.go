@@ -0,0 +1,231 @@
+/*
+# Module: pkg/mcp/server.go
+Model Context Protocol server.
+
+Serves the knowledge graph to LLM coding assistants over the MCP stdio
+transport: newline-delimited JSON-RPC 2.0 messages on stdin and stdout.
+Answers initialize, ping, tools/list and tools/call; the tools themselves
+are defined in tools.go.
+
+## Linked Modules
+- [tools](./tools.go) - Graph tools
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+mcp, ai, jsonrpc, server
+
+## Exports
+ProtocolVersion, Options, Server, NewServer
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#server.go> a code:Module ;
+    code:name "pkg/mcp/server.go" ;
+    code:description "Model Context Protocol server" ;
+    code:language "go" ;
+    code:layer "mcp" ;
+    code:linksTo <./tools.go>, <../graph/graph.go> ;
+    code:exports <#ProtocolVersion>, <#Options>, <#Server>, <#NewServer> ;
+    code:tags "mcp", "ai", "jsonrpc", "server" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// ProtocolVersion is the MCP revision the server implements. Clients
+// requesting another revision are answered with this one.
+const ProtocolVersion = "2025-06-18"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// maxMessageSize bounds one newline-delimited message
+const maxMessageSize = 16 * 1024 * 1024
+
+// message is a JSON-RPC request, notification or response
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+// responseError is a JSON-RPC error
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Options configures the MCP server
+type Options struct {
+	Version string // Server version reported to clients
+
+	// Concepts are the shadow concepts of each module path, searched by
+	// search_by_concept along with tags and descriptions (optional)
+	Concepts map[string][]string
+
+	Logf func(format string, args ...interface{}) // Optional log of server events
+}
+
+// Server serves one knowledge graph as MCP tools
+type Server struct {
+	graph *graph.Graph
+	opts  Options
+	tools []tool
+}
+
+// NewServer creates an MCP server for a graph
+func NewServer(g *graph.Graph, opts Options) *Server {
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...interface{}) {}
+	}
+	s := &Server{graph: g, opts: opts}
+	s.tools = s.graphTools()
+	return s
+}
+
+// Run serves messages read from r, one per line, writing responses to w
+// until r ends or ctx is cancelled
+func (s *Server) Run(ctx context.Context, r io.Reader, w io.Writer) error {
+	lines := make(chan []byte)
+	errs := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errs <- scanner.Err()
+	}()
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if err != nil {
+				return fmt.Errorf("failed to read message: %w", err)
+			}
+			return nil
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			if response := s.handle(line); response != nil {
+				response.JSONRPC = "2.0"
+				if err := encoder.Encode(response); err != nil {
+					return fmt.Errorf("failed to write message: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// handle returns the response to a message, or nil for notifications
+func (s *Server) handle(line []byte) *message {
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		null := json.RawMessage("null")
+		return &message{ID: &null, Error: &responseError{Code: codeParseError, Message: err.Error()}}
+	}
+	if msg.ID == nil || msg.Method == "" {
+		// Notifications need no response and the server sends no requests
+		return nil
+	}
+
+	result, err := s.request(msg.Method, msg.Params)
+	if err != nil {
+		return &message{ID: msg.ID, Error: err}
+	}
+	return &message{ID: msg.ID, Result: result}
+}
+
+// request returns the result of a request
+func (s *Server) request(method string, params json.RawMessage) (interface{}, *responseError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]bool{"listChanged": false}},
+			"serverInfo":      map[string]string{"name": "graphfs", "version": s.opts.Version},
+			"instructions": "Tools over the GraphFS knowledge graph of this project: modules with their " +
+				"descriptions, layers, tags and dependencies. Module paths are relative to the project root.",
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		definitions := make([]map[string]interface{}, len(s.tools))
+		for i, t := range s.tools {
+			definitions[i] = map[string]interface{}{"name": t.name, "description": t.description, "inputSchema": t.schema}
+		}
+		return map[string]interface{}{"tools": definitions}, nil
+	case "tools/call":
+		var call struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(params, &call); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		for _, t := range s.tools {
+			if t.name == call.Name {
+				return s.call(t, call.Arguments), nil
+			}
+		}
+		return nil, &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", call.Name)}
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", method)}
+}
+
+// call runs a tool. Tool failures are results with isError set, so the
+// model sees them, rather than protocol errors.
+func (s *Server) call(t tool, arguments json.RawMessage) map[string]interface{} {
+	if len(arguments) == 0 || string(arguments) == "null" {
+		arguments = json.RawMessage("{}")
+	}
+	s.opts.Logf("Calling %s %s", t.name, arguments)
+
+	result, err := t.run(arguments)
+	if err != nil {
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": fmt.Sprintf("failed to encode result: %v", err)}},
+			"isError": true,
+		}
+	}
+	return map[string]interface{}{
+		"content":           []map[string]string{{"type": "text", "text": string(text)}},
+		"structuredContent": result,
+		"isError":           false,
+	}
+}
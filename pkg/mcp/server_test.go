@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// testGraph returns main.go -> services/auth.go -> store/sessions.go
+func testGraph() *graph.Graph {
+	g := graph.NewGraph("/repo", store.NewTripleStore())
+	main := &graph.Module{Path: "main.go", URI: "<#main.go>", Name: "main.go", Layer: "cmd", Description: "Entry point"}
+	main.AddEdge(graph.Edge{Target: "services/auth.go", Relation: graph.RelationImports, Weight: 2})
+	auth := &graph.Module{Path: "services/auth.go", URI: "<#auth.go>", Layer: "service", Tags: []string{"auth"}, Description: "Login and token checks"}
+	auth.AddEdge(graph.Edge{Target: "store/sessions.go"})
+	sessions := &graph.Module{Path: "store/sessions.go", URI: "<#sessions.go>", Layer: "data", Description: "Session storage"}
+	for _, m := range []*graph.Module{main, auth, sessions} {
+		g.AddModule(m)
+		_ = g.Store.Add(m.URI, "https://schema.codedoc.org/layer", m.Layer)
+	}
+	return g
+}
+
+// callTool calls a tool and decodes its text result
+func callTool(t *testing.T, s *Server, name string, arguments string) (map[string]interface{}, bool) {
+	t.Helper()
+	line := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"` + name + `","arguments":` + arguments + `}}`
+	response := s.handle([]byte(line))
+	if response == nil || response.Error != nil {
+		t.Fatalf("tools/call %s failed: %+v", name, response)
+	}
+
+	data, _ := json.Marshal(response.Result)
+	var result struct {
+		Content []struct{ Text string } `json:"content"`
+		IsError bool                    `json:"isError"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || len(result.Content) != 1 {
+		t.Fatalf("Unexpected result %s", data)
+	}
+	if result.IsError {
+		return map[string]interface{}{"error": result.Content[0].Text}, true
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &decoded); err != nil {
+		t.Fatalf("Tool result is not JSON: %s", result.Content[0].Text)
+	}
+	return decoded, false
+}
+
+// paths returns the path of each object in a decoded list
+func paths(list interface{}) string {
+	var names []string
+	for _, item := range list.([]interface{}) {
+		names = append(names, item.(map[string]interface{})["path"].(string))
+	}
+	return strings.Join(names, " ")
+}
+
+func TestServer_Run(t *testing.T) {
+	s := NewServer(testGraph(), Options{Version: "1.2.3"})
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":"x","method":"resources/list"}`,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	if err := s.Run(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 responses, got %d:\n%s", len(lines), out.String())
+	}
+	for _, want := range []string{`"protocolVersion":"2025-06-18"`, `"version":"1.2.3"`, `"tools":{"listChanged":false}`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("initialize response missing %s: %s", want, lines[0])
+		}
+	}
+	for _, name := range []string{"query_modules", "get_dependencies", "impact_analysis", "search_by_concept"} {
+		if !strings.Contains(lines[1], `"name":"`+name+`"`) {
+			t.Errorf("tools/list missing %s", name)
+		}
+	}
+	if !strings.Contains(lines[2], `"code":-32700`) || !strings.Contains(lines[3], `"id":"x"`) || !strings.Contains(lines[3], `"code":-32601`) {
+		t.Errorf("Unexpected error responses:\n%s\n%s", lines[2], lines[3])
+	}
+}
+
+func TestQueryModules(t *testing.T) {
+	s := NewServer(testGraph(), Options{})
+
+	result, _ := callTool(t, s, "query_modules", `{"where":"layer=service || layer=data"}`)
+	if got := paths(result["modules"]); got != "services/auth.go store/sessions.go" {
+		t.Errorf("modules = %s", got)
+	}
+	result, _ = callTool(t, s, "query_modules", `{"limit":1}`)
+	if got := paths(result["modules"]); got != "main.go" || result["total"].(float64) != 3 {
+		t.Errorf("Limited modules = %s of %v", got, result["total"])
+	}
+
+	result, _ = callTool(t, s, "query_modules", `{"sparql":"SELECT ?m WHERE { ?m <https://schema.codedoc.org/layer> \"data\" }"}`)
+	if bindings := result["bindings"].([]interface{}); len(bindings) != 1 {
+		t.Errorf("SPARQL bindings = %v", bindings)
+	}
+
+	if result, isError := callTool(t, s, "query_modules", `{"where":"layer=("}`); !isError {
+		t.Errorf("Expected an invalid filter error, got %v", result)
+	}
+	if result, isError := callTool(t, s, "query_modules", `{"filter":"x"}`); !isError || !strings.Contains(result["error"].(string), "unknown field") {
+		t.Errorf("Expected an unknown argument error, got %v", result)
+	}
+}
+
+func TestGetDependencies(t *testing.T) {
+	s := NewServer(testGraph(), Options{})
+
+	result, _ := callTool(t, s, "get_dependencies", `{"path":"services/auth.go"}`)
+	if paths(result["dependencies"]) != "store/sessions.go" || paths(result["dependents"]) != "main.go" {
+		t.Errorf("Unexpected result %v", result)
+	}
+	dependent := result["dependents"].([]interface{})[0].(map[string]interface{})
+	if dependent["relation"] != graph.RelationImports || dependent["weight"].(float64) != 2 || dependent["layer"] != "cmd" {
+		t.Errorf("Dependent edge = %v", dependent)
+	}
+
+	result, _ = callTool(t, s, "get_dependencies", `{"path":"./main.go","direction":"dependencies","transitive":true}`)
+	if got := paths(result["dependencies"]); got != "services/auth.go store/sessions.go" {
+		t.Errorf("Transitive dependencies = %s", got)
+	}
+	if _, ok := result["dependents"]; ok {
+		t.Error("Expected only dependencies")
+	}
+
+	if result, isError := callTool(t, s, "get_dependencies", `{"path":"missing.go"}`); !isError || !strings.Contains(result["error"].(string), "module not found") {
+		t.Errorf("Expected module not found, got %v", result)
+	}
+}
+
+func TestImpactAnalysis(t *testing.T) {
+	s := NewServer(testGraph(), Options{})
+
+	result, _ := callTool(t, s, "impact_analysis", `{"paths":["store/sessions.go"]}`)
+	if got := paths(result["transitive_dependents"]); got != "services/auth.go main.go" {
+		t.Errorf("Transitive dependents = %s", got)
+	}
+	if result["total_impacted_modules"].(float64) != 2 || result["risk_level"] == "" {
+		t.Errorf("Unexpected result %v", result)
+	}
+
+	result, _ = callTool(t, s, "impact_analysis", `{"paths":["store/sessions.go","services/auth.go"]}`)
+	if modules := result["modules"].([]interface{}); len(modules) != 2 {
+		t.Errorf("modules = %v", modules)
+	}
+}
+
+func TestSearchByConcept(t *testing.T) {
+	s := NewServer(testGraph(), Options{Concepts: map[string][]string{"store/sessions.go": {"authentication"}}})
+
+	result, _ := callTool(t, s, "search_by_concept", `{"concept":"auth"}`)
+	// Both score 4: the tag and path of auth.go, the concept of sessions.go
+	if got := paths(result["modules"]); got != "services/auth.go store/sessions.go" {
+		t.Errorf("modules = %s", got)
+	}
+	first := result["modules"].([]interface{})[0].(map[string]interface{})
+	if first["score"].(float64) != 4 {
+		t.Errorf("score = %v", first["score"])
+	}
+
+	result, _ = callTool(t, s, "search_by_concept", `{"concept":"billing"}`)
+	if modules := result["modules"].([]interface{}); len(modules) != 0 {
+		t.Errorf("Expected no modules, got %v", modules)
+	}
+}
@@ -0,0 +1,443 @@
+/*
+# Module: pkg/mcp/tools.go
+MCP tools over the knowledge graph.
+
+Defines the query_modules, get_dependencies, impact_analysis and
+search_by_concept tools with their JSON input schemas. Results are plain
+structs encoded as JSON, with module paths relative to the project root.
+
+## Linked Modules
+- [server](./server.go) - MCP server
+- [../graph](../graph/graph.go) - Graph data structure
+- [../filter](../filter/filter.go) - Filter expressions
+- [../query](../query/executor.go) - SPARQL execution
+- [../analysis](../analysis/impact.go) - Impact analysis
+
+## Tags
+mcp, ai, tools, query
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#tools.go> a code:Module ;
+    code:name "pkg/mcp/tools.go" ;
+    code:description "MCP tools over the knowledge graph" ;
+    code:language "go" ;
+    code:layer "mcp" ;
+    code:linksTo <./server.go>, <../graph/graph.go>, <../filter/filter.go>, <../query/executor.go>, <../analysis/impact.go> ;
+    code:tags "mcp", "ai", "tools", "query" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/filter"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+)
+
+// defaultLimit caps the modules a tool returns unless the call sets limit
+const defaultLimit = 50
+
+// tool is an MCP tool with its JSON Schema input
+type tool struct {
+	name        string
+	description string
+	schema      map[string]interface{}
+	run         func(arguments json.RawMessage) (interface{}, error)
+}
+
+// moduleSummary is a module as tools return it
+type moduleSummary struct {
+	Path        string   `json:"path"`
+	Description string   `json:"description,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Layer       string   `json:"layer,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Exports     []string `json:"exports,omitempty"`
+}
+
+// summarize returns the summary of a module
+func summarize(m *graph.Module) moduleSummary {
+	return moduleSummary{
+		Path:        m.Path,
+		Description: m.Description,
+		Language:    m.Language,
+		Layer:       m.Layer,
+		Tags:        m.Tags,
+		Exports:     m.Exports,
+	}
+}
+
+// object returns a JSON Schema object with properties, some required
+func object(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// property returns a JSON Schema property of a type
+func property(kind, description string) map[string]interface{} {
+	return map[string]interface{}{"type": kind, "description": description}
+}
+
+// decode unmarshals tool arguments, rejecting unknown ones
+func decode(arguments json.RawMessage, v interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(string(arguments)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// graphTools returns the tools of the server
+func (s *Server) graphTools() []tool {
+	limit := property("integer", fmt.Sprintf("Maximum number of modules to return (default %d)", defaultLimit))
+	return []tool{
+		{
+			name: "query_modules",
+			description: "List modules of the project matching a filter expression such as " +
+				"'layer=service && tag=auth' or 'language=go && path!=internal/*', or run a SPARQL " +
+				"SELECT query over the RDF triples (prefix code: <https://schema.codedoc.org/>).",
+			schema: object(map[string]interface{}{
+				"where":  property("string", "Filter expression over path, name, description, language, layer, tag, export, dependency and other properties; empty matches every module"),
+				"sparql": property("string", "SPARQL SELECT query to run instead of the filter"),
+				"limit":  limit,
+			}),
+			run: s.queryModules,
+		},
+		{
+			name:        "get_dependencies",
+			description: "Get the modules a module depends on and the modules that depend on it, with the relation and weight of each edge, optionally transitively.",
+			schema: object(map[string]interface{}{
+				"path":       property("string", "Module path relative to the project root"),
+				"direction":  map[string]interface{}{"type": "string", "enum": []string{"dependencies", "dependents", "both"}, "description": "Which edges to return (default both)"},
+				"transitive": property("boolean", "Follow edges transitively and report the depth of each module"),
+			}, "path"),
+			run: s.getDependencies,
+		},
+		{
+			name:        "impact_analysis",
+			description: "Assess the impact of changing one or more modules: direct and transitive dependents, impact by layer, risk level, risk factors and recommendations.",
+			schema: object(map[string]interface{}{
+				"paths": map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}, "description": "Paths of the modules being changed"},
+			}, "paths"),
+			run: s.impactAnalysis,
+		},
+		{
+			name:        "search_by_concept",
+			description: "Find modules related to a concept or feature, such as 'authentication' or 'rate limiting', ranked by matches in shadow concepts, tags, descriptions, names and paths.",
+			schema: object(map[string]interface{}{
+				"concept": property("string", "Concept or keywords to search for"),
+				"limit":   limit,
+			}, "concept"),
+			run: s.searchByConcept,
+		},
+	}
+}
+
+// module returns the module at a path or alias
+func (s *Server) module(path string) (*graph.Module, error) {
+	module := s.graph.GetModule(strings.TrimPrefix(path, "./"))
+	if module == nil {
+		return nil, fmt.Errorf("module not found: %s", path)
+	}
+	return module, nil
+}
+
+// queryModules runs query_modules
+func (s *Server) queryModules(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Where  string `json:"where"`
+		SPARQL string `json:"sparql"`
+		Limit  int    `json:"limit"`
+	}
+	if err := decode(arguments, &args); err != nil {
+		return nil, err
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	if args.SPARQL != "" {
+		result, err := query.NewExecutor(s.graph.Store).ExecuteString(args.SPARQL)
+		if err != nil {
+			return nil, fmt.Errorf("query failed: %w", err)
+		}
+		bindings := result.Bindings
+		if len(bindings) > limit {
+			bindings = bindings[:limit]
+		}
+		return map[string]interface{}{"variables": result.Variables, "bindings": bindings, "total": result.Count}, nil
+	}
+
+	where, err := filter.Parse(args.Where)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*graph.Module
+	for _, module := range s.graph.Modules {
+		if where.Match(module) {
+			matches = append(matches, module)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Path < matches[j].Path })
+
+	modules := make([]moduleSummary, 0, min(len(matches), limit))
+	for _, module := range matches[:min(len(matches), limit)] {
+		modules = append(modules, summarize(module))
+	}
+	return map[string]interface{}{"modules": modules, "total": len(matches)}, nil
+}
+
+// dependency is an edge returned by get_dependencies
+type dependency struct {
+	Path       string  `json:"path"`
+	Relation   string  `json:"relation,omitempty"`
+	Predicate  string  `json:"predicate,omitempty"`
+	Weight     int     `json:"weight,omitempty"`
+	Inferred   bool    `json:"inferred,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Depth      int     `json:"depth,omitempty"` // Transitive results only
+	Layer      string  `json:"layer,omitempty"`
+}
+
+// getDependencies runs get_dependencies
+func (s *Server) getDependencies(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Path       string `json:"path"`
+		Direction  string `json:"direction"`
+		Transitive bool   `json:"transitive"`
+	}
+	if err := decode(arguments, &args); err != nil {
+		return nil, err
+	}
+	module, err := s.module(args.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{"module": summarize(module)}
+	switch args.Direction {
+	case "", "both":
+		result["dependencies"] = s.dependencies(module, args.Transitive)
+		result["dependents"] = s.dependents(module, args.Transitive)
+	case "dependencies":
+		result["dependencies"] = s.dependencies(module, args.Transitive)
+	case "dependents":
+		result["dependents"] = s.dependents(module, args.Transitive)
+	default:
+		return nil, fmt.Errorf("unknown direction %q (must be dependencies, dependents or both)", args.Direction)
+	}
+	return result, nil
+}
+
+// dependencies returns the edges of a module, or every module it reaches
+// with its depth
+func (s *Server) dependencies(module *graph.Module, transitive bool) []dependency {
+	if transitive {
+		return s.byDepth(analysis.TransitiveDependencies(s.graph, module.Path))
+	}
+	deps := make([]dependency, 0, len(module.Dependencies))
+	for _, edge := range module.DependencyEdges() {
+		deps = append(deps, s.edge(edge.Target, edge))
+	}
+	return deps
+}
+
+// dependents returns the modules with an edge to a module, or every module
+// reaching it with its depth
+func (s *Server) dependents(module *graph.Module, transitive bool) []dependency {
+	if transitive {
+		return s.byDepth(analysis.TransitiveDependents(s.graph, module.Path))
+	}
+	deps := make([]dependency, 0)
+	for path, other := range s.graph.Modules {
+		for _, target := range other.Dependencies {
+			if target == module.Path {
+				deps = append(deps, s.edge(path, other.EdgeTo(target)))
+				break
+			}
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Path < deps[j].Path })
+	return deps
+}
+
+// edge returns a dependency to or from path with the metadata of an edge
+func (s *Server) edge(path string, edge graph.Edge) dependency {
+	dep := dependency{
+		Path:       path,
+		Relation:   edge.Relation,
+		Predicate:  edge.Predicate,
+		Weight:     edge.Weight,
+		Inferred:   edge.Inferred,
+		Confidence: edge.Confidence,
+	}
+	if module := s.graph.GetModule(path); module != nil {
+		dep.Layer = module.Layer
+	}
+	return dep
+}
+
+// byDepth returns modules by depth, then path
+func (s *Server) byDepth(depths map[string]int) []dependency {
+	deps := make([]dependency, 0, len(depths))
+	for path, depth := range depths {
+		dep := dependency{Path: path, Depth: depth}
+		if module := s.graph.GetModule(path); module != nil {
+			dep.Layer = module.Layer
+		}
+		deps = append(deps, dep)
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Depth != deps[j].Depth {
+			return deps[i].Depth < deps[j].Depth
+		}
+		return deps[i].Path < deps[j].Path
+	})
+	return deps
+}
+
+// impactAnalysis runs impact_analysis
+func (s *Server) impactAnalysis(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Paths []string `json:"paths"`
+	}
+	if err := decode(arguments, &args); err != nil {
+		return nil, err
+	}
+	if len(args.Paths) == 0 {
+		return nil, fmt.Errorf("paths is required")
+	}
+	paths := make([]string, len(args.Paths))
+	for i, p := range args.Paths {
+		module, err := s.module(p)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = module.Path
+	}
+
+	ia := analysis.NewImpactAnalysis(s.graph)
+	var result *analysis.ImpactResult
+	var err error
+	if len(paths) == 1 {
+		result, err = ia.AnalyzeImpact(paths[0])
+	} else {
+		result, err = ia.AnalyzeMultipleModules(paths)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"modules":                paths,
+		"risk_level":             result.RiskLevel,
+		"risk_factors":           result.RiskFactors,
+		"recommendations":        result.Recommendations,
+		"direct_dependents":      result.DirectDependents,
+		"direct_call_sites":      result.DirectCallSites,
+		"transitive_dependents":  s.byDepth(result.TransitiveDependents),
+		"total_impacted_modules": result.TotalImpactedModules,
+		"impact_percentage":      result.ImpactPercentage,
+		"impact_by_layer":        result.ImpactByLayer,
+		"max_impact_depth":       result.MaxImpactDepth,
+		"critical_paths":         result.CriticalPaths,
+	}, nil
+}
+
+// conceptMatch is a module found by search_by_concept
+type conceptMatch struct {
+	moduleSummary
+	Concepts []string `json:"concepts,omitempty"`
+	Score    int      `json:"score"`
+}
+
+// Weights of a search term matching each field
+const (
+	conceptWeight     = 4
+	tagWeight         = 3
+	descriptionWeight = 2
+	pathWeight        = 1
+)
+
+// searchByConcept runs search_by_concept. Each term scores a module once
+// per field it occurs in.
+func (s *Server) searchByConcept(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Concept string `json:"concept"`
+		Limit   int    `json:"limit"`
+	}
+	if err := decode(arguments, &args); err != nil {
+		return nil, err
+	}
+	terms := strings.Fields(strings.ToLower(args.Concept))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("concept is required")
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	var matches []conceptMatch
+	for path, module := range s.graph.Modules {
+		concepts := s.opts.Concepts[path]
+		score := 0
+		for _, term := range terms {
+			if containsTerm(concepts, term) {
+				score += conceptWeight
+			}
+			if containsTerm(module.Tags, term) {
+				score += tagWeight
+			}
+			if strings.Contains(strings.ToLower(module.Description), term) {
+				score += descriptionWeight
+			}
+			if strings.Contains(strings.ToLower(module.Name+" "+module.Path), term) {
+				score += pathWeight
+			}
+		}
+		if score > 0 {
+			matches = append(matches, conceptMatch{moduleSummary: summarize(module), Concepts: concepts, Score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Path < matches[j].Path
+	})
+
+	total := len(matches)
+	if total > limit {
+		matches = matches[:limit]
+	}
+	if matches == nil {
+		matches = []conceptMatch{}
+	}
+	return map[string]interface{}{"modules": matches, "total": total}, nil
+}
+
+// containsTerm reports whether any value contains a lowercase term
+func containsTerm(values []string, term string) bool {
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), term) {
+			return true
+		}
+	}
+	return false
+}
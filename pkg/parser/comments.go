@@ -0,0 +1,158 @@
+/*
+# Module: pkg/parser/comments.go
+Per-language LinkedDoc comment styles.
+
+A comment style gives the markers that open and close a LinkedDoc block,
+the line comment leader stripped from block lines, the block comment
+delimiters used when writing headers, and how many leading lines (such as
+a license header) are not searched for markers. Styles are registered by
+scanner language key; languages without a registered style use the
+default markers with the comment leader found in front of the start marker.
+
+## Linked Modules
+- [parser](./parser.go) - LinkedDoc parser
+
+## Tags
+parser, linkeddoc, comments, configuration
+
+## Exports
+CommentStyle, DefaultStartMarker, DefaultEndMarker, DefaultCommentStyle, CommentStyleFor, RegisterCommentStyle
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#comments.go> a code:Module ;
+    code:name "pkg/parser/comments.go" ;
+    code:description "Per-language LinkedDoc comment styles" ;
+    code:language "go" ;
+    code:layer "parser" ;
+    code:linksTo <./parser.go> ;
+    code:exports <#CommentStyle>, <#DefaultStartMarker>, <#DefaultEndMarker>, <#DefaultCommentStyle>, <#CommentStyleFor>, <#RegisterCommentStyle> ;
+    code:tags "parser", "linkeddoc", "comments", "configuration" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package parser
+
+import (
+	"strings"
+	"sync"
+)
+
+// Default LinkedDoc block markers
+const (
+	DefaultStartMarker = "<!-- LinkedDoc RDF -->"
+	DefaultEndMarker   = "<!-- End LinkedDoc RDF -->"
+)
+
+// CommentStyle describes how LinkedDoc blocks are written in a language
+type CommentStyle struct {
+	StartMarker string `yaml:"start_marker,omitempty"`
+	EndMarker   string `yaml:"end_marker,omitempty"`
+
+	// LinePrefix is a line comment leader ("#", "//", "--") stripped, with
+	// one following space, from block lines
+	LinePrefix string `yaml:"line_prefix,omitempty"`
+
+	// BlockStart and BlockEnd wrap generated headers ("/*" and "*/")
+	BlockStart string `yaml:"block_start,omitempty"`
+	BlockEnd   string `yaml:"block_end,omitempty"`
+
+	// SkipLines is the number of leading lines, such as a license header,
+	// not searched for markers
+	SkipLines int `yaml:"skip_lines,omitempty"`
+}
+
+// withDefaults fills unset markers with the default ones
+func (s CommentStyle) withDefaults() CommentStyle {
+	if s.StartMarker == "" {
+		s.StartMarker = DefaultStartMarker
+	}
+	if s.EndMarker == "" {
+		s.EndMarker = DefaultEndMarker
+	}
+	return s
+}
+
+// merge overlays the set fields of override on s
+func (s CommentStyle) merge(override CommentStyle) CommentStyle {
+	if override.StartMarker != "" {
+		s.StartMarker = override.StartMarker
+	}
+	if override.EndMarker != "" {
+		s.EndMarker = override.EndMarker
+	}
+	if override.LinePrefix != "" {
+		s.LinePrefix = override.LinePrefix
+	}
+	if override.BlockStart != "" {
+		s.BlockStart = override.BlockStart
+	}
+	if override.BlockEnd != "" {
+		s.BlockEnd = override.BlockEnd
+	}
+	if override.SkipLines > 0 {
+		s.SkipLines = override.SkipLines
+	}
+	return s
+}
+
+var blockCommentStyle = CommentStyle{BlockStart: "/*", BlockEnd: "*/"}
+
+// builtinCommentStyles maps scanner language keys to their header comments
+var builtinCommentStyles = map[string]CommentStyle{
+	"go":         blockCommentStyle,
+	"javascript": blockCommentStyle,
+	"typescript": blockCommentStyle,
+	"java":       blockCommentStyle,
+	"rust":       blockCommentStyle,
+	"c":          blockCommentStyle,
+	"cpp":        blockCommentStyle,
+	"csharp":     blockCommentStyle,
+	"swift":      blockCommentStyle,
+	"kotlin":     blockCommentStyle,
+	"scala":      blockCommentStyle,
+	"php":        blockCommentStyle,
+	"python":     {BlockStart: `"""`, BlockEnd: `"""`},
+	"ruby":       {BlockStart: "=begin", BlockEnd: "=end"},
+}
+
+var (
+	commentStylesMu sync.RWMutex
+	commentStyles   = make(map[string]CommentStyle)
+)
+
+// DefaultCommentStyle returns the style of languages without a registered one
+func DefaultCommentStyle() CommentStyle {
+	return CommentStyle{}.withDefaults()
+}
+
+// CommentStyleFor returns the comment style of a scanner language key
+// ("go", "python"), with unset markers defaulted
+func CommentStyleFor(language string) CommentStyle {
+	language = strings.ToLower(language)
+	commentStylesMu.RLock()
+	style, ok := commentStyles[language]
+	commentStylesMu.RUnlock()
+	if !ok {
+		style = builtinCommentStyles[language]
+	}
+	return style.withDefaults()
+}
+
+// RegisterCommentStyle sets the comment style of a language. Fields left
+// empty keep the language's built-in values.
+func RegisterCommentStyle(language string, style CommentStyle) {
+	language = strings.ToLower(language)
+	commentStylesMu.Lock()
+	defer commentStylesMu.Unlock()
+	commentStyles[language] = builtinCommentStyles[language].merge(style)
+}
+
+// resetCommentStyles removes registered styles (for tests)
+func resetCommentStyles() {
+	commentStylesMu.Lock()
+	defer commentStylesMu.Unlock()
+	commentStyles = make(map[string]CommentStyle)
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommentStyleFor(t *testing.T) {
+	t.Cleanup(resetCommentStyles)
+
+	if style := CommentStyleFor("go"); style.BlockStart != "/*" || style.StartMarker != DefaultStartMarker {
+		t.Errorf("go style = %+v, want block comments with the default markers", style)
+	}
+	if style := CommentStyleFor("unknown"); style != DefaultCommentStyle() {
+		t.Errorf("unknown style = %+v, want the default", style)
+	}
+
+	RegisterCommentStyle("Python", CommentStyle{LinePrefix: "#"})
+	style := CommentStyleFor("python")
+	if style.LinePrefix != "#" || style.BlockStart != `"""` || style.EndMarker != DefaultEndMarker {
+		t.Errorf("python style = %+v, want the line prefix over the built-in style", style)
+	}
+}
+
+func TestExtractLinkedDocFor_LinePrefix(t *testing.T) {
+	t.Cleanup(resetCommentStyles)
+	RegisterCommentStyle("python", CommentStyle{LinePrefix: "#"})
+
+	content := `#!/usr/bin/env python3
+# Module: tool.py
+#
+# <!-- LinkedDoc RDF -->
+# @prefix code: <https://schema.codedoc.org/> .
+#     <#tool.py> a code:Module .
+# <!-- End LinkedDoc RDF -->
+
+import os
+`
+	got, err := NewParser().ExtractLinkedDocFor(content, "python")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "@prefix code: <https://schema.codedoc.org/> .\n    <#tool.py> a code:Module ."
+	if got != want {
+		t.Errorf("ExtractLinkedDocFor() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractLinkedDocFor_CustomMarkersAndSkipLines(t *testing.T) {
+	t.Cleanup(resetCommentStyles)
+	RegisterCommentStyle("sql", CommentStyle{
+		StartMarker: "-- BEGIN LINKEDDOC",
+		EndMarker:   "-- END LINKEDDOC",
+		LinePrefix:  "--",
+		SkipLines:   2,
+	})
+
+	// The license header mentions a marker but is skipped
+	content := `-- Copyright Example Corp.
+-- -- BEGIN LINKEDDOC is reserved
+-- BEGIN LINKEDDOC
+-- @prefix code: <https://schema.codedoc.org/> .
+-- <#schema.sql> a code:Module ;
+--     code:name "schema.sql" .
+-- END LINKEDDOC
+CREATE TABLE t (id int);
+`
+	triples, err := NewParser().ParseStringFor(content, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triples) != 2 {
+		t.Fatalf("Got %d triples, want 2: %v", len(triples), triples)
+	}
+
+	// The default style does not see the custom markers
+	if block, _ := NewParser().ExtractLinkedDoc(content); block != "" {
+		t.Errorf("ExtractLinkedDoc() = %q, want no block", block)
+	}
+}
+
+func TestExtractLinkedDocBlocksFor(t *testing.T) {
+	t.Cleanup(resetCommentStyles)
+	RegisterCommentStyle("ruby", CommentStyle{LinePrefix: "#"})
+
+	content := `# <!-- LinkedDoc RDF -->
+# <#a> a <#Module> .
+# <!-- End LinkedDoc RDF -->
+# <!-- LinkedDoc RDF -->
+# <#b> a <#Module> .
+# <!-- End LinkedDoc RDF -->
+`
+	blocks, err := NewParser().ExtractLinkedDocBlocksFor(content, "ruby")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 2 || !strings.HasPrefix(blocks[1], "<#b>") {
+		t.Errorf("Blocks = %q, want two blocks", blocks)
+	}
+}
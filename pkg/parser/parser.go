@@ -3,10 +3,14 @@
 LinkedDoc+RDF parser implementation.
 
 Extracts RDF/Turtle triples from LinkedDoc comment blocks in source code.
-Supports @prefix declarations, URIs, literals, and blank nodes.
+Supports @prefix declarations, URIs, literals, and blank nodes. A file may
+contain several LinkedDoc blocks, e.g. one per logical component. Block
+markers and comment leaders follow the comment style registered for the
+file's language.
 
 ## Linked Modules
 - [triple](./triple.go) - Triple data structure
+- [comments](./comments.go) - Per-language comment styles
 
 ## Tags
 parser, rdf, turtle, linkeddoc
@@ -23,7 +27,7 @@ Parser, NewParser, ParseError
     code:description "LinkedDoc+RDF parser implementation" ;
     code:language "go" ;
     code:layer "parser" ;
-    code:linksTo <./triple.go> ;
+    code:linksTo <./triple.go>, <./comments.go> ;
     code:exports <#Parser>, <#NewParser>, <#ParseError> ;
     code:tags "parser", "rdf", "turtle", "linkeddoc" .
 
@@ -31,7 +35,7 @@ Parser, NewParser, ParseError
     code:name "Parser" ;
     code:kind "struct" ;
     code:description "LinkedDoc parser" ;
-    code:hasMethod <#Parser.Parse>, <#Parser.ParseString>, <#Parser.ExtractLinkedDoc> .
+    code:hasMethod <#Parser.Parse>, <#Parser.ParseString>, <#Parser.ExtractLinkedDoc>, <#Parser.ExtractLinkedDocBlocks>, <#Parser.ParseFor>, <#Parser.ParseStringFor>, <#Parser.ExtractLinkedDocFor>, <#Parser.ExtractLinkedDocBlocksFor> .
 
 <#Parser.Parse> a code:Method ;
     code:name "Parse" ;
@@ -44,6 +48,10 @@ Parser, NewParser, ParseError
 <#Parser.ExtractLinkedDoc> a code:Method ;
     code:name "ExtractLinkedDoc" ;
     code:description "Extracts LinkedDoc RDF block from content" .
+
+<#Parser.ExtractLinkedDocBlocks> a code:Method ;
+    code:name "ExtractLinkedDocBlocks" ;
+    code:description "Extracts every LinkedDoc RDF block from content" .
 <!-- End LinkedDoc RDF -->
 */
 
@@ -84,56 +92,195 @@ func NewParser() *Parser {
 
 // Parse extracts RDF triples from a source file
 func (p *Parser) Parse(filePath string) ([]Triple, error) {
+	return p.ParseFor(filePath, "")
+}
+
+// ParseFor extracts RDF triples from a source file written in language (a
+// scanner language key), using the language's comment style
+func (p *Parser) ParseFor(filePath, language string) ([]Triple, error) {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	return p.ParseString(string(content))
+	return p.ParseStringFor(string(content), language)
 }
 
 // ParseString extracts RDF triples from a string
 func (p *Parser) ParseString(content string) ([]Triple, error) {
+	return p.ParseStringFor(content, "")
+}
+
+// ParseStringFor extracts RDF triples from a string in the comment style of
+// language; "" uses the default style
+func (p *Parser) ParseStringFor(content, language string) ([]Triple, error) {
 	// Reset prefixes for each parse with standard RDF prefix
 	p.prefixes = map[string]string{
 		"rdf": "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
 	}
 
-	linkedDoc, err := p.ExtractLinkedDoc(content)
+	blocks, err := p.ExtractLinkedDocBlocksFor(content, language)
 	if err != nil {
 		return nil, err
 	}
 
-	if linkedDoc == "" {
-		// No LinkedDoc block found - not an error, just return empty
-		return []Triple{}, nil
+	// No LinkedDoc block found - not an error, just return empty
+	triples := []Triple{}
+	for _, block := range blocks {
+		blockTriples, err := p.parseRDF(block)
+		if err != nil {
+			return nil, err
+		}
+		triples = append(triples, blockTriples...)
 	}
 
-	return p.parseRDF(linkedDoc)
+	return triples, nil
 }
 
 // ExtractLinkedDoc extracts the LinkedDoc RDF block from content
 func (p *Parser) ExtractLinkedDoc(content string) (string, error) {
-	startMarker := "<!-- LinkedDoc RDF -->"
-	endMarker := "<!-- End LinkedDoc RDF -->"
+	return p.ExtractLinkedDocFor(content, "")
+}
 
-	startIdx := strings.Index(content, startMarker)
+// ExtractLinkedDocFor extracts the first LinkedDoc RDF block from content in
+// the comment style of language; "" uses the default style
+func (p *Parser) ExtractLinkedDocFor(content, language string) (string, error) {
+	block, _, err := firstBlock(content, CommentStyleFor(language))
+	return block, err
+}
+
+// firstBlock returns the first block in content and the content after its
+// end marker. The start marker may appear anywhere after the style's
+// skipped lines; a comment leader in front of it, or the style's line
+// prefix, is stripped from the block's lines.
+func firstBlock(content string, style CommentStyle) (string, string, error) {
+	content = skipLines(content, style.SkipLines)
+
+	startIdx := strings.Index(content, style.StartMarker)
 	if startIdx == -1 {
-		return "", nil // No LinkedDoc block
+		return "", "", nil // No LinkedDoc block
 	}
 
-	endIdx := strings.Index(content, endMarker)
+	endIdx := strings.Index(content, style.EndMarker)
 	if endIdx == -1 {
-		return "", ParseError{Message: "LinkedDoc block not closed (missing <!-- End LinkedDoc RDF -->)"}
+		return "", "", ParseError{Message: fmt.Sprintf("LinkedDoc block not closed (missing %s)", style.EndMarker)}
 	}
 
 	if endIdx <= startIdx {
-		return "", ParseError{Message: "Invalid LinkedDoc block (end marker before start)"}
+		return "", "", ParseError{Message: "Invalid LinkedDoc block (end marker before start)"}
+	}
+
+	leader := style.LinePrefix
+	if leader == "" {
+		lineStart := strings.LastIndex(content[:startIdx], "\n") + 1
+		if prefix, ok := leaderPrefix(content[lineStart:startIdx], ""); ok {
+			leader = strings.TrimSpace(prefix)
+		}
 	}
 
 	// Extract content between markers
-	rdfContent := content[startIdx+len(startMarker) : endIdx]
-	return strings.TrimSpace(rdfContent), nil
+	rdfContent := content[startIdx+len(style.StartMarker) : endIdx]
+	if leader != "" {
+		lines := strings.Split(rdfContent, "\n")
+		for i, line := range lines {
+			lines[i] = stripLeader(line, leader)
+		}
+		rdfContent = strings.Join(lines, "\n")
+	}
+	return strings.TrimSpace(rdfContent), content[endIdx+len(style.EndMarker):], nil
+}
+
+// ExtractLinkedDocBlocks extracts every LinkedDoc RDF block from content, in
+// order. The first block is found as by ExtractLinkedDoc; later blocks must
+// have their markers on lines of their own, optionally behind a line comment
+// leader such as "//" or "#" which is then stripped from the block's lines.
+// Marker strings elsewhere in code are ignored.
+func (p *Parser) ExtractLinkedDocBlocks(content string) ([]string, error) {
+	return p.ExtractLinkedDocBlocksFor(content, "")
+}
+
+// ExtractLinkedDocBlocksFor extracts every LinkedDoc RDF block from content
+// in the comment style of language; "" uses the default style
+func (p *Parser) ExtractLinkedDocBlocksFor(content, language string) ([]string, error) {
+	style := CommentStyleFor(language)
+	first, rest, err := firstBlock(content, style)
+	if err != nil || first == "" {
+		return nil, err
+	}
+	blocks := []string{first}
+
+	var current []string
+	var leader string
+	inBlock := false
+	for _, line := range strings.Split(rest, "\n") {
+		if !inBlock {
+			if prefix, ok := markerPrefix(line, style.StartMarker, style.LinePrefix); ok {
+				inBlock, leader, current = true, prefix, nil
+				if style.LinePrefix != "" {
+					leader = style.LinePrefix
+				}
+			}
+			continue
+		}
+
+		if _, ok := markerPrefix(line, style.EndMarker, style.LinePrefix); ok {
+			blocks = append(blocks, strings.TrimSpace(strings.Join(current, "\n")))
+			inBlock = false
+			continue
+		}
+		if style.LinePrefix != "" {
+			current = append(current, stripLeader(line, leader))
+		} else {
+			current = append(current, strings.TrimPrefix(line, leader))
+		}
+	}
+
+	if inBlock {
+		return nil, ParseError{Message: fmt.Sprintf("LinkedDoc block %d not closed (missing %s)", len(blocks)+1, style.EndMarker)}
+	}
+
+	return blocks, nil
+}
+
+// markerPrefix reports whether line holds only marker, possibly behind
+// indentation and a line comment leader, and returns that prefix
+func markerPrefix(line, marker, linePrefix string) (string, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if !strings.HasSuffix(line, marker) {
+		return "", false
+	}
+	return leaderPrefix(strings.TrimSuffix(line, marker), linePrefix)
+}
+
+// leaderPrefix reports whether prefix is only indentation and a comment
+// leader: punctuation such as "//", "#" or " *", or linePrefix
+func leaderPrefix(prefix, linePrefix string) (string, bool) {
+	trimmed := strings.TrimSpace(prefix)
+	if trimmed == "" || strings.Trim(trimmed, "/#*;-") == "" || (linePrefix != "" && trimmed == linePrefix) {
+		return prefix, true
+	}
+	return "", false
+}
+
+// stripLeader removes indentation, leader and one following space from line
+func stripLeader(line, leader string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(trimmed, leader) {
+		return line
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(trimmed, leader), " ")
+}
+
+// skipLines drops the first n lines of content
+func skipLines(content string, n int) string {
+	for ; n > 0; n-- {
+		i := strings.Index(content, "\n")
+		if i == -1 {
+			return ""
+		}
+		content = content[i+1:]
+	}
+	return content
 }
 
 // parseRDF parses RDF/Turtle triples from LinkedDoc content
@@ -278,8 +425,10 @@ func (p *Parser) parseTripleLine(line string, currentSubject string, lineNum int
 	// Remove trailing punctuation for parsing
 	line = strings.TrimRight(line, ";.,")
 
-	// Check if this is a new subject declaration
-	if strings.Contains(line, " a ") || strings.Contains(line, " code:") || strings.Contains(line, " rdf:") || strings.Contains(line, " rdfs:") || strings.Contains(line, " sec:") || strings.Contains(line, " arch:") {
+	// Check if this is a new subject declaration, ignoring the text of
+	// literals such as "Creates a new user"
+	terms := stripLiterals(line)
+	if strings.Contains(terms, " a ") || strings.Contains(terms, " code:") || strings.Contains(terms, " rdf:") || strings.Contains(terms, " rdfs:") || strings.Contains(terms, " sec:") || strings.Contains(terms, " arch:") {
 		parts := strings.SplitN(line, " ", 2)
 		if len(parts) >= 2 {
 			subject := p.expandPrefix(strings.TrimSpace(parts[0]))
@@ -309,6 +458,30 @@ func (p *Parser) parseTripleLine(line string, currentSubject string, lineNum int
 	return triples, "", nil
 }
 
+// stripLiterals blanks out the contents of quoted literals in a line
+func stripLiterals(line string) string {
+	var b strings.Builder
+	quoted, escaped := false, false
+	for _, ch := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && ch == '\\':
+			escaped = true
+		case ch == '"':
+			quoted = !quoted
+			b.WriteRune(ch)
+			continue
+		}
+		if quoted {
+			b.WriteByte('_')
+		} else {
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
+
 // parsePredicateObjects parses predicate-object pairs
 func (p *Parser) parsePredicateObjects(subject, line string, lineNum int) ([]Triple, error) {
 	var triples []Triple
@@ -173,6 +173,24 @@ func TestParseString(t *testing.T) {
 				return len(triples) == 3
 			},
 		},
+		{
+			name: "literal containing a keyword",
+			content: `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#UserService.CreateUser> a code:Method ;
+    code:description "Creates a new user with code: rdf: links" ;
+    code:calls <./auth.go#CheckPermission> .
+<!-- End LinkedDoc RDF -->
+*/`,
+			wantTriples: 3,
+			checkTriple: func(triples []Triple) bool {
+				return len(triples) == 3 &&
+					triples[1].Subject == "<#UserService.CreateUser>" &&
+					triples[1].Object.String() == "Creates a new user with code: rdf: links" &&
+					triples[2].Subject == "<#UserService.CreateUser>"
+			},
+		},
 		{
 			name: "multiple values",
 			content: `/*
@@ -507,3 +525,48 @@ func TestBlankNodeParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractLinkedDocBlocks(t *testing.T) {
+	content := `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#handlers.go> a code:Module .
+<!-- End LinkedDoc RDF -->
+*/
+package handlers
+
+const marker = "<!-- LinkedDoc RDF -->"
+
+// <!-- LinkedDoc RDF -->
+// <#UserHandler> a code:Component ;
+//     code:linksTo <./user.go> .
+// <!-- End LinkedDoc RDF -->
+func UserHandler() {}
+`
+
+	p := NewParser()
+	blocks, err := p.ExtractLinkedDocBlocks(content)
+	if err != nil {
+		t.Fatalf("ExtractLinkedDocBlocks() error = %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 blocks, got %d: %q", len(blocks), blocks)
+	}
+	want := "<#UserHandler> a code:Component ;\n    code:linksTo <./user.go> ."
+	if blocks[1] != want {
+		t.Errorf("Second block = %q, want %q", blocks[1], want)
+	}
+
+	triples, err := p.ParseString(content)
+	if err != nil {
+		t.Fatalf("ParseString() error = %v", err)
+	}
+	if len(triples) != 3 {
+		t.Errorf("Expected 3 triples across blocks, got %d", len(triples))
+	}
+
+	unclosed := content + "\n// <!-- LinkedDoc RDF -->\n// <#Other> a code:Component .\n"
+	if _, err := p.ExtractLinkedDocBlocks(unclosed); err == nil {
+		t.Error("Expected error for unclosed block")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+# Module: pkg/pathkey/pathkey.go
+Canonical file-path keys.
+
+Shadow entries, the shadow index, the module cache and graph module keys all
+identify files by project-relative path. On Windows those paths arrive with
+backslashes, and on case-insensitive filesystems "Service.go" and
+"service.go" name the same file. This package maps paths to one canonical
+form: forward slashes, cleaned, no leading "./", and optionally case-folded.
+
+Case folding defaults to on for Windows and macOS and can be configured with
+paths.fold_case in .graphfs/config.yaml.
+
+## Linked Modules
+- [../shadow](../shadow/shadow.go) - Shadow file system
+- [../cache](../cache/manager.go) - Module cache
+
+## Tags
+paths, normalization, cross-platform, windows
+
+## Exports
+Normalizer, Canonical, Default, DefaultFoldCase, SetDefaultFoldCase, ParseFoldCase
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#pathkey.go> a code:Module ;
+    code:name "pkg/pathkey/pathkey.go" ;
+    code:description "Canonical file-path keys" ;
+    code:language "go" ;
+    code:layer "utility" ;
+    code:linksTo <../shadow/shadow.go>, <../cache/manager.go> ;
+    code:exports <#Normalizer>, <#Canonical>, <#Default>, <#DefaultFoldCase>, <#SetDefaultFoldCase>, <#ParseFoldCase> ;
+    code:tags "paths", "normalization", "cross-platform", "windows" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package pathkey
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// foldCaseOverride holds the configured folding: 0 = platform default,
+// 1 = fold, 2 = don't fold
+var foldCaseOverride atomic.Int32
+
+// Normalizer maps file paths to canonical keys
+type Normalizer struct {
+	// FoldCase lowercases keys, for case-insensitive filesystems
+	FoldCase bool
+}
+
+// Default returns a normalizer using the configured (or platform default)
+// case folding
+func Default() Normalizer {
+	return Normalizer{FoldCase: DefaultFoldCase()}
+}
+
+// Key returns the canonical key for a path: forward slashes, cleaned, without
+// a leading "./" and lowercased if FoldCase is set. Backslashes are treated as
+// separators on every platform, so entries written on Windows match.
+func (n Normalizer) Key(p string) string {
+	key := Canonical(p)
+	if n.FoldCase {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// Equal reports whether two paths have the same key
+func (n Normalizer) Equal(a, b string) bool {
+	return n.Key(a) == n.Key(b)
+}
+
+// Canonical returns a path with forward slashes, cleaned and without a
+// leading "./", keeping its case
+func Canonical(p string) string {
+	if p == "" {
+		return ""
+	}
+	p = path.Clean(strings.ReplaceAll(p, `\`, "/"))
+	return strings.TrimPrefix(p, "./")
+}
+
+// DefaultFoldCase reports whether keys are case-folded by default: the value
+// set with SetDefaultFoldCase, or true on Windows and macOS
+func DefaultFoldCase() bool {
+	switch foldCaseOverride.Load() {
+	case 1:
+		return true
+	case 2:
+		return false
+	}
+	return runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+}
+
+// SetDefaultFoldCase overrides the platform default used by Default
+func SetDefaultFoldCase(fold bool) {
+	if fold {
+		foldCaseOverride.Store(1)
+	} else {
+		foldCaseOverride.Store(2)
+	}
+}
+
+// ParseFoldCase parses a paths.fold_case setting: "auto" (or empty) for the
+// platform default, or a boolean
+func ParseFoldCase(value string) (fold bool, auto bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "auto":
+		return runtime.GOOS == "windows" || runtime.GOOS == "darwin", true, nil
+	case "true", "yes", "on":
+		return true, false, nil
+	case "false", "no", "off":
+		return false, false, nil
+	}
+	return false, false, fmt.Errorf("invalid paths.fold_case %q (use auto, true or false)", value)
+}
@@ -0,0 +1,68 @@
+package pathkey
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"pkg/api/handler.go", "pkg/api/handler.go"},
+		{`pkg\api\handler.go`, "pkg/api/handler.go"},
+		{"./pkg//api/../api/handler.go", "pkg/api/handler.go"},
+		{`.\Services\User.go`, "Services/User.go"},
+		{`C:\repo\main.go`, "C:/repo/main.go"},
+	}
+
+	for _, tt := range tests {
+		if got := Canonical(tt.in); got != tt.want {
+			t.Errorf("Canonical(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizerKey(t *testing.T) {
+	exact := Normalizer{}
+	folded := Normalizer{FoldCase: true}
+
+	if got := exact.Key(`Services\User.go`); got != "Services/User.go" {
+		t.Errorf("exact key = %q", got)
+	}
+	if got := folded.Key(`Services\User.go`); got != "services/user.go" {
+		t.Errorf("folded key = %q", got)
+	}
+	if exact.Equal("Services/User.go", "services/user.go") {
+		t.Error("exact keys should keep case")
+	}
+	if !folded.Equal(`Services\User.go`, "./services/user.go") {
+		t.Error("folded keys should match across case and separators")
+	}
+}
+
+func TestParseFoldCase(t *testing.T) {
+	if _, auto, err := ParseFoldCase(""); err != nil || !auto {
+		t.Errorf("empty = auto %v, err %v", auto, err)
+	}
+	if fold, auto, err := ParseFoldCase("true"); err != nil || auto || !fold {
+		t.Errorf("true = %v, auto %v, err %v", fold, auto, err)
+	}
+	if fold, auto, err := ParseFoldCase("False"); err != nil || auto || fold {
+		t.Errorf("False = %v, auto %v, err %v", fold, auto, err)
+	}
+	if _, _, err := ParseFoldCase("sometimes"); err == nil {
+		t.Error("expected an error for an invalid value")
+	}
+}
+
+func TestSetDefaultFoldCase(t *testing.T) {
+	defer foldCaseOverride.Store(0)
+
+	SetDefaultFoldCase(true)
+	if !Default().FoldCase {
+		t.Error("expected folding after SetDefaultFoldCase(true)")
+	}
+	SetDefaultFoldCase(false)
+	if Default().FoldCase {
+		t.Error("expected no folding after SetDefaultFoldCase(false)")
+	}
+}
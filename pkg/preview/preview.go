@@ -0,0 +1,332 @@
+/*
+# Module: pkg/preview/preview.go
+Live-reload preview of generated docs and the dependency graph.
+
+Regenerates the multi-file docs and an HTML page with the Mermaid dependency
+graph each time the graph is updated, and serves them over HTTP. Markdown
+pages are rendered in the browser. Every page listens on a server-sent events
+stream and reloads when an update lands, so doc authors see metadata edits
+as soon as the watcher rebuilds the graph.
+
+## Linked Modules
+- [../docs](../docs/markdown.go) - Markdown documentation generator
+- [../viz](../viz/mermaid.go) - Mermaid diagram generation
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+preview, docs, viz, live-reload
+
+## Exports
+Server, Options, NewServer, EventsPath
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#preview.go> a code:Module ;
+    code:name "pkg/preview/preview.go" ;
+    code:description "Live-reload preview of generated docs and the dependency graph" ;
+    code:language "go" ;
+    code:layer "preview" ;
+    code:linksTo <../docs/markdown.go>, <../viz/mermaid.go>, <../graph/graph.go> ;
+    code:exports <#Server>, <#Options>, <#NewServer>, <#EventsPath> ;
+    code:tags "preview", "docs", "viz", "live-reload" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/docs"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/viz"
+)
+
+// EventsPath is the server-sent events stream pages reload from
+const EventsPath = "/_preview/events"
+
+// graphPage is the path of the dependency graph page
+const graphPage = "graph.html"
+
+// Options configures the preview
+type Options struct {
+	Docs    docs.DocsOptions   // Docs options; OutputDir and Format are set by the preview
+	Mermaid viz.MermaidOptions // Dependency graph options
+
+	// Components, if set, recomputes the documented components on each update
+	Components func(*graph.Graph) (*analysis.ComponentAnalysis, error)
+}
+
+// Server regenerates and serves the preview
+type Server struct {
+	opts Options
+
+	mu      sync.RWMutex
+	dir     string // Directory holding the current generation
+	title   string
+	version int
+	lastErr error
+
+	clientsMu sync.Mutex
+	clients   map[chan int]struct{}
+}
+
+// NewServer creates a preview server; call Update to generate the first
+// preview and Close to remove the generated files
+func NewServer(opts Options) *Server {
+	if opts.Mermaid.Type == "" {
+		opts.Mermaid.Type = viz.MermaidFlowchart
+	}
+	return &Server{
+		opts:    opts,
+		clients: make(map[chan int]struct{}),
+	}
+}
+
+// Update regenerates the docs and graph page for g and tells open pages to
+// reload. On failure the previous preview keeps being served, with the error.
+func (s *Server) Update(g *graph.Graph) error {
+	dir, err := os.MkdirTemp("", "graphfs-preview-")
+	if err != nil {
+		return fmt.Errorf("failed to create preview directory: %w", err)
+	}
+	if err := s.generate(g, dir); err != nil {
+		os.RemoveAll(dir)
+		s.mu.Lock()
+		s.lastErr = err
+		version := s.version
+		s.mu.Unlock()
+		s.notify(version) // Show the error on open pages
+		return err
+	}
+
+	s.mu.Lock()
+	previous := s.dir
+	s.dir = dir
+	s.version++
+	s.lastErr = nil
+	version := s.version
+	s.mu.Unlock()
+
+	if previous != "" {
+		os.RemoveAll(previous)
+	}
+	s.notify(version)
+	return nil
+}
+
+// generate writes the docs and graph page for g into dir
+func (s *Server) generate(g *graph.Graph, dir string) error {
+	docsOpts := s.opts.Docs
+	docsOpts.OutputDir = dir
+	docsOpts.Format = docs.DocsMultiFile
+	if s.opts.Components != nil {
+		components, err := s.opts.Components(g)
+		if err != nil {
+			return fmt.Errorf("failed to load components: %w", err)
+		}
+		docsOpts.Components = components
+	}
+	if err := docs.GenerateDocs(g, docsOpts); err != nil {
+		return fmt.Errorf("failed to generate docs: %w", err)
+	}
+
+	diagram, err := viz.GenerateMermaid(g, s.opts.Mermaid)
+	if err != nil {
+		return fmt.Errorf("failed to generate graph: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, graphPage), []byte(diagram), 0644); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+
+	title := docsOpts.Title
+	if title == "" {
+		title = filepath.Base(g.Root) + " Documentation"
+	}
+	s.mu.Lock()
+	s.title = title
+	s.mu.Unlock()
+	return nil
+}
+
+// Close stops open event streams and removes the generated files
+func (s *Server) Close() error {
+	s.clientsMu.Lock()
+	for client := range s.clients {
+		close(client)
+		delete(s.clients, client)
+	}
+	s.clientsMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.dir)
+	s.dir = ""
+	return err
+}
+
+// Handler serves the preview pages and the reload event stream
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(EventsPath, s.serveEvents)
+	mux.HandleFunc("/", s.servePage)
+	return mux
+}
+
+// servePage renders a generated markdown page or the graph page
+func (s *Server) servePage(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if name == "" {
+		name = "index.md"
+	}
+	if strings.Contains(name, "/") || (path.Ext(name) != ".md" && name != graphPage) {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	dir, title, version, lastErr := s.dir, s.title, s.version, s.lastErr
+	var content []byte
+	var err error
+	if dir != "" {
+		content, err = os.ReadFile(filepath.Join(dir, name))
+	}
+	s.mu.RUnlock()
+
+	if dir == "" || err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := pageData{Title: title, Version: version, EventsPath: EventsPath, GraphPage: graphPage}
+	if lastErr != nil {
+		page.Error = lastErr.Error()
+	}
+	if name == graphPage {
+		page.Diagram = string(content)
+	} else {
+		// Embedded as a JSON string and rendered by the browser
+		source, _ := json.Marshal(string(content))
+		page.Markdown = template.JS(source)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := pageTemplate.Execute(w, page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// serveEvents streams the preview version after each update
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	client := make(chan int, 1)
+	s.clientsMu.Lock()
+	s.clients[client] = struct{}{}
+	s.clientsMu.Unlock()
+	defer s.removeClient(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case version, open := <-client:
+			if !open {
+				return
+			}
+			fmt.Fprintf(w, "event: reload\ndata: %d\n\n", version)
+			flusher.Flush()
+		}
+	}
+}
+
+// notify sends the new version to every event stream, dropping it for
+// clients that have not read the previous one
+func (s *Server) notify(version int) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	for client := range s.clients {
+		select {
+		case client <- version:
+		default:
+		}
+	}
+}
+
+func (s *Server) removeClient(client chan int) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if _, ok := s.clients[client]; ok {
+		delete(s.clients, client)
+		close(client)
+	}
+}
+
+// pageData fills the page template
+type pageData struct {
+	Title      string
+	Version    int
+	EventsPath string
+	GraphPage  string
+	Error      string
+	Markdown   template.JS // JSON string of the page's markdown
+	Diagram    string      // Mermaid source of the graph page
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 0 auto; padding: 1rem 2rem; line-height: 1.5; }
+nav { border-bottom: 1px solid #ddd; padding-bottom: .5rem; margin-bottom: 1rem; }
+nav a { margin-right: 1rem; }
+pre, code { background: #f6f8fa; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: .25rem .5rem; }
+.error { background: #fee; border: 1px solid #c00; padding: .5rem; }
+</style>
+</head>
+<body>
+<nav><a href="/">Docs</a><a href="/{{.GraphPage}}">Dependency graph</a><small>preview v{{.Version}}</small></nav>
+{{if .Error}}<p class="error">Last update failed, showing the previous preview: {{.Error}}</p>{{end}}
+{{if .Diagram}}<pre class="mermaid">{{.Diagram}}</pre>
+<script type="module">
+import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.esm.min.mjs";
+mermaid.initialize({ startOnLoad: true });
+</script>
+{{else}}<main id="content"></main>
+<script src="https://cdn.jsdelivr.net/npm/marked/marked.min.js"></script>
+<script>document.getElementById("content").innerHTML = marked.parse({{.Markdown}});</script>
+{{end}}
+<script>
+new EventSource("{{.EventsPath}}").addEventListener("reload", () => location.reload());
+</script>
+</body>
+</html>
+`))
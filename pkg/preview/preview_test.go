@@ -0,0 +1,133 @@
+package preview
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func newTestGraph(description string) *graph.Graph {
+	g := graph.NewGraph("/tmp/app", store.NewTripleStore())
+	g.AddModule(&graph.Module{Path: "main.go", Name: "main.go", Description: description, Dependencies: []string{"lib/util.go"}})
+	g.AddModule(&graph.Module{Path: "lib/util.go", Name: "util.go"})
+	return g
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_ServesDocsAndGraph(t *testing.T) {
+	s := NewServer(Options{})
+	defer s.Close()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	if status, _ := get(t, ts.URL+"/"); status != http.StatusNotFound {
+		t.Errorf("Expected 404 before the first update, got %d", status)
+	}
+
+	if err := s.Update(newTestGraph("Entry point")); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	status, index := get(t, ts.URL+"/")
+	if status != http.StatusOK || !strings.Contains(index, "app Documentation") || !strings.Contains(index, "EventSource") {
+		t.Errorf("Unexpected index page (%d):\n%s", status, index)
+	}
+	if _, page := get(t, ts.URL+"/main.md"); !strings.Contains(page, "Entry point") {
+		t.Errorf("Expected module page to embed its markdown:\n%s", page)
+	}
+	if _, page := get(t, ts.URL+"/graph.html"); !strings.Contains(page, `class="mermaid"`) || !strings.Contains(page, "flowchart") {
+		t.Errorf("Expected graph page with a Mermaid diagram:\n%s", page)
+	}
+	for _, path := range []string{"/missing.md", "/../etc/passwd", "/lib/util.md"} {
+		if status, _ := get(t, ts.URL+path); status != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", path, status)
+		}
+	}
+}
+
+func TestServer_NotifiesOnUpdate(t *testing.T) {
+	s := NewServer(Options{})
+	defer s.Close()
+	if err := s.Update(newTestGraph("v1")); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + EventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// Wait for the stream to be registered before updating
+	for i := 0; ; i++ {
+		s.clientsMu.Lock()
+		registered := len(s.clients)
+		s.clientsMu.Unlock()
+		if registered > 0 {
+			break
+		}
+		if i > 100 {
+			t.Fatal("Event stream was not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := s.Update(newTestGraph("v2")); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make(chan string)
+	go func() {
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- strings.TrimSpace(line)
+		}
+	}()
+
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for len(got) < 2 {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("Stream closed early, got %v", got)
+			}
+			if line != "" {
+				got = append(got, line)
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for reload event, got %v", got)
+		}
+	}
+	if got[0] != "event: reload" || got[1] != "data: 2" {
+		t.Errorf("Expected reload event for version 2, got %v", got)
+	}
+
+	if _, page := get(t, ts.URL+"/main.md"); !strings.Contains(page, "v2") {
+		t.Error("Expected the updated page to be served")
+	}
+}
@@ -45,6 +45,7 @@ type Executor struct {
 	store          *store.TripleStore
 	planner        *QueryPlanner
 	enablePlanning bool
+	preprocessor   *Preprocessor
 }
 
 // NewExecutor creates a new query executor with query optimization enabled
@@ -56,6 +57,11 @@ func NewExecutor(tripleStore *store.TripleStore) *Executor {
 	}
 }
 
+// SetPreprocessor sets the prefix/macro preprocessor applied by ExecuteString
+func (e *Executor) SetPreprocessor(p *Preprocessor) {
+	e.preprocessor = p
+}
+
 // DisablePlanning disables query optimization (for testing/benchmarking)
 func (e *Executor) DisablePlanning() {
 	e.enablePlanning = false
@@ -86,6 +92,11 @@ func (e *Executor) Execute(query *Query) (*QueryResult, error) {
 
 // ExecuteString parses and executes a SPARQL query string
 func (e *Executor) ExecuteString(queryStr string) (*QueryResult, error) {
+	queryStr, err := e.preprocessor.Expand(queryStr)
+	if err != nil {
+		return nil, fmt.Errorf("macro error: %w", err)
+	}
+
 	query, err := ParseQuery(queryStr)
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
@@ -110,6 +121,11 @@ func (e *Executor) executeSelect(query *SelectQuery) (*QueryResult, error) {
 		bindings = e.matchPattern(pattern, bindings, optimizedQuery.Prefixes)
 	}
 
+	// Drop bindings that match a FILTER NOT EXISTS group
+	for _, group := range query.NotExists {
+		bindings = e.applyNotExists(group, bindings, query.Prefixes)
+	}
+
 	// Apply filters
 	for _, filter := range query.Filters {
 		bindings = e.applyFilter(filter, bindings)
@@ -168,6 +184,10 @@ func (e *Executor) executeSelect(query *SelectQuery) (*QueryResult, error) {
 
 // matchPattern matches a triple pattern against the store
 func (e *Executor) matchPattern(pattern TriplePattern, currentBindings []map[string]string, prefixes map[string]string) []map[string]string {
+	if pattern.Path != nil {
+		return e.matchPath(pattern, currentBindings, prefixes)
+	}
+
 	var newBindings []map[string]string
 
 	for _, binding := range currentBindings {
@@ -236,6 +256,26 @@ func (e *Executor) resolveValue(value string, binding map[string]string, prefixe
 	return value
 }
 
+// applyNotExists keeps only the bindings for which the group has no match
+func (e *Executor) applyNotExists(group []TriplePattern, bindings []map[string]string, prefixes map[string]string) []map[string]string {
+	var filtered []map[string]string
+
+	for _, binding := range bindings {
+		matches := []map[string]string{binding}
+		for _, pattern := range group {
+			matches = e.matchPattern(pattern, matches, prefixes)
+			if len(matches) == 0 {
+				break
+			}
+		}
+		if len(matches) == 0 {
+			filtered = append(filtered, binding)
+		}
+	}
+
+	return filtered
+}
+
 // applyFilter applies a FILTER clause to bindings
 func (e *Executor) applyFilter(filter Filter, bindings []map[string]string) []map[string]string {
 	var filtered []map[string]string
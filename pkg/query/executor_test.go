@@ -182,6 +182,35 @@ func TestExecutor_WithFilter_REGEX(t *testing.T) {
 	}
 }
 
+func TestExecutor_WithFilterNotExists(t *testing.T) {
+	ts := setupTestStore()
+	executor := NewExecutor(ts)
+
+	queryStr := `
+		PREFIX code: <https://schema.codedoc.org/>
+		SELECT ?module ?name WHERE {
+			?module code:name ?name .
+			FILTER NOT EXISTS { ?module code:linksTo ?target }
+		}
+	`
+
+	result, err := executor.ExecuteString(queryStr)
+	if err != nil {
+		t.Fatalf("ExecuteString() error = %v", err)
+	}
+
+	if result.Count != 1 {
+		t.Fatalf("Count = %d, want 1 (filtered by NOT EXISTS)", result.Count)
+	}
+
+	if result.Bindings[0]["name"] != "utils.go" {
+		t.Errorf("name = %v, want utils.go", result.Bindings[0]["name"])
+	}
+	if _, ok := result.Bindings[0]["target"]; ok {
+		t.Errorf("NOT EXISTS variable leaked into bindings: %v", result.Bindings[0])
+	}
+}
+
 func TestExecutor_SpecificSubject(t *testing.T) {
 	ts := setupTestStore()
 	executor := NewExecutor(ts)
@@ -7,9 +7,11 @@ package query
 import (
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/parser"
 	"github.com/justin4957/graphfs/pkg/scanner"
 )
@@ -72,6 +74,116 @@ func setupMinimalAppStore(t *testing.T) *store.TripleStore {
 	return ts
 }
 
+// buildMinimalAppGraph builds the minimal-app graph the way graphfs scan
+// does, with dependency edge nodes
+func buildMinimalAppGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	absPath, err := filepath.Abs("../../examples/minimal-app")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	g, err := graph.NewBuilder().Build(absPath, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build minimal-app: %v", err)
+	}
+	return g
+}
+
+func TestIntegration_DependencyEdgeTargets(t *testing.T) {
+	g := buildMinimalAppGraph(t)
+	executor := NewExecutor(g.Store)
+
+	result, err := executor.ExecuteString(`
+		PREFIX code: <https://schema.codedoc.org/>
+		SELECT ?source ?target WHERE {
+			?m code:dependencyEdge ?e .
+			?e code:edgeTarget ?t .
+			?m code:name ?source .
+			?t code:name ?target .
+		}
+	`)
+	if err != nil {
+		t.Fatalf("ExecuteString() error = %v", err)
+	}
+
+	edges := 0
+	for _, module := range g.Modules {
+		for _, dep := range module.Dependencies {
+			if g.GetModule(dep) != nil {
+				edges++
+			}
+		}
+	}
+	if edges == 0 || result.Count != edges {
+		t.Fatalf("Expected %d edges joined to their target modules, got %d", edges, result.Count)
+	}
+	found := false
+	for _, binding := range result.Bindings {
+		if binding["source"] == "services/auth.go" && binding["target"] == "utils/crypto.go" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Missing edge services/auth.go -> utils/crypto.go in %v", result.Bindings)
+	}
+}
+
+func TestIntegration_TransitiveDependencies(t *testing.T) {
+	g := buildMinimalAppGraph(t)
+	executor := NewExecutor(g.Store)
+
+	// The transitive dependency example in pkg/query/README.md
+	result, err := executor.ExecuteString(`
+		PREFIX code: <https://schema.codedoc.org/>
+		SELECT ?dependency WHERE {
+			<#main.go> (code:dependencyEdge/code:edgeTarget)+ ?dependency .
+		}
+	`)
+	if err != nil {
+		t.Fatalf("ExecuteString() error = %v", err)
+	}
+	paths := make(map[string]string)
+	for _, module := range g.Modules {
+		paths[module.URI] = module.Path
+	}
+	reached := make(map[string]bool)
+	for _, binding := range result.Bindings {
+		reached[paths[binding["dependency"]]] = true
+	}
+	for _, path := range []string{"services/auth.go", "utils/crypto.go", "utils/validator.go"} {
+		if !reached[path] {
+			t.Errorf("Expected main.go to reach %s, got %v", path, result.Bindings)
+		}
+	}
+
+	// The impact analysis example in examples/minimal-app/README.md
+	result, err = executor.ExecuteString(`
+		PREFIX code: <https://schema.codedoc.org/>
+		PREFIX rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#>
+		SELECT ?module ?name
+		WHERE {
+		  ?module rdf:type code:Module ;
+		          code:name ?name ;
+		          (code:dependencyEdge/code:edgeTarget)+ ?target .
+		  ?target code:name "utils/crypto.go" .
+		}
+	`)
+	if err != nil {
+		t.Fatalf("ExecuteString() error = %v", err)
+	}
+	dependents := make(map[string]bool)
+	for _, binding := range result.Bindings {
+		dependents[binding["name"]] = true
+	}
+	for _, name := range []string{"main.go", "services/auth.go"} {
+		if !dependents[name] {
+			t.Errorf("Expected %s to depend on utils/crypto.go, got %v", name, result.Bindings)
+		}
+	}
+}
+
 func TestIntegration_FindAllModules(t *testing.T) {
 	ts := setupMinimalAppStore(t)
 	executor := NewExecutor(ts)
@@ -252,3 +364,41 @@ func TestIntegration_ComplexQuery(t *testing.T) {
 		t.Logf("  %s (%s)", binding["name"], binding["module"])
 	}
 }
+
+func TestIntegration_TransitiveTemplates(t *testing.T) {
+	g := buildMinimalAppGraph(t)
+	executor := NewExecutor(g.Store)
+	tm := NewTemplateManager("")
+
+	tests := []struct {
+		template string
+		module   string
+		variable string
+		want     []string
+	}{
+		// main.go -> services -> models/utils, three levels deep
+		{"dependency-tree", "main.go", "dep", []string{
+			"models/user.go", "services/auth.go", "services/user.go",
+			"utils/crypto.go", "utils/logger.go", "utils/validator.go",
+		}},
+		// crypto.go <- services/auth.go <- services/user.go <- main.go
+		{"change-impact", "utils/crypto.go", "affected", []string{
+			"main.go", "services/auth.go", "services/user.go",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			rendered, err := tm.RenderTemplate(tt.template, map[string]string{"module": tt.module})
+			if err != nil {
+				t.Fatalf("RenderTemplate() error = %v", err)
+			}
+			result, err := executor.ExecuteString(rendered)
+			if err != nil {
+				t.Fatalf("ExecuteString() error = %v", err)
+			}
+			if got := column(result, tt.variable); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s %s = %v, want %v", tt.template, tt.module, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+/*
+# Module: pkg/query/macros.go
+Shared prefixes and query macros.
+
+Loads project-wide PREFIX declarations from .graphfs/prefixes.ttl and named
+pattern fragments (macros) from .graphfs/macros.yaml, and expands them in
+query strings so queries, templates and rules do not repeat boilerplate.
+
+Macros are invoked as @name(arg, ...) inside a query. Parameters are
+referenced in the macro pattern as $param.
+
+## Linked Modules
+- [parser](./parser.go) - SPARQL parser
+- [executor](./executor.go) - Query executor
+
+## Tags
+query, macros, prefixes, sparql
+
+## Exports
+Macro, Preprocessor, NewPreprocessor, LoadPreprocessor, ParsePrefixes, PrefixesFile, MacrosFile
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#macros.go> a code:Module ;
+    code:name "pkg/query/macros.go" ;
+    code:description "Shared prefixes and query macros" ;
+    code:language "go" ;
+    code:layer "query" ;
+    code:linksTo <./parser.go>, <./executor.go> ;
+    code:exports <#Macro>, <#Preprocessor>, <#NewPreprocessor>, <#LoadPreprocessor>,
+                 <#ParsePrefixes>, <#PrefixesFile>, <#MacrosFile> ;
+    code:tags "query", "macros", "prefixes", "sparql" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package query
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PrefixesFile is the shared prefixes file inside the .graphfs directory
+	PrefixesFile = "prefixes.ttl"
+
+	// MacrosFile is the query macros file inside the .graphfs directory
+	MacrosFile = "macros.yaml"
+
+	// maxMacroDepth bounds nested macro expansion
+	maxMacroDepth = 10
+)
+
+var (
+	// prefixDeclRegex matches Turtle (@prefix) and SPARQL (PREFIX) declarations
+	prefixDeclRegex = regexp.MustCompile(`(?i)@?PREFIX\s+([\w-]*):\s*<([^>]+)>`)
+
+	// macroCallRegex matches @name or @name(args)
+	macroCallRegex = regexp.MustCompile(`@([A-Za-z_][\w-]*)(\(([^()]*)\))?`)
+)
+
+// Macro is a named, parameterized graph pattern fragment
+type Macro struct {
+	Name        string   `yaml:"-" json:"name"`
+	Description string   `yaml:"description" json:"description,omitempty"`
+	Params      []string `yaml:"params" json:"params,omitempty"`
+	Pattern     string   `yaml:"pattern" json:"pattern"`
+}
+
+// macrosFile is the on-disk format of macros.yaml
+type macrosFile struct {
+	Macros map[string]*Macro `yaml:"macros"`
+}
+
+// Preprocessor prepends shared prefixes and expands macros in query strings
+type Preprocessor struct {
+	Prefixes map[string]string
+	Macros   map[string]*Macro
+}
+
+// NewPreprocessor creates an empty preprocessor
+func NewPreprocessor() *Preprocessor {
+	return &Preprocessor{
+		Prefixes: make(map[string]string),
+		Macros:   make(map[string]*Macro),
+	}
+}
+
+// LoadPreprocessor loads prefixes.ttl and macros.yaml from a .graphfs
+// directory. Missing files are not an error.
+func LoadPreprocessor(graphfsDir string) (*Preprocessor, error) {
+	p := NewPreprocessor()
+
+	data, err := os.ReadFile(filepath.Join(graphfsDir, PrefixesFile))
+	if err == nil {
+		p.Prefixes = ParsePrefixes(string(data))
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", PrefixesFile, err)
+	}
+
+	data, err = os.ReadFile(filepath.Join(graphfsDir, MacrosFile))
+	if err == nil {
+		var file macrosFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", MacrosFile, err)
+		}
+		for name, macro := range file.Macros {
+			if macro == nil {
+				continue
+			}
+			macro.Name = name
+			p.Macros[name] = macro
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", MacrosFile, err)
+	}
+
+	return p, nil
+}
+
+// ParsePrefixes extracts prefix declarations from Turtle or SPARQL text
+func ParsePrefixes(content string) map[string]string {
+	prefixes := make(map[string]string)
+	for _, match := range prefixDeclRegex.FindAllStringSubmatch(content, -1) {
+		prefixes[match[1]] = match[2]
+	}
+	return prefixes
+}
+
+// Expand expands macros and prepends shared prefixes not already declared
+// by the query itself. A nil preprocessor returns the query unchanged.
+func (p *Preprocessor) Expand(queryStr string) (string, error) {
+	if p == nil {
+		return queryStr, nil
+	}
+
+	expanded, err := p.expandMacros(queryStr)
+	if err != nil {
+		return "", err
+	}
+
+	return p.prependPrefixes(expanded), nil
+}
+
+// expandMacros replaces macro calls until none remain
+func (p *Preprocessor) expandMacros(queryStr string) (string, error) {
+	if len(p.Macros) == 0 {
+		return queryStr, nil
+	}
+
+	for depth := 0; depth < maxMacroDepth; depth++ {
+		var expandErr error
+		changed := false
+
+		queryStr = macroCallRegex.ReplaceAllStringFunc(queryStr, func(call string) string {
+			match := macroCallRegex.FindStringSubmatch(call)
+			macro, ok := p.Macros[match[1]]
+			if !ok {
+				// Not a macro (e.g. a language tag); leave untouched
+				return call
+			}
+
+			var args []string
+			if match[2] != "" && strings.TrimSpace(match[3]) != "" {
+				for _, arg := range strings.Split(match[3], ",") {
+					args = append(args, strings.TrimSpace(arg))
+				}
+			}
+			if len(args) != len(macro.Params) {
+				if expandErr == nil {
+					expandErr = fmt.Errorf("macro @%s expects %d arguments, got %d", macro.Name, len(macro.Params), len(args))
+				}
+				return call
+			}
+
+			changed = true
+			return macro.expand(args)
+		})
+
+		if expandErr != nil {
+			return "", expandErr
+		}
+		if !changed {
+			return queryStr, nil
+		}
+	}
+
+	return "", fmt.Errorf("macro expansion exceeded depth %d (recursive macro?)", maxMacroDepth)
+}
+
+// expand substitutes arguments into the macro pattern. Longer parameter
+// names are substituted first so $module is not clobbered by $mod.
+func (m *Macro) expand(args []string) string {
+	order := make([]int, len(m.Params))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return len(m.Params[order[a]]) > len(m.Params[order[b]])
+	})
+
+	result := m.Pattern
+	for _, i := range order {
+		result = strings.ReplaceAll(result, "$"+m.Params[i], args[i])
+	}
+	return strings.TrimSpace(result)
+}
+
+// prependPrefixes adds PREFIX lines for shared prefixes the query does not declare
+func (p *Preprocessor) prependPrefixes(queryStr string) string {
+	if len(p.Prefixes) == 0 {
+		return queryStr
+	}
+
+	declared := ParsePrefixes(queryStr)
+
+	names := make([]string, 0, len(p.Prefixes))
+	for name := range p.Prefixes {
+		// The query parser only understands named prefixes
+		if name == "" {
+			continue
+		}
+		if _, ok := declared[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return queryStr
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "PREFIX %s: <%s>\n", name, p.Prefixes[name])
+	}
+	b.WriteString(queryStr)
+	return b.String()
+}
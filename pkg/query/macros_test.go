@@ -0,0 +1,167 @@
+/*
+# Module: pkg/query/macros_test.go
+Tests for shared prefixes and query macros.
+
+## Linked Modules
+- [macros](./macros.go) - Query macros
+
+## Tags
+query, macros, test
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#macros_test.go> a code:Module ;
+    code:name "pkg/query/macros_test.go" ;
+    code:description "Tests for shared prefixes and query macros" ;
+    code:language "go" ;
+    code:layer "query" ;
+    code:linksTo <./macros.go> ;
+    code:tags "query", "macros", "test" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+func writeMacroFiles(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	prefixes := `@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+`
+	macros := `macros:
+  inLayer:
+    description: Module in a given layer
+    params: [module, layer]
+    pattern: "$module code:layer $layer ."
+  serviceModule:
+    params: [m]
+    pattern: "$m rdf:type code:Module . @inLayer($m, \"services\")"
+`
+	if err := os.WriteFile(filepath.Join(dir, PrefixesFile), []byte(prefixes), 0644); err != nil {
+		t.Fatalf("Failed to write prefixes: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, MacrosFile), []byte(macros), 0644); err != nil {
+		t.Fatalf("Failed to write macros: %v", err)
+	}
+	return dir
+}
+
+func TestLoadPreprocessor(t *testing.T) {
+	p, err := LoadPreprocessor(writeMacroFiles(t))
+	if err != nil {
+		t.Fatalf("LoadPreprocessor failed: %v", err)
+	}
+
+	if p.Prefixes["code"] != "https://schema.codedoc.org/" {
+		t.Errorf("Expected code prefix, got %q", p.Prefixes["code"])
+	}
+	if m, ok := p.Macros["inLayer"]; !ok || m.Name != "inLayer" || len(m.Params) != 2 {
+		t.Errorf("Expected inLayer macro with 2 params, got %+v", m)
+	}
+
+	// Missing files are not an error
+	if _, err := LoadPreprocessor(t.TempDir()); err != nil {
+		t.Errorf("Expected no error for missing files, got %v", err)
+	}
+}
+
+func TestPreprocessorExpand(t *testing.T) {
+	p, err := LoadPreprocessor(writeMacroFiles(t))
+	if err != nil {
+		t.Fatalf("LoadPreprocessor failed: %v", err)
+	}
+
+	expanded, err := p.Expand(`PREFIX code: <http://override/>
+SELECT ?m WHERE { @serviceModule(?m) }`)
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+
+	if !strings.Contains(expanded, `?m rdf:type code:Module . ?m code:layer "services" .`) {
+		t.Errorf("Expected nested macros to be expanded, got:\n%s", expanded)
+	}
+	if !strings.HasPrefix(expanded, "PREFIX rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#>\n") {
+		t.Errorf("Expected rdf prefix to be prepended, got:\n%s", expanded)
+	}
+	if strings.Contains(expanded, "PREFIX code: <https://schema.codedoc.org/>") {
+		t.Error("Expected query-declared prefix to take precedence")
+	}
+
+	if _, err := p.Expand(`SELECT ?m WHERE { @inLayer(?m) }`); err == nil {
+		t.Error("Expected error for wrong argument count")
+	}
+
+	// Unknown @names are left alone
+	unchanged, err := p.Expand(`SELECT ?m WHERE { ?m code:author "dev@example" }`)
+	if err != nil || !strings.Contains(unchanged, "dev@example") {
+		t.Errorf("Expected unknown @name to be left untouched, got %q (%v)", unchanged, err)
+	}
+}
+
+func TestPreprocessorRecursiveMacro(t *testing.T) {
+	p := NewPreprocessor()
+	p.Macros["loop"] = &Macro{Name: "loop", Pattern: "@loop"}
+
+	if _, err := p.Expand(`SELECT ?m WHERE { @loop }`); err == nil {
+		t.Error("Expected error for recursive macro")
+	}
+}
+
+func TestExecutorWithPreprocessor(t *testing.T) {
+	ts := store.NewTripleStore()
+	ts.Add("<#auth.go>", "http://www.w3.org/1999/02/22-rdf-syntax-ns#type", "https://schema.codedoc.org/Module")
+	ts.Add("<#auth.go>", "https://schema.codedoc.org/layer", "services")
+	ts.Add("<#main.go>", "http://www.w3.org/1999/02/22-rdf-syntax-ns#type", "https://schema.codedoc.org/Module")
+	ts.Add("<#main.go>", "https://schema.codedoc.org/layer", "cmd")
+
+	p, err := LoadPreprocessor(writeMacroFiles(t))
+	if err != nil {
+		t.Fatalf("LoadPreprocessor failed: %v", err)
+	}
+
+	executor := NewExecutor(ts)
+	executor.SetPreprocessor(p)
+
+	result, err := executor.ExecuteString(`SELECT ?m WHERE { @serviceModule(?m) }`)
+	if err != nil {
+		t.Fatalf("ExecuteString failed: %v", err)
+	}
+	if result.Count != 1 || result.Bindings[0]["m"] != "<#auth.go>" {
+		t.Errorf("Expected only <#auth.go>, got %v", result.Bindings)
+	}
+}
+
+func TestTemplateManagerWithPreprocessor(t *testing.T) {
+	p, err := LoadPreprocessor(writeMacroFiles(t))
+	if err != nil {
+		t.Fatalf("LoadPreprocessor failed: %v", err)
+	}
+
+	tm := NewTemplateManager("")
+	tm.SetPreprocessor(p)
+
+	rendered, err := tm.Render(&QueryTemplate{
+		Name:      "layer",
+		Query:     `SELECT ?m WHERE { @inLayer(?m, "{{.layer}}") }`,
+		Variables: []Variable{{Name: "layer"}},
+	}, map[string]string{"layer": "services"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if !strings.Contains(rendered, `?m code:layer "services" .`) || !strings.Contains(rendered, "PREFIX code:") {
+		t.Errorf("Expected macros and prefixes in rendered template, got:\n%s", rendered)
+	}
+}
@@ -0,0 +1,313 @@
+/*
+# Module: pkg/query/packs.go
+Template packs fetched from remote sources.
+
+Downloads community or org-internal query template packs, verifies their
+SHA-256 checksums and installs them under .graphfs/templates/packs so the
+template manager loads them alongside built-in and custom templates.
+
+## Linked Modules
+- [templates](./templates.go) - Query templates
+
+## Tags
+query, templates, packs, download
+
+## Exports
+TemplatePack, InstalledPack, PackIndex, PackFetcher, ResolvePackSource, InstallPack, LoadPackIndex
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#packs.go> a code:Module ;
+    code:name "pkg/query/packs.go" ;
+    code:description "Template packs fetched from remote sources" ;
+    code:language "go" ;
+    code:layer "query" ;
+    code:linksTo <./templates.go> ;
+    code:exports <#TemplatePack>, <#InstalledPack>, <#PackIndex>, <#PackFetcher>, <#ResolvePackSource>, <#InstallPack>, <#LoadPackIndex> ;
+    code:tags "query", "templates", "packs", "download" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package query
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// PacksDir is the directory under the templates directory holding installed packs
+	PacksDir = "packs"
+
+	// PackIndexFile records where each installed pack came from
+	PackIndexFile = "packs.json"
+
+	// PackFileName is the pack file looked up in a GitHub repository
+	PackFileName = "graphfs-templates.json"
+
+	maxPackSize = 10 << 20
+)
+
+// Checksum verification modes recorded for installed packs
+const (
+	VerifyPublished = "published" // checked against the <url>.sha256 file
+	VerifyPinned    = "pinned"    // checked against a checksum given by the user
+	VerifyNone      = "none"      // not checked
+)
+
+var packNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var repoSourcePattern = regexp.MustCompile(`^([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)(?:@([A-Za-z0-9_./-]+))?$`)
+
+// TemplatePack is a named collection of query templates
+type TemplatePack struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Templates   []QueryTemplate `json:"templates"`
+}
+
+// InstalledPack records the source and checksum of an installed pack
+type InstalledPack struct {
+	Name      string    `json:"name"`
+	Version   string    `json:"version,omitempty"`
+	Source    string    `json:"source"`
+	URL       string    `json:"url"`
+	SHA256    string    `json:"sha256"`
+	Verify    string    `json:"verify"`
+	Templates []string  `json:"templates"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// PackIndex lists installed packs by name
+type PackIndex struct {
+	Packs map[string]*InstalledPack `json:"packs"`
+}
+
+// ResolvePackSource returns the download URL for a pack source. Sources are
+// http(s) URLs or GitHub repositories written as org/repo or org/repo@ref,
+// which resolve to the graphfs-templates.json file at the repository root.
+func ResolvePackSource(source string) (string, error) {
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		return source, nil
+	}
+
+	match := repoSourcePattern.FindStringSubmatch(source)
+	if match == nil {
+		return "", fmt.Errorf("invalid pack source %q: expected a URL or org/repo[@ref]", source)
+	}
+	ref := match[3]
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", match[1], match[2], ref, PackFileName), nil
+}
+
+// PackFetcher downloads and verifies template packs
+type PackFetcher struct {
+	Client *http.Client
+}
+
+// NewPackFetcher creates a pack fetcher with a request timeout
+func NewPackFetcher(timeout time.Duration) *PackFetcher {
+	return &PackFetcher{Client: &http.Client{Timeout: timeout}}
+}
+
+// Fetch downloads a pack and verifies its SHA-256 checksum. A non-empty
+// checksum pins the expected value; otherwise the checksum published at
+// <url>.sha256 is used. Packs without a checksum are rejected unless
+// allowUnverified is set. Fetch returns the pack, its checksum and the
+// verification mode used.
+func (f *PackFetcher) Fetch(url, checksum string, allowUnverified bool) (*TemplatePack, string, string, error) {
+	data, err := f.get(url)
+	if err != nil {
+		return nil, "", "", err
+	}
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+
+	verify := VerifyPinned
+	expected := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	if expected == "" {
+		published, err := f.get(url + ".sha256")
+		if err == nil {
+			// sha256sum format: "<hex>  <file>"
+			if fields := strings.Fields(string(published)); len(fields) > 0 {
+				expected = strings.ToLower(fields[0])
+				verify = VerifyPublished
+			}
+		}
+	}
+
+	switch {
+	case expected == "" && !allowUnverified:
+		return nil, "", "", fmt.Errorf("no checksum published at %s.sha256: pass a checksum or allow unverified packs", url)
+	case expected == "":
+		verify = VerifyNone
+	case expected != actual:
+		return nil, "", "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", url, expected, actual)
+	}
+
+	var pack TemplatePack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, "", "", fmt.Errorf("failed to parse template pack: %w", err)
+	}
+	if err := pack.validate(); err != nil {
+		return nil, "", "", err
+	}
+
+	return &pack, actual, verify, nil
+}
+
+// get downloads a URL, rejecting error statuses and oversized bodies
+func (f *PackFetcher) get(url string) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPackSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if len(data) > maxPackSize {
+		return nil, fmt.Errorf("%s exceeds the %d byte pack size limit", url, maxPackSize)
+	}
+	return data, nil
+}
+
+// validate checks that the pack and template names are safe file names
+func (p *TemplatePack) validate() error {
+	if !packNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid pack name %q", p.Name)
+	}
+	if len(p.Templates) == 0 {
+		return fmt.Errorf("pack %s contains no templates", p.Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, tmpl := range p.Templates {
+		if !packNamePattern.MatchString(tmpl.Name) {
+			return fmt.Errorf("pack %s: invalid template name %q", p.Name, tmpl.Name)
+		}
+		if tmpl.Query == "" {
+			return fmt.Errorf("pack %s: template %s has no query", p.Name, tmpl.Name)
+		}
+		if seen[tmpl.Name] {
+			return fmt.Errorf("pack %s: duplicate template %s", p.Name, tmpl.Name)
+		}
+		seen[tmpl.Name] = true
+	}
+	return nil
+}
+
+// LoadPackIndex reads the installed pack index from a templates directory
+func LoadPackIndex(templatesDir string) (*PackIndex, error) {
+	index := &PackIndex{Packs: make(map[string]*InstalledPack)}
+
+	data, err := os.ReadFile(filepath.Join(templatesDir, PackIndexFile))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack index: %w", err)
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse pack index: %w", err)
+	}
+	if index.Packs == nil {
+		index.Packs = make(map[string]*InstalledPack)
+	}
+	return index, nil
+}
+
+// Save writes the pack index to a templates directory
+func (idx *PackIndex) Save(templatesDir string) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pack index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, PackIndexFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write pack index: %w", err)
+	}
+	return nil
+}
+
+// Names returns the installed pack names in sorted order
+func (idx *PackIndex) Names() []string {
+	names := make([]string, 0, len(idx.Packs))
+	for name := range idx.Packs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InstallPack writes a pack's templates to <templatesDir>/packs/<name>,
+// replacing any previous version, and records it in the pack index
+func InstallPack(templatesDir string, pack *TemplatePack, record InstalledPack) error {
+	if err := pack.validate(); err != nil {
+		return err
+	}
+
+	packDir := filepath.Join(templatesDir, PacksDir, pack.Name)
+	staging := packDir + ".tmp"
+	if err := os.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to clear staging directory: %w", err)
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return fmt.Errorf("failed to create pack directory: %w", err)
+	}
+
+	record.Name = pack.Name
+	record.Version = pack.Version
+	record.Templates = nil
+	for i := range pack.Templates {
+		tmpl := &pack.Templates[i]
+		data, err := json.MarshalIndent(tmpl, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal template: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(staging, tmpl.Name+".json"), data, 0644); err != nil {
+			return fmt.Errorf("failed to write template: %w", err)
+		}
+		record.Templates = append(record.Templates, tmpl.Name)
+	}
+	sort.Strings(record.Templates)
+
+	if err := os.RemoveAll(packDir); err != nil {
+		return fmt.Errorf("failed to remove previous pack: %w", err)
+	}
+	if err := os.Rename(staging, packDir); err != nil {
+		return fmt.Errorf("failed to install pack: %w", err)
+	}
+
+	index, err := LoadPackIndex(templatesDir)
+	if err != nil {
+		return err
+	}
+	index.Packs[pack.Name] = &record
+	return index.Save(templatesDir)
+}
@@ -0,0 +1,176 @@
+/*
+# Module: pkg/query/packs_test.go
+Tests for fetched template packs.
+
+Tests source resolution, checksum verification and pack installation.
+
+## Linked Modules
+- [packs](./packs.go) - Template packs
+
+## Tags
+query, templates, packs, test
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#packs_test.go> a code:Module ;
+    code:name "pkg/query/packs_test.go" ;
+    code:description "Tests for fetched template packs" ;
+    code:language "go" ;
+    code:layer "query" ;
+    code:linksTo <./packs.go> ;
+    code:tags "query", "templates", "packs", "test" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package query
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPack = `{
+  "name": "org-rules",
+  "version": "1.0.0",
+  "templates": [
+    {"name": "find-owners", "description": "Find owners", "category": "ownership", "query": "SELECT ?m WHERE { ?m <#owner> ?o }"}
+  ]
+}`
+
+func testPackServer(t *testing.T, body string, published string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pack.json":
+			w.Write([]byte(body))
+		case "/pack.json.sha256":
+			if published == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(published + "  pack.json\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func packChecksum(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestResolvePackSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"https://example.com/pack.json", "https://example.com/pack.json"},
+		{"acme/queries", "https://raw.githubusercontent.com/acme/queries/HEAD/graphfs-templates.json"},
+		{"acme/queries@v1.2", "https://raw.githubusercontent.com/acme/queries/v1.2/graphfs-templates.json"},
+	}
+	for _, tt := range tests {
+		got, err := ResolvePackSource(tt.source)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolvePackSource(%q) = %q, %v; want %q", tt.source, got, err, tt.want)
+		}
+	}
+
+	if _, err := ResolvePackSource("not a source"); err == nil {
+		t.Error("Expected error for invalid source")
+	}
+}
+
+func TestPackFetcherVerify(t *testing.T) {
+	fetcher := NewPackFetcher(5 * time.Second)
+	sum := packChecksum(testPack)
+
+	published := testPackServer(t, testPack, sum)
+	pack, got, verify, err := fetcher.Fetch(published.URL+"/pack.json", "", false)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if pack.Name != "org-rules" || got != sum || verify != VerifyPublished {
+		t.Errorf("Fetch = %s, %s, %s; want org-rules, %s, %s", pack.Name, got, verify, sum, VerifyPublished)
+	}
+
+	tampered := testPackServer(t, testPack, strings.Repeat("0", 64))
+	if _, _, _, err := fetcher.Fetch(tampered.URL+"/pack.json", "", false); err == nil {
+		t.Error("Expected checksum mismatch error")
+	}
+
+	unpublished := testPackServer(t, testPack, "")
+	if _, _, _, err := fetcher.Fetch(unpublished.URL+"/pack.json", "", false); err == nil {
+		t.Error("Expected error for pack without checksum")
+	}
+	if _, _, verify, err := fetcher.Fetch(unpublished.URL+"/pack.json", "sha256:"+sum, false); err != nil || verify != VerifyPinned {
+		t.Errorf("Pinned fetch = %s, %v; want %s", verify, err, VerifyPinned)
+	}
+	if _, _, verify, err := fetcher.Fetch(unpublished.URL+"/pack.json", "", true); err != nil || verify != VerifyNone {
+		t.Errorf("Unverified fetch = %s, %v; want %s", verify, err, VerifyNone)
+	}
+
+	unsafe := `{"name": "../escape", "templates": [{"name": "x", "query": "SELECT"}]}`
+	unsafeServer := testPackServer(t, unsafe, packChecksum(unsafe))
+	if _, _, _, err := fetcher.Fetch(unsafeServer.URL+"/pack.json", "", false); err == nil {
+		t.Error("Expected error for unsafe pack name")
+	}
+}
+
+func TestInstallPack(t *testing.T) {
+	templatesDir := t.TempDir()
+
+	pack := &TemplatePack{
+		Name: "org-rules",
+		Templates: []QueryTemplate{
+			{Name: "find-owners", Category: "ownership", Query: "SELECT ?m WHERE { ?m <#owner> ?o }"},
+			{Name: "find-dependencies", Category: "custom", Query: "SELECT ?x WHERE { ?x ?p ?o }"},
+		},
+	}
+	if err := InstallPack(templatesDir, pack, InstalledPack{Source: "acme/queries"}); err != nil {
+		t.Fatalf("InstallPack failed: %v", err)
+	}
+
+	// A custom template overrides the pack template of the same name
+	custom := `{"name": "find-owners", "category": "local", "query": "SELECT ?local WHERE { ?local ?p ?o }"}`
+	if err := os.WriteFile(filepath.Join(templatesDir, "find-owners.json"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := NewTemplateManager(templatesDir)
+	if tmpl, err := tm.GetTemplate("find-owners"); err != nil || tmpl.Category != "local" {
+		t.Errorf("Expected custom find-owners to override the pack, got %+v, %v", tmpl, err)
+	}
+	if tmpl, err := tm.GetTemplate("find-dependencies"); err != nil || tmpl.Category != "custom" {
+		t.Errorf("Expected pack find-dependencies to override the built-in, got %+v, %v", tmpl, err)
+	}
+
+	// Reinstalling drops templates removed from the pack
+	pack.Templates = pack.Templates[:1]
+	if err := InstallPack(templatesDir, pack, InstalledPack{Source: "acme/queries"}); err != nil {
+		t.Fatalf("InstallPack failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(templatesDir, PacksDir, "org-rules", "find-dependencies.json")); !os.IsNotExist(err) {
+		t.Error("Expected removed template to be deleted on reinstall")
+	}
+
+	index, err := LoadPackIndex(templatesDir)
+	if err != nil {
+		t.Fatalf("LoadPackIndex failed: %v", err)
+	}
+	record := index.Packs["org-rules"]
+	if record == nil || record.Source != "acme/queries" || len(record.Templates) != 1 {
+		t.Errorf("Unexpected pack index entry: %+v", record)
+	}
+}
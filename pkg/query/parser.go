@@ -2,7 +2,8 @@
 # Module: pkg/query/parser.go
 SPARQL query parser.
 
-Parses SPARQL SELECT queries with WHERE, FILTER, ORDER BY, LIMIT, and OFFSET.
+Parses SPARQL SELECT queries with WHERE, FILTER, FILTER NOT EXISTS, ORDER BY,
+LIMIT, and OFFSET.
 
 ## Linked Modules
 - [query](./query.go) - Query data structures
@@ -99,14 +100,21 @@ func parseSelectQuery(queryStr string) (*SelectQuery, error) {
 		query.Variables = varMatches
 	}
 
-	// Extract WHERE clause
-	whereRegex := regexp.MustCompile(`(?i)WHERE\s*\{([\s\S]*?)\}`)
-	whereMatch := whereRegex.FindStringSubmatch(queryStr)
-	if whereMatch == nil {
+	// Extract WHERE clause - must handle nested braces
+	whereClause, ok := extractWhereClause(queryStr)
+	if !ok {
 		return nil, fmt.Errorf("invalid WHERE clause")
 	}
 
-	whereClause := whereMatch[1]
+	// Extract FILTER NOT EXISTS groups before the triple patterns are split
+	whereClause, notExists := extractNotExists(whereClause)
+	for _, group := range notExists {
+		patterns, err := parseTriplePatterns(group, query.Prefixes)
+		if err != nil {
+			return nil, err
+		}
+		query.NotExists = append(query.NotExists, patterns)
+	}
 
 	// Parse triple patterns
 	patterns, err := parseTriplePatterns(whereClause, query.Prefixes)
@@ -148,6 +156,61 @@ func parseSelectQuery(queryStr string) (*SelectQuery, error) {
 	return query, nil
 }
 
+// extractWhereClause returns the body of the WHERE clause with balanced braces
+func extractWhereClause(queryStr string) (string, bool) {
+	loc := regexp.MustCompile(`(?i)WHERE\s*\{`).FindStringIndex(queryStr)
+	if loc == nil {
+		return "", false
+	}
+	end, ok := matchingBrace(queryStr, loc[1])
+	if !ok {
+		return "", false
+	}
+	return queryStr[loc[1]:end], true
+}
+
+// extractNotExists removes FILTER NOT EXISTS { ... } groups from a WHERE
+// clause and returns the remaining clause along with each group's body
+func extractNotExists(whereClause string) (string, []string) {
+	notExistsKeyword := regexp.MustCompile(`(?i)\bFILTER\s+NOT\s+EXISTS\s*\{`)
+
+	var groups []string
+	for {
+		loc := notExistsKeyword.FindStringIndex(whereClause)
+		if loc == nil {
+			return whereClause, groups
+		}
+		end, ok := matchingBrace(whereClause, loc[1])
+		if !ok {
+			return whereClause, groups
+		}
+		groups = append(groups, whereClause[loc[1]:end])
+		whereClause = whereClause[:loc[0]] + whereClause[end+1:]
+	}
+}
+
+// matchingBrace returns the index of the '}' closing the brace opened just
+// before start, ignoring braces inside literals
+func matchingBrace(s string, start int) (int, bool) {
+	depth := 1
+	inLiteral := false
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			inLiteral = !inLiteral
+		case inLiteral:
+		case s[i] == '{':
+			depth++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
 // extractFilters extracts FILTER clauses with balanced parentheses
 func extractFilters(whereClause string) []Filter {
 	var filters []Filter
@@ -182,13 +245,19 @@ func extractFilters(whereClause string) []Filter {
 }
 
 // splitTriples splits a WHERE clause by periods, but not periods inside URIs
+// or literals such as "main.go"
 func splitTriples(whereClause string) []string {
 	var triples []string
 	var current strings.Builder
-	inURI := false
+	inURI, inLiteral := false, false
 
 	for _, ch := range whereClause {
-		if ch == '<' {
+		if ch == '"' {
+			inLiteral = !inLiteral
+			current.WriteRune(ch)
+		} else if inLiteral {
+			current.WriteRune(ch)
+		} else if ch == '<' {
 			inURI = true
 			current.WriteRune(ch)
 		} else if ch == '>' {
@@ -236,8 +305,8 @@ func parseTriplePatterns(whereClause string, prefixes map[string]string) ([]Trip
 				continue
 			}
 
-			// Split by whitespace
-			tokens := strings.Fields(part)
+			// Split by whitespace, keeping property paths in one token
+			tokens := joinPathTokens(strings.Fields(part))
 			if len(tokens) < 3 {
 				// Check if this is a continuation (has only 2 tokens)
 				if len(tokens) == 2 && currentSubject != "" {
@@ -249,7 +318,17 @@ func parseTriplePatterns(whereClause string, prefixes map[string]string) ([]Trip
 			}
 
 			subject := expandPrefix(tokens[0], prefixes)
-			predicate := expandPrefix(tokens[1], prefixes)
+
+			var path *PropertyPath
+			predicate := tokens[1]
+			if isPropertyPath(predicate) {
+				var err error
+				if path, err = ParsePropertyPath(predicate, prefixes); err != nil {
+					return nil, err
+				}
+			} else {
+				predicate = expandPrefix(predicate, prefixes)
+			}
 
 			// Handle "a" as rdf:type
 			if predicate == "a" {
@@ -265,6 +344,7 @@ func parseTriplePatterns(whereClause string, prefixes map[string]string) ([]Trip
 				Subject:   subject,
 				Predicate: predicate,
 				Object:    object,
+				Path:      path,
 			})
 
 			currentSubject = subject
@@ -274,6 +354,38 @@ func parseTriplePatterns(whereClause string, prefixes map[string]string) ([]Trip
 	return patterns, nil
 }
 
+// joinPathTokens rejoins property path tokens written with spaces around
+// "/", "|" or inside parentheses, such as "( code:a | code:b )+"
+func joinPathTokens(tokens []string) []string {
+	var joined []string
+	depth := 0
+	inLiteral := false
+	for _, token := range tokens {
+		n := len(joined)
+		continues := n > 0 && !inLiteral && (depth > 0 ||
+			strings.HasSuffix(joined[n-1], "/") || strings.HasSuffix(joined[n-1], "|") ||
+			strings.HasPrefix(token, "/") || strings.HasPrefix(token, "|"))
+		if continues {
+			joined[n-1] += token
+		} else {
+			joined = append(joined, token)
+		}
+
+		for _, ch := range token {
+			switch {
+			case ch == '"':
+				inLiteral = !inLiteral
+			case inLiteral:
+			case ch == '(':
+				depth++
+			case ch == ')':
+				depth--
+			}
+		}
+	}
+	return joined
+}
+
 // expandPrefix expands a prefixed URI
 func expandPrefix(term string, prefixes map[string]string) string {
 	term = strings.TrimSpace(term)
@@ -141,6 +141,21 @@ func TestParseQuery_PrefixExpansion(t *testing.T) {
 	}
 }
 
+func TestParseQuery_LiteralWithPeriod(t *testing.T) {
+	query, err := ParseQuery(`SELECT ?module WHERE { ?module <#name> "main.go" . ?module <#layer> ?layer . }`)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	where := query.Select.Where
+	if len(where) != 2 {
+		t.Fatalf("Patterns = %+v, want 2", where)
+	}
+	if where[0].Object != `"main.go"` {
+		t.Errorf("Object = %s, want \"main.go\"", where[0].Object)
+	}
+}
+
 func TestIsVariable(t *testing.T) {
 	tests := []struct {
 		input string
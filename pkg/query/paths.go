@@ -0,0 +1,394 @@
+/*
+# Module: pkg/query/paths.go
+SPARQL property paths.
+
+Parses property path predicates (p+, p*, p?, p1/p2 and p1|p2, grouped with
+parentheses) and evaluates them against the triple store. A path is
+evaluated from whichever end of the pattern is bound, walking the graph
+breadth-first for + and * so cycles terminate.
+
+## Linked Modules
+- [query](./query.go) - Query data structures
+- [parser](./parser.go) - SPARQL query parser
+- [executor](./executor.go) - Query executor
+
+## Tags
+query, sparql, property-paths
+
+## Exports
+PathOp, PropertyPath, ParsePropertyPath
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#pkg/query/paths.go> a code:Module ;
+    code:name "pkg/query/paths.go" ;
+    code:description "SPARQL property paths" ;
+    code:language "go" ;
+    code:layer "query" ;
+    code:linksTo <./query.go>, <./parser.go>, <./executor.go> ;
+    code:exports <#PathOp>, <#PropertyPath>, <#ParsePropertyPath> ;
+    code:tags "query", "sparql", "property-paths" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PathOp is a property path operator
+type PathOp int
+
+const (
+	PathPredicate   PathOp = iota // A single predicate
+	PathSequence                  // p1/p2
+	PathAlternative               // p1|p2
+	PathOneOrMore                 // p+
+	PathZeroOrMore                // p*
+	PathZeroOrOne                 // p?
+)
+
+// PropertyPath is a parsed SPARQL property path
+type PropertyPath struct {
+	Op        PathOp
+	Predicate string          // Predicate of a PathPredicate, prefixes expanded
+	Paths     []*PropertyPath // Operands of the other operators
+}
+
+// isPropertyPath reports whether a predicate term uses path operators
+// outside of IRIs
+func isPropertyPath(term string) bool {
+	if IsVariable(term) {
+		return false
+	}
+	inIRI := false
+	for _, ch := range term {
+		switch {
+		case ch == '<':
+			inIRI = true
+		case ch == '>':
+			inIRI = false
+		case !inIRI && strings.ContainsRune("+*?/|()", ch):
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePropertyPath parses a property path such as <#imports>+ or
+// (code:linksTo|code:imports)/code:name
+func ParsePropertyPath(expr string, prefixes map[string]string) (*PropertyPath, error) {
+	p := &pathParser{input: strings.TrimSpace(expr), prefixes: prefixes}
+	path, err := p.parseAlternative()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("invalid property path %q: unexpected %q", expr, p.input[p.pos:])
+	}
+	return path, nil
+}
+
+// pathParser is a recursive descent parser for property paths
+type pathParser struct {
+	input    string
+	pos      int
+	prefixes map[string]string
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t' || p.input[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end
+func (p *pathParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// parseAlternative parses seq ('|' seq)*
+func (p *pathParser) parseAlternative() (*PropertyPath, error) {
+	return p.parseList('|', PathAlternative, p.parseSequence)
+}
+
+// parseSequence parses elt ('/' elt)*
+func (p *pathParser) parseSequence() (*PropertyPath, error) {
+	return p.parseList('/', PathSequence, p.parseElement)
+}
+
+// parseList parses operands separated by sep into an op node
+func (p *pathParser) parseList(sep byte, op PathOp, operand func() (*PropertyPath, error)) (*PropertyPath, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	paths := []*PropertyPath{first}
+	for p.peek() == sep {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, next)
+	}
+	if len(paths) == 1 {
+		return first, nil
+	}
+	return &PropertyPath{Op: op, Paths: paths}, nil
+}
+
+// parseElement parses a primary path with an optional +, * or ? modifier
+func (p *pathParser) parseElement() (*PropertyPath, error) {
+	primary, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	// Modifiers must follow the primary directly
+	if p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case '+':
+			p.pos++
+			return &PropertyPath{Op: PathOneOrMore, Paths: []*PropertyPath{primary}}, nil
+		case '*':
+			p.pos++
+			return &PropertyPath{Op: PathZeroOrMore, Paths: []*PropertyPath{primary}}, nil
+		case '?':
+			p.pos++
+			return &PropertyPath{Op: PathZeroOrOne, Paths: []*PropertyPath{primary}}, nil
+		}
+	}
+	return primary, nil
+}
+
+// parsePrimary parses an IRI, a prefixed name, "a" or a parenthesized path
+func (p *pathParser) parsePrimary() (*PropertyPath, error) {
+	switch p.peek() {
+	case 0:
+		return nil, fmt.Errorf("invalid property path %q: missing predicate", p.input)
+	case '(':
+		p.pos++
+		path, err := p.parseAlternative()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("invalid property path %q: missing ')'", p.input)
+		}
+		p.pos++
+		return path, nil
+	case '<':
+		end := strings.IndexByte(p.input[p.pos:], '>')
+		if end == -1 {
+			return nil, fmt.Errorf("invalid property path %q: unterminated IRI", p.input)
+		}
+		iri := p.input[p.pos : p.pos+end+1]
+		p.pos += end + 1
+		return &PropertyPath{Op: PathPredicate, Predicate: iri}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune("+*?/|() \t\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if name == "" {
+		return nil, fmt.Errorf("invalid property path %q: unexpected %q", p.input, p.input[p.pos:])
+	}
+	if name == "a" {
+		name = expandPrefix("rdf:type", p.prefixes)
+	} else {
+		name = expandPrefix(name, p.prefixes)
+	}
+	return &PropertyPath{Op: PathPredicate, Predicate: name}, nil
+}
+
+// nullable reports whether the path matches zero-length paths, relating
+// every node to itself
+func (path *PropertyPath) nullable() bool {
+	switch path.Op {
+	case PathZeroOrMore, PathZeroOrOne:
+		return true
+	case PathSequence:
+		for _, operand := range path.Paths {
+			if !operand.nullable() {
+				return false
+			}
+		}
+		return true
+	case PathAlternative:
+		for _, operand := range path.Paths {
+			if operand.nullable() {
+				return true
+			}
+		}
+		return false
+	case PathOneOrMore:
+		return path.Paths[0].nullable()
+	default:
+		return false
+	}
+}
+
+// matchPath matches a triple pattern whose predicate is a property path
+func (e *Executor) matchPath(pattern TriplePattern, currentBindings []map[string]string, prefixes map[string]string) []map[string]string {
+	var newBindings []map[string]string
+
+	for _, binding := range currentBindings {
+		subject := e.resolveValue(pattern.Subject, binding, prefixes)
+		object := e.resolveValue(pattern.Object, binding, prefixes)
+
+		var pairs [][2]string
+		switch {
+		case subject != "":
+			for _, o := range e.evalPath(pattern.Path, subject, true, prefixes) {
+				if object == "" || o == object {
+					pairs = append(pairs, [2]string{subject, o})
+				}
+			}
+		case object != "":
+			for _, s := range e.evalPath(pattern.Path, object, false, prefixes) {
+				pairs = append(pairs, [2]string{s, object})
+			}
+		default:
+			for _, s := range e.pathStartNodes(pattern.Path) {
+				for _, o := range e.evalPath(pattern.Path, s, true, prefixes) {
+					pairs = append(pairs, [2]string{s, o})
+				}
+			}
+		}
+
+		for _, pair := range pairs {
+			// ?x p+ ?x only matches paths back to the start
+			if IsVariable(pattern.Subject) && pattern.Subject == pattern.Object && pair[0] != pair[1] {
+				continue
+			}
+			newBinding := make(map[string]string, len(binding)+2)
+			for k, v := range binding {
+				newBinding[k] = v
+			}
+			if IsVariable(pattern.Subject) {
+				newBinding[StripVariable(pattern.Subject)] = pair[0]
+			}
+			if IsVariable(pattern.Object) {
+				newBinding[StripVariable(pattern.Object)] = pair[1]
+			}
+			newBindings = append(newBindings, newBinding)
+		}
+	}
+
+	return newBindings
+}
+
+// pathStartNodes returns the nodes a path with neither end bound is
+// evaluated from: every subject, and every object if the path can be empty
+func (e *Executor) pathStartNodes(path *PropertyPath) []string {
+	nodes := e.store.Subjects()
+	if path.nullable() {
+		seen := make(map[string]bool, len(nodes))
+		for _, node := range nodes {
+			seen[node] = true
+		}
+		for _, node := range e.store.Objects() {
+			if !seen[node] {
+				seen[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// evalPath returns the nodes reachable from node along path, sorted;
+// backward walks the path from its end to its start
+func (e *Executor) evalPath(path *PropertyPath, node string, forward bool, prefixes map[string]string) []string {
+	reached := e.stepPath(path, map[string]bool{node: true}, forward, prefixes)
+	nodes := make([]string, 0, len(reached))
+	for n := range reached {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// stepPath returns the nodes reachable from any of nodes along path
+func (e *Executor) stepPath(path *PropertyPath, nodes map[string]bool, forward bool, prefixes map[string]string) map[string]bool {
+	reached := make(map[string]bool)
+
+	switch path.Op {
+	case PathPredicate:
+		predicate := e.resolveValue(path.Predicate, nil, prefixes)
+		for node := range nodes {
+			if forward {
+				for _, t := range e.store.Find(node, predicate, "") {
+					reached[t.Object] = true
+				}
+			} else {
+				for _, t := range e.store.Find("", predicate, node) {
+					reached[t.Subject] = true
+				}
+			}
+		}
+
+	case PathSequence:
+		reached = nodes
+		for i := range path.Paths {
+			operand := path.Paths[i]
+			if !forward {
+				operand = path.Paths[len(path.Paths)-1-i]
+			}
+			reached = e.stepPath(operand, reached, forward, prefixes)
+		}
+
+	case PathAlternative:
+		for _, operand := range path.Paths {
+			for n := range e.stepPath(operand, nodes, forward, prefixes) {
+				reached[n] = true
+			}
+		}
+
+	case PathZeroOrOne:
+		for n := range nodes {
+			reached[n] = true
+		}
+		for n := range e.stepPath(path.Paths[0], nodes, forward, prefixes) {
+			reached[n] = true
+		}
+
+	case PathOneOrMore, PathZeroOrMore:
+		if path.Op == PathZeroOrMore {
+			for n := range nodes {
+				reached[n] = true
+			}
+		}
+		// Breadth-first, expanding each node once so cycles terminate
+		frontier := nodes
+		expanded := make(map[string]bool)
+		for len(frontier) > 0 {
+			for n := range frontier {
+				expanded[n] = true
+			}
+			next := make(map[string]bool)
+			for n := range e.stepPath(path.Paths[0], frontier, forward, prefixes) {
+				reached[n] = true
+				if !expanded[n] {
+					next[n] = true
+				}
+			}
+			frontier = next
+		}
+	}
+
+	return reached
+}
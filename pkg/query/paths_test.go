@@ -0,0 +1,130 @@
+package query
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+// setupPathStore builds main -> service -> db -> service (a cycle), with
+// main also linking to util, which has no imports
+func setupPathStore() *store.TripleStore {
+	ts := store.NewTripleStore()
+	ts.Add("<#main.go>", "<#imports>", "<#service.go>")
+	ts.Add("<#service.go>", "<#imports>", "<#db.go>")
+	ts.Add("<#db.go>", "<#imports>", "<#service.go>")
+	ts.Add("<#main.go>", "https://schema.codedoc.org/linksTo", "<#util.go>")
+	for _, module := range []string{"main.go", "service.go", "db.go", "util.go"} {
+		ts.Add("<#"+module+">", "https://schema.codedoc.org/name", module)
+	}
+	return ts
+}
+
+// column returns the sorted values of a variable
+func column(result *QueryResult, variable string) []string {
+	var values []string
+	for _, binding := range result.Bindings {
+		values = append(values, binding[variable])
+	}
+	sort.Strings(values)
+	return values
+}
+
+func TestParsePropertyPath(t *testing.T) {
+	prefixes := map[string]string{"code": "https://schema.codedoc.org/"}
+
+	path, err := ParsePropertyPath("(code:linksTo|<#imports>)+/code:name", prefixes)
+	if err != nil {
+		t.Fatalf("ParsePropertyPath() error = %v", err)
+	}
+	if path.Op != PathSequence || len(path.Paths) != 2 {
+		t.Fatalf("Expected a sequence of two paths, got %+v", path)
+	}
+	closure := path.Paths[0]
+	if closure.Op != PathOneOrMore || closure.Paths[0].Op != PathAlternative {
+		t.Errorf("Expected (a|b)+, got %+v", closure)
+	}
+	if name := path.Paths[1]; name.Op != PathPredicate || name.Predicate != "<https://schema.codedoc.org/name>" {
+		t.Errorf("Expected expanded code:name, got %+v", name)
+	}
+
+	for _, invalid := range []string{"(<#imports>", "<#imports>/", "<#imports", "|<#imports>"} {
+		if _, err := ParsePropertyPath(invalid, prefixes); err == nil {
+			t.Errorf("ParsePropertyPath(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestExecutor_PropertyPaths(t *testing.T) {
+	executor := NewExecutor(setupPathStore())
+
+	tests := []struct {
+		name     string
+		query    string
+		variable string
+		want     []string
+	}{
+		{
+			name:     "one or more",
+			query:    `SELECT ?dep WHERE { <#main.go> <#imports>+ ?dep . }`,
+			variable: "dep",
+			want:     []string{"<#db.go>", "<#service.go>"},
+		},
+		{
+			name:     "one or more backward",
+			query:    `SELECT ?affected WHERE { ?affected <#imports>+ <#db.go> . }`,
+			variable: "affected",
+			want:     []string{"<#db.go>", "<#main.go>", "<#service.go>"},
+		},
+		{
+			name:     "zero or more",
+			query:    `SELECT ?dep WHERE { <#main.go> <#imports>* ?dep . }`,
+			variable: "dep",
+			want:     []string{"<#db.go>", "<#main.go>", "<#service.go>"},
+		},
+		{
+			name:     "zero or one",
+			query:    `SELECT ?dep WHERE { <#main.go> <#imports>? ?dep . }`,
+			variable: "dep",
+			want:     []string{"<#main.go>", "<#service.go>"},
+		},
+		{
+			name:     "sequence",
+			query:    `SELECT ?name WHERE { <#main.go> <#imports>/<#imports>/code:name ?name . }`,
+			variable: "name",
+			want:     []string{"db.go"},
+		},
+		{
+			name:     "alternative with spaces",
+			query:    `SELECT ?dep WHERE { <#main.go> ( <#imports> | code:linksTo ) ?dep . }`,
+			variable: "dep",
+			want:     []string{"<#service.go>", "<#util.go>"},
+		},
+		{
+			name:     "unbound ends",
+			query:    `SELECT ?module WHERE { ?module <#imports>+ ?module . }`,
+			variable: "module",
+			want:     []string{"<#db.go>", "<#service.go>"},
+		},
+		{
+			name:     "joined with another pattern",
+			query:    `SELECT ?name WHERE { <#main.go> <#imports>+ ?dep . ?dep code:name ?name . }`,
+			variable: "name",
+			want:     []string{"db.go", "service.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := executor.ExecuteString("PREFIX code: <https://schema.codedoc.org/>\n" + tt.query)
+			if err != nil {
+				t.Fatalf("ExecuteString() error = %v", err)
+			}
+			if got := column(result, tt.variable); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("?%s = %v, want %v", tt.variable, got, tt.want)
+			}
+		})
+	}
+}
@@ -130,7 +130,8 @@ func (qp *QueryPlanner) estimateSelectivity(pattern TriplePattern) float64 {
 		}
 	}
 
-	if !IsVariable(pattern.Predicate) {
+	// A property path may follow many predicates, so it counts as unbound
+	if !IsVariable(pattern.Predicate) && pattern.Path == nil {
 		boundCount++
 		// Predicate is bound - use predicate statistics
 		if count, ok := qp.stats.PredicateCounts[pattern.Predicate]; ok {
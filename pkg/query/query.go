@@ -48,6 +48,7 @@ type SelectQuery struct {
 	Distinct  bool              // DISTINCT modifier
 	Where     []TriplePattern   // WHERE clause triple patterns
 	Filters   []Filter          // FILTER clauses
+	NotExists [][]TriplePattern // FILTER NOT EXISTS groups
 	OrderBy   []OrderBy         // ORDER BY clauses
 	Limit     int               // LIMIT (0 = no limit)
 	Offset    int               // OFFSET (0 = no offset)
@@ -59,6 +60,7 @@ type TriplePattern struct {
 	Subject   string // Can be variable (?var), URI (<uri>), or literal
 	Predicate string
 	Object    string
+	Path      *PropertyPath // Set when the predicate is a property path
 }
 
 // Filter represents a FILTER clause
@@ -0,0 +1,314 @@
+/*
+# Module: pkg/query/results.go
+Query result serialization formats.
+
+Writes SELECT results as CSV, TSV, newline-delimited JSON or the W3C SPARQL
+1.1 Query Results JSON format. Writers take one binding at a time, so
+streamed results are written as they arrive.
+
+## Linked Modules
+- [executor](./executor.go) - Query results
+- [query](./query.go) - Term helpers
+
+## Tags
+query, results, csv, json, sparql
+
+## Exports
+ResultWriter, NewResultWriter, WriteResult, ResultFormats, Term, NewTerm
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#results.go> a code:Module ;
+    code:name "pkg/query/results.go" ;
+    code:description "Query result serialization formats" ;
+    code:language "go" ;
+    code:layer "query" ;
+    code:linksTo <./executor.go>, <./query.go> ;
+    code:exports <#ResultWriter>, <#NewResultWriter>, <#WriteResult>, <#ResultFormats>, <#Term>, <#NewTerm> ;
+    code:tags "query", "results", "csv", "json", "sparql" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package query
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Result formats written by NewResultWriter
+const (
+	FormatCSV        = "csv"
+	FormatTSV        = "tsv"
+	FormatNDJSON     = "ndjson"
+	FormatSPARQLJSON = "sparql-json"
+)
+
+// ResultFormats lists the formats NewResultWriter supports
+var ResultFormats = []string{FormatCSV, FormatTSV, FormatNDJSON, FormatSPARQLJSON}
+
+// absoluteIRIRegex matches unbracketed absolute IRIs such as
+// https://schema.codedoc.org/name or urn:uuid:...
+var absoluteIRIRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*://|urn:)\S+$`)
+
+// tsvEscaper keeps each TSV value on one line in one column
+var tsvEscaper = strings.NewReplacer("\\", `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// ResultWriter writes query results row by row
+type ResultWriter interface {
+	// WriteHeader writes the result variables; call it once, first
+	WriteHeader(variables []string) error
+	// WriteRow writes one binding; unbound variables are left empty
+	WriteRow(binding map[string]string) error
+	// Close finishes the output and flushes buffered data
+	Close() error
+}
+
+// NewResultWriter creates a writer for one of ResultFormats
+func NewResultWriter(w io.Writer, format string) (ResultWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvResultWriter{writer: csv.NewWriter(w)}, nil
+	case FormatTSV:
+		return &tsvResultWriter{w: w}, nil
+	case FormatNDJSON:
+		return &ndjsonResultWriter{w: w}, nil
+	case FormatSPARQLJSON:
+		return &sparqlJSONResultWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported result format: %s (available: %s)", format, strings.Join(ResultFormats, ", "))
+	}
+}
+
+// WriteResult writes a complete result in one of ResultFormats
+func WriteResult(w io.Writer, result *QueryResult, format string) error {
+	writer, err := NewResultWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteHeader(result.Variables); err != nil {
+		return err
+	}
+	for _, binding := range result.Bindings {
+		if err := writer.WriteRow(binding); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// Term is an RDF term in the SPARQL JSON results format
+type Term struct {
+	Type     string `json:"type"` // uri, literal or bnode
+	Value    string `json:"value"`
+	Lang     string `json:"xml:lang,omitempty"`
+	Datatype string `json:"datatype,omitempty"`
+}
+
+// NewTerm types a bound value: <...> and absolute IRIs are URIs, _: names
+// are blank nodes, and everything else is a literal. Quoted literals keep
+// their language tag or datatype.
+func NewTerm(value string) Term {
+	switch {
+	case IsURI(value):
+		return Term{Type: "uri", Value: StripURI(value)}
+	case strings.HasPrefix(value, "_:"):
+		return Term{Type: "bnode", Value: value[2:]}
+	case absoluteIRIRegex.MatchString(value):
+		return Term{Type: "uri", Value: value}
+	case strings.HasPrefix(value, `"`):
+		end := strings.LastIndex(value, `"`)
+		if end <= 0 {
+			break
+		}
+		term := Term{Type: "literal", Value: value[1:end]}
+		suffix := value[end+1:]
+		switch {
+		case suffix == "":
+			return term
+		case strings.HasPrefix(suffix, "@"):
+			term.Lang = suffix[1:]
+			return term
+		case strings.HasPrefix(suffix, "^^"):
+			term.Datatype = StripURI(suffix[2:])
+			return term
+		}
+	}
+	return Term{Type: "literal", Value: value}
+}
+
+// rowValues returns the binding's values in variable order
+func rowValues(variables []string, binding map[string]string) []string {
+	row := make([]string, len(variables))
+	for i, variable := range variables {
+		row[i] = binding[variable]
+	}
+	return row
+}
+
+// marshalJSON encodes v without escaping <, > and &, which are common in
+// IRIs
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsonObject encodes the bound variables of a binding as a JSON object
+// with keys in variable order
+func jsonObject(variables []string, binding map[string]string, encode func(string) any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, variable := range variables {
+		value, ok := binding[variable]
+		if !ok {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := marshalJSON(variable)
+		if err != nil {
+			return nil, err
+		}
+		data, err := marshalJSON(encode(value))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// csvResultWriter writes RFC 4180 CSV with a header row
+type csvResultWriter struct {
+	writer    *csv.Writer
+	variables []string
+}
+
+func (cw *csvResultWriter) WriteHeader(variables []string) error {
+	cw.variables = variables
+	return cw.writer.Write(variables)
+}
+
+func (cw *csvResultWriter) WriteRow(binding map[string]string) error {
+	return cw.writer.Write(rowValues(cw.variables, binding))
+}
+
+func (cw *csvResultWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// tsvResultWriter writes tab-separated values with a header row, escaping
+// tabs, newlines and backslashes in values
+type tsvResultWriter struct {
+	w         io.Writer
+	variables []string
+}
+
+func (tw *tsvResultWriter) WriteHeader(variables []string) error {
+	tw.variables = variables
+	return tw.writeLine(variables)
+}
+
+func (tw *tsvResultWriter) WriteRow(binding map[string]string) error {
+	return tw.writeLine(rowValues(tw.variables, binding))
+}
+
+func (tw *tsvResultWriter) writeLine(values []string) error {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = tsvEscaper.Replace(value)
+	}
+	_, err := io.WriteString(tw.w, strings.Join(escaped, "\t")+"\n")
+	return err
+}
+
+func (tw *tsvResultWriter) Close() error {
+	return nil
+}
+
+// ndjsonResultWriter writes one JSON object of bound variables per line
+type ndjsonResultWriter struct {
+	w         io.Writer
+	variables []string
+}
+
+func (nw *ndjsonResultWriter) WriteHeader(variables []string) error {
+	nw.variables = variables
+	return nil
+}
+
+func (nw *ndjsonResultWriter) WriteRow(binding map[string]string) error {
+	data, err := jsonObject(nw.variables, binding, func(value string) any { return value })
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(nw.w, "%s\n", data)
+	return err
+}
+
+func (nw *ndjsonResultWriter) Close() error {
+	return nil
+}
+
+// sparqlJSONResultWriter writes the W3C SPARQL 1.1 Query Results JSON
+// format, one binding per line so rows are written as they come
+type sparqlJSONResultWriter struct {
+	w         io.Writer
+	variables []string
+	rows      int
+}
+
+func (sw *sparqlJSONResultWriter) WriteHeader(variables []string) error {
+	if variables == nil {
+		variables = []string{}
+	}
+	sw.variables = variables
+
+	vars, err := marshalJSON(variables)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(sw.w, "{\n  \"head\": {\"vars\": %s},\n  \"results\": {\"bindings\": [", vars)
+	return err
+}
+
+func (sw *sparqlJSONResultWriter) WriteRow(binding map[string]string) error {
+	data, err := jsonObject(sw.variables, binding, func(value string) any { return NewTerm(value) })
+	if err != nil {
+		return err
+	}
+
+	separator := "\n    "
+	if sw.rows > 0 {
+		separator = ",\n    "
+	}
+	sw.rows++
+	_, err = fmt.Fprintf(sw.w, "%s%s", separator, data)
+	return err
+}
+
+func (sw *sparqlJSONResultWriter) Close() error {
+	closing := "]}\n}\n"
+	if sw.rows > 0 {
+		closing = "\n  ]}\n}\n"
+	}
+	_, err := io.WriteString(sw.w, closing)
+	return err
+}
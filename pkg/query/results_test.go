@@ -0,0 +1,121 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func resultFixture() *QueryResult {
+	return &QueryResult{
+		Variables: []string{"module", "name", "note"},
+		Bindings: []map[string]string{
+			{"module": "<#api/handler.go>", "name": "handler.go", "note": "says \"hi\",\tthen\nleaves"},
+			{"module": "https://graph.example.com/repo/db.go", "name": "db.go"}, // note unbound
+		},
+		Count: 2,
+	}
+}
+
+func TestWriteResult_Delimited(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatCSV, "module,name,note\n" +
+			"<#api/handler.go>,handler.go,\"says \"\"hi\"\",\tthen\nleaves\"\n" +
+			"https://graph.example.com/repo/db.go,db.go,\n"},
+		{FormatTSV, "module\tname\tnote\n" +
+			"<#api/handler.go>\thandler.go\tsays \"hi\",\\tthen\\nleaves\n" +
+			"https://graph.example.com/repo/db.go\tdb.go\t\n"},
+		{FormatNDJSON, `{"module":"<#api/handler.go>","name":"handler.go","note":"says \"hi\",\tthen\nleaves"}` + "\n" +
+			`{"module":"https://graph.example.com/repo/db.go","name":"db.go"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteResult(&buf, resultFixture(), tt.format); err != nil {
+				t.Fatalf("WriteResult failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteResult() =\n%q\nwant\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteResult_SPARQLJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResult(&buf, resultFixture(), FormatSPARQLJSON); err != nil {
+		t.Fatalf("WriteResult failed: %v", err)
+	}
+
+	var doc struct {
+		Head struct {
+			Vars []string `json:"vars"`
+		} `json:"head"`
+		Results struct {
+			Bindings []map[string]Term `json:"bindings"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if !reflect.DeepEqual(doc.Head.Vars, []string{"module", "name", "note"}) {
+		t.Errorf("Unexpected vars: %v", doc.Head.Vars)
+	}
+	want := []map[string]Term{
+		{
+			"module": {Type: "uri", Value: "#api/handler.go"},
+			"name":   {Type: "literal", Value: "handler.go"},
+			"note":   {Type: "literal", Value: "says \"hi\",\tthen\nleaves"},
+		},
+		{
+			"module": {Type: "uri", Value: "https://graph.example.com/repo/db.go"},
+			"name":   {Type: "literal", Value: "db.go"},
+		},
+	}
+	if !reflect.DeepEqual(doc.Results.Bindings, want) {
+		t.Errorf("Unexpected bindings:\n%+v", doc.Results.Bindings)
+	}
+
+	// Empty results are still a complete document
+	buf.Reset()
+	if err := WriteResult(&buf, &QueryResult{}, FormatSPARQLJSON); err != nil {
+		t.Fatalf("WriteResult failed: %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("Empty result is not valid JSON:\n%s", buf.String())
+	}
+}
+
+func TestWriteResult_UnknownFormat(t *testing.T) {
+	if err := WriteResult(&bytes.Buffer{}, resultFixture(), "xlsx"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestNewTerm(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Term
+	}{
+		{"<#main.go>", Term{Type: "uri", Value: "#main.go"}},
+		{"https://schema.codedoc.org/Module", Term{Type: "uri", Value: "https://schema.codedoc.org/Module"}},
+		{"urn:uuid:1234", Term{Type: "uri", Value: "urn:uuid:1234"}},
+		{"_:b0", Term{Type: "bnode", Value: "b0"}},
+		{"services", Term{Type: "literal", Value: "services"}},
+		{"layer: api", Term{Type: "literal", Value: "layer: api"}},
+		{`"Bonjour"@fr`, Term{Type: "literal", Value: "Bonjour", Lang: "fr"}},
+		{`"42"^^<http://www.w3.org/2001/XMLSchema#integer>`, Term{Type: "literal", Value: "42", Datatype: "http://www.w3.org/2001/XMLSchema#integer"}},
+	}
+
+	for _, tt := range tests {
+		if got := NewTerm(tt.value); got != tt.want {
+			t.Errorf("NewTerm(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+/*
+# Module: pkg/query/saved.go
+Saved project queries in .graphfs/queries.
+
+A saved query is a .sparql file named after the query, with a YAML header
+in # comments between "# ---" lines giving its description, category and
+variables. The body uses template syntax ({{.module}}), so saved queries
+load as templates and run wherever templates do; they override built-in,
+pack and custom templates with the same name.
+
+## Linked Modules
+- [templates](./templates.go) - Query templates
+
+## Tags
+query, templates, sparql, saved-queries
+
+## Exports
+QueriesDir, ParseSavedQuery, FormatSavedQuery, ReadSavedQueries, SaveQuery, TemplateVariableNames,
+TemplateManager.AddSavedQueries, BindTemplateArgs
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#saved.go> a code:Module ;
+    code:name "pkg/query/saved.go" ;
+    code:description "Saved project queries in .graphfs/queries" ;
+    code:language "go" ;
+    code:layer "query" ;
+    code:linksTo <./templates.go> ;
+    code:exports <#QueriesDir>, <#ParseSavedQuery>, <#FormatSavedQuery>, <#ReadSavedQueries>, <#SaveQuery>, <#TemplateVariableNames>, <#TemplateManager.AddSavedQueries>, <#BindTemplateArgs> ;
+    code:tags "query", "templates", "sparql", "saved-queries" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package query
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// QueriesDir is the saved queries directory inside the .graphfs directory
+	QueriesDir = "queries"
+
+	// savedQueryExt is the file extension of saved queries
+	savedQueryExt = ".sparql"
+
+	// headerDelimiter opens and closes the YAML header of a saved query
+	headerDelimiter = "# ---"
+)
+
+var (
+	// queryNameRegex restricts query names to safe file names
+	queryNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+	// templateFieldRegex matches {{.name}} references in a template
+	templateFieldRegex = regexp.MustCompile(`\{\{-?\s*\.([A-Za-z_]\w*)\s*-?\}\}`)
+)
+
+// savedQueryHeader is the YAML header of a saved query
+type savedQueryHeader struct {
+	Description string     `yaml:"description,omitempty"`
+	Category    string     `yaml:"category,omitempty"`
+	Variables   []Variable `yaml:"variables,omitempty"`
+}
+
+// ParseSavedQuery parses a saved query file. The header is optional; its
+// variables default to the {{.name}} references in the body.
+func ParseSavedQuery(name, content string) (*QueryTemplate, error) {
+	var header savedQueryHeader
+	body := content
+
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == headerDelimiter {
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == headerDelimiter {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("query %s: header is not closed with %q", name, headerDelimiter)
+		}
+
+		var yamlText strings.Builder
+		for _, line := range lines[1:end] {
+			line = strings.TrimPrefix(line, "#")
+			yamlText.WriteString(strings.TrimPrefix(line, " "))
+		}
+		if err := yaml.Unmarshal([]byte(yamlText.String()), &header); err != nil {
+			return nil, fmt.Errorf("query %s: invalid header: %w", name, err)
+		}
+		body = strings.Join(lines[end+1:], "")
+	}
+
+	tmpl := &QueryTemplate{
+		Name:        name,
+		Description: header.Description,
+		Category:    header.Category,
+		Query:       strings.TrimSpace(body),
+		Variables:   header.Variables,
+		Example:     "graphfs query run " + name,
+	}
+	if tmpl.Category == "" {
+		tmpl.Category = "project"
+	}
+	if tmpl.Variables == nil {
+		for _, variable := range TemplateVariableNames(tmpl.Query) {
+			tmpl.Variables = append(tmpl.Variables, Variable{Name: variable})
+		}
+	}
+	for _, v := range tmpl.Variables {
+		if v.Name == "" {
+			return nil, fmt.Errorf("query %s: variable without a name", name)
+		}
+		tmpl.Example += " " + v.Name + "=<" + v.Name + ">"
+	}
+	return tmpl, nil
+}
+
+// FormatSavedQuery returns the file content for a saved query
+func FormatSavedQuery(tmpl *QueryTemplate) (string, error) {
+	header := savedQueryHeader{
+		Description: tmpl.Description,
+		Variables:   tmpl.Variables,
+	}
+	if tmpl.Category != "project" {
+		header.Category = tmpl.Category
+	}
+
+	data, err := yaml.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal query header: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString(headerDelimiter + "\n")
+	if text := strings.TrimSpace(string(data)); text != "{}" {
+		for _, line := range strings.Split(text, "\n") {
+			b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+		}
+	}
+	b.WriteString(headerDelimiter + "\n")
+	b.WriteString(strings.TrimSpace(tmpl.Query) + "\n")
+	return b.String(), nil
+}
+
+// TemplateVariableNames returns the distinct {{.name}} references in a
+// template, in order of first use
+func TemplateVariableNames(queryStr string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templateFieldRegex.FindAllStringSubmatch(queryStr, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// ReadSavedQueries reads the saved queries in a directory, sorted by name.
+// A missing directory has no queries.
+func ReadSavedQueries(dir string) ([]*QueryTemplate, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var queries []*QueryTemplate
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), savedQueryExt) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		tmpl, err := ParseSavedQuery(strings.TrimSuffix(entry.Name(), savedQueryExt), string(data))
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, tmpl)
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+	return queries, nil
+}
+
+// SaveQuery writes a saved query to dir and returns its path. An existing
+// query is only replaced with overwrite.
+func SaveQuery(dir string, tmpl *QueryTemplate, overwrite bool) (string, error) {
+	if !queryNameRegex.MatchString(tmpl.Name) {
+		return "", fmt.Errorf("invalid query name %q: use letters, digits, '-', '_' and '.'", tmpl.Name)
+	}
+
+	content, err := FormatSavedQuery(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create queries directory: %w", err)
+	}
+
+	path := filepath.Join(dir, tmpl.Name+savedQueryExt)
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return "", fmt.Errorf("query %s already exists", tmpl.Name)
+		}
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write query: %w", err)
+	}
+	return path, nil
+}
+
+// AddSavedQueries loads the saved queries in dir, replacing templates with
+// the same name
+func (tm *TemplateManager) AddSavedQueries(dir string) error {
+	queries, err := ReadSavedQueries(dir)
+	if err != nil {
+		return err
+	}
+	for _, tmpl := range queries {
+		tm.templates[tmpl.Name] = tmpl
+	}
+	return nil
+}
+
+// BindTemplateArgs binds command arguments to a template's variables:
+// name=value pairs by name, bare values to the remaining variables in order
+func BindTemplateArgs(tmpl *QueryTemplate, args []string) (map[string]string, error) {
+	declared := make(map[string]bool, len(tmpl.Variables))
+	for _, v := range tmpl.Variables {
+		declared[v.Name] = true
+	}
+
+	variables := make(map[string]string)
+	var positional []string
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			positional = append(positional, arg)
+			continue
+		}
+		if !declared[name] {
+			return nil, fmt.Errorf("template %s has no variable %q", tmpl.Name, name)
+		}
+		variables[name] = value
+	}
+
+	for _, v := range tmpl.Variables {
+		if len(positional) == 0 {
+			break
+		}
+		if _, ok := variables[v.Name]; !ok {
+			variables[v.Name] = positional[0]
+			positional = positional[1:]
+		}
+	}
+	if len(positional) > 0 {
+		return nil, fmt.Errorf("too many values for template %s", tmpl.Name)
+	}
+
+	return variables, nil
+}
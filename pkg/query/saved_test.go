@@ -0,0 +1,130 @@
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSavedQuery_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := &QueryTemplate{
+		Name:        "importers",
+		Description: "Modules that import a module",
+		Category:    "project",
+		Query:       "SELECT ?user WHERE {\n    ?user <#imports> <#{{.module}}> .\n} LIMIT {{.limit}}",
+		Variables: []Variable{
+			{Name: "module", Description: "Module path"},
+			{Name: "limit", Default: "10"},
+		},
+	}
+
+	path, err := SaveQuery(dir, tmpl, false)
+	if err != nil {
+		t.Fatalf("SaveQuery failed: %v", err)
+	}
+	if path != filepath.Join(dir, "importers.sparql") {
+		t.Errorf("Unexpected path %s", path)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# ---\n# description: Modules that import a module\n# variables:\n") {
+		t.Errorf("Unexpected file content:\n%s", data)
+	}
+
+	queries, err := ReadSavedQueries(dir)
+	if err != nil {
+		t.Fatalf("ReadSavedQueries failed: %v", err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("Expected one query, got %d", len(queries))
+	}
+	got := queries[0]
+	if got.Name != tmpl.Name || got.Description != tmpl.Description || got.Category != "project" ||
+		got.Query != tmpl.Query || !reflect.DeepEqual(got.Variables, tmpl.Variables) {
+		t.Errorf("Round trip changed the query:\n%+v", got)
+	}
+
+	if _, err := SaveQuery(dir, tmpl, false); err == nil {
+		t.Error("Expected an error replacing an existing query")
+	}
+	if _, err := SaveQuery(dir, tmpl, true); err != nil {
+		t.Errorf("SaveQuery with overwrite failed: %v", err)
+	}
+	if _, err := SaveQuery(dir, &QueryTemplate{Name: "../escape", Query: "ASK {}"}, false); err == nil {
+		t.Error("Expected an error for a name with a path")
+	}
+}
+
+func TestParseSavedQuery(t *testing.T) {
+	// Without a header, variables come from the body
+	tmpl, err := ParseSavedQuery("path", "SELECT ?x WHERE { <#{{.from}}> <#imports>+ <#{{ .to }}> . <#{{.from}}> ?p ?x }\n")
+	if err != nil {
+		t.Fatalf("ParseSavedQuery failed: %v", err)
+	}
+	if !reflect.DeepEqual(tmpl.Variables, []Variable{{Name: "from"}, {Name: "to"}}) || tmpl.Category != "project" {
+		t.Errorf("Unexpected query: %+v", tmpl)
+	}
+
+	if _, err := ParseSavedQuery("broken", "# ---\n# description: never closed\nSELECT * WHERE { ?s ?p ?o }\n"); err == nil {
+		t.Error("Expected an error for an unclosed header")
+	}
+	if _, err := ParseSavedQuery("broken", "# ---\n# variables: [\n# ---\nASK {}\n"); err == nil {
+		t.Error("Expected an error for an invalid header")
+	}
+}
+
+func TestTemplateManager_AddSavedQueries(t *testing.T) {
+	dir := t.TempDir()
+	content := "# ---\n# description: Project override\n# ---\nSELECT ?a ?b WHERE { ?a <#imports> ?b }\n"
+	if err := os.WriteFile(filepath.Join(dir, "circular-deps.sparql"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := NewTemplateManager("")
+	if err := tm.AddSavedQueries(dir); err != nil {
+		t.Fatalf("AddSavedQueries failed: %v", err)
+	}
+	tmpl, err := tm.GetTemplate("circular-deps")
+	if err != nil || tmpl.Description != "Project override" {
+		t.Errorf("Expected the saved query to replace the built-in template, got %+v", tmpl)
+	}
+}
+
+func TestBindTemplateArgs(t *testing.T) {
+	tmpl := &QueryTemplate{
+		Name: "path-between",
+		Variables: []Variable{
+			{Name: "from"},
+			{Name: "to"},
+			{Name: "limit", Default: "10"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"named", []string{"to=b.go", "from=a.go"}, map[string]string{"from": "a.go", "to": "b.go"}, false},
+		{"positional", []string{"a.go", "b.go"}, map[string]string{"from": "a.go", "to": "b.go"}, false},
+		{"mixed", []string{"from=a.go", "b.go", "5"}, map[string]string{"from": "a.go", "to": "b.go", "limit": "5"}, false},
+		{"unknown variable", []string{"module=a.go"}, nil, true},
+		{"too many values", []string{"a", "b", "c", "d"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BindTemplateArgs(tmpl, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BindTemplateArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BindTemplateArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -79,6 +79,11 @@ func NewStreamingExecutor(tripleStore *store.TripleStore, config *StreamConfig)
 	}
 }
 
+// SetPreprocessor sets the prefix/macro preprocessor applied to query strings
+func (e *StreamingExecutor) SetPreprocessor(p *Preprocessor) {
+	e.executor.SetPreprocessor(p)
+}
+
 // ResultStream represents a streaming result set
 type ResultStream struct {
 	Results    chan map[string]string // Channel for streaming individual bindings
@@ -189,6 +194,11 @@ func (e *StreamingExecutor) ExecuteStreamWithProgress(query *Query, progressCall
 
 // ExecuteStringStream parses and executes a query string with streaming
 func (e *StreamingExecutor) ExecuteStringStream(queryStr string) (*ResultStream, error) {
+	queryStr, err := e.executor.preprocessor.Expand(queryStr)
+	if err != nil {
+		return nil, fmt.Errorf("macro error: %w", err)
+	}
+
 	query, err := ParseQuery(queryStr)
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
@@ -320,6 +330,11 @@ func (e *StreamingExecutor) ExecutePaginated(query *Query, page, pageSize int) (
 
 // ExecuteStringPaginated parses and executes a query string with pagination
 func (e *StreamingExecutor) ExecuteStringPaginated(queryStr string, page, pageSize int) (*PaginatedResult, error) {
+	queryStr, err := e.executor.preprocessor.Expand(queryStr)
+	if err != nil {
+		return nil, fmt.Errorf("macro error: %w", err)
+	}
+
 	query, err := ParseQuery(queryStr)
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
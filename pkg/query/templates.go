@@ -8,12 +8,13 @@ to help users run common queries without knowing SPARQL syntax.
 ## Linked Modules
 - [query](./query.go) - Query data structures
 - [executor](./executor.go) - Query executor
+- [packs](./packs.go) - Fetched template packs
 
 ## Tags
 query, templates, sparql, examples
 
 ## Exports
-QueryTemplate, Variable, BuiltInTemplates, TemplateManager
+QueryTemplate, Variable, BuiltInTemplates, TemplateManager, ParseTemplateDefinition, ValidateTemplate
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -24,8 +25,8 @@ QueryTemplate, Variable, BuiltInTemplates, TemplateManager
     code:description "Query template system for common SPARQL patterns" ;
     code:language "go" ;
     code:layer "query" ;
-    code:linksTo <./query.go>, <./executor.go> ;
-    code:exports <#QueryTemplate>, <#Variable>, <#BuiltInTemplates>, <#TemplateManager> ;
+    code:linksTo <./query.go>, <./executor.go>, <./packs.go> ;
+    code:exports <#QueryTemplate>, <#Variable>, <#BuiltInTemplates>, <#TemplateManager>, <#ParseTemplateDefinition>, <#ValidateTemplate> ;
     code:tags "query", "templates", "sparql", "examples" .
 <!-- End LinkedDoc RDF -->
 */
@@ -36,10 +37,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+
+	"gopkg.in/yaml.v3"
 )
 
 // QueryTemplate represents a parameterized SPARQL query template
@@ -54,9 +58,9 @@ type QueryTemplate struct {
 
 // Variable represents a template variable that can be substituted
 type Variable struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Default     string `json:"default,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description,omitempty"`
+	Default     string `json:"default,omitempty" yaml:"default,omitempty"`
 }
 
 // BuiltInTemplates contains all predefined query templates
@@ -100,8 +104,11 @@ var BuiltInTemplates = []QueryTemplate{
 		Name:        "dependency-tree",
 		Description: "Find all transitive dependencies of a module",
 		Category:    "dependencies",
-		Query: `SELECT ?dep WHERE {
-    <#{{.module}}> <#imports>+ ?dep .
+		Query: `PREFIX code: <https://schema.codedoc.org/>
+SELECT DISTINCT ?dep WHERE {
+    ?module code:name "{{.module}}" .
+    ?module (code:dependencyEdge/code:edgeTarget)+ ?target .
+    ?target code:name ?dep .
 }`,
 		Variables: []Variable{
 			{Name: "module", Description: "Module path"},
@@ -202,6 +209,60 @@ LIMIT {{.limit}}`,
 		},
 		Example: "graphfs examples run hot-paths --limit=20",
 	},
+	{
+		Name:        "outstanding-debt",
+		Description: "Count TODO, FIXME or DEPRECATED markers per module",
+		Category:    "analysis",
+		Query: `PREFIX code: <https://schema.codedoc.org/>
+SELECT ?module (COUNT(?marker) as ?count) WHERE {
+    ?module code:{{.kind}} ?marker .
+}
+GROUP BY ?module
+ORDER BY DESC(?count)`,
+		Variables: []Variable{
+			{Name: "kind", Description: "Marker kind (todo, fixme or deprecated)", Default: "todo"},
+		},
+		Example: "graphfs examples run outstanding-debt --kind=fixme",
+	},
+	{
+		Name:        "debt-by-owner",
+		Description: "Count TODO, FIXME or DEPRECATED markers per module owner",
+		Category:    "analysis",
+		Query: `PREFIX code: <https://schema.codedoc.org/>
+SELECT ?owner (COUNT(?marker) as ?count) WHERE {
+    ?module code:ownedBy ?owner .
+    ?module code:{{.kind}} ?marker .
+}
+GROUP BY ?owner
+ORDER BY DESC(?count)`,
+		Variables: []Variable{
+			{Name: "kind", Description: "Marker kind (todo, fixme or deprecated)", Default: "todo"},
+		},
+		Example: "graphfs examples run debt-by-owner --kind=todo",
+	},
+	{
+		Name:        "modules-by-owner",
+		Description: "List the modules owned by a team or person, from CODEOWNERS or code:owner",
+		Category:    "analysis",
+		Query: `PREFIX code: <https://schema.codedoc.org/>
+SELECT ?module WHERE {
+    ?module code:ownedBy "{{.owner}}" .
+}
+ORDER BY ?module`,
+		Variables: []Variable{
+			{Name: "owner", Description: "Owner as written in CODEOWNERS, such as @org/team"},
+		},
+		Example: "graphfs examples run modules-by-owner --owner=@acme/payments",
+	},
+	{
+		Name:        "build-provenance",
+		Description: "Show what produced the graph: graphfs version, commit, build time and options",
+		Category:    "analysis",
+		Query: `SELECT ?property ?value WHERE {
+    <#graphfs-build> ?property ?value .
+}`,
+		Example: "graphfs examples run build-provenance",
+	},
 	{
 		Name:        "layer-violations",
 		Description: "Find violations of layered architecture",
@@ -249,8 +310,11 @@ ORDER BY DESC(?moduleCount)`,
 		Name:        "change-impact",
 		Description: "Find all modules affected by changing a module",
 		Category:    "impact",
-		Query: `SELECT ?affected WHERE {
-    ?affected <#imports>+ <#{{.module}}> .
+		Query: `PREFIX code: <https://schema.codedoc.org/>
+SELECT DISTINCT ?affected WHERE {
+    ?module code:name "{{.module}}" .
+    ?dependent (code:dependencyEdge/code:edgeTarget)+ ?module .
+    ?dependent code:name ?affected .
 }`,
 		Variables: []Variable{
 			{Name: "module", Description: "Module path to analyze"},
@@ -303,6 +367,7 @@ ORDER BY ?module`,
 type TemplateManager struct {
 	customTemplatesDir string
 	templates          map[string]*QueryTemplate
+	preprocessor       *Preprocessor
 }
 
 // NewTemplateManager creates a new template manager
@@ -325,6 +390,11 @@ func NewTemplateManager(customTemplatesDir string) *TemplateManager {
 	return tm
 }
 
+// SetPreprocessor sets the prefix/macro preprocessor applied to rendered templates
+func (tm *TemplateManager) SetPreprocessor(p *Preprocessor) {
+	tm.preprocessor = p
+}
+
 // GetTemplate retrieves a template by name
 func (tm *TemplateManager) GetTemplate(templateName string) (*QueryTemplate, error) {
 	tmpl, ok := tm.templates[templateName]
@@ -394,7 +464,67 @@ func (tm *TemplateManager) Render(tmpl *QueryTemplate, variables map[string]stri
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	return strings.TrimSpace(buf.String()), nil
+	rendered, err := tm.preprocessor.Expand(strings.TrimSpace(buf.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to expand macros: %w", err)
+	}
+
+	return rendered, nil
+}
+
+// ParseTemplateDefinition parses a template definition written as JSON or
+// YAML, in the format of custom template files. Unknown fields are errors.
+func ParseTemplateDefinition(data []byte) (*QueryTemplate, error) {
+	var tmpl QueryTemplate
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&tmpl); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("template definition is empty")
+		}
+		return nil, fmt.Errorf("invalid template definition: %w", err)
+	}
+	return &tmpl, nil
+}
+
+// ValidateTemplate checks that a template can be saved: it has a usable
+// name and a query that parses, and its declared variables match the
+// {{.name}} fields the query uses
+func ValidateTemplate(tmpl *QueryTemplate) error {
+	if !queryNameRegex.MatchString(tmpl.Name) {
+		return fmt.Errorf("invalid template name %q: use letters, digits, '-', '_' and '.'", tmpl.Name)
+	}
+	if strings.TrimSpace(tmpl.Query) == "" {
+		return fmt.Errorf("template %s has no query", tmpl.Name)
+	}
+	if _, err := template.New(tmpl.Name).Parse(tmpl.Query); err != nil {
+		return fmt.Errorf("template %s: invalid query: %w", tmpl.Name, err)
+	}
+
+	declared := make(map[string]bool, len(tmpl.Variables))
+	for _, v := range tmpl.Variables {
+		if v.Name == "" {
+			return fmt.Errorf("template %s has a variable without a name", tmpl.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("template %s declares variable %s twice", tmpl.Name, v.Name)
+		}
+		declared[v.Name] = true
+	}
+
+	used := TemplateVariableNames(tmpl.Query)
+	for _, name := range used {
+		if !declared[name] {
+			return fmt.Errorf("template %s uses {{.%s}} but does not declare it", tmpl.Name, name)
+		}
+		delete(declared, name)
+	}
+	for _, v := range tmpl.Variables {
+		if declared[v.Name] {
+			return fmt.Errorf("template %s declares variable %s but the query does not use it", tmpl.Name, v.Name)
+		}
+	}
+	return nil
 }
 
 // SaveCustomTemplate saves a custom template to disk
@@ -442,19 +572,31 @@ func (tm *TemplateManager) ExportTemplate(templateName string, outputPath string
 	return nil
 }
 
-// loadCustomTemplates loads custom templates from the templates directory
+// loadCustomTemplates loads installed packs and then custom templates from
+// the templates directory, so custom templates override pack templates
 func (tm *TemplateManager) loadCustomTemplates() {
-	entries, err := os.ReadDir(tm.customTemplatesDir)
+	packs, _ := os.ReadDir(filepath.Join(tm.customTemplatesDir, PacksDir))
+	for _, pack := range packs {
+		if pack.IsDir() && !strings.HasSuffix(pack.Name(), ".tmp") {
+			tm.loadTemplatesFrom(filepath.Join(tm.customTemplatesDir, PacksDir, pack.Name()))
+		}
+	}
+	tm.loadTemplatesFrom(tm.customTemplatesDir)
+}
+
+// loadTemplatesFrom loads the template JSON files in a directory
+func (tm *TemplateManager) loadTemplatesFrom(dir string) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return // Directory doesn't exist or can't be read
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || entry.Name() == PackIndexFile {
 			continue
 		}
 
-		filePath := filepath.Join(tm.customTemplatesDir, entry.Name())
+		filePath := filepath.Join(dir, entry.Name())
 		data, err := os.ReadFile(filePath)
 		if err != nil {
 			continue
@@ -365,3 +365,55 @@ func TestTemplateCategories(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTemplateDefinition(t *testing.T) {
+	yamlDef := "name: importers\nquery: SELECT ?m WHERE { ?m <#imports> <#{{.module}}> }\nvariables:\n  - name: module\n    default: main.go\n"
+	jsonDef := `{"name": "importers", "query": "SELECT ?m WHERE { ?m <#imports> <#{{.module}}> }", "variables": [{"name": "module", "default": "main.go"}]}`
+
+	for _, def := range []string{yamlDef, jsonDef} {
+		tmpl, err := ParseTemplateDefinition([]byte(def))
+		if err != nil {
+			t.Fatalf("ParseTemplateDefinition failed: %v", err)
+		}
+		if tmpl.Name != "importers" || len(tmpl.Variables) != 1 || tmpl.Variables[0].Default != "main.go" {
+			t.Errorf("Unexpected template: %+v", tmpl)
+		}
+	}
+
+	if _, err := ParseTemplateDefinition([]byte(`{"name": "typo", "qurey": "ASK {}"}`)); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+	if _, err := ParseTemplateDefinition(nil); err == nil {
+		t.Error("Expected an error for an empty definition")
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    QueryTemplate
+		wantErr bool
+	}{
+		{"valid", QueryTemplate{Name: "ok", Query: "SELECT ?x WHERE { <#{{.m}}> ?p ?x }", Variables: []Variable{{Name: "m"}}}, false},
+		{"bad name", QueryTemplate{Name: "../x", Query: "ASK {}"}, true},
+		{"empty query", QueryTemplate{Name: "empty", Query: " "}, true},
+		{"syntax error", QueryTemplate{Name: "broken", Query: "SELECT {{.m }", Variables: []Variable{{Name: "m"}}}, true},
+		{"undeclared", QueryTemplate{Name: "undeclared", Query: "SELECT {{.m}}"}, true},
+		{"unused", QueryTemplate{Name: "unused", Query: "ASK {}", Variables: []Variable{{Name: "m"}}}, true},
+		{"duplicate", QueryTemplate{Name: "dup", Query: "SELECT {{.m}}", Variables: []Variable{{Name: "m"}, {Name: "m"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTemplate(&tt.tmpl); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	for i := range BuiltInTemplates {
+		if err := ValidateTemplate(&BuiltInTemplates[i]); err != nil {
+			t.Errorf("Built-in template is invalid: %v", err)
+		}
+	}
+}
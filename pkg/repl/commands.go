@@ -2,7 +2,8 @@
 # Module: pkg/repl/commands.go
 REPL command handlers.
 
-Implements REPL commands like .help, .format, .load, etc.
+Implements REPL commands like .help, .format, .load, etc., and the
+\template shortcut for running query templates.
 
 ## Linked Modules
 - [repl](./repl.go) - REPL core
@@ -30,7 +31,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+
+	"github.com/justin4957/graphfs/pkg/query"
 )
 
 // handleCommand processes REPL commands
@@ -66,6 +70,8 @@ func (r *REPL) handleCommand(line string) error {
 		return r.cmdModules(args)
 	case ".predicates":
 		return r.cmdPredicates(args)
+	case ".prefixes":
+		return r.cmdPrefixes(args)
 	case ".paginate":
 		return r.cmdPaginate(args)
 	case ".pagesize":
@@ -88,10 +94,17 @@ Query Commands:
   CONSTRUCT ...        Execute a SPARQL CONSTRUCT query
   ASK ...             Execute a SPARQL ASK query
   DESCRIBE ...        Execute a SPARQL DESCRIBE query
+  PREFIX ...          Start a query with prefix declarations
+
+Shortcuts:
+  \template                        List query templates
+  \template <name> [var=value ...] Run a template; bare values fill its
+                                   variables in order
 
 REPL Commands:
   .help               Show this help message
-  .format [fmt]       Change output format (table, json, csv)
+  .format [fmt]       Change output format (table, json, csv, tsv, ndjson,
+                      sparql-json)
   .paginate [on|off]  Toggle interactive pagination for large results
   .pagesize [N]       Set page size for pagination (default: 20)
   .load <file>        Load and execute query from file
@@ -103,12 +116,15 @@ REPL Commands:
   .stats              Show graph statistics
   .modules            List all modules (with autocomplete support)
   .predicates         List all predicates (with autocomplete support)
+  .prefixes           List known prefixes
   .exit               Exit REPL (or Ctrl+D)
 
 Query Features:
   - Multi-line queries: Start typing a query and press Enter on empty line to execute
-  - Tab completion: Press Tab for commands, keywords, modules, and predicates
-  - History: Use Up/Down arrows to navigate query history
+  - Tab completion: Press Tab for commands, keywords, modules, predicates,
+    prefixes (after PREFIX), and template names (after \template)
+  - History: Use Up/Down arrows to navigate query history; multi-line
+    queries are recalled as one line
   - Ctrl+R: Reverse search through history
   - Syntax highlighting: Color-coded SPARQL queries for better readability
   - Interactive pagination: Navigate through large result sets page by page
@@ -128,6 +144,7 @@ Examples:
   .pagesize 50
   .load my-query.sparql
   .stats
+  \template find-usages module=pkg/graph/graph.go
 `
 	fmt.Println(help)
 	return nil
@@ -137,17 +154,17 @@ Examples:
 func (r *REPL) cmdFormat(args []string) error {
 	if len(args) == 0 {
 		r.printInfo(fmt.Sprintf("Current format: %s", r.format))
-		r.printInfo("Available formats: table, json, csv")
+		r.printInfo("Available formats: table, json, csv, tsv, ndjson, sparql-json")
 		return nil
 	}
 
 	format := strings.ToLower(args[0])
 	switch format {
-	case "table", "json", "csv":
+	case "table", "json", "csv", "tsv", "ndjson", "sparql-json":
 		r.format = format
 		r.printSuccess(fmt.Sprintf("Output format set to: %s", format))
 	default:
-		return fmt.Errorf("unknown format: %s (available: table, json, csv)", format)
+		return fmt.Errorf("unknown format: %s (available: table, json, csv, tsv, ndjson, sparql-json)", format)
 	}
 
 	return nil
@@ -504,3 +521,70 @@ func (r *REPL) cmdPageSize(args []string) error {
 	r.printSuccess(fmt.Sprintf("Page size set to: %d", size))
 	return nil
 }
+
+// cmdPrefixes lists the prefixes known to completion
+func (r *REPL) cmdPrefixes(args []string) error {
+	declarations := r.completer.prefixDeclarations()
+
+	r.printInfo(fmt.Sprintf("Known Prefixes (%d):", len(declarations)))
+	r.printInfo("====================")
+	for _, declaration := range declarations {
+		fmt.Printf("  PREFIX %s\n", declaration)
+	}
+
+	r.printInfo("\nTip: Type PREFIX and press Tab to insert a declaration")
+	return nil
+}
+
+// handleShortcut processes backslash shortcuts
+func (r *REPL) handleShortcut(line string) error {
+	parts := strings.Fields(line)
+	switch parts[0] {
+	case `\template`:
+		return r.cmdTemplate(parts[1:])
+	default:
+		return fmt.Errorf("unknown shortcut: %s (type .help for available shortcuts)", parts[0])
+	}
+}
+
+// cmdTemplate lists templates, or renders one and executes it
+func (r *REPL) cmdTemplate(args []string) error {
+	if r.config.Templates == nil {
+		return fmt.Errorf("query templates are not available")
+	}
+
+	if len(args) == 0 {
+		templates := r.config.Templates.ListTemplates("")
+		sort.Slice(templates, func(i, j int) bool {
+			return templates[i].Name < templates[j].Name
+		})
+
+		r.printInfo(fmt.Sprintf("Query Templates (%d):", len(templates)))
+		r.printInfo("====================")
+		for _, tmpl := range templates {
+			fmt.Printf("  %-28s %s\n", tmpl.Name, tmpl.Description)
+		}
+		r.printInfo("\nUsage: \\template <name> [var=value ...]")
+		return nil
+	}
+
+	tmpl, err := r.config.Templates.GetTemplate(args[0])
+	if err != nil {
+		return err
+	}
+
+	variables, err := query.BindTemplateArgs(tmpl, args[1:])
+	if err != nil {
+		return err
+	}
+
+	rendered, err := r.config.Templates.Render(tmpl, variables)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(r.highlighter.HighlightQuery(rendered))
+	fmt.Println()
+	r.executeQuery(rendered)
+	return nil
+}
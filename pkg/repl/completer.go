@@ -2,8 +2,9 @@
 # Module: pkg/repl/completer.go
 Autocomplete functionality for REPL.
 
-Provides intelligent autocomplete for SPARQL keywords, module paths,
-predicates, and REPL commands with context-aware suggestions.
+Provides intelligent autocomplete for SPARQL keywords, module paths and
+URIs, predicates found in the store, prefix declarations, template names,
+and REPL commands with context-aware suggestions.
 
 ## Linked Modules
 - [repl](./repl.go) - REPL core
@@ -33,6 +34,7 @@ Completer, NewCompleter
 package repl
 
 import (
+	"sort"
 	"strings"
 	"unicode"
 
@@ -47,6 +49,16 @@ type Completer struct {
 	keywords   []string
 	predicates []string
 	modules    []string
+	prefixes   map[string]string // Prefix name -> namespace IRI
+	templates  []string
+}
+
+// defaultPrefixes are offered for PREFIX declarations in every project
+var defaultPrefixes = map[string]string{
+	"code": "https://schema.codedoc.org/",
+	"rdf":  "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"rdfs": "http://www.w3.org/2000/01/rdf-schema#",
+	"skos": "http://www.w3.org/2004/02/skos/core#",
 }
 
 // NewCompleter creates a new completer
@@ -54,6 +66,10 @@ func NewCompleter(g *graph.Graph) *Completer {
 	c := &Completer{
 		graph:    g,
 		keywords: getSPARQLKeywords(),
+		prefixes: make(map[string]string, len(defaultPrefixes)),
+	}
+	for name, iri := range defaultPrefixes {
+		c.prefixes[name] = iri
 	}
 
 	// Build module and predicate lists
@@ -73,6 +89,9 @@ func (c *Completer) buildCommandList() {
 			readline.PcItem("table"),
 			readline.PcItem("json"),
 			readline.PcItem("csv"),
+			readline.PcItem("tsv"),
+			readline.PcItem("ndjson"),
+			readline.PcItem("sparql-json"),
 		),
 		readline.PcItem(".load"),
 		readline.PcItem(".save"),
@@ -83,6 +102,7 @@ func (c *Completer) buildCommandList() {
 		readline.PcItem(".stats"),
 		readline.PcItem(".modules"),
 		readline.PcItem(".predicates"),
+		readline.PcItem(".prefixes"),
 		readline.PcItem(".exit"),
 		readline.PcItem(".quit"),
 
@@ -115,19 +135,48 @@ func (c *Completer) buildCommandList() {
 	}
 }
 
-// buildModuleList extracts module paths from the graph
+// SetPrefixes adds project prefixes, e.g. from .graphfs/prefixes.ttl, to
+// the defaults and compacts store predicates with them
+func (c *Completer) SetPrefixes(prefixes map[string]string) {
+	for name, iri := range prefixes {
+		c.prefixes[name] = iri
+	}
+	c.buildPredicateList()
+	c.buildCommandList()
+}
+
+// SetTemplates sets the template names completed after \template
+func (c *Completer) SetTemplates(names []string) {
+	c.templates = append([]string(nil), names...)
+	sort.Strings(c.templates)
+}
+
+// buildModuleList extracts module paths and URIs from the graph
 func (c *Completer) buildModuleList() {
 	seen := make(map[string]bool)
+	add := func(uri string) {
+		if !seen[uri] {
+			c.modules = append(c.modules, uri)
+			seen[uri] = true
+		}
+	}
 	for _, mod := range c.graph.Modules {
-		if mod.Name != "" && !seen[mod.Name] {
-			c.modules = append(c.modules, "<#"+mod.Name+">")
-			seen[mod.Name] = true
+		if mod.Name != "" {
+			add("<#" + mod.Name + ">")
+		}
+		if mod.Path != "" {
+			add("<#" + mod.Path + ">")
 		}
-		if mod.Path != "" && !seen[mod.Path] {
-			c.modules = append(c.modules, "<#"+mod.Path+">")
-			seen[mod.Path] = true
+		// Differs from the above when a base IRI is configured
+		if mod.URI != "" {
+			if !strings.HasPrefix(mod.URI, "<") {
+				add("<" + mod.URI + ">")
+			} else {
+				add(mod.URI)
+			}
 		}
 	}
+	sort.Strings(c.modules)
 }
 
 // buildPredicateList extracts common predicates
@@ -161,7 +210,56 @@ func (c *Completer) buildPredicateList() {
 		"rdf:type",
 	}
 
-	c.predicates = predicates
+	// Predicates actually used in the graph, compacted where a prefix fits
+	seen := make(map[string]bool, len(predicates))
+	for _, pred := range predicates {
+		seen[pred] = true
+	}
+	var found []string
+	if c.graph.Store != nil {
+		for _, iri := range c.graph.Store.Predicates() {
+			if strings.ContainsAny(iri, " \"<>") {
+				continue
+			}
+			pred := c.compact(iri)
+			if !seen[pred] {
+				found = append(found, pred)
+				seen[pred] = true
+			}
+		}
+	}
+	sort.Strings(found)
+
+	c.predicates = append(predicates, found...)
+}
+
+// compact returns iri as a prefixed name when a prefix covers it with a
+// plain local name, and in angle brackets otherwise
+func (c *Completer) compact(iri string) string {
+	best := ""
+	for name, namespace := range c.prefixes {
+		if !strings.HasPrefix(iri, namespace) || len(namespace) <= len(c.prefixes[best]) {
+			continue
+		}
+		local := iri[len(namespace):]
+		if local != "" && !strings.ContainsAny(local, "/#") {
+			best = name
+		}
+	}
+	if best == "" {
+		return "<" + iri + ">"
+	}
+	return best + ":" + iri[len(c.prefixes[best]):]
+}
+
+// prefixDeclarations returns the "name: <iri>" completions for PREFIX
+func (c *Completer) prefixDeclarations() []string {
+	declarations := make([]string, 0, len(c.prefixes))
+	for name, iri := range c.prefixes {
+		declarations = append(declarations, name+": <"+iri+">")
+	}
+	sort.Strings(declarations)
+	return declarations
 }
 
 // GetCompleter returns a readline completer
@@ -195,33 +293,54 @@ func (cc *contextCompleter) Do(line []rune, pos int) (newLine [][]rune, length i
 		lastWord = words[len(words)-1]
 	}
 
+	// The word before the one being completed
+	previous := ""
+	if lastWord == "" {
+		previous = words[len(words)-1]
+	} else if len(words) > 1 {
+		previous = words[len(words)-2]
+	}
+
 	// Determine what to suggest based on context
 	var suggestions []string
 
 	// Check what context we're in
-	if strings.HasPrefix(lastWord, ".") {
+	if previous == `\template` && len(words) <= 2 {
+		// Template name completion
+		suggestions = cc.completer.templates
+	} else if strings.EqualFold(previous, "PREFIX") {
+		// Prefix declaration completion
+		suggestions = cc.completer.prefixDeclarations()
+	} else if strings.HasPrefix(lastWord, `\`) {
+		// Shortcut completion
+		suggestions = []string{`\template`}
+	} else if strings.HasPrefix(lastWord, ".") {
 		// Command completion
 		suggestions = []string{
 			".help", ".format", ".load", ".save", ".history",
 			".clear", ".schema", ".examples", ".stats",
-			".modules", ".predicates", ".exit", ".quit",
+			".modules", ".predicates", ".prefixes", ".exit", ".quit",
 		}
 	} else if strings.HasPrefix(lastWord, "<#") || strings.HasPrefix(lastWord, "<") {
 		// Module or predicate completion
 		suggestions = append(suggestions, cc.completer.predicates...)
 		suggestions = append(suggestions, cc.completer.modules...)
-	} else if strings.HasPrefix(lastWord, "code:") {
-		// Code ontology completion
+	} else if name, _, ok := strings.Cut(lastWord, ":"); ok && cc.completer.prefixes[name] != "" {
+		// Prefixed name completion
 		for _, pred := range cc.completer.predicates {
-			if strings.HasPrefix(pred, "code:") {
+			if strings.HasPrefix(pred, name+":") {
 				suggestions = append(suggestions, pred)
 			}
 		}
 	} else {
 		// Keyword completion
 		suggestions = cc.completer.keywords
-		// Also add commands and predicates
+		// Also add commands, prefixes and predicates
 		suggestions = append(suggestions, ".help", ".format", ".modules", ".predicates")
+		for name := range cc.completer.prefixes {
+			suggestions = append(suggestions, name+":")
+		}
+		sort.Strings(suggestions[len(cc.completer.keywords):])
 	}
 
 	// Filter suggestions by prefix
@@ -275,6 +394,11 @@ func (c *Completer) GetPredicates() []string {
 	return c.predicates
 }
 
+// GetPrefixes returns the known prefixes, by name
+func (c *Completer) GetPrefixes() map[string]string {
+	return c.prefixes
+}
+
 // GetKeywords returns SPARQL keywords
 func (c *Completer) GetKeywords() []string {
 	return c.keywords
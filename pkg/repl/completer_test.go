@@ -201,3 +201,47 @@ func createTestGraph(t *testing.T) *graph.Graph {
 		Store:   tripleStore,
 	}
 }
+
+func TestCompleterContexts(t *testing.T) {
+	g := createTestGraph(t)
+	g.Modules["test/module1.go"].URI = "<https://graph.example.com/repo/test/module1.go>"
+	g.Store.Add("<#test/module1.go>", "https://schema.codedoc.org/ownedBy", "\"@team\"")
+	g.Store.Add("<#test/module1.go>", "https://example.com/custom/rel", "<#test/module2.go>")
+
+	completer := NewCompleter(g)
+	completer.SetPrefixes(map[string]string{"ex": "https://example.com/custom/"})
+	completer.SetTemplates([]string{"find-usages", "find-dependencies", "circular-deps"})
+	auto := completer.GetAutoCompleteFunc()
+
+	complete := func(line string) []string {
+		candidates, _ := auto.Do([]rune(line), len(line))
+		var completions []string
+		for _, candidate := range candidates {
+			completions = append(completions, string(candidate))
+		}
+		return completions
+	}
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`\template find-u`, "sages"},                               // Template names
+		{"PREFIX co", "de: <https://schema.codedoc.org/>"},          // Prefix declarations
+		{"?m code:own", "edBy"},                                     // Store predicates, compacted
+		{"?m ex:r", "el"},                                           // Project prefixes
+		{"?m <https://graph.example.com/repo/test/m", "odule1.go>"}, // Module URIs
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			completions := complete(tt.line)
+			found := false
+			for _, completion := range completions {
+				found = found || completion == tt.want
+			}
+			if !found {
+				t.Errorf("Expected completion %q for %q, got %q", tt.want, tt.line, completions)
+			}
+		})
+	}
+}
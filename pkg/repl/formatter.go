@@ -51,6 +51,8 @@ func (r *REPL) formatResult(result *query.QueryResult) error {
 		return r.formatJSON(result)
 	case "csv":
 		return r.formatCSV(result)
+	case query.FormatTSV, query.FormatNDJSON, query.FormatSPARQLJSON:
+		return query.WriteResult(r.rl.Stdout(), result, r.format)
 	default:
 		return fmt.Errorf("unknown format: %s", r.format)
 	}
@@ -3,7 +3,8 @@
 Interactive REPL for GraphFS queries.
 
 Provides an interactive Read-Eval-Print Loop for exploring the knowledge graph
-with SPARQL queries, syntax highlighting, and tab completion.
+with SPARQL queries, query templates, syntax highlighting, and tab completion.
+Multi-line queries are kept in the history as one entry.
 
 ## Linked Modules
 - [../query](../query/executor.go) - Query executor
@@ -33,11 +34,13 @@ REPL, Config, New
 package repl
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/chzyer/readline"
@@ -51,8 +54,10 @@ type Config struct {
 	HistoryFile string
 	Prompt      string
 	NoColor     bool
-	PageSize    int  // Number of results per page (default: 20)
-	Paginate    bool // Enable interactive pagination (default: true)
+	PageSize    int                    // Number of results per page (default: 20)
+	Paginate    bool                   // Enable interactive pagination (default: true)
+	Prefixes    map[string]string      // Project prefixes offered for completion
+	Templates   *query.TemplateManager // Templates run with \template (optional)
 }
 
 // REPL is the interactive Read-Eval-Print Loop
@@ -65,6 +70,7 @@ type REPL struct {
 	history     []string
 	completer   *Completer
 	highlighter *Highlighter
+	closeOnce   sync.Once
 }
 
 // New creates a new REPL instance
@@ -84,11 +90,14 @@ func New(executor *query.Executor, g *graph.Graph, config *Config) (*REPL, error
 	}
 
 	// Configure readline
+	// History is saved per input rather than per line, so a multi-line
+	// query is recalled whole
 	rlConfig := &readline.Config{
-		Prompt:          config.Prompt,
-		HistoryFile:     config.HistoryFile,
-		InterruptPrompt: "^C",
-		EOFPrompt:       "exit",
+		Prompt:                 config.Prompt,
+		HistoryFile:            config.HistoryFile,
+		InterruptPrompt:        "^C",
+		EOFPrompt:              "exit",
+		DisableAutoSaveHistory: true,
 	}
 
 	rl, err := readline.NewEx(rlConfig)
@@ -98,6 +107,14 @@ func New(executor *query.Executor, g *graph.Graph, config *Config) (*REPL, error
 
 	// Create completer and highlighter
 	completer := NewCompleter(g)
+	completer.SetPrefixes(config.Prefixes)
+	if config.Templates != nil {
+		var names []string
+		for _, tmpl := range config.Templates.ListTemplates("") {
+			names = append(names, tmpl.Name)
+		}
+		completer.SetTemplates(names)
+	}
 	highlighter := NewHighlighter(config.NoColor)
 
 	repl := &REPL{
@@ -119,7 +136,15 @@ func New(executor *query.Executor, g *graph.Graph, config *Config) (*REPL, error
 
 // Run starts the REPL loop
 func (r *REPL) Run() error {
-	defer r.rl.Close()
+	return r.RunContext(context.Background())
+}
+
+// RunContext starts the REPL loop and ends it like EOF when ctx is done,
+// releasing a pending prompt and saving the history
+func (r *REPL) RunContext(ctx context.Context) error {
+	defer r.close()
+	stop := context.AfterFunc(ctx, r.close)
+	defer stop()
 
 	r.printWelcome()
 
@@ -138,6 +163,9 @@ func (r *REPL) Run() error {
 			line, err = r.rl.Readline()
 		}
 
+		if ctx.Err() != nil {
+			break
+		}
 		if err != nil {
 			if err == readline.ErrInterrupt {
 				if inMultiline {
@@ -164,6 +192,7 @@ func (r *REPL) Run() error {
 				multilineQuery.Reset()
 				inMultiline = false
 				r.rl.SetPrompt(r.config.Prompt)
+				r.saveHistory(queryStr)
 				r.executeQuery(queryStr)
 			}
 			continue
@@ -175,6 +204,7 @@ func (r *REPL) Run() error {
 				r.printError("Cannot use commands in multiline mode. Press Enter on empty line to execute query.")
 				continue
 			}
+			r.saveHistory(line)
 			if err := r.handleCommand(line); err != nil {
 				if err == io.EOF {
 					break
@@ -184,11 +214,21 @@ func (r *REPL) Run() error {
 			continue
 		}
 
+		// Handle shortcuts
+		if strings.HasPrefix(line, `\`) {
+			if inMultiline {
+				r.printError("Cannot use shortcuts in multiline mode. Press Enter on empty line to execute query.")
+				continue
+			}
+			r.saveHistory(line)
+			if err := r.handleShortcut(line); err != nil {
+				r.printError(err.Error())
+			}
+			continue
+		}
+
 		// Check if starting multiline query
-		if !inMultiline && (strings.HasPrefix(strings.ToUpper(line), "SELECT") ||
-			strings.HasPrefix(strings.ToUpper(line), "CONSTRUCT") ||
-			strings.HasPrefix(strings.ToUpper(line), "ASK") ||
-			strings.HasPrefix(strings.ToUpper(line), "DESCRIBE")) {
+		if !inMultiline && startsQuery(line) {
 			inMultiline = true
 			multilineQuery.WriteString(line)
 			multilineQuery.WriteString("\n")
@@ -203,6 +243,7 @@ func (r *REPL) Run() error {
 		}
 
 		// Single line query
+		r.saveHistory(line)
 		r.executeQuery(line)
 	}
 
@@ -210,6 +251,33 @@ func (r *REPL) Run() error {
 	return nil
 }
 
+// startsQuery reports whether line begins a SPARQL query, which is read
+// until an empty line
+func startsQuery(line string) bool {
+	upper := strings.ToUpper(line)
+	for _, keyword := range []string{"SELECT", "CONSTRUCT", "ASK", "DESCRIBE", "PREFIX", "BASE"} {
+		if strings.HasPrefix(upper, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveHistory adds an input to the readline history, joining the lines of
+// a multi-line query
+func (r *REPL) saveHistory(input string) {
+	var lines []string
+	for _, line := range strings.Split(input, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	_ = r.rl.SaveHistory(strings.Join(lines, " "))
+}
+
 // executeQuery executes a SPARQL query and displays results
 func (r *REPL) executeQuery(queryStr string) {
 	queryStr = strings.TrimSpace(queryStr)
@@ -367,6 +435,7 @@ func (r *REPL) printWelcome() {
 		fmt.Println("Features:")
 		fmt.Println("  - Tab completion for commands, keywords, modules, and predicates")
 		fmt.Println("  - Multi-line query editing")
+		fmt.Println("  - Query templates with \\template <name> [var=value ...]")
 		fmt.Println("  - Query history with Up/Down arrows and Ctrl+R search")
 		fmt.Println("  - Syntax highlighting")
 		fmt.Println()
@@ -380,12 +449,20 @@ func (r *REPL) printWelcome() {
 		green.Println("Features:")
 		fmt.Println("  - Tab completion for commands, keywords, modules, and predicates")
 		fmt.Println("  - Multi-line query editing")
+		fmt.Println("  - Query templates with \\template <name> [var=value ...]")
 		fmt.Println("  - Query history with Up/Down arrows and Ctrl+R search")
 		fmt.Println("  - Syntax highlighting")
 		fmt.Println()
 	}
 }
 
+// close closes the terminal once
+func (r *REPL) close() {
+	r.closeOnce.Do(func() {
+		r.rl.Close()
+	})
+}
+
 // printGoodbye displays the goodbye message
 func (r *REPL) printGoodbye() {
 	fmt.Println("\nGoodbye!")
@@ -0,0 +1,42 @@
+/*
+# Module: pkg/repl/repl_test.go
+Tests for the REPL loop.
+
+## Linked Modules
+- [repl](./repl.go) - REPL core
+
+## Tags
+repl, test
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#repl_test.go> a code:Module ;
+    code:name "pkg/repl/repl_test.go" ;
+    code:description "Tests for the REPL loop" ;
+    code:language "go" ;
+    code:layer "repl" ;
+    code:linksTo <./repl.go> ;
+    code:tags "repl", "test" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package repl
+
+import (
+	"testing"
+)
+
+func TestStartsQuery(t *testing.T) {
+	for line, want := range map[string]bool{
+		"SELECT ?s WHERE {":                          true,
+		"prefix code: <https://schema.codedoc.org/>": true,
+		"ASK { ?s ?p ?o }":                           true,
+		"?s ?p ?o":                                   false,
+	} {
+		if got := startsQuery(line); got != want {
+			t.Errorf("startsQuery(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+/*
+# Module: pkg/report/bundle.go
+Audit report bundles.
+
+Writes a timestamped folder holding everything an architecture review or a
+compliance audit usually asks for: generated module docs, an HTML dependency
+graph, the rules report, an SBOM, a metrics snapshot and a manifest listing
+every artifact with its SHA-256 checksum.
+
+## Linked Modules
+- [sbom](./sbom.go) - CycloneDX SBOM generation
+- [../dashboard](../dashboard/dashboard.go) - Metrics snapshot
+- [../docs](../docs/markdown.go) - Documentation generation
+- [../rules](../rules/reporter.go) - Rules report
+- [../viz](../viz/mermaid.go) - Graph diagrams
+
+## Tags
+report, audit, bundle, compliance
+
+## Exports
+BundleOptions, Manifest, Artifact, WriteBundle, ManifestFile
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#bundle.go> a code:Module ;
+    code:name "pkg/report/bundle.go" ;
+    code:description "Audit report bundles" ;
+    code:language "go" ;
+    code:layer "report" ;
+    code:linksTo <./sbom.go>, <../dashboard/dashboard.go>, <../docs/markdown.go>,
+                 <../rules/reporter.go>, <../viz/mermaid.go> ;
+    code:exports <#BundleOptions>, <#Manifest>, <#Artifact>, <#WriteBundle>, <#ManifestFile> ;
+    code:tags "report", "audit", "bundle", "compliance" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/dashboard"
+	"github.com/justin4957/graphfs/pkg/docs"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/rules"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/justin4957/graphfs/pkg/viz"
+)
+
+// ManifestFile is the manifest's name inside a bundle
+const ManifestFile = "manifest.json"
+
+// BundleOptions configures WriteBundle
+type BundleOptions struct {
+	OutputDir    string              // Parent directory of the bundle folder
+	Tool         string              // Tool name and version recorded in the manifest
+	Files        []*scanner.FileInfo // Scanned files for docs coverage (optional)
+	ShadowStats  *shadow.IndexStats  // Shadow index statistics (optional)
+	Rules        []*rules.Rule       // Rules to validate (built-in rules if empty)
+	RulesSource  string              // Description of where rules came from
+	Preprocessor *query.Preprocessor // Shared prefixes and macros for rules (optional)
+	Now          time.Time           // Bundle timestamp (default: now)
+}
+
+// Manifest describes a bundle's contents
+type Manifest struct {
+	GeneratedAt time.Time  `json:"generated_at"`
+	Root        string     `json:"root"`
+	Tool        string     `json:"tool,omitempty"`
+	Modules     int        `json:"modules"`
+	RulesPassed bool       `json:"rules_passed"`
+	Artifacts   []Artifact `json:"artifacts"`
+}
+
+// Artifact is a file in the bundle
+type Artifact struct {
+	Path        string `json:"path"` // Relative to the bundle folder
+	Kind        string `json:"kind"` // docs, graph, rules, sbom or metrics
+	Description string `json:"description"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// bundleWriter accumulates artifacts while writing a bundle
+type bundleWriter struct {
+	dir       string
+	artifacts []Artifact
+}
+
+// WriteBundle writes a report bundle for the graph into a new timestamped
+// folder under opts.OutputDir and returns the folder path and its manifest
+func WriteBundle(g *graph.Graph, opts BundleOptions) (string, *Manifest, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	dir := filepath.Join(opts.OutputDir, "graphfs-report-"+opts.Now.UTC().Format("20060102-150405"))
+	if _, err := os.Stat(dir); err == nil {
+		return "", nil, fmt.Errorf("bundle %s already exists", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	manifest, err := writeBundle(g, dir, opts)
+	if err != nil {
+		// Don't leave a partial bundle that looks complete
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	return dir, manifest, nil
+}
+
+// writeBundle writes the artifacts and manifest into dir
+func writeBundle(g *graph.Graph, dir string, opts BundleOptions) (*Manifest, error) {
+	w := &bundleWriter{dir: dir}
+	manifest := &Manifest{
+		GeneratedAt: opts.Now,
+		Root:        g.Root,
+		Tool:        opts.Tool,
+		Modules:     len(g.Modules),
+	}
+
+	// Module documentation
+	docsDir := filepath.Join(dir, "docs")
+	if err := docs.GenerateDocs(g, docs.DocsOptions{OutputDir: docsDir, Format: docs.DocsMultiFile, IncludeGraph: true}); err != nil {
+		return nil, fmt.Errorf("failed to generate docs: %w", err)
+	}
+	if err := w.addTree("docs", "docs", "Module documentation"); err != nil {
+		return nil, err
+	}
+
+	// Dependency graph
+	if err := w.writeGraphHTML(g, opts.Now); err != nil {
+		return nil, err
+	}
+
+	// Rules report
+	ruleList, rulesSource := opts.Rules, opts.RulesSource
+	if len(ruleList) == 0 {
+		ruleList, rulesSource = rules.GetBuiltInRules(), "built-in"
+	}
+	engine := rules.NewEngine(g)
+	engine.SetPreprocessor(opts.Preprocessor)
+	result, err := engine.Validate(ruleList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate rules: %w", err)
+	}
+	manifest.RulesPassed = result.Success()
+	if err := w.write("rules.json", "rules", "Rules report ("+rulesSource+" rules)", []byte(rules.NewReporter(rules.FormatJSON).Report(result))); err != nil {
+		return nil, err
+	}
+	if err := w.write("rules.xml", "rules", "Rules report in JUnit format", []byte(rules.NewReporter(rules.FormatJUnit).Report(result))); err != nil {
+		return nil, err
+	}
+
+	// SBOM
+	sbom, err := GenerateSBOM(g, opts.Tool, opts.Now)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.write("sbom.cdx.json", "sbom", "CycloneDX software bill of materials", sbom); err != nil {
+		return nil, err
+	}
+
+	// Metrics snapshot
+	d, err := dashboard.Collect(g, dashboard.Options{
+		Files:        opts.Files,
+		ShadowStats:  opts.ShadowStats,
+		Rules:        ruleList,
+		RulesSource:  rulesSource,
+		Preprocessor: opts.Preprocessor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect metrics: %w", err)
+	}
+	for _, format := range []dashboard.ReportFormat{dashboard.FormatJSON, dashboard.FormatMarkdown} {
+		content, err := dashboard.FormatDashboard(d, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format metrics: %w", err)
+		}
+		if err := w.write("metrics."+string(format), "metrics", "Metrics snapshot", []byte(content)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Manifest last, so it covers every artifact
+	sort.Slice(w.artifacts, func(i, j int) bool {
+		return w.artifacts[i].Path < w.artifacts[j].Path
+	})
+	manifest.Artifacts = w.artifacts
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// write stores a file in the bundle and records it as an artifact
+func (w *bundleWriter) write(name, kind, description string, data []byte) error {
+	if err := os.WriteFile(filepath.Join(w.dir, name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return w.add(name, kind, description)
+}
+
+// addTree records every file below a bundle subdirectory as an artifact
+func (w *bundleWriter) addTree(subdir, kind, description string) error {
+	return filepath.Walk(filepath.Join(w.dir, subdir), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(w.dir, path)
+		if err != nil {
+			return err
+		}
+		return w.add(rel, kind, description)
+	})
+}
+
+// add checksums a bundle file and records it as an artifact
+func (w *bundleWriter) add(rel, kind, description string) error {
+	f, err := os.Open(filepath.Join(w.dir, rel))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("failed to checksum %s: %w", rel, err)
+	}
+
+	w.artifacts = append(w.artifacts, Artifact{
+		Path:        filepath.ToSlash(rel),
+		Kind:        kind,
+		Description: description,
+		Size:        size,
+		SHA256:      hex.EncodeToString(h.Sum(nil)),
+	})
+	return nil
+}
+
+// graphPage renders a Mermaid diagram in a standalone HTML page
+var graphPage = template.Must(template.New("graph").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<script src="https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.min.js"></script>
+<style>body { font-family: sans-serif; margin: 2em; } pre.mermaid { background: #fff; }</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Modules}} modules, generated {{.GeneratedAt}}. The diagram source is kept below if it cannot be rendered offline.</p>
+<pre class="mermaid">
+{{.Diagram}}
+</pre>
+<script>mermaid.initialize({ startOnLoad: true, maxTextSize: 10000000 });</script>
+</body>
+</html>
+`))
+
+// writeGraphHTML writes the dependency graph as graph.html
+func (w *bundleWriter) writeGraphHTML(g *graph.Graph, now time.Time) error {
+	diagram, err := viz.GenerateMermaid(g, viz.MermaidOptions{
+		Type:         viz.MermaidFlowchart,
+		Direction:    "LR",
+		ColorBy:      "layer",
+		UseSubgraphs: true,
+		Sampling:     &viz.SamplingOptions{Strategy: viz.SampleTopN},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate graph: %w", err)
+	}
+
+	var b strings.Builder
+	err = graphPage.Execute(&b, map[string]any{
+		"Title":       filepath.Base(g.Root) + " dependency graph",
+		"Modules":     len(g.Modules),
+		"GeneratedAt": now.Format(time.RFC3339),
+		"Diagram":     diagram,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render graph page: %w", err)
+	}
+
+	return w.write("graph.html", "graph", "Interactive dependency graph", []byte(b.String()))
+}
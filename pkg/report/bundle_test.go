@@ -0,0 +1,102 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestWriteBundle(t *testing.T) {
+	root, err := filepath.Abs("../../examples/minimal-app")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	g, err := graph.NewBuilder().Build(root, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	outDir := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	dir, manifest, err := WriteBundle(g, BundleOptions{OutputDir: outDir, Tool: "graphfs test", Now: now})
+	if err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+
+	if filepath.Base(dir) != "graphfs-report-20260102-030405" {
+		t.Errorf("Bundle folder = %s, want timestamped name", filepath.Base(dir))
+	}
+
+	kinds := make(map[string]bool)
+	for _, artifact := range manifest.Artifacts {
+		kinds[artifact.Kind] = true
+
+		data, err := os.ReadFile(filepath.Join(dir, artifact.Path))
+		if err != nil {
+			t.Errorf("Artifact %s missing: %v", artifact.Path, err)
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != artifact.SHA256 {
+			t.Errorf("Artifact %s checksum mismatch", artifact.Path)
+		}
+	}
+	for _, kind := range []string{"docs", "graph", "rules", "sbom", "metrics"} {
+		if !kinds[kind] {
+			t.Errorf("Expected a %s artifact", kind)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		t.Fatalf("Manifest missing: %v", err)
+	}
+	var written Manifest
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Manifest is not valid JSON: %v", err)
+	}
+	if len(written.Artifacts) != len(manifest.Artifacts) {
+		t.Errorf("Manifest lists %d artifacts, want %d", len(written.Artifacts), len(manifest.Artifacts))
+	}
+
+	if _, _, err := WriteBundle(g, BundleOptions{OutputDir: outDir, Now: now}); err == nil {
+		t.Error("Expected error when the bundle folder already exists")
+	}
+}
+
+func TestGenerateSBOM(t *testing.T) {
+	g := graph.NewGraph(t.TempDir(), nil)
+	api := graph.NewModule("api.go", "<#api.go>")
+	api.Language = "go"
+	api.AddDependency("store.go")
+	api.AddDependency("missing.go")
+	g.AddModule(api)
+	g.AddModule(graph.NewModule("store.go", "<#store.go>"))
+
+	data, err := GenerateSBOM(g, "graphfs test", time.Now())
+	if err != nil {
+		t.Fatalf("GenerateSBOM() error = %v", err)
+	}
+
+	var bom cdxBOM
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatalf("SBOM is not valid JSON: %v", err)
+	}
+	if bom.BOMFormat != "CycloneDX" || len(bom.Components) != 2 {
+		t.Errorf("Unexpected SBOM: format %s, %d components", bom.BOMFormat, len(bom.Components))
+	}
+	for _, dep := range bom.Dependencies {
+		if dep.Ref == "api.go" && (len(dep.DependsOn) != 1 || dep.DependsOn[0] != "store.go") {
+			t.Errorf("api.go dependsOn = %v, want only modules in the BOM", dep.DependsOn)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+# Module: pkg/report/sbom.go
+CycloneDX SBOM generation.
+
+Describes the scanned codebase as a CycloneDX 1.5 bill of materials: one file
+component per module with its SHA-256 hash, language and layer, and the
+module dependency graph as CycloneDX dependencies.
+
+## Linked Modules
+- [bundle](./bundle.go) - Audit report bundles
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+report, sbom, cyclonedx, compliance
+
+## Exports
+GenerateSBOM
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#sbom.go> a code:Module ;
+    code:name "pkg/report/sbom.go" ;
+    code:description "CycloneDX SBOM generation" ;
+    code:language "go" ;
+    code:layer "report" ;
+    code:linksTo <./bundle.go>, <../graph/graph.go> ;
+    code:exports <#GenerateSBOM> ;
+    code:tags "report", "sbom", "cyclonedx", "compliance" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package report
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// cdxBOM is the subset of the CycloneDX 1.5 JSON format GraphFS emits
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `json:"timestamp"`
+	Tools     *cdxTools     `json:"tools,omitempty"`
+	Component *cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type        string        `json:"type"`
+	BOMRef      string        `json:"bom-ref,omitempty"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Hashes      []cdxHash     `json:"hashes,omitempty"`
+	Properties  []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// GenerateSBOM returns a CycloneDX JSON SBOM for the graph's modules. Files
+// that cannot be read are listed without a hash.
+func GenerateSBOM(g *graph.Graph, tool string, now time.Time) ([]byte, error) {
+	serial, err := newUUID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SBOM serial number: %w", err)
+	}
+
+	project := filepath.Base(g.Root)
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + serial,
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: now.UTC().Format(time.RFC3339),
+			Component: &cdxComponent{Type: "application", BOMRef: project, Name: project},
+		},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+	if tool != "" {
+		bom.Metadata.Tools = &cdxTools{Components: []cdxComponent{{Type: "application", Name: tool}}}
+	}
+
+	paths := make([]string, 0, len(g.Modules))
+	for path := range g.Modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	rootDependency := cdxDependency{Ref: project}
+	for _, path := range paths {
+		module := g.Modules[path]
+		component := cdxComponent{
+			Type:        "file",
+			BOMRef:      path,
+			Name:        path,
+			Description: module.Description,
+		}
+		if hash, err := hashFile(filepath.Join(g.Root, path)); err == nil {
+			component.Hashes = []cdxHash{{Alg: "SHA-256", Content: hash}}
+		}
+		if module.Language != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: "graphfs:language", Value: module.Language})
+		}
+		if module.Layer != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: "graphfs:layer", Value: module.Layer})
+		}
+		bom.Components = append(bom.Components, component)
+		rootDependency.DependsOn = append(rootDependency.DependsOn, path)
+
+		// Only dependencies on modules in the BOM are valid references
+		var deps []string
+		for _, dep := range module.Dependencies {
+			if _, ok := g.Modules[dep]; ok {
+				deps = append(deps, dep)
+			}
+		}
+		sort.Strings(deps)
+		bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: path, DependsOn: deps})
+	}
+	bom.Dependencies = append([]cdxDependency{rootDependency}, bom.Dependencies...)
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize SBOM: %w", err)
+	}
+	return data, nil
+}
+
+// hashFile returns the hex SHA-256 of a file
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
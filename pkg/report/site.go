@@ -0,0 +1,544 @@
+/*
+# Module: pkg/report/site.go
+Static HTML report sites.
+
+Writes a standalone multi-page HTML report that can be published from CI as
+a build artifact: an overview, a page per module with its documentation and
+a Mermaid diagram of its neighbourhood, the full dependency graph, rule
+violations, dead code, and metrics with SVG charts. Pages link to each other
+with relative paths and need no server.
+
+## Linked Modules
+- [bundle](./bundle.go) - Audit report bundles
+- [../dashboard](../dashboard/dashboard.go) - Metrics snapshot
+- [../analysis](../analysis/metrics.go) - Structural metrics and dead code
+- [../rules](../rules/engine.go) - Rule validation
+- [../viz](../viz/mermaid.go) - Graph diagrams
+
+## Tags
+report, html, static-site, ci
+
+## Exports
+SiteOptions, SiteSummary, WriteSite
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#site.go> a code:Module ;
+    code:name "pkg/report/site.go" ;
+    code:description "Static HTML report sites" ;
+    code:language "go" ;
+    code:layer "report" ;
+    code:linksTo <./bundle.go>, <../dashboard/dashboard.go>, <../analysis/metrics.go>,
+                 <../rules/engine.go>, <../viz/mermaid.go> ;
+    code:exports <#SiteOptions>, <#SiteSummary>, <#WriteSite> ;
+    code:tags "report", "html", "static-site", "ci" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package report
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/dashboard"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/rules"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
+	"github.com/justin4957/graphfs/pkg/viz"
+)
+
+// siteModulesDir holds the module pages of a site
+const siteModulesDir = "modules"
+
+// SiteOptions configures WriteSite
+type SiteOptions struct {
+	OutputDir      string              // Directory to write the site into
+	Tool           string              // Tool name and version shown in page footers
+	Files          []*scanner.FileInfo // Scanned files for docs coverage (optional)
+	ShadowStats    *shadow.IndexStats  // Shadow index statistics (optional)
+	Rules          []*rules.Rule       // Rules to validate (built-in rules if empty)
+	RulesSource    string              // Description of where rules came from
+	Preprocessor   *query.Preprocessor // Shared prefixes and macros for rules (optional)
+	DeadCodeCutoff float64             // Minimum dead code confidence listed (default: 0.5)
+	Now            time.Time           // Generation time (default: now)
+}
+
+// SiteSummary describes a written site
+type SiteSummary struct {
+	Pages       int  // HTML pages written
+	Modules     int  // Modules documented
+	Violations  int  // Rule violations listed
+	DeadModules int  // Unreferenced modules listed
+	RulesPassed bool // Whether every rule passed
+}
+
+// siteData is shared by every page of a site
+type siteData struct {
+	Project     string
+	Tool        string
+	GeneratedAt string
+	Dashboard   *dashboard.Dashboard
+	Rules       *rules.ValidationResult
+	RulesSource string
+	DeadCode    *analysis.DeadCodeAnalysis
+	Metrics     []*analysis.StructuralMetrics
+	Modules     []*siteModule
+	LayerChart  template.HTML
+	FanInChart  template.HTML
+	FanOutChart template.HTML
+	Diagram     string
+}
+
+// siteModule is a module with the link to its page
+type siteModule struct {
+	*graph.Module
+	Page       string
+	Metrics    *analysis.StructuralMetrics
+	Diagram    string
+	Violations []rules.Violation
+}
+
+// page is the data a page template is executed with
+type page struct {
+	*siteData
+	Title  string
+	Base   string // Relative path to the site root ("" or "../")
+	Module *siteModule
+}
+
+// WriteSite writes a static HTML report for the graph into opts.OutputDir,
+// replacing the pages of an earlier report there
+func WriteSite(g *graph.Graph, opts SiteOptions) (*SiteSummary, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.DeadCodeCutoff <= 0 {
+		opts.DeadCodeCutoff = 0.5
+	}
+
+	data, err := collectSiteData(g, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Module pages of removed modules must not linger
+	if err := os.RemoveAll(filepath.Join(opts.OutputDir, siteModulesDir)); err != nil {
+		return nil, fmt.Errorf("failed to clear module pages: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(opts.OutputDir, siteModulesDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	summary := &SiteSummary{
+		Modules:     len(data.Modules),
+		Violations:  len(data.Rules.Violations),
+		DeadModules: len(data.DeadCode.UnreferencedModules),
+		RulesPassed: data.Rules.Success(),
+	}
+	write := func(name, title, base string, module *siteModule) error {
+		return writePage(filepath.Join(opts.OutputDir, name), name, &page{siteData: data, Title: title, Base: base, Module: module}, summary)
+	}
+
+	pages := []struct{ name, title string }{
+		{"index.html", "Overview"},
+		{"modules.html", "Modules"},
+		{"graph.html", "Dependency Graph"},
+		{"rules.html", "Rules"},
+		{"deadcode.html", "Dead Code"},
+		{"metrics.html", "Metrics"},
+	}
+	for _, p := range pages {
+		if err := write(p.name, p.title, "", nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, module := range data.Modules {
+		if err := write(module.Page, module.Path, "../", module); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// collectSiteData runs the analyses shown by the report
+func collectSiteData(g *graph.Graph, opts SiteOptions) (*siteData, error) {
+	data := &siteData{
+		Project:     filepath.Base(g.Root),
+		Tool:        opts.Tool,
+		GeneratedAt: opts.Now.Format(time.RFC3339),
+	}
+
+	ruleList, rulesSource := opts.Rules, opts.RulesSource
+	if len(ruleList) == 0 {
+		ruleList, rulesSource = rules.GetBuiltInRules(), "built-in"
+	}
+	d, err := dashboard.Collect(g, dashboard.Options{
+		Files:        opts.Files,
+		ShadowStats:  opts.ShadowStats,
+		Rules:        ruleList,
+		RulesSource:  rulesSource,
+		Preprocessor: opts.Preprocessor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect metrics: %w", err)
+	}
+	data.Dashboard = d
+
+	engine := rules.NewEngine(g)
+	engine.SetPreprocessor(opts.Preprocessor)
+	data.Rules, err = engine.Validate(ruleList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate rules: %w", err)
+	}
+	data.RulesSource = rulesSource
+
+	data.DeadCode, err = analysis.DetectDeadCode(g, analysis.DeadCodeOptions{MinConfidence: opts.DeadCodeCutoff})
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect dead code: %w", err)
+	}
+
+	data.Metrics = analysis.ComputeMetrics(g).Modules
+	metricsByPath := make(map[string]*analysis.StructuralMetrics, len(data.Metrics))
+	for _, m := range data.Metrics {
+		metricsByPath[m.Path] = m
+	}
+
+	violationsByPath := make(map[string][]rules.Violation)
+	for _, v := range data.Rules.Violations {
+		if v.Module != nil {
+			violationsByPath[v.Module.Path] = append(violationsByPath[v.Module.Path], v)
+		}
+	}
+
+	data.Diagram, err = viz.GenerateMermaid(g, viz.MermaidOptions{
+		Type:         viz.MermaidFlowchart,
+		Direction:    "LR",
+		ColorBy:      "layer",
+		UseSubgraphs: true,
+		Sampling:     &viz.SamplingOptions{Strategy: viz.SampleTopN},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate graph: %w", err)
+	}
+
+	paths := make([]string, 0, len(g.Modules))
+	for path := range g.Modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	pages := make(map[string]string, len(paths))
+	for _, path := range paths {
+		pages[path] = modulePage(path)
+	}
+	for _, path := range paths {
+		module := g.Modules[path]
+		diagram, err := neighbourhoodDiagram(g, module)
+		if err != nil {
+			return nil, err
+		}
+		data.Modules = append(data.Modules, &siteModule{
+			Module:     module,
+			Page:       pages[path],
+			Metrics:    metricsByPath[path],
+			Diagram:    diagram,
+			Violations: violationsByPath[path],
+		})
+	}
+
+	data.LayerChart = layerChart(d.Graph.ModulesByLayer)
+	data.FanInChart = topChart(data.Metrics, func(m *analysis.StructuralMetrics) int { return m.FanIn })
+	data.FanOutChart = topChart(data.Metrics, func(m *analysis.StructuralMetrics) int { return m.FanOut })
+	return data, nil
+}
+
+// modulePage returns the page of a module: its path made safe for a file
+// name, with a hash so that different paths never share a page
+func modulePage(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(path))
+	return fmt.Sprintf("%s/%s-%08x.html", siteModulesDir, b.String(), h.Sum32())
+}
+
+// neighbourhoodDiagram draws a module with its dependencies and dependents
+func neighbourhoodDiagram(g *graph.Graph, module *graph.Module) (string, error) {
+	neighbourhood := &graph.Graph{Root: g.Root, Modules: map[string]*graph.Module{module.Path: module}}
+	for _, path := range append(append([]string{}, module.Dependencies...), module.Dependents...) {
+		if m, ok := g.Modules[path]; ok {
+			neighbourhood.Modules[path] = m
+		}
+	}
+	diagram, err := viz.GenerateMermaid(neighbourhood, viz.MermaidOptions{
+		Type:      viz.MermaidFlowchart,
+		Direction: "LR",
+		ColorBy:   "layer",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to draw %s: %w", module.Path, err)
+	}
+	return diagram, nil
+}
+
+// writePage renders one page of the site
+func writePage(path, name string, p *page, summary *SiteSummary) error {
+	var b strings.Builder
+	if err := siteTemplates.ExecuteTemplate(&b, pageTemplate(name), p); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	summary.Pages++
+	return nil
+}
+
+// pageTemplate returns the template of a page
+func pageTemplate(name string) string {
+	if strings.HasPrefix(name, siteModulesDir+"/") {
+		return "module"
+	}
+	return strings.TrimSuffix(name, ".html")
+}
+
+// chartBar is one bar of a chart
+type chartBar struct {
+	Label string
+	Value int
+}
+
+// layerChart charts the modules of each layer, largest first
+func layerChart(byLayer map[string]int) template.HTML {
+	bars := make([]chartBar, 0, len(byLayer))
+	for layer, count := range byLayer {
+		if layer == "" {
+			layer = "(none)"
+		}
+		bars = append(bars, chartBar{layer, count})
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Value != bars[j].Value {
+			return bars[i].Value > bars[j].Value
+		}
+		return bars[i].Label < bars[j].Label
+	})
+	return barChart(bars)
+}
+
+// topChart charts the ten modules with the highest value
+func topChart(metrics []*analysis.StructuralMetrics, value func(*analysis.StructuralMetrics) int) template.HTML {
+	bars := make([]chartBar, 0, len(metrics))
+	for _, m := range metrics {
+		if v := value(m); v > 0 {
+			bars = append(bars, chartBar{m.Path, v})
+		}
+	}
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].Value > bars[j].Value })
+	if len(bars) > 10 {
+		bars = bars[:10]
+	}
+	return barChart(bars)
+}
+
+// barChart draws a horizontal bar chart as inline SVG, so charts need no
+// script or network access
+func barChart(bars []chartBar) template.HTML {
+	if len(bars) == 0 {
+		return template.HTML(`<p class="muted">No data.</p>`)
+	}
+	const labelWidth, barWidth, rowHeight = 260, 360, 22
+
+	max := 0
+	for _, bar := range bars {
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" width="%d" height="%d" role="img">`, labelWidth+barWidth+60, len(bars)*rowHeight)
+	for i, bar := range bars {
+		y := i * rowHeight
+		width := bar.Value * barWidth / max
+		label := bar.Label
+		if len(label) > 40 {
+			label = "…" + label[len(label)-39:]
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, labelWidth-8, y+15, template.HTMLEscapeString(label))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d"><title>%s: %d</title></rect>`,
+			labelWidth, y+3, width, rowHeight-6, template.HTMLEscapeString(bar.Label), bar.Value)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%d</text>`, labelWidth+width+6, y+15, bar.Value)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// siteTemplates renders the pages of a site
+var siteTemplates = template.Must(template.New("site").Funcs(template.FuncMap{
+	"pageOf": modulePage,
+	"percent": func(v float64) string {
+		return fmt.Sprintf("%.0f%%", v*100)
+	},
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} · {{.Project}}</title>
+<script src="https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.min.js"></script>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 0; color: #222; }
+nav { background: #24292f; padding: 0.8em 2em; }
+nav a { color: #fff; margin-right: 1.5em; text-decoration: none; }
+nav strong { color: #fff; margin-right: 2em; }
+main { padding: 1em 2em 3em; max-width: 1200px; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ddd; padding: 0.3em 0.7em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.cards { display: flex; flex-wrap: wrap; gap: 1em; }
+.card { border: 1px solid #ddd; border-radius: 6px; padding: 0.8em 1.2em; min-width: 9em; }
+.card b { display: block; font-size: 1.6em; }
+.pass { color: #1a7f37; } .fail { color: #cf222e; } .muted { color: #777; }
+.error { color: #cf222e; } .warning { color: #9a6700; } .info { color: #0969da; }
+svg.chart rect { fill: #0969da; } svg.chart text { font-size: 12px; }
+pre.mermaid { background: #fff; }
+footer { color: #777; font-size: 0.85em; padding: 1em 2em; }
+</style>
+</head>
+<body>
+<nav><strong>{{.Project}}</strong>
+<a href="{{.Base}}index.html">Overview</a>
+<a href="{{.Base}}modules.html">Modules</a>
+<a href="{{.Base}}graph.html">Graph</a>
+<a href="{{.Base}}rules.html">Rules</a>
+<a href="{{.Base}}deadcode.html">Dead Code</a>
+<a href="{{.Base}}metrics.html">Metrics</a>
+</nav>
+<main>
+<h1>{{.Title}}</h1>
+{{end}}
+
+{{define "footer"}}</main>
+<footer>Generated {{.GeneratedAt}}{{if .Tool}} by {{.Tool}}{{end}}. Diagrams are rendered with Mermaid; their source is shown if it cannot be loaded.</footer>
+<script>mermaid.initialize({ startOnLoad: true, maxTextSize: 10000000 });</script>
+</body>
+</html>
+{{end}}
+
+{{define "index"}}{{template "header" .}}
+<div class="cards">
+<div class="card"><b>{{.Dashboard.Graph.Modules}}</b>modules</div>
+<div class="card"><b>{{.Dashboard.Graph.Relationships}}</b>relationships</div>
+<div class="card"><b>{{printf "%.0f" .Dashboard.Docs.CoveragePercent}}%</b>documented files</div>
+<div class="card"><b class="{{if .Rules.Success}}pass{{else}}fail{{end}}">{{len .Rules.PassedRules}}/{{.Rules.TotalRules}}</b>rules passed</div>
+<div class="card"><b>{{len .Rules.Violations}}</b><a href="rules.html">violations</a></div>
+<div class="card"><b>{{len .DeadCode.UnreferencedModules}}</b><a href="deadcode.html">unreferenced modules</a></div>
+<div class="card"><b>{{len .Dashboard.Cycles}}</b>dependency cycles</div>
+</div>
+<h2>Modules by Layer</h2>
+{{.LayerChart}}
+<h2>Hotspots</h2>
+<table>
+<tr><th>Module</th><th>Layer</th><th>Dependents</th><th>Dependencies</th></tr>
+{{range .Dashboard.Hotspots}}<tr><td><a href="{{pageOf .Path}}">{{.Path}}</a></td><td>{{.Layer}}</td><td>{{.Dependents}}</td><td>{{.Dependencies}}</td></tr>
+{{end}}</table>
+{{if .Dashboard.Cycles}}<h2>Dependency Cycles</h2>
+<ul>{{range .Dashboard.Cycles}}<li>{{range $i, $p := .}}{{if $i}} → {{end}}<a href="{{pageOf $p}}">{{$p}}</a>{{end}}</li>
+{{end}}</ul>{{end}}
+{{template "footer" .}}{{end}}
+
+{{define "modules"}}{{template "header" .}}
+<table>
+<tr><th>Module</th><th>Layer</th><th>Language</th><th>Description</th><th>Tags</th></tr>
+{{range .Modules}}<tr><td><a href="{{.Page}}">{{.Path}}</a></td><td>{{.Layer}}</td><td>{{.Language}}</td><td>{{.Description}}</td><td>{{range $i, $t := .Tags}}{{if $i}}, {{end}}{{$t}}{{end}}</td></tr>
+{{end}}</table>
+{{template "footer" .}}{{end}}
+
+{{define "module"}}{{template "header" .}}{{with .Module}}
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<table>
+<tr><th>Name</th><td>{{.Name}}</td></tr>
+<tr><th>Layer</th><td>{{.Layer}}</td></tr>
+<tr><th>Language</th><td>{{.Language}}</td></tr>
+{{if .Tags}}<tr><th>Tags</th><td>{{range $i, $t := .Tags}}{{if $i}}, {{end}}{{$t}}{{end}}</td></tr>{{end}}
+{{with .Metrics}}<tr><th>Fan-in / fan-out</th><td>{{.FanIn}} / {{.FanOut}}</td></tr>
+<tr><th>Instability</th><td>{{.Instability}}</td></tr>{{end}}
+</table>
+{{if .Violations}}<h2>Rule Violations</h2>
+<ul>{{range .Violations}}<li class="{{.Rule.Severity}}">{{.Rule.ID}}: {{.Message}}</li>
+{{end}}</ul>{{end}}
+<h2>Neighbourhood</h2>
+<pre class="mermaid">
+{{.Diagram}}
+</pre>
+{{if .Dependencies}}<h2>Dependencies</h2>
+<ul>{{range .Dependencies}}<li><a href="../{{pageOf .}}">{{.}}</a></li>
+{{end}}</ul>{{end}}
+{{if .Dependents}}<h2>Dependents</h2>
+<ul>{{range .Dependents}}<li><a href="../{{pageOf .}}">{{.}}</a></li>
+{{end}}</ul>{{end}}
+{{if .Exports}}<h2>Exports</h2>
+<ul>{{range .Exports}}<li><code>{{.}}</code></li>
+{{end}}</ul>{{end}}
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "graph"}}{{template "header" .}}
+<pre class="mermaid">
+{{.Diagram}}
+</pre>
+{{template "footer" .}}{{end}}
+
+{{define "rules"}}{{template "header" .}}
+<p>{{.Rules.TotalRules}} {{.RulesSource}} rules: <span class="pass">{{len .Rules.PassedRules}} passed</span>,
+<span class="{{if .Rules.FailedRules}}fail{{end}}">{{len .Rules.FailedRules}} failed</span>,
+{{len .Rules.SkippedRules}} skipped.</p>
+{{if .Rules.Violations}}<table>
+<tr><th>Severity</th><th>Rule</th><th>Module</th><th>Message</th><th>Suggestion</th></tr>
+{{range .Rules.Violations}}<tr><td class="{{.Rule.Severity}}">{{.Rule.Severity}}</td><td>{{.Rule.ID}}</td>
+<td>{{if .Module}}<a href="{{pageOf .Module.Path}}">{{.Module.Path}}</a>{{else}}{{.FilePath}}{{end}}</td><td>{{.Message}}</td><td>{{.Suggestion}}</td></tr>
+{{end}}</table>{{else}}<p class="pass">No violations.</p>{{end}}
+{{template "footer" .}}{{end}}
+
+{{define "deadcode"}}{{template "header" .}}
+<h2>Unreferenced Modules</h2>
+{{if .DeadCode.UnreferencedModules}}<table>
+<tr><th>Module</th><th>Reason</th><th>Confidence</th><th>Safe to remove</th></tr>
+{{range .DeadCode.UnreferencedModules}}<tr><td><a href="{{pageOf .Module.Path}}">{{.Module.Path}}</a></td><td>{{.Reason}}</td><td>{{percent .Confidence}}</td><td>{{if .SafeToRemove}}yes{{else}}review{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="pass">No unreferenced modules.</p>{{end}}
+{{if .DeadCode.UnusedDependencies}}<h2>Unused Dependencies</h2>
+<table>
+<tr><th>Dependency</th><th>Reason</th></tr>
+{{range .DeadCode.UnusedDependencies}}<tr><td>{{.Path}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>{{end}}
+{{template "footer" .}}{{end}}
+
+{{define "metrics"}}{{template "header" .}}
+<h2>Highest Fan-in</h2>
+{{.FanInChart}}
+<h2>Highest Fan-out</h2>
+{{.FanOutChart}}
+<h2>All Modules</h2>
+<table>
+<tr><th>Module</th><th>Layer</th><th>Fan-in</th><th>Fan-out</th><th>Instability</th><th>Depth</th><th>Betweenness</th></tr>
+{{range .Metrics}}<tr><td><a href="{{pageOf .Path}}">{{.Path}}</a></td><td>{{.Layer}}</td><td>{{.FanIn}}</td><td>{{.FanOut}}</td><td>{{.Instability}}</td><td>{{.Depth}}</td><td>{{.Betweenness}}</td></tr>
+{{end}}</table>
+{{template "footer" .}}{{end}}
+`))
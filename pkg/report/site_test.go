@@ -0,0 +1,88 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestWriteSite(t *testing.T) {
+	root, err := filepath.Abs("../../examples/minimal-app")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	g, err := graph.NewBuilder().Build(root, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	outDir := t.TempDir()
+	stale := filepath.Join(outDir, siteModulesDir, "removed.html")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := WriteSite(g, SiteOptions{OutputDir: outDir, Tool: "graphfs test"})
+	if err != nil {
+		t.Fatalf("WriteSite() error = %v", err)
+	}
+	if summary.Modules != len(g.Modules) || summary.Pages != 6+len(g.Modules) {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("Expected pages of removed modules to be deleted")
+	}
+
+	for _, name := range []string{"index.html", "modules.html", "graph.html", "rules.html", "deadcode.html", "metrics.html"} {
+		data, err := os.ReadFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Errorf("Page %s missing: %v", name, err)
+			continue
+		}
+		if !strings.Contains(string(data), `href="metrics.html"`) {
+			t.Errorf("Page %s lacks navigation", name)
+		}
+	}
+
+	// Every module page exists and draws the module's neighbourhood
+	for path, module := range g.Modules {
+		data, err := os.ReadFile(filepath.Join(outDir, modulePage(path)))
+		if err != nil {
+			t.Errorf("Module page for %s missing: %v", path, err)
+			continue
+		}
+		page := string(data)
+		if !strings.Contains(page, `class="mermaid"`) || !strings.Contains(page, `href="../index.html"`) {
+			t.Errorf("Module page for %s lacks its diagram or navigation", path)
+		}
+		for _, dep := range module.Dependencies {
+			if _, ok := g.Modules[dep]; ok && !strings.Contains(page, modulePage(dep)) {
+				t.Errorf("Module page for %s does not link dependency %s", path, dep)
+			}
+		}
+	}
+
+	metrics, _ := os.ReadFile(filepath.Join(outDir, "metrics.html"))
+	if !strings.Contains(string(metrics), `<svg class="chart"`) {
+		t.Error("Expected an SVG chart on the metrics page")
+	}
+}
+
+func TestModulePage(t *testing.T) {
+	a, b := modulePage("pkg/a b.go"), modulePage("pkg/a_b.go")
+	if a == b {
+		t.Errorf("Different paths share a page: %s", a)
+	}
+	if !strings.HasPrefix(a, "modules/pkg_a_b.go-") || !strings.HasSuffix(a, ".html") {
+		t.Errorf("Unexpected page name %s", a)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+# Module: pkg/rules/baseline.go
+Violation baselines for ratchet mode.
+
+A baseline records the violations a codebase already has, so validation
+fails only on new ones while legacy violations are fixed over time.
+Violations are identified by rule, file and message; line numbers are not
+part of the identity, so unrelated edits do not resurface them.
+
+## Linked Modules
+- [rule](./rule.go) - Rule data structures
+
+## Tags
+rules, baseline, ci
+
+## Exports
+DefaultBaselineFile, BaselineVersion, Baseline, BaselineEntry, NewBaseline, LoadBaseline
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#baseline.go> a code:Module ;
+    code:name "pkg/rules/baseline.go" ;
+    code:description "Violation baselines for ratchet mode" ;
+    code:language "go" ;
+    code:layer "rules" ;
+    code:linksTo <./rule.go> ;
+    code:exports <#DefaultBaselineFile>, <#BaselineVersion>, <#Baseline>, <#BaselineEntry>, <#NewBaseline>, <#LoadBaseline> ;
+    code:tags "rules", "baseline", "ci" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultBaselineFile is the baseline location, relative to the project root
+const DefaultBaselineFile = ".graphfs/baseline.json"
+
+// BaselineVersion is the version of the baseline file format
+const BaselineVersion = 1
+
+// Baseline is a set of accepted violations
+type Baseline struct {
+	Version    int             `json:"version"`
+	Violations []BaselineEntry `json:"violations"`
+}
+
+// BaselineEntry is an accepted violation, with the number of times it occurs
+type BaselineEntry struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// baselineKey identifies a violation across runs
+type baselineKey struct {
+	rule, file, message string
+}
+
+// keyOf returns the baseline key of a violation
+func keyOf(v Violation) baselineKey {
+	return baselineKey{rule: v.Rule.ID, file: v.FilePath, message: v.Message}
+}
+
+// NewBaseline returns a baseline accepting every violation of a result
+func NewBaseline(result *ValidationResult) *Baseline {
+	counts := make(map[baselineKey]int)
+	for _, v := range result.Violations {
+		counts[keyOf(v)]++
+	}
+
+	b := &Baseline{Version: BaselineVersion, Violations: make([]BaselineEntry, 0, len(counts))}
+	for key, count := range counts {
+		b.Violations = append(b.Violations, BaselineEntry{Rule: key.rule, File: key.file, Message: key.message, Count: count})
+	}
+	sort.Slice(b.Violations, func(i, j int) bool {
+		a, c := b.Violations[i], b.Violations[j]
+		if a.Rule != c.Rule {
+			return a.Rule < c.Rule
+		}
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		return a.Message < c.Message
+	})
+	return b
+}
+
+// LoadBaseline reads a baseline file
+func LoadBaseline(filePath string) (*Baseline, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	if b.Version > BaselineVersion {
+		return nil, fmt.Errorf("baseline version %d is newer than supported version %d", b.Version, BaselineVersion)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline to a file
+func (b *Baseline) Save(filePath string) error {
+	// Messages quote IRIs, which read better unescaped
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(b); err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, data.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Apply removes the violations accepted by the baseline from a result, up
+// to the accepted count of each, and recounts it. Rules left without
+// violations pass. BaselinedCount and FixedCount record the violations
+// removed and the accepted violations no longer found.
+func (b *Baseline) Apply(result *ValidationResult) {
+	remaining := make(map[baselineKey]int)
+	for _, entry := range b.Violations {
+		remaining[baselineKey{rule: entry.Rule, file: entry.File, message: entry.Message}] += entry.Count
+	}
+
+	kept := make([]Violation, 0, len(result.Violations))
+	failing := make(map[string]bool)
+	result.ErrorCount, result.WarningCount, result.InfoCount = 0, 0, 0
+	for _, v := range result.Violations {
+		if key := keyOf(v); remaining[key] > 0 {
+			remaining[key]--
+			result.BaselinedCount++
+			continue
+		}
+		kept = append(kept, v)
+		failing[v.Rule.ID] = true
+		switch v.Rule.Severity {
+		case SeverityError:
+			result.ErrorCount++
+		case SeverityWarning:
+			result.WarningCount++
+		case SeverityInfo:
+			result.InfoCount++
+		}
+	}
+	result.Violations = kept
+
+	failed := make([]*Rule, 0, len(result.FailedRules))
+	for _, rule := range result.FailedRules {
+		if failing[rule.ID] {
+			failed = append(failed, rule)
+		} else {
+			result.PassedRules = append(result.PassedRules, rule)
+		}
+	}
+	result.FailedRules = failed
+
+	for _, count := range remaining {
+		result.FixedCount += count
+	}
+}
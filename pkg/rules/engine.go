@@ -9,7 +9,9 @@ Main engine that orchestrates rule parsing, evaluation, and reporting.
 - [./parser](./parser.go) - Rule parser
 - [./evaluator](./evaluator.go) - Rule evaluator
 - [./reporter](./reporter.go) - Violation reporter
+- [./fix](./fix.go) - Violation fixes
 - [../graph](../graph/graph.go) - Graph data structure
+- [../query](../query/macros.go) - Query prefixes and macros
 
 ## Tags
 rules, engine, validation
@@ -26,7 +28,7 @@ Engine, ValidateRules
     code:description "Rule engine for validating architectural constraints" ;
     code:language "go" ;
     code:layer "rules" ;
-    code:linksTo <./rule.go>, <./parser.go>, <./evaluator.go>, <./reporter.go>, <../graph/graph.go> ;
+    code:linksTo <./rule.go>, <./parser.go>, <./evaluator.go>, <./reporter.go>, <./fix.go>, <../graph/graph.go>, <../query/macros.go> ;
     code:exports <#Engine>, <#ValidateRules> ;
     code:tags "rules", "engine", "validation" .
 <!-- End LinkedDoc RDF -->
@@ -39,6 +41,7 @@ import (
 	"time"
 
 	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
 )
 
 // Engine is the main rule validation engine
@@ -57,6 +60,11 @@ func NewEngine(g *graph.Graph) *Engine {
 	}
 }
 
+// SetPreprocessor sets the prefix/macro preprocessor applied to rule patterns
+func (e *Engine) SetPreprocessor(p *query.Preprocessor) {
+	e.evaluator.SetPreprocessor(p)
+}
+
 // ValidateFile validates rules from a file
 func (e *Engine) ValidateFile(rulesFile string) (*ValidationResult, error) {
 	// Parse rules
@@ -96,14 +104,19 @@ func (e *Engine) Validate(rules []*Rule) (*ValidationResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to evaluate rule %s: %w", rule.ID, err)
 		}
+		violations = selectViolations(rule, violations)
+
+		for i := range violations {
+			e.addFixes(&violations[i])
+		}
 
 		if len(violations) > 0 {
 			result.FailedRules = append(result.FailedRules, rule)
 			result.Violations = append(result.Violations, violations...)
 
-			// Count by severity
-			for range violations {
-				switch rule.Severity {
+			// Count by severity (SHACL results carry their own)
+			for _, v := range violations {
+				switch v.Rule.Severity {
 				case SeverityError:
 					result.ErrorCount++
 				case SeverityWarning:
@@ -124,6 +137,26 @@ func (e *Engine) Validate(rules []*Rule) (*ValidationResult, error) {
 	return result, nil
 }
 
+// selectViolations drops violations in modules outside a rule's applies-to
+// and exempt selectors
+func selectViolations(rule *Rule, violations []Violation) []Violation {
+	if len(rule.AppliesTo) == 0 && len(rule.Exempt) == 0 {
+		return violations
+	}
+
+	selected := make([]Violation, 0, len(violations))
+	for _, v := range violations {
+		path := v.FilePath
+		if v.Module != nil {
+			path = v.Module.Path
+		}
+		if rule.Selects(path) {
+			selected = append(selected, v)
+		}
+	}
+	return selected
+}
+
 // ValidateWithFilter validates rules filtered by tags or severity
 func (e *Engine) ValidateWithFilter(rules []*Rule, tags []string, minSeverity Severity) (*ValidationResult, error) {
 	// Filter rules
@@ -203,11 +236,13 @@ func GetBuiltInRules() []*Rule {
 				SELECT ?module WHERE {
 					?module code:exports ?export .
 					FILTER NOT EXISTS { ?module code:description ?desc }
+					FILTER NOT EXISTS { ?module code:generated "true" }
 				}
 			`,
 			Expect:     0,
 			Enabled:    true,
 			Suggestion: "Add a description field to the module's LinkedDoc metadata",
+			Fix:        &FixSpec{Property: "description"},
 		},
 		{
 			ID:          "modules-have-layer",
@@ -223,6 +258,7 @@ func GetBuiltInRules() []*Rule {
 			Expect:     0,
 			Enabled:    true,
 			Suggestion: "Add a layer field to the module's LinkedDoc metadata",
+			Fix:        &FixSpec{Property: "layer"},
 		},
 		{
 			ID:          "modules-have-tags",
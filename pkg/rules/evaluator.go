@@ -3,11 +3,16 @@
 SPARQL-based rule evaluator for executing rules against the knowledge graph.
 
 Evaluates architectural rules using SPARQL queries and detects violations.
+Rules of type budget are checked with the dependency budget analyzer, and
+rules of type perf against benchmark metrics and performance budgets.
 
 ## Linked Modules
 - [./rule](./rule.go) - Rule data structures
 - [../graph](../graph/graph.go) - Graph data structure
 - [../query](../query/sparql.go) - SPARQL query engine
+- [../analysis](../analysis/budgets.go) - Dependency budgets
+- [../analysis](../analysis/benchmarks.go) - Performance budgets
+- [./layers](./layers.go) - Layer dependency rules
 
 ## Tags
 rules, evaluator, sparql
@@ -24,7 +29,7 @@ Evaluator, EvaluateRule
     code:description "SPARQL-based rule evaluator for executing rules" ;
     code:language "go" ;
     code:layer "rules" ;
-    code:linksTo <./rule.go>, <../graph/graph.go>, <../query/sparql.go> ;
+    code:linksTo <./rule.go>, <../graph/graph.go>, <../query/sparql.go>, <../analysis/budgets.go>, <../analysis/benchmarks.go>, <./layers.go> ;
     code:exports <#Evaluator>, <#EvaluateRule> ;
     code:tags "rules", "evaluator", "sparql" .
 <!-- End LinkedDoc RDF -->
@@ -34,8 +39,11 @@ package rules
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/justin4957/graphfs/pkg/analysis"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/query"
 )
@@ -54,8 +62,26 @@ func NewEvaluator(g *graph.Graph) *Evaluator {
 	}
 }
 
+// SetPreprocessor sets the prefix/macro preprocessor applied to rule patterns
+func (e *Evaluator) SetPreprocessor(p *query.Preprocessor) {
+	e.executor.SetPreprocessor(p)
+}
+
 // EvaluateRule evaluates a single rule and returns violations
 func (e *Evaluator) EvaluateRule(rule *Rule) ([]Violation, error) {
+	switch rule.Type {
+	case RuleTypeBudget:
+		return e.evaluateBudgetRule(rule)
+	case RuleTypeComponents:
+		return e.evaluateComponentsRule(rule)
+	case RuleTypeSHACL:
+		return e.evaluateSHACLRule(rule)
+	case RuleTypePerf:
+		return e.evaluatePerfRule(rule)
+	case RuleTypeLayers:
+		return e.evaluateLayerRule(rule)
+	}
+
 	// Execute SPARQL query
 	results, err := e.executor.ExecuteString(rule.Pattern)
 	if err != nil {
@@ -94,6 +120,123 @@ func (e *Evaluator) EvaluateRule(rule *Rule) ([]Violation, error) {
 	return violations, nil
 }
 
+// evaluateBudgetRule reports a violation for every exceeded package budget
+func (e *Evaluator) evaluateBudgetRule(rule *Rule) ([]Violation, error) {
+	budgetsFile := rule.Budgets
+	if budgetsFile == "" {
+		budgetsFile = analysis.DefaultBudgetsFile
+	}
+	if !filepath.IsAbs(budgetsFile) {
+		budgetsFile = filepath.Join(e.graph.Root, budgetsFile)
+	}
+
+	config, err := analysis.LoadBudgetConfig(budgetsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate rule %s: %w", rule.ID, err)
+	}
+
+	violations := make([]Violation, 0)
+	for _, usage := range analysis.AnalyzeBudgets(e.graph, config).OverBudget() {
+		violations = append(violations, Violation{
+			Rule:       rule,
+			Message:    fmt.Sprintf("%s: package %s over budget: %s", rule.Name, usage.Package, strings.Join(usage.Exceeded, ", ")),
+			FilePath:   usage.Package,
+			Suggestion: rule.Suggestion,
+			Details: map[string]any{
+				"package":        usage.Package,
+				"directDeps":     len(usage.DirectDeps),
+				"transitiveDeps": usage.TransitiveDeps,
+				"forbiddenDeps":  usage.ForbiddenDeps,
+			},
+		})
+	}
+
+	return violations, nil
+}
+
+// evaluatePerfRule reports a violation for every module exceeding a
+// performance budget in the latest benchmark run
+func (e *Evaluator) evaluatePerfRule(rule *Rule) ([]Violation, error) {
+	perfFile := e.rootPath(rule.Perf, analysis.DefaultPerfBudgetsFile)
+	config, err := analysis.LoadPerfBudgetConfig(perfFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate rule %s: %w", rule.ID, err)
+	}
+	history, err := analysis.LoadBenchmarkHistory(e.rootPath(rule.Benchmarks, analysis.DefaultBenchmarksFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate rule %s: %w", rule.ID, err)
+	}
+
+	violations := make([]Violation, 0)
+	for _, pv := range analysis.CheckPerfBudgets(e.graph, history, config) {
+		violations = append(violations, Violation{
+			Rule:       rule,
+			Module:     e.graph.GetModule(pv.Module),
+			Message:    fmt.Sprintf("%s: %s over performance budget: %s", rule.Name, pv.Module, strings.Join(pv.Exceeded, ", ")),
+			FilePath:   pv.Module,
+			Suggestion: rule.Suggestion,
+			Details: map[string]any{
+				"module":   pv.Module,
+				"tag":      pv.Budget.Tag,
+				"exceeded": pv.Exceeded,
+			},
+		})
+	}
+
+	return violations, nil
+}
+
+// rootPath resolves a file relative to the graph root, defaulting to def
+func (e *Evaluator) rootPath(file, def string) string {
+	if file == "" {
+		file = def
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(e.graph.Root, file)
+	}
+	return file
+}
+
+// evaluateComponentsRule reports a violation for every dependency on another
+// component's non-API modules or on a component missing from depends_on
+func (e *Evaluator) evaluateComponentsRule(rule *Rule) ([]Violation, error) {
+	componentsFile := rule.Components
+	if componentsFile == "" {
+		componentsFile = analysis.DefaultComponentsFile
+	}
+	if !filepath.IsAbs(componentsFile) {
+		componentsFile = filepath.Join(e.graph.Root, componentsFile)
+	}
+
+	config, err := analysis.LoadComponentConfig(componentsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate rule %s: %w", rule.ID, err)
+	}
+
+	violations := make([]Violation, 0)
+	for _, cv := range analysis.AnalyzeComponents(e.graph, config).Violations() {
+		reason := fmt.Sprintf("%s is not part of the public API of %s", cv.Target, cv.To)
+		if cv.Kind == analysis.ComponentViolationUndeclared {
+			reason = fmt.Sprintf("component %s does not declare a dependency on %s", cv.From, cv.To)
+		}
+		violations = append(violations, Violation{
+			Rule:       rule,
+			Module:     e.graph.GetModule(cv.Source),
+			Message:    fmt.Sprintf("%s: %s -> %s: %s", rule.Name, cv.Source, cv.Target, reason),
+			FilePath:   cv.Source,
+			Suggestion: rule.Suggestion,
+			Details: map[string]any{
+				"kind":          cv.Kind,
+				"fromComponent": cv.From,
+				"toComponent":   cv.To,
+				"target":        cv.Target,
+			},
+		})
+	}
+
+	return violations, nil
+}
+
 // createViolation creates a violation from a SPARQL result row
 func (e *Evaluator) createViolation(rule *Rule, row map[string]string) Violation {
 	violation := Violation{
@@ -154,13 +297,20 @@ func (e *Evaluator) buildViolationMessage(rule *Rule, row map[string]string) str
 	// Start with rule name
 	msg := rule.Name
 
-	// Add details from result row
+	// Add details from result row, in a stable order so messages can be
+	// matched against a baseline
 	if len(row) > 0 {
+		keys := make([]string, 0, len(row))
+		for key := range row {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
 		details := make([]string, 0)
-		for key, value := range row {
+		for _, key := range keys {
 			// Clean up variable names
 			cleanKey := strings.TrimPrefix(key, "?")
-			details = append(details, fmt.Sprintf("%s=%s", cleanKey, value))
+			details = append(details, fmt.Sprintf("%s=%s", cleanKey, row[key]))
 		}
 		if len(details) > 0 {
 			msg += ": " + strings.Join(details, ", ")
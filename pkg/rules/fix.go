@@ -0,0 +1,268 @@
+/*
+# Module: pkg/rules/fix.go
+Machine-applicable fixes for rule violations.
+
+Rules may declare a fix that adds missing LinkedDoc metadata (a layer,
+description or owner) to the violating module. The fix is computed as text
+edits on the module's source file, with zero-based line and UTF-16
+character positions as in the Language Server Protocol, so editor plugins
+and bots can apply it without knowing LinkedDoc.
+
+## Linked Modules
+- [./rule](./rule.go) - Rule data structures
+- [../graph](../graph/module.go) - Module data structure
+- [../parser](../parser/comments.go) - LinkedDoc comment styles
+- [../scanner](../scanner/language.go) - Language detection
+
+## Tags
+rules, fixes, editor, linkeddoc
+
+## Exports
+FixSpec, Fix, TextEdit, Range, Position, FixProperties, MetadataFix
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#fix.go> a code:Module ;
+    code:name "pkg/rules/fix.go" ;
+    code:description "Machine-applicable fixes for rule violations" ;
+    code:language "go" ;
+    code:layer "rules" ;
+    code:linksTo <./rule.go>, <../graph/module.go>, <../parser/comments.go>, <../scanner/language.go> ;
+    code:exports <#FixSpec>, <#Fix>, <#TextEdit>, <#Range>, <#Position>, <#FixProperties>, <#MetadataFix> ;
+    code:tags "rules", "fixes", "editor", "linkeddoc" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// FixProperties are the metadata properties fixes can add
+var FixProperties = []string{"layer", "description", "owner"}
+
+// FixSpec declares the fix for a rule's violations: add a property to the
+// violating module's LinkedDoc block
+type FixSpec struct {
+	Property string `yaml:"property"` // layer, description or owner
+	Value    string `yaml:"value"`    // Value to add (default: derived for layer and description)
+}
+
+// Fix is a machine-applicable fix for a violation
+type Fix struct {
+	Title string     `json:"title"`
+	Edits []TextEdit `json:"edits"`
+}
+
+// TextEdit replaces a range of a file with new text; an empty range inserts
+type TextEdit struct {
+	File    string `json:"file"` // Path relative to the graph root, with forward slashes
+	Range   Range  `json:"range"`
+	NewText string `json:"new_text"`
+}
+
+// Range is a half-open range of a file
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Position is a zero-based line and UTF-16 character offset in the line
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+const codeNamespace = "https://schema.codedoc.org/"
+
+// validFixProperty reports whether fixes can add a property
+func validFixProperty(property string) bool {
+	for _, p := range FixProperties {
+		if p == property {
+			return true
+		}
+	}
+	return false
+}
+
+// MetadataFix returns the fix adding spec's property to the module's
+// LinkedDoc block in its file under root, or nil when the module already
+// has the property or no value is known for it
+func MetadataFix(root string, module *graph.Module, spec FixSpec) (*Fix, error) {
+	if !validFixProperty(spec.Property) {
+		return nil, fmt.Errorf("cannot fix property %q (must be %s)", spec.Property, strings.Join(FixProperties, ", "))
+	}
+	if hasProperty(module, spec.Property) {
+		return nil, nil
+	}
+
+	source, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(module.Path)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", module.Path, err)
+	}
+	lines := strings.Split(string(source), "\n")
+	style := parser.CommentStyleFor(scanner.DetectLanguageKey(module.Path))
+
+	value := spec.Value
+	if value == "" {
+		value = defaultFixValue(module, spec.Property, lines, style.LinePrefix)
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	// The module's statement is the first subject typed code:Module
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		if start == -1 && strings.Contains(line, style.StartMarker) {
+			start = i
+		} else if start != -1 && strings.Contains(line, style.EndMarker) {
+			end = i
+			break
+		}
+	}
+	if start == -1 {
+		return nil, nil
+	}
+	subjectLine := -1
+	prefixed := false
+	for i := start + 1; i < end; i++ {
+		line := stripLinePrefix(lines[i], style.LinePrefix)
+		if strings.HasPrefix(line, "@prefix code:") {
+			prefixed = true
+		}
+		if subjectLine == -1 && (strings.Contains(line, " a code:Module") || strings.Contains(line, " a <"+codeNamespace+"Module>")) {
+			subjectLine = i
+		}
+	}
+	if subjectLine == -1 {
+		return nil, nil
+	}
+
+	// Replace the subject line's terminator with ";" and the new property,
+	// keeping the terminator
+	line := strings.TrimRight(lines[subjectLine], " \t\r")
+	terminator := line[len(line)-1:]
+	if terminator != ";" && terminator != "." {
+		return nil, nil
+	}
+	newText := fmt.Sprintf(";\n%scode:%s %q %s", propertyIndent(lines, subjectLine, end, style.LinePrefix), spec.Property, value, terminator)
+
+	// LinkedDoc keeps full-IRI predicates as written, so a block without the
+	// code: prefix gets its declaration first
+	var edits []TextEdit
+	if !prefixed {
+		at := Position{Line: start + 1}
+		declaration := "@prefix code: <" + codeNamespace + "> ."
+		if style.LinePrefix != "" {
+			declaration = style.LinePrefix + " " + declaration
+		}
+		edits = append(edits, TextEdit{
+			File:    module.Path,
+			Range:   Range{Start: at, End: at},
+			NewText: declaration + "\n",
+		})
+	}
+	character := utf16Len(line[:len(line)-1])
+	edits = append(edits, TextEdit{
+		File: module.Path,
+		Range: Range{
+			Start: Position{Line: subjectLine, Character: character},
+			End:   Position{Line: subjectLine, Character: character + 1},
+		},
+		NewText: newText,
+	})
+	return &Fix{
+		Title: fmt.Sprintf("Add code:%s %q to %s", spec.Property, value, module.Path),
+		Edits: edits,
+	}, nil
+}
+
+// hasProperty reports whether the module already declares a property
+func hasProperty(module *graph.Module, property string) bool {
+	switch property {
+	case "layer":
+		return module.Layer != "" && !module.HasInferredLayer()
+	case "description":
+		return module.Description != ""
+	}
+	return len(module.Properties[codeNamespace+property]) > 0
+}
+
+// defaultFixValue derives a value for a property: the inferred layer or the
+// module's directory for layer, the header's summary line for description
+func defaultFixValue(module *graph.Module, property string, lines []string, linePrefix string) string {
+	switch property {
+	case "layer":
+		if module.HasInferredLayer() {
+			return module.Layer
+		}
+		if dir := path.Base(path.Dir(module.Path)); dir != "." && dir != "/" {
+			return dir
+		}
+	case "description":
+		for i, line := range lines {
+			if !strings.HasPrefix(stripLinePrefix(line, linePrefix), "# Module:") || i+1 >= len(lines) {
+				continue
+			}
+			summary := stripLinePrefix(lines[i+1], linePrefix)
+			if summary != "" && !strings.HasPrefix(summary, "#") && !strings.HasPrefix(summary, "<!--") {
+				return strings.TrimSuffix(summary, ".")
+			}
+			break
+		}
+	}
+	return ""
+}
+
+// stripLinePrefix trims a block line and its line comment leader, if any
+func stripLinePrefix(line, linePrefix string) string {
+	line = strings.TrimSpace(line)
+	if linePrefix != "" {
+		line = strings.TrimSpace(strings.TrimPrefix(line, linePrefix))
+	}
+	return line
+}
+
+// propertyIndent returns the indentation, behind the line comment leader,
+// of the statement's other properties, or four spaces
+func propertyIndent(lines []string, subjectLine, end int, linePrefix string) string {
+	for i := subjectLine + 1; i < end; i++ {
+		if content := stripLinePrefix(lines[i], linePrefix); content != "" {
+			return lines[i][:strings.Index(lines[i], content)]
+		}
+	}
+	if linePrefix != "" {
+		return linePrefix + "    "
+	}
+	return "    "
+}
+
+// utf16Len returns the length of s in UTF-16 code units
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+// addFixes attaches the rule's fix to a violation of a module
+func (e *Engine) addFixes(v *Violation) {
+	if v.Rule.Fix == nil || v.Module == nil {
+		return
+	}
+	fix, err := MetadataFix(e.graph.Root, v.Module, *v.Rule.Fix)
+	if err != nil || fix == nil {
+		return
+	}
+	v.Fixes = append(v.Fixes, *fix)
+}
@@ -0,0 +1,186 @@
+/*
+# Module: pkg/rules/layers.go
+Declarative layer dependency rules.
+
+Layering policy without SPARQL: `forbid` lists layer pairs whose modules
+must not depend on each other, directly or with `transitive` through any
+chain of modules, and `allow-only` is a matrix of the layers each layer may
+depend on. Rules are evaluated by traversing module dependencies.
+
+## Linked Modules
+- [rule](./rule.go) - Rule data structures
+- [evaluator](./evaluator.go) - Rule evaluator
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+rules, layers, architecture
+
+## Exports
+LayerPair, LayerPairs
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#layers.go> a code:Module ;
+    code:name "pkg/rules/layers.go" ;
+    code:description "Declarative layer dependency rules" ;
+    code:language "go" ;
+    code:layer "rules" ;
+    code:linksTo <./rule.go>, <./evaluator.go>, <../graph/graph.go> ;
+    code:exports <#LayerPair>, <#LayerPairs> ;
+    code:tags "rules", "layers", "architecture" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"gopkg.in/yaml.v3"
+)
+
+// LayerPair is a dependency from modules of one layer on modules of another
+type LayerPair struct {
+	From       string `yaml:"from-layer"`
+	To         string `yaml:"to-layer"`
+	Transitive bool   `yaml:"transitive"` // Also match chains through other modules
+}
+
+// LayerPairs is a list of layer pairs, written in YAML as one pair or a
+// sequence of pairs
+type LayerPairs []LayerPair
+
+// UnmarshalYAML accepts a single mapping as a one-pair list
+func (p *LayerPairs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var pair LayerPair
+		if err := node.Decode(&pair); err != nil {
+			return err
+		}
+		*p = LayerPairs{pair}
+		return nil
+	}
+	var pairs []LayerPair
+	if err := node.Decode(&pairs); err != nil {
+		return err
+	}
+	*p = pairs
+	return nil
+}
+
+// validateLayerRule checks the forbid pairs and allow-only matrix of a rule
+func validateLayerRule(rule *Rule) error {
+	if len(rule.Forbid) == 0 && len(rule.AllowOnly) == 0 {
+		return fmt.Errorf("rule %s: missing forbid or allow-only", rule.ID)
+	}
+	for _, pair := range rule.Forbid {
+		if pair.From == "" || pair.To == "" {
+			return fmt.Errorf("rule %s: forbid needs from-layer and to-layer", rule.ID)
+		}
+	}
+	for layer := range rule.AllowOnly {
+		if layer == "" {
+			return fmt.Errorf("rule %s: allow-only has an empty layer", rule.ID)
+		}
+	}
+	return nil
+}
+
+// evaluateLayerRule reports a violation for every dependency matching a
+// forbidden pair and every dependency outside a layer's allow-only list.
+// Dependencies within a layer, and on modules without a layer, are allowed.
+func (e *Evaluator) evaluateLayerRule(rule *Rule) ([]Violation, error) {
+	paths := make([]string, 0, len(e.graph.Modules))
+	for path := range e.graph.Modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	violations := make([]Violation, 0)
+	for _, pair := range rule.Forbid {
+		for _, path := range paths {
+			if module := e.graph.Modules[path]; module.Layer == pair.From {
+				violations = append(violations, e.forbiddenDependencies(rule, pair, module)...)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		module := e.graph.Modules[path]
+		allowed, ok := rule.AllowOnly[module.Layer]
+		if !ok || module.Layer == "" {
+			continue
+		}
+		for _, dep := range module.Dependencies {
+			target := e.graph.GetModule(dep)
+			if target == nil || target.Layer == "" || target.Layer == module.Layer || slices.Contains(allowed, target.Layer) {
+				continue
+			}
+			violations = append(violations, e.layerViolation(rule, module, target, []string{module.Path, target.Path},
+				fmt.Sprintf("%s may only depend on %s", module.Layer, strings.Join(allowed, ", "))))
+		}
+	}
+
+	return violations, nil
+}
+
+// forbiddenDependencies returns a violation for each module of the pair's
+// target layer that a module depends on, following chains breadth first
+// when the pair is transitive so the shortest chain is reported
+func (e *Evaluator) forbiddenDependencies(rule *Rule, pair LayerPair, module *graph.Module) []Violation {
+	reason := fmt.Sprintf("%s must not depend on %s", pair.From, pair.To)
+	violations := make([]Violation, 0)
+
+	previous := map[string]string{module.Path: ""}
+	queue := []string{module.Path}
+	for len(queue) > 0 {
+		current := e.graph.GetModule(queue[0])
+		queue = queue[1:]
+		if current == nil {
+			continue
+		}
+		for _, dep := range current.Dependencies {
+			if _, seen := previous[dep]; seen {
+				continue
+			}
+			previous[dep] = current.Path
+
+			target := e.graph.GetModule(dep)
+			if target != nil && target.Layer == pair.To {
+				chain := []string{dep}
+				for p := current.Path; p != ""; p = previous[p] {
+					chain = append([]string{p}, chain...)
+				}
+				violations = append(violations, e.layerViolation(rule, module, target, chain, reason))
+			}
+			if pair.Transitive {
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	return violations
+}
+
+// layerViolation creates a violation for a dependency chain between layers
+func (e *Evaluator) layerViolation(rule *Rule, module, target *graph.Module, chain []string, reason string) Violation {
+	return Violation{
+		Rule:       rule,
+		Module:     module,
+		Message:    fmt.Sprintf("%s: %s: %s", rule.Name, strings.Join(chain, " -> "), reason),
+		FilePath:   module.Path,
+		Suggestion: rule.Suggestion,
+		Details: map[string]any{
+			"fromLayer": module.Layer,
+			"toLayer":   target.Layer,
+			"target":    target.Path,
+			"chain":     chain,
+		},
+	}
+}
@@ -33,6 +33,8 @@ package rules
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -45,8 +47,12 @@ func NewParser() *Parser {
 	return &Parser{}
 }
 
-// ParseFile parses rules from a YAML file
+// ParseFile parses rules from a YAML file, or from a SHACL shapes file
 func (p *Parser) ParseFile(filePath string) (*RuleSet, error) {
+	if IsShapesFile(filePath) {
+		return p.parseShapesFile(filePath)
+	}
+
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
@@ -99,8 +105,27 @@ func (p *Parser) validate(ruleSet *RuleSet) error {
 			return fmt.Errorf("rule %s: missing name", rule.ID)
 		}
 
-		if rule.Pattern == "" {
-			return fmt.Errorf("rule %s: missing pattern", rule.ID)
+		// Rules with a layer policy need no type
+		if rule.Type == "" && (len(rule.Forbid) > 0 || len(rule.AllowOnly) > 0) {
+			rule.Type = RuleTypeLayers
+		}
+
+		switch rule.Type {
+		case "", RuleTypeSPARQL:
+			if rule.Pattern == "" {
+				return fmt.Errorf("rule %s: missing pattern", rule.ID)
+			}
+		case RuleTypeBudget, RuleTypeComponents, RuleTypePerf:
+		case RuleTypeSHACL:
+			if rule.Shapes == "" {
+				return fmt.Errorf("rule %s: missing shapes", rule.ID)
+			}
+		case RuleTypeLayers:
+			if err := validateLayerRule(rule); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("rule %s: invalid type '%s' (must be sparql, budget, components, shacl, perf or layers)", rule.ID, rule.Type)
 		}
 
 		if rule.Severity == "" {
@@ -110,6 +135,16 @@ func (p *Parser) validate(ruleSet *RuleSet) error {
 		if rule.Severity != SeverityError && rule.Severity != SeverityWarning && rule.Severity != SeverityInfo {
 			return fmt.Errorf("rule %s: invalid severity '%s' (must be error, warning, or info)", rule.ID, rule.Severity)
 		}
+
+		for _, pattern := range append(append([]string{}, rule.AppliesTo...), rule.Exempt...) {
+			if _, err := filepath.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+				return fmt.Errorf("rule %s: invalid path glob '%s': %w", rule.ID, pattern, err)
+			}
+		}
+
+		if rule.Fix != nil && !validFixProperty(rule.Fix.Property) {
+			return fmt.Errorf("rule %s: invalid fix property '%s' (must be %s)", rule.ID, rule.Fix.Property, strings.Join(FixProperties, ", "))
+		}
 	}
 
 	return nil
@@ -123,6 +158,10 @@ func (p *Parser) setDefaults(ruleSet *RuleSet) {
 			rule.Enabled = true
 		}
 
+		if rule.Type == "" {
+			rule.Type = RuleTypeSPARQL
+		}
+
 		// Default expect to 0 (no violations)
 		if rule.Expect == 0 && rule.Pattern != "" {
 			rule.Expect = 0
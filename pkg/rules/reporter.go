@@ -47,8 +47,12 @@ const (
 	FormatText  OutputFormat = "text"
 	FormatJSON  OutputFormat = "json"
 	FormatJUnit OutputFormat = "junit"
+	FormatFixes OutputFormat = "fixes" // Quick-fix protocol, see docs/QUICK_FIX_PROTOCOL.md
 )
 
+// FixProtocolVersion is the version of the quick-fix protocol output
+const FixProtocolVersion = 1
+
 // Reporter formats and reports rule violations
 type Reporter struct {
 	format OutputFormat
@@ -68,6 +72,8 @@ func (r *Reporter) Report(result *ValidationResult) string {
 		return r.formatJSON(result)
 	case FormatJUnit:
 		return r.formatJUnit(result)
+	case FormatFixes:
+		return r.formatFixes(result)
 	default:
 		return r.formatText(result)
 	}
@@ -164,6 +170,12 @@ func (r *Reporter) formatText(result *ValidationResult) string {
 	if result.InfoCount > 0 {
 		output.WriteString(fmt.Sprintf("  • Info: %d\n", result.InfoCount))
 	}
+	if result.BaselinedCount > 0 {
+		output.WriteString(fmt.Sprintf("  • Baselined: %d\n", result.BaselinedCount))
+	}
+	if result.FixedCount > 0 {
+		green.Fprintf(&output, "  • Fixed since baseline: %d (update the baseline to lock them in)\n", result.FixedCount)
+	}
 
 	output.WriteString(fmt.Sprintf("  • Duration: %dms\n", result.Duration))
 
@@ -194,6 +206,9 @@ func (r *Reporter) formatViolations(output *strings.Builder, violations []Violat
 		if v.Suggestion != "" {
 			output.WriteString(fmt.Sprintf("%s  💡 %s\n", indent, v.Suggestion))
 		}
+		for _, fix := range v.Fixes {
+			output.WriteString(fmt.Sprintf("%s  🔧 %s\n", indent, fix.Title))
+		}
 	}
 }
 
@@ -207,6 +222,7 @@ func (r *Reporter) formatJSON(result *ValidationResult) string {
 		FilePath   string         `json:"file_path,omitempty"`
 		LineNumber int            `json:"line_number,omitempty"`
 		Suggestion string         `json:"suggestion,omitempty"`
+		Fixes      []Fix          `json:"fixes,omitempty"`
 		Details    map[string]any `json:"details,omitempty"`
 	}
 
@@ -219,6 +235,8 @@ func (r *Reporter) formatJSON(result *ValidationResult) string {
 		InfoCount    int             `json:"info_count"`
 		Success      bool            `json:"success"`
 		Duration     int64           `json:"duration_ms"`
+		Baselined    int             `json:"baselined_count,omitempty"`
+		Fixed        int             `json:"fixed_count,omitempty"`
 		Violations   []jsonViolation `json:"violations"`
 	}
 
@@ -232,6 +250,7 @@ func (r *Reporter) formatJSON(result *ValidationResult) string {
 			FilePath:   v.FilePath,
 			LineNumber: v.LineNumber,
 			Suggestion: v.Suggestion,
+			Fixes:      v.Fixes,
 			Details:    v.Details,
 		})
 	}
@@ -245,6 +264,8 @@ func (r *Reporter) formatJSON(result *ValidationResult) string {
 		InfoCount:    result.InfoCount,
 		Success:      result.Success(),
 		Duration:     result.Duration,
+		Baselined:    result.BaselinedCount,
+		Fixed:        result.FixedCount,
 		Violations:   violations,
 	}
 
@@ -252,6 +273,39 @@ func (r *Reporter) formatJSON(result *ValidationResult) string {
 	return string(data)
 }
 
+// formatFixes formats the fixes of the result's violations in the
+// quick-fix protocol
+func (r *Reporter) formatFixes(result *ValidationResult) string {
+	type protocolFix struct {
+		RuleID   string     `json:"rule_id"`
+		Severity Severity   `json:"severity"`
+		Message  string     `json:"message"`
+		Title    string     `json:"title"`
+		Edits    []TextEdit `json:"edits"`
+	}
+
+	type protocol struct {
+		Version int           `json:"version"`
+		Fixes   []protocolFix `json:"fixes"`
+	}
+
+	out := protocol{Version: FixProtocolVersion, Fixes: make([]protocolFix, 0)}
+	for _, v := range result.Violations {
+		for _, fix := range v.Fixes {
+			out.Fixes = append(out.Fixes, protocolFix{
+				RuleID:   v.Rule.ID,
+				Severity: v.Rule.Severity,
+				Message:  v.Message,
+				Title:    fix.Title,
+				Edits:    fix.Edits,
+			})
+		}
+	}
+
+	data, _ := json.MarshalIndent(out, "", "  ")
+	return string(data)
+}
+
 // formatJUnit formats the result as JUnit XML
 func (r *Reporter) formatJUnit(result *ValidationResult) string {
 	type junitFailure struct {
@@ -6,6 +6,8 @@ Defines the core types for architectural rules, violations, and validation resul
 
 ## Linked Modules
 - [../graph](../graph/graph.go) - Graph data structure
+- [../shacl](../shacl/shapes.go) - SHACL shapes
+- [../scanner](../scanner/focus_filter.go) - Path globs for rule selectors
 
 ## Tags
 rules, validation, architecture
@@ -22,7 +24,7 @@ Rule, Severity, Violation, ValidationResult
     code:description "Rule data structures and types for architecture validation" ;
     code:language "go" ;
     code:layer "rules" ;
-    code:linksTo <../graph/graph.go> ;
+    code:linksTo <../graph/graph.go>, <../shacl/shapes.go>, <../scanner/focus_filter.go> ;
     code:exports <#Rule>, <#Severity>, <#Violation>, <#ValidationResult> ;
     code:tags "rules", "validation", "architecture" .
 <!-- End LinkedDoc RDF -->
@@ -32,6 +34,8 @@ package rules
 
 import (
 	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shacl"
 )
 
 // Severity represents the severity level of a rule violation
@@ -43,17 +47,40 @@ const (
 	SeverityInfo    Severity = "info"
 )
 
+// Rule types
+const (
+	RuleTypeSPARQL     = "sparql"     // Pattern is a SPARQL query (default)
+	RuleTypeBudget     = "budget"     // Packages must stay within their dependency budgets
+	RuleTypeComponents = "components" // Dependencies must respect component APIs and depends_on
+	RuleTypeSHACL      = "shacl"      // The graph must conform to SHACL shapes
+	RuleTypePerf       = "perf"       // Benchmarked modules must stay within their performance budgets
+	RuleTypeLayers     = "layers"     // Dependencies between layers must follow forbid and allow-only
+)
+
 // Rule represents an architectural validation rule
 type Rule struct {
-	ID          string   `yaml:"id"`
-	Name        string   `yaml:"name"`
-	Description string   `yaml:"description"`
-	Severity    Severity `yaml:"severity"`
-	Pattern     string   `yaml:"pattern"`    // SPARQL query
-	Expect      int      `yaml:"expect"`     // Expected result count
-	Enabled     bool     `yaml:"enabled"`    // Whether rule is enabled
-	Tags        []string `yaml:"tags"`       // Rule tags for filtering
-	Suggestion  string   `yaml:"suggestion"` // Default suggestion for violations
+	ID          string              `yaml:"id"`
+	Name        string              `yaml:"name"`
+	Description string              `yaml:"description"`
+	Severity    Severity            `yaml:"severity"`
+	Type        string              `yaml:"type"`       // Rule type (sparql, budget, components, shacl, perf or layers)
+	Pattern     string              `yaml:"pattern"`    // SPARQL query
+	Expect      int                 `yaml:"expect"`     // Expected result count
+	Budgets     string              `yaml:"budgets"`    // Budgets file for budget rules, relative to the graph root
+	Components  string              `yaml:"components"` // Components file for components rules, relative to the graph root
+	Shapes      string              `yaml:"shapes"`     // SHACL shapes file (Turtle) for shacl rules, relative to the graph root
+	Perf        string              `yaml:"perf"`       // Performance budgets file for perf rules, relative to the graph root
+	Benchmarks  string              `yaml:"benchmarks"` // Benchmark history for perf rules, relative to the graph root
+	Forbid      LayerPairs          `yaml:"forbid"`     // Forbidden layer dependencies for layers rules
+	AllowOnly   map[string][]string `yaml:"allow-only"` // Layers each layer may depend on, for layers rules
+	Enabled     bool                `yaml:"enabled"`    // Whether rule is enabled
+	Tags        []string            `yaml:"tags"`       // Rule tags for filtering
+	Suggestion  string              `yaml:"suggestion"` // Default suggestion for violations
+	Fix         *FixSpec            `yaml:"fix"`        // Machine-applicable fix for violations of a module
+	AppliesTo   []string            `yaml:"applies-to"` // Module path globs the rule is limited to (supports **)
+	Exempt      []string            `yaml:"exempt"`     // Module path globs excluded from the rule (supports **)
+
+	shape *shacl.Shape // Shape of rules loaded from a shapes file
 }
 
 // Violation represents a rule violation
@@ -64,6 +91,7 @@ type Violation struct {
 	FilePath   string         // File path where violation occurred
 	LineNumber int            // Line number (0 if unknown)
 	Suggestion string         // Suggested fix
+	Fixes      []Fix          // Machine-applicable fixes
 	Details    map[string]any // Additional details from SPARQL results
 }
 
@@ -78,13 +106,30 @@ type ValidationResult struct {
 	WarningCount int         // Number of warning-level violations
 	InfoCount    int         // Number of info-level violations
 	Duration     int64       // Execution duration in milliseconds
+
+	BaselinedCount int // Violations accepted by a baseline and not reported
+	FixedCount     int // Baseline violations no longer found
 }
 
 // RuleSet represents a collection of rules
 type RuleSet struct {
-	Version string  `yaml:"version"`
-	Name    string  `yaml:"name"`
-	Rules   []*Rule `yaml:"rules"`
+	Version  string   `yaml:"version"`
+	Name     string   `yaml:"name"`
+	Rules    []*Rule  `yaml:"rules"`
+	Warnings []string `yaml:"-"` // Problems that did not prevent loading
+}
+
+// Selects reports whether the rule covers a module path: it matches
+// applies-to, if given, and does not match exempt. Violations not tied to a
+// path are always covered.
+func (r *Rule) Selects(path string) bool {
+	if path == "" {
+		return true
+	}
+	if len(r.AppliesTo) > 0 && len(scanner.NewFocusFilter(r.AppliesTo, "").Match([]string{path})) == 0 {
+		return false
+	}
+	return len(r.Exempt) == 0 || len(scanner.NewFocusFilter(r.Exempt, "").Match([]string{path})) == 0
 }
 
 // HasErrors returns true if there are any error-level violations
@@ -1,11 +1,20 @@
 package rules
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
+	"unicode/utf16"
 
 	"github.com/justin4957/graphfs/internal/store"
 	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/scanner"
 )
 
 func createTestGraph() *graph.Graph {
@@ -206,6 +215,18 @@ rules:
 `,
 			expected: "invalid severity",
 		},
+		{
+			name: "incomplete forbid",
+			yaml: `
+version: "1.0"
+rules:
+  - id: test
+    name: Test
+    severity: error
+    forbid: {from-layer: data}
+`,
+			expected: "forbid needs from-layer and to-layer",
+		},
 	}
 
 	parser := NewParser()
@@ -626,3 +647,598 @@ func TestEngine_SkippedRules(t *testing.T) {
 		t.Errorf("Expected 1 total rule (excluding skipped), got %d", result.TotalRules)
 	}
 }
+
+func TestEngine_Validate_ExportsDocumentedSkipsGenerated(t *testing.T) {
+	var rule *Rule
+	for _, r := range GetBuiltInRules() {
+		if r.ID == "exports-documented" {
+			rule = r
+		}
+	}
+	if rule == nil {
+		t.Fatal("Built-in rule exports-documented not found")
+	}
+
+	// Built-in patterns rely on the project's shared prefixes
+	prefixes := query.NewPreprocessor()
+	prefixes.Prefixes["code"] = "https://schema.codedoc.org/"
+
+	g := createTestGraph()
+	engine := NewEngine(g)
+	engine.SetPreprocessor(prefixes)
+	result, err := engine.Validate([]*Rule{rule})
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	if len(result.Violations) != 1 {
+		t.Fatalf("Expected auth.go to be flagged, got %d violations", len(result.Violations))
+	}
+
+	g.Store.Add("<#auth.go>", graph.GeneratedPredicate, "true")
+	result, err = engine.Validate([]*Rule{rule})
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	if len(result.Violations) != 0 {
+		t.Errorf("Expected generated modules to be skipped, got %+v", result.Violations)
+	}
+}
+
+func TestEngine_Validate_BudgetRule(t *testing.T) {
+	g := createTestGraph()
+	g.Root = t.TempDir()
+
+	budgets := "budgets:\n  - package: .\n    max_direct_deps: 0\n    forbidden_layers: [service]\n"
+	if err := os.WriteFile(filepath.Join(g.Root, "budgets.yaml"), []byte(budgets), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ruleSet, err := ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: budgets
+    name: Dependency budgets
+    severity: error
+    type: budget
+    budgets: budgets.yaml
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse budget rule: %v", err)
+	}
+
+	result, err := NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	if len(result.Violations) != 1 {
+		t.Fatalf("Expected 1 budget violation, got %d", len(result.Violations))
+	}
+	if v := result.Violations[0]; v.FilePath != "." || !strings.Contains(v.Message, "forbidden layers") {
+		t.Errorf("Unexpected violation: %+v", v)
+	}
+
+	if _, err := ParseRuleSet([]byte("version: \"1.0\"\nrules:\n  - id: x\n    name: X\n    severity: error\n    type: unknown\n")); err == nil {
+		t.Error("Expected error for unknown rule type")
+	}
+}
+
+func TestEngine_Validate_PerfRule(t *testing.T) {
+	g := createTestGraph()
+	g.Root = t.TempDir()
+
+	history := `{"current": {"modules": {"main.go": [{"name": "BenchmarkMain", "nsPerOp": 2000}]}}}`
+	perf := "budgets:\n  - tag: entrypoint\n    max_ns_per_op: 1000\n"
+	for name, content := range map[string]string{"benchmarks.json": history, "perf.yaml": perf} {
+		if err := os.WriteFile(filepath.Join(g.Root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ruleSet, err := ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: perf
+    name: Performance budgets
+    severity: error
+    type: perf
+    perf: perf.yaml
+    benchmarks: benchmarks.json
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse perf rule: %v", err)
+	}
+
+	result, err := NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	if len(result.Violations) != 1 {
+		t.Fatalf("Expected 1 perf violation, got %d", len(result.Violations))
+	}
+	if v := result.Violations[0]; v.FilePath != "main.go" || !strings.Contains(v.Message, "ns/op 2000 > 1000") {
+		t.Errorf("Unexpected violation: %+v", v)
+	}
+}
+
+func TestEngine_Validate_ComponentsRule(t *testing.T) {
+	g := createTestGraph()
+	g.Root = t.TempDir()
+
+	// main.go depends on services/auth.go, which is not in the services API
+	components := "components:\n  - name: app\n    paths: [main.go]\n  - name: services\n    paths: [services]\n    api: [services/api.go]\n"
+	if err := os.WriteFile(filepath.Join(g.Root, "components.yaml"), []byte(components), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ruleSet, err := ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: components
+    name: Component boundaries
+    severity: error
+    type: components
+    components: components.yaml
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse components rule: %v", err)
+	}
+
+	result, err := NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	if len(result.Violations) != 1 {
+		t.Fatalf("Expected 1 component violation, got %d", len(result.Violations))
+	}
+	if v := result.Violations[0]; v.FilePath != "main.go" || !strings.Contains(v.Message, "public API") {
+		t.Errorf("Unexpected violation: %+v", v)
+	}
+}
+
+func TestEngine_Validate_LayerRules(t *testing.T) {
+	g := createTestGraph()
+	// main.go (main) -> services/auth.go (service) -> models/user.go (model)
+	g.GetModule("services/auth.go").Dependencies = []string{"models/user.go"}
+
+	ruleSet, err := ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: main-not-model
+    name: Entry points do not use models
+    severity: error
+    forbid: {from-layer: main, to-layer: model}
+  - id: main-not-model-transitive
+    name: Entry points do not reach models
+    severity: warning
+    forbid:
+      - from-layer: main
+        to-layer: model
+        transitive: true
+  - id: matrix
+    name: Layer matrix
+    severity: error
+    allow-only:
+      main: [model]
+      model: []
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse layer rules: %v", err)
+	}
+	if rule := ruleSet.Rules[0]; rule.Type != RuleTypeLayers || len(rule.Forbid) != 1 {
+		t.Fatalf("Unexpected rule: %+v", rule)
+	}
+
+	result, err := NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	byRule := result.GetViolationsByRule()
+	if len(byRule["main-not-model"]) != 0 {
+		t.Errorf("Expected no direct violations, got %+v", byRule["main-not-model"])
+	}
+	if v := byRule["main-not-model-transitive"]; len(v) != 1 || !strings.Contains(v[0].Message, "main.go -> services/auth.go -> models/user.go") {
+		t.Errorf("Unexpected transitive violations: %+v", v)
+	}
+	if v := byRule["matrix"]; len(v) != 1 || v[0].FilePath != "main.go" || v[0].Details["toLayer"] != "service" {
+		t.Errorf("Unexpected matrix violations: %+v", v)
+	}
+	if result.ErrorCount != 1 || result.WarningCount != 1 {
+		t.Errorf("Expected 1 error and 1 warning, got %d and %d", result.ErrorCount, result.WarningCount)
+	}
+}
+
+func TestEngine_Validate_Selectors(t *testing.T) {
+	g := createTestGraph()
+
+	// The pattern matches all four modules
+	ruleSet, err := ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: models
+    name: Models
+    severity: error
+    applies-to: ["models/**"]
+    pattern: &modules "PREFIX rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> PREFIX code: <https://schema.codedoc.org/> SELECT ?module WHERE { ?module rdf:type code:Module . }"
+  - id: outside-utils-and-services
+    name: Outside utils and services
+    severity: error
+    exempt: ["utils/**", "services/**"]
+    pattern: *modules
+  - id: commands
+    name: Commands
+    severity: error
+    applies-to: ["cmd/**"]
+    pattern: *modules
+  - id: services-but-auth
+    name: Services except auth
+    severity: warning
+    applies-to: ["services/**"]
+    exempt: ["*auth.go"]
+    pattern: *modules
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	result, err := NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	byRule := result.GetViolationsByRule()
+	if v := byRule["models"]; len(v) != 1 || v[0].FilePath != "models/user.go" {
+		t.Errorf("Unexpected models violations: %+v", v)
+	}
+	var files []string
+	for _, v := range byRule["outside-utils-and-services"] {
+		files = append(files, v.FilePath)
+	}
+	sort.Strings(files)
+	if strings.Join(files, " ") != "main.go models/user.go" {
+		t.Errorf("Unexpected violations outside utils and services: %v", files)
+	}
+	if len(result.PassedRules) != 2 || len(result.FailedRules) != 2 {
+		t.Errorf("Expected 2 passed and 2 failed rules, got %d and %d", len(result.PassedRules), len(result.FailedRules))
+	}
+
+	rule := &Rule{AppliesTo: []string{"pkg/**"}, Exempt: []string{"pkg/internal/**"}}
+	for path, want := range map[string]bool{"pkg/api/a.go": true, "pkg/internal/x/b.go": false, "cmd/main.go": false, "": true} {
+		if got := rule.Selects(path); got != want {
+			t.Errorf("Selects(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if _, err := ParseRuleSet([]byte("version: \"1.0\"\nrules:\n  - id: bad\n    name: Bad\n    severity: error\n    pattern: SELECT\n    exempt: [\"pkg/[\"]\n")); err == nil || !strings.Contains(err.Error(), "invalid path glob") {
+		t.Errorf("Expected an invalid glob error, got %v", err)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	g := createTestGraph()
+	ruleSet, err := ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: layering
+    name: Layer matrix
+    severity: error
+    allow-only:
+      main: [model]
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+	validate := func() *ValidationResult {
+		result, err := NewEngine(g).Validate(ruleSet.Rules)
+		if err != nil {
+			t.Fatalf("Validation failed: %v", err)
+		}
+		return result
+	}
+
+	// main.go -> services/auth.go is accepted
+	path := filepath.Join(t.TempDir(), ".graphfs", "baseline.json")
+	if err := NewBaseline(validate()).Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+	if len(baseline.Violations) != 1 || baseline.Violations[0].File != "main.go" || baseline.Violations[0].Count != 1 {
+		t.Fatalf("Unexpected baseline: %+v", baseline.Violations)
+	}
+
+	result := validate()
+	baseline.Apply(result)
+	if !result.Success() || len(result.Violations) != 0 || result.BaselinedCount != 1 || len(result.PassedRules) != 1 {
+		t.Errorf("Expected the accepted violation to pass, got %+v", result)
+	}
+
+	// A new dependency fails; the accepted one is still not reported
+	g.GetModule("main.go").Dependencies = append(g.GetModule("main.go").Dependencies, "utils/helper.go")
+	g.GetModule("utils/helper.go").Layer = "util"
+	result = validate()
+	baseline.Apply(result)
+	if result.Success() || len(result.Violations) != 1 || result.Violations[0].Details["target"] != "utils/helper.go" {
+		t.Errorf("Expected only the new violation, got %+v", result.Violations)
+	}
+	if result.ErrorCount != 1 || result.BaselinedCount != 1 || len(result.FailedRules) != 1 {
+		t.Errorf("Unexpected counts: %+v", result)
+	}
+
+	// Fixing the accepted violation is reported
+	g.GetModule("main.go").Dependencies = nil
+	result = validate()
+	baseline.Apply(result)
+	if !result.Success() || result.FixedCount != 1 {
+		t.Errorf("Expected 1 fixed violation, got %+v", result)
+	}
+}
+
+func TestEngine_Validate_SHACLShapes(t *testing.T) {
+	g := createTestGraph()
+	g.Root = t.TempDir()
+
+	// auth.go has no description; helper.go has no layer
+	shapes := `
+@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix code: <https://schema.codedoc.org/> .
+@prefix ex: <https://example.org/shapes#> .
+
+ex:ModuleShape a sh:NodeShape ;
+    sh:name "Modules are documented" ;
+    sh:targetClass code:Module ;
+    sh:property [ sh:path code:description ; sh:minCount 1 ] ;
+    sh:property [ sh:path code:layer ; sh:minCount 1 ; sh:severity sh:Warning ] ;
+    sh:property [ sh:path code:name ; sh:sparql [ sh:select "SELECT $this WHERE {}" ] ] .
+`
+	shapesFile := filepath.Join(g.Root, "shapes.ttl")
+	if err := os.WriteFile(shapesFile, []byte(shapes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ruleSet, err := ParseRules(shapesFile)
+	if err != nil {
+		t.Fatalf("Failed to parse shapes: %v", err)
+	}
+	if len(ruleSet.Rules) != 1 || ruleSet.Rules[0].ID != "ex:ModuleShape" {
+		t.Fatalf("Expected one rule per shape, got %+v", ruleSet.Rules)
+	}
+	if len(ruleSet.Warnings) != 1 || !strings.Contains(ruleSet.Warnings[0], "sh:sparql") {
+		t.Errorf("Expected a warning for sh:sparql, got %v", ruleSet.Warnings)
+	}
+
+	result, err := NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	if result.ErrorCount != 1 || result.WarningCount != 1 {
+		t.Fatalf("Expected 1 error and 1 warning, got %d and %d", result.ErrorCount, result.WarningCount)
+	}
+	for _, v := range result.Violations {
+		switch v.Rule.Severity {
+		case SeverityError:
+			if v.FilePath != "services/auth.go" || v.Details["component"] != "sh:MinCountConstraintComponent" {
+				t.Errorf("Unexpected error violation: %+v", v)
+			}
+		case SeverityWarning:
+			if v.FilePath != "utils/helper.go" {
+				t.Errorf("Unexpected warning violation: %+v", v)
+			}
+		}
+	}
+
+	// The same shapes referenced from a YAML rule use the rule's severity
+	ruleSet, err = ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: shapes
+    name: Shapes
+    severity: warning
+    type: shacl
+    shapes: shapes.ttl
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse shacl rule: %v", err)
+	}
+	result, err = NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	if result.ErrorCount != 0 || result.WarningCount != 2 {
+		t.Errorf("Expected 2 warnings, got %d errors and %d warnings", result.ErrorCount, result.WarningCount)
+	}
+}
+
+// applyEdits applies single-line text edits to content, last edit first
+func applyEdits(t *testing.T, content string, edits []TextEdit) string {
+	t.Helper()
+	lines := strings.Split(content, "\n")
+	for i := len(edits) - 1; i >= 0; i-- {
+		edit := edits[i]
+		if edit.Range.Start.Line != edit.Range.End.Line {
+			t.Fatalf("Multi-line edits are not supported by this helper: %+v", edit)
+		}
+		line := utf16.Encode([]rune(lines[edit.Range.Start.Line]))
+		before := string(utf16.Decode(line[:edit.Range.Start.Character]))
+		after := string(utf16.Decode(line[edit.Range.End.Character:]))
+		lines[edit.Range.Start.Line] = before + edit.NewText + after
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestEngine_Validate_Fixes(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		// CLI header style: tab indentation, blank line after the subject
+		"api/users.go": "/*\n# Module: api/users.go\nUser endpoints.\n\n<!-- LinkedDoc RDF -->\n@prefix code: <https://schema.codedoc.org/> .\n\n<#users.go> a code:Module ;\n\n\tcode:name \"api/users.go\" .\n<!-- End LinkedDoc RDF -->\n*/\npackage api\n",
+		// A statement ending on the subject line, without a code: prefix
+		"svc/auth.go": "/*\n# Module: svc/auth.go\n<!-- LinkedDoc RDF -->\n<#auth.go> a <https://schema.codedoc.org/Module> .\n<!-- End LinkedDoc RDF -->\n*/\npackage svc\n",
+		"svc/ok.go":   "/*\n# Module: svc/ok.go\n<!-- LinkedDoc RDF -->\n@prefix code: <https://schema.codedoc.org/> .\n<#ok.go> a code:Module ;\n    code:name \"svc/ok.go\" ;\n    code:description \"Fine\" ;\n    code:owner \"@team\" ;\n    code:layer \"service\" .\n<!-- End LinkedDoc RDF -->\n*/\npackage svc\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, err := graph.NewBuilder().Build(root, graph.BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	// Each rule selects every module; fixes are only offered where the
+	// property is missing
+	ruleSet, err := ParseRuleSet([]byte(`
+version: "1.0"
+rules:
+  - id: owned
+    name: Modules must have an owner
+    severity: warning
+    pattern: |
+      PREFIX rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#>
+      PREFIX code: <https://schema.codedoc.org/>
+      SELECT ?module WHERE { ?module rdf:type code:Module . }
+    fix:
+      property: owner
+      value: "@platform"
+  - id: layered
+    name: Modules must have a layer
+    severity: warning
+    pattern: |
+      PREFIX rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#>
+      PREFIX code: <https://schema.codedoc.org/>
+      SELECT ?module WHERE { ?module rdf:type code:Module . }
+    fix:
+      property: layer
+  - id: described
+    name: Modules must have a description
+    severity: warning
+    pattern: |
+      PREFIX rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#>
+      PREFIX code: <https://schema.codedoc.org/>
+      SELECT ?module WHERE { ?module rdf:type code:Module . }
+    fix:
+      property: description
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	result, err := NewEngine(g).Validate(ruleSet.Rules)
+	if err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	// Each fix, applied on its own, adds its property to the parsed block
+	var titles []string
+	for _, v := range result.Violations {
+		for _, fix := range v.Fixes {
+			titles = append(titles, fix.Title)
+			applied := applyEdits(t, files[v.FilePath], fix.Edits)
+			triples, err := parser.NewParser().ParseString(applied)
+			if err != nil {
+				t.Fatalf("%s does not parse after %q: %v\n%s", v.FilePath, fix.Title, err, applied)
+			}
+			property := "https://schema.codedoc.org/" + v.Rule.Fix.Property
+			found := false
+			for _, triple := range triples {
+				found = found || triple.Predicate == property
+			}
+			if !found {
+				t.Errorf("%q did not add %s:\n%s", fix.Title, property, applied)
+			}
+		}
+	}
+	sort.Strings(titles)
+
+	want := []string{
+		`Add code:description "User endpoints" to api/users.go`,
+		`Add code:layer "api" to api/users.go`,
+		`Add code:layer "svc" to svc/auth.go`,
+		`Add code:owner "@platform" to api/users.go`,
+		`Add code:owner "@platform" to svc/auth.go`,
+	}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("Fixes = %v, want %v", titles, want)
+	}
+
+	// The quick-fix protocol output lists every fix with its edits
+	output := NewReporter(FormatFixes).Report(result)
+	var protocol struct {
+		Version int `json:"version"`
+		Fixes   []struct {
+			RuleID string     `json:"rule_id"`
+			Edits  []TextEdit `json:"edits"`
+		} `json:"fixes"`
+	}
+	if err := json.Unmarshal([]byte(output), &protocol); err != nil {
+		t.Fatalf("Invalid fixes output: %v\n%s", err, output)
+	}
+	if protocol.Version != FixProtocolVersion || len(protocol.Fixes) != len(want) {
+		t.Errorf("Unexpected fixes output: %s", output)
+	}
+
+	if _, err := ParseRuleSet([]byte("version: \"1.0\"\nrules:\n  - id: x\n    name: X\n    severity: error\n    pattern: SELECT\n    fix:\n      property: tags\n")); err == nil {
+		t.Error("Expected error for an unsupported fix property")
+	}
+}
+
+func TestMetadataFix_Positions(t *testing.T) {
+	root := t.TempDir()
+	content := "/*\n<!-- LinkedDoc RDF -->\n@prefix code: <https://schema.codedoc.org/> .\n<#café.go> a code:Module ;\n  code:name \"café.go\" .\n<!-- End LinkedDoc RDF -->\n*/\n"
+	if err := os.WriteFile(filepath.Join(root, "café.go"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fix, err := MetadataFix(root, &graph.Module{Path: "café.go"}, FixSpec{Property: "layer", Value: "core"})
+	if err != nil || fix == nil {
+		t.Fatalf("MetadataFix() = %v, %v", fix, err)
+	}
+	want := TextEdit{
+		File:    "café.go",
+		Range:   Range{Start: Position{Line: 3, Character: 25}, End: Position{Line: 3, Character: 26}},
+		NewText: ";\n  code:layer \"core\" ;",
+	}
+	if len(fix.Edits) != 1 || !reflect.DeepEqual(fix.Edits[0], want) {
+		t.Errorf("Edits = %+v, want %+v", fix.Edits, want)
+	}
+
+	// Nothing to add when the property is declared or no value is known
+	if fix, _ := MetadataFix(root, &graph.Module{Path: "café.go", Layer: "core"}, FixSpec{Property: "layer"}); fix != nil {
+		t.Errorf("Expected no fix for a declared layer, got %+v", fix)
+	}
+	if fix, _ := MetadataFix(root, &graph.Module{Path: "café.go"}, FixSpec{Property: "owner"}); fix != nil {
+		t.Errorf("Expected no owner fix without a value, got %+v", fix)
+	}
+	if _, err := MetadataFix(root, &graph.Module{Path: "café.go"}, FixSpec{Property: "tags"}); err == nil {
+		t.Error("Expected error for an unsupported property")
+	}
+}
+
+func TestMetadataFix_LinePrefix(t *testing.T) {
+	parser.RegisterCommentStyle("ruby", parser.CommentStyle{LinePrefix: "#", StartMarker: "LinkedDoc:", EndMarker: "End LinkedDoc"})
+
+	root := t.TempDir()
+	content := "# # Module: sync.rb\n# Syncs records.\n#\n# LinkedDoc:\n# <#sync.rb> a code:Module ;\n#     code:name \"sync.rb\" .\n# End LinkedDoc\n"
+	if err := os.WriteFile(filepath.Join(root, "sync.rb"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fix, err := MetadataFix(root, &graph.Module{Path: "sync.rb"}, FixSpec{Property: "description"})
+	if err != nil || fix == nil {
+		t.Fatalf("MetadataFix() = %v, %v", fix, err)
+	}
+	if len(fix.Edits) != 2 {
+		t.Fatalf("Edits = %+v, want prefix declaration and property", fix.Edits)
+	}
+	if got := fix.Edits[0]; got.Range.Start.Line != 4 || got.NewText != "# @prefix code: <https://schema.codedoc.org/> .\n" {
+		t.Errorf("Prefix edit = %+v", got)
+	}
+	if got := fix.Edits[1].NewText; got != ";\n#     code:description \"Syncs records\" ;" {
+		t.Errorf("Property edit = %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+# Module: pkg/rules/shacl.go
+SHACL shapes as a rule source.
+
+Loads SHACL shape files (Turtle) as rule sets, with one rule per shape with
+targets, and maps SHACL validation results into violations so shapes are
+reported like any other rule. Result severities map sh:Violation to error,
+sh:Warning to warning and sh:Info to info.
+
+## Linked Modules
+- [rule](./rule.go) - Rule data structures
+- [evaluator](./evaluator.go) - Rule evaluator
+- [../shacl](../shacl/shapes.go) - SHACL shapes
+
+## Tags
+rules, shacl, validation
+
+## Exports
+IsShapesFile, ShapeRules
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#shacl.go> a code:Module ;
+    code:name "pkg/rules/shacl.go" ;
+    code:description "SHACL shapes as a rule source" ;
+    code:language "go" ;
+    code:layer "rules" ;
+    code:linksTo <./rule.go>, <./evaluator.go>, <../shacl/shapes.go> ;
+    code:exports <#IsShapesFile>, <#ShapeRules> ;
+    code:tags "rules", "shacl", "validation" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package rules
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/shacl"
+)
+
+// IsShapesFile reports whether a rules file holds SHACL shapes in Turtle
+func IsShapesFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ttl", ".shacl":
+		return true
+	}
+	return false
+}
+
+// ShapeRules returns a rule for each shape with targets. Warnings list
+// constraint components that are not evaluated.
+func ShapeRules(shapes *shacl.Shapes) (rules []*Rule, warnings []string) {
+	for i, shape := range shapes.Shapes {
+		id := shape.ID
+		if strings.HasPrefix(id, "_:") {
+			id = fmt.Sprintf("shape-%d", i+1)
+		}
+		name := shape.Name
+		if name == "" {
+			name = id
+		}
+
+		rules = append(rules, &Rule{
+			ID:          id,
+			Name:        name,
+			Description: shape.Description,
+			Severity:    shaclSeverity(shape.Severity),
+			Type:        RuleTypeSHACL,
+			Enabled:     !shape.Deactivated,
+			Tags:        []string{"shacl"},
+			shape:       shape,
+		})
+
+		for _, unsupported := range unsupportedComponents(shape, make(map[*shacl.Shape]bool)) {
+			warnings = append(warnings, fmt.Sprintf("shape %s: sh:%s is not supported and is ignored", id, unsupported))
+		}
+	}
+	return rules, warnings
+}
+
+// unsupportedComponents collects the unsupported components of a shape
+// and the shapes it references
+func unsupportedComponents(shape *shacl.Shape, seen map[*shacl.Shape]bool) []string {
+	if seen[shape] {
+		return nil
+	}
+	seen[shape] = true
+
+	unsupported := append([]string{}, shape.Unsupported...)
+	for _, other := range shape.References() {
+		unsupported = append(unsupported, unsupportedComponents(other, seen)...)
+	}
+	return unsupported
+}
+
+// parseShapesFile loads a shapes file as a rule set
+func (p *Parser) parseShapesFile(filePath string) (*RuleSet, error) {
+	shapes, err := shacl.LoadShapes(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, warnings := ShapeRules(shapes)
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no shapes with targets defined")
+	}
+	return &RuleSet{
+		Version:  "1.0",
+		Name:     filepath.Base(filePath),
+		Rules:    rules,
+		Warnings: warnings,
+	}, nil
+}
+
+// shaclSeverity maps a SHACL severity to a rule severity
+func shaclSeverity(severity shacl.Severity) Severity {
+	switch severity {
+	case shacl.SeverityWarning:
+		return SeverityWarning
+	case shacl.SeverityInfo:
+		return SeverityInfo
+	}
+	return SeverityError
+}
+
+// evaluateSHACLRule reports a violation for every SHACL validation result.
+// Rules loaded from a shapes file report each result with the severity of
+// the shape that produced it; rules declared in YAML use their own.
+func (e *Evaluator) evaluateSHACLRule(rule *Rule) ([]Violation, error) {
+	var results []shacl.Result
+	if rule.shape != nil {
+		results = rule.shape.Validate(e.graph.Store)
+	} else {
+		shapesFile := rule.Shapes
+		if !filepath.IsAbs(shapesFile) {
+			shapesFile = filepath.Join(e.graph.Root, shapesFile)
+		}
+		shapes, err := shacl.LoadShapes(shapesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate rule %s: %w", rule.ID, err)
+		}
+		results = shapes.Validate(e.graph.Store)
+	}
+
+	bySeverity := map[Severity]*Rule{rule.Severity: rule}
+	violations := make([]Violation, 0, len(results))
+	for _, result := range results {
+		violationRule := rule
+		if rule.shape != nil {
+			severity := shaclSeverity(result.Severity)
+			if bySeverity[severity] == nil {
+				copied := *rule
+				copied.Severity = severity
+				bySeverity[severity] = &copied
+			}
+			violationRule = bySeverity[severity]
+		}
+
+		module := e.findModule("<" + result.FocusNode + ">")
+		focus := result.FocusNode
+		filePath := ""
+		if module != nil {
+			focus, filePath = module.Path, module.Path
+		}
+		if result.Path != "" {
+			focus += " " + result.Path
+		}
+
+		violations = append(violations, Violation{
+			Rule:       violationRule,
+			Module:     module,
+			Message:    fmt.Sprintf("%s: %s: %s", rule.Name, focus, result.Message),
+			FilePath:   filePath,
+			Suggestion: rule.Suggestion,
+			Details: map[string]any{
+				"focusNode":   result.FocusNode,
+				"path":        result.Path,
+				"value":       result.Value,
+				"component":   "sh:" + result.Component,
+				"sourceShape": result.SourceShape.ID,
+			},
+		})
+	}
+
+	return violations, nil
+}
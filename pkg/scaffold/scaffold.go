@@ -0,0 +1,249 @@
+/*
+# Module: pkg/scaffold/scaffold.go
+Module templates for new source files.
+
+Renders a source file with a LinkedDoc header in the comment syntax of the
+target language, optionally followed by a minimal language-specific stub.
+Declared links are resolved against the knowledge graph so new files never
+start out with dangling linksTo references.
+
+## Linked Modules
+- [../graph](../graph/graph.go) - Graph data structure
+- [../scanner](../scanner/language.go) - Language detection
+- [../parser](../parser/comments.go) - LinkedDoc comment styles
+
+## Tags
+scaffold, templates, linkeddoc, cli-support
+
+## Exports
+Options, Link, Render, ResolveLinks
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#scaffold.go> a code:Module ;
+    code:name "pkg/scaffold/scaffold.go" ;
+    code:description "Module templates for new source files" ;
+    code:language "go" ;
+    code:layer "scaffold" ;
+    code:linksTo <../graph/graph.go>, <../scanner/language.go>, <../parser/comments.go> ;
+    code:exports <#Options>, <#Link>, <#Render>, <#ResolveLinks> ;
+    code:tags "scaffold", "templates", "linkeddoc", "cli-support" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// Options describes the module to scaffold
+type Options struct {
+	Path        string   // File path relative to the project root
+	Description string   // One-line module description
+	Layer       string   // Architectural layer
+	Tags        []string // Module tags
+	Links       []Link   // Modules the new file links to
+	Exports     []string // Exported symbols
+	Stub        bool     // Append a language-specific stub after the header
+}
+
+// Link is a linked module, with paths relative to the project root
+type Link struct {
+	Path        string
+	Description string
+}
+
+// preambles are emitted before the header comment of a language
+var preambles = map[string]string{
+	"php": "<?php",
+}
+
+// Render returns the contents of a new source file for opts
+func Render(opts Options) (string, error) {
+	relPath := filepath.ToSlash(filepath.Clean(opts.Path))
+	language := scanner.DetectLanguageKey(relPath)
+	style := parser.CommentStyleFor(language)
+	if style.BlockStart == "" && style.LinePrefix == "" {
+		return "", fmt.Errorf("unsupported language for %s", relPath)
+	}
+
+	description := strings.TrimSuffix(strings.TrimSpace(opts.Description), ".")
+	if description == "" {
+		description = strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath)) + " module"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Module: %s\n", relPath)
+	fmt.Fprintf(&b, "%s.\n", description)
+
+	if len(opts.Links) > 0 {
+		b.WriteString("\n## Linked Modules\n")
+		for _, link := range opts.Links {
+			label := strings.TrimSuffix(link.Path, filepath.Ext(link.Path))
+			fmt.Fprintf(&b, "- [%s](%s)", label, relativeLink(relPath, link.Path))
+			if link.Description != "" {
+				fmt.Fprintf(&b, " - %s", link.Description)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(opts.Tags) > 0 {
+		fmt.Fprintf(&b, "\n## Tags\n%s\n", strings.Join(opts.Tags, ", "))
+	}
+	if len(opts.Exports) > 0 {
+		fmt.Fprintf(&b, "\n## Exports\n%s\n", strings.Join(opts.Exports, ", "))
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", style.StartMarker)
+	b.WriteString("@prefix code: <https://schema.codedoc.org/> .\n")
+	b.WriteString("@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .\n\n")
+
+	predicates := []string{
+		fmt.Sprintf("code:name %q", relPath),
+		fmt.Sprintf("code:description %q", description),
+		fmt.Sprintf("code:language %q", language),
+	}
+	if opts.Layer != "" {
+		predicates = append(predicates, fmt.Sprintf("code:layer %q", opts.Layer))
+	}
+	if len(opts.Links) > 0 {
+		links := make([]string, len(opts.Links))
+		for i, link := range opts.Links {
+			links[i] = "<" + relativeLink(relPath, link.Path) + ">"
+		}
+		predicates = append(predicates, "code:linksTo "+strings.Join(links, ", "))
+	}
+	if len(opts.Exports) > 0 {
+		exports := make([]string, len(opts.Exports))
+		for i, export := range opts.Exports {
+			exports[i] = "<#" + export + ">"
+		}
+		predicates = append(predicates, "code:exports "+strings.Join(exports, ", "))
+	}
+	if len(opts.Tags) > 0 {
+		tags := make([]string, len(opts.Tags))
+		for i, tag := range opts.Tags {
+			tags[i] = fmt.Sprintf("%q", tag)
+		}
+		predicates = append(predicates, "code:tags "+strings.Join(tags, ", "))
+	}
+
+	fmt.Fprintf(&b, "<#%s> a code:Module ;\n", relPath)
+	fmt.Fprintf(&b, "    %s .\n", strings.Join(predicates, " ;\n    "))
+	fmt.Fprintf(&b, "%s\n", style.EndMarker)
+
+	header := wrapHeader(b.String(), style, preambles[language])
+
+	if opts.Stub {
+		if stub := languageStub(language, relPath, opts.Exports); stub != "" {
+			header += "\n" + stub
+		}
+	}
+
+	return header, nil
+}
+
+// wrapHeader puts the header text in the language's block comment, or
+// behind its line comment leader when it has no block comments
+func wrapHeader(text string, style parser.CommentStyle, preamble string) string {
+	var b strings.Builder
+	if preamble != "" {
+		b.WriteString(preamble + "\n")
+	}
+	if style.LinePrefix == "" {
+		b.WriteString(style.BlockStart + "\n" + text + style.BlockEnd + "\n")
+		return b.String()
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		if line == "\n" {
+			b.WriteString(style.LinePrefix + "\n")
+			continue
+		}
+		b.WriteString(style.LinePrefix + " " + line)
+	}
+	return b.String()
+}
+
+// ResolveLinks looks up each path in the graph, returning the resolved links
+// (with module descriptions) and the paths that are not graph modules
+func ResolveLinks(g *graph.Graph, paths []string) ([]Link, []string) {
+	var links []Link
+	var unresolved []string
+
+	for _, path := range paths {
+		path = filepath.ToSlash(filepath.Clean(path))
+		module, exists := g.Modules[path]
+		if !exists {
+			unresolved = append(unresolved, path)
+			continue
+		}
+		links = append(links, Link{Path: path, Description: module.Description})
+	}
+
+	return links, unresolved
+}
+
+// languageStub returns a minimal body for the language, or "" if none
+func languageStub(language, relPath string, exports []string) string {
+	base := strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+
+	switch language {
+	case "go":
+		return fmt.Sprintf("package %s\n", goPackageName(relPath))
+	case "python":
+		quoted := make([]string, len(exports))
+		for i, export := range exports {
+			quoted[i] = fmt.Sprintf("%q", export)
+		}
+		return fmt.Sprintf("__all__ = [%s]\n", strings.Join(quoted, ", "))
+	case "javascript", "typescript":
+		if len(exports) == 0 {
+			return "export {};\n"
+		}
+		return fmt.Sprintf("export { %s };\n", strings.Join(exports, ", "))
+	case "java":
+		return fmt.Sprintf("public class %s {\n}\n", base)
+	}
+	return ""
+}
+
+// goPackageName derives a package name from the file's directory
+func goPackageName(relPath string) string {
+	dir := filepath.Base(filepath.Dir(relPath))
+	if dir == "." || dir == "/" {
+		return "main"
+	}
+
+	name := strings.ToLower(dir)
+	name = strings.NewReplacer("-", "", ".", "", " ", "").Replace(name)
+	if name == "" {
+		return "main"
+	}
+	return name
+}
+
+// relativeLink returns target as a link relative to the directory of from,
+// using the ./ and ../ forms LinkedDoc headers use
+func relativeLink(from, target string) string {
+	rel, err := filepath.Rel(filepath.Dir(from), target)
+	if err != nil {
+		return target
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
@@ -0,0 +1,114 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+)
+
+func TestRender_Go(t *testing.T) {
+	content, err := Render(Options{
+		Path:        "services/auth.go",
+		Description: "Authentication service.",
+		Layer:       "service",
+		Tags:        []string{"auth", "security"},
+		Links:       []Link{{Path: "models/user.go", Description: "User data model"}, {Path: "services/user.go"}},
+		Exports:     []string{"AuthService"},
+		Stub:        true,
+	})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"/*\n# Module: services/auth.go\nAuthentication service.\n",
+		"- [models/user](../models/user.go) - User data model\n",
+		"- [services/user](./user.go)\n",
+		"code:linksTo <../models/user.go>, <./user.go> ;",
+		"code:tags \"auth\", \"security\" .",
+		"*/\n\npackage services\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected rendered file to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	triples, err := parser.NewParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("Rendered header does not parse: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, triple := range triples {
+		if strings.HasSuffix(triple.Predicate, "layer") || strings.HasSuffix(triple.Predicate, "exports") {
+			found[triple.Predicate] = true
+		}
+	}
+	if len(found) != 2 {
+		t.Errorf("Expected layer and exports triples, got %v", triples)
+	}
+}
+
+func TestRender_CommentStyles(t *testing.T) {
+	python, err := Render(Options{Path: "scripts/sync.py", Exports: []string{"run"}, Stub: true})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasPrefix(python, "\"\"\"\n# Module: scripts/sync.py\nsync module.\n") {
+		t.Errorf("Expected docstring header with default description, got:\n%s", python)
+	}
+	if !strings.HasSuffix(python, "\"\"\"\n\n__all__ = [\"run\"]\n") {
+		t.Errorf("Expected __all__ stub, got:\n%s", python)
+	}
+
+	header, err := Render(Options{Path: "web/app.ts"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasSuffix(header, "<!-- End LinkedDoc RDF -->\n*/\n") {
+		t.Errorf("Expected header only without stub, got:\n%s", header)
+	}
+
+	if _, err := Render(Options{Path: "notes.txt"}); err == nil {
+		t.Error("Expected error for unsupported language")
+	}
+}
+
+func TestRender_LinePrefix(t *testing.T) {
+	parser.RegisterCommentStyle("ruby", parser.CommentStyle{LinePrefix: "#", StartMarker: "LinkedDoc:"})
+
+	content, err := Render(Options{Path: "lib/sync.rb"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasPrefix(content, "# # Module: lib/sync.rb\n# sync module.\n#\n") {
+		t.Errorf("Expected line comment header, got:\n%s", content)
+	}
+	if !strings.Contains(content, "\n# LinkedDoc:\n") || strings.Contains(content, "=begin") {
+		t.Errorf("Expected the configured start marker without block delimiters, got:\n%s", content)
+	}
+
+	triples, err := parser.NewParser().ParseStringFor(content, "ruby")
+	if err != nil || len(triples) == 0 {
+		t.Errorf("Rendered header does not parse: %v (%d triples)", err, len(triples))
+	}
+}
+
+func TestResolveLinks(t *testing.T) {
+	g := graph.NewGraph("/project", store.NewTripleStore())
+	user := graph.NewModule("models/user.go", "<#models/user.go>")
+	user.Description = "User data model"
+	g.AddModule(user)
+
+	links, unresolved := ResolveLinks(g, []string{"./models/user.go", "models/missing.go"})
+
+	if len(links) != 1 || links[0].Path != "models/user.go" || links[0].Description != "User data model" {
+		t.Errorf("Expected models/user.go to resolve with its description, got %+v", links)
+	}
+	if len(unresolved) != 1 || unresolved[0] != "models/missing.go" {
+		t.Errorf("Expected models/missing.go to be unresolved, got %v", unresolved)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+# Module: pkg/scanner/content.go
+Content sniffing for scanned files.
+
+Detects binary files from their leading bytes so they are skipped even when
+their extension looks like source code, and detects generated-code markers
+such as "Code generated by ... DO NOT EDIT".
+
+## Linked Modules
+None (utility module with no dependencies)
+
+## Tags
+scanner, content-detection, generated-code, utility
+
+## Exports
+IsBinaryContent, IsGeneratedContent
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#content.go> a code:Module ;
+    code:name "pkg/scanner/content.go" ;
+    code:description "Content sniffing for scanned files" ;
+    code:language "go" ;
+    code:layer "scanner" ;
+    code:exports <#IsBinaryContent>, <#IsGeneratedContent> ;
+    code:tags "scanner", "content-detection", "generated-code", "utility" ;
+    code:isLeaf true .
+
+<#IsBinaryContent> a code:Function ;
+    code:name "IsBinaryContent" ;
+    code:description "Reports whether content looks like a binary file" .
+
+<#IsGeneratedContent> a code:Function ;
+    code:name "IsGeneratedContent" ;
+    code:description "Reports whether content carries a generated-code marker" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package scanner
+
+import (
+	"bytes"
+	"regexp"
+	"unicode/utf8"
+)
+
+// sniffLength is the number of leading bytes inspected for binary detection
+const sniffLength = 8000
+
+// generatedMarker matches the conventional generated-code comment
+// (https://golang.org/s/generatedcode), accepting common comment styles
+var generatedMarker = regexp.MustCompile(`(?m)^\s*(?://|#|--|/?\*)?\s*Code generated\b.*\bDO NOT EDIT\b`)
+
+// IsBinaryContent reports whether content looks like a binary file.
+// A NUL byte or a high ratio of invalid UTF-8 in the leading bytes marks the
+// content as binary.
+func IsBinaryContent(content []byte) bool {
+	if len(content) > sniffLength {
+		content = content[:sniffLength]
+	}
+
+	if bytes.IndexByte(content, 0) != -1 {
+		return true
+	}
+
+	invalid := 0
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRune(content[i:])
+		if r == utf8.RuneError && size == 1 {
+			// A multi-byte rune cut off by the sniff window is not invalid
+			if len(content)-i < utf8.UTFMax && !utf8.FullRune(content[i:]) {
+				break
+			}
+			invalid++
+		}
+		i += size
+	}
+
+	return len(content) > 0 && invalid*10 > len(content)
+}
+
+// IsGeneratedContent reports whether content carries a generated-code marker
+// such as "// Code generated by protoc-gen-go. DO NOT EDIT."
+func IsGeneratedContent(content []byte) bool {
+	return generatedMarker.Match(content)
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsBinaryContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{"empty", []byte{}, false},
+		{"source", []byte("package main\n\nfunc main() {}\n"), false},
+		{"utf8", []byte("// héllo wörld ✓\n"), false},
+		{"nul byte", []byte("ELF\x00\x01\x02"), true},
+		{"invalid utf8", []byte{0xff, 0xfe, 0xfd, 0xfc, 'a', 'b'}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBinaryContent(tt.content); got != tt.want {
+				t.Errorf("IsBinaryContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGeneratedContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n", true},
+		{"python", "# Code generated by tool. DO NOT EDIT.\n", true},
+		{"block comment", "/*\n * Code generated by openapi-generator. DO NOT EDIT.\n */\n", true},
+		{"plain", "package main\n\n// DO NOT EDIT this by hand\n", false},
+		{"mid line", "x := \"Code generated by foo. DO NOT EDIT.\"\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGeneratedContent([]byte(tt.content)); got != tt.want {
+				t.Errorf("IsGeneratedContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_SkipsBinaryAndFlagsGenerated(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string][]byte{
+		"main.go":   []byte("package main\n\nfunc main() {}\n"),
+		"gen.pb.go": []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage main\n"),
+		"blob.go":   {0x7f, 'E', 'L', 'F', 0x00, 0x01},
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	opts := DefaultScanOptions()
+	opts.Concurrent = false
+	result, err := NewScanner().Scan(dir, opts)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	found := make(map[string]*FileInfo)
+	for _, file := range result.Files {
+		found[filepath.Base(file.Path)] = file
+	}
+
+	if _, ok := found["blob.go"]; ok {
+		t.Error("Binary file should be skipped")
+	}
+	if gen, ok := found["gen.pb.go"]; !ok || !gen.Generated {
+		t.Error("Generated file should be scanned and flagged")
+	}
+	if main, ok := found["main.go"]; !ok || main.Generated {
+		t.Error("Hand-written file should be scanned and not flagged")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+# Module: pkg/scanner/duplicates.go
+Detection of files reachable through several paths.
+
+Symlinks and hard links make one physical file reachable through several
+paths. Files are bucketed by size and compared by device and inode
+(os.SameFile), and each physical file is kept once, under its canonical
+path, with the other paths recorded as aliases.
+
+## Linked Modules
+- [scanner](./scanner.go) - Filesystem scanner
+
+## Tags
+scanner, filesystem, symlinks
+
+## Exports
+MergeDuplicates
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#duplicates.go> a code:Module ;
+    code:name "pkg/scanner/duplicates.go" ;
+    code:description "Detection of files reachable through several paths" ;
+    code:language "go" ;
+    code:layer "scanner" ;
+    code:linksTo <./scanner.go> ;
+    code:exports <#MergeDuplicates> ;
+    code:tags "scanner", "filesystem", "symlinks" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package scanner
+
+import (
+	"os"
+	"sort"
+)
+
+// MergeDuplicates keeps one entry per physical file, preferring a path that
+// is not a symlink, then the shortest path. The other paths are recorded in
+// the kept entry's Aliases. It returns the remaining files, in their
+// original order, and the number of paths merged.
+func MergeDuplicates(files []*FileInfo) ([]*FileInfo, int) {
+	bySize := make(map[int64][]int)
+	for i, file := range files {
+		bySize[file.Size] = append(bySize[file.Size], i)
+	}
+
+	merged := make(map[int]bool)
+	for _, indexes := range bySize {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		stats := make(map[int]os.FileInfo, len(indexes))
+		for _, i := range indexes {
+			if info, err := os.Stat(files[i].Path); err == nil {
+				stats[i] = info
+			}
+		}
+
+		for a, i := range indexes {
+			if merged[i] || stats[i] == nil {
+				continue
+			}
+			group := []int{i}
+			for _, j := range indexes[a+1:] {
+				if !merged[j] && stats[j] != nil && os.SameFile(stats[i], stats[j]) {
+					group = append(group, j)
+				}
+			}
+			if len(group) < 2 {
+				continue
+			}
+
+			sort.Slice(group, func(x, y int) bool {
+				return preferredPath(files[group[x]].Path, files[group[y]].Path)
+			})
+			canonical := files[group[0]]
+			for _, j := range group[1:] {
+				canonical.Aliases = append(canonical.Aliases, files[j].Path)
+				canonical.Aliases = append(canonical.Aliases, files[j].Aliases...)
+				merged[j] = true
+			}
+			sort.Strings(canonical.Aliases)
+		}
+	}
+
+	if len(merged) == 0 {
+		return files, 0
+	}
+	kept := make([]*FileInfo, 0, len(files)-len(merged))
+	for i, file := range files {
+		if !merged[i] {
+			kept = append(kept, file)
+		}
+	}
+	return kept, len(merged)
+}
+
+// preferredPath reports whether path a is a better canonical path than b
+func preferredPath(a, b string) bool {
+	if linkA, linkB := isSymlink(a), isSymlink(b); linkA != linkB {
+		return linkB
+	}
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func isSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMergeDuplicates(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	original := write("original.go", "package a\n")
+	twin := write("twin.go", "package a\n") // Same content, different file
+	link := filepath.Join(root, "a.go")
+	if err := os.Symlink(original, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	var files []*FileInfo
+	for _, path := range []string{link, original, twin} {
+		info, err := NewScanner().ScanFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, info)
+	}
+
+	kept, merged := MergeDuplicates(files)
+	if merged != 1 || len(kept) != 2 {
+		t.Fatalf("Expected 1 merged path and 2 files, got %d and %d", merged, len(kept))
+	}
+	// The symlink is shorter, but the real path is preferred
+	if kept[0].Path != original || len(kept[0].Aliases) != 1 || kept[0].Aliases[0] != link {
+		t.Errorf("Kept %s with aliases %v, want %s with alias %s", kept[0].Path, kept[0].Aliases, original, link)
+	}
+	if kept[1].Path != twin || len(kept[1].Aliases) != 0 {
+		t.Errorf("Files with equal content should not be merged: %+v", kept[1])
+	}
+}
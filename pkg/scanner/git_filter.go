@@ -157,6 +157,102 @@ func (g *GitFilter) StagedChanges() ([]string, error) {
 	return g.parseFileList(stdout.String()), nil
 }
 
+// CommitCounts returns the number of commits touching each file since a
+// date or duration in git --since syntax (all history if empty). Paths are
+// relative to the repository path, with forward slashes.
+func (g *GitFilter) CommitCounts(since string) (map[string]int, error) {
+	args := []string{"log", "--name-only", "--format=", "--relative"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.repoPath
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %s: %w", stderr.String(), err)
+	}
+
+	counts := make(map[string]int)
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			counts[line]++
+		}
+	}
+	return counts, nil
+}
+
+// DiffFromCommit returns files that differ between a commit and the working
+// tree, including untracked files. Renames are reported as a deletion and an
+// addition, so both paths are returned.
+func (g *GitFilter) DiffFromCommit(commit string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "--no-renames", "--relative", commit)
+	cmd.Dir = g.repoPath
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git diff failed: %s: %w", stderr.String(), err)
+	}
+	files := g.parseFileList(stdout.String())
+
+	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	cmd.Dir = g.repoPath
+	stdout.Reset()
+	stderr.Reset()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %s: %w", stderr.String(), err)
+	}
+
+	return append(files, g.parseFileList(stdout.String())...), nil
+}
+
+// ChangedInRange returns files changed in a diff range such as main...HEAD or
+// a single ref, or the staged changes when the range is empty. Only files
+// below the repository path are returned.
+func (g *GitFilter) ChangedInRange(rng string) ([]string, error) {
+	args := []string{"diff", "--name-only", "--relative"}
+	if rng == "" {
+		args = append(args, "--cached")
+	} else {
+		args = append(args, rng)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.repoPath
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git diff failed: %s: %w", stderr.String(), err)
+	}
+
+	return g.parseFileList(stdout.String()), nil
+}
+
+// HeadCommit returns the commit hash of HEAD
+func (g *GitFilter) HeadCommit() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = g.repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 // parseFileList parses newline-separated file list from git output
 func (g *GitFilter) parseFileList(output string) []string {
 	output = strings.TrimSpace(output)
@@ -12,7 +12,7 @@ None (utility module with no dependencies)
 scanner, language-detection, utility
 
 ## Exports
-Language, DetectLanguage, RegisterLanguage, SupportedLanguages
+Language, DetectLanguage, DetectLanguageKey, RegisterLanguage, SupportedLanguages
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -23,7 +23,7 @@ Language, DetectLanguage, RegisterLanguage, SupportedLanguages
     code:description "Language detection for source files" ;
     code:language "go" ;
     code:layer "scanner" ;
-    code:exports <#Language>, <#DetectLanguage>, <#RegisterLanguage>, <#SupportedLanguages> ;
+    code:exports <#Language>, <#DetectLanguage>, <#DetectLanguageKey>, <#RegisterLanguage>, <#SupportedLanguages> ;
     code:tags "scanner", "language-detection", "utility" ;
     code:isLeaf true .
 
@@ -145,6 +145,15 @@ func DetectLanguage(filePath string) string {
 	return "unknown"
 }
 
+// DetectLanguageKey returns the registry key (e.g. "go", "cpp") for a file
+// path, or "unknown"
+func DetectLanguageKey(filePath string) string {
+	if langKey, ok := extensionMap[strings.ToLower(filepath.Ext(filePath))]; ok {
+		return langKey
+	}
+	return "unknown"
+}
+
 // RegisterLanguage registers a new language or updates an existing one
 func RegisterLanguage(key string, name string, extensions []string) {
 	languageRegistry[key] = &Language{
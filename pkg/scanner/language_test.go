@@ -42,6 +42,22 @@ func TestDetectLanguage(t *testing.T) {
 	}
 }
 
+func TestDetectLanguageKey(t *testing.T) {
+	tests := map[string]string{
+		"main.go":       "go",
+		"program.cpp":   "cpp",
+		"Program.cs":    "csharp",
+		"Component.TSX": "typescript",
+		"README":        "unknown",
+	}
+
+	for filePath, want := range tests {
+		if got := DetectLanguageKey(filePath); got != want {
+			t.Errorf("DetectLanguageKey(%q) = %q, want %q", filePath, got, want)
+		}
+	}
+}
+
 func TestRegisterLanguage(t *testing.T) {
 	// Register custom language
 	RegisterLanguage("custom", "CustomLang", []string{".custom", ".cst"})
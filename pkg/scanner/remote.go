@@ -0,0 +1,277 @@
+/*
+# Module: pkg/scanner/remote.go
+Resumable, rate-limited scanning for remote and network-mounted roots.
+
+Scanning a large NFS or SMB mount is slow and a single flaky read should not
+force a rebuild from scratch. A scan can record the files it has scanned in a
+checkpoint file, saved periodically and when the scan fails; a later scan of
+the same root reuses every entry whose size and modification time still
+match, without reading the file again. Reads can be rate limited to spare the
+host, and failing stats and reads are retried with exponential backoff.
+
+## Linked Modules
+- [scanner](./scanner.go) - Filesystem scanner
+
+## Tags
+scanner, remote, checkpoint, retry
+
+## Exports
+Checkpoint, LoadCheckpoint
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#remote.go> a code:Module ;
+    code:name "pkg/scanner/remote.go" ;
+    code:description "Resumable, rate-limited scanning for remote and network-mounted roots" ;
+    code:language "go" ;
+    code:layer "scanner" ;
+    code:linksTo <./scanner.go> ;
+    code:exports <#Checkpoint>, <#LoadCheckpoint> ;
+    code:tags "scanner", "remote", "checkpoint", "retry" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// checkpointEvery is the number of newly scanned files between saves
+	checkpointEvery = 500
+
+	defaultRetryBackoff = 200 * time.Millisecond
+	maxRetryBackoff     = 10 * time.Second
+)
+
+// Checkpoint records the files a scan has already scanned, so an
+// interrupted scan of the same root can resume
+type Checkpoint struct {
+	Root  string               `json:"root"`
+	Files map[string]*FileInfo `json:"files"`
+
+	path    string
+	mu      sync.Mutex
+	pending int
+}
+
+// LoadCheckpoint loads the checkpoint at path for a scan of root. A missing
+// checkpoint, or one recorded for another root, yields an empty checkpoint.
+func LoadCheckpoint(path, root string) (*Checkpoint, error) {
+	cp := &Checkpoint{Root: root, Files: make(map[string]*FileInfo), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var saved Checkpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if saved.Root == root && saved.Files != nil {
+		cp.Files = saved.Files
+	}
+	return cp, nil
+}
+
+// Len returns the number of files recorded
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.Files)
+}
+
+// Save writes the checkpoint, replacing the previous one atomically
+func (c *Checkpoint) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.save()
+}
+
+func (c *Checkpoint) save() error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	c.pending = 0
+	return nil
+}
+
+// Remove deletes the checkpoint file once the scan it covers has completed
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+// lookup returns the recorded entry for a file that has not changed since
+func (c *Checkpoint) lookup(path string, info os.FileInfo) *FileInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.Files[path]
+	if entry == nil || entry.Size != info.Size() || !entry.ModTime.Equal(info.ModTime()) {
+		return nil
+	}
+	copied := *entry
+	copied.Aliases = nil
+	return &copied
+}
+
+// record adds a scanned file, saving every checkpointEvery files. A failed
+// periodic save is not fatal; the next one retries.
+func (c *Checkpoint) record(file *FileInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	copied := *file
+	c.Files[file.Path] = &copied
+	c.pending++
+	if c.pending >= checkpointEvery {
+		_ = c.save()
+	}
+}
+
+// rateLimiter spaces file reads evenly; a nil limiter does not wait
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{ticker: time.NewTicker(time.Duration(float64(time.Second) / perSecond))}
+}
+
+func (r *rateLimiter) wait() {
+	if r != nil {
+		<-r.ticker.C
+	}
+}
+
+func (r *rateLimiter) stop() {
+	if r != nil {
+		r.ticker.Stop()
+	}
+}
+
+// scanRun holds the state a scan shares between files
+type scanRun struct {
+	opts       ScanOptions
+	limiter    *rateLimiter
+	checkpoint *Checkpoint
+	resumed    int
+	mu         sync.Mutex
+}
+
+func newScanRun(rootPath string, opts ScanOptions) (*scanRun, error) {
+	run := &scanRun{opts: opts, limiter: newRateLimiter(opts.RateLimit)}
+	if opts.Checkpoint != "" {
+		cp, err := LoadCheckpoint(opts.Checkpoint, rootPath)
+		if err != nil {
+			return nil, err
+		}
+		run.checkpoint = cp
+	}
+	return run, nil
+}
+
+// finish stops the limiter and keeps the checkpoint only if the scan failed
+func (r *scanRun) finish(scanErr error) error {
+	r.limiter.stop()
+	if r.checkpoint == nil {
+		return nil
+	}
+	if scanErr != nil {
+		return r.checkpoint.Save()
+	}
+	return r.checkpoint.Remove()
+}
+
+// scanFile scans a file, reusing its checkpoint entry when unchanged and
+// retrying failed stats and reads
+func (r *scanRun) scanFile(s *Scanner, path string) (*FileInfo, error) {
+	if r.checkpoint != nil {
+		var info os.FileInfo
+		err := r.retry(func() (err error) {
+			info, err = os.Stat(path)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file: %w", err)
+		}
+		if entry := r.checkpoint.lookup(path, info); entry != nil {
+			r.mu.Lock()
+			r.resumed++
+			r.mu.Unlock()
+			return entry, nil
+		}
+	}
+
+	r.limiter.wait()
+
+	var fileInfo *FileInfo
+	err := r.retry(func() (err error) {
+		fileInfo, err = s.inspectFile(path, r.opts.Retries > 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.checkpoint != nil {
+		r.checkpoint.record(fileInfo)
+	}
+	return fileInfo, nil
+}
+
+// retry runs fn until it succeeds, fails with an error retrying cannot fix,
+// or has been retried opts.Retries times, doubling the backoff each time
+func (r *scanRun) retry(fn func() error) error {
+	backoff := r.opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.opts.Retries || !retryable(err) {
+			return err
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// retryable reports whether an IO error may be transient
+func retryable(err error) bool {
+	return !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fs.ErrPermission)
+}
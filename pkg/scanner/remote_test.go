@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScan_ResumesFromCheckpoint(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	// Record a.go as if an earlier, interrupted scan had found LinkedDoc in it
+	cp, err := LoadCheckpoint(checkpointPath, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := NewScanner().ScanFile(filepath.Join(root, "a.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded.HasLinkedDoc = true
+	cp.record(recorded)
+	if err := cp.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ScanOptions{Checkpoint: checkpointPath, Retries: 2}
+	for _, concurrent := range []bool{false, true} {
+		opts.Concurrent = concurrent
+		if err := cp.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := NewScanner().Scan(root, opts)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if result.Resumed != 1 || result.TotalFiles != 2 {
+			t.Errorf("Expected 1 of 2 files resumed, got %d of %d", result.Resumed, result.TotalFiles)
+		}
+		for _, file := range result.Files {
+			if want := filepath.Base(file.Path) == "a.go"; file.HasLinkedDoc != want {
+				t.Errorf("%s: HasLinkedDoc = %v, want %v", file.Path, file.HasLinkedDoc, want)
+			}
+		}
+		if _, err := os.Stat(checkpointPath); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Checkpoint should be removed after a successful scan, got %v", err)
+		}
+	}
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	cp, err := LoadCheckpoint(path, "/a")
+	if err != nil || cp.Len() != 0 {
+		t.Fatalf("Missing checkpoint should load empty, got %d entries, %v", cp.Len(), err)
+	}
+
+	cp.record(&FileInfo{Path: "/a/main.go", Size: 10, ModTime: time.Now()})
+	if err := cp.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if cp, _ := LoadCheckpoint(path, "/a"); cp.Len() != 1 {
+		t.Errorf("Expected 1 entry for the same root, got %d", cp.Len())
+	}
+	if cp, _ := LoadCheckpoint(path, "/b"); cp.Len() != 0 {
+		t.Errorf("Checkpoint of another root should be ignored, got %d entries", cp.Len())
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCheckpoint(path, "/a"); err == nil {
+		t.Error("Expected error for corrupt checkpoint")
+	}
+}
+
+func TestScanRun_Retry(t *testing.T) {
+	run := &scanRun{opts: ScanOptions{Retries: 3, RetryBackoff: time.Millisecond}}
+
+	attempts := 0
+	err := run.retry(func() error {
+		if attempts++; attempts < 3 {
+			return errors.New("stale file handle")
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Expected success on attempt 3, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = run.retry(func() error {
+		attempts++
+		return fs.ErrNotExist
+	})
+	if !errors.Is(err, fs.ErrNotExist) || attempts != 1 {
+		t.Errorf("Missing files should not be retried, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	run.retry(func() error {
+		attempts++
+		return errors.New("input/output error")
+	})
+	if attempts != 4 {
+		t.Errorf("Expected 1 attempt and 3 retries, got %d attempts", attempts)
+	}
+}
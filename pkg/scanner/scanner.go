@@ -3,11 +3,15 @@
 Filesystem scanner for GraphFS.
 
 Recursively scans directories to find source code files with language detection,
-ignore pattern filtering, and LinkedDoc detection.
+ignore pattern filtering, and LinkedDoc detection. Binary files are skipped
+by content sniffing and generated files are flagged. A file reachable through
+several paths is reported once, with its other paths as aliases.
 
 ## Linked Modules
 - [language](./language.go) - Language detection
 - [ignore](./ignore.go) - Ignore pattern matching
+- [content](./content.go) - Binary and generated-code detection
+- [duplicates](./duplicates.go) - Duplicate path detection
 - [../parser](../parser/parser.go) - LinkedDoc detection
 
 ## Tags
@@ -25,7 +29,7 @@ Scanner, NewScanner, ScanOptions, ScanResult, FileInfo
     code:description "Filesystem scanner for GraphFS" ;
     code:language "go" ;
     code:layer "scanner" ;
-    code:linksTo <./language.go>, <./ignore.go>, <../parser/parser.go> ;
+    code:linksTo <./language.go>, <./ignore.go>, <./content.go>, <./duplicates.go>, <../parser/parser.go> ;
     code:exports <#Scanner>, <#NewScanner>, <#ScanOptions>, <#ScanResult>, <#FileInfo> ;
     code:tags "scanner", "filesystem", "recursive" .
 
@@ -58,6 +62,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -83,6 +88,12 @@ type ScanOptions struct {
 	MaxErrors       int           // Stop after N errors (0 = unlimited)
 	Timeout         time.Duration // Overall operation timeout (0 = no timeout)
 	FileTimeout     time.Duration // Per-file parse timeout (0 = no timeout)
+
+	// Remote and network-mounted roots
+	RateLimit    float64       // Maximum files read per second (0 = unlimited)
+	Retries      int           // Retries of a failed stat or read (0 = none)
+	RetryBackoff time.Duration // Delay before the first retry, doubled after each (0 = 200ms)
+	Checkpoint   string        // Checkpoint file for resumable scans ("" = disabled)
 }
 
 // DefaultScanOptions returns default scan options
@@ -105,6 +116,8 @@ type ScanResult struct {
 	Errors       *ErrorCollector
 	FilesScanned int
 	FilesFailed  int
+	Duplicates   int // Paths merged into another file's aliases
+	Resumed      int // Files reused from the checkpoint without reading
 	Duration     time.Duration
 }
 
@@ -115,6 +128,11 @@ type FileInfo struct {
 	Size         int64
 	ModTime      time.Time
 	HasLinkedDoc bool
+	Binary       bool // Content sniffing found binary data
+	Generated    bool // File carries a "Code generated ... DO NOT EDIT" marker
+
+	// Aliases are other paths of the same physical file (symlinks, hard links)
+	Aliases []string
 }
 
 // NewScanner creates a new filesystem scanner
@@ -142,6 +160,11 @@ func (s *Scanner) Scan(rootPath string, opts ScanOptions) (*ScanResult, error) {
 	// Build ignore matcher
 	ignoreMatcher := s.buildIgnoreMatcher(absPath, opts)
 
+	run, err := newScanRun(absPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &ScanResult{
 		Files:  make([]*FileInfo, 0),
 		Errors: NewErrorCollector(),
@@ -149,15 +172,24 @@ func (s *Scanner) Scan(rootPath string, opts ScanOptions) (*ScanResult, error) {
 
 	// Scan based on concurrency setting
 	if opts.Concurrent {
-		err = s.scanConcurrent(absPath, ignoreMatcher, opts, result)
+		err = s.scanConcurrent(absPath, ignoreMatcher, run, result)
 	} else {
-		err = s.scanSequential(absPath, ignoreMatcher, opts, result)
+		err = s.scanSequential(absPath, ignoreMatcher, run, result)
+	}
+
+	// Keep the checkpoint of a failed scan for the next attempt
+	if cpErr := run.finish(err); cpErr != nil && err == nil {
+		return nil, cpErr
 	}
 
 	// Check if scan was aborted due to strict mode or max errors
 	if err != nil {
 		return nil, err
 	}
+	result.Resumed = run.resumed
+
+	// Keep one entry per physical file
+	result.Files, result.Duplicates = MergeDuplicates(result.Files)
 
 	result.Duration = time.Since(startTime)
 	result.TotalFiles = len(result.Files)
@@ -171,7 +203,8 @@ func (s *Scanner) Scan(rootPath string, opts ScanOptions) (*ScanResult, error) {
 }
 
 // scanSequential performs sequential directory scanning
-func (s *Scanner) scanSequential(rootPath string, ignoreMatcher *IgnoreMatcher, opts ScanOptions, result *ScanResult) error {
+func (s *Scanner) scanSequential(rootPath string, ignoreMatcher *IgnoreMatcher, run *scanRun, result *ScanResult) error {
+	opts := run.opts
 	var scanErr error
 
 	filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
@@ -222,7 +255,7 @@ func (s *Scanner) scanSequential(rootPath string, ignoreMatcher *IgnoreMatcher,
 		result.FilesScanned++
 
 		// Scan the file
-		fileInfo, err := s.ScanFile(path)
+		fileInfo, err := run.scanFile(s, path)
 		if err != nil {
 			result.Errors.Add(path, err)
 			result.FilesFailed++
@@ -247,8 +280,8 @@ func (s *Scanner) scanSequential(rootPath string, ignoreMatcher *IgnoreMatcher,
 			return nil
 		}
 
-		// Only include source files (not unknown language)
-		if fileInfo.Language != "unknown" {
+		// Only include source files (not unknown language or binary)
+		if fileInfo.Language != "unknown" && !fileInfo.Binary {
 			result.Files = append(result.Files, fileInfo)
 		}
 
@@ -259,7 +292,8 @@ func (s *Scanner) scanSequential(rootPath string, ignoreMatcher *IgnoreMatcher,
 }
 
 // scanConcurrent performs concurrent directory scanning
-func (s *Scanner) scanConcurrent(rootPath string, ignoreMatcher *IgnoreMatcher, opts ScanOptions, result *ScanResult) error {
+func (s *Scanner) scanConcurrent(rootPath string, ignoreMatcher *IgnoreMatcher, run *scanRun, result *ScanResult) error {
+	opts := run.opts
 	var (
 		wg        sync.WaitGroup
 		mu        sync.Mutex
@@ -284,7 +318,7 @@ func (s *Scanner) scanConcurrent(rootPath string, ignoreMatcher *IgnoreMatcher,
 				result.FilesScanned++
 				mu.Unlock()
 
-				fileInfo, err := s.ScanFile(path)
+				fileInfo, err := run.scanFile(s, path)
 				if err != nil {
 					mu.Lock()
 					result.Errors.Add(path, err)
@@ -318,8 +352,8 @@ func (s *Scanner) scanConcurrent(rootPath string, ignoreMatcher *IgnoreMatcher,
 					continue
 				}
 
-				// Only include source files
-				if fileInfo.Language != "unknown" {
+				// Only include source files (not unknown language or binary)
+				if fileInfo.Language != "unknown" && !fileInfo.Binary {
 					mu.Lock()
 					result.Files = append(result.Files, fileInfo)
 					mu.Unlock()
@@ -398,6 +432,12 @@ func (s *Scanner) scanConcurrent(rootPath string, ignoreMatcher *IgnoreMatcher,
 
 // ScanFile scans a single file
 func (s *Scanner) ScanFile(filePath string) (*FileInfo, error) {
+	return s.inspectFile(filePath, false)
+}
+
+// inspectFile scans a single file. Unless strictRead is set, a file whose
+// content cannot be read is reported without content flags.
+func (s *Scanner) inspectFile(filePath string, strictRead bool) (*FileInfo, error) {
 	info, err := os.Stat(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to stat file: %w", err)
@@ -410,11 +450,19 @@ func (s *Scanner) ScanFile(filePath string) (*FileInfo, error) {
 		ModTime:  info.ModTime(),
 	}
 
-	// Check if file has LinkedDoc (only for source files)
+	// Sniff content and check for LinkedDoc (only for source files)
 	if fileInfo.Language != "unknown" {
 		content, err := os.ReadFile(filePath)
+		if err != nil && strictRead {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
 		if err == nil {
-			linkedDoc, _ := s.parser.ExtractLinkedDoc(string(content))
+			if IsBinaryContent(content) {
+				fileInfo.Binary = true
+				return fileInfo, nil
+			}
+			fileInfo.Generated = IsGeneratedContent(content)
+			linkedDoc, _ := s.parser.ExtractLinkedDocFor(string(content), DetectLanguageKey(filePath))
 			fileInfo.HasLinkedDoc = linkedDoc != ""
 		}
 	}
@@ -422,6 +470,93 @@ func (s *Scanner) ScanFile(filePath string) (*FileInfo, error) {
 	return fileInfo, nil
 }
 
+// ScanPaths scans only the given files, applying the same ignore rules and
+// limits as Scan. Files that no longer exist are skipped.
+func (s *Scanner) ScanPaths(rootPath string, paths []string, opts ScanOptions) (*ScanResult, error) {
+	startTime := time.Now()
+
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	ignoreMatcher := s.buildIgnoreMatcher(absPath, opts)
+
+	run, err := newScanRun(absPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{
+		Files:  make([]*FileInfo, 0, len(paths)),
+		Errors: NewErrorCollector(),
+	}
+
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(absPath, path)
+		}
+
+		relPath, err := filepath.Rel(absPath, path)
+		if err != nil || strings.HasPrefix(relPath, "..") || ignoredPath(ignoreMatcher, relPath) {
+			continue
+		}
+
+		info, err := os.Lstat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if !opts.FollowSymlinks && info.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+
+		result.FilesScanned++
+		fileInfo, err := run.scanFile(s, path)
+		if err != nil {
+			result.Errors.Add(path, err)
+			result.FilesFailed++
+			if opts.StrictMode {
+				err = fmt.Errorf("strict mode: %s: %w", path, err)
+				_ = run.finish(err)
+				return nil, err
+			}
+			continue
+		}
+
+		if opts.MaxFileSize > 0 && fileInfo.Size > opts.MaxFileSize {
+			continue
+		}
+		if fileInfo.Language != "unknown" && !fileInfo.Binary {
+			result.Files = append(result.Files, fileInfo)
+		}
+	}
+
+	if err := run.finish(nil); err != nil {
+		return nil, err
+	}
+	result.Resumed = run.resumed
+
+	result.Files, result.Duplicates = MergeDuplicates(result.Files)
+	for _, file := range result.Files {
+		result.TotalBytes += file.Size
+	}
+
+	result.Duration = time.Since(startTime)
+	result.TotalFiles = len(result.Files)
+	return result, nil
+}
+
+// ignoredPath reports whether a path or any of its directories is ignored,
+// matching what a directory walk would skip
+func ignoredPath(matcher *IgnoreMatcher, relPath string) bool {
+	for p := relPath; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		if matcher.ShouldIgnore(p) {
+			return true
+		}
+	}
+	return false
+}
+
 // buildIgnoreMatcher builds the ignore matcher from options
 func (s *Scanner) buildIgnoreMatcher(rootPath string, opts ScanOptions) *IgnoreMatcher {
 	var patterns []string
@@ -0,0 +1,203 @@
+/*
+# Module: pkg/schedule/digest.go
+Report diffing and delivery.
+
+Compares a scheduled run's report with the previous one and keeps only what
+got worse: new dependency cycles, new dead code, coverage drops and new rule
+violations. The delta is posted as JSON to webhooks and rendered as a
+Markdown digest.
+
+## Linked Modules
+- [schedule](./schedule.go) - Scheduled analysis runs
+
+## Tags
+schedule, diff, digest, notifications
+
+## Exports
+Delta, CoverageDrop, Diff, FormatMarkdown, Deliver
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#digest.go> a code:Module ;
+    code:name "pkg/schedule/digest.go" ;
+    code:description "Report diffing and delivery" ;
+    code:language "go" ;
+    code:layer "schedule" ;
+    code:linksTo <./schedule.go> ;
+    code:exports <#Delta>, <#CoverageDrop>, <#Diff>, <#FormatMarkdown>, <#Deliver> ;
+    code:tags "schedule", "diff", "digest", "notifications" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// webhookTimeout bounds webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// Delta is what got worse between two reports
+type Delta struct {
+	Date          string         `json:"date"`
+	PreviousDate  string         `json:"previous_date"`
+	NewCycles     [][]string     `json:"new_cycles"`
+	NewDeadCode   []string       `json:"new_dead_code"`
+	CoverageDrops []CoverageDrop `json:"coverage_drops"`
+	NewViolations []Violation    `json:"new_violations"`
+}
+
+// CoverageDrop is a coverage metric that went down
+type CoverageDrop struct {
+	Metric string  `json:"metric"` // docs or usage
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+}
+
+// IsEmpty returns true if the delta has nothing to report
+func (d *Delta) IsEmpty() bool {
+	return len(d.NewCycles) == 0 && len(d.NewDeadCode) == 0 &&
+		len(d.CoverageDrops) == 0 && len(d.NewViolations) == 0
+}
+
+// Diff returns what got worse from previous to current. Only analyses both
+// reports ran are compared, and coverage drops of at most threshold
+// percentage points are ignored.
+func Diff(previous, current *Report, threshold float64) *Delta {
+	delta := &Delta{
+		Date:          current.Date,
+		PreviousDate:  previous.Date,
+		NewCycles:     [][]string{},
+		NewDeadCode:   []string{},
+		CoverageDrops: []CoverageDrop{},
+		NewViolations: []Violation{},
+	}
+	both := func(name string) bool { return previous.Ran(name) && current.Ran(name) }
+
+	if both("cycles") {
+		known := make(map[string]bool, len(previous.Cycles))
+		for _, cycle := range previous.Cycles {
+			known[cycleKey(cycle)] = true
+		}
+		for _, cycle := range current.Cycles {
+			if !known[cycleKey(cycle)] {
+				delta.NewCycles = append(delta.NewCycles, cycle)
+			}
+		}
+	}
+
+	if both("deadcode") {
+		for _, p := range current.DeadCode {
+			if !slices.Contains(previous.DeadCode, p) {
+				delta.NewDeadCode = append(delta.NewDeadCode, p)
+			}
+		}
+	}
+
+	if both("coverage") {
+		metrics := make([]string, 0, len(current.Coverage))
+		for metric := range current.Coverage {
+			metrics = append(metrics, metric)
+		}
+		sort.Strings(metrics)
+		for _, metric := range metrics {
+			before, ok := previous.Coverage[metric]
+			after := current.Coverage[metric]
+			if ok && before-after > threshold {
+				delta.CoverageDrops = append(delta.CoverageDrops, CoverageDrop{Metric: metric, Before: before, After: after})
+			}
+		}
+	}
+
+	if both("rules") {
+		known := make(map[string]bool, len(previous.Violations))
+		for _, v := range previous.Violations {
+			known[v.key()] = true
+		}
+		for _, v := range current.Violations {
+			if !known[v.key()] {
+				delta.NewViolations = append(delta.NewViolations, v)
+			}
+		}
+	}
+
+	return delta
+}
+
+// FormatMarkdown renders a delta as a Markdown digest
+func FormatMarkdown(delta *Delta) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# GraphFS nightly digest (%s)\n\n", delta.Date)
+	fmt.Fprintf(&b, "Changes since %s.\n", delta.PreviousDate)
+	if delta.IsEmpty() {
+		b.WriteString("\nNothing got worse.\n")
+		return b.String()
+	}
+
+	if len(delta.NewCycles) > 0 {
+		fmt.Fprintf(&b, "\n## New dependency cycles (%d)\n\n", len(delta.NewCycles))
+		for _, cycle := range delta.NewCycles {
+			fmt.Fprintf(&b, "- `%s` → `%s`\n", strings.Join(cycle, "` → `"), cycle[0])
+		}
+	}
+	if len(delta.NewDeadCode) > 0 {
+		fmt.Fprintf(&b, "\n## New dead code (%d)\n\n", len(delta.NewDeadCode))
+		for _, p := range delta.NewDeadCode {
+			fmt.Fprintf(&b, "- `%s`\n", p)
+		}
+	}
+	if len(delta.CoverageDrops) > 0 {
+		b.WriteString("\n## Coverage drops\n\n| Metric | Before | After |\n|--------|--------|-------|\n")
+		for _, drop := range delta.CoverageDrops {
+			fmt.Fprintf(&b, "| %s | %.1f%% | %.1f%% |\n", drop.Metric, drop.Before, drop.After)
+		}
+	}
+	if len(delta.NewViolations) > 0 {
+		fmt.Fprintf(&b, "\n## New rule violations (%d)\n\n", len(delta.NewViolations))
+		for _, v := range delta.NewViolations {
+			location := ""
+			if v.Module != "" {
+				location = fmt.Sprintf(" `%s`", v.Module)
+			}
+			fmt.Fprintf(&b, "- **%s** (%s)%s: %s\n", v.RuleID, v.Severity, location, v.Message)
+		}
+	}
+	return b.String()
+}
+
+// Deliver posts a delta as JSON to each webhook, returning the first error
+// after trying them all
+func Deliver(delta *Delta, webhooks []string) error {
+	data, err := json.Marshal(delta)
+	if err != nil {
+		return fmt.Errorf("failed to encode delta: %w", err)
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	var firstErr error
+	for _, url := range webhooks {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+		if err != nil {
+			err = fmt.Errorf("failed to post digest to %s: %w", url, err)
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("webhook %s returned %s", url, resp.Status)
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,335 @@
+/*
+# Module: pkg/schedule/schedule.go
+Scheduled analysis runs.
+
+Runs a configured set of analyses (dependency cycles, dead code, coverage
+and rule violations) for nightly use from cron or CI, and stores one report
+per day under .graphfs/schedule/ so the next run can compare against it.
+
+## Linked Modules
+- [digest](./digest.go) - Report diffing and delivery
+- [../analysis](../analysis/graph_algorithms.go) - Graph analyses
+- [../rules](../rules/engine.go) - Rules engine
+- [../dashboard](../dashboard/dashboard.go) - Documentation coverage
+- [../watch](../watch/subscriptions.go) - Violation keys
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+schedule, nightly, analysis, reports
+
+## Exports
+ConfigFile, ReportsDir, Config, LoadConfig, Analyses, Options, Report, Violation, Run, Store
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#schedule.go> a code:Module ;
+    code:name "pkg/schedule/schedule.go" ;
+    code:description "Scheduled analysis runs" ;
+    code:language "go" ;
+    code:layer "schedule" ;
+    code:linksTo <./digest.go>, <../analysis/graph_algorithms.go>, <../rules/engine.go>, <../graph/graph.go>,
+                 <../dashboard/dashboard.go>, <../watch/subscriptions.go> ;
+    code:exports <#ConfigFile>, <#ReportsDir>, <#Config>, <#LoadConfig>, <#Analyses>, <#Options>,
+                 <#Report>, <#Violation>, <#Run>, <#Store> ;
+    code:tags "schedule", "nightly", "analysis", "reports" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/dashboard"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/rules"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/watch"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the schedule configuration inside the .graphfs directory
+const ConfigFile = "schedule.yaml"
+
+// ReportsDir is the directory of stored reports inside the .graphfs
+// directory
+const ReportsDir = "schedule"
+
+// dateLayout names stored reports, one per day
+const dateLayout = "2006-01-02"
+
+// Analyses are the analyses a scheduled run can include
+var Analyses = []string{"cycles", "deadcode", "coverage", "rules"}
+
+// Config is the on-disk format of schedule.yaml
+type Config struct {
+	Analyses  []string `yaml:"analyses"`  // Analyses to run (default: all)
+	Webhooks  []string `yaml:"webhooks"`  // URLs to POST deltas to
+	Digest    string   `yaml:"digest"`    // Markdown digest file, relative to the root
+	Rules     string   `yaml:"rules"`     // Rules file (default: .graphfs-rules.yml or built-in rules)
+	Keep      int      `yaml:"keep"`      // Stored reports to keep (default: 30)
+	Threshold float64  `yaml:"threshold"` // Coverage drop in percentage points to report (default: any)
+}
+
+// LoadConfig loads schedule.yaml from a .graphfs directory. A missing file
+// yields the default configuration.
+func LoadConfig(graphfsDir string) (*Config, error) {
+	config := &Config{}
+
+	data, err := os.ReadFile(filepath.Join(graphfsDir, ConfigFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", ConfigFile, err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ConfigFile, err)
+		}
+	}
+
+	if len(config.Analyses) == 0 {
+		config.Analyses = Analyses
+	}
+	for _, name := range config.Analyses {
+		if !slices.Contains(Analyses, name) {
+			return nil, fmt.Errorf("%s: unknown analysis %q (must be %s)", ConfigFile, name, strings.Join(Analyses, ", "))
+		}
+	}
+	if config.Keep <= 0 {
+		config.Keep = 30
+	}
+	return config, nil
+}
+
+// Options configures a scheduled run
+type Options struct {
+	Analyses     []string            // Analyses to run (default: all)
+	Files        []*scanner.FileInfo // Scanned files for documentation coverage (optional)
+	Rules        []*rules.Rule       // Rules to validate (built-in rules if empty)
+	Preprocessor *query.Preprocessor // Shared prefixes and macros for rules (optional)
+	Now          time.Time           // Run time (default: now)
+}
+
+// Report is the stored result of a scheduled run
+type Report struct {
+	Date        string             `json:"date"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Analyses    []string           `json:"analyses"`
+	Cycles      [][]string         `json:"cycles,omitempty"`     // Each starting at its smallest path
+	DeadCode    []string           `json:"dead_code,omitempty"`  // Unreferenced module paths
+	Coverage    map[string]float64 `json:"coverage,omitempty"`   // Percentages: docs, usage
+	Violations  []Violation        `json:"violations,omitempty"` // Rule violations
+}
+
+// Violation is a rule violation in a report
+type Violation struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Module   string `json:"module,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Ran reports whether the report includes an analysis
+func (r *Report) Ran(name string) bool {
+	return slices.Contains(r.Analyses, name)
+}
+
+// Run runs the analyses on a graph and returns the report
+func Run(g *graph.Graph, opts Options) (*Report, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if len(opts.Analyses) == 0 {
+		opts.Analyses = Analyses
+	}
+
+	report := &Report{
+		Date:        opts.Now.Format(dateLayout),
+		GeneratedAt: opts.Now,
+		Analyses:    opts.Analyses,
+	}
+
+	if report.Ran("cycles") {
+		for _, cycle := range analysis.CyclicDependencies(g) {
+			report.Cycles = append(report.Cycles, normalizeCycle(cycle))
+		}
+		sort.Slice(report.Cycles, func(i, j int) bool {
+			return cycleKey(report.Cycles[i]) < cycleKey(report.Cycles[j])
+		})
+	}
+
+	if report.Ran("deadcode") {
+		dead, err := analysis.DetectDeadCode(g, analysis.DeadCodeOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("dead code analysis failed: %w", err)
+		}
+		for _, module := range dead.UnreferencedModules {
+			report.DeadCode = append(report.DeadCode, module.Module.Path)
+		}
+		sort.Strings(report.DeadCode)
+	}
+
+	if report.Ran("coverage") {
+		report.Coverage = map[string]float64{
+			"usage": analysis.AnalyzeCoverage(g).CoveragePercent,
+		}
+		if docs := dashboard.FileCoverage(opts.Files); docs.SourceFiles > 0 {
+			report.Coverage["docs"] = docs.CoveragePercent
+		}
+	}
+
+	if report.Ran("rules") {
+		ruleList := opts.Rules
+		if len(ruleList) == 0 {
+			ruleList = rules.GetBuiltInRules()
+		}
+		engine := rules.NewEngine(g)
+		engine.SetPreprocessor(opts.Preprocessor)
+		result, err := engine.Validate(ruleList)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate rules: %w", err)
+		}
+		violations := result.Violations
+		sort.Slice(violations, func(i, j int) bool {
+			return watch.ViolationKey(violations[i]) < watch.ViolationKey(violations[j])
+		})
+		for _, v := range violations {
+			report.Violations = append(report.Violations, toViolation(v))
+		}
+	}
+
+	return report, nil
+}
+
+// normalizeCycle rotates a cycle to start at its smallest path, so the same
+// cycle compares equal between runs
+func normalizeCycle(cycle []string) []string {
+	if len(cycle) == 0 {
+		return cycle
+	}
+	start := 0
+	for i, p := range cycle {
+		if p < cycle[start] {
+			start = i
+		}
+	}
+	return append(append([]string{}, cycle[start:]...), cycle[:start]...)
+}
+
+// cycleKey identifies a normalized cycle
+func cycleKey(cycle []string) string {
+	return strings.Join(cycle, "\x00")
+}
+
+// toViolation converts a rule violation for a report
+func toViolation(v rules.Violation) Violation {
+	violation := Violation{Module: v.FilePath, Message: v.Message}
+	if v.Module != nil {
+		violation.Module = v.Module.Path
+	}
+	if v.Rule != nil {
+		violation.RuleID = v.Rule.ID
+		violation.Severity = string(v.Rule.Severity)
+	}
+	return violation
+}
+
+// key identifies a stored violation the same way watch.ViolationKey
+// identifies a rule violation
+func (v Violation) key() string {
+	return watch.ViolationKey(rules.Violation{
+		Rule:     &rules.Rule{ID: v.RuleID},
+		FilePath: v.Module,
+		Message:  v.Message,
+	})
+}
+
+// Store keeps one report per day in a directory
+type Store struct {
+	Dir  string // Directory of <date>.json reports
+	Keep int    // Reports to keep; older ones are removed on Save (0 = all)
+}
+
+// Save stores a report, replacing an earlier report of the same day
+func (s *Store) Save(report *Report) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", s.Dir, err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, report.Date+".json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if s.Keep <= 0 {
+		return nil
+	}
+	dates, err := s.dates()
+	if err != nil {
+		return err
+	}
+	for len(dates) > s.Keep {
+		if err := os.Remove(filepath.Join(s.Dir, dates[0]+".json")); err != nil {
+			return fmt.Errorf("failed to remove old report: %w", err)
+		}
+		dates = dates[1:]
+	}
+	return nil
+}
+
+// Previous returns the latest report from before date, or nil if there is
+// none, so a run repeated on the same day still compares against the night
+// before
+func (s *Store) Previous(date string) (*Report, error) {
+	dates, err := s.dates()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(dates) - 1; i >= 0; i-- {
+		if dates[i] >= date {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.Dir, dates[i]+".json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report: %w", err)
+		}
+		report := &Report{}
+		if err := json.Unmarshal(data, report); err != nil {
+			return nil, fmt.Errorf("failed to parse report %s: %w", dates[i], err)
+		}
+		return report, nil
+	}
+	return nil, nil
+}
+
+// dates returns the dates of the stored reports, oldest first
+func (s *Store) dates() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.Dir, err)
+	}
+	var dates []string
+	for _, entry := range entries {
+		date, ok := strings.CutSuffix(entry.Name(), ".json")
+		if _, err := time.Parse(dateLayout, date); ok && err == nil && !entry.IsDir() {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
@@ -0,0 +1,184 @@
+package schedule
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func buildGraph(deps map[string][]string) *graph.Graph {
+	g := graph.NewGraph("/project", store.NewTripleStore())
+	for path := range deps {
+		module := graph.NewModule(path, "<#"+path+">")
+		module.Dependencies = deps[path]
+		g.AddModule(module)
+	}
+	for path, targets := range deps {
+		for _, target := range targets {
+			dependent := g.Modules[target]
+			dependent.Dependents = append(dependent.Dependents, "<#"+path+">")
+		}
+	}
+	return g
+}
+
+func TestRun(t *testing.T) {
+	g := buildGraph(map[string][]string{
+		"main.go": {"b.go"},
+		"b.go":    {"c.go"},
+		"c.go":    {"b.go"},
+		"old.go":  nil,
+	})
+	files := []*scanner.FileInfo{{Path: "a.go", HasLinkedDoc: true}, {Path: "b.go"}, {Path: "gen.go", Generated: true}}
+	now := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+
+	report, err := Run(g, Options{Files: files, Now: now})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Date != "2026-03-02" || !reflect.DeepEqual(report.Analyses, Analyses) {
+		t.Errorf("Date = %q, Analyses = %v", report.Date, report.Analyses)
+	}
+	if want := [][]string{{"b.go", "c.go"}}; !reflect.DeepEqual(report.Cycles, want) {
+		t.Errorf("Cycles = %v, want %v", report.Cycles, want)
+	}
+	if !slices.Contains(report.DeadCode, "old.go") {
+		t.Errorf("Expected old.go in dead code, got %v", report.DeadCode)
+	}
+	if report.Coverage["docs"] != 50 {
+		t.Errorf("Docs coverage = %v, want 50", report.Coverage["docs"])
+	}
+
+	report, err = Run(g, Options{Analyses: []string{"cycles"}, Now: now})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.DeadCode != nil || report.Coverage != nil || report.Violations != nil {
+		t.Errorf("Expected only cycles, got %+v", report)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	previous := &Report{
+		Date:       "2026-03-01",
+		Analyses:   []string{"cycles", "deadcode", "coverage", "rules"},
+		Cycles:     [][]string{{"a.go", "b.go"}},
+		DeadCode:   []string{"old.go"},
+		Coverage:   map[string]float64{"docs": 80, "usage": 90},
+		Violations: []Violation{{RuleID: "r1", Module: "a.go", Message: "bad"}},
+	}
+	current := &Report{
+		Date:     "2026-03-02",
+		Analyses: []string{"cycles", "deadcode", "coverage", "rules"},
+		Cycles:   [][]string{{"a.go", "b.go"}, {"c.go", "d.go"}},
+		DeadCode: []string{"old.go", "unused.go"},
+		Coverage: map[string]float64{"docs": 70, "usage": 89.5},
+		Violations: []Violation{
+			{RuleID: "r1", Module: "a.go", Message: "bad"},
+			{RuleID: "r2", Module: "c.go", Message: "worse"},
+		},
+	}
+
+	delta := Diff(previous, current, 1)
+	if want := [][]string{{"c.go", "d.go"}}; !reflect.DeepEqual(delta.NewCycles, want) {
+		t.Errorf("NewCycles = %v, want %v", delta.NewCycles, want)
+	}
+	if want := []string{"unused.go"}; !reflect.DeepEqual(delta.NewDeadCode, want) {
+		t.Errorf("NewDeadCode = %v, want %v", delta.NewDeadCode, want)
+	}
+	// The usage drop is within the threshold
+	if want := []CoverageDrop{{Metric: "docs", Before: 80, After: 70}}; !reflect.DeepEqual(delta.CoverageDrops, want) {
+		t.Errorf("CoverageDrops = %v, want %v", delta.CoverageDrops, want)
+	}
+	if len(delta.NewViolations) != 1 || delta.NewViolations[0].RuleID != "r2" {
+		t.Errorf("NewViolations = %v", delta.NewViolations)
+	}
+
+	digest := FormatMarkdown(delta)
+	for _, want := range []string{"Changes since 2026-03-01", "`c.go` → `d.go` → `c.go`", "`unused.go`", "| docs | 80.0% | 70.0% |", "**r2**"} {
+		if !strings.Contains(digest, want) {
+			t.Errorf("Digest missing %q:\n%s", want, digest)
+		}
+	}
+
+	// Analyses the previous run skipped are not compared
+	previous.Analyses = []string{"cycles"}
+	if delta := Diff(previous, current, 1); len(delta.NewDeadCode) != 0 || len(delta.NewViolations) != 0 || len(delta.NewCycles) != 1 {
+		t.Errorf("Expected only cycles to be compared, got %+v", delta)
+	}
+	if delta := Diff(current, current, 0); !delta.IsEmpty() {
+		t.Errorf("Expected an empty delta, got %+v", delta)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := &Store{Dir: filepath.Join(t.TempDir(), "schedule"), Keep: 2}
+
+	if previous, err := s.Previous("2026-03-01"); err != nil || previous != nil {
+		t.Fatalf("Previous() on an empty store = %v, %v", previous, err)
+	}
+	for _, date := range []string{"2026-03-01", "2026-03-02", "2026-03-03"} {
+		if err := s.Save(&Report{Date: date, Analyses: []string{"cycles"}}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "2026-03-01.json")); !os.IsNotExist(err) {
+		t.Error("Expected the oldest report to be removed")
+	}
+
+	// A second run on the same day still compares against the night before
+	previous, err := s.Previous("2026-03-03")
+	if err != nil || previous == nil || previous.Date != "2026-03-02" {
+		t.Errorf("Previous() = %+v, %v; want the 2026-03-02 report", previous, err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	config, err := LoadConfig(dir)
+	if err != nil || !reflect.DeepEqual(config.Analyses, Analyses) || config.Keep != 30 {
+		t.Fatalf("LoadConfig() without a file = %+v, %v", config, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte("analyses: [cycles, coverage]\nwebhooks: [http://example.com/hook]\nthreshold: 0.5\n"), 0644)
+	config, err = LoadConfig(dir)
+	if err != nil || len(config.Analyses) != 2 || len(config.Webhooks) != 1 || config.Threshold != 0.5 {
+		t.Errorf("LoadConfig() = %+v, %v", config, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte("analyses: [lint]\n"), 0644)
+	if _, err := LoadConfig(dir); err == nil {
+		t.Error("Expected error for an unknown analysis")
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	var received Delta
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	delta := &Delta{Date: "2026-03-02", NewDeadCode: []string{"old.go"}}
+	if err := Deliver(delta, []string{failing.URL, server.URL}); err == nil {
+		t.Error("Expected the failing webhook to be reported")
+	}
+	if received.Date != "2026-03-02" || len(received.NewDeadCode) != 1 {
+		t.Errorf("Webhook received %+v", received)
+	}
+}
@@ -0,0 +1,291 @@
+/*
+# Module: pkg/semantic/provider.go
+Embedding providers for semantic search.
+
+A provider turns texts into vectors. The local provider hashes word stems
+and character trigrams into a fixed-size vector: it needs no model or
+network and finds modules sharing vocabulary ("hash passwords" finds
+"password hashing"). The openai provider calls an OpenAI-compatible
+/embeddings endpoint, which also serves local models through Ollama,
+LM Studio or llama.cpp.
+
+## Linked Modules
+- [store](./store.go) - Embedding store and search
+
+## Tags
+semantic, embeddings, search, integration
+
+## Exports
+Config, Provider, NewProvider, LocalProvider, NewLocalProvider, OpenAIProvider, DefaultOpenAIURL, DefaultOpenAIModel
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#provider.go> a code:Module ;
+    code:name "pkg/semantic/provider.go" ;
+    code:description "Embedding providers for semantic search" ;
+    code:language "go" ;
+    code:layer "semantic" ;
+    code:linksTo <./store.go> ;
+    code:exports <#Config>, <#Provider>, <#NewProvider>, <#LocalProvider>, <#NewLocalProvider>, <#OpenAIProvider>, <#DefaultOpenAIURL>, <#DefaultOpenAIModel> ;
+    code:tags "semantic", "embeddings", "search", "integration" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package semantic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// OpenAI-compatible API defaults
+const (
+	DefaultOpenAIURL   = "https://api.openai.com/v1"
+	DefaultOpenAIModel = "text-embedding-3-small"
+	defaultAPIKeyEnv   = "OPENAI_API_KEY"
+	requestTimeout     = 60 * time.Second
+)
+
+// localDimensions is the vector size of the local provider
+const localDimensions = 512
+
+// Config selects and configures an embedding provider
+type Config struct {
+	Provider  string `yaml:"provider,omitempty"`    // local (default) or openai
+	Model     string `yaml:"model,omitempty"`       // Embedding model of the openai provider
+	BaseURL   string `yaml:"base_url,omitempty"`    // API root of the openai provider
+	APIKeyEnv string `yaml:"api_key_env,omitempty"` // Variable holding the API key (default: OPENAI_API_KEY)
+	BatchSize int    `yaml:"batch_size,omitempty"`  // Texts per request (default: 64)
+}
+
+// Provider computes embeddings
+type Provider interface {
+	// ModelID identifies the vector space; vectors of different models are
+	// not comparable
+	ModelID() string
+
+	// Embed returns one vector per text
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewProvider creates the provider selected by a configuration
+func NewProvider(config Config) (Provider, error) {
+	switch config.Provider {
+	case "", "local":
+		return NewLocalProvider(), nil
+	case "openai":
+		provider := &OpenAIProvider{
+			BaseURL: config.BaseURL,
+			Model:   config.Model,
+			HTTP:    &http.Client{Timeout: requestTimeout},
+		}
+		if provider.BaseURL == "" {
+			provider.BaseURL = DefaultOpenAIURL
+		}
+		if provider.Model == "" {
+			provider.Model = DefaultOpenAIModel
+		}
+		keyEnv := config.APIKeyEnv
+		if keyEnv == "" {
+			keyEnv = defaultAPIKeyEnv
+		}
+		provider.APIKey = os.Getenv(keyEnv)
+		if provider.APIKey == "" && provider.BaseURL == DefaultOpenAIURL {
+			return nil, fmt.Errorf("no API key; set %s", keyEnv)
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q (use local or openai)", config.Provider)
+	}
+}
+
+// LocalProvider embeds texts by feature hashing, without a model
+type LocalProvider struct {
+	dimensions int
+}
+
+// NewLocalProvider creates a local provider
+func NewLocalProvider() *LocalProvider {
+	return &LocalProvider{dimensions: localDimensions}
+}
+
+// ModelID implements Provider
+func (p *LocalProvider) ModelID() string {
+	return fmt.Sprintf("local-hash-%d", p.dimensions)
+}
+
+// Embed implements Provider. Word stems count fully and their character
+// trigrams a little, so related word forms land close together.
+func (p *LocalProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vector := make([]float32, p.dimensions)
+		for _, word := range words(text) {
+			p.add(vector, "w:"+word, 1)
+			padded := "^" + word + "$"
+			for j := 0; j+3 <= len(padded); j++ {
+				p.add(vector, "t:"+padded[j:j+3], 0.25)
+			}
+		}
+		normalize(vector)
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// add hashes a feature into the vector, with a hashed sign to spread
+// collisions
+func (p *LocalProvider) add(vector []float32, feature string, weight float32) {
+	h := fnv.New32a()
+	h.Write([]byte(feature))
+	sum := h.Sum32()
+	if sum&(1<<31) != 0 {
+		weight = -weight
+	}
+	vector[int(sum%uint32(p.dimensions))] += weight
+}
+
+// stopWords are words too common to tell modules apart
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "by": true,
+	"do": true, "does": true, "for": true, "from": true, "how": true,
+	"in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"the": true, "this": true, "to": true, "we": true, "what": true,
+	"where": true, "which": true, "with": true,
+}
+
+// words returns the lowercased stems of the words of text, split at
+// camelCase boundaries, without stop words
+func words(text string) []string {
+	var result []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(field)
+		start := 0
+		for i := 1; i <= len(runes); i++ {
+			if i < len(runes) && !(unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])) {
+				continue
+			}
+			word := strings.ToLower(string(runes[start:i]))
+			start = i
+			if len(word) > 1 && !stopWords[word] {
+				result = append(result, stem(word))
+			}
+		}
+	}
+	return result
+}
+
+// stem strips common English suffixes ("hashing", "hashes" -> "hash")
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// normalize scales a vector to unit length
+func normalize(vector []float32) {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vector {
+		vector[i] /= norm
+	}
+}
+
+// OpenAIProvider calls an OpenAI-compatible embeddings API
+type OpenAIProvider struct {
+	BaseURL string       // API root, e.g. DefaultOpenAIURL or http://localhost:11434/v1
+	Model   string       // Embedding model
+	APIKey  string       // Bearer token; may be empty for local servers
+	HTTP    *http.Client // HTTP client (default: 60s timeout)
+}
+
+// ModelID implements Provider
+func (p *OpenAIProvider) ModelID() string {
+	return "openai:" + p.Model
+}
+
+// Embed implements Provider
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	data, err := json.Marshal(map[string]interface{}{"model": p.Model, "input": texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	url := strings.TrimSuffix(p.BaseURL, "/") + "/embeddings"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	httpClient := p.HTTP
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiError struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		message := strings.TrimSpace(string(body))
+		if json.Unmarshal(body, &apiError) == nil && apiError.Error.Message != "" {
+			message = apiError.Error.Message
+		}
+		return nil, fmt.Errorf("embeddings request to %s returned %s: %s", url, resp.Status, message)
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings response has %d vectors for %d texts", len(result.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings response has invalid index %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
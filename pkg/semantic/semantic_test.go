@@ -0,0 +1,161 @@
+package semantic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+)
+
+const authSource = `/*
+# Module: auth/passwords.go
+Password storage.
+
+Hashes user passwords with bcrypt before they are stored, and checks
+login attempts against the stored hash.
+
+<!-- LinkedDoc RDF -->
+<#passwords.go> a code:Module .
+<!-- End LinkedDoc RDF -->
+*/
+package auth
+`
+
+func TestHeaderProse(t *testing.T) {
+	got := HeaderProse(authSource, parser.CommentStyleFor("go"))
+	want := "Password storage.\nHashes user passwords with bcrypt before they are stored, and checks\nlogin attempts against the stored hash."
+	if got != want {
+		t.Errorf("HeaderProse() = %q, want %q", got, want)
+	}
+	if got := HeaderProse("package main\n", parser.CommentStyleFor("go")); got != "" {
+		t.Errorf("HeaderProse() without a header = %q", got)
+	}
+}
+
+// countingProvider counts the texts it embeds
+type countingProvider struct {
+	*LocalProvider
+	embedded int
+}
+
+func (p *countingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	p.embedded += len(texts)
+	return p.LocalProvider.Embed(ctx, texts)
+}
+
+func TestStore_SearchLocal(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "auth"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "auth", "passwords.go"), []byte(authSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := graph.NewGraph(root, store.NewTripleStore())
+	passwords := graph.NewModule("auth/passwords.go", "<#passwords.go>")
+	passwords.Language = "go"
+	g.AddModule(passwords)
+	billing := graph.NewModule("billing/invoice.go", "<#invoice.go>")
+	billing.Description = "Renders monthly invoices as PDF"
+	g.AddModule(billing)
+	logging := graph.NewModule("utils/log.go", "<#log.go>")
+	logging.Description = "Structured request logging"
+	g.AddModule(logging)
+
+	docs := Documents(root, g)
+	if len(docs) != 3 || !strings.Contains(docs[0].Text, "bcrypt") {
+		t.Fatalf("Documents() = %+v", docs)
+	}
+
+	provider := &countingProvider{LocalProvider: NewLocalProvider()}
+	s := &Store{Vectors: make(map[string]Vector)}
+	if n, err := s.Update(context.Background(), provider, docs, 2); err != nil || n != 3 {
+		t.Fatalf("Update() = %d, %v", n, err)
+	}
+
+	results, err := s.Search(context.Background(), provider, "where do we hash passwords", 2)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Path != "auth/passwords.go" {
+		t.Errorf("Search() = %+v, want auth/passwords.go first", results)
+	}
+
+	// Unchanged modules are not embedded again, removed ones are dropped
+	path := filepath.Join(root, ".graphfs", StoreFile)
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	billing.Description = "Renders yearly invoices"
+	provider.embedded = 0
+	if n, err := loaded.Update(context.Background(), provider, Documents(root, g)[:2], 0); err != nil || n != 1 || provider.embedded != 1 {
+		t.Errorf("Update() after change = %d, %v (embedded %d), want 1", n, err, provider.embedded)
+	}
+	if _, ok := loaded.Vectors["utils/log.go"]; ok {
+		t.Error("Expected the vector of a removed module to be dropped")
+	}
+}
+
+func TestOpenAIProvider_Embed(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/v1/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		var request struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model != "nomic-embed-text" {
+			http.Error(w, `{"error":{"message":"bad request"}}`, http.StatusBadRequest)
+			return
+		}
+		// Answer out of order; vectors are placed by index
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("EMBEDDINGS_KEY", "secret")
+	provider, err := NewProvider(Config{Provider: "openai", BaseURL: server.URL + "/v1", Model: "nomic-embed-text", APIKeyEnv: "EMBEDDINGS_KEY"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	vectors, err := provider.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if vectors[0][0] != 1 || vectors[1][1] != 1 || auth != "Bearer secret" {
+		t.Errorf("Embed() = %v with Authorization %q", vectors, auth)
+	}
+	if provider.ModelID() != "openai:nomic-embed-text" {
+		t.Errorf("ModelID() = %q", provider.ModelID())
+	}
+
+	provider.(*OpenAIProvider).Model = "other"
+	if _, err := provider.Embed(context.Background(), []string{"a"}); err == nil || !strings.Contains(err.Error(), "bad request") {
+		t.Errorf("Embed() error = %v, want the API error message", err)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, err := NewProvider(Config{Provider: "openai"}); err == nil {
+		t.Error("Expected an error without an API key for the OpenAI API")
+	}
+	if _, err := NewProvider(Config{Provider: "bogus"}); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
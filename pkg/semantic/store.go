@@ -0,0 +1,265 @@
+/*
+# Module: pkg/semantic/store.go
+Embedding store and semantic search over modules.
+
+Builds one document per module from its name, description and the prose of
+its LinkedDoc header, and keeps their embeddings in .graphfs/embeddings.json.
+Each vector records a hash of its text, so updates only embed modules whose
+text changed. Searching embeds the query and ranks modules by cosine
+similarity.
+
+## Linked Modules
+- [provider](./provider.go) - Embedding providers
+- [../graph](../graph/graph.go) - Knowledge graph
+- [../parser](../parser/comments.go) - LinkedDoc comment styles
+
+## Tags
+semantic, embeddings, search
+
+## Exports
+StoreFile, Document, Documents, HeaderProse, Store, Vector, Result, LoadStore
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#store.go> a code:Module ;
+    code:name "pkg/semantic/store.go" ;
+    code:description "Embedding store and semantic search over modules" ;
+    code:language "go" ;
+    code:layer "semantic" ;
+    code:linksTo <./provider.go>, <../graph/graph.go>, <../parser/comments.go> ;
+    code:exports <#StoreFile>, <#Document>, <#Documents>, <#HeaderProse>, <#Store>, <#Vector>, <#Result>, <#LoadStore> ;
+    code:tags "semantic", "embeddings", "search" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package semantic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/parser"
+)
+
+// StoreFile is the file name of the embedding store in .graphfs/
+const StoreFile = "embeddings.json"
+
+// defaultBatchSize is how many texts are embedded per request
+const defaultBatchSize = 64
+
+// maxTextLength caps document text, in runes, to stay within model limits
+const maxTextLength = 4000
+
+// Document is the text embedded for a module
+type Document struct {
+	Path string
+	Text string
+}
+
+// Documents returns the document of every module of a graph, sorted by
+// path. Header prose is read from the module's file under root; modules
+// whose file can't be read are described by their metadata alone.
+func Documents(root string, g *graph.Graph) []Document {
+	docs := make([]Document, 0, len(g.Modules))
+	for _, module := range g.Modules {
+		parts := []string{module.Name, module.Description}
+		if content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(module.Path))); err == nil {
+			parts = append(parts, HeaderProse(string(content), parser.CommentStyleFor(module.Language)))
+		}
+
+		text := []rune(strings.TrimSpace(strings.Join(parts, "\n")))
+		if len(text) > maxTextLength {
+			text = text[:maxTextLength]
+		}
+		docs = append(docs, Document{Path: module.Path, Text: string(text)})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+	return docs
+}
+
+// HeaderProse returns the prose of a LinkedDoc header: the lines before the
+// RDF block without comment syntax and the "# Module:" line. It returns ""
+// if content has no LinkedDoc block.
+func HeaderProse(content string, style parser.CommentStyle) string {
+	lines := strings.Split(content, "\n")
+	var prose []string
+	for i := style.SkipLines; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.Contains(line, style.StartMarker) {
+			return strings.TrimSpace(strings.Join(prose, "\n"))
+		}
+		if style.LinePrefix != "" {
+			line = strings.TrimSpace(strings.TrimPrefix(line, style.LinePrefix))
+		}
+		if style.BlockStart != "" {
+			line = strings.TrimSpace(strings.TrimPrefix(line, style.BlockStart))
+		}
+		if line == "" || strings.HasPrefix(line, "# Module:") {
+			continue
+		}
+		prose = append(prose, line)
+	}
+	return ""
+}
+
+// Store holds module embeddings of one model
+type Store struct {
+	Model   string            `json:"model"`
+	Vectors map[string]Vector `json:"vectors"` // By module path
+}
+
+// Vector is the embedding of a module's document
+type Vector struct {
+	Hash   string    `json:"hash"` // SHA-256 of the embedded text
+	Values []float32 `json:"values"`
+}
+
+// Result is a module ranked by similarity to a query (-1 to 1)
+type Result struct {
+	Path  string  `json:"path"`
+	Score float64 `json:"score"`
+}
+
+// LoadStore loads an embedding store, returning an empty one if the file
+// doesn't exist
+func LoadStore(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Store{Vectors: make(map[string]Vector)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embeddings: %w", err)
+	}
+
+	var store Store
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse embeddings %s: %w", path, err)
+	}
+	if store.Vectors == nil {
+		store.Vectors = make(map[string]Vector)
+	}
+	return &store, nil
+}
+
+// Save writes the store to a file
+func (s *Store) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to serialize embeddings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write embeddings: %w", err)
+	}
+	return nil
+}
+
+// Update embeds the documents whose text changed since they were stored,
+// drops modules without a document and returns how many were embedded.
+// Switching to another model discards all stored vectors.
+func (s *Store) Update(ctx context.Context, provider Provider, docs []Document, batchSize int) (int, error) {
+	if s.Model != provider.ModelID() {
+		s.Model = provider.ModelID()
+		s.Vectors = make(map[string]Vector)
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	current := make(map[string]bool, len(docs))
+	var pending []Document
+	var hashes []string
+	for _, doc := range docs {
+		current[doc.Path] = true
+		hash := textHash(doc.Text)
+		if s.Vectors[doc.Path].Hash != hash {
+			pending = append(pending, doc)
+			hashes = append(hashes, hash)
+		}
+	}
+	for path := range s.Vectors {
+		if !current[path] {
+			delete(s.Vectors, path)
+		}
+	}
+
+	for start := 0; start < len(pending); start += batchSize {
+		end := min(start+batchSize, len(pending))
+		texts := make([]string, 0, end-start)
+		for _, doc := range pending[start:end] {
+			texts = append(texts, doc.Text)
+		}
+		vectors, err := provider.Embed(ctx, texts)
+		if err != nil {
+			return start, err
+		}
+		for i, doc := range pending[start:end] {
+			s.Vectors[doc.Path] = Vector{Hash: hashes[start+i], Values: vectors[i]}
+		}
+	}
+	return len(pending), nil
+}
+
+// Search embeds a query and returns stored modules by descending
+// similarity, at most limit of them (0 for all)
+func (s *Store) Search(ctx context.Context, provider Provider, query string, limit int) ([]Result, error) {
+	if s.Model != provider.ModelID() {
+		return nil, fmt.Errorf("embeddings were computed with %s, not %s", s.Model, provider.ModelID())
+	}
+	vectors, err := provider.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(s.Vectors))
+	for path, vector := range s.Vectors {
+		results = append(results, Result{Path: path, Score: cosine(vectors[0], vector.Values)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Path < results[j].Path
+	})
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// cosine returns the cosine similarity of two vectors, or 0 if they differ
+// in length or either is zero
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// textHash returns the hex SHA-256 of a text
+func textHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -68,8 +68,9 @@ func (rw *responseWriter) Header() http.Header {
 	return rw.ResponseWriter.Header()
 }
 
-// CacheMiddleware wraps an HTTP handler with caching support
-func CacheMiddleware(next http.Handler, c *cache.Cache) http.Handler {
+// CacheMiddleware wraps an HTTP handler with caching support. Responses are
+// cached under scope, so handlers for different graphs never share entries.
+func CacheMiddleware(next http.Handler, c *cache.Cache, scope string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip caching for non-GET/POST requests
 		if r.Method != http.MethodGet && r.Method != http.MethodPost {
@@ -84,7 +85,7 @@ func CacheMiddleware(next http.Handler, c *cache.Cache) http.Handler {
 		}
 
 		// Generate cache key based on URL and query parameters
-		cacheKey := cache.GenerateKey(r.Method, r.URL.String(), r.URL.Query().Get("query"))
+		cacheKey := cache.GenerateKey(scope, r.Method, r.URL.String(), r.URL.Query().Get("query"))
 
 		// Check cache
 		if cached, found := c.Get(cacheKey); found {
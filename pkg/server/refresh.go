@@ -0,0 +1,247 @@
+/*
+# Module: pkg/server/refresh.go
+Zero-downtime graph refresh for the HTTP server.
+
+The server serves one graph instance at a time. A refresh builds the next
+instance in the background, together with its SPARQL, GraphQL and REST
+handlers, and atomically swaps it in; each request is answered entirely by
+the instance that was current when it arrived, so clients never see a
+partially built graph. /health and /version report which instance is served
+and how fresh it is.
+
+## Linked Modules
+- [server](./server.go) - HTTP server
+- [../graph](../graph/graph.go) - Graph data structure
+- [../query](../query/executor.go) - Query executor
+
+## Tags
+server, refresh, health
+
+## Exports
+RebuildFunc, Server.Graph, Server.Swap, Server.Refresh
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#refresh.go> a code:Module ;
+    code:name "pkg/server/refresh.go" ;
+    code:description "Zero-downtime graph refresh for the HTTP server" ;
+    code:language "go" ;
+    code:layer "server" ;
+    code:linksTo <./server.go>, <../graph/graph.go>, <../query/executor.go> ;
+    code:exports <#RebuildFunc>, <#Server.Graph>, <#Server.Swap>, <#Server.Refresh> ;
+    code:tags "server", "refresh", "health" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+)
+
+// RebuildFunc builds the next graph to serve and its query executor. It
+// must return a new graph rather than modify the one being served.
+type RebuildFunc func() (*graph.Graph, *query.Executor, error)
+
+// servedGraph is one graph instance with the handlers that serve it
+type servedGraph struct {
+	graph    *graph.Graph
+	executor *query.Executor
+	handler  http.Handler
+	version  int       // Increases with every swap, starting at 1
+	loadedAt time.Time // When the instance was swapped in
+}
+
+// refreshState records the outcome of the last refresh
+type refreshState struct {
+	refreshing  bool
+	lastRefresh time.Time
+	lastError   string
+}
+
+// Graph returns the graph currently served
+func (s *Server) Graph() *graph.Graph {
+	if current := s.current.Load(); current != nil {
+		return current.graph
+	}
+	return s.graph
+}
+
+// Swap atomically replaces the served graph and clears the response cache.
+// Requests already in progress finish against the previous graph; responses
+// are cached per graph version, so those requests never fill the cache for
+// the new graph. The graph must not be modified once swapped in.
+func (s *Server) Swap(g *graph.Graph, executor *query.Executor) error {
+	s.swapMu.Lock()
+	defer s.swapMu.Unlock()
+
+	version := 1
+	if previous := s.current.Load(); previous != nil {
+		version = previous.version + 1
+	}
+	next, err := s.newServedGraph(g, executor, version)
+	if err != nil {
+		return err
+	}
+
+	s.current.Store(next)
+	if s.cache != nil {
+		s.cache.Clear()
+	}
+	return nil
+}
+
+// Refresh builds the next graph with rebuild and swaps it in. On failure the
+// current graph keeps being served and the error is reported by /health.
+// Concurrent refreshes run one at a time.
+func (s *Server) Refresh(rebuild RebuildFunc) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	s.setRefreshState(func(state *refreshState) { state.refreshing = true })
+
+	g, executor, err := rebuild()
+	if err == nil {
+		err = s.Swap(g, executor)
+	}
+
+	s.setRefreshState(func(state *refreshState) {
+		state.refreshing = false
+		state.lastRefresh = time.Now()
+		state.lastError = ""
+		if err != nil {
+			state.lastError = err.Error()
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh graph: %w", err)
+	}
+	return nil
+}
+
+// setRefreshState updates the refresh state (thread-safe)
+func (s *Server) setRefreshState(update func(*refreshState)) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	update(&s.refresh)
+}
+
+// newServedGraph creates the SPARQL, GraphQL and REST handlers for a graph
+func (s *Server) newServedGraph(g *graph.Graph, executor *query.Executor, version int) (*servedGraph, error) {
+	mux := http.NewServeMux()
+	if err := s.registerGraphRoutes(mux, g, executor, fmt.Sprintf("v%d", version)); err != nil {
+		return nil, err
+	}
+	return &servedGraph{
+		graph:    g,
+		executor: executor,
+		handler:  mux,
+		version:  version,
+		loadedAt: time.Now(),
+	}, nil
+}
+
+// serveCurrent answers a request with the graph current when it arrived
+func (s *Server) serveCurrent(w http.ResponseWriter, r *http.Request) {
+	current := s.current.Load()
+	if current == nil {
+		http.Error(w, "Graph not loaded", http.StatusServiceUnavailable)
+		return
+	}
+	current.handler.ServeHTTP(w, r)
+}
+
+// freshness describes the served graph for /health and /version
+type freshness struct {
+	Status           string     `json:"status"`
+	GraphVersion     int        `json:"graphVersion"`
+	LoadedAt         time.Time  `json:"loadedAt"`
+	AgeSeconds       int64      `json:"ageSeconds"`
+	Modules          int        `json:"modules"`
+	Triples          int        `json:"triples"`
+	Refreshing       bool       `json:"refreshing"`
+	LastRefresh      *time.Time `json:"lastRefresh,omitempty"`
+	LastRefreshError string     `json:"lastRefreshError,omitempty"`
+}
+
+// currentFreshness reports the served graph and the last refresh
+func (s *Server) currentFreshness() freshness {
+	f := freshness{Status: "ok"}
+
+	if current := s.current.Load(); current != nil {
+		f.GraphVersion = current.version
+		f.LoadedAt = current.loadedAt.UTC().Truncate(time.Second)
+		f.AgeSeconds = int64(time.Since(current.loadedAt).Seconds())
+		if current.graph != nil && current.graph.Store != nil {
+			f.Modules = len(current.graph.Modules)
+			f.Triples = current.graph.Store.Count()
+		}
+	} else {
+		f.Status = "loading"
+	}
+
+	s.stateMu.Lock()
+	f.Refreshing = s.refresh.refreshing
+	if !s.refresh.lastRefresh.IsZero() {
+		lastRefresh := s.refresh.lastRefresh.UTC().Truncate(time.Second)
+		f.LastRefresh = &lastRefresh
+	}
+	f.LastRefreshError = s.refresh.lastError
+	s.stateMu.Unlock()
+
+	// The graph is still served after a failed refresh, but it is stale
+	if f.LastRefreshError != "" {
+		f.Status = "stale"
+	}
+	return f
+}
+
+// handleHealth reports whether a graph is served and how fresh it is
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	f := s.currentFreshness()
+
+	w.Header().Set("Content-Type", "application/json")
+	if f.Status == "loading" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(f)
+}
+
+// versionInfo identifies the API and the served graph
+type versionInfo struct {
+	APIVersion   string     `json:"apiVersion"`
+	GraphVersion int        `json:"graphVersion"`
+	LoadedAt     time.Time  `json:"loadedAt"`
+	BuiltAt      *time.Time `json:"builtAt,omitempty"`
+	ToolVersion  string     `json:"toolVersion,omitempty"`
+	Commit       string     `json:"commit,omitempty"`
+	Uncommitted  bool       `json:"uncommittedChanges,omitempty"`
+}
+
+// handleVersion reports the version of the served graph and what built it
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	f := s.currentFreshness()
+	info := versionInfo{
+		APIVersion:   apiVersion,
+		GraphVersion: f.GraphVersion,
+		LoadedAt:     f.LoadedAt,
+	}
+	if current := s.current.Load(); current != nil && current.graph != nil && current.graph.Provenance != nil {
+		p := current.graph.Provenance
+		info.BuiltAt = &p.BuiltAt
+		info.ToolVersion = p.Version
+		info.Commit = p.Commit
+		info.Uncommitted = p.UncommittedChanges
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
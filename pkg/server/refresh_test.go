@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+)
+
+// testGraph returns a graph with the named modules and its executor
+func testGraph(names ...string) (*graph.Graph, *query.Executor) {
+	g := graph.NewGraph("/test", store.NewTripleStore())
+	for _, name := range names {
+		g.AddModule(graph.NewModule(name, "<#"+name+">"))
+		g.Store.Add("<#"+name+">", "https://schema.codedoc.org/name", name)
+	}
+	return g, query.NewExecutor(g.Store)
+}
+
+func getFreshness(t *testing.T, s *Server) freshness {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var f freshness
+	if err := json.NewDecoder(rec.Body).Decode(&f); err != nil {
+		t.Fatalf("Failed to decode /health: %v", err)
+	}
+	return f
+}
+
+func TestServer_Refresh(t *testing.T) {
+	config := DefaultConfig()
+	config.EnableGraphQL = false
+	s := NewServerWithGraph(config, nil, nil)
+
+	rec := httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before a graph is served, got %d", rec.Code)
+	}
+
+	g, executor := testGraph("a.go")
+	if err := s.Swap(g, executor); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+	if f := getFreshness(t, s); f.Status != "ok" || f.GraphVersion != 1 || f.Modules != 1 {
+		t.Errorf("Unexpected freshness after swap: %+v", f)
+	}
+
+	err := s.Refresh(func() (*graph.Graph, *query.Executor, error) {
+		g, executor := testGraph("a.go", "b.go")
+		return g, executor, nil
+	})
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if f := getFreshness(t, s); f.GraphVersion != 2 || f.Modules != 2 || f.LastRefresh == nil {
+		t.Errorf("Unexpected freshness after refresh: %+v", f)
+	}
+
+	// Queries are answered by the new graph
+	queryStr := "SELECT ?name WHERE { ?s <https://schema.codedoc.org/name> ?name }"
+	rec = httptest.NewRecorder()
+	s.serveCurrent(rec, httptest.NewRequest(http.MethodGet, "/sparql?query="+url.QueryEscape(queryStr), nil))
+	if !strings.Contains(rec.Body.String(), "b.go") {
+		t.Errorf("Expected results from the refreshed graph, got %s", rec.Body.String())
+	}
+
+	// A failed refresh keeps serving the previous graph
+	err = s.Refresh(func() (*graph.Graph, *query.Executor, error) {
+		return nil, nil, errors.New("parse error")
+	})
+	if err == nil {
+		t.Fatal("Expected refresh error")
+	}
+	if f := getFreshness(t, s); f.Status != "stale" || f.GraphVersion != 2 || f.LastRefreshError == "" {
+		t.Errorf("Unexpected freshness after failed refresh: %+v", f)
+	}
+	if s.Graph().Statistics.TotalModules != 2 {
+		t.Errorf("Expected the previous graph to be served, got %d modules", s.Graph().Statistics.TotalModules)
+	}
+}
+
+func TestServer_SwapIgnoresLateResponses(t *testing.T) {
+	config := DefaultConfig()
+	config.EnableGraphQL = false
+	s := NewServerWithGraph(config, nil, nil)
+
+	g, executor := testGraph("a.go")
+	if err := s.Swap(g, executor); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+	previous := s.current.Load()
+
+	g, executor = testGraph("a.go", "b.go")
+	if err := s.Swap(g, executor); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+
+	// A request that started on the previous graph finishes after the swap
+	// and caches its response
+	queryStr := "SELECT ?name WHERE { ?s <https://schema.codedoc.org/name> ?name }"
+	target := "/sparql?query=" + url.QueryEscape(queryStr)
+	previous.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+
+	rec := httptest.NewRecorder()
+	s.serveCurrent(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if !strings.Contains(rec.Body.String(), "b.go") {
+		t.Errorf("Expected results from the new graph, got %s", rec.Body.String())
+	}
+	if rec.Header().Get("X-Cache") == "HIT" {
+		t.Error("Expected the previous graph's response not to be served from the cache")
+	}
+}
+
+func TestServer_Version(t *testing.T) {
+	s := NewServerWithGraph(DefaultConfig(), nil, nil)
+	g, executor := testGraph("a.go")
+	g.SetProvenance(&graph.Provenance{Tool: "graphfs", Version: "1.2.3", Commit: "abc123"})
+	if err := s.Swap(g, executor); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleVersion(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	var info versionInfo
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatalf("Failed to decode /version: %v", err)
+	}
+	if info.APIVersion != apiVersion || info.GraphVersion != 1 || info.ToolVersion != "1.2.3" || info.Commit != "abc123" {
+		t.Errorf("Unexpected version info: %+v", info)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/justin4957/graphfs/pkg/analysis"
 	"github.com/justin4957/graphfs/pkg/graph"
 )
 
@@ -167,13 +168,27 @@ func (h *Handler) handleAnalysisImpact(w http.ResponseWriter, r *http.Request) {
 		impacted[i] = h.toModuleResponse(mod, false)
 	}
 
-	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"module":           h.toModuleResponse(module, false),
 		"depth":            depth,
 		"impactedModules":  impacted,
 		"impactCount":      len(impacted),
 		"directDependents": len(module.Dependents),
-	})
+	}
+
+	// Risk assessment over all transitive dependents, as graphfs impact
+	if impact, err := analysis.NewImpactAnalysis(h.graph).AnalyzeImpact(module.Path); err == nil {
+		response["risk"] = map[string]interface{}{
+			"level":                impact.RiskLevel,
+			"factors":              impact.RiskFactors,
+			"recommendations":      impact.Recommendations,
+			"directCallSites":      impact.DirectCallSites,
+			"totalImpactedModules": impact.TotalImpactedModules,
+			"impactPercentage":     impact.ImpactPercentage,
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
 }
 
 // getImpactedModules gets all modules impacted by changes to the given module
@@ -0,0 +1,168 @@
+/*
+# Module: pkg/server/rest/annotations.go
+Annotation write endpoint for REST API.
+
+Lets external tools such as ticketing systems and review bots attach
+annotations to modules with an authenticated POST. Annotations are stored as
+manual shadow data, exactly like graphfs shadow annotate.
+
+## Linked Modules
+- [./handler](./handler.go) - REST handler
+- [../../shadow](../../shadow/shadow.go) - Shadow file system
+
+## Tags
+rest, api, annotations, shadow
+
+## Exports
+AnnotationRequest, AnnotationResponse
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#rest-annotations.go> a code:Module ;
+    code:name "pkg/server/rest/annotations.go" ;
+    code:description "Annotation write endpoint for REST API" ;
+    code:language "go" ;
+    code:layer "server" ;
+    code:linksTo <./handler.go>, <../../shadow/shadow.go> ;
+    code:exports <#AnnotationRequest>, <#AnnotationResponse> ;
+    code:tags "rest", "api", "annotations", "shadow" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package rest
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/shadow"
+)
+
+// annotationLockTimeout is how long a write waits for the workspace lock
+const annotationLockTimeout = 5 * time.Second
+
+// maxAnnotationBody limits the size of an annotation request body
+const maxAnnotationBody = 1 << 20
+
+// AnnotationRequest is the body of POST /api/v1/modules/:id/annotations
+type AnnotationRequest struct {
+	Key       string      `json:"key"`
+	Value     interface{} `json:"value"`
+	Author    string      `json:"author,omitempty"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"`
+}
+
+// AnnotationResponse reports a module's annotations after a write
+type AnnotationResponse struct {
+	Module      ModuleResponse      `json:"module"`
+	Annotations []shadow.Annotation `json:"annotations"`
+}
+
+// EnableAnnotationWrites accepts annotation writes authenticated with
+// the bearer token and stores them in the shadow file system
+func (h *Handler) EnableAnnotationWrites(shadowFS *shadow.ShadowFS, token string) {
+	h.shadowFS = shadowFS
+	h.annotationToken = token
+}
+
+// handleModuleAnnotations handles POST /api/v1/modules/:id/annotations
+func (h *Handler) handleModuleAnnotations(w http.ResponseWriter, r *http.Request, path string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST method is allowed")
+		return
+	}
+
+	if h.shadowFS == nil || h.annotationToken == "" {
+		h.writeError(w, http.StatusForbidden, "ANNOTATIONS_DISABLED", "Annotation writes are not enabled on this server")
+		return
+	}
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="graphfs"`)
+		h.writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "A valid bearer token is required")
+		return
+	}
+
+	moduleID := strings.TrimSuffix(path, "/annotations")
+
+	// Find module
+	var module *graph.Module
+	for _, mod := range h.graph.Modules {
+		if mod.URI == moduleID || mod.Path == moduleID || mod.Name == moduleID {
+			module = mod
+			break
+		}
+	}
+
+	if module == nil {
+		h.writeError(w, http.StatusNotFound, "MODULE_NOT_FOUND",
+			fmt.Sprintf("Module '%s' not found", moduleID))
+		return
+	}
+
+	var req AnnotationRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnnotationBody)).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "INVALID_BODY", fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if req.Key == "" || req.Value == nil {
+		h.writeError(w, http.StatusBadRequest, "INVALID_BODY", "Fields 'key' and 'value' are required")
+		return
+	}
+
+	// Serialize with CLI commands writing shadow data
+	lock, err := shadow.AcquireLock(h.shadowFS.RootPath(), annotationLockTimeout)
+	if err != nil {
+		if errors.Is(err, shadow.ErrLocked) {
+			w.Header().Set("Retry-After", "5")
+			h.writeError(w, http.StatusServiceUnavailable, "WORKSPACE_LOCKED", err.Error())
+			return
+		}
+		h.writeError(w, http.StatusInternalServerError, "WRITE_FAILED", err.Error())
+		return
+	}
+	defer lock.Release()
+
+	actor := "api"
+	if req.Author != "" {
+		actor += ":" + req.Author
+	}
+	entry, err := h.shadowFS.Annotate(filepath.FromSlash(module.Path), shadow.Annotation{
+		Key:       req.Key,
+		Value:     req.Value,
+		Author:    req.Author,
+		ExpiresAt: req.ExpiresAt,
+	}, actor)
+	var violation shadow.SchemaViolation
+	if errors.As(err, &violation) {
+		h.writeError(w, http.StatusBadRequest, "INVALID_ANNOTATION", violation.Error())
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, "WRITE_FAILED",
+			fmt.Sprintf("Failed to save annotation: %v", err))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, AnnotationResponse{
+		Module:      h.toModuleResponse(module, false),
+		Annotations: entry.Annotations,
+	})
+}
+
+// authorized reports whether a request carries the annotation token
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(h.annotationToken)) == 1
+}
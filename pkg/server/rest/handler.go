@@ -37,12 +37,19 @@ import (
 
 	"github.com/justin4957/graphfs/pkg/cache"
 	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/shadow"
 )
 
 // Handler handles REST API requests
 type Handler struct {
 	graph      *graph.Graph
 	enableCORS bool
+	executor   *query.Executor // Serves /api/v1/query when set
+
+	// Annotation writes (disabled unless both are set)
+	shadowFS        *shadow.ShadowFS
+	annotationToken string
 }
 
 // NewHandler creates a new REST API handler
@@ -70,10 +77,14 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 
 	// Export endpoints
 	mux.HandleFunc("/api/v1/exports", h.handleExports)
+
+	// Query endpoint
+	mux.HandleFunc("/api/v1/query", h.handleQuery)
 }
 
-// RegisterRoutesWithCache registers all REST API routes with caching
-func (h *Handler) RegisterRoutesWithCache(mux *http.ServeMux, c *cache.Cache) {
+// RegisterRoutesWithCache registers all REST API routes with caching.
+// Responses are cached under scope, which identifies the graph served.
+func (h *Handler) RegisterRoutesWithCache(mux *http.ServeMux, c *cache.Cache, scope string) {
 	// Import the server package type
 	cacheMiddleware := func(next http.HandlerFunc) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -84,7 +95,7 @@ func (h *Handler) RegisterRoutesWithCache(mux *http.ServeMux, c *cache.Cache) {
 			}
 
 			// Generate cache key
-			cacheKey := cache.GenerateKey(r.URL.Path, r.URL.RawQuery)
+			cacheKey := cache.GenerateKey(scope, r.URL.Path, r.URL.RawQuery)
 
 			// Check cache
 			if cached, found := c.Get(cacheKey); found {
@@ -147,6 +158,9 @@ func (h *Handler) RegisterRoutesWithCache(mux *http.ServeMux, c *cache.Cache) {
 
 	// Export endpoints with caching
 	mux.Handle("/api/v1/exports", cacheMiddleware(h.handleExports))
+
+	// Query endpoint with caching (GET only)
+	mux.Handle("/api/v1/query", cacheMiddleware(h.handleQuery))
 }
 
 // responseRecorder captures the HTTP response
@@ -172,7 +186,7 @@ func (h *Handler) writeJSON(w http.ResponseWriter, statusCode int, data interfac
 	if h.enableCORS {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, Authorization")
 	}
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(data)
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/justin4957/graphfs/internal/store"
 	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+	"github.com/justin4957/graphfs/pkg/shadow"
 )
 
 func setupTestGraph() *graph.Graph {
@@ -239,6 +242,81 @@ func TestHandleAnalysisStats(t *testing.T) {
 	}
 }
 
+func TestHandleAnalysisImpact(t *testing.T) {
+	g := setupTestGraph()
+	handler := NewHandler(g, true)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/api/v1/analysis/impact/utils/helper.go", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if response["impactCount"].(float64) != 1 {
+		t.Errorf("Expected 1 impacted module, got %v", response["impactCount"])
+	}
+	risk, ok := response["risk"].(map[string]interface{})
+	if !ok || risk["level"] == "" || risk["totalImpactedModules"].(float64) != 1 {
+		t.Errorf("Expected a risk assessment, got %v", response["risk"])
+	}
+}
+
+func TestHandleQuery(t *testing.T) {
+	g := setupTestGraph()
+	g.Store.Add("<#main.go>", "https://schema.codedoc.org/name", "main.go")
+	g.Store.Add("<#helper.go>", "https://schema.codedoc.org/name", "helper.go")
+	handler := NewHandler(g, true)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	queryString := "SELECT ?name WHERE { ?m <https://schema.codedoc.org/name> ?name }"
+
+	// Disabled until an executor is configured
+	req := httptest.NewRequest("POST", "/api/v1/query", strings.NewReader(`{"query": "SELECT ?s WHERE { ?s ?p ?o }"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 while disabled, got %d", w.Code)
+	}
+
+	handler.EnableQueries(query.NewExecutor(g.Store))
+
+	body, _ := json.Marshal(QueryRequest{Query: queryString})
+	req = httptest.NewRequest("POST", "/api/v1/query", strings.NewReader(string(body)))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response QueryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Count != 2 || len(response.Variables) != 1 || response.Variables[0] != "name" {
+		t.Errorf("Unexpected query response: %+v", response)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/query?q=NOT+A+QUERY", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid query, got %d", w.Code)
+	}
+}
+
 func TestHandleTags(t *testing.T) {
 	g := setupTestGraph()
 	handler := NewHandler(g, true)
@@ -307,3 +385,72 @@ func TestCORSHeaders(t *testing.T) {
 		t.Error("Expected CORS header")
 	}
 }
+
+func TestHandleModuleAnnotations(t *testing.T) {
+	g := setupTestGraph()
+	handler := NewHandler(g, true)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	post := func(path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	body := `{"key": "threat-model", "value": "reviewed", "author": "review-bot"}`
+
+	// Disabled until a token and shadow file system are configured
+	if w := post("/api/v1/modules/utils/helper.go/annotations", "secret", body); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 while disabled, got %d", w.Code)
+	}
+
+	root := t.TempDir()
+	shadowFS, err := shadow.NewShadowFS(root, shadow.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	handler.EnableAnnotationWrites(shadowFS, "secret")
+
+	if w := post("/api/v1/modules/utils/helper.go/annotations", "wrong", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a wrong token, got %d", w.Code)
+	}
+	if w := post("/api/v1/modules/missing.go/annotations", "secret", body); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown module, got %d", w.Code)
+	}
+	if w := post("/api/v1/modules/utils/helper.go/annotations", "secret", `{"value": 1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a key, got %d", w.Code)
+	}
+
+	w := post("/api/v1/modules/utils/helper.go/annotations", "secret", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response AnnotationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Module.Path != "utils/helper.go" || len(response.Annotations) != 1 {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	entry, err := shadowFS.Get("utils/helper.go")
+	if err != nil {
+		t.Fatalf("Annotation was not stored: %v", err)
+	}
+	if value, _ := entry.GetAnnotation("threat-model"); value != "reviewed" {
+		t.Errorf("Stored annotation = %v, want reviewed", value)
+	}
+
+	// Other subpaths stay read-only
+	req := httptest.NewRequest("POST", "/api/v1/modules/main.go", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+}
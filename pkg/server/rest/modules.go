@@ -174,15 +174,21 @@ func (h *Handler) handleModulesWithID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Extract module ID and check for subpaths
+	path := r.URL.Path
+	path = strings.TrimPrefix(path, "/api/v1/modules/")
+
+	// Annotation writes are the only non-GET requests
+	if strings.HasSuffix(path, "/annotations") {
+		h.handleModuleAnnotations(w, r, path)
+		return
+	}
+
 	if r.Method != "GET" {
 		h.writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET method is allowed")
 		return
 	}
 
-	// Extract module ID and check for subpaths
-	path := r.URL.Path
-	path = strings.TrimPrefix(path, "/api/v1/modules/")
-
 	// Handle subpaths
 	if strings.Contains(path, "/dependencies") {
 		h.handleModuleDependencies(w, r, path)
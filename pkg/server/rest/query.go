@@ -0,0 +1,115 @@
+/*
+# Module: pkg/server/rest/query.go
+Query endpoint for REST API.
+
+Runs SPARQL queries against the served graph and returns the bindings as
+plain JSON, for dashboards and editor plugins that do not speak the SPARQL
+results format. Shared prefixes and macros apply as on the command line.
+
+## Linked Modules
+- [./handler](./handler.go) - REST handler
+- [../../query](../../query/executor.go) - Query executor
+
+## Tags
+rest, api, query, sparql
+
+## Exports
+QueryRequest, QueryResponse
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#rest-query.go> a code:Module ;
+    code:name "pkg/server/rest/query.go" ;
+    code:description "Query endpoint for REST API" ;
+    code:language "go" ;
+    code:layer "server" ;
+    code:linksTo <./handler.go>, <../../query/executor.go> ;
+    code:exports <#QueryRequest>, <#QueryResponse> ;
+    code:tags "rest", "api", "query", "sparql" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/justin4957/graphfs/pkg/query"
+)
+
+// maxQueryBody limits the size of a query request body
+const maxQueryBody = 1 << 20
+
+// QueryRequest is the body of POST /api/v1/query
+type QueryRequest struct {
+	Query string `json:"query"`
+}
+
+// QueryResponse holds the results of a query
+type QueryResponse struct {
+	Variables []string            `json:"variables"`
+	Bindings  []map[string]string `json:"bindings"`
+	Count     int                 `json:"count"`
+}
+
+// EnableQueries serves /api/v1/query with the executor
+func (h *Handler) EnableQueries(executor *query.Executor) {
+	h.executor = executor
+}
+
+// handleQuery handles GET /api/v1/query?q=... and POST /api/v1/query
+func (h *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "OPTIONS" {
+		h.writeJSON(w, http.StatusOK, nil)
+		return
+	}
+
+	if h.executor == nil {
+		h.writeError(w, http.StatusForbidden, "QUERIES_DISABLED", "Queries are not enabled on this server")
+		return
+	}
+
+	var queryString string
+	switch r.Method {
+	case "GET":
+		queryString = r.URL.Query().Get("q")
+		if queryString == "" {
+			queryString = r.URL.Query().Get("query")
+		}
+	case "POST":
+		var req QueryRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxQueryBody)).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "INVALID_BODY", fmt.Sprintf("Invalid request body: %v", err))
+			return
+		}
+		queryString = req.Query
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET and POST methods are allowed")
+		return
+	}
+
+	if queryString == "" {
+		h.writeError(w, http.StatusBadRequest, "MISSING_QUERY", "A query is required ('q' parameter or 'query' field)")
+		return
+	}
+
+	result, err := h.executor.ExecuteString(queryString)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "QUERY_FAILED", fmt.Sprintf("Query failed: %v", err))
+		return
+	}
+
+	bindings := result.Bindings
+	if bindings == nil {
+		bindings = []map[string]string{}
+	}
+	h.writeJSON(w, http.StatusOK, QueryResponse{
+		Variables: result.Variables,
+		Bindings:  bindings,
+		Count:     result.Count,
+	})
+}
@@ -6,6 +6,7 @@ Provides HTTP server for SPARQL, GraphQL, and REST API endpoints.
 
 ## Linked Modules
 - [sparql_handler](./sparql_handler.go) - SPARQL HTTP handler
+- [refresh](./refresh.go) - Zero-downtime graph refresh
 - [../query](../query/executor.go) - Query executor
 - [../graph](../graph/graph.go) - Graph builder
 
@@ -24,7 +25,7 @@ Server, Config, NewServer
     code:description "HTTP server for GraphFS query endpoints" ;
     code:language "go" ;
     code:layer "server" ;
-    code:linksTo <./sparql_handler.go>, <../query/executor.go> ;
+    code:linksTo <./sparql_handler.go>, <./refresh.go>, <../query/executor.go> ;
     code:exports <#Server>, <#Config>, <#NewServer> ;
     code:tags "server", "http", "api" .
 <!-- End LinkedDoc RDF -->
@@ -37,6 +38,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/justin4957/graphfs/pkg/cache"
@@ -44,6 +47,7 @@ import (
 	"github.com/justin4957/graphfs/pkg/query"
 	graphqlserver "github.com/justin4957/graphfs/pkg/server/graphql"
 	restserver "github.com/justin4957/graphfs/pkg/server/rest"
+	"github.com/justin4957/graphfs/pkg/shadow"
 )
 
 // Config holds server configuration
@@ -59,6 +63,11 @@ type Config struct {
 	EnableCache      bool
 	CacheMaxEntries  int
 	CacheTTL         time.Duration
+
+	// AnnotationToken enables POST /api/v1/modules/:id/annotations for
+	// clients presenting it as a bearer token; writes go to Shadow
+	AnnotationToken string
+	Shadow          *shadow.ShadowFS
 }
 
 // DefaultConfig returns default server configuration
@@ -78,13 +87,25 @@ func DefaultConfig() *Config {
 	}
 }
 
+// apiVersion is the version of the HTTP API
+const apiVersion = "0.2.0"
+
 // Server is the HTTP server for GraphFS
 type Server struct {
 	config   *Config
-	executor *query.Executor
-	graph    *graph.Graph
+	executor *query.Executor // Initial executor, served until the first swap
+	graph    *graph.Graph    // Initial graph, served until the first swap
 	server   *http.Server
 	cache    *cache.Cache
+
+	lifecycleMu sync.Mutex // Guards server and stopped
+	stopped     bool       // Stop was called; Start no longer serves
+
+	current   atomic.Pointer[servedGraph] // Graph being served
+	swapMu    sync.Mutex                  // Serializes swaps
+	refreshMu sync.Mutex                  // Serializes refreshes
+	stateMu   sync.Mutex                  // Guards refresh
+	refresh   refreshState
 }
 
 // NewServer creates a new HTTP server
@@ -128,64 +149,41 @@ func NewServerWithGraph(config *Config, executor *query.Executor, g *graph.Graph
 
 // Start starts the HTTP server
 func (s *Server) Start() error {
-	mux := http.NewServeMux()
-
-	// SPARQL endpoint
-	sparqlHandler := NewSPARQLHandler(s.executor, s.config.EnableCORS)
-	if s.config.EnableCache && s.cache != nil {
-		mux.Handle("/sparql", CacheMiddleware(sparqlHandler, s.cache))
-	} else {
-		mux.Handle("/sparql", sparqlHandler)
-	}
-
-	// GraphQL endpoint (if enabled and graph is available)
-	if s.config.EnableGraphQL && s.graph != nil {
-		graphqlHandler, err := graphqlserver.NewHandler(s.graph, graphqlserver.HandlerConfig{
-			EnablePlayground: s.config.EnablePlayground,
-			EnableCORS:       s.config.EnableCORS,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create GraphQL handler: %w", err)
-		}
-
-		if s.config.EnableCache && s.cache != nil {
-			mux.Handle("/graphql", CacheMiddleware(graphqlHandler, s.cache))
-		} else {
-			mux.Handle("/graphql", graphqlHandler)
+	// Serve the initial graph unless a refresh already swapped one in
+	if s.current.Load() == nil {
+		if err := s.Swap(s.graph, s.executor); err != nil {
+			return err
 		}
 	}
 
-	// REST API endpoints (if enabled and graph is available)
-	if s.config.EnableREST && s.graph != nil {
-		restHandler := restserver.NewHandler(s.graph, s.config.EnableCORS)
-		if s.config.EnableCache && s.cache != nil {
-			restHandler.RegisterRoutesWithCache(mux, s.cache)
-		} else {
-			restHandler.RegisterRoutes(mux)
-		}
-	}
+	mux := http.NewServeMux()
 
-	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	})
+	// Health check and version endpoints report the served graph
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/version", s.handleVersion)
 
 	// Cache stats endpoint (if cache is enabled)
 	if s.config.EnableCache && s.cache != nil {
 		mux.HandleFunc("/cache/stats", s.handleCacheStats)
 	}
 
-	// Root endpoint with API info
-	mux.HandleFunc("/", s.handleRoot)
+	// Query endpoints are answered by the current graph
+	mux.HandleFunc("/", s.serveCurrent)
 
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
-	s.server = &http.Server{
+	httpServer := &http.Server{
 		Addr:         addr,
 		Handler:      mux,
 		ReadTimeout:  s.config.ReadTimeout,
 		WriteTimeout: s.config.WriteTimeout,
 	}
+	s.lifecycleMu.Lock()
+	if s.stopped {
+		s.lifecycleMu.Unlock()
+		return http.ErrServerClosed
+	}
+	s.server = httpServer
+	s.lifecycleMu.Unlock()
 
 	log.Printf("Starting GraphFS server on http://%s", addr)
 	log.Printf("SPARQL endpoint: http://%s/sparql", addr)
@@ -197,21 +195,85 @@ func (s *Server) Start() error {
 	}
 	if s.config.EnableREST && s.graph != nil {
 		log.Printf("REST API: http://%s/api/v1", addr)
+		if s.annotationWrites() {
+			log.Printf("Annotation writes: POST http://%s/api/v1/modules/{path}/annotations", addr)
+		}
 	}
 	if s.config.EnableCache && s.cache != nil {
 		log.Printf("Cache enabled: %d max entries, %v TTL", s.config.CacheMaxEntries, s.config.CacheTTL)
 		log.Printf("Cache stats: http://%s/cache/stats", addr)
 	}
 
-	return s.server.ListenAndServe()
+	return httpServer.ListenAndServe()
 }
 
-// Stop gracefully stops the server
+// registerGraphRoutes registers the SPARQL, GraphQL, REST and root
+// endpoints for one graph. Cached responses are keyed by cacheScope, which
+// identifies the graph.
+func (s *Server) registerGraphRoutes(mux *http.ServeMux, g *graph.Graph, executor *query.Executor, cacheScope string) error {
+	// SPARQL endpoint
+	sparqlHandler := NewSPARQLHandler(executor, s.config.EnableCORS)
+	if s.config.EnableCache && s.cache != nil {
+		mux.Handle("/sparql", CacheMiddleware(sparqlHandler, s.cache, cacheScope))
+	} else {
+		mux.Handle("/sparql", sparqlHandler)
+	}
+
+	// GraphQL endpoint (if enabled and graph is available)
+	if s.config.EnableGraphQL && g != nil {
+		graphqlHandler, err := graphqlserver.NewHandler(g, graphqlserver.HandlerConfig{
+			EnablePlayground: s.config.EnablePlayground,
+			EnableCORS:       s.config.EnableCORS,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create GraphQL handler: %w", err)
+		}
+
+		if s.config.EnableCache && s.cache != nil {
+			mux.Handle("/graphql", CacheMiddleware(graphqlHandler, s.cache, cacheScope))
+		} else {
+			mux.Handle("/graphql", graphqlHandler)
+		}
+	}
+
+	// REST API endpoints (if enabled and graph is available)
+	if s.config.EnableREST && g != nil {
+		restHandler := restserver.NewHandler(g, s.config.EnableCORS)
+		if executor != nil {
+			restHandler.EnableQueries(executor)
+		}
+		if s.annotationWrites() {
+			restHandler.EnableAnnotationWrites(s.config.Shadow, s.config.AnnotationToken)
+		}
+		if s.config.EnableCache && s.cache != nil {
+			restHandler.RegisterRoutesWithCache(mux, s.cache, cacheScope)
+		} else {
+			restHandler.RegisterRoutes(mux)
+		}
+	}
+
+	// Root endpoint with API info
+	mux.HandleFunc("/", s.handleRoot)
+	return nil
+}
+
+// annotationWrites reports whether the REST API accepts annotation writes
+func (s *Server) annotationWrites() bool {
+	return s.config.AnnotationToken != "" && s.config.Shadow != nil
+}
+
+// Stop gracefully stops the server, waiting for in-flight requests until
+// ctx is done. A server stopped before Start never serves.
 func (s *Server) Stop(ctx context.Context) error {
-	if s.server == nil {
+	s.lifecycleMu.Lock()
+	s.stopped = true
+	httpServer := s.server
+	s.lifecycleMu.Unlock()
+
+	if httpServer == nil {
 		return nil
 	}
-	return s.server.Shutdown(ctx)
+	return httpServer.Shutdown(ctx)
 }
 
 // handleRoot provides API information
@@ -226,7 +288,7 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 	// Build endpoints info
 	endpoints := `{
   "name": "GraphFS API",
-  "version": "0.2.0",
+  "version": "` + apiVersion + `",
   "endpoints": {
     "sparql": {
       "path": "/sparql",
@@ -249,23 +311,38 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 		endpoints += `,
     "rest": {
       "path": "/api/v1",
-      "methods": ["GET"],
+      "methods": ["GET", "POST"],
       "description": "RESTful API for common queries",
       "endpoints": {
         "modules": "/api/v1/modules",
         "search": "/api/v1/modules/search?q=query",
         "stats": "/api/v1/analysis/stats",
+        "impact": "/api/v1/analysis/impact/{path}",
+        "query": "/api/v1/query?q=query",
         "tags": "/api/v1/tags",
         "exports": "/api/v1/exports"
       }
     }`
+		if s.annotationWrites() {
+			endpoints += `,
+    "annotations": {
+      "path": "/api/v1/modules/{path}/annotations",
+      "methods": ["POST"],
+      "description": "Attach annotations to a module (bearer token required)"
+    }`
+		}
 	}
 
 	endpoints += `,
     "health": {
       "path": "/health",
       "methods": ["GET"],
-      "description": "Health check endpoint"
+      "description": "Health check with graph freshness"
+    },
+    "version": {
+      "path": "/version",
+      "methods": ["GET"],
+      "description": "API and served graph version"
     }
   }
 }`
@@ -31,7 +31,6 @@ SPARQLHandler, NewSPARQLHandler
 package server
 
 import (
-	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -161,6 +160,9 @@ func (h *SPARQLHandler) determineFormat(r *http.Request) string {
 	if strings.Contains(accept, "text/tab-separated-values") {
 		return "tsv"
 	}
+	if strings.Contains(accept, "application/x-ndjson") {
+		return "ndjson"
+	}
 
 	// Default to JSON
 	return "json"
@@ -175,6 +177,8 @@ func (h *SPARQLHandler) writeResult(w http.ResponseWriter, result *query.QueryRe
 		return h.writeCSV(w, result)
 	case "tsv":
 		return h.writeTSV(w, result)
+	case "ndjson":
+		return h.writeNDJSON(w, result)
 	case "xml":
 		return h.writeXML(w, result)
 	default:
@@ -182,87 +186,28 @@ func (h *SPARQLHandler) writeResult(w http.ResponseWriter, result *query.QueryRe
 	}
 }
 
-// writeJSON writes result as JSON
+// writeJSON writes result in the SPARQL Results JSON format
 func (h *SPARQLHandler) writeJSON(w http.ResponseWriter, result *query.QueryResult) error {
 	w.Header().Set("Content-Type", "application/sparql-results+json")
-
-	response := map[string]interface{}{
-		"head": map[string]interface{}{
-			"vars": result.Variables,
-		},
-		"results": map[string]interface{}{
-			"bindings": h.formatBindingsForJSON(result),
-		},
-	}
-
-	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(response)
-}
-
-// formatBindingsForJSON formats bindings for SPARQL JSON results format
-func (h *SPARQLHandler) formatBindingsForJSON(result *query.QueryResult) []map[string]interface{} {
-	var bindings []map[string]interface{}
-
-	for _, binding := range result.Bindings {
-		row := make(map[string]interface{})
-		for _, varName := range result.Variables {
-			if value, ok := binding[varName]; ok {
-				row[varName] = map[string]string{
-					"type":  "literal",
-					"value": value,
-				}
-			}
-		}
-		bindings = append(bindings, row)
-	}
-
-	return bindings
+	return query.WriteResult(w, result, query.FormatSPARQLJSON)
 }
 
 // writeCSV writes result as CSV
 func (h *SPARQLHandler) writeCSV(w http.ResponseWriter, result *query.QueryResult) error {
 	w.Header().Set("Content-Type", "text/csv")
-
-	writer := csv.NewWriter(w)
-	defer writer.Flush()
-
-	// Write header
-	if err := writer.Write(result.Variables); err != nil {
-		return err
-	}
-
-	// Write rows
-	for _, binding := range result.Bindings {
-		row := make([]string, len(result.Variables))
-		for i, varName := range result.Variables {
-			row[i] = binding[varName]
-		}
-		if err := writer.Write(row); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return query.WriteResult(w, result, query.FormatCSV)
 }
 
 // writeTSV writes result as TSV
 func (h *SPARQLHandler) writeTSV(w http.ResponseWriter, result *query.QueryResult) error {
 	w.Header().Set("Content-Type", "text/tab-separated-values")
+	return query.WriteResult(w, result, query.FormatTSV)
+}
 
-	// Write header
-	fmt.Fprintln(w, strings.Join(result.Variables, "\t"))
-
-	// Write rows
-	for _, binding := range result.Bindings {
-		row := make([]string, len(result.Variables))
-		for i, varName := range result.Variables {
-			row[i] = binding[varName]
-		}
-		fmt.Fprintln(w, strings.Join(row, "\t"))
-	}
-
-	return nil
+// writeNDJSON writes result as one JSON object per line
+func (h *SPARQLHandler) writeNDJSON(w http.ResponseWriter, result *query.QueryResult) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	return query.WriteResult(w, result, query.FormatNDJSON)
 }
 
 // SPARQLResultsXML represents the XML structure for SPARQL results
@@ -0,0 +1,204 @@
+package shacl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+const testPrefixes = `
+@prefix sh: <http://www.w3.org/ns/shacl#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+@prefix code: <https://schema.codedoc.org/> .
+@prefix ex: <https://example.org/shapes#> .
+`
+
+func TestParseTurtle(t *testing.T) {
+	doc, err := ParseTurtle(`
+PREFIX ex: <http://example.org/>
+@base <http://example.org/base/> .
+# A comment
+ex:a a ex:Thing ;
+    ex:name "A \"quoted\" name", 'single'@en ;
+    ex:count 42 ; ex:ratio 1.5 ; ex:big 1e3 ; ex:flag true ;
+    ex:rel <rel> ;
+    ex:list ( ex:x "y" ) ;
+    ex:nested [ ex:p ex:q ] ;
+    ex:typed "5"^^<http://www.w3.org/2001/XMLSchema#integer> ;
+    ex:long """two
+lines""" ;
+    .
+_:b1 ex:p ex:a.
+`)
+	if err != nil {
+		t.Fatalf("ParseTurtle failed: %v", err)
+	}
+
+	objects := make(map[string][]Term)
+	for _, triple := range doc.Triples {
+		objects[triple.Predicate.Value] = append(objects[triple.Predicate.Value], triple.Object)
+	}
+	check := func(predicate string, want Term) {
+		t.Helper()
+		for _, got := range objects["http://example.org/"+predicate] {
+			if got == want {
+				return
+			}
+		}
+		t.Errorf("%s objects = %+v, want %+v", predicate, objects["http://example.org/"+predicate], want)
+	}
+
+	check("name", Term{Kind: Literal, Value: `A "quoted" name`})
+	check("name", Term{Kind: Literal, Value: "single", Lang: "en"})
+	check("count", Term{Kind: Literal, Value: "42", Datatype: XSD + "integer"})
+	check("ratio", Term{Kind: Literal, Value: "1.5", Datatype: XSD + "decimal"})
+	check("big", Term{Kind: Literal, Value: "1e3", Datatype: XSD + "double"})
+	check("flag", Term{Kind: Literal, Value: "true", Datatype: XSD + "boolean"})
+	check("rel", Term{Kind: IRI, Value: "http://example.org/base/rel"})
+	check("typed", Term{Kind: Literal, Value: "5", Datatype: XSD + "integer"})
+	check("long", Term{Kind: Literal, Value: "two\nlines"})
+	check("p", Term{Kind: IRI, Value: "http://example.org/q"})
+	check("p", Term{Kind: IRI, Value: "http://example.org/a"})
+
+	if types := objects[RDFType]; len(types) != 1 || types[0].Value != "http://example.org/Thing" {
+		t.Errorf("rdf:type objects = %+v", types)
+	}
+	if len(objects[RDFFirst]) != 2 || len(objects[RDFRest]) != 2 {
+		t.Errorf("collection has %d firsts and %d rests, want 2 each", len(objects[RDFFirst]), len(objects[RDFRest]))
+	}
+
+	invalid := []string{
+		`ex:a ex:b ex:c .`,                          // undefined prefix
+		`@prefix ex: <http://e/> . ex:a ex:b "open`, // unterminated string
+		`@prefix ex: <http://e/> . ex:a ex:b ex:c`,  // missing dot
+	}
+	for _, data := range invalid {
+		if _, err := ParseTurtle(data); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
+
+func TestParseShapes(t *testing.T) {
+	shapes, err := ParseShapes(testPrefixes + `
+ex:ModuleShape a sh:NodeShape ;
+    sh:name "Module shape" ;
+    sh:targetClass code:Module ;
+    sh:property [
+        sh:path ( code:linksTo [ sh:inversePath code:exports ] ) ;
+        sh:sparql [ sh:select "SELECT $this WHERE {}" ] ;
+    ] .
+
+ex:Helper a sh:NodeShape ;
+    sh:property [ sh:path [ sh:alternativePath ( code:tags [ sh:zeroOrMorePath code:linksTo ] ) ] ] .
+
+ex:Class a sh:NodeShape, rdfs:Class ;
+    sh:deactivated true .
+`)
+	if err != nil {
+		t.Fatalf("ParseShapes failed: %v", err)
+	}
+
+	// Shapes without targets are not top-level shapes
+	if len(shapes.Shapes) != 2 {
+		t.Fatalf("Expected 2 shapes with targets, got %d", len(shapes.Shapes))
+	}
+	module := shapes.Shapes[0]
+	if module.ID != "ex:ModuleShape" || module.Name != "Module shape" {
+		t.Errorf("Shape = %s (%s)", module.ID, module.Name)
+	}
+	property := module.References()[0]
+	if got := property.Path.String(); got != "code:linksTo/^code:exports" {
+		t.Errorf("Path = %q", got)
+	}
+	if len(property.Unsupported) != 1 || property.Unsupported[0] != "sparql" {
+		t.Errorf("Unsupported = %v, want [sparql]", property.Unsupported)
+	}
+
+	class := shapes.Shapes[1]
+	if !class.Deactivated || len(class.Targets) != 1 || class.Targets[0].Kind != TargetClass {
+		t.Errorf("Implicit class target shape = %+v", class)
+	}
+
+	if _, err := ParseShapes(testPrefixes + `ex:S sh:targetNode ex:x ; sh:pattern "(" .`); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func testStore() *store.TripleStore {
+	st := store.NewTripleStore()
+	add := func(s, p, o string) {
+		st.Add(s, p, o)
+	}
+	code := "https://schema.codedoc.org/"
+
+	add("<"+code+"Service>", RDFS+"subClassOf", code+"Module")
+	add("<#main.go>", RDFType, code+"Module")
+	add("<#main.go>", code+"description", "Entry point")
+	add("<#main.go>", code+"layer", "app")
+	add("<#main.go>", code+"linksTo", "#auth.go")
+	add("<#auth.go>", RDFType, code+"Service")
+	add("<#auth.go>", code+"layer", "Services")
+	add("<#auth.go>", code+"layer", "service")
+	add("<#auth.go>", code+"exports", "#AuthService")
+	add("<#AuthService>", RDFType, code+"Type")
+	add("<#AuthService>", code+"complexity", "12")
+	return st
+}
+
+func TestValidate(t *testing.T) {
+	shapes, err := ParseShapes(testPrefixes + `
+ex:ModuleShape a sh:NodeShape ;
+    sh:targetClass code:Module ;
+    sh:property [ sh:path code:description ; sh:minCount 1 ; sh:severity sh:Warning ] ;
+    sh:property [ sh:path code:layer ; sh:maxCount 1 ; sh:pattern "^[a-z]+$" ] ;
+    sh:property [ sh:path code:layer ; sh:in ( "app" "service" ) ; sh:message "Unknown layer" ] .
+
+ex:ExportShape a sh:NodeShape ;
+    sh:targetSubjectsOf code:exports ;
+    sh:property [ sh:path code:exports ; sh:class code:Type ; sh:nodeKind sh:IRI ; sh:node ex:SimpleType ] .
+
+ex:SimpleType a sh:NodeShape ;
+    sh:property [ sh:path code:complexity ; sh:datatype xsd:integer ; sh:maxInclusive 10 ] .
+
+ex:LinkedShape a sh:NodeShape ;
+    sh:targetNode <#auth.go> ;
+    sh:property [ sh:path [ sh:inversePath code:linksTo ] ; sh:minCount 1 ] ;
+    sh:or ( [ sh:path code:description ; sh:minCount 1 ] [ sh:path code:exports ; sh:minCount 1 ] ) .
+
+ex:Off a sh:NodeShape ;
+    sh:targetClass code:Module ;
+    sh:deactivated true ;
+    sh:property [ sh:path code:missing ; sh:minCount 1 ] .
+`)
+	if err != nil {
+		t.Fatalf("ParseShapes failed: %v", err)
+	}
+
+	var got []string
+	for _, r := range shapes.Validate(testStore()) {
+		got = append(got, strings.Join([]string{r.SourceShape.ID[:2], r.FocusNode, r.Path, r.Value, r.Component, string(r.Severity)}, " "))
+		if r.Component == "InConstraintComponent" && r.Message != "Unknown layer" {
+			t.Errorf("Message = %q, want the shape's sh:message", r.Message)
+		}
+	}
+
+	want := []string{
+		// auth.go is a Module through rdfs:subClassOf
+		"_: #auth.go code:description  MinCountConstraintComponent Warning",
+		"_: #auth.go code:layer  MaxCountConstraintComponent Violation",
+		"_: #auth.go code:layer Services PatternConstraintComponent Violation",
+		"_: #auth.go code:layer Services InConstraintComponent Violation",
+		"_: #auth.go code:exports #AuthService NodeConstraintComponent Violation",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Results:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Result %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,550 @@
+/*
+# Module: pkg/shacl/shapes.go
+SHACL shapes model.
+
+Loads node and property shapes from a Turtle shapes graph: targets, property
+paths, severities, messages and the SHACL Core constraint components graphfs
+can evaluate. Constraint components it cannot evaluate are recorded on the
+shape instead of failing the load, so existing shape libraries can be reused.
+
+## Linked Modules
+- [turtle](./turtle.go) - Turtle parser
+- [validate](./validate.go) - Shape validation
+
+## Tags
+shacl, shapes, validation, rdf
+
+## Exports
+Shapes, Shape, Target, Path, Severity, LoadShapes, ParseShapes
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#shapes.go> a code:Module ;
+    code:name "pkg/shacl/shapes.go" ;
+    code:description "SHACL shapes model" ;
+    code:language "go" ;
+    code:layer "rules" ;
+    code:linksTo <./turtle.go>, <./validate.go> ;
+    code:exports <#Shapes>, <#Shape>, <#Target>, <#Path>, <#Severity>, <#LoadShapes>, <#ParseShapes> ;
+    code:tags "shacl", "shapes", "validation", "rdf" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shacl
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Namespaces and terms used by shapes graphs
+const (
+	SH   = "http://www.w3.org/ns/shacl#"
+	RDF  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	RDFS = "http://www.w3.org/2000/01/rdf-schema#"
+	OWL  = "http://www.w3.org/2002/07/owl#"
+	XSD  = "http://www.w3.org/2001/XMLSchema#"
+
+	RDFType  = RDF + "type"
+	RDFFirst = RDF + "first"
+	RDFRest  = RDF + "rest"
+	RDFNil   = RDF + "nil"
+)
+
+// Severity is the severity of a shape's validation results
+type Severity string
+
+const (
+	SeverityViolation Severity = "Violation"
+	SeverityWarning   Severity = "Warning"
+	SeverityInfo      Severity = "Info"
+)
+
+// Target kinds
+const (
+	TargetClass      = "class"
+	TargetNode       = "node"
+	TargetSubjectsOf = "subjectsOf"
+	TargetObjectsOf  = "objectsOf"
+)
+
+// ignoredParameters are SHACL predicates that carry no constraint
+var ignoredParameters = map[string]bool{
+	"order": true, "group": true, "defaultValue": true, "description": true,
+	"name": true, "message": true, "severity": true, "deactivated": true,
+	"flags": true, "ignoredProperties": true,
+}
+
+// Shapes is a loaded shapes graph
+type Shapes struct {
+	// Shapes with targets, in document order
+	Shapes []*Shape
+
+	prefixes map[string]string
+}
+
+// Shape is a node shape, or a property shape when Path is set
+type Shape struct {
+	ID          string
+	Name        string
+	Description string
+	Message     string
+	Severity    Severity
+	Deactivated bool
+	Targets     []Target
+	Path        *Path
+
+	// Unsupported lists constraint components that are not evaluated
+	Unsupported []string
+
+	properties []*Shape
+	classes    []string
+	datatype   string
+	nodeKind   string
+	minCount   *int
+	maxCount   *int
+	minLength  *int
+	maxLength  *int
+	minIncl    *float64
+	maxIncl    *float64
+	minExcl    *float64
+	maxExcl    *float64
+	pattern    *regexp.Regexp
+	in         []Term
+	hasValue   []Term
+	equals     []string
+	disjoint   []string
+	node       []*Shape
+	not        []*Shape
+	and        [][]*Shape
+	or         [][]*Shape
+	xone       [][]*Shape
+	closed     bool
+	ignored    []string
+}
+
+// Target selects the focus nodes of a shape
+type Target struct {
+	Kind  string
+	Value string
+}
+
+// Path is a SHACL property path
+type Path struct {
+	kind      string // predicate, inverse, sequence, alternative, zeroOrMore, oneOrMore, zeroOrOne
+	predicate string
+	paths     []*Path
+	display   string
+}
+
+// String returns the path in SHACL syntax with the shapes graph prefixes
+func (p *Path) String() string {
+	return p.display
+}
+
+// References returns the shapes a shape refers to: its property shapes and
+// the shapes of its shape-based and logical constraints
+func (s *Shape) References() []*Shape {
+	refs := append([]*Shape{}, s.properties...)
+	refs = append(refs, s.node...)
+	refs = append(refs, s.not...)
+	for _, lists := range [][][]*Shape{s.and, s.or, s.xone} {
+		for _, members := range lists {
+			refs = append(refs, members...)
+		}
+	}
+	return refs
+}
+
+// LoadShapes loads a shapes graph from a Turtle file
+func LoadShapes(path string) (*Shapes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shapes file: %w", err)
+	}
+	shapes, err := ParseShapes(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shapes file %s: %w", path, err)
+	}
+	return shapes, nil
+}
+
+// ParseShapes parses a shapes graph from Turtle
+func ParseShapes(data string) (*Shapes, error) {
+	doc, err := ParseTurtle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &shapeLoader{
+		subjects: make(map[string]map[string][]Term),
+		shapes:   make(map[string]*Shape),
+		result:   &Shapes{prefixes: doc.Prefixes},
+	}
+	var order []Term
+	for _, t := range doc.Triples {
+		key := t.Subject.key()
+		if l.subjects[key] == nil {
+			l.subjects[key] = make(map[string][]Term)
+			order = append(order, t.Subject)
+		}
+		l.subjects[key][t.Predicate.Value] = append(l.subjects[key][t.Predicate.Value], t.Object)
+	}
+
+	for _, subject := range order {
+		if !l.hasTargets(subject) {
+			continue
+		}
+		shape, err := l.shape(subject)
+		if err != nil {
+			return nil, err
+		}
+		l.result.Shapes = append(l.result.Shapes, shape)
+	}
+	return l.result, nil
+}
+
+// Compact abbreviates an IRI with the shapes graph prefixes
+func (s *Shapes) Compact(iri string) string {
+	return compact(s.prefixes, iri)
+}
+
+func compact(prefixes map[string]string, iri string) string {
+	best, bestNamespace := "", ""
+	for prefix, namespace := range prefixes {
+		if namespace != "" && strings.HasPrefix(iri, namespace) && len(namespace) > len(bestNamespace) {
+			best, bestNamespace = prefix, namespace
+		}
+	}
+	if bestNamespace == "" {
+		return iri
+	}
+	return best + ":" + iri[len(bestNamespace):]
+}
+
+// shapeLoader builds shapes from an indexed shapes graph
+type shapeLoader struct {
+	subjects map[string]map[string][]Term
+	shapes   map[string]*Shape
+	result   *Shapes
+}
+
+func (l *shapeLoader) values(subject Term, predicate string) []Term {
+	return l.subjects[subject.key()][predicate]
+}
+
+func (l *shapeLoader) hasType(subject Term, types ...string) bool {
+	for _, t := range l.values(subject, RDFType) {
+		for _, want := range types {
+			if t.Value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTargets reports whether a subject is a shape with targets, including
+// implicit class targets
+func (l *shapeLoader) hasTargets(subject Term) bool {
+	for _, predicate := range []string{"targetClass", "targetNode", "targetSubjectsOf", "targetObjectsOf"} {
+		if len(l.values(subject, SH+predicate)) > 0 {
+			return true
+		}
+	}
+	return l.hasType(subject, SH+"NodeShape", SH+"PropertyShape") && l.hasType(subject, RDFS+"Class", OWL+"Class")
+}
+
+// list reads an RDF collection
+func (l *shapeLoader) list(head Term) []Term {
+	var items []Term
+	seen := make(map[string]bool)
+	for head.Value != RDFNil && !seen[head.key()] {
+		seen[head.key()] = true
+		first := l.values(head, RDFFirst)
+		rest := l.values(head, RDFRest)
+		if len(first) == 0 || len(rest) == 0 {
+			break
+		}
+		items = append(items, first[0])
+		head = rest[0]
+	}
+	return items
+}
+
+func (l *shapeLoader) shapeList(head Term) ([]*Shape, error) {
+	var shapes []*Shape
+	for _, item := range l.list(head) {
+		shape, err := l.shape(item)
+		if err != nil {
+			return nil, err
+		}
+		shapes = append(shapes, shape)
+	}
+	return shapes, nil
+}
+
+// shape loads the shape with the given node, once
+func (l *shapeLoader) shape(node Term) (*Shape, error) {
+	if shape, ok := l.shapes[node.key()]; ok {
+		return shape, nil
+	}
+
+	shape := &Shape{ID: node.Value}
+	if node.Kind == BlankNode {
+		shape.ID = "_:" + node.Value
+	} else {
+		shape.ID = l.result.Compact(node.Value)
+	}
+	l.shapes[node.key()] = shape
+
+	props := l.subjects[node.key()]
+	predicates := make([]string, 0, len(props))
+	for predicate := range props {
+		predicates = append(predicates, predicate)
+	}
+	sort.Strings(predicates)
+
+	for _, predicate := range predicates {
+		if !strings.HasPrefix(predicate, SH) {
+			continue
+		}
+		if err := l.parameter(shape, strings.TrimPrefix(predicate, SH), props[predicate]); err != nil {
+			return nil, fmt.Errorf("shape %s: %w", shape.ID, err)
+		}
+	}
+
+	shape.Name = firstValue(props[SH+"name"], props[RDFS+"label"])
+	shape.Description = firstValue(props[SH+"description"], props[RDFS+"comment"])
+	shape.Message = firstValue(props[SH+"message"])
+	if l.hasType(node, RDFS+"Class", OWL+"Class") {
+		shape.Targets = append(shape.Targets, Target{Kind: TargetClass, Value: node.Value})
+	}
+
+	if flags := firstValue(props[SH+"flags"]); shape.pattern != nil && flags != "" {
+		goFlags := ""
+		for _, flag := range flags {
+			switch flag {
+			case 'i', 's', 'm':
+				goFlags += string(flag)
+			default:
+				shape.Unsupported = append(shape.Unsupported, fmt.Sprintf("flags %q", flags))
+			}
+		}
+		if goFlags != "" {
+			re, err := regexp.Compile("(?" + goFlags + ")" + shape.pattern.String())
+			if err != nil {
+				return nil, fmt.Errorf("shape %s: invalid pattern: %w", shape.ID, err)
+			}
+			shape.pattern = re
+		}
+	}
+	return shape, nil
+}
+
+// parameter applies one sh: predicate to a shape
+func (l *shapeLoader) parameter(shape *Shape, name string, values []Term) error {
+	for _, value := range values {
+		switch name {
+		case "deactivated":
+			shape.Deactivated = value.Value == "true"
+		case "severity":
+			shape.Severity = Severity(strings.TrimPrefix(value.Value, SH))
+		case "targetClass":
+			shape.Targets = append(shape.Targets, Target{Kind: TargetClass, Value: value.Value})
+		case "targetNode":
+			shape.Targets = append(shape.Targets, Target{Kind: TargetNode, Value: value.Value})
+		case "targetSubjectsOf":
+			shape.Targets = append(shape.Targets, Target{Kind: TargetSubjectsOf, Value: value.Value})
+		case "targetObjectsOf":
+			shape.Targets = append(shape.Targets, Target{Kind: TargetObjectsOf, Value: value.Value})
+		case "path":
+			path, err := l.path(value, 0)
+			if err != nil {
+				return err
+			}
+			shape.Path = path
+		case "property":
+			property, err := l.shape(value)
+			if err != nil {
+				return err
+			}
+			shape.properties = append(shape.properties, property)
+		case "node", "not":
+			other, err := l.shape(value)
+			if err != nil {
+				return err
+			}
+			if name == "node" {
+				shape.node = append(shape.node, other)
+			} else {
+				shape.not = append(shape.not, other)
+			}
+		case "and", "or", "xone":
+			members, err := l.shapeList(value)
+			if err != nil {
+				return err
+			}
+			switch name {
+			case "and":
+				shape.and = append(shape.and, members)
+			case "or":
+				shape.or = append(shape.or, members)
+			default:
+				shape.xone = append(shape.xone, members)
+			}
+		case "class":
+			shape.classes = append(shape.classes, value.Value)
+		case "datatype":
+			shape.datatype = value.Value
+		case "nodeKind":
+			shape.nodeKind = strings.TrimPrefix(value.Value, SH)
+		case "minCount", "maxCount", "minLength", "maxLength":
+			n, err := strconv.Atoi(value.Value)
+			if err != nil {
+				return fmt.Errorf("sh:%s must be an integer, got %q", name, value.Value)
+			}
+			switch name {
+			case "minCount":
+				shape.minCount = &n
+			case "maxCount":
+				shape.maxCount = &n
+			case "minLength":
+				shape.minLength = &n
+			default:
+				shape.maxLength = &n
+			}
+		case "minInclusive", "maxInclusive", "minExclusive", "maxExclusive":
+			f, err := strconv.ParseFloat(value.Value, 64)
+			if err != nil {
+				shape.Unsupported = append(shape.Unsupported, fmt.Sprintf("%s %q", name, value.Value))
+				continue
+			}
+			switch name {
+			case "minInclusive":
+				shape.minIncl = &f
+			case "maxInclusive":
+				shape.maxIncl = &f
+			case "minExclusive":
+				shape.minExcl = &f
+			default:
+				shape.maxExcl = &f
+			}
+		case "pattern":
+			re, err := regexp.Compile(value.Value)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", value.Value, err)
+			}
+			shape.pattern = re
+		case "in":
+			shape.in = append(shape.in, l.list(value)...)
+		case "hasValue":
+			shape.hasValue = append(shape.hasValue, value)
+		case "equals":
+			shape.equals = append(shape.equals, value.Value)
+		case "disjoint":
+			shape.disjoint = append(shape.disjoint, value.Value)
+		case "closed":
+			shape.closed = value.Value == "true"
+		case "ignoredProperties":
+			for _, item := range l.list(value) {
+				shape.ignored = append(shape.ignored, item.Value)
+			}
+		default:
+			if !ignoredParameters[name] && !slices.Contains(shape.Unsupported, name) {
+				shape.Unsupported = append(shape.Unsupported, name)
+			}
+		}
+	}
+	return nil
+}
+
+// maxPathDepth bounds nested path expressions
+const maxPathDepth = 16
+
+// path parses a property path
+func (l *shapeLoader) path(node Term, depth int) (*Path, error) {
+	if depth > maxPathDepth {
+		return nil, fmt.Errorf("property path nested too deeply")
+	}
+	if node.Kind == IRI && node.Value != RDFNil {
+		return &Path{kind: "predicate", predicate: node.Value, display: l.result.Compact(node.Value)}, nil
+	}
+
+	// Sequence paths are lists
+	if len(l.values(node, RDFFirst)) > 0 {
+		items := l.list(node)
+		path := &Path{kind: "sequence"}
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			sub, err := l.path(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			path.paths = append(path.paths, sub)
+			parts = append(parts, sub.display)
+		}
+		path.display = strings.Join(parts, "/")
+		return path, nil
+	}
+
+	for _, kind := range []string{"inversePath", "zeroOrMorePath", "oneOrMorePath", "zeroOrOnePath"} {
+		values := l.values(node, SH+kind)
+		if len(values) == 0 {
+			continue
+		}
+		sub, err := l.path(values[0], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		path := &Path{kind: strings.TrimSuffix(kind, "Path"), paths: []*Path{sub}}
+		inner := sub.display
+		if sub.kind != "predicate" {
+			inner = "(" + inner + ")"
+		}
+		switch path.kind {
+		case "inverse":
+			path.display = "^" + inner
+		case "zeroOrMore":
+			path.display = inner + "*"
+		case "oneOrMore":
+			path.display = inner + "+"
+		default:
+			path.display = inner + "?"
+		}
+		return path, nil
+	}
+
+	if values := l.values(node, SH+"alternativePath"); len(values) > 0 {
+		path := &Path{kind: "alternative"}
+		parts := []string{}
+		for _, item := range l.list(values[0]) {
+			sub, err := l.path(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			path.paths = append(path.paths, sub)
+			parts = append(parts, sub.display)
+		}
+		path.display = strings.Join(parts, "|")
+		return path, nil
+	}
+
+	return nil, fmt.Errorf("unsupported property path %s", node.key())
+}
+
+func firstValue(lists ...[]Term) string {
+	for _, values := range lists {
+		if len(values) > 0 {
+			return values[0].Value
+		}
+	}
+	return ""
+}
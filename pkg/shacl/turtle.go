@@ -0,0 +1,660 @@
+/*
+# Module: pkg/shacl/turtle.go
+Turtle parser for SHACL shape files.
+
+Parses the Turtle syntax used by shape libraries: @prefix/@base and SPARQL
+style PREFIX/BASE directives, IRIs, prefixed names, blank node labels,
+blank node property lists, collections, and string, numeric and boolean
+literals.
+
+## Linked Modules
+- [shapes](./shapes.go) - Shapes model
+
+## Tags
+shacl, turtle, rdf, parser
+
+## Exports
+Term, TermKind, Triple, Turtle, ParseTurtle
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#turtle.go> a code:Module ;
+    code:name "pkg/shacl/turtle.go" ;
+    code:description "Turtle parser for SHACL shape files" ;
+    code:language "go" ;
+    code:layer "rules" ;
+    code:linksTo <./shapes.go> ;
+    code:exports <#Term>, <#TermKind>, <#Triple>, <#Turtle>, <#ParseTurtle> ;
+    code:tags "shacl", "turtle", "rdf", "parser" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shacl
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TermKind distinguishes IRIs, blank nodes and literals
+type TermKind int
+
+const (
+	IRI TermKind = iota
+	BlankNode
+	Literal
+)
+
+// Term is an RDF term
+type Term struct {
+	Kind     TermKind
+	Value    string // IRI, blank node label or lexical form
+	Datatype string // Datatype IRI of typed literals
+	Lang     string // Language tag of literals
+}
+
+// key identifies a term as a subject
+func (t Term) key() string {
+	if t.Kind == BlankNode {
+		return "_:" + t.Value
+	}
+	return t.Value
+}
+
+// Triple is a parsed RDF triple
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// Turtle is a parsed Turtle document
+type Turtle struct {
+	Triples  []Triple
+	Prefixes map[string]string
+}
+
+// ParseTurtle parses a Turtle document
+func ParseTurtle(data string) (*Turtle, error) {
+	p := &turtleParser{src: data, line: 1, prefixes: make(map[string]string)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return &Turtle{Triples: p.triples, Prefixes: p.prefixes}, nil
+}
+
+// turtleParser is a recursive descent Turtle parser
+type turtleParser struct {
+	src      string
+	pos      int
+	line     int
+	prefixes map[string]string
+	base     string
+	triples  []Triple
+	blanks   int
+}
+
+func (p *turtleParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *turtleParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *turtleParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *turtleParser) peekAt(offset int) byte {
+	if p.pos+offset >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+offset]
+}
+
+// skipSpace skips whitespace and comments
+func (p *turtleParser) skipSpace() {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// expect consumes c after optional whitespace
+func (p *turtleParser) expect(c byte) error {
+	p.skipSpace()
+	if p.peek() != c {
+		if p.eof() {
+			return p.errorf("expected '%c', found end of input", c)
+		}
+		return p.errorf("expected '%c', found '%c'", c, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+func (p *turtleParser) emit(s, pred, o Term) {
+	p.triples = append(p.triples, Triple{Subject: s, Predicate: pred, Object: o})
+}
+
+func (p *turtleParser) newBlank() Term {
+	p.blanks++
+	return Term{Kind: BlankNode, Value: fmt.Sprintf("genid%d", p.blanks)}
+}
+
+func (p *turtleParser) parse() error {
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil
+		}
+		if err := p.statement(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *turtleParser) statement() error {
+	if p.peek() == '@' {
+		p.pos++
+		word := p.word()
+		switch word {
+		case "prefix":
+			if err := p.prefixDirective(); err != nil {
+				return err
+			}
+		case "base":
+			if err := p.baseDirective(); err != nil {
+				return err
+			}
+		default:
+			return p.errorf("unknown directive @%s", word)
+		}
+		return p.expect('.')
+	}
+
+	// SPARQL style directives take no trailing dot
+	start, line := p.pos, p.line
+	switch word := strings.ToLower(p.word()); {
+	case word == "prefix" && p.atSpace():
+		return p.prefixDirective()
+	case word == "base" && p.atSpace():
+		return p.baseDirective()
+	}
+	p.pos, p.line = start, line
+
+	if err := p.triplesStatement(); err != nil {
+		return err
+	}
+	return p.expect('.')
+}
+
+// word reads a run of letters
+func (p *turtleParser) word() string {
+	start := p.pos
+	for !p.eof() && isLetter(p.peek()) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *turtleParser) atSpace() bool {
+	c := p.peek()
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func (p *turtleParser) prefixDirective() error {
+	p.skipSpace()
+	start := p.pos
+	for !p.eof() && p.peek() != ':' && !p.atSpace() {
+		p.pos++
+	}
+	prefix := p.src[start:p.pos]
+	if err := p.expect(':'); err != nil {
+		return err
+	}
+	p.skipSpace()
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	p.prefixes[prefix] = iri
+	return nil
+}
+
+func (p *turtleParser) baseDirective() error {
+	p.skipSpace()
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	p.base = iri
+	return nil
+}
+
+func (p *turtleParser) triplesStatement() error {
+	p.skipSpace()
+	if p.peek() == '[' {
+		subject, err := p.blankNodePropertyList()
+		if err != nil {
+			return err
+		}
+		// A blank node property list may stand alone
+		p.skipSpace()
+		if p.peek() == '.' {
+			return nil
+		}
+		return p.predicateObjectList(subject)
+	}
+
+	subject, err := p.subject()
+	if err != nil {
+		return err
+	}
+	return p.predicateObjectList(subject)
+}
+
+func (p *turtleParser) subject() (Term, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '<':
+		value, err := p.iriRef()
+		return Term{Kind: IRI, Value: value}, err
+	case c == '_' && p.peekAt(1) == ':':
+		return p.blankNodeLabel(), nil
+	case c == '(':
+		return p.collection()
+	default:
+		value, err := p.prefixedName()
+		return Term{Kind: IRI, Value: value}, err
+	}
+}
+
+func (p *turtleParser) predicateObjectList(subject Term) error {
+	for {
+		predicate, err := p.verb()
+		if err != nil {
+			return err
+		}
+		if err := p.objectList(subject, predicate); err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		if p.peek() != ';' {
+			return nil
+		}
+		for p.peek() == ';' {
+			p.pos++
+			p.skipSpace()
+		}
+		// A trailing semicolon ends the list
+		if c := p.peek(); c == '.' || c == ']' || p.eof() {
+			return nil
+		}
+	}
+}
+
+func (p *turtleParser) verb() (Term, error) {
+	p.skipSpace()
+	if p.peek() == 'a' && (p.pos+1 >= len(p.src) || isDelimiter(p.peekAt(1))) {
+		p.pos++
+		return Term{Kind: IRI, Value: RDFType}, nil
+	}
+	if p.peek() == '<' {
+		value, err := p.iriRef()
+		return Term{Kind: IRI, Value: value}, err
+	}
+	value, err := p.prefixedName()
+	return Term{Kind: IRI, Value: value}, err
+}
+
+func (p *turtleParser) objectList(subject, predicate Term) error {
+	for {
+		object, err := p.object()
+		if err != nil {
+			return err
+		}
+		p.emit(subject, predicate, object)
+
+		p.skipSpace()
+		if p.peek() != ',' {
+			return nil
+		}
+		p.pos++
+	}
+}
+
+func (p *turtleParser) object() (Term, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '<':
+		value, err := p.iriRef()
+		return Term{Kind: IRI, Value: value}, err
+	case c == '_' && p.peekAt(1) == ':':
+		return p.blankNodeLabel(), nil
+	case c == '[':
+		return p.blankNodePropertyList()
+	case c == '(':
+		return p.collection()
+	case c == '"' || c == '\'':
+		return p.literal()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	case strings.HasPrefix(p.src[p.pos:], "true") && isDelimiter(p.peekAt(4)):
+		p.pos += 4
+		return Term{Kind: Literal, Value: "true", Datatype: XSD + "boolean"}, nil
+	case strings.HasPrefix(p.src[p.pos:], "false") && isDelimiter(p.peekAt(5)):
+		p.pos += 5
+		return Term{Kind: Literal, Value: "false", Datatype: XSD + "boolean"}, nil
+	case p.eof():
+		return Term{}, p.errorf("expected object, found end of input")
+	default:
+		value, err := p.prefixedName()
+		return Term{Kind: IRI, Value: value}, err
+	}
+}
+
+// iriRef reads <iri>, resolving it against the base IRI
+func (p *turtleParser) iriRef() (string, error) {
+	if p.peek() != '<' {
+		return "", p.errorf("expected IRI")
+	}
+	end := strings.IndexByte(p.src[p.pos:], '>')
+	if end < 0 {
+		return "", p.errorf("unterminated IRI")
+	}
+	iri := p.src[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	if p.base != "" && !strings.Contains(iri, ":") {
+		base, err := url.Parse(p.base)
+		if err != nil {
+			return "", p.errorf("invalid base IRI %q", p.base)
+		}
+		ref, err := url.Parse(iri)
+		if err != nil {
+			return "", p.errorf("invalid IRI %q", iri)
+		}
+		iri = base.ResolveReference(ref).String()
+	}
+	return iri, nil
+}
+
+// prefixedName reads prefix:local and expands it
+func (p *turtleParser) prefixedName() (string, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != ':' && isNameChar(p.peek()) {
+		p.pos++
+	}
+	if p.peek() != ':' {
+		if p.eof() {
+			return "", p.errorf("unexpected end of input")
+		}
+		return "", p.errorf("unexpected %q", p.src[start:p.pos+1])
+	}
+	prefix := p.src[start:p.pos]
+	p.pos++
+
+	var local strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		if c == '\\' && p.pos+1 < len(p.src) {
+			local.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+			continue
+		}
+		if !isNameChar(c) && c != ':' && c != '%' {
+			break
+		}
+		local.WriteByte(c)
+		p.pos++
+	}
+	// A local name cannot end with a dot; it ends the statement
+	name := local.String()
+	for strings.HasSuffix(name, ".") {
+		name = name[:len(name)-1]
+		p.pos--
+	}
+
+	namespace, ok := p.prefixes[prefix]
+	if !ok {
+		return "", p.errorf("undefined prefix %q", prefix)
+	}
+	return namespace + name, nil
+}
+
+func (p *turtleParser) blankNodeLabel() Term {
+	p.pos += 2
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.pos++
+	}
+	label := strings.TrimRight(p.src[start:p.pos], ".")
+	p.pos = start + len(label)
+	return Term{Kind: BlankNode, Value: "b-" + label}
+}
+
+func (p *turtleParser) blankNodePropertyList() (Term, error) {
+	p.pos++ // [
+	node := p.newBlank()
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return node, nil
+	}
+	if err := p.predicateObjectList(node); err != nil {
+		return Term{}, err
+	}
+	return node, p.expect(']')
+}
+
+// collection reads ( ... ) as an rdf:first/rdf:rest list
+func (p *turtleParser) collection() (Term, error) {
+	p.pos++ // (
+	var items []Term
+	for {
+		p.skipSpace()
+		if p.peek() == ')' {
+			p.pos++
+			break
+		}
+		if p.eof() {
+			return Term{}, p.errorf("unterminated collection")
+		}
+		item, err := p.object()
+		if err != nil {
+			return Term{}, err
+		}
+		items = append(items, item)
+	}
+
+	head := Term{Kind: IRI, Value: RDFNil}
+	for i := len(items) - 1; i >= 0; i-- {
+		node := p.newBlank()
+		p.emit(node, Term{Kind: IRI, Value: RDFFirst}, items[i])
+		p.emit(node, Term{Kind: IRI, Value: RDFRest}, head)
+		head = node
+	}
+	return head, nil
+}
+
+func (p *turtleParser) literal() (Term, error) {
+	quote := p.peek()
+	long := p.peekAt(1) == quote && p.peekAt(2) == quote
+	if long {
+		p.pos += 3
+	} else {
+		p.pos++
+	}
+
+	var value strings.Builder
+	for {
+		if p.eof() {
+			return Term{}, p.errorf("unterminated string")
+		}
+		c := p.peek()
+		if c == quote {
+			if !long {
+				p.pos++
+				break
+			}
+			if p.peekAt(1) == quote && p.peekAt(2) == quote {
+				p.pos += 3
+				break
+			}
+		}
+		if c == '\n' {
+			if !long {
+				return Term{}, p.errorf("newline in string")
+			}
+			p.line++
+		}
+		if c == '\\' {
+			r, err := p.escape()
+			if err != nil {
+				return Term{}, err
+			}
+			value.WriteRune(r)
+			continue
+		}
+		value.WriteByte(c)
+		p.pos++
+	}
+
+	term := Term{Kind: Literal, Value: value.String()}
+	switch {
+	case p.peek() == '@':
+		p.pos++
+		start := p.pos
+		for !p.eof() && (isLetter(p.peek()) || p.peek() == '-' || (p.peek() >= '0' && p.peek() <= '9')) {
+			p.pos++
+		}
+		term.Lang = p.src[start:p.pos]
+	case p.peek() == '^' && p.peekAt(1) == '^':
+		p.pos += 2
+		var err error
+		if p.peek() == '<' {
+			term.Datatype, err = p.iriRef()
+		} else {
+			term.Datatype, err = p.prefixedName()
+		}
+		if err != nil {
+			return Term{}, err
+		}
+	}
+	return term, nil
+}
+
+func (p *turtleParser) escape() (rune, error) {
+	if p.pos+1 >= len(p.src) {
+		return 0, p.errorf("unterminated escape")
+	}
+	c := p.src[p.pos+1]
+	p.pos += 2
+	switch c {
+	case 't':
+		return '\t', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case '"', '\'', '\\':
+		return rune(c), nil
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		p.pos += size
+		return rune(code), nil
+	}
+	return 0, p.errorf("invalid escape \\%c", c)
+}
+
+func (p *turtleParser) number() (Term, error) {
+	start := p.pos
+	if c := p.peek(); c == '+' || c == '-' {
+		p.pos++
+	}
+	digits := func() int {
+		n := 0
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+			n++
+		}
+		return n
+	}
+
+	datatype := XSD + "integer"
+	n := digits()
+	// A dot is part of the number only if a digit follows
+	if p.peek() == '.' && p.peekAt(1) >= '0' && p.peekAt(1) <= '9' {
+		p.pos++
+		n += digits()
+		datatype = XSD + "decimal"
+	}
+	if n == 0 {
+		return Term{}, p.errorf("invalid number %q", p.src[start:p.pos+1])
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if digits() == 0 {
+			return Term{}, p.errorf("invalid exponent in %q", p.src[start:p.pos])
+		}
+		datatype = XSD + "double"
+	}
+	return Term{Kind: Literal, Value: p.src[start:p.pos], Datatype: datatype}, nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isNameChar reports whether c may appear in a prefixed or blank node name
+func isNameChar(c byte) bool {
+	return c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || c == '_' || c == '-' || c == '.'
+}
+
+// isDelimiter reports whether c ends a keyword
+func isDelimiter(c byte) bool {
+	switch c {
+	case 0, ' ', '\t', '\n', '\r', ';', ',', '.', ']', ')', '#', '[', '(', '<', '"', '\'':
+		return true
+	}
+	return false
+}
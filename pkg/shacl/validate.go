@@ -0,0 +1,516 @@
+/*
+# Module: pkg/shacl/validate.go
+SHACL validation against the knowledge graph.
+
+Selects each shape's focus nodes from the triple store, follows property
+paths and reports a result for every constraint a value node violates.
+Store subjects carry angle brackets and IRI objects do not, so nodes are
+compared in their bare form; store values are untyped, so datatypes are
+checked against the lexical form.
+
+## Linked Modules
+- [shapes](./shapes.go) - Shapes model
+- [../../internal/store](../../internal/store/store.go) - Triple store
+
+## Tags
+shacl, validation, rdf
+
+## Exports
+Result, Validate
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#validate.go> a code:Module ;
+    code:name "pkg/shacl/validate.go" ;
+    code:description "SHACL validation against the knowledge graph" ;
+    code:language "go" ;
+    code:layer "rules" ;
+    code:linksTo <./shapes.go>, <../../internal/store/store.go> ;
+    code:exports <#Result>, <#Validate> ;
+    code:tags "shacl", "validation", "rdf" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shacl
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/justin4957/graphfs/internal/store"
+)
+
+// maxShapeDepth bounds recursion through sh:node and logical constraints
+const maxShapeDepth = 32
+
+// Result is a SHACL validation result
+type Result struct {
+	FocusNode   string
+	Path        string // Result path, empty for node shapes
+	Value       string // Offending value node, if any
+	SourceShape *Shape
+	Component   string // Constraint component, e.g. MinCountConstraintComponent
+	Severity    Severity
+	Message     string
+}
+
+// Validate validates the graph against every active shape with targets
+func (s *Shapes) Validate(st *store.TripleStore) []Result {
+	var results []Result
+	for _, shape := range s.Shapes {
+		results = append(results, shape.Validate(st)...)
+	}
+	return results
+}
+
+// Validate validates the focus nodes of a shape
+func (s *Shape) Validate(st *store.TripleStore) []Result {
+	if s.Deactivated {
+		return nil
+	}
+	v := &validator{store: st}
+	var results []Result
+	for _, focus := range v.focusNodes(s) {
+		results = append(results, v.validate(s, focus)...)
+	}
+	return results
+}
+
+type validator struct {
+	store *store.TripleStore
+	depth int
+}
+
+// subjectKey returns the store subject for a node
+func subjectKey(node string) string {
+	if strings.HasPrefix(node, "_:") {
+		return node
+	}
+	return "<" + node + ">"
+}
+
+// nodeOf returns the bare node for a store subject
+func nodeOf(subject string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(subject, "<"), ">")
+}
+
+// focusNodes returns the sorted focus nodes selected by a shape's targets
+func (v *validator) focusNodes(s *Shape) []string {
+	seen := make(map[string]bool)
+	add := func(node string) {
+		seen[node] = true
+	}
+
+	for _, target := range s.Targets {
+		switch target.Kind {
+		case TargetClass:
+			for _, class := range v.subclasses(target.Value) {
+				for _, t := range v.store.Find("", RDFType, class) {
+					add(nodeOf(t.Subject))
+				}
+			}
+		case TargetNode:
+			add(target.Value)
+		case TargetSubjectsOf:
+			for _, t := range v.store.Find("", target.Value, "") {
+				add(nodeOf(t.Subject))
+			}
+		case TargetObjectsOf:
+			for _, t := range v.store.Find("", target.Value, "") {
+				add(t.Object)
+			}
+		}
+	}
+
+	nodes := make([]string, 0, len(seen))
+	for node := range seen {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// subclasses returns a class and its transitive rdfs:subClassOf subclasses
+func (v *validator) subclasses(class string) []string {
+	classes := []string{class}
+	seen := map[string]bool{class: true}
+	for i := 0; i < len(classes); i++ {
+		for _, t := range v.store.Find("", RDFS+"subClassOf", classes[i]) {
+			if sub := nodeOf(t.Subject); !seen[sub] {
+				seen[sub] = true
+				classes = append(classes, sub)
+			}
+		}
+	}
+	return classes
+}
+
+// values returns the sorted value nodes reached from a node by a path
+func (v *validator) values(node string, path *Path) []string {
+	seen := make(map[string]bool)
+	for _, value := range v.follow([]string{node}, path) {
+		seen[value] = true
+	}
+	values := make([]string, 0, len(seen))
+	for value := range seen {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func (v *validator) follow(nodes []string, path *Path) []string {
+	var out []string
+	switch path.kind {
+	case "predicate":
+		for _, node := range nodes {
+			for _, t := range v.store.Find(subjectKey(node), path.predicate, "") {
+				out = append(out, t.Object)
+			}
+		}
+	case "inverse":
+		sub := path.paths[0]
+		if sub.kind != "predicate" {
+			return nil
+		}
+		for _, node := range nodes {
+			for _, t := range v.store.Find("", sub.predicate, node) {
+				out = append(out, nodeOf(t.Subject))
+			}
+		}
+	case "sequence":
+		out = nodes
+		for _, sub := range path.paths {
+			out = v.follow(out, sub)
+		}
+	case "alternative":
+		for _, sub := range path.paths {
+			out = append(out, v.follow(nodes, sub)...)
+		}
+	case "zeroOrOne":
+		out = append(append(out, nodes...), v.follow(nodes, path.paths[0])...)
+	case "zeroOrMore", "oneOrMore":
+		seen := make(map[string]bool)
+		frontier := nodes
+		if path.kind == "zeroOrMore" {
+			for _, node := range nodes {
+				seen[node] = true
+			}
+			out = append(out, nodes...)
+		}
+		for len(frontier) > 0 {
+			var next []string
+			for _, node := range v.follow(frontier, path.paths[0]) {
+				if !seen[node] {
+					seen[node] = true
+					out = append(out, node)
+					next = append(next, node)
+				}
+			}
+			frontier = next
+		}
+	}
+	return out
+}
+
+// conforms reports whether a node conforms to a shape
+func (v *validator) conforms(s *Shape, node string) bool {
+	if v.depth >= maxShapeDepth {
+		return true
+	}
+	v.depth++
+	defer func() { v.depth-- }()
+	return len(v.validate(s, node)) == 0
+}
+
+// validate returns the results of validating one focus node against a shape
+func (v *validator) validate(s *Shape, focus string) []Result {
+	if s.Deactivated {
+		return nil
+	}
+
+	var results []Result
+	path := ""
+	values := []string{focus}
+	if s.Path != nil {
+		path = s.Path.String()
+		values = v.values(focus, s.Path)
+	}
+
+	report := func(component, value, message string) {
+		severity := s.Severity
+		if severity == "" {
+			severity = SeverityViolation
+		}
+		if s.Message != "" {
+			message = s.Message
+		}
+		results = append(results, Result{
+			FocusNode:   focus,
+			Path:        path,
+			Value:       value,
+			SourceShape: s,
+			Component:   component + "ConstraintComponent",
+			Severity:    severity,
+			Message:     message,
+		})
+	}
+
+	subject := "node"
+	if path != "" {
+		subject = path
+	}
+	if s.minCount != nil && len(values) < *s.minCount {
+		report("MinCount", "", fmt.Sprintf("%s has %d value(s), expected at least %d", subject, len(values), *s.minCount))
+	}
+	if s.maxCount != nil && len(values) > *s.maxCount {
+		report("MaxCount", "", fmt.Sprintf("%s has %d value(s), expected at most %d", subject, len(values), *s.maxCount))
+	}
+
+	for _, value := range values {
+		for _, class := range s.classes {
+			if !v.hasClass(value, class) {
+				report("Class", value, fmt.Sprintf("%s is not an instance of %s", value, class))
+			}
+		}
+		if s.datatype != "" && !v.hasDatatype(value, s.datatype) {
+			report("Datatype", value, fmt.Sprintf("%s is not a valid %s", value, s.datatype))
+		}
+		if s.nodeKind != "" && !v.hasNodeKind(value, s.nodeKind) {
+			report("NodeKind", value, fmt.Sprintf("%s is not a node of kind %s", value, s.nodeKind))
+		}
+
+		length := utf8.RuneCountInString(value)
+		if s.minLength != nil && length < *s.minLength {
+			report("MinLength", value, fmt.Sprintf("%q is shorter than %d characters", value, *s.minLength))
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			report("MaxLength", value, fmt.Sprintf("%q is longer than %d characters", value, *s.maxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			report("Pattern", value, fmt.Sprintf("%q does not match %s", value, s.pattern))
+		}
+		v.checkRange(s, value, report)
+
+		if len(s.in) > 0 && !termsContain(s.in, value) {
+			report("In", value, fmt.Sprintf("%s is not one of the allowed values", value))
+		}
+
+		for _, other := range s.node {
+			if !v.conforms(other, value) {
+				report("Node", value, fmt.Sprintf("%s does not conform to shape %s", value, other.ID))
+			}
+		}
+		for _, other := range s.not {
+			if v.conforms(other, value) {
+				report("Not", value, fmt.Sprintf("%s conforms to shape %s", value, other.ID))
+			}
+		}
+		for _, members := range s.and {
+			for _, other := range members {
+				if !v.conforms(other, value) {
+					report("And", value, fmt.Sprintf("%s does not conform to shape %s", value, other.ID))
+					break
+				}
+			}
+		}
+		for _, members := range s.or {
+			if v.countConforming(members, value) == 0 {
+				report("Or", value, fmt.Sprintf("%s conforms to none of the alternative shapes", value))
+			}
+		}
+		for _, members := range s.xone {
+			if n := v.countConforming(members, value); n != 1 {
+				report("Xone", value, fmt.Sprintf("%s conforms to %d of the shapes, expected exactly 1", value, n))
+			}
+		}
+
+		if s.closed {
+			v.checkClosed(s, value, report)
+		}
+	}
+
+	for _, required := range s.hasValue {
+		if !slices.Contains(values, required.Value) {
+			report("HasValue", "", fmt.Sprintf("%s is missing the value %s", subject, required.Value))
+		}
+	}
+	for _, predicate := range s.equals {
+		other := v.values(focus, &Path{kind: "predicate", predicate: predicate})
+		for _, value := range symmetricDifference(values, other) {
+			report("Equals", value, fmt.Sprintf("values of %s and %s differ in %s", subject, predicate, value))
+		}
+	}
+	for _, predicate := range s.disjoint {
+		other := v.values(focus, &Path{kind: "predicate", predicate: predicate})
+		for _, value := range values {
+			if slices.Contains(other, value) {
+				report("Disjoint", value, fmt.Sprintf("%s is also a value of %s", value, predicate))
+			}
+		}
+	}
+
+	// Property shapes apply to each value node
+	for _, property := range s.properties {
+		for _, value := range values {
+			results = append(results, v.validate(property, value)...)
+		}
+	}
+	return results
+}
+
+func (v *validator) countConforming(shapes []*Shape, node string) int {
+	n := 0
+	for _, shape := range shapes {
+		if v.conforms(shape, node) {
+			n++
+		}
+	}
+	return n
+}
+
+// hasClass reports whether a node is an instance of a class or a subclass
+func (v *validator) hasClass(node, class string) bool {
+	for _, c := range v.subclasses(class) {
+		if len(v.store.Find(subjectKey(node), RDFType, c)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isIRI reports whether a store value is an IRI rather than a literal
+func (v *validator) isIRI(value string) bool {
+	if strings.HasPrefix(value, "_:") {
+		return false
+	}
+	if strings.HasPrefix(value, "#") || strings.HasPrefix(value, "./") || strings.HasPrefix(value, "../") ||
+		strings.Contains(value, "://") || strings.HasPrefix(value, "urn:") {
+		return true
+	}
+	return len(v.store.Find(subjectKey(value), "", "")) > 0
+}
+
+func (v *validator) hasNodeKind(value, kind string) bool {
+	blank := strings.HasPrefix(value, "_:")
+	iri := !blank && v.isIRI(value)
+	literal := !blank && !iri
+	switch kind {
+	case "IRI":
+		return iri
+	case "BlankNode":
+		return blank
+	case "Literal":
+		return literal
+	case "BlankNodeOrIRI":
+		return blank || iri
+	case "BlankNodeOrLiteral":
+		return blank || literal
+	case "IRIOrLiteral":
+		return iri || literal
+	}
+	return true
+}
+
+// hasDatatype checks a value's lexical form against a datatype
+func (v *validator) hasDatatype(value, datatype string) bool {
+	if v.isIRI(value) || strings.HasPrefix(value, "_:") {
+		return false
+	}
+	switch strings.TrimPrefix(datatype, XSD) {
+	case "boolean":
+		return value == "true" || value == "false" || value == "1" || value == "0"
+	case "integer", "int", "long", "short", "byte":
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case "nonNegativeInteger", "unsignedInt", "unsignedLong":
+		n, err := strconv.ParseInt(value, 10, 64)
+		return err == nil && n >= 0
+	case "positiveInteger":
+		n, err := strconv.ParseInt(value, 10, 64)
+		return err == nil && n > 0
+	case "decimal", "double", "float":
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "dateTime":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	}
+	return true
+}
+
+func (v *validator) checkRange(s *Shape, value string, report func(component, value, message string)) {
+	if s.minIncl == nil && s.maxIncl == nil && s.minExcl == nil && s.maxExcl == nil {
+		return
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		report("MinInclusive", value, fmt.Sprintf("%s is not a number", value))
+		return
+	}
+	if s.minIncl != nil && n < *s.minIncl {
+		report("MinInclusive", value, fmt.Sprintf("%s is less than %v", value, *s.minIncl))
+	}
+	if s.maxIncl != nil && n > *s.maxIncl {
+		report("MaxInclusive", value, fmt.Sprintf("%s is greater than %v", value, *s.maxIncl))
+	}
+	if s.minExcl != nil && n <= *s.minExcl {
+		report("MinExclusive", value, fmt.Sprintf("%s is not greater than %v", value, *s.minExcl))
+	}
+	if s.maxExcl != nil && n >= *s.maxExcl {
+		report("MaxExclusive", value, fmt.Sprintf("%s is not less than %v", value, *s.maxExcl))
+	}
+}
+
+// checkClosed reports predicates of a node that no property shape allows
+func (v *validator) checkClosed(s *Shape, node string, report func(component, value, message string)) {
+	allowed := make(map[string]bool)
+	for _, predicate := range s.ignored {
+		allowed[predicate] = true
+	}
+	for _, property := range s.properties {
+		if property.Path != nil && property.Path.kind == "predicate" {
+			allowed[property.Path.predicate] = true
+		}
+	}
+	for _, t := range v.store.Find(subjectKey(node), "", "") {
+		if !allowed[t.Predicate] {
+			report("Closed", t.Object, fmt.Sprintf("%s has predicate %s, which the closed shape does not allow", node, t.Predicate))
+		}
+	}
+}
+
+func termsContain(terms []Term, value string) bool {
+	for _, t := range terms {
+		if t.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// symmetricDifference returns the values in exactly one of a and b
+func symmetricDifference(a, b []string) []string {
+	var diff []string
+	for _, value := range a {
+		if !slices.Contains(b, value) {
+			diff = append(diff, value)
+		}
+	}
+	for _, value := range b {
+		if !slices.Contains(a, value) {
+			diff = append(diff, value)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,224 @@
+/*
+# Module: pkg/shadow/annotations.go
+Bulk annotation management for shadow entries.
+
+Adds and removes annotations on every shadow entry matching a path pattern,
+and lists annotations across the shadow file system to audit how each key
+is used.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [defaults](./defaults.go) - Path pattern matching
+
+## Tags
+shadow, annotations, bulk
+
+## Exports
+AnnotationRecord, AnnotationKeyUsage, SummarizeAnnotations
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#annotations.go> a code:Module ;
+    code:name "pkg/shadow/annotations.go" ;
+    code:description "Bulk annotation management for shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./defaults.go> ;
+    code:exports <#AnnotationRecord>, <#AnnotationKeyUsage>, <#SummarizeAnnotations> ;
+    code:tags "shadow", "annotations", "bulk" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+)
+
+// AnnotationRecord is an annotation together with the entry it is on
+type AnnotationRecord struct {
+	Path string `json:"path"`
+	Annotation
+	Expired bool `json:"expired,omitempty"`
+}
+
+// AnnotationKeyUsage summarizes how an annotation key is used
+type AnnotationKeyUsage struct {
+	Key     string         `json:"key"`
+	Count   int            `json:"count"`
+	Expired int            `json:"expired,omitempty"`
+	Values  map[string]int `json:"values"` // Entries per value
+	Paths   []string       `json:"paths"`
+}
+
+// RemoveAnnotation removes the annotation with a key and reports whether
+// there was one
+func (e *Entry) RemoveAnnotation(key string) bool {
+	for i, a := range e.Annotations {
+		if a.Key == key {
+			e.Annotations = append(e.Annotations[:i], e.Annotations[i+1:]...)
+			e.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveAnnotation removes an annotation from a source file's entry on
+// behalf of actor and reports whether there was one. A missing entry has no
+// annotations.
+func (s *ShadowFS) RemoveAnnotation(sourcePath, key, actor string) (bool, error) {
+	if key == "" {
+		return false, fmt.Errorf("annotation key is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shadowPath, err := s.GetShadowPath(sourcePath)
+	if err != nil {
+		return false, err
+	}
+
+	entry, err := s.loadEntry(shadowPath)
+	if isVersionError(err) {
+		return false, err
+	}
+	if err != nil || !entry.RemoveAnnotation(key) {
+		return false, nil
+	}
+
+	if err := s.setUnlocked(sourcePath, entry, "annotate", actor); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MatchEntries returns the source paths of the entries matching a path
+// pattern, sorted. Patterns are written as for shadow defaults: a directory,
+// a directory followed by "/**", or a glob.
+func (s *ShadowFS) MatchEntries(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if matchTemplatePath(pattern, entry.SourcePath) >= 0 {
+			paths = append(paths, entry.SourcePath)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// AnnotateMatching adds an annotation to every entry matching a path pattern
+// and returns the annotated paths
+func (s *ShadowFS) AnnotateMatching(pattern string, annotation Annotation, actor string) ([]string, error) {
+	paths, err := s.MatchEntries(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range paths {
+		if _, err := s.Annotate(p, annotation, actor); err != nil {
+			return paths[:i], fmt.Errorf("failed to annotate %s: %w", p, err)
+		}
+	}
+	return paths, nil
+}
+
+// RemoveAnnotationMatching removes an annotation from every entry matching a
+// path pattern and returns the paths it was removed from
+func (s *ShadowFS) RemoveAnnotationMatching(pattern, key, actor string) ([]string, error) {
+	paths, err := s.MatchEntries(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, p := range paths {
+		ok, err := s.RemoveAnnotation(p, key, actor)
+		if err != nil {
+			return removed, fmt.Errorf("failed to update %s: %w", p, err)
+		}
+		if ok {
+			removed = append(removed, p)
+		}
+	}
+	return removed, nil
+}
+
+// ListAnnotations returns the annotations of every entry, or only those
+// with a key when key is set, sorted by key and then by path
+func (s *ShadowFS) ListAnnotations(key string) ([]AnnotationRecord, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var records []AnnotationRecord
+	for _, entry := range entries {
+		for _, a := range entry.Annotations {
+			if key != "" && a.Key != key {
+				continue
+			}
+			records = append(records, AnnotationRecord{
+				Path:       entry.SourcePath,
+				Annotation: a,
+				Expired:    a.IsExpired(now),
+			})
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Key != records[j].Key {
+			return records[i].Key < records[j].Key
+		}
+		return records[i].Path < records[j].Path
+	})
+	return records, nil
+}
+
+// SummarizeAnnotations groups annotation records by key, most used first
+func SummarizeAnnotations(records []AnnotationRecord) []AnnotationKeyUsage {
+	usageByKey := make(map[string]*AnnotationKeyUsage)
+	for _, r := range records {
+		usage, ok := usageByKey[r.Key]
+		if !ok {
+			usage = &AnnotationKeyUsage{Key: r.Key, Values: make(map[string]int)}
+			usageByKey[r.Key] = usage
+		}
+		usage.Count++
+		if r.Expired {
+			usage.Expired++
+		}
+		usage.Values[fmt.Sprint(r.Value)]++
+		usage.Paths = append(usage.Paths, r.Path)
+	}
+
+	result := make([]AnnotationKeyUsage, 0, len(usageByKey))
+	for _, usage := range usageByKey {
+		sort.Strings(usage.Paths)
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
@@ -0,0 +1,135 @@
+/*
+# Module: pkg/shadow/annotations_test.go
+Tests for bulk annotation management.
+
+## Tags
+shadow, test, annotations
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#annotations_test.go> a code:Module ;
+    code:name "pkg/shadow/annotations_test.go" ;
+    code:description "Tests for bulk annotation management" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./annotations.go> ;
+    code:tags "shadow", "test", "annotations" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newAnnotatedShadowFS(t *testing.T) *ShadowFS {
+	t.Helper()
+
+	shadowFS, err := NewShadowFS(t.TempDir(), DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	for _, path := range []string{"pkg/api/handler.go", "pkg/api/v2/routes.go", "pkg/store/db.go", "main.go"} {
+		if err := shadowFS.Set(path, NewAutoEntry(path)); err != nil {
+			t.Fatalf("Failed to set entry: %v", err)
+		}
+	}
+	return shadowFS
+}
+
+func TestAnnotateMatching(t *testing.T) {
+	shadowFS := newAnnotatedShadowFS(t)
+
+	paths, err := shadowFS.AnnotateMatching("pkg/api/**", Annotation{Key: "owner", Value: "team-x"}, "")
+	if err != nil {
+		t.Fatalf("AnnotateMatching failed: %v", err)
+	}
+	if want := []string{"pkg/api/handler.go", "pkg/api/v2/routes.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Annotated %v, want %v", paths, want)
+	}
+
+	entry, err := shadowFS.Get("pkg/api/v2/routes.go")
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if value, ok := entry.GetAnnotation("owner"); !ok || value != "team-x" {
+		t.Errorf("Expected owner annotation, got %v", entry.Annotations)
+	}
+
+	if paths, _ := shadowFS.AnnotateMatching("pkg/*/db.go", Annotation{Key: "owner", Value: "team-y"}, ""); len(paths) != 1 {
+		t.Errorf("Expected a single-star glob to match one entry, got %v", paths)
+	}
+	if _, err := shadowFS.AnnotateMatching("pkg/[", Annotation{Key: "owner", Value: "x"}, ""); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestRemoveAnnotationMatching(t *testing.T) {
+	shadowFS := newAnnotatedShadowFS(t)
+	if _, err := shadowFS.AnnotateMatching("pkg/api", Annotation{Key: "reviewed", Value: "true"}, ""); err != nil {
+		t.Fatalf("AnnotateMatching failed: %v", err)
+	}
+	if _, err := shadowFS.Annotate("main.go", Annotation{Key: "reviewed", Value: "true"}, ""); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+
+	removed, err := shadowFS.RemoveAnnotationMatching("pkg/**", "reviewed", "")
+	if err != nil {
+		t.Fatalf("RemoveAnnotationMatching failed: %v", err)
+	}
+	if want := []string{"pkg/api/handler.go", "pkg/api/v2/routes.go"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("Removed from %v, want %v", removed, want)
+	}
+
+	entry, _ := shadowFS.Get("main.go")
+	if _, ok := entry.GetAnnotation("reviewed"); !ok {
+		t.Error("Expected entries outside the pattern to keep their annotation")
+	}
+
+	if ok, err := shadowFS.RemoveAnnotation("main.go", "missing", ""); err != nil || ok {
+		t.Errorf("RemoveAnnotation(missing) = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := shadowFS.RemoveAnnotation("main.go", "reviewed", ""); err != nil || !ok {
+		t.Errorf("RemoveAnnotation(reviewed) = %v, %v; want true, nil", ok, err)
+	}
+}
+
+func TestListAnnotations(t *testing.T) {
+	shadowFS := newAnnotatedShadowFS(t)
+	if _, err := shadowFS.AnnotateMatching("pkg/api/**", Annotation{Key: "owner", Value: "team-x"}, ""); err != nil {
+		t.Fatalf("AnnotateMatching failed: %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if _, err := shadowFS.Annotate("pkg/store/db.go", Annotation{Key: "owner", Value: "team-y", ExpiresAt: &past}, ""); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+	if _, err := shadowFS.Annotate("main.go", Annotation{Key: "reviewed", Value: "true"}, ""); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+
+	records, err := shadowFS.ListAnnotations("owner")
+	if err != nil {
+		t.Fatalf("ListAnnotations failed: %v", err)
+	}
+	if len(records) != 3 || records[0].Path != "pkg/api/handler.go" || !records[2].Expired {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+
+	all, _ := shadowFS.ListAnnotations("")
+	usage := SummarizeAnnotations(all)
+	if len(usage) != 2 || usage[0].Key != "owner" || usage[0].Count != 3 || usage[0].Expired != 1 {
+		t.Fatalf("Unexpected usage: %+v", usage)
+	}
+	if want := map[string]int{"team-x": 2, "team-y": 1}; !reflect.DeepEqual(usage[0].Values, want) {
+		t.Errorf("Values = %v, want %v", usage[0].Values, want)
+	}
+}
@@ -0,0 +1,446 @@
+/*
+# Module: pkg/shadow/audit.go
+Audit log of shadow writes.
+
+Every write to a shadow or directory entry is appended to .graphfs/audit.log
+as one JSON record: when, who, which operation, which file and what changed.
+The log is only appended to, except when retention drops old records.
+Changes are listed as "+" and "-" facts (tags, layer, annotations, triples,
+...), so a reviewer can trace how a file's metadata evolved.
+
+The actor is Config.AuditActor, else $GRAPHFS_ACTOR, else the git user
+email, else the OS user.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [lock](./lock.go) - Atomic file writes
+
+## Tags
+shadow, audit, compliance, history
+
+## Exports
+DefaultAuditLog, AuditRecord, AuditRetention, AuditLog, NewAuditLog, DiffEntries, SetAuditEnabled
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#audit.go> a code:Module ;
+    code:name "pkg/shadow/audit.go" ;
+    code:description "Audit log of shadow writes" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./lock.go> ;
+    code:exports <#DefaultAuditLog>, <#AuditRecord>, <#AuditRetention>, <#AuditLog>, <#NewAuditLog>,
+                 <#DiffEntries>, <#SetAuditEnabled> ;
+    code:tags "shadow", "audit", "compliance", "history" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// DefaultAuditLog is the audit log location relative to the project root
+const DefaultAuditLog = ".graphfs/audit.log"
+
+// maxAuditChanges caps the changes listed per record; the rest are counted
+const maxAuditChanges = 50
+
+// auditDisabled turns off auditing for configs created by DefaultConfig
+var auditDisabled atomic.Bool
+
+// SetAuditEnabled sets whether DefaultConfig enables the audit log
+func SetAuditEnabled(enabled bool) {
+	auditDisabled.Store(!enabled)
+}
+
+// AuditRecord is one shadow write
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Operation string    `json:"operation"` // e.g. set, merge, delete, prune
+	Path      string    `json:"path"`      // Source path (directory entries end in /)
+	Changes   []string  `json:"changes,omitempty"`
+}
+
+// AuditRetention limits how long records are kept; zero values keep all
+type AuditRetention struct {
+	MaxAge     time.Duration // Drop records older than this
+	MaxRecords int           // Keep at most this many of the newest records
+}
+
+// AuditLog is an append-only JSON lines log of shadow writes
+type AuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAuditLog opens the audit log at path (created on first append)
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Path returns the log file path
+func (l *AuditLog) Path() string {
+	return l.path
+}
+
+// Append adds records to the end of the log
+func (l *AuditLog) Append(records ...AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit record: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	// One write per call keeps concurrent appends from interleaving
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Records returns the records for a source path (all records if path is
+// empty), oldest first. A directory path ending in / also matches the files
+// below it.
+func (l *AuditLog) Records(path string) ([]AuditRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records, err := l.readAll()
+	if err != nil || path == "" {
+		return records, err
+	}
+
+	keys := pathkey.Default()
+	key := keys.Key(path)
+	dir := strings.HasSuffix(path, "/")
+	var matched []AuditRecord
+	for _, record := range records {
+		recordKey := keys.Key(record.Path)
+		if recordKey == key || (dir && strings.HasPrefix(recordKey, key+"/")) {
+			matched = append(matched, record)
+		}
+	}
+	return matched, nil
+}
+
+// Prune drops records outside the retention limits and returns how many
+// were removed. The log is rewritten atomically.
+func (l *AuditLog) Prune(retention AuditRetention, now time.Time) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records, err := l.readAll()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := records
+	if retention.MaxAge > 0 {
+		cutoff := now.Add(-retention.MaxAge)
+		kept = kept[:0:0]
+		for _, record := range records {
+			if !record.Time.Before(cutoff) {
+				kept = append(kept, record)
+			}
+		}
+	}
+	if retention.MaxRecords > 0 && len(kept) > retention.MaxRecords {
+		kept = kept[len(kept)-retention.MaxRecords:]
+	}
+
+	removed := len(records) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	for _, record := range kept {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal audit record: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	if err := writeFileAtomic(l.path, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("failed to rewrite audit log: %w", err)
+	}
+	return removed, nil
+}
+
+// readAll reads every record (caller must hold mu)
+func (l *AuditLog) readAll() ([]AuditRecord, error) {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return records, nil
+}
+
+// DiffEntries lists what changed between two versions of an entry as "+"
+// (added) and "-" (removed) facts, sorted. A nil entry is an entry that does
+// not exist.
+func DiffEntries(before, after *Entry) []string {
+	old, updated := entryFacts(before), entryFacts(after)
+
+	var changes []string
+	for fact := range old {
+		if !updated[fact] {
+			changes = append(changes, "-"+fact)
+		}
+	}
+	for fact := range updated {
+		if !old[fact] {
+			changes = append(changes, "+"+fact)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		// Order by fact, removals before additions
+		if changes[i][1:] != changes[j][1:] {
+			return changes[i][1:] < changes[j][1:]
+		}
+		return changes[i][0] == '-'
+	})
+	return changes
+}
+
+// entryFacts flattens the audited content of an entry
+func entryFacts(e *Entry) map[string]bool {
+	facts := make(map[string]bool)
+	if e == nil {
+		return facts
+	}
+	add := func(format string, args ...interface{}) {
+		facts[fmt.Sprintf(format, args...)] = true
+	}
+
+	add("source %s", e.Source)
+	if e.Module != nil {
+		addModuleFacts(add, "module", *e.Module)
+	}
+	for _, component := range e.Components {
+		addModuleFacts(add, "component "+component.Name, component)
+	}
+	for _, dep := range e.Dependencies {
+		add("dependency %s %s", dep.Type, dep.Target)
+	}
+	for _, export := range e.Exports {
+		add("export %s", export)
+	}
+	for _, call := range e.Calls {
+		add("call %s", call)
+	}
+	for _, t := range e.Triples {
+		add("triple %s %s %s%s", t.Subject, t.Predicate, t.Object, expirySuffix(t.ExpiresAt))
+	}
+	for _, a := range e.Annotations {
+		add("annotation %s=%v%s", a.Key, a.Value, expirySuffix(a.ExpiresAt))
+	}
+	for _, concept := range e.Concepts {
+		add("concept %s", concept)
+	}
+	for key, value := range e.Properties {
+		add("property %s=%v", key, value)
+	}
+	return facts
+}
+
+// addModuleFacts adds the facts of a module or component
+func addModuleFacts(add func(string, ...interface{}), prefix string, m Module) {
+	for field, value := range map[string]string{
+		"uri": m.URI, "name": m.Name, "description": m.Description,
+		"language": m.Language, "layer": m.Layer,
+	} {
+		if value != "" {
+			add("%s.%s %s", prefix, field, value)
+		}
+	}
+	for _, tag := range m.Tags {
+		add("%s.tag %s", prefix, tag)
+	}
+}
+
+// expirySuffix describes an expiry for a fact
+func expirySuffix(expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return ""
+	}
+	return " (expires " + expiresAt.UTC().Format(time.RFC3339) + ")"
+}
+
+// recordWrite appends a record for a write that changed before into after.
+// Writes that change nothing are not recorded.
+func (s *ShadowFS) recordWrite(operation, path string, before, after *Entry) error {
+	return s.recordWriteAs(operation, path, "", before, after)
+}
+
+// recordWriteAs is recordWrite on behalf of actor (default: the configured
+// actor)
+func (s *ShadowFS) recordWriteAs(operation, path, actor string, before, after *Entry) error {
+	if s.audit == nil {
+		return nil
+	}
+
+	changes := DiffEntries(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	if len(changes) > maxAuditChanges {
+		more := len(changes) - maxAuditChanges
+		changes = append(changes[:maxAuditChanges], fmt.Sprintf("... %d more", more))
+	}
+	if actor == "" {
+		actor = s.auditActor()
+	}
+
+	return s.audit.Append(AuditRecord{
+		Time:      time.Now().UTC(),
+		Actor:     actor,
+		Operation: operation,
+		Path:      path,
+		Changes:   changes,
+	})
+}
+
+// saveEntryFile saves an entry to a shadow file and records the change
+// (caller must hold lock)
+func (s *ShadowFS) saveEntryFile(shadowPath, path string, entry *Entry, operation string) error {
+	return s.saveEntryFileAs(shadowPath, path, entry, operation, "")
+}
+
+// saveEntryFileAs is saveEntryFile on behalf of actor (caller must hold lock)
+func (s *ShadowFS) saveEntryFileAs(shadowPath, path string, entry *Entry, operation, actor string) error {
+	var before *Entry
+	if s.audit != nil {
+		before, _ = s.loadEntry(shadowPath)
+	}
+
+	if err := s.writeEntry(shadowPath, entry); err != nil {
+		return err
+	}
+	return s.recordWriteAs(operation, path, actor, before, entry)
+}
+
+// removeEntryFile deletes a shadow file and records the deletion (caller
+// must hold lock)
+func (s *ShadowFS) removeEntryFile(shadowPath, path, operation string) error {
+	var before *Entry
+	if s.audit != nil {
+		before, _ = s.loadEntry(shadowPath)
+	}
+
+	if err := s.store.Remove(shadowPath); err != nil {
+		return err
+	}
+	return s.recordWrite(operation, path, before, nil)
+}
+
+// AuditLog returns the audit log, or nil if auditing is disabled
+func (s *ShadowFS) AuditLog() *AuditLog {
+	return s.audit
+}
+
+// auditActor returns who is writing
+func (s *ShadowFS) auditActor() string {
+	s.actorOnce.Do(func() {
+		s.actor = s.config.AuditActor
+		if s.actor == "" {
+			s.actor = os.Getenv("GRAPHFS_ACTOR")
+		}
+		if s.actor == "" {
+			cmd := exec.Command("git", "config", "user.email")
+			cmd.Dir = s.rootPath
+			if output, err := cmd.Output(); err == nil {
+				s.actor = strings.TrimSpace(string(output))
+			}
+		}
+		if s.actor == "" {
+			if u, err := user.Current(); err == nil {
+				s.actor = u.Username
+			}
+		}
+		if s.actor == "" {
+			s.actor = "unknown"
+		}
+	})
+	return s.actor
+}
+
+// auditPath returns the source path a shadow or directory entry file
+// describes, as recorded in the audit log
+func (s *ShadowFS) auditPath(shadowFile string, entry *Entry) string {
+	if filepath.Base(shadowFile) == DirectoryEntryFile {
+		dir, err := filepath.Rel(s.shadowPath, filepath.Dir(shadowFile))
+		if err != nil {
+			dir = filepath.Dir(shadowFile)
+		}
+		return pathkey.Canonical(dir) + "/"
+	}
+	if entry != nil && entry.SourcePath != "" {
+		return entry.SourcePath
+	}
+	if source, err := s.GetSourcePath(shadowFile); err == nil {
+		if rel, err := s.getRelativePath(source); err == nil {
+			return rel
+		}
+	}
+	return pathkey.Canonical(shadowFile)
+}
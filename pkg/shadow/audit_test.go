@@ -0,0 +1,103 @@
+package shadow
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffEntries(t *testing.T) {
+	before := NewManualEntry("api.go")
+	before.SetModule("<#api.go>", "api.go", "", "go", "api", []string{"http"})
+	before.AddAnnotation("owner", "team-a", "")
+
+	after := NewManualEntry("api.go")
+	after.SetModule("<#api.go>", "api.go", "", "go", "core", []string{"http"})
+	after.AddAnnotation("owner", "team-b", "")
+
+	want := []string{
+		"-annotation owner=team-a",
+		"+annotation owner=team-b",
+		"-module.layer api",
+		"+module.layer core",
+	}
+	if got := DiffEntries(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffEntries = %v, want %v", got, want)
+	}
+
+	if got := DiffEntries(after, after); len(got) != 0 {
+		t.Errorf("Expected no changes for identical entries, got %v", got)
+	}
+	if got := DiffEntries(after, nil); len(got) == 0 || got[0][0] != '-' {
+		t.Errorf("Expected removals for a deleted entry, got %v", got)
+	}
+}
+
+func TestShadowFSAudit(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := DefaultConfig()
+	config.Audit = true
+	config.AuditActor = "alice"
+	shadowFS, err := NewShadowFS(tmpDir, config)
+	if err != nil {
+		t.Fatalf("NewShadowFS failed: %v", err)
+	}
+
+	entry := NewManualEntry("api.go")
+	entry.AddAnnotation("owner", "team-a", "")
+	if err := shadowFS.Set("api.go", entry); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	// Unchanged writes are not recorded
+	if err := shadowFS.Set("api.go", entry); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := shadowFS.Delete("api.go"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	log := NewAuditLog(filepath.Join(tmpDir, DefaultAuditLog))
+	records, err := log.Records("api.go")
+	if err != nil {
+		t.Fatalf("Records failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %+v", len(records), records)
+	}
+	if records[0].Operation != "set" || records[1].Operation != "delete" {
+		t.Errorf("Operations = %s, %s; want set, delete", records[0].Operation, records[1].Operation)
+	}
+	if records[0].Actor != "alice" {
+		t.Errorf("Actor = %q, want alice", records[0].Actor)
+	}
+
+	if other, _ := log.Records("other.go"); len(other) != 0 {
+		t.Errorf("Expected no records for other.go, got %d", len(other))
+	}
+}
+
+func TestAuditLogPrune(t *testing.T) {
+	log := NewAuditLog(filepath.Join(t.TempDir(), "audit.log"))
+	now := time.Now()
+	for i, age := range []time.Duration{72 * time.Hour, 48 * time.Hour, time.Hour} {
+		record := AuditRecord{Time: now.Add(-age), Actor: "bob", Operation: "set", Path: "api.go", Changes: []string{"+concept " + string(rune('a'+i))}}
+		if err := log.Append(record); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	removed, err := log.Prune(AuditRetention{MaxAge: 60 * time.Hour}, now)
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune by age removed %d (err %v), want 1", removed, err)
+	}
+	removed, err = log.Prune(AuditRetention{MaxRecords: 1}, now)
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune by count removed %d (err %v), want 1", removed, err)
+	}
+
+	records, _ := log.Records("")
+	if len(records) != 1 || records[0].Changes[0] != "+concept c" {
+		t.Errorf("Expected only the newest record to remain, got %+v", records)
+	}
+}
@@ -8,7 +8,9 @@ LinkedDoc metadata, enabling automatic shadow file generation.
 ## Linked Modules
 - [shadow](./shadow.go) - Shadow file system manager
 - [entry](./entry.go) - Shadow entry data structure
+- [defaults](./defaults.go) - Templates for new entries
 - [../graph/builder](../graph/builder.go) - Graph builder
+- [../pathkey](../pathkey/pathkey.go) - Canonical path keys
 
 ## Tags
 shadow, builder, generation, integration
@@ -25,7 +27,7 @@ Builder, NewBuilder, BuildOptions
     code:description "Shadow builder for generating shadow entries from source files" ;
     code:language "go" ;
     code:layer "shadow" ;
-    code:linksTo <./shadow.go>, <./entry.go>, <../graph/builder.go> ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./defaults.go>, <../graph/builder.go>, <../pathkey/pathkey.go> ;
     code:exports <#Builder>, <#NewBuilder>, <#BuildOptions> ;
     code:tags "shadow", "builder", "generation", "integration" .
 <!-- End LinkedDoc RDF -->
@@ -46,7 +48,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/pathkey"
 	"github.com/justin4957/graphfs/pkg/scanner"
 )
 
@@ -72,6 +76,10 @@ type BuildOptions struct {
 
 	// SkipUnchanged skips files that haven't changed since last build
 	SkipUnchanged bool
+
+	// BaseIRI rewrites fragment-only and relative URIs in entries into
+	// absolute IRIs under this base (empty = keep them as written)
+	BaseIRI string
 }
 
 // DefaultBuildOptions returns default build options
@@ -151,6 +159,11 @@ func (b *Builder) Build(opts BuildOptions) (*BuildResult, error) {
 
 	result.TotalFiles = len(linkedDocFiles)
 
+	defaults, err := b.shadowFS.LoadEntryDefaults()
+	if err != nil {
+		return nil, err
+	}
+
 	if opts.ReportProgress {
 		fmt.Printf("Found %d files with LinkedDoc metadata\n", result.TotalFiles)
 	}
@@ -178,7 +191,7 @@ func (b *Builder) Build(opts BuildOptions) (*BuildResult, error) {
 			workerParser := parser.NewParser()
 
 			for file := range fileChan {
-				fr := b.processFile(file, workerParser, opts)
+				fr := b.processFile(file, workerParser, defaults, opts)
 				resultChan <- fr
 			}
 		}()
@@ -274,7 +287,12 @@ func (b *Builder) BuildFile(sourcePath string, opts BuildOptions) error {
 		HasLinkedDoc: true,
 	}
 
-	fr := b.processFile(fileInfo, b.parser, opts)
+	defaults, err := b.shadowFS.LoadEntryDefaults()
+	if err != nil {
+		return err
+	}
+
+	fr := b.processFile(fileInfo, b.parser, defaults, opts)
 	if fr.err != nil {
 		return fr.err
 	}
@@ -297,8 +315,9 @@ type fileResult struct {
 	err    error
 }
 
-// processFile processes a single file and creates/updates shadow entry
-func (b *Builder) processFile(file scanner.FileInfo, p *parser.Parser, opts BuildOptions) fileResult {
+// processFile processes a single file and creates/updates shadow entry.
+// New entries start from the defaults matching the file's path.
+func (b *Builder) processFile(file scanner.FileInfo, p *parser.Parser, defaults *EntryDefaults, opts BuildOptions) fileResult {
 	result := fileResult{path: file.Path}
 
 	// Calculate file hash
@@ -313,6 +332,7 @@ func (b *Builder) processFile(file scanner.FileInfo, p *parser.Parser, opts Buil
 	if err != nil {
 		relPath = file.Path
 	}
+	relPath = pathkey.Canonical(relPath)
 
 	// Check if we should skip unchanged files
 	if opts.SkipUnchanged && !opts.ForceOverwrite {
@@ -324,7 +344,7 @@ func (b *Builder) processFile(file scanner.FileInfo, p *parser.Parser, opts Buil
 	}
 
 	// Parse LinkedDoc metadata
-	triples, err := p.Parse(file.Path)
+	triples, err := p.ParseFor(file.Path, scanner.DetectLanguageKey(file.Path))
 	if err != nil {
 		result.err = fmt.Errorf("failed to parse: %w", err)
 		return result
@@ -337,25 +357,48 @@ func (b *Builder) processFile(file scanner.FileInfo, p *parser.Parser, opts Buil
 	// Extract module information from triples
 	b.extractModuleInfo(entry, triples, relPath)
 
+	var iris *graph.IRIMapper
+	if opts.BaseIRI != "" {
+		if iris, err = graph.NewIRIMapper(opts.BaseIRI); err != nil {
+			result.err = err
+			return result
+		}
+		if entry.Module != nil {
+			entry.Module.URI = iris.Resolve(entry.Module.URI, relPath)
+		}
+	}
+
 	// Add raw triples if enabled
 	if opts.IncludeTriples {
 		for _, t := range triples {
+			subject := t.Subject
 			var objStr string
 			switch obj := t.Object.(type) {
 			case parser.LiteralObject:
 				objStr = obj.Value
 			case parser.URIObject:
 				objStr = obj.URI
+				if iris != nil {
+					objStr = iris.Resolve(objStr, relPath)
+				}
 			default:
 				continue
 			}
+			if iris != nil {
+				subject = iris.Resolve(subject, relPath)
+			}
 
-			entry.AddTriple(t.Subject, t.Predicate, objStr, SourceAuto)
+			entry.AddTriple(subject, t.Predicate, objStr, SourceAuto)
 		}
 	}
 
-	// Handle existing entries
-	existing, _ := b.shadowFS.Get(file.Path)
+	// Handle existing entries; entries that need migrating are left alone
+	// rather than replaced
+	existing, err := b.shadowFS.Get(file.Path)
+	if isVersionError(err) {
+		result.err = err
+		return result
+	}
 	if existing != nil {
 		if opts.ForceOverwrite {
 			if err := b.shadowFS.Set(file.Path, entry); err != nil {
@@ -373,6 +416,7 @@ func (b *Builder) processFile(file scanner.FileInfo, p *parser.Parser, opts Buil
 			result.status = statusSkipped
 		}
 	} else {
+		defaults.Apply(entry)
 		if err := b.shadowFS.Set(file.Path, entry); err != nil {
 			result.err = err
 			return result
@@ -383,37 +427,49 @@ func (b *Builder) processFile(file scanner.FileInfo, p *parser.Parser, opts Buil
 	return result
 }
 
-// extractModuleInfo extracts module information from parsed triples
+// extractModuleInfo extracts module information from parsed triples. The
+// first code:Module is the file's module; further module or component nodes
+// from additional LinkedDoc blocks are recorded as components.
 func (b *Builder) extractModuleInfo(entry *Entry, triples []parser.Triple, modulePath string) {
 	var moduleURI string
-	var name, description, language, layer string
-	var tags []string
-	var dependencies []string
-	var exports []string
-	var calls []string
+	var componentURIs []string
+	nodes := make(map[string]*Module)
 
-	// First pass: find module URI
+	// First pass: find module and component nodes
 	for _, t := range triples {
-		var objStr string
-		switch obj := t.Object.(type) {
-		case parser.LiteralObject:
-			objStr = obj.Value
-		case parser.URIObject:
-			objStr = obj.URI
-		default:
+		obj, ok := t.Object.(parser.URIObject)
+		if !ok || !strings.Contains(t.Predicate, "rdf-syntax-ns#type") || nodes[t.Subject] != nil {
 			continue
 		}
 
-		if strings.Contains(t.Predicate, "rdf-syntax-ns#type") && strings.Contains(objStr, "Module") {
+		isModule := strings.Contains(obj.URI, "Module")
+		if !isModule && !strings.HasSuffix(obj.URI, "Component") {
+			continue
+		}
+		nodes[t.Subject] = &Module{URI: t.Subject}
+		if isModule && moduleURI == "" {
 			moduleURI = t.Subject
-			break
+		} else {
+			componentURIs = append(componentURIs, t.Subject)
 		}
 	}
 
-	// Second pass: extract properties for the module
+	// Without any typed node, every triple describes the file
+	if len(nodes) == 0 {
+		nodes[""] = &Module{}
+	}
+
+	var dependencies []string
+	var exports []string
+	var calls []string
+
+	// Second pass: extract properties for each node
 	for _, t := range triples {
-		if t.Subject != moduleURI && moduleURI != "" {
-			continue
+		node := nodes[t.Subject]
+		if node == nil {
+			if node = nodes[""]; node == nil {
+				continue
+			}
 		}
 
 		var objStr string
@@ -428,15 +484,15 @@ func (b *Builder) extractModuleInfo(entry *Entry, triples []parser.Triple, modul
 
 		switch {
 		case strings.HasSuffix(t.Predicate, "name"):
-			name = objStr
+			node.Name = objStr
 		case strings.HasSuffix(t.Predicate, "description"):
-			description = objStr
+			node.Description = objStr
 		case strings.HasSuffix(t.Predicate, "language"):
-			language = objStr
+			node.Language = objStr
 		case strings.HasSuffix(t.Predicate, "layer"):
-			layer = objStr
+			node.Layer = objStr
 		case strings.HasSuffix(t.Predicate, "tags"):
-			tags = append(tags, objStr)
+			node.Tags = append(node.Tags, objStr)
 		case strings.HasSuffix(t.Predicate, "linksTo"):
 			dep := resolveDependencyPath(objStr, modulePath)
 			dependencies = append(dependencies, dep)
@@ -448,8 +504,15 @@ func (b *Builder) extractModuleInfo(entry *Entry, triples []parser.Triple, modul
 	}
 
 	// Set module info
-	if moduleURI != "" || name != "" {
-		entry.SetModule(moduleURI, name, description, language, layer, tags)
+	module := nodes[moduleURI]
+	if module == nil && len(componentURIs) > 0 {
+		module = &Module{URI: "<#" + filepath.ToSlash(modulePath) + ">", Name: filepath.ToSlash(modulePath)}
+	}
+	if module != nil && (module.URI != "" || module.Name != "") {
+		entry.SetModule(module.URI, module.Name, module.Description, module.Language, module.Layer, module.Tags)
+	}
+	for _, uri := range componentURIs {
+		entry.Components = append(entry.Components, *nodes[uri])
 	}
 
 	// Add relationships
@@ -597,6 +660,6 @@ func resolveDependencyPath(depPath, modulePath string) string {
 	// Resolve the relative path
 	resolvedPath := filepath.Join(moduleDir, depPath)
 
-	// Clean the path to normalize it
-	return filepath.Clean(resolvedPath)
+	// Clean the path and use forward slashes, as for module paths
+	return pathkey.Canonical(resolvedPath)
 }
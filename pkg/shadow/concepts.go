@@ -0,0 +1,221 @@
+/*
+# Module: pkg/shadow/concepts.go
+Hierarchical concept taxonomy for shadow entries.
+
+Loads .graphfs/concepts.yaml, which gives each concept an optional parent,
+aliases and a description. Searching for a concept expands it to its
+descendants and aliases, so "security" also matches entries about
+"authentication" and "crypto". Concepts are loaded into the graph as SKOS
+triples (skos:Concept, skos:prefLabel, skos:altLabel, skos:broader,
+skos:definition) with modules linked by code:concept, so SPARQL can follow
+skos:broader*.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [index](./index.go) - Concept search
+- [effective](./effective.go) - Applying concepts to the graph
+
+## Tags
+shadow, concepts, taxonomy, skos
+
+## Exports
+ConceptsFile, ConceptPredicate, Concept, ConceptTaxonomy, ParseConceptTaxonomy, ConceptIRI
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#concepts.go> a code:Module ;
+    code:name "pkg/shadow/concepts.go" ;
+    code:description "Hierarchical concept taxonomy for shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./index.go>, <./effective.go> ;
+    code:exports <#ConceptsFile>, <#ConceptPredicate>, <#Concept>, <#ConceptTaxonomy>, <#ParseConceptTaxonomy>, <#ConceptIRI> ;
+    code:tags "shadow", "concepts", "taxonomy", "skos" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// ConceptsFile is the file name of the concept taxonomy, next to the shadow
+// directory
+const ConceptsFile = "concepts.yaml"
+
+// ConceptPredicate links a module to the concepts of its effective metadata
+const ConceptPredicate = codePrefix + "concept"
+
+// SKOS terms used for the taxonomy
+const (
+	skosPrefix     = "http://www.w3.org/2004/02/skos/core#"
+	skosConcept    = skosPrefix + "Concept"
+	skosPrefLabel  = skosPrefix + "prefLabel"
+	skosAltLabel   = skosPrefix + "altLabel"
+	skosBroader    = skosPrefix + "broader"
+	skosDefinition = skosPrefix + "definition"
+	rdfType        = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+)
+
+// Concept is one entry of the taxonomy
+type Concept struct {
+	Parent      string   `yaml:"parent,omitempty" json:"parent,omitempty"`
+	Aliases     []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// ConceptTaxonomy is the project's concept hierarchy, keyed by concept name
+type ConceptTaxonomy struct {
+	Concepts map[string]Concept `yaml:"concepts" json:"concepts"`
+
+	aliases  map[string]string   // alias -> concept
+	children map[string][]string // concept -> direct children, sorted
+}
+
+// ParseConceptTaxonomy parses and validates a concept taxonomy. Parents must
+// be declared concepts, the hierarchy must not loop, and an alias may not
+// name another concept or alias.
+func ParseConceptTaxonomy(data []byte) (*ConceptTaxonomy, error) {
+	t := &ConceptTaxonomy{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("failed to parse concept taxonomy: %w", err)
+	}
+	if t.Concepts == nil {
+		t.Concepts = make(map[string]Concept)
+	}
+
+	t.aliases = make(map[string]string)
+	t.children = make(map[string][]string)
+	for name, concept := range t.Concepts {
+		if concept.Parent != "" {
+			if _, ok := t.Concepts[concept.Parent]; !ok {
+				return nil, fmt.Errorf("concept %q: unknown parent %q", name, concept.Parent)
+			}
+			t.children[concept.Parent] = append(t.children[concept.Parent], name)
+		}
+		for _, alias := range concept.Aliases {
+			if _, ok := t.Concepts[alias]; ok {
+				return nil, fmt.Errorf("concept %q: alias %q is itself a concept", name, alias)
+			}
+			if other, ok := t.aliases[alias]; ok && other != name {
+				return nil, fmt.Errorf("alias %q is used by both %q and %q", alias, other, name)
+			}
+			t.aliases[alias] = name
+		}
+	}
+	for _, children := range t.children {
+		sort.Strings(children)
+	}
+
+	for name := range t.Concepts {
+		seen := map[string]bool{name: true}
+		for parent := t.Concepts[name].Parent; parent != ""; parent = t.Concepts[parent].Parent {
+			if seen[parent] {
+				return nil, fmt.Errorf("concept %q: parent cycle through %q", name, parent)
+			}
+			seen[parent] = true
+		}
+	}
+
+	return t, nil
+}
+
+// LoadConceptTaxonomy loads the concept taxonomy, returning an empty one if
+// none exists
+func (s *ShadowFS) LoadConceptTaxonomy() (*ConceptTaxonomy, error) {
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(s.shadowPath), ConceptsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ParseConceptTaxonomy(nil)
+		}
+		return nil, fmt.Errorf("failed to read concept taxonomy: %w", err)
+	}
+	return ParseConceptTaxonomy(data)
+}
+
+// Canonical returns the concept an alias stands for, or name itself
+func (t *ConceptTaxonomy) Canonical(name string) string {
+	if t == nil {
+		return name
+	}
+	if concept, ok := t.aliases[name]; ok {
+		return concept
+	}
+	return name
+}
+
+// Expand returns the names matching a concept: the concept, its
+// descendants and all their aliases, sorted
+func (t *ConceptTaxonomy) Expand(name string) []string {
+	if t == nil {
+		return []string{name}
+	}
+
+	var names []string
+	queue := []string{t.Canonical(name)}
+	for len(queue) > 0 {
+		concept := queue[0]
+		queue = queue[1:]
+		names = append(names, concept)
+		names = append(names, t.Concepts[concept].Aliases...)
+		queue = append(queue, t.children[concept]...)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConceptIRI returns the IRI of a concept node in the graph
+func ConceptIRI(name string) string {
+	return "<#concept/" + url.PathEscape(name) + ">"
+}
+
+// addTriples records the taxonomy as SKOS triples
+func (t *ConceptTaxonomy) addTriples(ts *store.TripleStore) error {
+	names := make([]string, 0, len(t.Concepts))
+	for name := range t.Concepts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		concept := t.Concepts[name]
+		iri := ConceptIRI(name)
+		if err := addConceptNode(ts, iri, name); err != nil {
+			return err
+		}
+		for _, alias := range concept.Aliases {
+			if err := ts.Add(iri, skosAltLabel, alias); err != nil {
+				return err
+			}
+		}
+		if concept.Parent != "" {
+			if err := ts.Add(iri, skosBroader, ConceptIRI(concept.Parent)); err != nil {
+				return err
+			}
+		}
+		if concept.Description != "" {
+			if err := ts.Add(iri, skosDefinition, concept.Description); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addConceptNode records a concept's type and label
+func addConceptNode(ts *store.TripleStore, iri, name string) error {
+	if err := ts.Add(iri, rdfType, skosConcept); err != nil {
+		return err
+	}
+	return ts.Add(iri, skosPrefLabel, name)
+}
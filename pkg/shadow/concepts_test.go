@@ -0,0 +1,148 @@
+/*
+# Module: pkg/shadow/concepts_test.go
+Tests for the hierarchical concept taxonomy.
+
+## Tags
+shadow, test, concepts
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#concepts_test.go> a code:Module ;
+    code:name "pkg/shadow/concepts_test.go" ;
+    code:description "Tests for the hierarchical concept taxonomy" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./concepts.go> ;
+    code:tags "shadow", "test", "concepts" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/query"
+)
+
+const testConcepts = `concepts:
+  security:
+    description: Protecting data and access
+  authentication:
+    parent: security
+    aliases: [authn, login]
+  crypto:
+    parent: security
+    aliases: [cryptography]
+  hashing:
+    parent: crypto
+  billing: {}
+`
+
+func TestParseConceptTaxonomy(t *testing.T) {
+	taxonomy, err := ParseConceptTaxonomy([]byte(testConcepts))
+	if err != nil {
+		t.Fatalf("ParseConceptTaxonomy() error = %v", err)
+	}
+
+	if got := taxonomy.Canonical("authn"); got != "authentication" {
+		t.Errorf("Canonical(authn) = %q", got)
+	}
+	want := []string{"authentication", "authn", "crypto", "cryptography", "hashing", "login", "security"}
+	if got := taxonomy.Expand("security"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expand(security) = %v, want %v", got, want)
+	}
+	if got := taxonomy.Expand("cryptography"); !reflect.DeepEqual(got, []string{"crypto", "cryptography", "hashing"}) {
+		t.Errorf("Expand(cryptography) = %v", got)
+	}
+	if got := taxonomy.Expand("undeclared"); !reflect.DeepEqual(got, []string{"undeclared"}) {
+		t.Errorf("Expand(undeclared) = %v", got)
+	}
+
+	invalid := map[string]string{
+		"unknown parent":   "concepts:\n  a: {parent: b}\n",
+		"cycle":            "concepts:\n  a: {parent: b}\n  b: {parent: a}\n",
+		"alias is concept": "concepts:\n  a: {aliases: [b]}\n  b: {}\n",
+		"shared alias":     "concepts:\n  a: {aliases: [x]}\n  b: {aliases: [x]}\n",
+	}
+	for name, data := range invalid {
+		if _, err := ParseConceptTaxonomy([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestConceptTaxonomySearchAndGraph(t *testing.T) {
+	shadowFS, resolver := newTestResolver(t)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(shadowFS.shadowPath), ConceptsFile), []byte(testConcepts), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, concepts := range map[string][]string{
+		"services/auth.go":  {"login"},
+		"utils/hash.go":     {"hashing"},
+		"billing/charge.go": {"billing"},
+	} {
+		entry := NewManualEntry(path)
+		for _, concept := range concepts {
+			entry.AddConcept(concept)
+		}
+		if err := shadowFS.Set(path, entry); err != nil {
+			t.Fatalf("Failed to set %s: %v", path, err)
+		}
+	}
+
+	taxonomy, err := shadowFS.LoadConceptTaxonomy()
+	if err != nil {
+		t.Fatalf("LoadConceptTaxonomy() error = %v", err)
+	}
+	paths := shadowFS.Index().Search(SearchQuery{Concepts: []string{"security"}, Taxonomy: taxonomy})
+	sort.Strings(paths)
+	if !reflect.DeepEqual(paths, []string{"services/auth.go", "utils/hash.go"}) {
+		t.Errorf("Search(security) = %v", paths)
+	}
+	if paths := shadowFS.Index().Search(SearchQuery{Concepts: []string{"security"}}); len(paths) != 0 {
+		t.Errorf("Search(security) without a taxonomy = %v", paths)
+	}
+
+	g := graph.NewGraph("/project", store.NewTripleStore())
+	for _, path := range []string{"services/auth.go", "utils/hash.go", "billing/charge.go"} {
+		g.AddModule(graph.NewModule(path, "<#"+path+">"))
+	}
+	if _, err := resolver.ApplyToGraph(g); err != nil {
+		t.Fatalf("ApplyToGraph() error = %v", err)
+	}
+
+	// Aliases link to their concept
+	if len(g.Store.Find("<#services/auth.go>", ConceptPredicate, ConceptIRI("authentication"))) != 1 {
+		t.Error("Expected auth.go to be linked to authentication")
+	}
+
+	result, err := query.NewExecutor(g.Store).ExecuteString(`PREFIX code: <https://schema.codedoc.org/>
+PREFIX skos: <http://www.w3.org/2004/02/skos/core#>
+SELECT ?module WHERE {
+    ?module code:concept ?concept .
+    ?concept skos:broader* ?root .
+    ?root skos:prefLabel "security" .
+}`)
+	if err != nil {
+		t.Fatalf("SPARQL error = %v", err)
+	}
+	var modules []string
+	for _, binding := range result.Bindings {
+		modules = append(modules, strings.Trim(binding["module"], "<#>"))
+	}
+	sort.Strings(modules)
+	if !reflect.DeepEqual(modules, []string{"services/auth.go", "utils/hash.go"}) {
+		t.Errorf("SPARQL modules under security = %v", modules)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+# Module: pkg/shadow/defaults.go
+Per-directory templates for new shadow entries.
+
+.graphfs/shadow-defaults.yaml maps path patterns to a default layer, tags
+and owner. When the builder creates an auto entry for a file with no shadow
+entry yet, every matching template is applied from least to most specific:
+the layer fills in only if the file's header declares none, tags are added,
+and the owner is recorded as an owner annotation.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [builder](./builder.go) - Shadow builder
+
+## Tags
+shadow, defaults, templates, ownership
+
+## Exports
+EntryDefaultsFile, EntryDefaults, EntryTemplate, LoadEntryDefaults
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#defaults.go> a code:Module ;
+    code:name "pkg/shadow/defaults.go" ;
+    code:description "Per-directory templates for new shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./builder.go> ;
+    code:exports <#EntryDefaultsFile>, <#EntryDefaults>, <#EntryTemplate>, <#LoadEntryDefaults> ;
+    code:tags "shadow", "defaults", "templates", "ownership" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EntryDefaultsFile is the file name of the entry templates, next to the
+// shadow directory
+const EntryDefaultsFile = "shadow-defaults.yaml"
+
+// defaultsAuthor is the author of annotations added from templates
+const defaultsAuthor = "shadow-defaults"
+
+// EntryDefaults is the contents of a shadow defaults file
+type EntryDefaults struct {
+	Defaults []EntryTemplate `yaml:"defaults" json:"defaults"`
+}
+
+// EntryTemplate gives the initial metadata of new entries for files under a
+// path. Path is a directory ("pkg/api", matching every file below it), a
+// directory followed by "/**" or "/...", or a glob ("cmd/*/main.go").
+type EntryTemplate struct {
+	Path  string   `yaml:"path" json:"path"`
+	Layer string   `yaml:"layer,omitempty" json:"layer,omitempty"`
+	Tags  []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Owner string   `yaml:"owner,omitempty" json:"owner,omitempty"`
+}
+
+// LoadEntryDefaults reads and validates a shadow defaults file; a missing
+// file has no templates
+func LoadEntryDefaults(filePath string) (*EntryDefaults, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return &EntryDefaults{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shadow defaults: %w", err)
+	}
+
+	var defaults EntryDefaults
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse shadow defaults: %w", err)
+	}
+	for i, t := range defaults.Defaults {
+		if strings.TrimSpace(t.Path) == "" {
+			return nil, fmt.Errorf("shadow default %d: missing path", i)
+		}
+		if _, err := path.Match(t.Path, ""); err != nil {
+			return nil, fmt.Errorf("shadow default %s: invalid pattern: %w", t.Path, err)
+		}
+	}
+	return &defaults, nil
+}
+
+// EntryDefaultsPath returns the path of the shadow defaults file
+func (s *ShadowFS) EntryDefaultsPath() string {
+	return filepath.Join(filepath.Dir(s.shadowPath), EntryDefaultsFile)
+}
+
+// LoadEntryDefaults loads the workspace's shadow defaults file
+func (s *ShadowFS) LoadEntryDefaults() (*EntryDefaults, error) {
+	return LoadEntryDefaults(s.EntryDefaultsPath())
+}
+
+// Match returns the templates matching a canonical relative path, least
+// specific first
+func (d *EntryDefaults) Match(relPath string) []EntryTemplate {
+	if d == nil {
+		return nil
+	}
+
+	type match struct {
+		template    EntryTemplate
+		specificity int
+		order       int
+	}
+	var matches []match
+	for i, t := range d.Defaults {
+		if specificity := matchTemplatePath(t.Path, relPath); specificity >= 0 {
+			matches = append(matches, match{t, specificity, i})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].specificity != matches[j].specificity {
+			return matches[i].specificity < matches[j].specificity
+		}
+		return matches[i].order < matches[j].order
+	})
+
+	templates := make([]EntryTemplate, len(matches))
+	for i, m := range matches {
+		templates[i] = m.template
+	}
+	return templates
+}
+
+// Resolve merges the templates matching a path: the most specific layer and
+// owner, and the tags of every template
+func (d *EntryDefaults) Resolve(relPath string) EntryTemplate {
+	resolved := EntryTemplate{Path: relPath}
+	for _, t := range d.Match(relPath) {
+		if t.Layer != "" {
+			resolved.Layer = t.Layer
+		}
+		if t.Owner != "" {
+			resolved.Owner = t.Owner
+		}
+		for _, tag := range t.Tags {
+			if !slices.Contains(resolved.Tags, tag) {
+				resolved.Tags = append(resolved.Tags, tag)
+			}
+		}
+	}
+	return resolved
+}
+
+// Apply fills an entry's unset layer and owner and adds tags from the
+// templates matching its source path. It reports whether anything changed.
+func (d *EntryDefaults) Apply(entry *Entry) bool {
+	resolved := d.Resolve(entry.SourcePath)
+	if resolved.Layer == "" && resolved.Owner == "" && len(resolved.Tags) == 0 {
+		return false
+	}
+
+	if entry.Module == nil {
+		entry.Module = &Module{Name: entry.SourcePath}
+	}
+	changed := false
+	if entry.Module.Layer == "" && resolved.Layer != "" {
+		entry.Module.Layer = resolved.Layer
+		changed = true
+	}
+	for _, tag := range resolved.Tags {
+		if !slices.Contains(entry.Module.Tags, tag) {
+			entry.Module.Tags = append(entry.Module.Tags, tag)
+			changed = true
+		}
+	}
+	if resolved.Owner != "" && !entry.hasOwner() {
+		entry.AddAnnotation(string(PushOwner), resolved.Owner, defaultsAuthor)
+		changed = true
+	}
+	return changed
+}
+
+// hasOwner reports whether the entry's header or annotations name an owner
+func (e *Entry) hasOwner() bool {
+	if _, ok := e.GetAnnotation(string(PushOwner)); ok {
+		return true
+	}
+	for _, t := range e.Triples {
+		if strings.HasSuffix(t.Predicate, "/owner") || strings.HasSuffix(t.Predicate, "/owners") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTemplatePath returns the specificity of a pattern matching a path
+// (the length of its literal part), or -1 when it does not match
+func matchTemplatePath(pattern, relPath string) int {
+	pattern = strings.Trim(path.Clean(filepath.ToSlash(pattern)), "/")
+
+	switch {
+	case pattern == "." || pattern == "**" || pattern == "...":
+		return 0
+	case strings.HasSuffix(pattern, "/**") || strings.HasSuffix(pattern, "/..."):
+		dir := pattern[:strings.LastIndex(pattern, "/")]
+		if strings.HasPrefix(relPath, dir+"/") {
+			return len(dir)
+		}
+	case strings.ContainsAny(pattern, "*?["):
+		if matched, _ := path.Match(pattern, relPath); matched {
+			return strings.IndexAny(pattern, "*?[")
+		}
+	case relPath == pattern || strings.HasPrefix(relPath, pattern+"/"):
+		return len(pattern)
+	}
+	return -1
+}
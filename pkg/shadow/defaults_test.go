@@ -0,0 +1,130 @@
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const defaultsTestFile = `defaults:
+  - path: pkg
+    tags: [library]
+    owner: "@platform"
+  - path: pkg/api/**
+    layer: api
+    tags: [http]
+    owner: "@api-team"
+  - path: cmd/*/main.go
+    layer: cli
+`
+
+func TestEntryDefaults_Resolve(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, EntryDefaultsFile)
+	if err := os.WriteFile(file, []byte(defaultsTestFile), 0644); err != nil {
+		t.Fatalf("Failed to write defaults: %v", err)
+	}
+	defaults, err := LoadEntryDefaults(file)
+	if err != nil {
+		t.Fatalf("LoadEntryDefaults failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want EntryTemplate
+	}{
+		{"pkg/api/handler.go", EntryTemplate{Path: "pkg/api/handler.go", Layer: "api", Tags: []string{"library", "http"}, Owner: "@api-team"}},
+		{"pkg/store/store.go", EntryTemplate{Path: "pkg/store/store.go", Tags: []string{"library"}, Owner: "@platform"}},
+		{"cmd/tool/main.go", EntryTemplate{Path: "cmd/tool/main.go", Layer: "cli"}},
+		{"pkgs/other.go", EntryTemplate{Path: "pkgs/other.go"}},
+	}
+	for _, tt := range tests {
+		if got := defaults.Resolve(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Resolve(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadEntryDefaults_Missing(t *testing.T) {
+	defaults, err := LoadEntryDefaults(filepath.Join(t.TempDir(), EntryDefaultsFile))
+	if err != nil {
+		t.Fatalf("Missing file should not fail: %v", err)
+	}
+	if len(defaults.Defaults) != 0 {
+		t.Errorf("Expected no templates, got %d", len(defaults.Defaults))
+	}
+
+	file := filepath.Join(t.TempDir(), EntryDefaultsFile)
+	if err := os.WriteFile(file, []byte("defaults:\n  - layer: api\n"), 0644); err != nil {
+		t.Fatalf("Failed to write defaults: %v", err)
+	}
+	if _, err := LoadEntryDefaults(file); err == nil {
+		t.Error("Expected an error for a template without a path")
+	}
+}
+
+func TestBuilder_AppliesEntryDefaults(t *testing.T) {
+	root := t.TempDir()
+	source := `/*
+# Module: pkg/api/handler.go
+Handlers.
+
+<!-- LinkedDoc RDF -->
+<#handler.go> a code:Module ;
+    code:name "pkg/api/handler.go" ;
+    code:tags "handlers" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package api
+`
+	if err := os.MkdirAll(filepath.Join(root, "pkg", "api"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "pkg", "api", "handler.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+
+	shadowFS, err := NewShadowFS(root, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize shadow file system: %v", err)
+	}
+	if err := os.WriteFile(shadowFS.EntryDefaultsPath(), []byte(defaultsTestFile), 0644); err != nil {
+		t.Fatalf("Failed to write defaults: %v", err)
+	}
+
+	if err := NewBuilder(shadowFS).BuildFile("pkg/api/handler.go", BuildOptions{}); err != nil {
+		t.Fatalf("BuildFile failed: %v", err)
+	}
+
+	entry, err := shadowFS.Get("pkg/api/handler.go")
+	if err != nil || entry == nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.Module.Layer != "api" {
+		t.Errorf("Layer = %q, want api", entry.Module.Layer)
+	}
+	if want := []string{"handlers", "library", "http"}; !reflect.DeepEqual(entry.Module.Tags, want) {
+		t.Errorf("Tags = %v, want %v", entry.Module.Tags, want)
+	}
+	if owner, ok := entry.GetAnnotation("owner"); !ok || owner != "@api-team" {
+		t.Errorf("Owner annotation = %v, want @api-team", owner)
+	}
+
+	// Existing entries keep their metadata
+	entry.Module.Layer = "service"
+	if err := shadowFS.Set("pkg/api/handler.go", entry); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+	if err := NewBuilder(shadowFS).BuildFile("pkg/api/handler.go", BuildOptions{}); err != nil {
+		t.Fatalf("BuildFile failed: %v", err)
+	}
+	entry, _ = shadowFS.Get("pkg/api/handler.go")
+	if entry.Module.Layer != "service" {
+		t.Errorf("Rebuild should not reapply defaults, got layer %q", entry.Module.Layer)
+	}
+}
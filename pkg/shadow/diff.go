@@ -0,0 +1,534 @@
+/*
+# Module: pkg/shadow/diff.go
+Differences between two states of the shadow file system.
+
+Compares shadow entries module by module and reports the modules added and
+removed and, for the rest, the layer, dependencies, tags and annotations
+they gained or lost. States are the working tree, the shadow directory as
+committed in git, a graph snapshot such as those under .graphfs/snapshots,
+or a copied shadow directory, so pull requests can be reviewed for
+architectural drift.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [store](./store.go) - Storage layouts
+- [../graph](../graph/snapshot.go) - Graph snapshots
+- [../../internal/archive](../../internal/archive/tar.go) - Tar extraction
+
+## Tags
+shadow, diff, review, snapshot
+
+## Exports
+ShadowDiff, EntryChange, ChangeKind, AnnotationChange, DiffStates, SnapshotEntries, ShadowFS.RecordAnnotations, LoadSnapshot, DiffFormat, FormatDiff
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#diff.go> a code:Module ;
+    code:name "pkg/shadow/diff.go" ;
+    code:description "Differences between two states of the shadow file system" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./store.go>, <../graph/snapshot.go>, <../../internal/archive/tar.go> ;
+    code:exports <#ShadowDiff>, <#EntryChange>, <#ChangeKind>, <#AnnotationChange>, <#DiffStates>, <#SnapshotEntries>, <#ShadowFS.RecordAnnotations>, <#LoadSnapshot>, <#DiffFormat>, <#FormatDiff> ;
+    code:tags "shadow", "diff", "review", "snapshot" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/internal/archive"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// ChangeKind is how a module changed between two states
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// ShadowDiff is the difference between two states of the shadow entries
+type ShadowDiff struct {
+	From      string        `json:"from"` // Label of the older state
+	To        string        `json:"to"`   // Label of the newer state
+	Changes   []EntryChange `json:"changes"`
+	Unchanged int           `json:"unchanged"`
+}
+
+// EntryChange is how one module changed. Added and removed modules carry
+// only their layer.
+type EntryChange struct {
+	Path                string             `json:"path"`
+	Kind                ChangeKind         `json:"kind"`
+	OldLayer            string             `json:"old_layer,omitempty"`
+	NewLayer            string             `json:"new_layer,omitempty"`
+	DependenciesAdded   []string           `json:"dependencies_added,omitempty"`
+	DependenciesRemoved []string           `json:"dependencies_removed,omitempty"`
+	TagsAdded           []string           `json:"tags_added,omitempty"`
+	TagsRemoved         []string           `json:"tags_removed,omitempty"`
+	Annotations         []AnnotationChange `json:"annotations,omitempty"`
+}
+
+// AnnotationChange is an annotation added, removed or given a new value;
+// Old is empty when it was added and New when it was removed
+type AnnotationChange struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// Count returns the number of changes of a kind
+func (d *ShadowDiff) Count(kind ChangeKind) int {
+	count := 0
+	for _, change := range d.Changes {
+		if change.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// DiffStates compares two states of the shadow entries module by module,
+// changes sorted by path
+func DiffStates(oldEntries, newEntries []*Entry) *ShadowDiff {
+	diff := &ShadowDiff{Changes: []EntryChange{}}
+
+	oldByPath := make(map[string]*Entry, len(oldEntries))
+	for _, entry := range oldEntries {
+		oldByPath[entry.SourcePath] = entry
+	}
+	newByPath := make(map[string]*Entry, len(newEntries))
+	for _, entry := range newEntries {
+		newByPath[entry.SourcePath] = entry
+	}
+
+	for path, newEntry := range newByPath {
+		oldEntry, ok := oldByPath[path]
+		if !ok {
+			diff.Changes = append(diff.Changes, EntryChange{Path: path, Kind: ChangeAdded, NewLayer: entryLayer(newEntry)})
+			continue
+		}
+		if change, changed := diffEntry(oldEntry, newEntry); changed {
+			diff.Changes = append(diff.Changes, change)
+		} else {
+			diff.Unchanged++
+		}
+	}
+	for path, oldEntry := range oldByPath {
+		if _, ok := newByPath[path]; !ok {
+			diff.Changes = append(diff.Changes, EntryChange{Path: path, Kind: ChangeRemoved, OldLayer: entryLayer(oldEntry)})
+		}
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Path < diff.Changes[j].Path
+	})
+	return diff
+}
+
+// diffEntry compares two states of a module and reports whether they differ
+func diffEntry(oldEntry, newEntry *Entry) (EntryChange, bool) {
+	change := EntryChange{Path: newEntry.SourcePath, Kind: ChangeModified}
+
+	if oldLayer, newLayer := entryLayer(oldEntry), entryLayer(newEntry); oldLayer != newLayer {
+		change.OldLayer = oldLayer
+		change.NewLayer = newLayer
+	}
+
+	change.DependenciesAdded, change.DependenciesRemoved = diffStrings(dependencyLabels(oldEntry), dependencyLabels(newEntry))
+	change.TagsAdded, change.TagsRemoved = diffStrings(entryTags(oldEntry), entryTags(newEntry))
+
+	oldValues := annotationValues(oldEntry)
+	newValues := annotationValues(newEntry)
+	for key, value := range newValues {
+		if old, ok := oldValues[key]; !ok || old != value {
+			change.Annotations = append(change.Annotations, AnnotationChange{Key: key, Old: old, New: value})
+		}
+	}
+	for key, value := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			change.Annotations = append(change.Annotations, AnnotationChange{Key: key, Old: value})
+		}
+	}
+	sort.Slice(change.Annotations, func(i, j int) bool {
+		return change.Annotations[i].Key < change.Annotations[j].Key
+	})
+
+	changed := change.OldLayer != change.NewLayer ||
+		len(change.DependenciesAdded) > 0 || len(change.DependenciesRemoved) > 0 ||
+		len(change.TagsAdded) > 0 || len(change.TagsRemoved) > 0 ||
+		len(change.Annotations) > 0
+	return change, changed
+}
+
+// entryLayer returns an entry's architectural layer
+func entryLayer(entry *Entry) string {
+	if entry.Module == nil {
+		return ""
+	}
+	return entry.Module.Layer
+}
+
+// dependencyLabels returns an entry's dependencies, naming the relationship
+// type unless it is the default linksTo
+func dependencyLabels(entry *Entry) []string {
+	labels := make([]string, 0, len(entry.Dependencies))
+	for _, dep := range entry.Dependencies {
+		if dep.Type == "" || dep.Type == "linksTo" {
+			labels = append(labels, dep.Target)
+		} else {
+			labels = append(labels, dep.Target+" ("+dep.Type+")")
+		}
+	}
+	return labels
+}
+
+// annotationValues returns an entry's annotation values by key
+func annotationValues(entry *Entry) map[string]string {
+	values := make(map[string]string, len(entry.Annotations))
+	for _, a := range entry.Annotations {
+		values[a.Key] = fmt.Sprint(a.Value)
+	}
+	return values
+}
+
+// diffStrings returns the sorted items only in b (added) and only in a
+// (removed)
+func diffStrings(a, b []string) (added, removed []string) {
+	inA := make(map[string]bool, len(a))
+	for _, item := range a {
+		inA[item] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		inB[item] = true
+		if !inA[item] {
+			added = append(added, item)
+		}
+	}
+	for item := range inA {
+		if !inB[item] {
+			removed = append(removed, item)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// SnapshotEntries returns the shadow entries of the modules a graph
+// snapshot recorded: their layer, tags, declared linksTo dependencies and
+// the annotations saved with the snapshot, sorted by path
+func SnapshotEntries(snapshot *graph.Snapshot) []*Entry {
+	entries := make([]*Entry, 0, len(snapshot.Modules))
+	for path, recorded := range snapshot.Modules {
+		module := recorded.Module
+		if module == nil {
+			continue
+		}
+		entry := NewEntry(path, SourceAuto)
+		entry.SetModule(module.URI, module.Name, module.Description, module.Language, module.Layer, module.Tags)
+		for _, edge := range module.DependencyEdges() {
+			if edge.Relation == graph.RelationLinksTo && !edge.Inferred {
+				entry.AddDependency(graph.RelationLinksTo, edge.Target, SourceAuto)
+			}
+		}
+		keys := make([]string, 0, len(snapshot.Annotations[path]))
+		for key := range snapshot.Annotations[path] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			entry.Annotations = append(entry.Annotations, Annotation{Key: key, Value: snapshot.Annotations[path][key]})
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].SourcePath < entries[j].SourcePath
+	})
+	return entries
+}
+
+// RecordAnnotations saves the annotations of the shadow entries in a graph
+// snapshot, so diffs against it cover annotations too. A project without a
+// shadow directory has none.
+func (s *ShadowFS) RecordAnnotations(snapshot *graph.Snapshot) error {
+	if _, err := os.Stat(s.shadowPath); os.IsNotExist(err) {
+		return nil
+	}
+	entries, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if len(entry.Annotations) == 0 {
+			continue
+		}
+		if snapshot.Annotations == nil {
+			snapshot.Annotations = make(map[string]map[string]string)
+		}
+		snapshot.Annotations[entry.SourcePath] = annotationValues(entry)
+	}
+	return nil
+}
+
+// LoadSnapshot reads the entries of a graph snapshot file (see
+// SnapshotEntries) or of a shadow directory in either layout, such as a copy
+// of .graphfs/shadow
+func LoadSnapshot(path string) ([]*Entry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if info.IsDir() {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve snapshot: %w", err)
+		}
+		config := DefaultConfig()
+		config.ShadowDir = absPath
+		config.Audit = false
+		shadowFS, err := NewShadowFS(filepath.Dir(absPath), config)
+		if err != nil {
+			return nil, err
+		}
+		return shadowFS.List()
+	}
+
+	snapshot, err := graph.LoadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	return SnapshotEntries(snapshot), nil
+}
+
+// EntriesAt returns the shadow entries as committed at a git revision, in
+// whichever layout they were committed in
+func (s *ShadowFS) EntriesAt(rev string) ([]*Entry, error) {
+	// The shadow directory's path from the top of the repository
+	prefixCmd := exec.Command("git", "rev-parse", "--show-toplevel", "--show-prefix")
+	prefixCmd.Dir = s.rootPath
+	output, err := prefixCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse failed (is %s a git repository?): %w", s.rootPath, err)
+	}
+	lines := strings.SplitN(string(output), "\n", 3)
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("unexpected git rev-parse output: %q", output)
+	}
+	topLevel, prefix := lines[0], strings.TrimSpace(lines[1])
+	shadowRel, err := filepath.Rel(s.rootPath, s.shadowPath)
+	if err != nil || strings.HasPrefix(shadowRel, "..") {
+		return nil, fmt.Errorf("shadow directory %s is outside the project", s.shadowPath)
+	}
+	treePath := prefix + filepath.ToSlash(shadowRel)
+
+	// Archive the shadow tree itself, so paths are relative to it
+	var stderr bytes.Buffer
+	archiveCmd := exec.Command("git", "archive", "--format=tar", rev+":"+treePath)
+	archiveCmd.Dir = topLevel
+	archiveCmd.Stderr = &stderr
+	tarball, err := archiveCmd.Output()
+	if err != nil {
+		verifyCmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+		verifyCmd.Dir = topLevel
+		if verifyCmd.Run() == nil {
+			return nil, fmt.Errorf("no shadow entries are committed at %s (%s is not in the tree)", rev, treePath)
+		}
+		return nil, fmt.Errorf("git archive %s failed: %s", rev, strings.TrimSpace(stderr.String()))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "graphfs-shadow-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := archive.ExtractTar(bytes.NewReader(tarball), tmpDir); err != nil {
+		return nil, fmt.Errorf("failed to extract shadow entries at %s: %w", rev, err)
+	}
+	return LoadSnapshot(tmpDir)
+}
+
+// DiffFormat is the output format of a shadow diff
+type DiffFormat string
+
+const (
+	DiffText     DiffFormat = "text"
+	DiffJSON     DiffFormat = "json"
+	DiffMarkdown DiffFormat = "md"
+)
+
+// FormatDiff renders a shadow diff as text, JSON or Markdown
+func FormatDiff(diff *ShadowDiff, format DiffFormat) (string, error) {
+	switch format {
+	case DiffText:
+		return formatDiffText(diff), nil
+	case DiffJSON:
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize diff: %w", err)
+		}
+		return string(data) + "\n", nil
+	case DiffMarkdown:
+		return formatDiffMarkdown(diff), nil
+	}
+	return "", fmt.Errorf("unknown format: %s", format)
+}
+
+// formatDiffText renders a shadow diff as plain text
+func formatDiffText(diff *ShadowDiff) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Shadow changes from %s to %s\n\n", diff.From, diff.To)
+	fmt.Fprintf(&b, "  %d added, %d removed, %d modified, %d unchanged\n",
+		diff.Count(ChangeAdded), diff.Count(ChangeRemoved), diff.Count(ChangeModified), diff.Unchanged)
+
+	for _, change := range diff.Changes {
+		b.WriteString("\n")
+		switch change.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s%s\n", change.Path, layerSuffix(change.NewLayer))
+			continue
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s%s\n", change.Path, layerSuffix(change.OldLayer))
+			continue
+		}
+
+		fmt.Fprintf(&b, "~ %s\n", change.Path)
+		if change.OldLayer != change.NewLayer {
+			fmt.Fprintf(&b, "    layer: %s -> %s\n", orNone(change.OldLayer), orNone(change.NewLayer))
+		}
+		writeTextList(&b, "dependencies", change.DependenciesAdded, change.DependenciesRemoved)
+		writeTextList(&b, "tags", change.TagsAdded, change.TagsRemoved)
+		if len(change.Annotations) > 0 {
+			b.WriteString("    annotations:\n")
+			for _, a := range change.Annotations {
+				switch {
+				case a.Old == "":
+					fmt.Fprintf(&b, "      + %s = %s\n", a.Key, a.New)
+				case a.New == "":
+					fmt.Fprintf(&b, "      - %s = %s\n", a.Key, a.Old)
+				default:
+					fmt.Fprintf(&b, "      ~ %s: %s -> %s\n", a.Key, a.Old, a.New)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeTextList writes the added and removed items of a list
+func writeTextList(b *strings.Builder, name string, added, removed []string) {
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "    %s: +%d -%d\n", name, len(added), len(removed))
+	for _, item := range added {
+		fmt.Fprintf(b, "      + %s\n", item)
+	}
+	for _, item := range removed {
+		fmt.Fprintf(b, "      - %s\n", item)
+	}
+}
+
+// formatDiffMarkdown renders a shadow diff as Markdown for pull requests
+func formatDiffMarkdown(diff *ShadowDiff) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Shadow Changes\n\n")
+	fmt.Fprintf(&b, "From `%s` to `%s`: **%d added**, **%d removed**, **%d modified**, %d unchanged.\n",
+		diff.From, diff.To, diff.Count(ChangeAdded), diff.Count(ChangeRemoved), diff.Count(ChangeModified), diff.Unchanged)
+
+	for _, kind := range []ChangeKind{ChangeAdded, ChangeRemoved} {
+		if diff.Count(kind) == 0 {
+			continue
+		}
+		title := "Added Modules"
+		if kind == ChangeRemoved {
+			title = "Removed Modules"
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		for _, change := range diff.Changes {
+			if change.Kind != kind {
+				continue
+			}
+			layer := change.NewLayer
+			if kind == ChangeRemoved {
+				layer = change.OldLayer
+			}
+			fmt.Fprintf(&b, "- `%s`%s\n", change.Path, layerSuffix(layer))
+		}
+	}
+
+	if diff.Count(ChangeModified) > 0 {
+		b.WriteString("\n## Modified Modules\n")
+	}
+	for _, change := range diff.Changes {
+		if change.Kind != ChangeModified {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### `%s`\n\n", change.Path)
+		if change.OldLayer != change.NewLayer {
+			fmt.Fprintf(&b, "- **Layer**: `%s` → `%s`\n", orNone(change.OldLayer), orNone(change.NewLayer))
+		}
+		writeMarkdownList(&b, "Dependencies added", change.DependenciesAdded)
+		writeMarkdownList(&b, "Dependencies removed", change.DependenciesRemoved)
+		writeMarkdownList(&b, "Tags added", change.TagsAdded)
+		writeMarkdownList(&b, "Tags removed", change.TagsRemoved)
+		if len(change.Annotations) > 0 {
+			b.WriteString("- **Annotations**:\n")
+			for _, a := range change.Annotations {
+				switch {
+				case a.Old == "":
+					fmt.Fprintf(&b, "  - added `%s` = `%s`\n", a.Key, a.New)
+				case a.New == "":
+					fmt.Fprintf(&b, "  - removed `%s` (was `%s`)\n", a.Key, a.Old)
+				default:
+					fmt.Fprintf(&b, "  - `%s`: `%s` → `%s`\n", a.Key, a.Old, a.New)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeMarkdownList writes a labelled list of items, if there are any
+func writeMarkdownList(b *strings.Builder, label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "- **%s**:\n", label)
+	for _, item := range items {
+		fmt.Fprintf(b, "  - `%s`\n", item)
+	}
+}
+
+// layerSuffix names a layer after a path, if there is one
+func layerSuffix(layer string) string {
+	if layer == "" {
+		return ""
+	}
+	return " [" + layer + "]"
+}
+
+// orNone returns a value, or "(none)" when it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
@@ -0,0 +1,188 @@
+/*
+# Module: pkg/shadow/diff_test.go
+Tests for shadow diffs and snapshots.
+
+## Tags
+shadow, test, diff
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#diff_test.go> a code:Module ;
+    code:name "pkg/shadow/diff_test.go" ;
+    code:description "Tests for shadow diffs and snapshots" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./diff.go> ;
+    code:tags "shadow", "test", "diff" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func moduleEntry(path, layer string, tags []string, deps ...string) *Entry {
+	entry := NewAutoEntry(path)
+	entry.SetModule("<#"+path+">", path, "", "go", layer, tags)
+	for _, dep := range deps {
+		entry.AddDependency("linksTo", dep, SourceAuto)
+	}
+	return entry
+}
+
+func TestDiffStates(t *testing.T) {
+	oldAPI := moduleEntry("api.go", "api", []string{"http"}, "store.go")
+	oldAPI.AddAnnotation("owner", "team-a", "")
+	oldAPI.AddAnnotation("status", "stable", "")
+	newAPI := moduleEntry("api.go", "service", []string{"http", "public"}, "cache.go")
+	newAPI.AddAnnotation("owner", "team-b", "")
+	newAPI.AddDependency("imports", "log.go", SourceAuto)
+
+	oldEntries := []*Entry{oldAPI, moduleEntry("store.go", "data", nil), moduleEntry("old.go", "legacy", nil)}
+	newEntries := []*Entry{newAPI, moduleEntry("store.go", "data", nil), moduleEntry("cache.go", "data", nil)}
+
+	diff := DiffStates(oldEntries, newEntries)
+	if diff.Count(ChangeAdded) != 1 || diff.Count(ChangeRemoved) != 1 || diff.Count(ChangeModified) != 1 || diff.Unchanged != 1 {
+		t.Fatalf("Unexpected counts: %+v", diff)
+	}
+	if diff.Changes[0].Path != "api.go" || diff.Changes[1].Path != "cache.go" || diff.Changes[2].Path != "old.go" {
+		t.Errorf("Expected changes sorted by path, got %+v", diff.Changes)
+	}
+
+	change := diff.Changes[0]
+	if change.OldLayer != "api" || change.NewLayer != "service" {
+		t.Errorf("Layer change = %q -> %q", change.OldLayer, change.NewLayer)
+	}
+	if strings.Join(change.DependenciesAdded, ",") != "cache.go,log.go (imports)" || strings.Join(change.DependenciesRemoved, ",") != "store.go" {
+		t.Errorf("Dependencies +%v -%v", change.DependenciesAdded, change.DependenciesRemoved)
+	}
+	if strings.Join(change.TagsAdded, ",") != "public" || len(change.TagsRemoved) != 0 {
+		t.Errorf("Tags +%v -%v", change.TagsAdded, change.TagsRemoved)
+	}
+	want := []AnnotationChange{{Key: "owner", Old: "team-a", New: "team-b"}, {Key: "status", Old: "stable"}}
+	if len(change.Annotations) != 2 || change.Annotations[0] != want[0] || change.Annotations[1] != want[1] {
+		t.Errorf("Annotations = %+v, want %+v", change.Annotations, want)
+	}
+}
+
+func TestFormatDiff(t *testing.T) {
+	oldAPI := moduleEntry("api.go", "api", nil, "store.go")
+	newAPI := moduleEntry("api.go", "api", nil, "cache.go")
+	diff := DiffStates([]*Entry{oldAPI}, []*Entry{newAPI, moduleEntry("cache.go", "data", nil)})
+	diff.From, diff.To = "main", "working tree"
+
+	text, err := FormatDiff(diff, DiffText)
+	if err != nil {
+		t.Fatalf("FormatDiff(text) failed: %v", err)
+	}
+	for _, want := range []string{"from main to working tree", "+ cache.go [data]", "~ api.go", "+ cache.go\n", "- store.go"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text output missing %q:\n%s", want, text)
+		}
+	}
+
+	md, err := FormatDiff(diff, DiffMarkdown)
+	if err != nil {
+		t.Fatalf("FormatDiff(md) failed: %v", err)
+	}
+	for _, want := range []string{"## Added Modules", "### `api.go`", "**Dependencies removed**"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown output missing %q:\n%s", want, md)
+		}
+	}
+
+	data, err := FormatDiff(diff, DiffJSON)
+	if err != nil || !strings.Contains(data, `"dependencies_added"`) {
+		t.Errorf("Unexpected JSON output: %s, %v", data, err)
+	}
+	if _, err := FormatDiff(diff, "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	shadowFS := newLayoutShadowFS(t, root, LayoutPacked)
+	entry := moduleEntry("api.go", "api", nil, "store.go")
+	entry.AddAnnotation("owner", "team-a", "")
+	if err := shadowFS.Set("api.go", entry); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	snapshot := graph.NewSnapshot("")
+	snapshot.Name = "before"
+	module := graph.NewModule("api.go", "<#api.go>")
+	module.Name, module.Language, module.Layer = "api.go", "go", "api"
+	module.AddDependency("store.go")
+	module.AddEdge(graph.Edge{Target: "log.go", Relation: graph.RelationImports, Inferred: true})
+	snapshot.Modules["api.go"] = &graph.SnapshotEntry{Module: module}
+	if err := shadowFS.RecordAnnotations(snapshot); err != nil {
+		t.Fatalf("RecordAnnotations failed: %v", err)
+	}
+	snapshots := graph.NewSnapshotStore(filepath.Join(root, filepath.FromSlash(graph.SnapshotDir)))
+	if err := snapshots.Save(snapshot); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	for _, path := range []string{snapshots.Path("before"), shadowFS.ShadowPath()} {
+		entries, err := LoadSnapshot(path)
+		if err != nil {
+			t.Fatalf("LoadSnapshot(%s) failed: %v", path, err)
+		}
+		current, _ := shadowFS.List()
+		if diff := DiffStates(entries, current); len(diff.Changes) != 0 || diff.Unchanged != 1 {
+			t.Errorf("Expected no changes against %s, got %+v", path, diff)
+		}
+	}
+}
+
+func TestEntriesAt(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+
+	// A project in a subdirectory of the repository
+	shadowFS := newLayoutShadowFS(t, filepath.Join(root, "app"), LayoutFiles)
+	if err := shadowFS.Set("api.go", moduleEntry("api.go", "api", nil, "store.go")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "shadow")
+
+	if err := shadowFS.Set("api.go", moduleEntry("api.go", "api", nil, "cache.go")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	committed, err := shadowFS.EntriesAt("HEAD")
+	if err != nil {
+		t.Fatalf("EntriesAt failed: %v", err)
+	}
+	current, _ := shadowFS.List()
+	diff := DiffStates(committed, current)
+	if len(diff.Changes) != 1 || strings.Join(diff.Changes[0].DependenciesRemoved, ",") != "store.go" {
+		t.Errorf("Unexpected diff against HEAD: %+v", diff.Changes)
+	}
+
+	if _, err := shadowFS.EntriesAt("no-such-rev"); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+}
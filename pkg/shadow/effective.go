@@ -0,0 +1,325 @@
+/*
+# Module: pkg/shadow/effective.go
+Effective metadata resolver for shadow entries.
+
+Computes a module's effective metadata by layering the file's shadow entry over
+directory entries (nearest directory first) and workspace defaults, and can
+apply the resolved values back onto a knowledge graph.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+shadow, inheritance, metadata, resolver
+
+## Exports
+Resolver, NewResolver, EffectiveMetadata, DirectoryEntryFile
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#effective.go> a code:Module ;
+    code:name "pkg/shadow/effective.go" ;
+    code:description "Effective metadata resolver for shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <../graph/graph.go> ;
+    code:exports <#Resolver>, <#NewResolver>, <#EffectiveMetadata>, <#DirectoryEntryFile> ;
+    code:tags "shadow", "inheritance", "metadata", "resolver" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+const (
+	// DirectoryEntryFile is the file name of a directory-level shadow entry.
+	// It deliberately does not end in ShadowExtension so that directory entries
+	// are not picked up as per-file entries by List or RebuildIndex.
+	DirectoryEntryFile = "_directory.json"
+
+	// OriginFile marks a value that came from the file's own shadow entry
+	OriginFile = "file"
+
+	// OriginWorkspace marks a value that came from workspace defaults
+	OriginWorkspace = "workspace"
+
+	// codePrefix is the namespace used for effective-value triples
+	codePrefix = "https://schema.codedoc.org/"
+)
+
+// EffectiveMetadata is the resolved metadata for a single source file
+type EffectiveMetadata struct {
+	SourcePath  string                 `json:"source_path"`
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Language    string                 `json:"language,omitempty"`
+	Layer       string                 `json:"layer,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Concepts    []string               `json:"concepts,omitempty"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+
+	// Expires records expiry timestamps of ephemeral annotations, keyed by annotation key
+	Expires map[string]time.Time `json:"expires,omitempty"`
+
+	// Origins records where each value came from, keyed by field name
+	// ("layer", "tags:<tag>", "annotation:<key>", ...). Values are "file",
+	// "workspace" or "directory:<rel-dir>".
+	Origins map[string]string `json:"origins,omitempty"`
+}
+
+// Resolver computes effective metadata from file, directory and workspace levels
+type Resolver struct {
+	shadowFS *ShadowFS
+	defaults *Entry
+}
+
+// NewResolver creates a resolver backed by a shadow file system.
+// defaults may be nil when no workspace defaults are configured.
+func NewResolver(shadowFS *ShadowFS, defaults *Entry) *Resolver {
+	return &Resolver{
+		shadowFS: shadowFS,
+		defaults: defaults,
+	}
+}
+
+// DirectoryEntryPath returns the shadow path of the entry for a directory
+func (s *ShadowFS) DirectoryEntryPath(dirPath string) (string, error) {
+	relDir, err := s.getRelativePath(dirPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.shadowPath, relDir, DirectoryEntryFile), nil
+}
+
+// GetDirectory retrieves the directory-level entry for a directory
+func (s *ShadowFS) GetDirectory(dirPath string) (*Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entryPath, err := s.DirectoryEntryPath(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadEntry(entryPath)
+}
+
+// SetDirectory stores the directory-level entry for a directory
+func (s *ShadowFS) SetDirectory(dirPath string, entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entryPath, err := s.DirectoryEntryPath(dirPath)
+	if err != nil {
+		return err
+	}
+
+	if s.config.ValidateOnWrite {
+		if err := entry.Validate(); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	return s.saveEntryFile(entryPath, s.auditPath(entryPath, entry), entry, "set-directory")
+}
+
+// Resolve computes the effective metadata for a source file.
+// Scalar values (name, description, language, layer) are taken from the most
+// specific level that sets them, tags and concepts accumulate across levels,
+// and annotations are resolved per key with the most specific level winning.
+func (r *Resolver) Resolve(sourcePath string) (*EffectiveMetadata, error) {
+	relPath, err := r.shadowFS.getRelativePath(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &EffectiveMetadata{
+		SourcePath:  relPath,
+		Annotations: make(map[string]interface{}),
+		Expires:     make(map[string]time.Time),
+		Origins:     make(map[string]string),
+	}
+
+	// File level (a missing entry is fine, values may be fully inherited)
+	if entry, err := r.shadowFS.Get(sourcePath); err == nil {
+		meta.apply(entry, OriginFile)
+	}
+
+	// Directory levels, nearest first
+	for _, relDir := range ancestorDirs(relPath) {
+		entry, err := r.shadowFS.GetDirectory(relDir)
+		if err != nil {
+			continue
+		}
+		meta.apply(entry, "directory:"+relDir)
+	}
+
+	// Workspace defaults
+	if r.defaults != nil {
+		meta.apply(r.defaults, OriginWorkspace)
+	}
+
+	sort.Strings(meta.Tags)
+	sort.Strings(meta.Concepts)
+
+	return meta, nil
+}
+
+// ApplyToGraph fills in inherited values on graph modules and adds matching
+// triples to the graph store, so that queries and rules see effective values.
+// Values declared in LinkedDoc headers are never overridden. Concepts link
+// modules to concept nodes with code:concept, and the concept taxonomy is
+// added as SKOS triples.
+// Returns the number of modules that received at least one inherited value.
+func (r *Resolver) ApplyToGraph(g *graph.Graph) (int, error) {
+	updated := 0
+
+	taxonomy, err := r.shadowFS.LoadConceptTaxonomy()
+	if err != nil {
+		return updated, err
+	}
+	if err := taxonomy.addTriples(g.Store); err != nil {
+		return updated, err
+	}
+
+	for path, module := range g.Modules {
+		meta, err := r.Resolve(path)
+		if err != nil {
+			return updated, fmt.Errorf("failed to resolve %s: %w", path, err)
+		}
+
+		changed := false
+
+		if module.Layer == "" && meta.Layer != "" {
+			module.Layer = meta.Layer
+			g.Statistics.ModulesByLayer[meta.Layer]++
+			if err := g.Store.Add(module.URI, codePrefix+"layer", meta.Layer); err != nil {
+				return updated, err
+			}
+			changed = true
+		}
+
+		for _, tag := range meta.Tags {
+			if slices.Contains(module.Tags, tag) {
+				continue
+			}
+			module.AddTag(tag)
+			if err := g.Store.Add(module.URI, codePrefix+"tags", tag); err != nil {
+				return updated, err
+			}
+			changed = true
+		}
+
+		for _, name := range meta.Concepts {
+			concept := taxonomy.Canonical(name)
+			if err := addConceptNode(g.Store, ConceptIRI(concept), concept); err != nil {
+				return updated, err
+			}
+			if err := g.Store.Add(module.URI, ConceptPredicate, ConceptIRI(concept)); err != nil {
+				return updated, err
+			}
+			changed = true
+		}
+
+		for key, value := range meta.Annotations {
+			predicate := codePrefix + key
+			if len(module.Properties[predicate]) > 0 {
+				continue
+			}
+			valueStr := fmt.Sprintf("%v", value)
+			module.AddProperty(predicate, valueStr)
+			if expiresAt, ok := meta.Expires[key]; ok {
+				err = g.Store.AddWithExpiry(module.URI, predicate, valueStr, expiresAt)
+			} else {
+				err = g.Store.Add(module.URI, predicate, valueStr)
+			}
+			if err != nil {
+				return updated, err
+			}
+			changed = true
+		}
+
+		if changed {
+			updated++
+		}
+	}
+
+	g.Statistics.TotalTriples = g.Store.Count()
+
+	return updated, nil
+}
+
+// apply layers an entry's values underneath values already resolved
+func (m *EffectiveMetadata) apply(entry *Entry, origin string) {
+	if entry.Module != nil {
+		m.setScalar(&m.Name, "name", entry.Module.Name, origin)
+		m.setScalar(&m.Description, "description", entry.Module.Description, origin)
+		m.setScalar(&m.Language, "language", entry.Module.Language, origin)
+		m.setScalar(&m.Layer, "layer", entry.Module.Layer, origin)
+
+		for _, tag := range entry.Module.Tags {
+			if !slices.Contains(m.Tags, tag) {
+				m.Tags = append(m.Tags, tag)
+				m.Origins["tags:"+tag] = origin
+			}
+		}
+	}
+
+	for _, concept := range entry.Concepts {
+		if !slices.Contains(m.Concepts, concept) {
+			m.Concepts = append(m.Concepts, concept)
+			m.Origins["concepts:"+concept] = origin
+		}
+	}
+
+	for _, a := range entry.ActiveAnnotations(time.Now()) {
+		if _, exists := m.Annotations[a.Key]; !exists {
+			m.Annotations[a.Key] = a.Value
+			m.Origins["annotation:"+a.Key] = origin
+			if a.ExpiresAt != nil {
+				m.Expires[a.Key] = *a.ExpiresAt
+			}
+		}
+	}
+}
+
+// setScalar sets a scalar field if it has not been resolved yet
+func (m *EffectiveMetadata) setScalar(field *string, name, value, origin string) {
+	if *field != "" || value == "" {
+		return
+	}
+	*field = value
+	m.Origins[name] = origin
+}
+
+// ancestorDirs returns the directories containing relPath, nearest first,
+// ending with the project root (".")
+func ancestorDirs(relPath string) []string {
+	var dirs []string
+	dir := filepath.Dir(relPath)
+	for {
+		dirs = append(dirs, dir)
+		if dir == "." || dir == string(filepath.Separator) || !strings.Contains(dir, string(filepath.Separator)) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	if dirs[len(dirs)-1] != "." {
+		dirs = append(dirs, ".")
+	}
+	return dirs
+}
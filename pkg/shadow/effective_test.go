@@ -0,0 +1,177 @@
+/*
+# Module: pkg/shadow/effective_test.go
+Tests for the effective metadata resolver.
+
+## Tags
+shadow, test, inheritance
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#effective_test.go> a code:Module ;
+    code:name "pkg/shadow/effective_test.go" ;
+    code:description "Tests for the effective metadata resolver" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./effective.go> ;
+    code:tags "shadow", "test", "inheritance" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func newTestResolver(t *testing.T) (*ShadowFS, *Resolver) {
+	t.Helper()
+
+	shadowFS, err := NewShadowFS(t.TempDir(), DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	// Workspace defaults
+	defaults := NewManualEntry(".")
+	defaults.SetModule("", "", "", "", "misc", []string{"workspace"})
+	defaults.AddAnnotation("owner", "platform-team", "")
+	defaults.AddAnnotation("reviewed", "false", "")
+
+	// Directory entry for services/
+	dirEntry := NewManualEntry("services")
+	dirEntry.SetModule("", "", "", "", "services", []string{"backend"})
+	dirEntry.AddAnnotation("owner", "team-services", "")
+	if err := shadowFS.SetDirectory("services", dirEntry); err != nil {
+		t.Fatalf("Failed to set directory entry: %v", err)
+	}
+
+	// File entry for services/auth.go
+	fileEntry := NewManualEntry("services/auth.go")
+	fileEntry.SetModule("<#auth.go>", "auth", "", "go", "", []string{"auth"})
+	fileEntry.AddAnnotation("reviewed", "true", "")
+	if err := shadowFS.Set("services/auth.go", fileEntry); err != nil {
+		t.Fatalf("Failed to set file entry: %v", err)
+	}
+
+	return shadowFS, NewResolver(shadowFS, defaults)
+}
+
+func TestResolverResolve(t *testing.T) {
+	_, resolver := newTestResolver(t)
+
+	meta, err := resolver.Resolve("services/auth.go")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if meta.Layer != "services" {
+		t.Errorf("Expected layer 'services', got %q", meta.Layer)
+	}
+	if meta.Origins["layer"] != "directory:services" {
+		t.Errorf("Expected layer origin 'directory:services', got %q", meta.Origins["layer"])
+	}
+	if meta.Language != "go" || meta.Origins["language"] != OriginFile {
+		t.Errorf("Expected file-level language 'go', got %q from %q", meta.Language, meta.Origins["language"])
+	}
+
+	expectedTags := []string{"auth", "backend", "workspace"}
+	if len(meta.Tags) != len(expectedTags) {
+		t.Fatalf("Expected tags %v, got %v", expectedTags, meta.Tags)
+	}
+	for i, tag := range expectedTags {
+		if meta.Tags[i] != tag {
+			t.Errorf("Expected tag %q at %d, got %q", tag, i, meta.Tags[i])
+		}
+	}
+
+	if meta.Annotations["owner"] != "team-services" {
+		t.Errorf("Expected owner from directory, got %v", meta.Annotations["owner"])
+	}
+	if meta.Annotations["reviewed"] != "true" {
+		t.Errorf("Expected reviewed from file, got %v", meta.Annotations["reviewed"])
+	}
+}
+
+func TestResolverResolveWithoutFileEntry(t *testing.T) {
+	_, resolver := newTestResolver(t)
+
+	meta, err := resolver.Resolve("utils/crypto.go")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if meta.Layer != "misc" || meta.Origins["layer"] != OriginWorkspace {
+		t.Errorf("Expected workspace layer 'misc', got %q from %q", meta.Layer, meta.Origins["layer"])
+	}
+	if meta.Annotations["owner"] != "platform-team" {
+		t.Errorf("Expected workspace owner, got %v", meta.Annotations["owner"])
+	}
+}
+
+func TestResolverApplyToGraph(t *testing.T) {
+	_, resolver := newTestResolver(t)
+
+	g := graph.NewGraph("/project", store.NewTripleStore())
+	module := graph.NewModule("services/auth.go", "<#auth.go>")
+	module.Language = "go"
+	module.AddTag("auth")
+	g.AddModule(module)
+
+	updated, err := resolver.ApplyToGraph(g)
+	if err != nil {
+		t.Fatalf("ApplyToGraph failed: %v", err)
+	}
+	if updated != 1 {
+		t.Errorf("Expected 1 updated module, got %d", updated)
+	}
+
+	if module.Layer != "services" {
+		t.Errorf("Expected inherited layer 'services', got %q", module.Layer)
+	}
+	if g.Statistics.ModulesByLayer["services"] != 1 {
+		t.Errorf("Expected layer statistics to be updated")
+	}
+
+	if len(g.Store.Find("<#auth.go>", codePrefix+"layer", "services")) != 1 {
+		t.Error("Expected layer triple in store")
+	}
+	if len(g.Store.Find("<#auth.go>", codePrefix+"tags", "backend")) != 1 {
+		t.Error("Expected inherited tag triple in store")
+	}
+	if len(g.Store.Find("<#auth.go>", codePrefix+"owner", "team-services")) != 1 {
+		t.Error("Expected inherited annotation triple in store")
+	}
+}
+
+func TestAncestorDirs(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"main.go", []string{"."}},
+		{"services/auth.go", []string{"services", "."}},
+		{"pkg/api/v1/handler.go", []string{"pkg/api/v1", "pkg/api", "pkg", "."}},
+	}
+
+	for _, tt := range tests {
+		dirs := ancestorDirs(tt.path)
+		if len(dirs) != len(tt.expected) {
+			t.Errorf("ancestorDirs(%q) = %v, expected %v", tt.path, dirs, tt.expected)
+			continue
+		}
+		for i := range dirs {
+			if dirs[i] != tt.expected[i] {
+				t.Errorf("ancestorDirs(%q) = %v, expected %v", tt.path, dirs, tt.expected)
+				break
+			}
+		}
+	}
+}
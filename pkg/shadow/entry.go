@@ -36,6 +36,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/justin4957/graphfs/pkg/pathkey"
 )
 
 // EntrySource indicates the origin of shadow metadata
@@ -58,6 +60,7 @@ type Triple struct {
 	Predicate string      `json:"predicate"`
 	Object    string      `json:"object"`
 	Source    EntrySource `json:"source,omitempty"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"` // Ignored after this time
 }
 
 // Module represents structured module information
@@ -84,6 +87,7 @@ type Annotation struct {
 	Author    string      `json:"author,omitempty"`
 	CreatedAt time.Time   `json:"created_at,omitempty"`
 	UpdatedAt time.Time   `json:"updated_at,omitempty"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"` // Ignored after this time
 }
 
 // Entry represents a shadow file entry for a source file
@@ -99,6 +103,9 @@ type Entry struct {
 	// Module information (structured)
 	Module *Module `json:"module,omitempty"`
 
+	// Components declared by additional LinkedDoc blocks in the file
+	Components []Module `json:"components,omitempty"`
+
 	// Relationships (structured)
 	Dependencies []Relationship `json:"dependencies,omitempty"`
 	Dependents   []Relationship `json:"dependents,omitempty"`
@@ -123,7 +130,7 @@ func NewEntry(sourcePath string, source EntrySource) *Entry {
 	now := time.Now()
 	return &Entry{
 		Version:    ShadowVersion,
-		SourcePath: sourcePath,
+		SourcePath: pathkey.Canonical(sourcePath),
 		Source:     source,
 		CreatedAt:  now,
 		UpdatedAt:  now,
@@ -141,12 +148,28 @@ func NewManualEntry(sourcePath string) *Entry {
 	return NewEntry(sourcePath, SourceManual)
 }
 
-// LoadEntry loads a shadow entry from a file
+// LoadEntry loads a shadow entry from a file. Entries whose format version
+// cannot be read return a *VersionError (see CheckVersion).
 func LoadEntry(path string) (*Entry, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read shadow file: %w", err)
 	}
+	return parseEntry(path, data)
+}
+
+// parseEntry decodes an entry stored at path, checking its format version
+func parseEntry(path string, data []byte) (*Entry, error) {
+	var header struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse shadow file: %w", err)
+	}
+	if err := CheckVersion(header.Version); err != nil {
+		err.(*VersionError).Path = path
+		return nil, err
+	}
 
 	var entry Entry
 	if err := json.Unmarshal(data, &entry); err != nil {
@@ -158,6 +181,20 @@ func LoadEntry(path string) (*Entry, error) {
 
 // Save writes the entry to a file
 func (e *Entry) Save(path string, prettyPrint bool) error {
+	data, err := e.encode(prettyPrint)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write shadow file: %w", err)
+	}
+
+	return nil
+}
+
+// encode stamps the entry as updated and serializes it
+func (e *Entry) encode(prettyPrint bool) ([]byte, error) {
 	e.UpdatedAt = time.Now()
 
 	var data []byte
@@ -170,14 +207,9 @@ func (e *Entry) Save(path string, prettyPrint bool) error {
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to serialize shadow entry: %w", err)
+		return nil, fmt.Errorf("failed to serialize shadow entry: %w", err)
 	}
-
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write shadow file: %w", err)
-	}
-
-	return nil
+	return data, nil
 }
 
 // Validate checks if the entry is valid
@@ -300,6 +332,16 @@ func (e *Entry) AddConcept(concept string) {
 
 // AddAnnotation adds a manual annotation
 func (e *Entry) AddAnnotation(key string, value interface{}, author string) {
+	e.setAnnotation(key, value, author, nil)
+}
+
+// AddEphemeralAnnotation adds a manual annotation that is ignored after expiresAt
+func (e *Entry) AddEphemeralAnnotation(key string, value interface{}, author string, expiresAt time.Time) {
+	e.setAnnotation(key, value, author, &expiresAt)
+}
+
+// setAnnotation adds or updates an annotation with an optional expiry
+func (e *Entry) setAnnotation(key string, value interface{}, author string, expiresAt *time.Time) {
 	now := time.Now()
 
 	// Update existing annotation if key matches
@@ -307,6 +349,7 @@ func (e *Entry) AddAnnotation(key string, value interface{}, author string) {
 		if a.Key == key {
 			e.Annotations[i].Value = value
 			e.Annotations[i].UpdatedAt = now
+			e.Annotations[i].ExpiresAt = expiresAt
 			if author != "" {
 				e.Annotations[i].Author = author
 			}
@@ -322,14 +365,16 @@ func (e *Entry) AddAnnotation(key string, value interface{}, author string) {
 		Author:    author,
 		CreatedAt: now,
 		UpdatedAt: now,
+		ExpiresAt: expiresAt,
 	})
 	e.UpdatedAt = now
 }
 
-// GetAnnotation retrieves an annotation by key
+// GetAnnotation retrieves an unexpired annotation by key
 func (e *Entry) GetAnnotation(key string) (interface{}, bool) {
+	now := time.Now()
 	for _, a := range e.Annotations {
-		if a.Key == key {
+		if a.Key == key && !a.IsExpired(now) {
 			return a.Value, true
 		}
 	}
@@ -506,7 +551,7 @@ func (e *Entry) HasManualData() bool {
 		}
 	}
 
-	return len(e.Annotations) > 0
+	return len(e.ActiveAnnotations(time.Now())) > 0
 }
 
 // GetTriplesByPredicate returns all triples with the given predicate
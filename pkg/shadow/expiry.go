@@ -0,0 +1,209 @@
+/*
+# Module: pkg/shadow/expiry.go
+Ephemeral shadow triples and annotations.
+
+Triples and annotations may carry an expiry timestamp for temporary
+metadata such as rule waivers or incident notes. Expired items are ignored
+by the index and by effective metadata resolution, and can be removed from
+shadow files with PurgeExpired.
+
+## Linked Modules
+- [entry](./entry.go) - Shadow entry data structure
+- [shadow](./shadow.go) - Shadow file system manager
+- [effective](./effective.go) - Effective metadata resolution
+
+## Tags
+shadow, expiry, ttl, annotations
+
+## Exports
+ExpiredItem, FindExpired, PurgeExpired
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#expiry.go> a code:Module ;
+    code:name "pkg/shadow/expiry.go" ;
+    code:description "Ephemeral shadow triples and annotations" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./entry.go>, <./shadow.go>, <./effective.go> ;
+    code:exports <#ExpiredItem>, <#FindExpired>, <#PurgeExpired> ;
+    code:tags "shadow", "expiry", "ttl", "annotations" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ExpiredItem describes an expired triple or annotation in a shadow file
+type ExpiredItem struct {
+	Path      string    `json:"path"`       // Source path (or directory) of the entry
+	Kind      string    `json:"kind"`       // "triple" or "annotation"
+	Key       string    `json:"key"`        // Annotation key or triple predicate
+	Value     string    `json:"value"`      // Annotation value or triple object
+	ExpiresAt time.Time `json:"expires_at"` // When the item expired
+}
+
+// IsExpired reports whether the triple has expired at the given time
+func (t Triple) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// IsExpired reports whether the annotation has expired at the given time
+func (a Annotation) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// AddEphemeralTriple adds an RDF triple that is ignored after expiresAt
+func (e *Entry) AddEphemeralTriple(subject, predicate, object string, source EntrySource, expiresAt time.Time) {
+	for i, t := range e.Triples {
+		if t.Subject == subject && t.Predicate == predicate && t.Object == object {
+			e.Triples[i].ExpiresAt = &expiresAt
+			e.UpdatedAt = time.Now()
+			return
+		}
+	}
+
+	e.Triples = append(e.Triples, Triple{
+		Subject:   subject,
+		Predicate: predicate,
+		Object:    object,
+		Source:    source,
+		ExpiresAt: &expiresAt,
+	})
+	e.UpdatedAt = time.Now()
+}
+
+// ActiveTriples returns the triples that have not expired
+func (e *Entry) ActiveTriples(now time.Time) []Triple {
+	var active []Triple
+	for _, t := range e.Triples {
+		if !t.IsExpired(now) {
+			active = append(active, t)
+		}
+	}
+	return active
+}
+
+// ActiveAnnotations returns the annotations that have not expired
+func (e *Entry) ActiveAnnotations(now time.Time) []Annotation {
+	var active []Annotation
+	for _, a := range e.Annotations {
+		if !a.IsExpired(now) {
+			active = append(active, a)
+		}
+	}
+	return active
+}
+
+// ExpiredItems lists the expired triples and annotations in the entry
+func (e *Entry) ExpiredItems(now time.Time) []ExpiredItem {
+	var items []ExpiredItem
+	for _, t := range e.Triples {
+		if t.IsExpired(now) {
+			items = append(items, ExpiredItem{
+				Path:      e.SourcePath,
+				Kind:      "triple",
+				Key:       t.Predicate,
+				Value:     t.Object,
+				ExpiresAt: *t.ExpiresAt,
+			})
+		}
+	}
+	for _, a := range e.Annotations {
+		if a.IsExpired(now) {
+			items = append(items, ExpiredItem{
+				Path:      e.SourcePath,
+				Kind:      "annotation",
+				Key:       a.Key,
+				Value:     fmt.Sprintf("%v", a.Value),
+				ExpiresAt: *a.ExpiresAt,
+			})
+		}
+	}
+	return items
+}
+
+// PurgeExpired removes expired triples and annotations from the entry and
+// returns how many were removed
+func (e *Entry) PurgeExpired(now time.Time) int {
+	activeTriples := e.ActiveTriples(now)
+	activeAnnotations := e.ActiveAnnotations(now)
+	removed := len(e.Triples) - len(activeTriples) + len(e.Annotations) - len(activeAnnotations)
+	if removed > 0 {
+		e.Triples = activeTriples
+		e.Annotations = activeAnnotations
+	}
+	return removed
+}
+
+// FindExpired lists expired items across all shadow files, including
+// directory-level entries
+func (s *ShadowFS) FindExpired(now time.Time) ([]ExpiredItem, error) {
+	var items []ExpiredItem
+
+	err := s.walkEntryFiles(func(path string, entry *Entry) error {
+		items = append(items, entry.ExpiredItems(now)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Path != items[j].Path {
+			return items[i].Path < items[j].Path
+		}
+		return items[i].Key < items[j].Key
+	})
+
+	return items, nil
+}
+
+// PurgeExpired removes expired items from all shadow files, rebuilds the
+// index and returns the number of items removed
+func (s *ShadowFS) PurgeExpired(now time.Time) (int, error) {
+	removed := 0
+
+	err := s.walkEntryFiles(func(path string, entry *Entry) error {
+		count := entry.PurgeExpired(now)
+		if count == 0 {
+			return nil
+		}
+		if err := s.saveEntryFile(path, s.auditPath(path, entry), entry, "purge-expired"); err != nil {
+			return err
+		}
+		removed += count
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+
+	if removed > 0 {
+		if err := s.RebuildIndex(); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+// walkEntryFiles loads every shadow and directory entry file
+func (s *ShadowFS) walkEntryFiles(fn func(path string, entry *Entry) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.walkEntries(fn)
+	if err != nil {
+		return fmt.Errorf("failed to walk shadow entries: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package shadow
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEntryExpiry(t *testing.T) {
+	now := time.Now()
+	entry := NewManualEntry("api.go")
+	entry.AddAnnotation("owner", "team-api", "")
+	entry.AddEphemeralAnnotation("waiver", "no-cycles", "", now.Add(-time.Hour))
+	entry.AddTriple("<#api.go>", "code:layer", "api", SourceManual)
+	entry.AddEphemeralTriple("<#api.go>", "code:incident", "INC-42", SourceManual, now.Add(-time.Minute))
+
+	if _, ok := entry.GetAnnotation("waiver"); ok {
+		t.Error("Expected expired annotation to be ignored")
+	}
+	if _, ok := entry.GetAnnotation("owner"); !ok {
+		t.Error("Expected permanent annotation to be returned")
+	}
+	if got := len(entry.ActiveTriples(now)); got != 1 {
+		t.Errorf("ActiveTriples = %d, want 1", got)
+	}
+	if got := len(entry.ExpiredItems(now)); got != 2 {
+		t.Errorf("ExpiredItems = %d, want 2", got)
+	}
+
+	if removed := entry.PurgeExpired(now); removed != 2 {
+		t.Errorf("PurgeExpired = %d, want 2", removed)
+	}
+	if len(entry.Triples) != 1 || len(entry.Annotations) != 1 {
+		t.Errorf("Expected 1 triple and 1 annotation after purge, got %d and %d",
+			len(entry.Triples), len(entry.Annotations))
+	}
+}
+
+func TestShadowFSPurgeExpired(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	shadowFS, err := NewShadowFS(tmpDir, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize shadow file system: %v", err)
+	}
+
+	now := time.Now()
+	entry := NewManualEntry("api.go")
+	entry.AddEphemeralAnnotation("waiver", "no-cycles", "", now.Add(-time.Hour))
+	entry.AddEphemeralAnnotation("incident", "INC-42", "", now.Add(time.Hour))
+	if err := shadowFS.Set(filepath.Join(tmpDir, "api.go"), entry); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	dirEntry := NewManualEntry("pkg")
+	dirEntry.AddEphemeralAnnotation("freeze", "true", "", now.Add(-time.Hour))
+	if err := shadowFS.SetDirectory("pkg", dirEntry); err != nil {
+		t.Fatalf("Failed to set directory entry: %v", err)
+	}
+
+	items, err := shadowFS.FindExpired(now)
+	if err != nil {
+		t.Fatalf("FindExpired failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 expired items, got %d", len(items))
+	}
+
+	removed, err := shadowFS.PurgeExpired(now)
+	if err != nil {
+		t.Fatalf("PurgeExpired failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("PurgeExpired = %d, want 2", removed)
+	}
+
+	reloaded, err := shadowFS.Get(filepath.Join(tmpDir, "api.go"))
+	if err != nil {
+		t.Fatalf("Failed to reload entry: %v", err)
+	}
+	if len(reloaded.Annotations) != 1 || reloaded.Annotations[0].Key != "incident" {
+		t.Errorf("Unexpected annotations after purge: %+v", reloaded.Annotations)
+	}
+}
@@ -3,11 +3,23 @@
 Shadow index for fast lookups and queries.
 
 Provides in-memory indexing of shadow entries for efficient querying
-by various attributes like tags, concepts, language, and layer.
+by various attributes like tags, concepts, language, and layer, and
+full-text search ranked by relevance (see textindex.go).
+
+Entries are sharded by top-level directory. Statistics are updated
+incrementally, inverted indexes are rebuilt lazily on the first lookup after
+a change, and Save writes only shards changed since the last save.
+
+Load and Save take the workspace lock (see lock.go) so concurrent processes
+never read a half-saved index. Every save bumps a generation number; when
+the index on disk moved on since this one was loaded, Save replays the
+changes made here on top of it instead of overwriting it.
 
 ## Linked Modules
 - [shadow](./shadow.go) - Shadow file system manager
 - [entry](./entry.go) - Shadow entry data structure
+- [textindex](./textindex.go) - Full-text term index
+- [../pathkey](../pathkey/pathkey.go) - Canonical path keys
 
 ## Tags
 shadow, index, query, lookup
@@ -24,7 +36,7 @@ Index, NewIndex, IndexEntry
     code:description "Shadow index for fast lookups and queries" ;
     code:language "go" ;
     code:layer "shadow" ;
-    code:linksTo <./shadow.go>, <./entry.go> ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./textindex.go>, <../pathkey/pathkey.go> ;
     code:exports <#Index>, <#NewIndex>, <#IndexEntry> ;
     code:tags "shadow", "index", "query", "lookup" .
 <!-- End LinkedDoc RDF -->
@@ -34,12 +46,18 @@ package shadow
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/justin4957/graphfs/pkg/pathkey"
 )
 
 // IndexEntry represents a lightweight index record for a shadow entry
@@ -55,6 +73,9 @@ type IndexEntry struct {
 	UpdatedAt   time.Time   `json:"updated_at"`
 	TripleCount int         `json:"triple_count"`
 	HasManual   bool        `json:"has_manual"`
+
+	// Terms are the weighted full-text search terms of the entry
+	Terms map[string]int `json:"terms,omitempty"`
 }
 
 // Index provides fast lookups for shadow entries
@@ -68,22 +89,71 @@ type Index struct {
 	// UpdatedAt timestamp
 	UpdatedAt time.Time `json:"updated_at"`
 
-	// Entries indexed by path
+	// Entries indexed by path key (see pkg/pathkey)
 	Entries map[string]*IndexEntry `json:"entries"`
 
-	// Inverted indexes for fast lookups
+	// Inverted indexes for fast lookups, rebuilt lazily after changes
 	ByTag      map[string][]string `json:"by_tag"`
 	ByConcept  map[string][]string `json:"by_concept"`
 	ByLanguage map[string][]string `json:"by_language"`
 	ByLayer    map[string][]string `json:"by_layer"`
 
+	// text is the full-text term index, rebuilt with the inverted indexes
+	text *textIndex
+
 	// Statistics
 	Stats IndexStats `json:"stats"`
 
 	// Mutex for thread-safe operations
 	mu sync.RWMutex `json:"-"`
+
+	// keys maps paths to Entries keys
+	keys pathkey.Normalizer
+
+	// shards groups entry keys by top-level directory
+	shards map[string]map[string]*IndexEntry
+
+	// dirtyShards are shards changed since the last save
+	dirtyShards map[string]bool
+
+	// invertedDirty is set when the inverted indexes are out of date
+	invertedDirty bool
+
+	// savedPath is the file the index was last loaded from or saved to
+	savedPath string
+
+	// generation is the generation of the index file at savedPath when it
+	// was last loaded or saved
+	generation uint64
+
+	// pending are the entries added (or removed, when nil) since the last
+	// load or save, replayed onto the index file if it changed meanwhile
+	pending map[string]*IndexEntry
+
+	// replace makes the next Save overwrite the index file rather than
+	// merge into it, as after Clear
+	replace bool
+
+	// lockRoot is the project whose workspace lock guards Load and Save;
+	// when empty, the directory of the index file is used
+	lockRoot string
+
+	// noLock skips the workspace lock, and lockTimeout is how long to wait
+	// for it
+	noLock      bool
+	lockTimeout time.Duration
 }
 
+const (
+	// indexSaveAttempts is how often Save replays its changes when the
+	// index file keeps changing underneath it
+	indexSaveAttempts = 3
+)
+
+// ErrIndexConflict is returned when the index file kept changing while it
+// was being saved
+var ErrIndexConflict = errors.New("shadow index changed on disk while saving")
+
 // IndexStats tracks index statistics
 type IndexStats struct {
 	TotalEntries  int            `json:"total_entries"`
@@ -101,20 +171,21 @@ type IndexStats struct {
 func NewIndex() *Index {
 	now := time.Now()
 	return &Index{
-		Version:    ShadowVersion,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-		Entries:    make(map[string]*IndexEntry),
-		ByTag:      make(map[string][]string),
-		ByConcept:  make(map[string][]string),
-		ByLanguage: make(map[string][]string),
-		ByLayer:    make(map[string][]string),
-		Stats: IndexStats{
-			LanguageCount: make(map[string]int),
-			LayerCount:    make(map[string]int),
-			TagCount:      make(map[string]int),
-			ConceptCount:  make(map[string]int),
-		},
+		Version:     ShadowVersion,
+		keys:        pathkey.Default(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Entries:     make(map[string]*IndexEntry),
+		ByTag:       make(map[string][]string),
+		ByConcept:   make(map[string][]string),
+		ByLanguage:  make(map[string][]string),
+		ByLayer:     make(map[string][]string),
+		text:        newTextIndex(nil),
+		Stats:       newIndexStats(),
+		shards:      make(map[string]map[string]*IndexEntry),
+		dirtyShards: make(map[string]bool),
+		pending:     make(map[string]*IndexEntry),
+		lockTimeout: DefaultLockTimeout,
 	}
 }
 
@@ -123,19 +194,18 @@ func (idx *Index) Add(path string, entry *Entry) {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
-	// Remove existing entry from inverted indexes if present
-	if existing, ok := idx.Entries[path]; ok {
-		idx.removeFromInvertedIndexes(path, existing)
-	}
+	path = pathkey.Canonical(path)
+	key := idx.keys.Key(path)
 
 	// Create index entry
 	indexEntry := &IndexEntry{
 		Path:        path,
 		Source:      entry.Source,
 		UpdatedAt:   entry.UpdatedAt,
-		TripleCount: len(entry.Triples),
+		TripleCount: len(entry.ActiveTriples(time.Now())),
 		HasManual:   entry.HasManualData(),
 		Concepts:    entry.Concepts,
+		Terms:       entryTerms(path, entry),
 	}
 
 	// Extract module info if present
@@ -147,14 +217,8 @@ func (idx *Index) Add(path string, entry *Entry) {
 		indexEntry.Tags = entry.Module.Tags
 	}
 
-	// Store entry
-	idx.Entries[path] = indexEntry
-
-	// Update inverted indexes
-	idx.addToInvertedIndexes(path, indexEntry)
-
-	// Update stats
-	idx.updateStats()
+	idx.put(key, indexEntry)
+	idx.pending[key] = indexEntry
 	idx.UpdatedAt = time.Now()
 }
 
@@ -163,26 +227,65 @@ func (idx *Index) Remove(path string) {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
-	if entry, ok := idx.Entries[path]; ok {
-		idx.removeFromInvertedIndexes(path, entry)
-		delete(idx.Entries, path)
-		idx.updateStats()
+	// The removal is recorded even for entries this index never held, so
+	// it also applies to the index file when the two are merged
+	key := idx.keys.Key(path)
+	idx.pending[key] = nil
+	if idx.delete(key) {
 		idx.UpdatedAt = time.Now()
 	}
 }
 
+// put stores an entry under a key, updating statistics and marking its
+// shard and the inverted indexes dirty (caller must hold lock)
+func (idx *Index) put(key string, entry *IndexEntry) {
+	idx.delete(key)
+
+	shard := shardOf(key)
+	if idx.shards[shard] == nil {
+		idx.shards[shard] = make(map[string]*IndexEntry)
+	}
+	idx.shards[shard][key] = entry
+	idx.Entries[key] = entry
+	idx.Stats.add(entry, 1)
+
+	idx.dirtyShards[shard] = true
+	idx.invertedDirty = true
+}
+
+// delete removes the entry stored under a key and reports whether there was
+// one (caller must hold lock)
+func (idx *Index) delete(key string) bool {
+	existing, ok := idx.Entries[key]
+	if !ok {
+		return false
+	}
+
+	shard := shardOf(key)
+	delete(idx.shards[shard], key)
+	if len(idx.shards[shard]) == 0 {
+		delete(idx.shards, shard)
+	}
+	delete(idx.Entries, key)
+	idx.Stats.add(existing, -1)
+
+	idx.dirtyShards[shard] = true
+	idx.invertedDirty = true
+	return true
+}
+
 // Get retrieves an index entry by path
 func (idx *Index) Get(path string) (*IndexEntry, bool) {
 	idx.mu.RLock()
 	defer idx.mu.RUnlock()
 
-	entry, ok := idx.Entries[path]
+	entry, ok := idx.Entries[idx.keys.Key(path)]
 	return entry, ok
 }
 
 // GetByTag returns all paths with the given tag
 func (idx *Index) GetByTag(tag string) []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	paths := idx.ByTag[tag]
@@ -193,7 +296,7 @@ func (idx *Index) GetByTag(tag string) []string {
 
 // GetByConcept returns all paths with the given concept
 func (idx *Index) GetByConcept(concept string) []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	paths := idx.ByConcept[concept]
@@ -204,7 +307,7 @@ func (idx *Index) GetByConcept(concept string) []string {
 
 // GetByLanguage returns all paths with the given language
 func (idx *Index) GetByLanguage(language string) []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	paths := idx.ByLanguage[language]
@@ -215,7 +318,7 @@ func (idx *Index) GetByLanguage(language string) []string {
 
 // GetByLayer returns all paths with the given layer
 func (idx *Index) GetByLayer(layer string) []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	paths := idx.ByLayer[layer]
@@ -224,9 +327,21 @@ func (idx *Index) GetByLayer(layer string) []string {
 	return result
 }
 
-// Search performs a multi-criteria search on the index
+// Search performs a multi-criteria search on the index. Results are sorted
+// by path, or by relevance when the query has text.
 func (idx *Index) Search(query SearchQuery) []string {
-	idx.mu.RLock()
+	results := idx.SearchRanked(query)
+	paths := make([]string, len(results))
+	for i, result := range results {
+		paths[i] = result.Path
+	}
+	return paths
+}
+
+// SearchRanked performs a multi-criteria search on the index and returns
+// matches with their full-text relevance scores, best first
+func (idx *Index) SearchRanked(query SearchQuery) []SearchResult {
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	var results []string
@@ -262,7 +377,10 @@ func (idx *Index) Search(query SearchQuery) []string {
 
 	if len(query.Concepts) > 0 {
 		for _, concept := range query.Concepts {
-			conceptResults := idx.ByConcept[concept]
+			var conceptResults []string
+			for _, name := range query.Taxonomy.Expand(concept) {
+				conceptResults = union(conceptResults, idx.ByConcept[name])
+			}
 			if firstFilter {
 				results = conceptResults
 				firstFilter = false
@@ -275,14 +393,21 @@ func (idx *Index) Search(query SearchQuery) []string {
 	// If no filters applied, return all paths
 	if firstFilter {
 		results = make([]string, 0, len(idx.Entries))
-		for path := range idx.Entries {
-			results = append(results, path)
+		for _, entry := range idx.Entries {
+			results = append(results, entry.Path)
 		}
 	}
 
-	// Apply text search filter
+	// Apply text search, falling back to substring matching of paths,
+	// names and URIs for queries without searchable terms
+	var scores map[string]float64
 	if query.TextQuery != "" {
-		results = idx.filterByText(results, query.TextQuery)
+		var ok bool
+		if scores, ok = idx.text.score(query.TextQuery, len(idx.Entries)); ok {
+			results = filterByScore(results, scores)
+		} else {
+			results = idx.filterByText(results, query.TextQuery)
+		}
 	}
 
 	// Apply source filter
@@ -296,21 +421,30 @@ func (idx *Index) Search(query SearchQuery) []string {
 	}
 
 	// Sort results
-	sort.Strings(results)
+	ranked := make([]SearchResult, len(results))
+	for i, path := range results {
+		ranked[i] = SearchResult{Path: path, Score: scores[path]}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
 
 	// Apply limit and offset
 	if query.Offset > 0 {
-		if query.Offset >= len(results) {
-			return []string{}
+		if query.Offset >= len(ranked) {
+			return []SearchResult{}
 		}
-		results = results[query.Offset:]
+		ranked = ranked[query.Offset:]
 	}
 
-	if query.Limit > 0 && query.Limit < len(results) {
-		results = results[:query.Limit]
+	if query.Limit > 0 && query.Limit < len(ranked) {
+		ranked = ranked[:query.Limit]
 	}
 
-	return results
+	return ranked
 }
 
 // SearchQuery defines search criteria
@@ -319,16 +453,20 @@ type SearchQuery struct {
 	Layer     string
 	Tags      []string
 	Concepts  []string
-	TextQuery string
+	TextQuery string // Full-text query; entries must match every term
 	Source    EntrySource
 	HasManual bool
 	Limit     int
 	Offset    int
+
+	// Taxonomy expands each concept to its descendants and aliases; nil
+	// matches concepts exactly
+	Taxonomy *ConceptTaxonomy
 }
 
 // ListTags returns all unique tags
 func (idx *Index) ListTags() []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	tags := make([]string, 0, len(idx.ByTag))
@@ -341,7 +479,7 @@ func (idx *Index) ListTags() []string {
 
 // ListConcepts returns all unique concepts
 func (idx *Index) ListConcepts() []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	concepts := make([]string, 0, len(idx.ByConcept))
@@ -354,7 +492,7 @@ func (idx *Index) ListConcepts() []string {
 
 // ListLanguages returns all unique languages
 func (idx *Index) ListLanguages() []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	languages := make([]string, 0, len(idx.ByLanguage))
@@ -367,7 +505,7 @@ func (idx *Index) ListLanguages() []string {
 
 // ListLayers returns all unique layers
 func (idx *Index) ListLayers() []string {
-	idx.mu.RLock()
+	idx.rlockInverted()
 	defer idx.mu.RUnlock()
 
 	layers := make([]string, 0, len(idx.ByLayer))
@@ -378,74 +516,343 @@ func (idx *Index) ListLayers() []string {
 	return layers
 }
 
-// Statistics returns the current index statistics
+// Statistics returns a copy of the current index statistics
 func (idx *Index) Statistics() IndexStats {
 	idx.mu.RLock()
 	defer idx.mu.RUnlock()
 
-	return idx.Stats
+	stats := idx.Stats
+	stats.LanguageCount = maps.Clone(idx.Stats.LanguageCount)
+	stats.LayerCount = maps.Clone(idx.Stats.LayerCount)
+	stats.TagCount = maps.Clone(idx.Stats.TagCount)
+	stats.ConceptCount = maps.Clone(idx.Stats.ConceptCount)
+	return stats
+}
+
+// indexManifest is the index file: metadata and the list of shards, each
+// stored in its own file in the shard directory next to it
+type indexManifest struct {
+	Version   string     `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Stats     IndexStats `json:"stats"`
+	Shards    []string   `json:"shards"`
+
+	// Generation counts saves, so a writer can tell the file changed since
+	// it loaded it
+	Generation uint64 `json:"generation,omitempty"`
+
+	// Entries is set by index files written before sharding
+	Entries map[string]*IndexEntry `json:"entries,omitempty"`
+}
+
+// indexShard is a shard file
+type indexShard struct {
+	Entries map[string]*IndexEntry `json:"entries"`
 }
 
-// Load loads the index from a file
+// Load loads the index from a file. Index files written before sharding are
+// loaded too, and are converted to shards by the next Save.
 func (idx *Index) Load(path string) error {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to read index file: %w", err)
+	}
+	unlock, err := idx.lock(path)
+	switch {
+	case err == nil:
+		defer unlock()
+	case errors.Is(err, os.ErrPermission):
+		// Read-only workspaces are read without the lock
+	default:
+		return fmt.Errorf("failed to lock index: %w", err)
+	}
+
+	manifest, entries, err := readIndexFile(path)
+	if err != nil {
+		return err
+	}
+
+	idx.Version = manifest.Version
+	idx.CreatedAt = manifest.CreatedAt
+	idx.UpdatedAt = manifest.UpdatedAt
+
+	// Indexes written before path keys, or on another platform, may use
+	// different keys
+	idx.dirtyShards = make(map[string]bool)
+	idx.rekey(entries)
+
+	idx.savedPath = path
+	idx.generation = manifest.Generation
+	idx.pending = make(map[string]*IndexEntry)
+	idx.replace = false
+	if manifest.Entries != nil {
+		idx.markAllDirty()
+	}
+
+	return nil
+}
+
+// lock takes the workspace lock guarding the index file at path, unless
+// locking is disabled, and returns a function that releases it
+func (idx *Index) lock(path string) (func(), error) {
+	if idx.noLock {
+		return func() {}, nil
+	}
+	root := idx.lockRoot
+	if root == "" {
+		root = filepath.Dir(path)
+	}
+	return lockIndex(root, idx.lockTimeout)
+}
+
+// readIndexFile reads an index file and its shards (caller must hold the
+// workspace lock)
+func readIndexFile(path string) (*indexManifest, map[string]*IndexEntry, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to read index file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read index file: %w", err)
+	}
+
+	var manifest indexManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse index file: %w", err)
+	}
+	if manifest.Version != "" {
+		if err := CheckVersion(manifest.Version); err != nil {
+			err.(*VersionError).Path = path
+			return nil, nil, err
+		}
+	}
+
+	if manifest.Entries != nil {
+		return &manifest, manifest.Entries, nil
+	}
+
+	entries := make(map[string]*IndexEntry)
+	for _, shard := range manifest.Shards {
+		shardData, err := os.ReadFile(shardFile(path, shard))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read index shard %q: %w", shard, err)
+		}
+		var loaded indexShard
+		if err := json.Unmarshal(shardData, &loaded); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse index shard %q: %w", shard, err)
+		}
+		for key, entry := range loaded.Entries {
+			entries[key] = entry
+		}
+	}
+	return &manifest, entries, nil
+}
+
+// readIndexManifest reads an index file without its shards, returning nil
+// if there is no readable index file
+func readIndexManifest(path string) *indexManifest {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var manifest indexManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil
+	}
+	return &manifest
+}
+
+// SetKeys changes how paths map to entry keys and re-keys existing entries
+func (idx *Index) SetKeys(keys pathkey.Normalizer) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.keys = keys
+	idx.rekey(idx.Entries)
+}
+
+// rekey replaces the contents of the index with entries stored under their
+// current keys with canonical paths. Entries are assumed to be saved in the
+// shards of their old keys, so only shards whose contents moved are marked
+// dirty (caller must hold lock).
+func (idx *Index) rekey(entries map[string]*IndexEntry) {
+	dirty := idx.dirtyShards
+	idx.reset()
+
+	for key, entry := range entries {
+		if entry.Path == "" {
+			entry.Path = key
+		}
+		entry.Path = pathkey.Canonical(entry.Path)
+
+		newKey := idx.keys.Key(entry.Path)
+		idx.put(newKey, entry)
+		if newKey != key {
+			dirty[shardOf(key)] = true
+			dirty[shardOf(newKey)] = true
+		}
 	}
 
-	var loaded Index
-	if err := json.Unmarshal(data, &loaded); err != nil {
-		return fmt.Errorf("failed to parse index file: %w", err)
+	idx.dirtyShards = dirty
+}
+
+// Save saves the index to a file. Shards are written to a directory next to
+// it, and only shards changed since the last load or save are rewritten.
+// If another process saved the file since this index was loaded, the
+// changes made here are replayed on top of its contents, so neither
+// process's entries are lost; an index that was cleared, or is saved to a
+// new file, overwrites it instead.
+func (idx *Index) Save(path string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
 	}
+	unlock, err := idx.lock(path)
+	if err != nil {
+		return fmt.Errorf("failed to lock index: %w", err)
+	}
+	defer unlock()
+
+	overwrite := idx.replace || (idx.savedPath != "" && path != idx.savedPath)
+	for attempt := 1; ; attempt++ {
+		var generation uint64
+		onDisk := readIndexManifest(path)
+		switch {
+		case onDisk == nil:
+			idx.markAllDirty()
+		case overwrite:
+			// Rewrite every shard, and remove those only the old file had
+			idx.markAllDirty()
+			for _, shard := range onDisk.Shards {
+				idx.dirtyShards[shard] = true
+			}
+			generation = onDisk.Generation
+		case idx.savedPath != path || onDisk.Generation != idx.generation:
+			if err := idx.rebase(path); err != nil {
+				return err
+			}
+			generation = idx.generation
+		default:
+			generation = onDisk.Generation
+		}
+
+		if err := idx.writeShards(path); err != nil {
+			return err
+		}
 
-	// Copy data to current index
-	idx.Version = loaded.Version
-	idx.CreatedAt = loaded.CreatedAt
-	idx.UpdatedAt = loaded.UpdatedAt
-	idx.Entries = loaded.Entries
-	idx.ByTag = loaded.ByTag
-	idx.ByConcept = loaded.ByConcept
-	idx.ByLanguage = loaded.ByLanguage
-	idx.ByLayer = loaded.ByLayer
-	idx.Stats = loaded.Stats
+		current := uint64(0)
+		if m := readIndexManifest(path); m != nil {
+			current = m.Generation
+		}
+		if current != generation {
+			if attempt == indexSaveAttempts {
+				return ErrIndexConflict
+			}
+			continue
+		}
 
-	// Initialize maps if nil
-	if idx.Entries == nil {
-		idx.Entries = make(map[string]*IndexEntry)
+		if err := idx.writeManifest(path, generation+1); err != nil {
+			return err
+		}
+		break
 	}
-	if idx.ByTag == nil {
-		idx.ByTag = make(map[string][]string)
+
+	idx.dirtyShards = make(map[string]bool)
+	idx.savedPath = path
+	idx.pending = make(map[string]*IndexEntry)
+	idx.replace = false
+	return nil
+}
+
+// rebase replaces the contents of the index with the index file at path
+// plus the changes made since the last load or save, marking the shards
+// those changes touch dirty (caller must hold lock and the workspace lock).
+// An index file with missing or broken shards is an error rather than
+// overwritten.
+func (idx *Index) rebase(path string) error {
+	manifest, entries, err := readIndexFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to merge into index file: %w", err)
 	}
-	if idx.ByConcept == nil {
-		idx.ByConcept = make(map[string][]string)
+
+	idx.dirtyShards = make(map[string]bool)
+	idx.rekey(entries)
+	if manifest.Entries != nil {
+		idx.markAllDirty()
 	}
-	if idx.ByLanguage == nil {
-		idx.ByLanguage = make(map[string][]string)
+	for key, entry := range idx.pending {
+		if entry == nil {
+			idx.delete(key)
+		} else {
+			idx.put(key, entry)
+		}
+		idx.dirtyShards[shardOf(key)] = true
 	}
-	if idx.ByLayer == nil {
-		idx.ByLayer = make(map[string][]string)
+
+	idx.CreatedAt = manifest.CreatedAt
+	idx.generation = manifest.Generation
+	return nil
+}
+
+// writeShards writes the dirty shards of the index file at path and removes
+// those left empty (caller must hold lock)
+func (idx *Index) writeShards(path string) error {
+	if len(idx.dirtyShards) > 0 {
+		if err := os.MkdirAll(shardDir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create index shard directory: %w", err)
+		}
 	}
 
+	for shard := range idx.dirtyShards {
+		file := shardFile(path, shard)
+		entries, ok := idx.shards[shard]
+		if !ok {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove index shard %q: %w", shard, err)
+			}
+			continue
+		}
+
+		data, err := json.MarshalIndent(indexShard{Entries: entries}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize index shard %q: %w", shard, err)
+		}
+		if err := writeFileAtomic(file, data, 0644); err != nil {
+			return fmt.Errorf("failed to write index shard %q: %w", shard, err)
+		}
+	}
 	return nil
 }
 
-// Save saves the index to a file
-func (idx *Index) Save(path string) error {
-	idx.mu.RLock()
-	defer idx.mu.RUnlock()
+// writeManifest writes the index file listing the shards, as generation
+// (caller must hold lock)
+func (idx *Index) writeManifest(path string, generation uint64) error {
+	manifest := indexManifest{
+		Version:    idx.Version,
+		CreatedAt:  idx.CreatedAt,
+		UpdatedAt:  idx.UpdatedAt,
+		Stats:      idx.Stats,
+		Shards:     make([]string, 0, len(idx.shards)),
+		Generation: generation,
+	}
+	for shard := range idx.shards {
+		manifest.Shards = append(manifest.Shards, shard)
+	}
+	sort.Strings(manifest.Shards)
 
-	data, err := json.MarshalIndent(idx, "", "  ")
+	data, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to serialize index: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	// The manifest is written last so it never lists a shard not yet on disk
+	if err := writeFileAtomic(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write index file: %w", err)
 	}
 
+	idx.generation = generation
 	return nil
 }
 
@@ -454,18 +861,36 @@ func (idx *Index) Clear() {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
+	idx.markAllDirty()
+	idx.reset()
+	idx.pending = make(map[string]*IndexEntry)
+	idx.replace = true
+	idx.UpdatedAt = time.Now()
+}
+
+// reset empties entries, shards, inverted indexes and statistics, keeping
+// the dirty shard set (caller must hold lock)
+func (idx *Index) reset() {
 	idx.Entries = make(map[string]*IndexEntry)
 	idx.ByTag = make(map[string][]string)
 	idx.ByConcept = make(map[string][]string)
 	idx.ByLanguage = make(map[string][]string)
 	idx.ByLayer = make(map[string][]string)
-	idx.Stats = IndexStats{
-		LanguageCount: make(map[string]int),
-		LayerCount:    make(map[string]int),
-		TagCount:      make(map[string]int),
-		ConceptCount:  make(map[string]int),
+	idx.text = newTextIndex(nil)
+	idx.Stats = newIndexStats()
+	idx.shards = make(map[string]map[string]*IndexEntry)
+	if idx.dirtyShards == nil {
+		idx.dirtyShards = make(map[string]bool)
+	}
+	idx.invertedDirty = false
+}
+
+// markAllDirty marks every shard dirty so the next Save writes all of them
+// (caller must hold lock)
+func (idx *Index) markAllDirty() {
+	for shard := range idx.shards {
+		idx.dirtyShards[shard] = true
 	}
-	idx.UpdatedAt = time.Now()
 }
 
 // Count returns the total number of entries
@@ -475,99 +900,123 @@ func (idx *Index) Count() int {
 	return len(idx.Entries)
 }
 
-// addToInvertedIndexes adds entry to all inverted indexes
-func (idx *Index) addToInvertedIndexes(path string, entry *IndexEntry) {
-	// Add to language index
-	if entry.Language != "" {
-		idx.ByLanguage[entry.Language] = append(idx.ByLanguage[entry.Language], path)
+// rlockInverted read-locks the index with the inverted indexes up to date,
+// rebuilding them first if entries changed since the last lookup
+func (idx *Index) rlockInverted() {
+	for {
+		idx.mu.RLock()
+		if !idx.invertedDirty {
+			return
+		}
+		idx.mu.RUnlock()
+
+		idx.mu.Lock()
+		if idx.invertedDirty {
+			idx.rebuildInverted()
+		}
+		idx.mu.Unlock()
 	}
+}
 
-	// Add to layer index
-	if entry.Layer != "" {
-		idx.ByLayer[entry.Layer] = append(idx.ByLayer[entry.Layer], path)
+// rebuildInverted rebuilds all inverted indexes from the entries (caller
+// must hold lock)
+func (idx *Index) rebuildInverted() {
+	idx.ByTag = make(map[string][]string)
+	idx.ByConcept = make(map[string][]string)
+	idx.ByLanguage = make(map[string][]string)
+	idx.ByLayer = make(map[string][]string)
+
+	for _, entry := range idx.Entries {
+		if entry.Language != "" {
+			idx.ByLanguage[entry.Language] = append(idx.ByLanguage[entry.Language], entry.Path)
+		}
+		if entry.Layer != "" {
+			idx.ByLayer[entry.Layer] = append(idx.ByLayer[entry.Layer], entry.Path)
+		}
+		for _, tag := range entry.Tags {
+			idx.ByTag[tag] = append(idx.ByTag[tag], entry.Path)
+		}
+		for _, concept := range entry.Concepts {
+			idx.ByConcept[concept] = append(idx.ByConcept[concept], entry.Path)
+		}
 	}
 
-	// Add to tag indexes
-	for _, tag := range entry.Tags {
-		idx.ByTag[tag] = append(idx.ByTag[tag], path)
+	for _, inverted := range []map[string][]string{idx.ByTag, idx.ByConcept, idx.ByLanguage, idx.ByLayer} {
+		for _, paths := range inverted {
+			sort.Strings(paths)
+		}
 	}
+	idx.text = newTextIndex(idx.Entries)
+	idx.invertedDirty = false
+}
 
-	// Add to concept indexes
-	for _, concept := range entry.Concepts {
-		idx.ByConcept[concept] = append(idx.ByConcept[concept], path)
+// newIndexStats creates empty statistics
+func newIndexStats() IndexStats {
+	return IndexStats{
+		LanguageCount: make(map[string]int),
+		LayerCount:    make(map[string]int),
+		TagCount:      make(map[string]int),
+		ConceptCount:  make(map[string]int),
 	}
 }
 
-// removeFromInvertedIndexes removes entry from all inverted indexes
-func (idx *Index) removeFromInvertedIndexes(path string, entry *IndexEntry) {
-	// Remove from language index
-	if entry.Language != "" {
-		idx.ByLanguage[entry.Language] = removeFromSlice(idx.ByLanguage[entry.Language], path)
-		if len(idx.ByLanguage[entry.Language]) == 0 {
-			delete(idx.ByLanguage, entry.Language)
-		}
+// add adds (delta 1) or subtracts (delta -1) an entry's contribution
+func (s *IndexStats) add(entry *IndexEntry, delta int) {
+	s.TotalEntries += delta
+	s.TotalTriples += delta * entry.TripleCount
+
+	switch entry.Source {
+	case SourceManual:
+		s.ManualEntries += delta
+	case SourceAuto:
+		s.AutoEntries += delta
+	case SourceMixed:
+		s.MixedEntries += delta
 	}
 
-	// Remove from layer index
+	if entry.Language != "" {
+		addCount(s.LanguageCount, entry.Language, delta)
+	}
 	if entry.Layer != "" {
-		idx.ByLayer[entry.Layer] = removeFromSlice(idx.ByLayer[entry.Layer], path)
-		if len(idx.ByLayer[entry.Layer]) == 0 {
-			delete(idx.ByLayer, entry.Layer)
-		}
+		addCount(s.LayerCount, entry.Layer, delta)
 	}
-
-	// Remove from tag indexes
 	for _, tag := range entry.Tags {
-		idx.ByTag[tag] = removeFromSlice(idx.ByTag[tag], path)
-		if len(idx.ByTag[tag]) == 0 {
-			delete(idx.ByTag, tag)
-		}
+		addCount(s.TagCount, tag, delta)
 	}
-
-	// Remove from concept indexes
 	for _, concept := range entry.Concepts {
-		idx.ByConcept[concept] = removeFromSlice(idx.ByConcept[concept], path)
-		if len(idx.ByConcept[concept]) == 0 {
-			delete(idx.ByConcept, concept)
-		}
+		addCount(s.ConceptCount, concept, delta)
 	}
 }
 
-// updateStats recalculates index statistics
-func (idx *Index) updateStats() {
-	idx.Stats = IndexStats{
-		TotalEntries:  len(idx.Entries),
-		LanguageCount: make(map[string]int),
-		LayerCount:    make(map[string]int),
-		TagCount:      make(map[string]int),
-		ConceptCount:  make(map[string]int),
+// addCount adjusts a count, dropping counts that reach zero
+func addCount(counts map[string]int, key string, delta int) {
+	counts[key] += delta
+	if counts[key] <= 0 {
+		delete(counts, key)
 	}
+}
 
-	for _, entry := range idx.Entries {
-		idx.Stats.TotalTriples += entry.TripleCount
+// shardOf returns the shard of an entry key: its top-level directory, or
+// "" for files at the root
+func shardOf(key string) string {
+	if i := strings.IndexByte(key, '/'); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
 
-		switch entry.Source {
-		case SourceManual:
-			idx.Stats.ManualEntries++
-		case SourceAuto:
-			idx.Stats.AutoEntries++
-		case SourceMixed:
-			idx.Stats.MixedEntries++
-		}
+// shardDir returns the directory holding the shards of an index file
+func shardDir(indexPath string) string {
+	return strings.TrimSuffix(indexPath, filepath.Ext(indexPath)) + ".shards"
+}
 
-		if entry.Language != "" {
-			idx.Stats.LanguageCount[entry.Language]++
-		}
-		if entry.Layer != "" {
-			idx.Stats.LayerCount[entry.Layer]++
-		}
-		for _, tag := range entry.Tags {
-			idx.Stats.TagCount[tag]++
-		}
-		for _, concept := range entry.Concepts {
-			idx.Stats.ConceptCount[concept]++
-		}
+// shardFile returns the file of a shard
+func shardFile(indexPath, shard string) string {
+	name := "root.json"
+	if shard != "" {
+		name = "dir-" + url.PathEscape(shard) + ".json"
 	}
+	return filepath.Join(shardDir(indexPath), name)
 }
 
 // filterByText filters results by text query
@@ -576,7 +1025,7 @@ func (idx *Index) filterByText(paths []string, query string) []string {
 	var results []string
 
 	for _, path := range paths {
-		entry := idx.Entries[path]
+		entry := idx.Entries[idx.keys.Key(path)]
 		if entry == nil {
 			continue
 		}
@@ -603,12 +1052,23 @@ func (idx *Index) filterByText(paths []string, query string) []string {
 	return results
 }
 
+// filterByScore filters paths to those with a full-text score
+func filterByScore(paths []string, scores map[string]float64) []string {
+	var results []string
+	for _, path := range paths {
+		if _, ok := scores[path]; ok {
+			results = append(results, path)
+		}
+	}
+	return results
+}
+
 // filterBySource filters results by entry source
 func (idx *Index) filterBySource(paths []string, source EntrySource) []string {
 	var results []string
 
 	for _, path := range paths {
-		entry := idx.Entries[path]
+		entry := idx.Entries[idx.keys.Key(path)]
 		if entry != nil && entry.Source == source {
 			results = append(results, path)
 		}
@@ -622,7 +1082,7 @@ func (idx *Index) filterByManual(paths []string, hasManual bool) []string {
 	var results []string
 
 	for _, path := range paths {
-		entry := idx.Entries[path]
+		entry := idx.Entries[idx.keys.Key(path)]
 		if entry != nil && entry.HasManual == hasManual {
 			results = append(results, path)
 		}
@@ -648,11 +1108,16 @@ func intersect(a, b []string) []string {
 	return result
 }
 
-// removeFromSlice removes a string from a slice
-func removeFromSlice(slice []string, str string) []string {
-	result := make([]string, 0, len(slice))
-	for _, s := range slice {
-		if s != str {
+// union returns the paths in a or b, in order of first appearance
+func union(a, b []string) []string {
+	set := make(map[string]bool, len(a))
+	result := append([]string(nil), a...)
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			set[s] = true
 			result = append(result, s)
 		}
 	}
@@ -0,0 +1,105 @@
+/*
+# Module: pkg/shadow/iri.go
+Base IRI rewrite pass for existing shadow entries.
+
+Shadow entries built before a base IRI was configured (or under a previous
+base) hold fragment-only and relative URIs. RewriteIRIs rewrites them in
+place so stored metadata matches newly built triples.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system
+- [entry](./entry.go) - Shadow entry data structure
+- [../graph](../graph/iri.go) - Base IRI mapping
+
+## Tags
+shadow, rdf, iri, migration
+
+## Exports
+RewriteIRIs
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#iri.go> a code:Module ;
+    code:name "pkg/shadow/iri.go" ;
+    code:description "Base IRI rewrite pass for existing shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <../graph/iri.go> ;
+    code:exports <#RewriteIRIs> ;
+    code:tags "shadow", "rdf", "iri", "migration" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// RewriteIRIs rewrites module URIs and triple subjects and objects in every
+// shadow entry against the mapper's base IRI. Terms minted under fromBase
+// (if set) are moved to the new base. Shadow triples don't record whether an
+// object is a literal, so objects are rewritten whenever they look like a
+// fragment or relative URI. With dryRun nothing is written.
+// It returns the source paths of the entries that changed.
+func (s *ShadowFS) RewriteIRIs(iris *graph.IRIMapper, fromBase string, dryRun bool) ([]string, error) {
+	var changed []string
+
+	err := s.walkEntryFiles(func(path string, entry *Entry) error {
+		if !isShadowFile(path) || !entry.rewriteIRIs(iris, fromBase) {
+			return nil
+		}
+		changed = append(changed, entry.SourcePath)
+		if dryRun {
+			return nil
+		}
+		return s.saveEntryFile(path, entry.SourcePath, entry, "rewrite-iris")
+	})
+	if err != nil {
+		return changed, err
+	}
+
+	if len(changed) > 0 && !dryRun {
+		if err := s.RebuildIndex(); err != nil {
+			return changed, err
+		}
+	}
+
+	return changed, nil
+}
+
+// rewriteIRIs rewrites the entry's URIs and reports whether any changed
+func (e *Entry) rewriteIRIs(iris *graph.IRIMapper, fromBase string) bool {
+	rewrite := func(term string) string {
+		return iris.Resolve(iris.Rebase(term, fromBase), e.SourcePath)
+	}
+
+	changed := false
+	if e.Module != nil && e.Module.URI != "" {
+		if uri := rewrite(e.Module.URI); uri != e.Module.URI {
+			e.Module.URI = uri
+			changed = true
+		}
+	}
+
+	for i := range e.Components {
+		c := &e.Components[i]
+		if uri := rewrite(c.URI); uri != c.URI {
+			c.URI = uri
+			changed = true
+		}
+	}
+
+	for i := range e.Triples {
+		t := &e.Triples[i]
+		subject, object := rewrite(t.Subject), rewrite(t.Object)
+		if subject != t.Subject || object != t.Object {
+			t.Subject, t.Object = subject, object
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,71 @@
+package shadow
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestShadowFSRewriteIRIs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	shadowFS, err := NewShadowFS(tmpDir, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize shadow file system: %v", err)
+	}
+
+	entry := NewAutoEntry("services/user.go")
+	entry.SetModule("<#services/user.go>", "services/user.go", "User service", "go", "service", nil)
+	entry.AddTriple("<#services/user.go>", "https://schema.codedoc.org/linksTo", "../models/user.go", SourceAuto)
+	entry.AddTriple("<#services/user.go>", "https://schema.codedoc.org/description", "User service", SourceAuto)
+	if err := shadowFS.Set(filepath.Join(tmpDir, "services/user.go"), entry); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	iris, err := graph.NewIRIMapper("https://graph.example.com/repo/")
+	if err != nil {
+		t.Fatalf("NewIRIMapper failed: %v", err)
+	}
+
+	changed, err := shadowFS.RewriteIRIs(iris, "", true)
+	if err != nil || len(changed) != 1 {
+		t.Fatalf("Dry run = %v, %v; want one changed entry", changed, err)
+	}
+	unchanged, _ := shadowFS.Get("services/user.go")
+	if unchanged.Module.URI != "<#services/user.go>" {
+		t.Errorf("Dry run should not write, got URI %q", unchanged.Module.URI)
+	}
+
+	if _, err := shadowFS.RewriteIRIs(iris, "", false); err != nil {
+		t.Fatalf("RewriteIRIs failed: %v", err)
+	}
+
+	rewritten, err := shadowFS.Get("services/user.go")
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if rewritten.Module.URI != "<https://graph.example.com/repo/services/user.go>" {
+		t.Errorf("Module URI = %q", rewritten.Module.URI)
+	}
+	if rewritten.Triples[0].Object != "https://graph.example.com/repo/models/user.go" {
+		t.Errorf("linksTo object = %q", rewritten.Triples[0].Object)
+	}
+	if rewritten.Triples[1].Object != "User service" {
+		t.Errorf("Literal object should be unchanged, got %q", rewritten.Triples[1].Object)
+	}
+
+	// Moving to a new base rewrites the previous base's IRIs
+	moved, _ := graph.NewIRIMapper("https://new.example.com/repo/")
+	changed, err = shadowFS.RewriteIRIs(moved, iris.Base(), false)
+	if err != nil || len(changed) != 1 {
+		t.Fatalf("Rebase = %v, %v; want one changed entry", changed, err)
+	}
+	rebased, _ := shadowFS.Get("services/user.go")
+	if rebased.Module.URI != "<https://new.example.com/repo/services/user.go>" {
+		t.Errorf("Rebased module URI = %q", rebased.Module.URI)
+	}
+}
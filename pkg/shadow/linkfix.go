@@ -0,0 +1,90 @@
+/*
+# Module: pkg/shadow/linkfix.go
+Rewrites of unresolved links in LinkedDoc headers.
+
+Turns the fixes of unresolved links into source patches: each relative
+link in a header that resolves to a broken target, in code:linksTo and
+in the Linked Modules section, is replaced by a link to the fix.
+
+## Linked Modules
+- [rename](./rename.go) - Header link rewriting
+- [patch](./patch.go) - Source patches and unified diffs
+- [../graph](../graph/links.go) - Unresolved link detection
+
+## Tags
+shadow, links, refactoring, linkeddoc
+
+## Exports
+PlanLinkFixes
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#linkfix.go> a code:Module ;
+    code:name "pkg/shadow/linkfix.go" ;
+    code:description "Rewrites of unresolved links in LinkedDoc headers" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./rename.go>, <./patch.go>, <../graph/links.go> ;
+    code:exports <#PlanLinkFixes> ;
+    code:tags "shadow", "links", "refactoring", "linkeddoc" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// PlanLinkFixes prepares the header rewrites that point unresolved links
+// at their fix. Links without a fix are left alone, as are links written
+// without a ./ or ../ prefix. Patches are sorted by path.
+func PlanLinkFixes(rootPath string, links []graph.UnresolvedLink) ([]*SourcePatch, error) {
+	fixes := make(map[string]map[string]string) // Module -> target -> fix
+	for _, link := range links {
+		if link.Fix == "" {
+			continue
+		}
+		if fixes[link.Module] == nil {
+			fixes[link.Module] = make(map[string]string)
+		}
+		fixes[link.Module][link.Target] = link.Fix
+	}
+
+	modules := make([]string, 0, len(fixes))
+	for module := range fixes {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	var patches []*SourcePatch
+	for _, module := range modules {
+		original, err := os.ReadFile(filepath.Join(rootPath, filepath.FromSlash(module)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", module, err)
+		}
+
+		dir := filepath.Dir(filepath.FromSlash(module))
+		updated := rewriteHeaderLinks(string(original), module, func(link string) string {
+			fix, ok := fixes[module][pathkey.Canonical(filepath.Join(dir, link))]
+			if !ok {
+				return link
+			}
+			return relativeLink(dir, filepath.FromSlash(fix))
+		})
+
+		patch := &SourcePatch{Path: module, Original: string(original), Updated: updated}
+		if patch.Changed() {
+			patches = append(patches, patch)
+		}
+	}
+	return patches, nil
+}
@@ -0,0 +1,58 @@
+/*
+# Module: pkg/shadow/linkfix_test.go
+Tests for unresolved link rewrites.
+
+## Tags
+shadow, test, links
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#linkfix_test.go> a code:Module ;
+    code:name "pkg/shadow/linkfix_test.go" ;
+    code:description "Tests for unresolved link rewrites" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./linkfix.go> ;
+    code:tags "shadow", "test", "links" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestPlanLinkFixes(t *testing.T) {
+	root, g := setupRenameProject(t)
+
+	// utils/format.go doesn't exist; it was renamed to utils/formatting.go
+	links := []graph.UnresolvedLink{
+		{Module: "services/auth.go", Target: "utils/format.go", Fix: "utils/formatting.go"},
+		{Module: "utils/logger.go", Target: "utils/format.go", Fix: "utils/formatting.go"},
+		{Module: "services/auth.go", Target: "utils/missing.go"}, // No fix
+	}
+	if len(g.UnresolvedLinks()) != 2 {
+		t.Fatalf("Expected two unresolved links, got %+v", g.UnresolvedLinks())
+	}
+
+	patches, err := PlanLinkFixes(root, links)
+	if err != nil {
+		t.Fatalf("PlanLinkFixes failed: %v", err)
+	}
+	if len(patches) != 2 || patches[0].Path != "services/auth.go" || patches[1].Path != "utils/logger.go" {
+		t.Fatalf("Expected patches for both modules, got %+v", patches)
+	}
+
+	if updated := patches[0].Updated; !strings.Contains(updated, "code:linksTo <../utils/formatting.go>, <../utils/logger.go> .") {
+		t.Errorf("Expected linksTo to be rewritten:\n%s", updated)
+	}
+	if updated := patches[1].Updated; !strings.Contains(updated, "- [format](./formatting.go)") || !strings.Contains(updated, "code:linksTo <./formatting.go> .") {
+		t.Errorf("Expected both link forms to be rewritten:\n%s", updated)
+	}
+}
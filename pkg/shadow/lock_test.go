@@ -0,0 +1,294 @@
+package shadow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	lock, err := AcquireLock(tmpDir, 0)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+
+	if _, err := AcquireLock(tmpDir, 0); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked while held, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Errorf("Second Release should be a no-op, got %v", err)
+	}
+
+	again, err := AcquireLock(tmpDir, 0)
+	if err != nil {
+		t.Fatalf("Expected lock after release, got %v", err)
+	}
+	again.Release()
+}
+
+func TestAcquireLockWaits(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	lock, err := AcquireLock(tmpDir, 0)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		lock.Release()
+	}()
+
+	waited, err := AcquireLock(tmpDir, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Expected lock after waiting, got %v", err)
+	}
+	waited.Release()
+
+	held, _ := AcquireLock(tmpDir, 0)
+	defer held.Release()
+	start := time.Now()
+	if _, err := AcquireLock(tmpDir, 200*time.Millisecond); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked after timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected to wait for the timeout, returned after %v", elapsed)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "index.json")
+
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("writeFileAtomic failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("Expected new contents, got %q (%v)", data, err)
+	}
+
+	files, _ := os.ReadDir(tmpDir)
+	if len(files) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d files", len(files))
+	}
+}
+
+func TestIndexSaveMergesConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	base := NewIndex()
+	base.Add("shared/keep.go", NewAutoEntry("shared/keep.go"))
+	base.Add("shared/drop.go", NewAutoEntry("shared/drop.go"))
+	if err := base.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	first := NewIndex()
+	second := NewIndex()
+	if err := first.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := second.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	first.Add("a/one.go", NewAutoEntry("a/one.go"))
+	first.Remove("shared/drop.go")
+	second.Add("b/two.go", NewAutoEntry("b/two.go"))
+
+	if err := first.Save(path); err != nil {
+		t.Fatalf("First save failed: %v", err)
+	}
+	// The second writer loaded before the first saved and must not undo it
+	if err := second.Save(path); err != nil {
+		t.Fatalf("Second save failed: %v", err)
+	}
+
+	loaded := NewIndex()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	for _, p := range []string{"shared/keep.go", "a/one.go", "b/two.go"} {
+		if _, ok := loaded.Get(p); !ok {
+			t.Errorf("Expected %s in the merged index", p)
+		}
+	}
+	if _, ok := loaded.Get("shared/drop.go"); ok {
+		t.Error("Expected the removal to survive the merge")
+	}
+	if stats := loaded.Statistics(); stats.TotalEntries != 3 {
+		t.Errorf("TotalEntries = %d, want 3", stats.TotalEntries)
+	}
+	if loaded.generation != 3 {
+		t.Errorf("generation = %d, want 3", loaded.generation)
+	}
+
+	// A cleared index replaces the file instead of merging into it
+	loaded.Clear()
+	loaded.Add("c/three.go", NewAutoEntry("c/three.go"))
+	if err := loaded.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	replaced := NewIndex()
+	if err := replaced.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if replaced.Count() != 1 {
+		t.Errorf("Expected only the cleared index's entry, got %d entries", replaced.Count())
+	}
+	if _, err := os.Stat(shardFile(path, "a")); !os.IsNotExist(err) {
+		t.Error("Expected shards of the replaced index to be removed")
+	}
+}
+
+func TestIndexSaveConcurrentProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	// Each writer stands in for a separate process with its own index and
+	// its own lock file descriptor
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			idx := NewIndex()
+			p := fmt.Sprintf("dir%d/file.go", i)
+			idx.Add(p, NewAutoEntry(p))
+			errs <- idx.Save(path)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	loaded := NewIndex()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Count() != 8 {
+		t.Errorf("Expected all 8 writers' entries, got %d", loaded.Count())
+	}
+}
+
+func TestIndexLoadRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := os.WriteFile(path, []byte(`{"version": "99.0", "shards": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var versionErr *VersionError
+	if err := NewIndex().Load(path); !errors.As(err, &versionErr) || !versionErr.Newer {
+		t.Errorf("Expected a newer-version error, got %v", err)
+	}
+}
+
+func TestIndexSaveUnderWorkspaceLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "index.json")
+
+	lock, err := AcquireLock(tmpDir, 0)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	defer lock.Release()
+
+	idx := NewIndex()
+	idx.Add("a/one.go", NewAutoEntry("a/one.go"))
+	done := make(chan error, 1)
+	go func() { done <- idx.Save(path) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Save blocked on the workspace lock this process holds")
+	}
+
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("Expected no separate index lock file")
+	}
+}
+
+func TestIndexSaveRejectsBrokenIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	base := NewIndex()
+	base.Add("a/one.go", NewAutoEntry("a/one.go"))
+	if err := base.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	idx := NewIndex()
+	if err := idx.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Another writer saves, then the shard it wrote goes missing
+	base.Add("a/two.go", NewAutoEntry("a/two.go"))
+	if err := base.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := os.Remove(shardFile(path, "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	idx.Add("b/three.go", NewAutoEntry("b/three.go"))
+	if err := idx.Save(path); err == nil {
+		t.Error("Expected an error saving into a broken index")
+	}
+	if m := readIndexManifest(path); m == nil || m.Generation != 2 {
+		t.Error("Expected the broken index to be left in place")
+	}
+}
+
+func TestIndexLockSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Held by another process
+	other, err := acquireLock(tmpDir, 0)
+	if err != nil {
+		t.Fatalf("acquireLock failed: %v", err)
+	}
+	defer other.Release()
+
+	unlocked, err := NewShadowFS(tmpDir, Config{ValidateOnWrite: true, NoLock: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unlocked.SaveIndex(); err != nil {
+		t.Errorf("Expected NoLock to skip the workspace lock, got %v", err)
+	}
+
+	locked, err := NewShadowFS(tmpDir, Config{ValidateOnWrite: true, LockTimeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := locked.SaveIndex(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the configured timeout, waited %v", elapsed)
+	}
+}
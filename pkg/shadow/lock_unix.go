@@ -0,0 +1,50 @@
+//go:build !windows
+
+/*
+# Module: pkg/shadow/lock_unix.go
+File locking on Unix systems.
+
+Takes and releases the advisory workspace lock with flock(2).
+
+## Linked Modules
+- [lock](./lock.go) - Workspace locking
+
+## Tags
+shadow, locking, unix
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#lock_unix.go> a code:Module ;
+    code:name "pkg/shadow/lock_unix.go" ;
+    code:description "File locking on Unix systems" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./lock.go> ;
+    code:tags "shadow", "locking", "unix" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLockFile takes an exclusive lock on file without waiting, returning
+// errWouldBlock if another process holds it
+func tryLockFile(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return errWouldBlock
+	}
+	return err
+}
+
+// unlockFile releases a lock taken by tryLockFile
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,59 @@
+//go:build windows
+
+/*
+# Module: pkg/shadow/lock_windows.go
+File locking on Windows.
+
+Takes and releases the workspace lock with LockFileEx. Windows locks are
+mandatory, so the lock covers a byte far past the end of the file and the
+holder PID at its start stays readable by waiting processes.
+
+## Linked Modules
+- [lock](./lock.go) - Workspace locking
+
+## Tags
+shadow, locking, windows
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#lock_windows.go> a code:Module ;
+    code:name "pkg/shadow/lock_windows.go" ;
+    code:description "File locking on Windows" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./lock.go> ;
+    code:tags "shadow", "locking", "windows" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffsetHigh places the locked byte past any recorded PID
+const lockOffsetHigh = 0x7fffffff
+
+// tryLockFile takes an exclusive lock on file without waiting, returning
+// errWouldBlock if another process holds it
+func tryLockFile(file *os.File) error {
+	ol := &windows.Overlapped{OffsetHigh: lockOffsetHigh}
+	err := windows.LockFileEx(windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errWouldBlock
+	}
+	return err
+}
+
+// unlockFile releases a lock taken by tryLockFile
+func unlockFile(file *os.File) error {
+	ol := &windows.Overlapped{OffsetHigh: lockOffsetHigh}
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, ol)
+}
@@ -0,0 +1,314 @@
+/*
+# Module: pkg/shadow/migrate.go
+Shadow format version negotiation and migration.
+
+Every shadow entry records the format version it was written with.
+LoadEntry reads entries of the current version and of older minor versions
+of the same major version, which only add fields. Entries of an older major
+version, or without a version, must be migrated with Migrate first; entries
+written by a newer graphfs are refused. Migrate upgrades entries one version
+step at a time by applying per-entry transforms to their raw JSON.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [audit](./audit.go) - Audit log
+
+## Tags
+shadow, version, migration
+
+## Exports
+VersionError, CheckVersion, Migration, EntryMigration, MigrateResult, MigrateEntryData, Migrate
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#migrate.go> a code:Module ;
+    code:name "pkg/shadow/migrate.go" ;
+    code:description "Shadow format version negotiation and migration" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./audit.go> ;
+    code:exports <#VersionError>, <#CheckVersion>, <#Migration>, <#EntryMigration>,
+                 <#MigrateResult>, <#MigrateEntryData>, <#Migrate> ;
+    code:tags "shadow", "version", "migration" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// VersionError reports a shadow entry whose format version cannot be read,
+// with guidance on how to resolve it
+type VersionError struct {
+	Path    string // Shadow file, if known
+	Version string // Format version of the entry
+	Newer   bool   // Written by a newer graphfs
+	Invalid bool   // Not a recognized version
+}
+
+func (e *VersionError) Error() string {
+	entry := "shadow entry"
+	if e.Path != "" {
+		entry += " " + e.Path
+	}
+	switch {
+	case e.Invalid:
+		return fmt.Sprintf("%s has unrecognized format version %q (supported: %s); the file may be corrupt or written by another tool", entry, e.Version, ShadowVersion)
+	case e.Newer:
+		return fmt.Sprintf("%s has format version %s, newer than the supported %s; upgrade graphfs to read it", entry, e.Version, ShadowVersion)
+	case e.Version == "":
+		return fmt.Sprintf("%s has no format version; run 'graphfs shadow migrate' to upgrade it to %s", entry, ShadowVersion)
+	default:
+		return fmt.Sprintf("%s has format version %s, older than the supported %s; run 'graphfs shadow migrate' to upgrade it", entry, e.Version, ShadowVersion)
+	}
+}
+
+// formatVersion is a parsed major.minor format version
+type formatVersion struct {
+	major, minor int
+}
+
+// parseFormatVersion parses a format version; entries without one predate
+// versioning and are version 0
+func parseFormatVersion(version string) (formatVersion, bool) {
+	if version == "" {
+		return formatVersion{}, true
+	}
+	majorText, minorText, hasMinor := strings.Cut(version, ".")
+	major, err := strconv.Atoi(majorText)
+	if err != nil || major < 0 {
+		return formatVersion{}, false
+	}
+	minor := 0
+	if hasMinor {
+		if minor, err = strconv.Atoi(minorText); err != nil || minor < 0 {
+			return formatVersion{}, false
+		}
+	}
+	return formatVersion{major, minor}, true
+}
+
+// currentVersion is the parsed ShadowVersion
+var currentVersion, _ = parseFormatVersion(ShadowVersion)
+
+// CheckVersion reports whether entries of a format version can be read. The
+// current version and older minor versions of the current major version are
+// readable; any other version returns a *VersionError.
+func CheckVersion(version string) error {
+	v, ok := parseFormatVersion(version)
+	switch {
+	case !ok:
+		return &VersionError{Version: version, Invalid: true}
+	case v == currentVersion:
+		return nil
+	case v.major > currentVersion.major || (v.major == currentVersion.major && v.minor > currentVersion.minor):
+		return &VersionError{Version: version, Newer: true}
+	case v.major == currentVersion.major:
+		return nil
+	default:
+		return &VersionError{Version: version}
+	}
+}
+
+// Migration transforms raw shadow entries from one format version to the
+// next
+type Migration struct {
+	From        string // Version the transform applies to
+	To          string // Version it produces
+	Description string // What the transform changes
+	Transform   func(entry map[string]interface{}) error
+}
+
+// migrations are the format migrations, each from the version the previous
+// one produces
+var migrations = []Migration{
+	{
+		From:        "0",
+		To:          "1.0",
+		Description: "set the format version, default the entry source to auto and canonicalize the source path",
+		Transform:   migrateUnversioned,
+	},
+}
+
+// migrateUnversioned upgrades an entry written before format versions
+func migrateUnversioned(entry map[string]interface{}) error {
+	if source, _ := entry["source"].(string); source == "" {
+		entry["source"] = string(SourceAuto)
+	}
+	if sourcePath, ok := entry["source_path"].(string); ok {
+		entry["source_path"] = pathkey.Canonical(sourcePath)
+	}
+	return nil
+}
+
+// migrationPath returns the migrations that upgrade version to the current
+// version. Older minor versions of the current major version only need
+// their version updated.
+func migrationPath(version string) ([]Migration, error) {
+	v, ok := parseFormatVersion(version)
+	if !ok {
+		return nil, &VersionError{Version: version, Invalid: true}
+	}
+
+	var steps []Migration
+	for v != currentVersion {
+		if v.major == currentVersion.major && v.minor < currentVersion.minor {
+			return append(steps, Migration{
+				From:        fmt.Sprintf("%d.%d", v.major, v.minor),
+				To:          ShadowVersion,
+				Description: "update the format version",
+			}), nil
+		}
+
+		found := false
+		for _, m := range migrations {
+			if from, _ := parseFormatVersion(m.From); from == v {
+				steps = append(steps, m)
+				v, _ = parseFormatVersion(m.To)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no migration from shadow format version %s to %s", version, ShadowVersion)
+		}
+	}
+	return steps, nil
+}
+
+// MigrateEntryData upgrades the JSON of a shadow entry to the current format
+// version, returning the entry and the migrations applied. Entries written
+// by a newer graphfs return a *VersionError.
+func MigrateEntryData(data []byte) (*Entry, []Migration, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse shadow file: %w", err)
+	}
+
+	version, ok := raw["version"].(string)
+	if !ok && raw["version"] != nil {
+		return nil, nil, &VersionError{Version: fmt.Sprint(raw["version"]), Invalid: true}
+	}
+	var versionErr *VersionError
+	if err := CheckVersion(version); errors.As(err, &versionErr) && (versionErr.Newer || versionErr.Invalid) {
+		return nil, nil, err
+	}
+
+	steps, err := migrationPath(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, step := range steps {
+		if step.Transform != nil {
+			if err := step.Transform(raw); err != nil {
+				return nil, nil, fmt.Errorf("failed to migrate from version %s to %s: %w", step.From, step.To, err)
+			}
+		}
+		raw["version"] = step.To
+	}
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize shadow entry: %w", err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(migrated, &entry); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse migrated shadow entry: %w", err)
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("migrated shadow entry is invalid: %w", err)
+	}
+	return &entry, steps, nil
+}
+
+// EntryMigration describes a shadow entry upgraded to the current format
+type EntryMigration struct {
+	Path  string      // Source path of the entry, or directory with a trailing /
+	From  string      // Format version found
+	Steps []Migration // Migrations applied
+}
+
+// MigrateResult summarizes a migration of the shadow file system
+type MigrateResult struct {
+	Migrated []EntryMigration
+	Current  int             // Entries already at the current version
+	Refused  []*VersionError // Entries written by a newer graphfs or unreadable
+	Errors   []BuildError    // Entries that failed to migrate
+}
+
+// Migrate upgrades every shadow and directory entry to the current format
+// version, recording each rewrite in the audit log, then rebuilds the index.
+// Entries written by a newer graphfs are refused and left untouched. With
+// dryRun set it only reports what would change.
+func (s *ShadowFS) Migrate(dryRun bool) (*MigrateResult, error) {
+	result := &MigrateResult{}
+
+	s.mu.Lock()
+	err := s.store.Walk(func(path string, data []byte) error {
+		var header struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			result.Errors = append(result.Errors, BuildError{Path: path, Message: "failed to parse shadow file", Err: err})
+			return nil
+		}
+		if header.Version == ShadowVersion {
+			result.Current++
+			return nil
+		}
+
+		entry, steps, err := MigrateEntryData(data)
+		if err != nil {
+			var versionErr *VersionError
+			if errors.As(err, &versionErr) {
+				versionErr.Path = path
+				result.Refused = append(result.Refused, versionErr)
+			} else {
+				result.Errors = append(result.Errors, BuildError{Path: path, Message: "failed to migrate", Err: err})
+			}
+			return nil
+		}
+
+		auditPath := s.auditPath(path, entry)
+		result.Migrated = append(result.Migrated, EntryMigration{Path: auditPath, From: header.Version, Steps: steps})
+		if dryRun {
+			return nil
+		}
+
+		// Best effort: the audit diff is against the entry as it was stored
+		var before Entry
+		_ = json.Unmarshal(data, &before)
+		if err := s.writeEntry(path, entry); err != nil {
+			return err
+		}
+		return s.recordWrite("migrate", auditPath, &before, entry)
+	})
+	s.mu.Unlock()
+	if err != nil {
+		return result, fmt.Errorf("failed to walk shadow entries: %w", err)
+	}
+
+	if len(result.Migrated) > 0 && !dryRun {
+		if err := s.RebuildIndex(); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// isVersionError reports whether err is a *VersionError
+func isVersionError(err error) bool {
+	var versionErr *VersionError
+	return errors.As(err, &versionErr)
+}
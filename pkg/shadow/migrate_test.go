@@ -0,0 +1,119 @@
+package shadow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		ok      bool
+		newer   bool
+	}{
+		{ShadowVersion, true, false},
+		{"1", true, false},
+		{"1.7", false, true},
+		{"2.0", false, true},
+		{"0.9", false, false},
+		{"", false, false},
+		{"v1", false, false},
+	}
+	for _, tt := range tests {
+		err := CheckVersion(tt.version)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckVersion(%q) = %v, want ok=%v", tt.version, err, tt.ok)
+			continue
+		}
+		var versionErr *VersionError
+		if err != nil && (!errors.As(err, &versionErr) || versionErr.Newer != tt.newer) {
+			t.Errorf("CheckVersion(%q) = %#v, want newer=%v", tt.version, err, tt.newer)
+		}
+	}
+}
+
+func TestShadowFSMigrate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	shadowFS, err := NewShadowFS(tmpDir, Config{ValidateOnWrite: true})
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize shadow file system: %v", err)
+	}
+
+	write := func(source, content string) string {
+		t.Helper()
+		path := filepath.Join(shadowFS.ShadowPath(), source+ShadowExtension)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		return path
+	}
+
+	// Written before format versions, and by a newer graphfs
+	write("legacy.go", `{"source_path": "./legacy.go", "annotations": [{"key": "owner", "value": "team-a"}]}`)
+	future := write("future.go", `{"version": "3.0", "source_path": "future.go", "source": "manual"}`)
+	if err := shadowFS.Set("current.go", NewManualEntry("current.go")); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	_, err = shadowFS.Get("legacy.go")
+	var versionErr *VersionError
+	if !errors.As(err, &versionErr) || !strings.Contains(err.Error(), "graphfs shadow migrate") {
+		t.Errorf("Loading an unversioned entry should ask for a migration, got %v", err)
+	}
+	if _, err := shadowFS.Get("future.go"); err == nil || !strings.Contains(err.Error(), "upgrade graphfs") {
+		t.Errorf("Loading a newer entry should ask for an upgrade, got %v", err)
+	}
+
+	// Refused entries must not be replaced by other writers
+	if err := shadowFS.Merge("future.go", NewAutoEntry("future.go")); !errors.As(err, &versionErr) {
+		t.Errorf("Merge into a newer entry = %v, want VersionError", err)
+	}
+
+	result, err := shadowFS.Migrate(true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(result.Migrated) != 1 || result.Migrated[0].Path != "legacy.go" || result.Migrated[0].From != "" {
+		t.Errorf("Dry run migrated = %+v", result.Migrated)
+	}
+	if _, err := shadowFS.Get("legacy.go"); err == nil {
+		t.Error("Dry run should not rewrite entries")
+	}
+
+	result, err = shadowFS.Migrate(false)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if len(result.Migrated) != 1 || result.Current != 1 || len(result.Refused) != 1 {
+		t.Errorf("Migrate result = %+v", result)
+	}
+
+	entry, err := shadowFS.Get("legacy.go")
+	if err != nil {
+		t.Fatalf("Failed to get migrated entry: %v", err)
+	}
+	if entry.Version != ShadowVersion || entry.Source != SourceAuto || entry.SourcePath != "legacy.go" {
+		t.Errorf("Migrated entry = version %q, source %q, path %q", entry.Version, entry.Source, entry.SourcePath)
+	}
+	if value, ok := entry.GetAnnotation("owner"); !ok || value != "team-a" {
+		t.Errorf("Migration should keep annotations, got %v", value)
+	}
+	if _, ok := shadowFS.Index().Get("legacy.go"); !ok {
+		t.Error("Migrated entry should be indexed")
+	}
+
+	data, err := os.ReadFile(future)
+	if err != nil || !strings.Contains(string(data), `"3.0"`) {
+		t.Errorf("Newer entry should be left untouched, got %s", data)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+# Module: pkg/shadow/patch.go
+Whole-file source patches with unified diff rendering.
+
+Represents a proposed rewrite of a source file (for example a LinkedDoc
+header updated from shadow metadata), renders it as a unified diff for
+review, and applies it in place on request.
+
+## Linked Modules
+- [push](./push.go) - Shadow to source metadata push
+
+## Tags
+shadow, patch, diff, refactoring
+
+## Exports
+SourcePatch
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#patch.go> a code:Module ;
+    code:name "pkg/shadow/patch.go" ;
+    code:description "Whole-file source patches with unified diff rendering" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./push.go> ;
+    code:exports <#SourcePatch> ;
+    code:tags "shadow", "patch", "diff", "refactoring" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each hunk
+const diffContext = 3
+
+// SourcePatch is a proposed rewrite of a source file
+type SourcePatch struct {
+	Path     string `json:"path"` // Path relative to the project root
+	Original string `json:"-"`
+	Updated  string `json:"-"`
+}
+
+// Changed returns true if the patch modifies the file
+func (p *SourcePatch) Changed() bool {
+	return p.Original != p.Updated
+}
+
+// Apply writes the updated content to the file under rootPath
+func (p *SourcePatch) Apply(rootPath string) error {
+	fullPath := filepath.Join(rootPath, p.Path)
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", p.Path, err)
+	}
+
+	if err := os.WriteFile(fullPath, []byte(p.Updated), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p.Path, err)
+	}
+	return nil
+}
+
+// UnifiedDiff renders the patch as a unified diff
+func (p *SourcePatch) UnifiedDiff() string {
+	if !p.Changed() {
+		return ""
+	}
+
+	a := strings.Split(p.Original, "\n")
+	b := strings.Split(p.Updated, "\n")
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n", filepath.ToSlash(p.Path))
+	fmt.Fprintf(&sb, "+++ b/%s\n", filepath.ToSlash(p.Path))
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context lines of each other
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from := max(start-diffContext, 0)
+		to := min(end+diffContext, len(ops))
+		writeHunk(&sb, ops[from:to])
+		start = to
+	}
+
+	return sb.String()
+}
+
+// diffOp is a single line in an edit script
+type diffOp struct {
+	kind  byte // ' ', '-' or '+'
+	text  string
+	aLine int // 1-based line in the original (for ' ' and '-')
+	bLine int // 1-based line in the update (for ' ' and '+')
+}
+
+// diffLines computes a line edit script using the longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', text: a[i], aLine: i + 1, bLine: j + 1})
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', text: a[i], aLine: i + 1, bLine: j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', text: b[j], aLine: i, bLine: j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+// writeHunk writes one unified diff hunk
+func writeHunk(sb *strings.Builder, ops []diffOp) {
+	aStart, bStart, aCount, bCount := 0, 0, 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			if aStart == 0 {
+				aStart = op.aLine
+			}
+			aCount++
+		}
+		if op.kind != '-' {
+			if bStart == 0 {
+				bStart = op.bLine
+			}
+			bCount++
+		}
+	}
+	if aStart == 0 {
+		aStart = ops[0].aLine
+	}
+	if bStart == 0 {
+		bStart = ops[0].bLine
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+	for _, op := range ops {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.text)
+		sb.WriteByte('\n')
+	}
+}
@@ -0,0 +1,168 @@
+/*
+# Module: pkg/shadow/paths.go
+Shadow path migration.
+
+Shadow entries written on Windows, or before path keys, may store source
+paths with backslashes and live at shadow locations that differ from their
+path key. MigratePaths rewrites them to canonical paths and moves their
+files, merging entries whose paths differ only in case when case folding is
+on.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [../pathkey](../pathkey/pathkey.go) - Canonical path keys
+
+## Tags
+shadow, paths, migration, cross-platform
+
+## Exports
+PathMigration, MigratePaths
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#paths.go> a code:Module ;
+    code:name "pkg/shadow/paths.go" ;
+    code:description "Shadow path migration" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <../pathkey/pathkey.go> ;
+    code:exports <#PathMigration>, <#MigratePaths> ;
+    code:tags "shadow", "paths", "migration", "cross-platform" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// PathMigration describes a shadow entry moved to its canonical path
+type PathMigration struct {
+	From   string // Source path as stored
+	To     string // Canonical source path
+	Merged bool   // Merged into an existing entry with the same path key
+}
+
+// MigratePaths rewrites shadow entries to canonical source paths and moves
+// each file to the location of its path key, then rebuilds the index. With
+// dryRun set it only reports what would change.
+func (s *ShadowFS) MigratePaths(dryRun bool) ([]PathMigration, error) {
+	var migrations []PathMigration
+
+	err := s.walkEntryFiles(func(path string, entry *Entry) error {
+		if !isShadowFile(path) {
+			return nil
+		}
+
+		from := entry.SourcePath
+		if from == "" {
+			// Fall back to the shadow file's location
+			source, err := s.GetSourcePath(path)
+			if err != nil {
+				return nil
+			}
+			from, _ = s.getRelativePath(source)
+		}
+		to := pathkey.Canonical(from)
+
+		target, err := s.GetShadowPath(to)
+		if err != nil {
+			return nil
+		}
+		if entry.SourcePath == to && filepath.Clean(path) == target {
+			return nil
+		}
+
+		migration := PathMigration{From: from, To: to}
+		if filepath.Clean(path) != target && !sameFile(path, target) {
+			if s.store.Exists(target) {
+				migration.Merged = true
+			}
+		}
+		migrations = append(migrations, migration)
+		if dryRun {
+			return nil
+		}
+
+		return s.movePathEntry(path, target, entry, migration)
+	})
+	if err != nil {
+		return migrations, err
+	}
+
+	if len(migrations) > 0 && !dryRun {
+		if err := s.RebuildIndex(); err != nil {
+			return migrations, err
+		}
+	}
+
+	return migrations, nil
+}
+
+// movePathEntry writes entry under its canonical path at target and removes
+// the old shadow file (caller must hold lock)
+func (s *ShadowFS) movePathEntry(path, target string, entry *Entry, migration PathMigration) error {
+	entry.SourcePath = migration.To
+	if entry.Module != nil && entry.Module.Name == migration.From {
+		entry.Module.Name = migration.To
+	}
+
+	if migration.Merged {
+		existing, err := s.loadEntry(target)
+		if err != nil {
+			return fmt.Errorf("failed to load shadow entry for %s: %w", migration.To, err)
+		}
+		merged := existing.Merge(entry, s.config.PreserveManual)
+		if err := s.saveEntryFile(target, migration.To, merged, "migrate-paths"); err != nil {
+			return err
+		}
+		return s.removeShadowFile(path)
+	}
+
+	// Save in place and rename, which also fixes the case of a file name on
+	// case-insensitive filesystems
+	if err := s.saveEntryFile(path, migration.To, entry, "migrate-paths"); err != nil {
+		return err
+	}
+	if filepath.Clean(path) == target {
+		return nil
+	}
+	if err := s.store.Move(path, target); err != nil {
+		return fmt.Errorf("failed to move shadow entry for %s: %w", migration.To, err)
+	}
+	return nil
+}
+
+// removeShadowFile deletes a shadow entry and, in the files layout, its
+// directory if left empty
+func (s *ShadowFS) removeShadowFile(path string) error {
+	if err := s.store.Remove(path); err != nil {
+		return err
+	}
+	if s.layout == LayoutFiles {
+		_ = os.Remove(filepath.Dir(path))
+	}
+	return nil
+}
+
+// sameFile reports whether two paths name the same existing file, as two
+// spellings of a path do on case-insensitive filesystems
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,97 @@
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestShadowFSMigratePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	shadowFS, err := NewShadowFS(tmpDir, Config{ValidateOnWrite: true})
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize shadow file system: %v", err)
+	}
+
+	// An entry written on Windows before path keys
+	legacy := NewAutoEntry("services/user.go")
+	legacy.SourcePath = `services\user.go`
+	legacy.SetModule("<#user.go>", `services\user.go`, "User service", "go", "service", nil)
+	legacyFile := filepath.Join(shadowFS.ShadowPath(), `services\user.go`+ShadowExtension)
+	if err := legacy.Save(legacyFile, true); err != nil {
+		t.Fatalf("Failed to save legacy entry: %v", err)
+	}
+
+	migrations, err := shadowFS.MigratePaths(true)
+	if err != nil || len(migrations) != 1 {
+		t.Fatalf("Dry run = %v, %v; want one migration", migrations, err)
+	}
+	if migrations[0].From != `services\user.go` || migrations[0].To != "services/user.go" {
+		t.Errorf("Migration = %+v", migrations[0])
+	}
+	if _, err := os.Stat(legacyFile); err != nil {
+		t.Errorf("Dry run should not move files: %v", err)
+	}
+
+	if _, err := shadowFS.MigratePaths(false); err != nil {
+		t.Fatalf("MigratePaths failed: %v", err)
+	}
+
+	if _, err := os.Stat(legacyFile); !os.IsNotExist(err) {
+		t.Errorf("Legacy shadow file should be moved, stat err = %v", err)
+	}
+	entry, err := shadowFS.Get(`services\user.go`)
+	if err != nil {
+		t.Fatalf("Failed to get migrated entry: %v", err)
+	}
+	if entry.SourcePath != "services/user.go" || entry.Module.Name != "services/user.go" {
+		t.Errorf("Migrated entry path = %q, name = %q", entry.SourcePath, entry.Module.Name)
+	}
+	if _, ok := shadowFS.Index().Get(`./services\user.go`); !ok {
+		t.Error("Migrated entry should be indexed under its path key")
+	}
+
+	again, err := shadowFS.MigratePaths(false)
+	if err != nil || len(again) != 0 {
+		t.Errorf("Second migration = %v, %v; want none", again, err)
+	}
+}
+
+func TestShadowFSFoldCase(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	shadowFS, err := NewShadowFS(tmpDir, Config{ValidateOnWrite: true, FoldCase: true})
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize shadow file system: %v", err)
+	}
+
+	entry := NewAutoEntry("Services/User.go")
+	entry.SetModule("<#User.go>", "Services/User.go", "User service", "go", "service", []string{"users"})
+	if err := shadowFS.Set("Services/User.go", entry); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	if !shadowFS.Exists(`services\user.go`) {
+		t.Error("Entry should be found under another case and separator")
+	}
+	indexEntry, ok := shadowFS.Index().Get("services/USER.go")
+	if !ok {
+		t.Fatal("Index lookup should fold case")
+	}
+	if indexEntry.Path != "Services/User.go" {
+		t.Errorf("Index path = %q, want the path as written", indexEntry.Path)
+	}
+	if paths := shadowFS.Index().GetByTag("users"); len(paths) != 1 || paths[0] != "Services/User.go" {
+		t.Errorf("GetByTag = %v", paths)
+	}
+	if results := shadowFS.Index().Search(SearchQuery{TextQuery: "user"}); len(results) != 1 {
+		t.Errorf("Search = %v", results)
+	}
+}
@@ -0,0 +1,387 @@
+/*
+# Module: pkg/shadow/prune.go
+Pruning of stale shadow metadata with undo snapshots.
+
+Shadow entries outlive the code they describe: files get deleted, links point
+at modules that were removed, and auto-generated entries stop being refreshed.
+PlanPrune finds such nodes by kind, ApplyPrune removes them after copying the
+affected shadow files into a snapshot, and UndoPrune restores a snapshot.
+
+Kinds:
+  - external: entries whose source disappeared, and dependencies or linksTo
+    triples that point at project paths which no longer exist
+  - inferred: auto-generated entries never confirmed by a manual annotation,
+    and triples with inferred predicates such as code:inferredLayer
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [expiry](./expiry.go) - Shadow entry walking
+
+## Tags
+shadow, prune, cleanup, snapshot
+
+## Exports
+PruneKind, PruneOptions, PruneAction, PrunePlan, ParsePruneKinds, PruneSnapshotDir
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#prune.go> a code:Module ;
+    code:name "pkg/shadow/prune.go" ;
+    code:description "Pruning of stale shadow metadata with undo snapshots" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./expiry.go> ;
+    code:exports <#PruneKind>, <#PruneOptions>, <#PruneAction>, <#PrunePlan>, <#ParsePruneKinds>, <#PruneSnapshotDir> ;
+    code:tags "shadow", "prune", "cleanup", "snapshot" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PruneSnapshotDir holds undo snapshots, relative to the project root
+const PruneSnapshotDir = ".graphfs/snapshots"
+
+// pruneManifestFile describes a snapshot's contents
+const pruneManifestFile = "manifest.json"
+
+// PruneKind selects which stale nodes to prune
+type PruneKind string
+
+const (
+	// PruneExternal removes nodes whose sources or targets disappeared
+	PruneExternal PruneKind = "external"
+
+	// PruneInferred removes inferred metadata that was never confirmed
+	PruneInferred PruneKind = "inferred"
+)
+
+// ParsePruneKinds parses kind names such as "external,inferred"
+func ParsePruneKinds(values []string) ([]PruneKind, error) {
+	var kinds []PruneKind
+	for _, value := range values {
+		switch kind := PruneKind(strings.TrimSpace(value)); kind {
+		case PruneExternal, PruneInferred:
+			kinds = append(kinds, kind)
+		default:
+			return nil, fmt.Errorf("unknown prune kind %q (use external or inferred)", value)
+		}
+	}
+	return kinds, nil
+}
+
+// PruneOptions configures PlanPrune
+type PruneOptions struct {
+	Kinds []PruneKind
+
+	// Before only prunes entries last updated before this time (zero = any age)
+	Before time.Time
+}
+
+// PruneAction is a single removal in a prune plan
+type PruneAction struct {
+	Path   string    `json:"path"`             // Source path of the entry
+	Kind   PruneKind `json:"kind"`             // Kind that matched
+	Target string    `json:"target,omitempty"` // Removed dependency or triple; empty when the entry is removed
+	Reason string    `json:"reason"`
+}
+
+// PrunePlan lists the removals found by PlanPrune
+type PrunePlan struct {
+	Actions []PruneAction
+
+	// Pruned entries keyed by shadow file path; nil entries are deleted
+	files map[string]*Entry
+}
+
+// pruneManifest is written into each snapshot
+type pruneManifest struct {
+	CreatedAt time.Time     `json:"created_at"`
+	Files     []string      `json:"files"` // Shadow files, relative to the shadow directory
+	Actions   []PruneAction `json:"actions"`
+}
+
+// PlanPrune finds stale nodes of the requested kinds without changing
+// anything
+func (s *ShadowFS) PlanPrune(opts PruneOptions) (*PrunePlan, error) {
+	kinds := make(map[PruneKind]bool)
+	for _, kind := range opts.Kinds {
+		kinds[kind] = true
+	}
+
+	plan := &PrunePlan{files: make(map[string]*Entry)}
+	err := s.walkEntryFiles(func(path string, entry *Entry) error {
+		if !opts.Before.IsZero() && !entry.UpdatedAt.Before(opts.Before) {
+			return nil
+		}
+
+		if reason := s.entryPruneReason(entry, kinds); reason != nil {
+			plan.Actions = append(plan.Actions, PruneAction{Path: entry.SourcePath, Kind: reason.kind, Reason: reason.text})
+			plan.files[path] = nil
+			return nil
+		}
+
+		if actions := s.pruneEntryItems(entry, kinds); len(actions) > 0 {
+			plan.Actions = append(plan.Actions, actions...)
+			plan.files[path] = entry
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(plan.Actions, func(i, j int) bool {
+		return plan.Actions[i].Path < plan.Actions[j].Path
+	})
+	return plan, nil
+}
+
+type pruneReason struct {
+	kind PruneKind
+	text string
+}
+
+// entryPruneReason reports why the whole entry should be removed, if at all
+func (s *ShadowFS) entryPruneReason(entry *Entry, kinds map[PruneKind]bool) *pruneReason {
+	if kinds[PruneExternal] && !s.projectPathExists(entry.SourcePath) {
+		return &pruneReason{PruneExternal, "source no longer exists"}
+	}
+	if kinds[PruneInferred] && entry.Source == SourceAuto && len(entry.Annotations) == 0 && !entry.hasManualItems() {
+		return &pruneReason{PruneInferred, "auto-generated and never confirmed"}
+	}
+	return nil
+}
+
+// pruneEntryItems removes stale dependencies and triples from the entry and
+// returns the matching actions
+func (s *ShadowFS) pruneEntryItems(entry *Entry, kinds map[PruneKind]bool) []PruneAction {
+	var actions []PruneAction
+
+	if kinds[PruneExternal] {
+		var kept []Relationship
+		for _, dep := range entry.Dependencies {
+			if isProjectPath(dep.Target) && !s.projectPathExists(dep.Target) {
+				actions = append(actions, PruneAction{Path: entry.SourcePath, Kind: PruneExternal,
+					Target: dep.Type + " " + dep.Target, Reason: "target no longer exists"})
+				continue
+			}
+			kept = append(kept, dep)
+		}
+		entry.Dependencies = kept
+	}
+
+	var kept []Triple
+	for _, t := range entry.Triples {
+		if kinds[PruneExternal] && strings.HasSuffix(t.Predicate, "linksTo") {
+			target := resolveDependencyPath(t.Object, entry.SourcePath)
+			if isProjectPath(target) && !s.projectPathExists(target) {
+				actions = append(actions, PruneAction{Path: entry.SourcePath, Kind: PruneExternal,
+					Target: t.Predicate + " " + t.Object, Reason: "target no longer exists"})
+				continue
+			}
+		}
+		if kinds[PruneInferred] && t.Source != SourceManual && isInferredPredicate(t.Predicate) {
+			actions = append(actions, PruneAction{Path: entry.SourcePath, Kind: PruneInferred,
+				Target: t.Predicate + " " + t.Object, Reason: "inferred and never confirmed"})
+			continue
+		}
+		kept = append(kept, t)
+	}
+	entry.Triples = kept
+
+	return actions
+}
+
+// ApplyPrune snapshots the affected shadow files, applies the plan and
+// rebuilds the index. It returns the snapshot ID to pass to UndoPrune.
+func (s *ShadowFS) ApplyPrune(plan *PrunePlan) (string, error) {
+	if len(plan.files) == 0 {
+		return "", nil
+	}
+
+	id, err := s.writePruneSnapshot(plan)
+	if err != nil {
+		return "", err
+	}
+
+	for path, entry := range plan.files {
+		if entry == nil {
+			if err := s.removeEntryFile(path, s.auditPath(path, nil), "prune"); err != nil {
+				return id, err
+			}
+			continue
+		}
+		if err := s.saveEntryFile(path, s.auditPath(path, entry), entry, "prune"); err != nil {
+			return id, err
+		}
+	}
+
+	if err := s.RebuildIndex(); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+// UndoPrune restores the shadow files saved by a prune snapshot (the latest
+// if id is empty), rebuilds the index and returns the restored snapshot ID.
+// The snapshot is removed once restored.
+func (s *ShadowFS) UndoPrune(id string) (string, error) {
+	if id == "" {
+		snapshots, err := s.PruneSnapshots()
+		if err != nil {
+			return "", err
+		}
+		if len(snapshots) == 0 {
+			return "", fmt.Errorf("no prune snapshots to undo")
+		}
+		id = snapshots[len(snapshots)-1]
+	}
+
+	snapshotPath := filepath.Join(s.rootPath, PruneSnapshotDir, id)
+	data, err := os.ReadFile(filepath.Join(snapshotPath, pruneManifestFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to read prune snapshot %s: %w", id, err)
+	}
+	var manifest pruneManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse prune snapshot %s: %w", id, err)
+	}
+
+	for _, rel := range manifest.Files {
+		data, err := os.ReadFile(filepath.Join(snapshotPath, rel))
+		if err != nil {
+			return "", fmt.Errorf("failed to read snapshot file: %w", err)
+		}
+		target := filepath.Join(s.shadowPath, rel)
+		before, _ := s.loadEntry(target)
+		if err := s.store.Write(target, data); err != nil {
+			return "", fmt.Errorf("failed to restore shadow file: %w", err)
+		}
+		after, _ := s.loadEntry(target)
+		if err := s.recordWrite("undo-prune", s.auditPath(target, after), before, after); err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.RebuildIndex(); err != nil {
+		return id, err
+	}
+	if err := os.RemoveAll(snapshotPath); err != nil {
+		return id, fmt.Errorf("failed to remove prune snapshot: %w", err)
+	}
+	return id, nil
+}
+
+// PruneSnapshots returns the IDs of the available prune snapshots, oldest
+// first
+func (s *ShadowFS) PruneSnapshots() ([]string, error) {
+	dirEntries, err := os.ReadDir(filepath.Join(s.rootPath, PruneSnapshotDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prune snapshots: %w", err)
+	}
+
+	var ids []string
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() && strings.HasPrefix(dirEntry.Name(), "prune-") {
+			ids = append(ids, dirEntry.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// writePruneSnapshot copies the shadow files a plan touches into a new
+// snapshot directory
+func (s *ShadowFS) writePruneSnapshot(plan *PrunePlan) (string, error) {
+	now := time.Now()
+	id := "prune-" + now.UTC().Format("20060102T150405.000Z")
+	snapshotPath := filepath.Join(s.rootPath, PruneSnapshotDir, id)
+
+	manifest := pruneManifest{CreatedAt: now, Actions: plan.Actions}
+	for path := range plan.files {
+		rel, err := filepath.Rel(s.shadowPath, path)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve shadow file: %w", err)
+		}
+		data, err := s.store.Read(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read shadow file: %w", err)
+		}
+
+		target := filepath.Join(snapshotPath, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write snapshot file: %w", err)
+		}
+		manifest.Files = append(manifest.Files, rel)
+	}
+	sort.Strings(manifest.Files)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize snapshot manifest: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(snapshotPath, pruneManifestFile), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+
+	return id, nil
+}
+
+// projectPathExists reports whether a project-relative path exists
+func (s *ShadowFS) projectPathExists(relPath string) bool {
+	_, err := os.Stat(filepath.Join(s.rootPath, relPath))
+	return err == nil
+}
+
+// hasManualItems reports whether any relationship or triple was added by hand
+func (e *Entry) hasManualItems() bool {
+	for _, dep := range e.Dependencies {
+		if dep.Source == SourceManual {
+			return true
+		}
+	}
+	for _, t := range e.Triples {
+		if t.Source == SourceManual {
+			return true
+		}
+	}
+	return false
+}
+
+// isProjectPath reports whether a dependency target names a file in the
+// project rather than a symbol or an absolute IRI
+func isProjectPath(target string) bool {
+	if target == "" || strings.HasPrefix(target, "#") || strings.Contains(target, "://") {
+		return false
+	}
+	return filepath.Ext(target) != "" && !strings.HasPrefix(target, "..")
+}
+
+// isInferredPredicate reports whether a predicate records a guessed value,
+// such as code:inferredLayer
+func isInferredPredicate(predicate string) bool {
+	local := predicate
+	if i := strings.LastIndexAny(local, "/#:"); i >= 0 {
+		local = local[i+1:]
+	}
+	return strings.HasPrefix(local, "inferred")
+}
@@ -0,0 +1,96 @@
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestShadowFSPrune(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	shadowFS, err := NewShadowFS(tmpDir, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize shadow file system: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "api.go"), []byte("package api\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Source exists, but one link target was deleted
+	api := NewManualEntry("api.go")
+	api.AddDependency("linksTo", "models.go", SourceAuto)
+	api.AddTriple("<#api.go>", "https://schema.codedoc.org/linksTo", "./models.go", SourceAuto)
+	api.AddTriple("<#api.go>", "https://schema.codedoc.org/inferredLayer", "api", SourceAuto)
+	api.AddAnnotation("owner", "team-api", "")
+	if err := shadowFS.Set(filepath.Join(tmpDir, "api.go"), api); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	// Source was deleted
+	old := NewAutoEntry("old.go")
+	if err := shadowFS.Set(filepath.Join(tmpDir, "old.go"), old); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	// Entries updated after the cutoff are kept
+	plan, err := shadowFS.PlanPrune(PruneOptions{Kinds: []PruneKind{PruneExternal}, Before: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("PlanPrune() error = %v", err)
+	}
+	if len(plan.Actions) != 0 {
+		t.Errorf("Expected no actions for recent entries, got %v", plan.Actions)
+	}
+
+	plan, err = shadowFS.PlanPrune(PruneOptions{Kinds: []PruneKind{PruneExternal, PruneInferred}})
+	if err != nil {
+		t.Fatalf("PlanPrune() error = %v", err)
+	}
+	if len(plan.Actions) != 4 {
+		t.Fatalf("Expected 4 actions, got %v", plan.Actions)
+	}
+
+	id, err := shadowFS.ApplyPrune(plan)
+	if err != nil {
+		t.Fatalf("ApplyPrune() error = %v", err)
+	}
+	if shadowFS.Exists(filepath.Join(tmpDir, "old.go")) {
+		t.Error("Expected entry for deleted source to be removed")
+	}
+	pruned, err := shadowFS.Get(filepath.Join(tmpDir, "api.go"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if len(pruned.Dependencies) != 0 || len(pruned.Triples) != 0 || len(pruned.Annotations) != 1 {
+		t.Errorf("Unexpected entry after prune: %d deps, %d triples, %d annotations",
+			len(pruned.Dependencies), len(pruned.Triples), len(pruned.Annotations))
+	}
+
+	restored, err := shadowFS.UndoPrune("")
+	if err != nil {
+		t.Fatalf("UndoPrune() error = %v", err)
+	}
+	if restored != id {
+		t.Errorf("UndoPrune restored %s, want %s", restored, id)
+	}
+	if !shadowFS.Exists(filepath.Join(tmpDir, "old.go")) {
+		t.Error("Expected undo to restore deleted entry")
+	}
+	entry, err := shadowFS.Get(filepath.Join(tmpDir, "api.go"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if len(entry.Dependencies) != 1 || len(entry.Triples) != 2 {
+		t.Errorf("Expected undo to restore dependencies and triples, got %d and %d",
+			len(entry.Dependencies), len(entry.Triples))
+	}
+
+	if snapshots, _ := shadowFS.PruneSnapshots(); len(snapshots) != 0 {
+		t.Errorf("Expected snapshot to be removed after undo, got %v", snapshots)
+	}
+}
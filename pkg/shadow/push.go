@@ -0,0 +1,297 @@
+/*
+# Module: pkg/shadow/push.go
+Push curated shadow metadata back into LinkedDoc headers.
+
+Renders selected shadow metadata (tags, layer, owner) into a source file's
+LinkedDoc block so metadata curated in the shadow file system can graduate
+into in-file truth. Produces a SourcePatch for review; nothing is written
+unless the patch is applied.
+
+## Linked Modules
+- [patch](./patch.go) - Source patches and unified diffs
+- [entry](./entry.go) - Shadow entry data structure
+- [effective](./effective.go) - Effective metadata resolution
+- [../parser](../parser/comments.go) - LinkedDoc comment styles
+
+## Tags
+shadow, linkeddoc, sync, refactoring
+
+## Exports
+PushField, PushValues, PushValuesFromEntry, PushValuesFromEffective, ProposePushToSource
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#push.go> a code:Module ;
+    code:name "pkg/shadow/push.go" ;
+    code:description "Push curated shadow metadata back into LinkedDoc headers" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./patch.go>, <./entry.go>, <./effective.go>, <../parser/comments.go> ;
+    code:exports <#PushField>, <#PushValues>, <#PushValuesFromEntry>, <#PushValuesFromEffective>,
+                 <#ProposePushToSource> ;
+    code:tags "shadow", "linkeddoc", "sync", "refactoring" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/parser"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+// PushField names a metadata field that can be pushed to source
+type PushField string
+
+const (
+	PushTags  PushField = "tags"
+	PushLayer PushField = "layer"
+	PushOwner PushField = "owner"
+)
+
+// commentStyleFor returns the LinkedDoc comment style of a file's language
+func commentStyleFor(path string) parser.CommentStyle {
+	return parser.CommentStyleFor(scanner.DetectLanguageKey(path))
+}
+
+// quotedLiteralRegex matches a quoted RDF literal
+var quotedLiteralRegex = regexp.MustCompile(`"([^"]*)"`)
+
+// PushValues is the metadata to render into a LinkedDoc block
+type PushValues struct {
+	Tags  []string
+	Layer string
+	Owner string
+}
+
+// PushValuesFromEntry takes values from a file's own shadow entry
+func PushValuesFromEntry(entry *Entry) PushValues {
+	var values PushValues
+	if entry.Module != nil {
+		values.Tags = entry.Module.Tags
+		values.Layer = entry.Module.Layer
+	}
+	for _, a := range entry.ActiveAnnotations(time.Now()) {
+		if a.Key == string(PushOwner) {
+			values.Owner = fmt.Sprintf("%v", a.Value)
+		}
+	}
+	return values
+}
+
+// PushValuesFromEffective takes values from resolved effective metadata,
+// including values inherited from directories and workspace defaults
+func PushValuesFromEffective(meta *EffectiveMetadata) PushValues {
+	values := PushValues{
+		Tags:  meta.Tags,
+		Layer: meta.Layer,
+	}
+	if owner, ok := meta.Annotations[string(PushOwner)]; ok {
+		values.Owner = fmt.Sprintf("%v", owner)
+	}
+	return values
+}
+
+// ProposePushToSource renders the selected fields into the LinkedDoc block
+// of rootPath/relPath. Tags are merged with existing tags; layer and owner
+// replace existing values. Returns an error if the file has no LinkedDoc
+// module block. The file is not modified.
+func ProposePushToSource(rootPath, relPath string, values PushValues, fields []PushField) (*SourcePatch, error) {
+	content, err := os.ReadFile(filepath.Join(rootPath, relPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	block, err := findModuleBlock(lines, commentStyleFor(relPath))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", relPath, err)
+	}
+
+	for _, field := range fields {
+		switch field {
+		case PushTags:
+			lines = block.mergeTags(lines, values.Tags)
+		case PushLayer:
+			lines = block.setLiteral(lines, "code:layer", values.Layer)
+		case PushOwner:
+			lines = block.setLiteral(lines, "code:owner", values.Owner)
+		default:
+			return nil, fmt.Errorf("unknown field: %s (use: tags, layer, owner)", field)
+		}
+	}
+
+	return &SourcePatch{
+		Path:     relPath,
+		Original: string(content),
+		Updated:  strings.Join(lines, "\n"),
+	}, nil
+}
+
+// moduleBlock locates the code:Module statement inside a LinkedDoc block
+type moduleBlock struct {
+	start int // Line with "a code:Module"
+	end   int // Line terminating the statement with "."
+	style parser.CommentStyle
+}
+
+// findModuleBlock finds the module statement in a LinkedDoc block
+func findModuleBlock(lines []string, style parser.CommentStyle) (*moduleBlock, error) {
+	inBlock := false
+	block := &moduleBlock{start: -1, end: -1, style: style}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.Contains(line, style.StartMarker):
+			inBlock = true
+		case strings.Contains(line, style.EndMarker):
+			inBlock = false
+		case !inBlock:
+			continue
+		case block.start == -1 && strings.Contains(line, "a code:Module"):
+			block.start = i
+			if strings.HasSuffix(trimmed, ".") {
+				block.end = i
+				return block, nil
+			}
+		case block.start != -1 && strings.HasSuffix(trimmed, "."):
+			block.end = i
+			return block, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no LinkedDoc code:Module block found")
+}
+
+// predicateLine returns the first line in the block containing the predicate
+func (b *moduleBlock) predicateLine(lines []string, predicate string) int {
+	for i := b.start; i <= b.end; i++ {
+		if strings.Contains(lines[i], predicate+" ") {
+			return i
+		}
+	}
+	return -1
+}
+
+// setLiteral replaces or inserts a single-valued literal predicate
+func (b *moduleBlock) setLiteral(lines []string, predicate, value string) []string {
+	if value == "" {
+		return lines
+	}
+
+	if i := b.predicateLine(lines, predicate); i != -1 {
+		idx := strings.Index(lines[i], predicate)
+		rest := lines[i][idx+len(predicate):]
+		replaced := quotedLiteralRegex.ReplaceAllLiteralString(rest, fmt.Sprintf("%q", value))
+		lines[i] = lines[i][:idx+len(predicate)] + replaced
+		return lines
+	}
+
+	return b.insert(lines, fmt.Sprintf("%s %q", predicate, value))
+}
+
+// mergeTags adds tags missing from code:tags and from the "## Tags" section
+func (b *moduleBlock) mergeTags(lines []string, tags []string) []string {
+	if len(tags) == 0 {
+		return lines
+	}
+
+	start := b.predicateLine(lines, "code:tags")
+	if start == -1 {
+		quoted := make([]string, len(tags))
+		for i, tag := range tags {
+			quoted[i] = fmt.Sprintf("%q", tag)
+		}
+		lines = b.insert(lines, "code:tags "+strings.Join(quoted, ", "))
+		return b.mergeMarkdownTags(lines, tags)
+	}
+
+	// The tag list may continue over several lines until ";" or "."
+	end := start
+	for end < b.end && !strings.HasSuffix(strings.TrimSpace(lines[end]), ";") {
+		end++
+	}
+
+	var existing []string
+	for i := start; i <= end; i++ {
+		for _, match := range quotedLiteralRegex.FindAllStringSubmatch(lines[i], -1) {
+			existing = append(existing, match[1])
+		}
+	}
+
+	var missing []string
+	for _, tag := range tags {
+		if !slices.Contains(existing, tag) && !slices.Contains(missing, tag) {
+			missing = append(missing, fmt.Sprintf("%q", tag))
+		}
+	}
+	if len(missing) > 0 {
+		body := strings.TrimRight(lines[end], " \t;.")
+		terminator := lines[end][len(body):]
+		lines[end] = body + ", " + strings.Join(missing, ", ") + terminator
+	}
+
+	return b.mergeMarkdownTags(lines, tags)
+}
+
+// insert adds a predicate line at the end of the module statement
+func (b *moduleBlock) insert(lines []string, statement string) []string {
+	last := lines[b.end]
+	trimmed := strings.TrimRight(last, " \t")
+	lines[b.end] = strings.TrimSuffix(strings.TrimSuffix(trimmed, "."), " ") + " ;"
+
+	indent := "    "
+	if b.end > b.start {
+		indent = last[:len(last)-len(strings.TrimLeft(last, " \t"))]
+	}
+
+	newLine := indent + statement + " ."
+	lines = append(lines[:b.end+1], append([]string{newLine}, lines[b.end+1:]...)...)
+	b.end++
+	return lines
+}
+
+// mergeMarkdownTags appends missing tags to the line after "## Tags", if present
+func (b *moduleBlock) mergeMarkdownTags(lines []string, tags []string) []string {
+	for i, line := range lines {
+		if strings.Contains(line, b.style.StartMarker) {
+			return lines
+		}
+		if strings.TrimSpace(line) != "## Tags" || i+1 >= len(lines) {
+			continue
+		}
+
+		tagLine := lines[i+1]
+		trimmed := strings.TrimLeft(tagLine, " \t*")
+		prefix := tagLine[:len(tagLine)-len(trimmed)]
+
+		var existing []string
+		for _, part := range strings.Split(trimmed, ",") {
+			if value := strings.TrimSpace(part); value != "" {
+				existing = append(existing, value)
+			}
+		}
+		merged := existing
+		for _, tag := range tags {
+			if !slices.Contains(merged, tag) {
+				merged = append(merged, tag)
+			}
+		}
+		if len(merged) != len(existing) {
+			lines[i+1] = prefix + strings.Join(merged, ", ")
+		}
+		return lines
+	}
+	return lines
+}
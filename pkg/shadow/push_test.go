@@ -0,0 +1,130 @@
+/*
+# Module: pkg/shadow/push_test.go
+Tests for pushing shadow metadata into LinkedDoc headers.
+
+## Tags
+shadow, test, linkeddoc
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#push_test.go> a code:Module ;
+    code:name "pkg/shadow/push_test.go" ;
+    code:description "Tests for pushing shadow metadata into LinkedDoc headers" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./push.go>, <./patch.go> ;
+    code:tags "shadow", "test", "linkeddoc" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const pushTestSource = `/*
+# Module: api/handler.go
+Request handlers.
+
+## Tags
+api, http
+
+<!-- LinkedDoc RDF -->
+<#handler.go> a code:Module ;
+    code:name "api/handler.go" ;
+    code:layer "service" ;
+    code:tags "api", "http" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package api
+`
+
+func writePushSource(t *testing.T, content string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "api"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "api", "handler.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	return root
+}
+
+func TestProposePushToSource(t *testing.T) {
+	root := writePushSource(t, pushTestSource)
+
+	values := PushValues{Tags: []string{"http", "public"}, Layer: "api", Owner: "team-web"}
+	patch, err := ProposePushToSource(root, "api/handler.go", values,
+		[]PushField{PushTags, PushLayer, PushOwner})
+	if err != nil {
+		t.Fatalf("ProposePushToSource failed: %v", err)
+	}
+	if !patch.Changed() {
+		t.Fatal("Expected patch to change the file")
+	}
+
+	for _, want := range []string{
+		"api, http, public\n",
+		`    code:layer "api" ;`,
+		`    code:tags "api", "http", "public" ;`,
+		`    code:owner "team-web" .`,
+	} {
+		if !strings.Contains(patch.Updated, want) {
+			t.Errorf("Expected updated source to contain %q:\n%s", want, patch.Updated)
+		}
+	}
+
+	diff := patch.UnifiedDiff()
+	if !strings.HasPrefix(diff, "--- a/api/handler.go\n+++ b/api/handler.go\n@@ ") {
+		t.Errorf("Unexpected diff header:\n%s", diff)
+	}
+	if !strings.Contains(diff, `-    code:layer "service" ;`) || !strings.Contains(diff, `+    code:layer "api" ;`) {
+		t.Errorf("Expected layer change in diff:\n%s", diff)
+	}
+
+	// Proposing does not modify the file; applying does
+	content, _ := os.ReadFile(filepath.Join(root, "api", "handler.go"))
+	if string(content) != pushTestSource {
+		t.Error("Expected source to be unchanged before Apply")
+	}
+	if err := patch.Apply(root); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	content, _ = os.ReadFile(filepath.Join(root, "api", "handler.go"))
+	if string(content) != patch.Updated {
+		t.Error("Expected source to match patch after Apply")
+	}
+}
+
+func TestProposePushToSource_NoChange(t *testing.T) {
+	root := writePushSource(t, pushTestSource)
+
+	patch, err := ProposePushToSource(root, "api/handler.go",
+		PushValues{Tags: []string{"api"}, Layer: "service"}, []PushField{PushTags, PushLayer, PushOwner})
+	if err != nil {
+		t.Fatalf("ProposePushToSource failed: %v", err)
+	}
+	if patch.Changed() || patch.UnifiedDiff() != "" {
+		t.Errorf("Expected no change, got diff:\n%s", patch.UnifiedDiff())
+	}
+}
+
+func TestProposePushToSource_NoLinkedDoc(t *testing.T) {
+	root := writePushSource(t, "package api\n")
+
+	if _, err := ProposePushToSource(root, "api/handler.go", PushValues{Layer: "api"}, []PushField{PushLayer}); err == nil {
+		t.Error("Expected error for file without LinkedDoc block")
+	}
+	if _, err := ProposePushToSource(root, "api/handler.go", PushValues{}, []PushField{"bogus"}); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
@@ -0,0 +1,359 @@
+/*
+# Module: pkg/shadow/rename.go
+Graph-aware file rename support.
+
+Plans and applies a file move: rewrites every relative IRI that resolves
+to the moved file in dependents' LinkedDoc headers (linksTo, symbol
+references such as code:calls <../utils/logger.go#Info>, and Linked Modules
+links), rebases the moved file's own relative links, moves its shadow entry
+and records an alias from the old path to the new one.
+
+## Linked Modules
+- [patch](./patch.go) - Source patches and unified diffs
+- [shadow](./shadow.go) - Shadow file system manager
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+shadow, rename, refactoring, linkeddoc
+
+## Exports
+RenamePlan, PlanRename, AliasesFile, MoveEntry, RecordAlias, Aliases, ResolveAlias
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#rename.go> a code:Module ;
+    code:name "pkg/shadow/rename.go" ;
+    code:description "Graph-aware file rename support" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./patch.go>, <./shadow.go>, <../graph/graph.go> ;
+    code:exports <#RenamePlan>, <#PlanRename>, <#AliasesFile>, <#MoveEntry>,
+                 <#RecordAlias>, <#Aliases>, <#ResolveAlias> ;
+    code:tags "shadow", "rename", "refactoring", "linkeddoc" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// AliasesFile records renamed paths, stored in the shadow directory
+const AliasesFile = "aliases.json"
+
+var (
+	// rdfLinkRegex matches a relative RDF link such as <../pkg/file.go> or
+	// <../pkg/file.go#Symbol>, capturing the path and the fragment
+	rdfLinkRegex = regexp.MustCompile(`<(\.\.?/[^>#]*)(#[^>]*)?>`)
+
+	// markdownLinkRegex matches a relative markdown link such as ](./file.go)
+	// or ](./file.go#section), capturing the path and the fragment
+	markdownLinkRegex = regexp.MustCompile(`\]\((\.\.?/[^)#]*)(#[^)]*)?\)`)
+)
+
+// RenamePlan describes the source changes needed to move a file
+type RenamePlan struct {
+	OldPath    string         // Previous path relative to the project root
+	NewPath    string         // New path relative to the project root
+	Moved      *SourcePatch   // The moved file, with Path set to NewPath
+	Dependents []*SourcePatch // Dependents whose links change
+}
+
+// PlanRename prepares the source changes for moving oldPath to newPath.
+// Dependents are found in the graph by their resolved linksTo paths and by
+// the relative IRIs in their triples. The file may still be at oldPath or
+// may already have been moved to newPath.
+func PlanRename(g *graph.Graph, rootPath, oldPath, newPath string) (*RenamePlan, error) {
+	oldPath = filepath.Clean(oldPath)
+	newPath = filepath.Clean(newPath)
+	if oldPath == newPath {
+		return nil, fmt.Errorf("old and new paths are the same: %s", oldPath)
+	}
+
+	content, err := os.ReadFile(filepath.Join(rootPath, oldPath))
+	if os.IsNotExist(err) {
+		content, err = os.ReadFile(filepath.Join(rootPath, newPath))
+	} else if err == nil {
+		if _, statErr := os.Stat(filepath.Join(rootPath, newPath)); statErr == nil {
+			return nil, fmt.Errorf("destination already exists: %s", newPath)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", oldPath, err)
+	}
+
+	plan := &RenamePlan{
+		OldPath: oldPath,
+		NewPath: newPath,
+		Moved: &SourcePatch{
+			Path:     newPath,
+			Original: string(content),
+			Updated:  rebaseMovedFile(string(content), oldPath, newPath),
+		},
+	}
+
+	var dependents []string
+	for path, module := range g.Modules {
+		if path == oldPath || path == newPath {
+			continue
+		}
+		if referencesPath(g, module, oldPath) {
+			dependents = append(dependents, path)
+		}
+	}
+	sort.Strings(dependents)
+
+	for _, path := range dependents {
+		original, err := os.ReadFile(filepath.Join(rootPath, path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		dir := filepath.Dir(path)
+		updated := rewriteHeaderLinks(string(original), path, func(link string) string {
+			if filepath.Join(dir, link) != oldPath {
+				return link
+			}
+			return relativeLink(dir, newPath)
+		})
+
+		patch := &SourcePatch{Path: path, Original: string(original), Updated: updated}
+		if patch.Changed() {
+			plan.Dependents = append(plan.Dependents, patch)
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply writes the dependents and the moved file, removes the old file,
+// and moves the shadow entry (recording an alias) when shadowFS is not nil
+func (p *RenamePlan) Apply(rootPath string, shadowFS *ShadowFS) error {
+	for _, patch := range p.Dependents {
+		if err := patch.Apply(rootPath); err != nil {
+			return err
+		}
+	}
+
+	oldFull := filepath.Join(rootPath, p.OldPath)
+	newFull := filepath.Join(rootPath, p.NewPath)
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(oldFull); err == nil {
+		mode = info.Mode().Perm()
+	} else if info, err := os.Stat(newFull); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newFull), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", p.NewPath, err)
+	}
+	if err := os.WriteFile(newFull, []byte(p.Moved.Updated), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p.NewPath, err)
+	}
+	if err := os.Remove(oldFull); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", p.OldPath, err)
+	}
+
+	if shadowFS == nil {
+		return nil
+	}
+	if err := shadowFS.MoveEntry(p.OldPath, p.NewPath); err != nil {
+		return err
+	}
+	return shadowFS.RecordAlias(p.OldPath, p.NewPath)
+}
+
+// referencesPath reports whether a module links to target or names it in a
+// relative IRI of one of its triples, such as <../utils/logger.go#Info>
+func referencesPath(g *graph.Graph, module *graph.Module, target string) bool {
+	for _, dep := range module.Dependencies {
+		if filepath.Clean(dep) == target {
+			return true
+		}
+	}
+
+	dir := filepath.Dir(module.Path)
+	for _, triple := range g.FileTriples(module.Path) {
+		for _, term := range []string{triple.Subject, triple.Object} {
+			ref, _, _ := strings.Cut(strings.Trim(term, "<>"), "#")
+			if (strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../")) &&
+				filepath.Join(dir, filepath.FromSlash(ref)) == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rebaseMovedFile updates the moved file's own header: module name, subject
+// URI and relative links, which are now resolved from the new directory
+func rebaseMovedFile(content, oldPath, newPath string) string {
+	oldDir, newDir := filepath.Dir(oldPath), filepath.Dir(newPath)
+
+	updated := rewriteHeaderLinks(content, newPath, func(link string) string {
+		if oldDir == newDir {
+			return link
+		}
+		return relativeLink(newDir, filepath.Join(oldDir, link))
+	})
+
+	oldSlash, newSlash := filepath.ToSlash(oldPath), filepath.ToSlash(newPath)
+	oldBase, newBase := filepath.Base(oldPath), filepath.Base(newPath)
+
+	endMarker := commentStyleFor(newPath).EndMarker
+	lines := strings.Split(updated, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, endMarker) {
+			break
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "# Module:") {
+			lines[i] = strings.Replace(line, oldSlash, newSlash, 1)
+		}
+		if strings.Contains(line, "code:name") {
+			lines[i] = strings.Replace(line, fmt.Sprintf("%q", oldSlash), fmt.Sprintf("%q", newSlash), 1)
+		}
+		if oldBase != newBase && strings.Contains(line, "a code:Module") {
+			lines[i] = strings.Replace(line, "<#"+oldBase+">", "<#"+newBase+">", 1)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rewriteHeaderLinks applies fn to the path of every relative link in the
+// header of the file at path, up to the end of the LinkedDoc block. Fragments
+// such as #Info are kept.
+func rewriteHeaderLinks(content, path string, fn func(link string) string) string {
+	end := strings.Index(content, commentStyleFor(path).EndMarker)
+	if end == -1 {
+		return content
+	}
+
+	header := content[:end]
+	header = rdfLinkRegex.ReplaceAllStringFunc(header, func(match string) string {
+		parts := rdfLinkRegex.FindStringSubmatch(match)
+		return "<" + fn(parts[1]) + parts[2] + ">"
+	})
+	header = markdownLinkRegex.ReplaceAllStringFunc(header, func(match string) string {
+		parts := markdownLinkRegex.FindStringSubmatch(match)
+		return "](" + fn(parts[1]) + parts[2] + ")"
+	})
+
+	return header + content[end:]
+}
+
+// relativeLink returns a LinkedDoc-style relative link from dir to target
+func relativeLink(dir, target string) string {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// MoveEntry moves the shadow entry for oldPath to newPath and updates the
+// index. It does nothing if oldPath has no shadow entry.
+func (s *ShadowFS) MoveEntry(oldPath, newPath string) error {
+	if !s.Exists(oldPath) {
+		return nil
+	}
+
+	entry, err := s.Get(oldPath)
+	if err != nil {
+		return fmt.Errorf("failed to load shadow entry for %s: %w", oldPath, err)
+	}
+
+	entry.SourcePath = pathkey.Canonical(newPath)
+	if entry.Module != nil && entry.Module.Name == filepath.ToSlash(oldPath) {
+		entry.Module.Name = filepath.ToSlash(newPath)
+	}
+	entry.UpdatedAt = time.Now()
+
+	if err := s.Set(newPath, entry); err != nil {
+		return fmt.Errorf("failed to save shadow entry for %s: %w", newPath, err)
+	}
+	if err := s.Delete(oldPath); err != nil {
+		return err
+	}
+
+	return s.SaveIndex()
+}
+
+// Aliases returns the recorded renames, mapping old paths to new paths
+func (s *ShadowFS) Aliases() (map[string]string, error) {
+	aliases := make(map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(s.shadowPath, AliasesFile))
+	if os.IsNotExist(err) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aliases: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// RecordAlias records that oldPath was renamed to newPath. Earlier aliases
+// pointing at oldPath are updated to point at newPath.
+func (s *ShadowFS) RecordAlias(oldPath, newPath string) error {
+	aliases, err := s.Aliases()
+	if err != nil {
+		return err
+	}
+
+	oldPath, newPath = filepath.ToSlash(oldPath), filepath.ToSlash(newPath)
+	for from, to := range aliases {
+		if to == oldPath {
+			aliases[from] = newPath
+		}
+	}
+	aliases[oldPath] = newPath
+	delete(aliases, newPath)
+
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode aliases: %w", err)
+	}
+
+	if err := os.MkdirAll(s.shadowPath, 0755); err != nil {
+		return fmt.Errorf("failed to create shadow directory: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(s.shadowPath, AliasesFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write aliases: %w", err)
+	}
+	return nil
+}
+
+// ResolveAlias returns the current path for a path that may have been
+// renamed, or the path unchanged if no alias is recorded
+func (s *ShadowFS) ResolveAlias(path string) string {
+	aliases, err := s.Aliases()
+	if err != nil {
+		return path
+	}
+	if to, ok := aliases[filepath.ToSlash(filepath.Clean(path))]; ok {
+		return filepath.FromSlash(to)
+	}
+	return path
+}
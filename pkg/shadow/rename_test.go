@@ -0,0 +1,240 @@
+/*
+# Module: pkg/shadow/rename_test.go
+Tests for graph-aware file renames.
+
+## Tags
+shadow, test, rename
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#rename_test.go> a code:Module ;
+    code:name "pkg/shadow/rename_test.go" ;
+    code:description "Tests for graph-aware file renames" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./rename.go> ;
+    code:tags "shadow", "test", "rename" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+const renameTestLogger = `/*
+# Module: utils/logger.go
+Logger.
+
+## Linked Modules
+- [format](./format.go) - Formatting
+
+<!-- LinkedDoc RDF -->
+<#logger.go> a code:Module ;
+    code:name "utils/logger.go" ;
+    code:linksTo <./format.go> .
+<!-- End LinkedDoc RDF -->
+*/
+`
+
+const renameTestService = `/*
+# Module: services/auth.go
+
+## Linked Modules
+- [logger](../utils/logger.go) - Logging
+
+<!-- LinkedDoc RDF -->
+<#auth.go> a code:Module ;
+    code:name "services/auth.go" ;
+    code:linksTo <../utils/format.go>, <../utils/logger.go> .
+<!-- End LinkedDoc RDF -->
+*/
+`
+
+func setupRenameProject(t *testing.T) (string, *graph.Graph) {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"utils/logger.go":  renameTestLogger,
+		"services/auth.go": renameTestService,
+	}
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	g := graph.NewGraph(root, store.NewTripleStore())
+	logger := graph.NewModule("utils/logger.go", "<#logger.go>")
+	logger.AddDependency("utils/format.go")
+	auth := graph.NewModule("services/auth.go", "<#auth.go>")
+	auth.AddDependency("utils/format.go")
+	auth.AddDependency("utils/logger.go")
+	g.AddModule(logger)
+	g.AddModule(auth)
+
+	return root, g
+}
+
+func TestPlanRename(t *testing.T) {
+	root, g := setupRenameProject(t)
+
+	plan, err := PlanRename(g, root, "utils/logger.go", "pkg/log/logger.go")
+	if err != nil {
+		t.Fatalf("PlanRename failed: %v", err)
+	}
+
+	if len(plan.Dependents) != 1 || plan.Dependents[0].Path != "services/auth.go" {
+		t.Fatalf("Expected services/auth.go as the only dependent, got %+v", plan.Dependents)
+	}
+	dependent := plan.Dependents[0].Updated
+	if !strings.Contains(dependent, "<../utils/format.go>, <../pkg/log/logger.go> .") {
+		t.Errorf("Expected linksTo to be rewritten:\n%s", dependent)
+	}
+	if !strings.Contains(dependent, "- [logger](../pkg/log/logger.go)") {
+		t.Errorf("Expected markdown link to be rewritten:\n%s", dependent)
+	}
+
+	moved := plan.Moved.Updated
+	for _, want := range []string{
+		"# Module: pkg/log/logger.go",
+		`code:name "pkg/log/logger.go"`,
+		"code:linksTo <../../utils/format.go> .",
+		"- [format](../../utils/format.go)",
+	} {
+		if !strings.Contains(moved, want) {
+			t.Errorf("Expected moved file to contain %q:\n%s", want, moved)
+		}
+	}
+}
+
+func TestRenamePlan_Apply(t *testing.T) {
+	root, g := setupRenameProject(t)
+
+	shadowFS, err := NewShadowFS(root, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	entry := NewEntry("utils/logger.go", SourceAuto)
+	entry.Module = &Module{URI: "<#logger.go>", Name: "utils/logger.go"}
+	if err := shadowFS.Set("utils/logger.go", entry); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+
+	plan, err := PlanRename(g, root, "utils/logger.go", "pkg/log/logger.go")
+	if err != nil {
+		t.Fatalf("PlanRename failed: %v", err)
+	}
+	if err := plan.Apply(root, shadowFS); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "utils", "logger.go")); !os.IsNotExist(err) {
+		t.Error("Expected old file to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "pkg", "log", "logger.go")); err != nil {
+		t.Errorf("Expected new file to exist: %v", err)
+	}
+
+	if shadowFS.Exists("utils/logger.go") {
+		t.Error("Expected old shadow entry to be removed")
+	}
+	moved, err := shadowFS.Get("pkg/log/logger.go")
+	if err != nil {
+		t.Fatalf("Expected moved shadow entry: %v", err)
+	}
+	if moved.SourcePath != "pkg/log/logger.go" || moved.Module.Name != "pkg/log/logger.go" {
+		t.Errorf("Unexpected moved entry: %+v", moved)
+	}
+	if _, ok := shadowFS.Index().Get("pkg/log/logger.go"); !ok {
+		t.Error("Expected index to contain the new path")
+	}
+
+	// A second rename updates the existing alias
+	if err := shadowFS.RecordAlias("pkg/log/logger.go", "pkg/logging/logger.go"); err != nil {
+		t.Fatalf("RecordAlias failed: %v", err)
+	}
+	if got := shadowFS.ResolveAlias("utils/logger.go"); got != filepath.FromSlash("pkg/logging/logger.go") {
+		t.Errorf("Expected alias chain to resolve to the latest path, got %s", got)
+	}
+	if got := shadowFS.ResolveAlias("services/auth.go"); got != "services/auth.go" {
+		t.Errorf("Expected unrenamed path unchanged, got %s", got)
+	}
+}
+
+func TestPlanRename_DestinationExists(t *testing.T) {
+	root, g := setupRenameProject(t)
+
+	if _, err := PlanRename(g, root, "utils/logger.go", "services/auth.go"); err == nil {
+		t.Error("Expected error when destination exists")
+	}
+}
+
+func TestRenamePlan_ApplyMinimalApp(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "minimal-app")
+	if err := os.CopyFS(root, os.DirFS(filepath.Join("..", "..", "examples", "minimal-app"))); err != nil {
+		t.Fatalf("Failed to copy minimal-app: %v", err)
+	}
+	g, err := graph.NewBuilder().Build(root, graph.BuildOptions{
+		ScanOptions: scanner.ScanOptions{UseDefaults: true},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	plan, err := PlanRename(g, root, filepath.FromSlash("utils/logger.go"), filepath.FromSlash("pkg/log/logger.go"))
+	if err != nil {
+		t.Fatalf("PlanRename failed: %v", err)
+	}
+	if err := plan.Apply(root, nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		"main.go":          "<./pkg/log/logger.go#NewLogger>",
+		"services/auth.go": "<../pkg/log/logger.go#Info>",
+	} {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected %s to contain %s", path, want)
+		}
+	}
+
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".go" {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(string(content), "utils/logger.go") {
+			t.Errorf("Expected no references to utils/logger.go in %s", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
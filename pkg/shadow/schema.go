@@ -0,0 +1,279 @@
+/*
+# Module: pkg/shadow/schema.go
+Typed annotation schema for shadow entries.
+
+Loads .graphfs/annotations.schema.yaml, which declares the allowed
+annotation keys with their value types (bool, enum, string, date) and the
+keys every entry of a layer must carry. Writes reject annotations that break
+the schema; missing required keys are reported by ValidateAnnotations.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+
+## Tags
+shadow, annotations, schema, validation
+
+## Exports
+AnnotationSchemaFile, AnyLayer, AnnotationSchema, AnnotationKeySpec, AnnotationType, SchemaViolation, LoadAnnotationSchema
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#schema.go> a code:Module ;
+    code:name "pkg/shadow/schema.go" ;
+    code:description "Typed annotation schema for shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go> ;
+    code:exports <#AnnotationSchemaFile>, <#AnyLayer>, <#AnnotationSchema>, <#AnnotationKeySpec>, <#AnnotationType>, <#SchemaViolation>, <#LoadAnnotationSchema> ;
+    code:tags "shadow", "annotations", "schema", "validation" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AnnotationSchemaFile is the file name of the annotation schema, next to
+// the shadow directory
+const AnnotationSchemaFile = "annotations.schema.yaml"
+
+// AnyLayer lists the keys required on entries of every layer
+const AnyLayer = "*"
+
+// AnnotationType is the type of an annotation value
+type AnnotationType string
+
+const (
+	AnnotationBool   AnnotationType = "bool"
+	AnnotationEnum   AnnotationType = "enum"
+	AnnotationString AnnotationType = "string"
+	AnnotationDate   AnnotationType = "date" // YYYY-MM-DD or RFC 3339
+)
+
+// AnnotationSchema declares the allowed annotation keys and the keys each
+// layer requires
+type AnnotationSchema struct {
+	Keys map[string]AnnotationKeySpec `yaml:"keys" json:"keys"`
+
+	// Required lists the keys entries of a layer must carry; "*" applies
+	// to every entry
+	Required map[string][]string `yaml:"required,omitempty" json:"required,omitempty"`
+
+	// AllowUnknown accepts keys not declared in Keys
+	AllowUnknown bool `yaml:"allow-unknown,omitempty" json:"allow_unknown,omitempty"`
+}
+
+// AnnotationKeySpec declares the value type of an annotation key
+type AnnotationKeySpec struct {
+	Type        AnnotationType `yaml:"type" json:"type"`
+	Values      []string       `yaml:"values,omitempty" json:"values,omitempty"` // Allowed values of an enum
+	Description string         `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// SchemaViolation is an annotation, or a missing annotation, that breaks
+// the schema
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) Error() string {
+	return fmt.Sprintf("%s: annotation %s: %s", v.Path, v.Key, v.Message)
+}
+
+// LoadAnnotationSchema reads and checks an annotation schema file; a
+// missing file has no schema (nil)
+func LoadAnnotationSchema(filePath string) (*AnnotationSchema, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotation schema: %w", err)
+	}
+
+	var schema AnnotationSchema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation schema: %w", err)
+	}
+
+	for key, spec := range schema.Keys {
+		switch spec.Type {
+		case AnnotationBool, AnnotationString, AnnotationDate:
+		case AnnotationEnum:
+			if len(spec.Values) == 0 {
+				return nil, fmt.Errorf("annotation schema: enum %s has no values", key)
+			}
+		default:
+			return nil, fmt.Errorf("annotation schema: key %s has unknown type %q (use bool, enum, string or date)", key, spec.Type)
+		}
+	}
+	if !schema.AllowUnknown {
+		for layer, keys := range schema.Required {
+			for _, key := range keys {
+				if _, ok := schema.Keys[key]; !ok {
+					return nil, fmt.Errorf("annotation schema: layer %s requires undeclared key %s", layer, key)
+				}
+			}
+		}
+	}
+
+	return &schema, nil
+}
+
+// AnnotationSchemaPath returns the path of the annotation schema file
+func (s *ShadowFS) AnnotationSchemaPath() string {
+	return filepath.Join(filepath.Dir(s.shadowPath), AnnotationSchemaFile)
+}
+
+// AnnotationSchema returns the workspace's annotation schema, or nil when
+// there is none. The file is read once.
+func (s *ShadowFS) AnnotationSchema() (*AnnotationSchema, error) {
+	s.schemaOnce.Do(func() {
+		s.schema, s.schemaErr = LoadAnnotationSchema(s.AnnotationSchemaPath())
+	})
+	return s.schema, s.schemaErr
+}
+
+// ValidateAnnotation checks an annotation's key and value type
+func (sc *AnnotationSchema) ValidateAnnotation(a Annotation) error {
+	spec, ok := sc.Keys[a.Key]
+	if !ok {
+		if sc.AllowUnknown {
+			return nil
+		}
+		return fmt.Errorf("key is not declared in %s", AnnotationSchemaFile)
+	}
+
+	switch spec.Type {
+	case AnnotationBool:
+		switch v := a.Value.(type) {
+		case bool:
+			return nil
+		case string:
+			if v == "true" || v == "false" {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %v is not a bool (true or false)", a.Value)
+	case AnnotationEnum:
+		if v, ok := a.Value.(string); ok && slices.Contains(spec.Values, v) {
+			return nil
+		}
+		return fmt.Errorf("value %v is not one of %v", a.Value, spec.Values)
+	case AnnotationString:
+		if _, ok := a.Value.(string); ok {
+			return nil
+		}
+		return fmt.Errorf("value %v is not a string", a.Value)
+	case AnnotationDate:
+		if v, ok := a.Value.(string); ok {
+			if _, err := time.Parse("2006-01-02", v); err == nil {
+				return nil
+			}
+			if _, err := time.Parse(time.RFC3339, v); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %v is not a date (YYYY-MM-DD)", a.Value)
+	}
+	return nil
+}
+
+// ValidateEntry returns an entry's annotations that break the schema and
+// the keys its layer requires but it lacks (or has only expired)
+func (sc *AnnotationSchema) ValidateEntry(entry *Entry) []SchemaViolation {
+	var violations []SchemaViolation
+	for _, a := range entry.Annotations {
+		if err := sc.ValidateAnnotation(a); err != nil {
+			violations = append(violations, SchemaViolation{Path: entry.SourcePath, Key: a.Key, Message: err.Error()})
+		}
+	}
+
+	required := slices.Clone(sc.Required[AnyLayer])
+	if entry.Module != nil && entry.Module.Layer != "" {
+		required = append(required, sc.Required[entry.Module.Layer]...)
+	}
+	seen := make(map[string]bool)
+	for _, key := range required {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok := entry.GetAnnotation(key); !ok {
+			message := "required on every entry"
+			if !slices.Contains(sc.Required[AnyLayer], key) {
+				message = "required for layer " + entry.Module.Layer
+			}
+			violations = append(violations, SchemaViolation{Path: entry.SourcePath, Key: key, Message: message})
+		}
+	}
+	return violations
+}
+
+// ValidateAnnotations checks every shadow entry against the annotation
+// schema. Without a schema there are no violations.
+func (s *ShadowFS) ValidateAnnotations() ([]SchemaViolation, error) {
+	schema, err := s.AnnotationSchema()
+	if err != nil || schema == nil {
+		return nil, err
+	}
+
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []SchemaViolation
+	for _, entry := range entries {
+		violations = append(violations, schema.ValidateEntry(entry)...)
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Path != violations[j].Path {
+			return violations[i].Path < violations[j].Path
+		}
+		return violations[i].Key < violations[j].Key
+	})
+	return violations, nil
+}
+
+// checkAnnotationWrite rejects a write that adds or changes an annotation
+// breaking the schema. Annotations already stored unchanged are left for
+// ValidateAnnotations to report, so rebuilds keep working while the schema
+// is adopted. Required keys are not enforced on write.
+func (s *ShadowFS) checkAnnotationWrite(shadowPath string, entry *Entry) error {
+	schema, err := s.AnnotationSchema()
+	if err != nil || schema == nil {
+		return err
+	}
+
+	stored := make(map[string]string)
+	if existing, err := s.loadEntry(shadowPath); err == nil {
+		for _, a := range existing.Annotations {
+			stored[a.Key] = fmt.Sprint(a.Value)
+		}
+	}
+
+	for _, a := range entry.Annotations {
+		if value, ok := stored[a.Key]; ok && value == fmt.Sprint(a.Value) {
+			continue
+		}
+		if err := schema.ValidateAnnotation(a); err != nil {
+			return SchemaViolation{Path: entry.SourcePath, Key: a.Key, Message: err.Error()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+# Module: pkg/shadow/schema_test.go
+Tests for the annotation schema.
+
+## Tags
+shadow, test, annotations, schema
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#schema_test.go> a code:Module ;
+    code:name "pkg/shadow/schema_test.go" ;
+    code:description "Tests for the annotation schema" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./schema.go> ;
+    code:tags "shadow", "test", "annotations", "schema" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testAnnotationSchema = `keys:
+  owner:
+    type: string
+  reviewed:
+    type: bool
+  status:
+    type: enum
+    values: [active, deprecated]
+  review-date:
+    type: date
+required:
+  "*": [owner]
+  api: [reviewed]
+`
+
+func newSchemaShadowFS(t *testing.T, schema string) *ShadowFS {
+	t.Helper()
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".graphfs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".graphfs", AnnotationSchemaFile), []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	shadowFS, err := NewShadowFS(root, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	return shadowFS
+}
+
+func TestAnnotationSchemaValidateAnnotation(t *testing.T) {
+	shadowFS := newSchemaShadowFS(t, testAnnotationSchema)
+	schema, err := shadowFS.AnnotationSchema()
+	if err != nil || schema == nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	tests := []struct {
+		key     string
+		value   interface{}
+		wantErr bool
+	}{
+		{"owner", "team-x", false},
+		{"owner", 42.0, true},
+		{"reviewed", "true", false},
+		{"reviewed", true, false},
+		{"reviewed", "yes", true},
+		{"status", "active", false},
+		{"status", "retired", true},
+		{"review-date", "2026-03-01", false},
+		{"review-date", "next week", true},
+		{"undeclared", "x", true},
+	}
+	for _, tt := range tests {
+		err := schema.ValidateAnnotation(Annotation{Key: tt.key, Value: tt.value})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateAnnotation(%s=%v) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+
+	schema.AllowUnknown = true
+	if err := schema.ValidateAnnotation(Annotation{Key: "undeclared", Value: "x"}); err != nil {
+		t.Errorf("Expected unknown keys to be allowed: %v", err)
+	}
+}
+
+func TestLoadAnnotationSchemaErrors(t *testing.T) {
+	dir := t.TempDir()
+	if schema, err := LoadAnnotationSchema(filepath.Join(dir, "missing.yaml")); schema != nil || err != nil {
+		t.Errorf("Expected no schema for a missing file, got %v, %v", schema, err)
+	}
+
+	for name, content := range map[string]string{
+		"unknown type":     "keys:\n  owner:\n    type: number\n",
+		"empty enum":       "keys:\n  status:\n    type: enum\n",
+		"undeclared req":   "keys: {}\nrequired:\n  api: [owner]\n",
+		"invalid document": "keys: [\n",
+	} {
+		path := filepath.Join(dir, "schema.yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadAnnotationSchema(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestShadowFSSchemaOnWrite(t *testing.T) {
+	shadowFS := newSchemaShadowFS(t, testAnnotationSchema)
+
+	if _, err := shadowFS.Annotate("api/handler.go", Annotation{Key: "reviewed", Value: "maybe"}, ""); err == nil {
+		t.Error("Expected Annotate to reject a value of the wrong type")
+	}
+	if _, err := shadowFS.Annotate("api/handler.go", Annotation{Key: "color", Value: "blue"}, ""); err == nil {
+		t.Error("Expected Annotate to reject an undeclared key")
+	}
+
+	entry := NewAutoEntry("api/handler.go")
+	entry.AddAnnotation("status", "retired", "")
+	if err := shadowFS.Set("api/handler.go", entry); err == nil {
+		t.Error("Expected Set to reject an invalid annotation")
+	}
+
+	// Annotations stored before the schema existed do not block rebuilds
+	entry = NewAutoEntry("api/handler.go")
+	entry.SetModule("<#api/handler.go>", "handler.go", "", "go", "api", nil)
+	entry.AddAnnotation("legacy", "x", "")
+	if err := os.MkdirAll(filepath.Join(shadowFS.ShadowPath(), "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := entry.Save(filepath.Join(shadowFS.ShadowPath(), "api", "handler.go"+ShadowExtension), true); err != nil {
+		t.Fatal(err)
+	}
+	if err := shadowFS.Merge("api/handler.go", NewAutoEntry("api/handler.go")); err != nil {
+		t.Errorf("Expected Merge to keep an existing annotation: %v", err)
+	}
+
+	violations, err := shadowFS.ValidateAnnotations()
+	if err != nil {
+		t.Fatalf("ValidateAnnotations failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, v := range violations {
+		got[v.Key] = v.Message
+	}
+	if len(violations) != 3 || got["legacy"] == "" || got["owner"] != "required on every entry" || got["reviewed"] != "required for layer api" {
+		t.Errorf("Unexpected violations: %+v", violations)
+	}
+}
@@ -9,6 +9,7 @@ semantic/conceptual graph metadata without altering actual source files.
 - [entry](./entry.go) - Shadow entry data structure
 - [manager](./manager.go) - Shadow file system manager
 - [index](./index.go) - Shadow index for fast lookups
+- [../pathkey](../pathkey/pathkey.go) - Canonical path keys
 
 ## Tags
 shadow, metadata, filesystem, non-invasive
@@ -25,7 +26,7 @@ ShadowFS, NewShadowFS, Config
     code:description "Shadow file system for storing graph metadata separately from source code" ;
     code:language "go" ;
     code:layer "shadow" ;
-    code:linksTo <./entry.go>, <./manager.go>, <./index.go> ;
+    code:linksTo <./entry.go>, <./manager.go>, <./index.go>, <../pathkey/pathkey.go> ;
     code:exports <#ShadowFS>, <#NewShadowFS>, <#Config> ;
     code:tags "shadow", "metadata", "filesystem", "non-invasive" .
 <!-- End LinkedDoc RDF -->
@@ -39,6 +40,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/justin4957/graphfs/pkg/pathkey"
 )
 
 const (
@@ -68,16 +71,44 @@ type Config struct {
 
 	// ValidateOnWrite validates entries before writing
 	ValidateOnWrite bool
+
+	// FoldCase treats paths differing only in case as the same file, for
+	// case-insensitive filesystems (default: on for Windows and macOS)
+	FoldCase bool
+
+	// Audit records every write in the audit log next to the shadow
+	// directory (.graphfs/audit.log by default)
+	Audit bool
+
+	// AuditActor names who writes (default: $GRAPHFS_ACTOR, the git user
+	// email or the OS user)
+	AuditActor string
+
+	// Layout is how entries are stored: one file per source file or packed
+	// shards (default: detected from the shadow directory, else files)
+	Layout Layout
+
+	// NoLock reads and saves the index without taking the workspace lock
+	NoLock bool
+
+	// LockTimeout is how long the index waits for another process holding
+	// the workspace lock (0 tries once)
+	LockTimeout time.Duration
 }
 
 // DefaultConfig returns the default shadow configuration
 func DefaultConfig() Config {
+	noLock, lockTimeout := lockDefaults()
 	return Config{
 		ShadowDir:       DefaultShadowDir,
 		AutoSync:        false,
 		PreserveManual:  true,
 		CompactJSON:     false,
 		ValidateOnWrite: true,
+		FoldCase:        pathkey.DefaultFoldCase(),
+		Audit:           !auditDisabled.Load(),
+		NoLock:          noLock,
+		LockTimeout:     lockTimeout,
 	}
 }
 
@@ -93,12 +124,29 @@ type ShadowFS struct {
 	// Configuration
 	config Config
 
+	// Path keys for shadow file locations and the index
+	keys pathkey.Normalizer
+
 	// Index for fast lookups
 	index *Index
 
+	// Storage layout and the store holding the entries
+	layout Layout
+	store  entryStore
+
 	// Statistics
 	stats Statistics
 
+	// Audit log (nil when auditing is disabled) and the resolved actor
+	audit     *AuditLog
+	actor     string
+	actorOnce sync.Once
+
+	// Annotation schema, loaded on first use (nil when there is none)
+	schema     *AnnotationSchema
+	schemaErr  error
+	schemaOnce sync.Once
+
 	// Mutex for thread-safe operations
 	mu sync.RWMutex
 }
@@ -136,11 +184,24 @@ func NewShadowFS(rootPath string, config Config) (*ShadowFS, error) {
 		shadowPath = filepath.Join(absRoot, shadowDir)
 	}
 
+	layout := config.Layout
+	if layout == "" {
+		layout = DetectLayout(shadowPath)
+	} else if _, err := ParseLayout(string(layout)); err != nil {
+		return nil, err
+	}
+
 	shadowFS := &ShadowFS{
 		rootPath:   absRoot,
 		shadowPath: shadowPath,
 		config:     config,
-		index:      NewIndex(),
+		keys:       pathkey.Normalizer{FoldCase: config.FoldCase},
+		layout:     layout,
+		store:      newEntryStore(layout, shadowPath),
+	}
+	shadowFS.index = shadowFS.newIndex()
+	if config.Audit {
+		shadowFS.audit = NewAuditLog(filepath.Join(filepath.Dir(shadowPath), filepath.Base(DefaultAuditLog)))
 	}
 
 	return shadowFS, nil
@@ -155,6 +216,11 @@ func (s *ShadowFS) Initialize() error {
 	if err := os.MkdirAll(s.shadowPath, 0755); err != nil {
 		return fmt.Errorf("failed to create shadow directory: %w", err)
 	}
+	if s.layout == LayoutPacked {
+		if err := os.MkdirAll(filepath.Join(s.shadowPath, PackedDir), 0755); err != nil {
+			return fmt.Errorf("failed to create shadow directory: %w", err)
+		}
+	}
 
 	// Create index file if it doesn't exist
 	indexPath := filepath.Join(s.shadowPath, "index.json")
@@ -177,6 +243,11 @@ func (s *ShadowFS) ShadowPath() string {
 	return s.shadowPath
 }
 
+// Layout returns the storage layout of the shadow entries
+func (s *ShadowFS) Layout() Layout {
+	return s.layout
+}
+
 // Config returns the current configuration
 func (s *ShadowFS) Config() Config {
 	return s.config
@@ -190,8 +261,9 @@ func (s *ShadowFS) GetShadowPath(sourcePath string) (string, error) {
 		return "", err
 	}
 
-	// Construct shadow file path
-	shadowFile := filepath.Join(s.shadowPath, relPath+ShadowExtension)
+	// Construct shadow file path from the path key, so every spelling of a
+	// path maps to the same file
+	shadowFile := filepath.Join(s.shadowPath, filepath.FromSlash(s.keys.Key(relPath))+ShadowExtension)
 	return shadowFile, nil
 }
 
@@ -226,7 +298,7 @@ func (s *ShadowFS) Get(sourcePath string) (*Entry, error) {
 		return nil, err
 	}
 
-	return LoadEntry(shadowPath)
+	return s.loadEntry(shadowPath)
 }
 
 // Set stores a shadow entry for a source file
@@ -239,26 +311,23 @@ func (s *ShadowFS) Set(sourcePath string, entry *Entry) error {
 		return err
 	}
 
-	// Ensure directory exists
-	shadowDir := filepath.Dir(shadowPath)
-	if err := os.MkdirAll(shadowDir, 0755); err != nil {
-		return fmt.Errorf("failed to create shadow directory: %w", err)
-	}
-
 	// Validate if enabled
 	if s.config.ValidateOnWrite {
 		if err := entry.Validate(); err != nil {
 			return fmt.Errorf("validation failed: %w", err)
 		}
+		if err := s.checkAnnotationWrite(shadowPath, entry); err != nil {
+			return err
+		}
 	}
 
 	// Save entry
-	if err := entry.Save(shadowPath, !s.config.CompactJSON); err != nil {
+	relPath, _ := s.getRelativePath(sourcePath)
+	if err := s.saveEntryFile(shadowPath, relPath, entry, "set"); err != nil {
 		return err
 	}
 
 	// Update index
-	relPath, _ := s.getRelativePath(sourcePath)
 	s.index.Add(relPath, entry)
 
 	return nil
@@ -279,11 +348,7 @@ func (s *ShadowFS) Delete(sourcePath string) error {
 	s.index.Remove(relPath)
 
 	// Delete shadow file
-	if err := os.Remove(shadowPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete shadow file: %w", err)
-	}
-
-	return nil
+	return s.removeEntryFile(shadowPath, relPath, "delete")
 }
 
 // Exists checks if a shadow entry exists for a source file
@@ -293,8 +358,7 @@ func (s *ShadowFS) Exists(sourcePath string) bool {
 		return false
 	}
 
-	_, err = os.Stat(shadowPath)
-	return err == nil
+	return s.store.Exists(shadowPath)
 }
 
 // List returns all shadow entries
@@ -302,28 +366,7 @@ func (s *ShadowFS) List() ([]*Entry, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var entries []*Entry
-
-	err := filepath.Walk(s.shadowPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip directories and non-shadow files
-		if info.IsDir() || !isShadowFile(path) {
-			return nil
-		}
-
-		entry, err := LoadEntry(path)
-		if err != nil {
-			// Log warning but continue
-			return nil
-		}
-
-		entries = append(entries, entry)
-		return nil
-	})
-
+	entries, err := s.listUnlocked()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list shadow entries: %w", err)
 	}
@@ -343,30 +386,68 @@ func (s *ShadowFS) Merge(sourcePath string, newEntry *Entry) error {
 	}
 
 	// Try to load existing entry
-	existing, err := LoadEntry(shadowPath)
+	existing, err := s.loadEntry(shadowPath)
+	if isVersionError(err) {
+		return err
+	}
 	if err != nil {
 		// No existing entry, just save the new one
-		return s.setUnlocked(sourcePath, newEntry)
+		return s.setUnlocked(sourcePath, newEntry, "merge", "")
 	}
 
 	// Merge entries
 	merged := existing.Merge(newEntry, s.config.PreserveManual)
 
 	// Save merged entry
-	return s.setUnlocked(sourcePath, merged)
+	return s.setUnlocked(sourcePath, merged, "merge", "")
 }
 
-// setUnlocked saves an entry without acquiring the lock (caller must hold lock)
-func (s *ShadowFS) setUnlocked(sourcePath string, entry *Entry) error {
+// Annotate adds or updates a manual annotation on the entry for a source
+// file, creating a manual entry if there is none. Auto-generated entries
+// become mixed, so rebuilds preserve the annotation like any manual data.
+// A non-empty actor is recorded in the audit log instead of the configured one.
+func (s *ShadowFS) Annotate(sourcePath string, annotation Annotation, actor string) (*Entry, error) {
+	if annotation.Key == "" {
+		return nil, fmt.Errorf("annotation key is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	shadowPath, err := s.GetShadowPath(sourcePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Ensure directory exists
-	shadowDir := filepath.Dir(shadowPath)
-	if err := os.MkdirAll(shadowDir, 0755); err != nil {
-		return fmt.Errorf("failed to create shadow directory: %w", err)
+	entry, err := s.loadEntry(shadowPath)
+	if isVersionError(err) {
+		return nil, err
+	}
+	if err != nil {
+		relPath, err := s.getRelativePath(sourcePath)
+		if err != nil {
+			return nil, err
+		}
+		entry = NewManualEntry(relPath)
+	}
+
+	entry.setAnnotation(annotation.Key, annotation.Value, annotation.Author, annotation.ExpiresAt)
+	if entry.Source == SourceAuto {
+		entry.Source = SourceMixed
+	}
+
+	if err := s.setUnlocked(sourcePath, entry, "annotate", actor); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// setUnlocked saves an entry without acquiring the lock (caller must hold
+// lock), recording it in the audit log on behalf of actor
+func (s *ShadowFS) setUnlocked(sourcePath string, entry *Entry, operation, actor string) error {
+	shadowPath, err := s.GetShadowPath(sourcePath)
+	if err != nil {
+		return err
 	}
 
 	// Validate if enabled
@@ -374,15 +455,18 @@ func (s *ShadowFS) setUnlocked(sourcePath string, entry *Entry) error {
 		if err := entry.Validate(); err != nil {
 			return fmt.Errorf("validation failed: %w", err)
 		}
+		if err := s.checkAnnotationWrite(shadowPath, entry); err != nil {
+			return err
+		}
 	}
 
 	// Save entry
-	if err := entry.Save(shadowPath, !s.config.CompactJSON); err != nil {
+	relPath, _ := s.getRelativePath(sourcePath)
+	if err := s.saveEntryFileAs(shadowPath, relPath, entry, operation, actor); err != nil {
 		return err
 	}
 
 	// Update index
-	relPath, _ := s.getRelativePath(sourcePath)
 	s.index.Add(relPath, entry)
 
 	return nil
@@ -415,7 +499,7 @@ func (s *ShadowFS) RefreshStatistics() error {
 		} else {
 			s.stats.AutoGenEntries++
 		}
-		s.stats.TotalTriples += len(entry.Triples)
+		s.stats.TotalTriples += len(entry.ActiveTriples(time.Now()))
 	}
 
 	return nil
@@ -425,22 +509,10 @@ func (s *ShadowFS) RefreshStatistics() error {
 func (s *ShadowFS) listUnlocked() ([]*Entry, error) {
 	var entries []*Entry
 
-	err := filepath.Walk(s.shadowPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip directories and non-shadow files
-		if info.IsDir() || !isShadowFile(path) {
-			return nil
-		}
-
-		entry, err := LoadEntry(path)
-		if err != nil {
-			return nil
+	err := s.walkEntries(func(path string, entry *Entry) error {
+		if isShadowFile(path) {
+			entries = append(entries, entry)
 		}
-
-		entries = append(entries, entry)
 		return nil
 	})
 
@@ -475,28 +547,15 @@ func (s *ShadowFS) RebuildIndex() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Clear existing index
-	s.index = NewIndex()
-
-	// Walk shadow directory
-	err := filepath.Walk(s.shadowPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	// Clear existing index; the rebuilt index replaces the one on disk
+	s.index = s.newIndex()
+	s.index.Clear()
 
-		// Skip directories and non-shadow files
-		if info.IsDir() || !isShadowFile(path) {
-			return nil
+	// Walk shadow entries
+	err := s.walkEntries(func(path string, entry *Entry) error {
+		if isShadowFile(path) {
+			s.index.Add(entry.SourcePath, entry)
 		}
-
-		// Load entry
-		entry, err := LoadEntry(path)
-		if err != nil {
-			return nil // Skip invalid entries
-		}
-
-		// Add to index
-		s.index.Add(entry.SourcePath, entry)
 		return nil
 	})
 
@@ -523,7 +582,17 @@ func (s *ShadowFS) getRelativePath(sourcePath string) (string, error) {
 		return "", fmt.Errorf("failed to get relative path: %w", err)
 	}
 
-	return relPath, nil
+	return pathkey.Canonical(relPath), nil
+}
+
+// newIndex creates an empty index using this file system's path keys
+func (s *ShadowFS) newIndex() *Index {
+	idx := NewIndex()
+	idx.keys = s.keys
+	idx.lockRoot = s.rootPath
+	idx.noLock = s.config.NoLock
+	idx.lockTimeout = s.config.LockTimeout
+	return idx
 }
 
 // isShadowFile checks if a file is a shadow file
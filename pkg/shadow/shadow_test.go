@@ -300,6 +300,58 @@ func TestShadowFSMerge(t *testing.T) {
 	}
 }
 
+func TestShadowFSAnnotate(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := DefaultConfig()
+	config.Audit = true
+	config.AuditActor = "alice"
+	shadowFS, err := NewShadowFS(tmpDir, config)
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+
+	// Annotating an auto entry makes it mixed
+	auto := NewAutoEntry("api.go")
+	auto.SetModule("<#api.go>", "api.go", "API", "go", "api", nil)
+	if err := shadowFS.Set("api.go", auto); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, err := shadowFS.Annotate("api.go", Annotation{Key: "threat-model", Value: "pending"}, "api:bot"); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+	entry, err := shadowFS.Annotate("api.go", Annotation{Key: "threat-model", Value: "reviewed", Author: "bot"}, "")
+	if err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+	if entry.Source != SourceMixed || len(entry.Annotations) != 1 || entry.Annotations[0].Value != "reviewed" {
+		t.Errorf("Entry = %s with annotations %+v", entry.Source, entry.Annotations)
+	}
+
+	// Rebuilds preserve the annotation
+	if err := shadowFS.Merge("api.go", NewAutoEntry("api.go")); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if merged, _ := shadowFS.Get("api.go"); merged == nil || len(merged.Annotations) != 1 {
+		t.Errorf("Annotation lost on merge: %+v", merged)
+	}
+
+	// Files without an entry get a manual one
+	if entry, err := shadowFS.Annotate(filepath.Join("pkg", "new.go"), Annotation{Key: "owner", Value: "team-a"}, ""); err != nil || entry.Source != SourceManual || entry.SourcePath != "pkg/new.go" {
+		t.Errorf("Annotate new file = %+v, %v", entry, err)
+	}
+	if _, err := shadowFS.Annotate("api.go", Annotation{Value: "x"}, ""); err == nil {
+		t.Error("Expected error for annotation without key")
+	}
+
+	records, err := shadowFS.AuditLog().Records("api.go")
+	if err != nil {
+		t.Fatalf("Records failed: %v", err)
+	}
+	if len(records) != 3 || records[1].Operation != "annotate" || records[1].Actor != "api:bot" || records[2].Actor != "alice" {
+		t.Errorf("Audit records = %+v", records)
+	}
+}
+
 func TestShadowFSList(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "shadow-test-*")
 	if err != nil {
@@ -655,6 +707,121 @@ func TestIndexSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestIndexShardedSave(t *testing.T) {
+	tmpDir := t.TempDir()
+	indexPath := filepath.Join(tmpDir, "index.json")
+
+	idx := NewIndex()
+	for _, path := range []string{"main.go", "pkg/a.go", "pkg/b.go", "cmd/c.go"} {
+		entry := NewAutoEntry(path)
+		entry.SetModule("<#"+path+">", path, path, "go", "api", nil)
+		idx.Add(path, entry)
+	}
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+
+	shardDir := filepath.Join(tmpDir, "index.shards")
+	for _, name := range []string{"root.json", "dir-pkg.json", "dir-cmd.json"} {
+		if _, err := os.Stat(filepath.Join(shardDir, name)); err != nil {
+			t.Errorf("Expected shard file %s: %v", name, err)
+		}
+	}
+
+	// Only the changed shard is rewritten
+	cmdShard := filepath.Join(shardDir, "dir-cmd.json")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(cmdShard, old, old); err != nil {
+		t.Fatal(err)
+	}
+	idx.Remove("pkg/b.go")
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+	if info, _ := os.Stat(cmdShard); !info.ModTime().Equal(old) {
+		t.Error("Expected unchanged shard not to be rewritten")
+	}
+
+	// Emptied shards are removed
+	idx.Remove("cmd/c.go")
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+	if _, err := os.Stat(cmdShard); !os.IsNotExist(err) {
+		t.Error("Expected empty shard file to be removed")
+	}
+
+	loaded := NewIndex()
+	if err := loaded.Load(indexPath); err != nil {
+		t.Fatalf("Failed to load index: %v", err)
+	}
+	if loaded.Count() != 2 {
+		t.Errorf("Expected 2 entries, got %d", loaded.Count())
+	}
+	if _, ok := loaded.Get("pkg/a.go"); !ok {
+		t.Error("Expected pkg/a.go in loaded index")
+	}
+	if got := loaded.GetByLanguage("go"); len(got) != 2 {
+		t.Errorf("Expected 2 Go entries after load, got %v", got)
+	}
+}
+
+func TestIndexLoadLegacy(t *testing.T) {
+	tmpDir := t.TempDir()
+	indexPath := filepath.Join(tmpDir, "index.json")
+
+	legacy := `{"version": "1.0", "entries": {"pkg/a.go": {"path": "pkg/a.go", "language": "go", "source": "auto"}}}`
+	if err := os.WriteFile(indexPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := NewIndex()
+	if err := idx.Load(indexPath); err != nil {
+		t.Fatalf("Failed to load legacy index: %v", err)
+	}
+	if idx.Count() != 1 || idx.Statistics().LanguageCount["go"] != 1 {
+		t.Fatalf("Expected 1 Go entry, got %d entries, stats %+v", idx.Count(), idx.Statistics())
+	}
+
+	// The next save converts it to shards
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "index.shards", "dir-pkg.json")); err != nil {
+		t.Errorf("Expected legacy entries to be written to shards: %v", err)
+	}
+}
+
+func TestIndexIncrementalStatistics(t *testing.T) {
+	idx := NewIndex()
+
+	entry := NewAutoEntry("a.go")
+	entry.SetModule("<#a>", "a.go", "A", "go", "api", []string{"tag1"})
+	idx.Add("a.go", entry)
+
+	// Replacing an entry replaces its contribution
+	entry = NewManualEntry("a.go")
+	entry.SetModule("<#a>", "a.go", "A", "python", "api", []string{"tag2"})
+	idx.Add("a.go", entry)
+
+	stats := idx.Statistics()
+	if stats.TotalEntries != 1 || stats.AutoEntries != 0 || stats.ManualEntries != 1 {
+		t.Errorf("Unexpected entry counts: %+v", stats)
+	}
+	if _, ok := stats.LanguageCount["go"]; ok {
+		t.Error("Expected replaced language to be dropped from statistics")
+	}
+	if stats.TagCount["tag2"] != 1 {
+		t.Errorf("Expected tag2 count 1, got %d", stats.TagCount["tag2"])
+	}
+
+	idx.Remove("a.go")
+	stats = idx.Statistics()
+	if stats.TotalEntries != 0 || len(stats.LanguageCount) != 0 || len(stats.TagCount) != 0 {
+		t.Errorf("Expected empty statistics after remove, got %+v", stats)
+	}
+}
+
 func TestIndexStatistics(t *testing.T) {
 	idx := NewIndex()
 
@@ -0,0 +1,616 @@
+/*
+# Module: pkg/shadow/store.go
+Storage layouts for shadow entries.
+
+Entries are addressed by their shadow file path in every layout. The files
+layout keeps one .shadow.json per source file, mirroring the source tree.
+The packed layout keeps entries in 256 append-only JSONL shards under
+.graphfs/shadow/packed, which avoids huge directory trees in large
+repositories: writes append a record, reads replay a shard (last record
+wins), and shards are compacted once most of their records are stale.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [lock](./lock.go) - Atomic file writes
+
+## Tags
+shadow, storage, layout, jsonl
+
+## Exports
+Layout, LayoutFiles, LayoutPacked, PackedDir, DetectLayout
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#store.go> a code:Module ;
+    code:name "pkg/shadow/store.go" ;
+    code:description "Storage layouts for shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./lock.go> ;
+    code:exports <#Layout>, <#LayoutFiles>, <#LayoutPacked>, <#PackedDir>, <#DetectLayout> ;
+    code:tags "shadow", "storage", "layout", "jsonl" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Layout is how shadow entries are stored on disk
+type Layout string
+
+const (
+	// LayoutFiles stores one .shadow.json file per source file
+	LayoutFiles Layout = "files"
+
+	// LayoutPacked stores entries in sharded JSONL files under PackedDir
+	LayoutPacked Layout = "packed"
+)
+
+const (
+	// PackedDir is the directory of the packed layout inside the shadow
+	// directory; its presence marks a packed workspace
+	PackedDir = "packed"
+
+	// packedShards is the number of shard files of the packed layout
+	packedShards = 256
+
+	// packedShardExt is the file extension of packed shards
+	packedShardExt = ".jsonl"
+
+	// packedCompactSlack is how many stale records a shard may hold beyond
+	// its live entries before it is compacted
+	packedCompactSlack = 64
+)
+
+// ParseLayout parses a layout name
+func ParseLayout(name string) (Layout, error) {
+	switch Layout(name) {
+	case LayoutFiles, LayoutPacked:
+		return Layout(name), nil
+	}
+	return "", fmt.Errorf("unknown shadow layout %q (use files or packed)", name)
+}
+
+// DetectLayout returns the layout of the shadow directory at shadowPath:
+// packed when it has a packed directory, files otherwise
+func DetectLayout(shadowPath string) Layout {
+	if info, err := os.Stat(filepath.Join(shadowPath, PackedDir)); err == nil && info.IsDir() {
+		return LayoutPacked
+	}
+	return LayoutFiles
+}
+
+// entryStore stores the encoded entries of a shadow directory by their
+// shadow file path
+type entryStore interface {
+	// Read returns an entry; missing entries return an os.ErrNotExist error
+	Read(path string) ([]byte, error)
+
+	// Write stores an entry, replacing any entry at the path
+	Write(path string, data []byte) error
+
+	// Remove deletes an entry; missing entries are not an error
+	Remove(path string) error
+
+	// Exists reports whether there is an entry at the path
+	Exists(path string) bool
+
+	// Move moves an entry to another path
+	Move(from, to string) error
+
+	// Walk calls fn for every shadow and directory entry, in path order
+	Walk(fn func(path string, data []byte) error) error
+}
+
+// newEntryStore creates the store of a layout for the shadow directory
+func newEntryStore(layout Layout, shadowPath string) entryStore {
+	if layout == LayoutPacked {
+		return newPackedStore(shadowPath)
+	}
+	return fileStore{root: shadowPath}
+}
+
+// isEntryFile reports whether a path names a shadow or directory entry
+func isEntryFile(path string) bool {
+	return isShadowFile(path) || filepath.Base(path) == DirectoryEntryFile
+}
+
+// fileStore is the files layout: one file per entry
+type fileStore struct {
+	root string
+}
+
+func (fs fileStore) Read(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (fs fileStore) Write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create shadow directory: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write shadow file: %w", err)
+	}
+	return nil
+}
+
+func (fs fileStore) Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete shadow file: %w", err)
+	}
+	return nil
+}
+
+func (fs fileStore) Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (fs fileStore) Move(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create shadow directory: %w", err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to move shadow file: %w", err)
+	}
+	// Drop the old directory if the move left it empty
+	_ = os.Remove(filepath.Dir(from))
+	return nil
+}
+
+func (fs fileStore) Walk(fn func(path string, data []byte) error) error {
+	packedDir := filepath.Join(fs.root, PackedDir)
+	return filepath.Walk(fs.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path == packedDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isEntryFile(path) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		return fn(path, data)
+	})
+}
+
+// packedStore is the packed layout: append-only JSONL shards
+type packedStore struct {
+	root string // Shadow directory; records hold paths relative to it
+	dir  string
+
+	mu     sync.Mutex
+	shards map[string]*packedShard
+}
+
+// packedShard is a shard as last read or written
+type packedShard struct {
+	entries map[string][]byte // Compact entry JSON by relative path
+	records int               // Records in the file, including stale ones
+	size    int64             // File size and time, to notice writes by
+	modTime time.Time         // other processes
+}
+
+// packedRecord is a line of a shard
+type packedRecord struct {
+	Path    string          `json:"path"`
+	Entry   json.RawMessage `json:"entry,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+func newPackedStore(shadowPath string) *packedStore {
+	return &packedStore{
+		root:   shadowPath,
+		dir:    filepath.Join(shadowPath, PackedDir),
+		shards: make(map[string]*packedShard),
+	}
+}
+
+// relPath returns the slash-separated path of an entry relative to the
+// shadow directory
+func (ps *packedStore) relPath(path string) (string, error) {
+	rel, err := filepath.Rel(ps.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path is not under shadow directory: %s", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// packedShardFile returns the shard file name of a relative path
+func packedShardFile(rel string) string {
+	h := fnv.New32a()
+	h.Write([]byte(rel))
+	return fmt.Sprintf("%02x%s", h.Sum32()%packedShards, packedShardExt)
+}
+
+// shard returns a shard, reading it again if the file changed since it was
+// last read or written (caller must hold lock)
+func (ps *packedStore) shard(name string) (*packedShard, error) {
+	info, err := os.Stat(filepath.Join(ps.dir, name))
+	if os.IsNotExist(err) {
+		shard := &packedShard{entries: make(map[string][]byte)}
+		ps.shards[name] = shard
+		return shard, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shadow shard %s: %w", name, err)
+	}
+
+	if shard, ok := ps.shards[name]; ok && shard.size == info.Size() && shard.modTime.Equal(info.ModTime()) {
+		return shard, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(ps.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shadow shard %s: %w", name, err)
+	}
+	shard := &packedShard{
+		entries: make(map[string][]byte),
+		size:    info.Size(),
+		modTime: info.ModTime(),
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		var record packedRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue // A record cut short by a crash
+		}
+		shard.records++
+		if record.Deleted {
+			delete(shard.entries, record.Path)
+		} else {
+			shard.entries[record.Path] = record.Entry
+		}
+	}
+
+	ps.shards[name] = shard
+	return shard, nil
+}
+
+// appendRecord appends a record to a shard and compacts the shard when most
+// of its records are stale (caller must hold lock)
+func (ps *packedStore) appendRecord(name string, shard *packedShard, record packedRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to serialize shadow entry: %w", err)
+	}
+	if err := os.MkdirAll(ps.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create shadow directory: %w", err)
+	}
+
+	shardPath := filepath.Join(ps.dir, name)
+	file, err := os.OpenFile(shardPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open shadow shard: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write shadow shard: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write shadow shard: %w", err)
+	}
+	shard.records++
+
+	if shard.records > 2*len(shard.entries)+packedCompactSlack {
+		return ps.compact(name, shard)
+	}
+	return ps.touch(name, shard)
+}
+
+// compact rewrites a shard with only its live entries (caller must hold lock)
+func (ps *packedStore) compact(name string, shard *packedShard) error {
+	paths := make([]string, 0, len(shard.entries))
+	for rel := range shard.entries {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	for _, rel := range paths {
+		line, err := json.Marshal(packedRecord{Path: rel, Entry: shard.entries[rel]})
+		if err != nil {
+			return fmt.Errorf("failed to serialize shadow entry: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := writeFileAtomic(filepath.Join(ps.dir, name), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to compact shadow shard: %w", err)
+	}
+	shard.records = len(paths)
+	return ps.touch(name, shard)
+}
+
+// touch records a shard file's size and time after this store wrote it
+func (ps *packedStore) touch(name string, shard *packedShard) error {
+	info, err := os.Stat(filepath.Join(ps.dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to read shadow shard %s: %w", name, err)
+	}
+	shard.size = info.Size()
+	shard.modTime = info.ModTime()
+	return nil
+}
+
+func (ps *packedStore) Read(path string) ([]byte, error) {
+	rel, err := ps.relPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	shard, err := ps.shard(packedShardFile(rel))
+	if err != nil {
+		return nil, err
+	}
+	data, ok := shard.entries[rel]
+	if !ok {
+		return nil, fmt.Errorf("no shadow entry %s: %w", rel, os.ErrNotExist)
+	}
+	return data, nil
+}
+
+func (ps *packedStore) Write(path string, data []byte) error {
+	rel, err := ps.relPath(path)
+	if err != nil {
+		return err
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return fmt.Errorf("failed to serialize shadow entry: %w", err)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	name := packedShardFile(rel)
+	shard, err := ps.shard(name)
+	if err != nil {
+		return err
+	}
+	shard.entries[rel] = compact.Bytes()
+	return ps.appendRecord(name, shard, packedRecord{Path: rel, Entry: compact.Bytes()})
+}
+
+func (ps *packedStore) Remove(path string) error {
+	rel, err := ps.relPath(path)
+	if err != nil {
+		return err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	name := packedShardFile(rel)
+	shard, err := ps.shard(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := shard.entries[rel]; !ok {
+		return nil
+	}
+	delete(shard.entries, rel)
+	return ps.appendRecord(name, shard, packedRecord{Path: rel, Deleted: true})
+}
+
+func (ps *packedStore) Exists(path string) bool {
+	_, err := ps.Read(path)
+	return err == nil
+}
+
+func (ps *packedStore) Move(from, to string) error {
+	data, err := ps.Read(from)
+	if err != nil {
+		return fmt.Errorf("failed to move shadow entry: %w", err)
+	}
+	if err := ps.Write(to, data); err != nil {
+		return err
+	}
+	return ps.Remove(from)
+}
+
+func (ps *packedStore) Walk(fn func(path string, data []byte) error) error {
+	type item struct {
+		rel  string
+		data []byte
+	}
+
+	// Collect under the lock, then call fn without it so fn may write
+	ps.mu.Lock()
+	files, err := os.ReadDir(ps.dir)
+	if err != nil && !os.IsNotExist(err) {
+		ps.mu.Unlock()
+		return fmt.Errorf("failed to read shadow shards: %w", err)
+	}
+	var items []item
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != packedShardExt {
+			continue
+		}
+		shard, err := ps.shard(file.Name())
+		if err != nil {
+			ps.mu.Unlock()
+			return err
+		}
+		for rel, data := range shard.entries {
+			items = append(items, item{rel: rel, data: data})
+		}
+	}
+	ps.mu.Unlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].rel < items[j].rel
+	})
+	for _, it := range items {
+		if err := fn(filepath.Join(ps.root, filepath.FromSlash(it.rel)), it.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadEntry loads the entry at a shadow file path from the store
+func (s *ShadowFS) loadEntry(path string) (*Entry, error) {
+	data, err := s.store.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shadow file: %w", err)
+	}
+	return parseEntry(path, data)
+}
+
+// writeEntry stores an entry at a shadow file path
+func (s *ShadowFS) writeEntry(path string, entry *Entry) error {
+	data, err := entry.encode(!s.config.CompactJSON)
+	if err != nil {
+		return err
+	}
+	return s.store.Write(path, data)
+}
+
+// walkEntries loads every shadow and directory entry, skipping entries that
+// cannot be read (caller must hold lock)
+func (s *ShadowFS) walkEntries(fn func(path string, entry *Entry) error) error {
+	return s.store.Walk(func(path string, data []byte) error {
+		entry, err := parseEntry(path, data)
+		if err != nil {
+			return nil // Skip invalid entries
+		}
+		return fn(path, entry)
+	})
+}
+
+// ConvertLayout moves every entry into another storage layout, rebuilds the
+// index and returns the number of entries moved. Converting to the current
+// layout does nothing. Other processes must not write during the move.
+func (s *ShadowFS) ConvertLayout(layout Layout) (int, error) {
+	if _, err := ParseLayout(string(layout)); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	if layout == s.layout {
+		s.mu.Unlock()
+		return 0, nil
+	}
+
+	type stored struct {
+		path string
+		data []byte
+	}
+	var entries []stored
+	err := s.store.Walk(func(path string, data []byte) error {
+		entries = append(entries, stored{path: path, data: data})
+		return nil
+	})
+	if err != nil {
+		s.mu.Unlock()
+		return 0, fmt.Errorf("failed to read shadow entries: %w", err)
+	}
+
+	packedDir := filepath.Join(s.shadowPath, PackedDir)
+	switch layout {
+	case LayoutPacked:
+		// Pack into a scratch directory first so an interrupted conversion
+		// leaves the files layout untouched
+		scratch := packedDir + ".tmp"
+		if err := os.RemoveAll(scratch); err != nil {
+			s.mu.Unlock()
+			return 0, fmt.Errorf("failed to clear %s: %w", scratch, err)
+		}
+		packed := newPackedStore(s.shadowPath)
+		packed.dir = scratch
+		for _, e := range entries {
+			if err := packed.Write(e.path, e.data); err != nil {
+				s.mu.Unlock()
+				return 0, err
+			}
+		}
+		if err := os.MkdirAll(scratch, 0755); err != nil {
+			s.mu.Unlock()
+			return 0, fmt.Errorf("failed to create shadow directory: %w", err)
+		}
+		if err := os.Rename(scratch, packedDir); err != nil {
+			s.mu.Unlock()
+			return 0, fmt.Errorf("failed to install packed shadow store: %w", err)
+		}
+		for _, e := range entries {
+			if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+				s.mu.Unlock()
+				return 0, fmt.Errorf("failed to remove shadow file: %w", err)
+			}
+		}
+		removeEmptyDirs(s.shadowPath)
+		if err := os.MkdirAll(packedDir, 0755); err != nil {
+			s.mu.Unlock()
+			return 0, fmt.Errorf("failed to create shadow directory: %w", err)
+		}
+	case LayoutFiles:
+		files := fileStore{root: s.shadowPath}
+		for _, e := range entries {
+			var pretty bytes.Buffer
+			data := e.data
+			if !s.config.CompactJSON && json.Indent(&pretty, e.data, "", "  ") == nil {
+				data = pretty.Bytes()
+			}
+			if err := files.Write(e.path, data); err != nil {
+				s.mu.Unlock()
+				return 0, err
+			}
+		}
+		if err := os.RemoveAll(packedDir); err != nil {
+			s.mu.Unlock()
+			return 0, fmt.Errorf("failed to remove packed shadow store: %w", err)
+		}
+	}
+
+	s.layout = layout
+	s.config.Layout = layout
+	s.store = newEntryStore(layout, s.shadowPath)
+	s.mu.Unlock()
+
+	return len(entries), s.RebuildIndex()
+}
+
+// removeEmptyDirs removes the empty directories below root
+func removeEmptyDirs(root string) {
+	var dirs []string
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && path != root {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	// Deepest first, so parents are empty once their children are gone
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i])
+	}
+}
@@ -0,0 +1,181 @@
+/*
+# Module: pkg/shadow/store_test.go
+Tests for shadow storage layouts.
+
+## Tags
+shadow, test, storage
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#store_test.go> a code:Module ;
+    code:name "pkg/shadow/store_test.go" ;
+    code:description "Tests for shadow storage layouts" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./store.go> ;
+    code:tags "shadow", "test", "storage" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func newLayoutShadowFS(t *testing.T, root string, layout Layout) *ShadowFS {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.Layout = layout
+	shadowFS, err := NewShadowFS(root, config)
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	return shadowFS
+}
+
+func entryPaths(t *testing.T, shadowFS *ShadowFS) []string {
+	t.Helper()
+
+	entries, err := shadowFS.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		paths = append(paths, entry.SourcePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestPackedLayout(t *testing.T) {
+	root := t.TempDir()
+	shadowFS := newLayoutShadowFS(t, root, LayoutPacked)
+
+	for _, path := range []string{"pkg/api/handler.go", "pkg/store/db.go", "main.go"} {
+		if err := shadowFS.Set(path, NewAutoEntry(path)); err != nil {
+			t.Fatalf("Failed to set entry: %v", err)
+		}
+	}
+	if _, err := shadowFS.Annotate("main.go", Annotation{Key: "owner", Value: "team-x"}, ""); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+	if err := shadowFS.Delete("pkg/store/db.go"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(shadowFS.ShadowPath(), "main.go"+ShadowExtension)); !os.IsNotExist(err) {
+		t.Error("Expected no per-file shadow files in the packed layout")
+	}
+
+	// A new instance detects the layout and reads the shards back
+	reopened, err := NewShadowFS(root, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to reopen: %v", err)
+	}
+	if reopened.Layout() != LayoutPacked {
+		t.Errorf("Layout() = %s, want packed", reopened.Layout())
+	}
+	if got := entryPaths(t, reopened); len(got) != 2 || got[0] != "main.go" || got[1] != "pkg/api/handler.go" {
+		t.Errorf("Unexpected entries: %v", got)
+	}
+	entry, err := reopened.Get("main.go")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if value, ok := entry.GetAnnotation("owner"); !ok || value != "team-x" {
+		t.Errorf("Expected the annotation to survive, got %v", entry.Annotations)
+	}
+	if reopened.Exists("pkg/store/db.go") {
+		t.Error("Expected the deleted entry to stay deleted")
+	}
+
+	// Writes by one instance are seen by another
+	if err := reopened.Set("cmd/app.go", NewAutoEntry("cmd/app.go")); err != nil {
+		t.Fatalf("Failed to set entry: %v", err)
+	}
+	if !shadowFS.Exists("cmd/app.go") {
+		t.Error("Expected the first instance to see the new entry")
+	}
+}
+
+func TestPackedStoreCompaction(t *testing.T) {
+	shadowPath := t.TempDir()
+	store := newPackedStore(shadowPath)
+	path := filepath.Join(shadowPath, "main.go"+ShadowExtension)
+
+	for i := 0; i < packedCompactSlack*2; i++ {
+		if err := store.Write(path, []byte(`{"n": 1}`)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	shard := store.shards[packedShardFile("main.go"+ShadowExtension)]
+	if shard.records > packedCompactSlack+2 {
+		t.Errorf("Expected the shard to be compacted, it has %d records", shard.records)
+	}
+	data, err := newPackedStore(shadowPath).Read(path)
+	if err != nil || string(data) != `{"n":1}` {
+		t.Errorf("Read after compaction = %q, %v", data, err)
+	}
+}
+
+func TestConvertLayout(t *testing.T) {
+	root := t.TempDir()
+	shadowFS := newLayoutShadowFS(t, root, LayoutFiles)
+
+	for _, path := range []string{"pkg/api/handler.go", "main.go"} {
+		if err := shadowFS.Set(path, NewAutoEntry(path)); err != nil {
+			t.Fatalf("Failed to set entry: %v", err)
+		}
+	}
+	if err := shadowFS.SetDirectory("pkg/api", NewManualEntry("pkg/api")); err != nil {
+		t.Fatalf("SetDirectory failed: %v", err)
+	}
+
+	moved, err := shadowFS.ConvertLayout(LayoutPacked)
+	if err != nil {
+		t.Fatalf("ConvertLayout(packed) failed: %v", err)
+	}
+	if moved != 3 {
+		t.Errorf("Moved %d entries, want 3", moved)
+	}
+	if _, err := os.Stat(filepath.Join(shadowFS.ShadowPath(), "pkg")); !os.IsNotExist(err) {
+		t.Error("Expected the files layout to be removed")
+	}
+	if DetectLayout(shadowFS.ShadowPath()) != LayoutPacked {
+		t.Error("Expected the packed layout to be detected")
+	}
+	if got := entryPaths(t, shadowFS); len(got) != 2 {
+		t.Errorf("Unexpected entries after packing: %v", got)
+	}
+	if _, err := shadowFS.GetDirectory("pkg/api"); err != nil {
+		t.Errorf("Expected the directory entry to be packed: %v", err)
+	}
+	if shadowFS.Index().Statistics().TotalEntries != 2 {
+		t.Error("Expected the index to be rebuilt")
+	}
+
+	if _, err := shadowFS.ConvertLayout(LayoutFiles); err != nil {
+		t.Fatalf("ConvertLayout(files) failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(shadowFS.ShadowPath(), "pkg", "api", "handler.go"+ShadowExtension)); err != nil {
+		t.Errorf("Expected the shadow file to be restored: %v", err)
+	}
+	if DetectLayout(shadowFS.ShadowPath()) != LayoutFiles {
+		t.Error("Expected the files layout to be detected")
+	}
+	if got := entryPaths(t, shadowFS); len(got) != 2 {
+		t.Errorf("Unexpected entries after unpacking: %v", got)
+	}
+}
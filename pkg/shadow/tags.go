@@ -0,0 +1,417 @@
+/*
+# Module: pkg/shadow/tags.go
+Tag taxonomy management for shadow entries.
+
+Lists tag usage across shadow entries, renames and merges tags in bulk,
+records deprecated tags, and proposes matching edits to LinkedDoc headers
+in source files.
+
+## Linked Modules
+- [shadow](./shadow.go) - Shadow file system manager
+- [entry](./entry.go) - Shadow entry data structure
+- [push](./push.go) - LinkedDoc comment styles
+
+## Tags
+shadow, tags, taxonomy, refactoring
+
+## Exports
+TagUsage, TagTaxonomy, DeprecatedTag, TagChangeResult, SourceEdit, ProposeTagSourceEdits
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#tags.go> a code:Module ;
+    code:name "pkg/shadow/tags.go" ;
+    code:description "Tag taxonomy management for shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./shadow.go>, <./entry.go>, <./push.go> ;
+    code:exports <#TagUsage>, <#TagTaxonomy>, <#DeprecatedTag>, <#TagChangeResult>, <#SourceEdit>, <#ProposeTagSourceEdits> ;
+    code:tags "shadow", "tags", "taxonomy", "refactoring" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TaxonomyFile is the file name of the tag taxonomy inside the shadow directory
+const TaxonomyFile = "tags.json"
+
+// TagUsage describes how often a tag is used across shadow entries
+type TagUsage struct {
+	Tag         string   `json:"tag"`
+	Count       int      `json:"count"`
+	Paths       []string `json:"paths"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	Replacement string   `json:"replacement,omitempty"`
+}
+
+// DeprecatedTag records a tag that should no longer be used
+type DeprecatedTag struct {
+	Tag          string    `json:"tag"`
+	Replacement  string    `json:"replacement,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	DeprecatedAt time.Time `json:"deprecated_at"`
+}
+
+// TagTaxonomy holds project-wide tag policy
+type TagTaxonomy struct {
+	Version    string                   `json:"version"`
+	Deprecated map[string]DeprecatedTag `json:"deprecated"`
+}
+
+// TagChangeResult summarizes a bulk tag rename or merge
+type TagChangeResult struct {
+	From           []string `json:"from"`
+	To             string   `json:"to"`
+	UpdatedEntries []string `json:"updated_entries"`
+}
+
+// SourceEdit is a proposed single-line edit to a source file's LinkedDoc header
+type SourceEdit struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// NewTagTaxonomy creates an empty taxonomy
+func NewTagTaxonomy() *TagTaxonomy {
+	return &TagTaxonomy{
+		Version:    ShadowVersion,
+		Deprecated: make(map[string]DeprecatedTag),
+	}
+}
+
+// LoadTaxonomy loads the tag taxonomy, returning an empty one if none exists
+func (s *ShadowFS) LoadTaxonomy() (*TagTaxonomy, error) {
+	data, err := os.ReadFile(filepath.Join(s.shadowPath, TaxonomyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewTagTaxonomy(), nil
+		}
+		return nil, fmt.Errorf("failed to read tag taxonomy: %w", err)
+	}
+
+	taxonomy := NewTagTaxonomy()
+	if err := json.Unmarshal(data, taxonomy); err != nil {
+		return nil, fmt.Errorf("failed to parse tag taxonomy: %w", err)
+	}
+	if taxonomy.Deprecated == nil {
+		taxonomy.Deprecated = make(map[string]DeprecatedTag)
+	}
+
+	return taxonomy, nil
+}
+
+// SaveTaxonomy writes the tag taxonomy to the shadow directory
+func (s *ShadowFS) SaveTaxonomy(taxonomy *TagTaxonomy) error {
+	if err := os.MkdirAll(s.shadowPath, 0755); err != nil {
+		return fmt.Errorf("failed to create shadow directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(taxonomy, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize tag taxonomy: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(s.shadowPath, TaxonomyFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write tag taxonomy: %w", err)
+	}
+
+	return nil
+}
+
+// TagUsage returns usage for every tag across all shadow entries, sorted by
+// descending count and then by name
+func (s *ShadowFS) TagUsage() ([]TagUsage, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	taxonomy, err := s.LoadTaxonomy()
+	if err != nil {
+		return nil, err
+	}
+
+	usageByTag := make(map[string]*TagUsage)
+	for _, entry := range entries {
+		for _, tag := range entryTags(entry) {
+			usage, ok := usageByTag[tag]
+			if !ok {
+				usage = &TagUsage{Tag: tag}
+				usageByTag[tag] = usage
+			}
+			usage.Count++
+			usage.Paths = append(usage.Paths, entry.SourcePath)
+		}
+	}
+
+	// Deprecated tags are listed even when no longer used
+	for tag := range taxonomy.Deprecated {
+		if _, ok := usageByTag[tag]; !ok {
+			usageByTag[tag] = &TagUsage{Tag: tag}
+		}
+	}
+
+	result := make([]TagUsage, 0, len(usageByTag))
+	for tag, usage := range usageByTag {
+		if dep, ok := taxonomy.Deprecated[tag]; ok {
+			usage.Deprecated = true
+			usage.Replacement = dep.Replacement
+		}
+		sort.Strings(usage.Paths)
+		result = append(result, *usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+
+	return result, nil
+}
+
+// RenameTag renames a tag across all shadow entries
+func (s *ShadowFS) RenameTag(oldTag, newTag string) (*TagChangeResult, error) {
+	return s.MergeTags([]string{oldTag}, newTag)
+}
+
+// MergeTags replaces every source tag with the target tag across all shadow
+// entries. Entries that end up with the target tag twice are de-duplicated.
+func (s *ShadowFS) MergeTags(sources []string, target string) (*TagChangeResult, error) {
+	if target == "" {
+		return nil, fmt.Errorf("target tag is required")
+	}
+
+	result := &TagChangeResult{From: sources, To: target}
+
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !replaceEntryTags(entry, sources, target) {
+			continue
+		}
+
+		if err := s.Set(entry.SourcePath, entry); err != nil {
+			return result, fmt.Errorf("failed to update %s: %w", entry.SourcePath, err)
+		}
+		result.UpdatedEntries = append(result.UpdatedEntries, entry.SourcePath)
+	}
+
+	sort.Strings(result.UpdatedEntries)
+
+	// Rebuild rather than save so entries that were not touched stay indexed
+	if err := s.RebuildIndex(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// DeprecateTag marks a tag as deprecated with an optional replacement
+func (s *ShadowFS) DeprecateTag(tag, replacement, reason string) error {
+	taxonomy, err := s.LoadTaxonomy()
+	if err != nil {
+		return err
+	}
+
+	taxonomy.Deprecated[tag] = DeprecatedTag{
+		Tag:          tag,
+		Replacement:  replacement,
+		Reason:       reason,
+		DeprecatedAt: time.Now(),
+	}
+
+	return s.SaveTaxonomy(taxonomy)
+}
+
+// entryTags returns the tags of an entry's module
+func entryTags(entry *Entry) []string {
+	if entry.Module == nil {
+		return nil
+	}
+	return entry.Module.Tags
+}
+
+// replaceEntryTags rewrites module tags and tag triples in place.
+// Returns true if the entry was modified.
+func replaceEntryTags(entry *Entry, sources []string, target string) bool {
+	isSource := make(map[string]bool, len(sources))
+	for _, tag := range sources {
+		isSource[tag] = true
+	}
+
+	changed := false
+
+	if entry.Module != nil {
+		var tags []string
+		for _, tag := range entry.Module.Tags {
+			if isSource[tag] {
+				tag = target
+				changed = true
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		entry.Module.Tags = tags
+	}
+
+	seen := make(map[string]bool)
+	var triples []Triple
+	for _, t := range entry.Triples {
+		if strings.HasSuffix(t.Predicate, "tags") && isSource[t.Object] {
+			t.Object = target
+			changed = true
+		}
+		key := t.Subject + "|" + t.Predicate + "|" + t.Object
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		triples = append(triples, t)
+	}
+	entry.Triples = triples
+
+	return changed
+}
+
+// ProposeTagSourceEdits proposes edits to LinkedDoc headers that replace
+// each of oldTags with newTag, one edit per changed line. Both the "## Tags"
+// markdown section and code:tags RDF lines of the header are considered;
+// the rest of the file is not. Paths are relative to rootPath. No files
+// are modified.
+func ProposeTagSourceEdits(rootPath string, paths []string, oldTags []string, newTag string) ([]SourceEdit, error) {
+	replace := make(map[string]bool, len(oldTags))
+	for _, tag := range oldTags {
+		replace[tag] = true
+	}
+
+	var edits []SourceEdit
+	for _, relPath := range paths {
+		content, err := os.ReadFile(filepath.Join(rootPath, relPath))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+
+		style := commentStyleFor(relPath)
+		lines := strings.Split(string(content), "\n")
+		inTagsSection, inRDF := false, false
+
+		// Edits count only once the header's end marker is found
+		var fileEdits []SourceEdit
+		for i, line := range lines {
+			if i < style.SkipLines {
+				continue
+			}
+			if strings.Contains(line, style.EndMarker) {
+				edits = append(edits, fileEdits...)
+				break
+			}
+			trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), style.LinePrefix))
+
+			var after string
+			switch {
+			case strings.Contains(line, style.StartMarker):
+				inRDF = true
+				continue
+			case strings.HasPrefix(trimmed, "## Tags"):
+				inTagsSection = true
+				continue
+			case inTagsSection:
+				inTagsSection = false
+				after = replaceListTags(line, replace, newTag, false, style.LinePrefix)
+			case inRDF && strings.Contains(line, "code:tags"):
+				after = replaceListTags(line, replace, newTag, true, style.LinePrefix)
+			default:
+				continue
+			}
+
+			if after != line {
+				fileEdits = append(fileEdits, SourceEdit{
+					Path:   relPath,
+					Line:   i + 1,
+					Before: line,
+					After:  after,
+				})
+			}
+		}
+	}
+
+	return edits, nil
+}
+
+// replaceListTags replaces tags in a comma-separated list line. For RDF
+// lines tags are quoted literals following the code:tags predicate; other
+// lines may start with the comment leader linePrefix.
+func replaceListTags(line string, oldTags map[string]bool, newTag string, quoted bool, linePrefix string) string {
+	prefix := ""
+	body := line
+	suffix := ""
+
+	if quoted {
+		idx := strings.Index(line, "code:tags")
+		prefix = line[:idx+len("code:tags")]
+		body = line[idx+len("code:tags"):]
+		trimmed := strings.TrimRight(body, " \t;.")
+		suffix = body[len(trimmed):]
+		body = trimmed
+	} else {
+		trimmed := strings.TrimLeft(body, " \t*")
+		if linePrefix != "" {
+			trimmed = strings.TrimLeft(strings.TrimPrefix(trimmed, linePrefix), " \t")
+		}
+		prefix = body[:len(body)-len(trimmed)]
+		body = trimmed
+	}
+
+	parts := strings.Split(body, ",")
+	var values []string
+	changed := false
+	for _, part := range parts {
+		value := strings.TrimSpace(part)
+		if quoted {
+			value = strings.Trim(value, "\"")
+		}
+		if oldTags[value] {
+			value = newTag
+			changed = true
+		}
+		if value != "" && !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+
+	if !changed {
+		return line
+	}
+
+	if quoted {
+		for i, value := range values {
+			values[i] = "\"" + value + "\""
+		}
+		return prefix + " " + strings.Join(values, ", ") + suffix
+	}
+	return prefix + strings.Join(values, ", ")
+}
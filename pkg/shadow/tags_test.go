@@ -0,0 +1,191 @@
+/*
+# Module: pkg/shadow/tags_test.go
+Tests for tag taxonomy management.
+
+## Tags
+shadow, test, tags
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#tags_test.go> a code:Module ;
+    code:name "pkg/shadow/tags_test.go" ;
+    code:description "Tests for tag taxonomy management" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./tags.go> ;
+    code:tags "shadow", "test", "tags" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTaggedShadowFS(t *testing.T) *ShadowFS {
+	t.Helper()
+
+	shadowFS, err := NewShadowFS(t.TempDir(), DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create shadow file system: %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	files := map[string][]string{
+		"a.go": {"auth", "api"},
+		"b.go": {"authn", "authentication"},
+		"c.go": {"utils"},
+	}
+	for path, tags := range files {
+		entry := NewAutoEntry(path)
+		entry.SetModule("<#"+path+">", path, "", "go", "", tags)
+		for _, tag := range tags {
+			entry.AddTriple("<#"+path+">", "https://schema.codedoc.org/tags", tag, SourceAuto)
+		}
+		if err := shadowFS.Set(path, entry); err != nil {
+			t.Fatalf("Failed to set entry: %v", err)
+		}
+	}
+
+	return shadowFS
+}
+
+func TestMergeTags(t *testing.T) {
+	shadowFS := newTaggedShadowFS(t)
+
+	result, err := shadowFS.MergeTags([]string{"auth", "authn"}, "authentication")
+	if err != nil {
+		t.Fatalf("MergeTags failed: %v", err)
+	}
+	if len(result.UpdatedEntries) != 2 {
+		t.Errorf("Expected 2 updated entries, got %v", result.UpdatedEntries)
+	}
+
+	entry, err := shadowFS.Get("b.go")
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if len(entry.Module.Tags) != 1 || entry.Module.Tags[0] != "authentication" {
+		t.Errorf("Expected de-duplicated tags [authentication], got %v", entry.Module.Tags)
+	}
+	if len(entry.GetTriplesByPredicate("https://schema.codedoc.org/tags")) != 1 {
+		t.Errorf("Expected tag triples to be de-duplicated, got %v", entry.Triples)
+	}
+
+	if paths := shadowFS.Index().GetByTag("authentication"); len(paths) != 2 {
+		t.Errorf("Expected index to list 2 paths for merged tag, got %v", paths)
+	}
+	if paths := shadowFS.Index().GetByTag("utils"); len(paths) != 1 {
+		t.Errorf("Expected untouched entries to remain indexed, got %v", paths)
+	}
+}
+
+func TestTagUsageAndDeprecate(t *testing.T) {
+	shadowFS := newTaggedShadowFS(t)
+
+	if err := shadowFS.DeprecateTag("authn", "authentication", "duplicate"); err != nil {
+		t.Fatalf("DeprecateTag failed: %v", err)
+	}
+	if err := shadowFS.DeprecateTag("old", "", ""); err != nil {
+		t.Fatalf("DeprecateTag failed: %v", err)
+	}
+
+	usage, err := shadowFS.TagUsage()
+	if err != nil {
+		t.Fatalf("TagUsage failed: %v", err)
+	}
+
+	byTag := make(map[string]TagUsage)
+	for _, u := range usage {
+		byTag[u.Tag] = u
+	}
+
+	if byTag["auth"].Count != 1 {
+		t.Errorf("Expected auth count 1, got %d", byTag["auth"].Count)
+	}
+	if !byTag["authn"].Deprecated || byTag["authn"].Replacement != "authentication" {
+		t.Errorf("Expected authn to be deprecated with replacement, got %+v", byTag["authn"])
+	}
+	if u, ok := byTag["old"]; !ok || u.Count != 0 || !u.Deprecated {
+		t.Errorf("Expected unused deprecated tag to be listed, got %+v", u)
+	}
+}
+
+func TestProposeTagSourceEdits(t *testing.T) {
+	root := t.TempDir()
+	content := `/*
+# Module: a.go
+
+## Tags
+auth, api
+
+<!-- LinkedDoc RDF -->
+<#a.go> a code:Module ;
+    code:tags "auth", "api" .
+<!-- End LinkedDoc RDF -->
+*/
+package a
+`
+	if err := os.WriteFile(filepath.Join(root, "a.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	edits, err := ProposeTagSourceEdits(root, []string{"a.go", "missing.go"}, []string{"auth"}, "authentication")
+	if err != nil {
+		t.Fatalf("ProposeTagSourceEdits failed: %v", err)
+	}
+	if len(edits) != 2 {
+		t.Fatalf("Expected 2 edits, got %d: %+v", len(edits), edits)
+	}
+
+	if edits[0].Line != 5 || edits[0].After != "authentication, api" {
+		t.Errorf("Unexpected markdown edit: %+v", edits[0])
+	}
+	if edits[1].After != `    code:tags "authentication", "api" .` {
+		t.Errorf("Unexpected RDF edit: %q", edits[1].After)
+	}
+}
+
+func TestProposeTagSourceEdits_MergesLine(t *testing.T) {
+	root := t.TempDir()
+	content := `/*
+# Module: a.go
+
+## Tags
+auth, authn, api
+
+<!-- LinkedDoc RDF -->
+<#a.go> a code:Module ;
+    code:tags "auth", "authn", "api" .
+<!-- End LinkedDoc RDF -->
+*/
+package a
+
+// Not part of the header:
+// code:tags "auth"
+`
+	if err := os.WriteFile(filepath.Join(root, "a.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	edits, err := ProposeTagSourceEdits(root, []string{"a.go"}, []string{"auth", "authn"}, "authentication")
+	if err != nil {
+		t.Fatalf("ProposeTagSourceEdits failed: %v", err)
+	}
+	if len(edits) != 2 {
+		t.Fatalf("Expected one edit per header line, got %d: %+v", len(edits), edits)
+	}
+	if edits[0].After != "authentication, api" {
+		t.Errorf("Unexpected markdown edit: %q", edits[0].After)
+	}
+	if edits[1].After != `    code:tags "authentication", "api" .` {
+		t.Errorf("Unexpected RDF edit: %q", edits[1].After)
+	}
+}
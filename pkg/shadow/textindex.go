@@ -0,0 +1,264 @@
+/*
+# Module: pkg/shadow/textindex.go
+Full-text search over shadow entries.
+
+Tokenizes an entry's path, name, description, exports, tags, concepts and
+annotation values into weighted terms stored with its index entry. The index
+builds an inverted term index from them alongside the tag and concept
+indexes, and ranks text queries with BM25. Identifiers are split at
+camelCase boundaries, so "ParseConfig" is found by "parse" and by
+"parseconfig"; query terms also match as prefixes.
+
+## Linked Modules
+- [index](./index.go) - Shadow index
+- [entry](./entry.go) - Shadow entry data structure
+- [expiry](./expiry.go) - Active annotations
+
+## Tags
+shadow, index, search, full-text
+
+## Exports
+SearchResult
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#textindex.go> a code:Module ;
+    code:name "pkg/shadow/textindex.go" ;
+    code:description "Full-text search over shadow entries" ;
+    code:language "go" ;
+    code:layer "shadow" ;
+    code:linksTo <./index.go>, <./entry.go>, <./expiry.go> ;
+    code:exports <#SearchResult> ;
+    code:tags "shadow", "index", "search", "full-text" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SearchResult is a search match with its relevance score. Score is zero
+// when the query has no text.
+type SearchResult struct {
+	Path  string  `json:"path"`
+	Score float64 `json:"score"`
+}
+
+// Term weights by field: identifying fields count more than prose
+const (
+	identifierWeight = 2
+	textWeight       = 1
+)
+
+// BM25 parameters, and the weight of a query term matching only as a prefix
+const (
+	bm25K1       = 1.2
+	bm25B        = 0.75
+	prefixWeight = 0.5
+)
+
+// textIndex is the inverted term index of the shadow index
+type textIndex struct {
+	postings  map[string]map[string]int // term -> path -> weighted frequency
+	vocab     []string                  // sorted terms, for prefix lookups
+	docLen    map[string]int            // path -> sum of term frequencies
+	avgDocLen float64
+}
+
+// newTextIndex builds the term index of the given entries
+func newTextIndex(entries map[string]*IndexEntry) *textIndex {
+	t := &textIndex{
+		postings: make(map[string]map[string]int),
+		docLen:   make(map[string]int, len(entries)),
+	}
+
+	total := 0
+	for _, entry := range entries {
+		terms := entry.Terms
+		if terms == nil {
+			// Entries indexed before full-text search only have their
+			// identifying fields
+			terms = make(map[string]int)
+			addTerms(terms, identifierWeight, entry.Path, entry.Name)
+			addTerms(terms, identifierWeight, entry.Tags...)
+			addTerms(terms, identifierWeight, entry.Concepts...)
+			addTerms(terms, textWeight, entry.URI)
+		}
+		for term, freq := range terms {
+			if t.postings[term] == nil {
+				t.postings[term] = make(map[string]int)
+			}
+			t.postings[term][entry.Path] = freq
+			t.docLen[entry.Path] += freq
+			total += freq
+		}
+	}
+
+	t.vocab = make([]string, 0, len(t.postings))
+	for term := range t.postings {
+		t.vocab = append(t.vocab, term)
+	}
+	sort.Strings(t.vocab)
+	if len(entries) > 0 {
+		t.avgDocLen = float64(total) / float64(len(entries))
+	}
+	return t
+}
+
+// score returns the BM25 score of every entry matching all terms of the
+// query. ok is false if the query has no searchable terms.
+func (t *textIndex) score(query string, entries int) (scores map[string]float64, ok bool) {
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return nil, false
+	}
+
+	for i, term := range terms {
+		termScores := make(map[string]float64)
+		for j := sort.SearchStrings(t.vocab, term); j < len(t.vocab) && strings.HasPrefix(t.vocab[j], term); j++ {
+			match := t.vocab[j]
+			weight := 1.0
+			if match != term {
+				weight = prefixWeight
+			}
+
+			postings := t.postings[match]
+			df := float64(len(postings))
+			idf := math.Log(1 + (float64(entries)-df+0.5)/(df+0.5))
+			for path, freq := range postings {
+				tf := float64(freq)
+				norm := 1 - bm25B + bm25B*float64(t.docLen[path])/t.avgDocLen
+				termScores[path] += weight * idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+			}
+		}
+
+		// Entries must match every query term
+		if i == 0 {
+			scores = termScores
+			continue
+		}
+		for path, score := range scores {
+			if termScore, ok := termScores[path]; ok {
+				scores[path] = score + termScore
+			} else {
+				delete(scores, path)
+			}
+		}
+	}
+	return scores, true
+}
+
+// entryTerms returns the weighted search terms of a shadow entry
+func entryTerms(path string, entry *Entry) map[string]int {
+	terms := make(map[string]int)
+	addTerms(terms, identifierWeight, path)
+	addTerms(terms, identifierWeight, entry.Exports...)
+	addTerms(terms, identifierWeight, entry.Concepts...)
+	if entry.Module != nil {
+		addTerms(terms, identifierWeight, entry.Module.Name)
+		addTerms(terms, identifierWeight, entry.Module.Tags...)
+		addTerms(terms, textWeight, entry.Module.URI, entry.Module.Description)
+	}
+	for _, annotation := range entry.ActiveAnnotations(time.Now()) {
+		addTerms(terms, textWeight, annotationText(annotation.Value)...)
+	}
+	return terms
+}
+
+// addTerms adds the tokens of texts to terms with the given weight
+func addTerms(terms map[string]int, weight int, texts ...string) {
+	for _, text := range texts {
+		for _, word := range splitWords(text) {
+			if len(word) > 1 {
+				addToken(terms, strings.ToLower(strings.Join(word, "")), weight)
+				for _, part := range word {
+					addToken(terms, strings.ToLower(part), weight)
+				}
+			} else {
+				addToken(terms, strings.ToLower(word[0]), weight)
+			}
+		}
+	}
+}
+
+// addToken counts a token, ignoring single characters
+func addToken(terms map[string]int, token string, weight int) {
+	if len([]rune(token)) > 1 {
+		terms[token] += weight
+	}
+}
+
+// queryTerms tokenizes a text query into distinct terms. Identifiers are
+// split into their parts, so "parseConfig" matches "ParseConfig" and
+// "parse_config" alike.
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range splitWords(query) {
+		for _, part := range word {
+			term := strings.ToLower(part)
+			if len([]rune(term)) > 1 && !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// splitWords splits text into words of letters and digits, each split into
+// its camelCase parts ("HTTPServer" -> "HTTP", "Server")
+func splitWords(text string) [][]string {
+	var words [][]string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(field)
+		var parts []string
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+			acronymEnd := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				parts = append(parts, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, append(parts, string(runes[start:])))
+	}
+	return words
+}
+
+// annotationText returns the text of an annotation value, including the
+// elements of lists and values of maps
+func annotationText(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []interface{}:
+		var texts []string
+		for _, item := range v {
+			texts = append(texts, annotationText(item)...)
+		}
+		return texts
+	case map[string]interface{}:
+		var texts []string
+		for _, item := range v {
+			texts = append(texts, annotationText(item)...)
+		}
+		return texts
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
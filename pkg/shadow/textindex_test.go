@@ -0,0 +1,121 @@
+/*
+# Module: pkg/shadow/textindex_test.go
+Tests for full-text search over shadow entries.
+
+## Tags
+shadow, test, search
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#textindex_test.go> a code:Module ;
+    code:name "pkg/shadow/textindex_test.go" ;
+    code:description "Tests for full-text search over shadow entries" ;
+    code:language "go" ;
+    code:layer "test" ;
+    code:linksTo <./textindex.go> ;
+    code:tags "shadow", "test", "search" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package shadow
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTextTestIndex() *Index {
+	idx := NewIndex()
+
+	auth := NewManualEntry("services/auth.go")
+	auth.SetModule("<#auth.go>", "services/auth.go", "Session login and token validation", "go", "service", []string{"security"})
+	auth.AddExport("ValidateToken")
+	auth.AddExport("HTTPAuthHandler")
+	idx.Add("services/auth.go", auth)
+
+	tokens := NewManualEntry("utils/tokens.go")
+	tokens.SetModule("<#tokens.go>", "utils/tokens.go", "Token helpers", "go", "utility", nil)
+	tokens.AddAnnotation("note", "used by the login flow", "alice")
+	idx.Add("utils/tokens.go", tokens)
+
+	billing := NewManualEntry("billing/charge.go")
+	billing.SetModule("<#charge.go>", "billing/charge.go", "Charges cards", "go", "service", nil)
+	billing.AddAnnotation("runbook", map[string]interface{}{"steps": []interface{}{"retry the payment"}}, "bob")
+	idx.Add("billing/charge.go", billing)
+
+	return idx
+}
+
+func TestIndex_SearchRanked(t *testing.T) {
+	idx := newTextTestIndex()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"token", []string{"utils/tokens.go", "services/auth.go"}}, // exact path term outranks prose
+		{"login", []string{"utils/tokens.go", "services/auth.go"}}, // annotation and description; shorter entry first
+		{"validate token", []string{"services/auth.go"}},           // every term must match
+		{"validateToken", []string{"services/auth.go"}},            // camelCase query
+		{"http handler", []string{"services/auth.go"}},             // acronym export split
+		{"payment", []string{"billing/charge.go"}},                 // nested annotation value
+		{"secur", []string{"services/auth.go"}},                    // prefix of a tag
+		{"refund", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, result := range idx.SearchRanked(SearchQuery{TextQuery: tt.query}) {
+			if result.Score <= 0 {
+				t.Errorf("%q: %s has score %v", tt.query, result.Path, result.Score)
+			}
+			got = append(got, result.Path)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SearchRanked(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	// Text combines with other filters
+	if got := idx.Search(SearchQuery{TextQuery: "token", Layer: "service"}); !reflect.DeepEqual(got, []string{"services/auth.go"}) {
+		t.Errorf("Search(token, layer=service) = %v", got)
+	}
+	// Queries without searchable terms fall back to substring matching
+	if got := idx.Search(SearchQuery{TextQuery: "/"}); len(got) != 3 {
+		t.Errorf("Search(/) = %v", got)
+	}
+
+	// Removed entries are no longer found
+	idx.Remove("utils/tokens.go")
+	if got := idx.Search(SearchQuery{TextQuery: "login"}); !reflect.DeepEqual(got, []string{"services/auth.go"}) {
+		t.Errorf("Search(login) after remove = %v", got)
+	}
+}
+
+func TestIndex_SearchTermsPersist(t *testing.T) {
+	idx := newTextTestIndex()
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded := NewIndex()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Search(SearchQuery{TextQuery: "payment"}); !reflect.DeepEqual(got, []string{"billing/charge.go"}) {
+		t.Errorf("Search(payment) after load = %v", got)
+	}
+
+	// Entries indexed before full-text search are found by their path,
+	// name, tags and concepts
+	for _, entry := range loaded.Entries {
+		entry.Terms = nil
+	}
+	loaded.invertedDirty = true
+	if got := loaded.Search(SearchQuery{TextQuery: "security"}); !reflect.DeepEqual(got, []string{"services/auth.go"}) {
+		t.Errorf("Search(security) without terms = %v", got)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+# Module: pkg/subgraph/subgraph.go
+Scoped sub-graph extraction.
+
+Selects the modules under a directory, plus the modules within a number of
+dependency hops of them, and collects their triples into a self-contained
+subgraph. Dependencies outside the scope are kept as stubs carrying only
+their type and name, so the subgraph can be shared or loaded on its own
+without exposing the rest of the repository's metadata.
+
+## Linked Modules
+- [turtle](./turtle.go) - Turtle serialization
+- [../graph](../graph/graph.go) - Graph data structure
+- [../pathkey](../pathkey/pathkey.go) - Canonical path keys
+
+## Tags
+subgraph, export, rdf, scope
+
+## Exports
+Options, Subgraph, Extract, StubPredicate
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#subgraph.go> a code:Module ;
+    code:name "pkg/subgraph/subgraph.go" ;
+    code:description "Scoped sub-graph extraction" ;
+    code:language "go" ;
+    code:layer "export" ;
+    code:linksTo <./turtle.go>, <../graph/graph.go>, <../pathkey/pathkey.go> ;
+    code:exports <#Options>, <#Subgraph>, <#Extract>, <#StubPredicate> ;
+    code:tags "subgraph", "export", "rdf", "scope" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package subgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/pathkey"
+)
+
+// StubPredicate marks a module outside the subgraph's scope that a module
+// in scope depends on
+const StubPredicate = "https://schema.codedoc.org/external"
+
+const (
+	typePredicate = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	namePredicate = "https://schema.codedoc.org/name"
+	moduleType    = "https://schema.codedoc.org/Module"
+)
+
+// Options configures subgraph extraction
+type Options struct {
+	Root       string // Directory (or file) whose modules are in scope; "" or "." for all
+	Hops       int    // Dependency hops followed from the root modules
+	Dependents bool   // Also follow dependents, not just dependencies
+}
+
+// Subgraph is a self-contained part of a graph
+type Subgraph struct {
+	Root    string         `json:"root"`
+	Hops    int            `json:"hops"`
+	Modules []string       `json:"modules"` // Paths of modules in scope, sorted
+	Stubs   []string       `json:"stubs"`   // Paths of referenced modules out of scope, sorted
+	Triples []store.Triple `json:"-"`       // Triples of modules in scope, then stubs
+}
+
+// Extract returns the subgraph of the modules under opts.Root and those
+// within opts.Hops of them
+func Extract(g *graph.Graph, opts Options) (*Subgraph, error) {
+	if opts.Hops < 0 {
+		return nil, fmt.Errorf("hops must not be negative, got %d", opts.Hops)
+	}
+	root := strings.TrimSuffix(pathkey.Canonical(opts.Root), "/")
+	if root == "." {
+		root = ""
+	}
+
+	keys := pathkey.Default()
+	kept := make(map[string]bool)
+	var frontier []string
+	for p := range g.Modules {
+		if root == "" || keys.Equal(p, root) || strings.HasPrefix(keys.Key(p), keys.Key(root)+"/") {
+			kept[p] = true
+			frontier = append(frontier, p)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("no modules under %s", opts.Root)
+	}
+
+	pathsByURI := make(map[string]string, len(g.Modules))
+	for p, module := range g.Modules {
+		pathsByURI[module.URI] = p
+	}
+
+	for hop := 0; hop < opts.Hops && len(frontier) > 0; hop++ {
+		var next []string
+		for _, p := range frontier {
+			for _, neighbor := range neighbors(g, g.Modules[p], pathsByURI, opts.Dependents) {
+				if !kept[neighbor] {
+					kept[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	sub := &Subgraph{Root: opts.Root, Hops: opts.Hops}
+	stubs := make(map[string]bool)
+	for p := range kept {
+		sub.Modules = append(sub.Modules, p)
+		for _, dep := range neighbors(g, g.Modules[p], pathsByURI, false) {
+			if !kept[dep] {
+				stubs[dep] = true
+			}
+		}
+	}
+	for p := range stubs {
+		sub.Stubs = append(sub.Stubs, p)
+	}
+	sort.Strings(sub.Modules)
+	sort.Strings(sub.Stubs)
+
+	if g.Store != nil {
+		for _, p := range sub.Modules {
+			sub.Triples = append(sub.Triples, moduleTriples(g, g.Modules[p])...)
+		}
+	}
+	for _, p := range sub.Stubs {
+		module := g.Modules[p]
+		sub.Triples = append(sub.Triples,
+			store.Triple{Subject: module.URI, Predicate: typePredicate, Object: moduleType},
+			store.Triple{Subject: module.URI, Predicate: namePredicate, Object: module.Path},
+			store.Triple{Subject: module.URI, Predicate: StubPredicate, Object: "true"},
+		)
+	}
+	return sub, nil
+}
+
+// neighbors returns the paths of the modules a module depends on and, with
+// dependents, those depending on it, sorted
+func neighbors(g *graph.Graph, module *graph.Module, pathsByURI map[string]string, dependents bool) []string {
+	seen := make(map[string]bool)
+	for _, dep := range module.Dependencies {
+		if target := g.GetModule(dep); target != nil {
+			seen[target.Path] = true
+		}
+	}
+	if dependents {
+		for _, uri := range module.Dependents {
+			if p, ok := pathsByURI[uri]; ok {
+				seen[p] = true
+			}
+		}
+	}
+	delete(seen, module.Path)
+
+	paths := make([]string, 0, len(seen))
+	for p := range seen {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// moduleTriples returns the triples about a module, its components and its
+// dependency edge nodes, leaving out named graphs such as the provenance
+func moduleTriples(g *graph.Graph, module *graph.Module) []store.Triple {
+	subjects := []string{module.URI}
+	for _, component := range module.Components {
+		subjects = append(subjects, component.URI)
+	}
+	for _, t := range g.Store.Find(module.URI, graph.DependencyEdgePredicate, "") {
+		subjects = append(subjects, t.Object)
+	}
+
+	var triples []store.Triple
+	for _, subject := range subjects {
+		for _, t := range g.Store.Find(subject, "", "") {
+			if _, named := g.Store.GraphOf(t.Subject, t.Predicate, t.Object); named {
+				continue
+			}
+			triples = append(triples, t)
+		}
+	}
+	return triples
+}
@@ -0,0 +1,145 @@
+package subgraph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shacl"
+)
+
+// buildPaymentsGraph builds api/handler.go -> payments/charge.go ->
+// payments/ledger.go -> db/db.go -> log/log.go
+func buildPaymentsGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	root := t.TempDir()
+	modules := map[string]string{
+		"api/handler.go":     "../payments/charge.go",
+		"payments/charge.go": "./ledger.go",
+		"payments/ledger.go": "../db/db.go",
+		"db/db.go":           "../log/log.go",
+		"log/log.go":         "",
+	}
+	for name, linksTo := range modules {
+		links := ""
+		if linksTo != "" {
+			links = fmt.Sprintf("    code:linksTo <%s> ;\n", linksTo)
+		}
+		content := fmt.Sprintf(`/*
+# Module: %[1]s
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#%[2]s> a code:Module ;
+    code:name "%[1]s" ;
+%[3]s    code:description "Internal notes" .
+<!-- End LinkedDoc RDF -->
+*/
+package x
+`, name, filepath.Base(name), links)
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	g, err := graph.NewBuilder().Build(root, graph.BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return g
+}
+
+func TestExtract(t *testing.T) {
+	g := buildPaymentsGraph(t)
+
+	tests := []struct {
+		name    string
+		opts    Options
+		modules []string
+		stubs   []string
+	}{
+		{"root only", Options{Root: "payments/"}, []string{"payments/charge.go", "payments/ledger.go"}, []string{"db/db.go"}},
+		{"one hop", Options{Root: "payments", Hops: 1}, []string{"db/db.go", "payments/charge.go", "payments/ledger.go"}, []string{"log/log.go"}},
+		{"dependents", Options{Root: "payments", Hops: 1, Dependents: true},
+			[]string{"api/handler.go", "db/db.go", "payments/charge.go", "payments/ledger.go"}, []string{"log/log.go"}},
+		{"single file", Options{Root: "log/log.go", Hops: 3}, []string{"log/log.go"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := Extract(g, tt.opts)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if !reflect.DeepEqual(sub.Modules, tt.modules) {
+				t.Errorf("Modules = %v, want %v", sub.Modules, tt.modules)
+			}
+			if !reflect.DeepEqual(sub.Stubs, tt.stubs) {
+				t.Errorf("Stubs = %v, want %v", sub.Stubs, tt.stubs)
+			}
+		})
+	}
+
+	if _, err := Extract(g, Options{Root: "billing"}); err == nil {
+		t.Error("Extract() should fail without modules in scope")
+	}
+	if _, err := Extract(g, Options{Root: "payments", Hops: -1}); err == nil {
+		t.Error("Extract() should fail with negative hops")
+	}
+}
+
+func TestSubgraph_WriteTurtle(t *testing.T) {
+	g := buildPaymentsGraph(t)
+	sub, err := Extract(g, Options{Root: "payments"})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	var b strings.Builder
+	if err := sub.WriteTurtle(&b); err != nil {
+		t.Fatalf("WriteTurtle() error = %v", err)
+	}
+	output := b.String()
+
+	doc, err := shacl.ParseTurtle(output)
+	if err != nil {
+		t.Fatalf("Output is not valid Turtle: %v\n%s", err, output)
+	}
+
+	descriptions := make(map[string]string)
+	stubs := make(map[string]bool)
+	for _, triple := range doc.Triples {
+		switch triple.Predicate.Value {
+		case "https://schema.codedoc.org/description":
+			descriptions[triple.Subject.Value] = triple.Object.Value
+		case StubPredicate:
+			stubs[triple.Subject.Value] = true
+		}
+	}
+
+	if len(descriptions) != 2 {
+		t.Errorf("Expected descriptions of the 2 modules in scope, got %v", descriptions)
+	}
+	for _, description := range descriptions {
+		if description != "Internal notes" {
+			t.Errorf("Description = %q, want Internal notes", description)
+		}
+	}
+	if len(stubs) != 1 {
+		t.Errorf("Expected 1 stub, got %v\n%s", stubs, output)
+	}
+	if strings.Contains(output, "log.go") || strings.Contains(output, "handler.go") {
+		t.Errorf("Modules beyond the stubs should not be exposed:\n%s", output)
+	}
+	if !strings.Contains(output, "code:DependencyEdge") {
+		t.Errorf("Expected dependency edge nodes:\n%s", output)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+# Module: pkg/subgraph/turtle.go
+Turtle serialization.
+
+Writes subgraph triples as a Turtle document with a comment describing the
+scope. Serialization is shared with the full-graph export in pkg/export.
+
+## Linked Modules
+- [subgraph](./subgraph.go) - Scoped sub-graph extraction
+- [../export](../export/turtle.go) - Turtle serialization
+
+## Tags
+subgraph, export, rdf, turtle
+
+## Exports
+Subgraph.WriteTurtle
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#turtle.go> a code:Module ;
+    code:name "pkg/subgraph/turtle.go" ;
+    code:description "Turtle serialization" ;
+    code:language "go" ;
+    code:layer "export" ;
+    code:linksTo <./subgraph.go>, <../export/turtle.go> ;
+    code:exports <#Subgraph.WriteTurtle> ;
+    code:tags "subgraph", "export", "rdf", "turtle" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package subgraph
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/justin4957/graphfs/pkg/export"
+)
+
+// WriteTurtle writes the subgraph as a Turtle document. Fragment and
+// relative references stay relative.
+func (s *Subgraph) WriteTurtle(w io.Writer) error {
+	scope := s.Root
+	if scope == "" {
+		scope = "."
+	}
+	_, err := export.WriteTurtle(w, s.Triples, export.Options{
+		Header: fmt.Sprintf("Subgraph of %s within %d hop(s): %d module(s), %d external stub(s)",
+			scope, s.Hops, len(s.Modules), len(s.Stubs)),
+	})
+	return err
+}
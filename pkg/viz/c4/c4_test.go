@@ -0,0 +1,219 @@
+package c4
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/viz"
+)
+
+func createTestGraph() *graph.Graph {
+	g := graph.NewGraph("test", store.NewTripleStore())
+
+	handlers := &graph.Module{Path: "api/handlers.go", Description: "API handlers", Language: "go", Layer: "api"}
+	handlers.AddEdge(graph.Edge{Target: "services/auth.go", Weight: 3})
+	handlers.AddEdge(graph.Edge{Target: "services/users.go", Relation: graph.RelationImports, Inferred: true})
+	g.AddModule(handlers)
+
+	auth := &graph.Module{Path: "services/auth.go", Description: "Authentication", Language: "go", Layer: "service"}
+	auth.AddEdge(graph.Edge{Target: "data/users.go"})
+	auth.AddEdge(graph.Edge{Target: "vendor/oauth.go", Inferred: true})
+	g.AddModule(auth)
+
+	users := &graph.Module{Path: "services/users.go", Language: "go", Layer: "service"}
+	users.AddEdge(graph.Edge{Target: "data/users.go"})
+	users.AddEdge(graph.Edge{Target: "services/auth.go"})
+	g.AddModule(users)
+
+	g.AddModule(&graph.Module{Path: "data/users.go", Language: "sql", Layer: "data", Tags: []string{"container:Database"}})
+	g.AddModule(&graph.Module{Path: "vendor/oauth.go", Description: "OAuth provider", Language: "go", Tags: []string{"external", "container:OAuth"}})
+	return g
+}
+
+func TestBuild(t *testing.T) {
+	m := Build(createTestGraph(), Options{System: "Shop", Containers: map[string]string{"service": "Backend"}})
+
+	if m.System != "Shop" {
+		t.Errorf("System = %q", m.System)
+	}
+	var names []string
+	for _, container := range m.Containers {
+		names = append(names, container.Name)
+	}
+	if got := strings.Join(names, ","); got != "Backend,Database,api" {
+		t.Fatalf("containers = %s, want Backend,Database,api", got)
+	}
+	backend := m.Containers[0]
+	if len(backend.Components) != 2 || backend.Components[0].Path != "services/auth.go" || backend.Technology != "go" {
+		t.Errorf("Backend = %+v", backend)
+	}
+	if m.Containers[1].Technology != "sql" {
+		t.Errorf("Database technology = %q, want sql", m.Containers[1].Technology)
+	}
+
+	if len(m.Externals) != 1 || m.Externals[0].Name != "OAuth" || m.Externals[0].Paths[0] != "vendor/oauth.go" {
+		t.Fatalf("externals = %+v", m.Externals)
+	}
+
+	// Dependencies of components, including one on the external system
+	if len(m.Relations) != 6 {
+		t.Fatalf("relations = %d, want 6", len(m.Relations))
+	}
+	external := 0
+	for _, relation := range m.Relations {
+		if relation.External != nil {
+			external++
+			if relation.From.Path != "services/auth.go" || !relation.Inferred {
+				t.Errorf("external relation = %+v", relation)
+			}
+		}
+	}
+	if external != 1 {
+		t.Errorf("external relations = %d, want 1", external)
+	}
+}
+
+func TestBuild_Filter(t *testing.T) {
+	m := Build(createTestGraph(), Options{Filter: &viz.FilterOptions{Layers: []string{"api", "service"}}})
+	if len(m.Containers) != 2 || len(m.Externals) != 0 {
+		t.Fatalf("containers = %d, externals = %d, want 2 and 0", len(m.Containers), len(m.Externals))
+	}
+	if m.System != "Software System" {
+		t.Errorf("System = %q", m.System)
+	}
+}
+
+func TestModelLinks(t *testing.T) {
+	m := Build(createTestGraph(), Options{})
+	links := m.links(func(c *Component) string { return c.Container.Name })
+
+	byPair := make(map[string]link)
+	for _, l := range links {
+		byPair[l.From+">"+l.To] = l
+	}
+	if len(byPair) != 3 {
+		t.Fatalf("links = %+v, want api>service, service>Database, service>e0", links)
+	}
+	api := byPair["api>service"]
+	if api.label() != "imports, linksTo (4)" || api.Inferred {
+		t.Errorf("api>service = %+v (label %q)", api, api.label())
+	}
+	if ext := byPair["service>e0"]; !ext.Inferred {
+		t.Errorf("service>e0 should be inferred: %+v", ext)
+	}
+
+	entries := m.entryContainers()
+	if len(entries) != 1 || entries[0].Name != "api" {
+		t.Errorf("entry containers = %+v, want api", entries)
+	}
+}
+
+func TestGenerateStructurizr(t *testing.T) {
+	dsl, err := GenerateStructurizr(createTestGraph(), Options{System: "Shop", Person: "Customer", Rankdir: "TB"})
+	if err != nil {
+		t.Fatalf("GenerateStructurizr failed: %v", err)
+	}
+
+	for _, want := range []string{
+		`workspace "Shop" "" {`,
+		`user = person "Customer"`,
+		`system = softwareSystem "Shop" "" {`,
+		`c1 = container "api" "1 module" "go" {`,
+		`m1 = component "handlers.go" "API handlers" "go"`,
+		`e0 = softwareSystem "OAuth" "OAuth provider" "External"`,
+		`user -> c1 "Uses"`,
+		`m1 -> m2 "linksTo (3)"`,
+		`m1 -> m3 "imports" "" "Inferred"`,
+		`m2 -> e0 "linksTo" "" "Inferred"`,
+		`systemContext system "SystemContext" {`,
+		`container system "Containers" {`,
+		`component c2 "Components-c2" "service components" {`,
+		`autoLayout tb`,
+		`relationship "Inferred" {`,
+	} {
+		if !strings.Contains(dsl, want) {
+			t.Errorf("DSL missing %q:\n%s", want, dsl)
+		}
+	}
+	if strings.Count(dsl, "{") != strings.Count(dsl, "}") {
+		t.Errorf("unbalanced braces:\n%s", dsl)
+	}
+}
+
+func TestGeneratePlantUML(t *testing.T) {
+	g := createTestGraph()
+
+	t.Run("context", func(t *testing.T) {
+		puml, err := GeneratePlantUML(g, Options{System: "Shop", Person: "User", Level: LevelContext})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"!include <C4/C4_Context>",
+			"title System Context diagram for Shop",
+			`Person(user, "User")`,
+			`System(system, "Shop", "")`,
+			`System_Ext(e0, "OAuth", "OAuth provider")`,
+			`Rel(user, system, "Uses")`,
+			`Rel(system, e0, "linksTo", $tags = "inferred")`,
+			"@enduml",
+		} {
+			if !strings.Contains(puml, want) {
+				t.Errorf("context diagram missing %q:\n%s", want, puml)
+			}
+		}
+	})
+
+	t.Run("container", func(t *testing.T) {
+		puml, err := GeneratePlantUML(g, Options{System: "Shop", Person: "User"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"!include <C4/C4_Container>",
+			`System_Boundary(system, "Shop") {`,
+			`Container(c2, "service", "go", "2 modules")`,
+			`Rel(user, c1, "Uses")`,
+			`Rel(c1, c2, "imports, linksTo (4)")`,
+			`Rel(c2, c0, "linksTo (2)")`,
+		} {
+			if !strings.Contains(puml, want) {
+				t.Errorf("container diagram missing %q:\n%s", want, puml)
+			}
+		}
+	})
+
+	t.Run("component", func(t *testing.T) {
+		puml, err := GeneratePlantUML(g, Options{Level: LevelComponent, Container: "Service", Rankdir: "TB"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"!include <C4/C4_Component>",
+			`Container_Boundary(c2, "service") {`,
+			`Component(m2, "auth.go", "go", "Authentication")`,
+			`Container(c1, "api", "go", "1 module")`,
+			`Rel(c1, m2, "linksTo (3)")`,
+			`Rel(m3, m2, "linksTo")`,
+			`Rel(m2, e0, "linksTo", $tags = "inferred")`,
+		} {
+			if !strings.Contains(puml, want) {
+				t.Errorf("component diagram missing %q:\n%s", want, puml)
+			}
+		}
+		if strings.Contains(puml, "LAYOUT_LEFT_RIGHT") || strings.Contains(puml, "handlers.go") {
+			t.Errorf("component diagram should be top-down and collapse other containers:\n%s", puml)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := GeneratePlantUML(g, Options{Level: "deployment"}); err == nil {
+			t.Error("expected error for unknown level")
+		}
+		if _, err := GeneratePlantUML(g, Options{Level: LevelComponent, Container: "missing"}); err == nil {
+			t.Error("expected error for unknown container")
+		}
+	})
+}
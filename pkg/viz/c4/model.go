@@ -0,0 +1,374 @@
+/*
+# Module: pkg/viz/c4/model.go
+C4 model derived from LinkedDoc metadata.
+
+Maps modules to the C4 levels: the scanned codebase is one software system,
+each layer is a container (a "container:<name>" tag or a layer mapping
+overrides it), and each module is a component. Modules tagged "external"
+become external software systems. Dependencies between components are
+aggregated into relationships at the container and context levels.
+
+## Linked Modules
+- [structurizr](./structurizr.go) - Structurizr DSL generation
+- [plantuml](./plantuml.go) - C4-PlantUML generation
+- [../dot](../dot.go) - Filter options
+
+## Tags
+visualization, c4, architecture
+
+## Exports
+Level, LevelContext, LevelContainer, LevelComponent, Options, Model, Container, Component, External, Relation, Build
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#model.go> a code:Module ;
+    code:name "pkg/viz/c4/model.go" ;
+    code:description "C4 model derived from LinkedDoc metadata" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./structurizr.go>, <./plantuml.go>, <../dot.go> ;
+    code:exports <#Level>, <#LevelContext>, <#LevelContainer>, <#LevelComponent>, <#Options>, <#Model>,
+                 <#Container>, <#Component>, <#External>, <#Relation>, <#Build> ;
+    code:tags "visualization", "c4", "architecture" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package c4
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/viz"
+)
+
+// Level is a C4 abstraction level
+type Level string
+
+const (
+	LevelContext   Level = "context"   // The system, its users and external systems
+	LevelContainer Level = "container" // Containers inside the system
+	LevelComponent Level = "component" // Components inside containers
+)
+
+const (
+	containerTagPrefix = "container:" // Tag naming a module's container
+	externalTag        = "external"   // Tag marking an external system
+)
+
+// Options configures C4 model and view generation
+type Options struct {
+	System      string            // Software system name (default: "Software System")
+	Description string            // Software system description
+	Person      string            // Person using the system ("" = none)
+	Containers  map[string]string // Container name by layer
+	Filter      *viz.FilterOptions
+	Level       Level  // Level of C4-PlantUML diagrams (default: container)
+	Container   string // Container of component diagrams (default: all)
+	Rankdir     string // Diagram direction (LR, TB, RL, BT)
+	Title       string
+}
+
+// Model is a C4 model of a codebase
+type Model struct {
+	System      string
+	Description string
+	Person      string
+	Containers  []*Container // Ordered by name
+	Externals   []*External  // Ordered by name
+	Relations   []Relation   // Between components, and to external systems
+}
+
+// Container is a group of components, one per layer by default
+type Container struct {
+	ID         string
+	Name       string
+	Technology string // Languages of its components
+	Components []*Component
+}
+
+// Component is a module
+type Component struct {
+	ID          string
+	Name        string
+	Path        string
+	Technology  string
+	Description string
+	Container   *Container
+}
+
+// External is an external software system, made of modules tagged "external"
+type External struct {
+	ID          string
+	Name        string
+	Description string
+	Paths       []string
+}
+
+// Relation is a dependency of a component on a component or external system
+type Relation struct {
+	From     *Component
+	To       *Component // Nil for external systems
+	External *External
+	Relation string
+	Weight   int
+	Inferred bool
+}
+
+// Build maps the modules of a graph to a C4 model. Dependencies of
+// external modules are not part of the model.
+func Build(g *graph.Graph, opts Options) *Model {
+	m := &Model{System: opts.System, Description: opts.Description, Person: opts.Person}
+	if m.System == "" {
+		m.System = "Software System"
+	}
+
+	modules := make([]*graph.Module, 0, len(g.Modules))
+	for _, module := range g.Modules {
+		if opts.Filter.Match(module) {
+			modules = append(modules, module)
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+
+	containers := make(map[string]*Container)
+	externals := make(map[string]*External)
+	components := make(map[string]*Component, len(modules))
+	externalOf := make(map[string]*External)
+	for _, module := range modules {
+		name := containerName(module, opts.Containers)
+		if hasTag(module, externalTag) {
+			if _, ok := containerTag(module); !ok {
+				name = moduleName(module)
+			}
+			ext, ok := externals[name]
+			if !ok {
+				ext = &External{Name: name, Description: module.Description}
+				externals[name] = ext
+			}
+			ext.Paths = append(ext.Paths, module.Path)
+			externalOf[module.Path] = ext
+			continue
+		}
+
+		container, ok := containers[name]
+		if !ok {
+			container = &Container{Name: name}
+			containers[name] = container
+		}
+		component := &Component{
+			Name:        moduleName(module),
+			Path:        module.Path,
+			Technology:  module.Language,
+			Description: module.Description,
+			Container:   container,
+		}
+		container.Components = append(container.Components, component)
+		components[module.Path] = component
+	}
+
+	for _, container := range containers {
+		m.Containers = append(m.Containers, container)
+	}
+	sort.Slice(m.Containers, func(i, j int) bool {
+		return m.Containers[i].Name < m.Containers[j].Name
+	})
+	n := 0
+	for i, container := range m.Containers {
+		container.ID = fmt.Sprintf("c%d", i)
+		languages := make(map[string]bool)
+		for _, component := range container.Components {
+			component.ID = fmt.Sprintf("m%d", n)
+			n++
+			if component.Technology != "" {
+				languages[component.Technology] = true
+			}
+		}
+		container.Technology = joinKeys(languages)
+	}
+
+	for _, ext := range externals {
+		m.Externals = append(m.Externals, ext)
+	}
+	sort.Slice(m.Externals, func(i, j int) bool {
+		return m.Externals[i].Name < m.Externals[j].Name
+	})
+	for i, ext := range m.Externals {
+		ext.ID = fmt.Sprintf("e%d", i)
+	}
+
+	for _, module := range modules {
+		from, ok := components[module.Path]
+		if !ok {
+			continue
+		}
+		for _, dep := range module.Dependencies {
+			edge := module.EdgeTo(dep)
+			relation := Relation{From: from, Relation: edge.Relation, Weight: edge.Weight, Inferred: edge.Inferred}
+			if to, ok := components[dep]; ok && to != from {
+				relation.To = to
+			} else if ext, ok := externalOf[dep]; ok {
+				relation.External = ext
+			} else {
+				continue
+			}
+			m.Relations = append(m.Relations, relation)
+		}
+	}
+
+	return m
+}
+
+// link is a relationship between two elements of a view, aggregating the
+// relations between their components
+type link struct {
+	From, To  string
+	Relations []string
+	Weight    int
+	Inferred  bool // Every aggregated relation was inferred
+}
+
+// label describes the relations of a link, with the weight above one
+func (l link) label() string {
+	label := strings.Join(l.Relations, ", ")
+	if l.Weight > 1 {
+		label += fmt.Sprintf(" (%d)", l.Weight)
+	}
+	return label
+}
+
+// links aggregates relations between the view elements returned by
+// element; relations within one element are dropped
+func (m *Model) links(element func(*Component) string) []link {
+	byKey := make(map[[2]string]*link)
+	relations := make(map[[2]string]map[string]bool)
+	var keys [][2]string
+	for _, relation := range m.Relations {
+		from := element(relation.From)
+		to := ""
+		if relation.To != nil {
+			to = element(relation.To)
+		} else {
+			to = relation.External.ID
+		}
+		if from == to {
+			continue
+		}
+		key := [2]string{from, to}
+		l, ok := byKey[key]
+		if !ok {
+			l = &link{From: from, To: to, Inferred: true}
+			byKey[key] = l
+			relations[key] = make(map[string]bool)
+			keys = append(keys, key)
+		}
+		name := relation.Relation
+		if name == "" {
+			name = graph.RelationLinksTo
+		}
+		relations[key][name] = true
+		l.Weight += relation.Weight
+		l.Inferred = l.Inferred && relation.Inferred
+	}
+
+	links := make([]link, 0, len(keys))
+	for _, key := range keys {
+		l := byKey[key]
+		l.Relations = strings.Split(joinKeys(relations[key]), ", ")
+		links = append(links, *l)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].From != links[j].From {
+			return links[i].From < links[j].From
+		}
+		return links[i].To < links[j].To
+	})
+	return links
+}
+
+// entryContainers returns the containers no other container depends on,
+// which the person uses
+func (m *Model) entryContainers() []*Container {
+	used := make(map[string]bool)
+	for _, l := range m.links(func(c *Component) string { return c.Container.ID }) {
+		used[l.To] = true
+	}
+	var entries []*Container
+	for _, container := range m.Containers {
+		if !used[container.ID] {
+			entries = append(entries, container)
+		}
+	}
+	return entries
+}
+
+// container returns the container with a name
+func (m *Model) container(name string) (*Container, error) {
+	for _, container := range m.Containers {
+		if strings.EqualFold(container.Name, name) {
+			return container, nil
+		}
+	}
+	names := make([]string, 0, len(m.Containers))
+	for _, container := range m.Containers {
+		names = append(names, container.Name)
+	}
+	return nil, fmt.Errorf("unknown container %q (have %s)", name, strings.Join(names, ", "))
+}
+
+// containerName returns the container of a module: its container tag, its
+// mapped layer, or its layer
+func containerName(module *graph.Module, layers map[string]string) string {
+	if name, ok := containerTag(module); ok {
+		return name
+	}
+	if name, ok := layers[module.Layer]; ok && name != "" {
+		return name
+	}
+	if module.Layer == "" {
+		return "unknown"
+	}
+	return module.Layer
+}
+
+// containerTag returns the value of a module's container tag
+func containerTag(module *graph.Module) (string, bool) {
+	for _, tag := range module.Tags {
+		if len(tag) > len(containerTagPrefix) && strings.EqualFold(tag[:len(containerTagPrefix)], containerTagPrefix) {
+			return strings.TrimSpace(tag[len(containerTagPrefix):]), true
+		}
+	}
+	return "", false
+}
+
+// hasTag reports whether a module has a tag, ignoring case
+func hasTag(module *graph.Module, tag string) bool {
+	for _, t := range module.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleName returns the display name of a module
+func moduleName(module *graph.Module) string {
+	return filepath.Base(module.Path)
+}
+
+// joinKeys returns the keys of a set, sorted and comma-separated
+func joinKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,188 @@
+/*
+# Module: pkg/viz/c4/plantuml.go
+C4-PlantUML generation.
+
+Writes one C4 view of the model with the C4-PlantUML macros bundled in the
+PlantUML standard library: a system context diagram, a container diagram
+inside the system boundary, or a component diagram of one container (or of
+every container). Relationships are aggregated to the elements of the view
+and dashed when every dependency behind them was inferred.
+
+## Linked Modules
+- [model](./model.go) - C4 model
+- [../plantuml](../plantuml.go) - PlantUML escaping
+
+## Tags
+visualization, c4, plantuml, export
+
+## Exports
+GeneratePlantUML
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#plantuml.go> a code:Module ;
+    code:name "pkg/viz/c4/plantuml.go" ;
+    code:description "C4-PlantUML generation" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./model.go>, <../plantuml.go> ;
+    code:exports <#GeneratePlantUML> ;
+    code:tags "visualization", "c4", "plantuml", "export" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package c4
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/viz"
+)
+
+// plantUMLIncludes maps levels to their C4-PlantUML standard library file
+var plantUMLIncludes = map[Level]string{
+	LevelContext:   "C4/C4_Context",
+	LevelContainer: "C4/C4_Container",
+	LevelComponent: "C4/C4_Component",
+}
+
+// GeneratePlantUML generates a C4-PlantUML diagram of the graph at
+// opts.Level
+func GeneratePlantUML(g *graph.Graph, opts Options) (string, error) {
+	if opts.Level == "" {
+		opts.Level = LevelContainer
+	}
+	include, ok := plantUMLIncludes[opts.Level]
+	if !ok {
+		return "", fmt.Errorf("unknown C4 level %q (use context, container or component)", opts.Level)
+	}
+
+	m := Build(g, opts)
+	var focus *Container
+	if opts.Level == LevelComponent && opts.Container != "" {
+		container, err := m.container(opts.Container)
+		if err != nil {
+			return "", err
+		}
+		focus = container
+	}
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	fmt.Fprintf(&b, "!include <%s>\n", include)
+	if opts.Rankdir == "" || opts.Rankdir == "LR" || opts.Rankdir == "RL" {
+		b.WriteString("LAYOUT_LEFT_RIGHT()\n")
+	}
+	b.WriteString("AddRelTag(\"inferred\", $lineStyle = DashedLine())\n")
+	title := opts.Title
+	if title == "" {
+		title = plantUMLTitle(m, opts.Level, focus)
+	}
+	fmt.Fprintf(&b, "title %s\n\n", viz.PlantUMLEscape(title))
+
+	var links []link
+	switch opts.Level {
+	case LevelContext:
+		if m.Person != "" {
+			fmt.Fprintf(&b, "Person(user, \"%s\")\n", viz.PlantUMLEscape(m.Person))
+		}
+		fmt.Fprintf(&b, "System(system, \"%s\", \"%s\")\n", viz.PlantUMLEscape(m.System), viz.PlantUMLEscape(m.Description))
+		writePlantUMLExternals(&b, m)
+		if m.Person != "" {
+			links = append(links, link{From: "user", To: "system", Relations: []string{"Uses"}})
+		}
+		links = append(links, m.links(func(*Component) string { return "system" })...)
+
+	case LevelContainer:
+		if m.Person != "" {
+			fmt.Fprintf(&b, "Person(user, \"%s\")\n", viz.PlantUMLEscape(m.Person))
+		}
+		fmt.Fprintf(&b, "System_Boundary(system, \"%s\") {\n", viz.PlantUMLEscape(m.System))
+		for _, container := range m.Containers {
+			fmt.Fprintf(&b, "  Container(%s, \"%s\", \"%s\", \"%s\")\n", container.ID, viz.PlantUMLEscape(container.Name),
+				viz.PlantUMLEscape(container.Technology), componentCount(container))
+		}
+		b.WriteString("}\n")
+		writePlantUMLExternals(&b, m)
+		if m.Person != "" {
+			for _, container := range m.entryContainers() {
+				links = append(links, link{From: "user", To: container.ID, Relations: []string{"Uses"}})
+			}
+		}
+		links = append(links, m.links(func(c *Component) string { return c.Container.ID })...)
+
+	case LevelComponent:
+		// Components of the focused container; other containers collapse
+		inFocus := func(c *Container) bool { return focus == nil || c == focus }
+		links = m.links(func(c *Component) string {
+			if inFocus(c.Container) {
+				return c.ID
+			}
+			return c.Container.ID
+		})
+		shown := make(map[string]bool)
+		for _, l := range links {
+			shown[l.From] = true
+			shown[l.To] = true
+		}
+
+		for _, container := range m.Containers {
+			if !inFocus(container) {
+				continue
+			}
+			fmt.Fprintf(&b, "Container_Boundary(%s, \"%s\") {\n", container.ID, viz.PlantUMLEscape(container.Name))
+			for _, component := range container.Components {
+				fmt.Fprintf(&b, "  Component(%s, \"%s\", \"%s\", \"%s\")\n", component.ID, viz.PlantUMLEscape(component.Name),
+					viz.PlantUMLEscape(component.Technology), viz.PlantUMLEscape(component.Description))
+			}
+			b.WriteString("}\n")
+		}
+		for _, container := range m.Containers {
+			if !inFocus(container) && shown[container.ID] {
+				fmt.Fprintf(&b, "Container(%s, \"%s\", \"%s\", \"%s\")\n", container.ID, viz.PlantUMLEscape(container.Name),
+					viz.PlantUMLEscape(container.Technology), componentCount(container))
+			}
+		}
+		for _, ext := range m.Externals {
+			if shown[ext.ID] {
+				fmt.Fprintf(&b, "System_Ext(%s, \"%s\", \"%s\")\n", ext.ID, viz.PlantUMLEscape(ext.Name), viz.PlantUMLEscape(ext.Description))
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	for _, l := range links {
+		fmt.Fprintf(&b, "Rel(%s, %s, \"%s\"", l.From, l.To, viz.PlantUMLEscape(l.label()))
+		if l.Inferred {
+			b.WriteString(`, $tags = "inferred"`)
+		}
+		b.WriteString(")\n")
+	}
+	b.WriteString("\nSHOW_LEGEND()\n@enduml\n")
+	return b.String(), nil
+}
+
+// writePlantUMLExternals writes the external systems
+func writePlantUMLExternals(b *strings.Builder, m *Model) {
+	for _, ext := range m.Externals {
+		fmt.Fprintf(b, "System_Ext(%s, \"%s\", \"%s\")\n", ext.ID, viz.PlantUMLEscape(ext.Name), viz.PlantUMLEscape(ext.Description))
+	}
+}
+
+// plantUMLTitle returns the default title of a view
+func plantUMLTitle(m *Model, level Level, focus *Container) string {
+	switch level {
+	case LevelContext:
+		return "System Context diagram for " + m.System
+	case LevelContainer:
+		return "Container diagram for " + m.System
+	}
+	if focus != nil {
+		return "Component diagram for " + m.System + " - " + focus.Name
+	}
+	return "Component diagram for " + m.System
+}
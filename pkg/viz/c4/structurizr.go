@@ -0,0 +1,157 @@
+/*
+# Module: pkg/viz/c4/structurizr.go
+Structurizr DSL generation.
+
+Writes the C4 model as a Structurizr DSL workspace: the person, the software
+system with a container per layer and a component per module, external
+systems, and the relationships between components. Structurizr derives
+container and system relationships from them. The workspace defines a system
+context view, a container view and a component view per container, with the
+standard C4 styles.
+
+## Linked Modules
+- [model](./model.go) - C4 model
+
+## Tags
+visualization, c4, structurizr, export
+
+## Exports
+GenerateStructurizr
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#structurizr.go> a code:Module ;
+    code:name "pkg/viz/c4/structurizr.go" ;
+    code:description "Structurizr DSL generation" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./model.go> ;
+    code:exports <#GenerateStructurizr> ;
+    code:tags "visualization", "c4", "structurizr", "export" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package c4
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// structurizrDirections maps DOT rank directions to autoLayout directions
+var structurizrDirections = map[string]string{
+	"LR": "lr",
+	"RL": "rl",
+	"TB": "tb",
+	"BT": "bt",
+}
+
+// GenerateStructurizr generates a Structurizr DSL workspace with context,
+// container and component views of the graph
+func GenerateStructurizr(g *graph.Graph, opts Options) (string, error) {
+	m := Build(g, opts)
+	direction := structurizrDirections[opts.Rankdir]
+	if direction == "" {
+		direction = "lr"
+	}
+	name := opts.Title
+	if name == "" {
+		name = m.System
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "workspace %s %s {\n\n", structurizrQuote(name), structurizrQuote(m.Description))
+	b.WriteString("    model {\n")
+	if m.Person != "" {
+		fmt.Fprintf(&b, "        user = person %s\n", structurizrQuote(m.Person))
+	}
+	fmt.Fprintf(&b, "        system = softwareSystem %s %s {\n", structurizrQuote(m.System), structurizrQuote(m.Description))
+	for _, container := range m.Containers {
+		fmt.Fprintf(&b, "            %s = container %s %s %s {\n", container.ID, structurizrQuote(container.Name),
+			structurizrQuote(componentCount(container)), structurizrQuote(container.Technology))
+		for _, component := range container.Components {
+			fmt.Fprintf(&b, "                %s = component %s %s %s\n", component.ID, structurizrQuote(component.Name),
+				structurizrQuote(component.Description), structurizrQuote(component.Technology))
+		}
+		b.WriteString("            }\n")
+	}
+	b.WriteString("        }\n")
+	for _, ext := range m.Externals {
+		fmt.Fprintf(&b, "        %s = softwareSystem %s %s \"External\"\n", ext.ID, structurizrQuote(ext.Name),
+			structurizrQuote(ext.Description))
+	}
+
+	b.WriteString("\n")
+	if m.Person != "" {
+		for _, container := range m.entryContainers() {
+			fmt.Fprintf(&b, "        user -> %s \"Uses\"\n", container.ID)
+		}
+	}
+	for _, l := range m.links(func(c *Component) string { return c.ID }) {
+		fmt.Fprintf(&b, "        %s -> %s %s", l.From, l.To, structurizrQuote(l.label()))
+		if l.Inferred {
+			b.WriteString(` "" "Inferred"`)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("    }\n\n")
+
+	b.WriteString("    views {\n")
+	fmt.Fprintf(&b, "        systemContext system \"SystemContext\" {\n            include *\n            autoLayout %s\n        }\n\n", direction)
+	fmt.Fprintf(&b, "        container system \"Containers\" {\n            include *\n            autoLayout %s\n        }\n", direction)
+	for _, container := range m.Containers {
+		fmt.Fprintf(&b, "\n        component %s \"Components-%s\" %s {\n            include *\n            autoLayout %s\n        }\n",
+			container.ID, container.ID, structurizrQuote(container.Name+" components"), direction)
+	}
+	b.WriteString(`
+        styles {
+            element "Person" {
+                shape person
+                background #08427B
+                color #ffffff
+            }
+            element "Software System" {
+                background #1168BD
+                color #ffffff
+            }
+            element "Container" {
+                background #438DD5
+                color #ffffff
+            }
+            element "Component" {
+                background #85BBF0
+                color #000000
+            }
+            element "External" {
+                background #999999
+                color #ffffff
+            }
+            relationship "Inferred" {
+                style dashed
+            }
+        }
+    }
+}
+`)
+	return b.String(), nil
+}
+
+// componentCount describes how many components a container has
+func componentCount(container *Container) string {
+	if len(container.Components) == 1 {
+		return "1 module"
+	}
+	return fmt.Sprintf("%d modules", len(container.Components))
+}
+
+// structurizrQuote quotes a Structurizr DSL string
+func structurizrQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	return `"` + s + `"`
+}
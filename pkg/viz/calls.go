@@ -0,0 +1,116 @@
+/*
+# Module: pkg/viz/calls.go
+Visualization of call traces.
+
+Draws the callers and callees of a symbol as DOT, with one cluster per
+module. The traced symbol is highlighted, callers and callees are colored
+apart, and symbols outside the graph (such as fmt.Errorf) are dashed.
+
+## Linked Modules
+- [dot](./dot.go) - DOT generation helpers
+- [../analysis](../analysis/calls.go) - Call graph analysis
+
+## Tags
+visualization, calls, call-graph, graphviz
+
+## Exports
+GenerateCallDOT
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#calls.go> a code:Module ;
+    code:name "pkg/viz/calls.go" ;
+    code:description "Visualization of call traces" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./dot.go>, <../analysis/calls.go> ;
+    code:exports <#GenerateCallDOT> ;
+    code:tags "visualization", "calls", "call-graph", "graphviz" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+)
+
+// GenerateCallDOT generates DOT for a call trace: its symbols and every call
+// between them
+func GenerateCallDOT(cg *analysis.CallGraph, trace *analysis.CallTrace, title string) string {
+	fills := map[string]string{trace.Symbol: "#FFE082"}
+	for _, site := range trace.Callers {
+		fills[site.Symbol] = "#C8E6C9"
+	}
+	for _, site := range trace.Callees {
+		if _, ok := fills[site.Symbol]; !ok {
+			fills[site.Symbol] = "#BBDEFB"
+		}
+	}
+
+	// Symbols by module, with symbols outside the graph last
+	byModule := make(map[string][]string)
+	for symbol := range fills {
+		module := cg.Module(symbol)
+		byModule[module] = append(byModule[module], symbol)
+	}
+	modules := make([]string, 0, len(byModule))
+	for module, symbols := range byModule {
+		sort.Strings(symbols)
+		if module != "" {
+			modules = append(modules, module)
+		}
+	}
+	sort.Strings(modules)
+
+	var b strings.Builder
+	b.WriteString("digraph Calls {\n")
+	if title != "" {
+		fmt.Fprintf(&b, "  label=\"%s\";\n  labelloc=t;\n", escapeLabel(title))
+	}
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n\n")
+
+	for i, module := range modules {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=\"%s\";\n    style=rounded;\n    color=\"#9E9E9E\";\n", escapeLabel(module))
+		for _, symbol := range byModule[module] {
+			label := module
+			if _, name, ok := strings.Cut(symbol, "#"); ok {
+				label = name
+			}
+			attrs := fmt.Sprintf("label=\"%s\", fillcolor=\"%s\"", escapeLabel(label), fills[symbol])
+			if symbol == trace.Symbol {
+				attrs += ", penwidth=2"
+			}
+			fmt.Fprintf(&b, "    \"%s\" [%s];\n", escapeLabel(symbol), attrs)
+		}
+		b.WriteString("  }\n")
+	}
+	for _, symbol := range byModule[""] {
+		fmt.Fprintf(&b, "  \"%s\" [fillcolor=\"%s\", style=\"rounded,dashed,filled\"];\n", escapeLabel(symbol), fills[symbol])
+	}
+
+	b.WriteString("\n")
+	symbols := make([]string, 0, len(fills))
+	for symbol := range fills {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, from := range symbols {
+		for _, to := range cg.Callees[from] {
+			if _, ok := fills[to]; ok {
+				fmt.Fprintf(&b, "  \"%s\" -> \"%s\";\n", escapeLabel(from), escapeLabel(to))
+			}
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package viz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/scanner"
+)
+
+func TestGenerateCallDOT(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"api.go": `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#api.go> a code:Module ;
+    code:name "api.go" .
+<#Handle> a code:Function ;
+    code:calls <./auth.go#Check> .
+<!-- End LinkedDoc RDF -->
+*/
+package api
+`,
+		"auth.go": `/*
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#auth.go> a code:Module ;
+    code:name "auth.go" .
+<#Check> a code:Function ;
+    code:calls <./log.go#Info> .
+<!-- End LinkedDoc RDF -->
+*/
+package api
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g, err := graph.NewBuilder().Build(root, graph.BuildOptions{ScanOptions: scanner.ScanOptions{UseDefaults: true}})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	cg := analysis.BuildCallGraph(g)
+	trace, err := cg.Trace("Check", analysis.CallOptions{})
+	if err != nil {
+		t.Fatalf("Trace failed: %v", err)
+	}
+
+	dot := GenerateCallDOT(cg, trace, "Calls of Check")
+	for _, want := range []string{
+		`label="Calls of Check"`,
+		`label="api.go";`,
+		`"auth.go#Check" [label="Check", fillcolor="#FFE082", penwidth=2];`,
+		`"api.go#Handle" [label="Handle", fillcolor="#C8E6C9"];`,
+		`"log.go#Info" [fillcolor="#BBDEFB", style="rounded,dashed,filled"];`,
+		`"api.go#Handle" -> "auth.go#Check";`,
+		`"auth.go#Check" -> "log.go#Info";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+/*
+# Module: pkg/viz/collapse.go
+Directory-collapsed visualizations.
+
+Aggregates modules into one super-node per directory, cut at an expand
+depth, so large graphs stay readable. Dependencies between directories are
+bundled into a single edge whose weight counts the module dependencies it
+stands for; DOT and Mermaid label bundled edges with that count. Filters
+apply to modules before they are collapsed.
+
+## Linked Modules
+- [dot](./dot.go) - DOT generation
+- [mermaid](./mermaid.go) - Mermaid generation
+- [sampling](./sampling.go) - Large graph sampling
+
+## Tags
+visualization, large-graphs, collapse
+
+## Exports
+CollapseMode, CollapseDirs, CollapseOptions, DefaultCollapseDepth, CollapseGraph
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#collapse.go> a code:Module ;
+    code:name "pkg/viz/collapse.go" ;
+    code:description "Directory-collapsed visualizations" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./dot.go>, <./mermaid.go>, <./sampling.go> ;
+    code:exports <#CollapseMode>, <#CollapseDirs>, <#CollapseOptions>, <#DefaultCollapseDepth>, <#CollapseGraph> ;
+    code:tags "visualization", "large-graphs", "collapse" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// CollapseMode selects what modules are collapsed into
+type CollapseMode string
+
+const (
+	CollapseDirs CollapseMode = "dirs" // One node per directory
+)
+
+// DefaultCollapseDepth is the default number of directory levels kept
+const DefaultCollapseDepth = 1
+
+// CollapseOptions configures collapsing
+type CollapseOptions struct {
+	Mode  CollapseMode // What to collapse into (default: dirs)
+	Depth int          // Directory levels kept; deeper directories merge into their ancestor (default: 1)
+}
+
+// bundledEdge collects the module dependencies between two directories
+type bundledEdge struct {
+	count      int
+	relations  map[string]bool
+	inferred   bool    // Every bundled dependency was inferred
+	confidence float64 // Highest confidence of a bundled dependency
+}
+
+// CollapseGraph builds a graph with one module per directory, cut at
+// opts.Depth levels, and an edge per pair of directories with
+// dependencies. Edge weights count the bundled module dependencies.
+// Modules failing the filter are left out before collapsing.
+func CollapseGraph(g *graph.Graph, opts CollapseOptions, filter *FilterOptions) (*graph.Graph, error) {
+	if opts.Mode == "" {
+		opts.Mode = CollapseDirs
+	}
+	if opts.Mode != CollapseDirs {
+		return nil, fmt.Errorf("unknown collapse mode: %s (use: dirs)", opts.Mode)
+	}
+	if opts.Depth <= 0 {
+		opts.Depth = DefaultCollapseDepth
+	}
+
+	dirOf := make(map[string]string)
+	members := make(map[string][]*graph.Module)
+	for _, module := range g.Modules {
+		if !filter.Match(module) {
+			continue
+		}
+		dir := collapsedDir(module.Path, opts.Depth)
+		dirOf[module.Path] = dir
+		members[dir] = append(members[dir], module)
+	}
+
+	edges := make(map[string]map[string]*bundledEdge)
+	for _, modules := range members {
+		for _, module := range modules {
+			from := dirOf[module.Path]
+			for _, dep := range module.Dependencies {
+				to, ok := dirOf[dep]
+				edge := module.EdgeTo(dep)
+				if !ok || to == from || !filter.MatchEdge(edge) {
+					continue
+				}
+				if edges[from] == nil {
+					edges[from] = make(map[string]*bundledEdge)
+				}
+				be, ok := edges[from][to]
+				if !ok {
+					be = &bundledEdge{relations: make(map[string]bool), inferred: true}
+					edges[from][to] = be
+				}
+				be.count++
+				be.relations[edge.Relation] = true
+				be.inferred = be.inferred && edge.Inferred
+				be.confidence = math.Max(be.confidence, edge.Confidence)
+			}
+		}
+	}
+
+	collapsed := graph.NewGraph(g.Root, g.Store)
+	nodes := make(map[string]*graph.Module, len(members))
+	for dir, modules := range members {
+		module := graph.NewModule(dir+"/", "<#dir:"+dir+">")
+		module.Name = dir + "/"
+		module.Layer = mostCommon(modules, func(m *graph.Module) string { return m.Layer })
+		module.Language = mostCommon(modules, func(m *graph.Module) string { return m.Language })
+		module.Description = fmt.Sprintf("%d modules", len(modules))
+		if len(modules) == 1 {
+			module.Description = "1 module"
+		}
+		nodes[dir] = module
+	}
+	for from, targets := range edges {
+		dirs := make([]string, 0, len(targets))
+		for to := range targets {
+			dirs = append(dirs, to)
+		}
+		sort.Strings(dirs)
+		for _, to := range dirs {
+			be := targets[to]
+			relation := graph.RelationLinksTo
+			if len(be.relations) == 1 {
+				for r := range be.relations {
+					relation = r
+				}
+			}
+			nodes[from].AddEdge(graph.Edge{
+				Target:     to + "/",
+				Relation:   relation,
+				Weight:     be.count,
+				Inferred:   be.inferred,
+				Confidence: be.confidence,
+			})
+			nodes[to].Dependents = append(nodes[to].Dependents, nodes[from].URI)
+		}
+	}
+	for _, module := range nodes {
+		sort.Strings(module.Dependents)
+		collapsed.AddModule(module)
+	}
+
+	return collapsed, nil
+}
+
+// collapsedDir returns the directory a module path collapses into: its
+// directory, cut after depth levels ("." for the root)
+func collapsedDir(modulePath string, depth int) string {
+	dir := path.Dir(modulePath)
+	if dir == "." {
+		return dir
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
+
+// isCollapsedDir reports whether a module is a collapsed directory node
+func isCollapsedDir(module *graph.Module) bool {
+	return strings.HasSuffix(module.Path, "/")
+}
+
+// bundledEdgeAttributes styles a collapsed edge: labelled with the number of
+// dependencies it bundles, thicker for more, and dashed when all were inferred
+func bundledEdgeAttributes(edge graph.Edge) string {
+	tooltip := fmt.Sprintf("%d dependencies", edge.Weight)
+	if edge.Weight == 1 {
+		tooltip = "1 dependency"
+	}
+	attrs := []string{fmt.Sprintf("label=\"%d\"", edge.Weight), fmt.Sprintf("tooltip=\"%s\"", tooltip)}
+	if edge.Weight > 1 {
+		attrs = append(attrs, fmt.Sprintf("penwidth=%.1f", math.Min(1+math.Log2(float64(edge.Weight)), 6)))
+	}
+	if edge.Inferred {
+		attrs = append(attrs, "style=dashed")
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
+
+// mostCommon returns the most common non-empty value among modules, the
+// alphabetically first on ties
+func mostCommon(modules []*graph.Module, value func(*graph.Module) string) string {
+	counts := make(map[string]int)
+	for _, module := range modules {
+		if v := value(module); v != "" {
+			counts[v]++
+		}
+	}
+	best := ""
+	for v, n := range counts {
+		if n > counts[best] || (n == counts[best] && v < best) {
+			best = v
+		}
+	}
+	return best
+}
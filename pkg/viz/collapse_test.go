@@ -0,0 +1,120 @@
+package viz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestCollapseGraph(t *testing.T) {
+	g := createTestGraph()
+	g.GetModule("services/auth.go").AddEdge(graph.Edge{Target: "data/users.go", Inferred: true})
+
+	collapsed, err := CollapseGraph(g, CollapseOptions{Mode: CollapseDirs}, nil)
+	if err != nil {
+		t.Fatalf("CollapseGraph failed: %v", err)
+	}
+	if len(collapsed.Modules) != 3 {
+		t.Fatalf("modules = %d, want 3 directories", len(collapsed.Modules))
+	}
+
+	services := collapsed.GetModule("services/")
+	if services == nil {
+		t.Fatal("missing services/ node")
+	}
+	if services.Name != "services/" || services.Description != "2 modules" || services.Layer != "service" || services.Language != "go" {
+		t.Errorf("services/ = %+v", services)
+	}
+
+	// Both service modules depend on data/users.go; one edge was inferred
+	edge := services.EdgeTo("data/")
+	if edge.Weight != 2 || edge.Inferred {
+		t.Errorf("services/ -> data/ = %+v, want weight 2, declared", edge)
+	}
+	if api := collapsed.GetModule("api/"); api.EdgeTo("services/").Weight != 2 {
+		t.Errorf("api/ -> services/ weight = %d, want 2", api.EdgeTo("services/").Weight)
+	}
+	if len(collapsed.GetModule("data/").Dependents) != 1 {
+		t.Errorf("data/ dependents = %v", collapsed.GetModule("data/").Dependents)
+	}
+}
+
+func TestCollapseGraph_FilterAndErrors(t *testing.T) {
+	g := createTestGraph()
+	collapsed, err := CollapseGraph(g, CollapseOptions{}, &FilterOptions{Layers: []string{"api", "service"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collapsed.Modules) != 2 || collapsed.GetModule("data/") != nil {
+		t.Errorf("filtered modules = %d, want api/ and services/", len(collapsed.Modules))
+	}
+	if len(collapsed.GetModule("services/").Dependencies) != 0 {
+		t.Error("edges to filtered modules should be dropped")
+	}
+
+	if _, err := CollapseGraph(g, CollapseOptions{Mode: "layers"}, nil); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestCollapsedDir(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"main.go", 2, "."},
+		{"pkg/viz/dot.go", 1, "pkg"},
+		{"pkg/viz/dot.go", 2, "pkg/viz"},
+		{"pkg/viz/c4/model.go", 2, "pkg/viz"},
+		{"pkg/viz/c4/model.go", 5, "pkg/viz/c4"},
+	}
+	for _, tt := range tests {
+		if got := collapsedDir(tt.path, tt.depth); got != tt.want {
+			t.Errorf("collapsedDir(%q, %d) = %q, want %q", tt.path, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateDOT_Collapsed(t *testing.T) {
+	dot, err := GenerateDOT(createTestGraph(), VizOptions{
+		Type:     VizDependency,
+		Collapse: &CollapseOptions{Mode: CollapseDirs},
+		Filter:   &FilterOptions{Tags: []string{"service", "api"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateDOT failed: %v", err)
+	}
+	for _, want := range []string{
+		`"services/" [fillcolor="#90CAF9", label="services/\n2 modules"]`,
+		`"api/" -> "services/" [label="2", tooltip="2 dependencies", penwidth=2.0]`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+	if strings.Contains(dot, "data/") {
+		t.Errorf("filtered directory should be omitted:\n%s", dot)
+	}
+
+	if _, err := GenerateDOT(createTestGraph(), VizOptions{Type: VizSecurity, Collapse: &CollapseOptions{}}); err == nil {
+		t.Error("expected error collapsing a security visualization")
+	}
+}
+
+func TestGenerateMermaid_Collapsed(t *testing.T) {
+	mermaid, err := GenerateMermaid(createTestGraph(), MermaidOptions{Collapse: &CollapseOptions{Mode: CollapseDirs}})
+	if err != nil {
+		t.Fatalf("GenerateMermaid failed: %v", err)
+	}
+	for _, want := range []string{
+		`services_("services/<br/>2 modules")`,
+		"api_ -->|2| services_",
+		"services_ -->|2| data_",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+/*
+# Module: pkg/viz/cycles.go
+Visualization of dependency cycles.
+
+Draws one strongly connected component of the dependency graph as DOT or
+Mermaid: its modules, every dependency between them, and the edges of its
+shortest cycle highlighted as the first candidate to break.
+
+## Linked Modules
+- [dot](./dot.go) - DOT generation helpers
+- [mermaid](./mermaid.go) - Mermaid generation helpers
+- [../analysis](../analysis/cycles.go) - Cycle enumeration
+
+## Tags
+visualization, cycles, graphviz, mermaid
+
+## Exports
+GenerateCycleDOT, GenerateCycleMermaid
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#cycles.go> a code:Module ;
+    code:name "pkg/viz/cycles.go" ;
+    code:description "Visualization of dependency cycles" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./dot.go>, <./mermaid.go>, <../analysis/cycles.go> ;
+    code:exports <#GenerateCycleDOT>, <#GenerateCycleMermaid> ;
+    code:tags "visualization", "cycles", "graphviz", "mermaid" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// cycleEdge is a dependency between two modules of a component
+type cycleEdge struct {
+	from, to string
+	shortest bool // On the component's shortest cycle
+}
+
+// componentEdges returns the dependencies within a component in module
+// order, marking those on its shortest cycle
+func componentEdges(g *graph.Graph, component analysis.CycleComponent) []cycleEdge {
+	members := make(map[string]bool, len(component.Modules))
+	for _, path := range component.Modules {
+		members[path] = true
+	}
+
+	onShortest := make(map[[2]string]bool)
+	if len(component.Cycles) > 0 {
+		cycle := component.Cycles[0]
+		for i, path := range cycle {
+			onShortest[[2]string{path, cycle[(i+1)%len(cycle)]}] = true
+		}
+	}
+
+	var edges []cycleEdge
+	for _, path := range component.Modules {
+		module := g.GetModule(path)
+		if module == nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, dep := range module.Dependencies {
+			if !members[dep] || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			edges = append(edges, cycleEdge{from: path, to: dep, shortest: onShortest[[2]string{path, dep}]})
+		}
+	}
+	return edges
+}
+
+// GenerateCycleDOT generates DOT for a strongly connected component
+func GenerateCycleDOT(g *graph.Graph, component analysis.CycleComponent, title string) string {
+	var b strings.Builder
+
+	b.WriteString("digraph Cycle {\n")
+	if title != "" {
+		fmt.Fprintf(&b, "  label=\"%s\";\n  labelloc=t;\n", escapeLabel(title))
+	}
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#FFEBEE\", fontname=\"Helvetica\"];\n\n")
+
+	for _, path := range component.Modules {
+		label := path
+		if module := g.GetModule(path); module != nil && module.Layer != "" {
+			label = fmt.Sprintf("%s\n(%s)", path, module.Layer)
+		}
+		fmt.Fprintf(&b, "  \"%s\" [label=\"%s\"];\n", escapeLabel(path), escapeLabel(label))
+	}
+
+	b.WriteString("\n")
+	for _, edge := range componentEdges(g, component) {
+		attrs := ""
+		if edge.shortest {
+			attrs = " [color=\"#D32F2F\", penwidth=2.5]"
+		}
+		fmt.Fprintf(&b, "  \"%s\" -> \"%s\"%s;\n", escapeLabel(edge.from), escapeLabel(edge.to), attrs)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// GenerateCycleMermaid generates a Mermaid flowchart for a strongly
+// connected component
+func GenerateCycleMermaid(g *graph.Graph, component analysis.CycleComponent, title string) string {
+	gen := &MermaidGenerator{graph: g}
+
+	if title != "" {
+		fmt.Fprintf(&gen.builder, "---\ntitle: %q\n---\n", title)
+	}
+	gen.builder.WriteString("flowchart LR\n")
+
+	for _, path := range component.Modules {
+		label := path
+		if module := g.GetModule(path); module != nil && module.Layer != "" {
+			label = fmt.Sprintf("%s<br/>(%s)", path, module.Layer)
+		}
+		fmt.Fprintf(&gen.builder, "    %s[%s]\n", gen.sanitizeNodeID(path), escapeMermaidLabel(label))
+	}
+
+	gen.builder.WriteString("\n")
+	var highlighted []int
+	for i, edge := range componentEdges(g, component) {
+		fmt.Fprintf(&gen.builder, "    %s --> %s\n", gen.sanitizeNodeID(edge.from), gen.sanitizeNodeID(edge.to))
+		if edge.shortest {
+			highlighted = append(highlighted, i)
+		}
+	}
+
+	gen.builder.WriteString("\n    classDef cyclic fill:#FFEBEE,stroke:#C62828\n")
+	ids := make([]string, len(component.Modules))
+	for i, path := range component.Modules {
+		ids[i] = gen.sanitizeNodeID(path)
+	}
+	fmt.Fprintf(&gen.builder, "    class %s cyclic\n", strings.Join(ids, ","))
+	for _, i := range highlighted {
+		fmt.Fprintf(&gen.builder, "    linkStyle %d stroke:#D32F2F,stroke-width:3px\n", i)
+	}
+
+	return gen.builder.String()
+}
@@ -0,0 +1,41 @@
+package viz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestGenerateCycleDiagrams(t *testing.T) {
+	g := &graph.Graph{Modules: map[string]*graph.Module{
+		"pkg/a.go": {Path: "pkg/a.go", Layer: "core", Dependencies: []string{"pkg/b.go"}},
+		"pkg/b.go": {Path: "pkg/b.go", Dependencies: []string{"pkg/a.go", "pkg/c.go"}},
+		"pkg/c.go": {Path: "pkg/c.go", Dependencies: []string{"pkg/a.go", "other.go"}},
+	}}
+	report := analysis.FindCycles(g, analysis.CycleOptions{})
+	if len(report.Components) != 1 {
+		t.Fatalf("Expected one component, got %+v", report.Components)
+	}
+	component := report.Components[0]
+
+	dot := GenerateCycleDOT(g, component, "SCC 1")
+	for _, want := range []string{`label="SCC 1"`, `"pkg/a.go" [label="pkg/a.go\n(core)"]`,
+		`"pkg/a.go" -> "pkg/b.go" [color="#D32F2F", penwidth=2.5];`, `"pkg/b.go" -> "pkg/c.go";`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+	if strings.Contains(dot, "other.go") {
+		t.Errorf("DOT should only draw the component:\n%s", dot)
+	}
+
+	mermaid := GenerateCycleMermaid(g, component, "SCC 1")
+	for _, want := range []string{`title: "SCC 1"`, "flowchart LR", "pkg_a_go --> pkg_b_go", "linkStyle 0 stroke:#D32F2F",
+		"linkStyle 1 stroke:#D32F2F", "class pkg_a_go,pkg_b_go,pkg_c_go cyclic"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
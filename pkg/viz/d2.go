@@ -0,0 +1,252 @@
+/*
+# Module: pkg/viz/d2.go
+D2 diagram generation.
+
+Writes the dependency graph in the D2 language (terrastruct), a modern
+alternative to DOT for documentation sites. Modules are nested in containers
+by directory or grouped by layer, styled by language through D2 classes, and
+recolored by the chosen color scheme. Edges are labelled with relations other
+than linksTo, dashed when inferred, and thicker for more call sites.
+
+## Linked Modules
+- [exchange](./exchange.go) - Node and edge collection
+- [dot](./dot.go) - Visualization options and colors
+
+## Tags
+visualization, d2, export
+
+## Exports
+D2Group, D2GroupDirectory, D2GroupLayer, D2GroupNone, D2Options, GenerateD2
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#d2.go> a code:Module ;
+    code:name "pkg/viz/d2.go" ;
+    code:description "D2 diagram generation" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./exchange.go>, <./dot.go> ;
+    code:exports <#D2Group>, <#D2GroupDirectory>, <#D2GroupLayer>, <#D2GroupNone>, <#D2Options>, <#GenerateD2> ;
+    code:tags "visualization", "d2", "export" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// D2Group is how modules are placed in containers
+type D2Group string
+
+const (
+	D2GroupDirectory D2Group = "directory" // Nested containers per directory
+	D2GroupLayer     D2Group = "layer"     // A container per layer
+	D2GroupNone      D2Group = "none"      // No containers
+)
+
+// D2Options configures D2 generation
+type D2Options struct {
+	VizOptions
+	GroupBy D2Group // Containers (default: directory)
+}
+
+// d2Directions maps DOT rank directions to D2 directions
+var d2Directions = map[string]string{
+	"LR": "right",
+	"RL": "left",
+	"TB": "down",
+	"BT": "up",
+}
+
+// GenerateD2 generates a D2 diagram of the dependency graph
+func GenerateD2(g *graph.Graph, opts D2Options) (string, error) {
+	if opts.GroupBy == "" {
+		opts.GroupBy = D2GroupDirectory
+	}
+	switch opts.GroupBy {
+	case D2GroupDirectory, D2GroupLayer, D2GroupNone:
+	default:
+		return "", fmt.Errorf("unknown D2 grouping %q (use directory, layer or none)", opts.GroupBy)
+	}
+
+	eg, err := collectExchangeGraph(g, opts.VizOptions)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, line := range eg.Banner {
+		fmt.Fprintf(&b, "# %s\n", line)
+	}
+	direction := d2Directions[opts.Rankdir]
+	if direction == "" {
+		direction = "right"
+	}
+	fmt.Fprintf(&b, "direction: %s\n", direction)
+	if eg.Title != "" {
+		fmt.Fprintf(&b, "title: %s {\n  shape: text\n  near: top-center\n  style.font-size: 24\n}\n", d2Quote(eg.Title))
+	}
+
+	// A class per language, so modules are styled by language
+	languages := make(map[string]bool)
+	for _, node := range eg.Nodes {
+		if node.Language != "" {
+			languages[node.Language] = true
+		}
+	}
+	if len(languages) > 0 {
+		names := make([]string, 0, len(languages))
+		for language := range languages {
+			names = append(names, language)
+		}
+		sort.Strings(names)
+		b.WriteString("\nclasses: {\n")
+		for _, language := range names {
+			fmt.Fprintf(&b, "  %s: {\n    style.fill: %s\n  }\n", d2Quote(d2Class(language)), d2Quote(languageColor(language)))
+		}
+		b.WriteString("}\n")
+	}
+
+	keys := make(map[string]string, len(eg.Nodes))
+	root := &d2Container{children: make(map[string]*d2Container)}
+	for _, node := range eg.Nodes {
+		var containers []string
+		switch opts.GroupBy {
+		case D2GroupDirectory:
+			if dir := path.Dir(node.Path); dir != "." {
+				containers = strings.Split(dir, "/")
+			}
+		case D2GroupLayer:
+			layer := node.Layer
+			if layer == "" {
+				layer = "unknown"
+			}
+			containers = []string{layer}
+		}
+
+		// Without directories in the key, paths keep modules apart
+		name := node.Path
+		if opts.GroupBy == D2GroupDirectory {
+			name = path.Base(node.Path)
+		}
+		container := root.child(containers)
+		container.nodes = append(container.nodes, node)
+		container.names = append(container.names, name)
+
+		parts := make([]string, 0, len(containers)+1)
+		for _, c := range append(containers, name) {
+			parts = append(parts, d2Quote(c))
+		}
+		keys[node.ID] = strings.Join(parts, ".")
+	}
+
+	recolor := opts.ColorBy != "" && opts.ColorBy != "default" && opts.ColorBy != "language"
+	b.WriteString("\n")
+	root.write(&b, "", opts.GroupBy == D2GroupLayer, recolor)
+
+	if len(eg.Edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, edge := range eg.Edges {
+		fmt.Fprintf(&b, "%s -> %s", keys[edge.Source], keys[edge.Target])
+		if edge.Relation != "" && edge.Relation != graph.RelationLinksTo {
+			fmt.Fprintf(&b, ": %s", d2Quote(edge.Relation))
+		}
+		var style []string
+		if edge.Inferred {
+			style = append(style, "style.stroke-dash: 4")
+		}
+		if edge.Weight > 1 {
+			width := math.Round(edgeWidth(graph.Edge{Weight: edge.Weight}) * 2)
+			style = append(style, fmt.Sprintf("style.stroke-width: %.0f", width))
+		}
+		if len(style) > 0 {
+			b.WriteString(" {\n  " + strings.Join(style, "\n  ") + "\n}")
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// d2Container is a container and the modules and containers inside it
+type d2Container struct {
+	nodes    []exchangeNode
+	names    []string // Keys of nodes
+	children map[string]*d2Container
+}
+
+// child returns the container at a path below c, creating it if needed
+func (c *d2Container) child(names []string) *d2Container {
+	for _, name := range names {
+		next, ok := c.children[name]
+		if !ok {
+			next = &d2Container{children: make(map[string]*d2Container)}
+			c.children[name] = next
+		}
+		c = next
+	}
+	return c
+}
+
+// write writes the modules and containers inside c
+func (c *d2Container) write(b *strings.Builder, indent string, layers, recolor bool) {
+	for i, node := range c.nodes {
+		fmt.Fprintf(b, "%s%s {\n", indent, d2Declaration(c.names[i], node.Label))
+		if node.Language != "" {
+			fmt.Fprintf(b, "%s  class: %s\n", indent, d2Quote(d2Class(node.Language)))
+		}
+		if recolor {
+			fmt.Fprintf(b, "%s  style.fill: %s\n", indent, d2Quote(node.Color))
+		}
+		if node.Description != "" {
+			fmt.Fprintf(b, "%s  tooltip: %s\n", indent, d2Quote(node.Description))
+		}
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+
+	names := make([]string, 0, len(c.children))
+	for name := range c.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		label := name
+		if layers {
+			label = name + " layer"
+		}
+		fmt.Fprintf(b, "%s%s {\n", indent, d2Declaration(name, label))
+		c.children[name].write(b, indent+"  ", layers, recolor)
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+}
+
+// d2Declaration returns a quoted key, with its label when that differs
+func d2Declaration(key, label string) string {
+	if key == label {
+		return d2Quote(key)
+	}
+	return d2Quote(key) + ": " + d2Quote(label)
+}
+
+// d2Class returns the class name of a language
+func d2Class(language string) string {
+	return "lang-" + strings.ToLower(language)
+}
+
+// d2Quote quotes a D2 key or value
+func d2Quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,63 @@
+package viz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestGenerateD2(t *testing.T) {
+	g := createTestGraph()
+	g.Modules["api/handlers.go"].AddEdge(graph.Edge{Target: "services/auth.go", Relation: graph.RelationImports, Weight: 4, Inferred: true})
+
+	out, err := GenerateD2(g, D2Options{VizOptions: VizOptions{Title: `The "app"`, Rankdir: "TB"}})
+	if err != nil {
+		t.Fatalf("GenerateD2 failed: %v", err)
+	}
+	for _, want := range []string{
+		"direction: down\n",
+		`title: "The \"app\"" {`,
+		"\"lang-go\": {\n    style.fill: \"#00ADD8\"\n  }",
+		"\"services\" {\n  \"auth.go\" {\n    class: \"lang-go\"\n    tooltip: \"Authentication service\"\n  }",
+		"\"api\".\"handlers.go\" -> \"services\".\"auth.go\": \"imports\" {\n  style.stroke-dash: 4\n  style.stroke-width: 6\n}",
+		"\"services\".\"users.go\" -> \"data\".\"users.go\"\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "style.fill: \"#90CAF9\"") {
+		t.Error("Expected language styling only without --color-by")
+	}
+}
+
+func TestGenerateD2Grouping(t *testing.T) {
+	g := createTestGraph()
+
+	out, err := GenerateD2(g, D2Options{VizOptions: VizOptions{ColorBy: "layer"}, GroupBy: D2GroupLayer})
+	if err != nil {
+		t.Fatalf("GenerateD2(layer) failed: %v", err)
+	}
+	for _, want := range []string{
+		"\"service\": \"service layer\" {\n  \"services/auth.go\": \"auth.go\" {",
+		"style.fill: \"#2196F3\"",
+		"\"api\".\"api/handlers.go\" -> \"service\".\"services/auth.go\"\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = GenerateD2(g, D2Options{GroupBy: D2GroupNone})
+	if err != nil {
+		t.Fatalf("GenerateD2(none) failed: %v", err)
+	}
+	if !strings.Contains(out, "\"api/handlers.go\" -> \"services/auth.go\"\n") || strings.Contains(out, "layer") {
+		t.Errorf("Expected top-level modules:\n%s", out)
+	}
+
+	if _, err := GenerateD2(g, D2Options{GroupBy: "team"}); err == nil {
+		t.Error("Expected an error for an unknown grouping")
+	}
+}
@@ -3,12 +3,17 @@
 GraphViz DOT format generation for dependency visualization.
 
 Generates DOT format output for various graph visualizations including
-dependency graphs, impact analysis, security zones, and module relationships.
+dependency graphs, impact analysis, security zones, module relationships and
+declared components. Dependency edges are drawn thicker the more call sites
+they have, and dashed when inferred from imports.
 
 ## Linked Modules
 - [../graph](../graph/graph.go) - Graph data structure
 - [../analysis](../analysis/impact.go) - Impact analysis
 - [../analysis](../analysis/security.go) - Security analysis
+- [../analysis](../analysis/components.go) - Components
+- [sampling](./sampling.go) - Large graph sampling
+- [../filter](../filter/filter.go) - Filter expressions
 
 ## Tags
 visualization, graphviz, dot, export
@@ -25,7 +30,7 @@ GenerateDOT, VizOptions, VizType, RenderToFile
     code:description "GraphViz DOT format generation for dependency visualization" ;
     code:language "go" ;
     code:layer "visualization" ;
-    code:linksTo <../graph/graph.go>, <../analysis/impact.go>, <../analysis/security.go> ;
+    code:linksTo <../graph/graph.go>, <../analysis/impact.go>, <../analysis/security.go>, <../analysis/components.go>, <./sampling.go>, <../filter/filter.go> ;
     code:exports <#GenerateDOT>, <#VizOptions>, <#VizType>, <#RenderToFile> ;
     code:tags "visualization", "graphviz", "dot", "export" .
 <!-- End LinkedDoc RDF -->
@@ -35,11 +40,13 @@ package viz
 
 import (
 	"fmt"
+	"math"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/justin4957/graphfs/pkg/analysis"
+	"github.com/justin4957/graphfs/pkg/filter"
 	"github.com/justin4957/graphfs/pkg/graph"
 )
 
@@ -51,21 +58,28 @@ const (
 	VizImpact     VizType = "impact"     // Impact analysis
 	VizSecurity   VizType = "security"   // Security zones
 	VizLayer      VizType = "layer"      // Layer relationships
+	VizComponent  VizType = "component"  // Declared components and their edges
 )
 
 // VizOptions configures visualization generation
 type VizOptions struct {
-	Type       VizType                    // Type of visualization
-	Layout     string                     // dot, neato, fdp, circo, twopi
-	ColorBy    string                     // language, layer, security, tag
-	MaxDepth   int                        // Maximum depth (0 = unlimited)
-	Filter     *FilterOptions             // Filtering options
-	Theme      string                     // Theme name (default, security, language)
-	ShowLabels bool                       // Show detailed labels
-	Rankdir    string                     // Graph direction (LR, TB, RL, BT)
-	Title      string                     // Graph title
-	Security   *analysis.SecurityAnalysis // Security analysis results
-	Impact     *analysis.ImpactResult     // Impact analysis results
+	Type       VizType                     // Type of visualization
+	Layout     string                      // dot, neato, fdp, circo, twopi
+	ColorBy    string                      // language, layer, security, tag
+	MaxDepth   int                         // Maximum depth (0 = unlimited)
+	Filter     *FilterOptions              // Filtering options
+	Theme      string                      // Theme name (default, security, language)
+	ShowLabels bool                        // Show detailed labels
+	Rankdir    string                      // Graph direction (LR, TB, RL, BT)
+	Title      string                      // Graph title
+	Security   *analysis.SecurityAnalysis  // Security analysis results
+	Impact     *analysis.ImpactResult      // Impact analysis results
+	Sampling   *SamplingOptions            // Sampling for large graphs (optional)
+	Collapse   *CollapseOptions            // Collapse modules into directories (optional)
+	Components *analysis.ComponentAnalysis // Declared components (for VizComponent)
+
+	// Criticality scores (0-1) by module path; nodes are sized by score
+	Criticality map[string]float64
 }
 
 // FilterOptions configures graph filtering
@@ -76,6 +90,12 @@ type FilterOptions struct {
 	Tags         []string // Include only modules with these tags
 	MinDepth     int      // Minimum depth from root
 	MaxDepth     int      // Maximum depth from root
+
+	// Where is a filter expression modules must match (nil = all)
+	Where *filter.Expression
+
+	Relations     []string // Include only edges with these relations (e.g. linksTo, imports)
+	MinConfidence float64  // Include only edges with at least this confidence
 }
 
 // DOTGenerator generates DOT format output
@@ -85,6 +105,9 @@ type DOTGenerator struct {
 	builder strings.Builder
 	visited map[string]bool
 	depth   map[string]int
+	sample  *SampleResult
+
+	collapsed bool // Modules were collapsed into directories
 }
 
 // NewDOTGenerator creates a new DOT generator
@@ -117,6 +140,10 @@ func GenerateDOT(g *graph.Graph, opts VizOptions) (string, error) {
 func (dg *DOTGenerator) Generate() (string, error) {
 	dg.builder.Reset()
 
+	if err := dg.applySampling(); err != nil {
+		return "", err
+	}
+
 	// Write header
 	dg.writeHeader()
 
@@ -133,6 +160,8 @@ func (dg *DOTGenerator) Generate() (string, error) {
 		dg.generateSecurityGraph()
 	case VizLayer:
 		dg.generateLayerGraph()
+	case VizComponent:
+		dg.generateComponentGraph()
 	default:
 		dg.generateDependencyGraph()
 	}
@@ -143,11 +172,47 @@ func (dg *DOTGenerator) Generate() (string, error) {
 	return dg.builder.String(), nil
 }
 
+// applySampling collapses and reduces large graphs before rendering
+func (dg *DOTGenerator) applySampling() error {
+	if dg.options.Collapse != nil && !dg.collapsed {
+		if dg.options.Type != "" && dg.options.Type != VizDependency {
+			return fmt.Errorf("collapsing supports the dependency visualization only, not %s", dg.options.Type)
+		}
+		collapsed, err := CollapseGraph(dg.graph, *dg.options.Collapse, dg.options.Filter)
+		if err != nil {
+			return err
+		}
+		// Filters applied to modules; directory nodes are all kept
+		dg.graph = collapsed
+		dg.options.Filter = nil
+		dg.collapsed = true
+	}
+	if dg.options.Sampling != nil && dg.sample == nil {
+		sample, err := SampleGraph(dg.graph, *dg.options.Sampling)
+		if err != nil {
+			return fmt.Errorf("failed to sample graph: %w", err)
+		}
+		dg.sample = sample
+		dg.graph = sample.Graph
+	}
+	return nil
+}
+
 // writeHeader writes the DOT header
 func (dg *DOTGenerator) writeHeader() {
 	dg.builder.WriteString("digraph GraphFS {\n")
-	if dg.options.Title != "" {
-		dg.builder.WriteString(fmt.Sprintf("  labelloc=\"t\";\n  label=\"%s\";\n", escapeLabel(dg.options.Title)))
+
+	banner := dg.sample.Banner()
+	for _, line := range banner {
+		dg.builder.WriteString(fmt.Sprintf("  // %s\n", line))
+	}
+
+	label := dg.options.Title
+	if len(banner) > 0 {
+		label = strings.TrimSpace(label + "\n" + strings.Join(banner, "\n"))
+	}
+	if label != "" {
+		dg.builder.WriteString(fmt.Sprintf("  labelloc=\"t\";\n  label=\"%s\";\n", escapeLabel(label)))
 	}
 }
 
@@ -247,6 +312,26 @@ func (dg *DOTGenerator) generateImpactGraph() {
 	}
 }
 
+// securityZoneOrder is the order in which security zones are drawn
+var securityZoneOrder = []analysis.SecurityZone{
+	analysis.ZonePublic,
+	analysis.ZoneTrusted,
+	analysis.ZoneInternal,
+	analysis.ZoneAdmin,
+	analysis.ZoneData,
+	analysis.ZoneUnknown,
+}
+
+// securityZoneColors maps security zones to their colors
+var securityZoneColors = map[analysis.SecurityZone]string{
+	analysis.ZonePublic:   "#4CAF50", // Green
+	analysis.ZoneTrusted:  "#2196F3", // Blue
+	analysis.ZoneInternal: "#9E9E9E", // Gray
+	analysis.ZoneAdmin:    "#F44336", // Red
+	analysis.ZoneData:     "#FF9800", // Orange
+	analysis.ZoneUnknown:  "#E0E0E0", // Light gray
+}
+
 // generateSecurityGraph generates a security zone visualization
 func (dg *DOTGenerator) generateSecurityGraph() {
 	if dg.options.Security == nil {
@@ -257,26 +342,10 @@ func (dg *DOTGenerator) generateSecurityGraph() {
 	sec := dg.options.Security
 
 	// Group modules by zone using subgraphs
-	zoneOrder := []analysis.SecurityZone{
-		analysis.ZonePublic,
-		analysis.ZoneTrusted,
-		analysis.ZoneInternal,
-		analysis.ZoneAdmin,
-		analysis.ZoneData,
-		analysis.ZoneUnknown,
-	}
-
-	zoneColors := map[analysis.SecurityZone]string{
-		analysis.ZonePublic:   "#4CAF50", // Green
-		analysis.ZoneTrusted:  "#2196F3", // Blue
-		analysis.ZoneInternal: "#9E9E9E", // Gray
-		analysis.ZoneAdmin:    "#F44336", // Red
-		analysis.ZoneData:     "#FF9800", // Orange
-		analysis.ZoneUnknown:  "#E0E0E0", // Light gray
-	}
+	zoneColors := securityZoneColors
 
 	idx := 0
-	for _, zone := range zoneOrder {
+	for _, zone := range securityZoneOrder {
 		modules, ok := sec.Zones[zone]
 		if !ok || len(modules) == 0 {
 			continue
@@ -284,12 +353,7 @@ func (dg *DOTGenerator) generateSecurityGraph() {
 
 		dg.builder.WriteString(fmt.Sprintf("  // Zone: %s\n", zone))
 		dg.builder.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", idx))
-		// Capitalize first letter of zone name
-		zoneName := string(zone)
-		if len(zoneName) > 0 {
-			zoneName = strings.ToUpper(zoneName[:1]) + zoneName[1:]
-		}
-		dg.builder.WriteString(fmt.Sprintf("    label=\"%s Zone\";\n", zoneName))
+		dg.builder.WriteString(fmt.Sprintf("    label=\"%s\";\n", zoneLabel(zone)))
 		dg.builder.WriteString("    style=filled;\n")
 		dg.builder.WriteString(fmt.Sprintf("    fillcolor=\"%s30\";\n", zoneColors[zone])) // 30 = transparency
 		dg.builder.WriteString(fmt.Sprintf("    color=\"%s\";\n\n", zoneColors[zone]))
@@ -361,6 +425,41 @@ func (dg *DOTGenerator) generateLayerGraph() {
 	}
 }
 
+// generateComponentGraph generates one node per declared component, with
+// edges weighted by the module dependencies between components. Edges with
+// boundary violations are highlighted.
+func (dg *DOTGenerator) generateComponentGraph() {
+	components := dg.options.Components
+	if components == nil {
+		dg.builder.WriteString("  // No components declared\n")
+		return
+	}
+
+	dg.builder.WriteString("  // Components\n")
+	for _, component := range components.Components {
+		label := fmt.Sprintf("%s\n%d modules", component.Name, len(component.Modules))
+		if dg.options.ShowLabels && len(component.Layers) > 0 {
+			label += "\n" + strings.Join(component.Layers, ", ")
+		}
+		dg.builder.WriteString(fmt.Sprintf("  \"component:%s\" [fillcolor=\"#E3F2FD\", shape=component, label=\"%s\"];\n",
+			escapeLabel(component.Name), escapeLabel(label)))
+	}
+
+	dg.builder.WriteString("\n  // Component dependencies\n")
+	for _, edge := range components.Edges {
+		label := fmt.Sprintf("%d", len(edge.Dependencies))
+		if edge.NonAPI > 0 {
+			label += fmt.Sprintf(" (%d non-API)", edge.NonAPI)
+		}
+		style := ""
+		if edge.NonAPI > 0 || !edge.Declared {
+			style = ", color=red, penwidth=2.0, style=bold"
+		}
+		dg.builder.WriteString(fmt.Sprintf("  \"component:%s\" -> \"component:%s\" [label=\"%s\"%s];\n",
+			escapeLabel(edge.From), escapeLabel(edge.To), label, style))
+	}
+}
+
 // writeNode writes a node with default styling
 func (dg *DOTGenerator) writeNode(module *graph.Module) {
 	color := dg.getNodeColor(module)
@@ -372,8 +471,8 @@ func (dg *DOTGenerator) writeNodeWithColor(module *graph.Module, color string) {
 	nodeID := dg.getNodeID(module)
 	label := dg.getNodeLabel(module)
 
-	dg.builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=\"%s\", label=\"%s\"];\n",
-		nodeID, color, escapeLabel(label)))
+	dg.builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=\"%s\", label=\"%s\"%s];\n",
+		nodeID, color, escapeLabel(label), dg.getNodeSize(module)))
 }
 
 // writeNodeWithColorInCluster writes a node inside a cluster
@@ -381,8 +480,19 @@ func (dg *DOTGenerator) writeNodeWithColorInCluster(module *graph.Module, color
 	nodeID := dg.getNodeID(module)
 	label := dg.getNodeLabel(module)
 
-	dg.builder.WriteString(fmt.Sprintf("    \"%s\" [fillcolor=\"%s\", label=\"%s\"];\n",
-		nodeID, color, escapeLabel(label)))
+	dg.builder.WriteString(fmt.Sprintf("    \"%s\" [fillcolor=\"%s\", label=\"%s\"%s];\n",
+		nodeID, color, escapeLabel(label), dg.getNodeSize(module)))
+}
+
+// getNodeSize returns size attributes scaled by criticality, or "" when
+// nodes aren't sized
+func (dg *DOTGenerator) getNodeSize(module *graph.Module) string {
+	if dg.options.Criticality == nil {
+		return ""
+	}
+	score := dg.options.Criticality[module.Path]
+	return fmt.Sprintf(", width=%.2f, height=%.2f, fontsize=%.0f",
+		0.75+1.5*score, 0.5+0.75*score, 10+8*score)
 }
 
 // writeEdges writes edges for a module's dependencies
@@ -395,9 +505,37 @@ func (dg *DOTGenerator) writeEdges(module *graph.Module) {
 			continue
 		}
 
+		edge := module.EdgeTo(depPath)
+		if !dg.options.Filter.MatchEdge(edge) {
+			continue
+		}
 		toID := dg.getNodeID(depModule)
-		dg.builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\";\n", fromID, toID))
+		attrs := edgeAttributes(edge)
+		if dg.collapsed {
+			attrs = bundledEdgeAttributes(edge)
+		}
+		dg.builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\"%s;\n", fromID, toID, attrs))
+	}
+}
+
+// edgeAttributes styles an edge by its metadata: thicker for more call sites,
+// dashed when inferred, and labelled with relations other than linksTo
+func edgeAttributes(edge graph.Edge) string {
+	var attrs []string
+	if edge.Weight > 1 {
+		attrs = append(attrs, fmt.Sprintf("penwidth=%.1f", math.Min(1+math.Log2(float64(edge.Weight)), 6)),
+			fmt.Sprintf("tooltip=\"%d call sites\"", edge.Weight))
+	}
+	if edge.Inferred {
+		attrs = append(attrs, "style=dashed")
+	}
+	if edge.Relation != "" && edge.Relation != graph.RelationLinksTo {
+		attrs = append(attrs, fmt.Sprintf("label=\"%s\"", escapeLabel(edge.Relation)))
+	}
+	if len(attrs) == 0 {
+		return ""
 	}
+	return " [" + strings.Join(attrs, ", ") + "]"
 }
 
 // writeSecurityEdges writes edges with security violation highlighting
@@ -436,6 +574,9 @@ func (dg *DOTGenerator) getNodeID(module *graph.Module) string {
 
 // getNodeLabel returns the label for a node
 func (dg *DOTGenerator) getNodeLabel(module *graph.Module) string {
+	if isCollapsedDir(module) {
+		return fmt.Sprintf("%s\n%s", module.Name, module.Description)
+	}
 	if dg.options.ShowLabels {
 		return fmt.Sprintf("%s\n%s", filepath.Base(module.Path), module.Description)
 	}
@@ -456,7 +597,12 @@ func (dg *DOTGenerator) getNodeColor(module *graph.Module) string {
 
 // getLanguageColor returns color based on language
 func (dg *DOTGenerator) getLanguageColor(module *graph.Module) string {
-	switch strings.ToLower(module.Language) {
+	return languageColor(module.Language)
+}
+
+// languageColor returns the color of a programming language
+func languageColor(language string) string {
+	switch strings.ToLower(language) {
 	case "go":
 		return "#00ADD8" // Go cyan
 	case "python":
@@ -505,16 +651,23 @@ func (dg *DOTGenerator) getFilteredModules() []*graph.Module {
 
 // shouldIncludeModule checks if a module should be included
 func (dg *DOTGenerator) shouldIncludeModule(module *graph.Module) bool {
-	if dg.options.Filter == nil {
+	return dg.options.Filter.Match(module)
+}
+
+// Match reports whether a module passes the filter (a nil filter matches all)
+func (opts *FilterOptions) Match(module *graph.Module) bool {
+	if opts == nil {
 		return true
 	}
 
-	filter := dg.options.Filter
+	if !opts.Where.Match(module) {
+		return false
+	}
 
 	// Check layer filter
-	if len(filter.Layers) > 0 {
+	if len(opts.Layers) > 0 {
 		found := false
-		for _, layer := range filter.Layers {
+		for _, layer := range opts.Layers {
 			if strings.EqualFold(module.Layer, layer) {
 				found = true
 				break
@@ -526,9 +679,9 @@ func (dg *DOTGenerator) shouldIncludeModule(module *graph.Module) bool {
 	}
 
 	// Check tag filter
-	if len(filter.Tags) > 0 {
+	if len(opts.Tags) > 0 {
 		hasTag := false
-		for _, filterTag := range filter.Tags {
+		for _, filterTag := range opts.Tags {
 			for _, moduleTag := range module.Tags {
 				if strings.EqualFold(moduleTag, filterTag) {
 					hasTag = true
@@ -547,6 +700,26 @@ func (dg *DOTGenerator) shouldIncludeModule(module *graph.Module) bool {
 	return true
 }
 
+// MatchEdge reports whether a dependency edge passes the filter's relation
+// and confidence criteria (a nil filter matches all)
+func (opts *FilterOptions) MatchEdge(edge graph.Edge) bool {
+	if opts == nil {
+		return true
+	}
+	if edge.Confidence < opts.MinConfidence {
+		return false
+	}
+	if len(opts.Relations) == 0 {
+		return true
+	}
+	for _, relation := range opts.Relations {
+		if strings.EqualFold(edge.Relation, relation) {
+			return true
+		}
+	}
+	return false
+}
+
 // escapeLabel escapes special characters in DOT labels
 func escapeLabel(s string) string {
 	s = strings.ReplaceAll(s, "\\", "\\\\")
@@ -0,0 +1,153 @@
+/*
+# Module: pkg/viz/exchange.go
+Graph exchange formats for external explorers.
+
+Collects the nodes and edges written by the GraphML and GEXF generators so
+large dependency graphs can be explored in yEd and Gephi. Filters,
+collapsing and sampling apply as for DOT output; nodes carry their layer,
+language and tags, and edges their relationship type, declaring predicate,
+call-site weight, whether they were inferred and their confidence.
+
+## Linked Modules
+- [dot](./dot.go) - Visualization options, filtering and colors
+- [graphml](./graphml.go) - GraphML generation
+- [gexf](./gexf.go) - GEXF generation
+- [sampling](./sampling.go) - Large graph sampling
+
+## Tags
+visualization, graphml, gexf, export
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#exchange.go> a code:Module ;
+    code:name "pkg/viz/exchange.go" ;
+    code:description "Graph exchange formats for external explorers" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./dot.go>, <./graphml.go>, <./gexf.go>, <./sampling.go> ;
+    code:tags "visualization", "graphml", "gexf", "export" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// exchangeNode is a module written to an exchange format
+type exchangeNode struct {
+	ID          string
+	Path        string
+	Label       string
+	Layer       string
+	Language    string
+	Tags        string // Comma-separated
+	Description string
+	Color       string  // Hex color from VizOptions.ColorBy
+	Criticality float64 // 0 when not scored
+}
+
+// exchangeEdge is a dependency written to an exchange format
+type exchangeEdge struct {
+	ID         string
+	Source     string
+	Target     string
+	Relation   string
+	Predicate  string
+	Weight     int
+	Inferred   bool
+	Confidence float64
+}
+
+// exchangeGraph is a filtered, optionally sampled dependency graph with
+// stable node and edge IDs
+type exchangeGraph struct {
+	Title  string
+	Banner []string // Sampling warnings
+	Nodes  []exchangeNode
+	Edges  []exchangeEdge
+
+	// Scored reports whether nodes carry criticality scores
+	Scored bool
+}
+
+// collectExchangeGraph builds the exchange graph for the dependency view.
+// Modules are ordered by path so the output is reproducible.
+func collectExchangeGraph(g *graph.Graph, opts VizOptions) (*exchangeGraph, error) {
+	if opts.Type != "" && opts.Type != VizDependency {
+		return nil, fmt.Errorf("unsupported visualization type for graph exchange formats: %s (only dependency)", opts.Type)
+	}
+
+	dg := NewDOTGenerator(g, opts)
+	if err := dg.applySampling(); err != nil {
+		return nil, err
+	}
+	eg := &exchangeGraph{Title: opts.Title, Banner: dg.sample.Banner(), Scored: opts.Criticality != nil}
+
+	modules := dg.getFilteredModules()
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+
+	ids := make(map[string]string, len(modules))
+	for i, module := range modules {
+		id := fmt.Sprintf("n%d", i)
+		ids[module.Path] = id
+		label := filepath.Base(module.Path)
+		if isCollapsedDir(module) {
+			label = module.Name
+		}
+		eg.Nodes = append(eg.Nodes, exchangeNode{
+			ID:          id,
+			Path:        module.Path,
+			Label:       label,
+			Layer:       module.Layer,
+			Language:    module.Language,
+			Tags:        strings.Join(module.Tags, ","),
+			Description: module.Description,
+			Color:       dg.getNodeColor(module),
+			Criticality: opts.Criticality[module.Path],
+		})
+	}
+
+	for _, module := range modules {
+		for _, depPath := range module.Dependencies {
+			target, ok := ids[depPath]
+			if !ok {
+				continue
+			}
+			edge := module.EdgeTo(depPath)
+			if !opts.Filter.MatchEdge(edge) {
+				continue
+			}
+			eg.Edges = append(eg.Edges, exchangeEdge{
+				ID:         fmt.Sprintf("e%d", len(eg.Edges)),
+				Source:     ids[module.Path],
+				Target:     target,
+				Relation:   edge.Relation,
+				Predicate:  edge.Predicate,
+				Weight:     edge.Weight,
+				Inferred:   edge.Inferred,
+				Confidence: edge.Confidence,
+			})
+		}
+	}
+
+	return eg, nil
+}
+
+// rgb splits a #RRGGBB color into its components
+func rgb(color string) (r, g, b int) {
+	if _, err := fmt.Sscanf(color, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return 0x90, 0xCA, 0xF9 // Light blue default
+	}
+	return r, g, b
+}
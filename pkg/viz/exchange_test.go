@@ -0,0 +1,224 @@
+package viz
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// xmlDocument is a generic element tree for checking generated XML
+type xmlDocument struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr    `xml:",any,attr"`
+	Text     string        `xml:",chardata"`
+	Children []xmlDocument `xml:",any"`
+}
+
+// find returns every descendant element with a local name
+func (d xmlDocument) find(name string) []xmlDocument {
+	var found []xmlDocument
+	for _, child := range d.Children {
+		if child.XMLName.Local == name {
+			found = append(found, child)
+		}
+		found = append(found, child.find(name)...)
+	}
+	return found
+}
+
+// attr returns the value of an attribute by local name
+func (d xmlDocument) attr(name string) string {
+	for _, a := range d.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseXML(t *testing.T, output string) xmlDocument {
+	t.Helper()
+	var doc xmlDocument
+	if err := xml.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("Output is not valid XML: %v\n%s", err, output)
+	}
+	return doc
+}
+
+func exchangeTestGraph() *graph.Graph {
+	g := createTestGraph()
+	api := g.Modules["api/handlers.go"]
+	api.Description = `Handlers for "/api" & <admin>`
+	api.AddEdge(graph.Edge{Target: "services/auth.go", Weight: 8})
+	api.AddEdge(graph.Edge{Target: "services/users.go", Relation: graph.RelationImports, Weight: 1, Inferred: true})
+	return g
+}
+
+func TestGenerateGraphML(t *testing.T) {
+	output, err := GenerateGraphML(exchangeTestGraph(), VizOptions{Type: VizDependency, ColorBy: "layer", Title: "Deps"})
+	if err != nil {
+		t.Fatalf("GenerateGraphML failed: %v", err)
+	}
+	doc := parseXML(t, output)
+	if doc.XMLName.Local != "graphml" {
+		t.Fatalf("Root element = %s, want graphml", doc.XMLName.Local)
+	}
+
+	keys := make(map[string]string)
+	for _, key := range doc.find("key") {
+		if name := key.attr("attr.name"); name != "" {
+			keys[key.attr("for")+"/"+name] = key.attr("id")
+		}
+	}
+	for _, want := range []string{"node/layer", "node/language", "node/tags", "edge/relation", "edge/weight", "edge/inferred"} {
+		if keys[want] == "" {
+			t.Errorf("Missing key %s", want)
+		}
+	}
+	if keys["node/criticality"] != "" {
+		t.Error("Criticality key should be declared only with scores")
+	}
+
+	nodes := doc.find("node")
+	if len(nodes) != 4 {
+		t.Fatalf("Expected 4 nodes, got %d", len(nodes))
+	}
+	data := func(element xmlDocument, key string) string {
+		for _, d := range element.find("data") {
+			if d.attr("key") == keys[key] {
+				return d.Text
+			}
+		}
+		return ""
+	}
+	// Nodes are ordered by path
+	api := nodes[0]
+	if got := data(api, "node/path"); got != "api/handlers.go" {
+		t.Fatalf("First node path = %q", got)
+	}
+	if data(api, "node/layer") != "api" || data(api, "node/language") != "go" || data(api, "node/tags") != "api,http" {
+		t.Errorf("Unexpected node attributes: %+v", api)
+	}
+	if got := data(api, "node/description"); got != `Handlers for "/api" & <admin>` {
+		t.Errorf("Description = %q", got)
+	}
+	if got := data(api, "node/color"); got != "#4CAF50" {
+		t.Errorf("Color = %q, want the api layer color", got)
+	}
+	if labels := api.find("NodeLabel"); len(labels) != 1 || labels[0].Text != "handlers.go" {
+		t.Errorf("Expected a yFiles node label, got %+v", labels)
+	}
+
+	edges := doc.find("edge")
+	if len(edges) != 4 {
+		t.Fatalf("Expected 4 edges, got %d", len(edges))
+	}
+	for _, edge := range edges {
+		if edge.attr("source") != api.attr("id") {
+			continue
+		}
+		switch data(edge, "edge/relation") {
+		case graph.RelationLinksTo:
+			if data(edge, "edge/weight") != "8" || data(edge, "edge/inferred") != "false" ||
+				data(edge, "edge/confidence") != "1" || data(edge, "edge/predicate") != "code:linksTo" {
+				t.Errorf("Unexpected linksTo edge: %+v", edge)
+			}
+		case graph.RelationImports:
+			if data(edge, "edge/weight") != "1" || data(edge, "edge/inferred") != "true" ||
+				data(edge, "edge/confidence") != "0.6" || data(edge, "edge/predicate") != "" {
+				t.Errorf("Unexpected imports edge: %+v", edge)
+			}
+		default:
+			t.Errorf("Unexpected relation %q", data(edge, "edge/relation"))
+		}
+	}
+}
+
+func TestGenerateGEXF(t *testing.T) {
+	opts := VizOptions{
+		Type:        VizDependency,
+		Filter:      &FilterOptions{Layers: []string{"api", "service"}},
+		Criticality: map[string]float64{"services/auth.go": 0.5},
+	}
+	output, err := GenerateGEXF(exchangeTestGraph(), opts)
+	if err != nil {
+		t.Fatalf("GenerateGEXF failed: %v", err)
+	}
+	doc := parseXML(t, output)
+	if doc.XMLName.Local != "gexf" || doc.attr("version") != "1.3" {
+		t.Fatalf("Unexpected root element %s version %s", doc.XMLName.Local, doc.attr("version"))
+	}
+
+	columns := make(map[string]string)
+	for _, attributes := range doc.find("attributes") {
+		for _, column := range attributes.find("attribute") {
+			columns[attributes.attr("class")+"/"+column.attr("title")] = column.attr("id")
+		}
+	}
+	for _, want := range []string{"node/layer", "node/language", "node/tags", "node/criticality", "edge/relation", "edge/inferred"} {
+		if columns[want] == "" {
+			t.Errorf("Missing attribute %s", want)
+		}
+	}
+
+	// The data layer is filtered out, with the edges into it
+	nodes := doc.find("node")
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(nodes))
+	}
+	value := func(element xmlDocument, column string) string {
+		for _, v := range element.find("attvalue") {
+			if v.attr("for") == columns[column] {
+				return v.attr("value")
+			}
+		}
+		return ""
+	}
+	auth := nodes[1]
+	if auth.attr("label") != "auth.go" || value(auth, "node/layer") != "service" || value(auth, "node/tags") != "service,auth" {
+		t.Errorf("Unexpected node: %+v", auth)
+	}
+	if value(auth, "node/criticality") != "0.5000" {
+		t.Errorf("Criticality = %q", value(auth, "node/criticality"))
+	}
+	if sizes := auth.find("size"); len(sizes) != 1 || sizes[0].attr("value") != "20.0" {
+		t.Errorf("Expected a viz size, got %+v", sizes)
+	}
+	if value(nodes[0], "node/description") != `Handlers for "/api" & <admin>` {
+		t.Errorf("Description = %q", value(nodes[0], "node/description"))
+	}
+
+	edges := doc.find("edge")
+	if len(edges) != 2 {
+		t.Fatalf("Expected 2 edges, got %d", len(edges))
+	}
+	if edges[0].attr("weight") != "8" || value(edges[0], "edge/relation") != graph.RelationLinksTo {
+		t.Errorf("Unexpected edge: %+v", edges[0])
+	}
+	if value(edges[1], "edge/relation") != graph.RelationImports || value(edges[1], "edge/inferred") != "true" {
+		t.Errorf("Unexpected edge: %+v", edges[1])
+	}
+}
+
+func TestGenerateExchange_Sampling(t *testing.T) {
+	opts := VizOptions{Sampling: &SamplingOptions{Strategy: SampleLayerCollapsed, Threshold: 1}}
+	output, err := GenerateGEXF(createTestGraph(), opts)
+	if err != nil {
+		t.Fatalf("GenerateGEXF failed: %v", err)
+	}
+	doc := parseXML(t, output)
+	if descriptions := doc.find("description"); len(descriptions) != 1 || !strings.Contains(descriptions[0].Text, "collapsed") {
+		t.Errorf("Expected the sampling banner in the description, got %+v", descriptions)
+	}
+	if nodes := doc.find("node"); len(nodes) != 3 {
+		t.Errorf("Expected one node per layer, got %d", len(nodes))
+	}
+}
+
+func TestGenerateExchange_UnsupportedType(t *testing.T) {
+	if _, err := GenerateGraphML(createTestGraph(), VizOptions{Type: VizSecurity}); err == nil {
+		t.Error("Expected error for a security visualization")
+	}
+}
@@ -0,0 +1,148 @@
+/*
+# Module: pkg/viz/gexf.go
+GEXF generation for Gephi.
+
+Writes the dependency graph as GEXF 1.3 with node attributes for path,
+layer, language, tags and description, and edge attributes for relationship
+type, inference, confidence and declaring predicate. Edge weights are the call-site counts, and nodes carry
+viz colors and, with criticality scores, sizes so Gephi's layouts start from
+the same styling as the DOT output.
+
+## Linked Modules
+- [exchange](./exchange.go) - Node and edge collection
+
+## Tags
+visualization, gexf, gephi, export
+
+## Exports
+GenerateGEXF
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#gexf.go> a code:Module ;
+    code:name "pkg/viz/gexf.go" ;
+    code:description "GEXF generation for Gephi" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./exchange.go> ;
+    code:exports <#GenerateGEXF> ;
+    code:tags "visualization", "gexf", "gephi", "export" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// GEXF attribute IDs
+const (
+	gexfPath        = "0"
+	gexfLayer       = "1"
+	gexfLanguage    = "2"
+	gexfTags        = "3"
+	gexfDescription = "4"
+	gexfCriticality = "5"
+
+	gexfRelation   = "0"
+	gexfInferred   = "1"
+	gexfConfidence = "2"
+	gexfPredicate  = "3"
+)
+
+// GenerateGEXF generates GEXF for the dependency graph
+func GenerateGEXF(g *graph.Graph, opts VizOptions) (string, error) {
+	eg, err := collectExchangeGraph(g, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<gexf xmlns="http://gexf.net/1.3" xmlns:viz="http://gexf.net/1.3/viz"` +
+		` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"` +
+		` xsi:schemaLocation="http://gexf.net/1.3 http://gexf.net/1.3/gexf.xsd" version="1.3">` + "\n")
+
+	b.WriteString("  <meta>\n    <creator>GraphFS</creator>\n")
+	if description := strings.TrimSpace(eg.Title + "\n" + strings.Join(eg.Banner, "\n")); description != "" {
+		fmt.Fprintf(&b, "    <description>%s</description>\n", xmlText(description))
+	}
+	b.WriteString("  </meta>\n")
+
+	b.WriteString("  <graph defaultedgetype=\"directed\" mode=\"static\">\n")
+	b.WriteString("    <attributes class=\"node\">\n")
+	writeGEXFAttribute(&b, gexfPath, "path", "string")
+	writeGEXFAttribute(&b, gexfLayer, "layer", "string")
+	writeGEXFAttribute(&b, gexfLanguage, "language", "string")
+	writeGEXFAttribute(&b, gexfTags, "tags", "string")
+	writeGEXFAttribute(&b, gexfDescription, "description", "string")
+	if eg.Scored {
+		writeGEXFAttribute(&b, gexfCriticality, "criticality", "double")
+	}
+	b.WriteString("    </attributes>\n")
+	b.WriteString("    <attributes class=\"edge\">\n")
+	writeGEXFAttribute(&b, gexfRelation, "relation", "string")
+	writeGEXFAttribute(&b, gexfInferred, "inferred", "boolean")
+	writeGEXFAttribute(&b, gexfConfidence, "confidence", "double")
+	writeGEXFAttribute(&b, gexfPredicate, "predicate", "string")
+	b.WriteString("    </attributes>\n")
+
+	b.WriteString("    <nodes>\n")
+	for _, node := range eg.Nodes {
+		fmt.Fprintf(&b, "      <node id=%q label=\"%s\">\n", node.ID, xmlText(node.Label))
+		b.WriteString("        <attvalues>\n")
+		writeGEXFValue(&b, gexfPath, node.Path)
+		writeGEXFValue(&b, gexfLayer, node.Layer)
+		writeGEXFValue(&b, gexfLanguage, node.Language)
+		writeGEXFValue(&b, gexfTags, node.Tags)
+		writeGEXFValue(&b, gexfDescription, node.Description)
+		if eg.Scored {
+			writeGEXFValue(&b, gexfCriticality, fmt.Sprintf("%.4f", node.Criticality))
+		}
+		b.WriteString("        </attvalues>\n")
+		r, g, bl := rgb(node.Color)
+		fmt.Fprintf(&b, "        <viz:color r=\"%d\" g=\"%d\" b=\"%d\"/>\n", r, g, bl)
+		if eg.Scored {
+			fmt.Fprintf(&b, "        <viz:size value=\"%.1f\"/>\n", 10+20*node.Criticality)
+		}
+		b.WriteString("      </node>\n")
+	}
+	b.WriteString("    </nodes>\n")
+
+	b.WriteString("    <edges>\n")
+	for _, edge := range eg.Edges {
+		fmt.Fprintf(&b, "      <edge id=%q source=%q target=%q weight=\"%d\">\n", edge.ID, edge.Source, edge.Target, edge.Weight)
+		b.WriteString("        <attvalues>\n")
+		writeGEXFValue(&b, gexfRelation, edge.Relation)
+		writeGEXFValue(&b, gexfInferred, fmt.Sprintf("%t", edge.Inferred))
+		writeGEXFValue(&b, gexfConfidence, strconv.FormatFloat(edge.Confidence, 'f', -1, 64))
+		writeGEXFValue(&b, gexfPredicate, edge.Predicate)
+		b.WriteString("        </attvalues>\n")
+		b.WriteString("      </edge>\n")
+	}
+	b.WriteString("    </edges>\n")
+
+	b.WriteString("  </graph>\n</gexf>\n")
+	return b.String(), nil
+}
+
+// writeGEXFAttribute declares an attribute column
+func writeGEXFAttribute(b *strings.Builder, id, title, attrType string) {
+	fmt.Fprintf(b, "      <attribute id=%q title=%q type=%q/>\n", id, title, attrType)
+}
+
+// writeGEXFValue writes an attribute value, omitting empty values
+func writeGEXFValue(b *strings.Builder, id, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "          <attvalue for=%q value=\"%s\"/>\n", id, xmlText(value))
+}
@@ -0,0 +1,138 @@
+/*
+# Module: pkg/viz/graphml.go
+GraphML generation for yEd and other graph editors.
+
+Writes the dependency graph as GraphML with node attributes for path, layer,
+language, tags and description, and edge attributes for relationship type,
+weight, inference, confidence and declaring predicate. Nodes also carry yFiles shape graphics, so yEd shows
+labels and colors without a properties mapping.
+
+## Linked Modules
+- [exchange](./exchange.go) - Node and edge collection
+
+## Tags
+visualization, graphml, yed, export
+
+## Exports
+GenerateGraphML
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#graphml.go> a code:Module ;
+    code:name "pkg/viz/graphml.go" ;
+    code:description "GraphML generation for yEd and other graph editors" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./exchange.go> ;
+    code:exports <#GenerateGraphML> ;
+    code:tags "visualization", "graphml", "yed", "export" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// graphMLKeys declares the GraphML attributes: id, target, name and type
+var graphMLKeys = [][4]string{
+	{"d0", "graph", "description", "string"},
+	{"d1", "node", "label", "string"},
+	{"d2", "node", "path", "string"},
+	{"d3", "node", "layer", "string"},
+	{"d4", "node", "language", "string"},
+	{"d5", "node", "tags", "string"},
+	{"d6", "node", "description", "string"},
+	{"d7", "node", "color", "string"},
+	{"d8", "node", "criticality", "double"},
+	{"d9", "edge", "relation", "string"},
+	{"d10", "edge", "weight", "int"},
+	{"d11", "edge", "inferred", "boolean"},
+	{"d13", "edge", "confidence", "double"},
+	{"d14", "edge", "predicate", "string"},
+}
+
+// GenerateGraphML generates GraphML for the dependency graph
+func GenerateGraphML(g *graph.Graph, opts VizOptions) (string, error) {
+	eg, err := collectExchangeGraph(g, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	for _, line := range eg.Banner {
+		fmt.Fprintf(&b, "<!-- %s -->\n", strings.ReplaceAll(line, "--", "- -"))
+	}
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns"` +
+		` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"` +
+		` xmlns:y="http://www.yworks.com/xml/graphml"` +
+		` xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://www.yworks.com/xml/schema/graphml/1.1/ygraphml.xsd">` + "\n")
+
+	for _, key := range graphMLKeys {
+		if key[2] == "criticality" && !eg.Scored {
+			continue
+		}
+		fmt.Fprintf(&b, "  <key id=%q for=%q attr.name=%q attr.type=%q/>\n", key[0], key[1], key[2], key[3])
+	}
+	b.WriteString("  <key id=\"d12\" for=\"node\" yfiles.type=\"nodegraphics\"/>\n")
+
+	b.WriteString("  <graph id=\"GraphFS\" edgedefault=\"directed\">\n")
+	if description := strings.TrimSpace(eg.Title + "\n" + strings.Join(eg.Banner, "\n")); description != "" {
+		fmt.Fprintf(&b, "    <data key=\"d0\">%s</data>\n", xmlText(description))
+	}
+
+	for _, node := range eg.Nodes {
+		fmt.Fprintf(&b, "    <node id=%q>\n", node.ID)
+		writeGraphMLData(&b, "d1", node.Label)
+		writeGraphMLData(&b, "d2", node.Path)
+		writeGraphMLData(&b, "d3", node.Layer)
+		writeGraphMLData(&b, "d4", node.Language)
+		writeGraphMLData(&b, "d5", node.Tags)
+		writeGraphMLData(&b, "d6", node.Description)
+		writeGraphMLData(&b, "d7", node.Color)
+		if eg.Scored {
+			writeGraphMLData(&b, "d8", fmt.Sprintf("%.4f", node.Criticality))
+		}
+		fmt.Fprintf(&b, "      <data key=\"d12\"><y:ShapeNode><y:Geometry width=\"%.1f\" height=\"30.0\"/>"+
+			"<y:Fill color=%q/><y:NodeLabel>%s</y:NodeLabel></y:ShapeNode></data>\n",
+			float64(40+7*len(node.Label)), node.Color, xmlText(node.Label))
+		b.WriteString("    </node>\n")
+	}
+
+	for _, edge := range eg.Edges {
+		fmt.Fprintf(&b, "    <edge id=%q source=%q target=%q>\n", edge.ID, edge.Source, edge.Target)
+		writeGraphMLData(&b, "d9", edge.Relation)
+		writeGraphMLData(&b, "d10", fmt.Sprintf("%d", edge.Weight))
+		writeGraphMLData(&b, "d11", fmt.Sprintf("%t", edge.Inferred))
+		writeGraphMLData(&b, "d13", strconv.FormatFloat(edge.Confidence, 'f', -1, 64))
+		writeGraphMLData(&b, "d14", edge.Predicate)
+		b.WriteString("    </edge>\n")
+	}
+
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.String(), nil
+}
+
+// writeGraphMLData writes a data element, omitting empty values
+func writeGraphMLData(b *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "      <data key=%q>%s</data>\n", key, xmlText(value))
+}
+
+// xmlText escapes character data and attribute values
+func xmlText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -18,23 +18,41 @@ const (
 	FormatSVG OutputFormat = "svg" // SVG vector graphics
 	FormatPNG OutputFormat = "png" // PNG raster graphics
 	FormatPDF OutputFormat = "pdf" // PDF document
+
+	FormatGraphML OutputFormat = "graphml" // GraphML for yEd
+	FormatGEXF    OutputFormat = "gexf"    // GEXF for Gephi
+)
+
+// Renderer selects how SVG and PNG output is drawn
+type Renderer string
+
+const (
+	RendererAuto     Renderer = "auto"     // GraphViz when installed, otherwise built-in
+	RendererGraphViz Renderer = "graphviz" // The GraphViz binaries
+	RendererBuiltin  Renderer = "builtin"  // The built-in layout (SVG and PNG dependency graphs)
 )
 
+// ParseRenderer parses a renderer name
+func ParseRenderer(name string) (Renderer, error) {
+	switch Renderer(name) {
+	case "":
+		return RendererAuto, nil
+	case RendererAuto, RendererGraphViz, RendererBuiltin:
+		return Renderer(name), nil
+	}
+	return "", fmt.Errorf("unknown renderer %q (use auto, graphviz or builtin)", name)
+}
+
 // RenderOptions configures rendering
 type RenderOptions struct {
 	VizOptions
-	Output string       // Output file path
-	Format OutputFormat // Output format
+	Output   string       // Output file path
+	Format   OutputFormat // Output format
+	Renderer Renderer     // Renderer for SVG and PNG (default: auto)
 }
 
 // RenderToFile renders a graph to a file
 func RenderToFile(g *graph.Graph, opts RenderOptions) error {
-	// Generate DOT format
-	dotContent, err := GenerateDOT(g, opts.VizOptions)
-	if err != nil {
-		return fmt.Errorf("failed to generate DOT: %w", err)
-	}
-
 	// Determine output format from file extension if not specified
 	if opts.Format == "" {
 		ext := strings.ToLower(filepath.Ext(opts.Output))
@@ -47,16 +65,57 @@ func RenderToFile(g *graph.Graph, opts RenderOptions) error {
 			opts.Format = FormatPNG
 		case ".pdf":
 			opts.Format = FormatPDF
+		case ".graphml":
+			opts.Format = FormatGraphML
+		case ".gexf":
+			opts.Format = FormatGEXF
 		default:
 			opts.Format = FormatDOT // Default to DOT
 		}
 	}
 
+	// Graph exchange formats are written directly
+	switch opts.Format {
+	case FormatGraphML:
+		content, err := GenerateGraphML(g, opts.VizOptions)
+		if err != nil {
+			return fmt.Errorf("failed to generate GraphML: %w", err)
+		}
+		return os.WriteFile(opts.Output, []byte(content), 0644)
+	case FormatGEXF:
+		content, err := GenerateGEXF(g, opts.VizOptions)
+		if err != nil {
+			return fmt.Errorf("failed to generate GEXF: %w", err)
+		}
+		return os.WriteFile(opts.Output, []byte(content), 0644)
+	}
+
+	// Generate DOT format
+	dotContent, err := GenerateDOT(g, opts.VizOptions)
+	if err != nil {
+		return fmt.Errorf("failed to generate DOT: %w", err)
+	}
+
 	// For DOT format, just write the file
 	if opts.Format == FormatDOT {
 		return os.WriteFile(opts.Output, []byte(dotContent), 0644)
 	}
 
+	builtin := supportsBuiltin(opts)
+	switch opts.Renderer {
+	case RendererBuiltin:
+		if !builtin {
+			return fmt.Errorf("the built-in renderer draws svg and png dependency graphs, not %s %s graphs", opts.Format, opts.Type)
+		}
+		return renderBuiltin(g, opts)
+	case RendererGraphViz:
+		// Rendered below
+	default:
+		if builtin && ValidateLayout(opts.Layout) != nil {
+			return renderBuiltin(g, opts)
+		}
+	}
+
 	// For other formats, check if GraphViz is available
 	if !isGraphVizAvailable() {
 		// Fall back to DOT format with warning
@@ -72,6 +131,35 @@ func RenderToFile(g *graph.Graph, opts RenderOptions) error {
 	return renderWithGraphViz(dotContent, opts)
 }
 
+// supportsBuiltin reports whether the built-in renderer can draw the output
+func supportsBuiltin(opts RenderOptions) bool {
+	if opts.Format != FormatSVG && opts.Format != FormatPNG {
+		return false
+	}
+	return opts.Type == "" || opts.Type == VizDependency
+}
+
+// renderBuiltin lays out and draws a graph without GraphViz
+func renderBuiltin(g *graph.Graph, opts RenderOptions) error {
+	layout, err := LayoutGraph(g, opts.VizOptions)
+	if err != nil {
+		return err
+	}
+	if opts.Format == FormatSVG {
+		return os.WriteFile(opts.Output, []byte(RenderSVG(layout)), 0644)
+	}
+
+	file, err := os.Create(opts.Output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", opts.Output, err)
+	}
+	if err := RenderPNG(layout, file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to render PNG: %w", err)
+	}
+	return file.Close()
+}
+
 // isGraphVizAvailable checks if GraphViz is installed
 func isGraphVizAvailable() bool {
 	_, err := exec.LookPath("dot")
@@ -0,0 +1,423 @@
+/*
+# Module: pkg/viz/layout.go
+Built-in layered graph layout.
+
+Lays out a dependency graph without GraphViz: cycles are broken by reversing
+back edges, modules are assigned to layers by longest path, edges spanning
+several layers pass through a virtual node in each, layers are ordered by
+barycenter sweeps to reduce crossings, and edges are routed as cubic curves
+through their virtual nodes. Modules, labels, colors and sampling are those
+of the DOT output, so both renderers draw the same graph.
+
+## Linked Modules
+- [dot](./dot.go) - DOT generation (module selection and styling)
+- [svg](./svg.go) - SVG and PNG rendering of layouts
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+visualization, layout, svg, png
+
+## Exports
+Layout, LayoutNode, LayoutEdge, Point, LayoutGraph
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#layout.go> a code:Module ;
+    code:name "pkg/viz/layout.go" ;
+    code:description "Built-in layered graph layout" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./dot.go>, <./svg.go>, <../graph/graph.go> ;
+    code:exports <#Layout>, <#LayoutNode>, <#LayoutEdge>, <#Point>, <#LayoutGraph> ;
+    code:tags "visualization", "layout", "svg", "png" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+const (
+	layoutFontSize   = 12.0 // Label font size
+	layoutCharWidth  = 7.0  // Approximate width of a label character
+	layoutLineHeight = 15.0 // Height of a label line
+	layoutPadX       = 10.0 // Horizontal padding inside nodes
+	layoutPadY       = 8.0  // Vertical padding inside nodes
+	layoutNodeGap    = 20.0 // Space between nodes of a layer
+	layoutLayerGap   = 70.0 // Space between layers
+	layoutVirtual    = 6.0  // Size of the virtual nodes long edges pass through
+	layoutMargin     = 20.0 // Space around the drawing
+
+	// layoutSweeps is the number of crossing reduction passes
+	layoutSweeps = 8
+)
+
+// Point is a position in a layout
+type Point struct {
+	X, Y float64
+}
+
+// Layout is a layered drawing of a graph
+type Layout struct {
+	Width, Height float64
+	Title         []string // Title and sampling banner lines
+	Nodes         []*LayoutNode
+	Edges         []*LayoutEdge
+}
+
+// LayoutNode is a module placed in a layout
+type LayoutNode struct {
+	Module *graph.Module // nil for the virtual nodes of long edges
+	Label  []string      // Label lines
+	Color  string        // Fill color (#RRGGBB)
+	X, Y   float64       // Top-left corner
+	Width  float64
+	Height float64
+
+	layer int
+	order int
+}
+
+// LayoutEdge is a dependency routed in a layout
+type LayoutEdge struct {
+	From, To *LayoutNode
+	Edge     graph.Edge
+	Curve    []Point // Cubic Bézier segments: start, then two control points and an end per segment
+
+	reversed bool          // Drawn against the layering to break a cycle
+	via      []*LayoutNode // Virtual nodes between From and To, in order
+}
+
+// path returns the nodes an edge passes through, from its source
+func (e *LayoutEdge) path() []*LayoutNode {
+	return append(append([]*LayoutNode{e.From}, e.via...), e.To)
+}
+
+// Center returns the center of a node
+func (n *LayoutNode) Center() Point {
+	return Point{n.X + n.Width/2, n.Y + n.Height/2}
+}
+
+// LayoutGraph lays out the modules and dependencies that GenerateDOT would
+// draw for opts, in the direction of opts.Rankdir
+func LayoutGraph(g *graph.Graph, opts VizOptions) (*Layout, error) {
+	dg := NewDOTGenerator(g, opts)
+	if err := dg.applySampling(); err != nil {
+		return nil, err
+	}
+
+	modules := dg.getFilteredModules()
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Path < modules[j].Path })
+
+	layout := &Layout{}
+	if dg.options.Title != "" {
+		layout.Title = strings.Split(dg.options.Title, "\n")
+	}
+	layout.Title = append(layout.Title, dg.sample.Banner()...)
+
+	nodes := make(map[string]*LayoutNode, len(modules))
+	for _, module := range modules {
+		node := &LayoutNode{
+			Module: module,
+			Label:  strings.Split(dg.getNodeLabel(module), "\n"),
+			Color:  dg.getNodeColor(module),
+		}
+		longest := 0
+		for _, line := range node.Label {
+			if n := len([]rune(line)); n > longest {
+				longest = n
+			}
+		}
+		scale := 1.0
+		if dg.options.Criticality != nil {
+			scale += dg.options.Criticality[module.Path]
+		}
+		node.Width = (float64(longest)*layoutCharWidth + 2*layoutPadX) * scale
+		node.Height = (float64(len(node.Label))*layoutLineHeight + 2*layoutPadY) * scale
+		nodes[module.Path] = node
+		layout.Nodes = append(layout.Nodes, node)
+	}
+
+	for _, module := range modules {
+		seen := make(map[string]bool)
+		for _, dep := range module.Dependencies {
+			target, ok := nodes[dep]
+			if !ok || dep == module.Path || seen[dep] || !dg.options.Filter.MatchEdge(module.EdgeTo(dep)) {
+				continue
+			}
+			seen[dep] = true
+			layout.Edges = append(layout.Edges, &LayoutEdge{From: nodes[module.Path], To: target, Edge: module.EdgeTo(dep)})
+		}
+	}
+
+	layers := assignLayers(layout)
+	addVirtualNodes(layout, layers)
+	orderLayers(layout, layers)
+	placeNodes(layout, layers, dg.options.Rankdir)
+	routeEdges(layout, dg.options.Rankdir)
+	return layout, nil
+}
+
+// assignLayers breaks cycles and puts every node one layer after the
+// furthest node depending on it
+func assignLayers(layout *Layout) [][]*LayoutNode {
+	out := make(map[*LayoutNode][]*LayoutEdge)
+	for _, edge := range layout.Edges {
+		out[edge.From] = append(out[edge.From], edge)
+	}
+
+	// Depth-first search; edges back to a node on the stack close a cycle
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[*LayoutNode]int)
+	var postorder []*LayoutNode
+	var visit func(node *LayoutNode)
+	visit = func(node *LayoutNode) {
+		state[node] = visiting
+		for _, edge := range out[node] {
+			switch state[edge.To] {
+			case visiting:
+				edge.reversed = true
+			case unvisited:
+				visit(edge.To)
+			}
+		}
+		state[node] = done
+		postorder = append(postorder, node)
+	}
+	for _, node := range layout.Nodes {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+
+	// Reverse postorder is a topological order of the acyclic edges
+	successors := make(map[*LayoutNode][]*LayoutNode)
+	for _, edge := range layout.Edges {
+		if edge.reversed {
+			successors[edge.To] = append(successors[edge.To], edge.From)
+		} else {
+			successors[edge.From] = append(successors[edge.From], edge.To)
+		}
+	}
+	maxLayer := 0
+	for i := len(postorder) - 1; i >= 0; i-- {
+		node := postorder[i]
+		for _, next := range successors[node] {
+			if next.layer < node.layer+1 {
+				next.layer = node.layer + 1
+				if next.layer > maxLayer {
+					maxLayer = next.layer
+				}
+			}
+		}
+	}
+
+	if len(layout.Nodes) == 0 {
+		return nil
+	}
+	layers := make([][]*LayoutNode, maxLayer+1)
+	for i := len(postorder) - 1; i >= 0; i-- {
+		node := postorder[i]
+		node.order = len(layers[node.layer])
+		layers[node.layer] = append(layers[node.layer], node)
+	}
+	return layers
+}
+
+// addVirtualNodes gives edges spanning several layers a node in each layer
+// they cross, so ordering keeps them clear of other nodes
+func addVirtualNodes(layout *Layout, layers [][]*LayoutNode) {
+	for _, edge := range layout.Edges {
+		upper, lower := edge.From, edge.To
+		if edge.reversed {
+			upper, lower = lower, upper
+		}
+		for layer := upper.layer + 1; layer < lower.layer; layer++ {
+			node := &LayoutNode{Width: layoutVirtual, Height: layoutVirtual, layer: layer, order: len(layers[layer])}
+			layers[layer] = append(layers[layer], node)
+			edge.via = append(edge.via, node)
+		}
+		if edge.reversed {
+			for i, j := 0, len(edge.via)-1; i < j; i, j = i+1, j-1 {
+				edge.via[i], edge.via[j] = edge.via[j], edge.via[i]
+			}
+		}
+	}
+}
+
+// orderLayers reorders each layer by the mean position of its neighbours in
+// the layers already swept, alternating downwards and upwards
+func orderLayers(layout *Layout, layers [][]*LayoutNode) {
+	neighbours := make(map[*LayoutNode][]*LayoutNode)
+	for _, edge := range layout.Edges {
+		path := edge.path()
+		for i := 1; i < len(path); i++ {
+			neighbours[path[i-1]] = append(neighbours[path[i-1]], path[i])
+			neighbours[path[i]] = append(neighbours[path[i]], path[i-1])
+		}
+	}
+
+	reorder := func(layer []*LayoutNode, before func(n *LayoutNode) bool) {
+		barycenter := make(map[*LayoutNode]float64, len(layer))
+		for _, node := range layer {
+			sum, count := 0.0, 0
+			for _, n := range neighbours[node] {
+				if before(n) {
+					sum += float64(n.order)
+					count++
+				}
+			}
+			if count > 0 {
+				barycenter[node] = sum / float64(count)
+			} else {
+				barycenter[node] = float64(node.order)
+			}
+		}
+		sort.SliceStable(layer, func(i, j int) bool { return barycenter[layer[i]] < barycenter[layer[j]] })
+		for i, node := range layer {
+			node.order = i
+		}
+	}
+
+	for sweep := 0; sweep < layoutSweeps; sweep++ {
+		if sweep%2 == 0 {
+			for i := 1; i < len(layers); i++ {
+				reorder(layers[i], func(n *LayoutNode) bool { return n.layer < i })
+			}
+		} else {
+			for i := len(layers) - 2; i >= 0; i-- {
+				reorder(layers[i], func(n *LayoutNode) bool { return n.layer > i })
+			}
+		}
+	}
+}
+
+// placeNodes assigns coordinates, with layers as columns for LR and RL and
+// as rows for TB and BT, each layer centered on the widest
+func placeNodes(layout *Layout, layers [][]*LayoutNode, rankdir string) {
+	horizontal := rankdir != "TB" && rankdir != "BT"
+	titleHeight := float64(len(layout.Title)) * layoutLineHeight * 1.3
+
+	// Extent of each layer along the ranks and across them
+	depth := make([]float64, len(layers))
+	breadth := make([]float64, len(layers))
+	maxBreadth := 0.0
+	for i, layer := range layers {
+		for j, node := range layer {
+			along, across := node.Height, node.Width
+			if horizontal {
+				along, across = node.Width, node.Height
+			}
+			if along > depth[i] {
+				depth[i] = along
+			}
+			if j > 0 {
+				breadth[i] += layoutNodeGap
+			}
+			breadth[i] += across
+		}
+		if breadth[i] > maxBreadth {
+			maxBreadth = breadth[i]
+		}
+	}
+
+	position := 0.0
+	for i, layer := range layers {
+		offset := (maxBreadth - breadth[i]) / 2
+		for _, node := range layer {
+			if horizontal {
+				node.X = position + (depth[i]-node.Width)/2
+				node.Y = offset
+				offset += node.Height + layoutNodeGap
+			} else {
+				node.X = offset
+				node.Y = position + (depth[i]-node.Height)/2
+				offset += node.Width + layoutNodeGap
+			}
+		}
+		position += depth[i] + layoutLayerGap
+	}
+	if len(layers) > 0 {
+		position -= layoutLayerGap
+	}
+
+	width, height := position, maxBreadth
+	if !horizontal {
+		width, height = maxBreadth, position
+	}
+	for _, layer := range layers {
+		for _, node := range layer {
+			switch rankdir {
+			case "RL":
+				node.X = width - node.X - node.Width
+			case "BT":
+				node.Y = height - node.Y - node.Height
+			}
+			node.X += layoutMargin
+			node.Y += layoutMargin + titleHeight
+		}
+	}
+
+	layout.Width = width + 2*layoutMargin
+	layout.Height = height + 2*layoutMargin + titleHeight
+	for _, line := range layout.Title {
+		if w := float64(len([]rune(line)))*layoutCharWidth + 2*layoutMargin; w > layout.Width {
+			layout.Width = w
+		}
+	}
+}
+
+// routeEdges draws each edge from the side of its source facing the next
+// node on its path, through its virtual nodes, to the side of its target
+// facing the previous one, leaving and entering along the rank direction
+func routeEdges(layout *Layout, rankdir string) {
+	horizontal := rankdir != "TB" && rankdir != "BT"
+
+	// side returns the middle of the side of node facing towards
+	side := func(node *LayoutNode, towards Point) Point {
+		center := node.Center()
+		if horizontal {
+			if towards.X >= center.X {
+				return Point{node.X + node.Width, center.Y}
+			}
+			return Point{node.X, center.Y}
+		}
+		if towards.Y >= center.Y {
+			return Point{center.X, node.Y + node.Height}
+		}
+		return Point{center.X, node.Y}
+	}
+
+	for _, edge := range layout.Edges {
+		path := edge.path()
+		points := make([]Point, len(path))
+		for i, node := range path {
+			points[i] = node.Center()
+		}
+		points[0] = side(edge.From, points[1])
+		points[len(points)-1] = side(edge.To, points[len(points)-2])
+
+		edge.Curve = []Point{points[0]}
+		for i := 1; i < len(points); i++ {
+			a, b := points[i-1], points[i]
+			if horizontal {
+				mid := (a.X + b.X) / 2
+				edge.Curve = append(edge.Curve, Point{mid, a.Y}, Point{mid, b.Y}, b)
+			} else {
+				mid := (a.Y + b.Y) / 2
+				edge.Curve = append(edge.Curve, Point{a.X, mid}, Point{b.X, mid}, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package viz
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLayoutGraph(t *testing.T) {
+	g := createTestGraph()
+	layout, err := LayoutGraph(g, VizOptions{ColorBy: "layer"})
+	if err != nil {
+		t.Fatalf("LayoutGraph failed: %v", err)
+	}
+	if len(layout.Nodes) != 4 || len(layout.Edges) != 4 {
+		t.Fatalf("Expected 4 nodes and 4 edges, got %d and %d", len(layout.Nodes), len(layout.Edges))
+	}
+
+	nodes := make(map[string]*LayoutNode)
+	for _, node := range layout.Nodes {
+		nodes[node.Module.Path] = node
+	}
+	api, auth, users, data := nodes["api/handlers.go"], nodes["services/auth.go"], nodes["services/users.go"], nodes["data/users.go"]
+
+	// Left to right: dependents before their dependencies
+	if !(api.X < auth.X && auth.X < data.X) || auth.Center().X != users.Center().X {
+		t.Errorf("Unexpected columns: api %.0f, auth %.0f, users %.0f, data %.0f", api.X, auth.X, users.X, data.X)
+	}
+	if auth.Y+auth.Height > users.Y && users.Y+users.Height > auth.Y {
+		t.Error("Nodes of a layer overlap")
+	}
+	if api.Color != "#4CAF50" {
+		t.Errorf("Expected layer colors, got %s", api.Color)
+	}
+	for _, node := range layout.Nodes {
+		if node.X < 0 || node.Y < 0 || node.X+node.Width > layout.Width || node.Y+node.Height > layout.Height {
+			t.Errorf("Node %s lies outside the layout", node.Module.Path)
+		}
+	}
+
+	// Top to bottom turns columns into rows
+	layout, err = LayoutGraph(g, VizOptions{Rankdir: "TB"})
+	if err != nil {
+		t.Fatalf("LayoutGraph(TB) failed: %v", err)
+	}
+	for _, node := range layout.Nodes {
+		nodes[node.Module.Path] = node
+	}
+	if !(nodes["api/handlers.go"].Y < nodes["services/auth.go"].Y && nodes["services/auth.go"].Y < nodes["data/users.go"].Y) {
+		t.Error("Expected layers as rows for TB")
+	}
+}
+
+func TestLayoutGraphCyclesAndLongEdges(t *testing.T) {
+	g := createTestGraph()
+	api := g.Modules["api/handlers.go"]
+	api.Dependencies = append(api.Dependencies, "data/users.go")
+	g.Modules["data/users.go"].Dependencies = []string{"api/handlers.go"}
+
+	layout, err := LayoutGraph(g, VizOptions{})
+	if err != nil {
+		t.Fatalf("LayoutGraph failed: %v", err)
+	}
+	if len(layout.Edges) != 6 {
+		t.Fatalf("Expected 6 edges, got %d", len(layout.Edges))
+	}
+
+	reversed := 0
+	for _, edge := range layout.Edges {
+		if edge.reversed {
+			reversed++
+		}
+		// Each layer crossed adds a virtual node and a curve segment
+		if len(edge.Curve) != 3*len(edge.path())-2 {
+			t.Errorf("Edge %s -> %s has %d curve points for %d virtual nodes",
+				edge.From.Module.Path, edge.To.Module.Path, len(edge.Curve), len(edge.via))
+		}
+		start, end := edge.Curve[0], edge.Curve[len(edge.Curve)-1]
+		if start.X != edge.From.X && start.X != edge.From.X+edge.From.Width {
+			t.Errorf("Edge %s -> %s does not start on its source", edge.From.Module.Path, edge.To.Module.Path)
+		}
+		if end.X != edge.To.X && end.X != edge.To.X+edge.To.Width {
+			t.Errorf("Edge %s -> %s does not end on its target", edge.From.Module.Path, edge.To.Module.Path)
+		}
+	}
+	if reversed != 1 {
+		t.Errorf("Expected one edge reversed to break the cycle, got %d", reversed)
+	}
+}
+
+func TestRenderSVGAndPNG(t *testing.T) {
+	g := createTestGraph()
+	layout, err := LayoutGraph(g, VizOptions{Title: "Test <graph>"})
+	if err != nil {
+		t.Fatalf("LayoutGraph failed: %v", err)
+	}
+
+	svg := RenderSVG(layout)
+	for _, want := range []string{"<svg", "Test &lt;graph&gt;", "handlers.go", "<path d=\"M"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %q", want)
+		}
+	}
+	if strings.Count(svg, "<polygon") != len(layout.Edges) {
+		t.Errorf("Expected an arrowhead per edge")
+	}
+
+	var buf bytes.Buffer
+	if err := RenderPNG(layout, &buf); err != nil {
+		t.Fatalf("RenderPNG failed: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Invalid PNG: %v", err)
+	}
+	if img.Bounds().Dx() < int(layout.Width)*pngScale || img.Bounds().Dy() < int(layout.Height)*pngScale {
+		t.Errorf("PNG is %v, layout %.0fx%.0f", img.Bounds(), layout.Width, layout.Height)
+	}
+}
+
+func TestRenderToFileBuiltin(t *testing.T) {
+	g := createTestGraph()
+	dir := t.TempDir()
+
+	for _, name := range []string{"deps.svg", "deps.png"} {
+		output := filepath.Join(dir, name)
+		if err := RenderToFile(g, RenderOptions{Output: output, Renderer: RendererBuiltin}); err != nil {
+			t.Fatalf("RenderToFile(%s) failed: %v", name, err)
+		}
+		if info, err := os.Stat(output); err != nil || info.Size() == 0 {
+			t.Errorf("Expected %s to be written", name)
+		}
+	}
+
+	err := RenderToFile(g, RenderOptions{Output: filepath.Join(dir, "deps.pdf"), Renderer: RendererBuiltin})
+	if err == nil {
+		t.Error("Expected the built-in renderer to reject PDF")
+	}
+	if _, err := ParseRenderer("cairo"); err == nil {
+		t.Error("Expected an error for an unknown renderer")
+	}
+}
@@ -27,7 +27,10 @@ type MermaidOptions struct {
 	Filter       *FilterOptions
 	Links        bool // Add clickable links
 	Title        string
-	UseSubgraphs bool // Group nodes by layer/package
+	UseSubgraphs bool                       // Group nodes by layer/package
+	Sampling     *SamplingOptions           // Sampling for large graphs (optional)
+	Collapse     *CollapseOptions           // Collapse modules into directories (optional)
+	Security     *analysis.SecurityAnalysis // Security analysis results (for ColorBy security)
 }
 
 // MermaidGenerator generates Mermaid diagram syntax
@@ -37,23 +40,77 @@ type MermaidGenerator struct {
 	builder strings.Builder
 	nodeIDs map[string]string // Map module paths to sanitized IDs
 	colors  map[string]string // Map for node colors
+
+	violationEdges []int // Indexes of edges that violate security policy
+	collapsed      bool  // Modules were collapsed into directories
 }
 
 // GenerateMermaid generates a Mermaid diagram from the graph
 func GenerateMermaid(g *graph.Graph, opts MermaidOptions) (string, error) {
+	diagram, _, err := generateMermaid(g, opts)
+	return diagram, err
+}
+
+// generateMermaid samples the graph if configured and generates the diagram
+func generateMermaid(g *graph.Graph, opts MermaidOptions) (string, *SampleResult, error) {
+	if opts.Collapse != nil {
+		collapsed, err := CollapseGraph(g, *opts.Collapse, opts.Filter)
+		if err != nil {
+			return "", nil, err
+		}
+		g = collapsed
+		opts.Filter = nil
+	}
+
+	var sample *SampleResult
+	if opts.Sampling != nil {
+		var err error
+		sample, err = SampleGraph(g, *opts.Sampling)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to sample graph: %w", err)
+		}
+		g = sample.Graph
+	}
+
 	gen := &MermaidGenerator{
-		graph:   g,
-		options: opts,
-		nodeIDs: make(map[string]string),
-		colors:  make(map[string]string),
+		graph:     g,
+		options:   opts,
+		nodeIDs:   make(map[string]string),
+		colors:    make(map[string]string),
+		collapsed: opts.Collapse != nil,
+	}
+
+	diagram, err := gen.generate()
+	if err != nil {
+		return "", nil, err
 	}
 
-	return gen.generate()
+	return withMermaidBanner(diagram, sample.Banner()), sample, nil
+}
+
+// withMermaidBanner inserts sampling warnings as comments after the diagram declaration
+func withMermaidBanner(diagram string, banner []string) string {
+	if len(banner) == 0 {
+		return diagram
+	}
+
+	declaration, body, _ := strings.Cut(diagram, "\n")
+
+	var sb strings.Builder
+	sb.WriteString(declaration)
+	sb.WriteString("\n")
+	for _, line := range banner {
+		sb.WriteString("    %% ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(body)
+	return sb.String()
 }
 
 // GenerateMermaidMarkdown generates Mermaid embedded in Markdown code block
 func GenerateMermaidMarkdown(g *graph.Graph, opts MermaidOptions) (string, error) {
-	mermaid, err := GenerateMermaid(g, opts)
+	mermaid, sample, err := generateMermaid(g, opts)
 	if err != nil {
 		return "", err
 	}
@@ -64,6 +121,14 @@ func GenerateMermaidMarkdown(g *graph.Graph, opts MermaidOptions) (string, error
 		sb.WriteString(opts.Title)
 		sb.WriteString("\n\n")
 	}
+	for _, line := range sample.Banner() {
+		sb.WriteString("> ⚠️ ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	if sample.Banner() != nil {
+		sb.WriteString("\n")
+	}
 	sb.WriteString("```mermaid\n")
 	sb.WriteString(mermaid)
 	sb.WriteString("\n```\n")
@@ -110,7 +175,9 @@ func (mg *MermaidGenerator) generateFlowchart() (string, error) {
 	}
 
 	// Generate nodes and edges based on organization
-	if mg.options.UseSubgraphs {
+	if mg.showSecurityZones() {
+		mg.generateWithSecurityZones(modules)
+	} else if mg.options.UseSubgraphs {
 		mg.generateWithSubgraphs(modules)
 	} else {
 		mg.generateNodesAndEdges(modules)
@@ -135,7 +202,9 @@ func (mg *MermaidGenerator) generateGraph() (string, error) {
 		mg.nodeIDs[module.Path] = mg.sanitizeNodeID(module.Path)
 	}
 
-	if mg.options.UseSubgraphs {
+	if mg.showSecurityZones() {
+		mg.generateWithSecurityZones(modules)
+	} else if mg.options.UseSubgraphs {
 		mg.generateWithSubgraphs(modules)
 	} else {
 		mg.generateNodesAndEdges(modules)
@@ -182,7 +251,7 @@ func (mg *MermaidGenerator) generateClassDiagram() (string, error) {
 		className := mg.sanitizeClassName(module.Name)
 		for _, dep := range module.Dependencies {
 			depModule := mg.graph.GetModule(dep)
-			if depModule != nil && mg.shouldIncludeModule(depModule) {
+			if depModule != nil && mg.shouldIncludeModule(depModule) && mg.shouldIncludeEdge(module, dep) {
 				depClassName := mg.sanitizeClassName(depModule.Name)
 				mg.builder.WriteString(fmt.Sprintf("    %s --> %s : depends on\n",
 					className, depClassName))
@@ -203,8 +272,8 @@ func (mg *MermaidGenerator) generateNodesAndEdges(modules []*graph.Module) {
 		// Use different shapes based on layer or type
 		shape := mg.getNodeShape(module)
 
-		mg.builder.WriteString(fmt.Sprintf("    %s%s%s\n",
-			nodeID, shape, escapeMermaidLabel(label)))
+		mg.builder.WriteString(fmt.Sprintf("    %s%s%s%s\n",
+			nodeID, shape, escapeMermaidLabel(label), closeNodeShape(shape)))
 	}
 
 	// Generate edges
@@ -212,8 +281,8 @@ func (mg *MermaidGenerator) generateNodesAndEdges(modules []*graph.Module) {
 	for _, module := range modules {
 		fromID := mg.nodeIDs[module.Path]
 		for _, dep := range module.Dependencies {
-			if toID, exists := mg.nodeIDs[dep]; exists {
-				mg.builder.WriteString(fmt.Sprintf("    %s --> %s\n", fromID, toID))
+			if toID, exists := mg.nodeIDs[dep]; exists && mg.shouldIncludeEdge(module, dep) {
+				mg.builder.WriteString(fmt.Sprintf("    %s %s %s\n", fromID, mg.edgeArrow(module, dep), toID))
 			}
 		}
 	}
@@ -247,8 +316,8 @@ func (mg *MermaidGenerator) generateWithSubgraphs(modules []*graph.Module) {
 			label := mg.getNodeLabel(module)
 			shape := mg.getNodeShape(module)
 
-			mg.builder.WriteString(fmt.Sprintf("        %s%s%s\n",
-				nodeID, shape, escapeMermaidLabel(label)))
+			mg.builder.WriteString(fmt.Sprintf("        %s%s%s%s\n",
+				nodeID, shape, escapeMermaidLabel(label), closeNodeShape(shape)))
 		}
 
 		mg.builder.WriteString("    end\n\n")
@@ -258,8 +327,8 @@ func (mg *MermaidGenerator) generateWithSubgraphs(modules []*graph.Module) {
 	for _, module := range modules {
 		fromID := mg.nodeIDs[module.Path]
 		for _, dep := range module.Dependencies {
-			if toID, exists := mg.nodeIDs[dep]; exists {
-				mg.builder.WriteString(fmt.Sprintf("    %s --> %s\n", fromID, toID))
+			if toID, exists := mg.nodeIDs[dep]; exists && mg.shouldIncludeEdge(module, dep) {
+				mg.builder.WriteString(fmt.Sprintf("    %s %s %s\n", fromID, mg.edgeArrow(module, dep), toID))
 			}
 		}
 	}
@@ -281,10 +350,171 @@ func (mg *MermaidGenerator) addStyling(modules []*graph.Module) {
 		mg.addLanguageStyling(modules)
 	case "security":
 		// Security styling requires security analysis
-		// This would be added if security analysis is provided
+		if mg.options.Security != nil {
+			mg.addSecurityStyling(modules)
+		}
+	}
+}
+
+// showSecurityZones reports whether nodes should be grouped by security zone
+func (mg *MermaidGenerator) showSecurityZones() bool {
+	return mg.options.ColorBy == "security" && mg.options.Security != nil
+}
+
+// generateWithSecurityZones generates nodes grouped by security zone in
+// subgraphs, a legend, and edges with policy violations highlighted
+func (mg *MermaidGenerator) generateWithSecurityZones(modules []*graph.Module) {
+	sec := mg.options.Security
+
+	included := make(map[string]*graph.Module)
+	for _, module := range modules {
+		included[module.Path] = module
+	}
+
+	// Generate zone subgraphs
+	zoned := make(map[string]bool)
+	for _, zone := range securityZoneOrder {
+		var zoneModules []*graph.Module
+		for _, mz := range sec.Zones[zone] {
+			if module, ok := included[mz.Module.Path]; ok && !zoned[module.Path] {
+				zoneModules = append(zoneModules, module)
+				zoned[module.Path] = true
+			}
+		}
+		if len(zoneModules) == 0 {
+			continue
+		}
+
+		mg.builder.WriteString(fmt.Sprintf("    subgraph zone_%s[\"%s\"]\n", zone, zoneLabel(zone)))
+		for _, module := range zoneModules {
+			shape := mg.getNodeShape(module)
+			mg.builder.WriteString(fmt.Sprintf("        %s%s%s%s\n",
+				mg.nodeIDs[module.Path], shape, escapeMermaidLabel(mg.getNodeLabel(module)), closeNodeShape(shape)))
+		}
+		mg.builder.WriteString("    end\n\n")
+	}
+
+	// Modules without a zone (e.g. layer nodes from sampling) stay outside
+	for _, module := range modules {
+		if !zoned[module.Path] {
+			shape := mg.getNodeShape(module)
+			mg.builder.WriteString(fmt.Sprintf("    %s%s%s%s\n",
+				mg.nodeIDs[module.Path], shape, escapeMermaidLabel(mg.getNodeLabel(module)), closeNodeShape(shape)))
+		}
+	}
+
+	// Generate legend
+	mg.builder.WriteString("    subgraph legend[\"Legend\"]\n")
+	for _, zone := range securityZoneOrder {
+		if len(sec.Zones[zone]) > 0 {
+			mg.builder.WriteString(fmt.Sprintf("        legend_%s[\"%s\"]\n", zone, zoneLabel(zone)))
+		}
+	}
+	mg.builder.WriteString("        legend_violation[\"Red edge: policy violation\"]\n")
+	mg.builder.WriteString("    end\n\n")
+
+	// Generate edges, tracking violations by edge index for linkStyle
+	violations := securityViolationEdges(sec)
+	edgeIndex := 0
+	for _, module := range modules {
+		fromID := mg.nodeIDs[module.Path]
+		for _, dep := range module.Dependencies {
+			toID, exists := mg.nodeIDs[dep]
+			if !exists || !mg.shouldIncludeEdge(module, dep) {
+				continue
+			}
+			if violations[module.Path][dep] {
+				mg.builder.WriteString(fmt.Sprintf("    %s ==>|violation| %s\n", fromID, toID))
+				mg.violationEdges = append(mg.violationEdges, edgeIndex)
+			} else {
+				mg.builder.WriteString(fmt.Sprintf("    %s --> %s\n", fromID, toID))
+			}
+			edgeIndex++
+		}
 	}
 }
 
+// addSecurityStyling colors nodes, zones and legend entries by security
+// zone and highlights violation edges
+func (mg *MermaidGenerator) addSecurityStyling(modules []*graph.Module) {
+	sec := mg.options.Security
+
+	zoneOf := make(map[string]analysis.SecurityZone)
+	for _, zone := range securityZoneOrder {
+		for _, mz := range sec.Zones[zone] {
+			if _, ok := zoneOf[mz.Module.Path]; !ok {
+				zoneOf[mz.Module.Path] = zone
+			}
+		}
+	}
+
+	for _, zone := range securityZoneOrder {
+		if len(sec.Zones[zone]) == 0 {
+			continue
+		}
+		color := securityZoneColors[zone]
+		mg.builder.WriteString(fmt.Sprintf("    classDef %s fill:%s,stroke:#333,stroke-width:2px\n",
+			zoneClass(zone), color))
+	}
+	mg.builder.WriteString("    classDef legendViolation fill:#FFFFFF,stroke:#F44336,stroke-width:3px\n")
+
+	mg.builder.WriteString("\n")
+	for _, zone := range securityZoneOrder {
+		if len(sec.Zones[zone]) == 0 {
+			continue
+		}
+		color := securityZoneColors[zone]
+		mg.builder.WriteString(fmt.Sprintf("    style zone_%s fill:%s20,stroke:%s\n", zone, color, color))
+	}
+
+	mg.builder.WriteString("\n")
+	for _, module := range modules {
+		if zone, ok := zoneOf[module.Path]; ok {
+			mg.builder.WriteString(fmt.Sprintf("    class %s %s\n", mg.nodeIDs[module.Path], zoneClass(zone)))
+		}
+	}
+	for _, zone := range securityZoneOrder {
+		if len(sec.Zones[zone]) > 0 {
+			mg.builder.WriteString(fmt.Sprintf("    class legend_%s %s\n", zone, zoneClass(zone)))
+		}
+	}
+	mg.builder.WriteString("    class legend_violation legendViolation\n")
+
+	for _, index := range mg.violationEdges {
+		mg.builder.WriteString(fmt.Sprintf("    linkStyle %d stroke:#F44336,stroke-width:3px\n", index))
+	}
+}
+
+// securityViolationEdges indexes violating dependencies by source and destination path
+func securityViolationEdges(sec *analysis.SecurityAnalysis) map[string]map[string]bool {
+	edges := make(map[string]map[string]bool)
+	for _, violation := range sec.Violations {
+		if violation.Crossing == nil {
+			continue
+		}
+		from := violation.Crossing.Source.Path
+		if edges[from] == nil {
+			edges[from] = make(map[string]bool)
+		}
+		edges[from][violation.Crossing.Destination.Path] = true
+	}
+	return edges
+}
+
+// zoneLabel returns the display label for a security zone
+func zoneLabel(zone analysis.SecurityZone) string {
+	name := string(zone)
+	if len(name) > 0 {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name + " Zone"
+}
+
+// zoneClass returns the Mermaid class name for a security zone
+func zoneClass(zone analysis.SecurityZone) string {
+	return "zone" + strings.TrimSuffix(strings.ReplaceAll(zoneLabel(zone), " ", ""), "Zone")
+}
+
 // addLayerStyling adds styling based on module layers
 func (mg *MermaidGenerator) addLayerStyling(modules []*graph.Module) {
 	layerColors := map[string]string{
@@ -392,6 +622,9 @@ func (mg *MermaidGenerator) shouldIncludeModule(module *graph.Module) bool {
 	if mg.options.Filter == nil {
 		return true
 	}
+	if !mg.options.Filter.Where.Match(module) {
+		return false
+	}
 
 	// Filter by layers
 	if len(mg.options.Filter.Layers) > 0 {
@@ -431,10 +664,27 @@ func (mg *MermaidGenerator) shouldIncludeModule(module *graph.Module) bool {
 
 // getNodeLabel returns the display label for a module
 func (mg *MermaidGenerator) getNodeLabel(module *graph.Module) string {
+	if isCollapsedDir(module) {
+		return module.Name + "<br/>" + module.Description
+	}
 	// Use short name for clarity
 	return module.Name
 }
 
+// shouldIncludeEdge checks if a dependency passes the edge filters
+func (mg *MermaidGenerator) shouldIncludeEdge(module *graph.Module, dep string) bool {
+	return mg.options.Filter.MatchEdge(module.EdgeTo(dep))
+}
+
+// edgeArrow returns the arrow for a dependency, labelled with the number of
+// dependencies it bundles in collapsed graphs
+func (mg *MermaidGenerator) edgeArrow(module *graph.Module, dep string) string {
+	if !mg.collapsed {
+		return "-->"
+	}
+	return fmt.Sprintf("-->|%d|", module.EdgeTo(dep).Weight)
+}
+
 // getNodeShape returns the Mermaid shape syntax for a module
 func (mg *MermaidGenerator) getNodeShape(module *graph.Module) string {
 	// Different shapes for different layers
@@ -452,6 +702,20 @@ func (mg *MermaidGenerator) getNodeShape(module *graph.Module) string {
 	}
 }
 
+// closeNodeShape returns the closing syntax for a shape from getNodeShape
+func closeNodeShape(shape string) string {
+	switch shape {
+	case "(":
+		return ")"
+	case "{":
+		return "}"
+	case "[/":
+		return "/]"
+	default:
+		return "]"
+	}
+}
+
 // sanitizeNodeID creates a valid Mermaid node ID from a path
 func (mg *MermaidGenerator) sanitizeNodeID(path string) string {
 	// Replace special characters with underscores
@@ -524,8 +788,8 @@ func GenerateMermaidForImpact(g *graph.Graph, impact *analysis.ImpactResult, opt
 		label := module.Name
 		shape := gen.getNodeShape(module)
 
-		gen.builder.WriteString(fmt.Sprintf("    %s%s%s\n",
-			nodeID, shape, escapeMermaidLabel(label)))
+		gen.builder.WriteString(fmt.Sprintf("    %s%s%s%s\n",
+			nodeID, shape, escapeMermaidLabel(label), closeNodeShape(shape)))
 	}
 
 	// Generate edges
@@ -572,6 +836,51 @@ func GenerateMermaidForImpact(g *graph.Graph, impact *analysis.ImpactResult, opt
 	return gen.builder.String(), nil
 }
 
+// GenerateMermaidForComponents generates a Mermaid diagram of declared
+// components, with edges labelled by the number of module dependencies and
+// boundary violations highlighted
+func GenerateMermaidForComponents(components *analysis.ComponentAnalysis, opts MermaidOptions) (string, error) {
+	if components == nil || len(components.Components) == 0 {
+		return "", fmt.Errorf("no components to display")
+	}
+	if opts.Direction == "" {
+		opts.Direction = "TD"
+	}
+
+	gen := &MermaidGenerator{options: opts}
+	gen.builder.WriteString(fmt.Sprintf("flowchart %s\n", opts.Direction))
+
+	for _, component := range components.Components {
+		label := fmt.Sprintf("%s<br/>%d modules", component.Name, len(component.Modules))
+		gen.builder.WriteString(fmt.Sprintf("    %s[%s]\n",
+			gen.sanitizeNodeID("component_"+component.Name), escapeMermaidLabel(label)))
+	}
+
+	gen.builder.WriteString("\n")
+	var violations []int
+	for i, edge := range components.Edges {
+		label := fmt.Sprintf("%d", len(edge.Dependencies))
+		if edge.NonAPI > 0 {
+			label += fmt.Sprintf(" (%d non-API)", edge.NonAPI)
+		}
+		gen.builder.WriteString(fmt.Sprintf("    %s -->|%s| %s\n",
+			gen.sanitizeNodeID("component_"+edge.From), escapeMermaidLabel(label), gen.sanitizeNodeID("component_"+edge.To)))
+		if edge.NonAPI > 0 || !edge.Declared {
+			violations = append(violations, i)
+		}
+	}
+
+	gen.builder.WriteString("\n    classDef component fill:#E3F2FD,stroke:#1565C0,stroke-width:2px\n")
+	for _, component := range components.Components {
+		gen.builder.WriteString(fmt.Sprintf("    class %s component\n", gen.sanitizeNodeID("component_"+component.Name)))
+	}
+	for _, i := range violations {
+		gen.builder.WriteString(fmt.Sprintf("    linkStyle %d stroke:#D32F2F,stroke-width:3px\n", i))
+	}
+
+	return gen.builder.String(), nil
+}
+
 // contains checks if a string slice contains a value
 func contains(slice []string, value string) bool {
 	for _, item := range slice {
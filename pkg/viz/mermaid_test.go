@@ -1,6 +1,7 @@
 package viz
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -459,6 +460,92 @@ func TestGetNodeShape(t *testing.T) {
 	}
 }
 
+func TestCloseNodeShape(t *testing.T) {
+	g := createMermaidTestGraph()
+
+	mermaid, err := GenerateMermaid(g, MermaidOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMermaid failed: %v", err)
+	}
+
+	for _, node := range []string{
+		`api_handlers_go["handlers.go"]`,
+		`services_auth_go("auth.go")`,
+		`data_users_go{"users.go"}`,
+	} {
+		if !strings.Contains(mermaid, node) {
+			t.Errorf("Missing closed node %s in:\n%s", node, mermaid)
+		}
+	}
+}
+
+func TestGenerateMermaid_SecurityZones(t *testing.T) {
+	g := createMermaidTestGraph()
+	api := g.GetModule("api/handlers.go")
+	auth := g.GetModule("services/auth.go")
+	users := g.GetModule("services/users.go")
+	data := g.GetModule("data/users.go")
+
+	sec := &analysis.SecurityAnalysis{
+		Zones: map[analysis.SecurityZone][]*analysis.ModuleZone{
+			analysis.ZonePublic:  {{Module: api, Zone: analysis.ZonePublic}},
+			analysis.ZoneTrusted: {{Module: auth, Zone: analysis.ZoneTrusted}, {Module: users, Zone: analysis.ZoneTrusted}},
+			analysis.ZoneData:    {{Module: data, Zone: analysis.ZoneData}},
+		},
+		Violations: []*analysis.SecurityViolation{
+			{Crossing: &analysis.BoundaryCrossing{Source: auth, Destination: data}},
+		},
+	}
+
+	mermaid, err := GenerateMermaid(g, MermaidOptions{ColorBy: "security", Security: sec})
+	if err != nil {
+		t.Fatalf("GenerateMermaid failed: %v", err)
+	}
+
+	expected := []string{
+		`subgraph zone_public["Public Zone"]`,
+		`subgraph zone_trusted["Trusted Zone"]`,
+		`subgraph zone_data["Data Zone"]`,
+		`subgraph legend["Legend"]`,
+		`legend_violation["Red edge: policy violation"]`,
+		"services_auth_go ==>|violation| data_users_go",
+		"services_users_go --> data_users_go",
+		"classDef zonePublic fill:#4CAF50",
+		"class services_auth_go zoneTrusted",
+		"class legend_data zoneData",
+		"style zone_trusted fill:#2196F320,stroke:#2196F3",
+	}
+	for _, want := range expected {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Missing %q in:\n%s", want, mermaid)
+		}
+	}
+
+	// linkStyle must reference the violation edge by its declaration index
+	edgeIndex := 0
+	violationIndex := -1
+	for _, line := range strings.Split(mermaid, "\n") {
+		if strings.Contains(line, "-->") || strings.Contains(line, "==>") {
+			if strings.Contains(line, "==>") {
+				violationIndex = edgeIndex
+			}
+			edgeIndex++
+		}
+	}
+	if !strings.Contains(mermaid, fmt.Sprintf("linkStyle %d stroke:#F44336", violationIndex)) {
+		t.Errorf("Expected linkStyle for violation edge %d in:\n%s", violationIndex, mermaid)
+	}
+
+	// Without analysis results, security coloring falls back to plain output
+	plain, err := GenerateMermaid(g, MermaidOptions{ColorBy: "security"})
+	if err != nil {
+		t.Fatalf("GenerateMermaid failed: %v", err)
+	}
+	if strings.Contains(plain, "subgraph zone_") {
+		t.Error("Expected no zone subgraphs without security analysis")
+	}
+}
+
 func TestGenerateMermaid_EmptyGraph(t *testing.T) {
 	tripleStore := store.NewTripleStore()
 	g := graph.NewGraph("empty", tripleStore)
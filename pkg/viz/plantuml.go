@@ -0,0 +1,279 @@
+/*
+# Module: pkg/viz/plantuml.go
+PlantUML component and package diagram generation.
+
+Writes the dependency graph as a PlantUML component diagram, with modules
+grouped by layer or by package (directory), or as a package diagram with one
+node per package and the dependencies between packages. Edges carry their
+relationship as a stereotype (<<linksTo>>, <<imports>>, ...), are dashed
+when inferred, and show their call-site weight, so PlantUML documentation
+pipelines can consume graph output directly.
+
+## Linked Modules
+- [exchange](./exchange.go) - Node and edge collection
+- [dot](./dot.go) - Visualization options
+
+## Tags
+visualization, plantuml, uml, export
+
+## Exports
+PlantUMLDiagram, PlantUMLComponent, PlantUMLPackage, PlantUMLGroup, PlantUMLGroupLayer, PlantUMLGroupPackage, PlantUMLGroupNone, PlantUMLOptions, GeneratePlantUML, PlantUMLEscape
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#plantuml.go> a code:Module ;
+    code:name "pkg/viz/plantuml.go" ;
+    code:description "PlantUML component and package diagram generation" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./exchange.go>, <./dot.go> ;
+    code:exports <#PlantUMLDiagram>, <#PlantUMLComponent>, <#PlantUMLPackage>, <#PlantUMLGroup>, <#PlantUMLGroupLayer>,
+                 <#PlantUMLGroupPackage>, <#PlantUMLGroupNone>, <#PlantUMLOptions>, <#GeneratePlantUML>,
+                 <#PlantUMLEscape> ;
+    code:tags "visualization", "plantuml", "uml", "export" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// PlantUMLDiagram is the kind of PlantUML diagram
+type PlantUMLDiagram string
+
+const (
+	PlantUMLComponent PlantUMLDiagram = "component" // A component per module
+	PlantUMLPackage   PlantUMLDiagram = "package"   // A package per directory
+)
+
+// PlantUMLGroup is how components are grouped
+type PlantUMLGroup string
+
+const (
+	PlantUMLGroupLayer   PlantUMLGroup = "layer"   // A package per layer
+	PlantUMLGroupPackage PlantUMLGroup = "package" // A package per directory
+	PlantUMLGroupNone    PlantUMLGroup = "none"    // No grouping
+)
+
+// PlantUMLOptions configures PlantUML generation
+type PlantUMLOptions struct {
+	VizOptions
+	Diagram PlantUMLDiagram // Diagram kind (default: component)
+	GroupBy PlantUMLGroup   // Component grouping (default: layer)
+}
+
+// GeneratePlantUML generates a PlantUML diagram of the dependency graph
+func GeneratePlantUML(g *graph.Graph, opts PlantUMLOptions) (string, error) {
+	if opts.Diagram == "" {
+		opts.Diagram = PlantUMLComponent
+	}
+	if opts.GroupBy == "" {
+		opts.GroupBy = PlantUMLGroupLayer
+	}
+	switch opts.Diagram {
+	case PlantUMLComponent, PlantUMLPackage:
+	default:
+		return "", fmt.Errorf("unknown PlantUML diagram %q (use component or package)", opts.Diagram)
+	}
+	switch opts.GroupBy {
+	case PlantUMLGroupLayer, PlantUMLGroupPackage, PlantUMLGroupNone:
+	default:
+		return "", fmt.Errorf("unknown PlantUML grouping %q (use layer, package or none)", opts.GroupBy)
+	}
+
+	eg, err := collectExchangeGraph(g, opts.VizOptions)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	for _, line := range eg.Banner {
+		fmt.Fprintf(&b, "' %s\n", line)
+	}
+	if eg.Title != "" {
+		fmt.Fprintf(&b, "title %s\n", PlantUMLEscape(eg.Title))
+	}
+	if opts.Rankdir == "" || opts.Rankdir == "LR" || opts.Rankdir == "RL" {
+		b.WriteString("left to right direction\n")
+	}
+	// Directory names contain dots, which would otherwise nest packages
+	b.WriteString("set separator none\n")
+	b.WriteString("skinparam componentStyle rectangle\n\n")
+
+	if opts.Diagram == PlantUMLPackage {
+		writePlantUMLPackages(&b, eg)
+	} else {
+		writePlantUMLComponents(&b, eg, opts.GroupBy)
+	}
+
+	b.WriteString("@enduml\n")
+	return b.String(), nil
+}
+
+// writePlantUMLComponents writes a component per module, grouped in packages
+func writePlantUMLComponents(b *strings.Builder, eg *exchangeGraph, groupBy PlantUMLGroup) {
+	component := func(indent string, node exchangeNode) {
+		fmt.Fprintf(b, "%scomponent \"%s\" as %s", indent, PlantUMLEscape(node.Label), node.ID)
+		if node.Language != "" {
+			fmt.Fprintf(b, " <<%s>>", PlantUMLEscape(node.Language))
+		}
+		fmt.Fprintf(b, " %s\n", node.Color)
+	}
+
+	if groupBy == PlantUMLGroupNone {
+		for _, node := range eg.Nodes {
+			component("", node)
+		}
+	} else {
+		groups := make(map[string][]exchangeNode)
+		for _, node := range eg.Nodes {
+			key := plantUMLGroupKey(node, groupBy)
+			groups[key] = append(groups[key], node)
+		}
+		keys := make([]string, 0, len(groups))
+		for key := range groups {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		stereotype := "layer"
+		if groupBy == PlantUMLGroupPackage {
+			stereotype = "package"
+		}
+		for i, key := range keys {
+			fmt.Fprintf(b, "package \"%s\" as g%d <<%s>> {\n", PlantUMLEscape(key), i, stereotype)
+			for _, node := range groups[key] {
+				component("  ", node)
+			}
+			b.WriteString("}\n")
+		}
+	}
+
+	b.WriteString("\n")
+	for _, edge := range eg.Edges {
+		fmt.Fprintf(b, "%s %s %s : %s\n", edge.Source, plantUMLArrow(edge.Inferred), edge.Target,
+			plantUMLEdgeLabel([]string{edge.Relation}, edge.Weight))
+	}
+}
+
+// writePlantUMLPackages writes a package per directory, with an edge for
+// the dependencies between two packages
+func writePlantUMLPackages(b *strings.Builder, eg *exchangeGraph) {
+	type packageEdge struct {
+		relations map[string]bool
+		weight    int
+		inferred  bool // Every dependency was inferred
+	}
+
+	packageOf := make(map[string]string, len(eg.Nodes))
+	modules := make(map[string]int)
+	for _, node := range eg.Nodes {
+		dir := plantUMLGroupKey(node, PlantUMLGroupPackage)
+		packageOf[node.ID] = dir
+		modules[dir]++
+	}
+	dirs := make([]string, 0, len(modules))
+	for dir := range modules {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	ids := make(map[string]string, len(dirs))
+	for i, dir := range dirs {
+		ids[dir] = fmt.Sprintf("p%d", i)
+		count := fmt.Sprintf("%d modules", modules[dir])
+		if modules[dir] == 1 {
+			count = "1 module"
+		}
+		fmt.Fprintf(b, "package \"%s\\n%s\" as %s {\n}\n", PlantUMLEscape(dir), count, ids[dir])
+	}
+
+	edges := make(map[[2]string]*packageEdge)
+	for _, edge := range eg.Edges {
+		from, to := packageOf[edge.Source], packageOf[edge.Target]
+		if from == to {
+			continue
+		}
+		key := [2]string{from, to}
+		pe, ok := edges[key]
+		if !ok {
+			pe = &packageEdge{relations: make(map[string]bool), inferred: true}
+			edges[key] = pe
+		}
+		pe.relations[edge.Relation] = true
+		pe.weight += edge.Weight
+		pe.inferred = pe.inferred && edge.Inferred
+	}
+	keys := make([][2]string, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	b.WriteString("\n")
+	for _, key := range keys {
+		pe := edges[key]
+		relations := make([]string, 0, len(pe.relations))
+		for relation := range pe.relations {
+			relations = append(relations, relation)
+		}
+		sort.Strings(relations)
+		fmt.Fprintf(b, "%s %s %s : %s\n", ids[key[0]], plantUMLArrow(pe.inferred), ids[key[1]],
+			plantUMLEdgeLabel(relations, pe.weight))
+	}
+}
+
+// plantUMLGroupKey returns the group a node belongs to
+func plantUMLGroupKey(node exchangeNode, groupBy PlantUMLGroup) string {
+	if groupBy == PlantUMLGroupPackage {
+		return path.Dir(node.Path)
+	}
+	if node.Layer == "" {
+		return "unknown"
+	}
+	return node.Layer
+}
+
+// plantUMLArrow returns a solid arrow, or a dashed one for inferred edges
+func plantUMLArrow(inferred bool) string {
+	if inferred {
+		return "..>"
+	}
+	return "-->"
+}
+
+// plantUMLEdgeLabel returns relationship stereotypes and, above one, the
+// call-site weight
+func plantUMLEdgeLabel(relations []string, weight int) string {
+	parts := make([]string, 0, len(relations)+1)
+	for _, relation := range relations {
+		if relation == "" {
+			relation = graph.RelationLinksTo
+		}
+		parts = append(parts, "<<"+relation+">>")
+	}
+	if weight > 1 {
+		parts = append(parts, fmt.Sprintf("(%d)", weight))
+	}
+	return strings.Join(parts, " ")
+}
+
+// plantUMLEscape makes a string safe inside a quoted PlantUML name
+func PlantUMLEscape(s string) string {
+	s = strings.ReplaceAll(s, `"`, `'`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
@@ -0,0 +1,75 @@
+package viz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+func TestGeneratePlantUML_Components(t *testing.T) {
+	g := createTestGraph()
+	g.Modules["api/handlers.go"].AddEdge(graph.Edge{Target: "services/auth.go", Relation: graph.RelationImports, Weight: 4, Inferred: true})
+
+	out, err := GeneratePlantUML(g, PlantUMLOptions{VizOptions: VizOptions{Title: "Test", ColorBy: "layer"}})
+	if err != nil {
+		t.Fatalf("GeneratePlantUML failed: %v", err)
+	}
+	for _, want := range []string{
+		"@startuml\n",
+		"title Test\n",
+		`package "api" as g0 <<layer>> {`,
+		`component "handlers.go" as n0 <<go>> #4CAF50`,
+		"n0 ..> n2 : <<imports>> (4)\n",
+		"n0 --> n3 : <<linksTo>>\n",
+		"@enduml\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = GeneratePlantUML(g, PlantUMLOptions{GroupBy: PlantUMLGroupPackage})
+	if err != nil {
+		t.Fatalf("GeneratePlantUML(package grouping) failed: %v", err)
+	}
+	if !strings.Contains(out, `package "services" as g2 <<package>> {`) {
+		t.Errorf("Expected directory packages:\n%s", out)
+	}
+
+	out, err = GeneratePlantUML(g, PlantUMLOptions{GroupBy: PlantUMLGroupNone, VizOptions: VizOptions{Rankdir: "TB"}})
+	if err != nil {
+		t.Fatalf("GeneratePlantUML(no grouping) failed: %v", err)
+	}
+	if strings.Contains(out, "package ") || strings.Contains(out, "left to right direction") {
+		t.Errorf("Expected ungrouped top-down output:\n%s", out)
+	}
+}
+
+func TestGeneratePlantUML_Packages(t *testing.T) {
+	g := createTestGraph()
+	out, err := GeneratePlantUML(g, PlantUMLOptions{Diagram: PlantUMLPackage})
+	if err != nil {
+		t.Fatalf("GeneratePlantUML failed: %v", err)
+	}
+	for _, want := range []string{
+		`package "api\n1 module" as p0 {`,
+		`package "services\n2 modules" as p2 {`,
+		"p0 --> p2 : <<linksTo>> (2)\n",
+		"p2 --> p1 : <<linksTo>> (2)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "-->") != 2 {
+		t.Errorf("Expected dependencies merged per package pair:\n%s", out)
+	}
+
+	if _, err := GeneratePlantUML(g, PlantUMLOptions{Diagram: "class"}); err == nil {
+		t.Error("Expected an error for an unknown diagram")
+	}
+	if _, err := GeneratePlantUML(g, PlantUMLOptions{VizOptions: VizOptions{Type: VizSecurity}}); err == nil {
+		t.Error("Expected an error for a non-dependency visualization")
+	}
+}
@@ -0,0 +1,346 @@
+/*
+# Module: pkg/viz/sampling.go
+Graph sampling and truncation for large visualizations.
+
+Reduces graphs above a size threshold to a renderable subset before DOT or
+Mermaid generation. Strategies keep the top-N modules by degree centrality,
+ego networks around entry points, or collapse modules into one node per
+layer. The result records what was omitted so generators can emit a
+warning banner.
+
+## Linked Modules
+- [dot](./dot.go) - DOT generation
+- [mermaid](./mermaid.go) - Mermaid generation
+- [../graph](../graph/graph.go) - Graph data structure
+
+## Tags
+visualization, sampling, large-graphs
+
+## Exports
+SamplingStrategy, SamplingOptions, SampleResult, SampleGraph, DefaultSampleThreshold, DefaultSampleMaxNodes
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#sampling.go> a code:Module ;
+    code:name "pkg/viz/sampling.go" ;
+    code:description "Graph sampling and truncation for large visualizations" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./dot.go>, <./mermaid.go>, <../graph/graph.go> ;
+    code:exports <#SamplingStrategy>, <#SamplingOptions>, <#SampleResult>, <#SampleGraph>,
+                 <#DefaultSampleThreshold>, <#DefaultSampleMaxNodes> ;
+    code:tags "visualization", "sampling", "large-graphs" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+// SamplingStrategy selects how large graphs are reduced
+type SamplingStrategy string
+
+const (
+	SampleTopN           SamplingStrategy = "top-n" // Top-N modules by degree centrality
+	SampleEgo            SamplingStrategy = "ego"   // Ego networks around entry points
+	SampleLayerCollapsed SamplingStrategy = "layer" // One node per layer
+)
+
+const (
+	// DefaultSampleThreshold is the module count above which sampling applies
+	DefaultSampleThreshold = 5000
+
+	// DefaultSampleMaxNodes is the default number of modules kept by sampling
+	DefaultSampleMaxNodes = 500
+
+	// defaultEgoDepth is the default ego network radius
+	defaultEgoDepth = 2
+
+	// maxBannerLayers bounds the omitted layers listed in the banner
+	maxBannerLayers = 10
+)
+
+// SamplingOptions configures graph sampling
+type SamplingOptions struct {
+	Strategy    SamplingStrategy // Sampling strategy
+	Threshold   int              // Apply only above this many modules (default: 5000)
+	MaxNodes    int              // Maximum modules kept (default: 500)
+	EntryPoints []string         // Entry point paths for ego sampling
+	EgoDepth    int              // Ego network radius (default: 2)
+}
+
+// SampleResult describes a sampled graph and what was omitted
+type SampleResult struct {
+	Graph          *graph.Graph     // Sampled graph (the original if not applied)
+	Applied        bool             // Whether sampling was applied
+	Strategy       SamplingStrategy // Strategy used
+	TotalModules   int              // Modules in the original graph
+	KeptModules    int              // Modules represented in the sampled graph
+	OmittedModules int              // Modules not shown
+	OmittedByLayer map[string]int   // Omitted modules grouped by layer
+}
+
+// SampleGraph reduces the graph according to the options. Graphs at or
+// below the threshold are returned unchanged.
+func SampleGraph(g *graph.Graph, opts SamplingOptions) (*SampleResult, error) {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultSampleThreshold
+	}
+	if opts.MaxNodes <= 0 {
+		opts.MaxNodes = DefaultSampleMaxNodes
+	}
+	if opts.EgoDepth <= 0 {
+		opts.EgoDepth = defaultEgoDepth
+	}
+
+	result := &SampleResult{
+		Graph:          g,
+		Strategy:       opts.Strategy,
+		TotalModules:   len(g.Modules),
+		KeptModules:    len(g.Modules),
+		OmittedByLayer: make(map[string]int),
+	}
+
+	if opts.Strategy == "" || len(g.Modules) <= opts.Threshold {
+		return result, nil
+	}
+
+	var kept map[string]bool
+	switch opts.Strategy {
+	case SampleTopN:
+		kept = topNModules(g, opts.MaxNodes)
+	case SampleEgo:
+		if len(opts.EntryPoints) == 0 {
+			return nil, fmt.Errorf("ego sampling requires at least one entry point")
+		}
+		var err error
+		kept, err = egoModules(g, opts.EntryPoints, opts.EgoDepth, opts.MaxNodes)
+		if err != nil {
+			return nil, err
+		}
+	case SampleLayerCollapsed:
+		result.Graph = collapseLayers(g)
+		result.Applied = true
+		result.OmittedModules = len(g.Modules)
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown sampling strategy: %s (use: top-n, ego, layer)", opts.Strategy)
+	}
+
+	result.Graph = subgraph(g, kept)
+	result.Applied = true
+	result.KeptModules = len(kept)
+	result.OmittedModules = len(g.Modules) - len(kept)
+	for path, module := range g.Modules {
+		if !kept[path] {
+			result.OmittedByLayer[layerName(module)]++
+		}
+	}
+
+	return result, nil
+}
+
+// Banner returns warning lines describing what sampling omitted
+func (r *SampleResult) Banner() []string {
+	if r == nil || !r.Applied {
+		return nil
+	}
+
+	if r.Strategy == SampleLayerCollapsed {
+		return []string{
+			fmt.Sprintf("WARNING: graph has %d modules; collapsed into %d layer nodes", r.TotalModules, len(r.Graph.Modules)),
+			"Individual modules are omitted; edges show dependencies between layers",
+		}
+	}
+
+	lines := []string{
+		fmt.Sprintf("WARNING: graph has %d modules; showing %d (%s sampling), %d omitted",
+			r.TotalModules, r.KeptModules, r.Strategy, r.OmittedModules),
+	}
+
+	layers := make([]string, 0, len(r.OmittedByLayer))
+	for layer := range r.OmittedByLayer {
+		layers = append(layers, layer)
+	}
+	sort.Slice(layers, func(i, j int) bool {
+		if r.OmittedByLayer[layers[i]] != r.OmittedByLayer[layers[j]] {
+			return r.OmittedByLayer[layers[i]] > r.OmittedByLayer[layers[j]]
+		}
+		return layers[i] < layers[j]
+	})
+
+	parts := make([]string, 0, maxBannerLayers+1)
+	for i, layer := range layers {
+		if i == maxBannerLayers {
+			parts = append(parts, fmt.Sprintf("%d more layers", len(layers)-maxBannerLayers))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s: %d", layer, r.OmittedByLayer[layer]))
+	}
+	if len(parts) > 0 {
+		lines = append(lines, "Omitted by layer: "+strings.Join(parts, ", "))
+	}
+
+	return lines
+}
+
+// topNModules keeps the modules with the highest degree centrality
+func topNModules(g *graph.Graph, n int) map[string]bool {
+	modules := make([]*graph.Module, 0, len(g.Modules))
+	for _, module := range g.Modules {
+		modules = append(modules, module)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		di := len(modules[i].Dependents) + len(modules[i].Dependencies)
+		dj := len(modules[j].Dependents) + len(modules[j].Dependencies)
+		if di != dj {
+			return di > dj
+		}
+		return modules[i].Path < modules[j].Path
+	})
+
+	if len(modules) > n {
+		modules = modules[:n]
+	}
+
+	kept := make(map[string]bool, len(modules))
+	for _, module := range modules {
+		kept[module.Path] = true
+	}
+	return kept
+}
+
+// egoModules keeps modules within depth hops of the entry points, in either
+// direction, stopping once maxNodes is reached
+func egoModules(g *graph.Graph, entryPoints []string, depth, maxNodes int) (map[string]bool, error) {
+	pathsByURI := make(map[string]string, len(g.Modules))
+	for _, module := range g.Modules {
+		pathsByURI[module.URI] = module.Path
+	}
+
+	kept := make(map[string]bool)
+	var frontier []string
+	for _, entry := range entryPoints {
+		if g.GetModule(entry) == nil {
+			return nil, fmt.Errorf("entry point not found: %s", entry)
+		}
+		if !kept[entry] {
+			kept[entry] = true
+			frontier = append(frontier, entry)
+		}
+	}
+
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, path := range frontier {
+			module := g.GetModule(path)
+			neighbors := append([]string{}, module.Dependencies...)
+			for _, uri := range module.Dependents {
+				if depPath, ok := pathsByURI[uri]; ok {
+					neighbors = append(neighbors, depPath)
+				}
+			}
+			sort.Strings(neighbors)
+
+			for _, neighbor := range neighbors {
+				if len(kept) >= maxNodes {
+					return kept, nil
+				}
+				if kept[neighbor] || g.GetModule(neighbor) == nil {
+					continue
+				}
+				kept[neighbor] = true
+				next = append(next, neighbor)
+			}
+		}
+		frontier = next
+	}
+
+	return kept, nil
+}
+
+// subgraph copies the kept modules into a new graph, dropping edges to
+// omitted modules
+func subgraph(g *graph.Graph, kept map[string]bool) *graph.Graph {
+	keptURIs := make(map[string]bool, len(kept))
+	for path := range kept {
+		keptURIs[g.GetModule(path).URI] = true
+	}
+
+	sampled := graph.NewGraph(g.Root, g.Store)
+	for path := range kept {
+		module := *g.GetModule(path)
+
+		module.Dependencies = nil
+		for _, dep := range g.GetModule(path).Dependencies {
+			if kept[dep] {
+				module.Dependencies = append(module.Dependencies, dep)
+			}
+		}
+
+		module.Dependents = nil
+		for _, dep := range g.GetModule(path).Dependents {
+			if keptURIs[dep] {
+				module.Dependents = append(module.Dependents, dep)
+			}
+		}
+
+		sampled.AddModule(&module)
+	}
+
+	return sampled
+}
+
+// collapseLayers builds a graph with one module per layer and edges for
+// dependencies that cross layers
+func collapseLayers(g *graph.Graph) *graph.Graph {
+	counts := make(map[string]int)
+	deps := make(map[string]map[string]bool)
+
+	for _, module := range g.Modules {
+		layer := layerName(module)
+		counts[layer]++
+		if deps[layer] == nil {
+			deps[layer] = make(map[string]bool)
+		}
+		for _, depPath := range module.Dependencies {
+			if depModule := g.GetModule(depPath); depModule != nil {
+				if depLayer := layerName(depModule); depLayer != layer {
+					deps[layer][depLayer] = true
+				}
+			}
+		}
+	}
+
+	collapsed := graph.NewGraph(g.Root, g.Store)
+	for layer, count := range counts {
+		module := graph.NewModule("layer:"+layer, "<#layer:"+layer+">")
+		module.Name = layer
+		module.Layer = layer
+		module.Description = fmt.Sprintf("%d modules", count)
+		for depLayer := range deps[layer] {
+			module.AddDependency("layer:" + depLayer)
+		}
+		sort.Strings(module.Dependencies)
+		collapsed.AddModule(module)
+	}
+
+	return collapsed
+}
+
+// layerName returns the module layer, or "unknown" if unset
+func layerName(module *graph.Module) string {
+	if module.Layer == "" {
+		return "unknown"
+	}
+	return module.Layer
+}
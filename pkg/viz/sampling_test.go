@@ -0,0 +1,116 @@
+package viz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSampleGraph_BelowThreshold(t *testing.T) {
+	g := createTestGraph()
+
+	result, err := SampleGraph(g, SamplingOptions{Strategy: SampleTopN})
+	if err != nil {
+		t.Fatalf("SampleGraph failed: %v", err)
+	}
+	if result.Applied {
+		t.Error("Expected sampling not to apply below threshold")
+	}
+	if result.Graph != g {
+		t.Error("Expected original graph to be returned")
+	}
+	if result.Banner() != nil {
+		t.Error("Expected no banner when sampling is not applied")
+	}
+}
+
+func TestSampleGraph_TopN(t *testing.T) {
+	g := createTestGraph()
+
+	result, err := SampleGraph(g, SamplingOptions{Strategy: SampleTopN, Threshold: 1, MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("SampleGraph failed: %v", err)
+	}
+	if !result.Applied || len(result.Graph.Modules) != 2 || result.OmittedModules != 2 {
+		t.Fatalf("Expected 2 kept and 2 omitted modules, got %d kept, %d omitted",
+			len(result.Graph.Modules), result.OmittedModules)
+	}
+	if result.Graph.GetModule("api/handlers.go") == nil {
+		t.Error("Expected most connected module to be kept")
+	}
+
+	// Edges to omitted modules are dropped
+	for _, module := range result.Graph.Modules {
+		for _, dep := range module.Dependencies {
+			if result.Graph.GetModule(dep) == nil {
+				t.Errorf("Dangling edge %s -> %s", module.Path, dep)
+			}
+		}
+	}
+
+	banner := result.Banner()
+	if len(banner) != 2 || !strings.Contains(banner[1], "Omitted by layer") {
+		t.Errorf("Unexpected banner: %v", banner)
+	}
+}
+
+func TestSampleGraph_Ego(t *testing.T) {
+	g := createTestGraph()
+
+	result, err := SampleGraph(g, SamplingOptions{
+		Strategy:    SampleEgo,
+		Threshold:   1,
+		EntryPoints: []string{"services/auth.go"},
+		EgoDepth:    1,
+	})
+	if err != nil {
+		t.Fatalf("SampleGraph failed: %v", err)
+	}
+	if result.Graph.GetModule("data/users.go") == nil {
+		t.Error("Expected direct dependency in ego network")
+	}
+	if result.Graph.GetModule("services/users.go") != nil {
+		t.Error("Expected sibling service to be omitted at depth 1")
+	}
+
+	if _, err := SampleGraph(g, SamplingOptions{Strategy: SampleEgo, Threshold: 1}); err == nil {
+		t.Error("Expected error for ego sampling without entry points")
+	}
+}
+
+func TestSampleGraph_LayerCollapsed(t *testing.T) {
+	g := createTestGraph()
+
+	result, err := SampleGraph(g, SamplingOptions{Strategy: SampleLayerCollapsed, Threshold: 1})
+	if err != nil {
+		t.Fatalf("SampleGraph failed: %v", err)
+	}
+	if len(result.Graph.Modules) != 3 {
+		t.Fatalf("Expected 3 layer nodes, got %d", len(result.Graph.Modules))
+	}
+
+	api := result.Graph.GetModule("layer:api")
+	if api == nil || len(api.Dependencies) != 1 || api.Dependencies[0] != "layer:service" {
+		t.Errorf("Unexpected api layer node: %+v", api)
+	}
+}
+
+func TestGenerate_SamplingBanner(t *testing.T) {
+	g := createTestGraph()
+	sampling := &SamplingOptions{Strategy: SampleTopN, Threshold: 1, MaxNodes: 2}
+
+	dot, err := GenerateDOT(g, VizOptions{Type: VizDependency, Sampling: sampling})
+	if err != nil {
+		t.Fatalf("GenerateDOT failed: %v", err)
+	}
+	if !strings.Contains(dot, "// WARNING: graph has 4 modules") {
+		t.Error("Missing sampling banner in DOT output")
+	}
+
+	mermaid, err := GenerateMermaid(g, MermaidOptions{Sampling: sampling})
+	if err != nil {
+		t.Fatalf("GenerateMermaid failed: %v", err)
+	}
+	if !strings.HasPrefix(mermaid, "flowchart TD\n    %% WARNING") {
+		t.Errorf("Missing sampling banner in Mermaid output:\n%s", mermaid)
+	}
+}
@@ -0,0 +1,326 @@
+/*
+# Module: pkg/viz/svg.go
+SVG and PNG rendering of built-in layouts.
+
+Draws a Layout as SVG markup or as a PNG image with a small rasterizer and a
+built-in bitmap font, so graphs can be rendered on machines without
+GraphViz. Edge styling follows the DOT output: thicker for more call sites,
+dashed when inferred, labelled with relations other than linksTo.
+
+## Linked Modules
+- [layout](./layout.go) - Built-in layered layout
+- [font](./font.go) - Bitmap font for PNG labels
+
+## Tags
+visualization, svg, png, rendering
+
+## Exports
+RenderSVG, RenderPNG
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#svg.go> a code:Module ;
+    code:name "pkg/viz/svg.go" ;
+    code:description "SVG and PNG rendering of built-in layouts" ;
+    code:language "go" ;
+    code:layer "visualization" ;
+    code:linksTo <./layout.go>, <./font.go> ;
+    code:exports <#RenderSVG>, <#RenderPNG> ;
+    code:tags "visualization", "svg", "png", "rendering" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package viz
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+)
+
+const (
+	edgeColor   = "#555555" // Edge and node outline color
+	textColor   = "#222222" // Label color
+	arrowLength = 9.0       // Length of arrowheads
+	arrowWidth  = 4.0       // Half width of arrowheads
+
+	// pngScale renders PNG images at twice the layout size for sharper lines
+	pngScale = 2
+)
+
+// edgeWidth returns the stroke width of an edge, growing with its call sites
+func edgeWidth(edge graph.Edge) float64 {
+	if edge.Weight > 1 {
+		return math.Min(1+math.Log2(float64(edge.Weight)), 6)
+	}
+	return 1
+}
+
+// edgeLabel returns the label drawn on an edge, if any
+func edgeLabel(edge graph.Edge) string {
+	if edge.Relation != "" && edge.Relation != graph.RelationLinksTo {
+		return edge.Relation
+	}
+	return ""
+}
+
+// bezier returns the point at t on a cubic Bézier curve
+func bezier(c [4]Point, t float64) Point {
+	u := 1 - t
+	a, b, d, e := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+	return Point{
+		a*c[0].X + b*c[1].X + d*c[2].X + e*c[3].X,
+		a*c[0].Y + b*c[1].Y + d*c[2].Y + e*c[3].Y,
+	}
+}
+
+// segments splits a curve into its cubic Bézier segments
+func segments(curve []Point) [][4]Point {
+	var result [][4]Point
+	for i := 3; i < len(curve); i += 3 {
+		result = append(result, [4]Point{curve[i-3], curve[i-2], curve[i-1], curve[i]})
+	}
+	return result
+}
+
+// midpoint returns a point halfway along a curve
+func midpoint(curve []Point) Point {
+	segs := segments(curve)
+	if len(segs)%2 == 0 {
+		return segs[len(segs)/2][0]
+	}
+	return bezier(segs[len(segs)/2], 0.5)
+}
+
+// arrowhead returns the tip and base corners of the arrow ending a curve
+func arrowhead(c [4]Point) [3]Point {
+	tip := c[3]
+	from := bezier(c, 0.95)
+	dx, dy := tip.X-from.X, tip.Y-from.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		dx, dy, length = 1, 0, 1
+	}
+	dx, dy = dx/length, dy/length
+	base := Point{tip.X - dx*arrowLength, tip.Y - dy*arrowLength}
+	return [3]Point{
+		tip,
+		{base.X - dy*arrowWidth, base.Y + dx*arrowWidth},
+		{base.X + dy*arrowWidth, base.Y - dx*arrowWidth},
+	}
+}
+
+// RenderSVG draws a layout as an SVG document
+func RenderSVG(layout *Layout) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Arial, Helvetica, sans-serif" font-size="%.0f">
+<rect width="100%%" height="100%%" fill="#ffffff"/>
+`, layout.Width, layout.Height, layout.Width, layout.Height, layoutFontSize)
+
+	for i, line := range layout.Title {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="%.0f" fill="%s">%s</text>
+`, layout.Width/2, layoutMargin+float64(i+1)*layoutLineHeight*1.3-4, layoutFontSize+2, textColor, html.EscapeString(line))
+	}
+
+	b.WriteString("<g class=\"edges\">\n")
+	for _, edge := range layout.Edges {
+		segs := segments(edge.Curve)
+		if len(segs) == 0 {
+			continue
+		}
+		dash := ""
+		if edge.Edge.Inferred {
+			dash = ` stroke-dasharray="5,3"`
+		}
+		fmt.Fprintf(&b, `<path d="M%.1f,%.1f`, segs[0][0].X, segs[0][0].Y)
+		for _, c := range segs {
+			fmt.Fprintf(&b, ` C%.1f,%.1f %.1f,%.1f %.1f,%.1f`, c[1].X, c[1].Y, c[2].X, c[2].Y, c[3].X, c[3].Y)
+		}
+		fmt.Fprintf(&b, `" fill="none" stroke="%s" stroke-width="%.1f"%s><title>%s → %s</title></path>
+`, edgeColor, edgeWidth(edge.Edge), dash, html.EscapeString(edge.From.Module.Path), html.EscapeString(edge.To.Module.Path))
+		a := arrowhead(segs[len(segs)-1])
+		fmt.Fprintf(&b, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s"/>
+`, a[0].X, a[0].Y, a[1].X, a[1].Y, a[2].X, a[2].Y, edgeColor)
+		if label := edgeLabel(edge.Edge); label != "" {
+			mid := midpoint(edge.Curve)
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="10" fill="%s">%s</text>
+`, mid.X, mid.Y-3, textColor, html.EscapeString(label))
+		}
+	}
+	b.WriteString("</g>\n<g class=\"nodes\">\n")
+
+	for _, node := range layout.Nodes {
+		fmt.Fprintf(&b, `<g><title>%s</title><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="3" fill="%s" stroke="%s"/>
+`, html.EscapeString(node.Module.Path), node.X, node.Y, node.Width, node.Height, node.Color, edgeColor)
+		center := node.Center()
+		top := center.Y - float64(len(node.Label))*layoutLineHeight/2
+		for i, line := range node.Label {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" fill="%s">%s</text>
+`, center.X, top+float64(i+1)*layoutLineHeight-3, textColor, html.EscapeString(line))
+		}
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.String()
+}
+
+// RenderPNG draws a layout as a PNG image
+func RenderPNG(layout *Layout, w io.Writer) error {
+	r := newRaster(int(math.Ceil(layout.Width)), int(math.Ceil(layout.Height)), pngScale)
+	edge, text := parseColor(edgeColor), parseColor(textColor)
+
+	for i, line := range layout.Title {
+		r.text(line, layout.Width/2, layoutMargin+float64(i)*layoutLineHeight*1.3+layoutLineHeight/2, text)
+	}
+
+	for _, e := range layout.Edges {
+		segs := segments(e.Curve)
+		if len(segs) == 0 {
+			continue
+		}
+		width := edgeWidth(e.Edge)
+		const steps = 24
+		for _, c := range segs {
+			previous := c[0]
+			for i := 1; i <= steps; i++ {
+				next := bezier(c, float64(i)/steps)
+				// Inferred edges are dashed by skipping every other step
+				if !e.Edge.Inferred || i%2 == 1 {
+					r.line(previous, next, width, edge)
+				}
+				previous = next
+			}
+		}
+		r.triangle(arrowhead(segs[len(segs)-1]), edge)
+		if label := edgeLabel(e.Edge); label != "" {
+			mid := midpoint(e.Curve)
+			r.text(label, mid.X, mid.Y-8, text)
+		}
+	}
+
+	for _, node := range layout.Nodes {
+		r.fillRect(node.X, node.Y, node.Width, node.Height, parseColor(node.Color))
+		r.strokeRect(node.X, node.Y, node.Width, node.Height, edge)
+		center := node.Center()
+		top := center.Y - float64(len(node.Label))*layoutLineHeight/2
+		for i, line := range node.Label {
+			r.text(line, center.X, top+(float64(i)+0.5)*layoutLineHeight, text)
+		}
+	}
+
+	return png.Encode(w, r.img)
+}
+
+// parseColor parses a #RRGGBB color, defaulting to gray
+func parseColor(hex string) color.RGBA {
+	if len(hex) == 7 && hex[0] == '#' {
+		if v, err := strconv.ParseUint(hex[1:], 16, 32); err == nil {
+			return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+		}
+	}
+	return color.RGBA{0x90, 0x90, 0x90, 0xff}
+}
+
+// raster draws shapes in layout coordinates onto an image
+type raster struct {
+	img   *image.RGBA
+	scale float64
+}
+
+func newRaster(width, height, scale int) *raster {
+	img := image.NewRGBA(image.Rect(0, 0, width*scale, height*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	return &raster{img: img, scale: float64(scale)}
+}
+
+// fillPixels fills the image rectangle [x0,x1)x[y0,y1)
+func (r *raster) fillPixels(x0, y0, x1, y1 int, c color.RGBA) {
+	bounds := r.img.Bounds()
+	x0, y0 = max(x0, bounds.Min.X), max(y0, bounds.Min.Y)
+	x1, y1 = min(x1, bounds.Max.X), min(y1, bounds.Max.Y)
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			r.img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func (r *raster) fillRect(x, y, width, height float64, c color.RGBA) {
+	s := r.scale
+	r.fillPixels(int(x*s), int(y*s), int((x+width)*s), int((y+height)*s), c)
+}
+
+func (r *raster) strokeRect(x, y, width, height float64, c color.RGBA) {
+	corners := []Point{{x, y}, {x + width, y}, {x + width, y + height}, {x, y + height}, {x, y}}
+	for i := 1; i < len(corners); i++ {
+		r.line(corners[i-1], corners[i], 1, c)
+	}
+}
+
+// line draws a line of the given width by stamping squares along it
+func (r *raster) line(from, to Point, width float64, c color.RGBA) {
+	s := r.scale
+	size := max(int(math.Round(width*s)), 1)
+	steps := int(math.Max(math.Abs(to.X-from.X), math.Abs(to.Y-from.Y))*s) + 1
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int((from.X+(to.X-from.X)*t)*s) - size/2
+		y := int((from.Y+(to.Y-from.Y)*t)*s) - size/2
+		r.fillPixels(x, y, x+size, y+size, c)
+	}
+}
+
+// triangle fills a triangle
+func (r *raster) triangle(p [3]Point, c color.RGBA) {
+	s := r.scale
+	minX := int(math.Min(p[0].X, math.Min(p[1].X, p[2].X)) * s)
+	maxX := int(math.Max(p[0].X, math.Max(p[1].X, p[2].X))*s) + 1
+	minY := int(math.Min(p[0].Y, math.Min(p[1].Y, p[2].Y)) * s)
+	maxY := int(math.Max(p[0].Y, math.Max(p[1].Y, p[2].Y))*s) + 1
+	side := func(a, b Point, x, y float64) float64 {
+		return (b.X-a.X)*(y-a.Y) - (b.Y-a.Y)*(x-a.X)
+	}
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			px, py := (float64(x)+0.5)/s, (float64(y)+0.5)/s
+			d0, d1, d2 := side(p[0], p[1], px, py), side(p[1], p[2], px, py), side(p[2], p[0], px, py)
+			if (d0 >= 0 && d1 >= 0 && d2 >= 0) || (d0 <= 0 && d1 <= 0 && d2 <= 0) {
+				r.fillPixels(x, y, x+1, y+1, c)
+			}
+		}
+	}
+}
+
+// text draws a line of text centered on (x, y) with the bitmap font
+func (r *raster) text(s string, x, y float64, c color.RGBA) {
+	scale := int(r.scale)
+	runes := []rune(s)
+	width := (len(runes)*glyphAdvance - 1) * scale
+	left := int(x*r.scale) - width/2
+	top := int(y*r.scale) - glyphHeight*scale/2
+	for i, ch := range runes {
+		rows := glyph(ch)
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if rows[row]&(1<<(glyphWidth-1-col)) != 0 {
+					px := left + (i*glyphAdvance+col)*scale
+					py := top + row*scale
+					r.fillPixels(px, py, px+scale, py+scale, c)
+				}
+			}
+		}
+	}
+}
@@ -113,6 +113,73 @@ func TestGenerateDOT_Dependency(t *testing.T) {
 	}
 }
 
+func TestGenerateDOT_EdgeMetadata(t *testing.T) {
+	g := createTestGraph()
+	api := g.Modules["api/handlers.go"]
+	api.AddEdge(graph.Edge{Target: "services/auth.go", Weight: 8})
+	api.AddEdge(graph.Edge{Target: "services/users.go", Relation: graph.RelationImports, Weight: 1, Inferred: true})
+
+	dot, err := GenerateDOT(g, VizOptions{Type: VizDependency})
+	if err != nil {
+		t.Fatalf("GenerateDOT failed: %v", err)
+	}
+
+	for _, want := range []string{
+		`"api/handlers.go" -> "services/auth.go" [penwidth=4.0, tooltip="8 call sites"];`,
+		`"api/handlers.go" -> "services/users.go" [style=dashed, label="imports"];`,
+		`"services/auth.go" -> "data/users.go";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Missing edge %s in:\n%s", want, dot)
+		}
+	}
+}
+
+func TestGenerateDOT_EdgeFilter(t *testing.T) {
+	g := createTestGraph()
+	api := g.Modules["api/handlers.go"]
+	api.AddEdge(graph.Edge{Target: "services/users.go", Relation: graph.RelationImports, Inferred: true})
+
+	tests := []struct {
+		name    string
+		filter  FilterOptions
+		keep    []string
+		dropped []string
+	}{
+		{
+			name:    "min confidence",
+			filter:  FilterOptions{MinConfidence: 0.9},
+			keep:    []string{`"api/handlers.go" -> "services/auth.go"`, `"services/auth.go" -> "data/users.go"`},
+			dropped: []string{`"api/handlers.go" -> "services/users.go"`},
+		},
+		{
+			name:    "relation",
+			filter:  FilterOptions{Relations: []string{"Imports"}},
+			keep:    []string{`"api/handlers.go" -> "services/users.go"`},
+			dropped: []string{`"api/handlers.go" -> "services/auth.go"`, `"services/auth.go" -> "data/users.go"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			dot, err := GenerateDOT(g, VizOptions{Type: VizDependency, Filter: &filter})
+			if err != nil {
+				t.Fatalf("GenerateDOT failed: %v", err)
+			}
+			for _, want := range tt.keep {
+				if !strings.Contains(dot, want) {
+					t.Errorf("Missing edge %s in:\n%s", want, dot)
+				}
+			}
+			for _, unwanted := range tt.dropped {
+				if strings.Contains(dot, unwanted) {
+					t.Errorf("Edge %s should be filtered out:\n%s", unwanted, dot)
+				}
+			}
+		})
+	}
+}
+
 func TestGenerateDOT_Layer(t *testing.T) {
 	g := createTestGraph()
 	opts := VizOptions{
@@ -140,6 +207,35 @@ func TestGenerateDOT_Layer(t *testing.T) {
 	}
 }
 
+func TestGenerateDOT_Component(t *testing.T) {
+	g := createTestGraph()
+	components := analysis.AnalyzeComponents(g, &analysis.ComponentConfig{Components: []analysis.ComponentSpec{
+		{Name: "web", Paths: []string{"api"}},
+		{Name: "core", Paths: []string{"services", "data"}, API: []string{"services/auth.go"}},
+	}})
+
+	dot, err := GenerateDOT(g, VizOptions{Type: VizComponent, Components: components})
+	if err != nil {
+		t.Fatalf("GenerateDOT failed: %v", err)
+	}
+
+	if !strings.Contains(dot, `"component:core" [fillcolor="#E3F2FD", shape=component, label="core\n3 modules"]`) {
+		t.Errorf("Missing core component node:\n%s", dot)
+	}
+	// handlers.go uses services/users.go, outside the core API
+	if !strings.Contains(dot, `"component:web" -> "component:core" [label="2 (1 non-API)", color=red`) {
+		t.Errorf("Missing highlighted component edge:\n%s", dot)
+	}
+
+	mermaid, err := GenerateMermaidForComponents(components, MermaidOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMermaidForComponents failed: %v", err)
+	}
+	if !strings.Contains(mermaid, `component_web -->|"2 (1 non-API)"| component_core`) || !strings.Contains(mermaid, "linkStyle 0") {
+		t.Errorf("Unexpected Mermaid output:\n%s", mermaid)
+	}
+}
+
 func TestGenerateDOT_Impact(t *testing.T) {
 	g := createTestGraph()
 
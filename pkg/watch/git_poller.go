@@ -0,0 +1,150 @@
+/*
+# Module: pkg/watch/git_poller.go
+Git polling fallback for the file watcher.
+
+Detects saved files by polling git status for uncommitted files and
+comparing their size and modification time between polls. Used when
+fsnotify is unavailable, for example when the inotify watch limit is
+exhausted or on network file systems.
+
+## Linked Modules
+- [watcher](./watcher.go) - File system watcher
+
+## Tags
+watch, git, polling
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#git_poller.go> a code:Module ;
+    code:name "pkg/watch/git_poller.go" ;
+    code:description "Git polling fallback for the file watcher" ;
+    code:language "go" ;
+    code:layer "watch" ;
+    code:linksTo <./watcher.go> ;
+    code:tags "watch", "git", "polling" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fileState is what a poll observes of a file
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime int64
+}
+
+// statFile returns the current state of a file
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime().UnixNano()}
+}
+
+// gitPoller reports files that changed between polls of git status
+type gitPoller struct {
+	root   string               // Watched directory
+	prefix string               // Root relative to the repository root, as git status paths are
+	seen   map[string]fileState // Uncommitted files at the last poll
+}
+
+// newGitPoller creates a poller for a directory inside a git repository
+// and takes the first snapshot, so files already modified are not reported
+func newGitPoller(root string) (*gitPoller, error) {
+	output, err := runGit(root, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &gitPoller{root: root, prefix: strings.TrimSpace(output)}
+	if p.seen, err = p.uncommitted(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// poll returns the files that changed since the last poll. A file changed
+// if it became uncommitted, or if its size or modification time differ;
+// files that stop being uncommitted are reported only if they changed on
+// disk (e.g. a checkout), not when they are merely committed.
+func (p *gitPoller) poll() ([]string, error) {
+	current, err := p.uncommitted()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for path, state := range current {
+		if previous, ok := p.seen[path]; !ok || previous != state {
+			changed = append(changed, path)
+		}
+	}
+	for path, previous := range p.seen {
+		if _, ok := current[path]; !ok && statFile(path) != previous {
+			changed = append(changed, path)
+		}
+	}
+
+	p.seen = current
+	return changed, nil
+}
+
+// uncommitted returns the state of each uncommitted file under the root,
+// including untracked files
+func (p *gitPoller) uncommitted() (map[string]fileState, error) {
+	output, err := runGit(p.root, "status", "--porcelain", "-z", "--untracked-files=all", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]fileState)
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// "XY path"; renames and copies are followed by the original path,
+		// which a rename removes
+		p.add(files, entry[3:])
+		if (entry[0] == 'R' || entry[0] == 'C') && i+1 < len(entries) {
+			i++
+			p.add(files, entries[i])
+		}
+	}
+	return files, nil
+}
+
+// add records the state of a file given by its git status path
+func (p *gitPoller) add(files map[string]fileState, gitPath string) {
+	path := filepath.Join(p.root, filepath.FromSlash(strings.TrimPrefix(gitPath, p.prefix)))
+	files[path] = statFile(path)
+}
+
+// runGit runs a git command in dir and returns its output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %s: %w", args[0], strings.TrimSpace(stderr.String()), err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,118 @@
+package watch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// initRepo creates a git repository with one committed file
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "committed.go"), "package a\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		if _, err := runGit(dir, args...); err != nil {
+			t.Fatalf("Failed to set up repository: %v", err)
+		}
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+// mustPoll polls and returns the changed files relative to root
+func mustPoll(t *testing.T, p *gitPoller) string {
+	t.Helper()
+	changed, err := p.poll()
+	if err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+	names := make([]string, len(changed))
+	for i, path := range changed {
+		names[i], _ = filepath.Rel(p.root, path)
+		names[i] = filepath.ToSlash(names[i])
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
+
+func TestGitPoller(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, filepath.Join(dir, "dirty.go"), "package a\n")
+
+	// Files modified before the first poll are not reported
+	p, err := newGitPoller(dir)
+	if err != nil {
+		t.Fatalf("newGitPoller() error = %v", err)
+	}
+	if got := mustPoll(t, p); got != "" {
+		t.Errorf("Expected no changes, got %q", got)
+	}
+
+	writeFile(t, filepath.Join(dir, "committed.go"), "package a\n\nfunc A() {}\n")
+	writeFile(t, filepath.Join(dir, "pkg", "new.go"), "package pkg\n")
+	if got := mustPoll(t, p); got != "committed.go pkg/new.go" {
+		t.Errorf("changed = %q", got)
+	}
+
+	// Saving a file already modified changes its size or time
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "dirty.go"), future, future); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	if got := mustPoll(t, p); got != "dirty.go" {
+		t.Errorf("changed = %q", got)
+	}
+
+	// Committing is not a change, reverting is
+	if _, err := runGit(dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-am", "update"); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	if got := mustPoll(t, p); got != "" {
+		t.Errorf("Expected no changes after commit, got %q", got)
+	}
+	if err := os.Remove(filepath.Join(dir, "pkg", "new.go")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got := mustPoll(t, p); got != "pkg/new.go" {
+		t.Errorf("changed = %q", got)
+	}
+}
+
+func TestGitPoller_Subdirectory(t *testing.T) {
+	dir := initRepo(t)
+	sub := filepath.Join(dir, "services")
+	writeFile(t, filepath.Join(sub, "keep.go"), "package services\n")
+
+	p, err := newGitPoller(sub)
+	if err != nil {
+		t.Fatalf("newGitPoller() error = %v", err)
+	}
+	writeFile(t, filepath.Join(sub, "auth.go"), "package services\n")
+	writeFile(t, filepath.Join(dir, "outside.go"), "package a\n")
+
+	if got := mustPoll(t, p); got != "auth.go" {
+		t.Errorf("changed = %q", got)
+	}
+}
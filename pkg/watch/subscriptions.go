@@ -0,0 +1,345 @@
+/*
+# Module: pkg/watch/subscriptions.go
+Team subscriptions and change digests.
+
+Lets teams register interest in path globs or tags in
+.graphfs/subscriptions.yaml. After a change, the watcher compares the
+previous and current graph snapshots and builds a per-team digest of new
+dependents on subscribed modules and new rule violations in the team's area.
+Digests are delivered by webhook or written to a report file.
+
+## Linked Modules
+- [watcher](./watcher.go) - File system watcher
+- [../graph](../graph/graph.go) - Graph data structure
+- [../rules](../rules/rule.go) - Rule violations
+- [../scanner](../scanner/focus_filter.go) - Glob matching
+
+## Tags
+watch, subscriptions, notifications, teams
+
+## Exports
+SubscriptionsFile, Subscription, SubscriptionConfig, LoadSubscriptions, Snapshot, Digest,
+DependentChange, DigestViolation, BuildDigests, DeliverDigest, ViolationKey
+
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+
+<#subscriptions.go> a code:Module ;
+    code:name "pkg/watch/subscriptions.go" ;
+    code:description "Team subscriptions and change digests" ;
+    code:language "go" ;
+    code:layer "watch" ;
+    code:linksTo <./watcher.go>, <../graph/graph.go>, <../rules/rule.go>, <../scanner/focus_filter.go> ;
+    code:exports <#SubscriptionsFile>, <#Subscription>, <#SubscriptionConfig>, <#LoadSubscriptions>,
+                 <#Snapshot>, <#Digest>, <#DependentChange>, <#DigestViolation>, <#BuildDigests>,
+                 <#DeliverDigest>, <#ViolationKey> ;
+    code:tags "watch", "subscriptions", "notifications", "teams" .
+<!-- End LinkedDoc RDF -->
+*/
+
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/rules"
+	"github.com/justin4957/graphfs/pkg/scanner"
+	"gopkg.in/yaml.v3"
+)
+
+// SubscriptionsFile is the subscriptions file inside the .graphfs directory
+const SubscriptionsFile = "subscriptions.yaml"
+
+// webhookTimeout bounds webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// Subscription registers a team's interest in part of the codebase
+type Subscription struct {
+	Team    string   `yaml:"team" json:"team"`
+	Paths   []string `yaml:"paths" json:"paths,omitempty"`     // Path globs (supports **)
+	Tags    []string `yaml:"tags" json:"tags,omitempty"`       // Module tags
+	Webhook string   `yaml:"webhook" json:"webhook,omitempty"` // URL to POST digests to
+	Report  string   `yaml:"report" json:"report,omitempty"`   // File to write digests to
+}
+
+// SubscriptionConfig is the on-disk format of subscriptions.yaml
+type SubscriptionConfig struct {
+	Subscriptions []Subscription `yaml:"subscriptions"`
+}
+
+// LoadSubscriptions loads subscriptions.yaml from a .graphfs directory.
+// A missing file yields an empty configuration.
+func LoadSubscriptions(graphfsDir string) (*SubscriptionConfig, error) {
+	config := &SubscriptionConfig{}
+
+	data, err := os.ReadFile(filepath.Join(graphfsDir, SubscriptionsFile))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SubscriptionsFile, err)
+	}
+
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SubscriptionsFile, err)
+	}
+
+	for i, sub := range config.Subscriptions {
+		if sub.Team == "" {
+			return nil, fmt.Errorf("subscription %d: team is required", i+1)
+		}
+		if len(sub.Paths) == 0 && len(sub.Tags) == 0 {
+			return nil, fmt.Errorf("subscription %q: at least one path or tag is required", sub.Team)
+		}
+	}
+
+	return config, nil
+}
+
+// Matches returns true if the module falls under the subscription
+func (s Subscription) Matches(module *graph.Module) bool {
+	if module == nil {
+		return false
+	}
+	return s.matchesPath(module.Path) || s.matchesTags(module.Tags)
+}
+
+// matchesPath checks a relative path against the subscription globs
+func (s Subscription) matchesPath(path string) bool {
+	if len(s.Paths) == 0 || path == "" {
+		return false
+	}
+	return len(scanner.NewFocusFilter(s.Paths, "").Match([]string{path})) > 0
+}
+
+// matchesTags checks module tags against the subscription tags
+func (s Subscription) matchesTags(tags []string) bool {
+	for _, want := range s.Tags {
+		for _, tag := range tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Snapshot is the state of the graph and rule violations at a point in time
+type Snapshot struct {
+	Graph      *graph.Graph
+	Violations []rules.Violation
+}
+
+// Digest summarizes changes relevant to one team
+type Digest struct {
+	Team          string            `json:"team"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+	ChangedFiles  []string          `json:"changed_files,omitempty"`
+	NewDependents []DependentChange `json:"new_dependents"`
+	Violations    []DigestViolation `json:"violations"`
+}
+
+// DependentChange is a new dependency on a subscribed module
+type DependentChange struct {
+	Module    string `json:"module"`
+	Dependent string `json:"dependent"`
+}
+
+// DigestViolation is a new rule violation in a subscribed area
+type DigestViolation struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Module   string `json:"module"`
+	Message  string `json:"message"`
+}
+
+// IsEmpty returns true if the digest has nothing to report
+func (d *Digest) IsEmpty() bool {
+	return len(d.NewDependents) == 0 && len(d.Violations) == 0
+}
+
+// BuildDigests compares two snapshots and returns one digest per team that
+// has new dependents or new violations. Teams with nothing to report are omitted.
+func BuildDigests(subs []Subscription, before, after Snapshot, changedFiles []string) []*Digest {
+	dependents := newDependents(before.Graph, after.Graph)
+	violations := newViolations(before.Violations, after.Violations)
+	now := time.Now()
+
+	var digests []*Digest
+	for _, sub := range subs {
+		digest := &Digest{
+			Team:          sub.Team,
+			GeneratedAt:   now,
+			ChangedFiles:  changedFiles,
+			NewDependents: []DependentChange{},
+			Violations:    []DigestViolation{},
+		}
+
+		for _, change := range dependents {
+			if sub.Matches(after.Graph.GetModule(change.Module)) {
+				digest.NewDependents = append(digest.NewDependents, change)
+			}
+		}
+
+		for _, v := range violations {
+			module := v.Module
+			if module == nil && v.FilePath != "" {
+				module = after.Graph.GetModule(v.FilePath)
+			}
+			if sub.Matches(module) || sub.matchesPath(v.FilePath) {
+				digest.Violations = append(digest.Violations, toDigestViolation(v))
+			}
+		}
+
+		if !digest.IsEmpty() {
+			digests = append(digests, digest)
+		}
+	}
+
+	return digests
+}
+
+// newDependents returns dependent edges present in after but not in before
+func newDependents(before, after *graph.Graph) []DependentChange {
+	if after == nil {
+		return nil
+	}
+
+	existing := make(map[string]bool)
+	if before != nil {
+		beforePaths := modulePathsByURI(before)
+		for _, module := range before.Modules {
+			for _, dep := range module.Dependents {
+				existing[module.Path+"\x00"+resolvePath(beforePaths, dep)] = true
+			}
+		}
+	}
+
+	afterPaths := modulePathsByURI(after)
+	var changes []DependentChange
+	for _, module := range after.Modules {
+		for _, dep := range module.Dependents {
+			dependent := resolvePath(afterPaths, dep)
+			if existing[module.Path+"\x00"+dependent] {
+				continue
+			}
+			changes = append(changes, DependentChange{Module: module.Path, Dependent: dependent})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Module != changes[j].Module {
+			return changes[i].Module < changes[j].Module
+		}
+		return changes[i].Dependent < changes[j].Dependent
+	})
+	return changes
+}
+
+// modulePathsByURI maps module URIs to their relative paths
+func modulePathsByURI(g *graph.Graph) map[string]string {
+	paths := make(map[string]string, len(g.Modules))
+	for _, module := range g.Modules {
+		paths[module.URI] = module.Path
+	}
+	return paths
+}
+
+// resolvePath returns the module path for a URI, or the URI if unknown
+func resolvePath(paths map[string]string, uri string) string {
+	if path, ok := paths[uri]; ok {
+		return path
+	}
+	return uri
+}
+
+// newViolations returns violations present in after but not in before
+func newViolations(before, after []rules.Violation) []rules.Violation {
+	existing := make(map[string]bool, len(before))
+	for _, v := range before {
+		existing[ViolationKey(v)] = true
+	}
+
+	var result []rules.Violation
+	for _, v := range after {
+		if !existing[ViolationKey(v)] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// ViolationKey identifies a violation by rule, location and message
+func ViolationKey(v rules.Violation) string {
+	ruleID := ""
+	if v.Rule != nil {
+		ruleID = v.Rule.ID
+	}
+	location := v.FilePath
+	if v.Module != nil {
+		location = v.Module.Path
+	}
+	return ruleID + "\x00" + location + "\x00" + v.Message
+}
+
+// toDigestViolation converts a rule violation for reporting
+func toDigestViolation(v rules.Violation) DigestViolation {
+	dv := DigestViolation{
+		Module:  v.FilePath,
+		Message: v.Message,
+	}
+	if v.Module != nil {
+		dv.Module = v.Module.Path
+	}
+	if v.Rule != nil {
+		dv.RuleID = v.Rule.ID
+		dv.Severity = string(v.Rule.Severity)
+	}
+	return dv
+}
+
+// DeliverDigest sends a digest to the subscription's webhook and/or appends
+// it to the subscription's report file (one JSON object per line).
+func DeliverDigest(sub Subscription, digest *Digest) error {
+	data, err := json.Marshal(digest)
+	if err != nil {
+		return fmt.Errorf("failed to encode digest: %w", err)
+	}
+
+	if sub.Webhook != "" {
+		client := &http.Client{Timeout: webhookTimeout}
+		resp, err := client.Post(sub.Webhook, "application/json", bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to post digest for %s: %w", sub.Team, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook for %s returned %s", sub.Team, resp.Status)
+		}
+	}
+
+	if sub.Report != "" {
+		if err := os.MkdirAll(filepath.Dir(sub.Report), 0755); err != nil {
+			return fmt.Errorf("failed to create report directory for %s: %w", sub.Team, err)
+		}
+		f, err := os.OpenFile(sub.Report, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open report for %s: %w", sub.Team, err)
+		}
+		defer f.Close()
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write report for %s: %w", sub.Team, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package watch
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justin4957/graphfs/internal/store"
+	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/rules"
+)
+
+func newSubscriptionGraph(t *testing.T, edges map[string][]string) *graph.Graph {
+	t.Helper()
+
+	g := graph.NewGraph("/project", store.NewTripleStore())
+	for _, path := range []string{"services/auth/login.go", "services/billing/invoice.go", "pkg/util/strings.go"} {
+		module := graph.NewModule(path, "<#"+path+">")
+		if strings.HasPrefix(path, "pkg/") {
+			module.AddTag("shared")
+		}
+		g.AddModule(module)
+	}
+	for target, dependents := range edges {
+		for _, dep := range dependents {
+			g.GetModule(target).AddDependent("<#" + dep + ">")
+		}
+	}
+	return g
+}
+
+func TestLoadSubscriptions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	config, err := LoadSubscriptions(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to load missing subscriptions: %v", err)
+	}
+	if len(config.Subscriptions) != 0 {
+		t.Errorf("Expected no subscriptions, got %d", len(config.Subscriptions))
+	}
+
+	content := `subscriptions:
+  - team: auth
+    paths: ["services/auth/**"]
+    webhook: https://hooks.example.com/auth
+  - team: platform
+    tags: [shared]
+    report: platform-digest.jsonl
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, SubscriptionsFile), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write subscriptions: %v", err)
+	}
+
+	config, err = LoadSubscriptions(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to load subscriptions: %v", err)
+	}
+	if len(config.Subscriptions) != 2 {
+		t.Fatalf("Expected 2 subscriptions, got %d", len(config.Subscriptions))
+	}
+	if config.Subscriptions[0].Webhook != "https://hooks.example.com/auth" {
+		t.Errorf("Unexpected webhook: %s", config.Subscriptions[0].Webhook)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, SubscriptionsFile), []byte("subscriptions:\n  - team: empty\n"), 0644); err != nil {
+		t.Fatalf("Failed to write subscriptions: %v", err)
+	}
+	if _, err := LoadSubscriptions(tmpDir); err == nil {
+		t.Error("Expected error for subscription without paths or tags")
+	}
+}
+
+func TestBuildDigests(t *testing.T) {
+	before := newSubscriptionGraph(t, map[string][]string{
+		"pkg/util/strings.go": {"services/auth/login.go"},
+	})
+	after := newSubscriptionGraph(t, map[string][]string{
+		"pkg/util/strings.go":    {"services/auth/login.go", "services/billing/invoice.go"},
+		"services/auth/login.go": {"services/billing/invoice.go"},
+	})
+
+	rule := &rules.Rule{ID: "no-cross-service", Severity: rules.SeverityError}
+	violation := rules.Violation{
+		Rule:    rule,
+		Module:  after.GetModule("services/billing/invoice.go"),
+		Message: "billing imports auth",
+	}
+
+	subs := []Subscription{
+		{Team: "auth", Paths: []string{"services/auth/**"}},
+		{Team: "billing", Paths: []string{"services/billing/**"}},
+		{Team: "platform", Tags: []string{"shared"}},
+		{Team: "docs", Paths: []string{"docs/**"}},
+	}
+
+	digests := BuildDigests(subs, Snapshot{Graph: before}, Snapshot{Graph: after, Violations: []rules.Violation{violation}}, nil)
+
+	byTeam := make(map[string]*Digest)
+	for _, d := range digests {
+		byTeam[d.Team] = d
+	}
+
+	if _, ok := byTeam["docs"]; ok {
+		t.Error("Expected no digest for team with nothing to report")
+	}
+
+	auth := byTeam["auth"]
+	if auth == nil || len(auth.NewDependents) != 1 || auth.NewDependents[0].Dependent != "services/billing/invoice.go" {
+		t.Errorf("Unexpected auth digest: %+v", auth)
+	}
+
+	platform := byTeam["platform"]
+	if platform == nil || len(platform.NewDependents) != 1 {
+		t.Errorf("Expected 1 new dependent for platform (existing edge excluded), got %+v", platform)
+	}
+
+	billing := byTeam["billing"]
+	if billing == nil || len(billing.Violations) != 1 || billing.Violations[0].RuleID != "no-cross-service" {
+		t.Errorf("Unexpected billing digest: %+v", billing)
+	}
+
+	// A violation already present before the change is not reported again
+	digests = BuildDigests(subs,
+		Snapshot{Graph: after, Violations: []rules.Violation{violation}},
+		Snapshot{Graph: after, Violations: []rules.Violation{violation}}, nil)
+	if len(digests) != 0 {
+		t.Errorf("Expected no digests for unchanged snapshots, got %d", len(digests))
+	}
+}
+
+func TestDeliverDigest(t *testing.T) {
+	var received Digest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("Failed to decode webhook body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	report := filepath.Join(t.TempDir(), "digest.jsonl")
+	sub := Subscription{Team: "auth", Webhook: server.URL, Report: report}
+	digest := &Digest{
+		Team:          "auth",
+		NewDependents: []DependentChange{{Module: "a.go", Dependent: "b.go"}},
+	}
+
+	if err := DeliverDigest(sub, digest); err != nil {
+		t.Fatalf("Failed to deliver digest: %v", err)
+	}
+
+	if received.Team != "auth" || len(received.NewDependents) != 1 {
+		t.Errorf("Unexpected webhook payload: %+v", received)
+	}
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if !strings.Contains(string(data), `"dependent":"b.go"`) {
+		t.Errorf("Report missing dependent: %s", data)
+	}
+}
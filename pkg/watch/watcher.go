@@ -3,19 +3,26 @@
 File system watcher for live monitoring.
 
 Monitors file system changes and triggers incremental graph updates with
-debouncing to batch rapid changes.
+debouncing to batch rapid changes. Each batch is applied with
+graph.Builder.Update, which re-parses only the changed files, and
+optionally to the shadow file system. Changes are reported by fsnotify as
+files are saved, falling back to polling git status where fsnotify is
+unavailable.
 
 ## Linked Modules
 - [debouncer](./debouncer.go) - Change debouncing
 - [../graph](../graph/graph.go) - Graph updates
+- [../graph/update](../graph/update.go) - Incremental updates
 - [../scanner](../scanner/scanner.go) - File scanning
 - [../parser](../parser/parser.go) - File parsing
+- [../shadow](../shadow/builder.go) - Shadow entry updates
+- [git_poller](./git_poller.go) - Git polling fallback
 
 ## Tags
 watch, filesystem, monitoring
 
 ## Exports
-Watcher, WatchOptions, NewWatcher
+Watcher, WatchOptions, NewWatcher, Backend, BackendAuto, BackendFSNotify, BackendGit
 
 <!-- LinkedDoc RDF -->
 @prefix code: <https://schema.codedoc.org/> .
@@ -26,8 +33,8 @@ Watcher, WatchOptions, NewWatcher
     code:description "File system watcher for live monitoring" ;
     code:language "go" ;
     code:layer "watch" ;
-    code:linksTo <./debouncer.go>, <../graph/graph.go>, <../scanner/scanner.go>, <../parser/parser.go> ;
-    code:exports <#Watcher>, <#WatchOptions>, <#NewWatcher> ;
+    code:linksTo <./debouncer.go>, <../graph/graph.go>, <../graph/update.go>, <../scanner/scanner.go>, <../parser/parser.go>, <../shadow/builder.go>, <./git_poller.go> ;
+    code:exports <#Watcher>, <#WatchOptions>, <#NewWatcher>, <#Backend>, <#BackendAuto>, <#BackendFSNotify>, <#BackendGit> ;
     code:tags "watch", "filesystem", "monitoring" .
 <!-- End LinkedDoc RDF -->
 */
@@ -35,6 +42,7 @@ Watcher, WatchOptions, NewWatcher
 package watch
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -46,14 +54,41 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/justin4957/graphfs/pkg/graph"
 	"github.com/justin4957/graphfs/pkg/scanner"
+	"github.com/justin4957/graphfs/pkg/shadow"
 )
 
+// Backend is how a watcher learns of changed files
+type Backend string
+
+const (
+	// BackendAuto uses fsnotify, falling back to git polling when fsnotify
+	// cannot watch the tree
+	BackendAuto Backend = "auto"
+	// BackendFSNotify reports files as they are saved
+	BackendFSNotify Backend = "fsnotify"
+	// BackendGit polls git status for uncommitted files
+	BackendGit Backend = "git"
+)
+
+// DefaultPollInterval is how often the git backend polls
+const DefaultPollInterval = 2 * time.Second
+
 // WatchOptions configures watch behavior
 type WatchOptions struct {
 	Path           string        // Root path to watch
 	Debounce       time.Duration // Debounce duration for batching changes
 	IgnorePatterns []string      // Patterns to ignore
 	Verbose        bool          // Enable verbose logging
+	Backend        Backend       // Change source (empty = BackendAuto)
+	PollInterval   time.Duration // Git backend poll interval (0 = DefaultPollInterval)
+
+	// ShadowFS, if set, has the shadow entries of changed files rebuilt
+	// and those of removed files deleted. Its index must be loaded.
+	ShadowFS *shadow.ShadowFS
+
+	// BuildOptions are the options the graph was built with, reused for
+	// incremental updates
+	BuildOptions graph.BuildOptions
 }
 
 // DefaultWatchOptions returns default watch options
@@ -69,47 +104,108 @@ func DefaultWatchOptions() WatchOptions {
 			".idea",
 			".vscode",
 		},
-		Verbose: false,
+		Verbose:      false,
+		Backend:      BackendAuto,
+		PollInterval: DefaultPollInterval,
 	}
 }
 
 // Watcher monitors file system changes and updates the graph
 type Watcher struct {
-	watcher   *fsnotify.Watcher
+	watcher   *fsnotify.Watcher // Nil with the git backend
+	poller    *gitPoller        // Nil with the fsnotify backend
+	backend   Backend
 	graph     *graph.Graph
-	scanner   *scanner.Scanner
+	builder   *graph.Builder
 	debouncer *Debouncer
 	onChange  func(*graph.Graph, []string) // Callback with changed files
 	opts      WatchOptions
 	mu        sync.Mutex
 	running   bool
 	changes   map[string]bool // Track pending changes
+
+	stop       chan struct{}  // Closed by Stop to end git polling
+	loopDone   chan struct{}  // Closed when the event loop exits
+	processing sync.WaitGroup // Change batches being processed
 }
 
-// NewWatcher creates a new file system watcher
+// NewWatcher creates a new file system watcher. With a nil graph the
+// watcher only reports changed files to onChange.
 func NewWatcher(g *graph.Graph, opts WatchOptions, onChange func(*graph.Graph, []string)) (*Watcher, error) {
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	if opts.Backend == "" {
+		opts.Backend = BackendAuto
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
 	}
 
 	w := &Watcher{
-		watcher:   watcher,
 		graph:     g,
-		scanner:   scanner.NewScanner(),
+		builder:   graph.NewBuilder(),
 		debouncer: NewDebouncer(opts.Debounce),
 		onChange:  onChange,
 		opts:      opts,
 		changes:   make(map[string]bool),
 	}
 
+	switch opts.Backend {
+	case BackendFSNotify:
+		if err := w.watchFSNotify(); err != nil {
+			return nil, err
+		}
+	case BackendGit:
+		if err := w.watchGit(); err != nil {
+			return nil, err
+		}
+	case BackendAuto:
+		err := w.watchFSNotify()
+		if err == nil {
+			break
+		}
+		if gitErr := w.watchGit(); gitErr != nil {
+			return nil, fmt.Errorf("%w (git fallback: %v)", err, gitErr)
+		}
+		log.Printf("Falling back to polling git every %v: %v", opts.PollInterval, err)
+	default:
+		return nil, fmt.Errorf("unknown watch backend: %s (use auto, fsnotify or git)", opts.Backend)
+	}
+
+	return w, nil
+}
+
+// watchFSNotify sets up fsnotify watches on the tree
+func (w *Watcher) watchFSNotify() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	w.watcher = watcher
+
 	// Watch directory recursively
-	if err := w.watchRecursive(opts.Path); err != nil {
+	if err := w.watchRecursive(w.opts.Path); err != nil {
 		watcher.Close()
-		return nil, fmt.Errorf("failed to setup watches: %w", err)
+		w.watcher = nil
+		return fmt.Errorf("failed to setup watches: %w", err)
 	}
 
-	return w, nil
+	w.backend = BackendFSNotify
+	return nil
+}
+
+// watchGit sets up polling of git status
+func (w *Watcher) watchGit() error {
+	poller, err := newGitPoller(w.opts.Path)
+	if err != nil {
+		return fmt.Errorf("failed to poll git: %w", err)
+	}
+	w.poller = poller
+	w.backend = BackendGit
+	return nil
+}
+
+// Backend returns the change source in use, BackendFSNotify or BackendGit
+func (w *Watcher) Backend() Backend {
+	return w.backend
 }
 
 // watchRecursive adds watches to all directories recursively
@@ -162,9 +258,17 @@ func (w *Watcher) Start() {
 		return
 	}
 	w.running = true
+	w.stop = make(chan struct{})
+	w.loopDone = make(chan struct{})
 	w.mu.Unlock()
 
+	if w.poller != nil {
+		go w.pollLoop()
+		return
+	}
+
 	go func() {
+		defer close(w.loopDone)
 		for {
 			select {
 			case event, ok := <-w.watcher.Events:
@@ -186,23 +290,53 @@ func (w *Watcher) Start() {
 	}()
 }
 
+// pollLoop polls git until the watcher is stopped
+func (w *Watcher) pollLoop() {
+	defer close(w.loopDone)
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			changed, err := w.poller.poll()
+			if err != nil {
+				log.Printf("Watch error: %v", err)
+				continue
+			}
+			for _, path := range changed {
+				if w.shouldProcessPath(path) {
+					w.trackChange(path)
+				}
+			}
+		}
+	}
+}
+
 // shouldProcess determines if an event should trigger processing
 func (w *Watcher) shouldProcess(event fsnotify.Event) bool {
-	// Only process write and create events
-	if event.Op&fsnotify.Write != fsnotify.Write &&
-		event.Op&fsnotify.Create != fsnotify.Create {
+	// Only process write, create, remove and rename events
+	if !event.Op.Has(fsnotify.Write) && !event.Op.Has(fsnotify.Create) &&
+		!event.Op.Has(fsnotify.Remove) && !event.Op.Has(fsnotify.Rename) {
 		return false
 	}
 
+	return w.shouldProcessPath(event.Name)
+}
+
+// shouldProcessPath determines if a changed file should trigger processing
+func (w *Watcher) shouldProcessPath(path string) bool {
 	// Check if it's a supported file type
-	lang := scanner.DetectLanguage(event.Name)
+	lang := scanner.DetectLanguage(path)
 	if lang == "unknown" {
 		return false
 	}
 
 	// Check if file should be ignored
 	for _, pattern := range w.opts.IgnorePatterns {
-		if strings.Contains(event.Name, pattern) {
+		if strings.Contains(path, pattern) {
 			return false
 		}
 	}
@@ -221,9 +355,16 @@ func (w *Watcher) trackChange(path string) {
 	})
 }
 
-// processChanges handles all pending file changes
+// processChanges handles all pending file changes. Batches that fire after
+// Stop are dropped.
 func (w *Watcher) processChanges() {
 	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.processing.Add(1)
+	defer w.processing.Done()
 	changedFiles := make([]string, 0, len(w.changes))
 	for path := range w.changes {
 		changedFiles = append(changedFiles, path)
@@ -239,11 +380,12 @@ func (w *Watcher) processChanges() {
 		log.Printf("Processing %d changed file(s)", len(changedFiles))
 	}
 
-	// Re-scan changed files and update graph
-	for _, path := range changedFiles {
-		if err := w.updateFile(path); err != nil {
-			log.Printf("Failed to update %s: %v", path, err)
-		}
+	// Re-parse changed files and patch the graph in place
+	if w.graph != nil {
+		w.updateGraph(changedFiles)
+	}
+	if w.opts.ShadowFS != nil {
+		w.updateShadow(changedFiles)
 	}
 
 	// Notify callback
@@ -252,44 +394,62 @@ func (w *Watcher) processChanges() {
 	}
 }
 
-// updateFile re-parses a file and updates the graph
-func (w *Watcher) updateFile(path string) error {
-	// Check if file still exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		// File was deleted - remove from graph
-		relPath, _ := filepath.Rel(w.opts.Path, path)
-		w.graph.RemoveModule(relPath)
-		if w.opts.Verbose {
-			log.Printf("Removed deleted file: %s", relPath)
-		}
-		return nil
-	}
-
-	// Re-scan the file
-	fileInfo, err := w.scanner.ScanFile(path)
+// updateGraph applies changed files to the graph
+func (w *Watcher) updateGraph(changedFiles []string) {
+	result, err := w.builder.Update(w.graph, changedFiles, w.opts.BuildOptions)
 	if err != nil {
-		return fmt.Errorf("scan failed: %w", err)
+		log.Printf("Failed to update graph: %v", err)
+		return
 	}
-
-	// Only update if file has LinkedDoc
-	if !fileInfo.HasLinkedDoc {
-		if w.opts.Verbose {
-			log.Printf("Skipping file without LinkedDoc: %s", path)
-		}
-		return nil
+	if !w.opts.Verbose {
+		return
 	}
-
-	// Get relative path
-	relPath, err := filepath.Rel(w.opts.Path, path)
-	if err != nil {
-		relPath = path
+	for _, p := range result.Added {
+		log.Printf("Added: %s", p)
 	}
+	for _, p := range result.Updated {
+		log.Printf("Updated: %s", p)
+	}
+	for _, p := range result.Removed {
+		log.Printf("Removed: %s", p)
+	}
+	log.Printf("Graph updated in %v", result.Duration)
+}
 
-	if w.opts.Verbose {
-		log.Printf("Updated: %s", relPath)
+// updateShadow rebuilds the shadow entries of changed LinkedDoc files and
+// deletes those of removed files, then saves the shadow index
+func (w *Watcher) updateShadow(changedFiles []string) {
+	shadowFS := w.opts.ShadowFS
+	builder := shadow.NewBuilder(shadowFS)
+	fileScanner := scanner.NewScanner()
+	opts := shadow.DefaultBuildOptions()
+	opts.BaseIRI = w.opts.BuildOptions.BaseIRI
+
+	for _, path := range changedFiles {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if !shadowFS.Exists(path) {
+				continue
+			}
+			if err := shadowFS.Delete(path); err != nil {
+				log.Printf("Failed to delete shadow entry for %s: %v", path, err)
+			} else if w.opts.Verbose {
+				log.Printf("Deleted shadow entry: %s", path)
+			}
+			continue
+		}
+		if info, err := fileScanner.ScanFile(path); err != nil || !info.HasLinkedDoc {
+			continue
+		}
+		if err := builder.BuildFile(path, opts); err != nil {
+			log.Printf("Failed to update shadow entry for %s: %v", path, err)
+		} else if w.opts.Verbose {
+			log.Printf("Updated shadow entry: %s", path)
+		}
 	}
 
-	return nil
+	if err := shadowFS.SaveIndex(); err != nil {
+		log.Printf("Failed to save shadow index: %v", err)
+	}
 }
 
 // Stop stops the watcher
@@ -303,10 +463,42 @@ func (w *Watcher) Stop() error {
 
 	w.running = false
 	w.debouncer.Stop()
+	close(w.stop)
 
+	if w.watcher == nil {
+		return nil
+	}
 	return w.watcher.Close()
 }
 
+// Shutdown stops the watcher and waits for the event loop and any batch
+// being processed to finish, or for ctx to be done
+func (w *Watcher) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	loopDone := w.loopDone
+	w.mu.Unlock()
+
+	if err := w.Stop(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		if loopDone != nil {
+			<-loopDone
+		}
+		w.processing.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("watcher did not stop: %w", ctx.Err())
+	}
+}
+
 // IsRunning returns true if the watcher is running
 func (w *Watcher) IsRunning() bool {
 	w.mu.Lock()
@@ -1,6 +1,7 @@
 package watch
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sync"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/justin4957/graphfs/internal/store"
 	"github.com/justin4957/graphfs/pkg/graph"
+	"github.com/justin4957/graphfs/pkg/shadow"
 )
 
 func TestNewWatcher(t *testing.T) {
@@ -70,6 +72,49 @@ func TestWatcher_StartStop(t *testing.T) {
 	}
 }
 
+func TestWatcher_Shutdown(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	opts := DefaultWatchOptions()
+	opts.Path = tmpDir
+	opts.Debounce = 10 * time.Millisecond
+
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	watcher, err := NewWatcher(nil, opts, func(_ *graph.Graph, _ []string) {
+		entered <- struct{}{}
+		<-release
+	})
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	watcher.Start()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the change to be processed")
+	}
+
+	// A batch in progress holds up shutdown until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := watcher.Shutdown(ctx); err == nil {
+		t.Error("Expected Shutdown to time out while a batch is processed")
+	}
+
+	close(release)
+	if err := watcher.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if watcher.IsRunning() {
+		t.Error("Expected watcher to not be running after Shutdown()")
+	}
+}
+
 func TestWatcher_FileChange(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "graphfs-watch-test")
 	if err != nil {
@@ -213,3 +258,79 @@ func TestWatcher_MultipleChanges(t *testing.T) {
 		t.Errorf("Expected debouncing to batch changes, got %d callbacks", count)
 	}
 }
+
+func TestWatcher_GitBackendUpdatesShadow(t *testing.T) {
+	dir := initRepo(t)
+
+	shadowFS, err := shadow.NewShadowFS(dir, shadow.DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewShadowFS() error = %v", err)
+	}
+	if err := shadowFS.Initialize(); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	opts := DefaultWatchOptions()
+	opts.Path = dir
+	opts.Debounce = 10 * time.Millisecond
+	opts.Backend = BackendGit
+	opts.PollInterval = 20 * time.Millisecond
+	opts.ShadowFS = shadowFS
+
+	batches := make(chan []string, 10)
+	watcher, err := NewWatcher(nil, opts, func(_ *graph.Graph, files []string) {
+		batches <- files
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+	if watcher.Backend() != BackendGit {
+		t.Errorf("Backend() = %s", watcher.Backend())
+	}
+	watcher.Start()
+	defer watcher.Stop()
+
+	wait := func() {
+		t.Helper()
+		select {
+		case <-batches:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected the change to be processed")
+		}
+	}
+
+	source := filepath.Join(dir, "auth.go")
+	writeFile(t, source, `/*
+# Module: auth.go
+<!-- LinkedDoc RDF -->
+@prefix code: <https://schema.codedoc.org/> .
+<#auth.go> a code:Module ;
+    code:description "Authentication" .
+<!-- End LinkedDoc RDF -->
+*/
+package a
+`)
+	wait()
+	entry, err := shadowFS.Get(source)
+	if err != nil || entry == nil {
+		t.Fatalf("Expected a shadow entry, got %v, %v", entry, err)
+	}
+
+	if err := os.Remove(source); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	wait()
+	if shadowFS.Exists(source) {
+		t.Error("Expected the shadow entry to be deleted")
+	}
+}
+
+func TestNewWatcher_UnknownBackend(t *testing.T) {
+	opts := DefaultWatchOptions()
+	opts.Path = t.TempDir()
+	opts.Backend = "inotify"
+
+	if _, err := NewWatcher(nil, opts, nil); err == nil {
+		t.Error("Expected an unknown backend error")
+	}
+}